@@ -0,0 +1,177 @@
+// Package alert 基于阈值的异常告警：按自然分钟统计单个分享的浏览量、5xx 响应数与登录失败次数，
+// 达到 alert 配置的阈值时记录日志，并通过邮件、浏览器推送通知管理员、调用订阅 alert 事件的钩子；
+// 同一告警在冷却期内只通知一次，便于及时发现盗链、刷量、服务故障与暴力破解。
+package alert
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/hooks"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+)
+
+// 告警类型
+const (
+	KindShareViews   = "share_views"
+	KindServerErrors = "server_errors"
+	KindAuthFailures = "auth_failures"
+	KindShareReports = "share_reports"
+)
+
+// counter 按自然分钟计数，进入新的一分钟时清零
+type counter struct {
+	mu     sync.Mutex
+	minute int64
+	counts map[string]int
+	fired  map[string]time.Time // 各告警最近一次通知的时间
+}
+
+var counters = counter{counts: map[string]int{}, fired: map[string]time.Time{}}
+
+// hit 计数加一，恰好达到阈值且不在冷却期内时返回本分钟的次数
+func (c *counter) hit(key string, threshold int, now time.Time) (int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if m := now.Unix() / 60; m != c.minute {
+		c.minute = m
+		clear(c.counts)
+		cooldown := config.Get().Alert.Cooldown.Std()
+		for k, t := range c.fired {
+			if now.Sub(t) >= cooldown {
+				delete(c.fired, k)
+			}
+		}
+	}
+	c.counts[key]++
+	n := c.counts[key]
+	if n != threshold {
+		return n, false
+	}
+	if t, ok := c.fired[key]; ok && now.Sub(t) < config.Get().Alert.Cooldown.Std() {
+		return n, false
+	}
+	c.fired[key] = now
+	return n, true
+}
+
+// ShareViewed 记录一次分享浏览
+func ShareViewed(id, title, url string) {
+	threshold := config.Get().Alert.ShareViews
+	if threshold <= 0 {
+		return
+	}
+	n, ok := counters.hit(KindShareViews+":"+id, threshold, time.Now())
+	if !ok {
+		return
+	}
+	send(&hooks.Event{
+		Type:  hooks.Alert,
+		Share: &hooks.Share{ID: id, Title: title, URL: url},
+		Alert: &hooks.AlertInfo{Kind: KindShareViews, Count: n, Threshold: threshold},
+	}, url, "alert.share_views", title, n)
+}
+
+// ServerError 记录一次 5xx 响应
+func ServerError() {
+	threshold := config.Get().Alert.ServerErrors
+	if threshold <= 0 {
+		return
+	}
+	n, ok := counters.hit(KindServerErrors, threshold, time.Now())
+	if !ok {
+		return
+	}
+	send(&hooks.Event{
+		Type:  hooks.Alert,
+		Alert: &hooks.AlertInfo{Kind: KindServerErrors, Count: n, Threshold: threshold},
+	}, "", "alert.server_errors", n)
+}
+
+// AuthFailed 记录一次登录失败，ip 为触发告警的那次请求的来源
+func AuthFailed(ip string) {
+	threshold := config.Get().Alert.AuthFailures
+	if threshold <= 0 {
+		return
+	}
+	n, ok := counters.hit(KindAuthFailures, threshold, time.Now())
+	if !ok {
+		return
+	}
+	send(&hooks.Event{
+		Type:  hooks.Alert,
+		IP:    ip,
+		Alert: &hooks.AlertInfo{Kind: KindAuthFailures, Count: n, Threshold: threshold},
+	}, "", "alert.auth_failures", n, ip)
+}
+
+// ShareReported 记录一次读者举报，同一分享在冷却期内只通知一次
+func ShareReported(id, title, url string) {
+	if !config.Get().Alert.Reports {
+		return
+	}
+	n, ok := counters.hit(KindShareReports+":"+id, 1, time.Now())
+	if !ok {
+		return
+	}
+	send(&hooks.Event{
+		Type:  hooks.Alert,
+		Share: &hooks.Share{ID: id, Title: title, URL: url},
+		Alert: &hooks.AlertInfo{Kind: KindShareReports, Count: n, Threshold: 1},
+	}, url, "alert.share_reports", title)
+}
+
+// send 记录告警日志并异步通知管理员与 alert 钩子；告警说明由文案 key 与参数生成，
+// 日志与钩子使用默认语言，邮件与推送使用各管理员的语言偏好
+func send(ev *hooks.Event, url, key string, args ...any) {
+	a := ev.Alert
+	a.Message = i18n.T(i18n.Default(), key, args...)
+	slog.Warn("alert", "kind", a.Kind, "count", a.Count, "threshold", a.Threshold, "message", a.Message)
+	hooks.Fire(ev)
+	background.Go(func() {
+		notifyAdmins(a, url, func(locale string) string { return i18n.T(locale, key, args...) })
+	})
+}
+
+// notifyAdmins 向配置的管理员发送邮件（已配置 SMTP 时）与浏览器推送，message 按语言生成告警说明
+func notifyAdmins(a *hooks.AlertInfo, url string, message func(locale string) string) {
+	admins := config.Get().Auth.Admins
+	if len(admins) == 0 {
+		return
+	}
+	names := make([]string, len(admins))
+	for i, name := range admins {
+		names[i] = strings.ToLower(name)
+	}
+	var users []models.User
+	if err := models.DB.Select("id", "username", "email", "locale").Where("LOWER(username) IN ? AND is_active = ?", names, true).
+		Find(&users).Error; err != nil {
+		slog.Error("alert: failed to load admins", "error", err)
+		return
+	}
+
+	for _, u := range users {
+		locale := i18n.Match(u.Locale)
+		if locale == "" {
+			locale = i18n.Default()
+		}
+		msg := message(locale)
+		if mailer.Enabled() && u.Email != "" {
+			body := msg + "\n"
+			if url != "" {
+				body += "\n" + url + "\n"
+			}
+			if err := mailer.Send(mailer.Message{To: u.Email, Subject: i18n.T(locale, "email.alert.subject", a.Kind), Body: body}); err != nil {
+				slog.Error("alert: email failed", "user", u.Username, "error", err)
+			}
+		}
+		notify.UserPush(u.ID, notify.PushMessage{Title: i18n.T(locale, "alert.title"), Body: msg, URL: url})
+	}
+}
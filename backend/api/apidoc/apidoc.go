@@ -0,0 +1,228 @@
+// Package apidoc 由已注册的路由生成 OpenAPI 3 文档：路径、方法与路径参数取自路由表，
+// 接口说明、认证要求与请求体取自 operations 中按处理函数登记的说明，请求体结构由请求结构体反射得到，
+// 新增接口无需手工维护路径即可出现在文档中
+package apidoc
+
+import (
+	"encoding/json"
+	"log"
+	"path"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BasePath 当前版本接口的路径前缀，/api 下的旧路径不写入文档
+const BasePath = "/api/v1"
+
+// Auth 接口的认证要求
+type Auth int
+
+const (
+	// AuthUser 需要登录会话 JWT 或 API Token（默认）
+	AuthUser Auth = iota
+	// AuthPublic 无需认证
+	AuthPublic
+	// AuthOptional 可匿名调用，携带 Token 时按该用户处理
+	AuthOptional
+)
+
+// Operation 接口说明
+type Operation struct {
+	Summary string
+	Auth    Auth
+	// Body JSON 请求体结构体的零值，nil 表示没有 JSON 请求体
+	Body any
+	// Form multipart/form-data 表单字段，file 为上传的文件
+	Form []string
+}
+
+// Build 生成 BasePath 下全部接口的 OpenAPI 文档（JSON）
+func Build(routes gin.RoutesInfo) []byte {
+	g := &schemaGen{defs: map[string]any{
+		"Envelope": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "msg"},
+			"properties": map[string]any{
+				"code": map[string]any{"type": "integer", "description": "0 表示成功"},
+				"msg":  map[string]any{"type": "string"},
+				"data": map[string]any{"description": "接口数据，结构因接口而异"},
+			},
+		},
+		"Error": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "msg"},
+			"properties": map[string]any{
+				"code":      map[string]any{"type": "integer", "description": "非 0"},
+				"msg":       map[string]any{"type": "string", "description": "按 Accept-Language 翻译后的错误信息"},
+				"msgKey":    map[string]any{"type": "string", "description": "未翻译的英文错误信息，可用于程序判断"},
+				"requestId": map[string]any{"type": "string"},
+			},
+		},
+	}}
+
+	paths := map[string]map[string]any{}
+	opIDs := map[string]int{}
+	for _, rt := range routes {
+		rel, ok := strings.CutPrefix(rt.Path, BasePath)
+		if !ok || !strings.HasPrefix(rel, "/") || rt.Method == "OPTIONS" || rt.Method == "HEAD" {
+			continue
+		}
+		name := handlerName(rt.HandlerFunc)
+		key := name
+		if name == "" {
+			key = rt.Method + " " + rel
+		}
+		op, documented := operations[key]
+		if !documented {
+			log.Printf("apidoc: %s %s (%s) is not documented", rt.Method, rel, key)
+		}
+
+		segments := strings.Split(strings.Trim(rel, "/"), "/")
+		var params []any
+		for i, seg := range segments {
+			if seg == "" || (seg[0] != ':' && seg[0] != '*') {
+				continue
+			}
+			p := map[string]any{"name": seg[1:], "in": "path", "required": true, "schema": map[string]any{"type": "string"}}
+			if seg[0] == '*' {
+				p["description"] = "以 / 开头的剩余路径"
+			}
+			params = append(params, p)
+			segments[i] = "{" + seg[1:] + "}"
+		}
+		oaPath := "/" + strings.Join(segments, "/")
+		// 阅读分享的接口可通过请求头传入访问密码
+		if segments[0] == "s" && rt.Method == "GET" {
+			params = append(params, map[string]any{"$ref": "#/components/parameters/SharePassword"})
+		}
+
+		id := operationID(name, rt.Method, segments)
+		opIDs[id]++
+		if n := opIDs[id]; n > 1 {
+			id += strconv.Itoa(n)
+		}
+		summary := op.Summary
+		if summary == "" {
+			summary = id
+		}
+		entry := map[string]any{
+			"operationId": id,
+			"summary":     summary,
+			"tags":        []string{segments[0]},
+			"responses": map[string]any{
+				"200":     map[string]any{"$ref": "#/components/responses/OK"},
+				"default": map[string]any{"$ref": "#/components/responses/Error"},
+			},
+		}
+		if len(params) > 0 {
+			entry["parameters"] = params
+		}
+		switch {
+		case segments[0] == "admin":
+			entry["description"] = "仅管理员可用"
+		case op.Auth == AuthPublic:
+			entry["security"] = []any{}
+		case op.Auth == AuthOptional:
+			entry["security"] = []any{map[string]any{}, map[string]any{"bearerAuth": []string{}}}
+		}
+		if op.Body != nil {
+			entry["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"application/json": map[string]any{"schema": g.schema(reflect.TypeOf(op.Body))}},
+			}
+		} else if len(op.Form) > 0 {
+			props := map[string]any{}
+			for _, f := range op.Form {
+				if f == "file" {
+					props[f] = map[string]any{"type": "string", "format": "binary"}
+				} else {
+					props[f] = map[string]any{"type": "string"}
+				}
+			}
+			entry["requestBody"] = map[string]any{
+				"required": true,
+				"content":  map[string]any{"multipart/form-data": map[string]any{"schema": map[string]any{"type": "object", "properties": props}}},
+			}
+		}
+		if paths[oaPath] == nil {
+			paths[oaPath] = map[string]any{}
+		}
+		paths[oaPath][strings.ToLower(rt.Method)] = entry
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "SiYuan Share API",
+			"version":     "v1",
+			"description": "思源笔记分享服务接口。成功响应为 {code: 0, msg, data}，失败时 code 非 0 并使用对应的 HTTP 状态码。/api 下保留与 /api/v1 相同的旧路径。",
+		},
+		"servers":  []any{map[string]any{"url": BasePath}},
+		"security": []any{map[string]any{"bearerAuth": []string{}}},
+		"paths":    paths,
+		"components": map[string]any{
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "登录返回的会话 JWT，或在控制面板创建的 API Token",
+				},
+			},
+			"parameters": map[string]any{
+				"SharePassword": map[string]any{
+					"name": "X-Share-Password", "in": "header", "required": false,
+					"description": "受密码保护的分享的访问密码",
+					"schema":      map[string]any{"type": "string"},
+				},
+			},
+			"responses": map[string]any{
+				"OK": map[string]any{
+					"description": "成功；下载与页面类接口直接返回文件内容",
+					"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Envelope"}}},
+				},
+				"Error": map[string]any{
+					"description": "请求错误",
+					"content":     map[string]any{"application/json": map[string]any{"schema": map[string]any{"$ref": "#/components/schemas/Error"}}},
+				},
+			},
+			"schemas": g.defs,
+		},
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Printf("apidoc: marshal OpenAPI document: %v", err)
+		return []byte("{}")
+	}
+	return data
+}
+
+// handlerName 处理函数的名称，匿名函数返回空字符串
+func handlerName(h gin.HandlerFunc) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := path.Ext(fn.Name())
+	if strings.HasPrefix(name, ".func") || len(name) < 2 {
+		return ""
+	}
+	return name[1:]
+}
+
+// operationID 以处理函数名作为 operationId，匿名处理函数按方法与路径生成
+func operationID(name, method string, segments []string) string {
+	if name != "" {
+		return strings.ToLower(name[:1]) + name[1:]
+	}
+	id := strings.ToLower(method)
+	for _, seg := range segments {
+		for _, part := range strings.FieldsFunc(seg, func(r rune) bool { return r == '-' || r == '{' || r == '}' || r == '.' }) {
+			id += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return id
+}
@@ -0,0 +1,248 @@
+package apidoc
+
+import (
+	"github.com/ZeroHawkeye/siyuan-share-api/controllers"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// operations 按处理函数名登记的接口说明，匿名处理函数以「方法 路径」（路径相对于 BasePath）登记；
+// 同一处理函数挂载在多个路径下时共用一条说明
+var operations = map[string]Operation{
+	// 服务状态与文档
+	"GET /health":             {Summary: "服务健康检查", Auth: AuthPublic},
+	"GET /auth/health":        {Summary: "校验会话或 API Token 是否有效"},
+	"GET /openapi.json":       {Summary: "OpenAPI 文档", Auth: AuthPublic},
+	"ResetTestData":           {Summary: "清空数据并重置 ID 序列（仅测试模式）", Auth: AuthPublic, Body: controllers.ResetTestDataRequest{}},
+	"GetSigningKey":           {Summary: "内容签名公钥", Auth: AuthPublic},
+	"OEmbed":                  {Summary: "由分享链接生成 oEmbed 嵌入信息", Auth: AuthPublic},
+	"GetVAPIDPublicKey":       {Summary: "浏览器推送的 VAPID 公钥", Auth: AuthPublic},
+	"SearchShares":            {Summary: "站内公开搜索", Auth: AuthPublic},
+	"Events":                  {Summary: "控制面板实时事件流（SSE）"},
+	"RegistrationInfo":        {Summary: "注册方式（是否开放注册、是否需要邀请码）", Auth: AuthPublic},
+	"GetChallengeInfo":        {Summary: "人机验证的服务商、站点密钥与要求验证的操作（未开启时 provider 为空）", Auth: AuthPublic},
+	"Register":                {Summary: "注册账号", Auth: AuthPublic, Body: controllers.RegisterRequest{}},
+	"Login":                   {Summary: "登录，返回会话 JWT", Auth: AuthPublic, Body: controllers.LoginRequest{}},
+	"Logout":                  {Summary: "注销当前会话"},
+	"VerifyEmail":             {Summary: "验证邮箱（token 由验证邮件提供）", Auth: AuthPublic},
+	"ResendVerification":      {Summary: "重新发送验证邮件"},
+	"ForgotPassword":          {Summary: "发送重置密码邮件", Auth: AuthPublic, Body: controllers.ForgotPasswordRequest{}},
+	"ResetPassword":           {Summary: "重置密码", Auth: AuthPublic, Body: controllers.ResetPasswordRequest{}},
+	"UnlockAccount":           {Summary: "通过锁定通知邮件解锁账号", Auth: AuthPublic},
+	"SetupTwoFactor":          {Summary: "生成两步验证密钥"},
+	"EnableTwoFactor":         {Summary: "开启两步验证", Body: controllers.TwoFactorCodeRequest{}},
+	"DisableTwoFactor":        {Summary: "关闭两步验证", Body: controllers.DisableTwoFactorRequest{}},
+	"RegenerateRecoveryCodes": {Summary: "重新生成两步验证恢复码", Body: controllers.TwoFactorCodeRequest{}},
+	"ListOIDCProviders":       {Summary: "第三方登录方式列表", Auth: AuthPublic},
+	"OIDCLogin":               {Summary: "跳转到第三方登录", Auth: AuthPublic},
+	"OIDCCallback":            {Summary: "第三方登录回调", Auth: AuthPublic},
+	"OIDCTwoFactor":           {Summary: "第三方登录的两步验证", Auth: AuthPublic, Body: controllers.OIDCTwoFactorRequest{}},
+
+	// 分享管理
+	"CreateShare":               {Summary: "创建分享，同一文档已有分享时更新内容", Body: controllers.CreateShareRequest{}},
+	"ImportShare":               {Summary: "从思源笔记导出的 zip（Markdown 或 HTML 及资源）创建分享，multipart 上传 file 与发布设置"},
+	"ListShares":                {Summary: "分页列出分享"},
+	"DeleteSharesBatch":         {Summary: "批量删除分享（移入回收站）", Body: controllers.BatchDeleteShareRequest{}},
+	"DeleteShare":               {Summary: "删除分享（移入回收站）"},
+	"UpdateShare":               {Summary: "更新分享设置（只修改传入的字段）", Body: controllers.UpdateShareRequest{}},
+	"BatchShares":               {Summary: "批量删除、停用、启用或延长分享", Body: controllers.BatchShareRequest{}},
+	"UpdateShareStatus":         {Summary: "切换草稿、发布、不公开列出状态", Body: controllers.UpdateShareStatusRequest{}},
+	"PreviewShare":              {Summary: "预览分享（包括草稿）"},
+	"UploadAsset":               {Summary: "上传分享引用的资源文件", Form: []string{"file", "path", "sha256", "size"}},
+	"ListAssets":                {Summary: "列出分享的资源文件"},
+	"CheckShareAssets":          {Summary: "列出正文引用但尚未上传的资源"},
+	"AttachTranscript":          {Summary: "为音视频资源关联字幕", Body: controllers.AttachTranscriptRequest{}},
+	"RemoveTranscript":          {Summary: "移除音视频资源的字幕"},
+	"SemanticSearch":            {Summary: "语义搜索自己的分享"},
+	"ReindexShares":             {Summary: "重建语义搜索索引"},
+	"RelatedShares":             {Summary: "内容相近的分享"},
+	"ListTrash":                 {Summary: "回收站中的分享"},
+	"EmptyTrash":                {Summary: "清空回收站"},
+	"RestoreTrashedShare":       {Summary: "从回收站恢复分享"},
+	"PurgeTrashedShare":         {Summary: "彻底删除回收站中的分享"},
+	"GetShareAccess":            {Summary: "受限分享的允许读者"},
+	"UpdateShareAccess":         {Summary: "设置受限分享的允许读者", Body: controllers.ShareAccessRequest{}},
+	"ListShareTermsAcceptances": {Summary: "同意使用条款的读者记录"},
+	"ListOwnerSnapshots":        {Summary: "外部链接存档"},
+	"ListOwnerAnnotations":      {Summary: "分享的全部读者批注"},
+	"ModerateAnnotation":        {Summary: "隐藏或恢复读者批注", Body: controllers.ModerateAnnotationRequest{}},
+	"ListOwnerComments":         {Summary: "分享的全部评论（包括待审核）"},
+	"ApproveComment":            {Summary: "通过评论审核"},
+	"DeleteComment":             {Summary: "删除评论"},
+	"GetNarration":              {Summary: "朗读音频的生成状态"},
+	"CreateNarration":           {Summary: "生成朗读音频"},
+	"DeleteNarration":           {Summary: "删除朗读音频"},
+	"ListTranslations":          {Summary: "分享的译文列表"},
+	"CreateTranslation":         {Summary: "机器翻译分享", Body: controllers.TranslationRequest{}},
+	"GetTranslation":            {Summary: "查看译文"},
+	"SaveTranslation":           {Summary: "保存人工修改的译文", Body: controllers.SaveTranslationRequest{}},
+	"DeleteTranslation":         {Summary: "删除译文"},
+	"GetLanguageCheck":          {Summary: "语法与拼写检查结果"},
+	"CreateLanguageCheck":       {Summary: "检查语法与拼写"},
+	"GetPrerender":              {Summary: "服务端预渲染状态"},
+	"TraceShareRender":          {Summary: "跟踪正文处理流程（试运行）", Body: controllers.TraceRenderRequest{}},
+	"GetSummary":                {Summary: "AI 摘要"},
+	"CreateSummary":             {Summary: "生成 AI 摘要"},
+	"SealShare":                 {Summary: "将分享的当前版本存证", Body: controllers.SealShareRequest{}},
+	"ListShareRevisions":        {Summary: "分享的历史版本"},
+	"GetShareRevision":          {Summary: "查看历史版本"},
+	"RollbackShare":             {Summary: "回滚到历史版本"},
+	"ExportShareBundle":         {Summary: "导出分享（正文与资源打包）"},
+	"CreateExport":              {Summary: "创建导出任务", Body: controllers.CreateExportRequest{}},
+	"ListExports":               {Summary: "导出任务列表"},
+	"GetExport":                 {Summary: "导出任务状态"},
+	"DownloadExport":            {Summary: "下载导出结果"},
+	"ExportShareViews":          {Summary: "导出浏览记录（CSV）"},
+	"GetShareVariantStats":      {Summary: "主题 A/B 测试各组数据"},
+	"GetShareFeedbackStats":     {Summary: "读者反馈汇总与文字反馈"},
+	"GetShareReadingStats":      {Summary: "字数、预计阅读时长与按标题统计的阅读漏斗"},
+	"ListOwnerForms":            {Summary: "正文中的表单与提交数"},
+	"ListFormSubmissions":       {Summary: "表单的提交"},
+	"ExportFormSubmissions":     {Summary: "导出表单的全部提交（CSV）"},
+	"DeleteFormSubmission":      {Summary: "删除一条表单提交"},
+	"ListSharePayments":         {Summary: "付费阅读订单与汇总"},
+	"DeleteShareFeedback":       {Summary: "删除读者反馈"},
+	"ListShortLinks":            {Summary: "分享的短链接"},
+	"CreateShortLink":           {Summary: "创建短链接", Body: controllers.CreateShortLinkRequest{}},
+	"GetShortLink":              {Summary: "短链接与点击统计"},
+	"UpdateShortLink":           {Summary: "修改短链接", Body: controllers.UpdateShortLinkRequest{}},
+	"DeleteShortLink":           {Summary: "删除短链接"},
+
+	// 主题、合集与自定义域名
+	"ListThemes":          {Summary: "自定义主题列表"},
+	"CreateTheme":         {Summary: "创建自定义主题", Body: controllers.ThemeRequest{}},
+	"GetTheme":            {Summary: "查看自定义主题"},
+	"UpdateTheme":         {Summary: "修改自定义主题", Body: controllers.ThemeRequest{}},
+	"DeleteTheme":         {Summary: "删除自定义主题"},
+	"ServeThemeCSS":       {Summary: "自定义主题样式表", Auth: AuthPublic},
+	"ListShareTemplates":  {Summary: "发布模板列表"},
+	"CreateShareTemplate": {Summary: "创建发布模板", Body: controllers.ShareTemplateRequest{}},
+	"GetShareTemplate":    {Summary: "查看发布模板"},
+	"UpdateShareTemplate": {Summary: "修改发布模板", Body: controllers.ShareTemplateRequest{}},
+	"DeleteShareTemplate": {Summary: "删除发布模板"},
+	"ListCollections":     {Summary: "合集列表"},
+	"CreateCollection":    {Summary: "创建合集", Body: controllers.CollectionRequest{}},
+	"UpdateCollection":    {Summary: "修改合集", Body: controllers.CollectionRequest{}},
+	"DeleteCollection":    {Summary: "删除合集"},
+	"GetCollection":       {Summary: "合集首页数据", Auth: AuthPublic},
+	"AskCollection":       {Summary: "向合集提问", Auth: AuthPublic, Body: controllers.AskRequest{}},
+	"ListDomains":         {Summary: "自定义域名列表"},
+	"CreateDomain":        {Summary: "绑定自定义域名", Body: controllers.DomainRequest{}},
+	"UpdateDomain":        {Summary: "修改自定义域名设置", Body: controllers.DomainRequest{}},
+	"VerifyDomain":        {Summary: "验证自定义域名的 DNS 记录"},
+	"DeleteDomain":        {Summary: "解绑自定义域名"},
+
+	// 当前用户
+	"Me":                         {Summary: "当前用户信息"},
+	"UpdateSettings":             {Summary: "修改用户设置", Body: controllers.UpdateSettingsRequest{}},
+	"GetNotificationSettings":    {Summary: "邮件通知设置"},
+	"UpdateNotificationSettings": {Summary: "修改邮件通知设置", Body: controllers.NotificationSettingsRequest{}},
+	"UpdateProfile":              {Summary: "修改用户名、邮箱或密码", Body: controllers.UpdateProfileRequest{}},
+	"DeleteAccount":              {Summary: "申请注销账号", Body: controllers.DeleteAccountRequest{}},
+	"CancelAccountDeletion":      {Summary: "撤销注销账号"},
+	"ListSessions":               {Summary: "登录会话列表"},
+	"RevokeAllSessions":          {Summary: "注销其他全部会话"},
+	"RevokeSession":              {Summary: "注销指定会话"},
+	"GetUsage":                   {Summary: "存储用量与配额"},
+	"GetAPIUsage":                {Summary: "接口用量"},
+	"GetBandwidth":               {Summary: "本月各分享的读者流量"},
+	"GetCapabilities":            {Summary: "服务端能力、限制与当前用户的配额状态（插件使用）"},
+	"ListTokens":                 {Summary: "API Token 列表"},
+	"CreateToken":                {Summary: "创建 API Token", Body: controllers.CreateTokenRequest{}},
+	"RefreshToken":               {Summary: "重新生成 API Token"},
+	"RevokeToken":                {Summary: "吊销 API Token"},
+	"ListPushSubscriptions":      {Summary: "浏览器推送订阅列表"},
+	"CreatePushSubscription":     {Summary: "添加浏览器推送订阅", Body: controllers.PushSubscriptionRequest{}},
+	"DeletePushSubscription":     {Summary: "删除浏览器推送订阅"},
+	"TestPush":                   {Summary: "发送测试推送"},
+
+	// 管理员
+	"GetUserQuota":    {Summary: "用户配额"},
+	"UpdateUserQuota": {Summary: "覆盖用户配额", Body: models.QuotaOverride{}},
+	"AdminUnlockUser": {Summary: "解锁被锁定的账号"},
+	"GetUserAPIUsage": {Summary: "用户接口用量"},
+	"AdminBindDomain": {Summary: "为用户绑定自定义域名（跳过 DNS 验证）", Body: controllers.DomainRequest{}},
+	"ListAPIUsage":    {Summary: "各用户接口用量"},
+	"ListRedirects":   {Summary: "重定向规则列表"},
+	"CreateRedirect":  {Summary: "添加重定向规则", Body: controllers.RedirectRequest{}},
+	"UpdateRedirect":  {Summary: "修改重定向规则", Body: controllers.RedirectRequest{}},
+	"DeleteRedirect":  {Summary: "删除重定向规则"},
+	"AdminStats":      {Summary: "实例统计"},
+	"StatsHistory":    {Summary: "实例统计历史"},
+	"ListJobs":        {Summary: "后台任务状态"},
+	"RunJob":          {Summary: "立即运行后台任务"},
+	"CreateBackup":    {Summary: "下载数据备份"},
+	"ExportSite":      {Summary: "导出静态站点（zip）"},
+	"ReloadConfig":    {Summary: "重新加载配置文件"},
+	"RunSQLQuery":     {Summary: "执行只读 SQL 查询"},
+	"ListReports":     {Summary: "读者举报列表"},
+	"ResolveReport":   {Summary: "处理读者举报", Body: controllers.ResolveReportRequest{}},
+	"ReinstateShare":  {Summary: "恢复被下架的分享"},
+	"ListInvites":     {Summary: "邀请码列表"},
+	"CreateInvite":    {Summary: "创建邀请码", Body: controllers.CreateInviteRequest{}},
+	"DeleteInvite":    {Summary: "删除邀请码"},
+
+	// 正文处理流程
+	"ContentPipelineStats":  {Summary: "正文处理各步骤的顺序与耗时统计"},
+	"AdminTraceShareRender": {Summary: "跟踪任意分享的正文处理流程（试运行）", Body: controllers.TraceRenderRequest{}},
+
+	// 全站公告
+	"ListAnnouncements":      {Summary: "当前显示的全站公告，scope 为 dashboard 或 shares", Auth: AuthOptional},
+	"DismissAnnouncement":    {Summary: "关闭公告，之后不再显示"},
+	"AdminListAnnouncements": {Summary: "全部公告（含关闭人数）"},
+	"CreateAnnouncement":     {Summary: "发布公告", Body: controllers.AnnouncementRequest{}},
+	"UpdateAnnouncement":     {Summary: "修改公告（resetDismissals=1 时清除关闭记录）", Body: controllers.AnnouncementRequest{}},
+	"DeleteAnnouncement":     {Summary: "删除公告"},
+
+	// 阅读分享（公开）
+	"GetShare":              {Summary: "分享内容与设置", Auth: AuthPublic},
+	"ServeAsset":            {Summary: "分享的资源文件", Auth: AuthPublic},
+	"GetShareTranslation":   {Summary: "分享的译文", Auth: AuthPublic},
+	"ListShareTranscripts":  {Summary: "音视频字幕列表", Auth: AuthPublic},
+	"ServeTranscript":       {Summary: "音视频字幕（WebVTT）", Auth: AuthPublic},
+	"ListShareDrawings":     {Summary: "白板绘图列表", Auth: AuthPublic},
+	"ServeDrawing":          {Summary: "白板绘图（SVG）", Auth: AuthPublic},
+	"ListShareMindmaps":     {Summary: "思维导图列表", Auth: AuthPublic},
+	"ServeMindmap":          {Summary: "思维导图（SVG）", Auth: AuthPublic},
+	"ListShareRenders":      {Summary: "预渲染的公式与图表列表", Auth: AuthPublic},
+	"GetOfflineManifest":    {Summary: "离线阅读清单：预缓存地址与内容版本", Auth: AuthPublic},
+	"ServeRender":           {Summary: "预渲染的公式或图表（SVG）", Auth: AuthPublic},
+	"ShareCalendar":         {Summary: "分享中的日期导出为日历（iCalendar）", Auth: AuthPublic},
+	"ShareCitations":        {Summary: "分享的参考文献", Auth: AuthPublic},
+	"GetShareSeal":          {Summary: "存证信息", Auth: AuthPublic},
+	"GetShareSealSource":    {Summary: "存证时的原文", Auth: AuthPublic},
+	"GetShareSignature":     {Summary: "内容签名", Auth: AuthPublic},
+	"GetShareSignedSource":  {Summary: "签名对应的原文", Auth: AuthPublic},
+	"VerifyShare":           {Summary: "校验内容与签名是否一致", Auth: AuthPublic, Body: controllers.VerifyShareRequest{}},
+	"ExportSharePDF":        {Summary: "导出 PDF", Auth: AuthPublic},
+	"ShareTables":           {Summary: "分享中的表格", Auth: AuthPublic},
+	"ShareTableCSV":         {Summary: "导出表格（CSV）", Auth: AuthPublic},
+	"ShareFlashcards":       {Summary: "闪卡", Auth: AuthPublic},
+	"ListShareSnapshots":    {Summary: "外部链接存档列表", Auth: AuthPublic},
+	"ServeSnapshot":         {Summary: "外部链接存档页面", Auth: AuthPublic},
+	"RecordShareRead":       {Summary: "上报阅读时长与深度", Auth: AuthPublic, Body: controllers.ShareReadRequest{}},
+	"GetReadingProgress":    {Summary: "查询读者的阅读进度", Auth: AuthPublic},
+	"SaveReadingProgress":   {Summary: "保存读者的阅读进度", Auth: AuthPublic, Body: controllers.ReadingProgressRequest{}},
+	"DeleteReadingProgress": {Summary: "清除读者的阅读进度", Auth: AuthPublic},
+	"AskShare":              {Summary: "向分享提问", Auth: AuthPublic, Body: controllers.AskRequest{}},
+	"RequestShareAccess":    {Summary: "受限分享：发送邮件登录链接", Auth: AuthPublic, Body: controllers.ShareAccessLinkRequest{}},
+	"VerifyShareAccess":     {Summary: "受限分享：验证邮件登录链接", Auth: AuthPublic, Body: controllers.ShareAccessVerifyRequest{}},
+	"AcceptShareTerms":      {Summary: "同意使用条款", Auth: AuthPublic, Body: controllers.AcceptShareTermsRequest{}},
+	"ListShareAnnotations":  {Summary: "公开的读者批注", Auth: AuthPublic},
+	"CreateAnnotation":      {Summary: "添加划线批注", Body: controllers.AnnotationRequest{}},
+	"UpdateAnnotation":      {Summary: "修改划线批注", Body: controllers.UpdateAnnotationRequest{}},
+	"DeleteAnnotation":      {Summary: "删除划线批注"},
+	"ListShareComments":     {Summary: "已通过审核的评论", Auth: AuthPublic},
+	"CreateComment":         {Summary: "发表评论（可匿名）", Auth: AuthOptional, Body: controllers.CommentRequest{}},
+	"ListSharePolls":        {Summary: "正文中的投票与实时结果", Auth: AuthOptional},
+	"VotePoll":              {Summary: "投票（再次投票时改为新的选项）", Auth: AuthOptional, Body: controllers.VoteRequest{}},
+	"ListShareForms":        {Summary: "正文中的表单", Auth: AuthPublic},
+	"SubmitForm":            {Summary: "提交表单", Auth: AuthPublic, Body: controllers.FormSubmitRequest{}},
+	"CreatePaymentOrder":    {Summary: "为付费分享创建订单", Auth: AuthPublic},
+	"GetPaymentOrder":       {Summary: "查询订单状态与解锁令牌", Auth: AuthPublic},
+	"PaymentWebhook":        {Summary: "支付平台回调（X-Payment-Timestamp、X-Payment-Nonce 与 X-Payment-Signature 签名）", Auth: AuthPublic, Body: controllers.PaymentWebhookRequest{}},
+	"SubmitFeedback":        {Summary: "提交「是否有帮助」反馈", Auth: AuthPublic, Body: controllers.FeedbackRequest{}},
+	"ReportShare":           {Summary: "举报分享（可匿名）", Auth: AuthOptional, Body: controllers.ReportShareRequest{}},
+	"SubscribeShare":        {Summary: "订阅分享更新", Auth: AuthPublic, Body: controllers.SubscribeRequest{}},
+	"ConfirmSubscription":   {Summary: "确认订阅", Auth: AuthPublic},
+	"Unsubscribe":           {Summary: "退订", Auth: AuthPublic},
+}
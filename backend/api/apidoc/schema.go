@@ -0,0 +1,137 @@
+package apidoc
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaGen 由 Go 类型生成 JSON Schema，具名结构体写入 defs 并以 $ref 引用
+type schemaGen struct {
+	defs map[string]any
+}
+
+// schema 返回类型 t 的 JSON Schema
+func (g *schemaGen) schema(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t == rawType:
+		return map[string]any{"description": "任意 JSON"}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]any{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			// 先占位，结构体递归引用自身时不会无限展开
+			g.defs[t.Name()] = map[string]any{}
+			g.defs[t.Name()] = g.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	}
+	return map[string]any{}
+}
+
+// object 按 json 与 binding 标签生成结构体的 object Schema，匿名嵌入的结构体字段展开到同一层
+func (g *schemaGen) object(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if f.Anonymous && name == "" {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					walk(ft)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			s := g.schema(f.Type)
+			if applyBinding(s, f.Tag.Get("binding")) {
+				required = append(required, name)
+			}
+			props[name] = s
+		}
+	}
+	walk(t)
+	obj := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		obj["required"] = required
+	}
+	return obj
+}
+
+// applyBinding 将 binding 校验规则中的 oneof、min、max 写入 Schema，返回字段是否必填
+func applyBinding(s map[string]any, binding string) (required bool) {
+	kind, _ := s["type"].(string)
+	for _, rule := range strings.Split(binding, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			s["enum"] = strings.Fields(arg)
+		case "email":
+			s["format"] = "email"
+		case "url":
+			s["format"] = "uri"
+		case "min", "max":
+			n, err := strconv.Atoi(arg)
+			if err != nil {
+				continue
+			}
+			switch kind {
+			case "integer", "number":
+				s[map[string]string{"min": "minimum", "max": "maximum"}[name]] = n
+			case "string":
+				s[name+"Length"] = n
+			case "array":
+				s[name+"Items"] = n
+			}
+		}
+	}
+	return required
+}
@@ -0,0 +1,216 @@
+// Package archive 为分享正文中引用的外部链接保存存档副本，防止原链接失效。
+// HTML 页面经正文提取后保存为精简的静态页面，PDF 原样保存。
+package archive
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/safehttp"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"gorm.io/gorm/clause"
+)
+
+var client = safehttp.NewClient(30 * time.Second)
+
+// 限制并发抓取数量
+var slots = make(chan struct{}, 2)
+
+// Enabled 是否启用链接存档 (links.archive)，启用后仍需在分享上单独开启
+func Enabled() bool {
+	return config.Get().Links.Archive
+}
+
+// maxBytes 单个存档的大小上限 (links.archive_max_bytes，默认 10MB)
+func maxBytes() int64 {
+	return config.Get().Links.ArchiveMaxBytes
+}
+
+// Snapshot 为分享正文中尚未存档（或上次失败）的外部链接创建存档任务，在后台抓取
+func Snapshot(shareID, content string) {
+	if !Enabled() || storage.Default == nil {
+		return
+	}
+	links := ExtractLinks(content)
+	if len(links) == 0 {
+		return
+	}
+
+	var existing []models.LinkSnapshot
+	models.DB.Where("share_id = ? AND url IN ?", shareID, links).Find(&existing)
+	done := map[string]bool{}
+	for _, s := range existing {
+		if s.Status != models.SnapshotFailed {
+			done[s.URL] = true
+		}
+	}
+
+	for _, link := range links {
+		if done[link] {
+			continue
+		}
+		snap := models.LinkSnapshot{ID: "snp_" + randHex(12), ShareID: shareID, URL: link, Status: models.SnapshotPending}
+		// 失败的存档重新发布时重试，沿用原记录
+		if err := models.DB.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "share_id"}, {Name: "url"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"status": models.SnapshotPending, "error": ""}),
+		}).Create(&snap).Error; err != nil {
+			log.Printf("link snapshot create failed (%s): %v", shareID, err)
+			continue
+		}
+		models.DB.Where("share_id = ? AND url = ?", shareID, link).First(&snap)
+		background.Go(func() {
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			capture(&snap)
+		})
+	}
+}
+
+// capture 抓取并保存单个链接的存档，结果写回记录
+func capture(snap *models.LinkSnapshot) {
+	ctx, cancel := context.WithTimeout(context.Background(), 45*time.Second)
+	defer cancel()
+
+	updates := map[string]interface{}{}
+	data, contentType, title, err := fetch(ctx, snap.URL)
+	if err == nil {
+		ext := ".html"
+		if contentType == "application/pdf" {
+			ext = ".pdf"
+		}
+		key := "snapshots/" + snap.ShareID + "/" + snap.ID + ext
+		err = storage.Default.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType)
+		if err == nil {
+			now := time.Now()
+			updates = map[string]interface{}{
+				"status": models.SnapshotOK, "error": "", "title": title, "content_type": contentType,
+				"storage_key": key, "size": len(data), "captured_at": &now,
+			}
+		}
+	}
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		updates = map[string]interface{}{"status": models.SnapshotFailed, "error": msg}
+		if !errors.Is(err, safehttp.ErrPrivateAddress) {
+			log.Printf("link snapshot for %s failed: %v", snap.URL, err)
+		}
+	}
+	if err := models.DB.Model(&models.LinkSnapshot{}).Where("id = ?", snap.ID).Updates(updates).Error; err != nil {
+		log.Printf("link snapshot save failed (%s): %v", snap.ID, err)
+	}
+}
+
+// fetch 下载页面：PDF 原样返回，HTML 提取正文后生成存档页面
+func fetch(ctx context.Context, rawURL string) ([]byte, string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, "", "", err
+	}
+	req.Header.Set("User-Agent", safehttp.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/pdf;q=0.9")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, "", "", fmt.Errorf("archive: %s", resp.Status)
+	}
+
+	limit := maxBytes()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, "", "", err
+	}
+	if int64(len(body)) > limit {
+		return nil, "", "", fmt.Errorf("archive: page exceeds %d bytes", limit)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "" {
+		mediaType = http.DetectContentType(body)
+		mediaType, _, _ = mime.ParseMediaType(mediaType)
+	}
+	switch mediaType {
+	case "application/pdf":
+		return body, "application/pdf", "", nil
+	case "text/html", "application/xhtml+xml":
+		title, content, err := extractReadable(bytes.NewReader(body), resp.Request.URL)
+		if err != nil {
+			return nil, "", "", err
+		}
+		if strings.TrimSpace(content) == "" {
+			return nil, "", "", errors.New("archive: no readable content")
+		}
+		if title == "" {
+			title = rawURL
+		}
+		page, err := renderPage(title, rawURL, content)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return page, "text/html; charset=utf-8", title, nil
+	default:
+		return nil, "", "", fmt.Errorf("archive: unsupported content type %s", mediaType)
+	}
+}
+
+var pageTemplate = template.Must(template.New("snapshot").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}（存档）</title>
+<style>
+body { max-width: 760px; margin: 0 auto; padding: 24px 16px 48px; font: 16px/1.75 -apple-system, BlinkMacSystemFont, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif; color: #262626; }
+.archive-banner { padding: 12px 16px; margin-bottom: 24px; background: #fffbe6; border: 1px solid #ffe58f; border-radius: 6px; font-size: 13px; color: #595959; word-break: break-all; }
+pre { overflow: auto; padding: 12px; background: #f6f8fa; border-radius: 6px; }
+blockquote { margin: 0; padding-left: 16px; border-left: 4px solid #e8e8e8; color: #595959; }
+table { border-collapse: collapse; } td, th { border: 1px solid #e8e8e8; padding: 4px 8px; }
+a { color: #1677ff; }
+</style>
+</head>
+<body>
+<div class="archive-banner">这是 <a href="{{.URL}}" rel="noopener noreferrer">{{.URL}}</a> 于 {{.CapturedAt}} 保存的存档副本，仅保留正文文字，可能与原页面不一致。</div>
+<h1>{{.Title}}</h1>
+{{.Content}}
+</body>
+</html>
+`))
+
+func renderPage(title, rawURL, content string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := pageTemplate.Execute(&buf, map[string]interface{}{
+		"Title":      title,
+		"URL":        rawURL,
+		"CapturedAt": time.Now().Format("2006-01-02 15:04"),
+		// content 由 extractReadable 生成，仅包含白名单标签且文本已转义
+		"Content": template.HTML(content),
+	})
+	return buf.Bytes(), err
+}
+
+func randHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// maxLinksPerShare 单个分享最多存档的链接数
+const maxLinksPerShare = 50
+
+var (
+	urlPattern   = regexp.MustCompile(`https?://[^\s<>"'()\[\]]+`)
+	imagePattern = regexp.MustCompile(`!\[[^\]]*\]\((https?://[^\s)]+)`)
+	inlineCode   = regexp.MustCompile("`[^`\n]*`")
+)
+
+// ExtractLinks 提取正文中引用的外部链接（Markdown 链接、尖括号链接与裸链接），忽略图片与代码
+func ExtractLinks(content string) []string {
+	seen := map[string]bool{}
+	var links []string
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = inlineCode.ReplaceAllString(line, "")
+		for _, m := range imagePattern.FindAllStringSubmatch(line, -1) {
+			seen[m[1]] = true
+		}
+		for _, link := range urlPattern.FindAllString(line, -1) {
+			link = strings.TrimRight(link, ".,;:!?。，；：！？")
+			if seen[link] {
+				continue
+			}
+			seen[link] = true
+			if u, err := url.Parse(link); err != nil || u.Host == "" {
+				continue
+			}
+			links = append(links, link)
+			if len(links) >= maxLinksPerShare {
+				return links
+			}
+		}
+	}
+	return links
+}
@@ -0,0 +1,204 @@
+package archive
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// 提取正文时整体丢弃的元素
+var droppedTags = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Iframe: true, atom.Object: true,
+	atom.Embed: true, atom.Nav: true, atom.Header: true, atom.Footer: true, atom.Aside: true,
+	atom.Form: true, atom.Button: true, atom.Input: true, atom.Select: true, atom.Textarea: true,
+	atom.Svg: true, atom.Canvas: true, atom.Template: true, atom.Dialog: true,
+}
+
+// 输出时保留的元素，其余元素只保留其内容
+var keptTags = map[atom.Atom]bool{
+	atom.P: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Li: true, atom.Blockquote: true, atom.Pre: true, atom.Code: true,
+	atom.Em: true, atom.Strong: true, atom.B: true, atom.I: true, atom.A: true, atom.Br: true, atom.Hr: true,
+	atom.Table: true, atom.Thead: true, atom.Tbody: true, atom.Tr: true, atom.Td: true, atom.Th: true,
+	atom.Figure: true, atom.Figcaption: true, atom.Sup: true, atom.Sub: true, atom.Dl: true, atom.Dt: true, atom.Dd: true,
+}
+
+// 块级元素：去掉标签时补换行，避免相邻文字粘连
+var blockTags = map[atom.Atom]bool{
+	atom.Div: true, atom.Section: true, atom.Article: true, atom.Main: true,
+}
+
+// extractReadable 提取页面标题与正文，返回只包含基础排版标签的 HTML 片段
+func extractReadable(r io.Reader, base *url.URL) (string, string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", "", err
+	}
+
+	var title string
+	var body *html.Node
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Title:
+				if title == "" {
+					title = strings.Join(strings.Fields(textContent(n)), " ")
+				}
+			case atom.Body:
+				body = n
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+	if body == nil {
+		return title, "", nil
+	}
+	prune(body)
+
+	root := findContentRoot(body)
+	var b strings.Builder
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		render(&b, c, base)
+	}
+	return title, b.String(), nil
+}
+
+// prune 移除脚本、导航、页眉页脚等非正文元素
+func prune(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode || (c.Type == html.ElementNode && (droppedTags[c.DataAtom] || isHidden(c))) {
+			n.RemoveChild(c)
+		} else {
+			prune(c)
+		}
+		c = next
+	}
+}
+
+func isHidden(n *html.Node) bool {
+	for _, a := range n.Attr {
+		switch a.Key {
+		case "hidden":
+			return true
+		case "aria-hidden":
+			return a.Val == "true"
+		case "role":
+			return a.Val == "navigation" || a.Val == "banner" || a.Val == "contentinfo" || a.Val == "complementary"
+		}
+	}
+	return false
+}
+
+// findContentRoot 优先使用 <article> / <main>，否则选择直接包含段落文字最多的元素
+func findContentRoot(body *html.Node) *html.Node {
+	var article, main *html.Node
+	scores := map[*html.Node]int{}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case n.DataAtom == atom.Article && article == nil:
+				article = n
+			case (n.DataAtom == atom.Main || attr(n, "role") == "main") && main == nil:
+				main = n
+			case n.DataAtom == atom.P && n.Parent != nil:
+				scores[n.Parent] += len(strings.TrimSpace(textContent(n)))
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(body)
+	if article != nil {
+		return article
+	}
+	if main != nil {
+		return main
+	}
+	best, bestScore := body, 0
+	for n, score := range scores {
+		if score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	// 正文过短时（非文章类页面）保留整个 body
+	if bestScore < 200 {
+		return body
+	}
+	return best
+}
+
+func render(b *strings.Builder, n *html.Node, base *url.URL) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	keep := keptTags[n.DataAtom]
+	if keep {
+		b.WriteString("<" + n.Data)
+		if n.DataAtom == atom.A {
+			if href := absoluteURL(base, attr(n, "href")); href != "" {
+				b.WriteString(` href="` + html.EscapeString(href) + `" rel="noopener noreferrer"`)
+			}
+		}
+		b.WriteString(">")
+		if n.DataAtom == atom.Br || n.DataAtom == atom.Hr {
+			return
+		}
+	} else if blockTags[n.DataAtom] {
+		b.WriteString("\n")
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		render(b, c, base)
+	}
+	if keep {
+		b.WriteString("</" + n.Data + ">")
+	} else if blockTags[n.DataAtom] {
+		b.WriteString("\n")
+	}
+}
+
+func absoluteURL(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	u, err := base.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,61 @@
+// Package background 跟踪写数据库的后台任务（通知投递、导出、链接预览与存档），
+// 进程退出前停止接收新任务并等待已登记的任务完成，避免关闭数据库时丢失写入。
+package background
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+)
+
+// Add 登记一个稍后执行的任务（如进入队列），开始退出后返回 false，调用方应放弃该任务
+func Add() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	if draining {
+		return false
+	}
+	wg.Add(1)
+	return true
+}
+
+// Done 标记通过 Add 登记的任务已完成
+func Done() {
+	wg.Done()
+}
+
+// Go 在后台执行 f，开始退出后不再执行并返回 false
+func Go(f func()) bool {
+	if !Add() {
+		return false
+	}
+	go func() {
+		defer Done()
+		f()
+	}()
+	return true
+}
+
+// Drain 停止接收新任务并等待已登记的任务完成，ctx 到期时返回其错误
+func Drain(ctx context.Context) error {
+	mu.Lock()
+	draining = true
+	mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
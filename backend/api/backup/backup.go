@@ -0,0 +1,288 @@
+// Package backup 生成与恢复实例备份：SQLite 数据库以 VACUUM INTO 在线生成一致的快照（WAL 模式下直接复制数据库文件并不安全），
+// 连同快照中引用的存储对象（资源文件、图片副本、外置正文、导出文件与链接存档）打包为 tar.gz
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 备份包中的文件
+const (
+	databaseEntry = "database.db"
+	manifestEntry = "manifest.json"
+	storagePrefix = "storage/"
+)
+
+// formatVersion 备份包格式版本
+const formatVersion = 1
+
+// contentTypeRecord 存储对象的内容类型，保存在 tar 的 PAX 扩展头中
+const contentTypeRecord = "SIYUAN.content_type"
+
+// Manifest 备份包说明，位于包的末尾
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Schema    string    `json:"schema"`            // 快照时已执行的最新迁移
+	Objects   int       `json:"objects"`           // 打包的存储对象数
+	Bytes     int64     `json:"bytes"`             // 存储对象总大小
+	Missing   []string  `json:"missing,omitempty"` // 数据库引用但存储中不存在的对象
+}
+
+// Snapshot 数据库快照，写出备份包后应调用 Close 删除临时文件
+type Snapshot struct {
+	path   string
+	keys   []string
+	schema string
+}
+
+// Take 以 VACUUM INTO 生成数据库快照，并从快照中读取引用的存储对象（assets 为 false 时不包含存储对象）
+func Take(ctx context.Context, assets bool) (*Snapshot, error) {
+	dir := filepath.Dir(config.Get().DBPath())
+	f, err := os.CreateTemp(dir, ".backup-*.db")
+	if err != nil {
+		return nil, err
+	}
+	path := f.Name()
+	f.Close()
+	// VACUUM INTO 要求目标文件不存在
+	os.Remove(path)
+
+	if err := models.DB.WithContext(ctx).Exec("VACUUM INTO ?", path).Error; err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	s := &Snapshot{path: path}
+	if err := s.inspect(assets); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// inspect 从快照中读取最新迁移与引用的存储对象
+func (s *Snapshot) inspect(assets bool) error {
+	// 加密数据库的快照沿用主库的密钥
+	dialector, err := models.Dialector(s.path)
+	if err != nil {
+		return err
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	db.Raw("SELECT id FROM schema_migrations ORDER BY id DESC LIMIT 1").Scan(&s.schema)
+	if !assets {
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, q := range []string{
+		"SELECT storage_key FROM assets",
+		"SELECT storage_key FROM asset_variants",
+		"SELECT content_key FROM shares",
+		"SELECT storage_key FROM export_jobs",
+		"SELECT storage_key FROM link_snapshots",
+	} {
+		var keys []string
+		if err := db.Raw(q).Scan(&keys).Error; err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if k != "" && !seen[k] {
+				seen[k] = true
+				s.keys = append(s.keys, k)
+			}
+		}
+	}
+	return nil
+}
+
+// Close 删除快照临时文件
+func (s *Snapshot) Close() {
+	os.Remove(s.path)
+}
+
+// WriteTo 将快照与存储对象打包为 tar.gz 写入 w；对象在存储中不存在时记录在 Manifest.Missing 中
+func (s *Snapshot) WriteTo(ctx context.Context, w io.Writer) (*Manifest, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	m := &Manifest{Version: formatVersion, CreatedAt: time.Now().UTC(), Schema: s.schema}
+
+	if err := addFile(tw, databaseEntry, s.path); err != nil {
+		return nil, err
+	}
+	for _, key := range s.keys {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		size, err := addObject(ctx, tw, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			m.Missing = append(m.Missing, key)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		m.Objects++
+		m.Bytes += size
+	}
+
+	data, _ := json.MarshalIndent(m, "", "  ")
+	if err := tw.WriteHeader(&tar.Header{Name: manifestEntry, Mode: 0644, Size: int64(len(data)), ModTime: m.CreatedAt}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return m, gz.Close()
+}
+
+func addFile(tw *tar.Writer, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: st.Size(), ModTime: st.ModTime()}); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// addObject 写入一个存储对象；大小未知时（部分 S3 兼容服务不返回 Content-Length）先读入内存
+func addObject(ctx context.Context, tw *tar.Writer, key string) (int64, error) {
+	rc, info, err := storage.Default.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	var r io.Reader = rc
+	size := info.Size
+	if size < 0 {
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return 0, err
+		}
+		r, size = bytes.NewReader(data), int64(len(data))
+	}
+	hdr := &tar.Header{Name: storagePrefix + key, Mode: 0644, Size: size, ModTime: time.Now()}
+	if info.ContentType != "" {
+		hdr.PAXRecords = map[string]string{contentTypeRecord: info.ContentType}
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return 0, err
+	}
+	_, err = io.Copy(tw, r)
+	return size, err
+}
+
+// Write 生成快照并将备份包写入 w
+func Write(ctx context.Context, w io.Writer, assets bool) (*Manifest, error) {
+	s, err := Take(ctx, assets)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	return s.WriteTo(ctx, w)
+}
+
+// FileName 备份包的文件名，按创建时间（UTC）命名
+func FileName(t time.Time) string {
+	return "siyuan-share-" + t.UTC().Format("20060102-150405") + ".tar.gz"
+}
+
+// Push 生成备份包并保存到 backup 配置的位置：配置了 backup.s3.bucket 时上传到 S3，否则写入 backup.dir 并按 backup.keep 清理旧备份。
+// 返回保存位置
+func Push(ctx context.Context) (string, error) {
+	cfg := config.Get().Backup
+	name := FileName(time.Now())
+	if err := os.MkdirAll(cfg.Dir, 0700); err != nil {
+		return "", err
+	}
+	f, err := os.CreateTemp(cfg.Dir, ".pending-*.tar.gz")
+	if err != nil {
+		return "", err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+	_, werr := Write(ctx, f, cfg.Assets)
+	if err := f.Close(); werr == nil {
+		werr = err
+	}
+	if werr != nil {
+		return "", werr
+	}
+
+	if cfg.S3.Bucket != "" {
+		s3, err := storage.NewS3(cfg.S3.S3Config())
+		if err != nil {
+			return "", err
+		}
+		f, err := os.Open(tmp)
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		st, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+		if err := s3.Put(ctx, name, f, st.Size(), "application/gzip"); err != nil {
+			return "", err
+		}
+		return "s3://" + cfg.S3.Bucket + "/" + strings.TrimLeft(strings.Trim(cfg.S3.Prefix, "/")+"/"+name, "/"), nil
+	}
+
+	dest := filepath.Join(cfg.Dir, name)
+	if err := os.Rename(tmp, dest); err != nil {
+		return "", err
+	}
+	prune(cfg.Dir, cfg.Keep)
+	return dest, nil
+}
+
+// prune 仅保留目录中最新的 keep 个备份包
+func prune(dir string, keep int) {
+	if keep <= 0 {
+		return
+	}
+	names, err := filepath.Glob(filepath.Join(dir, "siyuan-share-*.tar.gz"))
+	if err != nil || len(names) <= keep {
+		return
+	}
+	// 文件名中的时间戳按字典序即为时间顺序
+	for _, name := range names[:len(names)-keep] {
+		if err := os.Remove(name); err != nil {
+			log.Printf("backup cleanup failed (%s): %v", name, err)
+		}
+	}
+}
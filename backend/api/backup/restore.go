@@ -0,0 +1,99 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// Restore 从备份包恢复：存储对象写回 store，数据库快照替换 dbPath。原数据库文件（含 -wal、-shm）
+// 改名为 <dbPath>.pre-restore-<时间> 保留；必须在服务停止时执行。返回备份包的说明
+func Restore(ctx context.Context, r io.Reader, dbPath string, store storage.Storage) (*Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	// 数据库先写入临时文件，整个备份包读取成功后再替换
+	tmp := dbPath + ".restore"
+	defer os.Remove(tmp)
+	var manifest *Manifest
+	hasDB := false
+
+	tr := tar.NewReader(gz)
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case hdr.Name == databaseEntry:
+			if err := writeFile(tmp, tr); err != nil {
+				return nil, err
+			}
+			hasDB = true
+		case hdr.Name == manifestEntry:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+		case strings.HasPrefix(hdr.Name, storagePrefix):
+			key, err := storage.CleanKey(strings.TrimPrefix(hdr.Name, storagePrefix))
+			if err != nil {
+				return nil, err
+			}
+			if err := store.Put(ctx, key, tr, hdr.Size, hdr.PAXRecords[contentTypeRecord]); err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+	if !hasDB || manifest == nil {
+		return nil, errors.New("incomplete backup archive: database or manifest missing")
+	}
+	if manifest.Version > formatVersion {
+		return nil, fmt.Errorf("backup format version %d is newer than this server supports", manifest.Version)
+	}
+
+	suffix := ".pre-restore-" + time.Now().Format("20060102-150405")
+	for _, ext := range []string{"", "-wal", "-shm"} {
+		if err := os.Rename(dbPath+ext, dbPath+suffix+ext); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp, dbPath); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,182 @@
+// Package cache 热点数据缓存：读者访问的分享记录与渲染后的正文缓存在此，热门分享不必每次查询数据库。
+// 默认为进程内 LRU；配置 cache.redis_url 后使用 Redis，多个实例共享缓存，失效对所有实例生效。
+// 缓存只是加速手段，读写失败时按未命中处理。
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// Store 缓存后端
+type Store interface {
+	// Get 读取条目，不存在时返回 false
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set 写入条目，ttl 后过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete 删除条目，不存在时不报错
+	Delete(ctx context.Context, keys ...string) error
+	// Incr 计数器加一并返回新值（不过期）
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+const (
+	// keyPrefix 全部键的前缀，与共用 Redis 的其他应用区分
+	keyPrefix = "siyuan-share:"
+	// genKey 缓存代数，整体失效时递增，条目键中带有代数
+	genKey = keyPrefix + "gen"
+	// genRefresh 使用 Redis 时重新读取代数的间隔，其他实例的整体失效至多延迟这么久生效
+	genRefresh = time.Second
+	// opTimeout 单次缓存操作的超时
+	opTimeout = 500 * time.Millisecond
+)
+
+var (
+	store     Store
+	ttl       time.Duration
+	shared    bool         // 后端由多个实例共享 (Redis)
+	gen       atomic.Int64 // 当前缓存代数
+	genLoaded atomic.Int64 // 最近一次读取代数的时间 (UnixNano)
+	lastError atomic.Int64 // 最近一次记录错误日志的时间 (UnixNano)
+)
+
+// Init 根据配置初始化缓存：配置 cache.redis_url 时使用 Redis，否则 cache.size > 0 时使用进程内 LRU
+func Init() error {
+	cfg := config.Get().Cache
+	store, shared, ttl = nil, false, cfg.TTL.Std()
+	switch {
+	case cfg.RedisURL != "":
+		r, err := NewRedis(cfg.RedisURL)
+		if err != nil {
+			return err
+		}
+		store, shared = r, true
+		log.Printf("Cache: redis (%s)", r.addr)
+	case cfg.Size > 0:
+		store = NewLRU(cfg.Size)
+		log.Printf("Cache: in-process LRU (%d entries)", cfg.Size)
+	}
+	return nil
+}
+
+// Enabled 是否启用了缓存
+func Enabled() bool {
+	return store != nil
+}
+
+// logError 记录缓存后端错误，每分钟至多一条，避免后端不可用时刷屏
+func logError(op string, err error) {
+	now := time.Now().UnixNano()
+	last := lastError.Load()
+	if now-last < int64(time.Minute) || !lastError.CompareAndSwap(last, now) {
+		return
+	}
+	log.Printf("cache %s failed: %v", op, err)
+}
+
+// generation 当前缓存代数；使用 Redis 时每秒至多读取一次
+func generation(ctx context.Context) int64 {
+	if !shared {
+		return gen.Load()
+	}
+	now := time.Now().UnixNano()
+	if last := genLoaded.Load(); now-last >= int64(genRefresh) && genLoaded.CompareAndSwap(last, now) {
+		v, ok, err := store.Get(ctx, genKey)
+		if err != nil {
+			logError("get", err)
+		} else if ok {
+			if n, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+				gen.Store(n)
+			}
+		}
+	}
+	return gen.Load()
+}
+
+func entryKey(ctx context.Context, key string) string {
+	return keyPrefix + strconv.FormatInt(generation(ctx), 10) + ":" + key
+}
+
+// Load 读取缓存并以 gob 解码到 v，未命中或出错时返回 false
+func Load(key string, v interface{}) bool {
+	if store == nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	data, ok, err := store.Get(ctx, entryKey(ctx, key))
+	if err != nil {
+		logError("get", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(v); err != nil {
+		logError("decode", err)
+		return false
+	}
+	return true
+}
+
+// Save 以 gob 编码缓存 v，在 cache.ttl 后过期
+func Save(key string, v interface{}) {
+	if store == nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		logError("encode", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	if err := store.Set(ctx, entryKey(ctx, key), buf.Bytes(), ttl); err != nil {
+		logError("set", err)
+	}
+}
+
+// Delete 删除缓存条目
+func Delete(keys ...string) {
+	if store == nil || len(keys) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	full := make([]string, len(keys))
+	for i, k := range keys {
+		full[i] = entryKey(ctx, k)
+	}
+	if err := store.Delete(ctx, full...); err != nil {
+		logError("delete", err)
+	}
+}
+
+// Flush 使全部缓存失效：递增缓存代数，旧条目不再命中并随有效期过期
+func Flush() {
+	if store == nil {
+		return
+	}
+	if !shared {
+		gen.Add(1)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	defer cancel()
+	n, err := store.Incr(ctx, genKey)
+	if err != nil {
+		logError("incr", err)
+		// 至少让本实例不再命中旧条目
+		gen.Add(1)
+		return
+	}
+	gen.Store(n)
+	genLoaded.Store(time.Now().UnixNano())
+}
@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LRU 进程内缓存：条目数超过上限时淘汰最近最少使用的条目
+type LRU struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time // 零值表示不过期
+}
+
+// NewLRU 创建最多保存 size 个条目的进程内缓存
+func NewLRU(size int) *LRU {
+	return &LRU{size: size, items: map[string]*list.Element{}, order: list.New()}
+}
+
+// Get 读取条目，已过期的条目视为不存在
+func (l *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		l.order.Remove(el)
+		delete(l.items, key)
+		return nil, false, nil
+	}
+	l.order.MoveToFront(el)
+	return e.value, true, nil
+}
+
+// Set 写入条目
+func (l *LRU) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.set(key, value, expires)
+	return nil
+}
+
+func (l *LRU) set(key string, value []byte, expires time.Time) {
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*lruEntry)
+		e.value, e.expires = value, expires
+		l.order.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Delete 删除条目
+func (l *LRU) Delete(_ context.Context, keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := l.items[key]; ok {
+			l.order.Remove(el)
+			delete(l.items, key)
+		}
+	}
+	return nil
+}
+
+// Incr 计数器加一并返回新值
+func (l *LRU) Incr(_ context.Context, key string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var n int64
+	if el, ok := l.items[key]; ok {
+		n, _ = strconv.ParseInt(string(el.Value.(*lruEntry).value), 10, 64)
+	}
+	n++
+	l.set(key, []byte(strconv.FormatInt(n, 10)), time.Time{})
+	return n, nil
+}
@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// redisPoolSize 空闲连接数上限
+	redisPoolSize = 16
+	// redisDialTimeout 建立连接的超时
+	redisDialTimeout = 3 * time.Second
+	// maxBulkBytes 单个响应值的大小上限
+	maxBulkBytes = 64 << 20
+)
+
+// redisError Redis 返回的错误响应，连接仍可继续使用
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// Redis 最小的 Redis 客户端（RESP 协议），只实现缓存用到的 GET/SET/DEL/INCR 与定时任务选主用到的 EVAL
+type Redis struct {
+	addr     string
+	username string
+	password string
+	db       int
+	tls      bool
+	pool     chan *redisConn
+}
+
+type redisConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+// NewRedis 根据 redis://[用户名:密码@]主机:端口[/库号] 创建客户端，rediss:// 使用 TLS；连接在首次使用时建立
+func NewRedis(raw string) (*Redis, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "redis" && u.Scheme != "rediss" {
+		return nil, errors.New("unsupported redis URL scheme: " + u.Scheme)
+	}
+	r := &Redis{addr: u.Host, tls: u.Scheme == "rediss", pool: make(chan *redisConn, redisPoolSize)}
+	if u.Port() == "" {
+		r.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		r.username = u.User.Username()
+		r.password, _ = u.User.Password()
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if r.db, err = strconv.Atoi(db); err != nil {
+			return nil, errors.New("invalid redis database: " + db)
+		}
+	}
+	return r, nil
+}
+
+// dial 建立连接并完成认证与选库
+func (r *Redis) dial(ctx context.Context) (*redisConn, error) {
+	d := net.Dialer{Timeout: redisDialTimeout}
+	var conn net.Conn
+	var err error
+	if r.tls {
+		host, _, _ := net.SplitHostPort(r.addr)
+		conn, err = (&tls.Dialer{NetDialer: &d, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}).DialContext(ctx, "tcp", r.addr)
+	} else {
+		conn, err = d.DialContext(ctx, "tcp", r.addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{Conn: conn, r: bufio.NewReader(conn)}
+	if deadline, ok := ctx.Deadline(); ok {
+		c.SetDeadline(deadline)
+	}
+	var setup [][]string
+	switch {
+	case r.username != "":
+		setup = append(setup, []string{"AUTH", r.username, r.password})
+	case r.password != "":
+		setup = append(setup, []string{"AUTH", r.password})
+	}
+	if r.db != 0 {
+		setup = append(setup, []string{"SELECT", strconv.Itoa(r.db)})
+	}
+	for _, args := range setup {
+		if _, err := c.do(args); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// do 在连接池中的连接上执行命令
+func (r *Redis) do(ctx context.Context, args ...string) (interface{}, error) {
+	var c *redisConn
+	select {
+	case c = <-r.pool:
+	default:
+		var err error
+		if c, err = r.dial(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		c.SetDeadline(deadline)
+	} else {
+		c.SetDeadline(time.Time{})
+	}
+	reply, err := c.do(args)
+	var re redisError
+	if err != nil && !errors.As(err, &re) {
+		// 网络或协议错误后连接状态未知，不再复用
+		c.Close()
+		return nil, err
+	}
+	select {
+	case r.pool <- c:
+	default:
+		c.Close()
+	}
+	return reply, err
+}
+
+// do 发送命令并读取响应
+func (c *redisConn) do(args []string) (interface{}, error) {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, a := range args {
+		b.WriteString("$" + strconv.Itoa(len(a)) + "\r\n" + a + "\r\n")
+	}
+	if _, err := io.WriteString(c.Conn, b.String()); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// read 读取一个 RESP 响应：简单字符串与整数、批量字符串（[]byte，不存在时为 nil）、数组
+func (c *redisConn) read() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, errors.New("redis: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n > maxBulkBytes {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
+
+// Get 读取条目
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := r.do(ctx, "GET", key)
+	if err != nil || reply == nil {
+		return nil, false, err
+	}
+	data, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return data, true, nil
+}
+
+// Set 写入条目，ttl 以毫秒精度设置过期
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		args = append(args, "PX", strconv.FormatInt(ttl.Milliseconds(), 10))
+	}
+	_, err := r.do(ctx, args...)
+	return err
+}
+
+// Delete 删除条目
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	_, err := r.do(ctx, append([]string{"DEL"}, keys...)...)
+	return err
+}
+
+// Incr 计数器加一并返回新值
+func (r *Redis) Incr(ctx context.Context, key string) (int64, error) {
+	reply, err := r.do(ctx, "INCR", key)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return 0, fmt.Errorf("redis: unexpected INCR reply %T", reply)
+	}
+	return n, nil
+}
+
+// Eval 执行 Lua 脚本，脚本在 Redis 中原子执行
+func (r *Redis) Eval(ctx context.Context, script string, keys []string, args ...string) (interface{}, error) {
+	cmd := append([]string{"EVAL", script, strconv.Itoa(len(keys))}, keys...)
+	return r.do(ctx, append(cmd, args...)...)
+}
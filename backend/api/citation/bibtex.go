@@ -0,0 +1,100 @@
+package citation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// bibtexTypes CSL 条目类型对应的 BibTeX 类型，未列出的类型输出为 misc
+var bibtexTypes = map[string]string{
+	"article":           "article",
+	"article-journal":   "article",
+	"article-magazine":  "article",
+	"article-newspaper": "article",
+	"book":              "book",
+	"chapter":           "incollection",
+	"paper-conference":  "inproceedings",
+	"report":            "techreport",
+	"thesis":            "phdthesis",
+	"manuscript":        "unpublished",
+}
+
+var (
+	texKeyCleaner = regexp.MustCompile(`[^A-Za-z0-9_:.+\-/]+`)
+	bibEscaper    = strings.NewReplacer(
+		`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "&", `\&`, "%", `\%`, "$", `\$`,
+		"#", `\#`, "_", `\_`, "~", `\textasciitilde{}`, "^", `\textasciicircum{}`,
+	)
+)
+
+// TeXKey 转换为 BibTeX 可用的引用键（BibTeX 不允许 #%~ 等字符）
+func TeXKey(key string) string {
+	return strings.Trim(texKeyCleaner.ReplaceAllString(key, "-"), "-")
+}
+
+// BibTeX 将文献条目输出为 .bib 文件内容
+func BibTeX(items []Item) string {
+	var b strings.Builder
+	for i := range items {
+		it := &items[i]
+		typ, ok := bibtexTypes[it.Type]
+		if !ok {
+			typ = "misc"
+		}
+		b.WriteString("@" + typ + "{" + TeXKey(it.ID) + ",\n")
+		field := func(name, value string) {
+			if value = strings.TrimSpace(value); value != "" {
+				b.WriteString("  " + name + " = {" + value + "},\n")
+			}
+		}
+		field("author", bibNames(it.Author))
+		field("editor", bibNames(it.Editor))
+		// 标题额外加一层括号，避免样式改变大小写
+		if it.Title != "" {
+			field("title", "{"+bibEscaper.Replace(it.Title)+"}")
+		}
+		switch typ {
+		case "article":
+			field("journal", bibEscaper.Replace(it.ContainerTitle))
+		case "incollection", "inproceedings":
+			field("booktitle", bibEscaper.Replace(it.ContainerTitle))
+		default:
+			if it.ContainerTitle != "" {
+				field("howpublished", bibEscaper.Replace(it.ContainerTitle))
+			}
+		}
+		if year := it.Year(); year != "n.d." {
+			field("year", year)
+		}
+		field("volume", bibEscaper.Replace(it.Volume))
+		field("number", bibEscaper.Replace(it.Issue))
+		field("pages", strings.ReplaceAll(bibEscaper.Replace(it.Page), "-", "--"))
+		if typ == "phdthesis" || typ == "techreport" {
+			field("school", bibEscaper.Replace(it.Publisher))
+		} else {
+			field("publisher", bibEscaper.Replace(it.Publisher))
+		}
+		field("doi", strings.TrimPrefix(strings.TrimPrefix(it.DOI, "https://doi.org/"), "doi:"))
+		field("url", strings.NewReplacer("%", `\%`, "#", `\#`).Replace(it.URL))
+		b.WriteString("}\n\n")
+	}
+	return b.String()
+}
+
+// bibNames 输出 "Family, Given and ..." 形式的姓名列表，机构名整体加括号
+func bibNames(names []Name) string {
+	parts := make([]string, 0, len(names))
+	for _, n := range names {
+		switch {
+		case n.Family != "" && n.Given != "":
+			parts = append(parts, bibEscaper.Replace(n.Family)+", "+bibEscaper.Replace(n.Given))
+		case n.Family != "":
+			parts = append(parts, bibEscaper.Replace(n.Family))
+		case n.Literal != "":
+			parts = append(parts, "{"+bibEscaper.Replace(n.Literal)+"}")
+		case n.Given != "":
+			parts = append(parts, bibEscaper.Replace(n.Given))
+		}
+	}
+	return strings.Join(parts, " and ")
+}
@@ -0,0 +1,187 @@
+package citation
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// 引用簇：[@a]、[see @a, p. 3; -@b]，不匹配紧跟 ( 的 Markdown 链接文字
+	clusterPattern = regexp.MustCompile(`\[([^\[\]]*-?@[^\[\]]+)\]`)
+	// Pandoc citekey：以字母、数字或下划线开头，中间可含 :.#$%&-+?<>~/
+	keyPattern    = regexp.MustCompile(`(-?)@([\p{L}\p{N}_](?:[\p{L}\p{N}_:.#$%&\-+?<>~/]*[\p{L}\p{N}_])?)`)
+	anchorCleaner = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+)
+
+// Cite 引用簇中的单条引用
+type Cite struct {
+	Key            string
+	Prefix         string
+	Locator        string
+	SuppressAuthor bool
+}
+
+// parseCluster 解析引用簇内容，任一部分不是有效引用时返回 nil（视为普通方括号文本）
+func parseCluster(inner string) []Cite {
+	var cites []Cite
+	for _, part := range strings.Split(inner, ";") {
+		loc := keyPattern.FindStringSubmatchIndex(part)
+		if loc == nil {
+			return nil
+		}
+		// @ 前须为空白或开头，避免把邮箱地址识别为引用
+		if loc[0] > 0 && !strings.ContainsAny(part[loc[0]-1:loc[0]], " \t") {
+			return nil
+		}
+		cites = append(cites, Cite{
+			Key:            part[loc[4]:loc[5]],
+			Prefix:         strings.TrimSpace(part[:loc[0]]),
+			Locator:        strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part[loc[1]:]), ",")),
+			SuppressAuthor: part[loc[2]:loc[3]] == "-",
+		})
+	}
+	return cites
+}
+
+// forEachCluster 遍历正文（跳过代码块与行内代码）中的引用簇，replace 返回替换文本；返回 ok=false 时保留原文
+func forEachCluster(content string, replace func([]Cite, string) (string, bool)) string {
+	lines := strings.Split(content, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.Contains(line, "@") {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		for _, m := range clusterPattern.FindAllStringSubmatchIndex(line, -1) {
+			// 跳过 Markdown 链接文字与行内代码中的内容
+			if (m[1] < len(line) && line[m[1]] == '(') || strings.Count(line[:m[0]], "`")%2 == 1 {
+				continue
+			}
+			cites := parseCluster(line[m[2]:m[3]])
+			if cites == nil {
+				continue
+			}
+			if repl, ok := replace(cites, line[m[0]:m[1]]); ok {
+				b.WriteString(line[last:m[0]])
+				b.WriteString(repl)
+				last = m[1]
+			}
+		}
+		if last > 0 {
+			b.WriteString(line[last:])
+			lines[i] = b.String()
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ExtractKeys 按首次出现顺序返回正文中引用的 citekey
+func ExtractKeys(content string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	forEachCluster(content, func(cites []Cite, _ string) (string, bool) {
+		for _, c := range cites {
+			if !seen[c.Key] {
+				seen[c.Key] = true
+				keys = append(keys, c.Key)
+			}
+		}
+		return "", false
+	})
+	return keys
+}
+
+// ReplaceClusters 将正文中的引用簇交由 replace 转换为其他格式（如 LaTeX 的 \cite），返回 ok=false 时保留原文
+func ReplaceClusters(content string, replace func(cites []Cite) (string, bool)) string {
+	return forEachCluster(content, func(cites []Cite, _ string) (string, bool) {
+		return replace(cites)
+	})
+}
+
+// Anchor 参考文献条目的页面锚点
+func Anchor(key string) string {
+	return "ref-" + strings.Trim(anchorCleaner.ReplaceAllString(key, "-"), "-")
+}
+
+// Render 将正文中的引用簇替换为作者-年份标注（保留 citekey 于 data-cite 属性），未知的 citekey 保持原文
+func Render(content string, items []Item) string {
+	if len(items) == 0 {
+		return content
+	}
+	index := indexItems(items)
+	return forEachCluster(content, func(cites []Cite, original string) (string, bool) {
+		keys := make([]string, len(cites))
+		parts := make([]string, len(cites))
+		for i, c := range cites {
+			it, ok := index[c.Key]
+			if !ok {
+				return "", false
+			}
+			keys[i] = c.Key
+			label := it.Year()
+			if !c.SuppressAuthor {
+				label = it.Label() + ", " + label
+			}
+			if c.Prefix != "" {
+				label = c.Prefix + " " + label
+			}
+			if c.Locator != "" {
+				label += ", " + c.Locator
+			}
+			parts[i] = label
+		}
+		href := "#" + Anchor(cites[0].Key)
+		return `<a class="citation" href="` + html.EscapeString(href) + `" data-cite="` + html.EscapeString(strings.Join(keys, " ")) +
+			`" title="` + html.EscapeString(original) + `">(` + html.EscapeString(strings.Join(parts, "; ")) + `)</a>`, true
+	})
+}
+
+// Entry 参考文献条目
+type Entry struct {
+	ID     string `json:"id"`
+	Anchor string `json:"anchor"`
+	Text   string `json:"text"`
+	DOI    string `json:"doi,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// Cited 返回正文中引用到的条目（按首次出现顺序）
+func Cited(content string, items []Item) []Item {
+	index := indexItems(items)
+	var cited []Item
+	for _, key := range ExtractKeys(content) {
+		if it, ok := index[key]; ok {
+			cited = append(cited, *it)
+		}
+	}
+	return cited
+}
+
+// Bibliography 生成正文中引用到的文献列表，按作者与年份排序
+func Bibliography(content string, items []Item) []Entry {
+	cited := Cited(content, items)
+	ptrs := make([]*Item, len(cited))
+	for i := range cited {
+		ptrs[i] = &cited[i]
+	}
+	sortItems(ptrs)
+	entries := make([]Entry, 0, len(ptrs))
+	for _, it := range ptrs {
+		entries = append(entries, Entry{ID: it.ID, Anchor: Anchor(it.ID), Text: it.Format(), DOI: it.DOI, URL: it.URL})
+	}
+	return entries
+}
+
+func indexItems(items []Item) map[string]*Item {
+	index := make(map[string]*Item, len(items))
+	for i := range items {
+		index[items[i].ID] = &items[i]
+	}
+	return index
+}
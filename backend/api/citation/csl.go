@@ -0,0 +1,271 @@
+// Package citation 处理思源文献引用插件导出的 Pandoc 风格引用（[@key]、[@a; @b, p. 3]），
+// 结合发布时附带的 CSL-JSON 文献数据生成作者-年份标注与参考文献列表。
+package citation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Name CSL 姓名
+type Name struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+// Date CSL 日期（date-parts 中的年份可能是数字或字符串）
+type Date struct {
+	DateParts [][]interface{} `json:"date-parts,omitempty"`
+	Literal   string          `json:"literal,omitempty"`
+	Raw       string          `json:"raw,omitempty"`
+}
+
+// Item CSL-JSON 条目中用于生成标注与参考文献的字段，原始 JSON 另行保留
+type Item struct {
+	ID             string `json:"id"`
+	Type           string `json:"type,omitempty"`
+	Title          string `json:"title,omitempty"`
+	Author         []Name `json:"author,omitempty"`
+	Editor         []Name `json:"editor,omitempty"`
+	Issued         *Date  `json:"issued,omitempty"`
+	ContainerTitle string `json:"container-title,omitempty"`
+	Volume         string `json:"volume,omitempty"`
+	Issue          string `json:"issue,omitempty"`
+	Page           string `json:"page,omitempty"`
+	Publisher      string `json:"publisher,omitempty"`
+	DOI            string `json:"DOI,omitempty"`
+	URL            string `json:"URL,omitempty"`
+
+	raw json.RawMessage
+}
+
+// UnmarshalJSON 兼容 volume / issue / page 等字段为数字的情况，并保留原始 JSON
+func (it *Item) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, k := range []string{"id", "volume", "issue", "page"} {
+		if v, ok := fields[k]; ok && len(v) > 0 && v[0] != '"' {
+			fields[k], _ = json.Marshal(strings.Trim(string(v), " "))
+		}
+	}
+	normalized, _ := json.Marshal(fields)
+	type plain Item
+	var p plain
+	if err := json.Unmarshal(normalized, &p); err != nil {
+		return err
+	}
+	*it = Item(p)
+	it.raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON 输出原始 CSL-JSON，保留本包未解析的字段
+func (it Item) MarshalJSON() ([]byte, error) {
+	if len(it.raw) > 0 {
+		return it.raw, nil
+	}
+	type plain Item
+	return json.Marshal(plain(it))
+}
+
+// maxItems 单个分享最多保存的文献条目数
+const maxItems = 2000
+
+// Parse 解析 CSL-JSON 数组，忽略缺少 id 的条目，id 重复时保留第一条
+func Parse(data []byte) ([]Item, error) {
+	var items []Item
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	out := make([]Item, 0, len(items))
+	for _, it := range items {
+		it.ID = strings.TrimSpace(it.ID)
+		if it.ID == "" || seen[it.ID] {
+			continue
+		}
+		seen[it.ID] = true
+		out = append(out, it)
+	}
+	if len(out) > maxItems {
+		return nil, fmt.Errorf("too many citation items (max %d)", maxItems)
+	}
+	return out, nil
+}
+
+// Year 出版年份，缺失时返回 n.d.
+func (it *Item) Year() string {
+	if it.Issued != nil {
+		if len(it.Issued.DateParts) > 0 && len(it.Issued.DateParts[0]) > 0 {
+			switch y := it.Issued.DateParts[0][0].(type) {
+			case float64:
+				return fmt.Sprintf("%d", int(y))
+			case string:
+				if y != "" {
+					return y
+				}
+			}
+		}
+		for _, s := range []string{it.Issued.Literal, it.Issued.Raw} {
+			if m := yearPattern.FindString(s); m != "" {
+				return m
+			}
+		}
+	}
+	return "n.d."
+}
+
+var yearPattern = regexp.MustCompile(`\d{4}`)
+
+func (it *Item) creators() []Name {
+	if len(it.Author) > 0 {
+		return it.Author
+	}
+	return it.Editor
+}
+
+// Label 作者-年份标注中的作者部分：Smith / Smith & Lee / Smith et al.，无作者时使用标题
+func (it *Item) Label() string {
+	names := it.creators()
+	switch {
+	case len(names) == 0:
+		title := []rune(it.Title)
+		if len(title) > 30 {
+			return string(title[:30]) + "…"
+		}
+		if len(title) == 0 {
+			return it.ID
+		}
+		return it.Title
+	case len(names) == 1:
+		return shortName(names[0])
+	case len(names) == 2:
+		return shortName(names[0]) + " & " + shortName(names[1])
+	default:
+		return shortName(names[0]) + " et al."
+	}
+}
+
+func shortName(n Name) string {
+	if n.Family != "" {
+		return n.Family
+	}
+	if n.Literal != "" {
+		return n.Literal
+	}
+	return n.Given
+}
+
+// fullName 参考文献中的姓名：Smith, J. A.
+func fullName(n Name) string {
+	if n.Family == "" {
+		if n.Literal != "" {
+			return n.Literal
+		}
+		return n.Given
+	}
+	var initials []string
+	for _, part := range strings.FieldsFunc(n.Given, func(r rune) bool { return r == ' ' || r == '-' || r == '.' }) {
+		r := []rune(part)
+		initials = append(initials, string(r[0])+".")
+	}
+	if len(initials) == 0 {
+		return n.Family
+	}
+	return n.Family + ", " + strings.Join(initials, " ")
+}
+
+// Format 生成 APA 风格的参考文献条目文本
+func (it *Item) Format() string {
+	var b strings.Builder
+	names := it.creators()
+	if len(names) > 0 {
+		parts := make([]string, len(names))
+		for i, n := range names {
+			parts[i] = fullName(n)
+		}
+		switch len(parts) {
+		case 1:
+			b.WriteString(parts[0])
+		case 2:
+			b.WriteString(parts[0] + ", & " + parts[1])
+		default:
+			if len(parts) > 20 {
+				parts = append(parts[:19], "… "+parts[len(parts)-1])
+				b.WriteString(strings.Join(parts, ", "))
+			} else {
+				b.WriteString(strings.Join(parts[:len(parts)-1], ", ") + ", & " + parts[len(parts)-1])
+			}
+		}
+		if len(it.Author) == 0 {
+			b.WriteString(" (Ed.)")
+		}
+		b.WriteString(" ")
+		b.WriteString("(" + it.Year() + "). ")
+		if it.Title != "" {
+			b.WriteString(sentence(it.Title))
+		}
+	} else {
+		// 无作者时标题前置
+		b.WriteString(sentence(it.Title))
+		b.WriteString("(" + it.Year() + "). ")
+	}
+
+	if it.ContainerTitle != "" {
+		b.WriteString(it.ContainerTitle)
+		if it.Volume != "" {
+			b.WriteString(", " + it.Volume)
+			if it.Issue != "" {
+				b.WriteString("(" + it.Issue + ")")
+			}
+		}
+		if it.Page != "" {
+			b.WriteString(", " + it.Page)
+		}
+		b.WriteString(". ")
+	} else if it.Publisher != "" {
+		b.WriteString(sentence(it.Publisher))
+	}
+
+	switch {
+	case it.DOI != "":
+		doi := strings.TrimPrefix(strings.TrimPrefix(it.DOI, "https://doi.org/"), "doi:")
+		b.WriteString("https://doi.org/" + doi)
+	case it.URL != "":
+		b.WriteString(it.URL)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// sentence 补全结尾标点
+func sentence(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ""
+	}
+	if strings.HasSuffix(s, ".") || strings.HasSuffix(s, "?") || strings.HasSuffix(s, "!") || strings.HasSuffix(s, "。") {
+		return s + " "
+	}
+	return s + ". "
+}
+
+// sortKey 参考文献按第一作者与年份排序
+func (it *Item) sortKey() string {
+	names := it.creators()
+	key := it.Title
+	if len(names) > 0 {
+		key = shortName(names[0]) + " " + names[0].Given
+	}
+	return strings.ToLower(key) + "\x00" + it.Year()
+}
+
+// sortItems 按参考文献顺序排序
+func sortItems(items []*Item) {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].sortKey() < items[j].sortKey() })
+}
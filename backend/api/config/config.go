@@ -0,0 +1,1191 @@
+// Package config 集中管理服务配置：默认值 → 配置文件（config.yaml / config.toml）→ 环境变量覆盖，
+// 启动时统一校验。环境变量名与早期版本保持一致，未使用配置文件的部署无需改动。
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config 服务配置
+type Config struct {
+	Server      ServerConfig      `yaml:"server" toml:"server"`
+	TLS         TLSConfig         `yaml:"tls" toml:"tls"`
+	CORS        CORSConfig        `yaml:"cors" toml:"cors"`
+	CDN         CDNConfig         `yaml:"cdn" toml:"cdn"`
+	Assets      AssetConfig       `yaml:"assets" toml:"assets"`
+	Images      ImageConfig       `yaml:"images" toml:"images"`
+	Database    DatabaseConfig    `yaml:"database" toml:"database"`
+	Log         LogConfig         `yaml:"log" toml:"log"`
+	Auth        AuthConfig        `yaml:"auth" toml:"auth"`
+	OIDC        OIDCConfig        `yaml:"oidc" toml:"oidc"`
+	SMTP        SMTPConfig        `yaml:"smtp" toml:"smtp"`
+	Storage     StorageConfig     `yaml:"storage" toml:"storage"`
+	Content     ContentConfig     `yaml:"content" toml:"content"`
+	RateLimit   RateLimitConfig   `yaml:"rate_limit" toml:"rate_limit"`
+	Challenge   ChallengeConfig   `yaml:"challenge" toml:"challenge"`
+	Quota       QuotaConfig       `yaml:"quota" toml:"quota"`
+	Notify      NotifyConfig      `yaml:"notify" toml:"notify"`
+	Push        PushConfig        `yaml:"push" toml:"push"`
+	PWA         PWAConfig         `yaml:"pwa" toml:"pwa"`
+	Signing     SigningConfig     `yaml:"signing" toml:"signing"`
+	Embed       EmbedConfig       `yaml:"embed" toml:"embed"`
+	Links       LinksConfig       `yaml:"links" toml:"links"`
+	Export      ExportConfig      `yaml:"export" toml:"export"`
+	Geo         GeoConfig         `yaml:"geo" toml:"geo"`
+	Alert       AlertConfig       `yaml:"alert" toml:"alert"`
+	TTS         TTSConfig         `yaml:"tts" toml:"tts"`
+	AI          AIConfig          `yaml:"ai" toml:"ai"`
+	Embedding   EmbeddingConfig   `yaml:"embedding" toml:"embedding"`
+	Translation TranslationConfig `yaml:"translation" toml:"translation"`
+	Cache       CacheConfig       `yaml:"cache" toml:"cache"`
+	Payment     PaymentConfig     `yaml:"payment" toml:"payment"`
+	// LanguageCheck 发布时的拼写与语法检查
+	LanguageCheck LanguageCheckConfig `yaml:"language_check" toml:"language_check"`
+	// Backup 定时备份的保存位置（本地目录或 S3）
+	Backup BackupConfig `yaml:"backup" toml:"backup"`
+	// Jobs 进程内定时任务（过期分享清理、孤立资源回收、WAL 检查点、指标汇总、钩子重试、账号注销）
+	Jobs JobsConfig `yaml:"jobs" toml:"jobs"`
+	// Renderers 外部渲染插件，键为代码块语言（如 abc、graphviz、typst）
+	Renderers map[string]RendererConfig `yaml:"renderers" toml:"renderers"`
+	// Prerender 服务端预渲染公式、Mermaid 图与代码高亮的外部程序，由分享各自开启
+	Prerender PrerenderConfig `yaml:"prerender" toml:"prerender"`
+	// Hooks 外部 HTTP 钩子，按登记顺序调用
+	Hooks []HookConfig `yaml:"hooks" toml:"hooks"`
+	// Secrets 从文件（Docker secrets）或 HashiCorp Vault 读取密码、密钥等敏感配置项，见 secrets.go
+	Secrets SecretsConfig `yaml:"secrets" toml:"secrets"`
+
+	source string // 加载的配置文件路径，重新加载时使用
+}
+
+// ServerConfig HTTP 服务
+type ServerConfig struct {
+	Port    string `yaml:"port" toml:"port" env:"PORT"`
+	Mode    string `yaml:"mode" toml:"mode" env:"GIN_MODE"` // debug / release / test
+	DataDir string `yaml:"data_dir" toml:"data_dir" env:"DATA_DIR"`
+	// ShutdownTimeout 收到退出信号后等待进行中的请求与后台任务完成的最长时间
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// Locale 默认语言（zh-CN / en），用户未设置语言偏好且 Accept-Language 不匹配时使用
+	Locale string `yaml:"locale" toml:"locale" env:"DEFAULT_LOCALE"`
+	// Listen 监听地址：主机:端口（如 127.0.0.1:8088）或 unix:<套接字路径>，为空时在所有地址上监听 port
+	Listen string `yaml:"listen" toml:"listen" env:"LISTEN"`
+	// SocketMode Unix 套接字文件的权限（八进制），默认 0666，反向代理须有读写权限
+	SocketMode string `yaml:"socket_mode" toml:"socket_mode" env:"LISTEN_SOCKET_MODE"`
+	// TrustedProxies 可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才按 X-Forwarded-For / X-Real-IP 确定客户端 IP；
+	// 默认只信任本机，none 表示不信任任何代理。经 Unix 套接字的连接视为来自本机
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	// TestMode 端到端测试模式：数据库为内存 SQLite，数据目录为退出时删除的临时目录，ID 与 Token 按 TestSeed 确定性生成，
+	// 并开放 POST /api/test/reset 清空全部数据。任何人都能清空数据，切勿在生产环境开启
+	TestMode bool `yaml:"test_mode" toml:"test_mode" env:"TEST_MODE"`
+	// TestSeed 测试模式下生成 ID 与 Token 的随机数种子，默认 1
+	TestSeed int64 `yaml:"test_seed" toml:"test_seed" env:"TEST_SEED"`
+}
+
+// UnixSocket listen 为 unix:<路径> 时返回套接字路径，否则为空
+func (s ServerConfig) UnixSocket() string {
+	if path, ok := strings.CutPrefix(s.Listen, "unix:"); ok {
+		return path
+	}
+	return ""
+}
+
+// ListenAddr TCP 监听地址：listen 或 :port
+func (s ServerConfig) ListenAddr() string {
+	if s.Listen != "" {
+		return s.Listen
+	}
+	return ":" + s.Port
+}
+
+// CORSConfig API 跨域访问，供思源桌面端插件与浏览器扩展直接调用
+type CORSConfig struct {
+	// AllowOrigins 允许的来源，逗号分隔；* 允许任意来源，https://*.example.com 匹配子域名
+	AllowOrigins     []string `yaml:"allow_origins" toml:"allow_origins" env:"CORS_ALLOW_ORIGINS"`
+	AllowMethods     []string `yaml:"allow_methods" toml:"allow_methods" env:"CORS_ALLOW_METHODS"`
+	AllowCredentials bool     `yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"` // 允许携带 Cookie 等凭据，须列出具体来源
+	MaxAge           Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE"`                               // 预检结果的缓存时间
+}
+
+// CDNConfig 阅读页通过 CDN 或独立域名引用分享资源；CDN 回源到本服务的相同路径
+type CDNConfig struct {
+	AssetBaseURL string   `yaml:"asset_base_url" toml:"asset_base_url" env:"CDN_ASSET_BASE_URL"` // 如 https://cdn.example.com，为空时不启用
+	SignedURLTTL Duration `yaml:"signed_url_ttl" toml:"signed_url_ttl" env:"CDN_SIGNED_URL_TTL"` // 资源签名地址的最短有效期（私密分享或 assets.signed_urls）
+}
+
+// Enabled 是否启用了 CDN 资源地址
+func (c CDNConfig) Enabled() bool {
+	return c.AssetBaseURL != ""
+}
+
+// AssetConfig 分享资源防盗链：签名地址与 Referer 检查可单独或同时启用
+type AssetConfig struct {
+	// SignedURLs 所有分享的资源只能通过阅读页签发的带过期时间的签名地址访问，有效期见 cdn.signed_url_ttl
+	SignedURLs   bool `yaml:"signed_urls" toml:"signed_urls" env:"ASSET_SIGNED_URLS"`
+	RefererCheck bool `yaml:"referer_check" toml:"referer_check" env:"ASSET_REFERER_CHECK"` // 拒绝其他站点页面引用资源
+	// AllowedReferers 额外允许引用资源的站点域名，逗号分隔；*.example.com 匹配任意子域名
+	AllowedReferers   []string `yaml:"allowed_referers" toml:"allowed_referers" env:"ASSET_ALLOWED_REFERERS"`
+	BlockEmptyReferer bool     `yaml:"block_empty_referer" toml:"block_empty_referer" env:"ASSET_BLOCK_EMPTY_REFERER"` // 同时拒绝不带 Referer 的请求（直接打开、部分阅读器）
+}
+
+// ImageConfig 上传图片的优化：PNG/JPEG 超出尺寸上限时生成缩小的副本，配置 webp_command 时另存 WebP 副本，
+// 读者请求时按 Accept 返回最小的可用格式；原图保持不变，导出仍使用原图
+type ImageConfig struct {
+	MaxDimension int `yaml:"max_dimension" toml:"max_dimension" env:"IMAGE_MAX_DIMENSION"` // 长边像素上限，0 不缩放
+	Quality      int `yaml:"quality" toml:"quality" env:"IMAGE_QUALITY"`                   // 缩小后 JPEG 的质量（1-100），默认 85
+	// WebPCommand 从标准输入读取 PNG/JPEG、向标准输出写出 WebP 的命令，不经过 shell，如 ["magick", "-", "-quality", "80", "webp:-"]
+	WebPCommand []string `yaml:"webp_command" toml:"webp_command"`
+}
+
+// Enabled 是否启用了图片优化
+func (i ImageConfig) Enabled() bool {
+	return i.MaxDimension > 0 || len(i.WebPCommand) > 0
+}
+
+// TLSConfig 内置 HTTPS：配置域名后通过 Let's Encrypt（ACME HTTP-01）自动申请与续期证书
+type TLSConfig struct {
+	Domains   []string `yaml:"domains" toml:"domains" env:"TLS_DOMAINS"` // 逗号分隔，为空时不启用
+	Email     string   `yaml:"email" toml:"email" env:"TLS_EMAIL"`       // 证书到期等通知邮箱（可选）
+	CacheDir  string   `yaml:"cache_dir" toml:"cache_dir" env:"TLS_CACHE_DIR"`
+	HTTPPort  string   `yaml:"http_port" toml:"http_port" env:"TLS_HTTP_PORT"` // 证书验证与跳转 HTTPS，须能从公网以 80 端口访问
+	HTTPSPort string   `yaml:"https_port" toml:"https_port" env:"TLS_HTTPS_PORT"`
+	Staging   bool     `yaml:"staging" toml:"staging" env:"TLS_STAGING"` // 使用 Let's Encrypt 测试环境，证书不受浏览器信任
+	// CustomDomains 为用户绑定并验证的自定义域名自动申请证书
+	CustomDomains bool `yaml:"custom_domains" toml:"custom_domains" env:"TLS_CUSTOM_DOMAINS"`
+}
+
+// Enabled 是否启用内置 HTTPS
+func (t TLSConfig) Enabled() bool {
+	return len(t.Domains) > 0
+}
+
+// DatabaseConfig 数据库
+type DatabaseConfig struct {
+	Driver  string `yaml:"driver" toml:"driver" env:"DB_DRIVER"`           // sqlite / sqlcipher（加密，需以 sqlcipher 标签编译）
+	DSN     string `yaml:"dsn" toml:"dsn" env:"DB_DSN"`                    // 为空时使用 data_dir/siyuan-share.db
+	LogMode string `yaml:"log_mode" toml:"log_mode" env:"SQLITE_LOG_MODE"` // info / warn / error / silent，为空时跟随 log.level
+	// AutoMigrate 启动时自动执行待执行的迁移；关闭后表结构版本落后时拒绝启动，需先运行 migrate up
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate" env:"DB_AUTO_MIGRATE"`
+	// QueryConsole 允许管理员通过 /api/admin/sql 执行只读 SQL 查询（默认关闭）；每次查询记录审计日志
+	QueryConsole bool     `yaml:"query_console" toml:"query_console" env:"DB_QUERY_CONSOLE"`
+	QueryMaxRows int      `yaml:"query_max_rows" toml:"query_max_rows" env:"DB_QUERY_MAX_ROWS"` // 单次查询返回的行数上限，默认 200
+	QueryTimeout Duration `yaml:"query_timeout" toml:"query_timeout" env:"DB_QUERY_TIMEOUT"`    // 单次查询的超时，默认 5s
+	// EncryptionKey 等为 driver 为 sqlcipher 时的加密密钥来源，按顺序取第一个已配置的：直接配置、
+	// 文件（如 KMS / Secrets 代理挂载的文件）、命令（不经 shell 执行，取标准输出，如 KMS 解密命令）。
+	// 64 位十六进制密钥作为原始密钥使用，其他值作为口令经 PBKDF2 派生
+	EncryptionKey        string   `yaml:"encryption_key" toml:"encryption_key" env:"DB_ENCRYPTION_KEY"`
+	EncryptionKeyFile    string   `yaml:"encryption_key_file" toml:"encryption_key_file" env:"DB_ENCRYPTION_KEY_FILE"`
+	EncryptionKeyCommand []string `yaml:"encryption_key_command" toml:"encryption_key_command" env:"DB_ENCRYPTION_KEY_COMMAND"`
+}
+
+// LogConfig 日志
+type LogConfig struct {
+	Level          string   `yaml:"level" toml:"level" env:"LOG_LEVEL"`                               // debug / info / warn / error
+	Format         string   `yaml:"format" toml:"format" env:"LOG_FORMAT"`                            // json / text
+	Access         bool     `yaml:"access" toml:"access" env:"LOG_ACCESS"`                            // 是否记录访问日志
+	SlowRequest    Duration `yaml:"slow_request" toml:"slow_request" env:"LOG_SLOW_REQUEST"`          // 处理时间超过该值的请求以 warn 级别记录并标记 slow，0 不标记
+	File           string   `yaml:"file" toml:"file" env:"LOG_FILE"`                                  // 日志文件，为空时输出到 output
+	Output         string   `yaml:"output" toml:"output" env:"LOG_OUTPUT"`                            // 未配置日志文件时的输出：stdout / syslog / eventlog（Windows 事件日志）
+	Tag            string   `yaml:"tag" toml:"tag" env:"LOG_TAG"`                                     // syslog 标识与 Windows 事件日志来源名称
+	MaxSize        int64    `yaml:"max_size" toml:"max_size" env:"LOG_MAX_SIZE"`                      // 日志文件轮转大小（MB），0 不按大小轮转
+	RotateInterval Duration `yaml:"rotate_interval" toml:"rotate_interval" env:"LOG_ROTATE_INTERVAL"` // 按时间轮转的周期（如 24h 每天 UTC 0 点），0 不按时间轮转
+	MaxBackups     int      `yaml:"max_backups" toml:"max_backups" env:"LOG_MAX_BACKUPS"`             // 保留的旧日志文件数
+	MaxAge         Duration `yaml:"max_age" toml:"max_age" env:"LOG_MAX_AGE"`                         // 旧日志文件的保留时长，0 不按时间清理
+}
+
+// AuthConfig 登录与账号安全
+type AuthConfig struct {
+	SessionSecret            string   `yaml:"session_secret" toml:"session_secret" env:"SESSION_SECRET"`
+	RequireEmailVerification bool     `yaml:"require_email_verification" toml:"require_email_verification" env:"REQUIRE_EMAIL_VERIFICATION"`
+	TOTPIssuer               string   `yaml:"totp_issuer" toml:"totp_issuer" env:"TOTP_ISSUER"`
+	Admins                   []string `yaml:"admins" toml:"admins" env:"ADMIN_USERS"` // 管理员用户名，逗号分隔
+	// 注册方式：open 开放注册，invite 需管理员生成的邀请码，closed 关闭注册（仍可用 user create 子命令创建用户）；
+	// 非 open 时第三方登录也不再自动创建账号
+	Registration string `yaml:"registration" toml:"registration" env:"REGISTRATION"`
+	// 防暴力破解：账号连续登录失败达到 lockout_threshold 次后锁定 lockout_duration（再次锁定时加倍，最长 24h），0 不锁定；
+	// 同一 IP 在 15 分钟内失败达到 ip_failure_limit 次后拒绝该 IP 登录 15 分钟，0 不限制
+	LockoutThreshold int      `yaml:"lockout_threshold" toml:"lockout_threshold" env:"LOGIN_LOCKOUT_THRESHOLD"`
+	LockoutDuration  Duration `yaml:"lockout_duration" toml:"lockout_duration" env:"LOGIN_LOCKOUT_DURATION"`
+	IPFailureLimit   int      `yaml:"ip_failure_limit" toml:"ip_failure_limit" env:"LOGIN_IP_FAILURE_LIMIT"`
+	// 用户申请注销账号后的宽限期，期满由 jobs.account_deletions 彻底删除账号及其数据，期间可撤销；0 立即删除
+	DeletionGrace Duration `yaml:"deletion_grace" toml:"deletion_grace" env:"ACCOUNT_DELETION_GRACE"`
+	// 轮换会话密钥时把旧密钥放在这里：新会话与邮件链接使用 session_secret 签名，旧密钥签发的在有效期内仍可验证
+	SessionSecretPrevious []string `yaml:"session_secret_previous" toml:"session_secret_previous" env:"SESSION_SECRET_PREVIOUS"`
+	// API Token 的最近使用时间、来源与请求数先在内存中按 Token 合并，每隔 token_usage_flush_interval 批量写库一次，
+	// 插件同步时的大量请求不会每次都写 SQLite；Token 列表查询与服务退出时立即写入
+	TokenUsageFlushInterval Duration `yaml:"token_usage_flush_interval" toml:"token_usage_flush_interval" env:"TOKEN_USAGE_FLUSH_INTERVAL"`
+}
+
+// OIDCConfig 第三方登录；提供方也可通过 OIDC_PROVIDERS 与 OIDC_<NAME>_* 环境变量配置
+type OIDCConfig struct {
+	RedirectBase string                  `yaml:"redirect_base" toml:"redirect_base" env:"OIDC_REDIRECT_BASE"`
+	AutoRegister bool                    `yaml:"auto_register" toml:"auto_register" env:"OIDC_AUTO_REGISTER"`
+	Providers    map[string]OIDCProvider `yaml:"providers" toml:"providers"`
+}
+
+// OIDCProvider 单个登录提供方
+type OIDCProvider struct {
+	ClientID     string   `yaml:"client_id" toml:"client_id"`
+	ClientSecret string   `yaml:"client_secret" toml:"client_secret"`
+	Issuer       string   `yaml:"issuer" toml:"issuer"`
+	Scopes       []string `yaml:"scopes" toml:"scopes"`
+	DisplayName  string   `yaml:"display_name" toml:"display_name"`
+}
+
+// SMTPConfig 邮件发送
+type SMTPConfig struct {
+	Host     string `yaml:"host" toml:"host" env:"SMTP_HOST"`
+	Port     string `yaml:"port" toml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" toml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" toml:"password" env:"SMTP_PASSWORD"`
+	From     string `yaml:"from" toml:"from" env:"SMTP_FROM"`
+	TLS      string `yaml:"tls" toml:"tls" env:"SMTP_TLS"` // starttls / tls / none，为空时按端口推断
+}
+
+// StorageConfig 资源存储
+type StorageConfig struct {
+	Driver string   `yaml:"driver" toml:"driver" env:"STORAGE_DRIVER"` // local / s3
+	S3     S3Config `yaml:"s3" toml:"s3"`
+	// SignedDownloads 导出文件下载重定向到存储签发的直链，由浏览器直接从存储下载（仅 s3，存储地址须对读者可达）
+	SignedDownloads bool `yaml:"signed_downloads" toml:"signed_downloads" env:"STORAGE_SIGNED_DOWNLOADS"`
+}
+
+// S3Config S3 兼容存储
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint" toml:"endpoint" env:"S3_ENDPOINT"`
+	Region          string `yaml:"region" toml:"region" env:"S3_REGION"`
+	Bucket          string `yaml:"bucket" toml:"bucket" env:"S3_BUCKET"`
+	AccessKeyID     string `yaml:"access_key_id" toml:"access_key_id" env:"S3_ACCESS_KEY_ID"`
+	SecretAccessKey string `yaml:"secret_access_key" toml:"secret_access_key" env:"S3_SECRET_ACCESS_KEY"`
+	Prefix          string `yaml:"prefix" toml:"prefix" env:"S3_PREFIX"`
+	PathStyle       bool   `yaml:"path_style" toml:"path_style" env:"S3_PATH_STYLE"`
+}
+
+// ContentConfig 分享正文存储与展示
+type ContentConfig struct {
+	Storage          string `yaml:"storage" toml:"storage" env:"CONTENT_STORAGE"`             // db / blob
+	Compression      string `yaml:"compression" toml:"compression" env:"CONTENT_COMPRESSION"` // zstd / none
+	CompressMinBytes int    `yaml:"compress_min_bytes" toml:"compress_min_bytes" env:"CONTENT_COMPRESS_MIN_BYTES"`
+	OGDefaultImage   string `yaml:"og_default_image" toml:"og_default_image" env:"OG_DEFAULT_IMAGE"`
+	// NoIndex 禁止搜索引擎收录整个站点：robots.txt 禁止抓取且不提供 sitemap.xml
+	NoIndex bool `yaml:"noindex" toml:"noindex" env:"NOINDEX"`
+	// RobotsTxt 自定义 robots.txt 规则，替换默认规则（sitemap 地址仍会自动追加）
+	RobotsTxt string `yaml:"robots_txt" toml:"robots_txt" env:"ROBOTS_TXT"`
+	// RawMarkdown 分享链接按 Accept: text/markdown 返回 Markdown 原文（JSON 始终可用）
+	RawMarkdown bool `yaml:"raw_markdown" toml:"raw_markdown" env:"CONTENT_RAW_MARKDOWN"`
+}
+
+// RateLimitConfig 发布接口、读者评论、提问、举报、反馈、投票、表单提交、付费阅读订单与阅读进度限流
+type RateLimitConfig struct {
+	PublishPerMinute  int `yaml:"publish_per_minute" toml:"publish_per_minute" env:"PUBLISH_RATE_LIMIT"`
+	PublishDailyQuota int `yaml:"publish_daily_quota" toml:"publish_daily_quota" env:"PUBLISH_DAILY_QUOTA"`
+	CommentsPerHour   int `yaml:"comments_per_hour" toml:"comments_per_hour" env:"COMMENT_RATE_LIMIT"`  // 每个 IP 每小时可发表的评论数
+	QuestionsPerHour  int `yaml:"questions_per_hour" toml:"questions_per_hour" env:"QA_RATE_LIMIT"`     // 每个 IP 每小时可提问的次数
+	ReportsPerHour    int `yaml:"reports_per_hour" toml:"reports_per_hour" env:"REPORT_RATE_LIMIT"`     // 每个 IP 每小时可提交的举报数
+	FeedbackPerHour   int `yaml:"feedback_per_hour" toml:"feedback_per_hour" env:"FEEDBACK_RATE_LIMIT"` // 每个 IP 每小时可提交的反馈数
+	VotesPerHour      int `yaml:"votes_per_hour" toml:"votes_per_hour" env:"VOTE_RATE_LIMIT"`           // 每个 IP 每小时可投票的次数
+	FormsPerHour      int `yaml:"forms_per_hour" toml:"forms_per_hour" env:"FORM_RATE_LIMIT"`           // 每个 IP 每小时可提交的表单数
+	OrdersPerHour     int `yaml:"orders_per_hour" toml:"orders_per_hour" env:"ORDER_RATE_LIMIT"`        // 每个 IP 每小时可创建的付费阅读订单数
+	ProgressPerHour   int `yaml:"progress_per_hour" toml:"progress_per_hour" env:"PROGRESS_RATE_LIMIT"` // 每个 IP 每小时可保存阅读进度的次数
+}
+
+// ChallengeConfig 公开接口的人机验证（Turnstile / hCaptcha / reCAPTCHA），provider 为空时关闭
+type ChallengeConfig struct {
+	Provider      string   `yaml:"provider" toml:"provider" env:"CHALLENGE_PROVIDER"`                   // turnstile / hcaptcha / recaptcha，为空关闭
+	SiteKey       string   `yaml:"site_key" toml:"site_key" env:"CHALLENGE_SITE_KEY"`                   // 前端渲染验证组件使用的站点密钥
+	SecretKey     string   `yaml:"secret_key" toml:"secret_key" env:"CHALLENGE_SECRET_KEY"`             // 服务端核验令牌使用的密钥
+	VerifyURL     string   `yaml:"verify_url" toml:"verify_url" env:"CHALLENGE_VERIFY_URL"`             // 核验接口，为空时使用服务商的默认地址
+	Actions       []string `yaml:"actions" toml:"actions" env:"CHALLENGE_ACTIONS"`                      // 要求验证的操作：register / login / share_password / comment / report
+	AfterFailures int      `yaml:"after_failures" toml:"after_failures" env:"CHALLENGE_AFTER_FAILURES"` // 登录与分享密码：同一 IP 失败达到该次数后才要求验证，0 每次都要求
+}
+
+// Requires 操作 action 是否要求人机验证
+func (c ChallengeConfig) Requires(action string) bool {
+	return c.Provider != "" && slices.Contains(c.Actions, action)
+}
+
+// QuotaConfig 每个用户的默认存储配额（0 不限制），管理员可为单个用户覆盖；用量达到 80%、95% 与超出配额时提醒用户
+type QuotaConfig struct {
+	MaxBytes      int64 `yaml:"max_bytes" toml:"max_bytes" env:"QUOTA_MAX_BYTES"`                   // 资源文件总大小
+	MaxShares     int   `yaml:"max_shares" toml:"max_shares" env:"QUOTA_MAX_SHARES"`                // 分享数（不含引用块分享）
+	MaxAssetBytes int64 `yaml:"max_asset_bytes" toml:"max_asset_bytes" env:"QUOTA_MAX_ASSET_BYTES"` // 单个资源文件大小
+	MaxBandwidth  int64 `yaml:"max_bandwidth" toml:"max_bandwidth" env:"QUOTA_MAX_BANDWIDTH"`       // 每月读者流量，超出后资源以占位图代替
+	GracePercent  int   `yaml:"grace_percent" toml:"grace_percent" env:"QUOTA_GRACE_PERCENT"`       // 超出配额后仍允许的百分比，超出宽限后才拒绝
+}
+
+// NotifyConfig 订阅通知
+type NotifyConfig struct {
+	SubscriptionInterval Duration `yaml:"subscription_interval" toml:"subscription_interval" env:"SUBSCRIPTION_NOTIFY_INTERVAL"`
+}
+
+// PushConfig 浏览器推送
+type PushConfig struct {
+	VAPIDPublicKey  string `yaml:"vapid_public_key" toml:"vapid_public_key" env:"VAPID_PUBLIC_KEY"`
+	VAPIDPrivateKey string `yaml:"vapid_private_key" toml:"vapid_private_key" env:"VAPID_PRIVATE_KEY"`
+	VAPIDSubject    string `yaml:"vapid_subject" toml:"vapid_subject" env:"VAPID_SUBJECT"`
+}
+
+// PWAConfig 仪表盘与阅读页安装为应用（PWA）时的名称、颜色与图标，见 /manifest.webmanifest
+type PWAConfig struct {
+	// Name 应用名称，为空时按读者语言使用站点标题
+	Name string `yaml:"name" toml:"name" env:"PWA_NAME"`
+	// ShortName 主屏幕图标下显示的名称，为空时同 Name
+	ShortName       string `yaml:"short_name" toml:"short_name" env:"PWA_SHORT_NAME"`
+	Description     string `yaml:"description" toml:"description" env:"PWA_DESCRIPTION"`
+	ThemeColor      string `yaml:"theme_color" toml:"theme_color" env:"PWA_THEME_COLOR"`
+	BackgroundColor string `yaml:"background_color" toml:"background_color" env:"PWA_BACKGROUND_COLOR"`
+	// StartURL 从主屏幕打开时的页面，站内路径
+	StartURL string `yaml:"start_url" toml:"start_url" env:"PWA_START_URL"`
+	// Icon 应用图标文件（PNG 或 SVG，PNG 建议 512×512）；为空时使用以主题色与名称首字生成的内置图标
+	Icon string `yaml:"icon" toml:"icon" env:"PWA_ICON"`
+	// MaskableIcon 可裁切为圆形等形状的图标（主体位于中心 80% 的安全区内），可选
+	MaskableIcon string `yaml:"maskable_icon" toml:"maskable_icon" env:"PWA_MASKABLE_ICON"`
+}
+
+// SigningConfig 发布内容签名
+type SigningConfig struct {
+	// PrivateKey Ed25519 私钥种子（32 字节，base64url）；为空时自动生成并保存到 data_dir/signing.json
+	PrivateKey string `yaml:"private_key" toml:"private_key" env:"SIGNING_PRIVATE_KEY"`
+}
+
+// EmbedConfig 在其他网站中嵌入分享（/s/<id>/embed 与 oEmbed）及页面的 X-Frame-Options
+type EmbedConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled" env:"EMBED_ENABLED"`
+	// FrameAncestors 允许嵌入分享的来源，逗号分隔；* 允许任意网站，https://*.example.com 匹配子域名
+	FrameAncestors []string `yaml:"frame_ancestors" toml:"frame_ancestors" env:"EMBED_FRAME_ANCESTORS"`
+	// FrameOptions 嵌入页之外的页面能否被放入 iframe：DENY、SAMEORIGIN 或 ALLOW（不限制）
+	FrameOptions string `yaml:"frame_options" toml:"frame_options" env:"FRAME_OPTIONS"`
+}
+
+// LinksConfig 外部链接预览与存档，以及分享短链接使用的域名
+type LinksConfig struct {
+	Previews        bool     `yaml:"previews" toml:"previews" env:"LINK_PREVIEWS"`
+	PreviewTTL      Duration `yaml:"preview_ttl" toml:"preview_ttl" env:"LINK_PREVIEW_TTL"`
+	AllowPrivate    bool     `yaml:"allow_private" toml:"allow_private" env:"LINK_PREVIEW_ALLOW_PRIVATE"`
+	Archive         bool     `yaml:"archive" toml:"archive" env:"LINK_ARCHIVE"`
+	ArchiveMaxBytes int64    `yaml:"archive_max_bytes" toml:"archive_max_bytes" env:"LINK_ARCHIVE_MAX_BYTES"`
+	// ShortBaseURL 短链接（/x/<code>）使用的地址，如 https://go.example.com，该域名须解析到本服务；为空时使用站点地址
+	ShortBaseURL string `yaml:"short_base_url" toml:"short_base_url" env:"SHORT_LINK_BASE_URL"`
+}
+
+// PaymentConfig 付费阅读：支付平台（或对接平台的中转服务）在读者付款后回调 POST /api/payments/webhook，
+// 请求体以 webhook_secret 计算 HMAC-SHA256 签名；未配置时不能开启付费阅读
+type PaymentConfig struct {
+	WebhookSecret string `yaml:"webhook_secret" toml:"webhook_secret" env:"PAYMENT_WEBHOOK_SECRET"`
+}
+
+// ExportConfig 导出：PDF 由无头 Chromium 打印阅读页生成
+type ExportConfig struct {
+	Chromium   string   `yaml:"chromium" toml:"chromium" env:"PDF_CHROMIUM"`         // Chromium 可执行文件，为空时在 PATH 中查找
+	PDFBaseURL string   `yaml:"pdf_base_url" toml:"pdf_base_url" env:"PDF_BASE_URL"` // Chromium 访问本服务的地址，为空时使用本机监听端口
+	PDFTimeout Duration `yaml:"pdf_timeout" toml:"pdf_timeout" env:"PDF_TIMEOUT"`
+}
+
+// GeoConfig 按读者所在国家/地区限制整个站点的分享阅读（阅读页、公开接口、订阅源与站内搜索），
+// 国家代码为 ISO 3166-1 alpha-2；allow 与 block 二选一
+type GeoConfig struct {
+	Header       string   `yaml:"header" toml:"header" env:"GEO_HEADER"`                      // 反向代理或 CDN 提供国家代码的请求头（如 CF-IPCountry），优先于 database
+	Database     string   `yaml:"database" toml:"database" env:"GEO_DATABASE"`                // IP 段到国家代码的 CSV 文件
+	Allow        []string `yaml:"allow" toml:"allow" env:"GEO_ALLOW"`                         // 仅允许这些国家访问，逗号分隔
+	Block        []string `yaml:"block" toml:"block" env:"GEO_BLOCK"`                         // 禁止这些国家访问，逗号分隔
+	BlockUnknown bool     `yaml:"block_unknown" toml:"block_unknown" env:"GEO_BLOCK_UNKNOWN"` // 无法识别国家时拒绝访问
+	BlockPage    string   `yaml:"block_page" toml:"block_page" env:"GEO_BLOCK_PAGE"`          // 拒绝访问时展示的 HTML 文件，为空时使用内置页面
+}
+
+// Enabled 是否启用了国家/地区访问限制
+func (g GeoConfig) Enabled() bool {
+	return len(g.Allow) > 0 || len(g.Block) > 0
+}
+
+// Blocked 判断国家代码是否被禁止访问，country 为空表示无法识别
+func (g GeoConfig) Blocked(country string) bool {
+	if country == "" {
+		return g.BlockUnknown
+	}
+	if len(g.Allow) > 0 {
+		for _, c := range g.Allow {
+			if c == country {
+				return false
+			}
+		}
+		return true
+	}
+	for _, c := range g.Block {
+		if c == country {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertConfig 异常告警：阈值为每分钟次数，0 表示不检查该项；触发后通知管理员并调用 alert 钩子
+type AlertConfig struct {
+	ShareViews   int      `yaml:"share_views" toml:"share_views" env:"ALERT_SHARE_VIEWS"`       // 单个分享每分钟浏览量（盗链、刷量）
+	ServerErrors int      `yaml:"server_errors" toml:"server_errors" env:"ALERT_SERVER_ERRORS"` // 每分钟 5xx 响应数
+	AuthFailures int      `yaml:"auth_failures" toml:"auth_failures" env:"ALERT_AUTH_FAILURES"` // 每分钟登录失败次数（撞库、暴力破解）
+	Reports      bool     `yaml:"reports" toml:"reports" env:"ALERT_REPORTS"`                   // 收到读者举报时通知，默认开启
+	Cooldown     Duration `yaml:"cooldown" toml:"cooldown" env:"ALERT_COOLDOWN"`                // 同一告警再次通知的最短间隔，默认 30m
+}
+
+// TTSConfig 分享朗读音频（可选）：command 与 url 二选一。命令从标准输入读取朗读文本、向标准输出写出音频；
+// url 为 OpenAI 兼容的语音合成接口（POST JSON：model、input、voice、response_format，响应体为音频）
+type TTSConfig struct {
+	Command    []string `yaml:"command" toml:"command"`                               // 可执行文件及参数，不经过 shell
+	URL        string   `yaml:"url" toml:"url" env:"TTS_URL"`                         // 如 https://api.openai.com/v1/audio/speech
+	APIKey     string   `yaml:"api_key" toml:"api_key" env:"TTS_API_KEY"`             // 以 Bearer 令牌发送（可选）
+	Model      string   `yaml:"model" toml:"model" env:"TTS_MODEL"`                   // 默认 tts-1
+	Voice      string   `yaml:"voice" toml:"voice" env:"TTS_VOICE"`                   // 默认 alloy
+	Format     string   `yaml:"format" toml:"format" env:"TTS_FORMAT"`                // mp3 / opus / aac / flac / wav，默认 mp3
+	ChunkChars int      `yaml:"chunk_chars" toml:"chunk_chars" env:"TTS_CHUNK_CHARS"` // 单次合成的文本长度上限，超出时按句分段合成后拼接，0 不分段；url 默认 4000
+	MaxChars   int      `yaml:"max_chars" toml:"max_chars" env:"TTS_MAX_CHARS"`       // 可朗读的正文长度上限（字符），默认 50000
+	Timeout    Duration `yaml:"timeout" toml:"timeout" env:"TTS_TIMEOUT"`             // 整篇合成超时，默认 10m
+	Auto       bool     `yaml:"auto" toml:"auto" env:"TTS_AUTO"`                      // 发布后自动生成，否则由作者手动生成
+}
+
+// Enabled 是否配置了语音合成
+func (t TTSConfig) Enabled() bool {
+	return len(t.Command) > 0 || t.URL != ""
+}
+
+// AIConfig 分享摘要与建议标签（可选）：url 为 OpenAI 兼容的对话补全接口，发布时根据正文生成摘要
+// （页面 meta description、订阅源与合集的简介）及建议标签，作者可在分享管理中修改摘要、采纳标签
+type AIConfig struct {
+	URL           string   `yaml:"url" toml:"url" env:"AI_URL"`                                     // 如 https://api.openai.com/v1/chat/completions
+	APIKey        string   `yaml:"api_key" toml:"api_key" env:"AI_API_KEY"`                         // 以 Bearer 令牌发送（可选）
+	Model         string   `yaml:"model" toml:"model" env:"AI_MODEL"`                               // 默认 gpt-4o-mini
+	MaxInputChars int      `yaml:"max_input_chars" toml:"max_input_chars" env:"AI_MAX_INPUT_CHARS"` // 发送给接口的正文长度上限（字符），超出部分截断，默认 12000
+	Timeout       Duration `yaml:"timeout" toml:"timeout" env:"AI_TIMEOUT"`                         // 单次请求超时，默认 60s
+	Auto          bool     `yaml:"auto" toml:"auto" env:"AI_AUTO"`                                  // 发布后自动生成，否则由作者手动生成
+	QA            bool     `yaml:"qa" toml:"qa" env:"AI_QA"`                                        // 开放读者提问，仅对作者开启了问答的分享生效
+}
+
+// Enabled 是否配置了摘要生成接口
+func (a AIConfig) Enabled() bool {
+	return a.URL != ""
+}
+
+// EmbeddingConfig 分享的语义向量（可选）：url 为 OpenAI 兼容的向量接口，发布时为分享计算向量，
+// 用于作者的相关分享推荐与语义搜索
+type EmbeddingConfig struct {
+	URL           string   `yaml:"url" toml:"url" env:"EMBEDDING_URL"`                                     // 如 https://api.openai.com/v1/embeddings
+	APIKey        string   `yaml:"api_key" toml:"api_key" env:"EMBEDDING_API_KEY"`                         // 以 Bearer 令牌发送（可选）
+	Model         string   `yaml:"model" toml:"model" env:"EMBEDDING_MODEL"`                               // 默认 text-embedding-3-small，更换后需重建索引
+	MaxInputChars int      `yaml:"max_input_chars" toml:"max_input_chars" env:"EMBEDDING_MAX_INPUT_CHARS"` // 参与计算的正文长度上限（字符），默认 6000
+	Timeout       Duration `yaml:"timeout" toml:"timeout" env:"EMBEDDING_TIMEOUT"`                         // 单次请求超时，默认 30s
+}
+
+// Enabled 是否配置了向量接口
+func (e EmbeddingConfig) Enabled() bool {
+	return e.URL != ""
+}
+
+// TranslationConfig 分享的翻译版本（可选）：url 为 OpenAI 兼容的对话补全接口，未配置时使用 ai 的接口与模型；
+// 作者为分享生成译文后，读者可通过 /s/<id>/<语言> 访问
+type TranslationConfig struct {
+	URL        string   `yaml:"url" toml:"url" env:"TRANSLATION_URL"`                         // 默认同 ai.url
+	APIKey     string   `yaml:"api_key" toml:"api_key" env:"TRANSLATION_API_KEY"`             // 默认同 ai.api_key
+	Model      string   `yaml:"model" toml:"model" env:"TRANSLATION_MODEL"`                   // 默认同 ai.model
+	Languages  []string `yaml:"languages" toml:"languages" env:"TRANSLATION_LANGUAGES"`       // 可选的目标语言（BCP 47，如 en、ja、zh-tw），逗号分隔，为空时不限制
+	ChunkChars int      `yaml:"chunk_chars" toml:"chunk_chars" env:"TRANSLATION_CHUNK_CHARS"` // 单次请求的正文长度上限（字符），默认 4000
+	Timeout    Duration `yaml:"timeout" toml:"timeout" env:"TRANSLATION_TIMEOUT"`             // 翻译整篇分享的超时，默认 10m
+	Auto       bool     `yaml:"auto" toml:"auto" env:"TRANSLATION_AUTO"`                      // 重新发布后自动更新已有的机器译文
+}
+
+// Enabled 是否可以生成译文（配置了 translation.url 或 ai.url）
+func (t TranslationConfig) Enabled() bool {
+	return t.URL != ""
+}
+
+// CacheConfig 热点分享缓存：缓存分享记录与渲染后的正文，分享更新或删除时失效。默认进程内 LRU，
+// 配置 redis_url 后改用 Redis，多个实例共享缓存与失效
+type CacheConfig struct {
+	Size     int      `yaml:"size" toml:"size" env:"CACHE_SIZE"`                // 进程内缓存的条目数上限，默认 1000，0 表示关闭缓存
+	TTL      Duration `yaml:"ttl" toml:"ttl" env:"CACHE_TTL"`                   // 缓存有效期，默认 5m；引用的其他分享变化后至多在此期间内显示旧链接
+	RedisURL string   `yaml:"redis_url" toml:"redis_url" env:"CACHE_REDIS_URL"` // redis://[:密码@]主机:端口[/库号]，rediss:// 使用 TLS
+}
+
+// Enabled 是否启用缓存
+func (c CacheConfig) Enabled() bool {
+	return c.Size > 0 || c.RedisURL != ""
+}
+
+// LanguageCheckConfig 发布时的拼写与语法检查（可选）：url 为 LanguageTool 兼容的 /v2/check 接口，
+// 分享发布或更新后在后台检查正文，问题报告只提供给作者，不修改分享内容
+type LanguageCheckConfig struct {
+	URL           string   `yaml:"url" toml:"url" env:"LANGUAGE_CHECK_URL"`                                  // 如 https://api.languagetool.org/v2/check
+	Username      string   `yaml:"username" toml:"username" env:"LANGUAGE_CHECK_USERNAME"`                   // LanguageTool Premium 账号（可选）
+	APIKey        string   `yaml:"api_key" toml:"api_key" env:"LANGUAGE_CHECK_API_KEY"`                      // LanguageTool Premium API Key（可选）
+	Language      string   `yaml:"language" toml:"language" env:"LANGUAGE_CHECK_LANGUAGE"`                   // 检查语言，如 en-US、zh-CN，默认 auto 自动识别
+	DisabledRules []string `yaml:"disabled_rules" toml:"disabled_rules" env:"LANGUAGE_CHECK_DISABLED_RULES"` // 不检查的规则 ID，逗号分隔
+	ChunkChars    int      `yaml:"chunk_chars" toml:"chunk_chars" env:"LANGUAGE_CHECK_CHUNK_CHARS"`          // 单次请求的文本长度上限（字符），默认 10000
+	MaxChars      int      `yaml:"max_chars" toml:"max_chars" env:"LANGUAGE_CHECK_MAX_CHARS"`                // 检查的正文长度上限（字符），超出部分不检查，默认 100000
+	MaxIssues     int      `yaml:"max_issues" toml:"max_issues" env:"LANGUAGE_CHECK_MAX_ISSUES"`             // 报告保留的问题数上限，默认 500
+	Timeout       Duration `yaml:"timeout" toml:"timeout" env:"LANGUAGE_CHECK_TIMEOUT"`                      // 检查整篇分享的超时，默认 2m
+}
+
+// Enabled 是否配置了拼写与语法检查
+func (l LanguageCheckConfig) Enabled() bool {
+	return l.URL != ""
+}
+
+// BackupConfig 定时备份：数据库快照与存储对象打包为 tar.gz，配置 s3.bucket 时上传到 S3，否则写入本地目录
+type BackupConfig struct {
+	Dir    string         `yaml:"dir" toml:"dir" env:"BACKUP_DIR"`          // 本地备份目录，默认 DATA_DIR/backups
+	Keep   int            `yaml:"keep" toml:"keep" env:"BACKUP_KEEP"`       // 本地目录保留的备份数，默认 7，0 表示不清理；S3 请使用存储桶的生命周期规则
+	Assets bool           `yaml:"assets" toml:"assets" env:"BACKUP_ASSETS"` // 是否包含资源文件等存储对象，默认 true
+	S3     BackupS3Config `yaml:"s3" toml:"s3"`
+}
+
+// BackupS3Config 备份上传的 S3 兼容存储，与资源存储分开配置
+type BackupS3Config struct {
+	Endpoint        string `yaml:"endpoint" toml:"endpoint" env:"BACKUP_S3_ENDPOINT"`
+	Region          string `yaml:"region" toml:"region" env:"BACKUP_S3_REGION"`
+	Bucket          string `yaml:"bucket" toml:"bucket" env:"BACKUP_S3_BUCKET"`
+	AccessKeyID     string `yaml:"access_key_id" toml:"access_key_id" env:"BACKUP_S3_ACCESS_KEY_ID"`
+	SecretAccessKey string `yaml:"secret_access_key" toml:"secret_access_key" env:"BACKUP_S3_SECRET_ACCESS_KEY"`
+	Prefix          string `yaml:"prefix" toml:"prefix" env:"BACKUP_S3_PREFIX"`
+	PathStyle       bool   `yaml:"path_style" toml:"path_style" env:"BACKUP_S3_PATH_STYLE"`
+}
+
+// S3Config 转换为存储使用的 S3 配置
+func (b BackupS3Config) S3Config() S3Config {
+	return S3Config{
+		Endpoint:        b.Endpoint,
+		Region:          b.Region,
+		Bucket:          b.Bucket,
+		AccessKeyID:     b.AccessKeyID,
+		SecretAccessKey: b.SecretAccessKey,
+		Prefix:          b.Prefix,
+		PathStyle:       b.PathStyle,
+	}
+}
+
+// SecretsConfig 敏感配置项的外部来源。配置文件或环境变量中的敏感项可写为 file:/run/secrets/<名称>（读取文件内容）
+// 或 vault:<路径>#<字段>（读取 Vault KV 密钥的字段），每次加载与重新加载配置时重新读取
+type SecretsConfig struct {
+	// RefreshInterval 定期重新读取文件与 Vault 中的密钥，有变化时重新加载配置（与 SIGHUP 相同），0 不定期读取
+	RefreshInterval Duration    `yaml:"refresh_interval" toml:"refresh_interval" env:"SECRETS_REFRESH_INTERVAL"`
+	Vault           VaultConfig `yaml:"vault" toml:"vault"`
+}
+
+// VaultConfig HashiCorp Vault 连接，令牌可由 Vault Agent 写入文件后以 VAULT_TOKEN_FILE 或 file: 引用读取
+type VaultConfig struct {
+	Address   string   `yaml:"address" toml:"address" env:"VAULT_ADDR"`
+	Token     string   `yaml:"token" toml:"token" env:"VAULT_TOKEN"`
+	Namespace string   `yaml:"namespace" toml:"namespace" env:"VAULT_NAMESPACE"` // Vault Enterprise 命名空间（可选）
+	Timeout   Duration `yaml:"timeout" toml:"timeout" env:"VAULT_TIMEOUT"`       // 单次请求超时，默认 10s
+}
+
+// JobsConfig 进程内定时任务；每个任务可单独开关并设置间隔，环境变量 JOB_<NAME>_ENABLED / JOB_<NAME>_INTERVAL
+// 覆盖对应任务（如 JOB_EXPIRED_SHARES_ENABLED=true）
+type JobsConfig struct {
+	Jitter          Duration  `yaml:"jitter" toml:"jitter" env:"JOBS_JITTER"`                                  // 每次执行前随机延迟的上限（不超过间隔的一半），默认 1m
+	LeaderElection  string    `yaml:"leader_election" toml:"leader_election" env:"JOBS_LEADER_ELECTION"`       // 多实例选主，只有主实例执行定时任务：db（默认，数据库租约）/ redis（cache.redis_url）/ none（每个实例都执行）
+	LeaseTTL        Duration  `yaml:"lease_ttl" toml:"lease_ttl" env:"JOBS_LEASE_TTL"`                         // 主实例租约的有效期，每隔 1/3 续期，主实例失联后至多这么久由其他实例接管，默认 30s
+	ExpiredShares   JobConfig `yaml:"expired_shares" toml:"expired_shares"`                                    // 删除过期超过 share_retention 的分享，默认关闭，间隔 24h
+	ShareRetention  Duration  `yaml:"share_retention" toml:"share_retention" env:"JOBS_SHARE_RETENTION"`       // 分享过期后保留多久再删除，默认 720h（30 天）
+	OrphanAssets    JobConfig `yaml:"orphan_assets" toml:"orphan_assets"`                                      // 清理已删除分享的资源文件与图片副本，默认 24h
+	WALCheckpoint   JobConfig `yaml:"wal_checkpoint" toml:"wal_checkpoint"`                                    // SQLite WAL 检查点并截断 WAL 文件，默认 1h
+	InstanceStats   JobConfig `yaml:"instance_stats" toml:"instance_stats"`                                    // 实例指标每日汇总，默认 1h
+	HookRetries     JobConfig `yaml:"hook_retries" toml:"hook_retries"`                                        // 重试投递失败的异步 HTTP 钩子，默认 1m
+	Backup          JobConfig `yaml:"backup" toml:"backup"`                                                    // 定时备份，默认关闭，间隔 24h（见 backup）
+	AccountDeletion JobConfig `yaml:"account_deletions" toml:"account_deletions"`                              // 删除注销宽限期已满的账号及其数据，默认 1h
+	ViewEvents      JobConfig `yaml:"view_events" toml:"view_events"`                                          // 删除超过 view_retention（且所在日期已汇总）的原始浏览记录与过期的阅读进度，默认 24h
+	ViewRetention   Duration  `yaml:"view_retention" toml:"view_retention" env:"JOBS_VIEW_RETENTION"`          // 原始浏览记录的保留时间，默认 2160h（90 天）；0 不记录
+	ViewRollup      JobConfig `yaml:"view_rollup" toml:"view_rollup"`                                          // 将已结束日期的原始浏览记录汇总为每日数据，默认 24h
+	RollupRetention Duration  `yaml:"summary_retention" toml:"summary_retention" env:"JOBS_SUMMARY_RETENTION"` // 每日浏览汇总的保留时间，默认 0 不删除
+	HookMaxAttempts int       `yaml:"hook_max_attempts" toml:"hook_max_attempts" env:"JOBS_HOOK_MAX_ATTEMPTS"` // 异步钩子的最多投递次数（含首次），默认 8
+	Trash           JobConfig `yaml:"trash" toml:"trash"`                                                      // 彻底删除回收站中超过 trash_retention 的分享，默认 1h
+	TrashRetention  Duration  `yaml:"trash_retention" toml:"trash_retention" env:"JOBS_TRASH_RETENTION"`       // 已删除的分享在回收站中保留的时间，默认 720h（30 天）
+	ExpiryNotices   JobConfig `yaml:"share_expiry_notices" toml:"share_expiry_notices"`                        // 邮件提醒分享者即将到期的分享，默认 1h
+}
+
+// JobConfig 单个定时任务
+type JobConfig struct {
+	Enabled  bool     `yaml:"enabled" toml:"enabled"`
+	Interval Duration `yaml:"interval" toml:"interval"`
+}
+
+// Tasks 按任务名列出各定时任务的配置
+func (j *JobsConfig) Tasks() map[string]*JobConfig {
+	return map[string]*JobConfig{
+		"expired_shares":       &j.ExpiredShares,
+		"orphan_assets":        &j.OrphanAssets,
+		"wal_checkpoint":       &j.WALCheckpoint,
+		"instance_stats":       &j.InstanceStats,
+		"hook_retries":         &j.HookRetries,
+		"backup":               &j.Backup,
+		"account_deletions":    &j.AccountDeletion,
+		"view_events":          &j.ViewEvents,
+		"view_rollup":          &j.ViewRollup,
+		"trash":                &j.Trash,
+		"share_expiry_notices": &j.ExpiryNotices,
+	}
+}
+
+// RendererConfig 外部渲染插件：command 与 url 二选一。命令从标准输入读取代码块内容、向标准输出写出渲染结果；
+// HTTP 服务接收 POST 的代码块内容（text/plain），以响应体返回渲染结果
+type RendererConfig struct {
+	Command     []string `yaml:"command" toml:"command"`           // 可执行文件及参数，不经过 shell
+	URL         string   `yaml:"url" toml:"url"`                   // HTTP 渲染服务地址
+	ContentType string   `yaml:"content_type" toml:"content_type"` // 输出类型，默认 image/svg+xml
+	Timeout     Duration `yaml:"timeout" toml:"timeout"`           // 单次渲染超时，默认 10s
+	MaxBytes    int64    `yaml:"max_bytes" toml:"max_bytes"`       // 输出大小上限，默认 5MB
+}
+
+// PrerenderConfig 服务端预渲染：各项与外部渲染插件一样为 command 或 url（content_type 不使用），未配置的项不预渲染。
+// 输出直接嵌入页面，须为 HTML 片段（Mermaid 为 SVG）
+type PrerenderConfig struct {
+	Math        RendererConfig `yaml:"math" toml:"math"`                 // 行内公式：TeX 转 HTML 或 MathML，如 ["katex", "--output", "mathml"]
+	MathDisplay RendererConfig `yaml:"math_display" toml:"math_display"` // 块级公式，如 ["katex", "--display-mode", "--output", "mathml"]；未配置时使用 math
+	Mermaid     RendererConfig `yaml:"mermaid" toml:"mermaid"`           // Mermaid 图转 SVG，如 ["mmdc", "-i", "-", "-o", "-", "-e", "svg"]
+	// Highlight 代码高亮，输出 <pre><code> 中的 HTML；命令参数与 URL 中的 {lang} 替换为代码块语言，
+	// 如 ["pygmentize", "-l", "{lang}", "-f", "html", "-O", "nowrap,noclasses"]
+	Highlight RendererConfig `yaml:"highlight" toml:"highlight"`
+}
+
+// Items 已配置的预渲染项，键为配置名
+func (p *PrerenderConfig) Items() map[string]*RendererConfig {
+	return map[string]*RendererConfig{
+		"math":         &p.Math,
+		"math_display": &p.MathDisplay,
+		"mermaid":      &p.Mermaid,
+		"highlight":    &p.Highlight,
+	}
+}
+
+// Configured 预渲染项是否已配置
+func (r *RendererConfig) Configured() bool {
+	return len(r.Command) > 0 || r.URL != ""
+}
+
+// HookConfig 服务端钩子，url 与 script 二选一：HTTP 钩子在订阅的事件发生时以 POST 发送 JSON 事件，
+// 脚本钩子在沙箱中运行 Lua 脚本；同步事件可拒绝操作或改写内容
+type HookConfig struct {
+	Name      string   `yaml:"name" toml:"name"`             // 日志与错误中显示的名称，默认取 URL 的主机名或脚本文件名
+	URL       string   `yaml:"url" toml:"url"`               // 钩子服务地址
+	Script    string   `yaml:"script" toml:"script"`         // Lua 脚本文件路径
+	Events    []string `yaml:"events" toml:"events"`         // pre_publish / post_publish / pre_render / auth / alert
+	Secret    string   `yaml:"secret" toml:"secret"`         // 请求签名密钥（可选，仅 HTTP 钩子）
+	Timeout   Duration `yaml:"timeout" toml:"timeout"`       // 单次调用超时（脚本即 CPU 时间上限），默认 5s
+	MaxMemory int64    `yaml:"max_memory" toml:"max_memory"` // 脚本单次运行的内存上限（字节），默认 32MB
+	FailOpen  bool     `yaml:"fail_open" toml:"fail_open"`   // 钩子出错或超时时是否继续操作，默认拒绝
+}
+
+// Duration 支持 "1h30m" 形式的时长
+type Duration time.Duration
+
+// UnmarshalText 解析时长字符串
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(strings.TrimSpace(string(text)))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText 输出时长字符串
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// Std 转换为 time.Duration
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// Default 返回默认配置
+func Default() *Config {
+	return &Config{
+		Server:    ServerConfig{Port: "8088", Mode: "release", DataDir: "./data", ShutdownTimeout: Duration(30 * time.Second), Locale: "zh-CN", SocketMode: "0666", TrustedProxies: []string{"127.0.0.1", "::1"}, TestSeed: 1},
+		TLS:       TLSConfig{HTTPPort: "80", HTTPSPort: "443"},
+		Database:  DatabaseConfig{Driver: "sqlite", AutoMigrate: true, QueryMaxRows: 200, QueryTimeout: Duration(5 * time.Second)},
+		Log:       LogConfig{Level: "info", Format: "json", Access: true, SlowRequest: Duration(time.Second), Output: "stdout", Tag: "siyuan-share", MaxSize: 100, MaxBackups: 5},
+		Auth:      AuthConfig{Registration: "open", TOTPIssuer: "SiYuan Share", LockoutThreshold: 5, LockoutDuration: Duration(15 * time.Minute), IPFailureLimit: 20, DeletionGrace: Duration(7 * 24 * time.Hour), TokenUsageFlushInterval: Duration(time.Minute)},
+		OIDC:      OIDCConfig{AutoRegister: true},
+		SMTP:      SMTPConfig{Port: "587"},
+		Storage:   StorageConfig{Driver: "local", S3: S3Config{Region: "us-east-1", PathStyle: true}},
+		Content:   ContentConfig{Storage: "db", Compression: "zstd", CompressMinBytes: 4096},
+		RateLimit: RateLimitConfig{PublishPerMinute: 60, CommentsPerHour: 10, QuestionsPerHour: 20, ReportsPerHour: 5, FeedbackPerHour: 20, VotesPerHour: 30, FormsPerHour: 10, OrdersPerHour: 10, ProgressPerHour: 600},
+		Challenge: ChallengeConfig{Actions: []string{"register", "login", "share_password", "comment", "report"}, AfterFailures: 3},
+		Notify:    NotifyConfig{SubscriptionInterval: Duration(time.Hour)},
+		Export:    ExportConfig{PDFTimeout: Duration(time.Minute)},
+		Alert:     AlertConfig{Reports: true, Cooldown: Duration(30 * time.Minute)},
+		CDN:       CDNConfig{SignedURLTTL: Duration(time.Hour)},
+		Cache:     CacheConfig{Size: 1000, TTL: Duration(5 * time.Minute)},
+		Backup:    BackupConfig{Keep: 7, Assets: true, S3: BackupS3Config{Region: "us-east-1", PathStyle: true}},
+		Secrets:   SecretsConfig{Vault: VaultConfig{Timeout: Duration(10 * time.Second)}},
+		Jobs: JobsConfig{
+			Jitter:          Duration(time.Minute),
+			LeaderElection:  "db",
+			LeaseTTL:        Duration(30 * time.Second),
+			ExpiredShares:   JobConfig{Interval: Duration(24 * time.Hour)},
+			ShareRetention:  Duration(30 * 24 * time.Hour),
+			OrphanAssets:    JobConfig{Enabled: true, Interval: Duration(24 * time.Hour)},
+			WALCheckpoint:   JobConfig{Enabled: true, Interval: Duration(time.Hour)},
+			InstanceStats:   JobConfig{Enabled: true, Interval: Duration(time.Hour)},
+			HookRetries:     JobConfig{Enabled: true, Interval: Duration(time.Minute)},
+			Backup:          JobConfig{Interval: Duration(24 * time.Hour)},
+			AccountDeletion: JobConfig{Enabled: true, Interval: Duration(time.Hour)},
+			ViewEvents:      JobConfig{Enabled: true, Interval: Duration(24 * time.Hour)},
+			ViewRetention:   Duration(90 * 24 * time.Hour),
+			ViewRollup:      JobConfig{Enabled: true, Interval: Duration(24 * time.Hour)},
+			Trash:           JobConfig{Enabled: true, Interval: Duration(time.Hour)},
+			TrashRetention:  Duration(30 * 24 * time.Hour),
+			ExpiryNotices:   JobConfig{Enabled: true, Interval: Duration(time.Hour)},
+			HookMaxAttempts: 8,
+		},
+		CORS: CORSConfig{
+			AllowOrigins: []string{"*"},
+			AllowMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			MaxAge:       Duration(10 * time.Minute),
+		},
+		PWA: PWAConfig{
+			ThemeColor:      "#1890ff",
+			BackgroundColor: "#ffffff",
+			StartURL:        "/",
+		},
+		Embed: EmbedConfig{
+			Enabled:        true,
+			FrameAncestors: []string{"*"},
+			FrameOptions:   "SAMEORIGIN",
+		},
+		Links: LinksConfig{
+			Previews:        true,
+			PreviewTTL:      Duration(7 * 24 * time.Hour),
+			Archive:         true,
+			ArchiveMaxBytes: 10 << 20,
+		},
+	}
+}
+
+// configCandidates 未指定配置文件时依次查找的文件
+var configCandidates = []string{"config.yaml", "config.yml", "config.toml"}
+
+// Load 加载配置：path 为空时使用 CONFIG_FILE 环境变量或当前目录下的 config.yaml / config.yml / config.toml，
+// 都不存在时仅使用默认值与环境变量。返回的错误包含全部校验失败项
+func Load(path string) (*Config, []string, error) {
+	cfg := Default()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path == "" {
+		for _, name := range configCandidates {
+			if _, err := os.Stat(name); err == nil {
+				path = name
+				break
+			}
+		}
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var problems []string
+	problems = append(problems, applyEnv(cfg)...)
+	problems = append(problems, applyOIDCEnv(cfg)...)
+	problems = append(problems, applyJobsEnv(cfg)...)
+	problems = append(problems, cfg.resolveSecrets()...)
+	cfg.normalize()
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, nil, &ValidationError{Source: path, Problems: problems}
+	}
+	cfg.source = path
+	return cfg, cfg.warnings(), nil
+}
+
+// Source 加载的配置文件路径，只使用默认值与环境变量时为空
+func (c *Config) Source() string {
+	return c.source
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		// 拒绝未知字段，避免拼写错误被静默忽略
+		dec.KnownFields(true)
+		if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(c); err != nil {
+			var strict *toml.StrictMissingError
+			if errors.As(err, &strict) {
+				return fmt.Errorf("parse %s: %s", path, strict.String())
+			}
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+	default:
+		return fmt.Errorf("unsupported config file format %q (use .yaml, .yml or .toml)", filepath.Ext(path))
+	}
+	return nil
+}
+
+// normalize 规范化取值（大小写、首尾空白与派生默认值）
+func (c *Config) normalize() {
+	c.Server.Mode = strings.ToLower(strings.TrimSpace(c.Server.Mode))
+	c.Server.Listen = strings.TrimSpace(c.Server.Listen)
+	c.Server.SocketMode = strings.TrimSpace(c.Server.SocketMode)
+	if c.Server.SocketMode == "" {
+		c.Server.SocketMode = "0666"
+	}
+	proxies := make([]string, 0, len(c.Server.TrustedProxies))
+	for _, p := range c.Server.TrustedProxies {
+		if p = strings.TrimSpace(p); p != "" && !strings.EqualFold(p, "none") {
+			proxies = append(proxies, p)
+		}
+	}
+	c.Server.TrustedProxies = proxies
+	c.Database.Driver = strings.ToLower(strings.TrimSpace(c.Database.Driver))
+	c.Database.LogMode = strings.ToLower(strings.TrimSpace(c.Database.LogMode))
+	c.Log.Level = strings.ToLower(strings.TrimSpace(c.Log.Level))
+	c.Log.Format = strings.ToLower(strings.TrimSpace(c.Log.Format))
+	c.Storage.Driver = strings.ToLower(strings.TrimSpace(c.Storage.Driver))
+	c.Content.Storage = strings.ToLower(strings.TrimSpace(c.Content.Storage))
+	c.Content.Compression = strings.ToLower(strings.TrimSpace(c.Content.Compression))
+	c.SMTP.TLS = strings.ToLower(strings.TrimSpace(c.SMTP.TLS))
+	c.Auth.Registration = strings.ToLower(strings.TrimSpace(c.Auth.Registration))
+	if c.Auth.Registration == "" {
+		c.Auth.Registration = "open"
+	}
+	c.Storage.S3.Prefix = strings.Trim(c.Storage.S3.Prefix, "/")
+	c.OIDC.RedirectBase = strings.TrimSuffix(c.OIDC.RedirectBase, "/")
+	c.Export.PDFBaseURL = strings.TrimSuffix(c.Export.PDFBaseURL, "/")
+	c.CDN.AssetBaseURL = strings.TrimSuffix(strings.TrimSpace(c.CDN.AssetBaseURL), "/")
+	c.Links.ShortBaseURL = strings.TrimSuffix(strings.TrimSpace(c.Links.ShortBaseURL), "/")
+	if c.Images.Quality == 0 {
+		c.Images.Quality = 85
+	}
+	for i, r := range c.Assets.AllowedReferers {
+		c.Assets.AllowedReferers[i] = strings.ToLower(strings.TrimSpace(r))
+	}
+
+	origins := c.CORS.AllowOrigins[:0:0]
+	for _, o := range c.CORS.AllowOrigins {
+		if o = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(o), "/")); o != "" {
+			origins = append(origins, o)
+		}
+	}
+	c.CORS.AllowOrigins = origins
+	methods := c.CORS.AllowMethods[:0:0]
+	for _, m := range c.CORS.AllowMethods {
+		if m = strings.ToUpper(strings.TrimSpace(m)); m != "" {
+			methods = append(methods, m)
+		}
+	}
+	c.CORS.AllowMethods = methods
+
+	c.Geo.Header = strings.TrimSpace(c.Geo.Header)
+	c.Geo.Allow = normalizeCountries(c.Geo.Allow)
+	c.Geo.Block = normalizeCountries(c.Geo.Block)
+
+	if c.Server.DataDir == "" {
+		c.Server.DataDir = "./data"
+	}
+	domains := c.TLS.Domains[:0:0]
+	for _, d := range c.TLS.Domains {
+		if d = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(d), ".")); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	c.TLS.Domains = domains
+	if c.TLS.CacheDir == "" {
+		c.TLS.CacheDir = filepath.Join(c.Server.DataDir, "autocert")
+	}
+	if c.SMTP.From == "" {
+		c.SMTP.From = c.SMTP.Username
+	}
+	if c.SMTP.TLS == "" {
+		c.SMTP.TLS = "starttls"
+		if c.SMTP.Port == "465" {
+			c.SMTP.TLS = "tls"
+		}
+	}
+	if c.Content.Compression == "none" {
+		c.Content.CompressMinBytes = -1
+	}
+	providers := make(map[string]OIDCProvider, len(c.OIDC.Providers))
+	for name, p := range c.OIDC.Providers {
+		p.Issuer = strings.TrimSuffix(p.Issuer, "/")
+		providers[strings.ToLower(strings.TrimSpace(name))] = p
+	}
+	c.OIDC.Providers = providers
+	c.TTS.URL = strings.TrimSpace(c.TTS.URL)
+	c.TTS.Format = strings.ToLower(strings.TrimSpace(c.TTS.Format))
+	if c.TTS.Format == "" {
+		c.TTS.Format = "mp3"
+	}
+	if c.TTS.URL != "" {
+		if c.TTS.Model == "" {
+			c.TTS.Model = "tts-1"
+		}
+		if c.TTS.Voice == "" {
+			c.TTS.Voice = "alloy"
+		}
+		if c.TTS.ChunkChars == 0 {
+			c.TTS.ChunkChars = 4000
+		}
+	}
+	if c.TTS.MaxChars == 0 {
+		c.TTS.MaxChars = 50000
+	}
+	if c.TTS.Timeout == 0 {
+		c.TTS.Timeout = Duration(10 * time.Minute)
+	}
+	c.AI.URL = strings.TrimSpace(c.AI.URL)
+	if c.AI.Model == "" {
+		c.AI.Model = "gpt-4o-mini"
+	}
+	if c.AI.MaxInputChars == 0 {
+		c.AI.MaxInputChars = 12000
+	}
+	if c.AI.Timeout == 0 {
+		c.AI.Timeout = Duration(60 * time.Second)
+	}
+	c.Embedding.URL = strings.TrimSpace(c.Embedding.URL)
+	if c.Embedding.Model == "" {
+		c.Embedding.Model = "text-embedding-3-small"
+	}
+	if c.Embedding.MaxInputChars == 0 {
+		c.Embedding.MaxInputChars = 6000
+	}
+	if c.Embedding.Timeout == 0 {
+		c.Embedding.Timeout = Duration(30 * time.Second)
+	}
+	c.Cache.RedisURL = strings.TrimSpace(c.Cache.RedisURL)
+	c.LanguageCheck.URL = strings.TrimSpace(c.LanguageCheck.URL)
+	c.LanguageCheck.Language = strings.TrimSpace(c.LanguageCheck.Language)
+	if c.LanguageCheck.Language == "" {
+		c.LanguageCheck.Language = "auto"
+	}
+	if c.LanguageCheck.ChunkChars == 0 {
+		c.LanguageCheck.ChunkChars = 10000
+	}
+	if c.LanguageCheck.MaxChars == 0 {
+		c.LanguageCheck.MaxChars = 100000
+	}
+	if c.LanguageCheck.MaxIssues == 0 {
+		c.LanguageCheck.MaxIssues = 500
+	}
+	if c.LanguageCheck.Timeout == 0 {
+		c.LanguageCheck.Timeout = Duration(2 * time.Minute)
+	}
+	c.PWA.Name = strings.TrimSpace(c.PWA.Name)
+	c.PWA.ShortName = strings.TrimSpace(c.PWA.ShortName)
+	c.PWA.Icon = strings.TrimSpace(c.PWA.Icon)
+	c.PWA.MaskableIcon = strings.TrimSpace(c.PWA.MaskableIcon)
+	if c.PWA.StartURL == "" {
+		c.PWA.StartURL = "/"
+	}
+	c.Backup.Dir = strings.TrimSpace(c.Backup.Dir)
+	if c.Backup.Dir == "" {
+		c.Backup.Dir = filepath.Join(c.Server.DataDir, "backups")
+	}
+	c.Backup.S3.Endpoint = strings.TrimSpace(c.Backup.S3.Endpoint)
+	c.Backup.S3.Bucket = strings.TrimSpace(c.Backup.S3.Bucket)
+	rules := make([]string, 0, len(c.LanguageCheck.DisabledRules))
+	for _, rule := range c.LanguageCheck.DisabledRules {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	c.LanguageCheck.DisabledRules = rules
+	c.Translation.URL = strings.TrimSpace(c.Translation.URL)
+	if c.Translation.URL == "" {
+		c.Translation.URL, c.Translation.APIKey = c.AI.URL, c.AI.APIKey
+	}
+	if c.Translation.Model == "" {
+		c.Translation.Model = c.AI.Model
+	}
+	if c.Translation.ChunkChars == 0 {
+		c.Translation.ChunkChars = 4000
+	}
+	if c.Translation.Timeout == 0 {
+		c.Translation.Timeout = Duration(10 * time.Minute)
+	}
+	languages := make([]string, 0, len(c.Translation.Languages))
+	for _, lang := range c.Translation.Languages {
+		if lang = strings.ToLower(strings.TrimSpace(lang)); lang != "" {
+			languages = append(languages, lang)
+		}
+	}
+	c.Translation.Languages = languages
+	renderers := make(map[string]RendererConfig, len(c.Renderers))
+	for lang, r := range c.Renderers {
+		r.ContentType = strings.ToLower(strings.TrimSpace(r.ContentType))
+		if r.ContentType == "" {
+			r.ContentType = "image/svg+xml"
+		}
+		if r.Timeout == 0 {
+			r.Timeout = Duration(10 * time.Second)
+		}
+		if r.MaxBytes == 0 {
+			r.MaxBytes = 5 << 20
+		}
+		renderers[strings.ToLower(strings.TrimSpace(lang))] = r
+	}
+	c.Renderers = renderers
+	for _, r := range c.Prerender.Items() {
+		if r.Timeout == 0 {
+			r.Timeout = Duration(10 * time.Second)
+		}
+		if r.MaxBytes == 0 {
+			r.MaxBytes = 1 << 20
+		}
+	}
+	for i := range c.Hooks {
+		h := &c.Hooks[i]
+		h.Name = strings.TrimSpace(h.Name)
+		if h.Name == "" {
+			if u, err := url.Parse(h.URL); err == nil && u.Host != "" {
+				h.Name = u.Host
+			} else if h.Script != "" {
+				h.Name = strings.TrimSuffix(filepath.Base(h.Script), filepath.Ext(h.Script))
+			} else {
+				h.Name = fmt.Sprintf("hook-%d", i+1)
+			}
+		}
+		for j, ev := range h.Events {
+			h.Events[j] = strings.ToLower(strings.TrimSpace(ev))
+		}
+		if h.Timeout == 0 {
+			h.Timeout = Duration(5 * time.Second)
+		}
+		if h.Script != "" && h.MaxMemory == 0 {
+			h.MaxMemory = 32 << 20
+		}
+	}
+}
+
+// normalizeCountries 国家代码转为大写并去除空项
+func normalizeCountries(codes []string) []string {
+	out := codes[:0:0]
+	for _, code := range codes {
+		if code = strings.ToUpper(strings.TrimSpace(code)); code != "" {
+			out = append(out, code)
+		}
+	}
+	return out
+}
+
+// TestDatabaseDSN 测试模式使用的内存数据库，同一进程内的连接共享数据，进程退出后即丢弃
+const TestDatabaseDSN = "file:siyuan-share-test?mode=memory&cache=shared"
+
+// DBPath SQLite 数据库文件路径，测试模式下为内存数据库
+func (c *Config) DBPath() string {
+	if c.Server.TestMode {
+		return TestDatabaseDSN
+	}
+	if c.Database.DSN != "" {
+		return c.Database.DSN
+	}
+	return filepath.Join(c.Server.DataDir, "siyuan-share.db")
+}
+
+// DatabaseKey sqlcipher 驱动的加密密钥，按 encryption_key → encryption_key_file → encryption_key_command 的顺序读取，
+// 去除首尾空白；均未配置时返回空字符串
+func (c *Config) DatabaseKey() (string, error) {
+	d := c.Database
+	switch {
+	case d.EncryptionKey != "":
+		return d.EncryptionKey, nil
+	case d.EncryptionKeyFile != "":
+		b, err := os.ReadFile(d.EncryptionKeyFile)
+		if err != nil {
+			return "", fmt.Errorf("read database.encryption_key_file: %w", err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	case len(d.EncryptionKeyCommand) > 0:
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, d.EncryptionKeyCommand[0], d.EncryptionKeyCommand[1:]...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return "", fmt.Errorf("run database.encryption_key_command: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+	return "", nil
+}
+
+// SessionSecret 会话签名密钥，未配置时使用开发用默认值
+func (c *Config) SessionSecret() []byte {
+	if c.Auth.SessionSecret == "" {
+		return []byte("dev-secret")
+	}
+	return []byte(c.Auth.SessionSecret)
+}
+
+// SessionSecrets 验证会话与邮件链接签名时接受的密钥：当前密钥在前，其后为轮换前的旧密钥
+func (c *Config) SessionSecrets() [][]byte {
+	secrets := [][]byte{c.SessionSecret()}
+	for _, s := range c.Auth.SessionSecretPrevious {
+		if s != "" {
+			secrets = append(secrets, []byte(s))
+		}
+	}
+	return secrets
+}
+
+// IsAdmin 用户名是否在管理员列表中
+func (c *Config) IsAdmin(username string) bool {
+	for _, name := range c.Auth.Admins {
+		if strings.EqualFold(name, username) {
+			return true
+		}
+	}
+	return false
+}
+
+// SMTPEnabled 是否已配置 SMTP
+func (c *Config) SMTPEnabled() bool {
+	return c.SMTP.Host != "" && c.SMTP.From != ""
+}
+
+var current atomic.Pointer[Config]
+
+// Set 设置当前生效的配置
+func Set(c *Config) {
+	current.Store(c)
+}
+
+// Get 返回当前生效的配置；尚未加载时按默认值与环境变量生成（忽略校验错误，便于独立使用各子包）
+func Get() *Config {
+	if c := current.Load(); c != nil {
+		return c
+	}
+	c := Default()
+	applyEnv(c)
+	applyOIDCEnv(c)
+	applyJobsEnv(c)
+	c.normalize()
+	current.CompareAndSwap(nil, c)
+	return current.Load()
+}
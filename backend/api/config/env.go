@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// applyEnv 使用带 env 标签的环境变量覆盖配置（空值视为未设置），返回无法解析的变量。
+// 每个变量都可改用 <变量名>_FILE 指定文件（如 Docker secrets 的 /run/secrets/<名称>），取文件内容作为值；
+// 已有同名配置项的 _FILE 变量（如 DB_ENCRYPTION_KEY_FILE）除外
+func applyEnv(c *Config) []string {
+	declared := map[string]bool{}
+	walkEnv(reflect.ValueOf(c).Elem(), func(name string, _ reflect.Value) {
+		declared[name] = true
+	})
+	var problems []string
+	walkEnv(reflect.ValueOf(c).Elem(), func(name string, field reflect.Value) {
+		raw, source, err := lookupEnv(name, !declared[name+"_FILE"])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", source, err))
+			return
+		}
+		if raw == "" {
+			return
+		}
+		if err := setField(field, raw); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", source, err))
+		}
+	})
+	return problems
+}
+
+// walkEnv 依次处理带 env 标签的配置项
+func walkEnv(v reflect.Value, fn func(name string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			if field.Kind() == reflect.Struct {
+				walkEnv(field, fn)
+			}
+			continue
+		}
+		fn(name, field)
+	}
+}
+
+// lookupEnv 读取环境变量 name（去除首尾空白），未设置时若 withFile 则读取 name_FILE 指向的文件；
+// source 为实际使用的变量名，都未设置时返回空值
+func lookupEnv(name string, withFile bool) (value, source string, err error) {
+	if raw := strings.TrimSpace(os.Getenv(name)); raw != "" {
+		return raw, name, nil
+	}
+	if !withFile {
+		return "", name, nil
+	}
+	path := strings.TrimSpace(os.Getenv(name + "_FILE"))
+	if path == "" {
+		return "", name, nil
+	}
+	value, err = readSecretFile(path)
+	return value, name + "_FILE", err
+}
+
+func setField(field reflect.Value, raw string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(raw))
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q (use true or false)", raw)
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		// 逗号分隔的列表
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Kind())
+	}
+	return nil
+}
+
+// applyOIDCEnv 读取 OIDC_PROVIDERS=github,google,... 与 OIDC_<NAME>_CLIENT_ID / CLIENT_SECRET（或 CLIENT_SECRET_FILE）/
+// ISSUER / SCOPES（空格分隔）/ DISPLAY_NAME，覆盖或补充配置文件中的同名提供方
+func applyOIDCEnv(c *Config) []string {
+	list, ok := os.LookupEnv("OIDC_PROVIDERS")
+	if !ok {
+		return nil
+	}
+	var problems []string
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if c.OIDC.Providers == nil {
+			c.OIDC.Providers = map[string]OIDCProvider{}
+		}
+		p := c.OIDC.Providers[name]
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		if v := os.Getenv(prefix + "CLIENT_ID"); v != "" {
+			p.ClientID = v
+		}
+		v, source, err := lookupEnv(prefix+"CLIENT_SECRET", true)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", source, err))
+		} else if v != "" {
+			p.ClientSecret = v
+		}
+		if v := os.Getenv(prefix + "ISSUER"); v != "" {
+			p.Issuer = v
+		}
+		if v := os.Getenv(prefix + "SCOPES"); v != "" {
+			p.Scopes = strings.Fields(v)
+		}
+		if v := os.Getenv(prefix + "DISPLAY_NAME"); v != "" {
+			p.DisplayName = v
+		}
+		c.OIDC.Providers[name] = p
+	}
+	return problems
+}
+
+// applyJobsEnv 读取 JOB_<NAME>_ENABLED / JOB_<NAME>_INTERVAL 覆盖对应定时任务（NAME 为任务名的大写，如 EXPIRED_SHARES）
+func applyJobsEnv(c *Config) []string {
+	var problems []string
+	for name, job := range c.Jobs.Tasks() {
+		prefix := "JOB_" + strings.ToUpper(name) + "_"
+		if v := strings.TrimSpace(os.Getenv(prefix + "ENABLED")); v != "" {
+			if err := setField(reflect.ValueOf(&job.Enabled).Elem(), v); err != nil {
+				problems = append(problems, fmt.Sprintf("%sENABLED: %v", prefix, err))
+			}
+		}
+		if v := strings.TrimSpace(os.Getenv(prefix + "INTERVAL")); v != "" {
+			if err := job.Interval.UnmarshalText([]byte(v)); err != nil {
+				problems = append(problems, fmt.Sprintf("%sINTERVAL: %v", prefix, err))
+			}
+		}
+	}
+	return problems
+}
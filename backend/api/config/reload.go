@@ -0,0 +1,55 @@
+package config
+
+import "reflect"
+
+// KeepStartupSettings 重新加载配置时沿用 running 中只在启动时读取的设置（监听地址、TLS、数据库、存储、缓存、
+// 日志输出、签名与推送密钥、定时任务安排、密钥刷新间隔），返回其中被修改、需要重启才能生效的配置项
+func (c *Config) KeepStartupSettings(running *Config) []string {
+	var changed []string
+	// 测试模式的数据目录是启动时创建的临时目录，与重新读取的配置不同是预期的
+	if running.Server.TestMode {
+		c.Server.DataDir = running.Server.DataDir
+	}
+	keep(&changed, "server.port", &c.Server.Port, running.Server.Port)
+	keep(&changed, "server.mode", &c.Server.Mode, running.Server.Mode)
+	keep(&changed, "server.data_dir", &c.Server.DataDir, running.Server.DataDir)
+	keep(&changed, "server.listen", &c.Server.Listen, running.Server.Listen)
+	keep(&changed, "server.socket_mode", &c.Server.SocketMode, running.Server.SocketMode)
+	keep(&changed, "server.trusted_proxies", &c.Server.TrustedProxies, running.Server.TrustedProxies)
+	keep(&changed, "server.test_mode", &c.Server.TestMode, running.Server.TestMode)
+	keep(&changed, "tls", &c.TLS, running.TLS)
+	keep(&changed, "database.driver", &c.Database.Driver, running.Database.Driver)
+	keep(&changed, "database.dsn", &c.Database.DSN, running.Database.DSN)
+	keep(&changed, "database.log_mode", &c.Database.LogMode, running.Database.LogMode)
+	keep(&changed, "database.auto_migrate", &c.Database.AutoMigrate, running.Database.AutoMigrate)
+	keep(&changed, "database.encryption_key", &c.Database.EncryptionKey, running.Database.EncryptionKey)
+	keep(&changed, "database.encryption_key_file", &c.Database.EncryptionKeyFile, running.Database.EncryptionKeyFile)
+	keep(&changed, "database.encryption_key_command", &c.Database.EncryptionKeyCommand, running.Database.EncryptionKeyCommand)
+	// 存储的访问密钥可在运行中轮换（见 storage.Init），比较时不计入
+	accessKey, secretKey := c.Storage.S3.AccessKeyID, c.Storage.S3.SecretAccessKey
+	c.Storage.S3.AccessKeyID, c.Storage.S3.SecretAccessKey = running.Storage.S3.AccessKeyID, running.Storage.S3.SecretAccessKey
+	keep(&changed, "storage", &c.Storage, running.Storage)
+	c.Storage.S3.AccessKeyID, c.Storage.S3.SecretAccessKey = accessKey, secretKey
+	keep(&changed, "cache", &c.Cache, running.Cache)
+	keep(&changed, "log.format", &c.Log.Format, running.Log.Format)
+	keep(&changed, "log.file", &c.Log.File, running.Log.File)
+	keep(&changed, "log.output", &c.Log.Output, running.Log.Output)
+	keep(&changed, "log.tag", &c.Log.Tag, running.Log.Tag)
+	keep(&changed, "log.max_size", &c.Log.MaxSize, running.Log.MaxSize)
+	keep(&changed, "log.rotate_interval", &c.Log.RotateInterval, running.Log.RotateInterval)
+	keep(&changed, "log.max_backups", &c.Log.MaxBackups, running.Log.MaxBackups)
+	keep(&changed, "log.max_age", &c.Log.MaxAge, running.Log.MaxAge)
+	keep(&changed, "signing", &c.Signing, running.Signing)
+	keep(&changed, "push", &c.Push, running.Push)
+	keep(&changed, "jobs", &c.Jobs, running.Jobs)
+	keep(&changed, "secrets.refresh_interval", &c.Secrets.RefreshInterval, running.Secrets.RefreshInterval)
+	return changed
+}
+
+// keep 将 dst 恢复为运行中的值，值不同时记录配置项名称
+func keep[T any](changed *[]string, name string, dst *T, running T) {
+	if !reflect.DeepEqual(*dst, running) {
+		*changed = append(*changed, name)
+		*dst = running
+	}
+}
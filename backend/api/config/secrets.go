@@ -0,0 +1,196 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// 敏感配置项的引用前缀：file:<路径> 读取文件内容（去除首尾空白），vault:<路径>#<字段> 读取 Vault 中的密钥字段。
+// 路径为 Vault HTTP API 中 /v1/ 之后的部分，KV v2 引擎需包含 data/，如 vault:secret/data/siyuan-share#smtp_password
+const (
+	fileRefPrefix  = "file:"
+	vaultRefPrefix = "vault:"
+)
+
+// defaultVaultTimeout secrets.vault.timeout 为 0 时的请求超时
+const defaultVaultTimeout = 10 * time.Second
+
+// eachSecret 依次处理可使用 file: / vault: 引用的敏感配置项，name 为错误信息中显示的配置项名称
+func (c *Config) eachSecret(fn func(name string, value *string)) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"auth.session_secret", &c.Auth.SessionSecret},
+		{"database.encryption_key", &c.Database.EncryptionKey},
+		{"smtp.username", &c.SMTP.Username},
+		{"smtp.password", &c.SMTP.Password},
+		{"storage.s3.access_key_id", &c.Storage.S3.AccessKeyID},
+		{"storage.s3.secret_access_key", &c.Storage.S3.SecretAccessKey},
+		{"backup.s3.access_key_id", &c.Backup.S3.AccessKeyID},
+		{"backup.s3.secret_access_key", &c.Backup.S3.SecretAccessKey},
+		{"challenge.secret_key", &c.Challenge.SecretKey},
+		{"payment.webhook_secret", &c.Payment.WebhookSecret},
+		{"push.vapid_private_key", &c.Push.VAPIDPrivateKey},
+		{"signing.private_key", &c.Signing.PrivateKey},
+		{"tts.api_key", &c.TTS.APIKey},
+		{"ai.api_key", &c.AI.APIKey},
+		{"embedding.api_key", &c.Embedding.APIKey},
+		{"translation.api_key", &c.Translation.APIKey},
+		{"language_check.api_key", &c.LanguageCheck.APIKey},
+	}
+	for _, f := range fields {
+		fn(f.name, f.value)
+	}
+	for i := range c.Auth.SessionSecretPrevious {
+		fn(fmt.Sprintf("auth.session_secret_previous[%d]", i), &c.Auth.SessionSecretPrevious[i])
+	}
+	for i := range c.Hooks {
+		fn(fmt.Sprintf("hooks[%d].secret", i), &c.Hooks[i].Secret)
+	}
+	// map 的值不可寻址，处理后写回
+	for name, p := range c.OIDC.Providers {
+		fn("oidc.providers."+name+".client_id", &p.ClientID)
+		fn("oidc.providers."+name+".client_secret", &p.ClientSecret)
+		c.OIDC.Providers[name] = p
+	}
+}
+
+// resolveSecrets 将敏感配置项中的 file: / vault: 引用替换为实际的值，返回无法读取的项
+func (c *Config) resolveSecrets() []string {
+	var problems []string
+	// Vault 令牌本身只能来自配置、环境变量或文件
+	if strings.HasPrefix(c.Secrets.Vault.Token, fileRefPrefix) {
+		token, err := readSecretFile(strings.TrimPrefix(c.Secrets.Vault.Token, fileRefPrefix))
+		if err != nil {
+			problems = append(problems, "secrets.vault.token: "+err.Error())
+		}
+		c.Secrets.Vault.Token = token
+	}
+	vault := &vaultReader{cfg: c.Secrets.Vault, cache: map[string]map[string]any{}}
+	c.eachSecret(func(name string, value *string) {
+		var err error
+		switch {
+		case strings.HasPrefix(*value, fileRefPrefix):
+			*value, err = readSecretFile(strings.TrimPrefix(*value, fileRefPrefix))
+		case strings.HasPrefix(*value, vaultRefPrefix):
+			*value, err = vault.read(strings.TrimPrefix(*value, vaultRefPrefix))
+		}
+		if err != nil {
+			problems = append(problems, name+": "+err.Error())
+		}
+	})
+	return problems
+}
+
+// SecretsDigest 全部敏感配置项的摘要，用于判断重新读取后密钥是否变化
+func (c *Config) SecretsDigest() string {
+	h := sha256.New()
+	c.eachSecret(func(name string, value *string) {
+		fmt.Fprintf(h, "%s=%d:%s\n", name, len(*value), *value)
+	})
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readSecretFile 读取密钥文件（如 Docker secrets 挂载的 /run/secrets/<名称>），去除首尾空白
+func readSecretFile(path string) (string, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", errors.New("empty secret file path")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read secret file: %w", err)
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// vaultReader 读取 Vault KV 密钥（v1 与 v2 引擎），同一次加载中每个路径只请求一次
+type vaultReader struct {
+	cfg   VaultConfig
+	cache map[string]map[string]any
+}
+
+// read 读取 <路径>#<字段> 引用的值，字段须为字符串
+func (v *vaultReader) read(ref string) (string, error) {
+	path, field, ok := strings.Cut(strings.TrimSpace(ref), "#")
+	path = strings.Trim(path, "/")
+	if !ok || path == "" || field == "" {
+		return "", errors.New("vault reference must be vault:<path>#<field>")
+	}
+	data, ok := v.cache[path]
+	if !ok {
+		var err error
+		if data, err = v.fetch(path); err != nil {
+			return "", err
+		}
+		v.cache[path] = data
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("field %q not found in vault secret %s", field, path)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("field %q in vault secret %s is not a string", field, path)
+	}
+	return s, nil
+}
+
+// fetch 请求 GET /v1/<路径>；KV v2 的字段位于 data.data 中，KV v1 位于 data 中
+func (v *vaultReader) fetch(path string) (map[string]any, error) {
+	if v.cfg.Address == "" {
+		return nil, errors.New("secrets.vault.address (VAULT_ADDR) is required for vault: references")
+	}
+	if v.cfg.Token == "" {
+		return nil, errors.New("secrets.vault.token (VAULT_TOKEN) is required for vault: references")
+	}
+	timeout := v.cfg.Timeout.Std()
+	if timeout <= 0 {
+		timeout = defaultVaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(v.cfg.Address, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+	if v.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.cfg.Namespace)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &e)
+		return nil, fmt.Errorf("vault returned %s for %s: %s", resp.Status, path, strings.Join(e.Errors, "; "))
+	}
+	var result struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("decode vault response: %w", err)
+	}
+	if inner, ok := result.Data["data"].(map[string]any); ok {
+		if _, v2 := result.Data["metadata"]; v2 {
+			return inner, nil
+		}
+	}
+	return result.Data, nil
+}
@@ -0,0 +1,626 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError 配置校验失败，包含全部问题项
+type ValidationError struct {
+	Source   string
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	src := "environment"
+	if e.Source != "" {
+		src = e.Source + " (with environment overrides)"
+	}
+	return fmt.Sprintf("invalid configuration in %s:\n  - %s", src, strings.Join(e.Problems, "\n  - "))
+}
+
+func oneOf(field, value string, allowed ...string) string {
+	for _, a := range allowed {
+		if value == a {
+			return ""
+		}
+	}
+	return fmt.Sprintf("%s: unsupported value %q (allowed: %s)", field, value, strings.Join(allowed, ", "))
+}
+
+func validPort(field, value string) string {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Sprintf("%s: %q is not a valid port (1-65535)", field, value)
+	}
+	return ""
+}
+
+// validDomain 可申请 HTTP-01 证书的域名：至少两级，不含通配符与端口，且不是 IP 地址
+func validDomain(d string) bool {
+	labels := strings.Split(d, ".")
+	if len(labels) < 2 || net.ParseIP(d) != nil {
+		return false
+	}
+	for _, l := range labels {
+		if l == "" || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return false
+		}
+		for _, r := range l {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validate 校验配置，返回全部问题项
+func (c *Config) validate() []string {
+	var problems []string
+	add := func(msg string) {
+		if msg != "" {
+			problems = append(problems, msg)
+		}
+	}
+
+	add(validPort("server.port (PORT)", c.Server.Port))
+	add(oneOf("server.mode (GIN_MODE)", c.Server.Mode, "debug", "release", "test"))
+	if c.Server.ShutdownTimeout <= 0 {
+		add("server.shutdown_timeout (SHUTDOWN_TIMEOUT): must be positive")
+	}
+	add(oneOf("server.locale (DEFAULT_LOCALE)", c.Server.Locale, "zh-CN", "en"))
+	if socket := c.Server.UnixSocket(); socket != "" {
+		if !filepath.IsAbs(socket) {
+			add(fmt.Sprintf("server.listen (LISTEN): %q must use an absolute socket path (e.g. unix:/run/siyuan-share.sock)", c.Server.Listen))
+		}
+		if mode, err := strconv.ParseUint(c.Server.SocketMode, 8, 32); err != nil || mode > 0o777 {
+			add(fmt.Sprintf("server.socket_mode (LISTEN_SOCKET_MODE): %q is not an octal file mode such as 0660", c.Server.SocketMode))
+		}
+	} else if c.Server.Listen != "" {
+		if _, port, err := net.SplitHostPort(c.Server.Listen); err != nil {
+			add(fmt.Sprintf("server.listen (LISTEN): %q is not host:port or unix:<path>", c.Server.Listen))
+		} else {
+			add(validPort("server.listen (LISTEN)", port))
+		}
+	}
+	if c.Server.Listen != "" && c.TLS.Enabled() {
+		add("server.listen (LISTEN): cannot be combined with tls.domains, which listens on tls.http_port and tls.https_port")
+	}
+	for _, p := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			add(fmt.Sprintf("server.trusted_proxies (TRUSTED_PROXIES): %q is not an IP address or CIDR", p))
+		}
+	}
+
+	if len(c.CORS.AllowOrigins) == 0 {
+		add("cors.allow_origins (CORS_ALLOW_ORIGINS): must not be empty (use * to allow any origin)")
+	}
+	for _, o := range c.CORS.AllowOrigins {
+		if o == "*" {
+			if c.CORS.AllowCredentials {
+				add("cors.allow_credentials (CORS_ALLOW_CREDENTIALS): requires explicit origins instead of *")
+			}
+			continue
+		}
+		if u, err := url.Parse(strings.Replace(o, "*.", "", 1)); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			add(fmt.Sprintf("cors.allow_origins (CORS_ALLOW_ORIGINS): %q is not an origin like https://example.com", o))
+		}
+	}
+	if len(c.CORS.AllowMethods) == 0 {
+		add("cors.allow_methods (CORS_ALLOW_METHODS): must not be empty")
+	}
+	for _, m := range c.CORS.AllowMethods {
+		add(oneOf("cors.allow_methods (CORS_ALLOW_METHODS)", m, "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"))
+	}
+	if c.CORS.MaxAge < 0 {
+		add("cors.max_age (CORS_MAX_AGE): must be >= 0")
+	}
+
+	if c.TLS.Enabled() {
+		add(validPort("tls.http_port (TLS_HTTP_PORT)", c.TLS.HTTPPort))
+		add(validPort("tls.https_port (TLS_HTTPS_PORT)", c.TLS.HTTPSPort))
+		if c.TLS.HTTPPort == c.TLS.HTTPSPort {
+			add("tls.http_port / tls.https_port (TLS_HTTP_PORT / TLS_HTTPS_PORT): must differ")
+		}
+		for _, d := range c.TLS.Domains {
+			if !validDomain(d) {
+				add(fmt.Sprintf("tls.domains (TLS_DOMAINS): %q is not a valid domain name (wildcards and IP addresses are not supported)", d))
+			}
+		}
+	}
+
+	add(oneOf("database.driver (DB_DRIVER)", c.Database.Driver, "sqlite", "sqlcipher"))
+	if c.Database.Driver == "sqlcipher" && !c.Server.TestMode && !c.Database.encrypted() {
+		add("database.driver (DB_DRIVER): sqlcipher requires database.encryption_key, encryption_key_file or encryption_key_command (DB_ENCRYPTION_KEY / DB_ENCRYPTION_KEY_FILE / DB_ENCRYPTION_KEY_COMMAND)")
+	}
+	if c.Database.LogMode != "" {
+		add(oneOf("database.log_mode (SQLITE_LOG_MODE)", c.Database.LogMode, "info", "warn", "error", "silent"))
+	}
+	if c.Database.QueryMaxRows < 1 || c.Database.QueryMaxRows > 10000 {
+		add("database.query_max_rows (DB_QUERY_MAX_ROWS): must be between 1 and 10000")
+	}
+	if c.Database.QueryTimeout <= 0 {
+		add("database.query_timeout (DB_QUERY_TIMEOUT): must be positive")
+	}
+	add(oneOf("log.level (LOG_LEVEL)", c.Log.Level, "debug", "info", "warn", "error"))
+	add(oneOf("log.format (LOG_FORMAT)", c.Log.Format, "json", "text"))
+	add(oneOf("log.output (LOG_OUTPUT)", c.Log.Output, "stdout", "syslog", "eventlog"))
+	if c.Log.File != "" && c.Log.Output != "stdout" {
+		add("log.output (LOG_OUTPUT): must be stdout when log.file (LOG_FILE) is set")
+	}
+	if c.Log.Output != "stdout" && c.Log.Tag == "" {
+		add("log.tag (LOG_TAG): required when log.output is syslog or eventlog")
+	}
+	if c.Log.SlowRequest < 0 {
+		add("log.slow_request (LOG_SLOW_REQUEST): must not be negative")
+	}
+	if c.Log.MaxSize < 0 || c.Log.MaxBackups < 0 {
+		add("log.max_size / log.max_backups (LOG_MAX_SIZE / LOG_MAX_BACKUPS): must be >= 0")
+	}
+	if c.Log.RotateInterval != 0 && c.Log.RotateInterval < Duration(time.Minute) {
+		add("log.rotate_interval (LOG_ROTATE_INTERVAL): must be 0 or at least 1m")
+	}
+	if c.Log.MaxAge < 0 {
+		add("log.max_age (LOG_MAX_AGE): must not be negative")
+	}
+
+	add(oneOf("auth.registration (REGISTRATION)", c.Auth.Registration, "open", "invite", "closed"))
+	if c.Auth.LockoutThreshold < 0 || c.Auth.IPFailureLimit < 0 {
+		add("auth.lockout_threshold / auth.ip_failure_limit (LOGIN_LOCKOUT_THRESHOLD / LOGIN_IP_FAILURE_LIMIT): must not be negative")
+	}
+	if c.Auth.LockoutThreshold > 0 && c.Auth.LockoutDuration <= 0 {
+		add("auth.lockout_duration (LOGIN_LOCKOUT_DURATION): must be positive when lockout is enabled")
+	}
+	if c.Auth.DeletionGrace < 0 {
+		add("auth.deletion_grace (ACCOUNT_DELETION_GRACE): must not be negative")
+	}
+	if c.Auth.TokenUsageFlushInterval < Duration(time.Second) {
+		add("auth.token_usage_flush_interval (TOKEN_USAGE_FLUSH_INTERVAL): must be at least 1s")
+	}
+	if c.Secrets.RefreshInterval != 0 && c.Secrets.RefreshInterval < Duration(10*time.Second) {
+		add("secrets.refresh_interval (SECRETS_REFRESH_INTERVAL): must be 0 or at least 10s")
+	}
+	if c.Secrets.Vault.Timeout < 0 {
+		add("secrets.vault.timeout (VAULT_TIMEOUT): must not be negative")
+	}
+
+	if ch := c.Challenge; ch.Provider != "" {
+		add(oneOf("challenge.provider (CHALLENGE_PROVIDER)", ch.Provider, "turnstile", "hcaptcha", "recaptcha"))
+		if ch.SiteKey == "" || ch.SecretKey == "" {
+			add("challenge.site_key / challenge.secret_key (CHALLENGE_SITE_KEY / CHALLENGE_SECRET_KEY): required when challenge.provider is set")
+		}
+		if u, err := url.Parse(ch.VerifyURL); ch.VerifyURL != "" && (err != nil || u.Host == "") {
+			add(fmt.Sprintf("challenge.verify_url (CHALLENGE_VERIFY_URL): %q is not a valid URL", ch.VerifyURL))
+		}
+		for _, a := range ch.Actions {
+			add(oneOf("challenge.actions (CHALLENGE_ACTIONS)", a, "register", "login", "share_password", "comment", "report"))
+		}
+		if ch.AfterFailures < 0 {
+			add("challenge.after_failures (CHALLENGE_AFTER_FAILURES): must not be negative")
+		}
+	}
+
+	if c.SMTP.Host != "" {
+		add(validPort("smtp.port (SMTP_PORT)", c.SMTP.Port))
+		add(oneOf("smtp.tls (SMTP_TLS)", c.SMTP.TLS, "starttls", "tls", "none"))
+		if c.SMTP.From == "" {
+			add("smtp.from (SMTP_FROM): required when smtp.host is set and smtp.username is empty")
+		}
+	}
+
+	if c.Cache.Size < 0 {
+		add("cache.size (CACHE_SIZE): must not be negative")
+	}
+	if c.Cache.Enabled() && c.Cache.TTL <= 0 {
+		add("cache.ttl (CACHE_TTL): must be positive when the cache is enabled")
+	}
+	if c.Cache.RedisURL != "" {
+		if u, err := url.Parse(c.Cache.RedisURL); err != nil || (u.Scheme != "redis" && u.Scheme != "rediss") || u.Host == "" {
+			add(fmt.Sprintf("cache.redis_url (CACHE_REDIS_URL): %q is not a valid Redis URL (e.g. redis://localhost:6379/0)", c.Cache.RedisURL))
+		}
+	}
+
+	add(oneOf("storage.driver (STORAGE_DRIVER)", c.Storage.Driver, "local", "s3"))
+	if c.Storage.Driver == "s3" {
+		s3 := c.Storage.S3
+		if s3.Bucket == "" {
+			add("storage.s3.bucket (S3_BUCKET): required for s3 storage")
+		}
+		if u, err := url.Parse(s3.Endpoint); s3.Endpoint == "" || err != nil || u.Host == "" {
+			add(fmt.Sprintf("storage.s3.endpoint (S3_ENDPOINT): %q is not a valid URL (e.g. https://s3.amazonaws.com)", s3.Endpoint))
+		}
+	}
+
+	add(oneOf("content.storage (CONTENT_STORAGE)", c.Content.Storage, "db", "blob"))
+	add(oneOf("content.compression (CONTENT_COMPRESSION)", c.Content.Compression, "zstd", "none"))
+	if c.Content.OGDefaultImage != "" && !strings.HasPrefix(c.Content.OGDefaultImage, "/") {
+		if u, err := url.Parse(c.Content.OGDefaultImage); err != nil || u.Host == "" {
+			add("content.og_default_image (OG_DEFAULT_IMAGE): must be an absolute URL or a path starting with /")
+		}
+	}
+
+	if c.RateLimit.PublishPerMinute < 0 {
+		add("rate_limit.publish_per_minute (PUBLISH_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.PublishDailyQuota < 0 {
+		add("rate_limit.publish_daily_quota (PUBLISH_DAILY_QUOTA): must be >= 0")
+	}
+	if c.RateLimit.CommentsPerHour < 0 {
+		add("rate_limit.comments_per_hour (COMMENT_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.QuestionsPerHour < 0 {
+		add("rate_limit.questions_per_hour (QA_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.ReportsPerHour < 0 {
+		add("rate_limit.reports_per_hour (REPORT_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.FeedbackPerHour < 0 {
+		add("rate_limit.feedback_per_hour (FEEDBACK_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.VotesPerHour < 0 {
+		add("rate_limit.votes_per_hour (VOTE_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.FormsPerHour < 0 {
+		add("rate_limit.forms_per_hour (FORM_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.OrdersPerHour < 0 {
+		add("rate_limit.orders_per_hour (ORDER_RATE_LIMIT): must be >= 0")
+	}
+	if c.RateLimit.ProgressPerHour < 0 {
+		add("rate_limit.progress_per_hour (PROGRESS_RATE_LIMIT): must be >= 0")
+	}
+	if c.Quota.MaxBytes < 0 || c.Quota.MaxShares < 0 || c.Quota.MaxAssetBytes < 0 || c.Quota.MaxBandwidth < 0 {
+		add("quota.max_bytes / quota.max_shares / quota.max_asset_bytes / quota.max_bandwidth (QUOTA_*): must be >= 0")
+	}
+	if c.Quota.GracePercent < 0 || c.Quota.GracePercent > 100 {
+		add("quota.grace_percent (QUOTA_GRACE_PERCENT): must be between 0 and 100")
+	}
+	if c.Notify.SubscriptionInterval < 0 {
+		add("notify.subscription_interval (SUBSCRIPTION_NOTIFY_INTERVAL): must not be negative")
+	}
+	if c.Links.PreviewTTL <= 0 {
+		add("links.preview_ttl (LINK_PREVIEW_TTL): must be positive")
+	}
+	if c.Links.ArchiveMaxBytes <= 0 {
+		add("links.archive_max_bytes (LINK_ARCHIVE_MAX_BYTES): must be positive")
+	}
+
+	if (c.Push.VAPIDPublicKey == "") != (c.Push.VAPIDPrivateKey == "") {
+		add("push.vapid_public_key / push.vapid_private_key (VAPID_PUBLIC_KEY / VAPID_PRIVATE_KEY): both must be set")
+	}
+	for _, f := range [][2]string{
+		{"pwa.theme_color (PWA_THEME_COLOR)", c.PWA.ThemeColor},
+		{"pwa.background_color (PWA_BACKGROUND_COLOR)", c.PWA.BackgroundColor},
+	} {
+		if !hexColorPattern.MatchString(f[1]) {
+			add(fmt.Sprintf("%s: %q is not a color like #1890ff", f[0], f[1]))
+		}
+	}
+	if !strings.HasPrefix(c.PWA.StartURL, "/") || strings.HasPrefix(c.PWA.StartURL, "//") {
+		add(fmt.Sprintf("pwa.start_url (PWA_START_URL): %q must be a path on this site like /dashboard", c.PWA.StartURL))
+	}
+	for _, f := range [][2]string{
+		{"pwa.icon (PWA_ICON)", c.PWA.Icon},
+		{"pwa.maskable_icon (PWA_MASKABLE_ICON)", c.PWA.MaskableIcon},
+	} {
+		if f[1] == "" {
+			continue
+		}
+		if ext := strings.ToLower(filepath.Ext(f[1])); ext != ".png" && ext != ".svg" {
+			add(fmt.Sprintf("%s: %q must be a .png or .svg file", f[0], f[1]))
+		} else if st, err := os.Stat(f[1]); err != nil || st.IsDir() {
+			add(fmt.Sprintf("%s: cannot read %q", f[0], f[1]))
+		}
+	}
+	switch strings.ToUpper(c.Embed.FrameOptions) {
+	case "DENY", "SAMEORIGIN", "ALLOW":
+	default:
+		add("embed.frame_options (FRAME_OPTIONS): must be DENY, SAMEORIGIN or ALLOW")
+	}
+	if c.Embed.Enabled && len(c.Embed.FrameAncestors) == 0 {
+		add("embed.frame_ancestors (EMBED_FRAME_ANCESTORS): must not be empty when embedding is enabled (use * to allow any site)")
+	}
+	for _, o := range c.Embed.FrameAncestors {
+		if o == "*" {
+			continue
+		}
+		if u, err := url.Parse(strings.Replace(o, "*.", "", 1)); err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			add(fmt.Sprintf("embed.frame_ancestors (EMBED_FRAME_ANCESTORS): %q is not an origin like https://example.com", o))
+		}
+	}
+	if k := c.Signing.PrivateKey; k != "" {
+		if seed, err := base64.RawURLEncoding.DecodeString(k); err != nil || len(seed) != 32 {
+			add("signing.private_key (SIGNING_PRIVATE_KEY): must be a base64url encoded 32-byte Ed25519 seed")
+		}
+	}
+
+	if c.OIDC.RedirectBase != "" {
+		if u, err := url.Parse(c.OIDC.RedirectBase); err != nil || u.Host == "" {
+			add(fmt.Sprintf("oidc.redirect_base (OIDC_REDIRECT_BASE): %q is not a valid URL", c.OIDC.RedirectBase))
+		}
+	}
+	for name, p := range c.OIDC.Providers {
+		env := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		if p.ClientID == "" || p.ClientSecret == "" {
+			add(fmt.Sprintf("oidc.providers.%s (%sCLIENT_ID / %sCLIENT_SECRET): client id and secret are required", name, env, env))
+		}
+		if name != "github" && name != "google" && p.Issuer == "" {
+			add(fmt.Sprintf("oidc.providers.%s.issuer (%sISSUER): required for generic OIDC providers", name, env))
+		}
+	}
+	if c.Export.PDFBaseURL != "" {
+		if u, err := url.Parse(c.Export.PDFBaseURL); err != nil || u.Host == "" {
+			add(fmt.Sprintf("export.pdf_base_url (PDF_BASE_URL): %q is not a valid URL", c.Export.PDFBaseURL))
+		}
+	}
+	if c.CDN.Enabled() {
+		if u, err := url.Parse(c.CDN.AssetBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.RawQuery != "" {
+			add(fmt.Sprintf("cdn.asset_base_url (CDN_ASSET_BASE_URL): %q is not a valid http(s) URL", c.CDN.AssetBaseURL))
+		}
+	}
+	if c.Links.ShortBaseURL != "" {
+		if u, err := url.Parse(c.Links.ShortBaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.RawQuery != "" {
+			add(fmt.Sprintf("links.short_base_url (SHORT_LINK_BASE_URL): %q is not a valid http(s) URL", c.Links.ShortBaseURL))
+		}
+	}
+	if (c.CDN.Enabled() || c.Assets.SignedURLs) && c.CDN.SignedURLTTL < Duration(time.Minute) {
+		add("cdn.signed_url_ttl (CDN_SIGNED_URL_TTL): must be at least 1m")
+	}
+	if c.Images.MaxDimension < 0 {
+		add("images.max_dimension (IMAGE_MAX_DIMENSION): must not be negative")
+	}
+	if c.Images.Quality < 1 || c.Images.Quality > 100 {
+		add("images.quality (IMAGE_QUALITY): must be between 1 and 100")
+	}
+	for _, r := range c.Assets.AllowedReferers {
+		if host := strings.TrimPrefix(r, "*."); host == "" || strings.ContainsAny(host, "/:*@ ") {
+			add(fmt.Sprintf("assets.allowed_referers (ASSET_ALLOWED_REFERERS): %q is not a host name (use example.com or *.example.com)", r))
+		}
+	}
+	if c.Export.PDFTimeout <= 0 {
+		add("export.pdf_timeout (PDF_TIMEOUT): must be positive")
+	}
+
+	if len(c.Geo.Allow) > 0 && len(c.Geo.Block) > 0 {
+		add("geo.allow / geo.block (GEO_ALLOW / GEO_BLOCK): only one of them may be set")
+	}
+	for _, code := range append(append([]string{}, c.Geo.Allow...), c.Geo.Block...) {
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			add(fmt.Sprintf("geo.allow / geo.block (GEO_ALLOW / GEO_BLOCK): %q is not an ISO 3166-1 alpha-2 country code", code))
+		}
+	}
+	if c.Geo.Enabled() && c.Geo.Header == "" && c.Geo.Database == "" {
+		add("geo.header / geo.database (GEO_HEADER / GEO_DATABASE): one of them is required when geo.allow or geo.block is set")
+	}
+	if c.Geo.Database != "" {
+		if _, err := os.Stat(c.Geo.Database); err != nil {
+			add(fmt.Sprintf("geo.database (GEO_DATABASE): %v", err))
+		}
+	}
+	if c.Geo.BlockPage != "" {
+		if _, err := os.Stat(c.Geo.BlockPage); err != nil {
+			add(fmt.Sprintf("geo.block_page (GEO_BLOCK_PAGE): %v", err))
+		}
+	}
+
+	if c.Alert.ShareViews < 0 || c.Alert.ServerErrors < 0 || c.Alert.AuthFailures < 0 {
+		add("alert.share_views / alert.server_errors / alert.auth_failures: must not be negative")
+	}
+	if c.Alert.Cooldown < 0 {
+		add("alert.cooldown (ALERT_COOLDOWN): must not be negative")
+	}
+
+	if c.TTS.Enabled() {
+		if len(c.TTS.Command) > 0 && c.TTS.URL != "" {
+			add("tts: only one of command and url (TTS_URL) may be set")
+		}
+		if c.TTS.URL != "" {
+			if u, err := url.Parse(c.TTS.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				add(fmt.Sprintf("tts.url (TTS_URL): %q is not a valid http(s) URL", c.TTS.URL))
+			}
+		}
+		add(oneOf("tts.format (TTS_FORMAT)", c.TTS.Format, "mp3", "opus", "aac", "flac", "wav"))
+		// 分段合成的音频直接拼接，仅适用于可按帧拼接的格式
+		if c.TTS.ChunkChars > 0 && (c.TTS.Format == "flac" || c.TTS.Format == "wav") {
+			add("tts.chunk_chars (TTS_CHUNK_CHARS): chunked synthesis requires mp3, opus or aac; set it to 0 for " + c.TTS.Format)
+		}
+		if c.TTS.ChunkChars < 0 || c.TTS.MaxChars < 0 || c.TTS.Timeout < 0 {
+			add("tts: chunk_chars, max_chars and timeout must not be negative")
+		}
+	}
+
+	if c.AI.Enabled() {
+		if u, err := url.Parse(c.AI.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			add(fmt.Sprintf("ai.url (AI_URL): %q is not a valid http(s) URL", c.AI.URL))
+		}
+		if c.AI.MaxInputChars < 0 || c.AI.Timeout < 0 {
+			add("ai: max_input_chars and timeout must not be negative")
+		}
+	} else if c.AI.QA {
+		add("ai.qa (AI_QA): requires ai.url (AI_URL)")
+	}
+
+	if c.Translation.Enabled() {
+		if u, err := url.Parse(c.Translation.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			add(fmt.Sprintf("translation.url (TRANSLATION_URL): %q is not a valid http(s) URL", c.Translation.URL))
+		}
+		if c.Translation.ChunkChars < 0 || c.Translation.Timeout < 0 {
+			add("translation: chunk_chars and timeout must not be negative")
+		}
+	}
+	for _, lang := range c.Translation.Languages {
+		if !IsLanguageTag(lang) {
+			add(fmt.Sprintf("translation.languages (TRANSLATION_LANGUAGES): %q is not a language tag such as en or zh-tw", lang))
+		}
+	}
+
+	if c.LanguageCheck.Enabled() {
+		if u, err := url.Parse(c.LanguageCheck.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			add(fmt.Sprintf("language_check.url (LANGUAGE_CHECK_URL): %q is not a valid http(s) URL", c.LanguageCheck.URL))
+		}
+		if c.LanguageCheck.ChunkChars < 0 || c.LanguageCheck.MaxChars < 0 || c.LanguageCheck.MaxIssues < 0 || c.LanguageCheck.Timeout < 0 {
+			add("language_check: chunk_chars, max_chars, max_issues and timeout must not be negative")
+		}
+		if (c.LanguageCheck.Username == "") != (c.LanguageCheck.APIKey == "") {
+			add("language_check: username and api_key must be set together")
+		}
+	}
+
+	if c.Backup.Keep < 0 {
+		add("backup.keep (BACKUP_KEEP): must not be negative")
+	}
+	if c.Backup.S3.Bucket != "" {
+		if u, err := url.Parse(c.Backup.S3.Endpoint); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			add(fmt.Sprintf("backup.s3.endpoint (BACKUP_S3_ENDPOINT): %q is not a valid http(s) URL", c.Backup.S3.Endpoint))
+		}
+	}
+	if c.Jobs.Jitter < 0 || c.Jobs.ShareRetention < 0 || c.Jobs.ViewRetention < 0 || c.Jobs.RollupRetention < 0 || c.Jobs.TrashRetention < 0 {
+		add("jobs: jitter, share_retention, view_retention, summary_retention and trash_retention must not be negative")
+	}
+	if c.Jobs.RollupRetention > 0 && c.Jobs.RollupRetention < c.Jobs.ViewRetention {
+		add("jobs.summary_retention (JOBS_SUMMARY_RETENTION): must be 0 (keep forever) or at least jobs.view_retention")
+	}
+	if c.Jobs.HookMaxAttempts < 1 {
+		add("jobs.hook_max_attempts (JOBS_HOOK_MAX_ATTEMPTS): must be at least 1")
+	}
+	add(oneOf("jobs.leader_election (JOBS_LEADER_ELECTION)", c.Jobs.LeaderElection, "db", "redis", "none"))
+	if c.Jobs.LeaderElection == "redis" && c.Cache.RedisURL == "" {
+		add("jobs.leader_election (JOBS_LEADER_ELECTION): redis requires cache.redis_url (CACHE_REDIS_URL)")
+	}
+	if c.Jobs.LeaderElection != "none" && c.Jobs.LeaseTTL < Duration(3*time.Second) {
+		add("jobs.lease_ttl (JOBS_LEASE_TTL): must be at least 3s")
+	}
+	tasks := c.Jobs.Tasks()
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if tasks[name].Interval < Duration(time.Second) {
+			add(fmt.Sprintf("jobs.%s.interval (JOB_%s_INTERVAL): must be at least 1s", name, strings.ToUpper(name)))
+		}
+	}
+
+	if c.Embedding.Enabled() {
+		if u, err := url.Parse(c.Embedding.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			add(fmt.Sprintf("embedding.url (EMBEDDING_URL): %q is not a valid http(s) URL", c.Embedding.URL))
+		}
+		if c.Embedding.MaxInputChars < 0 || c.Embedding.Timeout < 0 {
+			add("embedding: max_input_chars and timeout must not be negative")
+		}
+	}
+
+	for lang, r := range c.Renderers {
+		field := "renderers." + lang
+		if lang == "" || strings.ContainsAny(lang, " \t/") {
+			add(fmt.Sprintf("renderers: %q is not a valid code block language", lang))
+		}
+		if (len(r.Command) > 0) == (r.URL != "") {
+			add(field + ": exactly one of command and url must be set")
+		}
+		if r.URL != "" {
+			if u, err := url.Parse(r.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				add(fmt.Sprintf("%s.url: %q is not a valid http(s) URL", field, r.URL))
+			}
+		}
+		add(oneOf(field+".content_type", r.ContentType, "image/svg+xml", "image/png", "image/jpeg", "image/webp"))
+		if r.Timeout < 0 || r.MaxBytes < 0 {
+			add(field + ": timeout and max_bytes must be positive")
+		}
+	}
+
+	for name, r := range c.Prerender.Items() {
+		field := "prerender." + name
+		if len(r.Command) > 0 && r.URL != "" {
+			add(field + ": only one of command and url may be set")
+		}
+		if r.URL != "" {
+			if u, err := url.Parse(strings.ReplaceAll(r.URL, "{lang}", "x")); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				add(fmt.Sprintf("%s.url: %q is not a valid http(s) URL", field, r.URL))
+			}
+		}
+		if r.Timeout < 0 || r.MaxBytes < 0 {
+			add(field + ": timeout and max_bytes must be positive")
+		}
+	}
+
+	for i, h := range c.Hooks {
+		field := fmt.Sprintf("hooks[%d] (%s)", i, h.Name)
+		if (h.URL != "") == (h.Script != "") {
+			add(field + ": exactly one of url and script must be set")
+		}
+		if h.URL != "" {
+			if u, err := url.Parse(h.URL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				add(fmt.Sprintf("%s.url: %q is not a valid http(s) URL", field, h.URL))
+			}
+		}
+		if h.Script != "" {
+			if _, err := os.Stat(h.Script); err != nil {
+				add(fmt.Sprintf("%s.script: %v", field, err))
+			}
+		}
+		if len(h.Events) == 0 {
+			add(field + ".events: at least one event is required")
+		}
+		for _, ev := range h.Events {
+			add(oneOf(field+".events", ev, "pre_publish", "post_publish", "pre_render", "auth", "alert"))
+		}
+		if h.Timeout < 0 || h.MaxMemory < 0 {
+			add(field + ": timeout and max_memory must be positive")
+		}
+	}
+	return problems
+}
+
+// warnings 不阻止启动但需要提示的配置
+func (c *Config) warnings() []string {
+	var warns []string
+	if c.Server.TestMode {
+		warns = append(warns, "server.test_mode (TEST_MODE) is enabled; data is kept in memory and anyone can wipe it via POST /api/test/reset")
+	}
+	if c.Auth.SessionSecret == "" {
+		warns = append(warns, "auth.session_secret (SESSION_SECRET) is not set; using an insecure development secret")
+	}
+	if c.Database.Driver != "sqlcipher" && c.Database.encrypted() {
+		warns = append(warns, "database.encryption_key has no effect unless database.driver (DB_DRIVER) is sqlcipher; the database is not encrypted")
+	}
+	if c.Auth.RequireEmailVerification && !c.SMTPEnabled() {
+		warns = append(warns, "auth.require_email_verification has no effect until SMTP is configured")
+	}
+	if c.Storage.SignedDownloads && c.Storage.Driver != "s3" {
+		warns = append(warns, "storage.signed_downloads (STORAGE_SIGNED_DOWNLOADS) has no effect unless storage.driver is s3; downloads are served by this server")
+	}
+	if c.Server.UnixSocket() != "" && c.Export.PDFBaseURL == "" {
+		warns = append(warns, "export.pdf_base_url (PDF_BASE_URL) is not set; PDF export is unavailable while listening on a Unix socket")
+	}
+	return warns
+}
+
+// hexColorPattern #rgb 或 #rrggbb 形式的颜色
+var hexColorPattern = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// languageTagPattern 小写的 BCP 47 语言标签（语言与可选的地区或文字），如 en、ja、zh-tw、zh-hans
+var languageTagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// IsLanguageTag 是否为小写的语言标签，用作译文的路径段 /s/<id>/<语言>
+func IsLanguageTag(tag string) bool {
+	return languageTagPattern.MatchString(tag)
+}
+
+// encrypted 是否配置了数据库加密密钥来源
+func (d DatabaseConfig) encrypted() bool {
+	return d.EncryptionKey != "" || d.EncryptionKeyFile != "" || len(d.EncryptionKeyCommand) > 0
+}
@@ -0,0 +1,307 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// 受限分享的访问令牌：邮件中的登录链接令牌短时有效，兑换后得到较长期的访问令牌；
+// 打印令牌供服务端导出 PDF 时由无头浏览器打开阅读页
+const (
+	purposeShareLink  = "share_link"
+	purposeShareGrant = "share_grant"
+	purposeSharePrint = "share_print"
+)
+
+const (
+	shareLinkTTL  = 30 * time.Minute
+	shareGrantTTL = 30 * 24 * time.Hour
+	sharePrintTTL = 2 * time.Minute
+	// maxShareAccessEntries 单个分享访问名单的最大条目数
+	maxShareAccessEntries = 200
+)
+
+// ShareAccessRequest 替换分享访问名单：每项为用户名或邮箱
+type ShareAccessRequest struct {
+	Entries []string `json:"entries" binding:"max=200"`
+}
+
+// ShareAccessLinkRequest 读者申请受限分享的邮件登录链接
+type ShareAccessLinkRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// ShareAccessVerifyRequest 读者用邮件链接中的令牌兑换访问令牌
+type ShareAccessVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// aclShareID 访问名单所属的分享：引用块子分享沿用父分享的名单
+func aclShareID(share *models.Share) string {
+	if share.ParentShareID != "" {
+		return share.ParentShareID
+	}
+	return share.ID
+}
+
+// issueShareToken 签发绑定分享与邮箱的访问令牌
+func issueShareToken(shareID, email, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": email,
+		"shr": shareID,
+		"pur": purpose,
+		"iat": now.Unix(),
+		"exp": now.Add(ttl).Unix(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(purposeKey(purpose))
+}
+
+// parseShareToken 校验访问令牌的签名、有效期、用途与所属分享，并确认邮箱仍在访问名单中，返回邮箱
+func parseShareToken(raw, shareID, purpose string) (string, error) {
+	email, err := parseShareClaims(raw, shareID, purpose)
+	if err != nil {
+		return "", err
+	}
+	// 从名单中移除后已签发的令牌随即失效
+	if !emailAllowed(shareID, email) {
+		return "", errors.New("access revoked")
+	}
+	return email, nil
+}
+
+// parseShareClaims 校验分享令牌的签名、有效期、用途与所属分享，返回令牌主体
+func parseShareClaims(raw, shareID, purpose string) (string, error) {
+	tok, err := jwt.Parse(raw, func(t *jwt.Token) (interface{}, error) {
+		return purposeKeySet(purpose), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired())
+	if err != nil || !tok.Valid {
+		return "", errors.New("invalid or expired token")
+	}
+	claims, _ := tok.Claims.(jwt.MapClaims)
+	email, _ := claims["sub"].(string)
+	shr, _ := claims["shr"].(string)
+	if pur, _ := claims["pur"].(string); pur != purpose || shr != shareID || email == "" {
+		return "", errors.New("invalid or expired token")
+	}
+	return email, nil
+}
+
+// isPrintRequest 请求是否携带该分享有效的打印令牌（X-Share-Print 请求头），
+// 打印请求跳过访问名单与密码校验，且不计入浏览次数
+func isPrintRequest(c *gin.Context, shareID string) bool {
+	token := c.GetHeader("X-Share-Print")
+	if token == "" {
+		return false
+	}
+	_, err := parseShareClaims(token, shareID, purposeSharePrint)
+	return err == nil
+}
+
+// emailAllowed 邮箱是否在分享访问名单中
+func emailAllowed(shareID, email string) bool {
+	var count int64
+	models.DB.Model(&models.ShareAccess{}).Where("share_id = ? AND email = ?", shareID, strings.ToLower(email)).Count(&count)
+	return count > 0
+}
+
+// canAccessRestricted 判断读者能否打开受限分享：分享者本人、名单中的注册用户、
+// 已验证邮箱在名单中的用户，或持有有效访问令牌（X-Share-Access 请求头或 access 查询参数）的读者
+func canAccessRestricted(c *gin.Context, share *models.Share) bool {
+	aclID := aclShareID(share)
+	if userID := middleware.IdentifyUser(c); userID != "" {
+		if userID == share.UserID {
+			return true
+		}
+		var user models.User
+		if err := models.DB.Select("id", "email", "email_verified").Where("id = ?", userID).First(&user).Error; err == nil {
+			query := models.DB.Model(&models.ShareAccess{}).Where("share_id = ?", aclID)
+			if user.EmailVerified && user.Email != "" {
+				query = query.Where("user_id = ? OR email = ?", user.ID, strings.ToLower(user.Email))
+			} else {
+				query = query.Where("user_id = ?", user.ID)
+			}
+			var count int64
+			if query.Count(&count); count > 0 {
+				return true
+			}
+		}
+	}
+	token := c.GetHeader("X-Share-Access")
+	if token == "" {
+		token = c.Query("access")
+	}
+	if token != "" {
+		if _, err := parseShareToken(token, aclID, purposeShareGrant); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetShareAccess 分享者查看受限访问设置与访问名单
+func GetShareAccess(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var items []models.ShareAccess
+	if err := models.DB.Where("share_id = ?", share.ID).Order("id").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load access list: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"restricted":     share.Restricted,
+		"assetDownloads": share.AssetDownloads,
+		"items":          items,
+		"emailAccess":    mailer.Enabled(),
+	}})
+}
+
+// UpdateShareAccess 分享者替换访问名单：含 @ 的条目视为邮箱，其余按用户名匹配注册用户
+func UpdateShareAccess(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var req ShareAccessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+
+	items := make([]models.ShareAccess, 0, len(req.Entries))
+	seen := map[string]bool{}
+	for _, raw := range req.Entries {
+		entry := strings.TrimSpace(raw)
+		if entry == "" {
+			continue
+		}
+		item := models.ShareAccess{ShareID: share.ID}
+		if strings.Contains(entry, "@") {
+			addr, err := mail.ParseAddress(entry)
+			if err != nil || addr.Name != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid email: " + entry})
+				return
+			}
+			item.Email = strings.ToLower(addr.Address)
+		} else {
+			var user models.User
+			if err := models.DB.Select("id", "username").Where("username = ?", entry).First(&user).Error; err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "User not found: " + entry})
+				return
+			}
+			item.UserID, item.Username = user.ID, user.Username
+		}
+		key := item.UserID + "|" + item.Email
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		items = append(items, item)
+	}
+	if len(items) > maxShareAccessEntries {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": fmt.Sprintf("At most %d entries are allowed", maxShareAccessEntries)})
+		return
+	}
+
+	tx := models.DB.Begin()
+	if err := tx.Where("share_id = ?", share.ID).Delete(&models.ShareAccess{}).Error; err != nil {
+		tx.Rollback()
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update access list: " + err.Error()})
+		return
+	}
+	if len(items) > 0 {
+		if err := tx.Create(&items).Error; err != nil {
+			tx.Rollback()
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update access list: " + err.Error()})
+			return
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update access list: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"restricted": share.Restricted, "items": items}})
+}
+
+// RequestShareAccess 读者申请邮件登录链接：仅当邮箱在访问名单中时发信，
+// 响应始终一致以免泄露名单内容
+func RequestShareAccess(c *gin.Context) {
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil || !share.Reachable() || share.IsExpired() {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	var req ShareAccessLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if !mailer.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Email access is not available"})
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	aclID := aclShareID(&share)
+	if share.Restricted && emailAllowed(aclID, email) {
+		token, err := issueShareToken(aclID, email, purposeShareLink, shareLinkTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to issue token"})
+			return
+		}
+		link := getBaseURL(c) + "/s/" + share.ID + "?access=" + token
+		locale := middleware.Locale(c)
+		if err := mailer.Send(mailer.Message{
+			To:      email,
+			Subject: i18n.T(locale, "email.share_access.subject", share.DocTitle),
+			Body:    i18n.T(locale, "email.share_access.body", share.DocTitle, int(shareLinkTTL.Minutes()), link),
+		}); err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to send access email"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"pending": true}})
+}
+
+// VerifyShareAccess 读者用邮件链接中的令牌兑换访问令牌，之后通过 X-Share-Access 请求头访问受限分享
+func VerifyShareAccess(c *gin.Context) {
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	var req ShareAccessVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	aclID := aclShareID(&share)
+	email, err := parseShareToken(req.Token, aclID, purposeShareLink)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid or expired link"})
+		return
+	}
+	expiresAt := time.Now().Add(shareGrantTTL)
+	token, err := issueShareToken(aclID, email, purposeShareGrant, shareGrantTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to issue token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"accessToken": token,
+		"email":       email,
+		"expiresAt":   expiresAt,
+	}})
+}
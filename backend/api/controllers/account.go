@@ -0,0 +1,283 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	jwt "github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 一次性令牌用途，不同用途使用不同的派生密钥签名，互相不能通用，也不能当作会话令牌使用
+const (
+	purposeVerifyEmail   = "verify_email"
+	purposeResetPassword = "reset_password"
+	purposeUnlockAccount = "unlock_account"
+	// purposeOIDCTwoFactor 第三方登录后待完成两步验证，实际用途带提供方名称（oidc_two_factor:<提供方>）
+	purposeOIDCTwoFactor = "oidc_two_factor"
+)
+
+const (
+	verifyEmailTTL   = 48 * time.Hour
+	resetPasswordTTL = time.Hour
+)
+
+// purposeKey 根据会话密钥与用途派生签名密钥
+func purposeKey(purpose string) []byte {
+	return derivePurposeKey(config.Get().SessionSecret(), purpose)
+}
+
+// purposeKeys 校验时接受的派生密钥：当前会话密钥在前，其后为轮换前的旧密钥 (auth.session_secret_previous)
+func purposeKeys(purpose string) [][]byte {
+	secrets := config.Get().SessionSecrets()
+	keys := make([][]byte, len(secrets))
+	for i, secret := range secrets {
+		keys[i] = derivePurposeKey(secret, purpose)
+	}
+	return keys
+}
+
+// purposeKeySet 供 jwt.Parse 使用的 purposeKeys
+func purposeKeySet(purpose string) jwt.VerificationKeySet {
+	var set jwt.VerificationKeySet
+	for _, key := range purposeKeys(purpose) {
+		set.Keys = append(set.Keys, key)
+	}
+	return set
+}
+
+func derivePurposeKey(secret []byte, purpose string) []byte {
+	sum := sha256.Sum256([]byte(string(secret) + "|" + purpose))
+	return sum[:]
+}
+
+// accountFingerprint 账号状态指纹：邮箱验证令牌绑定邮箱，重置密码令牌绑定当前密码哈希，解锁令牌绑定本次锁定，
+// 状态变化（验证完成 / 密码已修改 / 已解锁）后旧令牌自动失效，从而保证一次性
+func accountFingerprint(user *models.User, purpose string) string {
+	src := user.Email
+	switch purpose {
+	case purposeResetPassword:
+		src = user.PasswordHash
+	case purposeUnlockAccount:
+		src = "unlocked"
+		if user.LockedUntil != nil {
+			src = strconv.FormatInt(user.LockedUntil.Unix(), 10)
+		}
+	}
+	if strings.HasPrefix(purpose, purposeOIDCTwoFactor+":") {
+		// 关闭或重新设置两步验证后作废；验证码与恢复码本身已保证一次性
+		src = user.TOTPSecret
+	}
+	sum := sha256.Sum256([]byte(user.ID + "|" + src))
+	return hex.EncodeToString(sum[:8])
+}
+
+// issueAccountToken 签发带用途的一次性限时令牌
+func issueAccountToken(user *models.User, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": user.ID,
+		"pur": purpose,
+		"fp":  accountFingerprint(user, purpose),
+		"iat": now.Unix(),
+		"exp": now.Add(ttl).Unix(),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(purposeKey(purpose))
+}
+
+// parseAccountToken 校验令牌签名、有效期、用途与账号指纹，返回对应用户
+func parseAccountToken(raw, purpose string) (*models.User, error) {
+	tok, err := jwt.Parse(raw, func(t *jwt.Token) (interface{}, error) {
+		return purposeKeySet(purpose), nil
+	}, jwt.WithValidMethods([]string{"HS256"}), jwt.WithExpirationRequired())
+	if err != nil || !tok.Valid {
+		return nil, errors.New("invalid or expired token")
+	}
+	claims, _ := tok.Claims.(jwt.MapClaims)
+	sub, _ := claims["sub"].(string)
+	if pur, _ := claims["pur"].(string); pur != purpose || sub == "" {
+		return nil, errors.New("invalid or expired token")
+	}
+	var user models.User
+	if err := models.DB.Where("id = ?", sub).First(&user).Error; err != nil {
+		return nil, errors.New("invalid or expired token")
+	}
+	if fp, _ := claims["fp"].(string); fp != accountFingerprint(&user, purpose) {
+		return nil, errors.New("token has already been used")
+	}
+	return &user, nil
+}
+
+// emailVerificationRequired 是否要求验证邮箱后才能登录 (auth.require_email_verification)
+func emailVerificationRequired() bool {
+	return config.Get().Auth.RequireEmailVerification && mailer.Enabled()
+}
+
+// userLocale 发给用户的邮件使用的语言：用户的语言偏好，未设置时使用当前请求的语言
+func userLocale(c *gin.Context, user *models.User) string {
+	if l := i18n.Match(user.Locale); l != "" {
+		return l
+	}
+	return middleware.Locale(c)
+}
+
+// sendVerificationEmail 发送邮箱验证邮件
+func sendVerificationEmail(c *gin.Context, user *models.User) error {
+	token, err := issueAccountToken(user, purposeVerifyEmail, verifyEmailTTL)
+	if err != nil {
+		return err
+	}
+	link := getBaseURL(c) + "/api/auth/verify-email?token=" + token
+	locale := userLocale(c, user)
+	return mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(locale, "email.verify.subject"),
+		Body:    i18n.T(locale, "email.verify.body", user.Username, int(verifyEmailTTL.Hours()), link),
+	})
+}
+
+// VerifyEmail 通过邮件中的链接验证邮箱（GET 链接直接在浏览器打开，POST 供前端调用）
+func VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		var req struct {
+			Token string `json:"token"`
+		}
+		_ = c.ShouldBindJSON(&req)
+		token = req.Token
+	}
+	respond := func(status int, msg, page string) {
+		if c.Request.Method == http.MethodGet {
+			messagePage(c, status, page)
+			return
+		}
+		if status == http.StatusOK {
+			c.JSON(status, gin.H{"code": 0, "msg": "success"})
+			return
+		}
+		c.JSON(status, gin.H{"code": 1, "msg": msg})
+	}
+
+	user, err := parseAccountToken(token, purposeVerifyEmail)
+	if err != nil {
+		respond(http.StatusBadRequest, "Invalid or expired token", "page.verify_invalid")
+		return
+	}
+	if !user.EmailVerified {
+		if err := models.DB.Model(user).Update("email_verified", true).Error; err != nil {
+			respond(http.StatusInternalServerError, "Failed to verify email", "page.verify_failed")
+			return
+		}
+	}
+	respond(http.StatusOK, "", "page.verified")
+}
+
+// ResendVerification 重新发送邮箱验证邮件（需登录）
+func ResendVerification(c *gin.Context) {
+	var user models.User
+	if err := models.DB.Where("id = ?", c.GetString("userID")).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "User not found"})
+		return
+	}
+	if user.EmailVerified {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Email already verified"})
+		return
+	}
+	if !mailer.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Email is not configured"})
+		return
+	}
+	if err := sendVerificationEmail(c, &user); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to send verification email"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// ForgotPasswordRequest 申请重置密码
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ForgotPassword 发送重置密码邮件；无论邮箱是否注册都返回成功，避免探测账号
+func ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if !mailer.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Email is not configured"})
+		return
+	}
+
+	var user models.User
+	if err := models.DB.Where("LOWER(email) = LOWER(?) AND is_active = ?", strings.TrimSpace(req.Email), true).First(&user).Error; err == nil {
+		token, err := issueAccountToken(&user, purposeResetPassword, resetPasswordTTL)
+		if err == nil {
+			link := getBaseURL(c) + "/reset-password?token=" + token
+			locale := userLocale(c, &user)
+			err = mailer.Send(mailer.Message{
+				To:      user.Email,
+				Subject: i18n.T(locale, "email.reset.subject"),
+				Body:    i18n.T(locale, "email.reset.body", user.Username, int(resetPasswordTTL.Minutes()), link),
+			})
+		}
+		if err != nil {
+			log.Printf("Failed to send password reset email to %s: %v", user.ID, err)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// ResetPasswordRequest 使用令牌设置新密码
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6,max=200"`
+}
+
+// ResetPassword 校验重置令牌并设置新密码
+func ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	user, err := parseAccountToken(req.Token, purposeResetPassword)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid or expired token"})
+		return
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to hash password"})
+		return
+	}
+	// 能收到重置邮件即证明邮箱有效，同时解除登录锁定
+	if err := models.DB.Model(user).Updates(map[string]interface{}{
+		"password_hash":  string(hash),
+		"email_verified": true,
+		"failed_logins":  0,
+		"locked_until":   nil,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to reset password"})
+		return
+	}
+	// 密码重置后注销所有已登录会话
+	if err := revokeUserSessions(user.ID); err != nil {
+		log.Printf("Failed to revoke sessions for %s: %v", user.ID, err)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/gin-gonic/gin"
+)
+
+// AnnotationRequest 创建/更新批注请求
+type AnnotationRequest struct {
+	BlockID     string `json:"blockId" binding:"max=64"`
+	Quote       string `json:"quote" binding:"required,max=2000"`
+	Prefix      string `json:"prefix" binding:"max=255"`
+	Suffix      string `json:"suffix" binding:"max=255"`
+	StartOffset int    `json:"startOffset" binding:"min=0"`
+	EndOffset   int    `json:"endOffset" binding:"min=0"`
+	Color       string `json:"color" binding:"max=32"`
+	Note        string `json:"note" binding:"max=5000"`
+}
+
+// UpdateAnnotationRequest 作者修改批注内容
+type UpdateAnnotationRequest struct {
+	Color *string `json:"color" binding:"omitempty,max=32"`
+	Note  *string `json:"note" binding:"omitempty,max=5000"`
+}
+
+// ModerateAnnotationRequest 分享者审核批注
+type ModerateAnnotationRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// ListShareAnnotations 读者查看分享上可见的批注
+func ListShareAnnotations(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if !share.AllowAnnotation {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"enabled": false, "items": []models.Annotation{}}})
+		return
+	}
+	var items []models.Annotation
+	if err := models.DB.Where("share_id = ? AND hidden = ?", share.ID, false).Order("created_at").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list annotations: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"enabled": true, "items": items}})
+}
+
+// CreateAnnotation 登录读者在分享上划线批注
+func CreateAnnotation(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if !share.AllowAnnotation {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Annotations are disabled for this share"})
+		return
+	}
+	var req AnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.EndOffset < req.StartOffset {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "endOffset must not be less than startOffset"})
+		return
+	}
+
+	userID := c.GetString("userID")
+	var user models.User
+	if err := models.DB.Select("id", "username").Where("id = ?", userID).First(&user).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "User not found"})
+		return
+	}
+
+	a := &models.Annotation{
+		ID:          "ann_" + randHex(10),
+		ShareID:     share.ID,
+		UserID:      user.ID,
+		Username:    user.Username,
+		BlockID:     strings.TrimSpace(req.BlockID),
+		Quote:       req.Quote,
+		Prefix:      req.Prefix,
+		Suffix:      req.Suffix,
+		StartOffset: req.StartOffset,
+		EndOffset:   req.EndOffset,
+		Color:       req.Color,
+		Note:        strings.TrimSpace(req.Note),
+	}
+	if err := models.DB.Create(a).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save annotation: " + err.Error()})
+		return
+	}
+
+	// 通知分享者有新的批注
+	if share.UserID != user.ID {
+		notify.UserPush(share.UserID, notify.PushMessage{
+			Title: share.DocTitle,
+			Body:  user.Username + " 添加了批注：" + plainSummary(a.Quote, 80),
+			URL:   getBaseURL(c) + "/s/" + share.ID,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": a})
+}
+
+// UpdateAnnotation 批注作者修改颜色或备注
+func UpdateAnnotation(c *gin.Context) {
+	var req UpdateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	var a models.Annotation
+	if err := models.DB.Where("id = ? AND share_id = ? AND user_id = ?", c.Param("aid"), c.Param("id"), c.GetString("userID")).
+		First(&a).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Annotation not found"})
+		return
+	}
+	updates := map[string]interface{}{}
+	if req.Color != nil {
+		updates["color"] = *req.Color
+	}
+	if req.Note != nil {
+		updates["note"] = strings.TrimSpace(*req.Note)
+	}
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "No fields to update"})
+		return
+	}
+	if err := models.DB.Model(&a).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update annotation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": a})
+}
+
+// DeleteAnnotation 删除批注（批注作者或分享者）
+func DeleteAnnotation(c *gin.Context) {
+	userID := c.GetString("userID")
+	var a models.Annotation
+	if err := models.DB.Where("id = ? AND share_id = ?", c.Param("aid"), c.Param("id")).First(&a).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Annotation not found"})
+		return
+	}
+	if a.UserID != userID {
+		var count int64
+		models.DB.Model(&models.Share{}).Where("id = ? AND user_id = ?", a.ShareID, userID).Count(&count)
+		if count == 0 {
+			c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Not allowed to delete this annotation"})
+			return
+		}
+	}
+	if err := models.DB.Delete(&a).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete annotation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// ListOwnerAnnotations 分享者查看全部批注（含已隐藏）
+func ListOwnerAnnotations(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var items []models.Annotation
+	if err := models.DB.Where("share_id = ?", share.ID).Order("created_at DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list annotations: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"enabled": share.AllowAnnotation, "items": items}})
+}
+
+// ModerateAnnotation 分享者隐藏或恢复批注
+func ModerateAnnotation(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var req ModerateAnnotationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	result := models.DB.Model(&models.Annotation{}).Where("id = ? AND share_id = ?", c.Param("aid"), share.ID).Update("hidden", req.Hidden)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update annotation: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Annotation not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
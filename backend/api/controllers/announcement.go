@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxAnnouncements 公告数量上限（含已过期的）
+const maxAnnouncements = 200
+
+// announcementCache 全部公告的内存缓存，阅读页每次打开都会读取；公告变更后清空，下次读取时重新加载
+var announcementCache atomic.Pointer[[]models.Announcement]
+
+// AnnouncementRequest 创建或更新公告
+type AnnouncementRequest struct {
+	Title       string     `json:"title" binding:"required"`
+	Content     string     `json:"content"`
+	Link        string     `json:"link"`
+	Level       string     `json:"level"`    // info（默认）/ warning / error
+	Audience    string     `json:"audience"` // all（默认）/ dashboard / shares
+	Dismissible bool       `json:"dismissible"`
+	StartsAt    *time.Time `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt"`
+}
+
+// normalizeAnnouncement 校验公告：标题 1-200 字、正文最多 2000 字，链接须为站内路径或 http(s) 地址，结束时间晚于开始时间
+func normalizeAnnouncement(req *AnnouncementRequest) (*models.Announcement, error) {
+	a := &models.Announcement{
+		Title:       slug.Title(req.Title),
+		Content:     strings.TrimSpace(req.Content),
+		Link:        strings.TrimSpace(req.Link),
+		Level:       req.Level,
+		Audience:    req.Audience,
+		Dismissible: req.Dismissible,
+		StartsAt:    req.StartsAt,
+		EndsAt:      req.EndsAt,
+	}
+	if a.Level == "" {
+		a.Level = "info"
+	}
+	if a.Audience == "" {
+		a.Audience = "all"
+	}
+	switch {
+	case a.Title == "" || utf8.RuneCountInString(a.Title) > 200:
+		return nil, errors.New("Title must be 1-200 characters")
+	case utf8.RuneCountInString(a.Content) > 2000:
+		return nil, errors.New("Content must be at most 2000 characters")
+	case a.Level != "info" && a.Level != "warning" && a.Level != "error":
+		return nil, errors.New("level must be info, warning or error")
+	case a.Audience != "all" && a.Audience != "dashboard" && a.Audience != "shares":
+		return nil, errors.New("audience must be all, dashboard or shares")
+	case a.StartsAt != nil && a.EndsAt != nil && !a.EndsAt.After(*a.StartsAt):
+		return nil, errors.New("endsAt must be after startsAt")
+	}
+	switch {
+	case a.Link == "":
+	case len(a.Link) > 2048:
+		return nil, errors.New("link is too long")
+	case strings.HasPrefix(a.Link, "/") && !strings.HasPrefix(a.Link, "//"):
+	case strings.HasPrefix(a.Link, "http://") || strings.HasPrefix(a.Link, "https://"):
+		if u, err := url.Parse(a.Link); err != nil || u.Host == "" {
+			return nil, errors.New("link is not a valid URL")
+		}
+	default:
+		return nil, errors.New("link must be a path starting with / or an http(s) URL")
+	}
+	return a, nil
+}
+
+// loadAnnouncements 全部公告（按开始时间倒序），优先使用缓存
+func loadAnnouncements() ([]models.Announcement, error) {
+	if items := announcementCache.Load(); items != nil {
+		return *items, nil
+	}
+	items := []models.Announcement{}
+	if err := models.DB.Order("COALESCE(starts_at, created_at) DESC, id DESC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	announcementCache.Store(&items)
+	return items, nil
+}
+
+// ListAnnouncements 当前显示的公告（公开）：scope 为 dashboard（控制面板）或 shares（阅读页，默认），
+// 只返回显示时间段内、面向该页面的公告；携带登录凭证时不返回该用户已关闭的公告
+func ListAnnouncements(c *gin.Context) {
+	scope := c.DefaultQuery("scope", "shares")
+	if scope != "dashboard" && scope != "shares" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "scope must be dashboard or shares"})
+		return
+	}
+	all, err := loadAnnouncements()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list announcements: " + err.Error()})
+		return
+	}
+	now := time.Now()
+	items := []models.Announcement{}
+	for _, a := range all {
+		if (a.Audience == "all" || a.Audience == scope) && a.ActiveAt(now) {
+			items = append(items, a)
+		}
+	}
+	if userID := middleware.IdentifyUser(c); userID != "" && len(items) > 0 {
+		var dismissed []uint
+		models.DB.Model(&models.AnnouncementDismissal{}).Where("user_id = ?", userID).Pluck("announcement_id", &dismissed)
+		hidden := map[uint]bool{}
+		for _, id := range dismissed {
+			hidden[id] = true
+		}
+		kept := items[:0]
+		for _, a := range items {
+			if !a.Dismissible || !hidden[a.ID] {
+				kept = append(kept, a)
+			}
+		}
+		items = kept
+	}
+	// 公告只在结束时间之后失效，不做缓存以免过期公告继续显示
+	c.Header("Cache-Control", "no-cache")
+	for i := range items {
+		items[i].CreatedBy = ""
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// DismissAnnouncement 登录用户关闭公告，之后不再显示；重复关闭不报错
+func DismissAnnouncement(c *gin.Context) {
+	var a models.Announcement
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&a).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Announcement not found"})
+		return
+	}
+	if !a.Dismissible {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "This announcement cannot be dismissed"})
+		return
+	}
+	d := models.AnnouncementDismissal{AnnouncementID: a.ID, UserID: c.GetString("userID"), DismissedAt: time.Now()}
+	if err := models.DB.Where(models.AnnouncementDismissal{AnnouncementID: d.AnnouncementID, UserID: d.UserID}).
+		FirstOrCreate(&d).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to dismiss announcement: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// adminAnnouncement 管理员公告列表的条目
+type adminAnnouncement struct {
+	models.Announcement
+	Active     bool  `json:"active"`     // 当前处于显示时间段内
+	Dismissals int64 `json:"dismissals"` // 关闭该公告的登录用户数
+}
+
+// AdminListAnnouncements 管理员查看全部公告（含未开始与已结束的），附带关闭人数与当前是否显示
+func AdminListAnnouncements(c *gin.Context) {
+	items := []models.Announcement{}
+	if err := models.DB.Order("id DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list announcements: " + err.Error()})
+		return
+	}
+	var counts []struct {
+		AnnouncementID uint
+		N              int64
+	}
+	models.DB.Model(&models.AnnouncementDismissal{}).Select("announcement_id, COUNT(*) AS n").Group("announcement_id").Scan(&counts)
+	dismissals := map[uint]int64{}
+	for _, row := range counts {
+		dismissals[row.AnnouncementID] = row.N
+	}
+	now := time.Now()
+	out := make([]adminAnnouncement, 0, len(items))
+	for _, a := range items {
+		out = append(out, adminAnnouncement{Announcement: a, Active: a.ActiveAt(now), Dismissals: dismissals[a.ID]})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": out}})
+}
+
+// CreateAnnouncement 管理员发布公告
+func CreateAnnouncement(c *gin.Context) {
+	var req AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	a, err := normalizeAnnouncement(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	var count int64
+	models.DB.Model(&models.Announcement{}).Count(&count)
+	if count >= maxAnnouncements {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Too many announcements"})
+		return
+	}
+	a.CreatedBy = c.GetString("userID")
+	if err := models.DB.Create(a).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create announcement: " + err.Error()})
+		return
+	}
+	announcementCache.Store(nil)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": a})
+}
+
+// UpdateAnnouncement 管理员修改公告；resetDismissals=1 时清除关闭记录，已关闭的用户重新看到公告（如条款再次更新）
+func UpdateAnnouncement(c *gin.Context) {
+	var existing models.Announcement
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&existing).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Announcement not found"})
+		return
+	}
+	var req AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	a, err := normalizeAnnouncement(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	a.ID = existing.ID
+	a.CreatedBy = existing.CreatedBy
+	a.CreatedAt = existing.CreatedAt
+	reset, _ := strconv.ParseBool(c.Query("resetDismissals"))
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(a).Error; err != nil {
+			return err
+		}
+		if !reset {
+			return nil
+		}
+		return tx.Where("announcement_id = ?", a.ID).Delete(&models.AnnouncementDismissal{}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update announcement: " + err.Error()})
+		return
+	}
+	announcementCache.Store(nil)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": a})
+}
+
+// DeleteAnnouncement 管理员删除公告及其关闭记录
+func DeleteAnnouncement(c *gin.Context) {
+	var res *gorm.DB
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		res = tx.Where("id = ?", c.Param("id")).Delete(&models.Announcement{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return tx.Where("announcement_id = ?", c.Param("id")).Delete(&models.AnnouncementDismissal{}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete announcement: " + err.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Announcement not found"})
+		return
+	}
+	announcementCache.Store(nil)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// archivedLinks 返回已完成存档的链接（URL -> 存档副本地址），分享未开启链接存档时为空
+func archivedLinks(share *models.Share) map[string]string {
+	result := map[string]string{}
+	if !share.ArchiveLinks {
+		return result
+	}
+	var snaps []models.LinkSnapshot
+	models.DB.Select("id", "url").Where("share_id = ? AND status = ?", share.ID, models.SnapshotOK).Find(&snaps)
+	for _, s := range snaps {
+		result[s.URL] = "/api/s/" + share.ID + "/archive/" + s.ID
+	}
+	return result
+}
+
+// ListShareSnapshots 列出分享中已存档的外部链接
+func ListShareSnapshots(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	items := []models.LinkSnapshot{}
+	if share.ArchiveLinks {
+		if err := models.DB.Where("share_id = ? AND status = ?", share.ID, models.SnapshotOK).
+			Order("created_at").Find(&items).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list snapshots: " + err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// ServeSnapshot 输出存档副本；存档页面禁止执行脚本，PDF 以内联方式展示
+func ServeSnapshot(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var snap models.LinkSnapshot
+	if !share.ArchiveLinks || models.DB.Where("id = ? AND share_id = ? AND status = ?",
+		c.Param("sid"), share.ID, models.SnapshotOK).First(&snap).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Snapshot not found"})
+		return
+	}
+	if storage.Default == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Storage not available"})
+		return
+	}
+	rc, info, err := storage.Default.Get(c.Request.Context(), snap.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Snapshot not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read snapshot: " + err.Error()})
+		return
+	}
+	defer rc.Close()
+
+	c.Header("Content-Type", snap.ContentType)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("X-Robots-Tag", "noindex")
+	c.Header("Cache-Control", "public, max-age=3600")
+	if info != nil && info.Size > 0 {
+		c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	if snap.ContentType == "application/pdf" {
+		c.Header("Content-Disposition", "inline")
+	} else {
+		// 浏览器内置 PDF 阅读器不支持 sandbox，仅对存档页面启用
+		c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src https: http: data:; sandbox")
+	}
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, rc)
+}
+
+// ListOwnerSnapshots 分享所有者查看全部存档记录（含抓取中与失败的链接）
+func ListOwnerSnapshots(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	items := []models.LinkSnapshot{}
+	if err := models.DB.Where("share_id = ?", share.ID).Order("created_at").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list snapshots: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"enabled": share.ArchiveLinks, "items": items}})
+}
@@ -0,0 +1,325 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/imageopt"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UploadAsset 上传分享引用的资源文件（multipart: file, path, 可选 sha256/size 用于校验）
+// 同一路径重复上传相同内容时幂等返回，不会重复写入存储
+func UploadAsset(c *gin.Context) {
+	shareID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var share models.Share
+	if err := models.DB.Where("id = ? AND user_id = ?", shareID, userID).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found or unauthorized"})
+		return
+	}
+	if rejectSealed(c, &share) {
+		return
+	}
+
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	assetPath, err := normalizeAssetPath(c.PostForm("path"), fh.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid asset path"})
+		return
+	}
+
+	quota := models.UserQuota(userID)
+	if quota.MaxAssetBytes > 0 && fh.Size > quota.MaxAssetBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"code": CodeQuotaExceeded,
+			"msg":  "Asset exceeds the maximum file size",
+			"data": gin.H{"path": assetPath, "size": fh.Size, "quota": quota},
+		})
+		return
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read upload: " + err.Error()})
+		return
+	}
+	defer f.Close()
+
+	// 先计算哈希，再回到文件头写入存储
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read upload: " + err.Error()})
+		return
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+
+	// 校验客户端声明的哈希与大小，不一致时要求重新上传
+	expectedHash := strings.ToLower(strings.TrimSpace(c.PostForm("sha256")))
+	if expectedHash == "" {
+		expectedHash = strings.ToLower(strings.TrimSpace(c.GetHeader("X-Content-SHA256")))
+	}
+	expectedSize := strings.TrimSpace(c.PostForm("size"))
+	if (expectedHash != "" && expectedHash != hash) ||
+		(expectedSize != "" && expectedSize != strconv.FormatInt(size, 10)) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"code": CodeAssetChecksumMismatch,
+			"msg":  "Checksum mismatch, please re-upload",
+			"data": gin.H{"path": assetPath, "hash": hash, "size": size},
+		})
+		return
+	}
+
+	existing, err := models.FindAsset(share.ID, assetPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query asset: " + err.Error()})
+		return
+	}
+	if existing != nil && existing.Hash == hash && existing.Size == size {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": assetUploadResponse(c, existing, true)})
+		return
+	}
+
+	// 替换已有资源时先扣除旧文件大小，再判断总容量（含宽限）
+	if quota.MaxBytes > 0 {
+		usage, err := models.UserUsage(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+			return
+		}
+		used := usage.Bytes
+		if existing != nil {
+			used -= existing.Size
+		}
+		if used+size > models.GraceLimit(quota.MaxBytes) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"code": CodeQuotaExceeded,
+				"msg":  "Storage quota exceeded",
+				"data": gin.H{"path": assetPath, "size": size, "usage": usage, "quota": quota},
+			})
+			return
+		}
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read upload: " + err.Error()})
+		return
+	}
+
+	contentType := fh.Header.Get("Content-Type")
+	if ext := mime.TypeByExtension(path.Ext(assetPath)); ext != "" {
+		contentType = ext
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	key := "shares/" + share.ID + "/" + assetPath
+	if err := storage.Default.Put(c.Request.Context(), key, f, size, contentType); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to store asset: " + err.Error()})
+		return
+	}
+
+	asset := existing
+	if asset == nil {
+		asset = &models.Asset{ID: "ast_" + randHex(12), ShareID: share.ID, UserID: userID, Path: assetPath}
+	}
+	asset.StorageKey = key
+	asset.ContentType = contentType
+	asset.Size = size
+	asset.Hash = hash
+	if err := models.DB.Save(asset).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save asset: " + err.Error()})
+		return
+	}
+	imageopt.Schedule(asset)
+	notify.CheckQuota(userID, getBaseURL(c))
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": assetUploadResponse(c, asset, false)})
+}
+
+// assetUploadResponse 构造上传结果，返回服务端计算的哈希与大小供客户端核对
+func assetUploadResponse(c *gin.Context, asset *models.Asset, duplicate bool) gin.H {
+	return gin.H{
+		"id":        asset.ID,
+		"path":      asset.Path,
+		"url":       getBaseURL(c) + "/api/s/" + asset.ShareID + "/" + asset.Path,
+		"size":      asset.Size,
+		"hash":      asset.Hash,
+		"duplicate": duplicate,
+	}
+}
+
+// ListAssets 列出分享的资源文件
+func ListAssets(c *gin.Context) {
+	shareID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var assets []models.Asset
+	if err := models.DB.Where("share_id = ? AND user_id = ?", shareID, userID).Order("path").Find(&assets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list assets: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": assets}})
+}
+
+// ServeAsset 公开访问分享资源
+func ServeAsset(c *gin.Context) {
+	shareID := c.Param("id")
+	assetPath := "assets/" + strings.TrimPrefix(c.Param("path"), "/")
+	if !assetRefererAllowed(c) {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Hotlinking of share assets is not allowed"})
+		return
+	}
+
+	share, err := models.FindShare(shareID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	c.Set("contentOwner", share.UserID)
+	// 草稿的资源仍可访问，以便所有者预览；停用后一并下线（浏览次数用尽后的宽限时间内除外，供最后一位读者加载）
+	if share.Status == models.ShareStatusDisabled && !share.InViewGrace() {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Share is disabled"})
+		return
+	}
+	if share.IsExpired() {
+		c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Share has expired"})
+		return
+	}
+
+	asset, err := models.FindAsset(share.ID, assetPath)
+	if err != nil || asset == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Asset not found"})
+		return
+	}
+
+	// 需要签名的资源只能通过阅读页签发的签名地址访问，CDN 回源时原样转发查询参数
+	cacheControl := "public, max-age=86400"
+	versioned := len(asset.Hash) >= assetVersionLen && c.Query("v") == asset.Hash[:assetVersionLen]
+	if signedAssets(share) {
+		exp, ok := validAssetSignature(c, share.ID, asset.Path)
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Invalid or expired asset signature"})
+			return
+		}
+		cacheControl = "public, max-age=" + strconv.Itoa(int(time.Until(exp).Seconds()))
+	} else if versioned {
+		// 地址带有内容版本，内容变化后地址随之变化，可长期缓存
+		cacheControl = "public, max-age=31536000, immutable"
+	}
+
+	// 附件下载策略：下载请求与无法内联预览的附件按分享设置拒绝或要求再次输入密码
+	download := c.Query("download") == "1"
+	if download || !inlineViewable(asset.ContentType) {
+		switch share.DownloadPolicy() {
+		case models.AssetDownloadsInline:
+			c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Downloads are disabled for this share"})
+			return
+		case models.AssetDownloadsPassword:
+			password := sharePassword(c)
+			if password == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Password required for download"})
+				return
+			}
+			if bcrypt.CompareHashAndPassword([]byte(share.PasswordHash), []byte(password)) != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid password"})
+				return
+			}
+			// 带密码的请求不经共享缓存
+			cacheControl = "private, no-store"
+		}
+	}
+	if download {
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(asset.Path)}))
+	} else if share.DownloadPolicy() != models.AssetDownloadsAllow {
+		c.Header("Content-Disposition", "inline")
+	}
+
+	// 图片优化副本：按 Accept 选择，刚上传的图片副本可能仍在生成，暂不长期缓存
+	storageKey, size, contentType := asset.StorageKey, asset.Size, asset.ContentType
+	etag, modified := `"`+asset.Hash+`"`, asset.UpdatedAt
+	if imageopt.Enabled() && imageopt.Optimizable(asset) {
+		c.Header("Vary", "Accept")
+		if v := imageopt.Pick(asset, c.GetHeader("Accept")); v != nil {
+			storageKey, size, contentType = v.StorageKey, v.Size, v.ContentType
+			etag, modified = `"`+v.SourceHash+"-"+v.Format+`"`, v.CreatedAt
+		} else if time.Since(asset.UpdatedAt) < 10*time.Minute {
+			cacheControl = "public, max-age=60"
+		}
+	}
+	if asset.Hash == "" {
+		etag = ""
+	}
+	c.Header("Cache-Control", cacheControl)
+	if notModified(c, etag, modified) {
+		return
+	}
+
+	rc, _, err := storage.Default.Get(c.Request.Context(), storageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Asset not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read asset"})
+		return
+	}
+	defer rc.Close()
+
+	// 所有者本月读者流量超出配额时以占位图代替，避免单篇爆款笔记耗尽小主机的流量
+	if models.BandwidthExceeded(share.UserID) {
+		c.Header("Cache-Control", "no-store")
+		c.Data(http.StatusOK, "image/svg+xml", bandwidthPlaceholder)
+		return
+	}
+	c.Set("contentShare", share.ID)
+	notify.CheckQuota(share.UserID, getBaseURL(c))
+	c.DataFromReader(http.StatusOK, size, contentType, rc, nil)
+}
+
+// inlineViewable 浏览器能否内联展示该类型的资源（图片、音视频、PDF 与纯文本）
+func inlineViewable(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "image/"), strings.HasPrefix(mediaType, "audio/"), strings.HasPrefix(mediaType, "video/"):
+		return true
+	}
+	return mediaType == "application/pdf" || mediaType == "text/plain"
+}
+
+// bandwidthPlaceholder 流量超出配额时代替资源返回的占位图
+var bandwidthPlaceholder = []byte(`<svg xmlns="http://www.w3.org/2000/svg" width="320" height="80" viewBox="0 0 320 80">` +
+	`<rect width="320" height="80" fill="#f5f5f5" stroke="#d9d9d9"/>` +
+	`<text x="160" y="45" font-family="sans-serif" font-size="14" fill="#8c8c8c" text-anchor="middle">本月流量已用尽，资源暂不可用</text></svg>`)
+
+// normalizeAssetPath 规范化资源引用路径，统一为 assets/ 前缀
+func normalizeAssetPath(p, filename string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		p = "assets/" + path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	}
+	p = strings.TrimPrefix(strings.ReplaceAll(p, "\\", "/"), "/")
+	if !strings.HasPrefix(p, "assets/") {
+		p = "assets/" + p
+	}
+	return storage.CleanKey(p)
+}
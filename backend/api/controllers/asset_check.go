@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// assetReferencePattern 正文中对分享资源的引用：Markdown 图片与链接、HTML src/href 中的 assets/ 路径，
+	// 以及指向 /api/s/<id>/assets/ 的地址
+	assetReferencePattern = regexp.MustCompile(`(?:https?://[^\s"'()<>]+)?/api/s/([0-9A-Za-z_-]+)/(assets/[^\s"'()<>?#]+)|(?:\]\(|(?:src|href)=["'])/?(assets/[^\s"'()<>?#]+)`)
+	// codeFencePattern 围栏代码块，其中的示例路径不是资源引用
+	codeFencePattern = regexp.MustCompile("(?ms)^\\s*(```|~~~).*?^\\s*(```|~~~)\\s*$")
+)
+
+// MissingAsset 正文引用但尚未上传的资源
+type MissingAsset struct {
+	Path       string `json:"path"`       // 引用路径，如 assets/image-20240101.png
+	References int    `json:"references"` // 正文中的引用次数
+}
+
+// AssetCheck 分享正文引用的资源与缺失情况
+type AssetCheck struct {
+	Referenced int            `json:"referenced"` // 引用的不同资源数
+	Missing    []MissingAsset `json:"missing"`
+}
+
+// assetReferences 按首次出现顺序返回正文（与闪卡）中引用的本分享资源路径及次数
+func assetReferences(share *models.Share) ([]string, map[string]int) {
+	var paths []string
+	counts := map[string]int{}
+	for _, text := range []string{share.Content, share.Flashcards} {
+		text = codeFencePattern.ReplaceAllString(text, "")
+		for _, m := range assetReferencePattern.FindAllStringSubmatch(text, -1) {
+			p := m[3]
+			if p == "" {
+				if m[1] != share.ID {
+					continue
+				}
+				p = m[2]
+			}
+			if decoded, err := url.PathUnescape(p); err == nil {
+				p = decoded
+			}
+			if counts[p] == 0 {
+				paths = append(paths, p)
+			}
+			counts[p]++
+		}
+	}
+	return paths, counts
+}
+
+// checkAssets 找出正文引用但分享没有对应上传资源的路径，读者打开时这些图片与附件会失效
+func checkAssets(share *models.Share) (AssetCheck, error) {
+	paths, counts := assetReferences(share)
+	check := AssetCheck{Referenced: len(paths), Missing: []MissingAsset{}}
+	if len(paths) == 0 {
+		return check, nil
+	}
+	var uploaded []string
+	if err := models.DB.Model(&models.Asset{}).Where("share_id = ? AND path IN ?", share.ID, paths).Pluck("path", &uploaded).Error; err != nil {
+		return check, err
+	}
+	exists := make(map[string]bool, len(uploaded))
+	for _, p := range uploaded {
+		exists[p] = true
+	}
+	for _, p := range paths {
+		if !exists[p] {
+			check.Missing = append(check.Missing, MissingAsset{Path: p, References: counts[p]})
+		}
+	}
+	return check, nil
+}
+
+// CheckShareAssets 列出分享正文引用但尚未上传的资源，插件上传资源后可再次核对
+func CheckShareAssets(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	check, err := checkAssets(share)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to check assets: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": check})
+}
@@ -1,31 +1,59 @@
 package controllers
 
 import (
-	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
-	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ZeroHawkeye/siyuan-share-api/challenge"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
 	"github.com/ZeroHawkeye/siyuan-share-api/models"
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type RegisterRequest struct {
-	Username string `json:"username" binding:"required,min=3,max=100"`
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6,max=200"`
+	Username   string `json:"username" binding:"required,min=3,max=100"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=6,max=200"`
+	InviteCode string `json:"inviteCode"` // 注册方式为 invite 时必填
 }
 
-// Register 用户注册
+// RegistrationInfo 当前注册方式，供注册页决定是否显示邀请码输入框
+func RegistrationInfo(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"mode": config.Get().Auth.Registration}})
+}
+
+// Register 用户注册，按 auth.registration 开放、凭邀请码或关闭
 func Register(c *gin.Context) {
+	mode := config.Get().Auth.Registration
+	if mode == "closed" {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Registration is closed"})
+		return
+	}
 	var req RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
 		return
 	}
+	inviteCode := normalizeInviteCode(req.InviteCode)
+	if mode == "invite" && inviteCode == "" {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "An invite code is required to register"})
+		return
+	}
+	if !passChallenge(c, challenge.ActionRegister) {
+		return
+	}
 
 	// 查重
 	var count int64
@@ -50,17 +78,46 @@ func Register(c *gin.Context) {
 		IsActive:     true,
 	}
 
-	if err := models.DB.Create(user).Error; err != nil {
+	// 邀请码与用户在同一事务中计数与创建，创建失败时不消耗邀请码
+	errInvalidInvite := errors.New("invalid invite")
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if mode == "invite" {
+			ok, err := models.RedeemInvite(tx, inviteCode)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errInvalidInvite
+			}
+		}
+		return tx.Create(user).Error
+	})
+	if errors.Is(err, errInvalidInvite) {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Invalid or expired invite code"})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create user: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+	// 配置了 SMTP 时发送邮箱验证邮件，发送失败不影响注册，可稍后重新发送
+	verificationSent := false
+	if mailer.Enabled() {
+		if err := sendVerificationEmail(c, user); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", user.ID, err)
+		} else {
+			verificationSent = true
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"verificationSent": verificationSent}})
 }
 
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	OTP      string `json:"otp"` // 启用两步验证后必填：验证码或恢复码
 }
 
 // Login 用户登录，返回会话 JWT
@@ -71,33 +128,74 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// 同一 IP 失败过多时直接拒绝，不再校验密码
+	if wait, blocked := ipLoginBlocked(c.ClientIP()); blocked {
+		c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many failed login attempts, please try again later"})
+		return
+	}
+	// 同一 IP 失败次数达到 challenge.after_failures 后要求人机验证；只按 IP 判断，避免泄露哪些账号存在
+	if failures, _ := ipFailures.current(c.ClientIP()); !passChallengeAfterFailures(c, challenge.ActionLogin, failures) {
+		return
+	}
+
 	var user models.User
 	if err := models.DB.Where("username = ?", req.Username).First(&user).Error; err != nil {
+		loginFailed(c, nil, "unknown_user")
 		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid credentials"})
 		return
 	}
+	// 锁定期间不校验密码，避免继续猜测
+	if accountLocked(&user) {
+		respondLocked(c, &user)
+		return
+	}
 	if user.PasswordHash == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Password not set"})
 		return
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		loginFailed(c, &user, "bad_password")
+		if accountLocked(&user) {
+			respondLocked(c, &user)
+			return
+		}
 		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid credentials"})
 		return
 	}
+	if !user.IsActive {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Account disabled"})
+		return
+	}
+	if !user.EmailVerified && emailVerificationRequired() {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Email not verified"})
+		return
+	}
 
-	// 生成 JWT
-	secret := os.Getenv("SESSION_SECRET")
-	if secret == "" {
-		secret = "dev-secret"
+	// 两步验证：密码正确后再校验第二因素，通过后才签发会话 JWT
+	if user.TOTPEnabled {
+		if strings.TrimSpace(req.OTP) == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"code": CodeTwoFactorRequired, "msg": "Two-factor code required"})
+			return
+		}
+		if !verifySecondFactor(&user, req.OTP) {
+			loginFailed(c, &user, "bad_otp")
+			if accountLocked(&user) {
+				respondLocked(c, &user)
+				return
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{"code": CodeTwoFactorRequired, "msg": "Invalid two-factor code"})
+			return
+		}
 	}
-	expires := time.Now().Add(24 * time.Hour)
-	claims := jwt.MapClaims{
-		"sub": user.ID,
-		"exp": expires.Unix(),
-		"iat": time.Now().Unix(),
+	loginSucceeded(&user)
+
+	if err := runAuthHooks(c, &user, "password"); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Login denied: " + err.Error()})
+		return
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	s, err := token.SignedString([]byte(secret))
+
+	s, err := issueSessionToken(c, &user, "password")
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to sign token"})
 		return
@@ -109,6 +207,37 @@ func Login(c *gin.Context) {
 	}})
 }
 
+// sessionTTL 会话有效期
+const sessionTTL = 24 * time.Hour
+
+// issueSessionToken 创建会话记录并签发会话 JWT（密码登录与第三方登录共用），jti 对应 sessions 表主键以便撤销
+func issueSessionToken(c *gin.Context, user *models.User, method string) (string, error) {
+	now := time.Now()
+	session := &models.Session{
+		ID:         "ses_" + randHex(16),
+		UserID:     user.ID,
+		Method:     method,
+		UserAgent:  truncateRunes(c.Request.UserAgent(), 500),
+		IP:         c.ClientIP(),
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(sessionTTL),
+	}
+	if err := models.DB.Create(session).Error; err != nil {
+		return "", err
+	}
+	// 顺带清理该用户已过期的会话记录
+	models.DB.Where("user_id = ? AND expires_at < ?", user.ID, now).Delete(&models.Session{})
+
+	claims := jwt.MapClaims{
+		"sub": user.ID,
+		"jti": session.ID,
+		"exp": session.ExpiresAt.Unix(),
+		"iat": now.Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(config.Get().SessionSecret())
+}
+
 // Me 返回当前认证用户信息
 func Me(c *gin.Context) {
 	userID, _ := c.Get("userID")
@@ -119,11 +248,63 @@ func Me(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
 		"id": user.ID, "username": user.Username, "email": user.Email, "isActive": user.IsActive, "createdAt": user.CreatedAt,
+		"feedEnabled": user.FeedEnabled, "emailVerified": user.EmailVerified, "totpEnabled": user.TOTPEnabled,
+		"locale": user.Locale, "isAdmin": config.Get().IsAdmin(user.Username), "deletionScheduledAt": user.DeletionScheduledAt,
+		"donation": user.Donation(),
 	}})
 }
 
 func randHex(n int) string {
 	b := make([]byte, n)
-	_, _ = rand.Read(b)
+	idgen.Read(b)
 	return hex.EncodeToString(b)
 }
+
+// UpdateSettingsRequest 用户个人设置
+type UpdateSettingsRequest struct {
+	FeedEnabled *bool                 `json:"feedEnabled"`
+	Locale      *string               `json:"locale"`   // zh-CN / en，空字符串表示跟随浏览器语言
+	Donation    *models.DonationLinks `json:"donation"` // 公开页面展示的赞助链接，各项均为空时不展示
+}
+
+// UpdateSettings 更新当前用户的个人设置
+func UpdateSettings(c *gin.Context) {
+	userID := c.GetString("userID")
+	var req UpdateSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	updates := map[string]interface{}{}
+	if req.FeedEnabled != nil {
+		updates["feed_enabled"] = *req.FeedEnabled
+	}
+	if req.Locale != nil {
+		if *req.Locale != "" && i18n.Match(*req.Locale) != *req.Locale {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Unsupported locale"})
+			return
+		}
+		updates["locale"] = *req.Locale
+	}
+	if req.Donation != nil {
+		if msg := validDonationLinks(req.Donation); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": msg})
+			return
+		}
+		links := ""
+		if !req.Donation.Empty() {
+			b, _ := json.Marshal(req.Donation)
+			links = string(b)
+		}
+		updates["donation_links"] = links
+	}
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "No fields to update"})
+		return
+	}
+	if err := models.DB.Model(&models.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update settings: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/backup"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateBackup 管理员下载实例备份（tar.gz）：在线生成的数据库快照与其引用的存储对象，?assets=0 时只包含数据库。
+// 快照生成后才开始写出响应，传输途中出错只能中断下载
+func CreateBackup(c *gin.Context) {
+	snap, err := backup.Take(c.Request.Context(), c.Query("assets") != "0")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create backup: " + err.Error()})
+		return
+	}
+	defer snap.Close()
+
+	c.Header("Content-Type", "application/gzip")
+	c.Header("Content-Disposition", `attachment; filename="`+backup.FileName(time.Now())+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	if _, err := snap.WriteTo(c.Request.Context(), c.Writer); err != nil {
+		log.Printf("Backup download failed: %v", err)
+		c.Abort()
+	}
+}
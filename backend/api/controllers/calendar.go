@@ -0,0 +1,222 @@
+package controllers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// calendarEvent 从分享内容中解析出的日程
+type calendarEvent struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+	AllDay  bool
+	URL     string
+}
+
+// 支持 2024-05-01、2024/5/1、2024.05.01、2024年5月1日；同一行中的 14:00 或 14:00-15:30 作为时间（段）
+var (
+	calendarDatePattern = regexp.MustCompile(`(\d{4})[-/.年](\d{1,2})[-/.月](\d{1,2})日?`)
+	calendarTimePattern = regexp.MustCompile(`(?:^|[^\d:])(\d{1,2}):(\d{2})(?:\s*[-~–—至到]\s*(\d{1,2}):(\d{2}))?`)
+	calendarListMarker  = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+)
+
+// extractCalendarEvents 解析带日期的标题、列表项与段落：每行取第一个日期作为日程时间，其余文本作为标题
+func extractCalendarEvents(share *models.Share, baseURL string) []calendarEvent {
+	var events []calendarEvent
+	inFence := false
+	for _, line := range strings.Split(share.Content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || trimmed == "" {
+			continue
+		}
+		m := calendarDatePattern.FindStringSubmatch(trimmed)
+		if m == nil {
+			continue
+		}
+		year, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		// 日程使用浮动时间（不带时区），由日历客户端按本地时区展示
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if month < 1 || month > 12 || date.Day() != day {
+			continue
+		}
+		text := strings.Replace(trimmed, m[0], " ", 1)
+
+		ev := calendarEvent{Start: date, End: date.AddDate(0, 0, 1), AllDay: true}
+		if idx := calendarTimePattern.FindStringSubmatchIndex(text); idx != nil {
+			t := make([]string, len(idx)/2)
+			for i := range t {
+				if idx[2*i] >= 0 {
+					t[i] = text[idx[2*i]:idx[2*i+1]]
+				}
+			}
+			if start, end, ok := parseCalendarTime(date, t); ok {
+				ev = calendarEvent{Start: start, End: end}
+				// 只移除时间本身，保留匹配时带上的前一个字符
+				text = text[:idx[2]] + " " + text[idx[1]:]
+			}
+		}
+
+		text = calendarListMarker.ReplaceAllString(text, "")
+		summary := strings.Trim(plainSummary(text, 120), " :：-–—|")
+		if summary == "" {
+			summary = share.DocTitle
+		}
+		ev.Summary = summary
+		ev.URL = baseURL + "/s/" + share.ID
+
+		// UID 由分享 ID、日期与标题生成，重新发布时同一日程保持不变
+		sum := sha1.Sum([]byte(share.ID + "|" + m[0] + "|" + summary))
+		ev.UID = share.ID + "-" + hex.EncodeToString(sum[:8]) + "@siyuan-share"
+		events = append(events, ev)
+	}
+	return events
+}
+
+// parseCalendarTime 解析时间（段），未给出结束时间时默认持续 1 小时
+func parseCalendarTime(date time.Time, t []string) (time.Time, time.Time, bool) {
+	clock := func(h, m string) (time.Duration, bool) {
+		hour, _ := strconv.Atoi(h)
+		minute, _ := strconv.Atoi(m)
+		if hour > 23 || minute > 59 {
+			return 0, false
+		}
+		return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+	}
+	offset, ok := clock(t[1], t[2])
+	if !ok {
+		return time.Time{}, time.Time{}, false
+	}
+	start := date.Add(offset)
+	end := start.Add(time.Hour)
+	if t[3] != "" {
+		if e, ok := clock(t[3], t[4]); ok && date.Add(e).After(start) {
+			end = date.Add(e)
+		}
+	}
+	return start, end, true
+}
+
+// ShareCalendar 输出单个分享中日程的 ICS 订阅
+func ShareCalendar(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	writeCalendar(c, share.DocTitle, extractCalendarEvents(share, getBaseURL(c)))
+}
+
+// UserCalendar 输出用户所有公开收录分享中日程的 ICS 订阅（与 RSS 订阅源使用同一开关），生成结果缓存到有分享变化为止
+func UserCalendar(c *gin.Context) {
+	var user models.User
+	if err := models.DB.Where("username = ? AND is_active = ?", c.Param("username"), true).First(&user).Error; err != nil || !user.FeedEnabled {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Calendar not found"})
+		return
+	}
+
+	baseURL := getBaseURL(c)
+	key := "calendar:" + user.Username + ":" + baseURL
+	if body, ok := models.LoadListing(key); ok {
+		writeICS(c, body)
+		return
+	}
+
+	builtAt := time.Now()
+	var shares []models.Share
+	if err := models.DB.Where("user_id = ? AND listed = ? AND is_public = ? AND status = ? AND require_password = ? AND restricted = ? AND access_schedule = ? AND terms = ? AND paywall = ? AND teaser = ? AND max_views = ? AND expire_at > ?",
+		user.ID, true, true, models.ShareStatusPublished, false, false, "", "", false, false, 0, time.Now()).
+		Order("created_at DESC").Limit(50).Find(&shares).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load calendar: " + err.Error()})
+		return
+	}
+
+	var events []calendarEvent
+	for i := range shares {
+		events = append(events, extractCalendarEvents(&shares[i], baseURL)...)
+	}
+	body := renderCalendar(user.Username+" 的日程", events)
+	models.SaveListing(key, builtAt, earliestExpiry(shares), body)
+	writeICS(c, body)
+}
+
+func writeCalendar(c *gin.Context, name string, events []calendarEvent) {
+	writeICS(c, renderCalendar(name, events))
+}
+
+func writeICS(c *gin.Context, body []byte) {
+	c.Header("Cache-Control", "public, max-age=600")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", body)
+}
+
+func renderCalendar(name string, events []calendarEvent) []byte {
+	var b strings.Builder
+	line := func(s string) {
+		b.WriteString(foldICSLine(s))
+		b.WriteString("\r\n")
+	}
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//SiYuan Share//Calendar//ZH")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICSText(name))
+	for _, ev := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + ev.UID)
+		line("DTSTAMP:" + stamp)
+		if ev.AllDay {
+			line("DTSTART;VALUE=DATE:" + ev.Start.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + ev.End.Format("20060102"))
+		} else {
+			line("DTSTART:" + ev.Start.Format("20060102T150405"))
+			line("DTEND:" + ev.End.Format("20060102T150405"))
+		}
+		line("SUMMARY:" + escapeICSText(ev.Summary))
+		line("URL:" + ev.URL)
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICSText(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// foldICSLine 按 RFC 5545 将超过 75 字节的行折叠，不拆分 UTF-8 字符
+func foldICSLine(s string) string {
+	if len(s) <= 75 {
+		return s
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// purposeCDNAsset 私密分享资源签名地址的密钥用途
+const purposeCDNAsset = "cdn_asset"
+
+// assetVersionLen 资源地址中内容版本（sha256 前缀）的长度
+const assetVersionLen = 12
+
+// assetRefPattern 正文中引用分享资源的地址：本服务的绝对地址、/api/s/<id>/assets/ 或相对的 assets/ 路径
+var assetRefPattern = regexp.MustCompile(`(?:https?://[^\s"'()<>]+)?/api/s/([0-9A-Za-z_-]+)/(assets/[^\s"'()<>?#]+)|(\]\(|src=["'])(assets/[^\s"'()<>?#]+)`)
+
+// privateAssets 分享资源是否需要签名地址：需要密码、仅访问名单可见、设置了开放时间、使用条款、付费阅读、试读模式或浏览次数上限的分享
+func privateAssets(share *models.Share) bool {
+	return share.RequirePassword || share.Restricted || share.Scheduled() || share.Terms != "" || share.Paywall || share.Teaser || share.MaxViews > 0
+}
+
+// signedAssets 资源请求是否须带签名：开启 assets.signed_urls 时所有分享，启用 CDN 时私密分享
+func signedAssets(share *models.Share) bool {
+	return config.Get().Assets.SignedURLs || (config.Get().CDN.Enabled() && privateAssets(share))
+}
+
+// versionedAssetURLs 阅读页是否改写资源地址（带内容版本，按需签名）：启用 CDN、资源签名或图片优化时，
+// 带版本的地址可长期缓存
+func versionedAssetURLs() bool {
+	cfg := config.Get()
+	return cfg.CDN.Enabled() || cfg.Assets.SignedURLs || cfg.Images.Enabled()
+}
+
+// assetSignature 资源签名，绑定分享、资源路径与过期时间
+func assetSignature(shareID, assetPath string, exp int64) string {
+	return signAsset(purposeKey(purposeCDNAsset), shareID, assetPath, exp)
+}
+
+func signAsset(key []byte, shareID, assetPath string, exp int64) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(shareID + "/" + assetPath + "|" + strconv.FormatInt(exp, 10)))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
+
+// assetURL 生成阅读页引用的资源地址：启用 CDN 时指向 cdn.asset_base_url，附带内容版本 v 使同一内容的地址稳定、
+// 内容变化后地址随之变化；需要签名时追加过期时间与签名，过期时间按有效期对齐，同一时段内所有读者拿到相同地址便于缓存
+func assetURL(c *gin.Context, share *models.Share, assetPath, hash string) string {
+	return assetURLWithBase(getBaseURL(c), share, assetPath, hash)
+}
+
+// assetURLWithBase 同 assetURL，未启用 CDN 时以 baseURL 为地址前缀
+func assetURLWithBase(baseURL string, share *models.Share, assetPath, hash string) string {
+	cfg := config.Get().CDN
+	if !versionedAssetURLs() {
+		return baseURL + "/api/s/" + share.ID + "/" + assetPath
+	}
+	base := baseURL
+	if cfg.Enabled() {
+		base = cfg.AssetBaseURL
+	}
+	u := base + "/api/s/" + share.ID + "/" + assetPath
+	if len(hash) >= assetVersionLen {
+		u += "?v=" + hash[:assetVersionLen]
+	} else {
+		u += "?v=0"
+	}
+	if signedAssets(share) {
+		ttl := int64(cfg.SignedURLTTL.Std().Seconds())
+		exp := (time.Now().Unix()/ttl + 2) * ttl
+		u += "&exp=" + strconv.FormatInt(exp, 10) + "&sig=" + assetSignature(share.ID, assetPath, exp)
+	}
+	return u
+}
+
+// validAssetSignature 校验资源请求的过期时间与签名，返回过期时间
+func validAssetSignature(c *gin.Context, shareID, assetPath string) (time.Time, bool) {
+	exp, err := strconv.ParseInt(c.Query("exp"), 10, 64)
+	if err != nil || time.Now().Unix() >= exp {
+		return time.Time{}, false
+	}
+	// 轮换会话密钥前签发的地址在过期前仍然有效
+	for _, key := range purposeKeys(purposeCDNAsset) {
+		if hmac.Equal([]byte(c.Query("sig")), []byte(signAsset(key, shareID, assetPath, exp))) {
+			return time.Unix(exp, 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// rewriteAssetURLs 将正文中引用本分享资源的地址替换为 assetURL（见 versionedAssetURLs），未登记的资源保持原样
+func rewriteAssetURLs(c *gin.Context, share *models.Share, content string) string {
+	if !versionedAssetURLs() || !strings.Contains(content, "assets/") {
+		return content
+	}
+	var assets []models.Asset
+	if err := models.DB.Select("path", "hash").Where("share_id = ?", share.ID).Find(&assets).Error; err != nil || len(assets) == 0 {
+		return content
+	}
+	hashes := make(map[string]string, len(assets))
+	for _, a := range assets {
+		hashes[a.Path] = a.Hash
+	}
+	return assetRefPattern.ReplaceAllStringFunc(content, func(m string) string {
+		sub := assetRefPattern.FindStringSubmatch(m)
+		prefix, shareID, assetPath := sub[3], sub[1], sub[2]
+		if prefix != "" {
+			shareID, assetPath = share.ID, sub[4]
+		}
+		hash, ok := hashes[assetPath]
+		if shareID != share.ID || !ok {
+			return m
+		}
+		return prefix + assetURL(c, share, assetPath, hash)
+	})
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/challenge"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/gin-gonic/gin"
+)
+
+// sharePasswordFailures 各 IP 对各分享输错访问密码的次数，键为 IP|分享 ID
+var sharePasswordFailures = &failureCounter{m: map[string]*ipFailure{}}
+
+// GetChallengeInfo 人机验证的服务商、站点密钥与要求验证的操作，供前端渲染验证组件；未开启时 provider 为空
+func GetChallengeInfo(c *gin.Context) {
+	cfg := config.Get().Challenge
+	actions := cfg.Actions
+	if cfg.Provider == "" {
+		actions = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"provider":      cfg.Provider,
+		"siteKey":       cfg.SiteKey,
+		"actions":       actions,
+		"afterFailures": cfg.AfterFailures,
+	}})
+}
+
+// passChallenge 操作 action 开启人机验证时核验 X-Challenge-Token 请求头；未通过时已写入响应并返回 false
+func passChallenge(c *gin.Context, action string) bool {
+	if !challenge.Required(action) {
+		return true
+	}
+	err := challenge.Verify(c.Request.Context(), c.GetHeader(challenge.Header), c.ClientIP())
+	if err == nil {
+		return true
+	}
+	provider, siteKey := challenge.SiteInfo()
+	data := gin.H{"provider": provider, "siteKey": siteKey, "action": action}
+	switch {
+	case errors.Is(err, challenge.ErrMissing):
+		c.JSON(http.StatusForbidden, gin.H{"code": CodeChallengeRequired, "msg": "Challenge required", "data": data})
+	case errors.Is(err, challenge.ErrFailed):
+		auditLog(c, "challenge_failed", "action", action, "error", err.Error())
+		c.JSON(http.StatusForbidden, gin.H{"code": CodeChallengeRequired, "msg": "Challenge verification failed", "data": data})
+	default:
+		log.Printf("Challenge verification error (%s): %v", action, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Challenge verification unavailable, please try again later"})
+	}
+	return false
+}
+
+// passChallengeAfterFailures 登录与分享密码等可被猜测的操作：失败次数达到 challenge.after_failures 后才要求人机验证
+func passChallengeAfterFailures(c *gin.Context, action string, failures int) bool {
+	if failures < config.Get().Challenge.AfterFailures {
+		return true
+	}
+	return passChallenge(c, action)
+}
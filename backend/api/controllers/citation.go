@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/citation"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// normalizeCitations 校验发布时附带的 CSL-JSON 数组并重新序列化；ok=false 表示未传入（保留原数据）
+func normalizeCitations(raw json.RawMessage) (string, bool, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", false, nil
+	}
+	items, err := citation.Parse(raw)
+	if err != nil {
+		return "", true, err
+	}
+	if len(items) == 0 {
+		return "", true, nil
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return "", true, err
+	}
+	return string(data), true, nil
+}
+
+// shareCitations 解析分享保存的文献数据
+func shareCitations(share *models.Share) []citation.Item {
+	if share.Citations == "" {
+		return nil
+	}
+	items, err := citation.Parse([]byte(share.Citations))
+	if err != nil {
+		log.Printf("invalid citations on share %s: %v", share.ID, err)
+		return nil
+	}
+	return items
+}
+
+// renderCitations 将正文中的引用替换为作者-年份标注，并生成参考文献列表
+func renderCitations(share *models.Share, content string) (string, []citation.Entry) {
+	items := shareCitations(share)
+	if len(items) == 0 {
+		return content, []citation.Entry{}
+	}
+	return citation.Render(content, items), citation.Bibliography(share.Content, items)
+}
+
+// ShareCitations 以 CSL-JSON 输出分享中引用的文献；?all=true 时输出发布时附带的全部条目
+func ShareCitations(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	items := shareCitations(share)
+	if c.Query("all") != "true" {
+		items = citation.Cited(share.Content, items)
+	}
+	if items == nil {
+		items = []citation.Item{}
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to encode citations: " + err.Error()})
+		return
+	}
+	if c.Query("download") == "true" {
+		c.Header("Content-Disposition", `attachment; filename="`+share.ID+`.json"`)
+	}
+	c.Data(http.StatusOK, "application/vnd.citationstyles.csl+json; charset=utf-8", data)
+}
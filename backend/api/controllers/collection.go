@@ -0,0 +1,361 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/qa"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 合集数量与单个合集的分享数量上限
+const (
+	maxCollections     = 100
+	maxCollectionItems = 200
+	maxCollectionDesc  = 2000
+)
+
+// collectionSlugPattern 合集地址 /c/<slug>：小写字母、数字与连字符
+var collectionSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,63}$`)
+
+// CollectionRequest 创建或更新合集，ShareIDs 的顺序即合集首页中的排列顺序
+type CollectionRequest struct {
+	Slug        string   `json:"slug"` // 为空时创建合集由标题生成，更新合集保持原地址
+	Title       string   `json:"title" binding:"required,max=200"`
+	Description string   `json:"description"`
+	CoverImage  string   `json:"coverImage"`
+	ShareIDs    []string `json:"shareIds"`
+}
+
+// collectionView 合集及其分享 ID（管理接口）
+type collectionView struct {
+	models.Collection
+	ShareIDs []string `json:"shareIds"`
+	URL      string   `json:"url"`
+}
+
+// normalizeCollection 校验合集地址、描述与封面，并去重、校验分享均属于当前用户
+func normalizeCollection(userID string, req *CollectionRequest) (*models.Collection, []string, error) {
+	col := &models.Collection{
+		UserID:      userID,
+		Slug:        strings.ToLower(strings.TrimSpace(req.Slug)),
+		Title:       slug.Title(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		CoverImage:  strings.TrimSpace(req.CoverImage),
+	}
+	if col.Slug != "" && !collectionSlugPattern.MatchString(col.Slug) {
+		return nil, nil, errors.New("invalid collection slug")
+	}
+	if col.Title == "" {
+		return nil, nil, errors.New("title is required")
+	}
+	if len([]rune(col.Description)) > maxCollectionDesc {
+		return nil, nil, errors.New("description is too long")
+	}
+	if cover := col.CoverImage; cover != "" {
+		switch {
+		case len(cover) > 2048:
+			return nil, nil, errors.New("cover image URL is too long")
+		case strings.HasPrefix(cover, "/") && !strings.HasPrefix(cover, "//"):
+		case strings.HasPrefix(cover, "http://") || strings.HasPrefix(cover, "https://"):
+			if u, err := url.Parse(cover); err != nil || u.Host == "" {
+				return nil, nil, errors.New("cover image must be a path starting with / or an http(s) URL")
+			}
+		default:
+			return nil, nil, errors.New("cover image must be a path starting with / or an http(s) URL")
+		}
+	}
+
+	ids := make([]string, 0, len(req.ShareIDs))
+	seen := map[string]bool{}
+	for _, id := range req.ShareIDs {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxCollectionItems {
+		return nil, nil, errors.New("too many shares in collection")
+	}
+	if len(ids) > 0 {
+		var owned []string
+		if err := models.DB.Model(&models.Share{}).Where("id IN ? AND user_id = ?", ids, userID).Pluck("id", &owned).Error; err != nil {
+			return nil, nil, err
+		}
+		mine := make(map[string]bool, len(owned))
+		for _, id := range owned {
+			mine[id] = true
+		}
+		for _, id := range ids {
+			if !mine[id] {
+				return nil, nil, errors.New("share not found: " + id)
+			}
+		}
+	}
+	return col, ids, nil
+}
+
+// slugTaken 合集地址是否已被其他合集使用
+func slugTaken(slug, exceptID string) bool {
+	var count int64
+	models.DB.Model(&models.Collection{}).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count)
+	return count > 0
+}
+
+// titleSlug 由合集标题生成未被占用的地址：中文等标题使用内容哈希，重复时依次追加 -2、-3
+func titleSlug(title string) string {
+	base := slug.Make(title, 56)
+	candidate := base
+	for i := 2; slugTaken(candidate, ""); i++ {
+		if i > 20 {
+			return base + "-" + randHex(3)
+		}
+		candidate = base + "-" + strconv.Itoa(i)
+	}
+	return candidate
+}
+
+// ListCollections 列出当前用户的合集
+func ListCollections(c *gin.Context) {
+	var cols []models.Collection
+	if err := models.DB.Where("user_id = ?", c.GetString("userID")).Order("created_at DESC").Find(&cols).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list collections: " + err.Error()})
+		return
+	}
+	baseURL := getBaseURL(c)
+	items := make([]collectionView, 0, len(cols))
+	for _, col := range cols {
+		ids, err := models.CollectionShareIDs(col.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list collections: " + err.Error()})
+			return
+		}
+		items = append(items, collectionView{Collection: col, ShareIDs: ids, URL: baseURL + "/c/" + col.Slug})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// CreateCollection 创建合集
+func CreateCollection(c *gin.Context) {
+	userID := c.GetString("userID")
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	col, ids, err := normalizeCollection(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	var count int64
+	models.DB.Model(&models.Collection{}).Where("user_id = ?", userID).Count(&count)
+	if count >= maxCollections {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Too many collections"})
+		return
+	}
+	custom := col.Slug != ""
+	if !custom {
+		col.Slug = titleSlug(col.Title)
+	}
+	col.ID = "col_" + randHex(8)
+	// 地址是否被占用以唯一索引为准：并发创建时由标题生成的地址冲突则重新生成，指定的地址被占用时返回 409
+	err = models.CreateUnique(func() error {
+		return models.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(col).Error; err != nil {
+				return err
+			}
+			return models.SetCollectionItems(tx, col.ID, ids)
+		})
+	}, func(int) bool {
+		if custom && slugTaken(col.Slug, "") {
+			return false
+		}
+		col.ID = "col_" + randHex(8)
+		if !custom {
+			col.Slug = titleSlug(col.Title)
+		}
+		return true
+	}, "collections.id", "collections.slug")
+	if errors.Is(err, models.ErrTaken) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Collection slug already taken"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save collection: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": collectionView{Collection: *col, ShareIDs: ids, URL: getBaseURL(c) + "/c/" + col.Slug}})
+}
+
+// UpdateCollection 更新合集信息与分享列表（整体替换）
+func UpdateCollection(c *gin.Context) {
+	userID := c.GetString("userID")
+	existing, err := models.FindUserCollection(userID, c.Param("id"))
+	if err != nil || existing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Collection not found"})
+		return
+	}
+	var req CollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	col, ids, err := normalizeCollection(userID, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if col.Slug == "" {
+		col.Slug = existing.Slug
+	}
+	col.ID = existing.ID
+	col.CreatedAt = existing.CreatedAt
+	err = models.CreateUnique(func() error {
+		return models.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(col).Error; err != nil {
+				return err
+			}
+			return models.SetCollectionItems(tx, col.ID, ids)
+		})
+	}, nil, "collections.slug")
+	if errors.Is(err, models.ErrTaken) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Collection slug already taken"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save collection: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": collectionView{Collection: *col, ShareIDs: ids, URL: getBaseURL(c) + "/c/" + col.Slug}})
+}
+
+// DeleteCollection 删除合集（合集中的分享不受影响）
+func DeleteCollection(c *gin.Context) {
+	col, err := models.FindUserCollection(c.GetString("userID"), c.Param("id"))
+	if err != nil || col == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Collection not found"})
+		return
+	}
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", col.ID).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(col).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete collection: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// collectionEntry 合集首页中的一篇分享
+type collectionEntry struct {
+	ID        string    `json:"id"`
+	DocTitle  string    `json:"docTitle"`
+	Summary   string    `json:"summary,omitempty"`
+	URL       string    `json:"url"`
+	Locked    bool      `json:"locked"` // 需要密码或仅限名单访问，不展示摘要
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// publicCollection 加载合集及其中可供读者访问的分享（跳过草稿、停用、过期与已删除的分享），按合集顺序排列
+func publicCollection(slug string) (*models.Collection, []models.Share, error) {
+	var col models.Collection
+	if err := models.DB.Where("slug = ?", strings.ToLower(slug)).First(&col).Error; err != nil {
+		return nil, nil, err
+	}
+	ids, err := models.CollectionShareIDs(col.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ids) == 0 {
+		return &col, nil, nil
+	}
+	var found []models.Share
+	if err := models.DB.Where("id IN ? AND user_id = ?", ids, col.UserID).Find(&found).Error; err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[string]*models.Share, len(found))
+	for i := range found {
+		byID[found[i].ID] = &found[i]
+	}
+	shares := make([]models.Share, 0, len(found))
+	for _, id := range ids {
+		if s := byID[id]; s != nil && s.Reachable() && !s.IsExpired() {
+			shares = append(shares, *s)
+		}
+	}
+	return &col, shares, nil
+}
+
+// collectionCover 合集封面：配置的封面，否则为第一篇公开分享的封面
+func collectionCover(col *models.Collection, shares []models.Share, baseURL string) string {
+	if col.CoverImage != "" {
+		if strings.HasPrefix(col.CoverImage, "/") {
+			return baseURL + col.CoverImage
+		}
+		return col.CoverImage
+	}
+	for i := range shares {
+		if !shares[i].RequirePassword && !shares[i].Restricted && shares[i].Terms == "" && !shares[i].Paywall && !shares[i].Teaser && shares[i].MaxViews == 0 && shares[i].OpenAt(time.Now()) {
+			return shareCoverImage(&shares[i], baseURL)
+		}
+	}
+	return ""
+}
+
+// GetCollection 公开的合集首页数据
+func GetCollection(c *gin.Context) {
+	col, shares, err := publicCollection(c.Param("slug"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Collection not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load collection: " + err.Error()})
+		return
+	}
+
+	var owner models.User
+	models.DB.Select("username").Where("id = ?", col.UserID).First(&owner)
+	baseURL := getBaseURL(c)
+	items := make([]collectionEntry, 0, len(shares))
+	askEnabled := false
+	for i := range shares {
+		s := &shares[i]
+		locked := s.RequirePassword || s.Restricted || s.Terms != "" || s.Paywall || s.Teaser || s.MaxViews > 0 || !s.OpenAt(time.Now())
+		askEnabled = askEnabled || (s.AllowQA && !locked)
+		entry := collectionEntry{
+			ID:        s.ID,
+			DocTitle:  s.DocTitle,
+			URL:       baseURL + "/s/" + s.ID,
+			Locked:    locked,
+			UpdatedAt: s.UpdatedAt,
+		}
+		if !entry.Locked {
+			entry.Summary = shareDescription(s, 160)
+		}
+		items = append(items, entry)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"slug":        col.Slug,
+		"title":       col.Title,
+		"description": col.Description,
+		"coverImage":  collectionCover(col, shares, baseURL),
+		"author":      owner.Username,
+		"updatedAt":   col.UpdatedAt,
+		"items":       items,
+		"askEnabled":  askEnabled && qa.Enabled(), // 可向合集提问，见 AskCollection
+	}})
+}
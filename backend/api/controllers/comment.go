@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/challenge"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/gin-gonic/gin"
+)
+
+// CommentRequest 发表评论请求；website 为隐藏的蜜罐字段，正常读者不会填写
+type CommentRequest struct {
+	Author  string `json:"author" binding:"max=50"` // 匿名评论的昵称，登录读者忽略
+	Content string `json:"content" binding:"required,max=5000"`
+	Website string `json:"website"`
+}
+
+// ListShareComments 读者查看分享下已公开的评论
+func ListShareComments(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if !share.AllowComments {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"enabled": false, "items": []models.Comment{}}})
+		return
+	}
+	var items []models.Comment
+	if err := models.DB.Where("share_id = ? AND status = ?", share.ID, models.CommentStatusApproved).
+		Order("created_at").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list comments: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"enabled": true, "items": items}})
+}
+
+// CreateComment 读者发表评论：登录读者的评论直接公开，匿名评论需分享者审核后公开
+func CreateComment(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if !share.AllowComments {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Comments are disabled for this share"})
+		return
+	}
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	content := strings.TrimSpace(req.Content)
+	if content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Comment must not be empty"})
+		return
+	}
+	// 匿名评论按 challenge.actions 要求人机验证，登录读者已通过登录校验
+	if c.GetString("userID") == "" && !passChallenge(c, challenge.ActionComment) {
+		return
+	}
+
+	cm := &models.Comment{
+		ID:      "cmt_" + randHex(10),
+		ShareID: share.ID,
+		Content: content,
+		Status:  models.CommentStatusPending,
+		IP:      c.ClientIP(),
+	}
+	// 蜜罐字段被填写时按待审核返回，但不保存
+	if req.Website != "" {
+		cm.Author, cm.CreatedAt = strings.TrimSpace(req.Author), time.Now()
+		cm.UpdatedAt = cm.CreatedAt
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": cm})
+		return
+	}
+	if userID := c.GetString("userID"); userID != "" {
+		var user models.User
+		if err := models.DB.Select("id", "username").Where("id = ?", userID).First(&user).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "User not found"})
+			return
+		}
+		cm.UserID, cm.Author, cm.Status = user.ID, user.Username, models.CommentStatusApproved
+	} else {
+		cm.Author = strings.TrimSpace(req.Author)
+		if cm.Author == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Name is required for anonymous comments"})
+			return
+		}
+	}
+	if err := models.DB.Create(cm).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save comment: " + err.Error()})
+		return
+	}
+
+	// 通知分享者有新的评论
+	if share.UserID != cm.UserID {
+		action := " 发表了评论："
+		if cm.Status == models.CommentStatusPending {
+			action = " 发表了评论（待审核）："
+		}
+		notify.UserPush(share.UserID, notify.PushMessage{
+			Title: share.DocTitle,
+			Body:  cm.Author + action + plainSummary(cm.Content, 80),
+			URL:   getBaseURL(c) + "/s/" + share.ID,
+		})
+		notify.OwnerCommentReceived(share, cm.Author, plainSummary(cm.Content, 200), cm.Status == models.CommentStatusPending, getBaseURL(c))
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": cm})
+}
+
+// ListOwnerComments 分享者查看全部评论（含待审核），可按 status 过滤
+func ListOwnerComments(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	query := models.DB.Where("share_id = ?", share.ID)
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var items []models.Comment
+	if err := query.Order("created_at DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list comments: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"enabled": share.AllowComments, "items": items}})
+}
+
+// ApproveComment 分享者审核通过评论
+func ApproveComment(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	result := models.DB.Model(&models.Comment{}).Where("id = ? AND share_id = ?", c.Param("cid"), share.ID).
+		Update("status", models.CommentStatusApproved)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to approve comment: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Comment not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// DeleteComment 分享者删除评论
+func DeleteComment(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	result := models.DB.Where("id = ? AND share_id = ?", c.Param("cid"), share.ID).Delete(&models.Comment{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete comment: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Comment not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// notModified 设置 ETag 与 Last-Modified（modified 为零值时不设置），按条件请求头判断读者的缓存是否仍然有效，
+// 有效时写入 304 并返回 true。同时带有两种条件时以 If-None-Match 为准 (RFC 9110 13.2.2)
+func notModified(c *gin.Context, etag string, modified time.Time) bool {
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		if etag == "" || !etagMatch(inm, etag) {
+			return false
+		}
+	} else if ims := c.GetHeader("If-Modified-Since"); ims != "" && !modified.IsZero() {
+		t, err := http.ParseTime(ims)
+		// Last-Modified 精确到秒
+		if err != nil || modified.Truncate(time.Second).After(t) {
+			return false
+		}
+	} else {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	return true
+}
+
+// etagMatch 按弱比较判断 If-None-Match 列表是否包含 etag
+func etagMatch(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// weakETag 以内容摘要生成弱 ETag，用于语义相同即可复用的响应（如阅读页数据）
+func weakETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxDomains 每个用户可绑定的自定义域名数量上限
+const maxDomains = 5
+
+// domainPattern 自定义域名：至少两级，国际化域名须使用 Punycode 形式
+var domainPattern = regexp.MustCompile(`^(?:[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9-]{2,63}$`)
+
+// DomainRequest 绑定自定义域名或修改其首页
+type DomainRequest struct {
+	Domain   string `json:"domain"`
+	HomePath string `json:"homePath"`
+}
+
+// domainView 自定义域名及其验证记录
+type domainView struct {
+	models.CustomDomain
+	Verified    bool   `json:"verified"`
+	RecordName  string `json:"recordName"`  // TXT 记录名称
+	RecordValue string `json:"recordValue"` // TXT 记录值
+}
+
+func newDomainView(d *models.CustomDomain) domainView {
+	return domainView{CustomDomain: *d, Verified: d.Verified(), RecordName: d.VerifyRecordName(), RecordValue: d.VerifyRecordValue()}
+}
+
+// normalizeDomain 校验域名格式，拒绝本实例自身使用的域名
+func normalizeDomain(c *gin.Context, domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if !domainPattern.MatchString(domain) || len(domain) > 253 {
+		return "", errors.New("invalid domain")
+	}
+	own := append([]string{}, config.Get().TLS.Domains...)
+	if h, _, err := net.SplitHostPort(c.Request.Host); err == nil {
+		own = append(own, h)
+	} else {
+		own = append(own, c.Request.Host)
+	}
+	for _, d := range own {
+		if strings.EqualFold(d, domain) {
+			return "", errors.New("domain is used by this server")
+		}
+	}
+	return domain, nil
+}
+
+// validHomePath 根路径跳转的目标须为站内路径
+func validHomePath(p string) bool {
+	return p == "" || (strings.HasPrefix(p, "/") && !strings.HasPrefix(p, "//") && len(p) <= 200 && !strings.ContainsAny(p, "\\\r\n"))
+}
+
+// reloadDomains 域名变更后刷新中间件中的域名路由表
+func reloadDomains() {
+	if err := middleware.ReloadDomains(); err != nil {
+		log.Printf("Failed to reload custom domains: %v", err)
+	}
+}
+
+// ListDomains 列出当前用户绑定的自定义域名
+func ListDomains(c *gin.Context) {
+	var rows []models.CustomDomain
+	if err := models.DB.Where("user_id = ?", c.GetString("userID")).Order("created_at").Find(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list domains: " + err.Error()})
+		return
+	}
+	items := make([]domainView, 0, len(rows))
+	for i := range rows {
+		items = append(items, newDomainView(&rows[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// bindDomain 为用户绑定域名，verified 为 true 时跳过 DNS 验证（管理员代为绑定）
+func bindDomain(c *gin.Context, userID string, verified bool) {
+	var req DomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	domain, err := normalizeDomain(c, req.Domain)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if !validHomePath(req.HomePath) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "homePath must be a path starting with /"})
+		return
+	}
+	var count int64
+	models.DB.Model(&models.CustomDomain{}).Where("user_id = ?", userID).Count(&count)
+	if count >= maxDomains {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Too many domains"})
+		return
+	}
+	// 未验证的绑定不占用域名，避免他人抢先申请导致域名所有者无法绑定；管理员绑定时替换所有未验证的申请
+	query := models.DB.Model(&models.CustomDomain{}).Where("domain = ?", domain)
+	if verified {
+		query = query.Where("verified_at IS NOT NULL")
+	} else {
+		query = query.Where("verified_at IS NOT NULL OR user_id = ?", userID)
+	}
+	var taken int64
+	query.Count(&taken)
+	if taken > 0 {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Domain already bound"})
+		return
+	}
+
+	d := &models.CustomDomain{ID: "dom_" + randHex(8), UserID: userID, Domain: domain, Token: randHex(16), HomePath: req.HomePath}
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if verified {
+			now := time.Now()
+			d.VerifiedAt = &now
+			if err := tx.Where("domain = ?", domain).Delete(&models.CustomDomain{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(d).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to bind domain: " + err.Error()})
+		return
+	}
+	if verified {
+		reloadDomains()
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": newDomainView(d)})
+}
+
+// CreateDomain 绑定自定义域名，返回需要添加的 TXT 验证记录
+func CreateDomain(c *gin.Context) {
+	bindDomain(c, c.GetString("userID"), false)
+}
+
+// AdminBindDomain 管理员为用户绑定自定义域名，无需 DNS 验证
+func AdminBindDomain(c *gin.Context) {
+	user, ok := loadQuotaUser(c)
+	if !ok {
+		return
+	}
+	bindDomain(c, user.ID, true)
+}
+
+// loadUserDomain 加载当前用户的指定域名，不存在时已写入响应
+func loadUserDomain(c *gin.Context) (*models.CustomDomain, bool) {
+	var d models.CustomDomain
+	if err := models.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetString("userID")).First(&d).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Domain not found"})
+		return nil, false
+	}
+	return &d, true
+}
+
+// UpdateDomain 修改访问域名根路径时跳转的首页
+func UpdateDomain(c *gin.Context) {
+	d, ok := loadUserDomain(c)
+	if !ok {
+		return
+	}
+	var req DomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if !validHomePath(req.HomePath) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "homePath must be a path starting with /"})
+		return
+	}
+	if err := models.DB.Model(d).Update("home_path", req.HomePath).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update domain: " + err.Error()})
+		return
+	}
+	reloadDomains()
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": newDomainView(d)})
+}
+
+// VerifyDomain 查询 _siyuan-share.<域名> 的 TXT 记录，包含验证值时完成归属验证
+func VerifyDomain(c *gin.Context) {
+	d, ok := loadUserDomain(c)
+	if !ok {
+		return
+	}
+	if d.Verified() {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": newDomainView(d)})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	records, err := net.DefaultResolver.LookupTXT(ctx, d.VerifyRecordName())
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "DNS lookup failed: " + err.Error()})
+		return
+	}
+	found := false
+	for _, r := range records {
+		if strings.TrimSpace(r) == d.VerifyRecordValue() {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Verification record not found"})
+		return
+	}
+	var taken int64
+	models.DB.Model(&models.CustomDomain{}).Where("domain = ? AND verified_at IS NOT NULL", d.Domain).Count(&taken)
+	if taken > 0 {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Domain already bound"})
+		return
+	}
+	now := time.Now()
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(d).Update("verified_at", now).Error; err != nil {
+			return err
+		}
+		// 验证成功后移除其他用户对同一域名的未验证申请
+		return tx.Where("domain = ? AND id <> ?", d.Domain, d.ID).Delete(&models.CustomDomain{}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update domain: " + err.Error()})
+		return
+	}
+	d.VerifiedAt = &now
+	reloadDomains()
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": newDomainView(d)})
+}
+
+// DeleteDomain 解绑自定义域名
+func DeleteDomain(c *gin.Context) {
+	d, ok := loadUserDomain(c)
+	if !ok {
+		return
+	}
+	if err := models.DB.Delete(d).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete domain: " + err.Error()})
+		return
+	}
+	reloadDomains()
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
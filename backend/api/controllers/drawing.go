@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/drawing"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxDrawings     = 50
+	maxDrawingBytes = 5 << 20 // 单个场景 JSON 上限（含内嵌图片）
+)
+
+var (
+	drawingIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	// 正文中的绘图引用: ![名称](drawing:ID)
+	drawingLinkPattern = regexp.MustCompile(`\]\(drawing:([A-Za-z0-9_-]{1,64})\)`)
+)
+
+// normalizeDrawings 校验发布时附带的绘图数组：检查 ID 与场景格式并压缩 JSON；
+// 第二个返回值表示请求是否携带了绘图字段（未携带时保持原数据）
+func normalizeDrawings(raw json.RawMessage) (string, bool, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", false, nil
+	}
+	var drawings []models.Drawing
+	if err := json.Unmarshal(raw, &drawings); err != nil {
+		return "", true, errors.New("expected an array of drawings")
+	}
+	if len(drawings) > maxDrawings {
+		return "", true, fmt.Errorf("too many drawings (max %d)", maxDrawings)
+	}
+	seen := make(map[string]bool, len(drawings))
+	for i := range drawings {
+		d := &drawings[i]
+		d.ID = strings.TrimSpace(d.ID)
+		d.Name = strings.TrimSpace(d.Name)
+		if !drawingIDPattern.MatchString(d.ID) {
+			return "", true, fmt.Errorf("drawing %d has an invalid id", i+1)
+		}
+		if seen[d.ID] {
+			return "", true, fmt.Errorf("duplicate drawing id %q", d.ID)
+		}
+		seen[d.ID] = true
+		if d.Format == "" {
+			d.Format = drawing.FormatExcalidraw
+		}
+		if d.Format != drawing.FormatExcalidraw {
+			return "", true, fmt.Errorf("drawing %s: unsupported format %q", d.ID, d.Format)
+		}
+		if len(d.Scene) > maxDrawingBytes {
+			return "", true, fmt.Errorf("drawing %s is too large", d.ID)
+		}
+		if _, err := drawing.Parse(d.Scene); err != nil {
+			return "", true, fmt.Errorf("drawing %s: %w", d.ID, err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, d.Scene); err != nil {
+			return "", true, fmt.Errorf("drawing %s: %w", d.ID, err)
+		}
+		d.Scene = compact.Bytes()
+	}
+	if len(drawings) == 0 {
+		return "", true, nil
+	}
+	data, err := json.Marshal(drawings)
+	if err != nil {
+		return "", true, err
+	}
+	return string(data), true, nil
+}
+
+// replaceDrawingLinks 将正文中的绘图引用替换为服务端渲染的 SVG 地址
+func replaceDrawingLinks(content, baseURL, shareID string) string {
+	if !strings.Contains(content, "](drawing:") {
+		return content
+	}
+	return drawingLinkPattern.ReplaceAllString(content, "]("+baseURL+"/api/s/"+shareID+"/drawings/$1.svg)")
+}
+
+// ListShareDrawings 列出分享附带的绘图及其 SVG 与源文件地址
+func ListShareDrawings(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	base := getBaseURL(c) + "/api/s/" + share.ID + "/drawings/"
+	items := []gin.H{}
+	for _, d := range share.DrawingList() {
+		items = append(items, gin.H{
+			"id":        d.ID,
+			"name":      d.Name,
+			"format":    d.Format,
+			"svgUrl":    base + d.ID + ".svg",
+			"sourceUrl": base + d.ID + "." + d.Format,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// ServeDrawing 输出绘图：<id>.svg 为服务端渲染的 SVG，<id>.excalidraw 为原始场景，
+// 可在 Excalidraw 中打开编辑。与分享资源一样以图片方式嵌入正文，因此不校验访问密码
+func ServeDrawing(c *gin.Context) {
+	file := c.Param("file")
+	dot := strings.LastIndexByte(file, '.')
+	if dot <= 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Drawing not found"})
+		return
+	}
+	id, ext := file[:dot], file[dot+1:]
+
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	// 与资源一致：草稿仍可访问以便所有者预览，停用后一并下线
+	if share.Status == models.ShareStatusDisabled && !share.InViewGrace() {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Share is disabled"})
+		return
+	}
+	if share.IsExpired() {
+		c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Share has expired"})
+		return
+	}
+	d := share.FindDrawing(id)
+	if d == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Drawing not found"})
+		return
+	}
+
+	etag := `"` + share.UpdatedAt.Format("20060102150405.000000") + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	switch ext {
+	case "svg":
+		scene, err := drawing.Parse(d.Scene)
+		if err != nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"code": 1, "msg": "Invalid drawing: " + err.Error()})
+			return
+		}
+		// SVG 与页面同源，禁止其中的脚本与外部资源
+		c.Header("Content-Security-Policy", "default-src 'none'; img-src data:; style-src 'unsafe-inline'")
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", drawing.SVG(scene))
+	case d.Format:
+		name := d.Name
+		if name == "" {
+			name = d.ID
+		}
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + "." + d.Format}))
+		c.Data(http.StatusOK, "application/json; charset=utf-8", d.Scene)
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Drawing not found"})
+	}
+}
@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// oEmbed 返回的 iframe 默认尺寸
+const (
+	embedDefaultWidth  = 800
+	embedDefaultHeight = 600
+)
+
+// embedSharePathPattern oEmbed 可解析的分享地址：阅读页 /s/<id> 或嵌入页 /s/<id>/embed
+var embedSharePathPattern = regexp.MustCompile(`^/s/([0-9A-Za-z_-]+)(?:/embed)?/?$`)
+
+// ShareEmbed 嵌入其他网站（iframe）的分享页面：服务端渲染、不含站点导航，?title=0 时不显示标题。
+// 允许被 embed.frame_ancestors 中的网站嵌入；需要密码、仅限名单访问、需同意使用条款、限制浏览次数、
+// 设置了开放时间或端到端加密的分享只显示在新窗口中打开的链接
+func ShareEmbed(c *gin.Context) {
+	if !config.Get().Embed.Enabled {
+		messagePage(c, http.StatusNotFound, "page.embed_disabled")
+		return
+	}
+	middleware.AllowFraming(c)
+	c.Header("X-Robots-Tag", "noindex")
+
+	share, err := models.FindShare(c.Param("id"))
+	if err != nil || !share.Reachable() || share.IsExpired() || !share.OpenAt(time.Now()) {
+		messagePage(c, http.StatusNotFound, "page.link_invalid")
+		return
+	}
+	baseURL := getBaseURL(c)
+	fullURL := baseURL + "/s/" + share.ID
+	locale := middleware.Locale(c)
+	if privateAssets(share) || share.Encrypted() {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(`<!doctype html><html lang="`+locale+`"><head><meta charset="utf-8">`+
+			`<meta name="viewport" content="width=device-width,initial-scale=1"><title>`+html.EscapeString(i18n.T(locale, "page.title"))+`</title></head>`+
+			`<body style="font-family:sans-serif;text-align:center;padding:32px 16px">`+
+			`<p>`+html.EscapeString(i18n.T(locale, "page.embed_private"))+`</p>`+
+			`<p><a href="`+html.EscapeString(fullURL)+`" target="_blank" rel="noopener">`+html.EscapeString(i18n.T(locale, "page.embed_open"))+`</a></p>`+
+			`</body></html>`))
+		return
+	}
+	// 读者访问的流量计入分享所有者
+	c.Set("contentOwner", share.UserID)
+	c.Set("contentShare", share.ID)
+	if !countShareView(c, share) {
+		return
+	}
+
+	var owner models.User
+	models.DB.Select("username").Where("id = ?", share.UserID).First(&owner)
+
+	content, _ := renderShareContent(c, share)
+	content = deliverShareContent(c, share, content)
+	page := export.EmbedPage(export.EmbedDoc{
+		Title:     share.DocTitle,
+		Author:    owner.Username,
+		Date:      share.UpdatedAt.Format("2006-01-02"),
+		Lang:      locale,
+		Content:   content,
+		HideTitle: c.Query("title") == "0",
+		FullURL:   fullURL,
+		FullLabel: i18n.T(locale, "page.full_version"),
+		Link:      shareAssetLink(baseURL, share.ID),
+		Prerender: sharePrerenderer(share),
+	})
+
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.Header("Cache-Control", "no-cache")
+	if notModified(c, weakETag([]byte(page)), time.Time{}) {
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+}
+
+// OEmbed oEmbed 接口（https://oembed.com）：url 为分享链接，返回嵌入 /s/<id>/embed 的 iframe（rich 类型），
+// 可用 maxwidth / maxheight 限制尺寸。仅支持 JSON 格式；不能公开嵌入的分享返回 401，与规范一致
+func OEmbed(c *gin.Context) {
+	if !config.Get().Embed.Enabled {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Embedding is disabled"})
+		return
+	}
+	if format := c.DefaultQuery("format", "json"); format != "json" {
+		c.JSON(http.StatusNotImplemented, gin.H{"code": 1, "msg": "Only JSON format is supported"})
+		return
+	}
+	u, err := url.Parse(c.Query("url"))
+	if err != nil || u.Host == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid url"})
+		return
+	}
+	m := embedSharePathPattern.FindStringSubmatch(u.Path)
+	if m == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	share, err := models.FindShare(m[1])
+	if err != nil || !share.Reachable() || share.IsExpired() {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	if privateAssets(share) || share.Encrypted() {
+		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Share cannot be embedded"})
+		return
+	}
+
+	width, height := embedDefaultWidth, embedDefaultHeight
+	if v, err := strconv.Atoi(c.Query("maxwidth")); err == nil && v > 0 && v < width {
+		width = v
+	}
+	if v, err := strconv.Atoi(c.Query("maxheight")); err == nil && v > 0 && v < height {
+		height = v
+	}
+	var owner models.User
+	models.DB.Select("username").Where("id = ?", share.UserID).First(&owner)
+
+	baseURL := getBaseURL(c)
+	src := baseURL + "/s/" + share.ID + "/embed"
+	iframe := fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" frameborder="0" loading="lazy" style="border:0;max-width:100%%"></iframe>`,
+		html.EscapeString(src), width, height, html.EscapeString(share.DocTitle))
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, gin.H{
+		"version":       "1.0",
+		"type":          "rich",
+		"provider_name": i18n.T(middleware.Locale(c), "page.title"),
+		"provider_url":  baseURL,
+		"title":         share.DocTitle,
+		"author_name":   owner.Username,
+		"html":          iframe,
+		"width":         width,
+		"height":        height,
+		"cache_age":     3600,
+	})
+}
+
+// oEmbedDiscovery 阅读页中的 oEmbed 发现链接，供 WordPress、Notion 等工具由分享链接自动生成嵌入
+func oEmbedDiscovery(baseURL, shareURL, title string) string {
+	href := baseURL + "/api/oembed?format=json&url=" + url.QueryEscape(shareURL)
+	return `<link rel="alternate" type="application/json+oembed" href="` + html.EscapeString(href) +
+		`" title="` + title + `" />`
+}
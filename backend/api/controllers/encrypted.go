@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// encryptedPayload 端到端加密分享的阅读页数据：正文原样返回密文，由阅读页用链接 # 片段中的密钥解密；
+// 标题只保存在密文中（服务器保存的标题仅用于所有者管理，不返回给读者），依赖正文的字段均为空
+func encryptedPayload(c *gin.Context, share *models.Share) gin.H {
+	shown := variantShare(c, share)
+	return gin.H{
+		"id":              share.ID,
+		"docTitle":        "",
+		"content":         share.Content,
+		"requirePassword": share.RequirePassword,
+		"restricted":      share.Restricted,
+		"allowPdf":        false,
+		"assetDownloads":  share.DownloadPolicy(),
+		"allowQa":         false,
+		"allowFeedback":   share.AllowFeedback,
+		"trackReading":    share.TrackReading,
+		"readingMinutes":  0,
+		"wordCount":       0,
+		"lang":            "",
+		"translations":    []string{},
+		"expireAt":        share.ExpireAt,
+		"theme":           resolveTheme(c, shown),
+		"toc":             shown.TOC,
+		"variant":         shareVariant(c, share),
+		"headingAnchors":  share.HeadingAnchors,
+		"headings":        []any{},
+		"prerender":       gin.H{"math": false, "mermaid": false, "code": false},
+		"customThemeUrl":  customThemeURL(shown),
+		"linkPreviews":    gin.H{},
+		"archivedLinks":   gin.H{},
+		"narration":       nil,
+		"bibliography":    []any{},
+		"tables":          []any{},
+		"tasks":           nil,
+		"mode":            share.Mode,
+		"cardCount":       0,
+		"status":          share.Status,
+		"seal":            sealPayload(share),
+		"signature":       signaturePayload(share),
+		"viewLimit":       viewLimitPayload(c, share),
+		"viewCount":       share.ViewCount,
+		"paywall":         share.Paywall,
+		"donation":        ownerDonation(share.UserID),
+		"createdAt":       share.CreatedAt,
+	}
+}
+
+// rejectEncrypted 服务器读取正文的操作（朗读、翻译、摘要、语言检查等）不适用于加密分享：已写入 400 响应时返回 true
+func rejectEncrypted(c *gin.Context, share *models.Share) bool {
+	if !share.Encrypted() {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Not available for encrypted shares"})
+	return true
+}
@@ -0,0 +1,29 @@
+package controllers
+
+// 业务错误码：0 表示成功，1 为通用错误；以下为需要客户端特殊处理的错误
+const (
+	// CodeAssetChecksumMismatch 资源上传后服务端计算的哈希/大小与客户端声明不一致，客户端应重新上传
+	CodeAssetChecksumMismatch = 1001
+	// CodeTwoFactorRequired 账号已启用两步验证，需在登录请求中附带 otp（验证码或恢复码）
+	CodeTwoFactorRequired = 1002
+	// CodeQuotaExceeded 超出存储配额（总容量、分享数或单个资源大小），data 中附带当前用量与配额
+	CodeQuotaExceeded = 1003
+	// CodeShareRestricted 分享仅对访问名单开放，读者需登录名单中的账号或通过邮件链接验证邮箱；data.emailAccess 表示能否申请邮件链接
+	CodeShareRestricted = 1004
+	// CodeAccountLocked 账号因连续登录失败被临时锁定，data.lockedUntil 为解锁时间；可通过邮件中的链接或联系管理员提前解锁
+	CodeAccountLocked = 1005
+	// CodeShareNotOpen 分享不在开放时间内，data.opensAt 为下一次开放时间（不会再开放时为 null），data.timezone 为发布者时区
+	CodeShareNotOpen = 1006
+	// CodeTermsRequired 读者须先同意分享的使用条款（data.terms 为条款内容），同意后通过 X-Share-Terms 请求头携带令牌
+	CodeTermsRequired = 1007
+	// CodeShareSealed 分享已封存，保留期（data.sealedUntil）满前不能修改正文、访问设置或删除
+	CodeShareSealed = 1008
+	// CodeShareModerated 分享因举报被管理员停用（data.moderatedAt），所有者不能重新上线，需管理员恢复
+	CodeShareModerated = 1009
+	// CodePaymentRequired 付费分享未解锁（data.price 为价格，data.payable 为能否在线购买），支付后通过 X-Share-Unlock 请求头携带令牌
+	CodePaymentRequired = 1010
+	// CodeLoginRequired 试读分享须登录后阅读全文，阅读页正文接口此时只返回试读部分（data.teaser）
+	CodeLoginRequired = 1011
+	// CodeChallengeRequired 需要完成人机验证（data.provider 与 data.siteKey 用于渲染验证组件），通过后以 X-Challenge-Token 请求头携带令牌重试
+	CodeChallengeRequired = 1012
+)
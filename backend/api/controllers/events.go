@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/events"
+	"github.com/gin-gonic/gin"
+)
+
+// eventsHeartbeat 事件流的心跳间隔，避免反向代理因连接空闲而断开
+const eventsHeartbeat = 25 * time.Second
+
+// Events 控制面板的实时事件流（Server-Sent Events）：推送当前用户的分享浏览、分享发布与 API Token 使用事件，
+// 每条消息的 event 为事件类型，data 为 JSON；连接断开后客户端应重连并重新加载一次数据
+func Events(c *gin.Context) {
+	ch, cancel, err := events.Subscribe(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many event streams"})
+		return
+	}
+	defer cancel()
+
+	w := c.Writer
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Accel-Buffering", "no") // 关闭 nginx 的响应缓冲
+	c.Status(http.StatusOK)
+	fmt.Fprint(w, "retry: 5000\n\n")
+	w.Flush()
+
+	ticker := time.NewTicker(eventsHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case ev, ok := <-ch:
+			if !ok {
+				return
+			}
+			data, _ := json.Marshal(ev)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data)
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// exportURLTTL 导出文件直链的有效期（storage.signed_downloads）
+const exportURLTTL = 10 * time.Minute
+
+// CreateExportRequest 创建导出任务
+type CreateExportRequest struct {
+	Format       string `json:"format"`       // 目前仅支持 latex（默认）
+	Reproducible bool   `json:"reproducible"` // 可复现导出：相同内容生成逐字节一致的文件包
+}
+
+// CreateExport 为分享创建后台导出任务，完成后通过 GetExport 查询状态并下载
+func CreateExport(c *gin.Context) {
+	var req CreateExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.Format == "" {
+		req.Format = export.FormatLaTeX
+	}
+	if !export.Formats[req.Format] {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Unsupported export format"})
+		return
+	}
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if storage.Default == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Storage not available"})
+		return
+	}
+	job, err := export.Enqueue(share, req.Format, req.Reproducible)
+	if err != nil {
+		if errors.Is(err, export.ErrQueueFull) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Export queue is full, please retry later"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create export: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"code": 0, "msg": "success", "data": job})
+}
+
+// ListExports 列出分享最近的导出任务
+func ListExports(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	items := []models.ExportJob{}
+	if err := models.DB.Where("share_id = ?", share.ID).Order("created_at DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list exports: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+func loadOwnedExport(c *gin.Context) (*models.ExportJob, bool) {
+	var job models.ExportJob
+	if err := models.DB.Where("id = ? AND share_id = ? AND user_id = ?", c.Param("eid"), c.Param("id"), c.GetString("userID")).
+		First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Export not found"})
+		return nil, false
+	}
+	return &job, true
+}
+
+// GetExport 查询导出任务状态
+func GetExport(c *gin.Context) {
+	job, ok := loadOwnedExport(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": job})
+}
+
+// DownloadExport 下载已完成的导出文件
+func DownloadExport(c *gin.Context) {
+	job, ok := loadOwnedExport(c)
+	if !ok {
+		return
+	}
+	if job.Status != models.ExportDone {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Export is not ready", "data": job})
+		return
+	}
+	if storage.Default == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Storage not available"})
+		return
+	}
+	// 存储支持直链时重定向，下载流量不经过本服务；签发失败时照常由本服务转发
+	if config.Get().Storage.SignedDownloads {
+		u, err := storage.Default.SignedURL(c.Request.Context(), job.StorageKey, exportURLTTL, job.FileName)
+		if err == nil {
+			c.Header("Cache-Control", "private, no-store")
+			c.Redirect(http.StatusFound, u)
+			return
+		}
+		if !errors.Is(err, storage.ErrNotSupported) {
+			log.Printf("export %s: signed url failed: %v", job.ID, err)
+		}
+	}
+	rc, info, err := storage.Default.Get(c.Request.Context(), job.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Export file not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read export: " + err.Error()})
+		return
+	}
+	defer rc.Close()
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": job.FileName}))
+	c.Header("Cache-Control", "private, no-store")
+	if info != nil && info.Size > 0 {
+		c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	}
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, rc)
+}
+
+// ExportShareBundle 下载分享的独立文件包（format=md|html，默认 md）：正文连同分享资源与绘图打包为 zip，
+// 便于归档或迁移；文件包即时生成，不经过后台导出任务。reproducible=1 时相同内容的文件包逐字节一致，
+// 响应附带内容的 SHA-256（X-Content-SHA256），便于下游比对
+func ExportShareBundle(c *gin.Context) {
+	format := c.DefaultQuery("format", export.BundleMarkdown)
+	if !export.BundleFormats[format] {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Unsupported export format, use md or html"})
+		return
+	}
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if storage.Default == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Storage not available"})
+		return
+	}
+	var user models.User
+	models.DB.Select("username").Where("id = ?", share.UserID).First(&user)
+
+	data, name, err := export.Bundle(c.Request.Context(), share, format, export.BundleInfo{
+		Author:       user.Username,
+		URL:          getBaseURL(c) + "/s/" + share.ID,
+		Reproducible: c.Query("reproducible") == "1",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to export share: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Header("Cache-Control", "private, no-store")
+	sum := sha256.Sum256(data)
+	c.Header("X-Content-SHA256", hex.EncodeToString(sum[:]))
+	c.Data(http.StatusOK, "application/zip", data)
+}
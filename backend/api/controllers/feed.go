@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	GUID        string `xml:"guid"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+}
+
+// UserFeed 输出用户公开收录分享的 RSS 订阅源（需用户在设置中开启），生成结果缓存到有分享变化为止
+func UserFeed(c *gin.Context) {
+	username := c.Param("username")
+
+	var user models.User
+	if err := models.DB.Where("username = ? AND is_active = ?", username, true).First(&user).Error; err != nil || !user.FeedEnabled {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Feed not found"})
+		return
+	}
+
+	baseURL := getBaseURL(c)
+	key := "feed:" + user.Username + ":" + baseURL
+	if body, ok := models.LoadListing(key); ok {
+		writeFeed(c, body)
+		return
+	}
+
+	builtAt := time.Now()
+	var shares []models.Share
+	if err := models.DB.Where("user_id = ? AND listed = ? AND is_public = ? AND status = ? AND require_password = ? AND restricted = ? AND access_schedule = ? AND terms = ? AND paywall = ? AND teaser = ? AND max_views = ? AND expire_at > ?",
+		user.ID, true, true, models.ShareStatusPublished, false, false, "", "", false, false, 0, time.Now()).
+		Order("created_at DESC").Limit(50).Find(&shares).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load feed: " + err.Error()})
+		return
+	}
+
+	feed := rssFeed{
+		Version: "2.0",
+		Atom:    "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:       user.Username + " 的分享",
+			Link:        baseURL,
+			Description: user.Username + " 公开发布的笔记",
+			AtomLink:    rssLink{Href: baseURL + "/u/" + user.Username + "/feed.xml", Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+	if len(shares) > 0 {
+		feed.Channel.LastBuildDate = shares[0].UpdatedAt.UTC().Format(time.RFC1123Z)
+	}
+	for _, s := range shares {
+		link := baseURL + "/s/" + s.ID
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       s.DocTitle,
+			Link:        link,
+			GUID:        link,
+			Description: shareDescription(&s, 200),
+			PubDate:     s.CreatedAt.UTC().Format(time.RFC1123Z),
+		})
+	}
+
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to render feed"})
+		return
+	}
+	body := append([]byte(xml.Header), out...)
+	models.SaveListing(key, builtAt, earliestExpiry(shares), body)
+	writeFeed(c, body)
+}
+
+func writeFeed(c *gin.Context, body []byte) {
+	c.Header("Cache-Control", "public, max-age=600")
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", body)
+}
+
+var (
+	// htmlCommentPattern HTML 注释，包括试读分隔线 <!-- more -->
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?-->`)
+	mdImagePattern     = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	mdLinkPattern      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	mdIALPattern       = regexp.MustCompile(`\{:[^}]*\}`)
+	mdSymbolPattern    = regexp.MustCompile("[#>*_`~|]+")
+	spacePattern       = regexp.MustCompile(`\s+`)
+)
+
+// plainSummary 将 Markdown 粗略转为纯文本并截取前 n 个字符作为摘要
+func plainSummary(content string, n int) string {
+	text := htmlCommentPattern.ReplaceAllString(content, "")
+	text = mdImagePattern.ReplaceAllString(text, "")
+	text = mdLinkPattern.ReplaceAllString(text, "$1")
+	text = mdIALPattern.ReplaceAllString(text, "")
+	text = mdSymbolPattern.ReplaceAllString(text, "")
+	text = strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+	runes := []rune(text)
+	if len(runes) > n {
+		return string(runes[:n]) + "…"
+	}
+	return text
+}
+
+// shareDescription 分享的简介：优先使用摘要（作者填写或自动生成），没有时截取正文开头 n 个字符
+func shareDescription(share *models.Share, n int) string {
+	if share.Summary != "" {
+		return share.Summary
+	}
+	return plainSummary(share.Content, n)
+}
@@ -0,0 +1,23 @@
+package controllers
+
+import "testing"
+
+func TestPlainSummary(t *testing.T) {
+	cases := []struct {
+		content string
+		n       int
+		want    string
+	}{
+		{"# Title\n\nSome **bold** text", 100, "Title Some bold text"},
+		{"See [the docs](https://example.com) ![img](a.png)", 100, "See the docs"},
+		{"> quoted {: id=\"x\"}", 100, "quoted"},
+		{"Teaser part\n\n<!-- more -->\n\nRest of the share", 100, "Teaser part Rest of the share"},
+		{"Before <!--\nmulti-line\ncomment --> after", 100, "Before after"},
+		{"一二三四五", 3, "一二三…"},
+	}
+	for _, c := range cases {
+		if got := plainSummary(c.content, c.n); got != c.want {
+			t.Errorf("plainSummary(%q, %d) = %q, want %q", c.content, c.n, got, c.want)
+		}
+	}
+}
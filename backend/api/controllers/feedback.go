@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxFeedbackLength = 1000 // 反馈文字说明的最大字符数
+	maxFeedbackLinks  = 2    // 文字说明中允许的链接数，超过时视为垃圾信息
+)
+
+var feedbackLinkPattern = regexp.MustCompile(`(?i)https?://|www\.`)
+
+// FeedbackRequest 读者反馈
+type FeedbackRequest struct {
+	Helpful *bool  `json:"helpful" binding:"required"`
+	Comment string `json:"comment"`
+	Website string `json:"website"` // 蜜罐字段，正常读者不会填写
+}
+
+// SubmitFeedback 读者提交「是否有帮助」反馈，可附带文字说明；分享需开启反馈。
+// 同一 IP 在 24 小时内对同一分享重复提交时覆盖之前的反馈
+func SubmitFeedback(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if !share.AllowFeedback {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Feedback is disabled for this share"})
+		return
+	}
+	var req FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if utf8.RuneCountInString(comment) > maxFeedbackLength {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Feedback must be at most 1000 characters"})
+		return
+	}
+	if len(feedbackLinkPattern.FindAllStringIndex(comment, maxFeedbackLinks+1)) > maxFeedbackLinks {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Feedback must not contain more than 2 links"})
+		return
+	}
+	// 蜜罐字段被填写时照常返回，但不保存
+	if req.Website != "" {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+		return
+	}
+
+	ip := c.ClientIP()
+	var fb models.ShareFeedback
+	err := models.DB.Where("share_id = ? AND ip = ? AND created_at >= ?", share.ID, ip, time.Now().Add(-models.FeedbackDedupWindow)).
+		Order("created_at DESC").First(&fb).Error
+	if err == nil {
+		err = models.DB.Model(&fb).Updates(map[string]any{"helpful": *req.Helpful, "comment": comment}).Error
+	} else {
+		fb = models.ShareFeedback{ID: "fb_" + randHex(10), ShareID: share.ID, Helpful: *req.Helpful, Comment: comment, IP: ip}
+		err = models.DB.Create(&fb).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save feedback: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// GetShareFeedbackStats 所有者查看反馈汇总与附带文字说明的反馈（按时间倒序分页）；
+// from / to 格式同浏览数据导出，默认为全部反馈
+func GetShareFeedbackStats(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var from time.Time
+	to := time.Now().Add(time.Second)
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = parseViewExportTime(v, false); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = parseViewExportTime(v, true); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+	page, size := 1, 20
+	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(c.Query("size")); err == nil && v > 0 && v <= 100 {
+		size = v
+	}
+
+	summary, err := models.SummarizeFeedback(share.ID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load feedback: " + err.Error()})
+		return
+	}
+	items := []models.ShareFeedback{}
+	if err := models.DB.Where("share_id = ? AND comment <> '' AND created_at >= ? AND created_at < ?", share.ID, from, to).
+		Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load feedback: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"enabled": share.AllowFeedback,
+		"summary": summary,
+		"items":   items,
+		"total":   summary.Comments,
+		"page":    page,
+		"size":    size,
+	}})
+}
+
+// DeleteShareFeedback 所有者删除一条反馈（如垃圾信息），同时从汇总中移除
+func DeleteShareFeedback(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	res := models.DB.Where("id = ? AND share_id = ?", c.Param("fid"), share.ID).Delete(&models.ShareFeedback{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete feedback: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Feedback not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
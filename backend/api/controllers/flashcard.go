@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxFlashcards     = 5000
+	maxFlashcardBytes = 64 * 1024 // 单面内容上限
+)
+
+// flashcardScheduler 推荐的间隔重复参数（SM-2），阅读页与第三方客户端据此安排复习
+type flashcardScheduler struct {
+	Algorithm          string  `json:"algorithm"`
+	InitialEase        float64 `json:"initialEase"`
+	MinEase            float64 `json:"minEase"`
+	LearningSteps      []int   `json:"learningSteps"`      // 学习阶段的间隔（分钟）
+	GraduatingInterval int     `json:"graduatingInterval"` // 毕业后的首个间隔（天）
+	EasyInterval       int     `json:"easyInterval"`       // 新卡直接选“简单”时的间隔（天）
+}
+
+var defaultScheduler = flashcardScheduler{
+	Algorithm:          "sm2",
+	InitialEase:        2.5,
+	MinEase:            1.3,
+	LearningSteps:      []int{1, 10},
+	GraduatingInterval: 1,
+	EasyInterval:       4,
+}
+
+// normalizeFlashcards 校验发布时附带的闪卡数组：去除空白、补全 ID 并去重，返回序列化结果与卡片数
+func normalizeFlashcards(raw json.RawMessage) (string, int, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", 0, nil
+	}
+	var cards []models.Flashcard
+	if err := json.Unmarshal(raw, &cards); err != nil {
+		return "", 0, errors.New("expected an array of cards")
+	}
+	if len(cards) > maxFlashcards {
+		return "", 0, fmt.Errorf("too many cards (max %d)", maxFlashcards)
+	}
+	seen := make(map[string]bool, len(cards))
+	out := make([]models.Flashcard, 0, len(cards))
+	for i, card := range cards {
+		card.Front = strings.TrimSpace(card.Front)
+		card.Back = strings.TrimSpace(card.Back)
+		card.ID = strings.TrimSpace(card.ID)
+		if card.Front == "" {
+			return "", 0, fmt.Errorf("card %d has no front", i+1)
+		}
+		if len(card.Front) > maxFlashcardBytes || len(card.Back) > maxFlashcardBytes {
+			return "", 0, fmt.Errorf("card %d is too large", i+1)
+		}
+		if card.ID == "" {
+			card.ID = card.BlockID
+		}
+		if card.ID == "" {
+			sum := sha256.Sum256([]byte(card.Front + "\x00" + card.Back))
+			card.ID = hex.EncodeToString(sum[:8])
+		}
+		if seen[card.ID] {
+			return "", 0, fmt.Errorf("duplicate card id %q", card.ID)
+		}
+		seen[card.ID] = true
+		out = append(out, card)
+	}
+	if len(out) == 0 {
+		return "", 0, nil
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return "", 0, err
+	}
+	return string(data), len(out), nil
+}
+
+// ShareFlashcards 输出闪卡卡组：卡组信息、推荐的间隔重复参数与全部卡片；
+// ?format=tsv 时下载制表符分隔的文本，可直接导入 Anki 等工具
+func ShareFlashcards(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if share.Mode != models.ShareModeFlashcards {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share has no flashcards"})
+		return
+	}
+
+	cards := share.FlashcardList()
+	// 与正文一致，将块引用替换为分享链接
+	if share.References != "" {
+		var refs []models.BlockReference
+		if err := json.Unmarshal([]byte(share.References), &refs); err == nil {
+			baseURL := getBaseURL(c)
+			for i := range cards {
+				cards[i].Front = replaceBlockReferences(cards[i].Front, refs, baseURL, share.UserID)
+				cards[i].Back = replaceBlockReferences(cards[i].Back, refs, baseURL, share.UserID)
+			}
+		}
+	}
+
+	if c.Query("format") == "tsv" {
+		var b strings.Builder
+		field := strings.NewReplacer("\t", " ", "\r\n", "<br>", "\n", "<br>")
+		for _, card := range cards {
+			b.WriteString(field.Replace(card.Front) + "\t" + field.Replace(card.Back) + "\t" + strings.Join(card.Tags, " ") + "\n")
+		}
+		c.Header("Content-Disposition", `attachment; filename="`+share.ID+`.tsv"`)
+		c.Data(http.StatusOK, "text/tab-separated-values; charset=utf-8", []byte(b.String()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"deck": gin.H{
+			"id":        share.ID,
+			"name":      share.DocTitle,
+			"cardCount": len(cards),
+			"updatedAt": share.UpdatedAt,
+		},
+		"scheduler": defaultScheduler,
+		"cards":     cards,
+	}})
+}
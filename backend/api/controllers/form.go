@@ -0,0 +1,392 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxFormFields      = 20    // 每个表单的字段数上限
+	maxFormLabelLength = 100   // 字段标签的最大字符数
+	maxFormTextLength  = 200   // 单行文本与邮箱的最大字符数
+	maxFormAreaLength  = 2000  // 多行文本的最大字符数
+	maxFormSubmissions = 10000 // 每个表单保存的提交数上限
+)
+
+var (
+	formTitlePattern = regexp.MustCompile(`^#{1,6}\s+(.+)$`)
+	formFieldPattern = regexp.MustCompile(`^[-*+]\s+(.+)$`)
+	formTypePattern  = regexp.MustCompile(`\s*\[(text|textarea|email)\]$`)
+)
+
+// formField 表单字段
+type formField struct {
+	Label    string `json:"label"`
+	Type     string `json:"type"` // text / textarea / email
+	Required bool   `json:"required"`
+}
+
+// shareForm 正文中的表单块
+type shareForm struct {
+	Key    string      `json:"key"` // 由标题（无标题时由字段标签）生成，重新发布时保持不变
+	Index  int         `json:"index"`
+	Line   int         `json:"line"` // 代码块起始行，阅读页据此对应渲染出的代码块
+	Title  string      `json:"title"`
+	Fields []formField `json:"fields"`
+}
+
+// parseForm 解析 ```form 代码块：# 标题行为表单标题，列表项为字段；
+// 字段标签后可加 [text] / [textarea] / [email] 指定类型（默认 text），末尾加 * 表示必填
+func parseForm(source string) (title string, fields []formField) {
+	seen := map[string]bool{}
+	for _, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if m := formTitlePattern.FindStringSubmatch(line); m != nil {
+			if title == "" {
+				title = truncateRunes(strings.TrimSpace(m[1]), maxFormLabelLength)
+			}
+			continue
+		}
+		m := formFieldPattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		f := formField{Label: strings.TrimSpace(m[1]), Type: "text"}
+		if rest, ok := strings.CutSuffix(f.Label, "*"); ok {
+			f.Label, f.Required = strings.TrimSpace(rest), true
+		}
+		if t := formTypePattern.FindStringSubmatch(f.Label); t != nil {
+			f.Label, f.Type = strings.TrimSpace(strings.TrimSuffix(f.Label, t[0])), t[1]
+		}
+		if rest, ok := strings.CutSuffix(f.Label, "*"); ok {
+			f.Label, f.Required = strings.TrimSpace(rest), true
+		}
+		f.Label = truncateRunes(f.Label, maxFormLabelLength)
+		if f.Label != "" && !seen[f.Label] && len(fields) < maxFormFields {
+			seen[f.Label] = true
+			fields = append(fields, f)
+		}
+	}
+	return title, fields
+}
+
+// extractForms 提取正文中的表单块，没有字段的代码块忽略；相同的 key 后出现的附加序号
+func extractForms(content string) []shareForm {
+	var forms []shareForm
+	keys := map[string]int{}
+	for _, b := range extractFencedBlocks(content, func(lang string) bool { return lang == "form" }) {
+		title, fields := parseForm(b.Source)
+		if len(fields) == 0 {
+			continue
+		}
+		id := title
+		if id == "" {
+			labels := make([]string, len(fields))
+			for i, f := range fields {
+				labels[i] = f.Label
+			}
+			id = strings.Join(labels, "\n")
+		}
+		sum := sha256.Sum256([]byte(id))
+		key := hex.EncodeToString(sum[:8])
+		keys[key]++
+		if n := keys[key]; n > 1 {
+			key += "-" + strconv.Itoa(n)
+		}
+		forms = append(forms, shareForm{Key: key, Index: b.Index, Line: b.Line, Title: title, Fields: fields})
+	}
+	return forms
+}
+
+// findShareForm 按 key 查找分享正文中的表单
+func findShareForm(c *gin.Context, share *models.Share, key string) *shareForm {
+	content, _ := renderShareContent(c, share)
+	for _, f := range extractForms(content) {
+		if f.Key == key {
+			return &f
+		}
+	}
+	return nil
+}
+
+// ListShareForms 返回正文中的表单定义，供阅读页渲染
+func ListShareForms(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	content, _ := renderShareContent(c, share)
+	forms := extractForms(content)
+	if forms == nil {
+		forms = []shareForm{}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": forms}})
+}
+
+// FormSubmitRequest 读者提交表单
+type FormSubmitRequest struct {
+	Values  map[string]string `json:"values" binding:"required"` // 字段标签到填写内容
+	Website string            `json:"website"`                   // 蜜罐字段，正常读者不会填写
+}
+
+// SubmitForm 读者提交表单：按正文中的字段定义校验必填项、长度与邮箱格式，未定义的字段忽略
+func SubmitForm(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var req FormSubmitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	form := findShareForm(c, share, c.Param("key"))
+	if form == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Form not found"})
+		return
+	}
+
+	values := map[string]string{}
+	for _, f := range form.Fields {
+		v := strings.TrimSpace(req.Values[f.Label])
+		if v == "" {
+			if f.Required {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Missing required field: " + f.Label})
+				return
+			}
+			continue
+		}
+		limit := maxFormTextLength
+		if f.Type == "textarea" {
+			limit = maxFormAreaLength
+		}
+		if utf8.RuneCountInString(v) > limit {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Field is too long: " + f.Label})
+			return
+		}
+		if f.Type == "email" {
+			if addr, err := mail.ParseAddress(v); err != nil || addr.Address != v {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid email address: " + f.Label})
+				return
+			}
+		}
+		values[f.Label] = v
+	}
+	if len(values) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Form is empty"})
+		return
+	}
+	// 蜜罐字段被填写时照常返回，但不保存
+	if req.Website != "" {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+		return
+	}
+
+	var count int64
+	models.DB.Model(&models.FormSubmission{}).Where("share_id = ? AND form_key = ?", share.ID, form.Key).Count(&count)
+	if count >= maxFormSubmissions {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "This form is no longer accepting submissions"})
+		return
+	}
+	data, _ := json.Marshal(values)
+	sub := models.FormSubmission{
+		ID:        "fs_" + randHex(10),
+		ShareID:   share.ID,
+		FormKey:   form.Key,
+		FormTitle: form.Title,
+		Data:      string(data),
+		IP:        c.ClientIP(),
+	}
+	if err := models.DB.Create(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save submission: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// ListOwnerForms 所有者查看分享正文中的表单与各表单的提交数；已从正文删除但仍有提交的表单一并列出（fields 为空）
+func ListOwnerForms(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	counts, err := models.CountFormSubmissions(share.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load submissions: " + err.Error()})
+		return
+	}
+	byKey := make(map[string]models.FormSubmissionCount, len(counts))
+	for _, n := range counts {
+		byKey[n.FormKey] = n
+	}
+	items := []gin.H{}
+	for _, f := range extractForms(share.Content) {
+		items = append(items, gin.H{"key": f.Key, "title": f.Title, "fields": f.Fields, "submissions": byKey[f.Key].Count, "inContent": true})
+		delete(byKey, f.Key)
+	}
+	for _, n := range counts {
+		if _, ok := byKey[n.FormKey]; ok {
+			items = append(items, gin.H{"key": n.FormKey, "title": n.FormTitle, "fields": []formField{}, "submissions": n.Count, "inContent": false})
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// formSubmissionValues 解析提交内容
+func formSubmissionValues(s *models.FormSubmission) map[string]string {
+	values := map[string]string{}
+	_ = json.Unmarshal([]byte(s.Data), &values)
+	return values
+}
+
+// ListFormSubmissions 所有者按时间倒序分页查看表单的提交
+func ListFormSubmissions(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	page, size := 1, 20
+	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(c.Query("size")); err == nil && v > 0 && v <= 100 {
+		size = v
+	}
+	q := models.DB.Model(&models.FormSubmission{}).Where("share_id = ? AND form_key = ?", share.ID, c.Param("key"))
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load submissions: " + err.Error()})
+		return
+	}
+	var subs []models.FormSubmission
+	if err := q.Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&subs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load submissions: " + err.Error()})
+		return
+	}
+	items := make([]gin.H, 0, len(subs))
+	for i := range subs {
+		items = append(items, gin.H{"id": subs[i].ID, "values": formSubmissionValues(&subs[i]), "createdAt": subs[i].CreatedAt})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"items": items,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	}})
+}
+
+// csvCell 以 =、+、-、@ 等开头的读者填写内容前加单引号，避免在表格软件中被当作公式执行
+func csvCell(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+// ExportFormSubmissions 所有者将表单的全部提交导出为 CSV（带 BOM）：第一列为提交时间，
+// 其后为正文中当前的字段，再之后为只出现在早期提交中的字段
+func ExportFormSubmissions(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	key := c.Param("key")
+	var columns []string
+	title := ""
+	for _, f := range extractForms(share.Content) {
+		if f.Key == key {
+			title = f.Title
+			for _, field := range f.Fields {
+				columns = append(columns, field.Label)
+			}
+			break
+		}
+	}
+
+	var subs []models.FormSubmission
+	if err := models.DB.Where("share_id = ? AND form_key = ?", share.ID, key).Order("created_at").Find(&subs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load submissions: " + err.Error()})
+		return
+	}
+	if len(subs) == 0 && columns == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Form not found"})
+		return
+	}
+	known := map[string]bool{}
+	for _, col := range columns {
+		known[col] = true
+	}
+	rows := make([]map[string]string, len(subs))
+	for i := range subs {
+		rows[i] = formSubmissionValues(&subs[i])
+		if title == "" {
+			title = subs[i].FormTitle
+		}
+		extra := []string{}
+		for label := range rows[i] {
+			if !known[label] {
+				extra = append(extra, label)
+			}
+		}
+		// 同一条提交中的早期字段按名称排序，保证导出结果稳定
+		sort.Strings(extra)
+		for _, label := range extra {
+			known[label] = true
+			columns = append(columns, label)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\ufeff")
+	w := csv.NewWriter(&b)
+	_ = w.Write(append([]string{"submittedAt"}, columns...))
+	for i, row := range rows {
+		record := []string{subs[i].CreatedAt.Format(time.RFC3339)}
+		for _, col := range columns {
+			record = append(record, csvCell(row[col]))
+		}
+		_ = w.Write(record)
+	}
+	w.Flush()
+
+	name := slug.FileName(share.DocTitle, share.ID) + "-form"
+	if title != "" {
+		name = slug.FileName(title, share.ID) + "-form"
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + ".csv"}))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(b.String()))
+}
+
+// DeleteFormSubmission 所有者删除一条提交（如垃圾信息）
+func DeleteFormSubmission(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	res := models.DB.Where("id = ? AND share_id = ? AND form_key = ?", c.Param("sid"), share.ID, c.Param("key")).
+		Delete(&models.FormSubmission{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete submission: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Submission not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// readyTimeout 就绪检查中每项检查的超时
+const readyTimeout = 5 * time.Second
+
+// Healthz 存活检查：进程能处理请求即返回 200，不访问数据库与存储
+func Healthz(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Readyz 就绪检查：数据库可连接、表结构迁移已完成且资源存储可写时返回 200，否则返回 503 并列出失败项
+func Readyz(c *gin.Context) {
+	checks := gin.H{}
+	ready := true
+	check := func(name string, fn func(ctx context.Context) error) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+		defer cancel()
+		if err := fn(ctx); err != nil {
+			checks[name] = err.Error()
+			ready = false
+			return
+		}
+		checks[name] = "ok"
+	}
+
+	check("database", models.Ping)
+	check("migrations", func(context.Context) error {
+		if !models.Migrated() {
+			return errors.New("migrations not applied")
+		}
+		return nil
+	})
+	check("storage", storageWritable)
+
+	c.Header("Cache-Control", "no-store")
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": checks})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "checks": checks})
+}
+
+// storageWritable 写入并删除一个探测对象，确认资源存储可写
+func storageWritable(ctx context.Context) error {
+	if storage.Default == nil {
+		return errors.New("storage not initialized")
+	}
+	body := strconv.FormatInt(time.Now().UnixNano(), 10)
+	key := "healthz/probe"
+	if err := storage.Default.Put(ctx, key, strings.NewReader(body), int64(len(body)), "text/plain"); err != nil {
+		return err
+	}
+	return storage.Default.Delete(ctx, key)
+}
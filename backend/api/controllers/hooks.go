@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/hooks"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/gin-gonic/gin"
+)
+
+// hookShare 构造钩子事件中的分享信息
+func hookShare(c *gin.Context, share *models.Share, reused bool) *hooks.Share {
+	return &hooks.Share{
+		ID:      share.ID,
+		DocID:   share.DocID,
+		Title:   share.DocTitle,
+		Content: share.Content,
+		URL:     getBaseURL(c) + "/s/" + share.ID,
+		Status:  share.Status,
+		Tags:    share.TagList(),
+		Reused:  reused,
+	}
+}
+
+// hookUser 构造钩子事件中的用户信息
+func hookUser(userID string) *hooks.User {
+	var user models.User
+	if err := models.DB.Select("id", "username", "email").Where("id = ?", userID).First(&user).Error; err != nil {
+		return &hooks.User{ID: userID}
+	}
+	return &hooks.User{ID: user.ID, Username: user.Username, Email: user.Email}
+}
+
+// runPrePublishHooks 调用发布前钩子，钩子改写的标题、正文与标签写回 share；
+// 钩子拒绝或出错时已写入响应并返回 false
+func runPrePublishHooks(c *gin.Context, share *models.Share, reused bool) bool {
+	if !hooks.Has(hooks.PrePublish) || share.Status != models.ShareStatusPublished {
+		return true
+	}
+	ev := &hooks.Event{Type: hooks.PrePublish, User: hookUser(share.UserID), Share: hookShare(c, share, reused), IP: c.ClientIP()}
+	if err := hooks.Run(c.Request.Context(), ev); err != nil {
+		if denied, ok := hooks.IsDenied(err); ok {
+			c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Publish rejected: " + denied.Error()})
+			return false
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Publish hook failed: " + err.Error()})
+		return false
+	}
+	tags, err := normalizeTags(ev.Share.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Publish hook returned invalid tags: " + err.Error()})
+		return false
+	}
+	if title := slug.Title(ev.Share.Title); title != "" {
+		share.DocTitle = slug.Truncate(title, maxDocTitle)
+	}
+	// 加密分享的正文是密文，钩子可以拒绝发布或修改标题与标签，但不能改写正文
+	if !share.Encrypted() {
+		share.Content = ev.Share.Content
+	}
+	share.Tags = models.EncodeTags(tags)
+	return true
+}
+
+// firePostPublishHooks 在后台通知发布后钩子
+func firePostPublishHooks(c *gin.Context, share *models.Share, reused bool) {
+	if !hooks.Has(hooks.PostPublish) || share.Status != models.ShareStatusPublished {
+		return
+	}
+	hooks.Fire(&hooks.Event{Type: hooks.PostPublish, User: hookUser(share.UserID), Share: hookShare(c, share, reused), IP: c.ClientIP()})
+}
+
+// preRenderHooks 调用渲染前钩子改写阅读页正文（正文处理的 hooks 步骤）；钩子出错时返回错误，由处理流程记录日志并使用原正文
+func preRenderHooks(c *gin.Context, share *models.Share, content string) (string, error) {
+	if !hooks.Has(hooks.PreRender) {
+		return content, nil
+	}
+	s := hookShare(c, share, false)
+	s.Content = content
+	ev := &hooks.Event{Type: hooks.PreRender, Share: s, IP: c.ClientIP()}
+	if err := hooks.Run(c.Request.Context(), ev); err != nil {
+		return content, fmt.Errorf("pre-render hook for share %s: %w", share.ID, err)
+	}
+	return ev.Share.Content, nil
+}
+
+// runAuthHooks 在签发会话前调用登录钩子，返回错误时应拒绝登录
+func runAuthHooks(c *gin.Context, user *models.User, method string) error {
+	if !hooks.Has(hooks.Auth) {
+		return nil
+	}
+	return hooks.Run(c.Request.Context(), &hooks.Event{
+		Type:   hooks.Auth,
+		User:   &hooks.User{ID: user.ID, Username: user.Username, Email: user.Email},
+		Method: method,
+		IP:     c.ClientIP(),
+	})
+}
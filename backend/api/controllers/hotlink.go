@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// assetRefererAllowed 开启 assets.referer_check 时检查资源请求的 Referer：本站（含内置 HTTPS 域名与已验证的自定义域名）
+// 与 assets.allowed_referers 中的站点可以引用，其余站点的页面视为盗链；不带 Referer 的请求按 block_empty_referer 处理
+func assetRefererAllowed(c *gin.Context) bool {
+	cfg := config.Get().Assets
+	if !cfg.RefererCheck {
+		return true
+	}
+	ref := c.Request.Referer()
+	if ref == "" {
+		return !cfg.BlockEmptyReferer
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, d := range append(ownHostnames(c), config.Get().TLS.Domains...) {
+		if strings.EqualFold(d, host) {
+			return true
+		}
+	}
+	for _, r := range cfg.AllowedReferers {
+		if r == host || (strings.HasPrefix(r, "*.") && strings.HasSuffix(host, r[1:])) {
+			return true
+		}
+	}
+	return models.IsVerifiedDomain(host)
+}
+
+// ownHostnames 读者访问本服务使用的主机名（不含端口）：请求的 Host 与 getBaseURL 考虑的反向代理地址
+func ownHostnames(c *gin.Context) []string {
+	hosts := []string{}
+	for _, h := range []string{"//" + c.Request.Host, getBaseURL(c)} {
+		if u, err := url.Parse(h); err == nil && u.Hostname() != "" {
+			hosts = append(hosts, strings.ToLower(u.Hostname()))
+		}
+	}
+	return hosts
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// inviteAlphabet 邀请码字符集，去掉易混淆的 0/O、1/I/L
+const inviteAlphabet = "23456789ABCDEFGHJKMNPQRSTUVWXYZ"
+
+// inviteCodeLen 邀请码长度
+const inviteCodeLen = 10
+
+// CreateInviteRequest 生成邀请码请求
+type CreateInviteRequest struct {
+	MaxUses     *int   `json:"maxUses"`     // 可用次数，默认 1，0 不限
+	ExpiresDays int    `json:"expiresDays"` // 有效天数，0 长期有效
+	Note        string `json:"note"`
+}
+
+// normalizeInviteCode 邀请码不区分大小写，忽略首尾空白与分隔符
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
+
+// generateInviteCode 生成随机邀请码
+func generateInviteCode() string {
+	b := make([]byte, inviteCodeLen)
+	idgen.Read(b)
+	for i := range b {
+		b[i] = inviteAlphabet[int(b[i])%len(inviteAlphabet)]
+	}
+	return string(b)
+}
+
+// ListInvites 管理员查看邀请码及使用情况
+func ListInvites(c *gin.Context) {
+	items := []models.InviteCode{}
+	if err := models.DB.Order("created_at DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list invites: " + err.Error()})
+		return
+	}
+	type item struct {
+		models.InviteCode
+		Usable bool `json:"usable"`
+	}
+	list := make([]item, 0, len(items))
+	for _, i := range items {
+		list = append(list, item{InviteCode: i, Usable: i.Usable()})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"registration": config.Get().Auth.Registration,
+		"items":        list,
+	}})
+}
+
+// CreateInvite 管理员生成邀请码
+func CreateInvite(c *gin.Context) {
+	var req CreateInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	invite := &models.InviteCode{
+		Code:      generateInviteCode(),
+		CreatedBy: c.GetString("userID"),
+		MaxUses:   1,
+		Note:      strings.TrimSpace(req.Note),
+	}
+	if req.MaxUses != nil {
+		invite.MaxUses = *req.MaxUses
+	}
+	if invite.MaxUses < 0 || invite.MaxUses > 10000 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "maxUses must be between 0 and 10000"})
+		return
+	}
+	if req.ExpiresDays < 0 || req.ExpiresDays > 365 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "expiresDays must be between 0 and 365"})
+		return
+	}
+	if req.ExpiresDays > 0 {
+		t := time.Now().AddDate(0, 0, req.ExpiresDays)
+		invite.ExpiresAt = &t
+	}
+	if len([]rune(invite.Note)) > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Note must be at most 200 characters"})
+		return
+	}
+	err := models.CreateUnique(func() error { return models.DB.Create(invite).Error }, func(int) bool {
+		invite.Code = generateInviteCode()
+		return true
+	}, "invite_codes.code")
+	if errors.Is(err, models.ErrTaken) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "No invite code available, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create invite: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": invite})
+}
+
+// DeleteInvite 管理员删除邀请码，已注册的用户不受影响
+func DeleteInvite(c *gin.Context) {
+	res := models.DB.Where("code = ?", normalizeInviteCode(c.Param("code"))).Delete(&models.InviteCode{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete invite: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Invite not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,29 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/scheduler"
+	"github.com/gin-gonic/gin"
+)
+
+// ListJobs 管理员查看定时任务的配置与最近一次执行情况
+func ListJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": scheduler.List()})
+}
+
+// RunJob 管理员立即执行一次定时任务（不论是否启用）；任务在后台执行，结果通过 ListJobs 查看
+func RunJob(c *gin.Context) {
+	err := scheduler.Run(c.Param("name"))
+	switch {
+	case errors.Is(err, scheduler.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Job not found"})
+	case errors.Is(err, scheduler.ErrRunning):
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Job is already running"})
+	case err != nil:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Server is shutting down"})
+	default:
+		c.JSON(http.StatusAccepted, gin.H{"code": 0, "msg": "success"})
+	}
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/langcheck"
+	"github.com/gin-gonic/gin"
+)
+
+// GetLanguageCheck 分享所有者查看正文的拼写与语法检查报告
+func GetLanguageCheck(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"enabled": langcheck.Enabled(),
+		"report":  langcheck.Current(share),
+	}})
+}
+
+// CreateLanguageCheck 重新检查分享正文（后台进行），只生成报告，不修改内容
+func CreateLanguageCheck(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if rejectEncrypted(c, share) {
+		return
+	}
+	if !langcheck.Enabled() {
+		c.JSON(http.StatusNotImplemented, gin.H{"code": 1, "msg": "Language check is not configured"})
+		return
+	}
+	l, err := langcheck.Start(share)
+	if errors.Is(err, langcheck.ErrNoText) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to start language check: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": l})
+}
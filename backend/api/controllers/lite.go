@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// wantsLitePage 是否返回轻量阅读页：?lite=1，或浏览器开启省流量模式（Save-Data: on）且未以 ?lite=0 选择完整版
+func wantsLitePage(c *gin.Context) bool {
+	switch c.Query("lite") {
+	case "1":
+		return true
+	case "0":
+		return false
+	}
+	return strings.EqualFold(strings.TrimSpace(c.GetHeader("Save-Data")), "on")
+}
+
+// shareAssetLink 将正文中的相对资源路径改写为绝对地址：正文中的路径相对于分享，而阅读页位于 /s/<id>
+func shareAssetLink(baseURL, shareID string) func(string) string {
+	return func(dest string) string {
+		if strings.HasPrefix(dest, "assets/") {
+			return baseURL + "/api/s/" + shareID + "/" + dest
+		}
+		return dest
+	}
+}
+
+// ServeLitePage 为慢速网络返回分享的轻量阅读页（服务端渲染、样式内联、不含脚本），已写入响应时返回 true；
+// 非分享页面、未请求轻量版，或分享需要密码、仅限名单访问、不可访问、已过期时返回 false，
+// 由完整阅读页处理（包括密码输入与访问验证）
+func ServeLitePage(c *gin.Context) bool {
+	m := shareHTMLPathPattern.FindStringSubmatch(c.Request.URL.Path)
+	if m == nil {
+		return false
+	}
+	c.Writer.Header().Add("Vary", "Save-Data")
+	if !wantsLitePage(c) {
+		return false
+	}
+
+	share, err := models.FindShare(m[1])
+	if err != nil {
+		return false
+	}
+	if privateAssets(share) || share.Encrypted() || !share.Reachable() || share.IsExpired() || !share.OpenAt(time.Now()) {
+		return false
+	}
+	// 读者访问的流量计入分享所有者
+	c.Set("contentOwner", share.UserID)
+	c.Set("contentShare", share.ID)
+	if !countShareView(c, share) {
+		return true
+	}
+
+	var owner models.User
+	models.DB.Select("username").Where("id = ?", share.UserID).First(&owner)
+
+	content, _ := renderShareContent(c, share)
+	content = deliverShareContent(c, share, content)
+	baseURL := getBaseURL(c)
+	locale := middleware.Locale(c)
+	page := export.LitePage(export.LiteDoc{
+		Title:     share.DocTitle,
+		Author:    owner.Username,
+		Date:      share.UpdatedAt.Format("2006-01-02"),
+		Lang:      locale,
+		Content:   content,
+		FullURL:   baseURL + "/s/" + share.ID + "?lite=0",
+		FullLabel: i18n.T(locale, "page.full_version"),
+		Link:      shareAssetLink(baseURL, share.ID),
+		Prerender: sharePrerenderer(share),
+	})
+
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	c.Header("Cache-Control", "no-cache")
+	if shareNoIndex(share) {
+		c.Header("X-Robots-Tag", "noindex")
+	}
+	if notModified(c, weakETag([]byte(page)), time.Time{}) {
+		return true
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(page))
+	return true
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"log"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/alert"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxLockout 连续锁定时加倍后的最长锁定时长
+	maxLockout = 24 * time.Hour
+	// ipFailureWindow 按 IP 统计登录失败的时间窗口，超限后该 IP 同样被拒绝这么久
+	ipFailureWindow = 15 * time.Minute
+	// maxFailureDelay 登录失败响应的最长延迟
+	maxFailureDelay = 8 * time.Second
+)
+
+// auditLog 记录安全审计事件（结构化日志，msg 为 audit），附带请求 ID 与客户端 IP
+func auditLog(c *gin.Context, event string, args ...any) {
+	slog.Info("audit", append([]any{"event", event, "request_id", c.GetString("requestID"), "ip", c.ClientIP()}, args...)...)
+}
+
+// ipFailure 某个 IP 在当前窗口内的登录失败次数
+type ipFailure struct {
+	count int
+	since time.Time
+}
+
+// failureCounter 按键（来源 IP 等）统计 ipFailureWindow 内的失败次数，仅保存在内存中
+type failureCounter struct {
+	sync.Mutex
+	m map[string]*ipFailure
+}
+
+// ipFailures 各 IP 的登录失败次数
+var ipFailures = &failureCounter{m: map[string]*ipFailure{}}
+
+// current 返回 key 在当前窗口内的失败次数与窗口的剩余时间，窗口已结束时清除记录
+func (fc *failureCounter) current(key string) (int, time.Duration) {
+	fc.Lock()
+	defer fc.Unlock()
+	f := fc.m[key]
+	if f == nil {
+		return 0, 0
+	}
+	wait := time.Until(f.since.Add(ipFailureWindow))
+	if wait <= 0 {
+		delete(fc.m, key)
+		return 0, 0
+	}
+	return f.count, wait
+}
+
+// add 记录一次失败，返回当前窗口内的失败次数
+func (fc *failureCounter) add(key string) int {
+	now := time.Now()
+	fc.Lock()
+	defer fc.Unlock()
+	f := fc.m[key]
+	if f == nil || now.Sub(f.since) >= ipFailureWindow {
+		// 顺便清理过期的记录，避免大量来源 IP 占用内存
+		if len(fc.m) >= 10000 {
+			for k, v := range fc.m {
+				if now.Sub(v.since) >= ipFailureWindow {
+					delete(fc.m, k)
+				}
+			}
+		}
+		f = &ipFailure{since: now}
+		fc.m[key] = f
+	}
+	f.count++
+	return f.count
+}
+
+// reset 清除 key 的失败记录
+func (fc *failureCounter) reset(key string) {
+	fc.Lock()
+	delete(fc.m, key)
+	fc.Unlock()
+}
+
+// ipLoginBlocked 判断 IP 是否因登录失败过多被暂时拒绝，返回剩余等待时间
+func ipLoginBlocked(ip string) (time.Duration, bool) {
+	limit := config.Get().Auth.IPFailureLimit
+	if limit <= 0 {
+		return 0, false
+	}
+	n, wait := ipFailures.current(ip)
+	return wait, n >= limit
+}
+
+// recordIPFailure 记录一次来自 ip 的登录失败，返回当前窗口内的失败次数
+func recordIPFailure(ip string) int {
+	return ipFailures.add(ip)
+}
+
+// failureDelay 第 n 次连续失败的响应延迟：前两次不延迟，之后 1s、2s、4s……最长 maxFailureDelay
+func failureDelay(n int) time.Duration {
+	if n <= 2 {
+		return 0
+	}
+	d := time.Second << min(n-3, 10)
+	return min(d, maxFailureDelay)
+}
+
+// sleepCtx 等待 d，客户端断开时提前返回
+func sleepCtx(c *gin.Context, d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-c.Request.Context().Done():
+	}
+}
+
+// accountLocked 账号是否处于锁定期
+func accountLocked(user *models.User) bool {
+	return user.LockedUntil != nil && time.Now().Before(*user.LockedUntil)
+}
+
+// respondLocked 返回账号锁定错误
+func respondLocked(c *gin.Context, user *models.User) {
+	c.Header("Retry-After", strconv.Itoa(int(time.Until(*user.LockedUntil).Seconds())+1))
+	c.JSON(http.StatusLocked, gin.H{"code": CodeAccountLocked, "msg": "Account temporarily locked due to too many failed login attempts",
+		"data": gin.H{"lockedUntil": user.LockedUntil}})
+}
+
+// loginFailed 记录一次登录失败（user 为 nil 表示用户名不存在）：累计账号与 IP 的失败次数，
+// 达到阈值时锁定账号并发送解锁邮件，然后按失败次数延迟响应，拖慢暴力破解
+func loginFailed(c *gin.Context, user *models.User, reason string) {
+	ip := c.ClientIP()
+	alert.AuthFailed(ip)
+	n := recordIPFailure(ip)
+	if user == nil {
+		auditLog(c, "login_failed", "reason", reason)
+		sleepCtx(c, failureDelay(n))
+		return
+	}
+
+	cfg := config.Get().Auth
+	user.FailedLogins++
+	updates := map[string]interface{}{"failed_logins": user.FailedLogins}
+	locked := cfg.LockoutThreshold > 0 && user.FailedLogins >= cfg.LockoutThreshold
+	if locked {
+		d := cfg.LockoutDuration.Std() << min(user.Lockouts, 10)
+		until := time.Now().Add(min(d, maxLockout))
+		user.Lockouts++
+		user.LockedUntil = &until
+		user.FailedLogins = 0
+		updates = map[string]interface{}{"failed_logins": 0, "lockouts": user.Lockouts, "locked_until": &until}
+	}
+	if err := models.DB.Model(user).UpdateColumns(updates).Error; err != nil {
+		log.Printf("Failed to record login failure for %s: %v", user.ID, err)
+	}
+	auditLog(c, "login_failed", "user_id", user.ID, "username", user.Username, "reason", reason)
+	if locked {
+		auditLog(c, "account_locked", "user_id", user.ID, "username", user.Username, "until", user.LockedUntil, "lockouts", user.Lockouts)
+		sendUnlockEmail(c, user)
+		return
+	}
+	sleepCtx(c, failureDelay(max(user.FailedLogins, n)))
+}
+
+// loginSucceeded 登录成功后清除失败计数与锁定
+func loginSucceeded(user *models.User) {
+	if user.FailedLogins == 0 && user.Lockouts == 0 && user.LockedUntil == nil {
+		return
+	}
+	models.DB.Model(user).UpdateColumns(map[string]interface{}{"failed_logins": 0, "lockouts": 0, "locked_until": nil})
+}
+
+// sendUnlockEmail 账号被锁定时向账号邮箱发送解锁链接，未配置 SMTP 时跳过
+func sendUnlockEmail(c *gin.Context, user *models.User) {
+	if !mailer.Enabled() || user.Email == "" {
+		return
+	}
+	token, err := issueAccountToken(user, purposeUnlockAccount, maxLockout)
+	if err == nil {
+		link := getBaseURL(c) + "/api/auth/unlock?token=" + token
+		locale := userLocale(c, user)
+		err = mailer.Send(mailer.Message{
+			To:      user.Email,
+			Subject: i18n.T(locale, "email.unlock.subject"),
+			Body: i18n.T(locale, "email.unlock.body",
+				user.Username, user.LockedUntil.Format("2006-01-02 15:04:05 MST"), c.ClientIP(), link),
+		})
+	}
+	if err != nil {
+		log.Printf("Failed to send unlock email to %s: %v", user.ID, err)
+	}
+}
+
+// unlockAccount 解除账号锁定并清除失败次数
+func unlockAccount(user *models.User) error {
+	return models.DB.Model(user).UpdateColumns(map[string]interface{}{"failed_logins": 0, "locked_until": nil}).Error
+}
+
+// UnlockAccount 通过锁定通知邮件中的链接解锁账号（GET 链接直接在浏览器打开，POST 供前端调用）
+func UnlockAccount(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		var req struct {
+			Token string `json:"token"`
+		}
+		_ = c.ShouldBindJSON(&req)
+		token = req.Token
+	}
+	respond := func(status int, msg, page string) {
+		if c.Request.Method == http.MethodGet {
+			messagePage(c, status, page)
+			return
+		}
+		if status == http.StatusOK {
+			c.JSON(status, gin.H{"code": 0, "msg": "success"})
+			return
+		}
+		c.JSON(status, gin.H{"code": 1, "msg": msg})
+	}
+
+	user, err := parseAccountToken(token, purposeUnlockAccount)
+	if err != nil {
+		respond(http.StatusBadRequest, "Invalid or expired token", "page.unlock_invalid")
+		return
+	}
+	if err := unlockAccount(user); err != nil {
+		respond(http.StatusInternalServerError, "Failed to unlock account", "page.unlock_failed")
+		return
+	}
+	auditLog(c, "account_unlocked", "user_id", user.ID, "username", user.Username, "by", "email")
+	respond(http.StatusOK, "", "page.unlocked")
+}
+
+// AdminUnlockUser 管理员解除用户的登录锁定
+func AdminUnlockUser(c *gin.Context) {
+	user, ok := loadQuotaUser(c)
+	if !ok {
+		return
+	}
+	if err := unlockAccount(user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to unlock account"})
+		return
+	}
+	auditLog(c, "account_unlocked", "user_id", user.ID, "username", user.Username, "by", "admin", "admin_id", c.GetString("userID"))
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/ZeroHawkeye/siyuan-share-api/translate"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	shareHTMLPathPattern      = regexp.MustCompile(`^/s/([0-9A-Za-z_-]+)(?:/([a-z]{2,3}(?:-[a-z0-9]{2,8})?))?/?$`)
+	collectionHTMLPathPattern = regexp.MustCompile(`^/c/([0-9A-Za-z-]+)/?$`)
+	firstImagePattern         = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)`)
+	htmlTitlePattern          = regexp.MustCompile(`(?s)<title>.*?</title>`)
+	htmlLangPattern           = regexp.MustCompile(`<html lang="[^"]*"`)
+)
+
+// RenderSharePage 渲染分享页面的 index.html：按协商结果注入主题样式，
+// 并为可公开访问的分享与合集首页注入 Open Graph / Twitter Card 元信息，使链接在微信、Telegram、Discord 等平台展示富预览；
+// 分享的译文页 (/s/<id>/<语言>) 使用译文的标题与描述，有译文时列出各语言版本的 hreflang 链接；其他页面原样返回
+func RenderSharePage(c *gin.Context, page []byte) []byte {
+	m := shareHTMLPathPattern.FindStringSubmatch(c.Request.URL.Path)
+	cm := collectionHTMLPathPattern.FindStringSubmatch(c.Request.URL.Path)
+	if m == nil && cm == nil {
+		return page
+	}
+
+	applyThemeHeaders(c)
+	// 页面语言随请求语言，密码与过期提示页的标题同样按语言显示
+	locale := middleware.Locale(c)
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	page = htmlLangPattern.ReplaceAll(page, []byte(`<html lang="`+locale+`"`))
+	if cm != nil {
+		page = []byte(injectTheme(string(page), resolveTheme(c, nil), ""))
+		return renderCollectionPage(c, page, cm[1])
+	}
+	share, err := models.FindShare(m[1])
+	if err != nil {
+		return []byte(injectTheme(string(page), resolveTheme(c, nil), ""))
+	}
+	shown := variantShare(c, share)
+	page = []byte(injectTheme(string(page), resolveTheme(c, shown), customThemeURL(shown)))
+	if shareNoIndex(share) {
+		c.Header("X-Robots-Tag", "noindex")
+	}
+
+	// 受密码保护、仅限名单访问、需同意使用条款、付费阅读、试读模式、限制浏览次数、端到端加密、草稿、停用、过期或不在开放时间内的分享不暴露标题与摘要
+	if share.RequirePassword || share.Encrypted() || share.Restricted || share.Terms != "" || share.Paywall || share.Teaser || share.MaxViews > 0 || !share.Reachable() || share.IsExpired() || !share.OpenAt(time.Now()) {
+		key := ""
+		switch {
+		case share.IsExpired():
+			key = "page.share_expired"
+		case !share.OpenAt(time.Now()):
+			key = "page.share_not_open"
+		case share.RequirePassword:
+			key = "page.share_password"
+		case share.Encrypted():
+			key = "page.share_encrypted"
+		}
+		if key != "" {
+			title := "<title>" + html.EscapeString(i18n.T(locale, key)+" - "+i18n.T(locale, "page.title")) + "</title>"
+			page = htmlTitlePattern.ReplaceAllLiteral(page, []byte(title))
+		}
+		return page
+	}
+
+	baseURL := getBaseURL(c)
+	canonical := baseURL + "/s/" + share.ID
+	original := share
+	if lang := m[2]; lang != "" {
+		t := translate.Find(share.ID, lang)
+		if t == nil || t.Status != models.TranslationReady {
+			return page
+		}
+		share = translatedShare(share, t)
+		canonical += "/" + lang
+		page = []byte(strings.Replace(string(page), ` lang="`+locale+`"`, ` lang="`+lang+`"`, 1))
+	}
+	title := html.EscapeString(slug.Title(share.DocTitle))
+	summary := shareDescription(share, 160)
+	if share.TasksTotal > 0 {
+		// 项目进度类笔记在链接预览中直接展示完成情况
+		summary = fmt.Sprintf("进度 %d/%d · %s", share.TasksDone, share.TasksTotal, summary)
+	}
+	desc := html.EscapeString(summary)
+	image := shareCoverImage(share, baseURL)
+
+	page = injectMeta(page, title, desc, canonical, image, shareNoIndex(share))
+	if config.Get().Embed.Enabled {
+		page = []byte(strings.Replace(string(page), "</head>", "  "+oEmbedDiscovery(baseURL, canonical, title)+"\n  </head>", 1))
+	}
+	return injectAlternates(page, baseURL, original)
+}
+
+// injectAlternates 分享有译文时为原文与各译文注入 hreflang 链接，原文同时作为 x-default
+func injectAlternates(page []byte, baseURL string, share *models.Share) []byte {
+	langs := models.ReadyTranslationLangs(share.ID)
+	if len(langs) == 0 {
+		return page
+	}
+	href := html.EscapeString(baseURL + "/s/" + share.ID)
+	link := func(lang, href string) string {
+		return `<link rel="alternate" hreflang="` + lang + `" href="` + href + `" />`
+	}
+	var links []string
+	if lang := translate.Detect(share.Content); lang != "" && !slices.Contains(langs, lang) {
+		links = append(links, link(lang, href))
+	}
+	links = append(links, link("x-default", href))
+	for _, lang := range langs {
+		links = append(links, link(lang, href+"/"+lang))
+	}
+	return []byte(strings.Replace(string(page), "</head>", "  "+strings.Join(links, "\n    ")+"\n  </head>", 1))
+}
+
+// PageNotModified 为渲染后的页面设置 ETag，读者缓存的页面仍然有效时写入 304 并返回 true
+func PageNotModified(c *gin.Context, page []byte) bool {
+	return notModified(c, weakETag(page), time.Time{})
+}
+
+// injectMeta 以标题、描述、规范链接与 Open Graph / Twitter Card 元信息替换页面的 <title>，title 与 desc 须已转义
+func injectMeta(page []byte, title, desc, canonical, image string, noindex bool) []byte {
+	var b strings.Builder
+	b.WriteString("<title>" + title + "</title>\n")
+	b.WriteString(`    <meta name="description" content="` + desc + `" />` + "\n")
+	b.WriteString(`    <link rel="canonical" href="` + html.EscapeString(canonical) + `" />` + "\n")
+	if noindex {
+		b.WriteString(`    <meta name="robots" content="noindex" />` + "\n")
+	}
+	b.WriteString(`    <meta property="og:type" content="article" />` + "\n")
+	b.WriteString(`    <meta property="og:title" content="` + title + `" />` + "\n")
+	b.WriteString(`    <meta property="og:description" content="` + desc + `" />` + "\n")
+	b.WriteString(`    <meta property="og:url" content="` + html.EscapeString(canonical) + `" />` + "\n")
+	cardType := "summary"
+	if image != "" {
+		b.WriteString(`    <meta property="og:image" content="` + html.EscapeString(image) + `" />` + "\n")
+		b.WriteString(`    <meta name="twitter:image" content="` + html.EscapeString(image) + `" />` + "\n")
+		cardType = "summary_large_image"
+	}
+	b.WriteString(`    <meta name="twitter:card" content="` + cardType + `" />` + "\n")
+	b.WriteString(`    <meta name="twitter:title" content="` + title + `" />` + "\n")
+	b.WriteString(`    <meta name="twitter:description" content="` + desc + `" />`)
+
+	out := string(page)
+	if htmlTitlePattern.MatchString(out) {
+		out = htmlTitlePattern.ReplaceAllLiteralString(out, b.String())
+	} else {
+		out = strings.Replace(out, "</head>", b.String()+"\n</head>", 1)
+	}
+	return []byte(out)
+}
+
+// renderCollectionPage 为合集首页注入标题、描述与封面的 Open Graph 元信息
+func renderCollectionPage(c *gin.Context, page []byte, collectionSlug string) []byte {
+	col, shares, err := publicCollection(collectionSlug)
+	if err != nil {
+		return page
+	}
+	baseURL := getBaseURL(c)
+	desc := col.Description
+	if desc == "" {
+		titles := make([]string, 0, len(shares))
+		for _, s := range shares {
+			titles = append(titles, s.DocTitle)
+		}
+		desc = strings.Join(titles, " · ")
+	}
+	if r := []rune(desc); len(r) > 160 {
+		desc = string(r[:160]) + "…"
+	}
+	return injectMeta(page, html.EscapeString(slug.Title(col.Title)), html.EscapeString(desc), baseURL+"/c/"+col.Slug,
+		collectionCover(col, shares, baseURL), config.Get().Content.NoIndex)
+}
+
+// shareCoverImage 选取分享封面：正文第一张图片，否则使用 OG_DEFAULT_IMAGE 配置的默认封面
+func shareCoverImage(share *models.Share, baseURL string) string {
+	if m := firstImagePattern.FindStringSubmatch(share.Content); m != nil {
+		src := m[1]
+		switch {
+		case strings.HasPrefix(src, "http://"), strings.HasPrefix(src, "https://"):
+			return src
+		case strings.HasPrefix(strings.TrimPrefix(src, "/"), "assets/"):
+			p := strings.TrimPrefix(src, "/")
+			// 按 assetURL 生成地址，开启资源签名时社交平台同样可以抓取预览图
+			if a, err := models.FindAsset(share.ID, p); err == nil && a != nil {
+				return assetURLWithBase(baseURL, share, p, a.Hash)
+			}
+			return baseURL + "/api/s/" + share.ID + "/" + p
+		}
+	}
+	if def := config.Get().Content.OGDefaultImage; def != "" {
+		if strings.HasPrefix(def, "/") {
+			return baseURL + def
+		}
+		return def
+	}
+	return ""
+}
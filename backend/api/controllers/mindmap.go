@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/mindmap"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// fencedBlock 正文中的围栏代码块
+type fencedBlock struct {
+	Index  int    // 同类代码块中的序号，从 1 开始
+	Line   int    // 代码块起始行（从 1 开始），阅读页据此对应渲染出的代码块
+	Lang   string // 代码块语言（小写）
+	Source string // 代码块内容
+}
+
+// extractFencedBlocks 提取正文中语言满足 match 的围栏代码块
+func extractFencedBlocks(content string, match func(lang string) bool) []fencedBlock {
+	var blocks []fencedBlock
+	lines := strings.Split(content, "\n")
+	fence, lang, start := "", "", -1
+	var body []string
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				if start >= 0 {
+					blocks = append(blocks, fencedBlock{Index: len(blocks) + 1, Line: start + 1, Lang: lang, Source: strings.Join(body, "\n")})
+				}
+				fence, lang, start, body = "", "", -1, nil
+				continue
+			}
+			if start >= 0 {
+				body = append(body, line)
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			lang = ""
+			if info := strings.Fields(strings.TrimLeft(trimmed, fence[:1])); len(info) > 0 {
+				lang = strings.ToLower(info[0])
+			}
+			if match(lang) {
+				start = i
+			}
+		}
+	}
+	return blocks
+}
+
+// extractMindmaps 提取正文中的思维导图代码块（```mindmap 或 ```markmap）
+func extractMindmaps(content string) []fencedBlock {
+	return extractFencedBlocks(content, func(lang string) bool {
+		return lang == "mindmap" || lang == "markmap"
+	})
+}
+
+// ListShareMindmaps 返回正文中思维导图的节点树与 SVG 地址，供阅读页交互渲染
+func ListShareMindmaps(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	base := getBaseURL(c) + "/api/s/" + share.ID + "/mindmaps/"
+	items := []gin.H{}
+	content, _ := renderShareContent(c, share)
+	for _, b := range extractMindmaps(content) {
+		entry := mindmap.Load(b.Source, share.DocTitle)
+		item := gin.H{"index": b.Index, "line": b.Line, "hash": entry.Hash}
+		if entry.Err != nil {
+			item["error"] = entry.Err.Error()
+		} else {
+			item["root"] = entry.Root
+			item["nodes"] = entry.Root.Count()
+			item["svgUrl"] = base + entry.Hash + ".svg"
+		}
+		items = append(items, item)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// ServeMindmap 输出服务端渲染的思维导图：<hash>.svg 为图片，<hash>.json 为节点树。
+// 与白板绘图一样以图片方式嵌入，因此不校验访问密码；哈希须属于该分享的正文
+func ServeMindmap(c *gin.Context) {
+	file := c.Param("file")
+	dot := strings.LastIndexByte(file, '.')
+	if dot <= 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Mind map not found"})
+		return
+	}
+	hash, ext := file[:dot], file[dot+1:]
+
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	if share.Status == models.ShareStatusDisabled && !share.InViewGrace() {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Share is disabled"})
+		return
+	}
+	if share.IsExpired() {
+		c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Share has expired"})
+		return
+	}
+
+	var entry *mindmap.Entry
+	content, _ := renderShareContent(c, &share)
+	for _, b := range extractMindmaps(content) {
+		if mindmap.Hash(b.Source) == hash {
+			entry = mindmap.Load(b.Source, share.DocTitle)
+			break
+		}
+	}
+	if entry == nil || entry.Err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Mind map not found"})
+		return
+	}
+
+	// 内容由哈希确定，标题变化时根节点文字可能不同，因此 ETag 带上标题
+	etag := `"` + hash + "-" + mindmap.Hash(share.DocTitle) + `"`
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	switch ext {
+	case "svg":
+		c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Data(http.StatusOK, "image/svg+xml; charset=utf-8", entry.SVG())
+	case "json":
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"hash": entry.Hash, "root": entry.Root}})
+	default:
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Mind map not found"})
+	}
+}
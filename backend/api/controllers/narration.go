@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/tts"
+	"github.com/gin-gonic/gin"
+)
+
+// narrationPayload 阅读页的朗读音频，未生成或正文已变化时为 nil
+func narrationPayload(c *gin.Context, share *models.Share) gin.H {
+	if !tts.Enabled() {
+		return nil
+	}
+	n := tts.Current(share)
+	if n == nil {
+		return nil
+	}
+	asset, err := models.FindAsset(share.ID, n.AssetPath)
+	if err != nil || asset == nil {
+		return nil
+	}
+	return gin.H{"url": assetURL(c, share, asset.Path, asset.Hash), "contentType": asset.ContentType}
+}
+
+// autoNarrate 开启 tts.auto 时为发布的分享生成朗读音频，正文未变化时沿用已有音频
+func autoNarrate(share *models.Share) {
+	if !config.Get().TTS.Auto || !tts.Enabled() || share.Status != models.ShareStatusPublished {
+		return
+	}
+	if _, err := tts.Generate(share); err != nil && !errors.Is(err, tts.ErrNoText) && !errors.Is(err, tts.ErrTextTooLong) {
+		log.Printf("narration for share %s not started: %v", share.ID, err)
+	}
+}
+
+// GetNarration 分享所有者查看朗读音频的生成状态
+func GetNarration(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	data := gin.H{"enabled": tts.Enabled(), "narration": models.FindNarration(share.ID)}
+	if p := narrationPayload(c, share); p != nil {
+		data["url"] = p["url"]
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": data})
+}
+
+// CreateNarration 为分享生成朗读音频（后台进行），正文未变化时直接返回已有音频
+func CreateNarration(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if rejectEncrypted(c, share) {
+		return
+	}
+	if !tts.Enabled() {
+		c.JSON(http.StatusNotImplemented, gin.H{"code": 1, "msg": "Text-to-speech is not configured"})
+		return
+	}
+	n, err := tts.Generate(share)
+	if errors.Is(err, tts.ErrNoText) || errors.Is(err, tts.ErrTextTooLong) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to generate narration: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": n})
+}
+
+// DeleteNarration 删除分享的朗读音频
+func DeleteNarration(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if err := tts.Remove(share.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete narration: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/translate"
+	"github.com/gin-gonic/gin"
+)
+
+// mimeMarkdown Markdown 原文的媒体类型
+const mimeMarkdown = "text/markdown"
+
+// shareFormat 按 Accept 请求头选择分享链接的表示：阅读页（HTML）、与 GET /api/s/:id 相同的 JSON，
+// 或开启 content.raw_markdown 时的 Markdown 原文；未指定或同时接受多种时以阅读页优先
+func shareFormat(c *gin.Context) string {
+	offered := []string{gin.MIMEHTML, gin.MIMEJSON}
+	if config.Get().Content.RawMarkdown {
+		offered = append(offered, mimeMarkdown)
+	}
+	return c.NegotiateFormat(offered...)
+}
+
+// ServeNegotiatedShare 分享链接 /s/<id>（及译文页 /s/<id>/<语言>）按内容协商返回 JSON 或 Markdown，
+// 使分享链接可直接用于脚本；已写入响应时返回 true，请求阅读页时返回 false
+func ServeNegotiatedShare(c *gin.Context) bool {
+	m := shareHTMLPathPattern.FindStringSubmatch(c.Request.URL.Path)
+	if m == nil {
+		return false
+	}
+	c.Writer.Header().Add("Vary", "Accept")
+	format := shareFormat(c)
+	if format != gin.MIMEJSON && format != mimeMarkdown {
+		return false
+	}
+
+	c.Params = append(c.Params, gin.Param{Key: "id", Value: m[1]})
+	if m[2] != "" {
+		c.Params = append(c.Params, gin.Param{Key: "lang", Value: m[2]})
+	}
+	switch {
+	case format == gin.MIMEJSON && m[2] != "":
+		GetShareTranslation(c)
+	case format == gin.MIMEJSON:
+		GetShare(c)
+	default:
+		serveShareMarkdown(c, m[2])
+	}
+	return true
+}
+
+// serveShareMarkdown 以 Markdown 原文返回分享（lang 非空时返回该语言的译文），正文经过与阅读页相同的渲染前处理，
+// 资源与块引用链接改写为绝对地址；访问校验与 GET /api/s/:id 相同
+func serveShareMarkdown(c *gin.Context, lang string) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	modified := share.ContentModified
+	if lang != "" {
+		t := translate.Find(share.ID, lang)
+		if t == nil || t.Status != models.TranslationReady {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Translation not found"})
+			return
+		}
+		if t.UpdatedAt.After(modified) {
+			modified = t.UpdatedAt
+		}
+		share = translatedShare(share, t)
+	}
+	if !countShareView(c, share) {
+		return
+	}
+
+	var owner models.User
+	models.DB.Select("username").Where("id = ?", share.UserID).First(&owner)
+
+	content, _ := renderShareContent(c, share)
+	content = deliverShareContent(c, share, content)
+	baseURL := getBaseURL(c)
+	url := baseURL + "/s/" + share.ID
+	if lang != "" {
+		url += "/" + lang
+	}
+	doc := export.Markdown(export.MarkdownDoc{
+		Title:   share.DocTitle,
+		Date:    share.UpdatedAt.Format("2006-01-02"),
+		Content: content,
+		Info:    export.BundleInfo{Author: owner.Username, URL: url},
+		Link:    shareAssetLink(baseURL, share.ID),
+	})
+
+	c.Header("Cache-Control", "private, no-cache")
+	if shareNoIndex(share) {
+		c.Header("X-Robots-Tag", "noindex")
+	}
+	if notModified(c, weakETag([]byte(doc)), modified) {
+		return
+	}
+	c.Data(http.StatusOK, mimeMarkdown+"; charset=utf-8", []byte(doc))
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationSettingsRequest 更新邮件通知偏好请求（整体替换）
+type NotificationSettingsRequest struct {
+	FirstView    bool `json:"firstView"`
+	ViewsEvery   int  `json:"viewsEvery" binding:"min=0,max=1000000"`
+	ExpiringDays int  `json:"expiringDays" binding:"min=0,max=30"`
+	Comments     bool `json:"comments"`
+}
+
+// notificationSettingsData 通知偏好的返回数据，附带邮件能否送达：站点未配置 SMTP 或邮箱未验证时不会发送
+func notificationSettingsData(userID string, s *models.NotificationSettings) gin.H {
+	var user models.User
+	models.DB.Select("email", "email_verified").Where("id = ?", userID).First(&user)
+	return gin.H{
+		"firstView":     s.FirstView,
+		"viewsEvery":    s.ViewsEvery,
+		"expiringDays":  s.ExpiringDays,
+		"comments":      s.Comments,
+		"emailEnabled":  mailer.Enabled(),
+		"emailVerified": user.Email != "" && user.EmailVerified,
+	}
+}
+
+// GetNotificationSettings 获取当前用户的邮件通知偏好，未设置时全部关闭
+func GetNotificationSettings(c *gin.Context) {
+	userID := c.GetString("userID")
+	settings, err := models.FindNotificationSettings(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load notification settings: " + err.Error()})
+		return
+	}
+	if settings == nil {
+		settings = &models.NotificationSettings{UserID: userID}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": notificationSettingsData(userID, settings)})
+}
+
+// UpdateNotificationSettings 更新当前用户的邮件通知偏好；同时记录当前站点地址，用于定时发送的到期提醒中的链接
+func UpdateNotificationSettings(c *gin.Context) {
+	userID := c.GetString("userID")
+	var req NotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	settings := &models.NotificationSettings{
+		UserID:       userID,
+		FirstView:    req.FirstView,
+		ViewsEvery:   req.ViewsEvery,
+		ExpiringDays: req.ExpiringDays,
+		Comments:     req.Comments,
+		BaseURL:      getBaseURL(c),
+		UpdatedAt:    time.Now(),
+	}
+	if err := models.DB.Save(settings).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save notification settings: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": notificationSettingsData(userID, settings)})
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/mindmap"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+	"github.com/gin-gonic/gin"
+)
+
+// GetOfflineManifest 离线阅读清单：阅读页的 Service Worker 据此预缓存资源、白板绘图、思维导图与外部渲染图片，
+// 并比较 version 判断是否需要重新下载。version 只取决于内容与资源，不受签名地址的过期时间影响；
+// 阅读页数据 data 请求时会计入浏览，不在预缓存列表 urls 中，由 Service Worker 缓存阅读页自身的请求
+func GetOfflineManifest(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var assets []models.Asset
+	if err := models.DB.Select("path", "hash").Where("share_id = ?", share.ID).Order("path").Find(&assets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list assets: " + err.Error()})
+		return
+	}
+
+	baseURL := getBaseURL(c)
+	apiBase := baseURL + "/api/s/" + share.ID + "/"
+	h := sha256.New()
+	add := func(parts ...string) {
+		for _, p := range parts {
+			h.Write([]byte(p))
+			h.Write([]byte{0})
+		}
+	}
+	add(share.ContentHash, strconv.FormatInt(share.ContentModified.UnixNano(), 10))
+
+	urls := []string{}
+	for _, a := range assets {
+		add("asset", a.Path, a.Hash)
+		urls = append(urls, assetURLWithBase(baseURL, share, a.Path, a.Hash))
+	}
+	for _, d := range share.DrawingList() {
+		add("drawing", d.ID)
+		urls = append(urls, apiBase+"drawings/"+d.ID+".svg")
+	}
+	content, _ := renderShareContent(c, share)
+	for _, b := range extractMindmaps(content) {
+		if entry := mindmap.Load(b.Source, share.DocTitle); entry.Err == nil {
+			add("mindmap", entry.Hash)
+			urls = append(urls, apiBase+"mindmaps/"+entry.Hash+".svg")
+		}
+	}
+	for _, b := range extractRenderBlocks(content) {
+		hash := renderer.Hash(b.Source)
+		add("render", hash)
+		urls = append(urls, apiBase+"renders/"+hash)
+	}
+	version := hex.EncodeToString(h.Sum(nil))[:16]
+
+	c.Header("Cache-Control", "private, no-cache")
+	if notModified(c, `"`+version+`"`, share.ContentModified) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"shareId":   share.ID,
+		"version":   version,
+		"updatedAt": share.ContentModified,
+		"page":      baseURL + "/s/" + share.ID,
+		"data":      baseURL + "/api/s/" + share.ID,
+		"urls":      urls,
+	}})
+}
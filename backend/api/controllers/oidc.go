@@ -0,0 +1,537 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// oidcProvider 第三方登录提供方配置，来自 oidc.providers（或 OIDC_PROVIDERS 与 OIDC_<NAME>_* 环境变量）：
+// client_id / client_secret（必填）、issuer（通用 OIDC 必填，google 默认 https://accounts.google.com）、
+// scopes（可选）、display_name（可选）
+type oidcProvider struct {
+	Name         string
+	DisplayName  string
+	ClientID     string
+	ClientSecret string
+	Issuer       string
+	Scopes       []string
+	GitHub       bool // GitHub 仅支持 OAuth2，用户信息走 REST API
+
+	mu          sync.Mutex
+	authURL     string
+	tokenURL    string
+	userInfoURL string
+}
+
+// oidcUserInfo 提供方返回的用户信息（已归一化）
+type oidcUserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+const oidcStateCookie = "oidc_state"
+
+var (
+	oidcProvidersOnce sync.Once
+	oidcProviders     map[string]*oidcProvider
+	oidcHTTPClient    = &http.Client{Timeout: 15 * time.Second}
+)
+
+// clientSecret 使用当前配置中的客户端密钥，重新加载配置后轮换的密钥立即生效
+func (p *oidcProvider) clientSecret() string {
+	if cfg, ok := config.Get().OIDC.Providers[p.Name]; ok && cfg.ClientSecret != "" {
+		return cfg.ClientSecret
+	}
+	return p.ClientSecret
+}
+
+// loadOIDCProviders 从配置加载已启用的提供方
+func loadOIDCProviders() map[string]*oidcProvider {
+	oidcProvidersOnce.Do(func() {
+		oidcProviders = map[string]*oidcProvider{}
+		for name, cfg := range config.Get().OIDC.Providers {
+			p := &oidcProvider{
+				Name:         name,
+				DisplayName:  cfg.DisplayName,
+				ClientID:     cfg.ClientID,
+				ClientSecret: cfg.ClientSecret,
+				Issuer:       cfg.Issuer,
+				Scopes:       cfg.Scopes,
+			}
+			switch name {
+			case "github":
+				p.GitHub = true
+				p.authURL = "https://github.com/login/oauth/authorize"
+				p.tokenURL = "https://github.com/login/oauth/access_token"
+				p.userInfoURL = "https://api.github.com/user"
+				if len(p.Scopes) == 0 {
+					p.Scopes = []string{"read:user", "user:email"}
+				}
+			case "google":
+				if p.Issuer == "" {
+					p.Issuer = "https://accounts.google.com"
+				}
+			}
+			if p.DisplayName == "" {
+				p.DisplayName = name
+			}
+			if len(p.Scopes) == 0 {
+				p.Scopes = []string{"openid", "email", "profile"}
+			}
+			if p.ClientID == "" || (!p.GitHub && p.Issuer == "") {
+				log.Printf("OIDC provider %q is missing client id or issuer, skipped", name)
+				continue
+			}
+			oidcProviders[name] = p
+		}
+	})
+	return oidcProviders
+}
+
+// endpoints 返回授权、令牌与用户信息端点，通用 OIDC 通过 discovery 文档获取并缓存
+func (p *oidcProvider) endpoints(ctx context.Context) (string, string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.authURL != "" && p.tokenURL != "" {
+		return p.authURL, p.tokenURL, p.userInfoURL, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return "", "", "", err
+	}
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", "", fmt.Errorf("discovery failed: %s", resp.Status)
+	}
+	var doc struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return "", "", "", err
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.UserinfoEndpoint == "" {
+		return "", "", "", errors.New("discovery document is incomplete")
+	}
+	p.authURL, p.tokenURL, p.userInfoURL = doc.AuthorizationEndpoint, doc.TokenEndpoint, doc.UserinfoEndpoint
+	return p.authURL, p.tokenURL, p.userInfoURL, nil
+}
+
+// oidcRedirectURI 回调地址，可通过 OIDC_REDIRECT_BASE 固定对外地址
+func oidcRedirectURI(c *gin.Context, provider string) string {
+	base := config.Get().OIDC.RedirectBase
+	if base == "" {
+		base = getBaseURL(c)
+	}
+	return base + "/api/auth/oidc/" + provider + "/callback"
+}
+
+// ListOIDCProviders 列出已启用的第三方登录方式
+func ListOIDCProviders(c *gin.Context) {
+	providers := loadOIDCProviders()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := []gin.H{}
+	for _, name := range names {
+		items = append(items, gin.H{"name": name, "displayName": providers[name].DisplayName})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// OIDCLogin 跳转到提供方授权页（state + PKCE）
+func OIDCLogin(c *gin.Context) {
+	p, ok := loadOIDCProviders()[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Unknown login provider"})
+		return
+	}
+	authURL, _, _, err := p.endpoints(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Login provider unavailable: " + err.Error()})
+		return
+	}
+
+	state := randHex(16)
+	verifier := randHex(32)
+	challenge := sha256.Sum256([]byte(verifier))
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state+"."+verifier, 600, "/api/auth/oidc", "", c.Request.TLS != nil, true)
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.ClientID)
+	q.Set("redirect_uri", oidcRedirectURI(c, p.Name))
+	q.Set("scope", strings.Join(p.Scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	q.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(authURL, "?") {
+		sep = "&"
+	}
+	c.Redirect(http.StatusFound, authURL+sep+q.Encode())
+}
+
+// OIDCCallback 处理授权回调：换取令牌、获取用户信息、按已验证邮箱关联账号并签发会话 JWT
+// 结果通过 URL fragment 回传前端（#oidc_token= 或 #oidc_error=），避免令牌出现在服务端日志中
+func OIDCCallback(c *gin.Context) {
+	fail := func(msg string) {
+		c.Redirect(http.StatusFound, "/#oidc_error="+url.QueryEscape(msg))
+	}
+
+	p, ok := loadOIDCProviders()[c.Param("provider")]
+	if !ok {
+		fail("Unknown login provider")
+		return
+	}
+	if e := c.Query("error"); e != "" {
+		fail("Authorization denied: " + e)
+		return
+	}
+
+	cookie, _ := c.Cookie(oidcStateCookie)
+	c.SetCookie(oidcStateCookie, "", -1, "/api/auth/oidc", "", c.Request.TLS != nil, true)
+	state, verifier, found := strings.Cut(cookie, ".")
+	if !found || state == "" || state != c.Query("state") {
+		fail("Invalid login state, please try again")
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		fail("Missing authorization code")
+		return
+	}
+
+	info, err := p.exchange(c, code, verifier)
+	if err != nil {
+		log.Printf("OIDC %s login failed: %v", p.Name, err)
+		fail("Login with provider failed")
+		return
+	}
+
+	user, err := resolveOIDCUser(p.Name, info)
+	if err != nil {
+		fail(err.Error())
+		return
+	}
+	// 启用两步验证的账号仍须输入验证码或恢复码：回传限时令牌，由登录页提交到 OIDCTwoFactor 后才签发会话
+	if user.TOTPEnabled {
+		pending, err := issueAccountToken(user, purposeOIDCTwoFactor+":"+p.Name, oidcTwoFactorTTL)
+		if err != nil {
+			fail("Failed to sign token")
+			return
+		}
+		c.Redirect(http.StatusFound, "/#oidc_2fa="+url.QueryEscape(pending)+"&oidc_provider="+url.QueryEscape(p.Name))
+		return
+	}
+
+	if err := runAuthHooks(c, user, "oidc:"+p.Name); err != nil {
+		fail("Login denied: " + err.Error())
+		return
+	}
+
+	token, err := issueSessionToken(c, user, "oidc:"+p.Name)
+	if err != nil {
+		fail("Failed to sign token")
+		return
+	}
+	c.Redirect(http.StatusFound, "/#oidc_token="+url.QueryEscape(token))
+}
+
+// oidcTwoFactorTTL 第三方登录后输入两步验证码的时限
+const oidcTwoFactorTTL = 5 * time.Minute
+
+// OIDCTwoFactorRequest 第三方登录的两步验证
+type OIDCTwoFactorRequest struct {
+	Token string `json:"token" binding:"required"` // 回调地址中的 oidc_2fa
+	OTP   string `json:"otp" binding:"required"`   // 验证码或恢复码
+}
+
+// OIDCTwoFactor 完成启用了两步验证的账号的第三方登录：校验回调签发的限时令牌与第二因素，通过后签发会话 JWT；
+// 验证码错误与密码登录一样计入失败次数并可能锁定账号
+func OIDCTwoFactor(c *gin.Context) {
+	var req OIDCTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if wait, blocked := ipLoginBlocked(c.ClientIP()); blocked {
+		c.Header("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many failed login attempts, please try again later"})
+		return
+	}
+	p, ok := loadOIDCProviders()[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Unknown login provider"})
+		return
+	}
+	user, err := parseAccountToken(req.Token, purposeOIDCTwoFactor+":"+p.Name)
+	if err != nil || !user.TOTPEnabled {
+		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Login session expired, please sign in again"})
+		return
+	}
+	if accountLocked(user) {
+		respondLocked(c, user)
+		return
+	}
+	if !user.IsActive {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Account disabled"})
+		return
+	}
+	if !verifySecondFactor(user, req.OTP) {
+		loginFailed(c, user, "bad_otp")
+		if accountLocked(user) {
+			respondLocked(c, user)
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"code": CodeTwoFactorRequired, "msg": "Invalid two-factor code"})
+		return
+	}
+	loginSucceeded(user)
+
+	if err := runAuthHooks(c, user, "oidc:"+p.Name); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Login denied: " + err.Error()})
+		return
+	}
+	token, err := issueSessionToken(c, user, "oidc:"+p.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to sign token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"token": token,
+		"user":  gin.H{"id": user.ID, "username": user.Username, "email": user.Email},
+	}})
+}
+
+// exchange 使用授权码换取 access token 并获取用户信息
+func (p *oidcProvider) exchange(c *gin.Context, code, verifier string) (*oidcUserInfo, error) {
+	ctx := c.Request.Context()
+	_, tokenURL, userInfoURL, err := p.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", oidcRedirectURI(c, p.Name))
+	form.Set("client_id", p.ClientID)
+	form.Set("client_secret", p.clientSecret())
+	form.Set("code_verifier", verifier)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := oidcDoJSON(req, &tok); err != nil {
+		return nil, err
+	}
+	if tok.AccessToken == "" {
+		return nil, fmt.Errorf("token exchange failed: %s", tok.Error)
+	}
+
+	if p.GitHub {
+		return githubUserInfo(ctx, tok.AccessToken)
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok.AccessToken)
+	var claims struct {
+		Sub               string `json:"sub"`
+		Email             string `json:"email"`
+		EmailVerified     any    `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+		Name              string `json:"name"`
+	}
+	if err := oidcDoJSON(req, &claims); err != nil {
+		return nil, err
+	}
+	if claims.Sub == "" {
+		return nil, errors.New("userinfo response has no subject")
+	}
+	username := claims.PreferredUsername
+	if username == "" {
+		username = claims.Name
+	}
+	// 部分提供方将 email_verified 返回为字符串
+	verified := claims.EmailVerified == true || claims.EmailVerified == "true"
+	return &oidcUserInfo{Subject: claims.Sub, Email: claims.Email, EmailVerified: verified, Username: username}, nil
+}
+
+// githubUserInfo GitHub 用户信息，邮箱取已验证的主邮箱
+func githubUserInfo(ctx context.Context, accessToken string) (*oidcUserInfo, error) {
+	get := func(u string, out any) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+accessToken)
+		req.Header.Set("Accept", "application/vnd.github+json")
+		return oidcDoJSON(req, out)
+	}
+
+	var u struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	}
+	if err := get("https://api.github.com/user", &u); err != nil {
+		return nil, err
+	}
+	if u.ID == 0 {
+		return nil, errors.New("github user response has no id")
+	}
+	info := &oidcUserInfo{Subject: fmt.Sprint(u.ID), Username: u.Login}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := get("https://api.github.com/user/emails", &emails); err == nil {
+		for _, e := range emails {
+			if e.Primary && e.Verified {
+				info.Email, info.EmailVerified = e.Email, true
+				break
+			}
+		}
+	}
+	return info, nil
+}
+
+func oidcDoJSON(req *http.Request, out any) error {
+	resp, err := oidcHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", req.Method, req.URL.Host, resp.Status)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// resolveOIDCUser 查找或创建本地账号：
+// 1) 已绑定的第三方身份直接登录；2) 已验证邮箱与现有账号一致时自动关联；3) 否则创建新账号
+// 提供方未验证的邮箱不会用于关联，防止通过第三方伪造邮箱接管账号；本地账号的邮箱尚未验证时同样不关联，
+// 否则他人可先用受害者的邮箱注册（不验证），待受害者第三方登录后以自己设置的密码登录同一账号
+func resolveOIDCUser(provider string, info *oidcUserInfo) (*models.User, error) {
+	var user models.User
+	var identity models.UserIdentity
+	err := models.DB.Where("provider = ? AND subject = ?", provider, info.Subject).First(&identity).Error
+	if err == nil {
+		if err := models.DB.Where("id = ?", identity.UserID).First(&user).Error; err != nil {
+			return nil, errors.New("Linked account not found")
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errors.New("Failed to query account")
+	} else {
+		if !info.EmailVerified || info.Email == "" {
+			return nil, errors.New("A verified email is required to sign in with this provider")
+		}
+		err := models.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("LOWER(email) = LOWER(?)", info.Email).First(&user).Error; err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					return err
+				}
+				if !config.Get().OIDC.AutoRegister || config.Get().Auth.Registration != "open" {
+					return errors.New("No account is registered with this email")
+				}
+				user = models.User{
+					ID:            "user_" + randHex(16),
+					Username:      uniqueUsername(tx, info.Username, info.Email),
+					Email:         info.Email,
+					IsActive:      true,
+					EmailVerified: true,
+				}
+				if err := tx.Create(&user).Error; err != nil {
+					return err
+				}
+			} else if !user.EmailVerified {
+				return errors.New("An account with this email exists but its email is not verified; sign in with your password and verify the email first")
+			}
+			return tx.Create(&models.UserIdentity{
+				ID:       "idt_" + randHex(12),
+				UserID:   user.ID,
+				Provider: provider,
+				Subject:  info.Subject,
+				Email:    info.Email,
+			}).Error
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !user.IsActive {
+		return nil, errors.New("User inactive")
+	}
+	return &user, nil
+}
+
+var usernameInvalidChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// uniqueUsername 根据提供方用户名或邮箱前缀生成不冲突的用户名
+func uniqueUsername(tx *gorm.DB, preferred, email string) string {
+	base := usernameInvalidChars.ReplaceAllString(preferred, "")
+	if len(base) < 3 {
+		base = usernameInvalidChars.ReplaceAllString(strings.SplitN(email, "@", 2)[0], "")
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+	if len(base) > 80 {
+		base = base[:80]
+	}
+	candidate := base
+	for i := 0; i < 10; i++ {
+		var count int64
+		tx.Model(&models.User{}).Unscoped().Where("username = ?", candidate).Count(&count)
+		if count == 0 {
+			return candidate
+		}
+		candidate = base + "_" + randHex(2)
+	}
+	return base + "_" + randHex(6)
+}
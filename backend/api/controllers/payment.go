@@ -0,0 +1,324 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// purposeSharePaid 付费阅读订单支付后签发的解锁令牌，主体为订单 ID
+const purposeSharePaid = "share_paid"
+
+const (
+	maxPaywallPriceLength = 32             // 价格文字的最大字符数
+	maxPaymentWebhookBody = 64 << 10       // 支付回调请求体上限
+	paymentOrderTTL       = 24 * time.Hour // 超过该时间仍未支付的订单不再接受回调
+)
+
+// donationNamePattern Ko-fi 与爱发电的用户名
+var donationNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// paymentsEnabled 是否配置了支付回调密钥；未配置时不能开启付费阅读
+func paymentsEnabled() bool {
+	return config.Get().Payment.WebhookSecret != ""
+}
+
+// paywallUnlocked 读者能否阅读付费分享：分享者本人，或持有有效解锁令牌（X-Share-Unlock 请求头或 unlock 查询参数）
+// 且订单仍为已支付（未退款）的读者
+func paywallUnlocked(c *gin.Context, share *models.Share) bool {
+	if userID := middleware.IdentifyUser(c); userID != "" && userID == share.UserID {
+		return true
+	}
+	token := c.GetHeader("X-Share-Unlock")
+	if token == "" {
+		token = c.Query("unlock")
+	}
+	if token == "" {
+		return false
+	}
+	aclID := aclShareID(share)
+	orderID, err := parseShareClaims(token, aclID, purposeSharePaid)
+	if err != nil {
+		return false
+	}
+	var count int64
+	models.DB.Model(&models.SharePayment{}).
+		Where("id = ? AND share_id = ? AND status = ?", orderID, aclID, models.PaymentStatusPaid).Count(&count)
+	return count > 0
+}
+
+// respondPaymentRequired 付费分享未解锁时返回 402，附带价格供阅读页显示购买提示
+func respondPaymentRequired(c *gin.Context, share *models.Share) {
+	c.JSON(http.StatusPaymentRequired, gin.H{
+		"code": CodePaymentRequired,
+		"msg":  "Payment required",
+		"data": gin.H{"price": share.PaywallPrice, "payable": paymentsEnabled() && share.PaywallURL != ""},
+	})
+}
+
+// applyPaywallUpdate 校验付费阅读设置并写入 updates，返回错误信息；开启时价格与支付页面地址须有效
+func applyPaywallUpdate(share *models.Share, enabled *bool, price, checkoutURL *string, updates map[string]interface{}) string {
+	newPrice, newURL := share.PaywallPrice, share.PaywallURL
+	if price != nil {
+		newPrice = strings.TrimSpace(*price)
+		if utf8.RuneCountInString(newPrice) > maxPaywallPriceLength {
+			return "paywallPrice must be at most 32 characters"
+		}
+		updates["paywall_price"] = newPrice
+	}
+	if checkoutURL != nil {
+		newURL = strings.TrimSpace(*checkoutURL)
+		if newURL != "" {
+			u, err := url.Parse(strings.ReplaceAll(newURL, "{order}", "order"))
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(newURL) > 1024 {
+				return "paywallUrl must be an http(s) URL"
+			}
+			if !strings.Contains(newURL, "{order}") {
+				return "paywallUrl must contain {order}"
+			}
+		}
+		updates["paywall_url"] = newURL
+	}
+	if enabled != nil {
+		if *enabled {
+			if !paymentsEnabled() {
+				return "Payments are not configured on this server"
+			}
+			if newPrice == "" || newURL == "" {
+				return "paywallPrice and paywallUrl are required to enable the paywall"
+			}
+		}
+		updates["paywall"] = *enabled
+	}
+	return ""
+}
+
+// CreatePaymentOrder 读者为付费分享创建订单，返回订单 ID 与替换了 {order} 的支付页面地址；
+// 读者在支付页面付款后由支付平台回调 webhook 确认。需先通过访问名单与密码校验
+func CreatePaymentOrder(c *gin.Context) {
+	share, ok := loadShareForReader(c, false)
+	if !ok {
+		return
+	}
+	if !share.Paywall {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Share is not paywalled"})
+		return
+	}
+	if !paymentsEnabled() || share.PaywallURL == "" {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Payments are not configured on this server"})
+		return
+	}
+	order := models.SharePayment{
+		ID:      "ord_" + randHex(12),
+		ShareID: aclShareID(share),
+		Status:  models.PaymentStatusPending,
+		IP:      c.ClientIP(),
+	}
+	if err := models.DB.Create(&order).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create order: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"orderId":     order.ID,
+		"checkoutUrl": strings.ReplaceAll(share.PaywallURL, "{order}", url.QueryEscape(order.ID)),
+	}})
+}
+
+// GetPaymentOrder 读者查询订单状态，已支付时返回解锁令牌（X-Share-Unlock），阅读页据此轮询
+func GetPaymentOrder(c *gin.Context) {
+	share, ok := loadShareForReader(c, false)
+	if !ok {
+		return
+	}
+	var order models.SharePayment
+	if err := models.DB.Where("id = ? AND share_id = ?", c.Param("oid"), aclShareID(share)).First(&order).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Order not found"})
+		return
+	}
+	data := gin.H{"orderId": order.ID, "status": order.Status}
+	if order.Status == models.PaymentStatusPaid {
+		token, err := issueShareToken(order.ShareID, order.ID, purposeSharePaid, shareGrantTTL)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to issue token"})
+			return
+		}
+		data["unlockToken"] = token
+		data["expiresAt"] = time.Now().Add(shareGrantTTL)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": data})
+}
+
+// PaymentWebhookRequest 支付平台回调；签名覆盖原始请求体，故手动解析而非 ShouldBindJSON
+type PaymentWebhookRequest struct {
+	OrderID   string `json:"orderId"`
+	Status    string `json:"status"` // paid 或 refunded
+	Amount    string `json:"amount"`
+	Reference string `json:"reference"`
+}
+
+// PaymentWebhookGuard 支付回调的签名与重放校验（X-Payment-Timestamp、X-Payment-Nonce、X-Payment-Signature）
+var PaymentWebhookGuard = middleware.WebhookGuard{
+	Source:  "payment",
+	Header:  "X-Payment",
+	Secret:  func() string { return config.Get().Payment.WebhookSecret },
+	MaxBody: maxPaymentWebhookBody,
+}
+
+// PaymentWebhook 支付平台确认读者付款或退款，签名与重放由 PaymentWebhookGuard 校验；重复回调幂等，
+// 退款后已签发的解锁令牌随即失效，已退款的订单不再接受支付确认
+func PaymentWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request"})
+		return
+	}
+	var req PaymentWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil || req.OrderID == "" || len(req.Amount) > 64 || len(req.Reference) > 255 ||
+		(req.Status != models.PaymentStatusPaid && req.Status != models.PaymentStatusRefunded) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request"})
+		return
+	}
+
+	var order models.SharePayment
+	if err := models.DB.Where("id = ?", req.OrderID).First(&order).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Order not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load order: " + err.Error()})
+		return
+	}
+	updates := map[string]interface{}{"status": req.Status}
+	if req.Amount != "" {
+		updates["amount"] = req.Amount
+	}
+	if req.Reference != "" {
+		updates["reference"] = req.Reference
+	}
+	if req.Status == models.PaymentStatusPaid {
+		if order.Status == models.PaymentStatusPaid {
+			c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+			return
+		}
+		// 退款是终态，迟到或重放的支付确认不能让已退款的订单重新解锁
+		if order.Status == models.PaymentStatusRefunded {
+			c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Order has been refunded"})
+			return
+		}
+		// 过期未支付的订单不再确认，避免旧订单号被重复利用
+		if order.Status == models.PaymentStatusPending && time.Since(order.CreatedAt) > paymentOrderTTL {
+			c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Order has expired"})
+			return
+		}
+		updates["paid_at"] = time.Now()
+	}
+	// 条件更新：与并发的退款回调竞争时，已退款的订单不会被改回已支付
+	query := models.DB.Model(&models.SharePayment{}).Where("id = ?", order.ID)
+	if req.Status == models.PaymentStatusPaid {
+		query = query.Where("status <> ?", models.PaymentStatusRefunded)
+	}
+	result := query.Updates(updates)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update order: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Order has been refunded"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// ListSharePayments 分享者查看付费阅读订单（新的在前）与按状态的汇总，默认只列出已支付与已退款的订单，
+// status=pending 时列出未支付的订单
+func ListSharePayments(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	page, size := 1, 20
+	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(c.Query("size")); err == nil && v > 0 && v <= 100 {
+		size = v
+	}
+	summary, err := models.SummarizePayments(share.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load orders: " + err.Error()})
+		return
+	}
+	q := models.DB.Model(&models.SharePayment{}).Where("share_id = ?", share.ID)
+	if c.Query("status") == models.PaymentStatusPending {
+		q = q.Where("status = ?", models.PaymentStatusPending)
+	} else {
+		q = q.Where("status <> ?", models.PaymentStatusPending)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load orders: " + err.Error()})
+		return
+	}
+	var items []models.SharePayment
+	if err := q.Order("created_at DESC").Offset((page - 1) * size).Limit(size).Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load orders: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"enabled": paymentsEnabled(),
+		"summary": summary,
+		"items":   items,
+		"total":   total,
+		"page":    page,
+		"size":    size,
+	}})
+}
+
+// validDonationLinks 校验并规范化赞助链接，返回错误信息
+func validDonationLinks(d *models.DonationLinks) string {
+	d.Kofi = strings.TrimSpace(d.Kofi)
+	d.Afdian = strings.TrimSpace(d.Afdian)
+	d.URL = strings.TrimSpace(d.URL)
+	d.Label = strings.TrimSpace(d.Label)
+	if d.Kofi != "" && !donationNamePattern.MatchString(d.Kofi) {
+		return "Invalid Ko-fi username"
+	}
+	if d.Afdian != "" && !donationNamePattern.MatchString(d.Afdian) {
+		return "Invalid Afdian username"
+	}
+	if d.URL != "" {
+		u, err := url.Parse(d.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(d.URL) > 1024 {
+			return "Donation URL must be an http(s) URL"
+		}
+	}
+	if utf8.RuneCountInString(d.Label) > 30 {
+		return "Donation label must be at most 30 characters"
+	}
+	return ""
+}
+
+// ownerDonation 分享者在公开页面展示的赞助链接，未设置时为 nil
+func ownerDonation(userID string) *models.DonationLinks {
+	var user models.User
+	if err := models.DB.Select("id", "donation_links").Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil
+	}
+	if d := user.Donation(); !d.Empty() {
+		return &d
+	}
+	return nil
+}
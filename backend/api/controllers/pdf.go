@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportSharePDF 读者下载分享的 PDF：由无头 Chromium 打印阅读页生成，内容未变化时复用缓存。
+// 分享者开启 allowPdf 后读者可下载，分享者本人始终可以下载
+func ExportSharePDF(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if !share.AllowPDF && middleware.IdentifyUser(c) != share.UserID {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "PDF download is disabled for this share"})
+		return
+	}
+	if !export.PDFAvailable() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "PDF export is not available"})
+		return
+	}
+
+	pageURL := func() string {
+		token, _ := issueShareToken(share.ID, "print", purposeSharePrint, sharePrintTTL)
+		base, _ := export.PDFPageBase()
+		return base + "/s/" + share.ID + "?print=" + url.QueryEscape(token)
+	}
+	data, name, err := export.SharePDF(c.Request.Context(), share, pageURL)
+	if err != nil {
+		if errors.Is(err, export.ErrPDFUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "PDF export is not available"})
+			return
+		}
+		log.Printf("pdf export of share %s failed: %v", share.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to export PDF: " + err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "application/pdf", data)
+}
@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/citation"
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/pipeline"
+	"github.com/gin-gonic/gin"
+)
+
+// 阅读页正文处理步骤的顺序。stageAssetURLs 之前的步骤结果按正文缓存（见 renderShareContent），
+// 之后的步骤每次请求执行（资源签名随时间变化）；预渲染只用于阅读页数据
+const (
+	stageHooks     = 100 // 渲染前钩子
+	stageBlockRefs = 200 // 块引用链接
+	stageDrawings  = 300 // 绘图链接
+	stageCitations = 400 // 文献引用标注与参考文献列表
+	stageAssetURLs = 500 // 资源地址（CDN、版本与签名）
+	stagePrerender = 600 // 公式、Mermaid 与代码高亮的服务端预渲染
+)
+
+// contentDoc 一次正文处理的上下文
+type contentDoc struct {
+	c            *gin.Context
+	share        *models.Share
+	bibliography []citation.Entry
+}
+
+// contentPipeline 阅读页正文的处理步骤
+var contentPipeline = pipeline.New[*contentDoc]("content")
+
+func init() {
+	contentPipeline.Register(pipeline.Stage[*contentDoc]{Name: "hooks", Order: stageHooks, Run: func(d *contentDoc, content string) (string, error) {
+		return preRenderHooks(d.c, d.share, content)
+	}})
+	contentPipeline.Register(pipeline.Stage[*contentDoc]{Name: "block_refs", Order: stageBlockRefs, Run: func(d *contentDoc, content string) (string, error) {
+		if d.share.References == "" {
+			return content, nil
+		}
+		var refs []models.BlockReference
+		if err := json.Unmarshal([]byte(d.share.References), &refs); err != nil {
+			return content, fmt.Errorf("share %s: invalid references: %w", d.share.ID, err)
+		}
+		return replaceBlockReferences(content, refs, getBaseURL(d.c), d.share.UserID), nil
+	}})
+	contentPipeline.Register(pipeline.Stage[*contentDoc]{Name: "drawings", Order: stageDrawings, Run: func(d *contentDoc, content string) (string, error) {
+		return replaceDrawingLinks(content, getBaseURL(d.c), d.share.ID), nil
+	}})
+	contentPipeline.Register(pipeline.Stage[*contentDoc]{Name: "citations", Order: stageCitations, Run: func(d *contentDoc, content string) (string, error) {
+		content, d.bibliography = renderCitations(d.share, content)
+		return content, nil
+	}})
+	contentPipeline.Register(pipeline.Stage[*contentDoc]{Name: "asset_urls", Order: stageAssetURLs, Run: func(d *contentDoc, content string) (string, error) {
+		return rewriteAssetURLs(d.c, d.share, content), nil
+	}})
+	contentPipeline.Register(pipeline.Stage[*contentDoc]{Name: "prerender", Order: stagePrerender, Run: func(d *contentDoc, content string) (string, error) {
+		return export.PrerenderMarkdown(content, sharePrerenderer(d.share)), nil
+	}})
+}
+
+// deliverShareContent 对 renderShareContent 的结果执行每次请求的步骤（资源地址），不含预渲染
+func deliverShareContent(c *gin.Context, share *models.Share, content string) string {
+	return contentPipeline.Run(&contentDoc{c: c, share: share}, content, stageAssetURLs, stagePrerender)
+}
+
+// prerenderShareContent 执行服务端预渲染步骤
+func prerenderShareContent(c *gin.Context, share *models.Share, content string) string {
+	return contentPipeline.Run(&contentDoc{c: c, share: share}, content, stagePrerender, pipeline.Last)
+}
+
+// TraceRenderRequest 跟踪正文处理的请求
+type TraceRenderRequest struct {
+	Content *string  `json:"content"` // 用于试运行的正文，不保存；为空时使用分享当前的正文
+	Skip    []string `json:"skip"`    // 跳过的步骤
+}
+
+// TraceShareRender 逐步跟踪自己的分享正文在阅读页处理流程中的变化（试运行：不计浏览、不读写缓存），
+// 返回每个步骤的耗时、是否改动了正文以及改动后的全文
+func TraceShareRender(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	traceShareRender(c, share)
+}
+
+// AdminTraceShareRender 管理员跟踪任意分享的正文处理，用于排查读者反馈的显示问题
+func AdminTraceShareRender(c *gin.Context) {
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	traceShareRender(c, &share)
+}
+
+func traceShareRender(c *gin.Context, share *models.Share) {
+	var req TraceRenderRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if share.Encrypted() {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Encrypted shares are rendered in the browser"})
+		return
+	}
+	input := share.Content
+	if req.Content != nil {
+		// 试运行的正文只用于本次跟踪，参考文献等依赖正文的步骤同样以它为准
+		copied := *share
+		copied.Content = *req.Content
+		share, input = &copied, *req.Content
+	}
+	skip := map[string]bool{}
+	for _, name := range req.Skip {
+		skip[name] = true
+	}
+
+	d := &contentDoc{c: c, share: share}
+	steps := contentPipeline.Trace(d, input, skip)
+	output := input
+	for _, s := range steps {
+		if s.Output != nil {
+			output = *s.Output
+		}
+	}
+	if d.bibliography == nil {
+		d.bibliography = []citation.Entry{}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"input":        input,
+		"output":       output,
+		"steps":        steps,
+		"bibliography": d.bibliography,
+	}})
+}
+
+// ContentPipelineStats 阅读页正文处理各步骤的顺序与启动以来的执行统计（次数、改动次数、错误与耗时）
+func ContentPipelineStats(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": contentPipeline.Stats()})
+}
@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxPollOptions        = 20  // 每个投票的选项数上限
+	maxPollQuestionLength = 500 // 问题的最大字符数
+	maxPollOptionLength   = 200 // 选项的最大字符数
+)
+
+var (
+	pollQuestionPattern = regexp.MustCompile(`^#{1,6}\s+(.+)$`)
+	pollOptionPattern   = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s*)?(.+)$`)
+)
+
+// sharePoll 正文中的投票块
+type sharePoll struct {
+	Key      string   // 由问题文字生成，重新发布时保持不变
+	Index    int      // 正文中的第几个投票块，从 1 开始
+	Line     int      // 代码块起始行，阅读页据此对应渲染出的代码块
+	Question string   // 问题，未写标题时为空
+	Options  []string // 选项，已去重
+}
+
+// parsePoll 解析 ```poll 代码块：# 标题行（或第一段文字）为问题，列表项为选项；少于 2 个选项时不是有效的投票
+func parsePoll(source string) (question string, options []string, ok bool) {
+	seen := map[string]bool{}
+	for _, line := range strings.Split(source, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if m := pollOptionPattern.FindStringSubmatch(line); m != nil {
+			opt := truncateRunes(strings.TrimSpace(m[1]), maxPollOptionLength)
+			if opt != "" && !seen[opt] && len(options) < maxPollOptions {
+				seen[opt] = true
+				options = append(options, opt)
+			}
+			continue
+		}
+		if question == "" {
+			if m := pollQuestionPattern.FindStringSubmatch(line); m != nil {
+				line = m[1]
+			}
+			question = truncateRunes(strings.TrimSpace(line), maxPollQuestionLength)
+		}
+	}
+	return question, options, len(options) >= 2
+}
+
+// extractPolls 提取正文中的投票块；同一问题出现多次时后出现的 key 附加序号
+func extractPolls(content string) []sharePoll {
+	var polls []sharePoll
+	keys := map[string]int{}
+	for _, b := range extractFencedBlocks(content, func(lang string) bool { return lang == "poll" }) {
+		question, options, ok := parsePoll(b.Source)
+		if !ok {
+			continue
+		}
+		sum := sha256.Sum256([]byte(question))
+		key := hex.EncodeToString(sum[:8])
+		keys[key]++
+		if n := keys[key]; n > 1 {
+			key += "-" + strconv.Itoa(n)
+		}
+		polls = append(polls, sharePoll{Key: key, Index: b.Index, Line: b.Line, Question: question, Options: options})
+	}
+	return polls
+}
+
+// pollVoter 投票者标识：登录读者按账号，匿名读者按阅读页提交的浏览器指纹，未提交时按 IP 与 User-Agent
+func pollVoter(c *gin.Context, fingerprint string) string {
+	if userID := c.GetString("userID"); userID != "" {
+		return "u:" + userID
+	}
+	fingerprint = strings.TrimSpace(fingerprint)
+	if fingerprint == "" {
+		fingerprint = c.ClientIP() + "|" + c.Request.UserAgent()
+	}
+	sum := sha256.Sum256([]byte(fingerprint))
+	return "fp:" + hex.EncodeToString(sum[:16])
+}
+
+// pollResults 投票块及当前票数，voted 为该投票者选择的选项（未投票时为空）
+func pollResults(poll sharePoll, counts map[string]int64, voted string) gin.H {
+	options := make([]gin.H, 0, len(poll.Options))
+	var total int64
+	for _, opt := range poll.Options {
+		options = append(options, gin.H{"text": opt, "votes": counts[opt]})
+		total += counts[opt]
+	}
+	return gin.H{
+		"key":      poll.Key,
+		"index":    poll.Index,
+		"line":     poll.Line,
+		"question": poll.Question,
+		"options":  options,
+		"total":    total,
+		"voted":    voted,
+	}
+}
+
+// ListSharePolls 返回正文中的投票块与实时票数；选项被作者删除后其票数不再计入
+func ListSharePolls(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	content, _ := renderShareContent(c, share)
+	polls := extractPolls(content)
+	items := []gin.H{}
+	if len(polls) > 0 {
+		counts, err := models.PollCounts(share.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load votes: " + err.Error()})
+			return
+		}
+		choices, err := models.VoterChoices(share.ID, pollVoter(c, c.Query("fingerprint")))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load votes: " + err.Error()})
+			return
+		}
+		for _, p := range polls {
+			items = append(items, pollResults(p, counts[p.Key], choices[p.Key]))
+		}
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// VoteRequest 读者投票
+type VoteRequest struct {
+	Option      string `json:"option" binding:"required"`
+	Fingerprint string `json:"fingerprint" binding:"max=128"`
+}
+
+// VotePoll 读者在投票块中投票，返回该投票的最新结果；同一投票者再次投票时改为新的选项
+func VotePoll(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var req VoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	content, _ := renderShareContent(c, share)
+	var poll *sharePoll
+	for _, p := range extractPolls(content) {
+		if p.Key == c.Param("key") {
+			poll = &p
+			break
+		}
+	}
+	if poll == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Poll not found"})
+		return
+	}
+	option := strings.TrimSpace(req.Option)
+	valid := false
+	for _, opt := range poll.Options {
+		if opt == option {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid poll option"})
+		return
+	}
+
+	vote := models.PollVote{
+		ID:      "pv_" + randHex(10),
+		ShareID: share.ID,
+		PollKey: poll.Key,
+		Voter:   pollVoter(c, req.Fingerprint),
+		Choice:  option,
+		IP:      c.ClientIP(),
+	}
+	err := models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "share_id"}, {Name: "poll_key"}, {Name: "voter"}},
+		DoUpdates: clause.AssignmentColumns([]string{"choice", "ip", "updated_at"}),
+	}).Create(&vote).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save vote: " + err.Error()})
+		return
+	}
+	counts, err := models.PollCounts(share.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load votes: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": pollResults(*poll, counts[poll.Key], option)})
+}
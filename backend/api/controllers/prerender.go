@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+	"github.com/gin-gonic/gin"
+)
+
+// prerenderBudget 生成一个页面时用于预渲染的总时长；超出后其余内容不再预渲染，交给阅读页脚本处理。
+// 预渲染结果按内容缓存，之后的访问不再受此限制
+const prerenderBudget = 5 * time.Second
+
+// sharePrerenderOptions 分享实际生效的预渲染选项：分享已开启且服务器已配置对应的渲染命令
+func sharePrerenderOptions(share *models.Share) (math, mermaid, code bool) {
+	return share.PrerenderMath && renderer.PrerenderEnabled(renderer.PrerenderMath),
+		share.PrerenderMermaid && renderer.PrerenderEnabled(renderer.PrerenderMermaid),
+		share.PrerenderCode && renderer.PrerenderEnabled(renderer.PrerenderHighlight)
+}
+
+// sharePrerenderer 分享的服务端预渲染函数，未开启任何生效的预渲染选项时返回 nil。
+// 预渲染失败的内容保持原样，由阅读页脚本或浏览器按未预渲染处理
+func sharePrerenderer(share *models.Share) export.Prerenderer {
+	math, mermaid, code := sharePrerenderOptions(share)
+	if !math && !mermaid && !code {
+		return nil
+	}
+	deadline := time.Now().Add(prerenderBudget)
+	return func(kind, lang, source string) (string, bool) {
+		switch kind {
+		case renderer.PrerenderMath, renderer.PrerenderMathDisplay:
+			if !math {
+				return "", false
+			}
+		case renderer.PrerenderMermaid:
+			if !mermaid {
+				return "", false
+			}
+		case renderer.PrerenderHighlight:
+			if !code {
+				return "", false
+			}
+		}
+		if time.Now().After(deadline) {
+			return "", false
+		}
+		out, err := renderer.Prerender(kind, lang, source)
+		if err != nil {
+			log.Printf("prerender %s of share %s failed: %v", kind, share.ID, err)
+			return "", false
+		}
+		return out, true
+	}
+}
+
+// prerenderPayload 阅读页数据中的预渲染选项，供阅读页决定是否加载公式样式
+func prerenderPayload(share *models.Share) gin.H {
+	math, mermaid, code := sharePrerenderOptions(share)
+	return gin.H{"math": math, "mermaid": mermaid, "code": code}
+}
+
+// PrerenderSettings 分享的服务端预渲染设置
+type PrerenderSettings struct {
+	Math    *bool `json:"math"`    // 公式
+	Mermaid *bool `json:"mermaid"` // Mermaid 图
+	Code    *bool `json:"code"`    // 代码高亮
+}
+
+// sharePrerenderSettings 分享保存的预渲染设置（不论服务器是否配置了对应命令）
+func sharePrerenderSettings(share *models.Share) PrerenderSettings {
+	return PrerenderSettings{Math: &share.PrerenderMath, Mermaid: &share.PrerenderMermaid, Code: &share.PrerenderCode}
+}
+
+// GetPrerender 分享所有者查看预渲染设置，available 为服务器已配置渲染命令的项
+func GetPrerender(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"settings": sharePrerenderSettings(share),
+		"available": gin.H{
+			"math":    renderer.PrerenderEnabled(renderer.PrerenderMath),
+			"mermaid": renderer.PrerenderEnabled(renderer.PrerenderMermaid),
+			"code":    renderer.PrerenderEnabled(renderer.PrerenderHighlight),
+		},
+	}})
+}
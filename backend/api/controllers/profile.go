@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UpdateProfileRequest 修改用户名、邮箱或密码，均需提供当前密码
+type UpdateProfileRequest struct {
+	CurrentPassword string  `json:"currentPassword" binding:"required"`
+	Username        *string `json:"username" binding:"omitempty,min=3,max=100"`
+	Email           *string `json:"email" binding:"omitempty,email"`
+	NewPassword     *string `json:"newPassword" binding:"omitempty,min=6,max=200"`
+}
+
+// DeleteAccountRequest 注销账号，confirm 须与用户名一致
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	Confirm  string `json:"confirm" binding:"required"`
+}
+
+// checkPassword 校验当前密码；未设置密码的账号（第三方登录创建）需先通过找回密码设置密码
+func checkPassword(user *models.User, password string) bool {
+	return user.PasswordHash != "" && bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
+
+// UpdateProfile 修改当前用户的用户名、邮箱或密码。修改邮箱后需重新验证；修改密码后注销其他会话
+func UpdateProfile(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if !checkPassword(user, req.CurrentPassword) {
+		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid credentials"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Username != nil {
+		if username := strings.TrimSpace(*req.Username); username != user.Username {
+			var count int64
+			models.DB.Model(&models.User{}).Where("username = ? AND id <> ?", username, user.ID).Count(&count)
+			if count > 0 {
+				c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Username already exists"})
+				return
+			}
+			updates["username"] = username
+		}
+	}
+	emailChanged := false
+	if req.Email != nil {
+		if email := strings.TrimSpace(*req.Email); !strings.EqualFold(email, user.Email) {
+			var count int64
+			models.DB.Model(&models.User{}).Where("LOWER(email) = LOWER(?) AND id <> ?", email, user.ID).Count(&count)
+			if count > 0 {
+				c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Email already exists"})
+				return
+			}
+			updates["email"] = email
+			updates["email_verified"] = false
+			emailChanged = true
+		}
+	}
+	if req.NewPassword != nil {
+		hash, err := bcrypt.GenerateFromPassword([]byte(*req.NewPassword), bcrypt.DefaultCost)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to hash password"})
+			return
+		}
+		updates["password_hash"] = string(hash)
+	}
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "No fields to update"})
+		return
+	}
+	if err := models.DB.Model(user).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update profile: " + err.Error()})
+		return
+	}
+	auditLog(c, "profile_updated", "user_id", user.ID, "username", user.Username, "email_changed", emailChanged, "password_changed", req.NewPassword != nil)
+
+	if req.NewPassword != nil {
+		query := models.DB.Model(&models.Session{}).Where("user_id = ? AND revoked = ?", user.ID, false)
+		if current := c.GetString("sessionID"); current != "" {
+			query = query.Where("id <> ?", current)
+		}
+		if err := query.Update("revoked", true).Error; err != nil {
+			log.Printf("Failed to revoke sessions for %s: %v", user.ID, err)
+		}
+	}
+	verificationSent := false
+	if emailChanged && mailer.Enabled() {
+		if err := sendVerificationEmail(c, user); err != nil {
+			log.Printf("Failed to send verification email to %s: %v", user.ID, err)
+		} else {
+			verificationSent = true
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"username": user.Username, "email": user.Email, "emailVerified": user.EmailVerified, "verificationSent": verificationSent,
+	}})
+}
+
+// DeleteAccount 注销当前账号：立即撤销全部 API Token，宽限期（auth.deletion_grace）满后由定时任务
+// 彻底删除账号与全部数据，期间登录后可撤销；宽限期为 0 时立即删除
+func DeleteAccount(c *gin.Context) {
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if !checkPassword(user, req.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid credentials"})
+		return
+	}
+	if req.Confirm != user.Username {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Confirmation does not match username"})
+		return
+	}
+	// 封存的分享须保留到期满，期间不能注销
+	if until, ok := models.LatestSealedUntil(user.ID); ok {
+		c.JSON(http.StatusConflict, gin.H{"code": CodeShareSealed, "msg": "Account has sealed shares", "data": gin.H{"sealedUntil": until}})
+		return
+	}
+
+	grace := config.Get().Auth.DeletionGrace.Std()
+	if grace == 0 {
+		if err := models.PurgeUser(c.Request.Context(), user.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete account: " + err.Error()})
+			return
+		}
+		auditLog(c, "account_deleted", "user_id", user.ID, "username", user.Username)
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"deleted": true}})
+		return
+	}
+
+	deleteAt := time.Now().Add(grace)
+	if err := models.DB.Model(user).Update("deletion_scheduled_at", deleteAt).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to schedule account deletion: " + err.Error()})
+		return
+	}
+	// 插件等客户端立即停止发布；撤销注销后需重新创建 API Token
+	if err := models.DB.Model(&models.UserToken{}).Where("user_id = ? AND revoked = ?", user.ID, false).Update("revoked", true).Error; err != nil {
+		log.Printf("Failed to revoke API tokens for %s: %v", user.ID, err)
+	}
+	auditLog(c, "account_deletion_scheduled", "user_id", user.ID, "username", user.Username, "delete_at", deleteAt)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"deleted": false, "deletionScheduledAt": deleteAt}})
+}
+
+// CancelAccountDeletion 在宽限期内撤销注销
+func CancelAccountDeletion(c *gin.Context) {
+	res := models.DB.Model(&models.User{}).Where("id = ? AND deletion_scheduled_at IS NOT NULL", c.GetString("userID")).
+		Update("deletion_scheduled_at", nil)
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to cancel account deletion: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Account deletion is not scheduled"})
+		return
+	}
+	auditLog(c, "account_deletion_cancelled", "user_id", c.GetString("userID"))
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
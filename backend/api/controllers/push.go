@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/ZeroHawkeye/siyuan-share-api/webpush"
+	"github.com/gin-gonic/gin"
+)
+
+// PushSubscriptionRequest 浏览器 PushSubscription.toJSON() 的结构
+type PushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,max=1024"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required,max=255"`
+		Auth   string `json:"auth" binding:"required,max=64"`
+	} `json:"keys"`
+}
+
+// validPushEndpoint 推送 endpoint 必须是 https 地址
+func validPushEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	return err == nil && u.Scheme == "https" && u.Host != ""
+}
+
+// GetVAPIDPublicKey 返回 VAPID 公钥，前端调用 pushManager.subscribe 时作为 applicationServerKey
+func GetVAPIDPublicKey(c *gin.Context) {
+	if webpush.VAPID == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Web Push is not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"publicKey": webpush.VAPID.PublicKey}})
+}
+
+// CreatePushSubscription 登记当前浏览器的推送订阅（同一 endpoint 重复登记时更新）
+func CreatePushSubscription(c *gin.Context) {
+	var req PushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if !validPushEndpoint(req.Endpoint) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid push endpoint"})
+		return
+	}
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	var sub models.PushSubscription
+	if err := models.DB.Where("endpoint = ?", req.Endpoint).First(&sub).Error; err != nil {
+		sub = models.PushSubscription{ID: "psh_" + randHex(10), Endpoint: req.Endpoint}
+	}
+	sub.UserID = c.GetString("userID")
+	sub.P256dh = req.Keys.P256dh
+	sub.Auth = req.Keys.Auth
+	sub.UserAgent = userAgent
+	if err := models.DB.Save(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save push subscription: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": sub})
+}
+
+// ListPushSubscriptions 列出当前用户已登记推送的浏览器
+func ListPushSubscriptions(c *gin.Context) {
+	var items []models.PushSubscription
+	if err := models.DB.Where("user_id = ?", c.GetString("userID")).Order("created_at DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list push subscriptions: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// DeletePushSubscription 取消推送订阅（按 ID）
+func DeletePushSubscription(c *gin.Context) {
+	result := models.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetString("userID")).Delete(&models.PushSubscription{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete push subscription: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Push subscription not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// TestPush 向指定浏览器发送一条测试通知
+func TestPush(c *gin.Context) {
+	var sub models.PushSubscription
+	if err := models.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetString("userID")).First(&sub).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Push subscription not found"})
+		return
+	}
+	err := notify.SendUserPush(c.Request.Context(), &sub, notify.PushMessage{
+		Title: "思源分享",
+		Body:  "浏览器通知已开启",
+		URL:   getBaseURL(c) + "/dashboard",
+	})
+	if errors.Is(err, webpush.ErrGone) {
+		c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Push subscription expired, please enable notifications again"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to send push: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// appIcon 应用图标在清单与页面中的地址、类型与尺寸
+type appIcon struct {
+	src   string
+	typ   string
+	sizes string
+}
+
+// appNames 应用名称与短名称，未配置时按语言使用站点标题
+func appNames(cfg config.PWAConfig, locale string) (string, string) {
+	name := cfg.Name
+	if name == "" {
+		name = i18n.T(locale, "page.title")
+	}
+	short := cfg.ShortName
+	if short == "" {
+		short = name
+	}
+	return name, short
+}
+
+// resolveAppIcon 配置的图标文件或内置图标；地址带内容版本，图标更换后浏览器重新获取
+func resolveAppIcon(cfg config.PWAConfig, maskable bool, short string) appIcon {
+	file, route := cfg.Icon, "/pwa/icon"
+	if maskable {
+		file, route = cfg.MaskableIcon, "/pwa/maskable-icon"
+	}
+	if file == "" {
+		sum := sha256.Sum256([]byte(cfg.ThemeColor + "|" + short))
+		return appIcon{src: route + "?v=" + hex.EncodeToString(sum[:4]), typ: "image/svg+xml", sizes: "any"}
+	}
+	icon := appIcon{src: route, typ: "image/svg+xml", sizes: "any"}
+	if st, err := os.Stat(file); err == nil {
+		icon.src += "?v=" + strconv.FormatInt(st.ModTime().Unix(), 36)
+	}
+	if strings.ToLower(filepath.Ext(file)) == ".png" {
+		icon.typ = "image/png"
+		if f, err := os.Open(file); err == nil {
+			if img, err := png.DecodeConfig(f); err == nil {
+				icon.sizes = fmt.Sprintf("%dx%d", img.Width, img.Height)
+			}
+			f.Close()
+		}
+	}
+	return icon
+}
+
+// defaultAppIcon 以主题色为底、名称首字为图案的内置图标；可裁切图标铺满画布，普通图标为圆角方形
+func defaultAppIcon(color, short string, maskable bool) string {
+	letter, _ := utf8.DecodeRuneInString(short)
+	if letter == utf8.RuneError {
+		letter = 'S'
+	}
+	radius := "96"
+	if maskable {
+		radius = "0"
+	}
+	return `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 512 512">` +
+		`<rect width="512" height="512" rx="` + radius + `" fill="` + color + `"/>` +
+		`<text x="256" y="256" dy=".35em" text-anchor="middle" font-family="system-ui, -apple-system, sans-serif" font-size="260" font-weight="600" fill="#fff">` +
+		html.EscapeString(strings.ToUpper(string(letter))) + `</text></svg>`
+}
+
+// AppManifest 输出 Web App Manifest，仪表盘与阅读页据此安装为应用（名称、颜色、起始页面与图标见 pwa 配置）
+func AppManifest(c *gin.Context) {
+	cfg := config.Get().PWA
+	locale := middleware.Locale(c)
+	name, short := appNames(cfg, locale)
+	description := cfg.Description
+	if description == "" {
+		description = i18n.T(locale, "page.description")
+	}
+	icon := resolveAppIcon(cfg, false, short)
+	maskable := resolveAppIcon(cfg, true, short)
+	if cfg.MaskableIcon == "" && cfg.Icon != "" {
+		// 只配置了普通图标时不提供可裁切图标，避免内置图标与自定义图标混用
+		maskable = appIcon{}
+	}
+	icons := []gin.H{{"src": icon.src, "type": icon.typ, "sizes": icon.sizes, "purpose": "any"}}
+	if maskable.src != "" {
+		icons = append(icons, gin.H{"src": maskable.src, "type": maskable.typ, "sizes": maskable.sizes, "purpose": "maskable"})
+	}
+	body, _ := json.Marshal(gin.H{
+		"id":               "/",
+		"name":             name,
+		"short_name":       short,
+		"description":      description,
+		"lang":             locale,
+		"start_url":        cfg.StartURL,
+		"scope":            "/",
+		"display":          "standalone",
+		"theme_color":      cfg.ThemeColor,
+		"background_color": cfg.BackgroundColor,
+		"icons":            icons,
+	})
+	c.Header("Vary", "Accept-Language")
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/manifest+json", body)
+}
+
+// AppIcon 输出应用图标（/pwa/icon 与 /pwa/maskable-icon），未配置图标文件时输出内置图标
+func AppIcon(c *gin.Context) {
+	cfg := config.Get().PWA
+	maskable := strings.HasSuffix(c.Request.URL.Path, "/maskable-icon")
+	file := cfg.Icon
+	if maskable {
+		file = cfg.MaskableIcon
+	}
+	c.Header("Cache-Control", "public, max-age=86400")
+	if file != "" {
+		c.File(file)
+		return
+	}
+	_, short := appNames(cfg, middleware.Locale(c))
+	c.Header("Vary", "Accept-Language")
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	c.Data(http.StatusOK, "image/svg+xml", []byte(defaultAppIcon(cfg.ThemeColor, short, maskable)))
+}
+
+// InjectAppManifest 在页面中加入应用清单、主题色与图标，浏览器据此提供“安装应用”
+func InjectAppManifest(c *gin.Context, page []byte) []byte {
+	cfg := config.Get().PWA
+	_, short := appNames(cfg, middleware.Locale(c))
+	icon := resolveAppIcon(cfg, false, short)
+	tags := []string{
+		`<link rel="manifest" href="/manifest.webmanifest" />`,
+		`<meta name="theme-color" content="` + html.EscapeString(cfg.ThemeColor) + `" />`,
+		`<meta name="apple-mobile-web-app-title" content="` + html.EscapeString(short) + `" />`,
+		`<link rel="icon" type="` + icon.typ + `" href="` + icon.src + `" />`,
+	}
+	// iOS 主屏幕图标不支持 SVG
+	if icon.typ == "image/png" {
+		tags = append(tags, `<link rel="apple-touch-icon" href="`+icon.src+`" />`)
+	}
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	return []byte(strings.Replace(string(page), "</head>", "  "+strings.Join(tags, "\n    ")+"\n  </head>", 1))
+}
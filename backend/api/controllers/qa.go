@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/qa"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AskRequest 读者提问
+type AskRequest struct {
+	Question string `json:"question" binding:"required"`
+}
+
+// readQuestion 读取并校验问题；失败时已写入响应
+func readQuestion(c *gin.Context) (string, bool) {
+	if !qa.Enabled() {
+		c.JSON(http.StatusNotImplemented, gin.H{"code": 1, "msg": "Question answering is not available"})
+		return "", false
+	}
+	var req AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return "", false
+	}
+	question := strings.TrimSpace(req.Question)
+	if question == "" || utf8.RuneCountInString(question) > qa.MaxQuestionRunes {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Question must be 1-500 characters"})
+		return "", false
+	}
+	return question, true
+}
+
+// answerQuestion 依据文档作答，为出处填写阅读页地址
+func answerQuestion(c *gin.Context, question string, docs []qa.Document) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.Get().AI.Timeout.Std())
+	defer cancel()
+	res, err := qa.Answer(ctx, question, docs)
+	if errors.Is(err, qa.ErrNoText) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to answer question: " + err.Error()})
+		return
+	}
+	baseURL := getBaseURL(c)
+	for i := range res.Citations {
+		cite := &res.Citations[i]
+		cite.URL = baseURL + "/s/" + cite.ShareID
+		if cite.Anchor != "" {
+			cite.URL += "#" + cite.Anchor
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": res})
+}
+
+// AskShare 读者就分享正文提问，需服务器开启 ai.qa 且作者为该分享开启问答；访问校验同阅读页
+func AskShare(c *gin.Context) {
+	question, ok := readQuestion(c)
+	if !ok {
+		return
+	}
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if !share.AllowQA {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Question answering is not enabled for this share"})
+		return
+	}
+	content, _ := renderShareContent(c, share)
+	answerQuestion(c, question, []qa.Document{{ShareID: share.ID, DocTitle: share.DocTitle, Content: content}})
+}
+
+// AskCollection 读者就合集提问，依据合集中开启了问答、且无需密码或访问名单的分享作答
+func AskCollection(c *gin.Context) {
+	question, ok := readQuestion(c)
+	if !ok {
+		return
+	}
+	_, shares, err := publicCollection(c.Param("slug"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Collection not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load collection: " + err.Error()})
+		return
+	}
+	var docs []qa.Document
+	for i := range shares {
+		s := &shares[i]
+		if !s.AllowQA || s.RequirePassword || s.Restricted || s.Terms != "" || s.Paywall || s.Teaser || s.MaxViews > 0 || !s.OpenAt(time.Now()) {
+			continue
+		}
+		content, _ := renderShareContent(c, s)
+		docs = append(docs, qa.Document{ShareID: s.ID, DocTitle: s.DocTitle, Content: content})
+	}
+	if len(docs) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Question answering is not enabled for this collection"})
+		return
+	}
+	answerQuestion(c, question, docs)
+}
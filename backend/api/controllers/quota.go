@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// GetUsage 当前用户的存储用量与生效配额
+func GetUsage(c *gin.Context) {
+	userID := c.GetString("userID")
+	usage, err := models.UserUsage(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"usage": usage,
+		"quota": models.UserQuota(userID),
+	}})
+}
+
+// quotaWarnings 用户达到提醒线（80%）或已超出配额的各项配额，均正常或查询失败时返回 nil
+func quotaWarnings(userID string) []models.QuotaStatus {
+	quota := models.UserQuota(userID)
+	if quota.MaxBytes <= 0 && quota.MaxShares <= 0 && quota.MaxBandwidth <= 0 {
+		return nil
+	}
+	usage, err := models.UserUsage(userID)
+	if err != nil {
+		return nil
+	}
+	var items []models.QuotaStatus
+	for _, s := range models.QuotaStatuses(usage, quota) {
+		if s.State != models.QuotaStateOK {
+			items = append(items, s)
+		}
+	}
+	return items
+}
+
+// GetCapabilities 插件连接后读取的服务端能力与限制：接口版本、单个资源与导入包大小上限、每日发布次数上限，
+// 以及当前用户各项配额的用量状态（ok / warning / grace / blocked），插件据此在发布前提示作者
+func GetCapabilities(c *gin.Context) {
+	userID := c.GetString("userID")
+	usage, err := models.UserUsage(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+		return
+	}
+	quota := models.UserQuota(userID)
+	cfg := config.Get()
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"version": "v1",
+		"limits": gin.H{
+			"maxAssetBytes":     quota.MaxAssetBytes,
+			"maxImportBytes":    maxImportBytes,
+			"publishDailyQuota": cfg.RateLimit.PublishDailyQuota,
+		},
+		"quota": gin.H{
+			"usage":        usage,
+			"quota":        quota,
+			"gracePercent": cfg.Quota.GracePercent,
+			"warnLevels":   models.QuotaWarnLevels,
+			"items":        models.QuotaStatuses(usage, quota),
+		},
+	}})
+}
+
+// loadQuotaUser 按 ID 或用户名查找管理目标用户
+func loadQuotaUser(c *gin.Context) (*models.User, bool) {
+	var user models.User
+	key := c.Param("user")
+	if err := models.DB.Where("id = ? OR username = ?", key, key).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "User not found"})
+		return nil, false
+	}
+	return &user, true
+}
+
+func userQuotaResponse(c *gin.Context, user *models.User) {
+	usage, err := models.UserUsage(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"userId":   user.ID,
+		"username": user.Username,
+		"usage":    usage,
+		"quota":    user.EffectiveQuota(),
+		"override": user.Override(),
+	}})
+}
+
+// GetUserQuota 管理员查看用户的用量、生效配额与覆盖设置
+func GetUserQuota(c *gin.Context) {
+	user, ok := loadQuotaUser(c)
+	if !ok {
+		return
+	}
+	userQuotaResponse(c, user)
+}
+
+// UpdateUserQuota 管理员覆盖用户配额：字段为 null 时恢复默认配置，0 表示不限制
+func UpdateUserQuota(c *gin.Context) {
+	user, ok := loadQuotaUser(c)
+	if !ok {
+		return
+	}
+	var req models.QuotaOverride
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if (req.MaxBytes != nil && *req.MaxBytes < 0) || (req.MaxShares != nil && *req.MaxShares < 0) ||
+		(req.MaxAssetBytes != nil && *req.MaxAssetBytes < 0) || (req.MaxBandwidth != nil && *req.MaxBandwidth < 0) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Quota values must not be negative"})
+		return
+	}
+	user.QuotaMaxBytes = req.MaxBytes
+	user.QuotaMaxShares = req.MaxShares
+	user.QuotaMaxAssetBytes = req.MaxAssetBytes
+	user.QuotaMaxBandwidth = req.MaxBandwidth
+	if err := models.DB.Model(user).Select("quota_max_bytes", "quota_max_shares", "quota_max_asset_bytes", "quota_max_bandwidth").
+		Updates(user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update quota: " + err.Error()})
+		return
+	}
+	userQuotaResponse(c, user)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxProgressAnchorLength 阅读进度中标题锚点的最大长度
+const maxProgressAnchorLength = 128
+
+// readerTokenPattern 服务端签发的匿名读者令牌
+var readerTokenPattern = regexp.MustCompile(`^rt_[0-9a-f]{32}$`)
+
+// ReadingProgressRequest 保存阅读进度请求
+type ReadingProgressRequest struct {
+	Token   string  `json:"token"` // 读者令牌，为空或无效时签发新令牌
+	Anchor  string  `json:"anchor"`
+	Percent float64 `json:"percent"`
+}
+
+// readerToken 请求携带的读者令牌，取自 token 查询参数或 X-Reader-Token 请求头；格式无效时返回空
+func readerToken(c *gin.Context) string {
+	token := c.Query("token")
+	if token == "" {
+		token = c.GetHeader("X-Reader-Token")
+	}
+	if !readerTokenPattern.MatchString(token) {
+		return ""
+	}
+	return token
+}
+
+// resumeURL 在其他设备上继续阅读的链接
+func resumeURL(c *gin.Context, shareID, token string) string {
+	return getBaseURL(c) + "/s/" + shareID + "?resume=" + url.QueryEscape(token)
+}
+
+// GetReadingProgress 读者查询在该分享中的阅读进度，没有记录时 data 为 null
+func GetReadingProgress(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	token := readerToken(c)
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid reader token"})
+		return
+	}
+	var progress models.ReadingProgress
+	err := models.DB.Where("token = ? AND share_id = ?", token, share.ID).First(&progress).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": nil})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load reading progress: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"anchor":    progress.Anchor,
+		"percent":   progress.Percent,
+		"updatedAt": progress.UpdatedAt,
+		"resumeUrl": resumeURL(c, share.ID, token),
+	}})
+}
+
+// SaveReadingProgress 读者保存阅读进度；未携带有效令牌时签发新令牌，读者保存后用于查询与续读链接
+func SaveReadingProgress(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var req ReadingProgressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	anchor := strings.TrimSpace(req.Anchor)
+	if len(anchor) > maxProgressAnchorLength {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Anchor must be at most 128 characters"})
+		return
+	}
+	if math.IsNaN(req.Percent) {
+		req.Percent = 0
+	}
+	token := req.Token
+	if !readerTokenPattern.MatchString(token) {
+		token = "rt_" + randHex(16)
+	}
+	progress := models.ReadingProgress{
+		Token:     token,
+		ShareID:   share.ID,
+		Anchor:    anchor,
+		Percent:   math.Round(min(max(req.Percent, 0), 100)*10) / 10,
+		UpdatedAt: time.Now(),
+	}
+	err := models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}, {Name: "share_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"anchor", "percent", "updated_at"}),
+	}).Create(&progress).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save reading progress: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"token":     token,
+		"anchor":    progress.Anchor,
+		"percent":   progress.Percent,
+		"updatedAt": progress.UpdatedAt,
+		"resumeUrl": resumeURL(c, share.ID, token),
+	}})
+}
+
+// DeleteReadingProgress 读者清除在该分享中的阅读进度
+func DeleteReadingProgress(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	token := readerToken(c)
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid reader token"})
+		return
+	}
+	if err := models.DB.Where("token = ? AND share_id = ?", token, share.ID).Delete(&models.ReadingProgress{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete reading progress: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
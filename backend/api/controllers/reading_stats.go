@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxFunnelHeadings 阅读漏斗中最多列出的标题数
+const maxFunnelHeadings = 200
+
+// funnelStep 阅读漏斗中的一个标题：reached 为读到该标题或更靠后位置的阅读数
+type funnelStep struct {
+	ID      string  `json:"id"`
+	Text    string  `json:"text"`
+	Level   int     `json:"level"`
+	Reached int64   `json:"reached"`
+	Rate    float64 `json:"rate"` // reached 占全部阅读的比例
+}
+
+// GetShareReadingStats 所有者查看分享的字数、预计阅读时长、阅读时长与深度，以及按标题统计的阅读漏斗；
+// from / to 格式同浏览数据导出，默认为原始记录保留期内的全部数据
+func GetShareReadingStats(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	to := time.Now()
+	from := to.Add(-config.Get().Jobs.ViewRetention.Std())
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = parseViewExportTime(v, false); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = parseViewExportTime(v, true); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+
+	models.FlushShareViews()
+	var summary struct {
+		N          int64
+		AvgSeconds float64
+		AvgDepth   float64
+		Completed  int64
+	}
+	if err := models.DB.Model(&models.ShareRead{}).
+		Select("COUNT(*) AS n, COALESCE(AVG(seconds), 0) AS avg_seconds, COALESCE(AVG(depth), 0) AS avg_depth, "+
+			"COALESCE(SUM(CASE WHEN depth >= ? THEN 1 ELSE 0 END), 0) AS completed", readCompletionDepth).
+		Where("share_id = ? AND read_at >= ? AND read_at < ?", share.ID, from, to).
+		Scan(&summary).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load views: " + err.Error()})
+		return
+	}
+	var anchors []struct {
+		Anchor string
+		N      int64
+	}
+	if err := models.DB.Model(&models.ShareRead{}).Select("anchor, COUNT(*) AS n").
+		Where("share_id = ? AND read_at >= ? AND read_at < ?", share.ID, from, to).
+		Group("anchor").Scan(&anchors).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load views: " + err.Error()})
+		return
+	}
+
+	// 按当前正文的标题顺序累计：读到第 i 个标题的阅读也经过了它之前的所有标题；
+	// 锚点在正文更新后已不存在的阅读只计入总数
+	headings := export.Headings(share.Content)
+	if len(headings) > maxFunnelHeadings {
+		headings = headings[:maxFunnelHeadings]
+	}
+	index := make(map[string]int, len(headings))
+	for i, h := range headings {
+		index[h.ID] = i
+	}
+	last := make([]int64, len(headings))
+	for _, a := range anchors {
+		if i, ok := index[a.Anchor]; ok {
+			last[i] += a.N
+		}
+	}
+	funnel := make([]funnelStep, len(headings))
+	var reached int64
+	for i := len(headings) - 1; i >= 0; i-- {
+		reached += last[i]
+		funnel[i] = funnelStep{ID: headings[i].ID, Text: headings[i].Text, Level: headings[i].Level, Reached: reached}
+		if summary.N > 0 {
+			funnel[i].Rate = float64(reached) / float64(summary.N)
+		}
+	}
+
+	completionRate := 0.0
+	if summary.N > 0 {
+		completionRate = float64(summary.Completed) / float64(summary.N)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"trackReading":   share.TrackReading,
+		"wordCount":      share.WordCount,
+		"readingMinutes": share.ReadingMinutes,
+		"from":           from,
+		"to":             to,
+		"reads":          summary.N,
+		"avgSeconds":     summary.AvgSeconds,
+		"avgDepth":       summary.AvgDepth,
+		"completionRate": completionRate,
+		"funnel":         funnel,
+	}})
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxRedirects 重定向规则数量上限
+const maxRedirects = 1000
+
+// RedirectRequest 创建或更新重定向规则
+type RedirectRequest struct {
+	Source string `json:"source" binding:"required"`
+	Target string `json:"target" binding:"required"`
+	Status int    `json:"status"` // 默认 301
+	Regex  bool   `json:"regex"`
+	Note   string `json:"note"`
+}
+
+// normalizeRedirect 校验规则：来源须为站内路径（不能是 /api 下的接口）或可编译的正则，目标须为站内路径或 http(s) 地址
+func normalizeRedirect(req *RedirectRequest) (*models.Redirect, error) {
+	r := &models.Redirect{
+		Source: strings.TrimSpace(req.Source),
+		Target: strings.TrimSpace(req.Target),
+		Status: req.Status,
+		Regex:  req.Regex,
+		Note:   strings.TrimSpace(req.Note),
+	}
+	if r.Status == 0 {
+		r.Status = http.StatusMovedPermanently
+	}
+	switch r.Status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return nil, errors.New("status must be 301, 302, 307 or 308")
+	}
+	if len(r.Source) > 1024 || len(r.Target) > 2048 || len([]rune(r.Note)) > 200 {
+		return nil, errors.New("source, target or note is too long")
+	}
+
+	if r.Regex {
+		if _, err := regexp.Compile(r.Source); err != nil {
+			return nil, errors.New("invalid source pattern: " + err.Error())
+		}
+	} else {
+		if !strings.HasPrefix(r.Source, "/") {
+			return nil, errors.New("source must be a path starting with /")
+		}
+		if r.Source == "/api" || strings.HasPrefix(r.Source, "/api/") {
+			return nil, errors.New("API paths cannot be redirected")
+		}
+		if strings.ContainsAny(r.Source, "?#") {
+			return nil, errors.New("source must not contain a query or fragment")
+		}
+	}
+
+	switch {
+	case strings.HasPrefix(r.Target, "/") && !strings.HasPrefix(r.Target, "//"):
+	case strings.HasPrefix(r.Target, "http://") || strings.HasPrefix(r.Target, "https://"):
+		if u, err := url.Parse(r.Target); err != nil || u.Host == "" {
+			return nil, errors.New("target is not a valid URL")
+		}
+	default:
+		return nil, errors.New("target must be a path starting with / or an http(s) URL")
+	}
+	if !r.Regex && r.Target == r.Source {
+		return nil, errors.New("target must differ from source")
+	}
+	return r, nil
+}
+
+// reloadRedirects 规则变更后刷新中间件中的规则缓存
+func reloadRedirects() {
+	if err := middleware.ReloadRedirects(); err != nil {
+		log.Printf("Failed to reload redirects: %v", err)
+	}
+}
+
+// ListRedirects 管理员查看全部重定向规则
+func ListRedirects(c *gin.Context) {
+	items := []models.Redirect{}
+	if err := models.DB.Order("id").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list redirects: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// CreateRedirect 管理员新增重定向规则
+func CreateRedirect(c *gin.Context) {
+	var req RedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	r, err := normalizeRedirect(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	var count int64
+	models.DB.Model(&models.Redirect{}).Count(&count)
+	if count >= maxRedirects {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Too many redirects"})
+		return
+	}
+	if !r.Regex {
+		var existing int64
+		models.DB.Model(&models.Redirect{}).Where("source = ? AND regex = ?", r.Source, false).Count(&existing)
+		if existing > 0 {
+			c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "A redirect for this path already exists"})
+			return
+		}
+	}
+	if err := models.DB.Create(r).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create redirect: " + err.Error()})
+		return
+	}
+	reloadRedirects()
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": r})
+}
+
+// UpdateRedirect 管理员修改重定向规则
+func UpdateRedirect(c *gin.Context) {
+	var existing models.Redirect
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&existing).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Redirect not found"})
+		return
+	}
+	var req RedirectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	r, err := normalizeRedirect(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if !r.Regex {
+		var dup int64
+		models.DB.Model(&models.Redirect{}).Where("source = ? AND regex = ? AND id <> ?", r.Source, false, existing.ID).Count(&dup)
+		if dup > 0 {
+			c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "A redirect for this path already exists"})
+			return
+		}
+	}
+	r.ID = existing.ID
+	r.CreatedAt = existing.CreatedAt
+	if err := models.DB.Save(r).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update redirect: " + err.Error()})
+		return
+	}
+	reloadRedirects()
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": r})
+}
+
+// DeleteRedirect 管理员删除重定向规则
+func DeleteRedirect(c *gin.Context) {
+	res := models.DB.Where("id = ?", c.Param("id")).Delete(&models.Redirect{})
+	if res.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete redirect: " + res.Error.Error()})
+		return
+	}
+	if res.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Redirect not found"})
+		return
+	}
+	reloadRedirects()
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/reload"
+	"github.com/gin-gonic/gin"
+)
+
+// ReloadConfig 管理员重新加载配置（与向进程发送 SIGHUP 相同），不中断读者的连接。
+// 配置无效或无法读取时返回 400（校验失败时附带全部问题项），服务继续按原配置运行；restartRequired 为需重启才能生效的配置项
+func ReloadConfig(c *gin.Context) {
+	result, err := reload.Run()
+	var invalid *config.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid configuration", "data": gin.H{"problems": invalid.Problems}})
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Failed to reload configuration: " + err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": result})
+	}
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+	"github.com/gin-gonic/gin"
+)
+
+// extractRenderBlocks 提取正文中已配置外部渲染插件的代码块
+func extractRenderBlocks(content string) []fencedBlock {
+	return extractFencedBlocks(content, func(lang string) bool {
+		return lang != "" && renderer.Lookup(lang) != nil
+	})
+}
+
+// ListShareRenders 返回正文中由外部渲染插件渲染的代码块及其图片地址；渲染在首次请求图片时进行
+func ListShareRenders(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	base := getBaseURL(c) + "/api/s/" + share.ID + "/renders/"
+	items := []gin.H{}
+	content, _ := renderShareContent(c, share)
+	for _, b := range extractRenderBlocks(content) {
+		hash := renderer.Hash(b.Source)
+		items = append(items, gin.H{
+			"index": b.Index,
+			"line":  b.Line,
+			"lang":  b.Lang,
+			"hash":  hash,
+			"url":   base + hash,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// ServeRender 输出外部渲染代码块的渲染结果。与白板绘图一样以图片方式嵌入，因此不校验访问密码；
+// 哈希须属于该分享的正文
+func ServeRender(c *gin.Context) {
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	if share.Status == models.ShareStatusDisabled && !share.InViewGrace() {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Share is disabled"})
+		return
+	}
+	if share.IsExpired() {
+		c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Share has expired"})
+		return
+	}
+
+	hash := c.Param("hash")
+	var block *fencedBlock
+	content, _ := renderShareContent(c, &share)
+	for _, b := range extractRenderBlocks(content) {
+		if renderer.Hash(b.Source) == hash {
+			block = &b
+			break
+		}
+	}
+	if block == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Rendered block not found"})
+		return
+	}
+	etag := `"` + block.Lang + "-" + hash + `"`
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	entry := renderer.Load(renderer.Lookup(block.Lang), block.Source)
+	if entry.Err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to render block: " + entry.Err.Error()})
+		return
+	}
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Header("X-Content-Type-Options", "nosniff")
+	if entry.ContentType == "image/svg+xml" {
+		// 外部程序输出的 SVG 禁止脚本与外部资源
+		c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; img-src data:; font-src data:")
+	}
+	c.Data(http.StatusOK, entry.ContentType, entry.Data)
+}
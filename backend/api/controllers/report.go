@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/alert"
+	"github.com/ZeroHawkeye/siyuan-share-api/challenge"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxReportLength 举报原因的最大字符数
+const maxReportLength = 2000
+
+// ReportShareRequest 读者举报分享
+type ReportShareRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Contact string `json:"contact" binding:"max=255"` // 联系方式（可选），便于管理员回复
+	Website string `json:"website"`                   // 蜜罐字段，正常读者不会填写
+}
+
+// ResolveReportRequest 管理员处理举报
+type ResolveReportRequest struct {
+	Action string `json:"action" binding:"required,oneof=dismiss disable ban"`
+	Note   string `json:"note" binding:"max=1000"`
+}
+
+// rejectModerated 分享被管理员停用时写入 403 并返回 true，所有者不能将其重新上线
+func rejectModerated(c *gin.Context, share *models.Share) bool {
+	if !share.Moderated() {
+		return false
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"code": CodeShareModerated,
+		"msg":  "Share was disabled by an administrator",
+		"data": gin.H{"moderatedAt": share.ModeratedAt},
+	})
+	return true
+}
+
+// ReportShare 读者举报分享，举报进入管理员的审核队列；同一 IP 对同一分享已有待处理的举报时不重复记录。
+// 收到举报时按 alert.reports 通知管理员（同一分享在冷却期内只通知一次）
+func ReportShare(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var req ReportShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" || utf8.RuneCountInString(reason) > maxReportLength {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Reason must be 1-2000 characters"})
+		return
+	}
+	if c.GetString("userID") == "" && !passChallenge(c, challenge.ActionReport) {
+		return
+	}
+	// 蜜罐字段被填写时照常返回，但不保存
+	if req.Website != "" {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+		return
+	}
+
+	ip := c.ClientIP()
+	var existing int64
+	models.DB.Model(&models.ShareReport{}).Where("share_id = ? AND ip = ? AND status = ?", share.ID, ip, models.ReportStatusOpen).Count(&existing)
+	if existing == 0 {
+		report := &models.ShareReport{
+			ID:         "rpt_" + randHex(10),
+			ShareID:    share.ID,
+			Reason:     reason,
+			Contact:    strings.TrimSpace(req.Contact),
+			ReporterID: c.GetString("userID"),
+			IP:         ip,
+			Status:     models.ReportStatusOpen,
+		}
+		if err := models.DB.Create(report).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save report: " + err.Error()})
+			return
+		}
+		auditLog(c, "share_reported", "share_id", share.ID, "report_id", report.ID)
+		alert.ShareReported(share.ID, share.DocTitle, getBaseURL(c)+"/s/"+share.ID)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// reportItem 审核队列中的举报，附带被举报分享的标题、状态与所有者
+type reportItem struct {
+	models.ShareReport
+	DocTitle    string     `json:"docTitle"`
+	ShareStatus string     `json:"shareStatus"`
+	ModeratedAt *time.Time `json:"moderatedAt,omitempty"`
+	OwnerID     string     `json:"ownerId"`
+	Owner       string     `json:"owner"`
+	OwnerActive bool       `json:"ownerActive"`
+}
+
+// ListReports 管理员查看举报，status 为 open（默认）、dismissed、actioned 或 all，按时间倒序分页
+func ListReports(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ReportStatusOpen)
+	page, size := 1, 50
+	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(c.Query("size")); err == nil && v > 0 && v <= 200 {
+		size = v
+	}
+	q := models.DB.Table("share_reports AS r").
+		Joins("LEFT JOIN shares s ON s.id = r.share_id").
+		Joins("LEFT JOIN users u ON u.id = s.user_id")
+	switch status {
+	case "all":
+	case models.ReportStatusOpen, models.ReportStatusDismissed, models.ReportStatusActioned:
+		q = q.Where("r.status = ?", status)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "status must be open, dismissed, actioned or all"})
+		return
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list reports: " + err.Error()})
+		return
+	}
+	items := []reportItem{}
+	if err := q.Select("r.*, s.doc_title, s.status AS share_status, s.moderated_at, s.user_id AS owner_id, u.username AS owner, u.is_active AS owner_active").
+		Order("r.created_at DESC").Offset((page - 1) * size).Limit(size).Scan(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list reports: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"items": items,
+		"total": total,
+		"page":  page,
+		"size":  size,
+	}})
+}
+
+// ResolveReport 管理员处理举报：dismiss 只驳回这条举报；disable 停用被举报的分享（含引用块子分享）；
+// ban 停用所有者账号、撤销其登录会话与 API Token 并停用其全部分享。被停用的分享所有者不能重新上线，
+// 相关分享的其他待处理举报一并标记为已处理
+func ResolveReport(c *gin.Context) {
+	var req ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	var report models.ShareReport
+	if err := models.DB.Where("id = ?", c.Param("rid")).First(&report).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Report not found"})
+		return
+	}
+	if report.Status != models.ReportStatusOpen {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Report already resolved"})
+		return
+	}
+	adminID := c.GetString("userID")
+	note := strings.TrimSpace(req.Note)
+
+	if req.Action == models.ReportActionDismiss {
+		now := time.Now()
+		if err := models.DB.Model(&report).Updates(map[string]any{
+			"status": models.ReportStatusDismissed, "action": req.Action, "note": note, "resolved_by": adminID, "resolved_at": now,
+		}).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to resolve report: " + err.Error()})
+			return
+		}
+		auditLog(c, "report_resolved", "report_id", report.ID, "share_id", report.ShareID, "action", req.Action, "admin_id", adminID)
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"id": report.ID, "action": req.Action}})
+		return
+	}
+
+	var share models.Share
+	if err := models.DB.Unscoped().Where("id = ?", report.ShareID).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	shares := []models.Share{share}
+	var owner models.User
+	if req.Action == models.ReportActionBan {
+		if err := models.DB.Where("id = ?", share.UserID).First(&owner).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "User not found"})
+			return
+		}
+		if config.Get().IsAdmin(owner.Username) {
+			c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Cannot ban an administrator"})
+			return
+		}
+		if err := models.DB.Where("user_id = ? AND parent_share_id = ?", owner.ID, "").Find(&shares).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to resolve report: " + err.Error()})
+			return
+		}
+	}
+
+	var sessions, tokens int64
+	disabled := make([]string, 0, len(shares))
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for i := range shares {
+			s := &shares[i]
+			if s.Status != models.ShareStatusDisabled {
+				if err := setShareStatus(tx, s, models.ShareStatusDisabled); err != nil {
+					return err
+				}
+			}
+			if err := tx.Model(&models.Share{}).Where("id = ? OR (parent_share_id = ? AND user_id = ?)", s.ID, s.ID, s.UserID).
+				Update("moderated_at", now).Error; err != nil {
+				return err
+			}
+			disabled = append(disabled, s.ID)
+		}
+		if req.Action == models.ReportActionBan {
+			var err error
+			if sessions, tokens, err = models.DeactivateUser(tx, owner.ID); err != nil {
+				return err
+			}
+		}
+		return models.ResolveReports(tx, disabled, req.Action, note, adminID)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to resolve report: " + err.Error()})
+		return
+	}
+	if req.Action == models.ReportActionBan {
+		auditLog(c, "user_banned", "user_id", owner.ID, "username", owner.Username, "report_id", report.ID,
+			"shares", len(disabled), "sessions_revoked", sessions, "tokens_revoked", tokens, "admin_id", adminID)
+	} else {
+		auditLog(c, "report_resolved", "report_id", report.ID, "share_id", share.ID, "action", req.Action, "admin_id", adminID)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"id":             report.ID,
+		"action":         req.Action,
+		"disabledShares": disabled,
+	}})
+}
+
+// ReinstateShare 管理员解除对分享的停用，分享保持停用状态，由所有者决定是否重新上线
+func ReinstateShare(c *gin.Context) {
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	if !share.Moderated() {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Share was not disabled by an administrator"})
+		return
+	}
+	if err := models.DB.Model(&models.Share{}).Where("id = ? OR (parent_share_id = ? AND user_id = ?)", share.ID, share.ID, share.UserID).
+		Update("moderated_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to reinstate share: " + err.Error()})
+		return
+	}
+	auditLog(c, "share_reinstated", "share_id", share.ID, "admin_id", c.GetString("userID"))
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
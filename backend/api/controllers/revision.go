@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/archive"
+	"github.com/ZeroHawkeye/siyuan-share-api/embedding"
+	"github.com/ZeroHawkeye/siyuan-share-api/langcheck"
+	"github.com/ZeroHawkeye/siyuan-share-api/linkpreview"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/summarize"
+	"github.com/ZeroHawkeye/siyuan-share-api/translate"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListShareRevisions 列出分享的历史版本（不含正文），按版本号倒序，第一项为当前版本
+func ListShareRevisions(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	items := []models.ShareRevision{}
+	if err := models.DB.Scopes(models.WithoutRevisionContent).Where("share_id = ?", share.ID).
+		Order("version DESC").Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list revisions: " + err.Error()})
+		return
+	}
+	current := 0
+	if len(items) > 0 {
+		current = items[0].Version
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items, "current": current}})
+}
+
+func loadOwnedRevision(c *gin.Context) (*models.Share, *models.ShareRevision, bool) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return nil, nil, false
+	}
+	var rev models.ShareRevision
+	if err := models.DB.Where("id = ? AND share_id = ?", c.Param("rid"), share.ID).First(&rev).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Revision not found"})
+		return nil, nil, false
+	}
+	return share, &rev, true
+}
+
+// GetShareRevision 查看历史版本的完整内容
+func GetShareRevision(c *gin.Context) {
+	_, rev, ok := loadOwnedRevision(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": rev})
+}
+
+// RollbackShare 将分享恢复到指定历史版本，恢复结果记录为新版本，之后仍可再次回滚；
+// 回滚用于撤销误操作，不通知订阅者
+func RollbackShare(c *gin.Context) {
+	share, rev, ok := loadOwnedRevision(c)
+	if !ok || rejectSealed(c, share) {
+		return
+	}
+	rev.Apply(share)
+
+	var refs []BlockReferenceReq
+	if share.References != "" {
+		_ = json.Unmarshal([]byte(share.References), &refs)
+	}
+
+	var created *models.ShareRevision
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(share).Error; err != nil {
+			return fmt.Errorf("failed to update share: %w", err)
+		}
+		for _, ref := range refs {
+			if err := upsertBlockShare(tx, share, ref); err != nil {
+				return fmt.Errorf("failed to save referenced block %s: %w", ref.BlockID, err)
+			}
+		}
+		var err error
+		if created, err = models.RecordRevision(tx, share, models.RevisionRollback, rev.Version); err != nil {
+			return fmt.Errorf("failed to save revision: %w", err)
+		}
+		if err := models.SyncShareIndex(tx, share); err != nil {
+			return fmt.Errorf("failed to update search index: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Rollback failed, no changes applied: " + err.Error()})
+		return
+	}
+
+	linkpreview.Warm(linkpreview.ExtractBareLinks(share.Content))
+	if share.ArchiveLinks {
+		archive.Snapshot(share.ID, share.Content)
+	}
+	autoNarrate(share)
+	summarize.Auto(share)
+	embedding.Auto(share)
+	translate.Auto(share)
+	langcheck.Auto(share)
+
+	created.Content = ""
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": created})
+}
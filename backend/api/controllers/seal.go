@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// SealShareRequest 封存分享请求
+type SealShareRequest struct {
+	RetentionDays int `json:"retentionDays" binding:"required,min=1,max=3650"` // 保留天数，从现在起算
+}
+
+// rejectSealed 分享处于封存保留期内时写入 409 响应并返回 true
+func rejectSealed(c *gin.Context, share *models.Share) bool {
+	if !share.Sealed() {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"code": CodeShareSealed,
+		"msg":  "Share is sealed",
+		"data": gin.H{"sealedUntil": share.SealedUntil},
+	})
+	return true
+}
+
+// loadMutableShare 同 loadOwnedShare，分享处于封存保留期内时返回 409
+func loadMutableShare(c *gin.Context) (*models.Share, bool) {
+	share, ok := loadOwnedShare(c)
+	if !ok || rejectSealed(c, share) {
+		return nil, false
+	}
+	return share, true
+}
+
+// sealPayload 封存信息：封存时间、保留期限与正文哈希，intact 表示当前正文与封存时的哈希一致
+func sealPayload(share *models.Share) gin.H {
+	if share.SealedAt == nil {
+		return nil
+	}
+	return gin.H{
+		"sealed":      share.Sealed(),
+		"sealedAt":    share.SealedAt,
+		"sealedUntil": share.SealedUntil,
+		"algorithm":   "sha256",
+		"hash":        share.SealHash,
+		"intact":      models.SealDigest(share.Content) == share.SealHash,
+	}
+}
+
+// SealShare 封存已发布的分享：记录封存时间与正文哈希，保留期满前不能修改正文、标题、访问设置、停用或删除，
+// 读者可据此校验内容未被改动。已封存的分享再次封存只能延长保留期限；引用块子分享随主分享一并封存
+func SealShare(c *gin.Context) {
+	var req SealShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if share.ParentShareID != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Block shares are sealed with their parent"})
+		return
+	}
+	if !share.Reachable() || share.IsExpired() {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Only published shares can be sealed"})
+		return
+	}
+	if share.MaxViews > 0 {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Shares with a view limit cannot be sealed"})
+		return
+	}
+	until := time.Now().AddDate(0, 0, req.RetentionDays)
+	if share.SealedUntil != nil && !until.After(*share.SealedUntil) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Retention can only be extended"})
+		return
+	}
+	if err := models.SealShare(share, until); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to seal share: " + err.Error()})
+		return
+	}
+	if err := models.DB.First(share, "id = ?", share.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load share: " + err.Error()})
+		return
+	}
+	auditLog(c, "share_sealed", "share_id", share.ID, "sealed_until", until, "hash", share.SealHash)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": sealPayload(share)})
+}
+
+// GetShareSeal 读者查看分享的封存信息，用于校验内容
+func GetShareSeal(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	payload := sealPayload(share)
+	if payload == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share is not sealed"})
+		return
+	}
+	payload["sourceUrl"] = getBaseURL(c) + "/api/s/" + share.ID + "/seal/source"
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": payload})
+}
+
+// GetShareSealSource 封存分享的正文 Markdown 源文，其 SHA-256 即封存哈希
+func GetShareSealSource(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	if share.SealedAt == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share is not sealed"})
+		return
+	}
+	c.Header("X-Content-SHA256", models.SealDigest(share.Content))
+	c.Header("Content-Disposition", `inline; filename="`+share.ID+`.md"`)
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(share.Content))
+}
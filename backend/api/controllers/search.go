@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// SearchShares 站内公开搜索：仅检索标记为 listed 且可匿名访问的分享
+func SearchShares(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Query parameter q is required"})
+		return
+	}
+	if len([]rune(q)) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Query too long"})
+		return
+	}
+
+	page := 1
+	size := 10
+	if p := c.Query("page"); p != "" {
+		if v, err := strconv.Atoi(p); err == nil && v > 0 {
+			page = v
+		}
+	}
+	if s := c.Query("size"); s != "" {
+		if v, err := strconv.Atoi(s); err == nil && v > 0 {
+			if v > 50 {
+				v = 50
+			}
+			size = v
+		}
+	}
+
+	hits, total, err := models.SearchListedShares(q, (page-1)*size, size)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Search failed: " + err.Error()})
+		return
+	}
+
+	baseURL := getBaseURL(c)
+	items := make([]gin.H, 0, len(hits))
+	for _, h := range hits {
+		items = append(items, gin.H{
+			"id":        h.ID,
+			"docTitle":  h.DocTitle,
+			"snippet":   h.Snippet,
+			"createdAt": h.CreatedAt,
+			"shareUrl":  baseURL + "/s/" + h.ID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "success",
+		"data": gin.H{
+			"items": items,
+			"page":  page,
+			"size":  size,
+			"total": total,
+		},
+	})
+}
@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/embedding"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// semanticLimit 解析 limit 参数（1-50，默认 def）
+func semanticLimit(c *gin.Context, def int) int {
+	if v, err := strconv.Atoi(c.Query("limit")); err == nil && v > 0 {
+		return min(v, 50)
+	}
+	return def
+}
+
+// semanticItems 补充匹配分享的标题与地址，保持相似度顺序
+func semanticItems(c *gin.Context, matches []embedding.Match) ([]gin.H, error) {
+	ids := make([]string, 0, len(matches))
+	for _, m := range matches {
+		ids = append(ids, m.ShareID)
+	}
+	var shares []models.Share
+	if err := models.DB.Select("id", "doc_title", "summary", "status", "created_at").Where("id IN ?", ids).Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*models.Share, len(shares))
+	for i := range shares {
+		byID[shares[i].ID] = &shares[i]
+	}
+	baseURL := getBaseURL(c)
+	items := make([]gin.H, 0, len(matches))
+	for _, m := range matches {
+		s, ok := byID[m.ShareID]
+		if !ok {
+			continue
+		}
+		items = append(items, gin.H{
+			"id":        s.ID,
+			"docTitle":  s.DocTitle,
+			"summary":   s.Summary,
+			"status":    s.Status,
+			"score":     m.Score,
+			"createdAt": s.CreatedAt,
+			"shareUrl":  baseURL + "/s/" + s.ID,
+		})
+	}
+	return items, nil
+}
+
+// requireEmbedding 未配置向量接口时返回 501
+func requireEmbedding(c *gin.Context) bool {
+	if !embedding.Enabled() {
+		c.JSON(http.StatusNotImplemented, gin.H{"code": 1, "msg": "Semantic search is not configured"})
+		return false
+	}
+	return true
+}
+
+// RelatedShares 与指定分享内容相近的本人其他分享
+func RelatedShares(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok || !requireEmbedding(c) {
+		return
+	}
+	matches, err := embedding.Related(share.UserID, share.ID, semanticLimit(c, 5))
+	if errors.Is(err, embedding.ErrNotIndexed) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to find related shares: " + err.Error()})
+		return
+	}
+	items, err := semanticItems(c, matches)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to find related shares: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// SemanticSearch 按语义搜索本人的分享
+func SemanticSearch(c *gin.Context) {
+	if !requireEmbedding(c) {
+		return
+	}
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Query parameter q is required"})
+		return
+	}
+	if len([]rune(q)) > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Query too long"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.Get().Embedding.Timeout.Std())
+	defer cancel()
+	matches, err := embedding.Search(ctx, c.GetString("userID"), q, semanticLimit(c, 10))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Semantic search failed: " + err.Error()})
+		return
+	}
+	items, err := semanticItems(c, matches)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Semantic search failed: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// ReindexShares 在后台为本人尚未索引或内容已变化的分享计算向量
+func ReindexShares(c *gin.Context) {
+	if !requireEmbedding(c) {
+		return
+	}
+	n, err := embedding.Reindex(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to reindex shares: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"shares": n}})
+}
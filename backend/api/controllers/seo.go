@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// sitemapLimit 单个 sitemap 文件最多包含的地址数（sitemaps.org 协议上限）
+const sitemapLimit = 50000
+
+// defaultRobotsRules 默认 robots.txt 规则：阅读页需要通过 /api/s/ 加载正文与图片，其余接口与管理页面不必抓取
+const defaultRobotsRules = `User-agent: *
+Allow: /api/s/
+Disallow: /api/
+Disallow: /dashboard
+Disallow: /shares
+Disallow: /reset-password
+`
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// shareNoIndex 分享页是否应禁止搜索引擎收录：站点关闭收录、分享不公开列出、分享者关闭了收录或为端到端加密分享
+func shareNoIndex(share *models.Share) bool {
+	return config.Get().Content.NoIndex || share.NoIndex || share.Status == models.ShareStatusUnlisted || share.Encrypted()
+}
+
+// Robots 输出 robots.txt
+func Robots(c *gin.Context) {
+	var b strings.Builder
+	if config.Get().Content.NoIndex {
+		b.WriteString("User-agent: *\nDisallow: /\n")
+	} else {
+		rules := strings.TrimSpace(config.Get().Content.RobotsTxt)
+		if rules == "" {
+			rules = defaultRobotsRules
+		}
+		b.WriteString(strings.TrimRight(rules, "\n") + "\n")
+		b.WriteString("\nSitemap: " + getBaseURL(c) + "/sitemap.xml\n")
+	}
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(b.String()))
+}
+
+// Sitemap 输出公开收录分享的 sitemap.xml，排除需要密码、仅限名单访问、已过期或关闭收录的分享。
+// 生成结果缓存到有分享发布、修改或删除为止
+func Sitemap(c *gin.Context) {
+	if config.Get().Content.NoIndex {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Sitemap disabled"})
+		return
+	}
+
+	baseURL := getBaseURL(c)
+	key := "sitemap:" + baseURL
+	if body, ok := models.LoadListing(key); ok {
+		writeSitemap(c, body)
+		return
+	}
+
+	builtAt := time.Now()
+	var shares []models.Share
+	if err := models.DB.Select("id", "updated_at", "expire_at").
+		Where("listed = ? AND no_index = ? AND is_public = ? AND status = ? AND require_password = ? AND restricted = ? AND access_schedule = ? AND terms = ? AND paywall = ? AND teaser = ? AND max_views = ? AND expire_at > ?",
+			true, false, true, models.ShareStatusPublished, false, false, "", "", false, false, 0, time.Now()).
+		Where("user_id IN (?)", models.DB.Model(&models.User{}).Select("id").Where("is_active = ?", true)).
+		Order("updated_at DESC").Limit(sitemapLimit).Find(&shares).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load sitemap: " + err.Error()})
+		return
+	}
+
+	set := sitemapURLSet{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9", URLs: make([]sitemapURL, 0, len(shares))}
+	for _, s := range shares {
+		set.URLs = append(set.URLs, sitemapURL{
+			Loc:     baseURL + "/s/" + s.ID,
+			LastMod: s.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	out, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to render sitemap"})
+		return
+	}
+	body := append([]byte(xml.Header), out...)
+	models.SaveListing(key, builtAt, earliestExpiry(shares), body)
+	writeSitemap(c, body)
+}
+
+func writeSitemap(c *gin.Context, body []byte) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/xml; charset=utf-8", body)
+}
+
+// earliestExpiry 列表中最早过期的分享的过期时间，缓存的列表在此之后需要重新生成
+func earliestExpiry(shares []models.Share) time.Time {
+	var t time.Time
+	for _, s := range shares {
+		if t.IsZero() || s.ExpireAt.Before(t) {
+			t = s.ExpireAt
+		}
+	}
+	return t
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ListSessions 列出当前用户的有效登录会话，current 标记发起请求的会话
+func ListSessions(c *gin.Context) {
+	userID := c.GetString("userID")
+	var sessions []models.Session
+	if err := models.DB.Where("user_id = ? AND revoked = ? AND expires_at > ?", userID, false, time.Now()).
+		Order("last_seen_at DESC").Find(&sessions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list sessions: " + err.Error()})
+		return
+	}
+	current := c.GetString("sessionID")
+	list := make([]gin.H, 0, len(sessions))
+	for _, s := range sessions {
+		list = append(list, gin.H{
+			"id": s.ID, "method": s.Method, "userAgent": s.UserAgent, "ip": s.IP,
+			"lastSeenAt": s.LastSeenAt, "expiresAt": s.ExpiresAt, "createdAt": s.CreatedAt,
+			"current": s.ID == current,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": list}})
+}
+
+// RevokeSession 撤销指定会话，该会话的 JWT 立即失效
+func RevokeSession(c *gin.Context) {
+	result := models.DB.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked = ?", c.Param("id"), c.GetString("userID"), false).
+		Update("revoked", true)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to revoke session: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Session not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// RevokeAllSessions 撤销当前用户的全部会话；默认保留发起请求的会话，?includeCurrent=true 时一并撤销
+func RevokeAllSessions(c *gin.Context) {
+	query := models.DB.Model(&models.Session{}).Where("user_id = ? AND revoked = ?", c.GetString("userID"), false)
+	if current := c.GetString("sessionID"); current != "" && c.Query("includeCurrent") != "true" {
+		query = query.Where("id <> ?", current)
+	}
+	result := query.Update("revoked", true)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to revoke sessions: " + result.Error.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"revoked": result.RowsAffected}})
+}
+
+// Logout 撤销当前会话（使用 API Token 认证时无操作）
+func Logout(c *gin.Context) {
+	if sessionID := c.GetString("sessionID"); sessionID != "" {
+		models.DB.Model(&models.Session{}).Where("id = ?", sessionID).Update("revoked", true)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// revokeUserSessions 撤销用户的全部会话（重置密码等安全操作后调用）
+func revokeUserSessions(userID string) error {
+	return models.DB.Model(&models.Session{}).Where("user_id = ? AND revoked = ?", userID, false).Update("revoked", true).Error
+}
+
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -1,31 +1,55 @@
 package controllers
 
 import (
-	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/ZeroHawkeye/siyuan-share-api/archive"
+	"github.com/ZeroHawkeye/siyuan-share-api/embedding"
+	"github.com/ZeroHawkeye/siyuan-share-api/events"
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
+	"github.com/ZeroHawkeye/siyuan-share-api/langcheck"
+	"github.com/ZeroHawkeye/siyuan-share-api/linkpreview"
 	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/ZeroHawkeye/siyuan-share-api/summarize"
+	"github.com/ZeroHawkeye/siyuan-share-api/translate"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
+// maxDocTitle 文档标题的最大字符数
+const maxDocTitle = 255
+
 // CreateShareRequest 创建分享请求
 type CreateShareRequest struct {
 	DocID           string              `json:"docId" binding:"required"`
 	DocTitle        string              `json:"docTitle" binding:"required"`
 	Content         string              `json:"content" binding:"required"`
-	RequirePassword bool                `json:"requirePassword"`
+	RequirePassword *bool               `json:"requirePassword"`
 	Password        string              `json:"password"`
-	ExpireDays      int                 `json:"expireDays" binding:"required,min=1,max=365"`
-	IsPublic        bool                `json:"isPublic"`
-	References      []BlockReferenceReq `json:"references"` // 引用块数据
+	ExpireDays      int                 `json:"expireDays" binding:"omitempty,min=1,max=365"` // 使用的模板设置了有效天数时可不传
+	IsPublic        *bool               `json:"isPublic"`
+	Listed          *bool               `json:"listed"`       // 是否收录到站内公开搜索
+	TemplateID      string              `json:"templateId"`   // 发布模板，未传入的设置取模板的值；不传时新建分享使用默认模板
+	References      []BlockReferenceReq `json:"references"`   // 引用块数据
+	ArchiveLinks    *bool               `json:"archiveLinks"` // 是否存档正文引用的外部链接，不传则保持原设置
+	Citations       json.RawMessage     `json:"citations"`    // CSL-JSON 文献数组（文献引用插件导出），不传则保持原数据
+	Mode            string              `json:"mode" binding:"omitempty,oneof=doc flashcards encrypted"`
+	Flashcards      json.RawMessage     `json:"flashcards"`                                                // 闪卡模式下的卡片数组
+	Drawings        json.RawMessage     `json:"drawings"`                                                  // 白板绘图数组（Excalidraw 场景），不传则保持原数据
+	Status          string              `json:"status" binding:"omitempty,oneof=draft published unlisted"` // 新建默认为 published，不传则保持原状态
 }
 
 // BlockReferenceReq 引用块请求数据
@@ -45,9 +69,70 @@ type CreateShareResponse struct {
 	RequirePassword bool      `json:"requirePassword"`
 	ExpireAt        time.Time `json:"expireAt"`
 	IsPublic        bool      `json:"isPublic"`
+	Mode            string    `json:"mode"`
+	Status          string    `json:"status"`
 	CreatedAt       time.Time `json:"createdAt"`
 	UpdatedAt       time.Time `json:"updatedAt"`
 	Reused          bool      `json:"reused"`
+	// MissingAssets 正文引用但尚未上传的资源，插件应上传或提示作者，否则阅读页中这些图片与附件无法显示
+	MissingAssets []MissingAsset `json:"missingAssets"`
+	// QuotaWarnings 用量达到 80% 或已超出配额（处于宽限中）的配额，插件据此提示作者；均正常时省略
+	QuotaWarnings []models.QuotaStatus `json:"quotaWarnings,omitempty"`
+}
+
+// UpdateShareRequest 局部更新分享元数据请求（仅更新提供的字段，不涉及内容）
+type UpdateShareRequest struct {
+	DocTitle        *string   `json:"docTitle"`
+	Tags            *[]string `json:"tags"`
+	Summary         *string   `json:"summary"` // 作者填写的摘要，清空后恢复自动生成
+	IsPublic        *bool     `json:"isPublic"`
+	Restricted      *bool     `json:"restricted"`
+	Listed          *bool     `json:"listed"`
+	NoIndex         *bool     `json:"noIndex"`
+	AllowAnnotation *bool     `json:"allowAnnotation"`
+	AllowComments   *bool     `json:"allowComments"`
+	AllowFeedback   *bool     `json:"allowFeedback"`
+	TrackReading    *bool     `json:"trackReading"` // 统计读者读到了哪个标题
+	AllowPDF        *bool     `json:"allowPdf"`
+	ArchiveLinks    *bool     `json:"archiveLinks"`
+	AllowQA         *bool     `json:"allowQa"`
+	AssetDownloads  *string   `json:"assetDownloads"` // 附件下载策略：allow / inline / password
+	ExpireDays      *int      `json:"expireDays"`
+	Theme           *string   `json:"theme"`
+	TOC             *string   `json:"toc"` // 目录样式：side / floating / off
+	HeadingAnchors  *bool     `json:"headingAnchors"`
+	RequirePassword *bool     `json:"requirePassword"`
+	Password        *string   `json:"password"`
+	// Variant 主题 A/B 测试，theme 与 toc 都为空时关闭
+	Variant *ShareVariantRequest `json:"variant"`
+	// AccessSchedule 开放时间，传入不含日期范围与每日时段的对象时取消限制
+	AccessSchedule *models.AccessSchedule `json:"accessSchedule"`
+	// Terms 使用条款（Markdown），读者同意后才能阅读；空字符串取消
+	Terms *string `json:"terms"`
+	// Paywall 付费阅读，开启时需同时提供价格与支付页面地址（或已设置过）；服务器须配置 payment.webhook_secret
+	Paywall      *bool   `json:"paywall"`
+	PaywallPrice *string `json:"paywallPrice"`
+	PaywallURL   *string `json:"paywallUrl"` // 支付页面地址，须包含 {order}，读者跳转时替换为订单 ID
+	// Teaser 试读模式：未通过密码、付费或登录校验的读者只能看到 <!-- more --> 之前的部分
+	Teaser *bool `json:"teaser"`
+	// MaxViews 浏览次数上限（含已有的浏览次数），用尽后自动停用；1 为阅后即焚，0 不限
+	MaxViews *int `json:"maxViews"`
+	// Prerender 服务端预渲染公式、Mermaid 图与代码高亮，只修改传入的项
+	Prerender *PrerenderSettings `json:"prerender"`
+}
+
+// BatchShareRequest 批量操作分享请求
+type BatchShareRequest struct {
+	Action     string   `json:"action" binding:"required,oneof=delete disable enable extend"`
+	IDs        []string `json:"ids" binding:"required,min=1,max=500"`
+	ExpireDays int      `json:"expireDays"` // extend 时必填，在当前有效期基础上延长的天数
+}
+
+// BatchShareResult 单条批量操作结果
+type BatchShareResult struct {
+	ID    string `json:"id"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
 }
 
 // BatchDeleteShareRequest 批量关闭分享请求
@@ -74,53 +159,196 @@ func CreateShare(c *gin.Context) {
 		return
 	}
 
-	// 获取用户 ID（从认证中间件）
-	userID, _ := c.Get("userID")
-	userIDStr := userID.(string)
+	share, reused, ok := publishShare(c, c.GetString("userID"), &req, "")
+	if !ok {
+		return
+	}
+	respondPublished(c, share, reused)
+}
 
+// publishShare 按创建分享请求新建或更新（同一文档已有分享时）分享，newID 非空时作为新建分享的 ID；
+// 校验或保存失败时已写入响应
+func publishShare(c *gin.Context, userIDStr string, req *CreateShareRequest, newID string) (*models.Share, bool, bool) {
 	existingShare, err := models.FindActiveShareByDoc(userIDStr, req.DocID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"code": 1,
 			"msg":  "Failed to query share: " + err.Error(),
 		})
-		return
+		return nil, false, false
 	}
 
 	// 若已有分享但已过期，则视为无效
 	if existingShare != nil && existingShare.IsExpired() {
 		existingShare = nil
 	}
+	// 封存的分享在保留期内不能重新发布，被管理员停用的分享在恢复前不能重新发布
+	if existingShare != nil && (rejectSealed(c, existingShare) || rejectModerated(c, existingShare)) {
+		return nil, false, false
+	}
+
+	template, ok := resolveShareTemplate(c, userIDStr, req.TemplateID, existingShare == nil)
+	if !ok {
+		return nil, false, false
+	}
+	if template != nil {
+		applyShareTemplateDefaults(req, template)
+	}
+	if req.ExpireDays == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code": 1,
+			"msg":  "expireDays must be between 1 and 365",
+		})
+		return nil, false, false
+	}
+	// 标题统一为 NFC 并去除不可见字符；只有空白时取正文第一行文字，过长时按字符（而非字节）截断
+	req.DocTitle = slug.Title(req.DocTitle)
+	if req.DocTitle == "" && req.Mode != models.ShareModeEncrypted {
+		req.DocTitle = slug.FromContent(req.Content, 80)
+	}
+	if req.DocTitle == "" {
+		req.DocTitle = req.DocID
+	}
+	req.DocTitle = slug.Truncate(req.DocTitle, maxDocTitle)
+	requirePassword := req.RequirePassword != nil && *req.RequirePassword
+
+	citations, hasCitations, err := normalizeCitations(req.Citations)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code": 1,
+			"msg":  "Invalid citations: " + err.Error(),
+		})
+		return nil, false, false
+	}
+
+	drawings, hasDrawings, err := normalizeDrawings(req.Drawings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code": 1,
+			"msg":  "Invalid drawings: " + err.Error(),
+		})
+		return nil, false, false
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = models.ShareModeDoc
+	}
+	var flashcards string
+	if mode == models.ShareModeFlashcards {
+		var count int
+		flashcards, count, err = normalizeFlashcards(req.Flashcards)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code": 1,
+				"msg":  "Invalid flashcards: " + err.Error(),
+			})
+			return nil, false, false
+		}
+		if count == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code": 1,
+				"msg":  "Flashcard deck is empty",
+			})
+			return nil, false, false
+		}
+	}
+
+	// 加密分享：服务器只保存密文，以明文保存的引用块、文献与白板不能随加密分享发布；
+	// 已有分享不能在加密与普通模式之间切换，否则旧的明文修订、译文与摘要仍会留在服务器上
+	encrypted := mode == models.ShareModeEncrypted
+	if encrypted {
+		if !models.ValidEncryptedContent(req.Content) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code": 1,
+				"msg":  "Invalid encrypted content",
+			})
+			return nil, false, false
+		}
+		if len(req.References) > 0 || hasCitations || hasDrawings {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code": 1,
+				"msg":  "Encrypted shares cannot include references or drawings",
+			})
+			return nil, false, false
+		}
+	}
+	if existingShare != nil && existingShare.Encrypted() != encrypted {
+		c.JSON(http.StatusConflict, gin.H{
+			"code": 1,
+			"msg":  "Cannot switch a share to or from encrypted mode",
+		})
+		return nil, false, false
+	}
 
 	password := strings.TrimSpace(req.Password)
 
-	if req.RequirePassword {
+	if requirePassword {
 		if password != "" && len(password) < 4 {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"code": 1,
 				"msg":  "Password must be at least 4 characters",
 			})
-			return
+			return nil, false, false
 		}
-		if password == "" {
+		if password == "" && (template == nil || template.PasswordHash == "") {
 			if existingShare == nil || existingShare.PasswordHash == "" {
 				c.JSON(http.StatusBadRequest, gin.H{
 					"code": 1,
 					"msg":  "Password must be provided for new share",
 				})
-				return
+				return nil, false, false
+			}
+		}
+	}
+
+	// 已发布过的分享不能再保存为草稿
+	if existingShare != nil && req.Status != "" && !models.CanTransitionStatus(existingShare.Status, req.Status) {
+		c.JSON(http.StatusConflict, gin.H{
+			"code": 1,
+			"msg":  fmt.Sprintf("Cannot change status from %s to %s", existingShare.Status, req.Status),
+		})
+		return nil, false, false
+	}
+
+	// 新建分享时检查分享数配额，更新已有分享不受限制
+	if existingShare == nil {
+		if quota := models.UserQuota(userIDStr); quota.MaxShares > 0 {
+			usage, err := models.UserUsage(userIDStr)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"code": 1,
+					"msg":  "Failed to query usage: " + err.Error(),
+				})
+				return nil, false, false
+			}
+			if usage.Shares >= models.GraceLimit(int64(quota.MaxShares)) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"code": CodeQuotaExceeded,
+					"msg":  "Share quota exceeded",
+					"data": gin.H{"usage": usage, "quota": quota},
+				})
+				return nil, false, false
 			}
 		}
 	}
 
 	var share *models.Share
+	var previous models.Share
+	fixedID := true // 导入压缩包时 ID 已写入正文中的资源地址，冲突时不能重新生成
 	reused := false
+	contentChanged := true
 	if existingShare != nil {
+		previous = *existingShare
 		share = existingShare
 		reused = true
 	} else {
+		if newID == "" {
+			newID = generateShareID()
+			fixedID = false
+		}
 		share = &models.Share{
-			ID:     generateShareID(),
+			ID:     newID,
 			UserID: userIDStr,
 			DocID:  req.DocID,
 		}
@@ -128,10 +356,44 @@ func CreateShare(c *gin.Context) {
 
 	share.DocTitle = req.DocTitle
 	share.Content = req.Content
-	share.RequirePassword = req.RequirePassword
-	share.IsPublic = req.IsPublic
+	share.RequirePassword = requirePassword
+	share.IsPublic = req.IsPublic != nil && *req.IsPublic
+	share.Listed = req.Listed != nil && *req.Listed && !encrypted
+	if template != nil {
+		if template.Theme != "" {
+			share.Theme = template.Theme
+		}
+		if template.TOC != "" {
+			share.TOC = template.TOC
+		}
+	}
+	if req.ArchiveLinks != nil {
+		share.ArchiveLinks = *req.ArchiveLinks && !encrypted
+	}
+	if hasCitations {
+		share.Citations = citations
+	}
+	share.Mode = mode
+	share.Flashcards = flashcards
+	if hasDrawings {
+		share.Drawings = drawings
+	}
+	if req.Status != "" {
+		share.Status = req.Status
+	} else if !reused {
+		share.Status = models.ShareStatusPublished
+	}
 	share.ExpireAt = time.Now().AddDate(0, 0, req.ExpireDays)
 
+	// 发布前钩子可拒绝发布或改写标题与正文，因此内容是否变化以钩子处理后的结果为准
+	if !runPrePublishHooks(c, share, reused) {
+		return nil, false, false
+	}
+	if reused {
+		contentChanged = previous.Content != share.Content || previous.DocTitle != share.DocTitle ||
+			previous.Flashcards != share.Flashcards || previous.Drawings != share.Drawings
+	}
+
 	// 处理引用块数据
 	if len(req.References) > 0 {
 		refsJSON, err := json.Marshal(req.References)
@@ -140,14 +402,14 @@ func CreateShare(c *gin.Context) {
 				"code": 1,
 				"msg":  "Failed to serialize references: " + err.Error(),
 			})
-			return
+			return nil, false, false
 		}
 		share.References = string(refsJSON)
 	} else {
 		share.References = ""
 	}
 
-	if req.RequirePassword {
+	if requirePassword {
 		if password != "" {
 			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 			if err != nil {
@@ -155,91 +417,107 @@ func CreateShare(c *gin.Context) {
 					"code": 1,
 					"msg":  "Failed to encrypt password",
 				})
-				return
+				return nil, false, false
 			}
 			share.PasswordHash = string(hashedPassword)
+		} else if template != nil && template.PasswordHash != "" && (share.PasswordHash == "" || req.TemplateID != "") {
+			// 未传入密码时使用模板的默认密码；未指定模板的更新沿用旧密码
+			share.PasswordHash = template.PasswordHash
 		}
 		// 若为空，则复用旧密码（已有校验保证可复用）
 	} else {
 		share.PasswordHash = ""
 	}
 
-	if reused {
-		if err := models.DB.Save(share).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"code": 1,
-				"msg":  "Failed to update share: " + err.Error(),
-			})
-			return
+	// 分享记录、引用块子分享、历史版本与搜索索引在同一事务中提交，任一步失败则整体回滚，
+	// 避免读者看到只发布了一半的分享；并发发布时随机生成的分享 ID 冲突则换一个 ID 重新提交
+	publish := func(tx *gorm.DB) error {
+		if reused && contentChanged {
+			if err := models.EnsureBaseRevision(tx, &previous); err != nil {
+				return fmt.Errorf("failed to save previous revision: %w", err)
+			}
 		}
-	} else {
-		if err := models.DB.Create(share).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"code": 1,
-				"msg":  "Failed to create share: " + err.Error(),
-			})
-			return
+		if reused {
+			if err := tx.Save(share).Error; err != nil {
+				return fmt.Errorf("failed to update share: %w", err)
+			}
+		} else {
+			if err := tx.Create(share).Error; err != nil {
+				return fmt.Errorf("failed to create share: %w", err)
+			}
 		}
-	}
 
-	// 构建分享 URL（双轨：自动推断 + 可被 X-Base-URL 覆盖）
-	baseURL := c.GetHeader("X-Base-URL")
-	if baseURL == "" {
-		// 优先使用代理头
-		proto := c.GetHeader("X-Forwarded-Proto")
-		host := c.GetHeader("X-Forwarded-Host")
-		if proto == "" {
-			if c.Request.TLS != nil {
-				proto = "https"
-			} else {
-				proto = "http"
+		// 为引用块创建子分享
+		for _, ref := range req.References {
+			if err := upsertBlockShare(tx, share, ref); err != nil {
+				return fmt.Errorf("failed to save referenced block %s: %w", ref.BlockID, err)
 			}
 		}
-		if host == "" {
-			host = c.Request.Host
+
+		if contentChanged {
+			if _, err := models.RecordRevision(tx, share, models.RevisionPublish, 0); err != nil {
+				return fmt.Errorf("failed to save revision: %w", err)
+			}
 		}
-		// 移除可能存在的尾部斜杠
-		baseURL = proto + "://" + strings.TrimSuffix(host, "/")
-	}
-	shareURL := strings.TrimSuffix(baseURL, "/") + "/s/" + share.ID
 
-	// 为引用块创建子分享
-	if len(req.References) > 0 {
-		for _, ref := range req.References {
-			// 检查是否已存在该块的分享(通过 docId = blockId 查找)
-			existingBlockShare, _ := models.FindActiveShareByDoc(userIDStr, ref.BlockID)
-
-			// 生成引用块标题
-			blockTitle := generateBlockTitle(ref)
-
-			var blockShare *models.Share
-			if existingBlockShare != nil && !existingBlockShare.IsExpired() {
-				// 更新已有的块分享
-				blockShare = existingBlockShare
-				blockShare.DocTitle = blockTitle
-				blockShare.Content = ref.Content
-				blockShare.ExpireAt = share.ExpireAt
-				blockShare.ParentShareID = share.ID
-				models.DB.Save(blockShare)
-			} else {
-				// 创建新的块分享
-				blockShare = &models.Share{
-					ID:            generateShareID(),
-					UserID:        userIDStr,
-					DocID:         ref.BlockID, // 使用 blockId 作为 docId
-					DocTitle:      blockTitle,
-					Content:       ref.Content,
-					ParentShareID: share.ID,
-					// 继承父分享的密码和过期时间
-					RequirePassword: share.RequirePassword,
-					PasswordHash:    share.PasswordHash,
-					ExpireAt:        share.ExpireAt,
-					IsPublic:        share.IsPublic,
-				}
-				models.DB.Create(blockShare)
+		if err := models.SyncShareIndex(tx, share); err != nil {
+			return fmt.Errorf("failed to update search index: %w", err)
+		}
+		return nil
+	}
+	err = models.CreateUnique(func() error { return models.DB.Transaction(publish) }, func(int) bool {
+		if !reused {
+			if fixedID {
+				return false
 			}
+			share.ID = generateShareID()
+		}
+		return true
+	}, "shares.id")
+	if errors.Is(err, models.ErrTaken) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Share ID already taken"})
+		return nil, false, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code": 1,
+			"msg":  "Publish failed, no changes applied: " + err.Error(),
+		})
+		return nil, false, false
+	}
+
+	// 重新发布且内容有变化时通知订阅者
+	if reused && contentChanged {
+		notifySubscribers(c, share)
+	}
+
+	// 预热正文中外部链接的预览缓存，并为新引用的链接保存存档副本；加密分享的正文不做任何处理
+	if !encrypted {
+		linkpreview.Warm(linkpreview.ExtractBareLinks(share.Content))
+		if share.ArchiveLinks {
+			archive.Snapshot(share.ID, share.Content)
 		}
+		autoNarrate(share)
+		summarize.Auto(share)
+		embedding.Auto(share)
+		translate.Auto(share)
+		langcheck.Auto(share)
+	}
+	firePostPublishHooks(c, share, reused)
+	events.Published(userIDStr, share.ID, share.DocTitle, reused, c.GetString("tokenID"))
+	models.RecordUsage(userIDStr, c.GetString("tokenID"), models.UsageDelta{Publishes: 1})
+
+	return share, reused, true
+}
+
+// respondPublished 返回发布结果，附带正文引用但尚未上传的资源
+func respondPublished(c *gin.Context, share *models.Share, reused bool) {
+	shareURL := getBaseURL(c) + "/s/" + share.ID
+	assets, err := checkAssets(share)
+	if err != nil {
+		log.Printf("asset check for share %s failed: %v", share.ID, err)
 	}
+	notify.CheckQuota(share.UserID, getBaseURL(c))
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -252,14 +530,400 @@ func CreateShare(c *gin.Context) {
 			RequirePassword: share.RequirePassword,
 			ExpireAt:        share.ExpireAt,
 			IsPublic:        share.IsPublic,
+			Mode:            share.Mode,
+			Status:          share.Status,
 			CreatedAt:       share.CreatedAt,
 			UpdatedAt:       share.UpdatedAt,
 			Reused:          reused,
+			MissingAssets:   assets.Missing,
+			QuotaWarnings:   quotaWarnings(share.UserID),
+		},
+	})
+}
+
+// upsertBlockShare 创建或更新引用块对应的子分享（使用 blockId 作为 docId）
+func upsertBlockShare(tx *gorm.DB, parent *models.Share, ref BlockReferenceReq) error {
+	// 生成引用块标题
+	blockTitle := generateBlockTitle(ref)
+
+	var existing models.Share
+	err := tx.Where("user_id = ? AND doc_id = ?", parent.UserID, ref.BlockID).Order("created_at DESC").First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil && !existing.IsExpired() {
+		// 更新已有的块分享
+		existing.DocTitle = blockTitle
+		existing.Content = ref.Content
+		existing.ExpireAt = parent.ExpireAt
+		existing.ParentShareID = parent.ID
+		existing.Status = parent.Status
+		return tx.Save(&existing).Error
+	}
+
+	// 创建新的块分享，继承父分享的密码、过期时间、开放时间、使用条款、付费阅读与状态
+	return tx.Create(&models.Share{
+		ID:              generateShareID(),
+		UserID:          parent.UserID,
+		DocID:           ref.BlockID,
+		DocTitle:        blockTitle,
+		Content:         ref.Content,
+		ParentShareID:   parent.ID,
+		RequirePassword: parent.RequirePassword,
+		PasswordHash:    parent.PasswordHash,
+		ExpireAt:        parent.ExpireAt,
+		IsPublic:        parent.IsPublic,
+		Restricted:      parent.Restricted,
+		AccessSchedule:  parent.AccessSchedule,
+		Terms:           parent.Terms,
+		Paywall:         parent.Paywall,
+		PaywallPrice:    parent.PaywallPrice,
+		PaywallURL:      parent.PaywallURL,
+		Teaser:          parent.Teaser,
+		Status:          parent.Status,
+	}).Error
+}
+
+// UpdateShare 局部更新分享的标题、标签、可见性、有效期、开放时间、主题与密码设置
+func UpdateShare(c *gin.Context) {
+	shareID := c.Param("id")
+	userID := c.GetString("userID")
+
+	var req UpdateShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+
+	var share models.Share
+	if err := models.DB.Where("id = ? AND user_id = ?", shareID, userID).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found or unauthorized"})
+		return
+	}
+
+	// 加密分享的正文只有持有密钥的读者能解密，依赖服务器读取正文的功能（站内搜索、PDF 导出、问答、链接存档、试读）
+	// 与会把正文片段以明文保存到服务器的批注不能开启
+	if share.Encrypted() {
+		for _, enabled := range []*bool{req.Listed, req.AllowPDF, req.AllowQA, req.ArchiveLinks, req.Teaser, req.AllowAnnotation} {
+			if enabled != nil && *enabled {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Not available for encrypted shares"})
+				return
+			}
+		}
+	}
+
+	updates := map[string]interface{}{}
+	if req.DocTitle != nil {
+		title := slug.Title(*req.DocTitle)
+		if title == "" || utf8.RuneCountInString(title) > maxDocTitle {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Title must be 1-255 characters"})
+			return
+		}
+		updates["doc_title"] = title
+	}
+	if req.Tags != nil {
+		tags, err := normalizeTags(*req.Tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+			return
+		}
+		updates["tags"] = models.EncodeTags(tags)
+	}
+	if req.Summary != nil {
+		summary := strings.Join(strings.Fields(*req.Summary), " ")
+		if utf8.RuneCountInString(summary) > summarize.MaxRunes {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Summary must be at most 300 characters"})
+			return
+		}
+		updates["summary"] = summary
+		updates["summary_manual"] = summary != ""
+		if summary == "" {
+			// 下次发布时重新生成
+			updates["summary_hash"] = ""
+		}
+	}
+	if req.IsPublic != nil {
+		updates["is_public"] = *req.IsPublic
+	}
+	if req.Restricted != nil {
+		updates["restricted"] = *req.Restricted
+	}
+	if req.Listed != nil {
+		updates["listed"] = *req.Listed
+	}
+	if req.NoIndex != nil {
+		updates["no_index"] = *req.NoIndex
+	}
+	if req.AllowAnnotation != nil {
+		updates["allow_annotation"] = *req.AllowAnnotation
+	}
+	if req.AllowComments != nil {
+		updates["allow_comments"] = *req.AllowComments
+	}
+	if req.AllowFeedback != nil {
+		updates["allow_feedback"] = *req.AllowFeedback
+	}
+	if req.TrackReading != nil {
+		updates["track_reading"] = *req.TrackReading
+	}
+	if req.AllowPDF != nil {
+		updates["allow_pdf"] = *req.AllowPDF
+	}
+	if req.ArchiveLinks != nil {
+		updates["archive_links"] = *req.ArchiveLinks
+	}
+	if req.AllowQA != nil {
+		updates["allow_qa"] = *req.AllowQA
+	}
+	if req.AssetDownloads != nil {
+		switch *req.AssetDownloads {
+		case models.AssetDownloadsAllow, models.AssetDownloadsInline, models.AssetDownloadsPassword:
+			updates["asset_downloads"] = *req.AssetDownloads
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "assetDownloads must be allow, inline or password"})
+			return
+		}
+	}
+	if req.AccessSchedule != nil {
+		schedule, err := models.EncodeSchedule(req.AccessSchedule)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+			return
+		}
+		updates["access_schedule"] = schedule
+	}
+	if req.Terms != nil {
+		terms := strings.TrimSpace(*req.Terms)
+		if !validTerms(terms) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": fmt.Sprintf("Terms must be at most %d characters", maxTermsLength)})
+			return
+		}
+		updates["terms"] = terms
+	}
+	if req.Paywall != nil || req.PaywallPrice != nil || req.PaywallURL != nil {
+		if msg := applyPaywallUpdate(&share, req.Paywall, req.PaywallPrice, req.PaywallURL, updates); msg != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": msg})
+			return
+		}
+	}
+	if req.Teaser != nil {
+		updates["teaser"] = *req.Teaser
+	}
+	if req.MaxViews != nil {
+		if *req.MaxViews < 0 || *req.MaxViews > maxViewsLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": fmt.Sprintf("maxViews must be between 0 and %d", maxViewsLimit)})
+			return
+		}
+		updates["max_views"] = *req.MaxViews
+		// 因浏览次数用尽而停用的分享，取消上限或提高到已有浏览次数之上时重新上线
+		if share.ViewsExhaustedAt != nil && (*req.MaxViews == 0 || *req.MaxViews > share.ViewCount) {
+			updates["views_exhausted_at"] = nil
+			if share.Status == models.ShareStatusDisabled && !share.Moderated() {
+				updates["status"] = models.ShareStatusPublished
+			}
+		}
+	}
+	if req.ExpireDays != nil {
+		if *req.ExpireDays < 1 || *req.ExpireDays > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "expireDays must be between 1 and 365"})
+			return
+		}
+		updates["expire_at"] = time.Now().AddDate(0, 0, *req.ExpireDays)
+	}
+	if req.Theme != nil {
+		theme, ok := normalizeShareTheme(c, userID, *req.Theme)
+		if !ok {
+			return
+		}
+		updates["theme"] = theme
+	}
+	if req.Variant != nil {
+		variant, ok := shareVariantUpdates(c, userID, req.Variant)
+		if !ok {
+			return
+		}
+		for k, v := range variant {
+			updates[k] = v
+		}
+	}
+	if req.TOC != nil {
+		switch *req.TOC {
+		case models.ShareTOCSide, models.ShareTOCFloating, models.ShareTOCOff:
+			updates["toc"] = *req.TOC
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "toc must be side, floating or off"})
+			return
+		}
+	}
+	if req.HeadingAnchors != nil {
+		updates["heading_anchors"] = *req.HeadingAnchors
+	}
+	if p := req.Prerender; p != nil {
+		if p.Math != nil {
+			updates["prerender_math"] = *p.Math
+		}
+		if p.Mermaid != nil {
+			updates["prerender_mermaid"] = *p.Mermaid
+		}
+		if p.Code != nil {
+			updates["prerender_code"] = *p.Code
+		}
+	}
+	if req.RequirePassword != nil {
+		if *req.RequirePassword {
+			password := ""
+			if req.Password != nil {
+				password = strings.TrimSpace(*req.Password)
+			}
+			if password == "" && share.PasswordHash == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Password must be provided"})
+				return
+			}
+			if password != "" {
+				if len(password) < 4 {
+					c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Password must be at least 4 characters"})
+					return
+				}
+				hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to encrypt password"})
+					return
+				}
+				updates["password_hash"] = string(hashed)
+			}
+			updates["require_password"] = true
+		} else {
+			updates["require_password"] = false
+			updates["password_hash"] = ""
+		}
+	}
+
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "No fields to update"})
+		return
+	}
+	// 封存期内只能调整互动与收录设置，有效期只能延长到保留期限之后
+	if share.Sealed() {
+		for k, v := range updates {
+			if expireAt, ok := v.(time.Time); ok && k == "expire_at" && !expireAt.Before(*share.SealedUntil) {
+				continue
+			}
+			if !models.SealedMutableColumns[k] && rejectSealed(c, &share) {
+				return
+			}
+		}
+	}
+
+	if err := models.DB.Model(&share).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update share: " + err.Error()})
+		return
+	}
+
+	_, titleChanged := updates["doc_title"]
+	_, listedChanged := updates["listed"]
+	if titleChanged || listedChanged {
+		if err := models.SyncShareIndex(models.DB, &share); err != nil {
+			log.Printf("search index update failed (%s): %v", share.ID, err)
+		}
+	}
+
+	// 开启链接存档时立即为当前正文中的链接创建存档
+	if req.ArchiveLinks != nil && *req.ArchiveLinks {
+		archive.Snapshot(share.ID, share.Content)
+	}
+
+	// 引用块子分享继承可见性、访问限制、有效期、开放时间、使用条款、付费阅读、试读模式与密码设置，以及浏览次数用尽后的重新上线
+	inherited := map[string]interface{}{}
+	for _, k := range []string{"is_public", "restricted", "expire_at", "access_schedule", "terms", "paywall", "paywall_price", "paywall_url", "teaser", "require_password", "password_hash", "status"} {
+		if v, ok := updates[k]; ok {
+			inherited[k] = v
+		}
+	}
+	if len(inherited) > 0 {
+		models.DB.Model(&models.Share{}).Where("parent_share_id = ? AND user_id = ?", share.ID, userID).Updates(inherited)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "success",
+		"data": gin.H{
+			"id":              share.ID,
+			"docId":           share.DocID,
+			"docTitle":        share.DocTitle,
+			"tags":            share.TagList(),
+			"theme":           share.Theme,
+			"toc":             share.TOC,
+			"variant":         share.Variant(),
+			"headingAnchors":  share.HeadingAnchors,
+			"prerender":       sharePrerenderSettings(&share),
+			"requirePassword": share.RequirePassword,
+			"expireAt":        share.ExpireAt,
+			"isPublic":        share.IsPublic,
+			"restricted":      share.Restricted,
+			"listed":          share.Listed,
+			"noIndex":         share.NoIndex,
+			"allowAnnotation": share.AllowAnnotation,
+			"allowComments":   share.AllowComments,
+			"allowFeedback":   share.AllowFeedback,
+			"trackReading":    share.TrackReading,
+			"allowPdf":        share.AllowPDF,
+			"archiveLinks":    share.ArchiveLinks,
+			"allowQa":         share.AllowQA,
+			"assetDownloads":  share.AssetDownloads,
+			"accessSchedule":  share.Schedule(),
+			"hasTerms":        share.Terms != "",
+			"paywall":         share.Paywall,
+			"paywallPrice":    share.PaywallPrice,
+			"paywallUrl":      share.PaywallURL,
+			"teaser":          share.Teaser,
+			"maxViews":        share.MaxViews,
+			"status":          share.Status,
+			"updatedAt":       share.UpdatedAt,
 		},
 	})
 }
 
+// loadOwnedShare 加载当前用户拥有的分享（路由参数 id）；不存在时已写入 404 响应并返回 false
+func loadOwnedShare(c *gin.Context) (*models.Share, bool) {
+	var share models.Share
+	if err := models.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetString("userID")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found or unauthorized"})
+		return nil, false
+	}
+	return &share, true
+}
+
+// normalizeTags 去除空白与重复标签，并限制数量与长度
+func normalizeTags(tags []string) ([]string, error) {
+	seen := map[string]bool{}
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		if len([]rune(t)) > 50 {
+			return nil, errors.New("Tag must be at most 50 characters")
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	if len(out) > 20 {
+		return nil, errors.New("At most 20 tags are allowed")
+	}
+	return out, nil
+}
+
+// shareSortColumns 分享列表允许的排序字段
+var shareSortColumns = map[string]string{
+	"created": "created_at",
+	"updated": "updated_at",
+	"views":   "view_count",
+	"title":   "doc_title",
+}
+
 // ListShares 获取用户的分享列表
+// 支持 page、size/pageSize 分页，q 标题搜索，sort 排序（created|updated|views|title，前缀 - 表示降序）
 func ListShares(c *gin.Context) {
 	userID, _ := c.Get("userID")
 
@@ -271,8 +935,12 @@ func ListShares(c *gin.Context) {
 			page = v
 		}
 	}
-	if s := c.Query("size"); s != "" {
-		if v, err := strconv.Atoi(s); err == nil && v > 0 {
+	sizeParam := c.Query("pageSize")
+	if sizeParam == "" {
+		sizeParam = c.Query("size")
+	}
+	if sizeParam != "" {
+		if v, err := strconv.Atoi(sizeParam); err == nil && v > 0 {
 			if v > 100 {
 				v = 100
 			}
@@ -281,15 +949,35 @@ func ListShares(c *gin.Context) {
 	}
 	offset := (page - 1) * size
 
+	// 排序参数
+	order := "created_at DESC"
+	if sortParam := strings.TrimSpace(c.Query("sort")); sortParam != "" {
+		dir := "ASC"
+		if strings.HasPrefix(sortParam, "-") {
+			dir = "DESC"
+			sortParam = sortParam[1:]
+		}
+		col, ok := shareSortColumns[sortParam]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid sort field: " + sortParam})
+			return
+		}
+		order = col + " " + dir
+	}
+
+	query := models.DB.Model(&models.Share{}).Where("user_id = ?", userID)
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		query = query.Where("doc_title LIKE ? ESCAPE '\\'", "%"+escapeLike(q)+"%")
+	}
+
 	var total int64
-	if err := models.DB.Model(&models.Share{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to count shares: " + err.Error()})
 		return
 	}
 
 	var shares []models.Share
-	if err := models.DB.Where("user_id = ?", userID).
-		Order("created_at DESC").
+	if err := query.Scopes(models.WithoutContent).Order(order).
 		Offset(offset).Limit(size).
 		Find(&shares).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -300,34 +988,41 @@ func ListShares(c *gin.Context) {
 	}
 
 	// 返回轻量结构并附带 shareUrl
-	baseURL := c.GetHeader("X-Base-URL")
-	if baseURL == "" {
-		proto := c.GetHeader("X-Forwarded-Proto")
-		host := c.GetHeader("X-Forwarded-Host")
-		if proto == "" {
-			if c.Request.TLS != nil {
-				proto = "https"
-			} else {
-				proto = "http"
-			}
-		}
-		if host == "" {
-			host = c.Request.Host
-		}
-		baseURL = proto + "://" + strings.TrimSuffix(host, "/")
-	}
-	baseURL = strings.TrimSuffix(baseURL, "/")
+	baseURL := getBaseURL(c)
 
 	type item struct {
-		ID              string    `json:"id"`
-		DocID           string    `json:"docId"`
-		DocTitle        string    `json:"docTitle"`
-		RequirePassword bool      `json:"requirePassword"`
-		ExpireAt        time.Time `json:"expireAt"`
-		IsPublic        bool      `json:"isPublic"`
-		ViewCount       int       `json:"viewCount"`
-		CreatedAt       time.Time `json:"createdAt"`
-		ShareURL        string    `json:"shareUrl"`
+		ID              string                 `json:"id"`
+		DocID           string                 `json:"docId"`
+		DocTitle        string                 `json:"docTitle"`
+		RequirePassword bool                   `json:"requirePassword"`
+		ExpireAt        time.Time              `json:"expireAt"`
+		IsPublic        bool                   `json:"isPublic"`
+		Restricted      bool                   `json:"restricted"`
+		AccessSchedule  *models.AccessSchedule `json:"accessSchedule"`
+		HasTerms        bool                   `json:"hasTerms"`
+		Paywall         bool                   `json:"paywall"`
+		PaywallPrice    string                 `json:"paywallPrice"`
+		PaywallURL      string                 `json:"paywallUrl"`
+		Teaser          bool                   `json:"teaser"`
+		MaxViews        int                    `json:"maxViews"`
+		SealedUntil     *time.Time             `json:"sealedUntil,omitempty"`
+		Listed          bool                   `json:"listed"`
+		Status          string                 `json:"status"`
+		ViewCount       int                    `json:"viewCount"`
+		Mode            string                 `json:"mode"`
+		TasksTotal      int                    `json:"tasksTotal"`
+		TasksDone       int                    `json:"tasksDone"`
+		ReadingMinutes  int                    `json:"readingMinutes"`
+		TrackReading    bool                   `json:"trackReading"`
+		CreatedAt       time.Time              `json:"createdAt"`
+		UpdatedAt       time.Time              `json:"updatedAt"`
+		ShareURL        string                 `json:"shareUrl"`
+		Tags            []string               `json:"tags"`
+		Theme           string                 `json:"theme"`
+		TOC             string                 `json:"toc"`
+		Variant         *models.ShareVariant   `json:"variant"`
+		HeadingAnchors  bool                   `json:"headingAnchors"`
+		Prerender       PrerenderSettings      `json:"prerender"`
 	}
 	items := make([]item, 0, len(shares))
 	for _, s := range shares {
@@ -338,9 +1033,32 @@ func ListShares(c *gin.Context) {
 			RequirePassword: s.RequirePassword,
 			ExpireAt:        s.ExpireAt,
 			IsPublic:        s.IsPublic,
+			Restricted:      s.Restricted,
+			AccessSchedule:  s.Schedule(),
+			HasTerms:        s.Terms != "",
+			Paywall:         s.Paywall,
+			PaywallPrice:    s.PaywallPrice,
+			PaywallURL:      s.PaywallURL,
+			Teaser:          s.Teaser,
+			MaxViews:        s.MaxViews,
+			SealedUntil:     s.SealedUntil,
+			Listed:          s.Listed,
+			Status:          s.Status,
 			ViewCount:       s.ViewCount,
+			Mode:            s.Mode,
+			TasksTotal:      s.TasksTotal,
+			TasksDone:       s.TasksDone,
+			ReadingMinutes:  s.ReadingMinutes,
+			TrackReading:    s.TrackReading,
 			CreatedAt:       s.CreatedAt,
+			UpdatedAt:       s.UpdatedAt,
 			ShareURL:        baseURL + "/s/" + s.ID,
+			Tags:            s.TagList(),
+			Theme:           s.Theme,
+			TOC:             s.TOC,
+			Variant:         s.Variant(),
+			HeadingAnchors:  s.HeadingAnchors,
+			Prerender:       sharePrerenderSettings(&s),
 		})
 	}
 
@@ -348,10 +1066,11 @@ func ListShares(c *gin.Context) {
 		"code": 0,
 		"msg":  "success",
 		"data": gin.H{
-			"items": items,
-			"page":  page,
-			"size":  size,
-			"total": total,
+			"items":    items,
+			"page":     page,
+			"size":     size,
+			"pageSize": size,
+			"total":    total,
 		},
 	})
 }
@@ -361,6 +1080,11 @@ func DeleteShare(c *gin.Context) {
 	shareID := c.Param("id")
 	userID, _ := c.Get("userID")
 
+	var share models.Share
+	if err := models.DB.Scopes(models.WithoutContent).Where("id = ? AND user_id = ?", shareID, userID).First(&share).Error; err == nil && rejectSealed(c, &share) {
+		return
+	}
+
 	result := models.DB.Where("id = ? AND user_id = ?", shareID, userID).Delete(&models.Share{})
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -377,6 +1101,7 @@ func DeleteShare(c *gin.Context) {
 		})
 		return
 	}
+	models.RemoveShareIndex(shareID)
 
 	c.JSON(http.StatusOK, gin.H{
 		"code": 0,
@@ -430,6 +1155,13 @@ func DeleteSharesBatch(c *gin.Context) {
 			continue
 		}
 
+		var sealed int64
+		models.DB.Model(&models.Share{}).Where("id = ? AND user_id = ? AND sealed_until > ?", shareID, userID, time.Now()).Count(&sealed)
+		if sealed > 0 {
+			failed[shareID] = "share is sealed"
+			continue
+		}
+
 		result := models.DB.Where("id = ? AND user_id = ?", shareID, userID).Delete(&models.Share{})
 		if result.Error != nil {
 			failed[shareID] = result.Error.Error()
@@ -441,6 +1173,7 @@ func DeleteSharesBatch(c *gin.Context) {
 		}
 		response.Deleted = append(response.Deleted, shareID)
 	}
+	models.RemoveShareIndex(response.Deleted...)
 
 	if len(failed) > 0 {
 		response.Failed = failed
@@ -453,45 +1186,143 @@ func DeleteSharesBatch(c *gin.Context) {
 	})
 }
 
+// escapeLike 转义 LIKE 查询中的通配符
+func escapeLike(s string) string {
+	r := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+	return r.Replace(s)
+}
+
+// BatchShares 在单个事务中批量删除、停用、启用或延长分享有效期，并逐条返回结果
+func BatchShares(c *gin.Context) {
+	var req BatchShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.Action == "extend" && (req.ExpireDays < 1 || req.ExpireDays > 365) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "expireDays must be between 1 and 365"})
+		return
+	}
+
+	userID := c.GetString("userID")
+	results := make([]BatchShareResult, 0, len(req.IDs))
+	succeeded := 0
+
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		for _, id := range req.IDs {
+			id = strings.TrimSpace(id)
+			if id == "" {
+				continue
+			}
+
+			var share models.Share
+			if err := tx.Where("id = ? AND user_id = ?", id, userID).First(&share).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					results = append(results, BatchShareResult{ID: id, Error: "not found"})
+					continue
+				}
+				return err
+			}
+
+			// 封存期内不能删除或停用，延长有效期不受影响
+			if share.Sealed() && (req.Action == "delete" || req.Action == "disable") {
+				results = append(results, BatchShareResult{ID: id, Error: "sealed"})
+				continue
+			}
+
+			var err error
+			switch req.Action {
+			case "delete":
+				err = tx.Delete(&share).Error
+			case "disable":
+				err = setShareStatus(tx, &share, models.ShareStatusDisabled)
+			case "enable":
+				// 草稿与已停用的分享启用后正式发布，已可访问的保持原状态；被管理员停用的分享不能启用
+				if share.Moderated() {
+					results = append(results, BatchShareResult{ID: id, Error: "moderated"})
+					continue
+				}
+				if !share.Reachable() {
+					err = setShareStatus(tx, &share, models.ShareStatusPublished)
+				}
+			case "extend":
+				base := share.ExpireAt
+				if base.Before(time.Now()) {
+					base = time.Now()
+				}
+				expireAt := base.AddDate(0, 0, req.ExpireDays)
+				err = tx.Model(&share).Update("expire_at", expireAt).Error
+				if err == nil {
+					// 引用块子分享同步有效期
+					err = tx.Model(&models.Share{}).Where("parent_share_id = ? AND user_id = ?", share.ID, userID).
+						Update("expire_at", expireAt).Error
+				}
+			}
+			if err != nil {
+				return err
+			}
+			results = append(results, BatchShareResult{ID: id, OK: true})
+			succeeded++
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Batch operation failed, no changes applied: " + err.Error()})
+		return
+	}
+	if req.Action == "delete" {
+		deleted := make([]string, 0, succeeded)
+		for _, r := range results {
+			if r.OK {
+				deleted = append(deleted, r.ID)
+			}
+		}
+		models.RemoveShareIndex(deleted...)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "success",
+		"data": gin.H{
+			"action":    req.Action,
+			"results":   results,
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+		},
+	})
+}
+
 // generateShareID 生成随机分享 ID
 func generateShareID() string {
 	b := make([]byte, 16)
-	rand.Read(b)
+	idgen.Read(b)
 	return hex.EncodeToString(b)
 }
 
 // generateBlockTitle 生成引用块的标题
 func generateBlockTitle(ref BlockReferenceReq) string {
 	// 优先使用显示文本
-	if ref.DisplayText != "" {
-		return ref.DisplayText
+	if title := slug.Title(ref.DisplayText); title != "" {
+		return slug.Truncate(title, maxDocTitle)
 	}
 
 	// 使用内容的第一行作为标题
 	if ref.Content != "" {
 		lines := strings.Split(ref.Content, "\n")
 		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
+			trimmed := slug.Title(line)
 			// 跳过空行和 Markdown 标记
 			if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
-				// 限制标题长度
-				if len(trimmed) > 50 {
-					return trimmed[:50] + "..."
-				}
-				return trimmed
+				return blockTitleLine(trimmed)
 			}
 		}
 
 		// 如果所有行都是标题或空行,使用第一个非空行
 		for _, line := range lines {
-			trimmed := strings.TrimSpace(line)
+			trimmed := slug.Title(line)
 			if trimmed != "" {
 				// 移除 Markdown 标题标记
-				trimmed = strings.TrimLeft(trimmed, "# ")
-				if len(trimmed) > 50 {
-					return trimmed[:50] + "..."
-				}
-				return trimmed
+				return blockTitleLine(strings.TrimLeft(trimmed, "# "))
 			}
 		}
 	}
@@ -499,3 +1330,11 @@ func generateBlockTitle(ref BlockReferenceReq) string {
 	// 降级使用 blockId
 	return "引用块"
 }
+
+// blockTitleLine 限制引用块标题长度，按字符截断，避免中文等多字节字符被截断为乱码
+func blockTitleLine(line string) string {
+	if utf8.RuneCountInString(line) > 50 {
+		return slug.Truncate(line, 50) + "..."
+	}
+	return line
+}
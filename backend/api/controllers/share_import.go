@@ -0,0 +1,424 @@
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/imageopt"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const (
+	// maxImportBytes 导入的压缩包大小上限
+	maxImportBytes = 100 << 20
+	// maxImportUnpacked 压缩包解压后的总大小上限，防止压缩炸弹
+	maxImportUnpacked = 500 << 20
+	// maxImportFiles 压缩包中的文件数上限
+	maxImportFiles = 2000
+)
+
+// importLinkPattern Markdown 与 HTML 中指向导出目录下 assets/ 的相对链接（可带 ./ 或 ../ 前缀）
+var importLinkPattern = regexp.MustCompile(`(\]\(<?|(?:src|href)=["'])((?:\./|(?:\.\./)+)?assets/[^\s"'()<>?#]+)`)
+
+// importedDoc 压缩包中的文档与资源
+type importedDoc struct {
+	name   string            // 文档文件名
+	isHTML bool              // HTML 导出，否则为 Markdown
+	body   string            // 文档内容
+	assets map[string][]byte // assets/ 下的相对路径 → 内容
+}
+
+// ImportShare 从思源笔记导出的 zip（Markdown 或 HTML 及 assets/ 资源）创建分享：解压后上传资源并把正文中的
+// 相对资源链接改写为分享资源地址，发布设置与创建分享相同，以表单字段传入
+func ImportShare(c *gin.Context) {
+	userID := c.GetString("userID")
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes+1<<20)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if fh.Size > maxImportBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"code": 1, "msg": "Import file is too large"})
+		return
+	}
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read upload: " + err.Error()})
+		return
+	}
+	defer f.Close()
+	zr, err := zip.NewReader(f, fh.Size)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid zip file"})
+		return
+	}
+	doc, msg := readImportZip(zr)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": msg})
+		return
+	}
+
+	// 先检查资源是否超出配额，避免分享已发布而资源只上传了一部分
+	quota := models.UserQuota(userID)
+	var total int64
+	for p, data := range doc.assets {
+		if quota.MaxAssetBytes > 0 && int64(len(data)) > quota.MaxAssetBytes {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"code": CodeQuotaExceeded,
+				"msg":  "Asset exceeds the maximum file size",
+				"data": gin.H{"path": "assets/" + p, "size": len(data), "quota": quota},
+			})
+			return
+		}
+		total += int64(len(data))
+	}
+	if quota.MaxBytes > 0 && total > 0 {
+		usage, err := models.UserUsage(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+			return
+		}
+		if usage.Bytes+total > models.GraceLimit(quota.MaxBytes) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"code": CodeQuotaExceeded,
+				"msg":  "Storage quota exceeded",
+				"data": gin.H{"size": total, "usage": usage, "quota": quota},
+			})
+			return
+		}
+	}
+
+	shareID := generateShareID()
+	assetBase := getBaseURL(c) + "/api/s/" + shareID + "/"
+	title, content := doc.markdownContent()
+	if doc.isHTML {
+		title, content, err = doc.htmlContent()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid HTML document"})
+			return
+		}
+	}
+	content = rewriteImportLinks(content, assetBase)
+	if t := strings.TrimSpace(c.PostForm("title")); t != "" {
+		title = t
+	}
+	if strings.TrimSpace(content) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Imported document is empty"})
+		return
+	}
+
+	req := CreateShareRequest{
+		DocID:           "import-" + shareID,
+		DocTitle:        title,
+		Content:         content,
+		RequirePassword: formBool(c, "requirePassword"),
+		Password:        c.PostForm("password"),
+		IsPublic:        formBool(c, "isPublic"),
+		Listed:          formBool(c, "listed"),
+		TemplateID:      c.PostForm("templateId"),
+		Status:          c.PostForm("status"),
+	}
+	if v := c.PostForm("expireDays"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > 365 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "expireDays must be between 1 and 365"})
+			return
+		}
+		req.ExpireDays = days
+	}
+	switch req.Status {
+	case "", models.ShareStatusDraft, models.ShareStatusPublished, models.ShareStatusUnlisted:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "status must be draft, published or unlisted"})
+		return
+	}
+
+	share, reused, ok := publishShare(c, userID, &req, shareID)
+	if !ok {
+		return
+	}
+	paths := make([]string, 0, len(doc.assets))
+	for p := range doc.assets {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		// 存储失败的资源会出现在返回的 missingAssets 中，可在分享的资源管理中补传
+		if err := saveImportedAsset(c, share, "assets/"+p, doc.assets[p]); err != nil {
+			log.Printf("import asset %s of share %s failed: %v", p, share.ID, err)
+		}
+	}
+	respondPublished(c, share, reused)
+}
+
+// formBool 解析表单中的布尔字段，未传入时返回 nil
+func formBool(c *gin.Context, key string) *bool {
+	v, ok := c.GetPostForm(key)
+	if !ok || v == "" {
+		return nil
+	}
+	b := v == "true" || v == "1" || v == "on"
+	return &b
+}
+
+// readImportZip 读取压缩包：选取目录层级最浅的 .md 或 .html 文档（忽略 HTML 导出中的 appearance/、stage/ 等目录），
+// 收集所有 assets/ 目录下的文件；压缩包无效时返回错误信息
+func readImportZip(zr *zip.Reader) (*importedDoc, string) {
+	if len(zr.File) > maxImportFiles {
+		return nil, "Zip contains too many files"
+	}
+	var docFile *zip.File
+	depth := func(name string) int { return strings.Count(name, "/") }
+	doc := &importedDoc{assets: map[string][]byte{}}
+	var unpacked int64
+	for _, zf := range zr.File {
+		name := strings.ReplaceAll(zf.Name, "\\", "/")
+		if zf.FileInfo().IsDir() || strings.HasPrefix(path.Base(name), ".") || strings.HasPrefix(name, "__MACOSX/") {
+			continue
+		}
+		if rel, ok := importAssetPath(name); ok {
+			if unpacked += int64(zf.UncompressedSize64); unpacked > maxImportUnpacked {
+				return nil, "Zip is too large when unpacked"
+			}
+			data, err := readZipFile(zf)
+			if err != nil {
+				return nil, "Invalid zip file"
+			}
+			doc.assets[rel] = data
+			continue
+		}
+		ext := strings.ToLower(path.Ext(name))
+		if ext != ".md" && ext != ".markdown" && ext != ".html" && ext != ".htm" {
+			continue
+		}
+		top := strings.SplitN(name, "/", 2)[0]
+		if top == "appearance" || top == "stage" || top == "emojis" {
+			continue
+		}
+		if docFile == nil || depth(name) < depth(docFile.Name) || (depth(name) == depth(docFile.Name) && name < docFile.Name) {
+			docFile = zf
+		}
+	}
+	if docFile == nil {
+		return nil, "No Markdown or HTML document found in zip"
+	}
+	if unpacked += int64(docFile.UncompressedSize64); unpacked > maxImportUnpacked {
+		return nil, "Zip is too large when unpacked"
+	}
+	body, err := readZipFile(docFile)
+	if err != nil {
+		return nil, "Invalid zip file"
+	}
+	doc.name = path.Base(strings.ReplaceAll(docFile.Name, "\\", "/"))
+	ext := strings.ToLower(path.Ext(doc.name))
+	doc.isHTML = ext == ".html" || ext == ".htm"
+	doc.body = strings.TrimPrefix(string(body), "\ufeff")
+	return doc, ""
+}
+
+// importAssetPath 压缩包内位于 assets/ 目录下的文件，返回 assets/ 之后的相对路径
+func importAssetPath(name string) (string, bool) {
+	var rel string
+	if strings.HasPrefix(name, "assets/") {
+		rel = strings.TrimPrefix(name, "assets/")
+	} else if i := strings.Index(name, "/assets/"); i >= 0 {
+		rel = name[i+len("/assets/"):]
+	} else {
+		return "", false
+	}
+	if _, err := storage.CleanKey("assets/" + rel); err != nil || rel == "" {
+		return "", false
+	}
+	return rel, true
+}
+
+// readZipFile 读取压缩包中的文件，实际大小超过声明的大小时视为损坏
+func readZipFile(zf *zip.File) ([]byte, error) {
+	rc, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, int64(zf.UncompressedSize64)+1))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) > zf.UncompressedSize64 {
+		return nil, fmt.Errorf("size mismatch")
+	}
+	return data, nil
+}
+
+// docTitle 文件名去掉扩展名作为默认标题
+func (d *importedDoc) docTitle() string {
+	return strings.TrimSuffix(d.name, path.Ext(d.name))
+}
+
+// markdownContent Markdown 导出：去掉 YAML front matter，其中的 title 作为标题
+func (d *importedDoc) markdownContent() (string, string) {
+	title, body := d.docTitle(), strings.ReplaceAll(d.body, "\r\n", "\n")
+	if rest, ok := strings.CutPrefix(body, "---\n"); ok {
+		if end := strings.Index(rest, "\n---\n"); end >= 0 {
+			for _, line := range strings.Split(rest[:end], "\n") {
+				if v, ok := strings.CutPrefix(line, "title:"); ok {
+					if t := strings.Trim(strings.TrimSpace(v), `"'`); t != "" {
+						title = t
+					}
+				}
+			}
+			body = rest[end+len("\n---\n"):]
+		}
+	}
+	return title, strings.TrimSpace(body)
+}
+
+// htmlContent HTML 导出：取编辑区（#preview 或 .protyle-wysiwyg）或 body 的内容作为正文，
+// 去掉脚本、样式与事件属性；<title> 作为标题
+func (d *importedDoc) htmlContent() (string, string, error) {
+	root, err := html.Parse(strings.NewReader(d.body))
+	if err != nil {
+		return "", "", err
+	}
+	title := d.docTitle()
+	var body, editor *html.Node
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch {
+			case n.DataAtom == atom.Title && n.FirstChild != nil:
+				if t := strings.TrimSpace(n.FirstChild.Data); t != "" {
+					title = t
+				}
+			case n.DataAtom == atom.Body:
+				body = n
+			case editor == nil && (htmlAttr(n, "id") == "preview" || strings.Contains(" "+htmlAttr(n, "class")+" ", " protyle-wysiwyg ")):
+				editor = n
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+	if editor != nil {
+		body = editor
+	}
+	if body == nil {
+		return title, "", nil
+	}
+	sanitizeImportHTML(body)
+	var buf bytes.Buffer
+	for child := body.FirstChild; child != nil; child = child.NextSibling {
+		if err := html.Render(&buf, child); err != nil {
+			return "", "", err
+		}
+	}
+	return title, strings.TrimSpace(buf.String()), nil
+}
+
+// htmlAttr 元素的属性值
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// sanitizeImportHTML 移除脚本、样式、框架等元素，以及事件属性与 javascript: 链接
+func sanitizeImportHTML(n *html.Node) {
+	for child := n.FirstChild; child != nil; {
+		next := child.NextSibling
+		if child.Type == html.ElementNode {
+			switch child.DataAtom {
+			case atom.Script, atom.Style, atom.Link, atom.Meta, atom.Iframe, atom.Object, atom.Embed, atom.Noscript, atom.Base, atom.Form:
+				n.RemoveChild(child)
+				child = next
+				continue
+			}
+			attrs := child.Attr[:0]
+			for _, a := range child.Attr {
+				key := strings.ToLower(a.Key)
+				val := strings.ToLower(strings.TrimSpace(a.Val))
+				if strings.HasPrefix(key, "on") || ((key == "href" || key == "src") && strings.HasPrefix(val, "javascript:")) ||
+					key == "contenteditable" || key == "spellcheck" {
+					continue
+				}
+				attrs = append(attrs, a)
+			}
+			child.Attr = attrs
+			sanitizeImportHTML(child)
+		}
+		child = next
+	}
+}
+
+// rewriteImportLinks 把正文中指向 assets/ 的相对链接改写为分享资源地址；压缩包中没有的资源同样改写，
+// 以便出现在返回的 missingAssets 中并可补传
+func rewriteImportLinks(content string, assetBase string) string {
+	return importLinkPattern.ReplaceAllStringFunc(content, func(m string) string {
+		sub := importLinkPattern.FindStringSubmatch(m)
+		prefix, ref := sub[1], sub[2]
+		rel := ref[strings.Index(ref, "assets/")+len("assets/"):]
+		if decoded, err := url.PathUnescape(rel); err == nil {
+			rel = decoded
+		}
+		segments := strings.Split("assets/"+rel, "/")
+		for i, s := range segments {
+			segments[i] = url.PathEscape(s)
+		}
+		return prefix + assetBase + strings.Join(segments, "/")
+	})
+}
+
+// saveImportedAsset 保存导入的资源文件
+func saveImportedAsset(c *gin.Context, share *models.Share, assetPath string, data []byte) error {
+	assetPath, err := storage.CleanKey(assetPath)
+	if err != nil {
+		return err
+	}
+	contentType := mime.TypeByExtension(path.Ext(assetPath))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	sum := sha256.Sum256(data)
+	key := "shares/" + share.ID + "/" + assetPath
+	if err := storage.Default.Put(c.Request.Context(), key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return err
+	}
+	asset, err := models.FindAsset(share.ID, assetPath)
+	if err != nil {
+		return err
+	}
+	if asset == nil {
+		asset = &models.Asset{ID: "ast_" + randHex(12), ShareID: share.ID, UserID: share.UserID, Path: assetPath}
+	}
+	asset.StorageKey = key
+	asset.ContentType = contentType
+	asset.Size = int64(len(data))
+	asset.Hash = hex.EncodeToString(sum[:])
+	if err := models.DB.Save(asset).Error; err != nil {
+		return err
+	}
+	imageopt.Schedule(asset)
+	return nil
+}
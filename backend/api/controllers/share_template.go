@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// maxShareTemplates 每个用户最多保存的发布模板数
+const maxShareTemplates = 50
+
+// ShareTemplateRequest 创建/更新发布模板请求
+type ShareTemplateRequest struct {
+	Name            string `json:"name" binding:"required,min=1,max=100"`
+	Theme           string `json:"theme"`
+	TOC             string `json:"toc"`
+	ExpireDays      int    `json:"expireDays" binding:"min=0,max=365"`
+	RequirePassword bool   `json:"requirePassword"`
+	Password        string `json:"password"` // 默认访问密码，更新时留空则保留原密码
+	IsPublic        bool   `json:"isPublic"`
+	Listed          bool   `json:"listed"`
+	IsDefault       bool   `json:"isDefault"`
+}
+
+// shareTemplateItem 模板的返回数据，不含密码哈希
+func shareTemplateItem(tpl *models.ShareTemplate) gin.H {
+	return gin.H{
+		"id":              tpl.ID,
+		"name":            tpl.Name,
+		"theme":           tpl.Theme,
+		"toc":             tpl.TOC,
+		"expireDays":      tpl.ExpireDays,
+		"requirePassword": tpl.RequirePassword,
+		"hasPassword":     tpl.PasswordHash != "",
+		"isPublic":        tpl.IsPublic,
+		"listed":          tpl.Listed,
+		"isDefault":       tpl.IsDefault,
+		"createdAt":       tpl.CreatedAt,
+		"updatedAt":       tpl.UpdatedAt,
+	}
+}
+
+// ListShareTemplates 列出当前用户的发布模板
+func ListShareTemplates(c *gin.Context) {
+	var templates []models.ShareTemplate
+	if err := models.DB.Where("user_id = ?", c.GetString("userID")).Order("created_at").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list templates: " + err.Error()})
+		return
+	}
+	items := make([]gin.H, 0, len(templates))
+	for i := range templates {
+		items = append(items, shareTemplateItem(&templates[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// GetShareTemplate 获取发布模板
+func GetShareTemplate(c *gin.Context) {
+	tpl, err := models.FindShareTemplate(c.GetString("userID"), c.Param("id"))
+	if err != nil || tpl == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Template not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": shareTemplateItem(tpl)})
+}
+
+// CreateShareTemplate 创建发布模板
+func CreateShareTemplate(c *gin.Context) {
+	userID := c.GetString("userID")
+	var req ShareTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	var count int64
+	if err := models.DB.Model(&models.ShareTemplate{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save template: " + err.Error()})
+		return
+	}
+	if count >= maxShareTemplates {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Too many templates"})
+		return
+	}
+	tpl := &models.ShareTemplate{ID: "tpl_" + randHex(8), UserID: userID}
+	if !applyShareTemplateRequest(c, tpl, &req) {
+		return
+	}
+	saveShareTemplate(c, tpl, true)
+}
+
+// UpdateShareTemplate 更新发布模板（整体替换，密码留空时保留原密码）
+func UpdateShareTemplate(c *gin.Context) {
+	tpl, err := models.FindShareTemplate(c.GetString("userID"), c.Param("id"))
+	if err != nil || tpl == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Template not found"})
+		return
+	}
+	var req ShareTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if !applyShareTemplateRequest(c, tpl, &req) {
+		return
+	}
+	saveShareTemplate(c, tpl, false)
+}
+
+// DeleteShareTemplate 删除发布模板，已创建的分享不受影响
+func DeleteShareTemplate(c *gin.Context) {
+	result := models.DB.Where("id = ? AND user_id = ?", c.Param("id"), c.GetString("userID")).Delete(&models.ShareTemplate{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete template: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Template not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// applyShareTemplateRequest 校验请求并写入模板，校验失败时已写入响应
+func applyShareTemplateRequest(c *gin.Context, tpl *models.ShareTemplate, req *ShareTemplateRequest) bool {
+	theme, ok := normalizeShareTheme(c, tpl.UserID, req.Theme)
+	if !ok {
+		return false
+	}
+	switch req.TOC {
+	case "", models.ShareTOCSide, models.ShareTOCFloating, models.ShareTOCOff:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "toc must be side, floating or off"})
+		return false
+	}
+	password := strings.TrimSpace(req.Password)
+	if password != "" && len(password) < 4 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Password must be at least 4 characters"})
+		return false
+	}
+
+	tpl.Name = strings.TrimSpace(req.Name)
+	tpl.Theme = theme
+	tpl.TOC = req.TOC
+	tpl.ExpireDays = req.ExpireDays
+	tpl.RequirePassword = req.RequirePassword
+	tpl.IsPublic = req.IsPublic
+	tpl.Listed = req.Listed
+	tpl.IsDefault = req.IsDefault
+	switch {
+	case !req.RequirePassword:
+		tpl.PasswordHash = ""
+	case password != "":
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to encrypt password"})
+			return false
+		}
+		tpl.PasswordHash = string(hashed)
+	}
+	return true
+}
+
+// saveShareTemplate 保存模板；设为默认时取消用户其他模板的默认标记
+func saveShareTemplate(c *gin.Context, tpl *models.ShareTemplate, create bool) {
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if tpl.IsDefault {
+			if err := tx.Model(&models.ShareTemplate{}).Where("user_id = ? AND id <> ?", tpl.UserID, tpl.ID).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		if create {
+			return tx.Create(tpl).Error
+		}
+		return tx.Save(tpl).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save template: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": shareTemplateItem(tpl)})
+}
+
+// resolveShareTemplate 创建分享时使用的发布模板：指定了 templateId 时为该模板（不存在时返回 400），
+// 否则新建分享使用用户的默认模板；校验失败时已写入响应
+func resolveShareTemplate(c *gin.Context, userID, templateID string, isNew bool) (*models.ShareTemplate, bool) {
+	if templateID == "" {
+		if !isNew {
+			return nil, true
+		}
+		tpl, err := models.DefaultShareTemplate(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query template: " + err.Error()})
+			return nil, false
+		}
+		return tpl, true
+	}
+	tpl, err := models.FindShareTemplate(userID, templateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query template: " + err.Error()})
+		return nil, false
+	}
+	if tpl == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Template not found"})
+		return nil, false
+	}
+	return tpl, true
+}
+
+// applyShareTemplateDefaults 用模板补全请求中未传入的发布设置
+func applyShareTemplateDefaults(req *CreateShareRequest, tpl *models.ShareTemplate) {
+	if req.ExpireDays == 0 {
+		req.ExpireDays = tpl.ExpireDays
+	}
+	if req.RequirePassword == nil {
+		req.RequirePassword = &tpl.RequirePassword
+	}
+	if req.IsPublic == nil {
+		req.IsPublic = &tpl.IsPublic
+	}
+	if req.Listed == nil {
+		req.Listed = &tpl.Listed
+	}
+}
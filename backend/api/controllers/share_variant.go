@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"encoding/binary"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	variantCookiePrefix = "sab_"         // 读者所在分组的 Cookie 名前缀，后接分享 ID
+	variantCookieMaxAge = 30 * 24 * 3600 // 分组保持 30 天，同一读者重复访问看到相同的版本
+	maxReadSeconds      = 4 * 3600       // 单次阅读上报时长的上限，超出按上限计
+	readCompletionDepth = 90             // 阅读深度达到该百分比视为读完
+	shareVariantCtxKey  = "shareVariant" // 本次请求已确定的分组
+)
+
+// ShareVariantRequest 设置分享的 A/B 测试：theme 与 toc 为 B 组使用的主题与目录样式，空字符串表示与 A 组相同；
+// 两者都为空时关闭测试。percent 为分配到 B 组的读者比例，默认 50
+type ShareVariantRequest struct {
+	Theme   string `json:"theme"`
+	TOC     string `json:"toc"`
+	Percent *int   `json:"percent"`
+}
+
+// ShareReadRequest 阅读页离开时上报的阅读数据
+type ShareReadRequest struct {
+	Variant string `json:"variant"` // A/B 测试分组，未开启测试时为空
+	Seconds int    `json:"seconds"` // 页面处于可见状态的秒数
+	Depth   int    `json:"depth"`   // 最大滚动深度（百分比）
+	Anchor  string `json:"anchor"`  // 读到的最后一个标题的锚点
+}
+
+// variantStats A/B 测试一个分组的统计
+type variantStats struct {
+	Variant        string  `json:"variant"`
+	Theme          string  `json:"theme"`
+	TOC            string  `json:"toc"`
+	Views          int64   `json:"views"`
+	Reads          int64   `json:"reads"`
+	AvgSeconds     float64 `json:"avgSeconds"`
+	AvgDepth       float64 `json:"avgDepth"`
+	CompletionRate float64 `json:"completionRate"` // 阅读深度达到 readCompletionDepth 的阅读占比
+}
+
+// normalizeShareTheme 校验分享主题：内置主题名（不区分大小写）或当前用户的自定义主题 ID，空字符串表示跟随默认
+func normalizeShareTheme(c *gin.Context, userID, raw string) (string, bool) {
+	theme := strings.ToLower(strings.TrimSpace(raw))
+	if theme == "" || isBuiltinTheme(theme) {
+		return theme, true
+	}
+	// 非内置主题需为当前用户的自定义主题（按原始大小写匹配 ID）
+	theme = strings.TrimSpace(raw)
+	if t, err := models.FindUserTheme(userID, theme); err != nil || t == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Unknown theme: " + theme})
+		return "", false
+	}
+	return theme, true
+}
+
+// shareVariantUpdates 校验 A/B 测试设置并返回要修改的字段，校验失败时已写入响应
+func shareVariantUpdates(c *gin.Context, userID string, req *ShareVariantRequest) (map[string]any, bool) {
+	theme, ok := normalizeShareTheme(c, userID, req.Theme)
+	if !ok {
+		return nil, false
+	}
+	toc := strings.TrimSpace(req.TOC)
+	switch toc {
+	case "", models.ShareTOCSide, models.ShareTOCFloating, models.ShareTOCOff:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "toc must be side, floating or off"})
+		return nil, false
+	}
+	percent := 50
+	if req.Percent != nil {
+		if *req.Percent < 0 || *req.Percent > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "percent must be between 0 and 100"})
+			return nil, false
+		}
+		percent = *req.Percent
+	}
+	return map[string]any{"variant_theme": theme, "variant_toc": toc, "variant_percent": percent}, true
+}
+
+// shareVariant 确定读者在分享 A/B 测试中的分组，未开启测试时返回空。
+// ?variant=a|b 指定分组（用于预览，不写入 Cookie）；否则沿用 Cookie 中的分组，没有时按比例随机分配并写入 Cookie
+func shareVariant(c *gin.Context, share *models.Share) string {
+	if v, ok := c.Get(shareVariantCtxKey); ok {
+		return v.(string)
+	}
+	variant := ""
+	if sv := share.Variant(); sv != nil {
+		name := variantCookiePrefix + share.ID
+		if q := c.Query("variant"); q == models.VariantA || q == models.VariantB {
+			variant = q
+		} else if v, err := c.Cookie(name); err == nil && (v == models.VariantA || v == models.VariantB) {
+			variant = v
+		} else {
+			var b [2]byte
+			idgen.Read(b[:])
+			variant = models.VariantA
+			if int(binary.BigEndian.Uint16(b[:])%100) < sv.Percent {
+				variant = models.VariantB
+			}
+			c.SetSameSite(http.SameSiteLaxMode)
+			c.SetCookie(name, variant, variantCookieMaxAge, "/", "", c.Request.TLS != nil, true)
+		}
+	}
+	c.Set(shareVariantCtxKey, variant)
+	return variant
+}
+
+// variantShare 返回读者所在分组看到的分享（主题与目录样式按分组替换）
+func variantShare(c *gin.Context, share *models.Share) *models.Share {
+	return share.WithVariant(shareVariant(c, share))
+}
+
+// RecordShareRead 阅读页离开时上报阅读时长、滚动深度与读到的标题（navigator.sendBeacon），
+// 只在分享开启 A/B 测试或阅读统计时记录
+func RecordShareRead(c *gin.Context) {
+	var req ShareReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Anchor) > maxProgressAnchorLength ||
+		(req.Variant != "" && req.Variant != models.VariantA && req.Variant != models.VariantB) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request"})
+		return
+	}
+	share, err := models.FindShare(c.Param("id"))
+	if err != nil || !share.Reachable() {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	variant := req.Variant
+	if share.Variant() == nil {
+		variant = ""
+	}
+	if variant == "" && !share.TrackReading {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	models.RecordShareRead(models.ShareRead{
+		ShareID: share.ID,
+		Variant: variant,
+		Seconds: min(max(req.Seconds, 0), maxReadSeconds),
+		Depth:   min(max(req.Depth, 0), 100),
+		Anchor:  strings.TrimSpace(req.Anchor),
+	})
+	c.Status(http.StatusNoContent)
+}
+
+// GetShareVariantStats 所有者查看 A/B 测试各分组的浏览次数、阅读时长、阅读深度与读完比例；
+// from / to 格式同浏览数据导出，默认为原始记录保留期内的全部数据
+func GetShareVariantStats(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	to := time.Now()
+	from := to.Add(-config.Get().Jobs.ViewRetention.Std())
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = parseViewExportTime(v, false); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = parseViewExportTime(v, true); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+
+	models.FlushShareViews()
+	var views []struct {
+		Variant string
+		N       int64
+	}
+	if err := models.DB.Model(&models.ShareView{}).Select("variant, COUNT(*) AS n").
+		Where("share_id = ? AND variant <> '' AND viewed_at >= ? AND viewed_at < ?", share.ID, from, to).
+		Group("variant").Scan(&views).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load views: " + err.Error()})
+		return
+	}
+	var reads []struct {
+		Variant    string
+		N          int64
+		AvgSeconds float64
+		AvgDepth   float64
+		Completed  int64
+	}
+	if err := models.DB.Model(&models.ShareRead{}).
+		Select("variant, COUNT(*) AS n, AVG(seconds) AS avg_seconds, AVG(depth) AS avg_depth, "+
+			"SUM(CASE WHEN depth >= ? THEN 1 ELSE 0 END) AS completed", readCompletionDepth).
+		Where("share_id = ? AND read_at >= ? AND read_at < ?", share.ID, from, to).
+		Group("variant").Scan(&reads).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load views: " + err.Error()})
+		return
+	}
+
+	a, b := share.WithVariant(models.VariantA), share.WithVariant(models.VariantB)
+	items := []*variantStats{
+		{Variant: models.VariantA, Theme: a.Theme, TOC: a.TOC},
+		{Variant: models.VariantB, Theme: b.Theme, TOC: b.TOC},
+	}
+	byName := map[string]*variantStats{models.VariantA: items[0], models.VariantB: items[1]}
+	for _, v := range views {
+		if s := byName[v.Variant]; s != nil {
+			s.Views = v.N
+		}
+	}
+	for _, r := range reads {
+		if s := byName[r.Variant]; s != nil && r.N > 0 {
+			s.Reads = r.N
+			s.AvgSeconds = r.AvgSeconds
+			s.AvgDepth = r.AvgDepth
+			s.CompletionRate = float64(r.Completed) / float64(r.N)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"variant": share.Variant(),
+		"from":    from,
+		"to":      to,
+		"items":   items,
+	}})
+}
@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	shortLinkCodeLength = 6  // 短链接代码长度（字母与数字）
+	maxShortLinks       = 50 // 每个分享的短链接数量上限
+)
+
+const shortLinkAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// CreateShortLinkRequest 创建短链接
+type CreateShortLinkRequest struct {
+	Label string `json:"label"` // 用途说明，如投放渠道，最多 100 字
+}
+
+// UpdateShortLinkRequest 修改短链接，只传需要修改的字段
+type UpdateShortLinkRequest struct {
+	Label    *string `json:"label"`
+	Disabled *bool   `json:"disabled"`
+}
+
+// generateShortCode 生成随机短链接代码，拒绝采样避免字符分布不均
+func generateShortCode() string {
+	code := make([]byte, 0, shortLinkCodeLength)
+	buf := make([]byte, shortLinkCodeLength*2)
+	for len(code) < shortLinkCodeLength {
+		idgen.Read(buf)
+		for _, b := range buf {
+			if int(b) < 256-256%len(shortLinkAlphabet) && len(code) < shortLinkCodeLength {
+				code = append(code, shortLinkAlphabet[int(b)%len(shortLinkAlphabet)])
+			}
+		}
+	}
+	return string(code)
+}
+
+// shortLinkURL 短链接的完整地址，配置了 links.short_base_url 时使用该地址
+func shortLinkURL(c *gin.Context, code string) string {
+	base := config.Get().Links.ShortBaseURL
+	if base == "" {
+		base = getBaseURL(c)
+	}
+	return base + "/x/" + code
+}
+
+// shortLinkItem 返回给所有者的短链接，附带完整地址
+func shortLinkItem(c *gin.Context, l *models.ShortLink) gin.H {
+	return gin.H{
+		"code":          l.Code,
+		"shareId":       l.ShareID,
+		"url":           shortLinkURL(c, l.Code),
+		"label":         l.Label,
+		"disabled":      l.Disabled,
+		"clicks":        l.Clicks,
+		"lastClickedAt": l.LastClickedAt,
+		"createdAt":     l.CreatedAt,
+	}
+}
+
+// loadOwnedShortLink 读取当前用户分享下的短链接，不存在时写入 404
+func loadOwnedShortLink(c *gin.Context) (*models.ShortLink, bool) {
+	var link models.ShortLink
+	if err := models.DB.Where("code = ? AND share_id = ? AND user_id = ?", c.Param("code"), c.Param("id"), c.GetString("userID")).
+		First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Short link not found"})
+		return nil, false
+	}
+	return &link, true
+}
+
+// ListShortLinks 列出分享的短链接及各自的点击数
+func ListShortLinks(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var links []models.ShortLink
+	if err := models.DB.Where("share_id = ?", share.ID).Order("created_at DESC").Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list short links: " + err.Error()})
+		return
+	}
+	items := make([]gin.H, 0, len(links))
+	for i := range links {
+		items = append(items, shortLinkItem(c, &links[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// CreateShortLink 为分享创建短链接，与分享的正式链接相互独立，可为不同受众分别创建并统计点击
+func CreateShortLink(c *gin.Context) {
+	var req CreateShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	label := strings.TrimSpace(req.Label)
+	if utf8.RuneCountInString(label) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Label must be at most 100 characters"})
+		return
+	}
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var count int64
+	models.DB.Model(&models.ShortLink{}).Where("share_id = ?", share.ID).Count(&count)
+	if count >= maxShortLinks {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Too many short links for this share"})
+		return
+	}
+
+	link := &models.ShortLink{ShareID: share.ID, UserID: share.UserID, Label: label, Code: generateShortCode()}
+	err := models.CreateUnique(func() error { return models.DB.Create(link).Error }, func(int) bool {
+		link.Code = generateShortCode()
+		return true
+	}, "short_links.code")
+	if errors.Is(err, models.ErrTaken) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "No short link code available, please retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to create short link: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": shortLinkItem(c, link)})
+}
+
+// GetShortLink 查询短链接及其点击数
+func GetShortLink(c *gin.Context) {
+	link, ok := loadOwnedShortLink(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": shortLinkItem(c, link)})
+}
+
+// UpdateShortLink 修改短链接的用途说明，或停用 / 重新启用短链接；停用后访问返回 404，点击数保留
+func UpdateShortLink(c *gin.Context) {
+	var req UpdateShortLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	link, ok := loadOwnedShortLink(c)
+	if !ok {
+		return
+	}
+	updates := map[string]any{}
+	if req.Label != nil {
+		label := strings.TrimSpace(*req.Label)
+		if utf8.RuneCountInString(label) > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Label must be at most 100 characters"})
+			return
+		}
+		updates["label"] = label
+	}
+	if req.Disabled != nil {
+		updates["disabled"] = *req.Disabled
+	}
+	if len(updates) > 0 {
+		if err := models.DB.Model(link).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update short link: " + err.Error()})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": shortLinkItem(c, link)})
+}
+
+// DeleteShortLink 删除短链接及其点击统计，删除后访问返回 404
+func DeleteShortLink(c *gin.Context) {
+	link, ok := loadOwnedShortLink(c)
+	if !ok {
+		return
+	}
+	if err := models.DB.Delete(link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete short link: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// FollowShortLink 访问短链接：记录一次点击并跳转到分享页面；短链接不存在、已停用或分享已删除时返回 404 页面。
+// 跳转使用站内路径，短链接域名与站点地址不同时须解析到同一服务
+func FollowShortLink(c *gin.Context) {
+	var link models.ShortLink
+	if err := models.DB.Where("code = ?", c.Param("code")).First(&link).Error; err != nil || link.Disabled {
+		messagePage(c, http.StatusNotFound, "page.link_invalid")
+		return
+	}
+	var share models.Share
+	if err := models.DB.Scopes(models.WithoutContent).Where("id = ?", link.ShareID).First(&share).Error; err != nil {
+		messagePage(c, http.StatusNotFound, "page.link_invalid")
+		return
+	}
+	if c.Request.Method == http.MethodGet {
+		if err := models.RecordShortLinkClick(link.Code); err != nil {
+			log.Printf("Failed to record short link click (%s): %v", link.Code, err)
+		}
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("X-Robots-Tag", "noindex")
+	c.Redirect(http.StatusFound, "/s/"+share.ID)
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/signing"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VerifyShareRequest 校验下载的副本：提供正文 Markdown 源文或其 SHA-256（十六进制）之一
+type VerifyShareRequest struct {
+	Content *string `json:"content"`
+	Hash    string  `json:"hash" binding:"omitempty,len=64,hexadecimal"`
+}
+
+// signaturePayload 阅读页中的签名摘要，完整信息见 GetShareSignature
+func signaturePayload(share *models.Share) gin.H {
+	rev := models.CurrentSignedRevision(share)
+	if rev == nil {
+		return nil
+	}
+	return gin.H{
+		"version":     rev.Version,
+		"algorithm":   signing.Algorithm,
+		"contentHash": rev.ContentHash,
+		"signature":   rev.Signature,
+		"keyId":       rev.KeyID,
+	}
+}
+
+// GetSigningKey 实例签名公钥，供读者离线校验分享版本的签名
+func GetSigningKey(c *gin.Context) {
+	if signing.Default == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Signing is not available"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"keyId":        signing.Default.ID,
+		"algorithm":    signing.Algorithm,
+		"publicKey":    signing.Default.PublicKey,
+		"publicKeyPem": signing.Default.PublicKeyPEM(),
+		"statement":    signing.Statement("<shareId>", 0, "<sha256>"),
+	}})
+}
+
+// GetShareSignature 读者查看分享当前版本（或 version 参数指定的历史版本）的签名，
+// 当前版本附带源文下载地址
+func GetShareSignature(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var rev *models.ShareRevision
+	if v := c.Query("version"); v != "" {
+		version, err := strconv.Atoi(v)
+		if err != nil || version <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid version"})
+			return
+		}
+		var r models.ShareRevision
+		if err := models.DB.Scopes(models.WithoutRevisionContent).Where("share_id = ? AND version = ?", share.ID, version).
+			First(&r).Error; err == nil && r.Signed() {
+			rev = &r
+		}
+	} else {
+		rev = models.CurrentSignedRevision(share)
+	}
+	if rev == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "No signed version found"})
+		return
+	}
+	current := rev.ContentHash == models.SealDigest(share.Content)
+	data := gin.H{"signature": rev.SignatureInfo(), "current": current}
+	if current {
+		data["sourceUrl"] = getBaseURL(c) + "/api/s/" + share.ID + "/signature/source"
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": data})
+}
+
+// GetShareSignedSource 下载当前版本的正文 Markdown 源文（即被签名的内容），计一次浏览；
+// 版本号、哈希与签名随响应头返回
+func GetShareSignedSource(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	rev := models.CurrentSignedRevision(share)
+	if rev == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "No signed version found"})
+		return
+	}
+	if !countShareView(c, share) {
+		return
+	}
+	c.Header("X-Share-Version", strconv.Itoa(rev.Version))
+	c.Header("X-Content-SHA256", rev.ContentHash)
+	c.Header("X-Signature", rev.Signature)
+	c.Header("X-Signature-Key-Id", rev.KeyID)
+	c.Header("Cache-Control", "private, no-cache")
+	c.Header("Content-Disposition", `attachment; filename="`+share.ID+`-v`+strconv.Itoa(rev.Version)+`.md"`)
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(share.Content))
+}
+
+// VerifyShare 校验下载的副本是否与分享发布过的某个版本一致：按 SHA-256 匹配保留的历史版本，
+// 并用实例公钥校验该版本的签名
+func VerifyShare(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var req VerifyShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	hash := strings.ToLower(req.Hash)
+	if req.Content != nil {
+		hash = models.SealDigest(*req.Content)
+	}
+	if hash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Either content or hash is required"})
+		return
+	}
+
+	var rev models.ShareRevision
+	err := models.DB.Scopes(models.WithoutRevisionContent).Where("share_id = ? AND content_hash = ?", share.ID, hash).
+		Order("version DESC").First(&rev).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"match": false, "contentHash": hash}})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to verify: " + err.Error()})
+		return
+	}
+	valid := rev.Signed() && signing.Default.Verify(signing.Statement(rev.ShareID, rev.Version, rev.ContentHash), rev.Signature)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"match":          true,
+		"contentHash":    hash,
+		"version":        rev.Version,
+		"current":        hash == models.SealDigest(share.Content),
+		"publishedAt":    rev.CreatedAt,
+		"signatureValid": valid,
+		"signature":      rev.SignatureInfo(),
+	}})
+}
@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/gin-gonic/gin"
+)
+
+// ExportSite 管理员下载静态站点（zip）：全部公开收录的分享导出为 HTML 页面与资源文件，可托管在 GitHub Pages 或对象存储上。
+// ?title= 为首页标题；页脚链接到原分享，地址取当前站点。边生成边写出，传输途中出错只能中断下载
+func ExportSite(c *gin.Context) {
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="siyuan-share-site-`+time.Now().Format("20060102-150405")+`.zip"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+	summary, err := export.WriteSiteZip(c.Request.Context(), c.Writer, export.SiteOptions{
+		Title:   c.Query("title"),
+		BaseURL: getBaseURL(c),
+	})
+	if err != nil {
+		log.Printf("Site export failed: %v", err)
+		c.Abort()
+		return
+	}
+	for _, w := range summary.Warnings {
+		log.Printf("Site export warning: %s", w)
+	}
+}
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxQueryLength 控制台查询语句的最大长度
+const maxQueryLength = 10000
+
+// RunSQLQuery 管理员在控制台执行一条只读 SQL 查询（需开启 database.query_console），便于排查数据问题而无需进入容器。
+// 只允许单条 SELECT / WITH / VALUES / EXPLAIN 语句，在只读连接上执行，受行数上限与超时限制；
+// 每次查询（含被拒绝与失败的）都记录审计日志
+func RunSQLQuery(c *gin.Context) {
+	cfg := config.Get().Database
+	if !cfg.QueryConsole {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Query console is disabled"})
+		return
+	}
+	var req struct {
+		Query string `json:"query" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if len(req.Query) > maxQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Query is too long"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), cfg.QueryTimeout.Std())
+	defer cancel()
+	result, err := models.RunReadOnlyQuery(ctx, req.Query, cfg.QueryMaxRows)
+	if err != nil {
+		auditLog(c, "sql_query", "admin_id", c.GetString("userID"), "query", req.Query, "error", err.Error())
+		switch {
+		case errors.Is(err, models.ErrNotReadOnly):
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Only a single read-only statement is allowed"})
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Query timed out"})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Query failed: " + err.Error()})
+		}
+		return
+	}
+	auditLog(c, "sql_query", "admin_id", c.GetString("userID"), "query", req.Query,
+		"rows", len(result.Rows), "truncated", result.Truncated, "elapsed_ms", result.ElapsedMs)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": result})
+}
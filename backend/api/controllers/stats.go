@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// maxStatsDays 指标趋势最多查询的天数
+const maxStatsDays = 366
+
+// topSharesLimit 统计概览中浏览量最高的分享数
+const topSharesLimit = 10
+
+// statsDays 解析 days 查询参数（默认 30），不合法时返回 400
+func statsDays(c *gin.Context) (int, bool) {
+	v := c.Query("days")
+	if v == "" {
+		return 30, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxStatsDays {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "days must be between 1 and 366"})
+		return 0, false
+	}
+	return n, true
+}
+
+// statsFrom 最近 days 天的起始时间（服务器本地日期的 0 点）
+func statsFrom(days int) time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1-days)
+}
+
+// dailyCount 某一天的数量
+type dailyCount struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// topShare 浏览量排行中的分享
+type topShare struct {
+	ID        string    `json:"id"`
+	DocTitle  string    `json:"docTitle"`
+	Username  string    `json:"username"`
+	ViewCount int       `json:"viewCount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// AdminStats 管理员查看实例概览：当前的用户数、分享数、累计浏览量与存储用量，
+// 最近 days 天（默认 30）每天新建的分享数（无新建的日期计 0）、浏览量最高的分享，以及启动以来最近一次浏览汇总的结果与耗时
+func AdminStats(c *gin.Context) {
+	days, ok := statsDays(c)
+	if !ok {
+		return
+	}
+	totals, err := models.CollectInstanceStats()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query stats: " + err.Error()})
+		return
+	}
+
+	// 按服务器本地日期分组，与每日汇总的日期一致
+	now := time.Now()
+	from := statsFrom(days)
+	var created []time.Time
+	if err := models.DB.Model(&models.Share{}).Where("parent_share_id = ? AND created_at >= ?", "", from).
+		Pluck("created_at", &created).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query stats: " + err.Error()})
+		return
+	}
+	perDay := make([]dailyCount, days)
+	index := make(map[string]int, days)
+	for i := range perDay {
+		perDay[i].Date = from.AddDate(0, 0, i).Format(time.DateOnly)
+		index[perDay[i].Date] = i
+	}
+	for _, t := range created {
+		if i, ok := index[t.In(now.Location()).Format(time.DateOnly)]; ok {
+			perDay[i].Count++
+		}
+	}
+
+	top := make([]topShare, 0, topSharesLimit)
+	if err := models.DB.Table("shares").
+		Select("shares.id, shares.doc_title, users.username, shares.view_count, shares.created_at").
+		Joins("LEFT JOIN users ON users.id = shares.user_id").
+		Where("shares.parent_share_id = ? AND shares.deleted_at IS NULL", "").
+		Order("shares.view_count DESC, shares.created_at DESC").Limit(topSharesLimit).Scan(&top).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query stats: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"users":        totals.Users,
+		"shares":       totals.Shares,
+		"views":        totals.Views,
+		"assets":       totals.Assets,
+		"storageBytes": totals.StorageBytes,
+		"sharesPerDay": perDay,
+		"topShares":    top,
+		"viewRollup":   models.LastViewRollup(),
+	}})
+}
+
+// StatsHistory 管理员查询最近 days 天（默认 30）的每日指标汇总，按日期升序；
+// 浏览量为累计值，相邻两天相减即为当天新增
+func StatsHistory(c *gin.Context) {
+	days, ok := statsDays(c)
+	if !ok {
+		return
+	}
+	stats, err := models.InstanceStatsSince(time.Now().AddDate(0, 0, 1-days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query stats: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"days": days, "items": stats}})
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/langcheck"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// UpdateShareStatusRequest 变更分享状态请求
+type UpdateShareStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=draft published unlisted disabled"`
+}
+
+// setShareStatus 更新分享状态，引用块子分享同步变更
+func setShareStatus(tx *gorm.DB, share *models.Share, status string) error {
+	if err := tx.Model(share).Update("status", status).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Share{}).Where("parent_share_id = ? AND user_id = ?", share.ID, share.UserID).
+		Update("status", status).Error
+}
+
+// UpdateShareStatus 变更分享的生命周期状态：发布草稿、设为不公开列出、临时停用或重新上线
+func UpdateShareStatus(c *gin.Context) {
+	var req UpdateShareStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if !models.CanTransitionStatus(share.Status, req.Status) {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": fmt.Sprintf("Cannot change status from %s to %s", share.Status, req.Status)})
+		return
+	}
+	// 封存期内只能在已发布与不公开列出之间切换，不能下线
+	if req.Status != models.ShareStatusPublished && req.Status != models.ShareStatusUnlisted && rejectSealed(c, share) {
+		return
+	}
+	// 被管理员停用的分享在恢复前只能保持停用
+	if req.Status != models.ShareStatusDisabled && rejectModerated(c, share) {
+		return
+	}
+
+	previous := share.Status
+	if previous != req.Status {
+		if err := models.DB.Transaction(func(tx *gorm.DB) error {
+			return setShareStatus(tx, share, req.Status)
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to update status: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"id":       share.ID,
+		"status":   req.Status,
+		"previous": previous,
+	}})
+}
+
+// PreviewShare 所有者预览分享（包括草稿与已停用的分享），不计入浏览次数
+func PreviewShare(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	data := sharePayload(c, share)
+	// 拼写与语法检查报告只提供给作者
+	if langcheck.Enabled() {
+		data["languageCheck"] = langcheck.Current(share)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": data})
+}
@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"errors"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/ZeroHawkeye/siyuan-share-api/webpush"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SubscribeRequest 读者订阅分享更新：channel 为 email（默认）或 push
+type SubscribeRequest struct {
+	Channel      string                   `json:"channel" binding:"omitempty,oneof=email push"`
+	Email        string                   `json:"email" binding:"omitempty,email,max=255"`
+	Subscription *PushSubscriptionRequest `json:"subscription"`
+}
+
+// subscriptionResendInterval 未确认订阅重复提交时，重新发送确认邮件的最短间隔
+const subscriptionResendInterval = 10 * time.Minute
+
+// SubscribeShare 匿名读者订阅分享更新：邮箱订阅需点击确认邮件后生效，浏览器推送订阅立即生效
+func SubscribeShare(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var req SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if req.Channel == "push" {
+		subscribeSharePush(c, share, req.Subscription)
+		return
+	}
+	if req.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Email is required"})
+		return
+	}
+	if !mailer.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Email subscription is not available"})
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	var sub models.Subscription
+	err := models.DB.Where("share_id = ? AND channel = ? AND target = ?", share.ID, "email", email).First(&sub).Error
+	switch {
+	case err == nil:
+		// 已确认或刚发送过确认邮件时不再重复发信，响应保持一致以免泄露订阅状态
+		if sub.Confirmed || time.Since(sub.UpdatedAt) < subscriptionResendInterval {
+			c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"pending": true}})
+			return
+		}
+		sub.Token = randHex(24)
+		sub.Locale = middleware.Locale(c)
+		err = models.DB.Save(&sub).Error
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		sub = models.Subscription{
+			ID:      "sub_" + randHex(10),
+			ShareID: share.ID,
+			Channel: "email",
+			Target:  email,
+			Token:   randHex(24),
+			Locale:  middleware.Locale(c),
+		}
+		err = models.DB.Create(&sub).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save subscription: " + err.Error()})
+		return
+	}
+
+	baseURL := getBaseURL(c)
+	confirmURL := baseURL + "/api/subscriptions/confirm?token=" + sub.Token
+	if err := mailer.Send(mailer.Message{
+		To:      email,
+		Subject: i18n.T(sub.Locale, "email.subscribe_confirm.subject", share.DocTitle),
+		Body:    i18n.T(sub.Locale, "email.subscribe_confirm.body", share.DocTitle, confirmURL),
+	}); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to send confirmation email"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"pending": true}})
+}
+
+// subscribeSharePush 浏览器推送订阅：授权通知即视为确认，返回退订令牌供页面取消订阅
+func subscribeSharePush(c *gin.Context, share *models.Share, push *PushSubscriptionRequest) {
+	if push == nil || push.Endpoint == "" || push.Keys.P256dh == "" || push.Keys.Auth == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Push subscription is required"})
+		return
+	}
+	if !validPushEndpoint(push.Endpoint) || len(push.Endpoint) > 1024 || len(push.Keys.P256dh) > 255 || len(push.Keys.Auth) > 64 {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid push subscription"})
+		return
+	}
+	if webpush.VAPID == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"code": 1, "msg": "Web Push is not available"})
+		return
+	}
+
+	var sub models.Subscription
+	if err := models.DB.Where("share_id = ? AND channel = ? AND target = ?", share.ID, "push", push.Endpoint).First(&sub).Error; err != nil {
+		sub = models.Subscription{
+			ID:      "sub_" + randHex(10),
+			ShareID: share.ID,
+			Channel: "push",
+			Target:  push.Endpoint,
+			Token:   randHex(24),
+		}
+	}
+	sub.PushP256dh = push.Keys.P256dh
+	sub.PushAuth = push.Keys.Auth
+	sub.Confirmed = true
+	if err := models.DB.Save(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save subscription: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"pending":        false,
+		"unsubscribeUrl": notify.UnsubscribeURL(getBaseURL(c), sub.Token),
+	}})
+}
+
+// ConfirmSubscription 确认订阅（确认邮件中的链接）
+func ConfirmSubscription(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		messagePage(c, http.StatusBadRequest, "page.link_invalid")
+		return
+	}
+	result := models.DB.Model(&models.Subscription{}).Where("token = ?", token).Update("confirmed", true)
+	if result.Error != nil {
+		messagePage(c, http.StatusInternalServerError, "page.subscribe_failed")
+		return
+	}
+	if result.RowsAffected == 0 {
+		messagePage(c, http.StatusNotFound, "page.subscription_not_found")
+		return
+	}
+	messagePage(c, http.StatusOK, "page.subscribed")
+}
+
+// Unsubscribe 退订（支持邮件客户端的 List-Unsubscribe 一键退订 POST）
+func Unsubscribe(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		messagePage(c, http.StatusBadRequest, "page.link_invalid")
+		return
+	}
+	if err := models.DB.Where("token = ?", token).Delete(&models.Subscription{}).Error; err != nil {
+		messagePage(c, http.StatusInternalServerError, "page.unsubscribe_failed")
+		return
+	}
+	messagePage(c, http.StatusOK, "page.unsubscribed")
+}
+
+// messagePage 订阅确认/退订、邮箱验证等链接在浏览器中打开，返回按请求语言翻译的简单提示页面，key 为 page.* 文案
+func messagePage(c *gin.Context, status int, key string) {
+	locale := middleware.Locale(c)
+	c.Data(status, "text/html; charset=utf-8", []byte(`<!doctype html><html lang="`+locale+`"><head><meta charset="utf-8">`+
+		`<meta name="viewport" content="width=device-width,initial-scale=1"><title>`+html.EscapeString(i18n.T(locale, "page.title"))+`</title></head>`+
+		`<body style="font-family:sans-serif;text-align:center;padding:48px 16px">`+
+		`<p>`+html.EscapeString(i18n.T(locale, key))+`</p></body></html>`))
+}
+
+// notifySubscribers 分享内容更新后通知订阅者
+func notifySubscribers(c *gin.Context, share *models.Share) {
+	notify.ShareUpdated(share.ID, getBaseURL(c))
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/qa"
+	"github.com/ZeroHawkeye/siyuan-share-api/summarize"
+	"github.com/gin-gonic/gin"
+)
+
+// summaryPayload 摘要、建议标签与当前标签，供作者对照采纳；附带读者问答开关
+func summaryPayload(share *models.Share) gin.H {
+	return gin.H{
+		"enabled":       summarize.Enabled(),
+		"summary":       share.Summary,
+		"summaryManual": share.SummaryManual,
+		"suggestedTags": share.SuggestedTagList(),
+		"tags":          share.TagList(),
+		"qaEnabled":     qa.Enabled(),
+		"allowQa":       share.AllowQA,
+	}
+}
+
+// GetSummary 分享所有者查看摘要与建议标签
+func GetSummary(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": summaryPayload(share)})
+}
+
+// CreateSummary 立即为分享重新生成摘要与建议标签，覆盖作者填写的摘要
+func CreateSummary(c *gin.Context) {
+	share, ok := loadMutableShare(c)
+	if !ok {
+		return
+	}
+	if rejectEncrypted(c, share) {
+		return
+	}
+	if !summarize.Enabled() {
+		c.JSON(http.StatusNotImplemented, gin.H{"code": 1, "msg": "Summary generation is not configured"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), config.Get().AI.Timeout.Std())
+	defer cancel()
+	if err := summarize.Refresh(ctx, share, true); err != nil {
+		if errors.Is(err, summarize.ErrNoText) {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"code": 1, "msg": "Failed to generate summary: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": summaryPayload(share)})
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/gin-gonic/gin"
+)
+
+// largeTableRows 数据行数达到该值的表格随分享内容返回结构化数据，阅读页据此提供排序、筛选与 CSV 下载
+const largeTableRows = 10
+
+// shareTable 正文中的 GFM 表格，单元格为去除 Markdown 标记后的纯文本
+type shareTable struct {
+	Index   int        `json:"index"` // 正文中的第几个表格，从 1 开始
+	Line    int        `json:"line"`  // 表头所在行（从 1 开始），阅读页据此对应渲染出的表格
+	Headers []string   `json:"headers"`
+	Align   []string   `json:"align"` // left / center / right，未指定为空
+	Rows    [][]string `json:"rows"`
+}
+
+var (
+	tableDelimiterPattern = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	cellBreakPattern      = regexp.MustCompile(`(?i)<br\s*/?>`)
+	cellTagPattern        = regexp.MustCompile(`</?[A-Za-z][^<>]*>`)
+	cellEmphasisPattern   = regexp.MustCompile(`(\*\*|__|~~)(.+?)(\*\*|__|~~)`)
+	cellCodePattern       = regexp.MustCompile("`([^`]*)`")
+	cellImagePattern      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+)
+
+// extractTables 解析正文中的 GFM 表格（跳过代码块），各行单元格数与表头对齐
+func extractTables(content string) []shareTable {
+	tables := []shareTable{}
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		if !strings.Contains(trimmed, "|") || i+1 >= len(lines) {
+			continue
+		}
+		delim := strings.TrimSpace(lines[i+1])
+		if !strings.Contains(delim, "-") || !tableDelimiterPattern.MatchString(delim) {
+			continue
+		}
+
+		t := shareTable{Index: len(tables) + 1, Line: i + 1}
+		for _, h := range splitTableRow(trimmed) {
+			t.Headers = append(t.Headers, cellText(h))
+		}
+		cols := len(t.Headers)
+		for j, d := range splitTableRow(delim) {
+			if j >= cols {
+				break
+			}
+			switch {
+			case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+				t.Align = append(t.Align, "center")
+			case strings.HasSuffix(d, ":"):
+				t.Align = append(t.Align, "right")
+			case strings.HasPrefix(d, ":"):
+				t.Align = append(t.Align, "left")
+			default:
+				t.Align = append(t.Align, "")
+			}
+		}
+		for len(t.Align) < cols {
+			t.Align = append(t.Align, "")
+		}
+		t.Rows = [][]string{}
+		i += 2
+		for ; i < len(lines); i++ {
+			row := strings.TrimSpace(lines[i])
+			if row == "" || !strings.Contains(row, "|") {
+				break
+			}
+			cells := make([]string, cols)
+			for j, cell := range splitTableRow(row) {
+				if j < cols {
+					cells[j] = cellText(cell)
+				}
+			}
+			t.Rows = append(t.Rows, cells)
+		}
+		i--
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// splitTableRow 拆分表格行，忽略转义与行内代码中的竖线
+func splitTableRow(line string) []string {
+	line = strings.TrimPrefix(strings.TrimSpace(line), "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cur strings.Builder
+	inCode := false
+	for i := 0; i < len(line); i++ {
+		ch := line[i]
+		switch {
+		case ch == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cur.WriteByte('|')
+			i++
+			continue
+		case ch == '`':
+			inCode = !inCode
+		case ch == '|' && !inCode:
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(ch)
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+// cellText 将单元格中的 Markdown / HTML 转为纯文本，<br> 转为换行
+func cellText(cell string) string {
+	text := cellBreakPattern.ReplaceAllString(cell, "\n")
+	text = cellImagePattern.ReplaceAllString(text, "$1")
+	text = mdLinkPattern.ReplaceAllString(text, "$1")
+	text = mdIALPattern.ReplaceAllString(text, "")
+	text = cellTagPattern.ReplaceAllString(text, "")
+	text = cellCodePattern.ReplaceAllString(text, "$1")
+	text = cellEmphasisPattern.ReplaceAllString(text, "$2")
+	return strings.TrimSpace(text)
+}
+
+// largeTables 筛选出需要在阅读页提供交互功能的表格
+func largeTables(tables []shareTable) []shareTable {
+	large := []shareTable{}
+	for _, t := range tables {
+		if len(t.Rows) >= largeTableRows {
+			large = append(large, t)
+		}
+	}
+	return large
+}
+
+// ShareTables 以 JSON 输出分享正文中的全部表格
+func ShareTables(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	content, _ := renderShareContent(c, share)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": extractTables(content)}})
+}
+
+// ShareTableCSV 下载分享正文中第 index 个表格的 CSV（带 BOM，便于 Excel 识别 UTF-8）
+func ShareTableCSV(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	content, _ := renderShareContent(c, share)
+	tables := extractTables(content)
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil || index < 1 || index > len(tables) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Table not found"})
+		return
+	}
+	t := tables[index-1]
+
+	var b strings.Builder
+	b.WriteString("\ufeff")
+	w := csv.NewWriter(&b)
+	_ = w.Write(t.Headers)
+	_ = w.WriteAll(t.Rows)
+	if err := w.Error(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to encode table: " + err.Error()})
+		return
+	}
+
+	name := slug.FileName(share.DocTitle, share.ID) + "-table-" + strconv.Itoa(index) + ".csv"
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", []byte(b.String()))
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// 试读分享的读者阅读全文前需要完成的校验
+const (
+	teaserPassword = "password" // 输入访问密码
+	teaserPayment  = "payment"  // 付费解锁
+	teaserLogin    = "login"    // 登录任意账号
+)
+
+// teaserFallbackRunes 正文中没有 <!-- more --> 时，试读部分按块累计到该字数为止
+const teaserFallbackRunes = 300
+
+// teaserMarker 试读分隔线：独占一行的 <!-- more -->（不区分大小写）
+var teaserMarker = regexp.MustCompile(`(?i)^\s*<!--\s*more\s*-->\s*$`)
+
+// teaserContent 截取试读部分：代码块之外第一个 <!-- more --> 之前的内容；没有分隔线时取开头的若干块
+// （按空行分隔，累计约 300 字），且不超过全文块数的一半，避免短文被完整返回
+func teaserContent(content string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	inFence := false
+	var ends []int // 代码块之外每个块结束（空行）的位置
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if teaserMarker.MatchString(line) {
+			return strings.TrimRight(strings.Join(lines[:i], "\n"), "\n") + "\n"
+		}
+		if trimmed == "" && i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+			ends = append(ends, i)
+		}
+	}
+	if n := len(lines); n > 0 && strings.TrimSpace(lines[n-1]) != "" {
+		ends = append(ends, n)
+	}
+	limit := len(ends) / 2
+	cut := 0
+	for k := 0; k < limit; k++ {
+		cut = ends[k]
+		if utf8.RuneCountInString(strings.Join(lines[:cut], "\n")) >= teaserFallbackRunes {
+			break
+		}
+	}
+	if cut == 0 {
+		return ""
+	}
+	return strings.Join(lines[:cut], "\n") + "\n"
+}
+
+// teaserPayload 试读部分的阅读页数据：正文截取到分隔线，朗读音频、封存与签名等针对全文的信息不返回；
+// data.teaser 说明读者阅读全文需要完成的校验
+func teaserPayload(c *gin.Context, share *models.Share, gate string) gin.H {
+	teased := *share
+	teased.Content = teaserContent(share.Content)
+	data := sharePayload(c, &teased)
+	for _, k := range []string{"narration", "seal", "signature", "tables", "bibliography"} {
+		delete(data, k)
+	}
+	teaser := gin.H{"gate": gate}
+	if gate == teaserPayment {
+		teaser["price"] = share.PaywallPrice
+		teaser["payable"] = paymentsEnabled() && share.PaywallURL != ""
+	}
+	data["teaser"] = teaser
+	return data
+}
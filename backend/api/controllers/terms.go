@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// purposeShareTerms 读者同意使用条款后签发的令牌，主体为同意记录 ID
+const purposeShareTerms = "share_terms"
+
+// maxTermsLength 使用条款的最大字符数
+const maxTermsLength = 20000
+
+// AcceptShareTermsRequest 读者同意使用条款
+type AcceptShareTermsRequest struct {
+	Version     string `json:"version" binding:"required"` // 读者看到的条款版本，与当前版本不一致时需重新阅读
+	Name        string `json:"name" binding:"max=100"`
+	Fingerprint string `json:"fingerprint" binding:"max=128"`
+}
+
+// termsAccepted 读者是否已同意分享当前版本的使用条款：分享者本人，或持有有效同意令牌
+// （X-Share-Terms 请求头或 terms 查询参数）且条款未修改的读者
+func termsAccepted(c *gin.Context, share *models.Share) bool {
+	if userID := middleware.IdentifyUser(c); userID != "" && userID == share.UserID {
+		return true
+	}
+	token := c.GetHeader("X-Share-Terms")
+	if token == "" {
+		token = c.Query("terms")
+	}
+	if token == "" {
+		return false
+	}
+	aclID := aclShareID(share)
+	id, err := parseShareClaims(token, aclID, purposeShareTerms)
+	if err != nil {
+		return false
+	}
+	var count int64
+	models.DB.Model(&models.ShareTermsAcceptance{}).
+		Where("id = ? AND share_id = ? AND terms_hash = ?", id, aclID, share.TermsVersion()).Count(&count)
+	return count > 0
+}
+
+// readerEmail 读者的邮箱：登录读者的邮箱，或受限分享访问令牌中的邮箱
+func readerEmail(c *gin.Context, share *models.Share) (userID, email string) {
+	if userID = middleware.IdentifyUser(c); userID != "" {
+		var user models.User
+		if err := models.DB.Select("id", "email").Where("id = ?", userID).First(&user).Error; err == nil {
+			return userID, user.Email
+		}
+	}
+	token := c.GetHeader("X-Share-Access")
+	if token == "" {
+		token = c.Query("access")
+	}
+	if token != "" {
+		if e, err := parseShareToken(token, aclShareID(share), purposeShareGrant); err == nil {
+			email = e
+		}
+	}
+	return userID, email
+}
+
+// AcceptShareTerms 读者同意使用条款：记录时间、IP、User-Agent 与浏览器指纹，返回 X-Share-Terms 令牌。
+// 需先通过访问名单与密码校验
+func AcceptShareTerms(c *gin.Context) {
+	share, ok := loadShareForReader(c, false)
+	if !ok {
+		return
+	}
+	var req AcceptShareTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if share.Terms == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Share has no terms"})
+		return
+	}
+	if req.Version != share.TermsVersion() {
+		c.JSON(http.StatusConflict, gin.H{"code": 1, "msg": "Terms have changed, please review them again"})
+		return
+	}
+
+	fingerprint := strings.TrimSpace(req.Fingerprint)
+	if fingerprint == "" {
+		sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+		fingerprint = hex.EncodeToString(sum[:16])
+	}
+	userID, email := readerEmail(c, share)
+	acceptance := &models.ShareTermsAcceptance{
+		ID:          "tac_" + randHex(12),
+		ShareID:     aclShareID(share),
+		TermsHash:   share.TermsVersion(),
+		UserID:      userID,
+		Email:       email,
+		Name:        strings.TrimSpace(req.Name),
+		Fingerprint: fingerprint,
+		IP:          c.ClientIP(),
+		UserAgent:   truncateRunes(c.Request.UserAgent(), 500),
+		AcceptedAt:  time.Now(),
+	}
+	if err := models.DB.Create(acceptance).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to record acceptance: " + err.Error()})
+		return
+	}
+	token, err := issueShareToken(acceptance.ShareID, acceptance.ID, purposeShareTerms, shareGrantTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to issue token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"termsToken": token,
+		"acceptedAt": acceptance.AcceptedAt,
+		"expiresAt":  acceptance.AcceptedAt.Add(shareGrantTTL),
+	}})
+}
+
+// ListShareTermsAcceptances 分享者查看使用条款与读者的同意记录（新的在前）
+func ListShareTermsAcceptances(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	page, size := 1, 50
+	if v, err := strconv.Atoi(c.Query("page")); err == nil && v > 0 {
+		page = v
+	}
+	if v, err := strconv.Atoi(c.Query("size")); err == nil && v > 0 && v <= 200 {
+		size = v
+	}
+	var total int64
+	models.DB.Model(&models.ShareTermsAcceptance{}).Where("share_id = ?", share.ID).Count(&total)
+	items := []models.ShareTermsAcceptance{}
+	if err := models.DB.Where("share_id = ?", share.ID).Order("accepted_at DESC").Offset((page - 1) * size).Limit(size).Find(&items).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load acceptances: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"terms":   share.Terms,
+		"version": share.TermsVersion(),
+		"items":   items,
+		"total":   total,
+		"page":    page,
+		"size":    size,
+	}})
+}
+
+// validTerms 校验使用条款长度
+func validTerms(terms string) bool {
+	return utf8.RuneCountInString(terms) <= maxTermsLength
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/cache"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// ResetTestDataRequest 测试模式重置数据，seed 为空时使用 server.test_seed
+type ResetTestDataRequest struct {
+	Seed *int64 `json:"seed"`
+}
+
+// ResetTestData 清空全部数据，并重置 ID 与 Token 的生成序列、限流计数与登录失败计数，使每个测试从相同的初始状态开始。
+// 只在测试模式下注册，无需认证
+func ResetTestData(c *gin.Context) {
+	var req ResetTestDataRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	seed := config.Get().Server.TestSeed
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+
+	tables, err := models.ResetData()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to reset data: " + err.Error()})
+		return
+	}
+	cache.Flush()
+	models.InvalidateListings()
+	middleware.ResetRateLimits()
+	ipFailures.Lock()
+	clear(ipFailures.m)
+	ipFailures.Unlock()
+	// 公告、重定向规则与自定义域名缓存在内存中，随数据一并清空
+	announcementCache.Store(nil)
+	if err := middleware.ReloadRedirects(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to reset data: " + err.Error()})
+		return
+	}
+	if err := middleware.ReloadDomains(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to reset data: " + err.Error()})
+		return
+	}
+	idgen.Seed(seed)
+
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"tables": tables, "seed": seed}})
+}
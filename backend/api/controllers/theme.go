@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 内置主题
+const (
+	ThemeAuto  = "auto"
+	ThemeLight = "light"
+	ThemeDark  = "dark"
+)
+
+// themePalettes 内置主题的 CSS 变量
+var themePalettes = map[string]string{
+	ThemeLight: "--share-bg:#ffffff;--share-text:#1f2328;--share-muted:#656d76;--share-border:#d0d7de;" +
+		"--share-link:#0969da;--share-code-bg:#f6f8fa;color-scheme:light;",
+	ThemeDark: "--share-bg:#0d1117;--share-text:#e6edf3;--share-muted:#8d96a0;--share-border:#30363d;" +
+		"--share-link:#4493f8;--share-code-bg:#161b22;color-scheme:dark;",
+}
+
+// isBuiltinTheme 是否为内置主题名
+func isBuiltinTheme(name string) bool {
+	return name == ThemeAuto || name == ThemeLight || name == ThemeDark
+}
+
+// resolveTheme 按优先级协商分享页主题：?theme 查询参数 > 分享默认主题 > 浏览器 prefers-color-scheme 客户端提示 > auto
+func resolveTheme(c *gin.Context, share *models.Share) string {
+	if t := strings.ToLower(strings.TrimSpace(c.Query("theme"))); t == ThemeLight || t == ThemeDark {
+		return t
+	}
+	if share != nil && (share.Theme == ThemeLight || share.Theme == ThemeDark) {
+		return share.Theme
+	}
+	switch strings.ToLower(strings.Trim(c.GetHeader("Sec-CH-Prefers-Color-Scheme"), `" `)) {
+	case ThemeDark:
+		return ThemeDark
+	case ThemeLight:
+		return ThemeLight
+	}
+	return ThemeAuto
+}
+
+// themeStyleTag 生成服务端主题样式，首屏即使用正确的配色，避免夜间模式下闪烁或内容不可读
+func themeStyleTag(theme string) string {
+	base := "body{background:var(--share-bg);color:var(--share-text);}a{color:var(--share-link);}"
+	var css string
+	switch theme {
+	case ThemeLight, ThemeDark:
+		css = ":root{" + themePalettes[theme] + "}" + base
+	default:
+		css = ":root{" + themePalettes[ThemeLight] + "}" +
+			"@media (prefers-color-scheme: dark){:root{" + themePalettes[ThemeDark] + "}}" + base
+	}
+	return `<style id="share-theme">` + css + `</style>`
+}
+
+// applyThemeHeaders 声明依赖的客户端提示，便于浏览器在后续请求中携带配色偏好
+func applyThemeHeaders(c *gin.Context) {
+	c.Header("Accept-CH", "Sec-CH-Prefers-Color-Scheme")
+	c.Header("Critical-CH", "Sec-CH-Prefers-Color-Scheme")
+	c.Writer.Header().Add("Vary", "Sec-CH-Prefers-Color-Scheme")
+}
+
+// injectTheme 为页面写入 data-theme 属性、主题样式与可选的自定义主题样式表
+func injectTheme(page, theme, customCSS string) string {
+	page = strings.Replace(page, "<html", `<html data-theme="`+theme+`"`, 1)
+	tags := themeStyleTag(theme)
+	if customCSS != "" {
+		tags += "\n    " + `<link rel="stylesheet" id="share-custom-theme" href="` + html.EscapeString(customCSS) + `" />`
+	}
+	return strings.Replace(page, "</head>", "  "+tags+"\n  </head>", 1)
+}
+
+// maxThemeCSSSize 自定义主题 CSS 的大小上限
+const maxThemeCSSSize = 256 * 1024
+
+// ThemeRequest 创建/更新自定义主题请求
+type ThemeRequest struct {
+	Name      string `json:"name" binding:"required,min=1,max=100"`
+	CSS       string `json:"css" binding:"required"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+// ListThemes 列出当前用户的自定义主题（不含 CSS 正文）
+func ListThemes(c *gin.Context) {
+	userID := c.GetString("userID")
+	var themes []models.Theme
+	if err := models.DB.Select("id", "user_id", "name", "is_default", "created_at", "updated_at").
+		Where("user_id = ?", userID).Order("created_at DESC").Find(&themes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list themes: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"items":    themes,
+		"builtins": []string{ThemeAuto, ThemeLight, ThemeDark},
+	}})
+}
+
+// GetTheme 获取主题详情（含 CSS）
+func GetTheme(c *gin.Context) {
+	theme, err := models.FindUserTheme(c.GetString("userID"), c.Param("id"))
+	if err != nil || theme == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Theme not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": theme})
+}
+
+// CreateTheme 上传自定义主题
+func CreateTheme(c *gin.Context) {
+	userID := c.GetString("userID")
+	var req ThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if len(req.CSS) > maxThemeCSSSize {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Theme CSS too large"})
+		return
+	}
+	theme := &models.Theme{ID: "thm_" + randHex(8), UserID: userID, Name: req.Name, CSS: req.CSS, IsDefault: req.IsDefault}
+	err := models.DB.Transaction(func(tx *gorm.DB) error {
+		if theme.IsDefault {
+			if err := tx.Model(&models.Theme{}).Where("user_id = ?", userID).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(theme).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save theme: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": theme})
+}
+
+// UpdateTheme 更新自定义主题
+func UpdateTheme(c *gin.Context) {
+	userID := c.GetString("userID")
+	theme, err := models.FindUserTheme(userID, c.Param("id"))
+	if err != nil || theme == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Theme not found"})
+		return
+	}
+	var req ThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if len(req.CSS) > maxThemeCSSSize {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Theme CSS too large"})
+		return
+	}
+	theme.Name = req.Name
+	theme.CSS = req.CSS
+	theme.IsDefault = req.IsDefault
+	err = models.DB.Transaction(func(tx *gorm.DB) error {
+		if theme.IsDefault {
+			if err := tx.Model(&models.Theme{}).Where("user_id = ? AND id <> ?", userID, theme.ID).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Save(theme).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save theme: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": theme})
+}
+
+// DeleteTheme 删除自定义主题，使用该主题的分享与发布模板回退为默认主题
+func DeleteTheme(c *gin.Context) {
+	userID := c.GetString("userID")
+	id := c.Param("id")
+	result := models.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Theme{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete theme: " + result.Error.Error()})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Theme not found"})
+		return
+	}
+	models.DB.Model(&models.Share{}).Where("user_id = ? AND theme = ?", userID, id).Update("theme", "")
+	models.DB.Model(&models.Share{}).Where("user_id = ? AND variant_theme = ?", userID, id).Update("variant_theme", "")
+	models.DB.Model(&models.ShareTemplate{}).Where("user_id = ? AND theme = ?", userID, id).Update("theme", "")
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// ServeThemeCSS 公开输出主题样式表，供分享页面引用
+func ServeThemeCSS(c *gin.Context) {
+	var theme models.Theme
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&theme).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Theme not found"})
+		return
+	}
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "text/css; charset=utf-8", []byte(theme.CSS))
+}
+
+// customThemeURL 分享使用的自定义主题样式地址，未使用自定义主题时返回空
+func customThemeURL(share *models.Share) string {
+	if isBuiltinTheme(share.Theme) {
+		return ""
+	}
+	theme := models.ResolveShareTheme(share)
+	if theme == nil {
+		return ""
+	}
+	return "/api/themes/" + theme.ID + "/style.css?v=" + strconv.FormatInt(theme.UpdatedAt.Unix(), 10)
+}
@@ -1,22 +1,25 @@
 package controllers
 
 import (
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"net/http"
 
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
 	"github.com/ZeroHawkeye/siyuan-share-api/models"
 	"github.com/gin-gonic/gin"
 )
 
 type CreateTokenRequest struct {
-	Name string `json:"name" binding:"required,min=1,max=100"`
+	Name         string   `json:"name" binding:"required,min=1,max=100"`
+	AllowedCIDRs []string `json:"allowedCidrs"` // 可选：来源地址白名单，IP 或 CIDR
 }
 
 // ListTokens 列出当前用户的非删除令牌（不返回明文）
 func ListTokens(c *gin.Context) {
 	userID := c.GetString("userID")
+	// 先写入累计的使用记录，使列表反映最新的使用情况
+	models.FlushTokenUsage()
 	var tokens []models.UserToken
 	if err := models.DB.Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list tokens: " + err.Error()})
@@ -27,12 +30,14 @@ func ListTokens(c *gin.Context) {
 	for _, t := range tokens {
 		list = append(list, gin.H{
 			"id": t.ID, "name": t.Name, "revoked": t.Revoked, "lastUsedAt": t.LastUsedAt, "createdAt": t.CreatedAt,
+			"requestCount": t.RequestCount, "lastUsedIp": t.LastUsedIP, "lastUserAgent": t.LastUserAgent,
+			"allowedCidrs": t.CIDRs(),
 		})
 	}
 	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": list}})
 }
 
-// CreateToken 创建新的 API Token（返回一次明文）
+// CreateToken 创建新的 API Token（返回一次明文），可选限制来源地址
 func CreateToken(c *gin.Context) {
 	userID := c.GetString("userID")
 	var req CreateTokenRequest
@@ -40,6 +45,11 @@ func CreateToken(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
 		return
 	}
+	cidrs, err := models.NormalizeCIDRs(req.AllowedCIDRs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
 	raw := randomToken(32)
 	hash := hashToken(raw)
 	ut := &models.UserToken{
@@ -48,13 +58,14 @@ func CreateToken(c *gin.Context) {
 		Name:      req.Name,
 		TokenHash: hash,
 	}
+	ut.SetCIDRs(cidrs)
 	if err := models.DB.Create(ut).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save token: " + err.Error()})
 		return
 	}
 	ut.PlainToken = raw
 	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
-		"id": ut.ID, "name": ut.Name, "token": ut.PlainToken, "createdAt": ut.CreatedAt,
+		"id": ut.ID, "name": ut.Name, "token": ut.PlainToken, "createdAt": ut.CreatedAt, "allowedCidrs": ut.CIDRs(),
 	}})
 }
 
@@ -95,7 +106,7 @@ func RevokeToken(c *gin.Context) {
 
 func randomToken(n int) string {
 	b := make([]byte, n)
-	rand.Read(b)
+	idgen.Read(b)
 	return hex.EncodeToString(b)
 }
 
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/transcript"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxTranscriptBytes 单个文字稿上限
+const maxTranscriptBytes = 1 << 20
+
+// transcriptLanguagePattern BCP 47 语言标签，如 zh-CN、en
+var transcriptLanguagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// AttachTranscriptRequest 为音视频资源附加文字稿
+type AttachTranscriptRequest struct {
+	Path     string `json:"path" binding:"required"`                       // 资源引用路径，如 assets/lecture.mp3
+	Format   string `json:"format" binding:"omitempty,oneof=vtt srt text"` // 缺省时根据内容判断
+	Language string `json:"language"`
+	Content  string `json:"content" binding:"required"`
+}
+
+// findOwnedMediaAsset 查找当前用户分享下的音视频资源，失败时已写入响应
+func findOwnedMediaAsset(c *gin.Context, share *models.Share, p string) (*models.Asset, bool) {
+	assetPath, err := normalizeAssetPath(p, "")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid asset path"})
+		return nil, false
+	}
+	asset, err := models.FindAsset(share.ID, assetPath)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query asset: " + err.Error()})
+		return nil, false
+	}
+	if asset == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Asset not found"})
+		return nil, false
+	}
+	if !asset.IsMedia() {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Transcripts can only be attached to audio or video assets"})
+		return nil, false
+	}
+	return asset, true
+}
+
+// saveTranscript 仅更新文字稿相关字段，经由结构体更新以便大文本压缩存储
+func saveTranscript(asset *models.Asset) error {
+	return models.DB.Model(asset).Select("transcript", "transcript_format", "transcript_language").Updates(asset).Error
+}
+
+// AttachTranscript 为分享中的音视频资源附加或替换文字稿（WebVTT / SRT / 纯文本），
+// SRT 保存时转换为 WebVTT
+func AttachTranscript(c *gin.Context) {
+	share, ok := loadMutableShare(c)
+	if !ok {
+		return
+	}
+	var req AttachTranscriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	if len(req.Content) > maxTranscriptBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"code": 1, "msg": "Transcript is too large (max 1MB)"})
+		return
+	}
+	req.Language = strings.TrimSpace(req.Language)
+	if req.Language != "" && (len(req.Language) > 16 || !transcriptLanguagePattern.MatchString(req.Language)) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid transcript language"})
+		return
+	}
+	asset, ok := findOwnedMediaAsset(c, share, req.Path)
+	if !ok {
+		return
+	}
+
+	format := req.Format
+	if format == "" {
+		format = transcript.Detect(req.Content)
+	}
+	content := strings.TrimSpace(strings.ReplaceAll(req.Content, "\r\n", "\n"))
+	cueCount := 0
+	if format != transcript.FormatText {
+		cues, err := transcript.Parse(content)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid transcript: " + err.Error()})
+			return
+		}
+		content, format, cueCount = transcript.VTT(cues), transcript.FormatVTT, len(cues)
+	} else if content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Transcript is empty"})
+		return
+	}
+
+	asset.Transcript, asset.TranscriptFormat, asset.TranscriptLanguage = content, format, req.Language
+	if err := saveTranscript(asset); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save transcript: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"path":     asset.Path,
+		"format":   format,
+		"language": req.Language,
+		"cues":     cueCount,
+	}})
+}
+
+// RemoveTranscript 移除音视频资源的文字稿（query: path）
+func RemoveTranscript(c *gin.Context) {
+	share, ok := loadMutableShare(c)
+	if !ok {
+		return
+	}
+	asset, ok := findOwnedMediaAsset(c, share, c.Query("path"))
+	if !ok {
+		return
+	}
+	asset.Transcript, asset.TranscriptFormat, asset.TranscriptLanguage = "", "", ""
+	if err := saveTranscript(asset); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to remove transcript: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// ListShareTranscripts 列出分享中音视频的文字稿：vtt 返回时间轴段落，text 返回全文
+func ListShareTranscripts(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	var assets []models.Asset
+	if err := models.DB.Where("share_id = ? AND transcript_format <> ''", share.ID).Order("path").Find(&assets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to list transcripts: " + err.Error()})
+		return
+	}
+	base := getBaseURL(c) + "/api/s/" + share.ID + "/"
+	items := []gin.H{}
+	for _, a := range assets {
+		item := gin.H{
+			"path":     a.Path,
+			"url":      assetURL(c, share, a.Path, a.Hash),
+			"format":   a.TranscriptFormat,
+			"language": a.TranscriptLanguage,
+		}
+		if a.TranscriptFormat == transcript.FormatVTT {
+			cues, err := transcript.Parse(a.Transcript)
+			if err != nil {
+				continue
+			}
+			item["cues"] = cues
+			item["vttUrl"] = base + "transcripts/" + a.Path
+		} else {
+			item["text"] = a.Transcript
+		}
+		items = append(items, item)
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"items": items}})
+}
+
+// ServeTranscript 以 WebVTT 输出文字稿，供 <track> 字幕使用；与资源一样不校验访问密码
+func ServeTranscript(c *gin.Context) {
+	assetPath, err := normalizeAssetPath(c.Param("path"), "")
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Transcript not found"})
+		return
+	}
+
+	var share models.Share
+	if err := models.DB.Where("id = ?", c.Param("id")).First(&share).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found"})
+		return
+	}
+	if share.Status == models.ShareStatusDisabled && !share.InViewGrace() {
+		c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Share is disabled"})
+		return
+	}
+	if share.IsExpired() {
+		c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Share has expired"})
+		return
+	}
+
+	var asset models.Asset
+	err = models.DB.Where("share_id = ? AND path = ? AND transcript_format = ?", share.ID, assetPath, transcript.FormatVTT).First(&asset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Transcript not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to read transcript"})
+		return
+	}
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "text/vtt; charset=utf-8", []byte(asset.Transcript))
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/translate"
+	"github.com/gin-gonic/gin"
+)
+
+// TranslationRequest 生成译文
+type TranslationRequest struct {
+	Lang string `json:"lang" binding:"required"`
+}
+
+// SaveTranslationRequest 作者提供或修改译文
+type SaveTranslationRequest struct {
+	DocTitle string `json:"docTitle" binding:"required,max=255"`
+	Content  string `json:"content"`
+}
+
+// translationItem 作者查看的译文状态（不含正文）
+func translationItem(c *gin.Context, share *models.Share, t *models.ShareTranslation) gin.H {
+	return gin.H{
+		"lang":       t.Lang,
+		"docTitle":   t.DocTitle,
+		"status":     t.Status,
+		"error":      t.Error,
+		"manual":     t.Manual,
+		"stale":      t.Status == models.TranslationReady && t.Stale(share),
+		"generating": translate.Generating(share.ID, t.Lang),
+		"url":        getBaseURL(c) + "/s/" + share.ID + "/" + t.Lang,
+		"updatedAt":  t.UpdatedAt,
+	}
+}
+
+// ListTranslations 分享所有者查看分享的译文
+func ListTranslations(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	var list []models.ShareTranslation
+	if err := models.DB.Select("share_id", "lang", "doc_title", "source_hash", "status", "error", "manual", "updated_at").
+		Where("share_id = ?", share.ID).Order("lang").Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load translations: " + err.Error()})
+		return
+	}
+	items := make([]gin.H, 0, len(list))
+	for i := range list {
+		items = append(items, translationItem(c, share, &list[i]))
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"enabled":    translate.Enabled(),
+		"languages":  config.Get().Translation.Languages,
+		"sourceLang": translate.Detect(share.Content),
+		"items":      items,
+	}})
+}
+
+// GetTranslation 分享所有者读取译文全文，用于修改
+func GetTranslation(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	t := translate.Find(share.ID, c.Param("lang"))
+	if t == nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Translation not found"})
+		return
+	}
+	data := translationItem(c, share, t)
+	data["content"] = t.Content
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": data})
+}
+
+// CreateTranslation 为分享生成（或重新生成）译文，翻译在后台进行
+func CreateTranslation(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if rejectEncrypted(c, share) {
+		return
+	}
+	if !translate.Enabled() {
+		c.JSON(http.StatusNotImplemented, gin.H{"code": 1, "msg": "Translation is not configured"})
+		return
+	}
+	var req TranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	lang := strings.ToLower(strings.TrimSpace(req.Lang))
+	t, err := translate.Start(share, lang)
+	if errors.Is(err, translate.ErrUnsupportedLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Unsupported language: " + lang})
+		return
+	}
+	if errors.Is(err, translate.ErrNoText) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": err.Error()})
+		return
+	}
+	if err != nil || t == nil {
+		msg := "translation record not found"
+		if err != nil {
+			msg = err.Error()
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to start translation: " + msg})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": translationItem(c, share, t)})
+}
+
+// SaveTranslation 分享所有者提供或修改译文，之后不会被自动更新覆盖
+func SaveTranslation(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if rejectEncrypted(c, share) {
+		return
+	}
+	var req SaveTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	lang := strings.ToLower(c.Param("lang"))
+	if req.Content == "" {
+		req.Content = "\n"
+	}
+	t, err := translate.Save(share, lang, strings.TrimSpace(req.DocTitle), req.Content)
+	if errors.Is(err, translate.ErrUnsupportedLanguage) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Unsupported language: " + lang})
+		return
+	}
+	if err != nil || t == nil {
+		msg := "translation record not found"
+		if err != nil {
+			msg = err.Error()
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save translation: " + msg})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": translationItem(c, share, t)})
+}
+
+// DeleteTranslation 删除分享的译文
+func DeleteTranslation(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	if err := translate.Remove(share.ID, c.Param("lang")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to delete translation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// GetShareTranslation 读者获取分享的译文，访问校验同原文，数据格式同 GetShare
+func GetShareTranslation(c *gin.Context) {
+	share, ok := loadViewableShare(c)
+	if !ok {
+		return
+	}
+	t := translate.Find(share.ID, c.Param("lang"))
+	if t == nil || t.Status != models.TranslationReady {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Translation not found"})
+		return
+	}
+	if !isPrintRequest(c, share.ID) && !countShareView(c, share) {
+		return
+	}
+
+	translated := translatedShare(share, t)
+	data := sharePayload(c, translated)
+	data["lang"] = t.Lang
+	data["sourceLang"] = translate.Detect(share.Content)
+	modified := share.ContentModified
+	if t.UpdatedAt.After(modified) {
+		modified = t.UpdatedAt
+	}
+	respondShare(c, data, modified)
+}
+
+// translatedShare 以译文标题与正文替换原文的分享副本；摘要为原文语言，不再沿用
+func translatedShare(share *models.Share, t *models.ShareTranslation) *models.Share {
+	s := *share
+	s.DocTitle, s.Content, s.Summary = t.DocTitle, t.Content, ""
+	return &s
+}
@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// trashItem 回收站中的分享，purgeAt 为预计彻底删除的时间
+type trashItem struct {
+	ID            string    `json:"id"`
+	DocID         string    `json:"docId"`
+	DocTitle      string    `json:"docTitle"`
+	ParentShareID string    `json:"parentShareId,omitempty"`
+	ViewCount     int       `json:"viewCount"`
+	CreatedAt     time.Time `json:"createdAt"`
+	DeletedAt     time.Time `json:"deletedAt"`
+	PurgeAt       time.Time `json:"purgeAt"`
+}
+
+// loadTrashedShare 读取当前用户回收站中的分享，不存在时写入 404
+func loadTrashedShare(c *gin.Context) (*models.Share, bool) {
+	share, err := models.FindTrashedShare(c.GetString("userID"), c.Param("id"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "Share not found in trash"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load trash: " + err.Error()})
+		return nil, false
+	}
+	return share, true
+}
+
+// ListTrash 列出回收站中的分享：删除的分享保留 jobs.trash_retention 后由定时任务彻底删除，期间可以恢复
+func ListTrash(c *gin.Context) {
+	shares, err := models.TrashedShares(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load trash: " + err.Error()})
+		return
+	}
+	retention := config.Get().Jobs.TrashRetention.Std()
+	items := make([]trashItem, 0, len(shares))
+	for _, s := range shares {
+		items = append(items, trashItem{
+			ID:            s.ID,
+			DocID:         s.DocID,
+			DocTitle:      s.DocTitle,
+			ParentShareID: s.ParentShareID,
+			ViewCount:     s.ViewCount,
+			CreatedAt:     s.CreatedAt,
+			DeletedAt:     s.DeletedAt.Time,
+			PurgeAt:       s.DeletedAt.Time.Add(retention),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"items":     items,
+		"retention": retention.String(),
+	}})
+}
+
+// RestoreTrashedShare 恢复回收站中的分享，链接、正文、资源与统计保持删除前的状态。
+// 同一文档已重新发布了新的分享时返回 409，需先删除新的分享；恢复后超出分享数配额时返回 403
+func RestoreTrashedShare(c *gin.Context) {
+	share, ok := loadTrashedShare(c)
+	if !ok {
+		return
+	}
+	userID := share.UserID
+	if share.ParentShareID == "" {
+		existing, err := models.FindActiveShareByDoc(userID, share.DocID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to restore share: " + err.Error()})
+			return
+		}
+		if existing != nil {
+			c.JSON(http.StatusConflict, gin.H{
+				"code": 1,
+				"msg":  "Another share of this document exists",
+				"data": gin.H{"shareId": existing.ID},
+			})
+			return
+		}
+		if quota := models.UserQuota(userID); quota.MaxShares > 0 {
+			usage, err := models.UserUsage(userID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+				return
+			}
+			if usage.Shares >= models.GraceLimit(int64(quota.MaxShares)) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"code": CodeQuotaExceeded,
+					"msg":  "Share quota exceeded",
+					"data": gin.H{"usage": usage, "quota": quota},
+				})
+				return
+			}
+		}
+	}
+	if err := models.RestoreShare(share); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to restore share: " + err.Error()})
+		return
+	}
+	auditLog(c, "share_restored", "share_id", share.ID)
+	notify.CheckQuota(share.UserID, getBaseURL(c))
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"id":       share.ID,
+		"docTitle": share.DocTitle,
+		"shareUrl": getBaseURL(c) + "/s/" + share.ID,
+	}})
+}
+
+// PurgeTrashedShare 立即彻底删除回收站中的一个分享及其资源、统计与评论等全部数据，不可恢复
+func PurgeTrashedShare(c *gin.Context) {
+	share, ok := loadTrashedShare(c)
+	if !ok {
+		return
+	}
+	if _, err := models.PurgeShares(c.Request.Context(), []string{share.ID}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to purge share: " + err.Error()})
+		return
+	}
+	auditLog(c, "share_purged", "share_id", share.ID)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
+
+// EmptyTrash 清空回收站，彻底删除其中的全部分享
+func EmptyTrash(c *gin.Context) {
+	userID := c.GetString("userID")
+	var total int64
+	for {
+		ids, err := models.TrashedShareIDs(userID, time.Now(), 100)
+		if err == nil && len(ids) > 0 {
+			var n int64
+			n, err = models.PurgeShares(c.Request.Context(), ids)
+			total += n
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to purge share: " + err.Error()})
+			return
+		}
+		if len(ids) < 100 {
+			break
+		}
+	}
+	auditLog(c, "trash_emptied", "count", total)
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"purged": total}})
+}
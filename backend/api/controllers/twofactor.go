@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/totp"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const recoveryCodeCount = 10
+
+// TwoFactorCodeRequest 提交验证码
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// DisableTwoFactorRequest 关闭两步验证需要密码与验证码（或恢复码）
+type DisableTwoFactorRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// sealTOTPSecret 使用由 SESSION_SECRET 派生的密钥加密存储 TOTP 密钥
+func sealTOTPSecret(secret string) (string, error) {
+	gcm, err := totpCipher(purposeKey("totp_secret"))
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(secret), nil)), nil
+}
+
+// openTOTPSecret 解密 TOTP 密钥；轮换会话密钥后，旧密钥加密的数据仍可解密
+func openTOTPSecret(sealed string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	for _, key := range purposeKeys("totp_secret") {
+		gcm, err := totpCipher(key)
+		if err != nil {
+			return "", err
+		}
+		if len(raw) < gcm.NonceSize() {
+			return "", errors.New("invalid totp secret")
+		}
+		if plain, err := gcm.Open(nil, raw[:gcm.NonceSize()], raw[gcm.NonceSize():], nil); err == nil {
+			return string(plain), nil
+		}
+	}
+	return "", errors.New("invalid totp secret")
+}
+
+func totpCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// generateRecoveryCodes 生成一组恢复码，返回明文（仅展示一次）与哈希（入库）
+func generateRecoveryCodes() ([]string, string) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw := randHex(5)
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	data, _ := json.Marshal(hashes)
+	return codes, string(data)
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// verifySecondFactor 校验验证码或恢复码；成功时记录时间步或消耗恢复码
+func verifySecondFactor(user *models.User, code string) bool {
+	if user.TOTPSecret == "" {
+		return false
+	}
+	secret, err := openTOTPSecret(user.TOTPSecret)
+	if err != nil {
+		return false
+	}
+	if step, ok := totp.Validate(secret, code, time.Now()); ok {
+		// 同一验证码不能重复使用；条件更新保证并发登录时只有一个请求成功
+		result := models.DB.Model(&models.User{}).
+			Where("id = ? AND totp_last_step < ?", user.ID, step).
+			Update("totp_last_step", step)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return false
+		}
+		user.TOTPLastStep = step
+		return true
+	}
+
+	// 恢复码
+	var hashes []string
+	if user.RecoveryCodes == "" || json.Unmarshal([]byte(user.RecoveryCodes), &hashes) != nil {
+		return false
+	}
+	target := hashRecoveryCode(code)
+	for i, h := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(target)) == 1 {
+			remaining := append(append([]string{}, hashes[:i]...), hashes[i+1:]...)
+			data, _ := json.Marshal(remaining)
+			result := models.DB.Model(&models.User{}).
+				Where("id = ? AND recovery_codes = ?", user.ID, user.RecoveryCodes).
+				Update("recovery_codes", string(data))
+			if result.Error != nil || result.RowsAffected == 0 {
+				return false
+			}
+			user.RecoveryCodes = string(data)
+			return true
+		}
+	}
+	return false
+}
+
+func currentUser(c *gin.Context) (*models.User, bool) {
+	var user models.User
+	if err := models.DB.Where("id = ?", c.GetString("userID")).First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"code": 1, "msg": "User not found"})
+		return nil, false
+	}
+	return &user, true
+}
+
+// SetupTwoFactor 生成新的 TOTP 密钥与 otpauth 配置地址，需调用 EnableTwoFactor 验证后才生效
+func SetupTwoFactor(c *gin.Context) {
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Two-factor authentication is already enabled"})
+		return
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to generate secret"})
+		return
+	}
+	sealed, err := sealTOTPSecret(secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to generate secret"})
+		return
+	}
+	if err := models.DB.Model(user).Updates(map[string]interface{}{"totp_secret": sealed, "totp_last_step": 0}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save secret: " + err.Error()})
+		return
+	}
+
+	issuer := config.Get().Auth.TOTPIssuer
+	if issuer == "" {
+		issuer = "SiYuan Share"
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"secret":     secret,
+		"otpauthUrl": totp.URI(issuer, user.Username, secret),
+	}})
+}
+
+// EnableTwoFactor 验证首个验证码后启用两步验证，并返回一次性展示的恢复码
+func EnableTwoFactor(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Two-factor authentication is already enabled"})
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Call setup first"})
+		return
+	}
+	user.RecoveryCodes = ""
+	if !verifySecondFactor(user, req.Code) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid verification code"})
+		return
+	}
+
+	codes, hashes := generateRecoveryCodes()
+	if err := models.DB.Model(user).Updates(map[string]interface{}{"totp_enabled": true, "recovery_codes": hashes}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to enable two-factor authentication: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"recoveryCodes": codes}})
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码（旧恢复码全部失效）
+func RegenerateRecoveryCodes(c *gin.Context) {
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if !user.TOTPEnabled || !verifySecondFactor(user, req.Code) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid verification code"})
+		return
+	}
+	codes, hashes := generateRecoveryCodes()
+	if err := models.DB.Model(user).Update("recovery_codes", hashes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to save recovery codes: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"recoveryCodes": codes}})
+}
+
+// DisableTwoFactor 关闭两步验证
+func DisableTwoFactor(c *gin.Context) {
+	var req DisableTwoFactorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request: " + err.Error()})
+		return
+	}
+	user, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Two-factor authentication is not enabled"})
+		return
+	}
+	if user.PasswordHash == "" || bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid credentials"})
+		return
+	}
+	if !verifySecondFactor(user, req.Code) {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid verification code"})
+		return
+	}
+	if err := models.DB.Model(user).Updates(map[string]interface{}{
+		"totp_enabled":   false,
+		"totp_secret":    "",
+		"totp_last_step": 0,
+		"recovery_codes": "",
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to disable two-factor authentication: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success"})
+}
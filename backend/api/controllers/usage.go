@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// usageTopUsers 管理员用量排行返回的用户数
+const usageTopUsers = 100
+
+// apiUsageResponse 返回用户最近 days 天的每日用量（按 Token 分行）、合计与 Token 名称
+func apiUsageResponse(c *gin.Context, userID string) {
+	days, ok := statsDays(c)
+	if !ok {
+		return
+	}
+	rows, err := models.UserUsageSince(userID, statsFrom(days))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+		return
+	}
+	var total models.UsageDelta
+	for _, r := range rows {
+		total.Requests += r.Requests
+		total.Publishes += r.Publishes
+		total.Views += r.Views
+		total.Bytes += r.Bytes
+	}
+	// 已删除的 Token 也要显示名称
+	var tokens []models.UserToken
+	models.DB.Unscoped().Select("id", "name").Where("user_id = ?", userID).Find(&tokens)
+	names := make(map[string]string, len(tokens))
+	for _, t := range tokens {
+		names[t.ID] = t.Name
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"days":   days,
+		"items":  rows,
+		"tokens": names,
+		"total": gin.H{
+			"requests":  total.Requests,
+			"publishes": total.Publishes,
+			"views":     total.Views,
+			"bytes":     total.Bytes,
+		},
+	}})
+}
+
+// GetAPIUsage 当前用户最近 days 天（默认 30）的接口用量
+func GetAPIUsage(c *gin.Context) {
+	apiUsageResponse(c, c.GetString("userID"))
+}
+
+// GetUserAPIUsage 管理员查看指定用户的接口用量
+func GetUserAPIUsage(c *gin.Context) {
+	user, ok := loadQuotaUser(c)
+	if !ok {
+		return
+	}
+	apiUsageResponse(c, user.ID)
+}
+
+// usageRank 管理员用量排行中的一行
+type usageRank struct {
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	Requests  int64  `json:"requests"`
+	Publishes int64  `json:"publishes"`
+	Views     int64  `json:"views"`
+	Bytes     int64  `json:"bytes"`
+}
+
+// ListAPIUsage 管理员查看最近 days 天各用户的用量合计，按流量降序，最多返回 usageTopUsers 个用户
+func ListAPIUsage(c *gin.Context) {
+	days, ok := statsDays(c)
+	if !ok {
+		return
+	}
+	models.FlushUsage()
+	rows := make([]usageRank, 0)
+	if err := models.DB.Table("api_usage").
+		Select("api_usage.user_id, users.username, SUM(api_usage.requests) AS requests, SUM(api_usage.publishes) AS publishes, "+
+			"SUM(api_usage.views) AS views, SUM(api_usage.bytes) AS bytes").
+		Joins("LEFT JOIN users ON users.id = api_usage.user_id").
+		Where("api_usage.date >= ?", statsFrom(days).Format(time.DateOnly)).
+		Group("api_usage.user_id, users.username").
+		Order("bytes DESC, requests DESC").Limit(usageTopUsers).Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query usage: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{"days": days, "items": rows}})
+}
+
+// shareBandwidth 分享当月读者流量
+type shareBandwidth struct {
+	ShareID  string `json:"shareId"`
+	DocTitle string `json:"docTitle"`
+	Bytes    int64  `json:"bytes"`
+}
+
+// GetBandwidth 当前用户本月各分享的读者流量（按流量降序）与流量配额
+func GetBandwidth(c *gin.Context) {
+	userID := c.GetString("userID")
+	models.FlushBandwidth()
+	rows := make([]shareBandwidth, 0)
+	if err := models.DB.Table("share_bandwidth").
+		Select("share_bandwidth.share_id, shares.doc_title, share_bandwidth.bytes").
+		Joins("LEFT JOIN shares ON shares.id = share_bandwidth.share_id").
+		Where("share_bandwidth.user_id = ? AND share_bandwidth.month = ?", userID, models.CurrentMonth()).
+		Order("share_bandwidth.bytes DESC").Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to query bandwidth: " + err.Error()})
+		return
+	}
+	var total int64
+	for _, r := range rows {
+		total += r.Bytes
+	}
+	c.JSON(http.StatusOK, gin.H{"code": 0, "msg": "success", "data": gin.H{
+		"month": models.CurrentMonth(),
+		"items": rows,
+		"total": total,
+		"limit": models.UserQuota(userID).MaxBandwidth,
+	}})
+}
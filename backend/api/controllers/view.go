@@ -1,27 +1,230 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
+	"github.com/ZeroHawkeye/siyuan-share-api/alert"
+	"github.com/ZeroHawkeye/siyuan-share-api/cache"
+	"github.com/ZeroHawkeye/siyuan-share-api/challenge"
+	"github.com/ZeroHawkeye/siyuan-share-api/citation"
+	"github.com/ZeroHawkeye/siyuan-share-api/events"
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/hooks"
+	"github.com/ZeroHawkeye/siyuan-share-api/linkpreview"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
 	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/qa"
+	"github.com/ZeroHawkeye/siyuan-share-api/translate"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// GetShare 获取分享内容
+// GetShare 获取分享内容；试读分享的读者未通过密码、付费或登录校验时只返回试读部分
 func GetShare(c *gin.Context) {
+	share, gate, ok := loadShareOrTeaser(c)
+	if !ok {
+		return
+	}
+
+	// 增加浏览次数（导出 PDF 时的打印请求除外）
+	if !isPrintRequest(c, share.ID) && !countShareView(c, share) {
+		return
+	}
+
+	if gate != "" {
+		respondShare(c, teaserPayload(c, share, gate), share.ContentModified)
+		return
+	}
+	respondShare(c, sharePayload(c, share), share.ContentModified)
+}
+
+// respondShare 返回阅读页数据。浏览次数之外的数据未变化时返回 304，重复访问的读者不必重新下载正文；
+// 正文之外还有链接预览、资源签名等随时间变化的部分，因此 ETag 取自生成的数据而非仅正文哈希
+func respondShare(c *gin.Context, data gin.H, modified time.Time) {
+	viewCount := data["viewCount"]
+	delete(data, "viewCount")
+	digest, _ := json.Marshal(data)
+	data["viewCount"] = viewCount
+	c.Header("Cache-Control", "private, no-cache")
+	if notModified(c, weakETag(digest), modified) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code": 0,
+		"msg":  "success",
+		"data": data,
+	})
+}
+
+// countShareView 记录一次读者浏览：增加浏览次数、计入浏览量告警与所有者的用量并保存浏览记录；
+// 浏览次数已用尽时已写入响应并返回 false，见 claimShareView
+func countShareView(c *gin.Context, share *models.Share) bool {
+	if !claimShareView(c, share) {
+		return false
+	}
+	alert.ShareViewed(share.ID, share.DocTitle, getBaseURL(c)+"/s/"+share.ID)
+	models.RecordUsage(share.UserID, "", models.UsageDelta{Views: 1})
+	recordShareView(c, share)
+	events.Viewed(share.UserID, share.ID, share.DocTitle, share.ViewCount)
+	return true
+}
+
+// sharePayload 生成阅读页所需的分享数据
+func sharePayload(c *gin.Context, share *models.Share) gin.H {
+	if share.Encrypted() {
+		return encryptedPayload(c, share)
+	}
+	content, bibliography := renderShareContent(c, share)
+	content = deliverShareContent(c, share, content)
+	shown := variantShare(c, share)
+	return gin.H{
+		"id":              share.ID,
+		"docTitle":        share.DocTitle,
+		"content":         prerenderShareContent(c, share, content),
+		"requirePassword": share.RequirePassword,
+		"restricted":      share.Restricted,
+		"allowPdf":        share.AllowPDF,
+		"assetDownloads":  share.DownloadPolicy(),
+		"allowQa":         share.AllowQA && qa.Enabled(),
+		"allowFeedback":   share.AllowFeedback,
+		"trackReading":    share.TrackReading,
+		"readingMinutes":  share.ReadingMinutes,
+		"wordCount":       share.WordCount,
+		"lang":            translate.Detect(share.Content),
+		"translations":    models.ReadyTranslationLangs(share.ID),
+		"expireAt":        share.ExpireAt,
+		"theme":           resolveTheme(c, shown),
+		"toc":             shown.TOC,
+		"variant":         shareVariant(c, share),
+		"headingAnchors":  share.HeadingAnchors,
+		"headings":        export.Headings(content),
+		"prerender":       prerenderPayload(share),
+		"customThemeUrl":  customThemeURL(shown),
+		"linkPreviews":    linkPreviews(content),
+		"archivedLinks":   archivedLinks(share),
+		"narration":       narrationPayload(c, share),
+		"bibliography":    bibliography,
+		"tables":          largeTables(extractTables(content)),
+		"tasks":           models.CountTasks(content),
+		"mode":            share.Mode,
+		"cardCount":       len(share.FlashcardList()),
+		"status":          share.Status,
+		"seal":            sealPayload(share),
+		"signature":       signaturePayload(share),
+		"viewLimit":       viewLimitPayload(c, share),
+		"viewCount":       share.ViewCount,
+		"paywall":         share.Paywall,
+		"donation":        ownerDonation(share.UserID),
+		"createdAt":       share.CreatedAt,
+	}
+}
+
+// renderedContent 缓存的阅读页正文
+type renderedContent struct {
+	Content      string
+	Bibliography []citation.Entry
+}
+
+// renderShareContent 生成阅读页正文：调用渲染前钩子，替换块引用链接，渲染文献引用标注并生成参考文献列表。
+// 结果按正文、引用数据与站点地址缓存；配置了渲染前钩子时不缓存（钩子可能因读者而异）
+func renderShareContent(c *gin.Context, share *models.Share) (string, []citation.Entry) {
+	if !cache.Enabled() || hooks.Has(hooks.PreRender) {
+		return renderShareContentUncached(c, share)
+	}
+	sum := sha256.Sum256([]byte(getBaseURL(c) + "\x00" + share.Content + "\x00" + share.References + "\x00" + share.Citations))
+	key := "render:" + share.ID + ":" + hex.EncodeToString(sum[:])
+	var r renderedContent
+	if cache.Load(key, &r) {
+		if r.Bibliography == nil {
+			r.Bibliography = []citation.Entry{}
+		}
+		return r.Content, r.Bibliography
+	}
+	content, bibliography := renderShareContentUncached(c, share)
+	cache.Save(key, &renderedContent{Content: content, Bibliography: bibliography})
+	return content, bibliography
+}
+
+// renderShareContentUncached 不经缓存生成阅读页正文：执行资源地址之前的处理步骤（见 contentPipeline）
+func renderShareContentUncached(c *gin.Context, share *models.Share) (string, []citation.Entry) {
+	d := &contentDoc{c: c, share: share}
+	content := contentPipeline.Run(d, share.Content, 0, stageAssetURLs)
+	if d.bibliography == nil {
+		d.bibliography = []citation.Entry{}
+	}
+	return content, d.bibliography
+}
+
+// linkPreviews 返回独占一行的外部链接的缓存预览（URL -> 预览），未缓存的链接在后台抓取
+func linkPreviews(content string) map[string]*models.LinkPreview {
+	return linkpreview.Lookup(linkpreview.ExtractBareLinks(content))
+}
+
+// loadViewableShare 加载可供读者访问的分享，依次校验存在、发布状态、过期、开放时间、访问名单（受限分享）、访问密码、使用条款
+// 与付费阅读（密码取自 password 查询参数或 X-Share-Password 请求头）；校验失败时已写入响应并返回 false
+func loadViewableShare(c *gin.Context) (*models.Share, bool) {
+	return loadShareForReader(c, true)
+}
+
+// loadShareForReader 同 loadViewableShare；requireTerms 为 false 时不检查使用条款、付费阅读与试读模式（读者提交同意与创建订单时使用）
+func loadShareForReader(c *gin.Context, requireTerms bool) (*models.Share, bool) {
+	share, _, ok := checkShareReader(c, requireTerms, false)
+	return share, ok
+}
+
+// loadShareOrTeaser 同 loadViewableShare，供阅读页正文接口使用：开启试读模式的分享在读者缺少密码、未付费或未登录时
+// 不返回错误，而是返回读者需要完成的校验（teaserPassword / teaserPayment / teaserLogin），由调用方只返回试读部分
+func loadShareOrTeaser(c *gin.Context) (*models.Share, string, bool) {
+	return checkShareReader(c, true, true)
+}
+
+// checkShareReader 依次执行读者访问校验；teaser 为 true 时试读分享未通过的校验记为 gate 而不是写入错误响应
+func checkShareReader(c *gin.Context, requireTerms, teaser bool) (*models.Share, string, bool) {
+	gate := ""
 	shareID := c.Param("id")
 
-	var share models.Share
-	if err := models.DB.Where("id = ?", shareID).First(&share).Error; err != nil {
+	share, err := models.FindShare(shareID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"code": 1,
 			"msg":  "Share not found",
 		})
-		return
+		return nil, "", false
+	}
+	// 读者访问的流量计入分享所有者
+	c.Set("contentOwner", share.UserID)
+	c.Set("contentShare", share.ID)
+
+	// 草稿与已停用的分享不可访问
+	switch share.Status {
+	case models.ShareStatusDraft:
+		c.JSON(http.StatusForbidden, gin.H{
+			"code": 1,
+			"msg":  "Share is not published",
+		})
+		return nil, "", false
+	case models.ShareStatusDisabled:
+		// 浏览次数用尽后自动停用的分享：最后一位读者在宽限时间内可继续加载阅读页数据
+		if share.ViewsExhaustedAt != nil {
+			if viewGrantValid(c, share) {
+				break
+			}
+			respondViewsExhausted(c)
+			return nil, "", false
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"code": 1,
+			"msg":  "Share is disabled",
+		})
+		return nil, "", false
 	}
 
 	// 检查是否过期
@@ -30,56 +233,118 @@ func GetShare(c *gin.Context) {
 			"code": 1,
 			"msg":  "Share has expired",
 		})
-		return
+		return nil, "", false
+	}
+
+	// 导出 PDF 的打印请求由服务端签发令牌，已在导出接口完成校验
+	if isPrintRequest(c, share.ID) {
+		return share, gate, true
+	}
+
+	// 不在开放时间内时返回下一次开放时间，阅读页据此显示倒计时
+	if schedule := share.Schedule(); schedule != nil && !schedule.Open(time.Now()) {
+		var opensAt *time.Time
+		if t, ok := schedule.NextOpen(time.Now()); ok {
+			opensAt = &t
+		}
+		c.JSON(http.StatusForbidden, gin.H{
+			"code": CodeShareNotOpen,
+			"msg":  "Share is not open at this time",
+			"data": gin.H{"opensAt": opensAt, "timezone": schedule.Location().String()},
+		})
+		return nil, "", false
+	}
+
+	// 受限分享仅对访问名单开放
+	if share.Restricted && !canAccessRestricted(c, share) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"code": CodeShareRestricted,
+			"msg":  "Share is restricted",
+			"data": gin.H{"emailAccess": mailer.Enabled()},
+		})
+		return nil, "", false
 	}
 
 	// 如果需要密码，验证密码
 	if share.RequirePassword {
-		password := c.Query("password")
-		if password == "" {
+		password := sharePassword(c)
+		if password == "" && teaser && share.Teaser {
+			gate = teaserPassword
+		} else if password == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"code": 1,
 				"msg":  "Password required",
 			})
-			return
+			return nil, "", false
+		} else if !verifySharePassword(c, share, password) {
+			return nil, "", false
 		}
+	}
+
+	// 设置了使用条款时，读者同意后才能阅读
+	if requireTerms && share.Terms != "" && !termsAccepted(c, share) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"code": CodeTermsRequired,
+			"msg":  "Terms must be accepted",
+			"data": gin.H{"terms": share.Terms, "version": share.TermsVersion()},
+		})
+		return nil, "", false
+	}
 
-		if err := bcrypt.CompareHashAndPassword([]byte(share.PasswordHash), []byte(password)); err != nil {
+	// 付费分享在读者支付后才能阅读
+	if requireTerms && share.Paywall && !paywallUnlocked(c, share) {
+		if !teaser || !share.Teaser {
+			respondPaymentRequired(c, share)
+			return nil, "", false
+		}
+		if gate == "" {
+			gate = teaserPayment
+		}
+	}
+
+	// 未设置密码与付费的试读分享须登录后阅读全文
+	if requireTerms && share.Teaser && !share.RequirePassword && !share.Paywall && middleware.IdentifyUser(c) == "" {
+		if !teaser {
 			c.JSON(http.StatusUnauthorized, gin.H{
-				"code": 1,
-				"msg":  "Invalid password",
+				"code": CodeLoginRequired,
+				"msg":  "Login required",
 			})
-			return
+			return nil, "", false
 		}
+		gate = teaserLogin
 	}
 
-	// 增加浏览次数
-	models.DB.Model(&share).UpdateColumn("view_count", share.ViewCount+1)
+	return share, gate, true
+}
 
-	// 处理引用链接替换
-	content := share.Content
-	if share.References != "" {
-		var refs []models.BlockReference
-		if err := json.Unmarshal([]byte(share.References), &refs); err == nil {
-			// 获取 baseURL 用于构建引用块分享链接
-			baseURL := getBaseURL(c)
-			content = replaceBlockReferences(content, refs, baseURL, share.UserID)
-		}
+// sharePassword 读者提交的访问密码，取自 password 查询参数或 X-Share-Password 请求头
+func sharePassword(c *gin.Context) string {
+	if password := c.Query("password"); password != "" {
+		return password
 	}
+	return c.GetHeader("X-Share-Password")
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code": 0,
-		"msg":  "success",
-		"data": gin.H{
-			"id":              share.ID,
-			"docTitle":        share.DocTitle,
-			"content":         content,
-			"requirePassword": share.RequirePassword,
-			"expireAt":        share.ExpireAt,
-			"viewCount":       share.ViewCount + 1,
-			"createdAt":       share.CreatedAt,
-		},
-	})
+// verifySharePassword 校验读者提交的访问密码：同一 IP 对该分享输错达到 challenge.after_failures 次（至少 1 次，
+// 否则之后每个携带密码的请求都要验证）后要求人机验证，密码正确后清除失败记录；未通过时已写入响应并返回 false
+func verifySharePassword(c *gin.Context, share *models.Share, password string) bool {
+	key := c.ClientIP() + "|" + share.ID
+	failures, _ := sharePasswordFailures.current(key)
+	if failures > 0 && !passChallengeAfterFailures(c, challenge.ActionSharePassword, failures) {
+		return false
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(share.PasswordHash), []byte(password)); err != nil {
+		sharePasswordFailures.add(key)
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"code": 1,
+			"msg":  "Invalid password",
+		})
+		return false
+	}
+	if failures > 0 {
+		sharePasswordFailures.reset(key)
+	}
+	return true
 }
 
 // getBaseURL 获取基础 URL
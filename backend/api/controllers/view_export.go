@@ -0,0 +1,254 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/geoip"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/gin-gonic/gin"
+)
+
+// viewExportFlushRows 导出浏览记录时每写出多少行刷新一次响应
+const viewExportFlushRows = 1000
+
+// viewExportGroups 浏览记录导出支持的汇总方式，空值导出原始记录
+var viewExportGroups = map[string]string{"": "viewedAt", "day": "date", "referrer": "referrer", "country": "country"}
+
+// recordShareView 保存一次浏览的原始记录：来源站点取自阅读页提交的 X-Share-Referrer 请求头（阅读页数据由脚本请求），
+// 其次为 Referer 请求头，只保留主机名，本站页面不计为来源
+func recordShareView(c *gin.Context, share *models.Share) {
+	ref := c.GetHeader("X-Share-Referrer")
+	if ref == "" {
+		ref = c.Request.Referer()
+	}
+	host := ""
+	if u, err := url.Parse(ref); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		host = strings.ToLower(u.Hostname())
+	}
+	if base, err := url.Parse(getBaseURL(c)); err == nil && strings.EqualFold(host, base.Hostname()) {
+		host = ""
+	}
+	if len(host) > 255 {
+		host = host[:255]
+	}
+	country := geoip.Country(c.Request, c.ClientIP())
+	if len(country) > 8 {
+		country = ""
+	}
+	models.RecordShareView(models.ShareView{ShareID: share.ID, Referrer: host, Country: country, Variant: shareVariant(c, share)})
+}
+
+// parseViewExportTime 解析导出范围：日期（服务器本地日期，to 包含当天）或 RFC 3339 时间
+func parseViewExportTime(v string, end bool) (time.Time, error) {
+	if d, err := time.ParseInLocation(time.DateOnly, v, time.Local); err == nil {
+		if end {
+			d = d.AddDate(0, 0, 1)
+		}
+		return d, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// ExportShareViews 所有者导出分享的浏览数据，format 为 csv（默认）或 json；
+// group 为空时逐条导出原始浏览记录，为 day / referrer / country 时按日期（服务器本地）、来源站点或国家/地区汇总浏览次数。
+// from / to 为日期或 RFC 3339 时间，默认为原始记录保留期内的全部数据；原始记录按行流式输出
+func ExportShareViews(c *gin.Context) {
+	share, ok := loadOwnedShare(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "format must be csv or json"})
+		return
+	}
+	group := c.Query("group")
+	keyName, ok := viewExportGroups[group]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "group must be day, referrer or country"})
+		return
+	}
+	to := time.Now()
+	from := to.Add(-config.Get().Jobs.ViewRetention.Std())
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = parseViewExportTime(v, false); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = parseViewExportTime(v, true); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid from or to"})
+			return
+		}
+	}
+
+	models.FlushShareViews()
+	var counts map[string]int64
+	rawFrom := from
+	if group != "" {
+		counts = map[string]int64{}
+		// 原始记录可能已删除的日期使用每日汇总
+		if boundary := viewSummaryBoundary(); from.Before(boundary) {
+			end := boundary
+			if to.Before(end) {
+				end = to
+			}
+			if err := models.AddShareViewDailyCounts(counts, share.ID, group, from, end); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load views: " + err.Error()})
+				return
+			}
+			rawFrom = boundary
+		}
+	}
+	rows, err := models.DB.Model(&models.ShareView{}).Select("viewed_at, referrer, country").
+		Where("share_id = ? AND viewed_at >= ? AND viewed_at < ?", share.ID, rawFrom, to).Order("viewed_at").Rows()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to load views: " + err.Error()})
+		return
+	}
+	defer rows.Close()
+
+	name := slug.FileName(share.DocTitle, share.ID) + "-views"
+	if group != "" {
+		name += "-by-" + group
+	}
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name + "." + format}))
+	if format == "csv" {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+	} else {
+		c.Header("Content-Type", "application/json; charset=utf-8")
+	}
+	c.Status(http.StatusOK)
+
+	out := newViewExportWriter(c, format, keyName)
+	for n := 1; rows.Next(); n++ {
+		var v models.ShareView
+		if err := rows.Scan(&v.ViewedAt, &v.Referrer, &v.Country); err != nil {
+			log.Printf("view export of %s failed: %v", share.ID, err)
+			break
+		}
+		switch group {
+		case "":
+			out.write(v.ViewedAt.Format(time.RFC3339), v.Referrer, v.Country)
+			if n%viewExportFlushRows == 0 {
+				out.flush()
+			}
+		case "day":
+			counts[v.ViewedAt.In(time.Local).Format(time.DateOnly)]++
+		case "referrer":
+			counts[v.Referrer]++
+		case "country":
+			counts[v.Country]++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("view export of %s failed: %v", share.ID, err)
+	}
+	if group != "" {
+		keys := make([]string, 0, len(counts))
+		for k := range counts {
+			keys = append(keys, k)
+		}
+		// 日期按时间顺序，来源与地区按浏览次数从多到少
+		sort.Slice(keys, func(i, j int) bool {
+			if group == "day" || counts[keys[i]] == counts[keys[j]] {
+				return keys[i] < keys[j]
+			}
+			return counts[keys[i]] > counts[keys[j]]
+		})
+		for _, k := range keys {
+			out.write(k, counts[k])
+		}
+	}
+	out.close()
+}
+
+// viewSummaryBoundary 此前的日期按每日汇总导出：这些日期已经汇总，且原始记录可能已按 view_retention 删除；
+// 没有汇总数据时返回零值
+func viewSummaryBoundary() time.Time {
+	last, ok := models.LastRolledUpDay()
+	if !ok {
+		return time.Time{}
+	}
+	boundary := last.AddDate(0, 0, 1)
+	// 保留期起点所在日期的原始记录不完整，同样使用汇总
+	cutoff := time.Now().Add(-config.Get().Jobs.ViewRetention.Std()).In(time.Local)
+	if start := time.Date(cutoff.Year(), cutoff.Month(), cutoff.Day()+1, 0, 0, 0, 0, time.Local); start.Before(boundary) {
+		boundary = start
+	}
+	return boundary
+}
+
+// viewExportWriter 按 CSV（带 BOM，便于 Excel 识别 UTF-8）或 JSON 数组逐行写出浏览数据
+type viewExportWriter struct {
+	c       *gin.Context
+	csv     *csv.Writer
+	columns []string
+	rows    int
+}
+
+func newViewExportWriter(c *gin.Context, format, keyName string) *viewExportWriter {
+	columns := []string{keyName, "views"}
+	if keyName == "viewedAt" {
+		columns = []string{"viewedAt", "referrer", "country"}
+	}
+	w := &viewExportWriter{c: c, columns: columns}
+	if format == "csv" {
+		c.Writer.WriteString("\ufeff")
+		w.csv = csv.NewWriter(c.Writer)
+		_ = w.csv.Write(columns)
+	} else {
+		c.Writer.WriteString("[")
+	}
+	return w
+}
+
+// write 写出一行，values 与列一一对应
+func (w *viewExportWriter) write(values ...any) {
+	w.rows++
+	if w.csv != nil {
+		record := make([]string, len(values))
+		for i, v := range values {
+			record[i] = fmt.Sprint(v)
+		}
+		_ = w.csv.Write(record)
+		return
+	}
+	obj := make(map[string]any, len(values))
+	for i, v := range values {
+		obj[w.columns[i]] = v
+	}
+	data, _ := json.Marshal(obj)
+	if w.rows > 1 {
+		w.c.Writer.WriteString(",")
+	}
+	w.c.Writer.WriteString("\n")
+	w.c.Writer.Write(data)
+}
+
+func (w *viewExportWriter) flush() {
+	if w.csv != nil {
+		w.csv.Flush()
+	}
+	w.c.Writer.Flush()
+}
+
+func (w *viewExportWriter) close() {
+	if w.csv == nil {
+		w.c.Writer.WriteString("\n]\n")
+	}
+	w.flush()
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/gin-gonic/gin"
+)
+
+// purposeShareView 限制浏览次数的分享在计入浏览后签发的令牌（主体为第几次浏览），最后一位读者在分享自动停用后的宽限时间内
+// 凭此继续加载文字稿、绘图等阅读页数据
+const purposeShareView = "share_view"
+
+// maxViewsLimit 浏览次数上限的最大值
+const maxViewsLimit = 10000
+
+// claimShareView 计入一次浏览；浏览次数已用尽时写入 410 响应并返回 false。
+// 限制浏览次数的分享由分享者本人打开时不计数，计数成功后签发浏览令牌（见 viewGrantValid）
+func claimShareView(c *gin.Context, share *models.Share) bool {
+	isOwner := false
+	if userID := middleware.IdentifyUser(c); userID != "" && userID == share.UserID {
+		isOwner = true
+	}
+	if share.MaxViews > 0 && isOwner {
+		return true
+	}
+	ok, err := models.ClaimView(share)
+	if err != nil {
+		log.Printf("Failed to record view for %s: %v", share.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to record view"})
+		return false
+	}
+	if !ok {
+		respondViewsExhausted(c)
+		return false
+	}
+	if share.MaxViews > 0 {
+		if token, err := issueShareToken(share.ID, strconv.Itoa(share.ViewCount), purposeShareView, models.ViewLimitGrace); err == nil {
+			c.Set("viewGrant", token)
+		}
+	}
+	if !isOwner {
+		notify.OwnerShareViewed(share, getBaseURL(c))
+	}
+	return true
+}
+
+// respondViewsExhausted 浏览次数已用尽
+func respondViewsExhausted(c *gin.Context) {
+	c.JSON(http.StatusGone, gin.H{"code": 1, "msg": "Share has reached its view limit"})
+}
+
+// viewGrantValid 分享因浏览次数用尽而停用后，请求是否仍处于宽限时间内且带有有效的浏览令牌
+// （X-Share-View 请求头或 view 查询参数）
+func viewGrantValid(c *gin.Context, share *models.Share) bool {
+	if !share.InViewGrace() {
+		return false
+	}
+	token := c.GetHeader("X-Share-View")
+	if token == "" {
+		token = c.Query("view")
+	}
+	if token == "" {
+		return false
+	}
+	_, err := parseShareClaims(token, share.ID, purposeShareView)
+	return err == nil
+}
+
+// viewLimitPayload 阅读页中的浏览次数限制：上限、剩余次数与浏览令牌
+func viewLimitPayload(c *gin.Context, share *models.Share) gin.H {
+	if share.MaxViews <= 0 {
+		return nil
+	}
+	left := share.MaxViews - share.ViewCount
+	if left < 0 {
+		left = 0
+	}
+	return gin.H{"maxViews": share.MaxViews, "viewsLeft": left, "viewToken": c.GetString("viewGrant")}
+}
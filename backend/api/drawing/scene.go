@@ -0,0 +1,205 @@
+// Package drawing 解析随分享发布的白板绘图（目前支持 Excalidraw 场景 JSON），
+// 计算画布范围并在服务端渲染为 SVG；手绘风格（roughness）按规整线条绘制。
+package drawing
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+)
+
+// FormatExcalidraw Excalidraw 场景（.excalidraw 文件内容）
+const FormatExcalidraw = "excalidraw"
+
+// Scene Excalidraw 场景，仅保留渲染所需的字段
+type Scene struct {
+	Type     string          `json:"type"`
+	Elements []Element       `json:"elements"`
+	AppState AppState        `json:"appState"`
+	Files    map[string]File `json:"files"`
+}
+
+// AppState 画布状态
+type AppState struct {
+	ViewBackgroundColor string `json:"viewBackgroundColor"`
+}
+
+// File 场景内嵌的图片
+type File struct {
+	MimeType string `json:"mimeType"`
+	DataURL  string `json:"dataURL"`
+}
+
+// Element 场景中的图形元素
+type Element struct {
+	ID              string      `json:"id"`
+	Type            string      `json:"type"`
+	X               float64     `json:"x"`
+	Y               float64     `json:"y"`
+	Width           float64     `json:"width"`
+	Height          float64     `json:"height"`
+	Angle           float64     `json:"angle"` // 弧度，顺时针
+	StrokeColor     string      `json:"strokeColor"`
+	BackgroundColor string      `json:"backgroundColor"`
+	FillStyle       string      `json:"fillStyle"` // solid / hachure / cross-hatch / zigzag
+	StrokeWidth     float64     `json:"strokeWidth"`
+	StrokeStyle     string      `json:"strokeStyle"` // solid / dashed / dotted
+	Opacity         *float64    `json:"opacity"`     // 0-100，缺省为 100
+	Roundness       *Roundness  `json:"roundness"`
+	Points          [][]float64 `json:"points"` // 线条、箭头与手绘的点，相对于 (x, y)
+	StartArrowhead  *string     `json:"startArrowhead"`
+	EndArrowhead    *string     `json:"endArrowhead"`
+	Text            string      `json:"text"`
+	FontSize        float64     `json:"fontSize"`
+	FontFamily      int         `json:"fontFamily"`
+	TextAlign       string      `json:"textAlign"`
+	FileID          string      `json:"fileId"`
+	Name            string      `json:"name"` // 框架名称
+	IsDeleted       bool        `json:"isDeleted"`
+}
+
+// Roundness 圆角设置，存在即表示圆角
+type Roundness struct {
+	Type int `json:"type"`
+}
+
+// maxElements 单个绘图的元素数上限
+const maxElements = 20000
+
+// Parse 解析 Excalidraw 场景并丢弃已删除的元素
+func Parse(data []byte) (*Scene, error) {
+	var scene Scene
+	if err := json.Unmarshal(data, &scene); err != nil {
+		return nil, errors.New("invalid excalidraw JSON")
+	}
+	if scene.Type != FormatExcalidraw {
+		return nil, errors.New(`not an excalidraw scene (type must be "excalidraw")`)
+	}
+	if len(scene.Elements) > maxElements {
+		return nil, errors.New("too many elements")
+	}
+	elements := scene.Elements[:0]
+	for _, el := range scene.Elements {
+		if !el.IsDeleted {
+			elements = append(elements, el)
+		}
+	}
+	scene.Elements = elements
+	return &scene, nil
+}
+
+// Bounds 场景内容的范围（最小 x、y 与宽高），空场景返回全零
+func (s *Scene) Bounds() (minX, minY, width, height float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for i := range s.Elements {
+		for _, p := range s.Elements[i].outline() {
+			minX, minY = math.Min(minX, p[0]), math.Min(minY, p[1])
+			maxX, maxY = math.Max(maxX, p[0]), math.Max(maxY, p[1])
+		}
+	}
+	if math.IsInf(minX, 1) {
+		return 0, 0, 0, 0
+	}
+	return minX, minY, maxX - minX, maxY - minY
+}
+
+// IsLinear 是否为由点构成的线条类元素
+func (el *Element) IsLinear() bool {
+	return el.Type == "line" || el.Type == "arrow" || el.Type == "freedraw"
+}
+
+// Center 元素外框中心，旋转围绕该点进行
+func (el *Element) Center() (float64, float64) {
+	return el.X + el.Width/2, el.Y + el.Height/2
+}
+
+// AbsPoints 线条类元素的绝对坐标
+func (el *Element) AbsPoints() [][2]float64 {
+	pts := make([][2]float64, 0, len(el.Points))
+	for _, p := range el.Points {
+		if len(p) >= 2 {
+			pts = append(pts, [2]float64{el.X + p[0], el.Y + p[1]})
+		}
+	}
+	return pts
+}
+
+// Lines 文本按行拆分
+func (el *Element) Lines() []string {
+	return strings.Split(strings.ReplaceAll(el.Text, "\r\n", "\n"), "\n")
+}
+
+// LineHeight 文本行高
+func (el *Element) LineHeight() float64 {
+	return el.FontSizeOrDefault() * 1.25
+}
+
+// FontSizeOrDefault 字号，缺省为 20
+func (el *Element) FontSizeOrDefault() float64 {
+	if el.FontSize > 0 {
+		return el.FontSize
+	}
+	return 20
+}
+
+// StrokeWidthOrDefault 线宽，缺省为 1
+func (el *Element) StrokeWidthOrDefault() float64 {
+	if el.StrokeWidth > 0 {
+		return el.StrokeWidth
+	}
+	return 1
+}
+
+// Alpha 不透明度（0-1）
+func (el *Element) Alpha() float64 {
+	if el.Opacity == nil {
+		return 1
+	}
+	return math.Max(0, math.Min(100, *el.Opacity)) / 100
+}
+
+// Arrowheads 两端的箭头样式，空字符串表示无箭头
+func (el *Element) Arrowheads() (start, end string) {
+	if el.StartArrowhead != nil {
+		start = *el.StartArrowhead
+	}
+	if el.EndArrowhead != nil {
+		end = *el.EndArrowhead
+	}
+	return start, end
+}
+
+// outline 元素旋转后的轮廓点，用于计算场景范围
+func (el *Element) outline() [][2]float64 {
+	var pts [][2]float64
+	if el.IsLinear() {
+		pts = el.AbsPoints()
+		pad := el.StrokeWidthOrDefault() * 2
+		if start, end := el.Arrowheads(); start != "" || end != "" {
+			pad += 10
+		}
+		grown := make([][2]float64, 0, len(pts)*2)
+		for _, p := range pts {
+			grown = append(grown, [2]float64{p[0] - pad, p[1] - pad}, [2]float64{p[0] + pad, p[1] + pad})
+		}
+		pts = grown
+	} else {
+		half := el.StrokeWidthOrDefault() / 2
+		pts = [][2]float64{
+			{el.X - half, el.Y - half}, {el.X + el.Width + half, el.Y - half},
+			{el.X + el.Width + half, el.Y + el.Height + half}, {el.X - half, el.Y + el.Height + half},
+		}
+	}
+	if el.Angle == 0 {
+		return pts
+	}
+	cx, cy := el.Center()
+	sin, cos := math.Sincos(el.Angle)
+	for i, p := range pts {
+		dx, dy := p[0]-cx, p[1]-cy
+		pts[i] = [2]float64{cx + dx*cos - dy*sin, cy + dx*sin + dy*cos}
+	}
+	return pts
+}
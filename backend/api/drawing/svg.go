@@ -0,0 +1,323 @@
+package drawing
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// padding 画布四周留白
+const padding = 10
+
+var (
+	colorPattern   = regexp.MustCompile(`^(?:#[0-9A-Fa-f]{3,8}|[A-Za-z]{3,20}|rgba?\([0-9.,% ]+\))$`)
+	dataURLPattern = regexp.MustCompile(`^data:image/(?:png|jpeg|gif|webp|svg\+xml);base64,[A-Za-z0-9+/=\s]+$`)
+)
+
+// fontFamilies Excalidraw 字体编号对应的 CSS 字体，未安装手写字体时回退到系统字体
+var fontFamilies = map[int]string{
+	1: "Virgil, Xiaolai, 'Segoe Print', 'Comic Sans MS', cursive",
+	2: "Helvetica, Arial, 'PingFang SC', 'Microsoft YaHei', sans-serif",
+	3: "Cascadia, 'Cascadia Code', Consolas, monospace",
+	5: "Excalifont, Xiaolai, 'Segoe Print', 'Comic Sans MS', cursive",
+	6: "Nunito, Helvetica, Arial, sans-serif",
+	8: "'Comic Shanns', Consolas, monospace",
+}
+
+// Color 规范化颜色值，transparent 与无效值返回空字符串
+func Color(c string) string {
+	c = strings.TrimSpace(c)
+	if c == "" || strings.EqualFold(c, "transparent") || !colorPattern.MatchString(c) {
+		return ""
+	}
+	return c
+}
+
+// DashArray 线型对应的虚线间隔，实线返回 nil
+func (el *Element) DashArray() []float64 {
+	w := el.StrokeWidthOrDefault()
+	switch el.StrokeStyle {
+	case "dashed":
+		return []float64{8, 8 + w}
+	case "dotted":
+		return []float64{1.5, 6 + w}
+	}
+	return nil
+}
+
+// svgWriter 逐个元素输出 SVG，填充图案统一放在 defs 中
+type svgWriter struct {
+	scene    *Scene
+	body     strings.Builder
+	defs     strings.Builder
+	patterns map[string]string
+}
+
+// SVG 将场景渲染为独立的 SVG 文档
+func SVG(scene *Scene) []byte {
+	minX, minY, width, height := scene.Bounds()
+	minX, minY = minX-padding, minY-padding
+	width, height = width+2*padding, height+2*padding
+
+	w := &svgWriter{scene: scene, patterns: map[string]string{}}
+	if bg := Color(scene.AppState.ViewBackgroundColor); bg != "" {
+		fmt.Fprintf(&w.body, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+			num(minX), num(minY), num(width), num(height), attr(bg))
+	}
+	for i := range scene.Elements {
+		w.element(&scene.Elements[i])
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s" width="%s" height="%s">`+"\n",
+		num(minX), num(minY), num(width), num(height), num(math.Ceil(width)), num(math.Ceil(height)))
+	if w.defs.Len() > 0 {
+		b.WriteString("<defs>\n" + w.defs.String() + "</defs>\n")
+	}
+	b.WriteString(w.body.String())
+	b.WriteString("</svg>\n")
+	return []byte(b.String())
+}
+
+func (w *svgWriter) element(el *Element) {
+	var shape string
+	switch el.Type {
+	case "rectangle", "frame", "magicframe", "embeddable", "iframe":
+		shape = w.rect(el)
+	case "ellipse":
+		cx, cy := el.Center()
+		shape = fmt.Sprintf(`<ellipse cx="%s" cy="%s" rx="%s" ry="%s"%s/>`,
+			num(cx), num(cy), num(el.Width/2), num(el.Height/2), w.paint(el, true))
+	case "diamond":
+		cx, cy := el.Center()
+		shape = fmt.Sprintf(`<polygon points="%s,%s %s,%s %s,%s %s,%s"%s/>`,
+			num(cx), num(el.Y), num(el.X+el.Width), num(cy), num(cx), num(el.Y+el.Height), num(el.X), num(cy), w.paint(el, true))
+	case "line", "arrow":
+		shape = w.line(el)
+	case "freedraw":
+		shape = w.freedraw(el)
+	case "text":
+		shape = w.text(el)
+	case "image":
+		shape = w.image(el)
+	}
+	if shape == "" {
+		return
+	}
+
+	var attrs string
+	if el.Angle != 0 {
+		cx, cy := el.Center()
+		attrs += fmt.Sprintf(` transform="rotate(%s %s %s)"`, num(el.Angle*180/math.Pi), num(cx), num(cy))
+	}
+	if a := el.Alpha(); a < 1 {
+		attrs += fmt.Sprintf(` opacity="%s"`, num(a))
+	}
+	if attrs != "" {
+		shape = "<g" + attrs + ">" + shape + "</g>"
+	}
+	w.body.WriteString(shape + "\n")
+}
+
+func (w *svgWriter) rect(el *Element) string {
+	var rx string
+	if el.Roundness != nil {
+		// 与 Excalidraw 一致：圆角取短边的 25%，最大 32
+		r := math.Min(math.Min(el.Width, el.Height)*0.25, 32)
+		rx = fmt.Sprintf(` rx="%s"`, num(r))
+	}
+	paint := w.paint(el, true)
+	if el.Type != "rectangle" {
+		paint = ` fill="none" stroke="#bbb" stroke-width="1" stroke-dasharray="4 4"`
+	}
+	shape := fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s"%s%s/>`,
+		num(el.X), num(el.Y), num(math.Abs(el.Width)), num(math.Abs(el.Height)), rx, paint)
+	if el.Type == "frame" && el.Name != "" {
+		shape += fmt.Sprintf(`<text x="%s" y="%s" font-size="14" font-family="%s" fill="#999">%s</text>`,
+			num(el.X), num(el.Y-6), attr(fontFamilies[2]), html.EscapeString(el.Name))
+	}
+	return shape
+}
+
+func (w *svgWriter) line(el *Element) string {
+	pts := el.AbsPoints()
+	if len(pts) < 2 {
+		return ""
+	}
+	closed := el.Type == "line" && len(pts) > 2 && pts[0] == pts[len(pts)-1]
+	tag := "polyline"
+	if closed {
+		tag = "polygon"
+		pts = pts[:len(pts)-1]
+	}
+	shape := fmt.Sprintf(`<%s points="%s"%s/>`, tag, pointList(pts), w.paint(el, closed))
+	start, end := el.Arrowheads()
+	if start != "" {
+		shape += w.arrowhead(el, start, pts[1], pts[0])
+	}
+	if end != "" {
+		shape += w.arrowhead(el, end, pts[len(pts)-2], pts[len(pts)-1])
+	}
+	return shape
+}
+
+// arrowhead 在 tip 处绘制从 from 指向 tip 的箭头
+func (w *svgWriter) arrowhead(el *Element, style string, from, tip [2]float64) string {
+	stroke := strokeColor(el)
+	sw := el.StrokeWidthOrDefault()
+	angle := math.Atan2(tip[1]-from[1], tip[0]-from[0])
+	size := math.Min(15+sw*2, math.Hypot(tip[0]-from[0], tip[1]-from[1]))
+	wing := func(delta float64) [2]float64 {
+		return [2]float64{tip[0] - size*math.Cos(angle+delta), tip[1] - size*math.Sin(angle+delta)}
+	}
+	switch style {
+	case "triangle", "triangle_outline":
+		l, r := wing(math.Pi/8), wing(-math.Pi/8)
+		fill := attr(stroke)
+		if style == "triangle_outline" {
+			fill = "none"
+		}
+		return fmt.Sprintf(`<polygon points="%s" fill="%s" stroke="%s" stroke-width="%s" stroke-linejoin="round"/>`,
+			pointList([][2]float64{tip, l, r}), fill, attr(stroke), num(sw))
+	case "dot", "circle", "circle_outline":
+		fill := attr(stroke)
+		if style == "circle_outline" {
+			fill = "none"
+		}
+		return fmt.Sprintf(`<circle cx="%s" cy="%s" r="%s" fill="%s" stroke="%s" stroke-width="%s"/>`,
+			num(tip[0]), num(tip[1]), num(4+sw), fill, attr(stroke), num(sw))
+	case "bar":
+		return fmt.Sprintf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linecap="round"/>`,
+			pointList([][2]float64{wing(math.Pi / 2), tip, wing(-math.Pi / 2)}), attr(stroke), num(sw))
+	default: // arrow
+		l, r := wing(math.Pi/7), wing(-math.Pi/7)
+		return fmt.Sprintf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linecap="round" stroke-linejoin="round"/>`,
+			pointList([][2]float64{l, tip, r}), attr(stroke), num(sw))
+	}
+}
+
+func (w *svgWriter) freedraw(el *Element) string {
+	pts := el.AbsPoints()
+	if len(pts) == 0 {
+		return ""
+	}
+	sw := el.StrokeWidthOrDefault() * 1.5
+	if len(pts) == 1 {
+		return fmt.Sprintf(`<circle cx="%s" cy="%s" r="%s" fill="%s"/>`, num(pts[0][0]), num(pts[0][1]), num(sw/2), attr(strokeColor(el)))
+	}
+	var d strings.Builder
+	for i, p := range pts {
+		if i == 0 {
+			d.WriteString("M" + num(p[0]) + " " + num(p[1]))
+		} else {
+			d.WriteString("L" + num(p[0]) + " " + num(p[1]))
+		}
+	}
+	return fmt.Sprintf(`<path d="%s" fill="none" stroke="%s" stroke-width="%s" stroke-linecap="round" stroke-linejoin="round"/>`,
+		d.String(), attr(strokeColor(el)), num(sw))
+}
+
+func (w *svgWriter) text(el *Element) string {
+	if strings.TrimSpace(el.Text) == "" {
+		return ""
+	}
+	size := el.FontSizeOrDefault()
+	x, anchor := el.X, "start"
+	switch el.TextAlign {
+	case "center":
+		x, anchor = el.X+el.Width/2, "middle"
+	case "right":
+		x, anchor = el.X+el.Width, "end"
+	}
+	family, ok := fontFamilies[el.FontFamily]
+	if !ok {
+		family = fontFamilies[2]
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, `<text font-size="%s" font-family="%s" fill="%s" text-anchor="%s" xml:space="preserve">`,
+		num(size), attr(family), attr(strokeColor(el)), anchor)
+	for i, line := range el.Lines() {
+		// 基线约在行框顶部下方 0.9 倍字号处
+		y := el.Y + float64(i)*el.LineHeight() + (el.LineHeight()-size)/2 + size*0.9
+		fmt.Fprintf(&b, `<tspan x="%s" y="%s">%s</tspan>`, num(x), num(y), html.EscapeString(line))
+	}
+	b.WriteString("</text>")
+	return b.String()
+}
+
+func (w *svgWriter) image(el *Element) string {
+	file, ok := w.scene.Files[el.FileID]
+	if !ok || !dataURLPattern.MatchString(file.DataURL) {
+		return fmt.Sprintf(`<rect x="%s" y="%s" width="%s" height="%s" fill="#f1f3f5" stroke="#adb5bd" stroke-dasharray="4 4"/>`,
+			num(el.X), num(el.Y), num(el.Width), num(el.Height))
+	}
+	return fmt.Sprintf(`<image x="%s" y="%s" width="%s" height="%s" preserveAspectRatio="none" href="%s"/>`,
+		num(el.X), num(el.Y), num(el.Width), num(el.Height), attr(file.DataURL))
+}
+
+// paint 生成描边与填充属性；filled 为 false 时不填充
+func (w *svgWriter) paint(el *Element, filled bool) string {
+	fill := "none"
+	if bg := Color(el.BackgroundColor); filled && bg != "" {
+		switch el.FillStyle {
+		case "hachure", "cross-hatch", "zigzag":
+			fill = "url(#" + w.pattern(el.FillStyle, bg) + ")"
+		default:
+			fill = attr(bg)
+		}
+	}
+	s := fmt.Sprintf(` fill="%s"`, fill)
+	if stroke := Color(el.StrokeColor); stroke != "" {
+		s += fmt.Sprintf(` stroke="%s" stroke-width="%s" stroke-linecap="round" stroke-linejoin="round"`,
+			attr(stroke), num(el.StrokeWidthOrDefault()))
+		if dash := el.DashArray(); dash != nil {
+			s += fmt.Sprintf(` stroke-dasharray="%s %s"`, num(dash[0]), num(dash[1]))
+		}
+	}
+	return s
+}
+
+// pattern 返回斜线填充图案的 ID，同样式同颜色的图案只定义一次
+func (w *svgWriter) pattern(style, color string) string {
+	key := style + "|" + color
+	if id, ok := w.patterns[key]; ok {
+		return id
+	}
+	id := "fill" + strconv.Itoa(len(w.patterns)+1)
+	w.patterns[key] = id
+	lines := `<line x1="0" y1="0" x2="0" y2="8" stroke="` + attr(color) + `" stroke-width="1"/>`
+	if style == "cross-hatch" {
+		lines += `<line x1="0" y1="0" x2="8" y2="0" stroke="` + attr(color) + `" stroke-width="1"/>`
+	}
+	fmt.Fprintf(&w.defs, `<pattern id="%s" patternUnits="userSpaceOnUse" width="8" height="8" patternTransform="rotate(-41)">%s</pattern>`+"\n", id, lines)
+	return id
+}
+
+func strokeColor(el *Element) string {
+	if c := Color(el.StrokeColor); c != "" {
+		return c
+	}
+	return "#1e1e1e"
+}
+
+func pointList(pts [][2]float64) string {
+	parts := make([]string, len(pts))
+	for i, p := range pts {
+		parts[i] = num(p[0]) + "," + num(p[1])
+	}
+	return strings.Join(parts, " ")
+}
+
+func num(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "0"
+	}
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+func attr(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,62 @@
+package election
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/cache"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// DB 租约保存在数据库的 leases 表中，适用于共用同一数据库的多个实例；过期判断使用各实例的本地时钟，实例间的时钟应保持同步
+type DB struct{}
+
+func (DB) Acquire(_ context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	return models.AcquireLease(name, holder, ttl)
+}
+
+func (DB) Release(_ context.Context, name, holder string) error {
+	return models.ReleaseLease(name, holder)
+}
+
+// redisKeyPrefix 租约键的前缀，与缓存共用 Redis 时的其他键区分
+const redisKeyPrefix = "siyuan-share:lease:"
+
+// acquireScript 已由本实例持有时续期，否则仅在键不存在时写入；过期由 Redis 判断，不受实例时钟影响
+const acquireScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+  return 1
+end
+return 0`
+
+// releaseScript 只删除本实例持有的租约
+const releaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+  return redis.call("DEL", KEYS[1])
+end
+return 0`
+
+// Redis 租约保存在 Redis 中（键 siyuan-share:lease:<名称>）
+type Redis struct {
+	Client *cache.Redis
+}
+
+func (r Redis) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	reply, err := r.Client.Eval(ctx, acquireScript, []string{redisKeyPrefix + name}, holder, strconv.FormatInt(ttl.Milliseconds(), 10))
+	if err != nil {
+		return false, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("redis: unexpected EVAL reply %T", reply)
+	}
+	return n == 1, nil
+}
+
+func (r Redis) Release(ctx context.Context, name, holder string) error {
+	_, err := r.Client.Eval(ctx, releaseScript, []string{redisKeyPrefix + name}, holder)
+	return err
+}
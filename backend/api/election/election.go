@@ -0,0 +1,178 @@
+// Package election 多实例部署时的选主：各实例定期争取同一名称的租约（保存在数据库或 Redis 中），
+// 持有租约的实例为主实例，每隔有效期的 1/3 续期；主实例退出或失联后租约过期，由其他实例接管。
+// 定时任务只在主实例上执行（见 scheduler），保证多副本部署时每次只执行一次
+package election
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// opTimeout 单次争取或释放租约的超时
+const opTimeout = 5 * time.Second
+
+// ErrNotLeader 本实例已不是主实例，LeaderContext 派生的 ctx 取消时的原因（context.Cause）
+var ErrNotLeader = errors.New("no longer the leader")
+
+// Backend 租约存储
+type Backend interface {
+	// Acquire 租约空闲、已过期或已由 holder 持有时取得（续期）租约，有效期为 ttl；返回 holder 是否持有租约
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// Release 释放 holder 持有的租约，未持有时不报错
+	Release(ctx context.Context, name, holder string) error
+}
+
+// Elector 争取并续期一个租约
+type Elector struct {
+	backend Backend
+	name    string
+	id      string
+	ttl     time.Duration
+
+	// until 本实例确认持有租约的截止时间 (UnixNano)：最近一次成功续期时的时间加有效期，
+	// 续期失败或进程停顿时到期后自动视为不再是主实例
+	until atomic.Int64
+
+	// lost 失去主实例身份（或停止）时关闭并换成新的通道，见 LeaderContext
+	mu   sync.Mutex
+	lost chan struct{}
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// New 创建选主器，实例 ID 由主机名与随机后缀组成
+func New(backend Backend, name string, ttl time.Duration) *Elector {
+	return &Elector{backend: backend, name: name, id: instanceID(), ttl: ttl, lost: make(chan struct{}),
+		stop: make(chan struct{}), done: make(chan struct{})}
+}
+
+// instanceID 本实例的标识：主机名（容器中通常为容器 ID 或 Pod 名）加随机后缀，同一主机上的多个进程互不相同
+func instanceID() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "instance"
+	}
+	b := make([]byte, 4)
+	rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
+
+// ID 本实例的标识
+func (e *Elector) ID() string {
+	return e.id
+}
+
+// IsLeader 本实例当前是否为主实例
+func (e *Elector) IsLeader() bool {
+	return time.Now().UnixNano() < e.until.Load()
+}
+
+// LeaderContext 派生一个在本实例失去主实例身份时取消的 ctx：续期被拒绝、停止选主，
+// 或无法续期直到已确认的截止时间到期。调用时本实例已不是主实例则立即取消。
+// 主实例上开始执行的任务以它为 ctx，失去租约后在批次之间停止，避免与接管的实例同时执行
+func (e *Elector) LeaderContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	e.mu.Lock()
+	lost := e.lost
+	e.mu.Unlock()
+	go func() {
+		for {
+			wait := time.Until(time.Unix(0, e.until.Load()))
+			if wait <= 0 {
+				cancel(ErrNotLeader)
+				return
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-lost:
+				timer.Stop()
+				cancel(ErrNotLeader)
+				return
+			case <-timer.C:
+				// 到期前可能已续期，重新读取截止时间
+			}
+		}
+	}()
+	return ctx, func() { cancel(nil) }
+}
+
+// resign 通知 LeaderContext 派生的 ctx 本实例已不是主实例
+func (e *Elector) resign() {
+	e.mu.Lock()
+	close(e.lost)
+	e.lost = make(chan struct{})
+	e.mu.Unlock()
+}
+
+// Run 立即争取一次租约，之后每隔有效期的 1/3 续期或重新争取，直到 Stop
+func (e *Elector) Run() {
+	defer close(e.done)
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	for {
+		e.campaign()
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign 争取或续期一次，记录主实例身份的变化
+func (e *Elector) campaign() {
+	was := e.IsLeader()
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+	ok, err := e.backend.Acquire(ctx, e.name, e.id, e.ttl)
+	cancel()
+	switch {
+	case err != nil:
+		// 无法确认时保留已确认的截止时间，到期后自动失去主实例身份
+		log.Printf("Leader election %s: %v", e.name, err)
+	case ok:
+		// 以发出请求的时间计算，存储中的租约不会早于本地截止时间过期
+		e.until.Store(started.Add(e.ttl).UnixNano())
+	default:
+		e.until.Store(0)
+	}
+	if now := e.IsLeader(); now != was {
+		if now {
+			log.Printf("Instance %s is now the leader for %s", e.id, e.name)
+		} else {
+			e.resign()
+			log.Printf("Instance %s is no longer the leader for %s", e.id, e.name)
+		}
+	}
+}
+
+// Stop 停止续期并释放租约，其他实例随即可以接管
+func (e *Elector) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+		<-e.done
+		leader := e.IsLeader()
+		e.until.Store(0)
+		e.resign()
+		if !leader {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), opTimeout)
+		defer cancel()
+		if err := e.backend.Release(ctx, e.name, e.id); err != nil {
+			log.Printf("Failed to release lease %s: %v", e.name, err)
+		}
+	})
+}
@@ -0,0 +1,127 @@
+// Package embedding 分享的语义向量：把标题、摘要与正文交给 OpenAI 兼容的向量接口计算向量，保存在数据库中，
+// 在作者自己的分享范围内按余弦相似度推荐相关分享与语义搜索。单个作者的分享数量有限，直接在进程内逐一比较，
+// 不依赖数据库的向量扩展。
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// maxResponseBytes 接口响应体大小上限
+const maxResponseBytes = 16 << 20
+
+// Enabled 是否配置了向量接口 (embedding.url)
+func Enabled() bool {
+	return config.Get().Embedding.Enabled()
+}
+
+// Embed 请求向量接口，按输入顺序返回归一化后的向量
+func Embed(ctx context.Context, inputs []string) ([][]float32, error) {
+	cfg := config.Get().Embedding
+	body, err := json.Marshal(map[string]interface{}{"model": cfg.Model, "input": inputs})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("embedding service returned %s: %s", resp.Status, msg)
+	}
+	var result struct {
+		Data []struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("invalid embedding service response: %w", err)
+	}
+	vectors := make([][]float32, len(inputs))
+	for _, d := range result.Data {
+		if d.Index < 0 || d.Index >= len(inputs) || len(d.Embedding) == 0 {
+			return nil, errors.New("invalid embedding service response: unexpected item")
+		}
+		vectors[d.Index] = normalize(d.Embedding)
+	}
+	for _, v := range vectors {
+		if v == nil {
+			return nil, errors.New("invalid embedding service response: missing items")
+		}
+	}
+	return vectors, nil
+}
+
+// normalize 归一化为单位长度，之后点积即余弦相似度
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+	n := float32(1 / math.Sqrt(sum))
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x * n
+	}
+	return out
+}
+
+// dot 两个等长向量的点积
+func dot(a, b []float32) float32 {
+	var s float32
+	for i := range a {
+		s += a[i] * b[i]
+	}
+	return s
+}
+
+// encode 以 float32 小端序编码向量
+func encode(v []float32) []byte {
+	b := make([]byte, 4*len(v))
+	for i, x := range v {
+		binary.LittleEndian.PutUint32(b[4*i:], math.Float32bits(x))
+	}
+	return b
+}
+
+// decode 解码 encode 的结果
+func decode(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[4*i:]))
+	}
+	return v
+}
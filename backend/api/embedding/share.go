@@ -0,0 +1,170 @@
+package embedding
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/tts"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNoText 分享没有可计算向量的文字
+	ErrNoText = errors.New("share has no text to index")
+	// ErrNotIndexed 分享尚未计算向量
+	ErrNotIndexed = errors.New("share is not indexed yet")
+)
+
+// Match 相似度匹配结果
+type Match struct {
+	ShareID string
+	Score   float32
+}
+
+// 同时进行的索引任务数，外部接口通常按并发限流
+var slots = make(chan struct{}, 2)
+
+// shareText 参与计算向量的文本：标题、摘要与正文纯文本，超过 embedding.max_input_chars 时截断
+func shareText(share *models.Share) string {
+	text := strings.TrimSpace(share.DocTitle + "\n" + share.Summary + "\n" + tts.Text(share.Content))
+	if n := config.Get().Embedding.MaxInputChars; n > 0 {
+		if runes := []rune(text); len(runes) > n {
+			text = string(runes[:n])
+		}
+	}
+	return text
+}
+
+// upToDate 分享的向量是否与当前文本和模型一致
+func upToDate(shareID, hash string) bool {
+	var e models.ShareEmbedding
+	res := models.DB.Select("text_hash", "model").Where("share_id = ?", shareID).Limit(1).Find(&e)
+	return res.Error == nil && res.RowsAffected > 0 && e.TextHash == hash && e.Model == config.Get().Embedding.Model
+}
+
+// Index 计算并保存分享的向量，文本与模型未变化时跳过
+func Index(ctx context.Context, share *models.Share) error {
+	text := shareText(share)
+	if text == "" {
+		return ErrNoText
+	}
+	hash := tts.Hash(text)
+	if upToDate(share.ID, hash) {
+		return nil
+	}
+	vectors, err := Embed(ctx, []string{text})
+	if err != nil {
+		return err
+	}
+	e := &models.ShareEmbedding{
+		ShareID: share.ID, UserID: share.UserID, Model: config.Get().Embedding.Model,
+		TextHash: hash, Dim: len(vectors[0]), Vector: encode(vectors[0]),
+	}
+	return models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "share_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "model", "text_hash", "dim", "vector", "updated_at"}),
+	}).Create(e).Error
+}
+
+// Auto 发布后在后台更新分享的向量
+func Auto(share *models.Share) {
+	if !Enabled() {
+		return
+	}
+	s := *share
+	background.Go(func() {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		ctx, cancel := context.WithTimeout(context.Background(), config.Get().Embedding.Timeout.Std())
+		defer cancel()
+		if err := Index(ctx, &s); err != nil && !errors.Is(err, ErrNoText) {
+			log.Printf("embedding for share %s failed: %v", s.ID, err)
+		}
+	})
+}
+
+// Reindex 在后台为用户尚未索引或已过期的全部分享计算向量，返回待处理的分享数
+func Reindex(userID string) (int, error) {
+	var ids []string
+	if err := models.DB.Model(&models.Share{}).Where("user_id = ?", userID).Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if !background.Go(func() {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		for _, id := range ids {
+			var share models.Share
+			if err := models.DB.Where("id = ?", id).First(&share).Error; err != nil {
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), config.Get().Embedding.Timeout.Std())
+			err := Index(ctx, &share)
+			cancel()
+			if err != nil && !errors.Is(err, ErrNoText) {
+				// 接口故障时不再继续请求，其余分享留待下次重建
+				log.Printf("embedding reindex for user %s stopped at share %s: %v", userID, id, err)
+				return
+			}
+		}
+	}) {
+		return 0, errors.New("server is shutting down")
+	}
+	return len(ids), nil
+}
+
+// candidates 用户未删除分享中以当前模型计算的向量
+func candidates(userID string) ([]models.ShareEmbedding, error) {
+	var list []models.ShareEmbedding
+	owned := models.DB.Model(&models.Share{}).Select("id").Where("user_id = ?", userID)
+	err := models.DB.Where("user_id = ? AND model = ? AND share_id IN (?)", userID, config.Get().Embedding.Model, owned).Find(&list).Error
+	return list, err
+}
+
+// rank 按与 query 的相似度从高到低返回前 limit 个结果，跳过 exclude
+func rank(list []models.ShareEmbedding, query []float32, exclude string, limit int) []Match {
+	matches := make([]Match, 0, len(list))
+	for _, e := range list {
+		if e.ShareID == exclude || e.Dim != len(query) {
+			continue
+		}
+		matches = append(matches, Match{ShareID: e.ShareID, Score: dot(query, decode(e.Vector))})
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// Related 与分享最相似的同一作者的其他分享
+func Related(userID, shareID string, limit int) ([]Match, error) {
+	list, err := candidates(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range list {
+		if e.ShareID == shareID {
+			return rank(list, decode(e.Vector), shareID, limit), nil
+		}
+	}
+	return nil, ErrNotIndexed
+}
+
+// Search 在用户的分享中按语义搜索
+func Search(ctx context.Context, userID, query string, limit int) ([]Match, error) {
+	vectors, err := Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	list, err := candidates(userID)
+	if err != nil {
+		return nil, err
+	}
+	return rank(list, vectors[0], "", limit), nil
+}
@@ -0,0 +1,144 @@
+// Package events 控制面板的实时事件：分享被浏览、插件发布分享、API Token 被使用等事件按用户分发给
+// 已连接的 /api/events 客户端（SSE），控制面板据此实时刷新而无需轮询。
+// 事件只在进程内存中分发，不持久化；客户端断线期间的事件会丢失，重连后应重新加载一次数据。
+package events
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// 事件类型
+const (
+	ShareViewed    = "share_viewed"    // 读者浏览分享
+	SharePublished = "share_published" // 发布或重新发布分享（插件或 API）
+	TokenUsed      = "token_used"      // API Token 认证了一次请求
+)
+
+// 同一分享 / 同一 Token 两次事件的最短间隔，高频浏览或批量调用时合并推送，避免淹没客户端
+const (
+	viewInterval  = time.Second
+	tokenInterval = 10 * time.Second
+)
+
+const (
+	subscriberBuffer = 32 // 每个连接的事件缓冲，客户端读取过慢时丢弃新事件
+	maxPerUser       = 10 // 每个用户同时保持的连接数上限
+)
+
+// ErrTooManyConnections 用户的连接数已达上限
+var ErrTooManyConnections = errors.New("too many event streams")
+
+// Event 推送给控制面板的事件
+type Event struct {
+	Type      string    `json:"type"`
+	Time      time.Time `json:"time"`
+	ShareID   string    `json:"shareId,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	ViewCount int       `json:"viewCount,omitempty"` // share_viewed：分享的浏览次数
+	Reused    bool      `json:"reused,omitempty"`    // share_published：是否为重新发布已有分享
+	TokenID   string    `json:"tokenId,omitempty"`   // share_published 由 API Token 发布时、token_used
+	IP        string    `json:"ip,omitempty"`        // token_used：请求来源
+}
+
+var (
+	mu     sync.Mutex
+	subs   = map[string]map[chan Event]struct{}{} // 用户 ID -> 连接
+	last   = map[string]time.Time{}               // 合并推送的键 -> 上次推送时间
+	closed bool
+)
+
+// Subscribe 订阅用户的事件，返回事件通道与取消函数；服务退出时通道被关闭
+func Subscribe(userID string) (<-chan Event, func(), error) {
+	ch := make(chan Event, subscriberBuffer)
+	mu.Lock()
+	defer mu.Unlock()
+	if closed {
+		close(ch)
+		return ch, func() {}, nil
+	}
+	if len(subs[userID]) >= maxPerUser {
+		return nil, nil, ErrTooManyConnections
+	}
+	if subs[userID] == nil {
+		subs[userID] = map[chan Event]struct{}{}
+	}
+	subs[userID][ch] = struct{}{}
+	return ch, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := subs[userID][ch]; ok {
+			delete(subs[userID], ch)
+			if len(subs[userID]) == 0 {
+				delete(subs, userID)
+			}
+			close(ch)
+		}
+	}, nil
+}
+
+// Close 关闭全部连接，服务退出时调用，使长连接的请求尽快结束
+func Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	closed = true
+	for userID, chans := range subs {
+		for ch := range chans {
+			close(ch)
+		}
+		delete(subs, userID)
+	}
+}
+
+// Publish 向用户的全部连接推送事件，用户没有连接时直接返回；不阻塞调用方
+func Publish(userID string, ev Event) {
+	publish(userID, "", 0, ev)
+}
+
+// publish 推送事件；key 非空时同一 key 在 interval 内只推送一次
+func publish(userID, key string, interval time.Duration, ev Event) {
+	mu.Lock()
+	defer mu.Unlock()
+	chans := subs[userID]
+	if len(chans) == 0 {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if key != "" {
+		if t, ok := last[key]; ok && ev.Time.Sub(t) < interval {
+			return
+		}
+		if len(last) > 10000 {
+			for k, t := range last {
+				if ev.Time.Sub(t) > tokenInterval {
+					delete(last, k)
+				}
+			}
+		}
+		last[key] = ev.Time
+	}
+	for ch := range chans {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Viewed 分享被浏览，同一分享每秒最多推送一次
+func Viewed(userID, shareID, title string, viewCount int) {
+	publish(userID, "view:"+shareID, viewInterval, Event{Type: ShareViewed, ShareID: shareID, Title: title, ViewCount: viewCount})
+}
+
+// Published 分享发布或重新发布
+func Published(userID, shareID, title string, reused bool, tokenID string) {
+	Publish(userID, Event{Type: SharePublished, ShareID: shareID, Title: title, Reused: reused, TokenID: tokenID})
+}
+
+// TokenUse API Token 被使用，同一 Token 每 10 秒最多推送一次
+func TokenUse(userID, tokenID, ip string) {
+	publish(userID, "token:"+tokenID, tokenInterval, Event{Type: TokenUsed, TokenID: tokenID, IP: ip})
+}
@@ -0,0 +1,264 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/citation"
+	"github.com/ZeroHawkeye/siyuan-share-api/drawing"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/safehttp"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+const (
+	maxImages     = 200
+	maxImageBytes = 10 << 20
+)
+
+var imageClient = safehttp.NewClient(30 * time.Second)
+
+// imageExts xelatex 可直接插入的图片格式
+var imageExts = map[string]string{
+	"image/png":       ".png",
+	"image/jpeg":      ".jpg",
+	"application/pdf": ".pdf",
+}
+
+// imageCollector 收集正文引用的图片（分享资源或外部图片），写入文件包的 images/ 目录
+type imageCollector struct {
+	ctx      context.Context
+	shareID  string
+	paths    map[string]string // 原地址 -> 包内路径，空字符串表示无法打包
+	files    []bundleFile
+	warnings []string
+}
+
+type bundleFile struct {
+	name string
+	data []byte
+}
+
+func (ic *imageCollector) resolve(src string) string {
+	if p, ok := ic.paths[src]; ok {
+		return p
+	}
+	p := ""
+	if len(ic.files) < maxImages {
+		data, contentType, err := ic.load(src)
+		if err == nil {
+			if ext, ok := imageExts[contentType]; ok {
+				p = fmt.Sprintf("images/%03d%s", len(ic.files)+1, ext)
+				ic.files = append(ic.files, bundleFile{name: p, data: data})
+			} else {
+				err = fmt.Errorf("unsupported image type %s", contentType)
+			}
+		}
+		if err != nil {
+			ic.warnings = append(ic.warnings, src+": "+err.Error())
+		}
+	} else {
+		ic.warnings = append(ic.warnings, src+": too many images")
+	}
+	ic.paths[src] = p
+	return p
+}
+
+// load 读取图片内容：assets/ 开头的路径从分享资源读取，http(s) 地址下载
+func (ic *imageCollector) load(src string) ([]byte, string, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		return ic.download(src)
+	}
+	p := shareAssetPath(ic.shareID, src)
+	if p == "" {
+		return nil, "", fmt.Errorf("not a share asset")
+	}
+	asset, err := models.FindAsset(ic.shareID, p)
+	if err != nil {
+		return nil, "", err
+	}
+	if asset == nil {
+		return nil, "", storage.ErrNotFound
+	}
+	data, err := readAsset(ic.ctx, asset, maxImageBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	contentType := asset.ContentType
+	if ct := mime.TypeByExtension(path.Ext(p)); contentType == "" && ct != "" {
+		contentType = ct
+	}
+	return data, sniffImageType(data, contentType), nil
+}
+
+// shareAssetPath 将正文中的地址规范化为分享资源路径（assets/...），不是分享资源时返回空字符串
+func shareAssetPath(shareID, src string) string {
+	p := src
+	if i := strings.IndexAny(p, "?#"); i >= 0 {
+		p = p[:i]
+	}
+	if unescaped, err := url.PathUnescape(p); err == nil {
+		p = unescaped
+	}
+	p = strings.TrimPrefix(strings.TrimPrefix(p, "./"), "/")
+	p = strings.TrimPrefix(p, "api/s/"+shareID+"/")
+	if !strings.HasPrefix(p, "assets/") {
+		return ""
+	}
+	return p
+}
+
+// readAsset 读取资源内容，超过 limit 字节时返回错误
+func readAsset(ctx context.Context, asset *models.Asset, limit int64) ([]byte, error) {
+	rc, _, err := storage.Default.Get(ctx, asset.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("file too large")
+	}
+	return data, nil
+}
+
+func (ic *imageCollector) download(src string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ic.ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", safehttp.UserAgent)
+	req.Header.Set("Accept", "image/png,image/jpeg,application/pdf;q=0.9,*/*;q=0.5")
+	resp, err := imageClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, "", fmt.Errorf("%s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(data) > maxImageBytes {
+		return nil, "", fmt.Errorf("image too large")
+	}
+	return data, sniffImageType(data, resp.Header.Get("Content-Type")), nil
+}
+
+// sniffImageType 以文件内容判断图片类型，声明的类型仅作后备
+func sniffImageType(data []byte, declared string) string {
+	ct := http.DetectContentType(data)
+	if ct == "application/octet-stream" {
+		ct = declared
+	}
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+	return ct
+}
+
+// buildLaTeX 生成 LaTeX 文件包（zip）：main.tex、references.bib、images/ 与签名文件 signature/；
+// reproducible 时相同内容生成逐字节一致的文件包，见 writeZip
+func buildLaTeX(ctx context.Context, share *models.Share, author string, reproducible bool) ([]byte, []string, error) {
+	var warnings []string
+	var cited []citation.Item
+	if share.Citations != "" {
+		items, err := citation.Parse([]byte(share.Citations))
+		if err != nil {
+			warnings = append(warnings, "citations: "+err.Error())
+		}
+		cited = citation.Cited(share.Content, items)
+	}
+	known := make(map[string]bool, len(cited))
+	for _, it := range cited {
+		known[it.ID] = true
+	}
+
+	images := &imageCollector{ctx: ctx, shareID: share.ID, paths: map[string]string{}}
+	// 绘图转换为 TikZ，原始场景随文件包一起导出，便于再次编辑
+	drawings := map[string]*drawing.Scene{}
+	rendered := map[string]string{}
+	var sources []bundleFile
+	for _, d := range share.DrawingList() {
+		scene, err := drawing.Parse(d.Scene)
+		if err != nil {
+			warnings = append(warnings, "drawing "+d.ID+": "+err.Error())
+			continue
+		}
+		drawings[d.ID] = scene
+		sources = append(sources, bundleFile{name: "drawings/" + d.ID + "." + d.Format, data: d.Scene})
+	}
+	tex := renderLaTeX(&latexDoc{
+		Title:   share.DocTitle,
+		Author:  author,
+		Date:    docDate(share, reproducible),
+		Content: share.Content,
+		Known:   known,
+		Image:   images.resolve,
+		Drawing: func(id string) string {
+			if tikz, ok := rendered[id]; ok {
+				return tikz
+			}
+			scene, ok := drawings[id]
+			if !ok {
+				warnings = append(warnings, "drawing "+id+": not found")
+				rendered[id] = ""
+				return ""
+			}
+			tikz, warns := drawingTikZ(scene)
+			warnings = append(warnings, warns...)
+			rendered[id] = tikz
+			return tikz
+		},
+	})
+
+	files := []bundleFile{{name: "main.tex", data: []byte(tex)}}
+	if len(cited) > 0 {
+		files = append(files, bundleFile{name: "references.bib", data: []byte(citation.BibTeX(cited))})
+	}
+	files = append(files, images.files...)
+	files = append(files, sources...)
+	if sig, warn := signatureFiles(share); warn != "" {
+		warnings = append(warnings, warn)
+	} else {
+		files = append(files, sig...)
+	}
+	data, err := writeZip(files, reproducible)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, append(warnings, images.warnings...), nil
+}
+
+// signatureFiles 当前版本的签名文件：被签名的正文源文 signature/source.md 与签名信息 signature/SIGNATURE.json，
+// 读者可据此离线校验；正文没有对应的已签名版本时返回警告
+func signatureFiles(share *models.Share) ([]bundleFile, string) {
+	rev := models.CurrentSignedRevision(share)
+	if rev == nil {
+		return nil, "signature: current content has no signed version"
+	}
+	info, err := json.MarshalIndent(struct {
+		*models.RevisionSignature
+		File string `json:"file"`
+	}{rev.SignatureInfo(), "source.md"}, "", "  ")
+	if err != nil {
+		return nil, "signature: " + err.Error()
+	}
+	return []bundleFile{
+		{name: "signature/source.md", data: []byte(share.Content)},
+		{name: "signature/SIGNATURE.json", data: append(info, '\n')},
+	}, ""
+}
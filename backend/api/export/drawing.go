@@ -0,0 +1,246 @@
+package export
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/drawing"
+)
+
+const (
+	// 绘图在版心内的最大尺寸（pt），超出时整体等比缩小
+	maxDrawingWidth  = 430.0
+	maxDrawingHeight = 360.0
+	pxToPt           = 0.75
+)
+
+// namedColors 常见颜色名对应的 RGB，其余颜色名按黑色处理
+var namedColors = map[string][3]int{
+	"black": {0, 0, 0}, "white": {255, 255, 255}, "red": {255, 0, 0}, "green": {0, 128, 0},
+	"blue": {0, 0, 255}, "yellow": {255, 255, 0}, "orange": {255, 165, 0}, "gray": {128, 128, 128},
+	"grey": {128, 128, 128}, "purple": {128, 0, 128},
+}
+
+// tikzWriter 将 Excalidraw 场景转换为 TikZ 图形：y 轴向下，坐标单位为像素经缩放后的 pt
+type tikzWriter struct {
+	b        strings.Builder
+	scale    float64 // 每像素对应的 pt
+	warnings []string
+}
+
+// drawingTikZ 将绘图渲染为 tikzpicture；线条按规整形状绘制，内嵌图片以占位框代替
+func drawingTikZ(scene *drawing.Scene) (string, []string) {
+	_, _, width, height := scene.Bounds()
+	scale := pxToPt
+	if width > 0 && height > 0 {
+		scale = math.Min(pxToPt, math.Min(maxDrawingWidth/width, maxDrawingHeight/height))
+	}
+	w := &tikzWriter{scale: scale}
+	w.b.WriteString(fmt.Sprintf("\\begin{tikzpicture}[x=%spt,y=-%spt]\n", dec(scale), dec(scale)))
+	for i := range scene.Elements {
+		w.element(&scene.Elements[i])
+	}
+	w.b.WriteString("\\end{tikzpicture}")
+	return w.b.String(), w.warnings
+}
+
+func (w *tikzWriter) element(el *drawing.Element) {
+	opts := w.options(el)
+	switch el.Type {
+	case "rectangle":
+		if el.Roundness != nil {
+			r := math.Min(math.Min(math.Abs(el.Width), math.Abs(el.Height))*0.25, 32)
+			opts = append(opts, "rounded corners="+dec(r*w.scale)+"pt")
+		}
+		w.draw(opts, fmt.Sprintf("(%s) rectangle (%s)", pt(el.X, el.Y), pt(el.X+el.Width, el.Y+el.Height)))
+	case "frame", "magicframe", "embeddable", "iframe":
+		w.draw(append(w.transform(el), "draw=black!30", "dashed"),
+			fmt.Sprintf("(%s) rectangle (%s)", pt(el.X, el.Y), pt(el.X+el.Width, el.Y+el.Height)))
+	case "ellipse":
+		cx, cy := el.Center()
+		w.draw(opts, fmt.Sprintf("(%s) ellipse [x radius=%spt, y radius=%spt]",
+			pt(cx, cy), dec(math.Abs(el.Width)/2*w.scale), dec(math.Abs(el.Height)/2*w.scale)))
+	case "diamond":
+		cx, cy := el.Center()
+		w.draw(opts, fmt.Sprintf("(%s) -- (%s) -- (%s) -- (%s) -- cycle",
+			pt(cx, el.Y), pt(el.X+el.Width, cy), pt(cx, el.Y+el.Height), pt(el.X, cy)))
+	case "line", "arrow":
+		pts := el.AbsPoints()
+		if len(pts) < 2 {
+			return
+		}
+		closed := el.Type == "line" && len(pts) > 2 && pts[0] == pts[len(pts)-1]
+		if !closed {
+			opts = removeFill(opts)
+			start, end := el.Arrowheads()
+			if start != "" || end != "" {
+				opts = append(opts, "arrows={"+arrowTip(start)+"-"+arrowTip(end)+"}")
+			}
+		}
+		w.draw(opts, polyline(pts, closed))
+	case "freedraw":
+		pts := simplify(el.AbsPoints(), 1.5)
+		if len(pts) < 2 {
+			return
+		}
+		w.draw(append(removeFill(opts), "line cap=round", "line join=round"), polyline(pts, false))
+	case "text":
+		w.text(el)
+	case "image":
+		w.warnings = append(w.warnings, "drawing image "+el.ID+": embedded images are replaced by a placeholder")
+		w.draw(append(w.transform(el), "draw=black!30", "dashed", "fill=black!5"),
+			fmt.Sprintf("(%s) rectangle (%s)", pt(el.X, el.Y), pt(el.X+el.Width, el.Y+el.Height)))
+	}
+}
+
+func (w *tikzWriter) draw(opts []string, path string) {
+	w.b.WriteString("\\draw[" + strings.Join(opts, ", ") + "] " + path + ";\n")
+}
+
+func (w *tikzWriter) text(el *drawing.Element) {
+	if strings.TrimSpace(el.Text) == "" {
+		return
+	}
+	anchor, align, x := "north west", "left", el.X
+	switch el.TextAlign {
+	case "center":
+		anchor, align, x = "north", "center", el.X+el.Width/2
+	case "right":
+		anchor, align, x = "north east", "right", el.X+el.Width
+	}
+	lines := el.Lines()
+	for i, l := range lines {
+		lines[i] = escapeTeX(l)
+	}
+	size := el.FontSizeOrDefault() * w.scale
+	opts := append(w.transform(el), "anchor="+anchor, "align="+align, "inner sep=0pt",
+		"text="+tikzColor(el.StrokeColor, "black"),
+		fmt.Sprintf(`font=\fontsize{%spt}{%spt}\selectfont`, dec(size), dec(el.LineHeight()*w.scale)))
+	if a := el.Alpha(); a < 1 {
+		opts = append(opts, "opacity="+dec(a))
+	}
+	w.b.WriteString(fmt.Sprintf("\\node[%s] at (%s) {%s};\n", strings.Join(opts, ", "), pt(x, el.Y), strings.Join(lines, `\\ `)))
+}
+
+// options 描边、填充、线型、透明度与旋转
+func (w *tikzWriter) options(el *drawing.Element) []string {
+	opts := []string{"draw=" + tikzColor(el.StrokeColor, "black"), "line width=" + dec(el.StrokeWidthOrDefault()*w.scale) + "pt"}
+	if drawing.Color(el.StrokeColor) == "" {
+		opts[0] = "draw=none"
+	}
+	if dash := el.DashArray(); dash != nil {
+		opts = append(opts, fmt.Sprintf("dash pattern=on %spt off %spt", dec(dash[0]*w.scale), dec(dash[1]*w.scale)))
+	}
+	if drawing.Color(el.BackgroundColor) != "" {
+		opts = append(opts, "fill="+tikzColor(el.BackgroundColor, "black"))
+		// 斜线填充以半透明的纯色近似
+		if el.FillStyle != "" && el.FillStyle != "solid" {
+			opts = append(opts, "fill opacity=0.35")
+		}
+	}
+	if a := el.Alpha(); a < 1 {
+		opts = append(opts, "opacity="+dec(a))
+	}
+	return append(opts, w.transform(el)...)
+}
+
+func (w *tikzWriter) transform(el *drawing.Element) []string {
+	if el.Angle == 0 {
+		return nil
+	}
+	cx, cy := el.Center()
+	// y 轴向下，Excalidraw 的顺时针角度在 TikZ 中为负
+	return []string{fmt.Sprintf("rotate around={%s:(%s)}", dec(-el.Angle*180/math.Pi), pt(cx, cy))}
+}
+
+func removeFill(opts []string) []string {
+	out := opts[:0:0]
+	for _, o := range opts {
+		if !strings.HasPrefix(o, "fill") {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// arrowTip Excalidraw 箭头样式对应的 arrows.meta 箭头
+func arrowTip(style string) string {
+	switch style {
+	case "":
+		return ""
+	case "triangle":
+		return "Stealth"
+	case "triangle_outline":
+		return "Stealth[open]"
+	case "dot", "circle":
+		return "Circle"
+	case "circle_outline":
+		return "Circle[open]"
+	case "bar":
+		return "Bar"
+	default:
+		return "To"
+	}
+}
+
+func polyline(pts [][2]float64, closed bool) string {
+	parts := make([]string, len(pts))
+	for i, p := range pts {
+		parts[i] = "(" + pt(p[0], p[1]) + ")"
+	}
+	if closed {
+		parts[len(parts)-1] = "cycle"
+	}
+	return strings.Join(parts, " -- ")
+}
+
+// simplify 丢弃与上一个保留点距离过近的点，减小手绘线条的代码量
+func simplify(pts [][2]float64, minDist float64) [][2]float64 {
+	if len(pts) < 3 {
+		return pts
+	}
+	out := [][2]float64{pts[0]}
+	for _, p := range pts[1 : len(pts)-1] {
+		last := out[len(out)-1]
+		if math.Hypot(p[0]-last[0], p[1]-last[1]) >= minDist {
+			out = append(out, p)
+		}
+	}
+	return append(out, pts[len(pts)-1])
+}
+
+// tikzColor 转换为 xcolor 表达式，无法识别的颜色使用 fallback
+func tikzColor(c, fallback string) string {
+	c = drawing.Color(c)
+	if rgb, ok := namedColors[strings.ToLower(c)]; ok {
+		return fmt.Sprintf("{rgb,255:red,%d;green,%d;blue,%d}", rgb[0], rgb[1], rgb[2])
+	}
+	if !strings.HasPrefix(c, "#") {
+		return fallback
+	}
+	hex := c[1:]
+	if len(hex) == 3 || len(hex) == 4 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) < 6 {
+		return fallback
+	}
+	v, err := strconv.ParseUint(hex[:6], 16, 32)
+	if err != nil {
+		return fallback
+	}
+	return fmt.Sprintf("{rgb,255:red,%d;green,%d;blue,%d}", v>>16, v>>8&0xff, v&0xff)
+}
+
+func pt(x, y float64) string {
+	return dec(x) + "," + dec(y)
+}
+
+func dec(f float64) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "0"
+	}
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,50 @@
+package export
+
+// embedStyle 嵌入页在独立页面样式之上的调整：去掉外边距，页脚更紧凑
+const embedStyle = `body{margin:0 auto;padding:16px 20px;background:transparent}
+footer{margin-top:1.5em;font-size:13px}
+`
+
+// embedScript 嵌入页脚本：站外链接在新窗口打开（页内锚点除外），并通过 postMessage 向父页面报告内容高度
+// （{type: "siyuan-share:resize", height}），便于宿主页面自适应 iframe 高度
+const embedScript = `(function(){
+document.querySelectorAll('a[href]').forEach(function(a){if(a.getAttribute('href').charAt(0)!=='#'){a.target='_blank';a.rel='noopener'}});
+function report(){if(parent!==window)parent.postMessage({type:'siyuan-share:resize',height:document.documentElement.scrollHeight},'*')}
+addEventListener('load',report);
+if(window.ResizeObserver)new ResizeObserver(report).observe(document.body);
+})();`
+
+// EmbedDoc 嵌入页的文档
+type EmbedDoc struct {
+	Title     string
+	Author    string
+	Date      string
+	Lang      string
+	Content   string // 已完成块引用、绘图与文献引用处理的 Markdown 正文
+	HideTitle bool
+	// FullURL 完整阅读页地址与说明，显示在页脚
+	FullURL   string
+	FullLabel string
+	// Link 改写链接与图片地址（如将相对的 assets/ 路径指向资源接口）
+	Link func(dest string) string
+	// Prerender 服务端预渲染公式、Mermaid 图与代码高亮，为 nil 时不预渲染
+	Prerender Prerenderer
+}
+
+// EmbedPage 生成嵌入其他网站（iframe）的阅读页：与独立 HTML 文件包使用同一渲染流程，样式内联、不含站点导航，
+// 公式通过 KaTeX 渲染
+func EmbedPage(doc EmbedDoc) string {
+	return renderHTML(&htmlDoc{
+		Title:       doc.Title,
+		Author:      doc.Author,
+		Date:        doc.Date,
+		Source:      doc.FullURL,
+		SourceLabel: doc.FullLabel,
+		Content:     doc.Content,
+		Lang:        doc.Lang,
+		Embed:       true,
+		HideTitle:   doc.HideTitle,
+		Link:        doc.Link,
+		Prerender:   doc.Prerender,
+	})
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Heading 正文中的标题：ID 为锚点（#id），按标题文字生成，同名标题依次加 -1、-2 后缀
+type Heading struct {
+	ID    string `json:"id"`
+	Text  string `json:"text"`
+	Level int    `json:"level"`
+}
+
+// slugger 按出现顺序为标题生成不重复的锚点，规则与 GitHub（github-slugger）一致：转小写，
+// 去掉标点与符号，空格替换为连字符；文字相同的标题只要顺序不变，锚点在重新发布后保持不变
+type slugger struct {
+	seen map[string]int
+}
+
+func (s *slugger) slug(text string) string {
+	if s.seen == nil {
+		s.seen = map[string]int{}
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) || r == '_' || r == '-':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteByte('-')
+		}
+	}
+	base := b.String()
+	if base == "" {
+		base = "section"
+	}
+	slug := base
+	for {
+		if _, ok := s.seen[slug]; !ok {
+			break
+		}
+		s.seen[base]++
+		slug = base + "-" + strconv.Itoa(s.seen[base])
+	}
+	s.seen[slug] = 0
+	return slug
+}
+
+// Headings 提取 Markdown 正文中的标题（不含代码块中的行），锚点与独立 HTML 页面、嵌入页中的标题 id 相同
+func Headings(content string) []Heading {
+	md := parseMarkdown(content)
+	w := &htmlWriter{doc: &htmlDoc{Content: content}, out: &strings.Builder{}, notes: md.Footnotes, headings: []Heading{}}
+	w.blocks(md.Blocks)
+	return w.headings
+}
+
+// headingText 标题的纯文本（去掉行内标记）
+func (w *htmlWriter) headingText(nodes []mdInline) string {
+	return strings.TrimSpace(html.UnescapeString(stripTags(w.inlines(nodes))))
+}
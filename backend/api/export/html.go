@@ -0,0 +1,404 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+)
+
+// htmlDoc 转换为独立 HTML 页面的文档
+type htmlDoc struct {
+	Title   string
+	Author  string
+	Date    string
+	Source  string // 原分享链接，显示在页脚
+	Content string
+	Lang    string
+	// SourceLabel 页脚链接前的说明（含标点），为空时为“原文：”
+	SourceLabel string
+	// Lite 轻量页面：不加载任何脚本（公式显示 TeX 源码），图片延迟加载
+	Lite bool
+	// Embed 嵌入其他网站的页面：紧凑边距，图片延迟加载，链接在新窗口打开，并向父页面报告内容高度
+	Embed bool
+	// HideTitle 不显示标题与作者行（嵌入页可选）
+	HideTitle bool
+	// Link 将链接与图片地址改写为文件包内的路径
+	Link func(dest string) string
+	// Home 返回站点首页的链接（静态站点导出），为空时不显示
+	Home string
+	// Prerender 服务端预渲染公式、Mermaid 图与代码高亮，为 nil 时公式交给 KaTeX 脚本
+	Prerender Prerenderer
+}
+
+type htmlWriter struct {
+	doc       *htmlDoc
+	out       *strings.Builder
+	notes     map[string][]mdInline
+	noteOrder []string // 按首次引用排列的脚注
+	skipTitle bool
+	math      bool
+	katexCSS  bool // 含预渲染的公式，需要 KaTeX 样式（输出为 MathML 时不影响显示）
+	slugs     slugger
+	headings  []Heading
+}
+
+// htmlStyle 独立页面的内联样式
+const htmlStyle = `body{max-width:820px;margin:40px auto;padding:0 20px;font:16px/1.75 -apple-system,BlinkMacSystemFont,"Segoe UI","PingFang SC","Microsoft YaHei",sans-serif;color:#1f1f1f}
+h1,h2,h3,h4,h5,h6{line-height:1.35;margin:1.6em 0 .6em}
+header h1{margin-top:0}
+.meta,footer{color:#8c8c8c;font-size:14px}
+footer{margin-top:3em;border-top:1px solid #f0f0f0;padding-top:1em}
+pre{background:#f6f8fa;padding:12px 16px;overflow:auto;border-radius:6px;font-size:14px;line-height:1.5}
+code{font-family:SFMono-Regular,Consolas,Menlo,monospace;background:#f6f8fa;padding:.1em .3em;border-radius:3px}
+pre code{background:none;padding:0}
+pre.output{border-left:3px solid #d9d9d9;background:#fafafa}
+blockquote{margin:1em 0;padding:.2em 1em;border-left:4px solid #d9d9d9;color:#595959}
+table{border-collapse:collapse;margin:1em 0;display:block;overflow:auto}
+th,td{border:1px solid #e8e8e8;padding:6px 12px}
+th{background:#fafafa}
+img{max-width:100%}
+figure{margin:1.5em 0;text-align:center}
+figcaption{color:#8c8c8c;font-size:14px}
+mark{background:#fff3b0}
+ul.task{list-style:none;padding-left:1.2em}
+.math-block{overflow:auto}
+.footnotes{font-size:14px;color:#595959}
+`
+
+// renderHTML 将 Markdown 正文转换为完整的 HTML 页面；含公式时通过 KaTeX 渲染（需联网，离线时显示 TeX 源码）
+func renderHTML(doc *htmlDoc) string {
+	w := &htmlWriter{doc: doc, out: &strings.Builder{}}
+	md := parseMarkdown(doc.Content)
+	w.notes = md.Footnotes
+	// 正文以与文档标题相同的一级标题开头时不重复输出
+	if len(md.Blocks) > 0 {
+		first := md.Blocks[0]
+		w.skipTitle = first.Kind == blockHeading && first.Level == 1 && first.Text == strings.TrimSpace(doc.Title)
+	}
+	w.blocks(md.Blocks)
+
+	var b strings.Builder
+	b.WriteString("<!doctype html>\n")
+	if doc.Lang != "" {
+		b.WriteString("<html lang=\"" + html.EscapeString(doc.Lang) + "\">\n")
+	} else {
+		b.WriteString("<html>\n")
+	}
+	b.WriteString("<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	b.WriteString("<title>" + html.EscapeString(doc.Title) + "</title>\n")
+	if doc.Author != "" {
+		b.WriteString("<meta name=\"author\" content=\"" + html.EscapeString(doc.Author) + "\">\n")
+	}
+	b.WriteString("<style>\n" + htmlStyle)
+	if doc.Embed {
+		b.WriteString(embedStyle)
+	}
+	b.WriteString("</style>\n")
+	if (w.math || w.katexCSS) && !doc.Lite {
+		b.WriteString(`<link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/katex@0.16/dist/katex.min.css">` + "\n")
+	}
+	if w.math && !doc.Lite {
+		b.WriteString(`<script defer src="https://cdn.jsdelivr.net/npm/katex@0.16/dist/katex.min.js"></script>` + "\n")
+		b.WriteString(`<script defer src="https://cdn.jsdelivr.net/npm/katex@0.16/dist/contrib/auto-render.min.js" onload="renderMathInElement(document.body)"></script>` + "\n")
+	}
+	b.WriteString("</head>\n<body>\n")
+	if doc.Home != "" {
+		b.WriteString(`<nav class="meta"><a href="` + html.EscapeString(doc.Home) + `">← 全部文档</a></nav>` + "\n")
+	}
+	if !doc.HideTitle {
+		b.WriteString("<header>\n<h1>" + html.EscapeString(doc.Title) + "</h1>\n")
+		meta := doc.Date
+		if doc.Author != "" {
+			meta = html.EscapeString(doc.Author) + " · " + meta
+		}
+		b.WriteString("<p class=\"meta\">" + meta + "</p>\n</header>\n")
+	}
+	b.WriteString("<main>\n")
+	b.WriteString(strings.TrimSpace(w.out.String()))
+	b.WriteString("\n</main>\n")
+	if len(w.noteOrder) > 0 {
+		b.WriteString("<section class=\"footnotes\">\n<hr>\n<ol>\n")
+		for i, id := range w.noteOrder {
+			n := strconv.Itoa(i + 1)
+			b.WriteString(`<li id="fn-` + n + `">` + w.inlines(w.notes[id]) + ` <a href="#fnref-` + n + `">↩</a></li>` + "\n")
+		}
+		b.WriteString("</ol>\n</section>\n")
+	}
+	if doc.Source != "" {
+		src := html.EscapeString(doc.Source)
+		label := "原文："
+		if doc.SourceLabel != "" {
+			label = html.EscapeString(doc.SourceLabel)
+		}
+		b.WriteString(`<footer>` + label + `<a href="` + src + `">` + src + "</a></footer>\n")
+	}
+	if doc.Embed {
+		b.WriteString("<script>" + embedScript + "</script>\n")
+	}
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+func (w *htmlWriter) line(s string) {
+	w.out.WriteString(s)
+	w.out.WriteString("\n")
+}
+
+// link 改写地址，并拒绝 javascript: 等可执行脚本的协议
+func (w *htmlWriter) link(dest string) string {
+	if w.doc.Link != nil {
+		dest = w.doc.Link(dest)
+	}
+	lower := strings.ToLower(strings.TrimSpace(dest))
+	if i := strings.IndexAny(lower, ":/?#"); i > 0 && lower[i] == ':' {
+		switch lower[:i] {
+		case "http", "https", "mailto", "tel", "siyuan":
+		default:
+			return "#"
+		}
+	}
+	return html.EscapeString(dest)
+}
+
+// img 图片标签，轻量页面与嵌入页中延迟加载
+func (w *htmlWriter) img(src, alt string) string {
+	lazy := ""
+	if w.doc.Lite || w.doc.Embed {
+		lazy = ` loading="lazy"`
+	}
+	return `<img src="` + w.link(src) + `" alt="` + html.EscapeString(alt) + `"` + lazy + `>`
+}
+
+// blocks 按块输出
+func (w *htmlWriter) blocks(blocks []*mdBlock) {
+	for _, b := range blocks {
+		switch b.Kind {
+		case blockParagraph:
+			w.line("<p>" + w.inlines(b.Inlines) + "</p>")
+		case blockFigure:
+			w.line("<figure>")
+			w.line(w.img(b.Src, b.Alt))
+			if b.Alt != "" && b.Alt != "image" && !strings.Contains(b.Src, b.Alt) {
+				w.line("<figcaption>" + w.inlines(b.Caption) + "</figcaption>")
+			}
+			w.line("</figure>")
+		case blockHeading:
+			w.heading(b)
+		case blockCode:
+			w.codeBlock(b)
+		case blockMath:
+			w.displayMath(b.Text)
+		case blockRule:
+			w.line("<hr>")
+		case blockQuote:
+			w.line("<blockquote>")
+			if b.Caption != nil {
+				w.line("<p><strong>" + w.inlines(b.Caption) + "</strong></p>")
+			}
+			w.blocks(b.Children)
+			w.line("</blockquote>")
+		case blockTable:
+			w.table(b)
+		case blockList:
+			w.list(b)
+		}
+	}
+}
+
+func (w *htmlWriter) heading(b *mdBlock) {
+	// 与阅读页一致，与文档标题相同的首个一级标题同样占用锚点
+	plain := w.headingText(b.Inlines)
+	id := w.slugs.slug(plain)
+	if w.skipTitle && b.Level == 1 && b.Text == strings.TrimSpace(w.doc.Title) {
+		w.skipTitle = false
+		return
+	}
+	if w.headings != nil {
+		w.headings = append(w.headings, Heading{ID: id, Text: plain, Level: b.Level})
+	}
+	level := strconv.Itoa(b.Level)
+	w.line("<h" + level + ` id="` + html.EscapeString(id) + `">` + w.inlines(b.Inlines) + "</h" + level + ">")
+}
+
+// codeBlock 输出代码块，math / latex 代码块按公式处理，output 代码块（运行结果）使用单独的样式
+func (w *htmlWriter) codeBlock(b *mdBlock) {
+	lang := b.Lang
+	switch {
+	case lang == "math" || lang == "latex" || lang == "katex":
+		w.displayMath(strings.TrimSpace(b.Text))
+	case lang == "mermaid" && w.prerender(renderer.PrerenderMermaid, "", b.Text, `<figure class="mermaid">`, "</figure>"):
+	case highlightable(lang) && w.prerender(renderer.PrerenderHighlight, lang, b.Text,
+		`<pre><code class="language-`+html.EscapeString(lang)+`">`, "</code></pre>"):
+	case lang == "output":
+		w.line(`<pre class="output"><code>` + html.EscapeString(b.Text) + "</code></pre>")
+	case lang != "":
+		w.line(`<pre><code class="language-` + html.EscapeString(lang) + `">` + html.EscapeString(b.Text) + "</code></pre>")
+	default:
+		w.line("<pre><code>" + html.EscapeString(b.Text) + "</code></pre>")
+	}
+}
+
+func (w *htmlWriter) displayMath(tex string) {
+	if tex == "" {
+		return
+	}
+	if w.prerender(renderer.PrerenderMathDisplay, "", tex, `<div class="math-block">`, "</div>") {
+		w.katexCSS = true
+		return
+	}
+	w.math = true
+	w.line(`<div class="math-block">\[` + html.EscapeString(tex) + `\]</div>`)
+}
+
+// prerender 输出预渲染结果（以 open、close 包裹），未配置或失败时返回 false
+func (w *htmlWriter) prerender(kind, lang, source, open, close string) bool {
+	if w.doc.Prerender == nil {
+		return false
+	}
+	out, ok := w.doc.Prerender(kind, lang, source)
+	if ok {
+		w.line(open + out + close)
+	}
+	return ok
+}
+
+// prerenderMath 预渲染行内公式，未配置或失败时返回 false
+func (w *htmlWriter) prerenderMath(b *strings.Builder, kind, tex string) bool {
+	if w.doc.Prerender == nil {
+		return false
+	}
+	out, ok := w.doc.Prerender(kind, "", tex)
+	if ok {
+		w.katexCSS = true
+		b.WriteString(out)
+	}
+	return ok
+}
+
+// tableAligns 表格列对齐对应的 text-align
+var tableAligns = map[byte]string{'c': "center", 'r': "right"}
+
+// table 输出表格，按分隔行设置列对齐
+func (w *htmlWriter) table(b *mdBlock) {
+	cols := b.columns()
+	row := func(cells [][]mdInline, tag string) string {
+		var r strings.Builder
+		r.WriteString("<tr>")
+		for j := 0; j < cols; j++ {
+			r.WriteString("<" + tag)
+			if j < len(b.Aligns) && tableAligns[b.Aligns[j]] != "" {
+				r.WriteString(` style="text-align:` + tableAligns[b.Aligns[j]] + `"`)
+			}
+			r.WriteString(">")
+			if j < len(cells) {
+				r.WriteString(w.inlines(cells[j]))
+			}
+			r.WriteString("</" + tag + ">")
+		}
+		r.WriteString("</tr>")
+		return r.String()
+	}
+
+	w.line("<table>")
+	w.line("<thead>" + row(b.Rows[0], "th") + "</thead>")
+	w.line("<tbody>")
+	for _, r := range b.Rows[1:] {
+		w.line(row(r, "td"))
+	}
+	w.line("</tbody>")
+	w.line("</table>")
+}
+
+// list 输出（嵌套）列表，只有一个段落的列表项不包裹 <p>
+func (w *htmlWriter) list(b *mdBlock) {
+	tag, attrs := "ul", ""
+	if b.Ordered {
+		tag = "ol"
+		if b.Start > 1 {
+			attrs = fmt.Sprintf(` start="%d"`, b.Start)
+		}
+	}
+	for _, item := range b.Items {
+		if item.Task != 0 {
+			attrs += ` class="task"`
+			break
+		}
+	}
+	w.line("<" + tag + attrs + ">")
+	for _, item := range b.Items {
+		out := w.out
+		w.out = &strings.Builder{}
+		w.blocks(item.Children)
+		body := strings.TrimSpace(w.out.String())
+		w.out = out
+		// 只有一个段落的列表项不包裹 <p>，保持紧凑
+		if strings.HasPrefix(body, "<p>") && strings.Count(body, "<p>") == 1 && strings.HasSuffix(body, "</p>") {
+			body = body[3 : len(body)-4]
+		}
+		switch item.Task {
+		case 1:
+			body = `<input type="checkbox" disabled> ` + body
+		case 2:
+			body = `<input type="checkbox" disabled checked> ` + body
+		}
+		w.line("<li>" + body + "</li>")
+	}
+	w.line("</" + tag + ">")
+}
+
+// inlines 输出行内元素，文字转义
+func (w *htmlWriter) inlines(nodes []mdInline) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Kind {
+		case inlineText:
+			b.WriteString(html.EscapeString(n.Text))
+		case inlineCode:
+			b.WriteString("<code>" + html.EscapeString(n.Text) + "</code>")
+		case inlineMath:
+			if n.Display {
+				if !w.prerenderMath(&b, renderer.PrerenderMathDisplay, n.Text) {
+					w.math = true
+					b.WriteString(`\[` + html.EscapeString(n.Text) + `\]`)
+				}
+			} else if !w.prerenderMath(&b, renderer.PrerenderMath, n.Text) {
+				w.math = true
+				b.WriteString(`\(` + html.EscapeString(n.Text) + `\)`)
+			}
+		case inlineImage:
+			b.WriteString(w.img(n.Dest, n.Text))
+		case inlineFootnote:
+			num := 0
+			for k, seen := range w.noteOrder {
+				if seen == n.Text {
+					num = k + 1
+				}
+			}
+			if num == 0 {
+				w.noteOrder = append(w.noteOrder, n.Text)
+				num = len(w.noteOrder)
+			}
+			ref := strconv.Itoa(num)
+			b.WriteString(`<sup><a href="#fn-` + ref + `" id="fnref-` + ref + `">` + ref + `</a></sup>`)
+		case inlineLink:
+			if n.Dest == "" {
+				b.WriteString(w.inlines(n.Children))
+			} else {
+				b.WriteString(`<a href="` + w.link(n.Dest) + `">` + w.inlines(n.Children) + "</a>")
+			}
+		case inlineAutoLink:
+			b.WriteString(`<a href="` + w.link(n.Dest) + `">` + html.EscapeString(n.Dest) + "</a>")
+		case inlineEmphasis:
+			b.WriteString("<" + n.Style + ">" + w.inlines(n.Children) + "</" + n.Style + ">")
+		case inlineBreak:
+			b.WriteString("<br>")
+		case inlineSoftBreak:
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,174 @@
+// Package export 将分享导出为可下载的文件包（目前支持可编译的 LaTeX 工程），
+// 导出在后台任务中进行，结果保存到 storage，供分享所有者下载。
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// FormatLaTeX LaTeX 文件包
+const FormatLaTeX = "latex"
+
+// Formats 支持的导出格式
+var Formats = map[string]bool{FormatLaTeX: true}
+
+// keepPerShare 每个分享保留的导出记录数，更早的记录与文件自动清理
+const keepPerShare = 5
+
+// ErrQueueFull 导出队列已满
+var ErrQueueFull = errors.New("export queue is full")
+
+var queue = make(chan string, 64)
+
+// Start 启动导出后台任务，并重新排队上次退出时未完成的任务
+func Start() {
+	go func() {
+		for id := range queue {
+			run(id)
+		}
+	}()
+
+	var unfinished []models.ExportJob
+	models.DB.Where("status IN ?", []string{models.ExportPending, models.ExportRunning}).Order("created_at").Find(&unfinished)
+	if len(unfinished) == 0 {
+		return
+	}
+	models.DB.Model(&models.ExportJob{}).Where("status = ?", models.ExportRunning).Update("status", models.ExportPending)
+	go func() {
+		for _, job := range unfinished {
+			queue <- job.ID
+		}
+	}()
+}
+
+// Enqueue 为分享创建导出任务；同一格式（及是否可复现）已有排队或进行中的任务时直接返回该任务
+func Enqueue(share *models.Share, format string, reproducible bool) (*models.ExportJob, error) {
+	var job models.ExportJob
+	err := models.DB.Where("share_id = ? AND format = ? AND reproducible = ? AND status IN ?", share.ID, format, reproducible,
+		[]string{models.ExportPending, models.ExportRunning}).First(&job).Error
+	if err == nil {
+		return &job, nil
+	}
+
+	job = models.ExportJob{
+		ID:           "exp_" + randHex(12),
+		ShareID:      share.ID,
+		UserID:       share.UserID,
+		Format:       format,
+		Reproducible: reproducible,
+		Status:       models.ExportPending,
+	}
+	if err := models.DB.Create(&job).Error; err != nil {
+		return nil, err
+	}
+	select {
+	case queue <- job.ID:
+		return &job, nil
+	default:
+		models.DB.Model(&job).Updates(map[string]interface{}{"status": models.ExportFailed, "error": ErrQueueFull.Error()})
+		return nil, ErrQueueFull
+	}
+}
+
+// run 执行导出任务并写回结果；服务退出时不再开始新任务，剩余任务在下次启动时继续
+func run(id string) {
+	if !background.Add() {
+		return
+	}
+	defer background.Done()
+
+	var job models.ExportJob
+	if err := models.DB.Where("id = ? AND status = ?", id, models.ExportPending).First(&job).Error; err != nil {
+		return
+	}
+	models.DB.Model(&job).Update("status", models.ExportRunning)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	data, fileName, warnings, err := build(ctx, &job)
+	var key string
+	if err == nil {
+		key = "exports/" + job.ShareID + "/" + job.ID + ".zip"
+		err = storage.Default.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/zip")
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"finished_at": &now}
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		updates["status"] = models.ExportFailed
+		updates["error"] = msg
+		log.Printf("export %s of share %s failed: %v", job.Format, job.ShareID, err)
+	} else {
+		updates["status"] = models.ExportDone
+		updates["storage_key"] = key
+		updates["size"] = len(data)
+		updates["file_name"] = fileName
+		updates["warnings"] = strings.Join(warnings, "\n")
+	}
+	if err := models.DB.Model(&models.ExportJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+		log.Printf("export job save failed (%s): %v", job.ID, err)
+	}
+	prune(job.ShareID)
+}
+
+func build(ctx context.Context, job *models.ExportJob) ([]byte, string, []string, error) {
+	if storage.Default == nil {
+		return nil, "", nil, errors.New("storage not initialized")
+	}
+	var share models.Share
+	if err := models.DB.Where("id = ?", job.ShareID).First(&share).Error; err != nil {
+		return nil, "", nil, errors.New("share not found")
+	}
+	var author string
+	var user models.User
+	if models.DB.Select("username").Where("id = ?", share.UserID).First(&user).Error == nil {
+		author = user.Username
+	}
+
+	switch job.Format {
+	case FormatLaTeX:
+		data, warnings, err := buildLaTeX(ctx, &share, author, job.Reproducible)
+		return data, slug.FileName(share.DocTitle, share.ID) + ".zip", warnings, err
+	default:
+		return nil, "", nil, errors.New("unsupported export format: " + job.Format)
+	}
+}
+
+// prune 仅保留分享最近的若干条导出记录
+func prune(shareID string) {
+	var old []models.ExportJob
+	models.DB.Where("share_id = ? AND status IN ?", shareID, []string{models.ExportDone, models.ExportFailed}).
+		Order("created_at DESC").Offset(keepPerShare).Find(&old)
+	for _, job := range old {
+		if job.StorageKey != "" {
+			if err := storage.Default.Delete(context.Background(), job.StorageKey); err != nil {
+				log.Printf("export cleanup failed (%s): %v", job.StorageKey, err)
+				continue
+			}
+		}
+		models.DB.Delete(&job)
+	}
+}
+
+func randHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,499 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/citation"
+)
+
+// latexDoc 转换为 LaTeX 的文档
+type latexDoc struct {
+	Title   string
+	Author  string
+	Date    string
+	Content string
+	// Known 参考文献中存在的 citekey，仅这些引用转换为 \citep
+	Known map[string]bool
+	// Image 将图片地址解析为文件包内的路径，无法打包时返回空字符串
+	Image func(src string) string
+	// Drawing 将 drawing:ID 引用的绘图渲染为 tikzpicture，不存在时返回空字符串
+	Drawing func(id string) string
+}
+
+var (
+	placeholder = regexp.MustCompile("\uE000(\\d+)\uE001")
+	// 自带编号环境的公式块不能再包裹在 \[ \] 中
+	mathEnvPattern = regexp.MustCompile(`^\\begin\{(?:align|alignat|gather|multline|flalign|equation|eqnarray)\*?\}`)
+)
+
+var texEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`, "{", `\{`, "}", `\}`, "$", `\$`, "&", `\&`, "#", `\#`, "_", `\_`, "%", `\%`,
+	"^", `\textasciicircum{}`, "~", `\textasciitilde{}`, "<", `\textless{}`, ">", `\textgreater{}`, "|", `\textbar{}`,
+)
+
+// escapeTeX 转义 LaTeX 特殊字符
+func escapeTeX(s string) string {
+	return texEscaper.Replace(s)
+}
+
+// escapeURL 转义 \href 链接地址中的特殊字符
+func escapeURL(u string) string {
+	return strings.NewReplacer(`\`, "%5C", "{", "%7B", "}", "%7D", "^", "%5E", "%", `\%`, "#", `\#`).Replace(u)
+}
+
+type latexWriter struct {
+	doc          *latexDoc
+	out          strings.Builder
+	fragments    []string // 引用等预先生成的 LaTeX 片段，以占位符嵌入正文
+	notes        map[string][]mdInline
+	inNote       map[string]bool // 正在输出的脚注，脚注内容引用自身时不再展开
+	topLevel     int             // 正文中最高的标题级别，映射为 \section
+	listDepth    int
+	enumDepth    int
+	skipTitle    bool
+	packages     map[string]bool
+	hasCitations bool
+}
+
+// renderLaTeX 将 Markdown 正文转换为完整的 LaTeX 文档（xelatex 编译）
+func renderLaTeX(doc *latexDoc) string {
+	w := &latexWriter{doc: doc, inNote: map[string]bool{}, topLevel: 7, packages: map[string]bool{}}
+
+	content := strings.ReplaceAll(doc.Content, "\r\n", "\n")
+	md := parseMarkdown(citation.ReplaceClusters(content, w.citeCommand))
+	w.notes = md.Footnotes
+	w.scanHeadings(md.Blocks)
+
+	w.blocks(md.Blocks)
+	body := placeholder.ReplaceAllStringFunc(w.out.String(), func(m string) string {
+		n, _ := strconv.Atoi(placeholder.FindStringSubmatch(m)[1])
+		return w.fragments[n]
+	})
+
+	var b strings.Builder
+	b.WriteString("% !TEX program = xelatex\n")
+	b.WriteString("% 由 SiYuan Share 导出，编译：latexmk -xelatex main.tex（或 xelatex → bibtex → xelatex → xelatex）\n")
+	if hasCJK(doc.Title + doc.Content) {
+		b.WriteString("\\documentclass[UTF8,a4paper]{ctexart}\n")
+	} else {
+		b.WriteString("\\documentclass[a4paper]{article}\n\\usepackage{fontspec}\n")
+	}
+	b.WriteString("\\usepackage[margin=2.5cm]{geometry}\n")
+	b.WriteString("\\usepackage{amsmath,amssymb,bm}\n")
+	b.WriteString("\\usepackage{graphicx}\n")
+	b.WriteString("\\usepackage{longtable,booktabs,array}\n")
+	b.WriteString("\\usepackage{listings}\n")
+	b.WriteString("\\usepackage[normalem]{ulem}\n")
+	b.WriteString("\\usepackage{xcolor}\n")
+	// 正文中用到的数学扩展宏包
+	for _, pkg := range []string{"mhchem", "cancel"} {
+		if w.packages[pkg] {
+			b.WriteString("\\usepackage{" + pkg + "}\n")
+		}
+	}
+	if w.packages["tikz"] {
+		b.WriteString("\\usepackage{tikz}\n\\usetikzlibrary{arrows.meta}\n")
+	}
+	if w.hasCitations {
+		b.WriteString("\\usepackage[round]{natbib}\n")
+	}
+	b.WriteString("\\usepackage[hidelinks]{hyperref}\n")
+	b.WriteString("\\lstset{basicstyle=\\ttfamily\\small,breaklines=true,columns=fullflexible,keepspaces=true,frame=single}\n")
+	b.WriteString("\\lstdefinestyle{output}{frame=leftline,framerule=1.5pt,rulecolor=\\color{black!30},backgroundcolor=\\color{black!3}}\n\n")
+
+	b.WriteString("\\title{" + escapeTeX(doc.Title) + "}\n")
+	b.WriteString("\\author{" + escapeTeX(doc.Author) + "}\n")
+	b.WriteString("\\date{" + escapeTeX(doc.Date) + "}\n\n")
+	b.WriteString("\\begin{document}\n\\maketitle\n\n")
+	b.WriteString(strings.TrimSpace(body))
+	b.WriteString("\n")
+	if w.hasCitations {
+		b.WriteString("\n\\bibliographystyle{plainnat}\n\\bibliography{references}\n")
+	}
+	b.WriteString("\n\\end{document}\n")
+	return b.String()
+}
+
+func hasCJK(s string) bool {
+	for _, r := range s {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return true
+		}
+	}
+	return false
+}
+
+// fragment 登记一段 LaTeX 片段并返回占位符，占位符不含需要转义的字符
+func (w *latexWriter) fragment(tex string) string {
+	w.fragments = append(w.fragments, tex)
+	return "\uE000" + strconv.Itoa(len(w.fragments)-1) + "\uE001"
+}
+
+// citeCommand 将引用簇转换为 natbib 命令，包含未知 citekey 时保留原文
+func (w *latexWriter) citeCommand(cites []citation.Cite) (string, bool) {
+	keys := make([]string, len(cites))
+	for i, c := range cites {
+		if !w.doc.Known[c.Key] {
+			return "", false
+		}
+		keys[i] = citation.TeXKey(c.Key)
+	}
+	w.hasCitations = true
+	cmd := `\citep`
+	if len(cites) == 1 && cites[0].SuppressAuthor {
+		cmd = `\citeyearpar`
+	}
+	// natbib 只支持整个引用簇的前缀与定位信息
+	first, last := cites[0], cites[len(cites)-1]
+	switch {
+	case first.Prefix != "":
+		cmd += "[" + escapeTeX(first.Prefix) + "][" + escapeTeX(last.Locator) + "]"
+	case last.Locator != "":
+		cmd += "[" + escapeTeX(last.Locator) + "]"
+	}
+	return w.fragment(cmd + "{" + strings.Join(keys, ",") + "}"), true
+}
+
+// scanHeadings 确定标题级别映射；首个标题是与文档标题相同的一级标题时不重复输出
+func (w *latexWriter) scanHeadings(blocks []*mdBlock) {
+	first := true
+	for _, b := range blocks {
+		if b.Kind != blockHeading {
+			continue
+		}
+		if first && b.Level == 1 && b.Text == strings.TrimSpace(w.doc.Title) {
+			w.skipTitle = true
+		} else if b.Level < w.topLevel {
+			w.topLevel = b.Level
+		}
+		first = false
+	}
+}
+
+func (w *latexWriter) line(s string) {
+	w.out.WriteString(s)
+	w.out.WriteString("\n")
+}
+
+// blocks 按块输出
+func (w *latexWriter) blocks(blocks []*mdBlock) {
+	for _, b := range blocks {
+		switch b.Kind {
+		case blockParagraph:
+			w.line(w.inlines(b.Inlines) + "\n")
+		case blockFigure:
+			w.figure(b)
+		case blockHeading:
+			w.heading(b)
+		case blockCode:
+			w.codeBlock(b)
+		case blockMath:
+			w.displayMath(b.Text)
+		case blockRule:
+			w.line("\\par\\noindent\\rule{\\linewidth}{0.4pt}\\par\n")
+		case blockQuote:
+			w.line("\\begin{quote}")
+			if b.Caption != nil {
+				w.line("\\textbf{" + w.inlines(b.Caption) + "}\n")
+			}
+			w.blocks(b.Children)
+			w.line("\\end{quote}\n")
+		case blockTable:
+			w.table(b)
+		case blockList:
+			w.list(b)
+		}
+	}
+}
+
+func (w *latexWriter) heading(b *mdBlock) {
+	if w.skipTitle && b.Level == 1 && b.Text == strings.TrimSpace(w.doc.Title) {
+		w.skipTitle = false
+		return
+	}
+	commands := []string{"section", "subsection", "subsubsection", "paragraph", "subparagraph"}
+	level := min(max(b.Level-w.topLevel, 0), len(commands)-1)
+	w.line("\\" + commands[level] + "{" + w.inlines(b.Inlines) + "}\n")
+}
+
+// codeBlock 输出代码块，math / latex 代码块按公式处理，output 代码块（运行结果）使用单独的样式
+func (w *latexWriter) codeBlock(b *mdBlock) {
+	if b.Lang == "math" || b.Lang == "latex" || b.Lang == "katex" {
+		w.displayMath(strings.TrimSpace(b.Text))
+		return
+	}
+	// 代码中出现结束标记时无法原样输出，改为逐行转义
+	if strings.Contains(b.Text, `\end{lstlisting}`) {
+		w.line("\\begin{flushleft}\\ttfamily")
+		for _, l := range strings.Split(b.Text, "\n") {
+			w.line(escapeTeX(l) + `\\`)
+		}
+		w.line("\\end{flushleft}\n")
+		return
+	}
+	if b.Lang == "output" {
+		w.line("\\begin{lstlisting}[style=output]")
+	} else {
+		w.line("\\begin{lstlisting}")
+	}
+	w.line(b.Text)
+	w.line("\\end{lstlisting}\n")
+}
+
+func (w *latexWriter) displayMath(tex string) {
+	if tex == "" {
+		return
+	}
+	w.notePackages(tex)
+	if mathEnvPattern.MatchString(tex) {
+		w.line(tex + "\n")
+		return
+	}
+	w.line("\\[\n" + tex + "\n\\]\n")
+}
+
+// notePackages 记录公式中用到的扩展宏包
+func (w *latexWriter) notePackages(tex string) {
+	if strings.Contains(tex, `\ce{`) || strings.Contains(tex, `\pu{`) {
+		w.packages["mhchem"] = true
+	}
+	if strings.Contains(tex, `\cancel`) {
+		w.packages["cancel"] = true
+	}
+}
+
+// table 输出表格（longtable，可跨页），内容较宽或含换行时使用定宽列
+func (w *latexWriter) table(b *mdBlock) {
+	cols := b.columns()
+	widths := make([]int, cols)
+	wrap := false
+	for _, row := range b.Rows {
+		for j, cell := range row {
+			if j >= cols {
+				break
+			}
+			text, breaks := plainText(cell)
+			widths[j] = max(widths[j], utf8.RuneCountInString(text))
+			wrap = wrap || breaks
+		}
+	}
+	total := 0
+	for _, n := range widths {
+		total += n
+	}
+	wrap = wrap || total > 80
+
+	var spec strings.Builder
+	spec.WriteString("@{}")
+	for j := 0; j < cols; j++ {
+		a := byte('l')
+		if j < len(b.Aligns) {
+			a = b.Aligns[j]
+		}
+		if !wrap {
+			spec.WriteByte(a)
+			continue
+		}
+		share := max(float64(widths[j]+1)/float64(total+cols), 0.08)
+		align := map[byte]string{'l': `\raggedright`, 'c': `\centering`, 'r': `\raggedleft`}[a]
+		fmt.Fprintf(&spec, `>{%s\arraybackslash}p{%.2f\linewidth}`, align, share*0.95)
+	}
+	spec.WriteString("@{}")
+
+	row := func(cells [][]mdInline, bold bool) string {
+		out := make([]string, cols)
+		for j := 0; j < cols && j < len(cells); j++ {
+			out[j] = w.inlines(cells[j])
+			if bold && out[j] != "" {
+				out[j] = "\\textbf{" + out[j] + "}"
+			}
+		}
+		return strings.Join(out, " & ") + ` \\`
+	}
+
+	w.line("\\begin{longtable}{" + spec.String() + "}")
+	w.line("\\toprule")
+	w.line(row(b.Rows[0], true))
+	w.line("\\midrule")
+	w.line("\\endhead")
+	for _, r := range b.Rows[1:] {
+		w.line(row(r, false))
+	}
+	w.line("\\bottomrule")
+	w.line("\\end{longtable}\n")
+}
+
+// plainText 单元格的纯文本（用于估算列宽），以及其中是否有强制换行
+func plainText(nodes []mdInline) (string, bool) {
+	var b strings.Builder
+	breaks := false
+	for _, n := range nodes {
+		switch n.Kind {
+		case inlineText, inlineCode, inlineMath, inlineImage:
+			b.WriteString(n.Text)
+		case inlineAutoLink:
+			b.WriteString(n.Dest)
+		case inlineLink, inlineEmphasis:
+			text, br := plainText(n.Children)
+			b.WriteString(text)
+			breaks = breaks || br
+		case inlineBreak:
+			breaks = true
+		}
+	}
+	return b.String(), breaks
+}
+
+// list 输出（嵌套）列表
+func (w *latexWriter) list(b *mdBlock) {
+	env := "itemize"
+	if b.Ordered {
+		env = "enumerate"
+	}
+	// LaTeX 列表最多嵌套四层，更深的层级平铺为段落
+	nested := w.listDepth < 4
+	if nested {
+		w.line("\\begin{" + env + "}")
+		if b.Ordered && w.enumDepth < 4 && b.Start > 1 {
+			w.line(fmt.Sprintf("\\setcounter{enum%s}{%d}", enumCounters[w.enumDepth], b.Start-1))
+		}
+	}
+	w.listDepth++
+	if b.Ordered {
+		w.enumDepth++
+	}
+	for _, item := range b.Items {
+		label := ""
+		switch item.Task {
+		case 1:
+			label = "[$\\square$]"
+		case 2:
+			label = "[$\\boxtimes$]"
+		}
+		if nested {
+			w.line("\\item" + label)
+		} else {
+			w.out.WriteString("-- ")
+		}
+		w.blocks(item.Children)
+	}
+	w.listDepth--
+	if b.Ordered {
+		w.enumDepth--
+	}
+	if nested {
+		w.line("\\end{" + env + "}\n")
+	}
+}
+
+// enumCounters 各层有序列表的计数器后缀（enumi / enumii / ...）
+var enumCounters = []string{"i", "ii", "iii", "iv"}
+
+// figure 单独成段的图片输出为带标题的浮动图，无法打包的图片按普通段落输出
+func (w *latexWriter) figure(b *mdBlock) {
+	if tikz := w.drawing(b.Src); tikz != "" {
+		w.line("\\begin{figure}[htbp]")
+		w.line("\\centering")
+		w.line(tikz)
+		if b.Alt != "" {
+			w.line("\\caption{" + w.inlines(b.Caption) + "}")
+		}
+		w.line("\\end{figure}\n")
+		return
+	}
+	if path := w.imagePath(b.Src); path != "" {
+		w.line("\\begin{figure}[htbp]")
+		w.line("\\centering")
+		w.line("\\includegraphics[width=0.9\\linewidth,height=0.5\\textheight,keepaspectratio]{" + path + "}")
+		if b.Alt != "" && b.Alt != "image" && !strings.Contains(b.Src, b.Alt) {
+			w.line("\\caption{" + w.inlines(b.Caption) + "}")
+		}
+		w.line("\\end{figure}\n")
+		return
+	}
+	w.line(w.inlines(b.Inlines) + "\n")
+}
+
+// drawing 渲染 drawing:ID 引用的绘图，非绘图引用或绘图不存在时返回空字符串
+func (w *latexWriter) drawing(src string) string {
+	id, ok := strings.CutPrefix(src, "drawing:")
+	if !ok || w.doc.Drawing == nil {
+		return ""
+	}
+	tikz := w.doc.Drawing(id)
+	if tikz != "" {
+		w.packages["tikz"] = true
+	}
+	return tikz
+}
+
+func (w *latexWriter) imagePath(src string) string {
+	if w.doc.Image == nil {
+		return ""
+	}
+	return w.doc.Image(src)
+}
+
+// latexEmphasis 强调样式对应的 LaTeX 命令
+var latexEmphasis = map[string]string{"strong": "textbf", "del": "sout", "mark": "uline", "em": "emph"}
+
+// inlines 输出行内元素，文字转义；未配对的反引号输出为 \`{}
+func (w *latexWriter) inlines(nodes []mdInline) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		switch n.Kind {
+		case inlineText:
+			b.WriteString(strings.ReplaceAll(escapeTeX(n.Text), "`", "\\`{}"))
+		case inlineCode:
+			b.WriteString("\\texttt{" + escapeTeX(n.Text) + "}")
+		case inlineMath:
+			w.notePackages(n.Text)
+			if n.Display {
+				b.WriteString("\\[" + n.Text + "\\]")
+			} else {
+				b.WriteString("$" + n.Text + "$")
+			}
+		case inlineImage:
+			if tikz := w.drawing(n.Dest); tikz != "" {
+				b.WriteString(tikz)
+			} else if path := w.imagePath(n.Dest); path != "" {
+				b.WriteString("\\includegraphics[width=\\linewidth,height=0.4\\textheight,keepaspectratio]{" + path + "}")
+			} else if strings.HasPrefix(n.Dest, "http://") || strings.HasPrefix(n.Dest, "https://") {
+				label := n.Text
+				if label == "" {
+					label = n.Dest
+				}
+				b.WriteString("\\href{" + escapeURL(n.Dest) + "}{" + escapeTeX(label) + "}")
+			} else {
+				b.WriteString("[" + escapeTeX(n.Text) + "]")
+			}
+		case inlineFootnote:
+			if !w.inNote[n.Text] {
+				w.inNote[n.Text] = true
+				b.WriteString("\\footnote{" + w.inlines(w.notes[n.Text]) + "}")
+				delete(w.inNote, n.Text)
+			}
+		case inlineLink:
+			// 页内锚点在 PDF 中没有对应位置，只保留文字
+			if n.Dest == "" || strings.HasPrefix(n.Dest, "#") {
+				b.WriteString(w.inlines(n.Children))
+			} else {
+				b.WriteString("\\href{" + escapeURL(n.Dest) + "}{" + w.inlines(n.Children) + "}")
+			}
+		case inlineAutoLink:
+			b.WriteString("\\href{" + escapeURL(n.Dest) + "}{" + escapeTeX(n.Dest) + "}")
+		case inlineEmphasis:
+			b.WriteString("\\" + latexEmphasis[n.Style] + "{" + w.inlines(n.Children) + "}")
+		case inlineBreak:
+			b.WriteString(`\newline{}`)
+		case inlineSoftBreak:
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
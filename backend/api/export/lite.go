@@ -0,0 +1,34 @@
+package export
+
+// LiteDoc 轻量阅读页的文档
+type LiteDoc struct {
+	Title   string
+	Author  string
+	Date    string
+	Lang    string
+	Content string // 已完成块引用、绘图与文献引用处理的 Markdown 正文
+	// FullURL 完整阅读页地址与说明，显示在页脚
+	FullURL   string
+	FullLabel string
+	// Link 改写链接与图片地址（如将相对的 assets/ 路径指向资源接口）
+	Link func(dest string) string
+	// Prerender 服务端预渲染公式、Mermaid 图与代码高亮，为 nil 时不预渲染
+	Prerender Prerenderer
+}
+
+// LitePage 生成面向慢速网络的轻量阅读页：与独立 HTML 文件包使用同一渲染流程，样式内联在页面中，
+// 不加载任何脚本与外部样式，公式显示 TeX 源码（启用服务端预渲染时显示预渲染结果），图片延迟加载
+func LitePage(doc LiteDoc) string {
+	return renderHTML(&htmlDoc{
+		Title:       doc.Title,
+		Author:      doc.Author,
+		Date:        doc.Date,
+		Source:      doc.FullURL,
+		SourceLabel: doc.FullLabel,
+		Content:     doc.Content,
+		Lang:        doc.Lang,
+		Lite:        true,
+		Link:        doc.Link,
+		Prerender:   doc.Prerender,
+	})
+}
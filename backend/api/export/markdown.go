@@ -0,0 +1,699 @@
+package export
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	headingPattern   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	rulePattern      = regexp.MustCompile(`^(?:(?:\*\s*){3,}|(?:-\s*){3,}|(?:_\s*){3,})$`)
+	listItemPattern  = regexp.MustCompile(`^( *)([-*+]|\d{1,9}[.)])(?:[ \t]+(.*))?$`)
+	tableDelimiter   = regexp.MustCompile(`^\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?$`)
+	footnoteDef      = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s*(.*)$`)
+	attrListPattern  = regexp.MustCompile(`^\{:[^}]*\}$`)
+	htmlTagPattern   = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(?:\s[^<>]*)?/?>`)
+	htmlBlockPattern = regexp.MustCompile(`^<(?:/?(?:div|details|summary|iframe|video|audio|section|figure|center|p|table|span)\b|!--)`)
+	calloutPattern   = regexp.MustCompile(`^\[!(\w+)\][+-]?\s*(.*)$`)
+)
+
+// mdDoc 解析后的 Markdown 正文，由 HTML（html.go）与 LaTeX（latex.go）输出分别渲染
+type mdDoc struct {
+	Blocks    []*mdBlock
+	Footnotes map[string][]mdInline // 脚注 ID → 脚注内容
+}
+
+type blockKind int
+
+const (
+	blockParagraph blockKind = iota // Inlines，行之间以 inlineSoftBreak 分隔
+	blockFigure                     // 单独成段的图片：Alt、Src，Caption 为解析后的 Alt，Inlines 为按段落输出时的内容
+	blockHeading                    // Level，Text 为标题原文
+	blockCode                       // Lang 为信息串的第一个词（小写），Text 为代码
+	blockMath                       // Text 为公式的 TeX 源码（已去掉首尾空白）
+	blockRule                       // 分隔线
+	blockQuote                      // Caption 为提示块标题（非提示块时为 nil），Children
+	blockTable                      // Aligns 为各列对齐（l / c / r），Rows 首行为表头
+	blockList                       // Ordered、Start、Items
+)
+
+// mdBlock 块级元素，各字段的含义见 blockKind
+type mdBlock struct {
+	Kind     blockKind
+	Level    int
+	Text     string
+	Lang     string
+	Alt      string
+	Src      string
+	Inlines  []mdInline
+	Caption  []mdInline
+	Children []*mdBlock
+	Aligns   []byte
+	Rows     [][][]mdInline
+	Ordered  bool
+	Start    int
+	Items    []mdListItem
+}
+
+// mdListItem 列表项
+type mdListItem struct {
+	Task     int // 0 普通项，1 未完成的任务，2 已完成的任务
+	Children []*mdBlock
+}
+
+type inlineKind int
+
+const (
+	inlineText      inlineKind = iota // Text 为原文，由输出转义
+	inlineCode                        // Text 为代码
+	inlineMath                        // Text 为 TeX 源码，Display 为 $$ 公式
+	inlineImage                       // Text 为替代文字，Dest 为地址
+	inlineLink                        // Dest 为地址（可为空），Children 为链接文字
+	inlineAutoLink                    // Dest 为地址，同时作为链接文字
+	inlineFootnote                    // Text 为脚注 ID，只用于已定义的脚注
+	inlineEmphasis                    // Style 为 strong / del / mark / em，Children
+	inlineBreak                       // 强制换行：<br> 或段落中的硬换行
+	inlineSoftBreak                   // 段落中的普通换行
+)
+
+// mdInline 行内元素，各字段的含义见 inlineKind
+type mdInline struct {
+	Kind     inlineKind
+	Text     string
+	Dest     string
+	Style    string
+	Display  bool
+	Children []mdInline
+}
+
+// emphasisStyles 强调定界符与对应的样式，双字符定界符在前
+var emphasisStyles = []struct{ delim, style string }{
+	{"**", "strong"}, {"__", "strong"}, {"~~", "del"}, {"==", "mark"}, {"*", "em"}, {"_", "em"},
+}
+
+type mdParser struct {
+	footnotes map[string]string
+}
+
+// parseMarkdown 解析正文：先提取脚注定义，再按块（标题、公式、代码、表格、列表、引用、段落）与行内元素解析
+func parseMarkdown(content string) *mdDoc {
+	p := &mdParser{footnotes: map[string]string{}}
+	lines := p.collectFootnotes(strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n"))
+	doc := &mdDoc{Blocks: p.blocks(lines), Footnotes: make(map[string][]mdInline, len(p.footnotes))}
+	for id, text := range p.footnotes {
+		doc.Footnotes[id] = p.inline(text)
+	}
+	return doc
+}
+
+// collectFootnotes 提取脚注定义（含缩进的续行），返回其余行
+func (p *mdParser) collectFootnotes(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		m := footnoteDef.FindStringSubmatch(lines[i])
+		if m == nil {
+			out = append(out, lines[i])
+			continue
+		}
+		text := []string{m[2]}
+		for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "    ") {
+			i++
+			text = append(text, strings.TrimSpace(lines[i]))
+		}
+		p.footnotes[m[1]] = strings.Join(text, " ")
+	}
+	return out
+}
+
+func isFence(line string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, "```") || strings.HasPrefix(t, "~~~")
+}
+
+func isBlockStart(line string) bool {
+	t := strings.TrimSpace(line)
+	return isFence(line) || strings.HasPrefix(t, "$$") || strings.HasPrefix(t, ">") ||
+		headingPattern.MatchString(t) || rulePattern.MatchString(t)
+}
+
+// blocks 按块解析
+func (p *mdParser) blocks(lines []string) []*mdBlock {
+	var out []*mdBlock
+	var para []string
+	flush := func() {
+		if len(para) > 0 {
+			out = append(out, p.paragraph(para))
+			para = nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := strings.ReplaceAll(lines[i], "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case attrListPattern.MatchString(trimmed):
+			// 思源块属性（IAL）
+		case isFence(line):
+			flush()
+			var b *mdBlock
+			b, i = p.codeBlock(lines, i)
+			out = append(out, b)
+		case strings.HasPrefix(trimmed, "$$"):
+			flush()
+			var b *mdBlock
+			b, i = p.mathBlock(lines, i)
+			out = append(out, b)
+		case headingPattern.MatchString(trimmed) && !strings.HasPrefix(line, "    "):
+			flush()
+			m := headingPattern.FindStringSubmatch(trimmed)
+			text := strings.TrimSpace(m[2])
+			out = append(out, &mdBlock{Kind: blockHeading, Level: len(m[1]), Text: text, Inlines: p.inline(text)})
+		case rulePattern.MatchString(trimmed):
+			flush()
+			out = append(out, &mdBlock{Kind: blockRule})
+		case strings.HasPrefix(trimmed, ">"):
+			flush()
+			var b *mdBlock
+			b, i = p.quote(lines, i)
+			out = append(out, b)
+		case strings.Contains(trimmed, "|") && i+1 < len(lines) && tableDelimiter.MatchString(strings.TrimSpace(lines[i+1])) && strings.Contains(lines[i+1], "-"):
+			flush()
+			var b *mdBlock
+			b, i = p.table(lines, i)
+			out = append(out, b)
+		case listItemPattern.MatchString(line) && !rulePattern.MatchString(trimmed):
+			flush()
+			var b *mdBlock
+			b, i = p.list(lines, i)
+			out = append(out, b)
+		case htmlBlockPattern.MatchString(trimmed) && len(para) == 0:
+			// HTML 块（嵌入视频、折叠块等）仅保留其中的文字
+			if text := stripTags(trimmed); strings.TrimSpace(text) != "" {
+				para = append(para, text)
+			}
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+	return out
+}
+
+// codeBlock 解析围栏代码块，返回代码块与结束行
+func (p *mdParser) codeBlock(lines []string, start int) (*mdBlock, int) {
+	open := strings.TrimSpace(lines[start])
+	marker := open[:3]
+	lang := ""
+	if info := strings.Fields(strings.TrimLeft(open, marker[:1])); len(info) > 0 {
+		lang = strings.ToLower(info[0])
+	}
+	var code []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), marker) {
+			break
+		}
+		code = append(code, lines[i])
+	}
+	return &mdBlock{Kind: blockCode, Lang: lang, Text: strings.Join(code, "\n")}, i
+}
+
+// mathBlock 解析 $$ 公式块（支持单行与多行形式）
+func (p *mdParser) mathBlock(lines []string, start int) (*mdBlock, int) {
+	first := strings.TrimSpace(lines[start])
+	if len(first) >= 4 && strings.HasSuffix(first, "$$") {
+		return &mdBlock{Kind: blockMath, Text: strings.TrimSpace(first[2 : len(first)-2])}, start
+	}
+	body := []string{strings.TrimPrefix(first, "$$")}
+	i := start + 1
+	for ; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if strings.HasSuffix(t, "$$") {
+			body = append(body, strings.TrimSuffix(t, "$$"))
+			break
+		}
+		body = append(body, lines[i])
+	}
+	return &mdBlock{Kind: blockMath, Text: strings.TrimSpace(strings.Join(body, "\n"))}, i
+}
+
+// quote 解析引用块，思源/Obsidian 风格的提示块标记作为标题
+func (p *mdParser) quote(lines []string, start int) (*mdBlock, int) {
+	var inner []string
+	i := start
+	for ; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(t, ">") {
+			break
+		}
+		t = strings.TrimPrefix(t, ">")
+		t = strings.TrimPrefix(t, " ")
+		inner = append(inner, t)
+	}
+	b := &mdBlock{Kind: blockQuote}
+	if len(inner) > 0 {
+		if m := calloutPattern.FindStringSubmatch(strings.TrimSpace(inner[0])); m != nil {
+			title := m[2]
+			if title == "" {
+				title = strings.ToUpper(m[1][:1]) + strings.ToLower(m[1][1:])
+			}
+			b.Caption = p.inline(title)
+			inner = inner[1:]
+		}
+	}
+	b.Children = p.blocks(inner)
+	return b, i - 1
+}
+
+// splitRow 拆分表格行，忽略转义、行内代码与公式中的竖线
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cur strings.Builder
+	inCode, inMath := false, false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cur.WriteByte('|')
+			i++
+			continue
+		case c == '`' && !inMath:
+			inCode = !inCode
+		case c == '$' && !inCode:
+			inMath = !inMath
+		case c == '|' && !inCode && !inMath:
+			cells = append(cells, strings.TrimSpace(cur.String()))
+			cur.Reset()
+			continue
+		}
+		cur.WriteByte(c)
+	}
+	return append(cells, strings.TrimSpace(cur.String()))
+}
+
+// table 解析表格，按分隔行确定列对齐
+func (p *mdParser) table(lines []string, start int) (*mdBlock, int) {
+	b := &mdBlock{Kind: blockTable}
+	for _, d := range splitRow(lines[start+1]) {
+		switch {
+		case strings.HasPrefix(d, ":") && strings.HasSuffix(d, ":"):
+			b.Aligns = append(b.Aligns, 'c')
+		case strings.HasSuffix(d, ":"):
+			b.Aligns = append(b.Aligns, 'r')
+		default:
+			b.Aligns = append(b.Aligns, 'l')
+		}
+	}
+	row := func(line string) [][]mdInline {
+		cells := splitRow(line)
+		out := make([][]mdInline, len(cells))
+		for j, cell := range cells {
+			out[j] = p.inline(cell)
+		}
+		return out
+	}
+	b.Rows = [][][]mdInline{row(lines[start])}
+	i := start + 2
+	for ; i < len(lines); i++ {
+		t := strings.TrimSpace(lines[i])
+		if t == "" || !strings.Contains(t, "|") {
+			break
+		}
+		b.Rows = append(b.Rows, row(t))
+	}
+	return b, i - 1
+}
+
+// columns 表格的列数：分隔行与表头中较多的一个
+func (b *mdBlock) columns() int {
+	return max(len(b.Aligns), len(b.Rows[0]))
+}
+
+// list 解析（嵌套）列表，列表项内容按块递归解析
+func (p *mdParser) list(lines []string, start int) (*mdBlock, int) {
+	first := listItemPattern.FindStringSubmatch(lines[start])
+	indent := len(first[1])
+	b := &mdBlock{Kind: blockList, Ordered: first[2][0] >= '0' && first[2][0] <= '9', Start: 1}
+	if b.Ordered {
+		if n, _ := strconv.Atoi(strings.TrimRight(first[2], ".)")); n > 1 {
+			b.Start = n
+		}
+	}
+
+	i := start
+	for i < len(lines) {
+		m := listItemPattern.FindStringSubmatch(strings.ReplaceAll(lines[i], "\t", "    "))
+		if m == nil || len(m[1]) != indent || (m[2][0] >= '0' && m[2][0] <= '9') != b.Ordered {
+			break
+		}
+		contentCol := len(m[0]) - len(m[3])
+		if m[3] == "" {
+			contentCol = len(m[1]) + len(m[2]) + 1
+		}
+		item := []string{m[3]}
+		i++
+		for i < len(lines) {
+			l := strings.ReplaceAll(lines[i], "\t", "    ")
+			lead := len(l) - len(strings.TrimLeft(l, " "))
+			if strings.TrimSpace(l) == "" {
+				// 空行后仍有缩进内容时属于当前列表项
+				j := i + 1
+				for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+					j++
+				}
+				if j < len(lines) {
+					next := strings.ReplaceAll(lines[j], "\t", "    ")
+					if len(next)-len(strings.TrimLeft(next, " ")) > indent {
+						item = append(item, "")
+						i++
+						continue
+					}
+				}
+				break
+			}
+			if lead > indent {
+				item = append(item, l[min(lead, contentCol):])
+				i++
+				continue
+			}
+			// 懒惰续行：紧接在段落文字之后、不是新块的行
+			if !listItemPattern.MatchString(l) && strings.TrimSpace(item[len(item)-1]) != "" && !isBlockStart(l) {
+				item = append(item, strings.TrimSpace(l))
+				i++
+				continue
+			}
+			break
+		}
+
+		task := 0
+		switch text := item[0]; {
+		case strings.HasPrefix(text, "[ ] "):
+			task, item[0] = 1, text[4:]
+		case strings.HasPrefix(text, "[x] "), strings.HasPrefix(text, "[X] "):
+			task, item[0] = 2, text[4:]
+		}
+		b.Items = append(b.Items, mdListItem{Task: task, Children: p.blocks(item)})
+		// 跳过列表项之间的空行
+		j := i
+		for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+			j++
+		}
+		if j > i && j < len(lines) {
+			if m := listItemPattern.FindStringSubmatch(lines[j]); m != nil && len(m[1]) == indent {
+				i = j
+			}
+		}
+	}
+	return b, i - 1
+}
+
+// paragraph 解析段落：行尾两个空格或反斜杠为硬换行；只包含一张图片的段落为 blockFigure
+func (p *mdParser) paragraph(lines []string) *mdBlock {
+	var inlines []mdInline
+	for i, l := range lines {
+		if i > 0 {
+			inlines = append(inlines, mdInline{Kind: inlineSoftBreak})
+		}
+		hard := strings.HasSuffix(l, "  ") || (strings.HasSuffix(l, `\`) && !strings.HasSuffix(l, `\\`))
+		inlines = append(inlines, p.inline(strings.TrimSpace(strings.TrimSuffix(l, `\`)))...)
+		if hard && i < len(lines)-1 {
+			inlines = append(inlines, mdInline{Kind: inlineBreak})
+		}
+	}
+	b := &mdBlock{Kind: blockParagraph, Inlines: inlines}
+	if len(lines) == 1 {
+		if alt, src, ok := soleImage(strings.TrimSpace(lines[0])); ok {
+			b.Kind, b.Alt, b.Src, b.Caption = blockFigure, alt, src, p.inline(alt)
+		}
+	}
+	return b
+}
+
+// soleImage 判断段落是否只包含一张图片
+func soleImage(s string) (alt, src string, ok bool) {
+	if !strings.HasPrefix(s, "![") {
+		return "", "", false
+	}
+	text, dest, end, ok := parseLink(s, 1)
+	if !ok || end != len(s) {
+		return "", "", false
+	}
+	return text, dest, true
+}
+
+// parseLink 解析 [text](dest "title")，start 指向 '['，返回文字、地址与结束位置
+func parseLink(s string, start int) (text, dest string, end int, ok bool) {
+	depth := 0
+	i := start
+	for ; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if i >= len(s) || i+1 >= len(s) || s[i+1] != '(' {
+		return "", "", 0, false
+	}
+	text = s[start+1 : i]
+	j := i + 2
+	depth = 1
+	for ; j < len(s); j++ {
+		if s[j] == '(' {
+			depth++
+		} else if s[j] == ')' {
+			depth--
+			if depth == 0 {
+				break
+			}
+		}
+	}
+	if j >= len(s) {
+		return "", "", 0, false
+	}
+	dest = strings.TrimSpace(s[i+2 : j])
+	// 去掉可选的标题
+	if k := strings.IndexAny(dest, " \t"); k > 0 {
+		dest = dest[:k]
+	}
+	dest = strings.TrimSuffix(strings.TrimPrefix(dest, "<"), ">")
+	return text, dest, j + 1, true
+}
+
+// stripTags 去除 HTML 标签
+func stripTags(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '<' {
+			if loc := htmlTagPattern.FindStringIndex(s[i:]); loc != nil {
+				i += loc[1] - 1
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// appendText 追加文字，与前一个文字节点合并
+func appendText(out []mdInline, s string) []mdInline {
+	if n := len(out); n > 0 && out[n-1].Kind == inlineText {
+		out[n-1].Text += s
+		return out
+	}
+	return append(out, mdInline{Kind: inlineText, Text: s})
+}
+
+// inline 解析行内元素：强调、代码、公式、链接、图片、脚注、<br> 与自动链接，其余 HTML 标签去掉
+func (p *mdParser) inline(s string) []mdInline {
+	var out []mdInline
+	for i := 0; i < len(s); {
+		c := s[i]
+		rest := s[i:]
+		switch {
+		case c == '\\' && i+1 < len(s) && strings.IndexByte("\\`*_{}[]()#+-.!|~=$<>", s[i+1]) >= 0:
+			out = appendText(out, s[i+1:i+2])
+			i += 2
+			continue
+
+		case c == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			fence := rest[:n]
+			if end := strings.Index(rest[n:], fence); end >= 0 {
+				out = append(out, mdInline{Kind: inlineCode, Text: strings.TrimSpace(rest[n : n+end])})
+				i += n + end + n
+				continue
+			}
+			out = appendText(out, fence)
+			i += n
+			continue
+
+		case strings.HasPrefix(rest, "$$"):
+			if end := strings.Index(rest[2:], "$$"); end > 0 {
+				out = append(out, mdInline{Kind: inlineMath, Text: rest[2 : 2+end], Display: true})
+				i += end + 4
+				continue
+			}
+
+		case c == '$':
+			if end := closingDollar(rest); end > 0 {
+				out = append(out, mdInline{Kind: inlineMath, Text: rest[1:end]})
+				i += end + 1
+				continue
+			}
+
+		case strings.HasPrefix(rest, "!["):
+			if alt, src, end, ok := parseLink(s, i+1); ok {
+				out = append(out, mdInline{Kind: inlineImage, Text: alt, Dest: src})
+				i = end
+				continue
+			}
+
+		case strings.HasPrefix(rest, "[^"):
+			if end := strings.IndexByte(rest, ']'); end > 2 {
+				if _, ok := p.footnotes[rest[2:end]]; ok {
+					out = append(out, mdInline{Kind: inlineFootnote, Text: rest[2:end]})
+					i += end + 1
+					continue
+				}
+			}
+
+		case c == '[':
+			if text, dest, end, ok := parseLink(s, i); ok {
+				out = append(out, mdInline{Kind: inlineLink, Dest: dest, Children: p.inline(text)})
+				i = end
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(rest, '>'); end > 0 {
+				inner := rest[1:end]
+				if strings.HasPrefix(inner, "http://") || strings.HasPrefix(inner, "https://") {
+					out = append(out, mdInline{Kind: inlineAutoLink, Dest: inner})
+					i += end + 1
+					continue
+				}
+				if loc := htmlTagPattern.FindStringIndex(rest); loc != nil {
+					if strings.HasPrefix(strings.ToLower(rest[:loc[1]]), "<br") {
+						out = append(out, mdInline{Kind: inlineBreak})
+					}
+					i += loc[1]
+					continue
+				}
+			}
+
+		case strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://"):
+			if i == 0 || !isWordByte(s[i-1]) {
+				end := strings.IndexFunc(rest, func(r rune) bool {
+					return unicode.IsSpace(r) || r == '<' || r == '>' || r == '"' || r > unicode.MaxASCII
+				})
+				if end < 0 {
+					end = len(rest)
+				}
+				u := strings.TrimRight(rest[:end], ".,;:!?)")
+				out = append(out, mdInline{Kind: inlineAutoLink, Dest: u})
+				i += len(u)
+				continue
+			}
+		}
+
+		if d, style := emphasisDelimiter(s, i); d != "" {
+			if end := closingDelimiter(s, i, d); end > 0 {
+				out = append(out, mdInline{Kind: inlineEmphasis, Style: style, Children: p.inline(s[i+len(d) : end])})
+				i = end + len(d)
+				continue
+			}
+			out = appendText(out, d)
+			i += len(d)
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(rest)
+		out = appendText(out, rest[:size])
+		i += size
+	}
+	return out
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// closingDollar 查找行内公式的结束 $：开头与结尾不能是空白，结尾后不能紧跟数字（避免误识别金额）
+func closingDollar(s string) int {
+	if len(s) < 3 || s[1] == ' ' || s[1] == '$' {
+		return -1
+	}
+	for j := 2; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '$':
+			if s[j-1] == ' ' || (j+1 < len(s) && s[j+1] >= '0' && s[j+1] <= '9') {
+				return -1
+			}
+			return j
+		}
+	}
+	return -1
+}
+
+// emphasisDelimiter 识别强调定界符，返回定界符与样式；_ 在单词内部时不视为强调
+func emphasisDelimiter(s string, i int) (string, string) {
+	rest := s[i:]
+	for _, d := range emphasisStyles {
+		if !strings.HasPrefix(rest, d.delim) {
+			continue
+		}
+		if d.delim[0] == '_' && i > 0 && isWordByte(s[i-1]) {
+			return "", ""
+		}
+		next := i + len(d.delim)
+		if next >= len(s) || s[next] == ' ' {
+			return "", ""
+		}
+		return d.delim, d.style
+	}
+	return "", ""
+}
+
+// closingDelimiter 查找匹配的结束定界符，返回其位置
+func closingDelimiter(s string, start int, d string) int {
+	from := start + len(d)
+	for from < len(s) {
+		k := strings.Index(s[from:], d)
+		if k < 0 {
+			return -1
+		}
+		end := from + k
+		// 结束符前不能是空白；单字符定界符不能是双字符定界符的一部分
+		valid := end > start+len(d) && s[end-1] != ' ' && s[end-1] != '\\'
+		if len(d) == 1 && end+1 < len(s) && s[end+1] == d[0] {
+			valid = false
+			end++
+		}
+		if d[0] == '_' && end+len(d) < len(s) && isWordByte(s[end+len(d)]) {
+			valid = false
+		}
+		if valid {
+			return end
+		}
+		from = end + 1
+	}
+	return -1
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// ErrPDFUnavailable 未找到 Chromium，无法导出 PDF
+var ErrPDFUnavailable = errors.New("pdf export is not available")
+
+// chromiumCandidates 未配置 export.chromium 时在 PATH 中查找的可执行文件
+var chromiumCandidates = []string{"chromium", "chromium-browser", "google-chrome", "google-chrome-stable", "headless-shell"}
+
+var (
+	chromiumOnce sync.Once
+	chromium     string
+	// pdfSlots 同时进行的 PDF 渲染数上限，每个渲染会启动一个浏览器进程
+	pdfSlots = make(chan struct{}, 2)
+	// pdfVersions 各分享已缓存 PDF 对应的内容版本；进程重启后首次下载会重新生成
+	pdfVersions sync.Map
+	pdfLocks    sync.Map
+)
+
+// chromiumPath 返回 Chromium 可执行文件路径，未找到时返回空字符串
+func chromiumPath() string {
+	chromiumOnce.Do(func() {
+		if p := config.Get().Export.Chromium; p != "" {
+			if path, err := exec.LookPath(p); err == nil {
+				chromium = path
+			}
+			return
+		}
+		for _, name := range chromiumCandidates {
+			if path, err := exec.LookPath(name); err == nil {
+				chromium = path
+				return
+			}
+		}
+	})
+	return chromium
+}
+
+// PDFAvailable 是否可以导出 PDF
+func PDFAvailable() bool {
+	base, _ := PDFPageBase()
+	return chromiumPath() != "" && base != ""
+}
+
+// PDFPageBase Chromium 访问本服务的地址：优先使用 export.pdf_base_url，否则为本机监听端口；
+// 启用内置 HTTPS 时证书域名与 127.0.0.1 不符，需忽略证书错误。只在 Unix 套接字上监听且未配置时为空
+func PDFPageBase() (string, bool) {
+	cfg := config.Get()
+	if cfg.Export.PDFBaseURL != "" {
+		return cfg.Export.PDFBaseURL, false
+	}
+	if cfg.TLS.Enabled() {
+		return "https://127.0.0.1:" + cfg.TLS.HTTPSPort, true
+	}
+	if cfg.Server.UnixSocket() != "" {
+		return "", false
+	}
+	host, port, _ := net.SplitHostPort(cfg.Server.ListenAddr())
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port), false
+}
+
+// pdfVersion 分享内容版本：正文、标题或设置变化后更新时间随之变化
+func pdfVersion(share *models.Share) string {
+	return strconv.FormatInt(share.UpdatedAt.UnixNano(), 36)
+}
+
+// SharePDF 返回分享的 PDF 与下载文件名：内容未变化时使用缓存，否则调用 pageURL 生成的打印地址重新渲染
+func SharePDF(ctx context.Context, share *models.Share, pageURL func() string) ([]byte, string, error) {
+	name := slug.FileName(share.DocTitle, share.ID) + ".pdf"
+	if storage.Default == nil {
+		return nil, "", errors.New("storage not initialized")
+	}
+	key := "exports/" + share.ID + "/share.pdf"
+	version := pdfVersion(share)
+
+	// 同一分享同时只渲染一次，后到的请求等待并复用结果
+	lock, _ := pdfLocks.LoadOrStore(share.ID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	if v, ok := pdfVersions.Load(share.ID); ok && v.(string) == version {
+		if rc, _, err := storage.Default.Get(ctx, key); err == nil {
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err == nil {
+				return data, name, nil
+			}
+		}
+	}
+
+	data, err := PDF(ctx, pageURL())
+	if err != nil {
+		return nil, "", err
+	}
+	if err := storage.Default.Put(ctx, key, bytes.NewReader(data), int64(len(data)), "application/pdf"); err == nil {
+		pdfVersions.Store(share.ID, version)
+	}
+	return data, name, nil
+}
+
+// PDF 用无头 Chromium 打开页面并打印为 PDF（A4，不含页眉页脚）
+func PDF(ctx context.Context, pageURL string) ([]byte, error) {
+	bin := chromiumPath()
+	if bin == "" {
+		return nil, ErrPDFUnavailable
+	}
+	ctx, cancel := context.WithTimeout(ctx, config.Get().Export.PDFTimeout.Std())
+	defer cancel()
+	select {
+	case pdfSlots <- struct{}{}:
+		defer func() { <-pdfSlots }()
+	case <-ctx.Done():
+		return nil, errors.New("pdf export is busy, please retry later")
+	}
+
+	dir, err := os.MkdirTemp("", "siyuan-share-pdf-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "share.pdf")
+
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		"--no-sandbox",
+		"--no-first-run",
+		"--disable-extensions",
+		"--hide-scrollbars",
+		"--user-data-dir=" + filepath.Join(dir, "profile"),
+		"--run-all-compositor-stages-before-draw",
+		// 给阅读页留出加载正文、图片与附属数据的时间
+		"--virtual-time-budget=15000",
+		"--no-pdf-header-footer",
+		"--print-to-pdf=" + out,
+	}
+	if _, insecure := PDFPageBase(); insecure {
+		args = append(args, "--ignore-certificate-errors")
+	}
+	args = append(args, pageURL)
+
+	cmd := exec.CommandContext(ctx, bin, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, errors.New("pdf export timed out")
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > 500 {
+			msg = msg[len(msg)-500:]
+		}
+		return nil, fmt.Errorf("chromium: %v: %s", err, msg)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		return nil, fmt.Errorf("chromium produced no pdf: %w", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		return nil, errors.New("chromium produced an invalid pdf")
+	}
+	return data, nil
+}
@@ -0,0 +1,186 @@
+package export
+
+import (
+	"html"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+)
+
+// Prerenderer 服务端预渲染：kind 为 renderer.Prerender* 之一，lang 为代码块语言；返回 false 时按未预渲染输出
+type Prerenderer func(kind, lang, source string) (string, bool)
+
+// clientBlockLangs 阅读页自行处理的代码块语言（运行结果、看板与思维导图），不做代码高亮
+var clientBlockLangs = map[string]bool{"output": true, "kanban": true, "mindmap": true, "markmap": true}
+
+// highlightable 代码块是否交给代码高亮：有语言、不是阅读页自行处理或由外部渲染插件渲染为图片的代码块
+func highlightable(lang string) bool {
+	return lang != "" && !clientBlockLangs[lang] && renderer.Lookup(lang) == nil
+}
+
+// PrerenderMarkdown 将正文中的公式、Mermaid 图与代码块替换为预渲染的 HTML，供阅读页直接显示。
+// 替换保持行号不变（阅读页按行号对应表格、任务与思维导图）：多行块以 HTML 注释补齐行数，
+// 高亮后的代码逐行对应原代码；行数对不上或预渲染失败的块保持原样
+func PrerenderMarkdown(content string, pre Prerenderer) string {
+	if pre == nil {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	out := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case isFence(line):
+			// 列表等缩进内容中的代码块替换后会脱离所在的块，保持原样
+			end := fenceEnd(lines, i)
+			if line == trimmed {
+				out = append(out, prerenderFence(lines[i:end+1], pre)...)
+			} else {
+				out = append(out, lines[i:end+1]...)
+			}
+			i = end
+		case strings.HasPrefix(line, "$$"):
+			end := i
+			if len(trimmed) < 4 || !strings.HasSuffix(trimmed, "$$") {
+				for end = i + 1; end < len(lines) && !strings.HasSuffix(strings.TrimSpace(lines[end]), "$$"); end++ {
+				}
+				if end == len(lines) {
+					out = append(out, line)
+					continue
+				}
+			}
+			block := strings.TrimSpace(strings.Join(lines[i:end+1], "\n"))
+			tex := strings.TrimSpace(block[2 : len(block)-2])
+			if htm, ok := pre(renderer.PrerenderMathDisplay, "", tex); ok && tex != "" {
+				out = append(out, padBlock(`<div class="math-block prerendered">`+oneLine(htm)+`</div>`, end-i+1)...)
+			} else {
+				out = append(out, lines[i:end+1]...)
+			}
+			i = end
+		case strings.HasPrefix(trimmed, "|"):
+			// 表格行中的 HTML 可能含有 | 破坏单元格划分，不处理行内公式
+			out = append(out, line)
+		default:
+			out = append(out, prerenderInlineMath(line, pre))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// fenceEnd 围栏代码块结束行的下标；未闭合时为最后一行
+func fenceEnd(lines []string, start int) int {
+	open := strings.TrimSpace(lines[start])
+	marker := open[:3]
+	for i := start + 1; i < len(lines); i++ {
+		if t := strings.TrimSpace(lines[i]); strings.HasPrefix(t, marker) && strings.Trim(t, marker[:1]) == "" {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
+
+// prerenderFence 预渲染一个围栏代码块（含首尾围栏行），返回同样行数的替换内容
+func prerenderFence(block []string, pre Prerenderer) []string {
+	open := strings.TrimSpace(block[0])
+	lang := ""
+	if info := strings.Fields(strings.TrimLeft(open, open[:1])); len(info) > 0 {
+		lang = strings.ToLower(info[0])
+	}
+	// 由外部渲染插件渲染的代码块阅读页按行号替换为图片，保持原样
+	closed := len(block) > 1 && isFence(block[len(block)-1])
+	if !closed || renderer.Lookup(lang) != nil {
+		return block
+	}
+	body := strings.Join(block[1:len(block)-1], "\n")
+	switch {
+	case lang == "math" || lang == "latex" || lang == "katex":
+		if htm, ok := pre(renderer.PrerenderMathDisplay, "", strings.TrimSpace(body)); ok {
+			return padBlock(`<div class="math-block prerendered">`+oneLine(htm)+`</div>`, len(block))
+		}
+	case lang == "mermaid":
+		if svg, ok := pre(renderer.PrerenderMermaid, "", body); ok {
+			return padBlock(`<figure class="mermaid prerendered">`+oneLine(svg)+`</figure>`, len(block))
+		}
+	case highlightable(lang):
+		htm, ok := pre(renderer.PrerenderHighlight, lang, body)
+		if !ok {
+			break
+		}
+		// <pre> 后紧跟的换行不显示：首行只放 <pre>，高亮后的每行对应原代码的一行
+		code := strings.Split(strings.TrimRight(htm, "\n"), "\n")
+		if len(code) != len(block)-2 {
+			break
+		}
+		out := make([]string, 0, len(block))
+		out = append(out, `<pre class="prerendered">`)
+		code[0] = `<code data-lang="` + html.EscapeString(lang) + `">` + code[0]
+		code[len(code)-1] += "</code>"
+		out = append(out, code...)
+		return append(out, "</pre>")
+	}
+	return block
+}
+
+// padBlock 单行 HTML 占据 n 行：前面以 HTML 注释补齐，使后续内容的行号不变
+func padBlock(htm string, n int) []string {
+	if n <= 1 {
+		return []string{htm}
+	}
+	out := make([]string, n)
+	out[0] = "<!--"
+	out[n-1] = "-->" + htm
+	return out
+}
+
+// oneLine 去掉 HTML 片段中的换行（空行会提前结束 Markdown 中的 HTML 块）
+func oneLine(htm string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(htm, "\n", " ")), " ")
+}
+
+// prerenderInlineMath 预渲染一行中的行内公式，跳过行内代码与转义的 $
+func prerenderInlineMath(line string, pre Prerenderer) string {
+	if !strings.Contains(line, "$") {
+		return line
+	}
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		c := line[i]
+		rest := line[i:]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			b.WriteString(line[i : i+2])
+			i += 2
+			continue
+		case c == '`':
+			n := len(rest) - len(strings.TrimLeft(rest, "`"))
+			if end := strings.Index(rest[n:], rest[:n]); end >= 0 {
+				b.WriteString(rest[:n+end+n])
+				i += n + end + n
+				continue
+			}
+			b.WriteString(rest[:n])
+			i += n
+			continue
+		case strings.HasPrefix(rest, "$$"):
+			if end := strings.Index(rest[2:], "$$"); end > 0 {
+				if htm, ok := pre(renderer.PrerenderMathDisplay, "", rest[2:2+end]); ok {
+					b.WriteString(`<span class="math-display prerendered">` + oneLine(htm) + `</span>`)
+					i += end + 4
+					continue
+				}
+			}
+		case c == '$':
+			if end := closingDollar(rest); end > 0 {
+				if htm, ok := pre(renderer.PrerenderMath, "", rest[1:end]); ok {
+					b.WriteString(`<span class="math-inline prerendered">` + oneLine(htm) + `</span>`)
+					i += end + 1
+					continue
+				}
+			}
+		}
+		b.WriteByte(c)
+		i++
+	}
+	return b.String()
+}
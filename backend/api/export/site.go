@@ -0,0 +1,241 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/drawing"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// SiteOptions 静态站点导出选项
+type SiteOptions struct {
+	Title   string // 首页标题，为空时为「思源分享」
+	BaseURL string // 原站点地址，页脚链接到原分享；为空时不输出原文链接
+}
+
+// SiteSummary 静态站点导出结果
+type SiteSummary struct {
+	Shares   int
+	Assets   int
+	Bytes    int64
+	Warnings []string
+}
+
+// siteEntry 首页列表中的分享
+type siteEntry struct {
+	id, title, author string
+	updated           time.Time
+}
+
+// Site 将全部公开收录的分享（与 sitemap 条件相同：已发布、未过期、无需密码等访问校验的分享）导出为静态 HTML 站点：
+// 首页 index.html 列出全部分享，每个分享为 s/<id>/index.html，资源文件与绘图（SVG）放在同一目录下，
+// 分享之间的链接改写为站内相对路径，可直接托管在 GitHub Pages 或对象存储上作为只读镜像。
+// 文件按生成顺序交给 put，无法导出的资源记录在 Warnings 中
+func Site(ctx context.Context, opts SiteOptions, put func(name string, data []byte) error) (*SiteSummary, error) {
+	if storage.Default == nil {
+		return nil, fmt.Errorf("storage not initialized")
+	}
+	if opts.Title == "" {
+		opts.Title = "思源分享"
+	}
+	base := strings.TrimRight(opts.BaseURL, "/")
+
+	var ids []string
+	if err := models.DB.Model(&models.Share{}).
+		Where("listed = ? AND is_public = ? AND status = ? AND require_password = ? AND restricted = ? AND access_schedule = ? AND terms = ? AND paywall = ? AND teaser = ? AND max_views = ? AND expire_at > ?",
+			true, true, models.ShareStatusPublished, false, false, "", "", false, false, 0, time.Now()).
+		Where("user_id IN (?)", models.DB.Model(&models.User{}).Select("id").Where("is_active = ?", true)).
+		Order("updated_at DESC").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	exported := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		exported[id] = true
+	}
+	authors := map[string]string{}
+
+	summary := &SiteSummary{}
+	entries := make([]siteEntry, 0, len(ids))
+	write := func(name string, data []byte) error {
+		summary.Bytes += int64(len(data))
+		return put(name, data)
+	}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		// 逐条加载，避免一次性读入全部正文
+		var share models.Share
+		if err := models.DB.Where("id = ?", id).First(&share).Error; err != nil {
+			summary.Warnings = append(summary.Warnings, id+": "+err.Error())
+			continue
+		}
+		author, ok := authors[share.UserID]
+		if !ok {
+			var user models.User
+			if models.DB.Select("id", "username").Where("id = ?", share.UserID).First(&user).Error == nil {
+				author = user.Username
+			}
+			authors[share.UserID] = author
+		}
+		assets, err := siteShareFiles(ctx, &share, author, exported, base, write, summary)
+		if err != nil {
+			return nil, err
+		}
+		summary.Assets += assets
+		summary.Shares++
+		entries = append(entries, siteEntry{id: share.ID, title: share.DocTitle, author: author, updated: share.UpdatedAt})
+	}
+
+	if err := write("index.html", []byte(siteIndex(opts.Title, entries))); err != nil {
+		return nil, err
+	}
+	// GitHub Pages 默认忽略下划线开头的文件
+	if err := write(".nojekyll", nil); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// siteShareFiles 写出一个分享的页面、资源文件与绘图，返回写出的资源数
+func siteShareFiles(ctx context.Context, share *models.Share, author string, exported map[string]bool, base string, write func(string, []byte) error, summary *SiteSummary) (int, error) {
+	dir := "s/" + share.ID + "/"
+
+	var assets []models.Asset
+	if err := models.DB.Where("share_id = ?", share.ID).Order("path").Find(&assets).Error; err != nil {
+		return 0, err
+	}
+	packed := map[string]bool{}
+	for i := range assets {
+		a := &assets[i]
+		if a.Size > maxBundleAssetBytes {
+			summary.Warnings = append(summary.Warnings, share.ID+"/"+a.Path+": file too large")
+			continue
+		}
+		data, err := readAsset(ctx, a, maxBundleAssetBytes)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			summary.Warnings = append(summary.Warnings, share.ID+"/"+a.Path+": "+err.Error())
+			continue
+		}
+		if err := write(dir+a.Path, data); err != nil {
+			return 0, err
+		}
+		packed[a.Path] = true
+	}
+
+	drawings := map[string]bool{}
+	for _, d := range share.DrawingList() {
+		scene, err := drawing.Parse(d.Scene)
+		if err != nil {
+			summary.Warnings = append(summary.Warnings, share.ID+" drawing "+d.ID+": "+err.Error())
+			continue
+		}
+		if err := write(dir+"drawings/"+d.ID+".svg", drawing.SVG(scene)); err != nil {
+			return 0, err
+		}
+		drawings[d.ID] = true
+	}
+
+	link := func(dest string) string {
+		if id, ok := strings.CutPrefix(dest, "drawing:"); ok {
+			if drawings[id] {
+				return "drawings/" + id + ".svg"
+			}
+			return dest
+		}
+		// 指向其他分享的链接：已导出的改为站内相对路径，其余指向原站点
+		target := dest
+		if base != "" {
+			target = strings.TrimPrefix(target, base)
+		}
+		if rest, ok := strings.CutPrefix(target, "/s/"); ok {
+			id, suffix := rest, ""
+			if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+				id, suffix = rest[:i], rest[i:]
+			}
+			if exported[id] && (suffix == "" || suffix == "/" || suffix[0] == '#') {
+				return "../" + id + "/" + strings.TrimPrefix(suffix, "/")
+			}
+			if base != "" {
+				return base + target
+			}
+			return dest
+		}
+		if strings.Contains(dest, "://") {
+			return dest
+		}
+		if p := shareAssetPath(share.ID, dest); p != "" && packed[p] {
+			return (&url.URL{Path: p}).String()
+		}
+		return dest
+	}
+
+	source := ""
+	if base != "" {
+		source = base + "/s/" + share.ID
+	}
+	page := renderHTML(&htmlDoc{
+		Title:   share.DocTitle,
+		Author:  author,
+		Date:    share.UpdatedAt.Format("2006-01-02"),
+		Source:  source,
+		Content: share.Content,
+		Link:    link,
+		Home:    "../../",
+	})
+	if err := write(dir+"index.html", []byte(page)); err != nil {
+		return 0, err
+	}
+	return len(packed), nil
+}
+
+// siteIndex 静态站点首页：按更新时间倒序列出全部分享
+func siteIndex(title string, entries []siteEntry) string {
+	var b strings.Builder
+	b.WriteString("<!doctype html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	b.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	b.WriteString("<style>\n" + htmlStyle + "ul.site{list-style:none;padding:0}\nul.site li{margin:0 0 1em}\n</style>\n")
+	b.WriteString("</head>\n<body>\n<header>\n<h1>" + html.EscapeString(title) + "</h1>\n")
+	fmt.Fprintf(&b, "<p class=\"meta\">共 %d 篇</p>\n</header>\n<main>\n<ul class=\"site\">\n", len(entries))
+	for _, e := range entries {
+		meta := e.updated.Format("2006-01-02")
+		if e.author != "" {
+			meta = html.EscapeString(e.author) + " · " + meta
+		}
+		b.WriteString(`<li><a href="s/` + url.PathEscape(e.id) + `/">` + html.EscapeString(e.title) + `</a><br><span class="meta">` + meta + "</span></li>\n")
+	}
+	b.WriteString("</ul>\n</main>\n")
+	fmt.Fprintf(&b, "<footer>导出于 %s</footer>\n", time.Now().Format("2006-01-02 15:04"))
+	b.WriteString("</body>\n</html>\n")
+	return b.String()
+}
+
+// WriteSiteZip 将静态站点写为 zip，供下载或上传到对象存储
+func WriteSiteZip(ctx context.Context, w io.Writer, opts SiteOptions) (*SiteSummary, error) {
+	zw := zip.NewWriter(w)
+	modified := time.Now()
+	summary, err := Site(ctx, opts, func(name string, data []byte) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, zw.Close()
+}
@@ -0,0 +1,213 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/citation"
+	"github.com/ZeroHawkeye/siyuan-share-api/drawing"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/slug"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// 独立文件包格式
+const (
+	BundleMarkdown = "md"   // index.md（带 YAML front matter）
+	BundleHTML     = "html" // index.html（内联样式，无需本服务即可打开）
+)
+
+// BundleFormats 支持的独立文件包格式
+var BundleFormats = map[string]bool{BundleMarkdown: true, BundleHTML: true}
+
+const (
+	maxBundleAssets     = 500
+	maxBundleAssetBytes = 100 << 20
+	maxBundleBytes      = 512 << 20
+)
+
+var (
+	mdLinkDest   = regexp.MustCompile(`(\]\(\s*<?)([^)\s>]+)`)
+	htmlLinkAttr = regexp.MustCompile(`((?:src|href)\s*=\s*["'])([^"']+)`)
+)
+
+// BundleInfo 文件包附带的文档信息
+type BundleInfo struct {
+	Author string
+	URL    string // 分享链接，写入 front matter 与 HTML 页脚
+	// Reproducible 可复现导出：条目时间固定、文档日期取内容修改时间，相同内容的文件包逐字节一致，便于比对导出结果
+	Reproducible bool
+}
+
+// Bundle 生成分享的独立文件包（zip）：正文（index.md 或 index.html）连同分享资源、绘图（SVG 与原始场景）
+// 与参考文献一起打包，链接改写为包内相对路径，便于归档或迁移到其他平台；当前版本的签名与被签名的源文放在 signature/ 目录。
+// 无法打包的内容记录在 WARNINGS.txt 中
+func Bundle(ctx context.Context, share *models.Share, format string, info BundleInfo) ([]byte, string, error) {
+	if !BundleFormats[format] {
+		return nil, "", errors.New("unsupported bundle format: " + format)
+	}
+	if storage.Default == nil {
+		return nil, "", errors.New("storage not initialized")
+	}
+
+	var warnings []string
+	var files []bundleFile
+
+	// 分享资源按原路径打包，正文中的相对引用无需改写即可使用
+	var assets []models.Asset
+	if err := models.DB.Where("share_id = ?", share.ID).Order("path").Find(&assets).Error; err != nil {
+		return nil, "", err
+	}
+	packed := map[string]bool{}
+	var total int64
+	for i := range assets {
+		a := &assets[i]
+		switch {
+		case len(packed) >= maxBundleAssets:
+			warnings = append(warnings, a.Path+": too many assets")
+			continue
+		case a.Size > maxBundleAssetBytes, total+a.Size > maxBundleBytes:
+			warnings = append(warnings, a.Path+": file too large")
+			continue
+		}
+		data, err := readAsset(ctx, a, maxBundleAssetBytes)
+		if err != nil {
+			warnings = append(warnings, a.Path+": "+err.Error())
+			continue
+		}
+		total += int64(len(data))
+		packed[a.Path] = true
+		files = append(files, bundleFile{name: a.Path, data: data})
+	}
+
+	drawings := map[string]bool{}
+	for _, d := range share.DrawingList() {
+		files = append(files, bundleFile{name: "drawings/" + d.ID + "." + d.Format, data: d.Scene})
+		scene, err := drawing.Parse(d.Scene)
+		if err != nil {
+			warnings = append(warnings, "drawing "+d.ID+": "+err.Error())
+			continue
+		}
+		drawings[d.ID] = true
+		files = append(files, bundleFile{name: "drawings/" + d.ID + ".svg", data: drawing.SVG(scene)})
+	}
+
+	link := func(dest string) string {
+		if id, ok := strings.CutPrefix(dest, "drawing:"); ok {
+			if drawings[id] {
+				return "drawings/" + id + ".svg"
+			}
+			return dest
+		}
+		if strings.Contains(dest, "://") {
+			return dest
+		}
+		if p := shareAssetPath(share.ID, dest); p != "" && packed[p] {
+			return (&url.URL{Path: p}).String()
+		}
+		return dest
+	}
+
+	if share.Citations != "" {
+		items, err := citation.Parse([]byte(share.Citations))
+		if err != nil {
+			warnings = append(warnings, "citations: "+err.Error())
+		}
+		if cited := citation.Cited(share.Content, items); len(cited) > 0 {
+			files = append(files, bundleFile{name: "references.bib", data: []byte(citation.BibTeX(cited))})
+		}
+	}
+
+	date := docDate(share, info.Reproducible)
+	var main bundleFile
+	switch format {
+	case BundleMarkdown:
+		main = bundleFile{name: "index.md", data: []byte(frontMatter(share.DocTitle, info, date) + rewriteLinks(share.Content, link))}
+	case BundleHTML:
+		main = bundleFile{name: "index.html", data: []byte(renderHTML(&htmlDoc{
+			Title:   share.DocTitle,
+			Author:  info.Author,
+			Date:    date,
+			Source:  info.URL,
+			Content: share.Content,
+			Link:    link,
+		}))}
+	}
+	files = append([]bundleFile{main}, files...)
+	if sig, warn := signatureFiles(share); warn != "" {
+		warnings = append(warnings, warn)
+	} else {
+		files = append(files, sig...)
+	}
+	if len(warnings) > 0 {
+		files = append(files, bundleFile{name: "WARNINGS.txt", data: []byte(strings.Join(warnings, "\n") + "\n")})
+	}
+
+	data, err := writeZip(files, info.Reproducible)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, slug.FileName(share.DocTitle, share.ID) + "-" + format + ".zip", nil
+}
+
+// MarkdownDoc 以 Markdown 原文提供的分享
+type MarkdownDoc struct {
+	Title   string
+	Date    string
+	Content string
+	Info    BundleInfo
+	Link    func(dest string) string // 改写正文中的链接地址，为 nil 时保持原样
+}
+
+// Markdown 生成带 YAML front matter 的 Markdown 原文，供分享链接以 text/markdown 返回
+func Markdown(doc MarkdownDoc) string {
+	content := doc.Content
+	if doc.Link != nil {
+		content = rewriteLinks(content, doc.Link)
+	}
+	return frontMatter(doc.Title, doc.Info, doc.Date) + content
+}
+
+// frontMatter 生成 Markdown 文件的 YAML front matter，便于导入静态站点生成器
+func frontMatter(title string, info BundleInfo, date string) string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "title: %q\n", title)
+	if info.Author != "" {
+		fmt.Fprintf(&b, "author: %q\n", info.Author)
+	}
+	fmt.Fprintf(&b, "date: %s\n", date)
+	if info.URL != "" {
+		fmt.Fprintf(&b, "source: %q\n", info.URL)
+	}
+	b.WriteString("---\n\n")
+	return b.String()
+}
+
+// rewriteLinks 改写 Markdown 链接、图片与内联 HTML 的 src/href 地址，代码块中的内容保持不变
+func rewriteLinks(content string, link func(string) string) string {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	inFence := false
+	for i, line := range lines {
+		if isFence(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.ContainsAny(line, "(=") {
+			continue
+		}
+		line = mdLinkDest.ReplaceAllStringFunc(line, func(m string) string {
+			sub := mdLinkDest.FindStringSubmatch(m)
+			return sub[1] + link(sub[2])
+		})
+		lines[i] = htmlLinkAttr.ReplaceAllStringFunc(line, func(m string) string {
+			sub := htmlLinkAttr.FindStringSubmatch(m)
+			return sub[1] + link(sub[2])
+		})
+	}
+	return strings.Join(lines, "\n")
+}
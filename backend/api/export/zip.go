@@ -0,0 +1,50 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"sort"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// reproducibleTime 可复现导出中压缩包条目的修改时间，取 zip 格式能表示的最早时间
+var reproducibleTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// writeZip 将文件写入 zip：第一个文件（正文）在前，其余按文件名排序，保证相同内容得到相同的条目顺序。
+// reproducible 时条目修改时间固定，相同内容的导出逐字节一致，否则为当前时间
+func writeZip(files []bundleFile, reproducible bool) ([]byte, error) {
+	if len(files) > 1 {
+		rest := files[1:]
+		sort.SliceStable(rest, func(i, j int) bool { return rest[i].name < rest[j].name })
+	}
+	modified := time.Now()
+	if reproducible {
+		modified = reproducibleTime
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// docDate 导出文档中的日期：可复现导出取内容最近一次变化的时间，内容未变的重新发布不改变导出结果；
+// 否则取分享的更新时间
+func docDate(share *models.Share, reproducible bool) string {
+	if reproducible && !share.ContentModified.IsZero() {
+		return share.ContentModified.Format("2006-01-02")
+	}
+	return share.UpdatedAt.Format("2006-01-02")
+}
@@ -0,0 +1,148 @@
+// Package geoip 识别读者所在的国家/地区：优先读取反向代理或 CDN 提供的请求头（如 Cloudflare 的 CF-IPCountry），
+// 否则在本地 IP 段数据库中查找客户端 IP。数据库为 CSV 文件，每行 "起始IP,结束IP,国家代码" 或 "CIDR,国家代码"，
+// 可直接使用 DB-IP Lite、ip-location-db 等公开的国家级数据。
+package geoip
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+type ipRange struct {
+	start, end netip.Addr
+	country    string
+}
+
+// database 按起始地址排序的 IP 段
+type database []ipRange
+
+var db atomic.Pointer[database]
+
+// Init 加载配置 geo.database 指定的 IP 段数据库，重新加载配置后再次调用时替换；加载失败时保留原有数据库
+func Init() error {
+	path := config.Get().Geo.Database
+	if path == "" {
+		db.Store(nil)
+		return nil
+	}
+	d, err := load(path)
+	if err != nil {
+		return err
+	}
+	db.Store(&d)
+	return nil
+}
+
+func load(path string) (database, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var d database
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 64<<10)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, ",")
+		for i := range fields {
+			fields[i] = strings.Trim(strings.TrimSpace(fields[i]), `"`)
+		}
+		r, ok := parseRange(fields)
+		if !ok {
+			// 表头或无法识别的行
+			if line == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("%s:%d: invalid entry %q", path, line, text)
+		}
+		if r.country == "" || r.country == "ZZ" || r.country == "-" {
+			continue
+		}
+		d = append(d, r)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(d, func(i, j int) bool { return d[i].start.Less(d[j].start) })
+	return d, nil
+}
+
+func parseRange(fields []string) (ipRange, bool) {
+	switch {
+	case len(fields) == 2 && strings.Contains(fields[0], "/"):
+		prefix, err := netip.ParsePrefix(fields[0])
+		if err != nil {
+			return ipRange{}, false
+		}
+		prefix = prefix.Masked()
+		return ipRange{start: prefix.Addr(), end: lastAddr(prefix), country: strings.ToUpper(fields[1])}, true
+	case len(fields) >= 3:
+		start, err1 := netip.ParseAddr(fields[0])
+		end, err2 := netip.ParseAddr(fields[1])
+		if err1 != nil || err2 != nil {
+			return ipRange{}, false
+		}
+		start, end = start.Unmap(), end.Unmap()
+		if start.BitLen() != end.BitLen() || end.Less(start) {
+			return ipRange{}, false
+		}
+		return ipRange{start: start, end: end, country: strings.ToUpper(fields[2])}, true
+	}
+	return ipRange{}, false
+}
+
+// lastAddr 返回网段中的最后一个地址
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Addr().AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - i%8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// Lookup 在 IP 段数据库中查找地址所属国家，未加载数据库或未命中时返回空字符串
+func Lookup(ip string) string {
+	d := db.Load()
+	if d == nil {
+		return ""
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+	ranges := *d
+	i := sort.Search(len(ranges), func(i int) bool { return addr.Less(ranges[i].start) }) - 1
+	if i < 0 || ranges[i].end.Less(addr) || ranges[i].start.BitLen() != addr.BitLen() {
+		return ""
+	}
+	return ranges[i].country
+}
+
+// Country 返回请求来源的国家代码（ISO 3166-1 alpha-2，大写），无法识别时返回空字符串
+func Country(r *http.Request, clientIP string) string {
+	if h := config.Get().Geo.Header; h != "" {
+		code := strings.ToUpper(strings.TrimSpace(r.Header.Get(h)))
+		// Cloudflare 以 XX 表示未知、T1 表示 Tor 出口
+		if code != "" && code != "XX" {
+			return code
+		}
+	}
+	return Lookup(clientIP)
+}
@@ -0,0 +1,247 @@
+// Package hooks 服务端扩展点：在发布前后、阅读页渲染前与登录时触发事件。运营者可以在 Go 代码中
+// 注册 Handler，或在配置 hooks 中登记外部 HTTP 钩子与沙箱中运行的 Lua 脚本，无需修改核心代码即可接入自定义逻辑
+// （如同步到内部 Wiki、发布前内容审查、限制登录）。
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// 事件类型
+const (
+	PrePublish  = "pre_publish"  // 发布前（同步）：可拒绝发布或改写标题与正文
+	PostPublish = "post_publish" // 发布后（异步）：结果不影响发布
+	PreRender   = "pre_render"   // 阅读页渲染正文前（同步）：可改写正文，不能拒绝访问
+	Auth        = "auth"         // 登录签发会话前（同步）：可拒绝登录
+	Alert       = "alert"        // 触发异常告警（异步）：可转发到 IM、值班系统
+)
+
+// Events 支持的事件类型
+var Events = []string{PrePublish, PostPublish, PreRender, Auth, Alert}
+
+// Share 事件中的分享信息
+type Share struct {
+	ID      string   `json:"id"`
+	DocID   string   `json:"docId"`
+	Title   string   `json:"title"`
+	Content string   `json:"content"`
+	URL     string   `json:"url"`
+	Status  string   `json:"status"`
+	Tags    []string `json:"tags"`
+	Reused  bool     `json:"reused"` // 是否为重新发布已有分享
+}
+
+// User 事件中的用户信息
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email,omitempty"`
+}
+
+// AlertInfo alert 事件中的告警信息
+type AlertInfo struct {
+	Kind      string `json:"kind"` // share_views / server_errors / auth_failures / share_reports
+	Message   string `json:"message"`
+	Count     int    `json:"count"` // 一分钟内的次数
+	Threshold int    `json:"threshold"`
+}
+
+// Event 钩子事件
+type Event struct {
+	Type   string     `json:"event"`
+	Time   time.Time  `json:"time"`
+	User   *User      `json:"user,omitempty"`
+	Share  *Share     `json:"share,omitempty"`
+	Alert  *AlertInfo `json:"alert,omitempty"`
+	Method string     `json:"method,omitempty"` // auth：登录方式（password、oidc:<provider>）
+	IP     string     `json:"ip,omitempty"`
+}
+
+// Result 钩子返回值，nil 表示放行且不做修改
+type Result struct {
+	Deny    bool      `json:"deny"`    // 拒绝本次操作（pre_publish、auth）
+	Message string    `json:"message"` // 拒绝原因，返回给客户端
+	Title   *string   `json:"title"`   // 改写后的标题（pre_publish）
+	Content *string   `json:"content"` // 改写后的正文（pre_publish、pre_render）
+	Tags    *[]string `json:"tags"`    // 替换分享标签（pre_publish）
+}
+
+// Handler 钩子处理器
+type Handler interface {
+	Handle(ctx context.Context, ev *Event) (*Result, error)
+}
+
+// HandlerFunc 以函数实现 Handler
+type HandlerFunc func(ctx context.Context, ev *Event) (*Result, error)
+
+// Handle 调用 f
+func (f HandlerFunc) Handle(ctx context.Context, ev *Event) (*Result, error) {
+	return f(ctx, ev)
+}
+
+// Hook 注册到若干事件的钩子
+type Hook struct {
+	Name     string
+	Events   []string
+	Timeout  time.Duration // 单次调用超时
+	FailOpen bool          // 同步事件中钩子出错时是否继续操作，默认拒绝
+	Handler  Handler
+}
+
+// DeniedError 钩子拒绝了操作
+type DeniedError struct {
+	Hook    string
+	Message string
+}
+
+func (e *DeniedError) Error() string {
+	if e.Message == "" {
+		return "denied by hook " + e.Hook
+	}
+	return e.Message
+}
+
+// defaultTimeout 未设置超时的钩子使用的超时
+const defaultTimeout = 5 * time.Second
+
+var registry = struct {
+	sync.RWMutex
+	hooks map[string][]*Hook
+}{hooks: map[string][]*Hook{}}
+
+// Register 注册钩子，同一事件的钩子按注册顺序依次调用
+func Register(h *Hook) {
+	registry.Lock()
+	defer registry.Unlock()
+	add(registry.hooks, h)
+}
+
+// add 将钩子加入按事件索引的表
+func add(hooks map[string][]*Hook, h *Hook) {
+	if h.Timeout <= 0 {
+		h.Timeout = defaultTimeout
+	}
+	for _, ev := range h.Events {
+		hooks[ev] = append(hooks[ev], h)
+	}
+}
+
+// Has 是否有钩子订阅了该事件，调用方可据此跳过构造事件的开销
+func Has(event string) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+	return len(registry.hooks[event]) > 0
+}
+
+func lookup(event string) []*Hook {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.hooks[event]
+}
+
+// Init 按配置 hooks 注册 HTTP 钩子与脚本钩子，替换已注册的钩子；任一钩子无效时保留原有钩子
+func Init() error {
+	hooks := map[string][]*Hook{}
+	for _, hc := range config.Get().Hooks {
+		h := &Hook{
+			Name:     hc.Name,
+			Events:   hc.Events,
+			Timeout:  hc.Timeout.Std(),
+			FailOpen: hc.FailOpen,
+		}
+		switch {
+		case hc.Script != "":
+			script, err := LoadScript(hc.Name, hc.Script, hc.MaxMemory)
+			if err != nil {
+				return fmt.Errorf("hook %s: %w", hc.Name, err)
+			}
+			h.Handler = script
+		case hc.URL != "":
+			h.Handler = &HTTP{URL: hc.URL, Secret: hc.Secret}
+		default:
+			return fmt.Errorf("hook %s: url or script is required", hc.Name)
+		}
+		add(hooks, h)
+	}
+	registry.Lock()
+	registry.hooks = hooks
+	registry.Unlock()
+	return nil
+}
+
+// Run 依次调用同步事件的钩子：钩子改写的标题与正文写回 ev.Share 并传给下一个钩子；
+// 任一钩子拒绝时返回 *DeniedError，出错且未设置 fail_open 时返回该错误
+func Run(ctx context.Context, ev *Event) error {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	for _, h := range lookup(ev.Type) {
+		res, err := call(ctx, h, ev)
+		if err != nil {
+			if h.FailOpen {
+				log.Printf("Hook %s (%s) failed, continuing: %v", h.Name, ev.Type, err)
+				continue
+			}
+			return fmt.Errorf("hook %s: %w", h.Name, err)
+		}
+		if res == nil {
+			continue
+		}
+		if res.Deny {
+			return &DeniedError{Hook: h.Name, Message: res.Message}
+		}
+		if ev.Share != nil {
+			if res.Title != nil {
+				ev.Share.Title = *res.Title
+			}
+			if res.Content != nil {
+				ev.Share.Content = *res.Content
+			}
+			if res.Tags != nil {
+				ev.Share.Tags = *res.Tags
+			}
+		}
+	}
+	return nil
+}
+
+// Fire 在后台调用异步事件的钩子，返回值只记录日志；HTTP 钩子投递失败时加入重试队列
+func Fire(ev *Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	for _, h := range lookup(ev.Type) {
+		h := h
+		background.Go(func() {
+			if _, err := call(context.Background(), h, ev); err != nil {
+				log.Printf("Hook %s (%s) failed: %v", h.Name, ev.Type, err)
+				enqueueRetry(h, ev, err)
+			}
+		})
+	}
+}
+
+// IsDenied 判断错误是否为钩子拒绝
+func IsDenied(err error) (*DeniedError, bool) {
+	var denied *DeniedError
+	ok := errors.As(err, &denied)
+	return denied, ok
+}
+
+func call(ctx context.Context, h *Hook, ev *Event) (*Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+	res, err := h.Handler.Handle(ctx, ev)
+	if err != nil && ctx.Err() != nil {
+		return nil, fmt.Errorf("timed out after %s", h.Timeout)
+	}
+	return res, err
+}
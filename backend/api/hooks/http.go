@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxResponseBytes HTTP 钩子响应体大小上限（可能包含改写后的正文）
+const maxResponseBytes = 32 << 20
+
+// HTTP 外部 HTTP 钩子：以 POST 发送 JSON 事件，配置 secret 时在 X-Hook-Signature 中附带
+// 请求体的 HMAC-SHA256 签名（sha256=<hex>）。2xx 响应表示成功，响应体为空或为 Result JSON
+type HTTP struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// Handle 把事件发送给钩子服务
+func (h *HTTP) Handle(ctx context.Context, ev *Event) (*Result, error) {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Hook-Event", ev.Type)
+	if h.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(h.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Hook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("hook service returned %s: %s", resp.Status, msg)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	var res Result
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, fmt.Errorf("invalid hook response: %w", err)
+	}
+	return &res, nil
+}
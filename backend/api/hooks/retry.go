@@ -0,0 +1,118 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// retryBatch 每次重试的事件数上限
+const retryBatch = 100
+
+// maxBackoff 两次重试之间的最长间隔
+const maxBackoff = 6 * time.Hour
+
+// backoff 第 attempts 次投递失败后的等待时间：1m、2m、4m……，最长 maxBackoff
+func backoff(attempts int) time.Duration {
+	d := time.Minute
+	for i := 1; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d
+}
+
+func truncateError(err error) string {
+	msg := err.Error()
+	if len(msg) > 500 {
+		msg = msg[:500]
+	}
+	return msg
+}
+
+// enqueueRetry 记录投递失败的异步 HTTP 钩子事件，由 hook_retries 定时任务重试；脚本钩子的失败重试也不会改变结果，不记录
+func enqueueRetry(h *Hook, ev *Event, err error) {
+	if _, ok := h.Handler.(*HTTP); !ok {
+		return
+	}
+	cfg := config.Get().Jobs
+	if !cfg.HookRetries.Enabled || cfg.HookMaxAttempts <= 1 || models.DB == nil {
+		return
+	}
+	payload, merr := json.Marshal(ev)
+	if merr != nil {
+		return
+	}
+	d := &models.HookDelivery{
+		Hook:        h.Name,
+		Event:       ev.Type,
+		Payload:     string(payload),
+		Attempts:    1,
+		LastError:   truncateError(err),
+		NextAttempt: time.Now().Add(backoff(1)),
+	}
+	if err := models.DB.Create(d).Error; err != nil {
+		log.Printf("Hook %s (%s) retry not queued: %v", h.Name, ev.Type, err)
+	}
+}
+
+// find 按名称查找订阅了该事件的钩子
+func find(name, event string) *Hook {
+	for _, h := range lookup(event) {
+		if h.Name == name {
+			return h
+		}
+	}
+	return nil
+}
+
+// RetryDeliveries 重新投递到期的异步钩子事件：成功或达到 jobs.hook_max_attempts 次后移出队列，
+// 其余按指数退避安排下一次重试。返回投递成功与放弃的数量
+func RetryDeliveries(ctx context.Context) (sent, dropped int, err error) {
+	due, err := models.DueHookDeliveries(retryBatch)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxAttempts := config.Get().Jobs.HookMaxAttempts
+	for i := range due {
+		if ctx.Err() != nil {
+			return sent, dropped, ctx.Err()
+		}
+		d := &due[i]
+		var ev Event
+		h := find(d.Hook, d.Event)
+		if h == nil || json.Unmarshal([]byte(d.Payload), &ev) != nil {
+			// 钩子已从配置中移除或不再订阅该事件
+			models.DB.Delete(d)
+			dropped++
+			continue
+		}
+		_, callErr := call(ctx, h, &ev)
+		if callErr == nil {
+			models.DB.Delete(d)
+			sent++
+			continue
+		}
+		d.Attempts++
+		if d.Attempts >= maxAttempts {
+			log.Printf("Hook %s (%s) dropped after %d attempts: %v", d.Hook, d.Event, d.Attempts, callErr)
+			models.DB.Delete(d)
+			dropped++
+			continue
+		}
+		if err := models.DB.Model(d).Updates(map[string]interface{}{
+			"attempts":     d.Attempts,
+			"last_error":   truncateError(callErr),
+			"next_attempt": time.Now().Add(backoff(d.Attempts)),
+		}).Error; err != nil {
+			return sent, dropped, err
+		}
+	}
+	return sent, dropped, nil
+}
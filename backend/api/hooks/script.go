@@ -0,0 +1,286 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/metrics"
+	"strings"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// ErrScriptMemory 脚本运行期间内存增长超过上限
+var ErrScriptMemory = errors.New("script exceeded memory limit")
+
+const (
+	// defaultScriptMemory 未设置 max_memory 时脚本可使用的内存
+	defaultScriptMemory = 32 << 20
+	// scriptCallStack 脚本调用栈深度上限，防止无限递归
+	scriptCallStack = 200
+	// scriptRegistryMax 脚本值栈（寄存器）上限
+	scriptRegistryMax = 256 * 1024
+)
+
+// Script Lua 脚本钩子：脚本中与事件同名的全局函数（如 pre_publish(event)）在事件发生时被调用，
+// 返回的表与 HTTP 钩子的响应含义相同。每次调用使用独立的虚拟机，仅开放 base、string、table、math
+// 与 os.time/os.date/os.clock，不能访问文件、网络或加载模块
+type Script struct {
+	Name      string
+	MaxMemory int64 // 单次运行的内存上限（字节），按运行期间进程堆内存的增长估算
+	proto     *lua.FunctionProto
+}
+
+// LoadScript 读取并编译脚本文件，语法错误在启动时即可发现
+func LoadScript(name, path string, maxMemory int64) (*Script, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	chunk, err := parse.Parse(strings.NewReader(string(src)), path)
+	if err != nil {
+		return nil, err
+	}
+	proto, err := lua.Compile(chunk, path)
+	if err != nil {
+		return nil, err
+	}
+	if maxMemory <= 0 {
+		maxMemory = defaultScriptMemory
+	}
+	return &Script{Name: name, MaxMemory: maxMemory, proto: proto}, nil
+}
+
+// Handle 在沙箱中执行脚本并调用与事件同名的函数，脚本未定义该函数时放行
+func (s *Script) Handle(ctx context.Context, ev *Event) (*Result, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := watchMemory(s.MaxMemory, func() { cancel(ErrScriptMemory) })
+	defer stop()
+
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:        true,
+		CallStackSize:       scriptCallStack,
+		RegistryMaxSize:     scriptRegistryMax,
+		MinimizeStackMemory: true,
+	})
+	defer L.Close()
+	L.SetContext(ctx)
+	s.openLibs(L)
+
+	res, err := s.run(L, ev)
+	if cause := context.Cause(ctx); cause != nil && ctx.Err() != nil {
+		return nil, cause
+	}
+	return res, err
+}
+
+func (s *Script) run(L *lua.LState, ev *Event) (*Result, error) {
+	L.Push(L.NewFunctionFromProto(s.proto))
+	if err := L.PCall(0, lua.MultRet, nil); err != nil {
+		return nil, scriptError(err)
+	}
+	fn, ok := L.GetGlobal(ev.Type).(*lua.LFunction)
+	if !ok {
+		return nil, nil
+	}
+	arg, err := toLua(L, ev)
+	if err != nil {
+		return nil, err
+	}
+	L.Push(fn)
+	L.Push(arg)
+	if err := L.PCall(1, 1, nil); err != nil {
+		return nil, scriptError(err)
+	}
+	ret := L.Get(-1)
+	L.Pop(1)
+	return fromLua(ret)
+}
+
+// openLibs 打开沙箱可用的标准库，移除可访问文件系统或加载模块的函数
+func (s *Script) openLibs(L *lua.LState) {
+	for _, lib := range []struct {
+		name string
+		fn   lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+		{lua.OsLibName, lua.OpenOs},
+	} {
+		L.Push(L.NewFunction(lib.fn))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	for _, name := range []string{"dofile", "loadfile", "require", "module", "collectgarbage", "_printregs"} {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	// os 只保留时间函数
+	osMod := L.NewTable()
+	if mod, ok := L.GetGlobal(lua.OsLibName).(*lua.LTable); ok {
+		for _, name := range []string{"time", "date", "clock"} {
+			osMod.RawSetString(name, mod.RawGetString(name))
+		}
+	}
+	L.SetGlobal(lua.OsLibName, osMod)
+
+	// string.rep 先检查结果大小，避免一次分配就超出内存上限
+	if mod, ok := L.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		rep := mod.RawGetString("rep")
+		mod.RawSetString("rep", L.NewFunction(func(L *lua.LState) int {
+			if int64(len(L.CheckString(1)))*int64(L.CheckInt(2)) > s.MaxMemory/4 {
+				L.RaiseError("string.rep: result too large")
+			}
+			L.Push(rep)
+			L.Push(L.Get(1))
+			L.Push(L.Get(2))
+			L.Call(2, 1)
+			return 1
+		}))
+	}
+
+	logFn := L.NewFunction(func(L *lua.LState) int {
+		parts := make([]string, L.GetTop())
+		for i := range parts {
+			parts[i] = L.ToStringMeta(L.Get(i + 1)).String()
+		}
+		log.Printf("Hook script %s: %s", s.Name, strings.Join(parts, " "))
+		return 0
+	})
+	L.SetGlobal("log", logFn)
+	L.SetGlobal("print", logFn)
+}
+
+func scriptError(err error) error {
+	var apiErr *lua.ApiError
+	if errors.As(err, &apiErr) && apiErr.Object != nil {
+		msg := apiErr.Object.String()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		return errors.New(msg)
+	}
+	return err
+}
+
+// toLua 将事件转换为 Lua 表（字段名与 HTTP 钩子的 JSON 一致）
+func toLua(L *lua.LState, ev *Event) (lua.LValue, error) {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return goToLua(L, v), nil
+}
+
+func goToLua(L *lua.LState, v interface{}) lua.LValue {
+	switch v := v.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []interface{}:
+		t := L.CreateTable(len(v), 0)
+		for _, item := range v {
+			t.Append(goToLua(L, item))
+		}
+		return t
+	case map[string]interface{}:
+		t := L.CreateTable(0, len(v))
+		for k, item := range v {
+			t.RawSetString(k, goToLua(L, item))
+		}
+		return t
+	}
+	return lua.LNil
+}
+
+// fromLua 解析脚本返回值：nil 表示放行，false 表示拒绝，表的字段与 Result 相同
+func fromLua(v lua.LValue) (*Result, error) {
+	switch v := v.(type) {
+	case *lua.LNilType:
+		return nil, nil
+	case lua.LBool:
+		if v {
+			return nil, nil
+		}
+		return &Result{Deny: true}, nil
+	case *lua.LTable:
+		res := &Result{
+			Deny:    lua.LVAsBool(v.RawGetString("deny")),
+			Message: luaString(v.RawGetString("message")),
+		}
+		if s, ok := v.RawGetString("title").(lua.LString); ok {
+			title := string(s)
+			res.Title = &title
+		}
+		if s, ok := v.RawGetString("content").(lua.LString); ok {
+			content := string(s)
+			res.Content = &content
+		}
+		if t, ok := v.RawGetString("tags").(*lua.LTable); ok {
+			tags := []string{}
+			t.ForEach(func(_, item lua.LValue) {
+				if s, ok := item.(lua.LString); ok {
+					tags = append(tags, string(s))
+				}
+			})
+			res.Tags = &tags
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("script returned %s, expected table, boolean or nil", v.Type())
+}
+
+func luaString(v lua.LValue) string {
+	if s, ok := v.(lua.LString); ok {
+		return string(s)
+	}
+	return ""
+}
+
+// watchMemory 定期采样进程堆内存，运行期间增长超过 limit 时调用 exceeded；返回停止采样的函数。
+// Go 无法统计单个 goroutine 的内存，同时运行的请求也会计入，因此这是偏保守的近似上限
+func watchMemory(limit int64, exceeded func()) func() {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	read := func() int64 {
+		metrics.Read(sample)
+		if sample[0].Value.Kind() != metrics.KindUint64 {
+			return 0
+		}
+		return int64(sample[0].Value.Uint64())
+	}
+	base := read()
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(5 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if read()-base > limit {
+					exceeded()
+					return
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
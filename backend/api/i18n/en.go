@@ -0,0 +1,75 @@
+package i18n
+
+// en 英文文案；接口错误信息以英文原文为键，无需登记
+var en = map[string]string{
+	// 浏览器中打开的提示页面
+	"page.title":                  "SiYuan Share",
+	"page.description":            "Read and manage documents shared from SiYuan",
+	"page.link_invalid":           "Invalid link",
+	"page.subscribe_failed":       "Failed to confirm the subscription, please try again later",
+	"page.subscription_not_found": "Subscription not found or already cancelled",
+	"page.subscribed":             "Subscribed. You will be notified when the note is updated",
+	"page.unsubscribe_failed":     "Failed to unsubscribe, please try again later",
+	"page.unsubscribed":           "Unsubscribed. You will no longer receive updates for this note",
+	"page.verify_invalid":         "The verification link is invalid or has expired",
+	"page.verify_failed":          "Email verification failed, please try again later",
+	"page.verified":               "Your email has been verified",
+	"page.unlock_invalid":         "The unlock link is invalid or has expired",
+	"page.unlock_failed":          "Failed to unlock the account, please try again later",
+	"page.unlocked":               "Your account has been unlocked, please sign in again",
+	"page.share_password":         "Password required",
+	"page.share_expired":          "This share has expired",
+	"page.share_not_open":         "This share is not open yet",
+	"page.share_encrypted":        "Encrypted share",
+	"page.full_version":           "Full version: ",
+	"page.embed_disabled":         "Embedding is disabled on this site",
+	"page.embed_private":          "This note requires verification before reading",
+	"page.embed_open":             "Open the note",
+	"page.geo_blocked.title":      "Content unavailable",
+	"page.geo_blocked.heading":    "This content is not available in your region",
+	"page.geo_blocked.body":       "Due to requirements where this site is operated, shared content cannot be accessed from your country or region.",
+
+	// 邮件
+	"email.verify.subject":            "Verify your email",
+	"email.verify.body":               "Hi %s,\n\nPlease click the link below to verify your email (valid for %d hours):\n%s\n\nIf you did not sign up, please ignore this email.\n",
+	"email.reset.subject":             "Reset your password",
+	"email.reset.body":                "Hi %s,\n\nWe received a request to reset your password. Click the link below to set a new one (valid for %d minutes, single use):\n%s\n\nIf you did not request this, please ignore this email and your password will stay the same.\n",
+	"email.unlock.subject":            "Your account has been temporarily locked",
+	"email.unlock.body":               "Hi %s,\n\nYour account has been temporarily locked until %s after repeated failed sign-in attempts (the latest from %s).\n\nIf this was you, click the link below to unlock it now:\n%s\n\nIf this was not you, someone is trying to sign in to your account. We recommend changing your password and enabling two-factor authentication.\n",
+	"email.share_access.subject":      "Access to \"%s\"",
+	"email.share_access.body":         "You have been invited to read the note \"%s\".\n\nClick the link below to open it (valid for %d minutes):\n%s\n\nIf you did not request this, please ignore this email.\n",
+	"email.subscribe_confirm.subject": "Confirm your subscription to \"%s\"",
+	"email.subscribe_confirm.body":    "You are subscribing to updates of the note \"%s\".\n\nConfirm the subscription: %s\n\nIf you did not request this, please ignore this email.\n",
+	"email.share_updated.subject":     "\"%s\" has been updated",
+	"email.share_updated.body":        "The note \"%s\" you subscribed to has new updates.\n\nView: %s\n\nDon't want these notifications? Unsubscribe: %s\n",
+	"email.alert.subject":             "[SiYuan Share] Alert: %s",
+	"email.owner_first_view.subject":  "\"%s\" has its first reader",
+	"email.owner_first_view.body":     "Your share \"%s\" was just viewed for the first time.\n\nView: %s\n",
+	"email.owner_views.subject":       "\"%s\" reached %d views",
+	"email.owner_views.body":          "Your share \"%s\" has now been viewed %d times.\n\nView: %s\n",
+	"email.owner_comment.subject":     "New comment on \"%s\"",
+	"email.owner_comment.body":        "%s commented on your share \"%s\"%s:\n\n%s\n\nView: %s\n",
+	"email.owner_comment.pending":     " (awaiting moderation)",
+	"email.owner_expiring.subject":    "%d of your shares will expire soon",
+	"email.owner_expiring.body":       "The following shares will expire within %d days. Extend them from the share list if they should stay online:\n\n%s\nManage shares: %s\n",
+	"email.quota_warning.subject":     "Your SiYuan Share usage is approaching its quota",
+	"email.quota_warning.body":        "Your account usage has reached a quota warning level:\n\n%s\n\nDelete shares or assets you no longer need, or ask the administrator to raise your quota. Usage details: %s\n",
+	"email.owner.footer":              "\n--\nYou received this email because of your notification settings. Change them: %s\n",
+
+	// 异常告警
+	"alert.title":         "Alert",
+	"alert.share_views":   "Share \"%s\" was viewed %d times within a minute, it may be hotlinked or botted",
+	"alert.server_errors": "%d server errors (5xx) within a minute, please check the server logs",
+	"alert.auth_failures": "%d failed sign-ins within a minute (the latest from %s), a brute-force attack may be in progress",
+	"alert.share_reports": "Share \"%s\" was reported by a reader, please review it in the moderation queue",
+
+	// 配额提醒
+	"quota.title":             "Quota warning",
+	"quota.storage":           "Storage",
+	"quota.shares":            "Shares",
+	"quota.bandwidth":         "Reader traffic this month",
+	"quota.warning":           "%s: %d%% used (%s / %s)",
+	"quota.grace":             "%s: over quota at %d%% (%s / %s), publishing is still allowed up to %s",
+	"quota.blocked":           "%s: limit reached (%s / %s), new publishes and uploads are rejected",
+	"quota.blocked_bandwidth": "%s: limit reached (%s / %s), readers see placeholders instead of assets until next month",
+}
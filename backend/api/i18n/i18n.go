@@ -0,0 +1,111 @@
+// Package i18n 服务端文案本地化：接口错误信息、浏览器中打开的提示页面与邮件模板。
+// 接口错误信息以英文原文为键，页面与邮件文案以 page.* / email.* 为键；
+// 语言按用户偏好、Accept-Language 请求头与 server.locale 配置依次确定
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// 支持的语言
+const (
+	ZhCN = "zh-CN"
+	En   = "en"
+)
+
+// Supported 支持的语言列表
+var Supported = []string{ZhCN, En}
+
+var catalogs = map[string]map[string]string{
+	ZhCN: zhCN,
+	En:   en,
+}
+
+// Match 将语言标签（如 zh、zh-Hans-CN、en-US）匹配为支持的语言，无法匹配时返回空字符串
+func Match(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	switch {
+	case tag == "":
+		return ""
+	case tag == "zh" || strings.HasPrefix(tag, "zh-"):
+		return ZhCN
+	case tag == "en" || strings.HasPrefix(tag, "en-"):
+		return En
+	}
+	return ""
+}
+
+// Default 默认语言（server.locale）
+func Default() string {
+	if l := Match(config.Get().Server.Locale); l != "" {
+		return l
+	}
+	return ZhCN
+}
+
+// Negotiate 按 Accept-Language 请求头的权重选择支持的语言，均不支持时返回默认语言
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if l := Match(tag); l != "" && q > 0 {
+			candidates = append(candidates, candidate{l, q})
+		}
+	}
+	if len(candidates) == 0 {
+		return Default()
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].locale
+}
+
+// catalog 返回语言的文案表，不支持的语言（包括空字符串）使用默认语言
+func catalog(locale string) map[string]string {
+	if c, ok := catalogs[locale]; ok {
+		return c
+	}
+	return catalogs[Default()]
+}
+
+// T 返回 key 在指定语言下的文案，有参数时按 fmt 格式化；缺少译文时依次使用英文与 key 本身
+func T(locale, key string, args ...any) string {
+	s, ok := catalog(locale)[key]
+	if !ok {
+		if s, ok = en[key]; !ok {
+			s = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(s, args...)
+	}
+	return s
+}
+
+// Message 翻译接口错误信息：先整句匹配，否则翻译首个 ": " 之前的前缀并保留其后的错误详情
+func Message(locale, msg string) string {
+	c := catalog(locale)
+	if s, ok := c[msg]; ok {
+		return s
+	}
+	if i := strings.Index(msg, ": "); i >= 0 {
+		if s, ok := c[msg[:i+2]]; ok {
+			return s + msg[i+2:]
+		}
+	}
+	return msg
+}
@@ -0,0 +1,549 @@
+package i18n
+
+// zhCN 简体中文文案；以 ": " 结尾的键为带错误详情的接口错误信息前缀
+var zhCN = map[string]string{
+	// 浏览器中打开的提示页面
+	"page.title":                  "思源分享",
+	"page.description":            "分享思源笔记文档的阅读与管理站点",
+	"page.link_invalid":           "链接无效",
+	"page.subscribe_failed":       "订阅确认失败，请稍后重试",
+	"page.subscription_not_found": "订阅不存在或已退订",
+	"page.subscribed":             "订阅成功，笔记更新时会通知你",
+	"page.unsubscribe_failed":     "退订失败，请稍后重试",
+	"page.unsubscribed":           "已退订，不会再收到该笔记的更新通知",
+	"page.verify_invalid":         "验证链接无效或已过期",
+	"page.verify_failed":          "邮箱验证失败，请稍后重试",
+	"page.verified":               "邮箱验证成功",
+	"page.unlock_invalid":         "解锁链接无效或已过期",
+	"page.unlock_failed":          "解锁失败，请稍后重试",
+	"page.unlocked":               "账号已解锁，请重新登录",
+	"page.share_password":         "需要访问密码",
+	"page.share_expired":          "分享已过期",
+	"page.share_not_open":         "分享暂未开放",
+	"page.share_encrypted":        "加密分享",
+	"page.full_version":           "完整版：",
+	"page.embed_disabled":         "本站未开启嵌入",
+	"page.embed_private":          "此笔记需要验证后才能阅读",
+	"page.embed_open":             "在新窗口中打开",
+	"page.geo_blocked.title":      "内容不可用",
+	"page.geo_blocked.heading":    "内容在您所在的地区不可用",
+	"page.geo_blocked.body":       "根据本站运营者所在地的要求，当前国家或地区无法访问本站分享的内容。",
+
+	// 邮件
+	"email.verify.subject":            "验证你的邮箱",
+	"email.verify.body":               "你好 %s，\n\n请点击以下链接验证邮箱（%d 小时内有效）：\n%s\n\n如果不是你本人注册，请忽略此邮件。\n",
+	"email.reset.subject":             "重置密码",
+	"email.reset.body":                "你好 %s，\n\n我们收到了重置密码的请求，请点击以下链接设置新密码（%d 分钟内有效，仅可使用一次）：\n%s\n\n如果不是你本人操作，请忽略此邮件，密码不会改变。\n",
+	"email.unlock.subject":            "账号已被临时锁定",
+	"email.unlock.body":               "你好 %s，\n\n你的账号因连续登录失败已被临时锁定至 %s（最近一次尝试来自 %s）。\n\n如果是你本人操作，可点击以下链接立即解锁：\n%s\n\n如果不是你本人操作，说明有人在尝试登录你的账号，建议修改密码并启用两步验证。\n",
+	"email.share_access.subject":      "访问「%s」",
+	"email.share_access.body":         "你已被邀请阅读笔记「%s」。\n\n点击以下链接打开（%d 分钟内有效）：\n%s\n\n如果不是你本人操作，请忽略此邮件。\n",
+	"email.subscribe_confirm.subject": "确认订阅「%s」的更新",
+	"email.subscribe_confirm.body":    "你正在订阅笔记「%s」的更新通知。\n\n点击确认订阅：%s\n\n如果不是你本人操作，请忽略此邮件。\n",
+	"email.share_updated.subject":     "「%s」已更新",
+	"email.share_updated.body":        "你订阅的笔记「%s」有新的内容更新。\n\n查看：%s\n\n不想再收到此类通知？退订：%s\n",
+	"email.alert.subject":             "[SiYuan Share] 异常告警：%s",
+	"email.owner_first_view.subject":  "「%s」迎来了第一位读者",
+	"email.owner_first_view.body":     "你的分享「%s」刚刚被第一次浏览。\n\n查看：%s\n",
+	"email.owner_views.subject":       "「%s」的浏览量达到 %d 次",
+	"email.owner_views.body":          "你的分享「%s」已被浏览 %d 次。\n\n查看：%s\n",
+	"email.owner_comment.subject":     "「%s」收到新评论",
+	"email.owner_comment.body":        "%s 评论了你的分享「%s」%s：\n\n%s\n\n查看：%s\n",
+	"email.owner_comment.pending":     "（待审核）",
+	"email.owner_expiring.subject":    "你有 %d 个分享即将到期",
+	"email.owner_expiring.body":       "以下分享将在 %d 天内到期，如需继续公开请在分享列表中延长有效期：\n\n%s\n管理分享：%s\n",
+	"email.quota_warning.subject":     "配额用量提醒",
+	"email.quota_warning.body":        "你的账号用量已达到配额提醒线：\n\n%s\n\n可以删除不再需要的分享或资源文件，或联系管理员提高配额。查看用量：%s\n",
+	"email.owner.footer":              "\n--\n你收到此邮件是因为开启了相应的通知，修改通知设置：%s\n",
+
+	// 异常告警
+	"alert.title":         "异常告警",
+	"alert.share_views":   "分享「%s」一分钟内被浏览 %d 次，可能被盗链或刷量",
+	"alert.server_errors": "一分钟内出现 %d 次服务端错误（5xx），请检查服务日志",
+	"alert.auth_failures": "一分钟内登录失败 %d 次（最近一次来自 %s），可能正在遭受暴力破解",
+	"alert.share_reports": "分享「%s」被读者举报，请在举报审核中处理",
+
+	// 配额提醒
+	"quota.title":             "配额用量提醒",
+	"quota.storage":           "存储空间",
+	"quota.shares":            "分享数",
+	"quota.bandwidth":         "本月读者流量",
+	"quota.warning":           "%s：已用 %d%%（%s / %s）",
+	"quota.grace":             "%s：已超出配额，用量 %d%%（%s / %s），达到 %s 前仍可继续发布",
+	"quota.blocked":           "%s：已达上限（%s / %s），新的发布与上传将被拒绝",
+	"quota.blocked_bandwidth": "%s：已达上限（%s / %s），本月剩余时间读者看到的资源将以占位图代替",
+
+	// 接口错误信息
+	"A redirect for this path already exists":                          "该路径的重定向规则已存在",
+	"Account deletion is not scheduled":                                "账号未申请注销",
+	"Account disabled":                                                 "账号已被停用",
+	"Account has sealed shares":                                        "账号下有处于保留期内的封存分享，期满前不能注销",
+	"Account temporarily locked due to too many failed login attempts": "登录失败次数过多，账号已被临时锁定",
+	"Admin permission required":                                        "需要管理员权限",
+	"An invite code is required to register":                           "注册需要邀请码",
+	"Anchor must be at most 128 characters":                            "锚点不能超过 128 个字符",
+	"Annotation not found":                                             "批注不存在",
+	"Annotations are disabled for this share":                          "该分享未开放批注",
+	"Announcement not found":                                           "公告不存在",
+	"Another share of this document exists":                            "该文档已有其他分享",
+	"Authorization header required":                                    "需要登录",
+	"Invalid authorization header format":                              "Authorization 请求头格式错误",
+	"Session revoked or expired":                                       "登录会话已注销或过期",
+	"Invalid or revoked token":                                         "令牌无效或已撤销",
+	"User inactive or not found":                                       "用户不存在或已停用",
+	"Asset exceeds the maximum file size":                              "资源文件超过大小上限",
+	"Asset not found":                                                  "资源不存在",
+	"Block shares are sealed with their parent":                        "引用块分享随主分享一并封存",
+	"Calendar not found":                                               "日历不存在",
+	"Call setup first":                                                 "请先调用 setup 生成密钥",
+	"Cannot ban an administrator":                                      "不能封禁管理员",
+	"Cannot switch a share to or from encrypted mode":                  "已有分享不能在加密与普通模式之间切换，请先删除",
+	"Challenge required":                                               "请先完成人机验证",
+	"Challenge verification failed":                                    "人机验证未通过，请重试",
+	"Challenge verification unavailable, please try again later":       "人机验证服务暂不可用，请稍后重试",
+	"Checksum mismatch, please re-upload":                              "文件校验不一致，请重新上传",
+	"Collection not found":                                             "合集不存在",
+	"Collection slug already taken":                                    "合集地址已被占用",
+	"Comment must not be empty":                                        "评论内容不能为空",
+	"Comment not found":                                                "评论不存在",
+	"Comments are disabled for this share":                             "该分享未开放评论",
+	"Confirmation does not match username":                             "确认内容与用户名不一致",
+	"Content is not available in your region":                          "内容在您所在的地区不可用",
+	"Content must be at most 2000 characters":                          "正文不能超过 2000 个字符",
+	"Daily publish quota exhausted":                                    "今日发布次数已用完",
+	"Domain already bound":                                             "该域名已被绑定",
+	"Domain not found":                                                 "域名不存在",
+	"Donation URL must be an http(s) URL":                              "赞助页面地址须为 http(s) 链接",
+	"Donation label must be at most 30 characters":                     "赞助按钮文字不能超过 30 个字符",
+	"Downloads are disabled for this share":                            "该分享已关闭附件下载",
+	"Drawing not found":                                                "绘图不存在",
+	"Duplicate request":                                                "重复的回调请求",
+	"Either content or hash is required":                               "请提供正文或哈希",
+	"Email access is not available":                                    "未开启邮件访问",
+	"Email already exists":                                             "邮箱已被其他账号使用",
+	"Email already verified":                                           "邮箱已验证",
+	"Email is not configured":                                          "未配置邮件服务",
+	"Email is required":                                                "请填写邮箱",
+	"Email not verified":                                               "邮箱尚未验证",
+	"Email subscription is not available":                              "未开启邮件订阅",
+	"Embedding is disabled":                                            "未开启嵌入",
+	"Encrypted shares cannot include references or drawings":           "加密分享不能包含引用块、文献引用或白板",
+	"Export file not found":                                            "导出文件不存在",
+	"Export is not ready":                                              "导出尚未完成",
+	"Export not found":                                                 "导出任务不存在",
+	"Export queue is full, please retry later":                         "导出队列已满，请稍后重试",
+	"Failed to encrypt password":                                       "密码加密失败",
+	"Failed to generate secret":                                        "生成密钥失败",
+	"Failed to hash password":                                          "密码加密失败",
+	"Failed to issue token":                                            "签发令牌失败",
+	"Failed to read asset":                                             "读取资源失败",
+	"Failed to read transcript":                                        "读取文字稿失败",
+	"Failed to record view":                                            "记录浏览失败",
+	"Failed to render feed":                                            "生成订阅源失败",
+	"Failed to render sitemap":                                         "生成站点地图失败",
+	"Failed to reset password":                                         "重置密码失败",
+	"Failed to send access email":                                      "发送访问邮件失败",
+	"Failed to send confirmation email":                                "发送确认邮件失败",
+	"Failed to send verification email":                                "发送验证邮件失败",
+	"Failed to sign token":                                             "签发令牌失败",
+	"Failed to unlock account":                                         "解锁账号失败",
+	"Failed to verify email":                                           "邮箱验证失败",
+	"Feed not found":                                                   "订阅源不存在",
+	"Feedback is disabled for this share":                              "该分享未开启反馈",
+	"Feedback must be at most 1000 characters":                         "反馈内容不能超过 1000 字",
+	"Feedback must not contain more than 2 links":                      "反馈内容最多包含 2 个链接",
+	"Feedback not found":                                               "反馈不存在",
+	"Flashcard deck is empty":                                          "闪卡卡组为空",
+	"Form is empty":                                                    "表单内容为空",
+	"Form not found":                                                   "表单不存在",
+	"Hotlinking of share assets is not allowed":                        "不允许其他站点引用分享资源",
+	"Import file is too large":                                         "导入的文件过大",
+	"Imported document is empty":                                       "导入的文档没有内容",
+	"Internal server error":                                            "服务器内部错误",
+	"Invalid Afdian username":                                          "爱发电用户名无效",
+	"Invalid HTML document":                                            "无效的 HTML 文档",
+	"Invalid Ko-fi username":                                           "Ko-fi 用户名无效",
+	"Invalid asset path":                                               "资源路径无效",
+	"Invalid configuration":                                            "配置无效",
+	"Invalid credentials":                                              "用户名或密码错误",
+	"Invalid encrypted content":                                        "加密正文格式无效",
+	"Invalid from or to":                                               "from 或 to 无效",
+	"Invalid or expired asset signature":                               "资源签名无效或已过期",
+	"Invalid or expired invite code":                                   "邀请码无效或已过期",
+	"Invalid or expired link":                                          "链接无效或已过期",
+	"Invalid or expired token":                                         "令牌无效或已过期",
+	"Invalid password":                                                 "密码错误",
+	"Invalid poll option":                                              "无效的投票选项",
+	"Invalid push endpoint":                                            "推送地址无效",
+	"Invalid push subscription":                                        "推送订阅无效",
+	"Invalid reader token":                                             "读者令牌无效",
+	"Invalid request":                                                  "请求无效",
+	"Invalid signature":                                                "签名无效",
+	"Invalid transcript language":                                      "文字稿语言无效",
+	"Invalid two-factor code":                                          "两步验证码错误",
+	"Invalid url":                                                      "链接无效",
+	"Invalid verification code":                                        "验证码错误",
+	"Invalid version":                                                  "版本号无效",
+	"Invalid zip file":                                                 "无效的 zip 文件",
+	"Invite not found":                                                 "邀请码不存在",
+	"Job is already running":                                           "定时任务正在执行",
+	"Job not found":                                                    "定时任务不存在",
+	"Label must be at most 100 characters":                             "用途说明最多 100 个字符",
+	"Language check is not configured":                                 "服务器未配置拼写与语法检查",
+	"Login required":                                                   "需要登录后阅读全文",
+	"Login session expired, please sign in again":                      "登录已过期，请重新登录",
+	"Mind map not found":                                               "思维导图不存在",
+	"Name is required for anonymous comments":                          "匿名评论需要填写昵称",
+	"No Markdown or HTML document found in zip":                        "压缩包中没有 Markdown 或 HTML 文档",
+	"No fields to update":                                              "没有需要更新的字段",
+	"No invite code available, please retry":                           "没有可用的邀请码，请重试",
+	"No short link code available, please retry":                       "没有可用的短链接代码，请重试",
+	"No signed version found":                                          "没有已签名的版本",
+	"Not available for encrypted shares":                               "加密分享不支持此功能",
+	"Not allowed to delete this annotation":                            "无权删除该批注",
+	"Note must be at most 200 characters":                              "备注最多 200 个字符",
+	"Only JSON format is supported":                                    "仅支持 JSON 格式",
+	"Only a single read-only statement is allowed":                     "只允许单条只读语句（SELECT、WITH、VALUES 或 EXPLAIN）",
+	"Only published shares can be sealed":                              "只能封存已发布且未过期的分享",
+	"Order has been refunded":                                          "订单已退款",
+	"Order has expired":                                                "订单已过期",
+	"Order not found":                                                  "订单不存在",
+	"PDF download is disabled for this share":                          "该分享未开放 PDF 下载",
+	"PDF export is not available":                                      "未开启 PDF 导出",
+	"Password must be at least 4 characters":                           "密码至少 4 个字符",
+	"Password must be provided for new share":                          "新建分享需要设置密码",
+	"Password must be provided":                                        "请设置密码",
+	"Password not set":                                                 "尚未设置密码",
+	"Password required":                                                "需要访问密码",
+	"Password required for download":                                   "下载附件需要输入访问密码",
+	"Payment required":                                                 "需要付费后阅读",
+	"Payments are not configured on this server":                       "服务器未配置支付回调，无法开启付费阅读",
+	"Poll not found":                                                   "投票不存在",
+	"Push subscription expired, please enable notifications again":     "推送订阅已失效，请重新开启通知",
+	"Push subscription is required":                                    "缺少推送订阅",
+	"Push subscription not found":                                      "推送订阅不存在",
+	"Query console is disabled":                                        "数据库查询控制台未开启",
+	"Query is too long":                                                "查询语句过长",
+	"Query parameter q is required":                                    "缺少查询参数 q",
+	"Query timed out":                                                  "查询超时",
+	"Query too long":                                                   "查询内容过长",
+	"Question answering is not available":                              "服务器未开放读者问答",
+	"Question answering is not enabled for this collection":            "该合集中没有开启问答的分享",
+	"Question answering is not enabled for this share":                 "该分享未开启问答",
+	"Question must be 1-500 characters":                                "问题长度需为 1-500 个字符",
+	"Quota values must not be negative":                                "配额不能为负数",
+	"Rate limit exceeded, please retry later":                          "请求过于频繁，请稍后重试",
+	"Reason must be 1-2000 characters":                                 "举报原因须为 1-2000 个字符",
+	"Redirect not found":                                               "重定向规则不存在",
+	"Registration is closed":                                           "注册已关闭",
+	"Rendered block not found":                                         "渲染块不存在",
+	"Report already resolved":                                          "该举报已处理",
+	"Report not found":                                                 "举报不存在",
+	"Request timestamp out of range":                                   "请求时间戳超出允许范围",
+	"Retention can only be extended":                                   "保留期限只能延长",
+	"Revision not found":                                               "历史版本不存在",
+	"Semantic search is not configured":                                "服务器未配置语义搜索",
+	"Server is shutting down":                                          "服务正在关闭",
+	"Session not found":                                                "会话不存在",
+	"Share ID already taken":                                           "分享 ID 已被占用，请重试",
+	"Share cannot be embedded":                                         "该分享不能嵌入",
+	"Share has expired":                                                "分享已过期",
+	"Share has no flashcards":                                          "该分享没有闪卡",
+	"Share has no terms":                                               "该分享未设置使用条款",
+	"Share has reached its view limit":                                 "分享的浏览次数已用完",
+	"Share is disabled":                                                "分享已停用",
+	"Share is not open at this time":                                   "分享当前不在开放时间内",
+	"Share is not paywalled":                                           "该分享未开启付费阅读",
+	"Share is not published":                                           "分享尚未发布",
+	"Share is not sealed":                                              "分享未封存",
+	"Share is restricted":                                              "该分享仅限受邀读者访问",
+	"Share is sealed":                                                  "分享已封存，保留期满前不能修改或删除",
+	"Share not found or unauthorized":                                  "分享不存在或无权操作",
+	"Share not found":                                                  "分享不存在",
+	"Share not found in trash":                                         "回收站中没有该分享",
+	"Share quota exceeded":                                             "分享数已达上限",
+	"Share was disabled by an administrator":                           "分享已被管理员停用",
+	"Share was not disabled by an administrator":                       "分享未被管理员停用",
+	"Shares with a view limit cannot be sealed":                        "限制浏览次数的分享不能封存",
+	"Short link not found":                                             "短链接不存在",
+	"Signing is not available":                                         "内容签名不可用",
+	"Sitemap disabled":                                                 "站点地图已关闭",
+	"Snapshot not found":                                               "存档不存在",
+	"Storage not available":                                            "存储不可用",
+	"Storage quota exceeded":                                           "存储空间已达上限",
+	"Submission not found":                                             "提交不存在",
+	"Summary generation is not configured":                             "服务器未配置摘要生成",
+	"Summary must be at most 300 characters":                           "摘要不能超过 300 个字符",
+	"Table not found":                                                  "表格不存在",
+	"Template not found":                                               "发布模板不存在",
+	"Terms have changed, please review them again":                     "使用条款已更新，请重新阅读",
+	"Terms must be accepted":                                           "请先阅读并同意使用条款",
+	"Terms must be at most 20000 characters":                           "使用条款最多 20000 字",
+	"Text-to-speech is not configured":                                 "未配置语音合成",
+	"Theme CSS too large":                                              "主题样式过大",
+	"Theme not found":                                                  "主题不存在",
+	"This announcement cannot be dismissed":                            "该公告不能关闭",
+	"This form is no longer accepting submissions":                     "该表单已停止接收提交",
+	"Title must be 1-200 characters":                                   "标题须为 1-200 个字符",
+	"Title must be 1-255 characters":                                   "标题须为 1-255 个字符",
+	"Token not allowed from this IP address":                           "该令牌不允许从当前 IP 地址使用",
+	"Token not found or already revoked":                               "令牌不存在或已撤销",
+	"Token not found":                                                  "令牌不存在",
+	"Too many announcements":                                           "公告数量已达上限",
+	"Too many collections":                                             "合集数量已达上限",
+	"Too many comments, please retry later":                            "评论过于频繁，请稍后重试",
+	"Too many domains":                                                 "自定义域名数量已达上限",
+	"Too many event streams":                                           "事件流连接过多",
+	"Too many failed login attempts, please try again later":           "登录失败次数过多，请稍后再试",
+	"Too many orders, please retry later":                              "创建订单过于频繁，请稍后重试",
+	"Too many progress updates, please retry later":                    "保存阅读进度过于频繁，请稍后重试",
+	"Too many questions, please retry later":                           "提问过于频繁，请稍后再试",
+	"Too many redirects":                                               "重定向次数过多",
+	"Too many reports, please retry later":                             "举报过于频繁，请稍后再试",
+	"Too many short links for this share":                              "该分享的短链接数量已达上限",
+	"Too many submissions, please retry later":                         "提交过于频繁，请稍后重试",
+	"Too many templates":                                               "发布模板数量已达上限",
+	"Too many votes, please retry later":                               "投票过于频繁，请稍后重试",
+	"Too much feedback, please retry later":                            "反馈过于频繁，请稍后重试",
+	"Transcript is empty":                                              "文字稿为空",
+	"Transcript is too large (max 1MB)":                                "文字稿过大（最大 1MB）",
+	"Transcript not found":                                             "文字稿不存在",
+	"Transcripts can only be attached to audio or video assets":        "文字稿只能关联到音视频资源",
+	"Translation is not configured":                                    "服务器未配置翻译接口",
+	"Translation not found":                                            "译文不存在",
+	"Two-factor authentication is already enabled":                     "两步验证已开启",
+	"Two-factor authentication is not enabled":                         "两步验证未开启",
+	"Two-factor code required":                                         "需要两步验证码",
+	"Unknown login provider":                                           "未知的登录方式",
+	"Unsupported locale":                                               "不支持的语言",
+	"Unsupported export format":                                        "不支持的导出格式",
+	"Unsupported export format, use md or html":                        "不支持的导出格式，请使用 md 或 html",
+	"User not found":                                                   "用户不存在",
+	"Username already exists":                                          "用户名已存在",
+	"Username or email already exists":                                 "用户名或邮箱已存在",
+	"Verification record not found":                                    "未找到验证 TXT 记录，DNS 记录生效可能需要几分钟",
+	"Web Push is not available":                                        "未开启浏览器推送",
+	"Zip contains too many files":                                      "压缩包中的文件过多",
+	"Zip is too large when unpacked":                                   "压缩包解压后过大",
+	"assetDownloads must be allow, inline or password":                 "assetDownloads 只能为 allow、inline 或 password",
+	"at most 20 date ranges and 20 daily windows are allowed":          "日期范围与每日时段最多各 20 个",
+	"audience must be all, dashboard or shares":                        "audience 只能为 all、dashboard 或 shares",
+	"cover image URL is too long":                                      "封面图片地址过长",
+	"cover image must be a path starting with / or an http(s) URL":     "封面图片须为以 / 开头的站内路径或 http(s) 地址",
+	"daily times must be formatted as HH:MM":                           "每日时刻格式应为 HH:MM",
+	"daily window must not be empty":                                   "每日时段的开始与结束不能相同",
+	"date range must end after it starts":                              "日期范围的结束时间需晚于开始时间",
+	"date range must have a start or an end":                           "日期范围需要设置开始或结束时间",
+	"dates must be formatted as YYYY-MM-DDTHH:MM":                      "日期格式应为 YYYY-MM-DDTHH:MM",
+	"days must be between 1 and 366":                                   "days 须在 1 到 366 之间",
+	"description is too long":                                          "描述过长",
+	"domain is used by this server":                                    "该域名为本站自身使用的域名",
+	"endOffset must not be less than startOffset":                      "endOffset 不能小于 startOffset",
+	"endsAt must be after startsAt":                                    "结束时间需晚于开始时间",
+	"expireDays must be between 1 and 365":                             "expireDays 须在 1 到 365 之间",
+	"expiresDays must be between 0 and 365":                            "有效天数须在 0 到 365 之间",
+	"format must be csv or json":                                       "format 只能是 csv 或 json",
+	"group must be day, referrer or country":                           "group 只能是 day、referrer 或 country",
+	"homePath must be a path starting with /":                          "homePath 须为以 / 开头的站内路径",
+	"invalid collection slug":                                          "合集地址只能包含小写字母、数字与连字符，且不超过 64 个字符",
+	"invalid domain":                                                   "域名格式无效",
+	"invalid path":                                                     "路径无效",
+	"level must be info, warning or error":                             "level 只能为 info、warning 或 error",
+	"link is not a valid URL":                                          "链接格式无效",
+	"link is too long":                                                 "链接过长",
+	"link must be a path starting with / or an http(s) URL":            "链接须为以 / 开头的站内路径或 http(s) 地址",
+	"maxUses must be between 0 and 10000":                              "可用次数须在 0 到 10000 之间",
+	"maxViews must be between 0 and 10000":                             "浏览次数上限须在 0 到 10000 之间",
+	"no text to answer from":                                           "没有可用于回答的正文",
+	"not found":                                                        "不存在",
+	"paywallPrice and paywallUrl are required to enable the paywall":   "开启付费阅读前须设置价格与支付页面地址",
+	"paywallPrice must be at most 32 characters":                       "价格不能超过 32 个字符",
+	"paywallUrl must be an http(s) URL":                                "支付页面地址须为 http(s) 链接",
+	"paywallUrl must contain {order}":                                  "支付页面地址须包含 {order} 占位符",
+	"percent must be between 0 and 100":                                "percent 必须在 0 到 100 之间",
+	"scope must be dashboard or shares":                                "scope 只能为 dashboard 或 shares",
+	"share has no readable text":                                       "分享正文中没有可朗读的文字",
+	"share has no text to check":                                       "分享正文中没有可检查的文字",
+	"share has no text to summarize":                                   "分享正文中没有可生成摘要的文字",
+	"share has no text to translate":                                   "分享没有可翻译的内容",
+	"share is not indexed yet":                                         "分享尚未建立语义索引",
+	"share text is too long for narration":                             "分享正文过长，无法生成朗读音频",
+	"status must be draft, published or unlisted":                      "status 须为 draft、published 或 unlisted",
+	"status must be open, dismissed, actioned or all":                  "status 须为 open、dismissed、actioned 或 all",
+	"title is required":                                                "标题不能为空",
+	"toc must be side, floating or off":                                "toc 只能为 side、floating 或 off",
+	"too many shares in collection":                                    "合集中的分享数量超过上限",
+
+	// 带错误详情的接口错误信息前缀
+	"Batch operation failed, no changes applied: ":  "批量操作失败，未做任何修改：",
+	"DNS lookup failed: ":                           "DNS 查询失败：",
+	"Failed to answer question: ":                   "回答失败：",
+	"Failed to approve comment: ":                   "审核评论失败：",
+	"Failed to bind domain: ":                       "绑定域名失败：",
+	"Failed to cancel account deletion: ":           "撤销注销失败：",
+	"Failed to check assets: ":                      "核对资源失败：",
+	"Failed to count shares: ":                      "统计分享失败：",
+	"Failed to create announcement: ":               "发布公告失败：",
+	"Failed to create backup: ":                     "创建备份失败：",
+	"Failed to create export: ":                     "创建导出任务失败：",
+	"Failed to create invite: ":                     "生成邀请码失败：",
+	"Failed to create order: ":                      "创建订单失败：",
+	"Failed to create redirect: ":                   "创建重定向规则失败：",
+	"Failed to create short link: ":                 "创建短链接失败：",
+	"Failed to create user: ":                       "创建用户失败：",
+	"Failed to delete account: ":                    "删除账号失败：",
+	"Failed to delete annotation: ":                 "删除批注失败：",
+	"Failed to delete announcement: ":               "删除公告失败：",
+	"Failed to delete collection: ":                 "删除合集失败：",
+	"Failed to delete comment: ":                    "删除评论失败：",
+	"Failed to delete domain: ":                     "解绑域名失败：",
+	"Failed to delete feedback: ":                   "删除反馈失败：",
+	"Failed to delete invite: ":                     "删除邀请码失败：",
+	"Failed to delete narration: ":                  "删除朗读音频失败：",
+	"Failed to delete push subscription: ":          "删除推送订阅失败：",
+	"Failed to delete reading progress: ":           "删除阅读进度失败：",
+	"Failed to delete redirect: ":                   "删除重定向规则失败：",
+	"Failed to delete share: ":                      "删除分享失败：",
+	"Failed to delete shares: ":                     "删除分享失败：",
+	"Failed to delete short link: ":                 "删除短链接失败：",
+	"Failed to delete submission: ":                 "删除表单提交失败：",
+	"Failed to delete template: ":                   "删除发布模板失败：",
+	"Failed to delete theme: ":                      "删除主题失败：",
+	"Failed to delete translation: ":                "删除译文失败：",
+	"Failed to disable two-factor authentication: ": "关闭两步验证失败：",
+	"Failed to dismiss announcement: ":              "关闭公告失败：",
+	"Failed to enable two-factor authentication: ":  "开启两步验证失败：",
+	"Failed to encode citations: ":                  "生成文献数据失败：",
+	"Failed to encode table: ":                      "生成表格数据失败：",
+	"Failed to export PDF: ":                        "导出 PDF 失败：",
+	"Failed to export share: ":                      "导出分享失败：",
+	"Failed to fetch shares: ":                      "获取分享失败：",
+	"Failed to find related shares: ":               "查找相关分享失败：",
+	"Failed to generate narration: ":                "生成朗读音频失败：",
+	"Failed to generate summary: ":                  "生成摘要失败：",
+	"Failed to list annotations: ":                  "获取批注失败：",
+	"Failed to list announcements: ":                "获取公告失败：",
+	"Failed to list assets: ":                       "获取资源失败：",
+	"Failed to list collections: ":                  "获取合集列表失败：",
+	"Failed to list comments: ":                     "获取评论失败：",
+	"Failed to list domains: ":                      "获取域名列表失败：",
+	"Failed to list exports: ":                      "获取导出任务失败：",
+	"Failed to list invites: ":                      "获取邀请码失败：",
+	"Failed to list push subscriptions: ":           "获取推送订阅失败：",
+	"Failed to list redirects: ":                    "获取重定向规则失败：",
+	"Failed to list reports: ":                      "获取举报列表失败：",
+	"Failed to list revisions: ":                    "获取历史版本失败：",
+	"Failed to list sessions: ":                     "获取会话失败：",
+	"Failed to list short links: ":                  "获取短链接失败：",
+	"Failed to list snapshots: ":                    "获取存档失败：",
+	"Failed to list templates: ":                    "获取发布模板失败：",
+	"Failed to list themes: ":                       "获取主题失败：",
+	"Failed to list tokens: ":                       "获取令牌失败：",
+	"Failed to list transcripts: ":                  "获取文字稿失败：",
+	"Failed to load acceptances: ":                  "获取同意记录失败：",
+	"Failed to load access list: ":                  "获取访问名单失败：",
+	"Failed to load calendar: ":                     "获取日历失败：",
+	"Failed to load collection: ":                   "加载合集失败：",
+	"Failed to load feed: ":                         "获取订阅源失败：",
+	"Failed to load feedback: ":                     "获取反馈失败：",
+	"Failed to load notification settings: ":        "获取通知设置失败：",
+	"Failed to load order: ":                        "加载订单失败：",
+	"Failed to load orders: ":                       "加载订单失败：",
+	"Failed to load reading progress: ":             "获取阅读进度失败：",
+	"Failed to load share: ":                        "加载分享失败：",
+	"Failed to load sitemap: ":                      "获取站点地图失败：",
+	"Failed to load submissions: ":                  "加载表单提交失败：",
+	"Failed to load translations: ":                 "加载译文失败：",
+	"Failed to load trash: ":                        "获取回收站失败：",
+	"Failed to load user: ":                         "获取用户失败：",
+	"Failed to load views: ":                        "获取浏览记录失败：",
+	"Failed to load votes: ":                        "加载投票失败：",
+	"Failed to purge share: ":                       "彻底删除分享失败：",
+	"Failed to query asset: ":                       "查询资源失败：",
+	"Failed to query bandwidth: ":                   "查询流量失败：",
+	"Failed to query share: ":                       "查询分享失败：",
+	"Failed to query stats: ":                       "查询统计失败：",
+	"Failed to query template: ":                    "查询发布模板失败：",
+	"Failed to query usage: ":                       "查询用量失败：",
+	"Failed to read export: ":                       "读取导出文件失败：",
+	"Failed to read snapshot: ":                     "读取存档失败：",
+	"Failed to read upload: ":                       "读取上传文件失败：",
+	"Failed to record acceptance: ":                 "记录同意失败：",
+	"Failed to record nonce: ":                      "记录回调 nonce 失败：",
+	"Failed to refresh token: ":                     "刷新令牌失败：",
+	"Failed to reindex shares: ":                    "重建语义索引失败：",
+	"Failed to reinstate share: ":                   "恢复分享失败：",
+	"Failed to reload configuration: ":              "重新加载配置失败：",
+	"Failed to remove transcript: ":                 "移除文字稿失败：",
+	"Failed to render block: ":                      "渲染代码块失败：",
+	"Failed to resolve report: ":                    "处理举报失败：",
+	"Failed to restore share: ":                     "恢复分享失败：",
+	"Failed to revoke session: ":                    "注销会话失败：",
+	"Failed to revoke sessions: ":                   "注销会话失败：",
+	"Failed to revoke token: ":                      "撤销令牌失败：",
+	"Failed to save annotation: ":                   "保存批注失败：",
+	"Failed to save asset: ":                        "保存资源失败：",
+	"Failed to save collection: ":                   "保存合集失败：",
+	"Failed to save comment: ":                      "保存评论失败：",
+	"Failed to save feedback: ":                     "保存反馈失败：",
+	"Failed to save notification settings: ":        "保存通知设置失败：",
+	"Failed to save push subscription: ":            "保存推送订阅失败：",
+	"Failed to save reading progress: ":             "保存阅读进度失败：",
+	"Failed to save recovery codes: ":               "保存恢复码失败：",
+	"Failed to save report: ":                       "保存举报失败：",
+	"Failed to save secret: ":                       "保存密钥失败：",
+	"Failed to save submission: ":                   "保存表单提交失败：",
+	"Failed to save subscription: ":                 "保存订阅失败：",
+	"Failed to save template: ":                     "保存发布模板失败：",
+	"Failed to save theme: ":                        "保存主题失败：",
+	"Failed to save token: ":                        "保存令牌失败：",
+	"Failed to save transcript: ":                   "保存文字稿失败：",
+	"Failed to save translation: ":                  "保存译文失败：",
+	"Failed to save vote: ":                         "保存投票失败：",
+	"Failed to schedule account deletion: ":         "申请注销失败：",
+	"Failed to seal share: ":                        "封存分享失败：",
+	"Failed to send push: ":                         "发送推送失败：",
+	"Failed to serialize references: ":              "序列化引用块失败：",
+	"Failed to start language check: ":              "开始检查失败：",
+	"Failed to start translation: ":                 "开始翻译失败：",
+	"Failed to store asset: ":                       "存储资源失败：",
+	"Failed to update access list: ":                "更新访问名单失败：",
+	"Failed to update annotation: ":                 "更新批注失败：",
+	"Failed to update announcement: ":               "更新公告失败：",
+	"Failed to update domain: ":                     "更新域名失败：",
+	"Failed to update order: ":                      "更新订单失败：",
+	"Failed to update profile: ":                    "更新资料失败：",
+	"Failed to update quota: ":                      "更新配额失败：",
+	"Failed to update redirect: ":                   "更新重定向规则失败：",
+	"Failed to update settings: ":                   "更新设置失败：",
+	"Failed to update share: ":                      "更新分享失败：",
+	"Failed to update short link: ":                 "更新短链接失败：",
+	"Failed to update status: ":                     "更新状态失败：",
+	"Failed to verify: ":                            "校验失败：",
+	"Field is too long: ":                           "字段内容过长：",
+	"Invalid citations: ":                           "文献数据无效：",
+	"Invalid drawing: ":                             "绘图无效：",
+	"Invalid drawings: ":                            "绘图数据无效：",
+	"Invalid email address: ":                       "邮箱地址无效：",
+	"Invalid email: ":                               "邮箱无效：",
+	"Invalid flashcards: ":                          "闪卡数据无效：",
+	"Invalid request: ":                             "请求无效：",
+	"Invalid sort field: ":                          "排序字段无效：",
+	"Invalid transcript: ":                          "文字稿无效：",
+	"Login denied: ":                                "登录被拒绝：",
+	"Login provider unavailable: ":                  "登录方式不可用：",
+	"Missing required field: ":                      "缺少必填字段：",
+	"Publish failed, no changes applied: ":          "发布失败，未做任何修改：",
+	"Publish hook failed: ":                         "发布钩子执行失败：",
+	"Publish hook returned invalid tags: ":          "发布钩子返回的标签无效：",
+	"Publish rejected: ":                            "发布被拒绝：",
+	"Query failed: ":                                "查询失败：",
+	"Rollback failed, no changes applied: ":         "回滚失败，未做任何修改：",
+	"Search failed: ":                               "搜索失败：",
+	"Semantic search failed: ":                      "语义搜索失败：",
+	"Unknown theme: ":                               "未知主题：",
+	"Unsupported language: ":                        "不支持的语言：",
+	"User not found: ":                              "用户不存在：",
+	"share not found: ":                             "分享不存在：",
+	"unknown timezone: ":                            "未知时区：",
+}
@@ -0,0 +1,50 @@
+// Package idgen 生成 ID 与 Token 使用的随机字节。默认使用 crypto/rand；
+// 测试模式下改为由种子决定的伪随机序列，相同的请求顺序得到相同的分享 ID、会话 ID 与 API Token，便于集成测试断言
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	mrand "math/rand/v2"
+	"sync"
+)
+
+var (
+	mu  sync.Mutex
+	det *mrand.ChaCha8 // 非 nil 时为确定性序列
+)
+
+// Seed 切换为由 seed 决定的确定性序列，再次调用时从头开始
+func Seed(seed int64) {
+	var key [32]byte
+	binary.LittleEndian.PutUint64(key[:], uint64(seed))
+	mu.Lock()
+	det = mrand.NewChaCha8(key)
+	mu.Unlock()
+}
+
+// Deterministic 是否使用确定性序列
+func Deterministic() bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return det != nil
+}
+
+// Read 用随机字节填满 b
+func Read(b []byte) {
+	mu.Lock()
+	defer mu.Unlock()
+	if det != nil {
+		_, _ = det.Read(b)
+		return
+	}
+	_, _ = rand.Read(b)
+}
+
+// Hex 返回 n 个随机字节的十六进制表示
+func Hex(n int) string {
+	b := make([]byte, n)
+	Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,169 @@
+// Package imageopt 图片资源优化：上传后在后台为过大的 PNG/JPEG 生成缩小的副本，并可借助外部命令生成
+// WebP 副本；读者请求资源时按 Accept 选择最小的可用副本，原图保持不变（导出仍使用原图）。
+package imageopt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"gorm.io/gorm/clause"
+)
+
+// maxSourceBytes 处理的原图大小上限
+const maxSourceBytes = 64 << 20
+
+// formats 可优化的图片类型；GIF 可能含动画，SVG 本身为矢量，均不处理
+var formats = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpeg",
+}
+
+// 同时进行的优化任务数，解码与缩放大图较耗内存
+var slots = make(chan struct{}, 2)
+
+// Enabled 是否启用了图片优化 (images.max_dimension / images.webp_command)
+func Enabled() bool {
+	return config.Get().Images.Enabled()
+}
+
+// Optimizable 资源是否为可优化的图片
+func Optimizable(asset *models.Asset) bool {
+	_, ok := formats[asset.ContentType]
+	return ok
+}
+
+// Schedule 在后台为新上传或替换的图片资源重新生成优化副本
+func Schedule(asset *models.Asset) {
+	if !Enabled() || !Optimizable(asset) || storage.Default == nil {
+		return
+	}
+	a := *asset
+	background.Go(func() {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		if err := optimize(ctx, &a); err != nil {
+			log.Printf("image optimization for %s/%s failed: %v", a.ShareID, a.Path, err)
+		}
+	})
+}
+
+// optimize 删除旧副本后按当前配置生成新副本，只保留比原图（或缩小后的图片）更小的结果
+func optimize(ctx context.Context, a *models.Asset) error {
+	removeVariants(a.ID)
+	if a.Size > maxSourceBytes {
+		return nil
+	}
+	rc, _, err := storage.Default.Get(ctx, a.StorageKey)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Get().Images
+	format := formats[a.ContentType]
+	normalized, width, height, err := normalize(data, format, cfg.MaxDimension, cfg.Quality)
+	if errors.Is(err, errTooLarge) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// WebP 以摆正、缩小后的图片为输入，外部命令未必识别 EXIF 方向
+	base := data
+	if normalized != nil {
+		base = normalized
+		if len(normalized) < len(data) {
+			if err := saveVariant(ctx, a, format, a.ContentType, normalized, width, height); err != nil {
+				return err
+			}
+		}
+	}
+	if len(cfg.WebPCommand) > 0 {
+		cmd := &renderer.Command{Args: cfg.WebPCommand, MaxBytes: int64(len(base))}
+		webp, err := cmd.Render(ctx, base)
+		if errors.Is(err, renderer.ErrTooLarge) {
+			// WebP 比输入更大，没有收益
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("webp: %w", err)
+		}
+		if len(webp) > 0 && len(webp) < len(base) && len(webp) < len(data) {
+			if err := saveVariant(ctx, a, "webp", "image/webp", webp, width, height); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// saveVariant 保存副本；原图在处理期间被替换时放弃
+func saveVariant(ctx context.Context, a *models.Asset, format, contentType string, data []byte, width, height int) error {
+	if current, err := models.FindAsset(a.ShareID, a.Path); err != nil || current == nil || current.Hash != a.Hash {
+		return err
+	}
+	key := "shares/" + a.ShareID + "/.variants/" + a.ID + "." + format
+	if err := storage.Default.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return err
+	}
+	v := &models.AssetVariant{
+		AssetID: a.ID, Format: format, SourceHash: a.Hash, StorageKey: key,
+		ContentType: contentType, Size: int64(len(data)), Width: width, Height: height,
+	}
+	return models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "asset_id"}, {Name: "format"}},
+		DoUpdates: clause.AssignmentColumns([]string{"source_hash", "storage_key", "content_type", "size", "width", "height", "created_at"}),
+	}).Create(v).Error
+}
+
+// removeVariants 删除资源的全部副本
+func removeVariants(assetID string) {
+	var variants []models.AssetVariant
+	models.DB.Where("asset_id = ?", assetID).Find(&variants)
+	for _, v := range variants {
+		if err := storage.Default.Delete(context.Background(), v.StorageKey); err != nil {
+			log.Printf("image variant cleanup failed (%s): %v", v.StorageKey, err)
+		}
+		models.DB.Delete(&v)
+	}
+}
+
+// Pick 按读者的 Accept 请求头选择最小的可用副本，没有合适副本时返回 nil（使用原图）
+func Pick(asset *models.Asset, accept string) *models.AssetVariant {
+	if !Optimizable(asset) {
+		return nil
+	}
+	var variants []models.AssetVariant
+	if err := models.DB.Where("asset_id = ? AND source_hash = ?", asset.ID, asset.Hash).Find(&variants).Error; err != nil {
+		return nil
+	}
+	webp := strings.Contains(accept, "image/webp")
+	var best *models.AssetVariant
+	for i := range variants {
+		v := &variants[i]
+		if v.Format == "webp" && !webp {
+			continue
+		}
+		if best == nil || v.Size < best.Size {
+			best = v
+		}
+	}
+	return best
+}
@@ -0,0 +1,147 @@
+// Package langcheck 发布时的拼写与语法检查：把分享正文的文字交给 LanguageTool 兼容的 /v2/check 接口，
+// 保存问题报告供作者在分享详情中查看。检查只产生报告，不修改分享内容。
+package langcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// maxResponseBytes 接口响应体大小上限
+const maxResponseBytes = 16 << 20
+
+// maxReplacements 每个问题保留的修改建议数
+const maxReplacements = 5
+
+// Enabled 是否配置了检查接口 (language_check.url)
+func Enabled() bool {
+	return config.Get().LanguageCheck.Enabled()
+}
+
+// Issue 检查发现的一个问题
+type Issue struct {
+	Message      string   `json:"message"`
+	ShortMessage string   `json:"shortMessage,omitempty"`
+	Rule         string   `json:"rule"`               // 规则 ID
+	Category     string   `json:"category,omitempty"` // 规则分类，如 TYPOS、GRAMMAR
+	Type         string   `json:"type,omitempty"`     // 问题类型，如 misspelling、grammar、style
+	Text         string   `json:"text"`               // 有问题的文字
+	Context      string   `json:"context"`            // 问题所在的上下文
+	Offset       int      `json:"offset"`             // 问题在上下文中的位置（字符）
+	Length       int      `json:"length"`             // 问题文字的长度（字符）
+	Replacements []string `json:"replacements,omitempty"`
+}
+
+// Result 一次检查请求的结果
+type Result struct {
+	Language string // 使用或识别出的语言
+	Issues   []Issue
+}
+
+type checkResponse struct {
+	Language struct {
+		Code             string `json:"code"`
+		DetectedLanguage struct {
+			Code string `json:"code"`
+		} `json:"detectedLanguage"`
+	} `json:"language"`
+	Matches []struct {
+		Message      string `json:"message"`
+		ShortMessage string `json:"shortMessage"`
+		Replacements []struct {
+			Value string `json:"value"`
+		} `json:"replacements"`
+		Context struct {
+			Text   string `json:"text"`
+			Offset int    `json:"offset"`
+			Length int    `json:"length"`
+		} `json:"context"`
+		Rule struct {
+			ID        string `json:"id"`
+			IssueType string `json:"issueType"`
+			Category  struct {
+				ID string `json:"id"`
+			} `json:"category"`
+		} `json:"rule"`
+	} `json:"matches"`
+}
+
+// Check 请求检查接口检查一段文本
+func Check(ctx context.Context, text string) (*Result, error) {
+	cfg := config.Get().LanguageCheck
+	form := url.Values{"text": {text}, "language": {cfg.Language}}
+	if cfg.Username != "" {
+		form.Set("username", cfg.Username)
+		form.Set("apiKey", cfg.APIKey)
+	}
+	if len(cfg.DisabledRules) > 0 {
+		form.Set("disabledRules", strings.Join(cfg.DisabledRules, ","))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("language check service returned %s: %s", resp.Status, msg)
+	}
+	var parsed checkResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, fmt.Errorf("invalid language check response: %w", err)
+	}
+
+	res := &Result{Language: parsed.Language.DetectedLanguage.Code, Issues: make([]Issue, 0, len(parsed.Matches))}
+	if res.Language == "" {
+		res.Language = parsed.Language.Code
+	}
+	for _, m := range parsed.Matches {
+		issue := Issue{
+			Message:      m.Message,
+			ShortMessage: m.ShortMessage,
+			Rule:         m.Rule.ID,
+			Category:     m.Rule.Category.ID,
+			Type:         m.Rule.IssueType,
+		}
+		// LanguageTool 的位置按 UTF-16 计算，转换为字符位置
+		ctxText := utf16.Encode([]rune(m.Context.Text))
+		start, end := m.Context.Offset, m.Context.Offset+m.Context.Length
+		if start >= 0 && start <= end && end <= len(ctxText) {
+			before := utf16.Decode(ctxText[:start])
+			issue.Text = string(utf16.Decode(ctxText[start:end]))
+			issue.Context = m.Context.Text
+			issue.Offset = len(before)
+			issue.Length = len([]rune(issue.Text))
+		}
+		for i, r := range m.Replacements {
+			if i == maxReplacements {
+				break
+			}
+			issue.Replacements = append(issue.Replacements, r.Value)
+		}
+		res.Issues = append(res.Issues, issue)
+	}
+	return res, nil
+}
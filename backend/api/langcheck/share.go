@@ -0,0 +1,156 @@
+package langcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/tts"
+	"gorm.io/gorm/clause"
+)
+
+// ErrNoText 分享正文中没有可检查的文字
+var ErrNoText = errors.New("share has no text to check")
+
+// 同时进行的检查任务数，公共检查服务按请求频率限流
+var slots = make(chan struct{}, 1)
+
+// Report 作者查看的检查报告
+type Report struct {
+	*models.ShareLanguageCheck
+	Issues []Issue `json:"issues"`
+	Stale  bool    `json:"stale"` // 正文在检查后有变化，报告不再对应当前内容
+}
+
+// text 参与检查的正文文字（去掉代码块、公式与 Markdown 标记），超过 language_check.max_chars 时截断
+func text(share *models.Share) (string, bool) {
+	runes := []rune(tts.Text(share.Content))
+	if limit := config.Get().LanguageCheck.MaxChars; limit > 0 && len(runes) > limit {
+		return string(runes[:limit]), true
+	}
+	return string(runes), false
+}
+
+// Current 分享的检查报告，尚未检查时返回 nil
+func Current(share *models.Share) *Report {
+	l := models.FindLanguageCheck(share.ID)
+	if l == nil {
+		return nil
+	}
+	r := &Report{ShareLanguageCheck: l, Issues: []Issue{}}
+	if l.Issues != "" {
+		if err := json.Unmarshal([]byte(l.Issues), &r.Issues); err != nil {
+			log.Printf("language check report for share %s is invalid: %v", share.ID, err)
+		}
+	}
+	t, _ := text(share)
+	r.Stale = l.TextHash != tts.Hash(t)
+	return r
+}
+
+// Start 在后台检查分享正文，返回当前记录；同一正文正在检查中时直接返回
+func Start(share *models.Share) (*models.ShareLanguageCheck, error) {
+	return start(share, true)
+}
+
+// Auto 配置了检查接口时在后台检查发布的分享，正文未变化且已有报告时跳过
+func Auto(share *models.Share) {
+	if !Enabled() || share.Status != models.ShareStatusPublished {
+		return
+	}
+	if _, err := start(share, false); err != nil && !errors.Is(err, ErrNoText) {
+		log.Printf("language check for share %s not started: %v", share.ID, err)
+	}
+}
+
+func start(share *models.Share, force bool) (*models.ShareLanguageCheck, error) {
+	if !Enabled() {
+		return nil, errors.New("language check is not configured")
+	}
+	t, truncated := text(share)
+	if t == "" {
+		return nil, ErrNoText
+	}
+	hash := tts.Hash(t)
+	if l := models.FindLanguageCheck(share.ID); l != nil && l.TextHash == hash {
+		// 进程在检查中途退出时记录停留在 pending，超过检查超时后允许重新检查
+		stale := l.Status == models.LanguageCheckPending && time.Since(l.UpdatedAt) > config.Get().LanguageCheck.Timeout.Std()+time.Minute
+		if (l.Status == models.LanguageCheckPending && !stale) || (!force && l.Status == models.LanguageCheckOK) {
+			return l, nil
+		}
+	}
+
+	l := &models.ShareLanguageCheck{ShareID: share.ID, Status: models.LanguageCheckPending, TextHash: hash}
+	if err := models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "share_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"status": models.LanguageCheckPending, "error": "", "text_hash": hash, "updated_at": time.Now()}),
+	}).Create(l).Error; err != nil {
+		return nil, err
+	}
+	l = models.FindLanguageCheck(share.ID)
+	if l == nil {
+		return nil, errors.New("language check record not found")
+	}
+	shareID := share.ID
+	if !background.Go(func() {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		run(shareID, t, hash, truncated)
+	}) {
+		return nil, errors.New("server is shutting down")
+	}
+	return l, nil
+}
+
+// run 分段检查正文并写回报告；期间正文再次变化时放弃本次结果
+func run(shareID, text, hash string, truncated bool) {
+	cfg := config.Get().LanguageCheck
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout.Std())
+	defer cancel()
+
+	issues := []Issue{}
+	language := ""
+	var err error
+	for _, chunk := range tts.Split(text, cfg.ChunkChars) {
+		var res *Result
+		if res, err = Check(ctx, chunk); err != nil {
+			break
+		}
+		if language == "" {
+			language = res.Language
+		}
+		issues = append(issues, res.Issues...)
+		if cfg.MaxIssues > 0 && len(issues) > cfg.MaxIssues {
+			issues, truncated = issues[:cfg.MaxIssues], true
+			break
+		}
+	}
+
+	var updates map[string]interface{}
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		updates = map[string]interface{}{"status": models.LanguageCheckFailed, "error": msg}
+		log.Printf("language check for share %s failed: %v", shareID, err)
+	} else {
+		data, _ := json.Marshal(issues)
+		updates = map[string]interface{}{
+			"status":      models.LanguageCheckOK,
+			"error":       "",
+			"language":    language,
+			"issues":      string(data),
+			"issue_count": len(issues),
+			"truncated":   truncated,
+		}
+	}
+	if err := models.DB.Model(&models.ShareLanguageCheck{}).Where("share_id = ? AND text_hash = ?", shareID, hash).Updates(updates).Error; err != nil {
+		log.Printf("language check save failed (%s): %v", shareID, err)
+	}
+}
@@ -0,0 +1,144 @@
+package linkpreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/safehttp"
+	"golang.org/x/net/html"
+)
+
+// maxPageBytes 只读取页面前 512KB，元信息通常位于 <head> 中
+const maxPageBytes = 512 << 10
+
+var client = safehttp.NewClient(10 * time.Second)
+
+// Meta 页面元信息
+type Meta struct {
+	Title       string
+	Description string
+	Image       string
+	SiteName    string
+}
+
+// fetchMeta 抓取页面并解析 Open Graph / Twitter Card / <title> 等元信息
+func fetchMeta(ctx context.Context, rawURL string) (*Meta, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", safehttp.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("linkpreview: %s", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		return nil, fmt.Errorf("linkpreview: unsupported content type %s", ct)
+	}
+	meta := parseMeta(io.LimitReader(resp.Body, maxPageBytes))
+	base := resp.Request.URL
+	if meta.Image != "" {
+		if u, err := base.Parse(meta.Image); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+			meta.Image = u.String()
+		} else {
+			meta.Image = ""
+		}
+	}
+	if meta.SiteName == "" {
+		meta.SiteName = base.Hostname()
+	}
+	if meta.Title == "" {
+		return nil, errors.New("linkpreview: page has no title")
+	}
+	return meta, nil
+}
+
+func parseMeta(r io.Reader) *Meta {
+	meta := &Meta{}
+	values := map[string]string{}
+	var title string
+	inTitle := false
+
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			goto done
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tok := z.Token()
+			switch tok.Data {
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				var key, content string
+				for _, a := range tok.Attr {
+					switch strings.ToLower(a.Key) {
+					case "property", "name":
+						key = strings.ToLower(a.Val)
+					case "content":
+						content = a.Val
+					}
+				}
+				if key != "" && content != "" {
+					if _, exists := values[key]; !exists {
+						values[key] = strings.TrimSpace(content)
+					}
+				}
+			case "body":
+				// 元信息都在 <head> 中，进入 body 后停止解析
+				goto done
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(z.Text())
+			}
+		case html.EndTagToken:
+			if z.Token().Data == "title" {
+				inTitle = false
+			}
+		}
+	}
+done:
+	pick := func(keys ...string) string {
+		for _, k := range keys {
+			if v := values[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	meta.Title = pick("og:title", "twitter:title")
+	if meta.Title == "" {
+		meta.Title = strings.Join(strings.Fields(title), " ")
+	}
+	meta.Description = pick("og:description", "twitter:description", "description")
+	meta.Image = pick("og:image", "og:image:url", "twitter:image", "twitter:image:src")
+	meta.SiteName = pick("og:site_name", "application-name")
+	meta.Title = truncate(meta.Title, 200)
+	meta.Description = truncate(meta.Description, 400)
+	return meta
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
+
+func isExternalURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,177 @@
+package linkpreview
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/safehttp"
+)
+
+// maxLinksPerShare 单个分享最多生成预览的链接数
+const maxLinksPerShare = 20
+
+// failureTTL 抓取失败的缓存时长，避免反复请求不可用的页面
+const failureTTL = time.Hour
+
+var (
+	bareURLPattern  = regexp.MustCompile(`^<?(https?://[^\s<>]+?)>?$`)
+	mdLinkOnlyRegex = regexp.MustCompile(`^\[([^\]]*)\]\((https?://[^\s)]+)(?:\s+"[^"]*")?\)$`)
+)
+
+// Enabled 是否启用链接预览 (links.previews)
+func Enabled() bool {
+	return config.Get().Links.Previews
+}
+
+// ttl 预览缓存有效期 (links.preview_ttl，默认 168h)
+func ttl() time.Duration {
+	return config.Get().Links.PreviewTTL.Std()
+}
+
+func cacheKey(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return hex.EncodeToString(sum[:])
+}
+
+// ExtractBareLinks 提取独占一行的外部链接（纯 URL、<URL> 或文字即 URL 的 Markdown 链接），即思源中的链接卡片
+func ExtractBareLinks(content string) []string {
+	seen := map[string]bool{}
+	var links []string
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || line == "" {
+			continue
+		}
+		var link string
+		if m := bareURLPattern.FindStringSubmatch(line); m != nil {
+			link = m[1]
+		} else if m := mdLinkOnlyRegex.FindStringSubmatch(line); m != nil && (m[1] == "" || m[1] == m[2]) {
+			link = m[2]
+		}
+		if link == "" || seen[link] || !isExternalURL(link) {
+			continue
+		}
+		seen[link] = true
+		links = append(links, link)
+		if len(links) >= maxLinksPerShare {
+			break
+		}
+	}
+	return links
+}
+
+// Lookup 返回缓存中仍有效的预览，过期或缺失的链接会在后台抓取
+func Lookup(links []string) map[string]*models.LinkPreview {
+	result := map[string]*models.LinkPreview{}
+	if len(links) == 0 || !Enabled() {
+		return result
+	}
+	keys := make([]string, len(links))
+	for i, l := range links {
+		keys[i] = cacheKey(l)
+	}
+	var cached []models.LinkPreview
+	models.DB.Where("id IN ?", keys).Find(&cached)
+
+	now := time.Now()
+	fresh := map[string]bool{}
+	for i := range cached {
+		p := &cached[i]
+		if p.ExpiresAt.After(now) {
+			fresh[p.URL] = true
+			if !p.Failed {
+				result[p.URL] = p
+			}
+		}
+	}
+	var stale []string
+	for _, l := range links {
+		if !fresh[l] {
+			stale = append(stale, l)
+		}
+	}
+	Prefetch(stale)
+	return result
+}
+
+// Warm 抓取缓存中缺失或已过期的预览（发布时调用）
+func Warm(links []string) {
+	Lookup(links)
+}
+
+var (
+	inflightMu sync.Mutex
+	inflight   = map[string]bool{}
+	// 限制并发抓取数量
+	slots = make(chan struct{}, 4)
+)
+
+// Prefetch 在后台抓取并缓存链接预览，同一链接不会重复抓取
+func Prefetch(links []string) {
+	if !Enabled() {
+		return
+	}
+	for _, l := range links {
+		inflightMu.Lock()
+		if inflight[l] {
+			inflightMu.Unlock()
+			continue
+		}
+		inflight[l] = true
+		inflightMu.Unlock()
+
+		link := l
+		started := background.Go(func() {
+			defer func() {
+				inflightMu.Lock()
+				delete(inflight, link)
+				inflightMu.Unlock()
+			}()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			refresh(link)
+		})
+		if !started {
+			inflightMu.Lock()
+			delete(inflight, link)
+			inflightMu.Unlock()
+		}
+	}
+}
+
+func refresh(link string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	p := models.LinkPreview{ID: cacheKey(link), URL: link, FetchedAt: now}
+	meta, err := fetchMeta(ctx, link)
+	if err != nil {
+		p.Failed = true
+		p.ExpiresAt = now.Add(failureTTL)
+		if !errors.Is(err, safehttp.ErrPrivateAddress) {
+			log.Printf("link preview for %s failed: %v", link, err)
+		}
+	} else {
+		p.Title, p.Description, p.Image, p.SiteName = meta.Title, meta.Description, meta.Image, meta.SiteName
+		p.ExpiresAt = now.Add(ttl())
+	}
+	if err := models.DB.Save(&p).Error; err != nil {
+		log.Printf("link preview cache save failed: %v", err)
+	}
+}
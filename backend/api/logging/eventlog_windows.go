@@ -0,0 +1,43 @@
+//go:build windows
+
+package logging
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventID 写入 Windows 事件日志的事件 ID
+const eventID = 1
+
+// openSystemLog 打开 Windows 事件日志，tag 为事件来源（安装服务时注册，见 service install），syslog 仅类 Unix 系统支持
+func openSystemLog(output, tag string) (systemLog, error) {
+	if output != "eventlog" {
+		return nil, errors.New("log.output syslog is not supported on Windows")
+	}
+	l, err := eventlog.Open(tag)
+	if err != nil {
+		return nil, err
+	}
+	return eventLogger{l}, nil
+}
+
+type eventLogger struct {
+	*eventlog.Log
+}
+
+func (e eventLogger) writer(l slog.Level) io.Writer {
+	write := e.Info
+	switch {
+	case l >= slog.LevelError:
+		write = e.Error
+	case l >= slog.LevelWarn:
+		write = e.Warning
+	}
+	return writerFunc(func(msg string) error {
+		return write(eventID, msg)
+	})
+}
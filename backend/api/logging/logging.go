@@ -0,0 +1,85 @@
+// Package logging 配置服务日志：按 log.format 输出 JSON 或 key=value 结构化日志到标准输出、按大小轮转的文件
+// 或系统日志（syslog / Windows 事件日志），并将标准库 log 的输出一并接入，使访问日志与其他日志格式一致。
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+var (
+	output io.Writer = os.Stdout
+	closer io.Closer
+	level  slog.LevelVar
+)
+
+// Init 按配置创建全局日志记录器，应在加载配置后、初始化其他模块前调用
+func Init() error {
+	cfg := config.Get().Log
+	if cfg.File != "" {
+		f, err := OpenRotatingFile(cfg.File, cfg.MaxSize<<20, cfg.MaxBackups, cfg.RotateInterval.Std(), cfg.MaxAge.Std())
+		if err != nil {
+			return err
+		}
+		output, closer = f, f
+	}
+
+	opts := &slog.HandlerOptions{Level: &level}
+	newHandler := func(w io.Writer) slog.Handler {
+		if cfg.Format == "text" {
+			return slog.NewTextHandler(w, opts)
+		}
+		return slog.NewJSONHandler(w, opts)
+	}
+	handler := newHandler(output)
+	// 写入系统日志时按记录级别选择严重程度
+	if cfg.File == "" && cfg.Output != "" && cfg.Output != "stdout" {
+		sys, err := openSystemLog(cfg.Output, cfg.Tag)
+		if err != nil {
+			return err
+		}
+		closer = sys
+		handler = newLevelHandler(sys, newHandler)
+	}
+	slog.SetDefault(slog.New(handler))
+	ApplyLevel()
+	return nil
+}
+
+// ApplyLevel 按当前配置 log.level 设置日志级别，重新加载配置后调用
+func ApplyLevel() {
+	l := parseLevel(config.Get().Log.Level)
+	level.Set(l)
+	// 标准库 log 的输出没有级别，按不低于 info 且不低于配置级别记录，确保始终输出
+	slog.SetLogLoggerLevel(max(l, slog.LevelInfo))
+}
+
+// parseLevel 将 log.level 转换为 slog 级别
+func parseLevel(name string) slog.Level {
+	switch name {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// Writer 日志输出目标
+func Writer() io.Writer {
+	return output
+}
+
+// Close 关闭日志文件（退出前调用）
+func Close() error {
+	if closer == nil {
+		return nil
+	}
+	return closer.Close()
+}
@@ -0,0 +1,135 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RotatingFile 按大小与时间轮转的日志文件：写入后超过 MaxBytes，或进入新的 Interval 周期（按 UTC 对齐，如每天 0 点）时，
+// 将当前文件依次重命名为 .1、.2……，最多保留 Backups 个旧文件，并删除修改时间早于 MaxAge 的旧文件
+type RotatingFile struct {
+	Path     string
+	MaxBytes int64
+	Backups  int
+	Interval time.Duration // 0 不按时间轮转
+	MaxAge   time.Duration // 0 不按时间清理
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	period time.Time // 当前文件所属的轮转周期
+}
+
+// OpenRotatingFile 以追加方式打开日志文件，目录不存在时自动创建
+func OpenRotatingFile(path string, maxBytes int64, backups int, interval, maxAge time.Duration) (*RotatingFile, error) {
+	r := &RotatingFile{Path: path, MaxBytes: maxBytes, Backups: backups, Interval: interval, MaxAge: maxAge}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	r.removeExpired()
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	// 已有内容的文件按最后写入时间归入周期，重启后跨周期的首次写入即轮转
+	r.period = r.truncate(time.Now())
+	if r.size > 0 {
+		r.period = r.truncate(info.ModTime())
+	}
+	return nil
+}
+
+func (r *RotatingFile) truncate(t time.Time) time.Time {
+	if r.Interval <= 0 {
+		return time.Time{}
+	}
+	return t.Truncate(r.Interval)
+}
+
+// Write 写入一条日志，必要时先轮转
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	bySize := r.MaxBytes > 0 && r.size+int64(len(p)) > r.MaxBytes
+	byTime := r.Interval > 0 && r.truncate(time.Now()).After(r.period)
+	if r.size > 0 && (bySize || byTime) {
+		if err := r.rotate(); err != nil {
+			// 轮转失败时继续写入当前文件，避免丢失日志
+			fmt.Fprintf(os.Stderr, "log rotation failed: %v\n", err)
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+	if r.Backups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", r.Path, r.Backups))
+		for i := r.Backups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.Path, i), fmt.Sprintf("%s.%d", r.Path, i+1))
+		}
+		if err := os.Rename(r.Path, r.Path+".1"); err != nil {
+			r.open()
+			return err
+		}
+	} else if err := os.Truncate(r.Path, 0); err != nil {
+		r.open()
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.removeExpired()
+	return nil
+}
+
+// removeExpired 删除修改时间早于 MaxAge 的旧日志文件
+func (r *RotatingFile) removeExpired() {
+	if r.MaxAge <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-r.MaxAge)
+	for i := 1; i <= r.Backups; i++ {
+		name := fmt.Sprintf("%s.%d", r.Path, i)
+		if info, err := os.Stat(name); err == nil && info.ModTime().Before(cutoff) {
+			os.Remove(name)
+		}
+	}
+}
+
+// Close 关闭日志文件
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package logging
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"log/syslog"
+)
+
+// openSystemLog 打开系统日志：syslog 写入本机 syslog 守护进程（daemon 设施），eventlog 仅 Windows 支持
+func openSystemLog(output, tag string) (systemLog, error) {
+	if output != "syslog" {
+		return nil, errors.New("log.output eventlog is only supported on Windows")
+	}
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return sysLogger{w}, nil
+}
+
+type sysLogger struct {
+	*syslog.Writer
+}
+
+func (s sysLogger) writer(l slog.Level) io.Writer {
+	switch {
+	case l >= slog.LevelError:
+		return writerFunc(s.Err)
+	case l >= slog.LevelWarn:
+		return writerFunc(s.Warning)
+	case l >= slog.LevelInfo:
+		return writerFunc(s.Info)
+	default:
+		return writerFunc(s.Debug)
+	}
+}
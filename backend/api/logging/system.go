@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+)
+
+// systemLog 系统日志（syslog / Windows 事件日志），按记录级别写入对应的严重程度
+type systemLog interface {
+	io.Closer
+	// writer 返回写入指定严重程度的输出，每次写入为一条日志
+	writer(l slog.Level) io.Writer
+}
+
+// writerFunc 将写入一条日志的函数适配为 io.Writer
+type writerFunc func(msg string) error
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	if err := f(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// levelHandler 按记录级别选择处理器：debug / info / warn / error 分别写入系统日志中对应的严重程度
+type levelHandler struct {
+	handlers [4]slog.Handler
+}
+
+// newLevelHandler 为每个严重程度创建一个处理器，newHandler 按输出创建 JSON 或 text 处理器
+func newLevelHandler(sys systemLog, newHandler func(w io.Writer) slog.Handler) *levelHandler {
+	h := &levelHandler{}
+	for i, l := range []slog.Level{slog.LevelDebug, slog.LevelInfo, slog.LevelWarn, slog.LevelError} {
+		h.handlers[i] = newHandler(sys.writer(l))
+	}
+	return h
+}
+
+func (h *levelHandler) pick(l slog.Level) slog.Handler {
+	switch {
+	case l >= slog.LevelError:
+		return h.handlers[3]
+	case l >= slog.LevelWarn:
+		return h.handlers[2]
+	case l >= slog.LevelInfo:
+		return h.handlers[1]
+	default:
+		return h.handlers[0]
+	}
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.pick(l).Enabled(ctx, l)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.pick(r.Level).Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := &levelHandler{}
+	for i, inner := range h.handlers {
+		next.handlers[i] = inner.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	next := &levelHandler{}
+	for i, inner := range h.handlers {
+		next.handlers[i] = inner.WithGroup(name)
+	}
+	return next
+}
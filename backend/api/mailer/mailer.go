@@ -0,0 +1,112 @@
+package mailer
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// ErrNotConfigured 未配置 SMTP
+var ErrNotConfigured = errors.New("mailer: smtp not configured")
+
+// Message 待发送的邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string            // 纯文本正文
+	Headers map[string]string // 额外邮件头（如 List-Unsubscribe）
+}
+
+// Enabled 是否已配置 SMTP
+func Enabled() bool {
+	return config.Get().SMTPEnabled()
+}
+
+// Send 发送纯文本邮件
+func Send(msg Message) error {
+	cfg := config.Get().SMTP
+	if cfg.Host == "" || cfg.From == "" {
+		return ErrNotConfigured
+	}
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+
+	var conn net.Conn
+	var err error
+	dialer := &net.Dialer{Timeout: 15 * time.Second}
+	if cfg.TLS == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: cfg.Host})
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(time.Now().Add(time.Minute))
+
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if cfg.TLS == "starttls" {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("mailer: server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(cfg.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMessage(cfg.From, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func buildMessage(from string, msg Message) []byte {
+	var b strings.Builder
+	header := func(k, v string) {
+		// 防止邮件头注入
+		v = strings.NewReplacer("\r", "", "\n", "").Replace(v)
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+	header("From", from)
+	header("To", msg.To)
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	for k, v := range msg.Headers {
+		header(k, v)
+	}
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
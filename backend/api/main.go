@@ -2,41 +2,96 @@ package main
 
 import (
 	"embed"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 
-	"github.com/ZeroHawkeye/siyuan-share-api/models"
-	"github.com/ZeroHawkeye/siyuan-share-api/routes"
-	"github.com/gin-gonic/gin"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/logging"
 )
 
+// 前端构建产物；嵌入前执行 go generate 生成 .br / .gz 预压缩文件
+//
+//go:generate go run precompress.go dist
 //go:embed dist/*
 var staticFiles embed.FS
 
 func main() {
-	// 初始化数据库
-	if err := models.InitDB(); err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+	configPath := flag.String("config", "", "配置文件路径（.yaml / .yml / .toml），默认读取 CONFIG_FILE 或当前目录下的 config.yaml")
+	flag.Usage = usage
+	flag.Parse()
+
+	// 子命令：不带子命令时启动服务
+	name, args := "serve", []string(nil)
+	if flag.NArg() > 0 {
+		name, args = flag.Arg(0), flag.Args()[1:]
+	}
+	run, ok := commands[name]
+	if name == "help" {
+		usage()
+		return
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
 	}
 
-	// 移除引导令牌流程：用户通过注册与个人中心管理 Token
+	// 加载并校验配置，存在无效配置时直接退出，避免带着错误配置运行
+	cfg, warnings, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	config.Set(cfg)
 
-	// 设置 Gin 模式
-	if os.Getenv("GIN_MODE") == "" {
-		gin.SetMode(gin.ReleaseMode)
+	// 服务（含以 Windows 服务运行）按配置切换为结构化日志（JSON / text，标准输出、轮转文件或系统日志）；
+	// 其他子命令的日志保持输出到标准错误，标准输出留给命令结果（如 token create 输出的 Token、backup create - 输出的备份包）
+	if name == "serve" || (name == "service" && len(args) > 0 && args[0] == "run") {
+		if err := logging.Init(); err != nil {
+			log.Fatalf("Failed to initialize logging: %v", err)
+		}
+	}
+	for _, w := range warnings {
+		log.Printf("Config warning: %s", w)
 	}
 
-	// 创建路由
-	r := routes.SetupRouter(&staticFiles)
+	os.Exit(run(args))
+}
 
-	// 启动服务器
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8088"
-	}
+// commands 子命令，返回进程退出码
+var commands = map[string]func(args []string) int{
+	"serve":   runServe,
+	"user":    runUser,
+	"token":   runToken,
+	"migrate": runMigrate,
+	"backup":  runBackup,
+	"seed":    runSeed,
+	"site":    runSite,
+	"storage": runStorage,
+	"service": runService,
+}
 
-	log.Printf("Server starting on port %s...", port)
-	if err := r.Run(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
-	}
+// usage 输出命令行用法
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: siyuan-share-api [-config <file>] <command> [arguments]
+
+Commands:
+  serve                      启动服务（默认）
+  user create                创建用户：-username <用户名> -email <邮箱> [-password <密码>] [-token-name <名称>]
+  user disable <用户名>      停用用户，撤销其登录会话与 API Token
+  token create <用户名>      为用户创建 API Token：[-name <名称>]
+  migrate [status|up]        查看或执行数据库迁移
+  backup create [-no-assets] <file|->
+  backup restore <file>      生成备份包或从备份包恢复（恢复前需停止服务）
+  site export [-title <标题>] [-base-url <地址>] <dir|file.zip|->
+                             将全部公开收录的分享导出为静态 HTML 站点（目录或 zip）
+  storage migrate -from <local|s3> -to <local|s3> [-dry-run] [-overwrite] [-delete-source]
+                             在存储后端之间按原键复制对象，资源地址不变
+  seed                       写入开发用示例数据：[-users 3] [-shares 12] [-prefix demo] [-password password] [-days 30] [-seed 1]
+  service install|uninstall  安装为 systemd 服务或 Windows 服务：[-name siyuan-share] [-data-dir <dir>] [-user <user>] [-env KEY=VALUE]... [-no-start]
+
+Flags:
+`)
+	flag.PrintDefaults()
 }
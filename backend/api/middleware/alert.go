@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/alert"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorAlert 统计 5xx 响应，达到 alert.server_errors 时告警；须注册在 Recovery 之前，以统计 panic 产生的 500
+func ErrorAlert() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			alert.ServerError()
+		}
+	}
+}
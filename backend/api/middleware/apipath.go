@@ -0,0 +1,11 @@
+package middleware
+
+import "strings"
+
+// legacyAPIPath 将 /api/v1 下的接口路径换算为对应的旧路径 /api/...，按路径前缀匹配的中间件对新旧路径同等处理
+func legacyAPIPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "/api/v1"); ok && (rest == "" || rest[0] == '/') {
+		return "/api" + rest
+	}
+	return path
+}
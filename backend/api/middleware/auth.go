@@ -4,10 +4,11 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/events"
 	"github.com/ZeroHawkeye/siyuan-share-api/models"
 	"github.com/gin-gonic/gin"
 	jwt "github.com/golang-jwt/jwt/v5"
@@ -18,82 +19,147 @@ import (
 // 2) 用户 API Token（user_tokens 表，长期令牌，供插件/CLI 使用）
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Authorization header required"})
+		if msg := authenticate(c); msg != "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": msg})
 			c.Abort()
 			return
 		}
+		c.Next()
+	}
+}
 
-		parts := strings.SplitN(authHeader, " ", 2)
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid authorization header format"})
-			c.Abort()
-			return
-		}
-		raw := strings.TrimSpace(parts[1])
+// IdentifyUser 识别请求携带的登录凭证并返回用户 ID，未携带或凭证无效时返回空字符串（不中断请求）；
+// 供公开接口按读者身份放行受限内容
+func IdentifyUser(c *gin.Context) string {
+	if userID := c.GetString("userID"); userID != "" {
+		return userID
+	}
+	if c.GetHeader("Authorization") == "" || authenticate(c) != "" {
+		return ""
+	}
+	return c.GetString("userID")
+}
 
-		// 优先尝试解析为 JWT 会话令牌
-		if userID, ok := parseJWT(raw); ok {
-			c.Set("userID", userID)
-			c.Next()
-			return
-		}
+// authenticate 校验 Authorization 头中的会话 JWT 或 API Token，成功时写入 userID 等上下文并返回空字符串，
+// 失败时返回错误信息
+func authenticate(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		return "Authorization header required"
+	}
 
-		// 回退为 API Token：查 user_tokens 表
-		hash := sha256.Sum256([]byte(raw))
-		tokenHash := hex.EncodeToString(hash[:])
+	parts := strings.SplitN(authHeader, " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return "Invalid authorization header format"
+	}
+	raw := strings.TrimSpace(parts[1])
 
-		var ut models.UserToken
-		if err := models.DB.Where("token_hash = ? AND revoked = ?", tokenHash, false).First(&ut).Error; err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid or revoked token"})
-			c.Abort()
-			return
+	// 优先尝试解析为 JWT 会话令牌，并校验对应会话未被撤销
+	if userID, sessionID, ok := parseJWT(raw); ok {
+		var session models.Session
+		if err := models.DB.Where("id = ? AND user_id = ? AND revoked = ? AND expires_at > ?",
+			sessionID, userID, false, time.Now()).First(&session).Error; err != nil {
+			return "Session revoked or expired"
 		}
+		touchSession(c, &session)
+		c.Set("userID", userID)
+		c.Set("sessionID", session.ID)
+		return ""
+	}
 
-		// 校验用户是否可用
-		var user models.User
-		if err := models.DB.Where("id = ? AND is_active = ?", ut.UserID, true).First(&user).Error; err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "User inactive or not found"})
-			c.Abort()
+	// 回退为 API Token：查 user_tokens 表
+	hash := sha256.Sum256([]byte(raw))
+	tokenHash := hex.EncodeToString(hash[:])
+
+	var ut models.UserToken
+	if err := models.DB.Where("token_hash = ? AND revoked = ?", tokenHash, false).First(&ut).Error; err != nil {
+		return "Invalid or revoked token"
+	}
+	// 设置了来源地址白名单的 Token 只接受白名单内的请求
+	if !ut.AllowsIP(c.ClientIP()) {
+		return "Token not allowed from this IP address"
+	}
+
+	// 校验用户是否可用
+	var user models.User
+	if err := models.DB.Where("id = ? AND is_active = ?", ut.UserID, true).First(&user).Error; err != nil {
+		return "User inactive or not found"
+	}
+
+	// 记录使用时间、来源与请求数（批量异步写库，不阻断主流程）
+	models.RecordTokenUse(ut.ID, c.ClientIP(), c.Request.UserAgent())
+	events.TokenUse(user.ID, ut.ID, c.ClientIP())
+
+	c.Set("userID", user.ID)
+	c.Set("username", user.Username)
+	c.Set("tokenID", ut.ID)
+	return ""
+}
+
+// OptionalAuthMiddleware 可选认证：未携带 Authorization 头时按匿名访问继续处理，
+// 携带时与 AuthMiddleware 相同（凭证无效返回 401）
+func OptionalAuthMiddleware() gin.HandlerFunc {
+	auth := AuthMiddleware()
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			c.Next()
 			return
 		}
+		auth(c)
+	}
+}
 
-		// 更新最近使用时间（不阻断主流程）
-		now := time.Now()
-		models.DB.Model(&ut).Update("last_used_at", &now)
-
-		c.Set("userID", user.ID)
-		c.Set("username", user.Username)
-		c.Next()
+// touchSession 更新会话最近活跃时间与 IP，5 分钟内不重复写库
+func touchSession(c *gin.Context, session *models.Session) {
+	ip := c.ClientIP()
+	if time.Since(session.LastSeenAt) < 5*time.Minute && session.IP == ip {
+		return
 	}
+	models.DB.Model(session).Updates(map[string]interface{}{"last_seen_at": time.Now(), "ip": ip})
 }
 
-func parseJWT(tokenString string) (string, bool) {
+// parseJWT 解析会话 JWT，返回用户 ID 与会话 ID（jti）
+func parseJWT(tokenString string) (string, string, bool) {
 	if strings.Count(tokenString, ".") != 2 {
-		return "", false
-	}
-	secret := os.Getenv("SESSION_SECRET")
-	if secret == "" {
-		secret = "dev-secret"
+		return "", "", false
 	}
 	tok, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
-		// 默认使用 HMAC 方法
-		return []byte(secret), nil
+		// 默认使用 HMAC 方法；轮换后旧密钥签发的会话仍然有效
+		var set jwt.VerificationKeySet
+		for _, secret := range config.Get().SessionSecrets() {
+			set.Keys = append(set.Keys, secret)
+		}
+		return set, nil
 	}, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
 	if err != nil || !tok.Valid {
-		return "", false
+		return "", "", false
 	}
 	if claims, ok := tok.Claims.(jwt.MapClaims); ok {
 		// 过期校验
 		if exp, has := claims["exp"].(float64); has {
 			if time.Unix(int64(exp), 0).Before(time.Now()) {
-				return "", false
+				return "", "", false
 			}
 		}
-		if sub, has := claims["sub"].(string); has && sub != "" {
-			return sub, true
+		sub, _ := claims["sub"].(string)
+		jti, _ := claims["jti"].(string)
+		if sub != "" && jti != "" {
+			return sub, jti, true
 		}
 	}
-	return "", false
+	return "", "", false
+}
+
+// AdminMiddleware 管理员校验，需在 AuthMiddleware 之后使用；管理员由 auth.admins 配置的用户名指定
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user models.User
+		if err := models.DB.Select("id", "username").Where("id = ?", c.GetString("userID")).First(&user).Error; err != nil ||
+			!config.Get().IsAdmin(user.Username) {
+			c.JSON(http.StatusForbidden, gin.H{"code": 1, "msg": "Admin permission required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
 }
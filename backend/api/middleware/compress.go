@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+// compressMinBytes 已知长度小于该值的响应不压缩，压缩头部开销大于收益
+const compressMinBytes = 1024
+
+var (
+	gzipWriters   = sync.Pool{New: func() any { w, _ := gzip.NewWriterLevel(io.Discard, gzip.BestSpeed); return w }}
+	brotliWriters = sync.Pool{New: func() any { return brotli.NewWriterLevel(io.Discard, 4) }}
+)
+
+// AcceptsEncoding Accept-Encoding 是否接受指定编码（q=0 表示不接受）
+func AcceptsEncoding(acceptEncoding, coding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), coding) {
+			continue
+		}
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v <= 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// negotiateEncoding 按 Accept-Encoding 选择响应编码：优先 br，其次 gzip，都不接受时返回空
+func negotiateEncoding(acceptEncoding string) string {
+	for _, coding := range []string{"br", "gzip"} {
+		if AcceptsEncoding(acceptEncoding, coding) {
+			return coding
+		}
+	}
+	return ""
+}
+
+// compressible 是否为值得压缩的文本类内容；图片、音视频与压缩包等本身已压缩
+func compressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if strings.HasPrefix(mediaType, "text/") && mediaType != "text/event-stream" {
+		return true
+	}
+	if strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml", "application/wasm", "application/x-ndjson", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// Compress 按 Accept-Encoding 以 brotli 或 gzip 压缩文本类响应。已设置 Content-Encoding 的响应（如预压缩的静态文件）、
+// 范围请求、不可压缩的类型与已知长度过小的响应原样输出；excluded 中的路径前缀（事件流、备份包等）不压缩
+func Compress(excluded ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, prefix := range excluded {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+		if c.Request.Method == http.MethodHead || c.GetHeader("Range") != "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+		w := &compressWriter{ResponseWriter: c.Writer, encoding: encoding}
+		c.Writer = w
+		defer func() {
+			w.close()
+			c.Writer = w.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// compressWriter 在首次写入正文时按响应头决定是否压缩
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	decided  bool
+	encoder  interface {
+		io.Writer
+		Flush() error
+		Close() error
+	}
+}
+
+// decide 首次写入前检查状态码与响应头，需要压缩时改写 Content-Encoding、Content-Length 与 ETag
+func (w *compressWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	h := w.Header()
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusPartialContent || status == http.StatusNotModified {
+		return
+	}
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" || !compressible(h.Get("Content-Type")) {
+		return
+	}
+	if n, err := strconv.Atoi(h.Get("Content-Length")); err == nil && n < compressMinBytes {
+		return
+	}
+	h.Set("Content-Encoding", w.encoding)
+	h.Del("Content-Length")
+	// 压缩后的字节与原文不同，强 ETag 改为弱 ETag
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+	if w.encoding == "br" {
+		bw := brotliWriters.Get().(*brotli.Writer)
+		bw.Reset(w.ResponseWriter)
+		w.encoder = bw
+	} else {
+		gw := gzipWriters.Get().(*gzip.Writer)
+		gw.Reset(w.ResponseWriter)
+		w.encoder = gw
+	}
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	w.ResponseWriter.WriteHeaderNow()
+	return w.encoder.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 先刷新压缩器缓冲的数据，流式导出等逐段输出的响应能及时送达
+func (w *compressWriter) Flush() {
+	if w.encoder != nil {
+		_ = w.encoder.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// close 写出压缩尾部并归还压缩器
+func (w *compressWriter) close() {
+	if w.encoder == nil {
+		return
+	}
+	_ = w.encoder.Close()
+	switch e := w.encoder.(type) {
+	case *brotli.Writer:
+		e.Reset(io.Discard)
+		brotliWriters.Put(e)
+	case *gzip.Writer:
+		e.Reset(io.Discard)
+		gzipWriters.Put(e)
+	}
+	w.encoder = nil
+}
@@ -1,27 +1,64 @@
 package middleware
 
 import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
 	"github.com/gin-gonic/gin"
 )
 
-// CORSMiddleware CORS 中间件
+// corsAllowHeaders 插件与阅读页使用的请求头
+const corsAllowHeaders = "Content-Type, Content-Length, Authorization, X-Base-URL, X-Content-SHA256, X-Share-Password, X-Share-Access, X-Share-Terms, X-Share-View, X-Share-Print, X-Share-Referrer, X-Challenge-Token, X-Request-ID"
+
+// corsExposeHeaders 允许插件读取的限流/配额反馈头与请求 ID
+const corsExposeHeaders = "X-RateLimit-Limit, X-RateLimit-Remaining, X-RateLimit-Reset, X-Quota-Limit, X-Quota-Remaining, X-Quota-Reset, Retry-After, X-Request-ID"
+
+// originAllowed 判断来源是否在 cors.allow_origins 中，https://*.example.com 匹配任意子域名
+func originAllowed(origins []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, o := range origins {
+		if o == "*" || o == origin {
+			return true
+		}
+		if scheme, host, ok := strings.Cut(o, "://*."); ok &&
+			strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host) {
+			return true
+		}
+	}
+	return false
+}
+
+// CORSMiddleware 按 cors 配置响应跨域请求，并直接应答预检请求；来源不在允许列表时不返回跨域头，由浏览器拦截
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		cfg := config.Get().CORS
+		anyOrigin := len(cfg.AllowOrigins) == 1 && cfg.AllowOrigins[0] == "*"
 		origin := c.GetHeader("Origin")
-		if origin != "" {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-			c.Writer.Header().Set("Vary", "Origin")
-		} else {
-			c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		h := c.Writer.Header()
+		if !anyOrigin {
+			h.Add("Vary", "Origin")
+		}
+		if origin != "" && originAllowed(cfg.AllowOrigins, origin) {
+			if anyOrigin {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+			h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			h.Set("Access-Control-Allow-Methods", strings.Join(cfg.AllowMethods, ", "))
+			h.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+			if cfg.MaxAge > 0 {
+				h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Std().Seconds())))
+			}
 		}
 
-		// Bearer Token 方案通常不需要 Credentials
-		// 若未来需要携带 Cookie，可在特定路由开启：c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Authorization, X-Base-URL, X-Bootstrap-Token")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
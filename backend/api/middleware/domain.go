@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// domainBinding 已验证的自定义域名对应的用户
+type domainBinding struct {
+	userID   string
+	username string
+	homePath string
+}
+
+var domains atomic.Pointer[map[string]*domainBinding]
+
+// ReloadDomains 从数据库重新加载已验证的自定义域名，绑定、验证或解绑后调用
+func ReloadDomains() error {
+	var rows []struct {
+		Domain   string
+		UserID   string
+		Username string
+		HomePath string
+	}
+	if err := models.DB.Table("custom_domains").
+		Select("custom_domains.domain, custom_domains.user_id, users.username, custom_domains.home_path").
+		Joins("JOIN users ON users.id = custom_domains.user_id").
+		Where("custom_domains.verified_at IS NOT NULL").Scan(&rows).Error; err != nil {
+		return err
+	}
+	table := make(map[string]*domainBinding, len(rows))
+	for _, r := range rows {
+		table[r.Domain] = &domainBinding{userID: r.UserID, username: r.Username, homePath: r.HomePath}
+	}
+	domains.Store(&table)
+	return nil
+}
+
+// requestHost 去掉端口并转为小写的请求域名
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// CustomDomains 按请求域名路由：自定义域名下只提供绑定用户的分享阅读页及其公开接口、合集首页、订阅源与前端静态资源，
+// 其他用户的分享与合集、登录和管理页面一律返回 404；访问根路径时跳转到绑定时设置的首页。
+// 绑定用户写入上下文 domainOwner
+func CustomDomains() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		table := domains.Load()
+		if table == nil {
+			if err := ReloadDomains(); err != nil {
+				c.Next()
+				return
+			}
+			table = domains.Load()
+		}
+		if len(*table) == 0 {
+			c.Next()
+			return
+		}
+		d, ok := (*table)[requestHost(c.Request)]
+		if !ok {
+			c.Next()
+			return
+		}
+		c.Set("domainOwner", d.userID)
+
+		path := c.Request.URL.Path
+		if path == "/" && d.homePath != "" {
+			c.Redirect(http.StatusFound, d.homePath)
+			c.Abort()
+			return
+		}
+		if !d.allows(legacyAPIPath(path)) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"code": 404, "msg": "not found"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// allows 自定义域名下是否允许访问该路径
+func (d *domainBinding) allows(path string) bool {
+	switch {
+	case path == "/healthz", path == "/readyz":
+		return true
+	case strings.HasPrefix(path, "/u/"):
+		return strings.HasPrefix(path, "/u/"+d.username+"/")
+	case strings.HasPrefix(path, "/assets/"):
+		// 前端构建产物
+		return true
+	case strings.HasPrefix(path, "/api/subscriptions/"):
+		// 订阅确认与退订链接使用订阅时的域名
+		return true
+	case strings.Count(path, "/") == 1 && strings.Contains(path, "."):
+		// 站点根目录下的图标、Service Worker 等静态文件；站点地图包含全站分享，不在自定义域名下提供
+		return path != "/sitemap.xml" && path != "/robots.txt"
+	}
+	for _, p := range []struct {
+		prefix string
+		model  any
+		column string
+	}{
+		{"/s/", &models.Share{}, "id"},
+		{"/api/s/", &models.Share{}, "id"},
+		{"/c/", &models.Collection{}, "slug"},
+		{"/api/c/", &models.Collection{}, "slug"},
+	} {
+		if !strings.HasPrefix(path, p.prefix) {
+			continue
+		}
+		key, _, _ := strings.Cut(strings.TrimPrefix(path, p.prefix), "/")
+		if p.column == "slug" {
+			key = strings.ToLower(key)
+		}
+		var owners []string
+		models.DB.Model(p.model).Where(p.column+" = ?", key).Pluck("user_id", &owners)
+		// 不存在的分享或合集交由后续处理返回 404
+		return len(owners) == 0 || owners[0] == d.userID
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/gin-gonic/gin"
+)
+
+// FrameOptions 按 embed.frame_options 限制页面被放入 iframe（X-Frame-Options 与 CSP frame-ancestors），
+// 防止仪表盘等页面被其他网站嵌入后诱导点击；嵌入页通过 AllowFraming 放开
+func FrameOptions() gin.HandlerFunc {
+	var xfo, ancestors string
+	switch strings.ToUpper(config.Get().Embed.FrameOptions) {
+	case "DENY":
+		xfo, ancestors = "DENY", "'none'"
+	case "SAMEORIGIN":
+		xfo, ancestors = "SAMEORIGIN", "'self'"
+	default:
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		c.Header("X-Frame-Options", xfo)
+		c.Header("Content-Security-Policy", "frame-ancestors "+ancestors)
+		c.Next()
+	}
+}
+
+// AllowFraming 允许当前响应被 embed.frame_ancestors 中的网站嵌入：移除 X-Frame-Options
+// （不支持来源列表），以 CSP frame-ancestors 声明允许的来源
+func AllowFraming(c *gin.Context) {
+	h := c.Writer.Header()
+	h.Del("X-Frame-Options")
+	h.Set("Content-Security-Policy", "frame-ancestors 'self' "+strings.Join(config.Get().Embed.FrameAncestors, " "))
+}
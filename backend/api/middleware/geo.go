@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/geoip"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// blockPageTemplate 未配置 geo.block_page 时展示的页面，按请求语言填入标题与说明
+const blockPageTemplate = `<!DOCTYPE html>
+<html lang="%s">
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1" />
+<meta name="robots" content="noindex" />
+<title>%s</title>
+<style>body{font-family:system-ui,sans-serif;display:flex;align-items:center;justify-content:center;min-height:90vh;margin:0;color:#333}main{max-width:32rem;padding:2rem;text-align:center}h1{font-size:1.5rem}p{color:#666;line-height:1.6}</style>
+</head>
+<body>
+<main>
+<h1>%s</h1>
+<p>%s</p>
+</main>
+</body>
+</html>
+`
+
+// defaultBlockPage 按语言生成内置拦截页
+func defaultBlockPage(locale string) []byte {
+	return []byte(fmt.Sprintf(blockPageTemplate, locale, i18n.T(locale, "page.geo_blocked.title"),
+		i18n.T(locale, "page.geo_blocked.heading"), i18n.T(locale, "page.geo_blocked.body")))
+}
+
+var blockPage atomic.Pointer[[]byte]
+
+// ReloadBlockPage 读取配置 geo.block_page 指定的拦截页，重新加载配置后调用；未配置或读取失败时使用内置拦截页
+func ReloadBlockPage() {
+	var page []byte
+	if path := config.Get().Geo.BlockPage; path != "" {
+		if data, err := os.ReadFile(path); err != nil {
+			log.Printf("Failed to read geo block page, using default: %v", err)
+		} else {
+			page = data
+		}
+	}
+	blockPage.Store(&page)
+}
+
+// geoRestrictedPrefixes 受国家/地区限制的读者访问路径
+var geoRestrictedPrefixes = []string{"/s/", "/api/s/", "/c/", "/api/c/", "/u/", "/api/search"}
+
+// GeoRestrict 按配置 geo 拒绝来自受限国家/地区的读者访问分享：页面请求返回拦截页，接口请求返回 JSON 错误，
+// 状态码均为 451。登录、分享管理等接口不受影响
+func GeoRestrict() gin.HandlerFunc {
+	ReloadBlockPage()
+
+	return func(c *gin.Context) {
+		cfg := config.Get().Geo
+		if !cfg.Enabled() {
+			c.Next()
+			return
+		}
+		path := legacyAPIPath(c.Request.URL.Path)
+		restricted := false
+		for _, prefix := range geoRestrictedPrefixes {
+			if strings.HasPrefix(path, prefix) {
+				restricted = true
+				break
+			}
+		}
+		if !restricted {
+			c.Next()
+			return
+		}
+		ip := c.ClientIP()
+		country := geoip.Country(c.Request, ip)
+		// 无法识别国家的本机请求（如导出 PDF 时 Chromium 访问阅读页）不受限制
+		if addr, err := netip.ParseAddr(ip); country == "" && err == nil && addr.IsLoopback() {
+			c.Next()
+			return
+		}
+		if !cfg.Blocked(country) {
+			c.Next()
+			return
+		}
+		c.Header("Cache-Control", "no-store")
+		if cfg.Header != "" {
+			c.Header("Vary", cfg.Header)
+		}
+		// 先于错误信息翻译中间件执行，直接按请求语言返回
+		locale := Locale(c)
+		if strings.HasPrefix(path, "/api/") {
+			msg := "Content is not available in your region"
+			c.AbortWithStatusJSON(http.StatusUnavailableForLegalReasons, gin.H{"code": 1, "msg": i18n.Message(locale, msg), "msgKey": msg})
+			return
+		}
+		body := *blockPage.Load()
+		if body == nil {
+			c.Writer.Header().Add("Vary", "Accept-Language")
+			body = defaultBlockPage(locale)
+		}
+		c.Data(http.StatusUnavailableForLegalReasons, "text/html; charset=utf-8", body)
+		c.Abort()
+	}
+}
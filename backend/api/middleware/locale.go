@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Locale 返回请求使用的语言：已登录用户的语言偏好优先，其次按 Accept-Language 协商，最后使用 server.locale；
+// 结果缓存在上下文 locale 中
+func Locale(c *gin.Context) string {
+	if l := c.GetString("locale"); l != "" {
+		return l
+	}
+	locale := ""
+	if userID := c.GetString("userID"); userID != "" {
+		var user models.User
+		if err := models.DB.Select("locale").Where("id = ?", userID).First(&user).Error; err == nil {
+			locale = i18n.Match(user.Locale)
+		}
+	}
+	if locale == "" {
+		locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+	}
+	c.Set("locale", locale)
+	return locale
+}
+
+// localizeWriter 将 JSON 错误响应中的 msg 翻译为请求语言
+type localizeWriter struct {
+	gin.ResponseWriter
+	c    *gin.Context
+	done bool
+}
+
+func (w *localizeWriter) Write(b []byte) (int, error) {
+	if w.done || w.Status() < http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") ||
+		len(b) < 2 || b[0] != '{' {
+		return w.ResponseWriter.Write(b)
+	}
+	w.done = true
+	var body map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&body); err != nil {
+		return w.ResponseWriter.Write(b)
+	}
+	msg, ok := body["msg"].(string)
+	if !ok {
+		return w.ResponseWriter.Write(b)
+	}
+	translated := i18n.Message(Locale(w.c), msg)
+	if translated == msg {
+		return w.ResponseWriter.Write(b)
+	}
+	body["msg"] = translated
+	body["msgKey"] = msg
+	out, err := json.Marshal(body)
+	if err != nil {
+		return w.ResponseWriter.Write(b)
+	}
+	if _, err := w.ResponseWriter.Write(out); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *localizeWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// LocalizeErrors 将 JSON 错误响应的 msg 翻译为请求语言，英文原文保留在 msgKey 中供客户端判断错误类型；
+// 须注册在响应压缩之后
+func LocalizeErrors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = &localizeWriter{ResponseWriter: c.Writer, c: c}
+		c.Next()
+	}
+}
@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"runtime/debug"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader 请求 ID 请求头与响应头
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDPattern 接受上游（反向代理、插件）传入的请求 ID 的格式
+var requestIDPattern = regexp.MustCompile(`^[0-9A-Za-z._-]{8,64}$`)
+
+// RequestID 为每个请求分配 ID（沿用格式合法的 X-Request-ID 请求头），写入上下文 requestID 与响应头，
+// 便于将用户反馈的错误与服务端日志对应
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			b := make([]byte, 8)
+			rand.Read(b)
+			id = hex.EncodeToString(b)
+		}
+		c.Set("requestID", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// AccessLog 以结构化日志记录每个请求（成功的健康检查除外）：5xx 为 error，处理时间超过 log.slow_request 的请求为 warn 并标记 slow
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg := config.Get().Log
+		if !cfg.Access {
+			c.Next()
+			return
+		}
+		slow := cfg.SlowRequest.Std()
+		start := time.Now()
+		path := c.Request.URL.Path
+		c.Next()
+
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		// 健康检查探针请求频繁，仅在失败时记录
+		if (path == "/healthz" || path == "/readyz") && status < http.StatusBadRequest {
+			return
+		}
+		attrs := []slog.Attr{
+			slog.String("request_id", c.GetString("requestID")),
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(latency.Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.String("ip", c.ClientIP()),
+		}
+		if userID := c.GetString("userID"); userID != "" {
+			attrs = append(attrs, slog.String("user_id", userID))
+		}
+		if ua := c.Request.UserAgent(); ua != "" {
+			attrs = append(attrs, slog.String("user_agent", ua))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+
+		level := slog.LevelInfo
+		// 事件流是长连接，持续时间不代表处理慢
+		streaming := strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "text/event-stream")
+		if slow > 0 && latency >= slow && !streaming {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.Bool("slow", true))
+		}
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recovery 捕获处理过程中的 panic，记录堆栈并返回带请求 ID 的 500 响应；客户端断开导致的写入失败只记录一行
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			requestID := c.GetString("requestID")
+			if err, ok := rec.(error); ok && brokenPipe(err) {
+				slog.Warn("client connection closed", "request_id", requestID, "path", c.Request.URL.Path, "error", err)
+				c.Abort()
+				return
+			}
+			slog.Error("panic recovered", "request_id", requestID, "method", c.Request.Method, "path", c.Request.URL.Path,
+				"panic", rec, "stack", string(debug.Stack()))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Internal server error", "requestId": requestID})
+		}()
+		c.Next()
+	}
+}
+
+func brokenPipe(err error) bool {
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if errors.As(opErr, &sysErr) {
+		return errors.Is(sysErr.Err, syscall.EPIPE) || errors.Is(sysErr.Err, syscall.ECONNRESET)
+	}
+	return false
+}
+
+// errorBodyWriter 在 JSON 错误响应的顶层对象中加入 requestId 字段
+type errorBodyWriter struct {
+	gin.ResponseWriter
+	requestID string
+	done      bool
+}
+
+func (w *errorBodyWriter) Write(b []byte) (int, error) {
+	if w.done || w.Status() < http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") ||
+		len(b) < 2 || b[0] != '{' || bytes.Contains(b, []byte(`"requestId"`)) {
+		return w.ResponseWriter.Write(b)
+	}
+	w.done = true
+	field := `"requestId":"` + w.requestID + `"`
+	if b[1] != '}' {
+		field += ","
+	}
+	if _, err := w.ResponseWriter.Write([]byte("{" + field)); err != nil {
+		return 0, err
+	}
+	n, err := w.ResponseWriter.Write(b[1:])
+	return n + 1, err
+}
+
+func (w *errorBodyWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// ErrorRequestID 为 JSON 错误响应附加请求 ID，须注册在响应压缩之后，使其看到未压缩的响应体
+func ErrorRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer = &errorBodyWriter{ResponseWriter: c.Writer, requestID: c.GetString("requestID")}
+		c.Next()
+	}
+}
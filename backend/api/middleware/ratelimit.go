@@ -0,0 +1,334 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/gin-gonic/gin"
+)
+
+// windowLimiter 固定窗口计数器，按 key（用户 ID）统计请求次数
+type windowLimiter struct {
+	mu      sync.Mutex
+	limit   int
+	period  time.Duration
+	windows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+func newWindowLimiter(limit int, period time.Duration) *windowLimiter {
+	return &windowLimiter{limit: limit, period: period, windows: map[string]*rateWindow{}}
+}
+
+// take 消耗一次额度，返回剩余次数、窗口重置时间以及是否允许
+func (l *windowLimiter) take(key string, now time.Time) (int, time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 顺带清理过期窗口，避免 map 无限增长
+	if len(l.windows) > 10000 {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.period {
+				delete(l.windows, k)
+			}
+		}
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.period {
+		w = &rateWindow{start: now}
+		l.windows[key] = w
+	}
+	reset := w.start.Add(l.period)
+	if w.count >= l.limit {
+		return 0, reset, false
+	}
+	w.count++
+	return l.limit - w.count, reset, true
+}
+
+// limiterEpoch 递增后所有限流器重新开始计数，见 ResetRateLimits
+var limiterEpoch atomic.Int64
+
+// ResetRateLimits 清空所有限流计数（测试模式重置数据时调用）
+func ResetRateLimits() {
+	limiterEpoch.Add(1)
+}
+
+// reloadableLimiter 按当前配置的限额计数的限流器：限额在重新加载配置后变化时重新开始计数
+type reloadableLimiter struct {
+	mu      sync.Mutex
+	period  time.Duration
+	limiter *windowLimiter
+	epoch   int64
+}
+
+// get 返回限额为 limit 的限流器，limit 不大于 0 表示不限制，返回 nil
+func (r *reloadableLimiter) get(limit int) *windowLimiter {
+	if limit <= 0 {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	epoch := limiterEpoch.Load()
+	if r.limiter == nil || r.limiter.limit != limit || r.epoch != epoch {
+		r.limiter = newWindowLimiter(limit, r.period)
+		r.epoch = epoch
+	}
+	return r.limiter
+}
+
+// PublishRateLimit 发布类接口的限流中间件（需在 AuthMiddleware 之后使用）
+// 短周期突发限额：rate_limit.publish_per_minute 次/分钟（默认 60，0 表示不限制），返回 X-RateLimit-* 头
+// 每日发布配额：rate_limit.publish_daily_quota 次/天（默认 0 不限制），返回 X-Quota-* 头
+// 便于自动重发布、CLI 批量任务等客户端根据响应头自行节流
+func PublishRateLimit() gin.HandlerFunc {
+	bursts := &reloadableLimiter{period: time.Minute}
+	dailies := &reloadableLimiter{period: 24 * time.Hour}
+
+	return func(c *gin.Context) {
+		cfg := config.Get().RateLimit
+		burst, daily := bursts.get(cfg.PublishPerMinute), dailies.get(cfg.PublishDailyQuota)
+		key := c.GetString("userID")
+		if key == "" {
+			key = c.ClientIP()
+		}
+		now := time.Now()
+
+		if burst != nil {
+			remaining, reset, ok := burst.take(key, now)
+			c.Header("X-RateLimit-Limit", strconv.Itoa(burst.limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+			c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+			if !ok {
+				c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+				c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Rate limit exceeded, please retry later"})
+				c.Abort()
+				return
+			}
+		}
+
+		if daily != nil {
+			remaining, reset, ok := daily.take(key, now)
+			c.Header("X-Quota-Limit", strconv.Itoa(daily.limit))
+			c.Header("X-Quota-Remaining", strconv.Itoa(remaining))
+			c.Header("X-Quota-Reset", strconv.FormatInt(reset.Unix(), 10))
+			if !ok {
+				c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+				c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Daily publish quota exhausted"})
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// CommentRateLimit 读者评论限流：按客户端 IP 每小时 rate_limit.comments_per_hour 次（默认 10，0 表示不限制）
+func CommentRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.CommentsPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many comments, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// QuestionRateLimit 读者提问限流：按客户端 IP 每小时 rate_limit.questions_per_hour 次（默认 20，0 表示不限制）
+func QuestionRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.QuestionsPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many questions, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ReportRateLimit 举报限流：按客户端 IP 每小时 rate_limit.reports_per_hour 次（默认 5，0 表示不限制）
+func ReportRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.ReportsPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many reports, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// FeedbackRateLimit 读者反馈限流：按客户端 IP 每小时 rate_limit.feedback_per_hour 次（默认 20，0 表示不限制）
+func FeedbackRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.FeedbackPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too much feedback, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// VoteRateLimit 读者投票限流：按客户端 IP 每小时 rate_limit.votes_per_hour 次（默认 30，0 表示不限制）
+func VoteRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.VotesPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many votes, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// FormRateLimit 读者提交表单限流：按客户端 IP 每小时 rate_limit.forms_per_hour 次（默认 10，0 表示不限制）
+func FormRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.FormsPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many submissions, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// ProgressRateLimit 读者保存阅读进度限流：按客户端 IP 每小时 rate_limit.progress_per_hour 次（默认 600，0 表示不限制）
+func ProgressRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.ProgressPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many progress updates, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// OrderRateLimit 读者创建付费阅读订单限流：按客户端 IP 每小时 rate_limit.orders_per_hour 次（默认 10，0 表示不限制）
+func OrderRateLimit() gin.HandlerFunc {
+	limiters := &reloadableLimiter{period: time.Hour}
+
+	return func(c *gin.Context) {
+		limiter := limiters.get(config.Get().RateLimit.OrdersPerHour)
+		if limiter == nil {
+			c.Next()
+			return
+		}
+		now := time.Now()
+		remaining, reset, ok := limiter.take(c.ClientIP(), now)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limiter.limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		if !ok {
+			c.Header("Retry-After", strconv.Itoa(int(reset.Sub(now).Seconds())+1))
+			c.JSON(http.StatusTooManyRequests, gin.H{"code": 1, "msg": "Too many orders, please retry later"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
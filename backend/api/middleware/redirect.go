@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+type redirectPattern struct {
+	re     *regexp.Regexp
+	target string
+	status int
+}
+
+// redirectTable 编译后的重定向规则：精确路径优先，其次按 ID 顺序匹配正则规则
+type redirectTable struct {
+	exact    map[string]*models.Redirect
+	patterns []redirectPattern
+}
+
+var redirects atomic.Pointer[redirectTable]
+
+// ReloadRedirects 从数据库重新加载重定向规则，规则变更后调用；无法编译的正则规则被忽略
+func ReloadRedirects() error {
+	var rules []models.Redirect
+	if err := models.DB.Order("id").Find(&rules).Error; err != nil {
+		return err
+	}
+	table := &redirectTable{exact: map[string]*models.Redirect{}}
+	for i := range rules {
+		r := &rules[i]
+		if !r.Regex {
+			if _, ok := table.exact[r.Source]; !ok {
+				table.exact[r.Source] = r
+			}
+			continue
+		}
+		re, err := regexp.Compile(r.Source)
+		if err != nil {
+			continue
+		}
+		table.patterns = append(table.patterns, redirectPattern{re: re, target: r.Target, status: r.Status})
+	}
+	redirects.Store(table)
+	return nil
+}
+
+// match 返回路径对应的跳转地址与状态码
+func (t *redirectTable) match(path string) (string, int, bool) {
+	if r, ok := t.exact[path]; ok {
+		return r.Target, r.Status, true
+	}
+	for _, p := range t.patterns {
+		if m := p.re.FindStringSubmatchIndex(path); m != nil {
+			return string(p.re.ExpandString(nil, p.target, path, m)), p.status, true
+		}
+	}
+	return "", 0, false
+}
+
+// Redirects 在路由处理前按管理员配置的规则重定向旧链接；仅处理 GET/HEAD 请求，/api 下的接口不受影响。
+// 目标地址不含查询参数时保留原请求的查询参数
+func Redirects() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		path := c.Request.URL.Path
+		if path == "/api" || strings.HasPrefix(path, "/api/") {
+			c.Next()
+			return
+		}
+		table := redirects.Load()
+		if table == nil {
+			if err := ReloadRedirects(); err != nil {
+				c.Next()
+				return
+			}
+			table = redirects.Load()
+		}
+		if len(table.exact) == 0 && len(table.patterns) == 0 {
+			c.Next()
+			return
+		}
+		target, status, ok := table.match(path)
+		if !ok {
+			c.Next()
+			return
+		}
+		if q := c.Request.URL.RawQuery; q != "" && !strings.Contains(target, "?") {
+			target += "?" + q
+		}
+		c.Redirect(status, target)
+		c.Abort()
+	}
+}
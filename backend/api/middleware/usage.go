@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+// Usage 统计接口用量：已认证的请求计入当前用户（及所用 API Token）的请求数；响应流量计入分享所有者
+// （读取分享与资源时由处理函数写入上下文 contentOwner），否则计入当前用户；
+// 处理函数写入 contentShare 时同时计入该分享的月度读者流量
+func Usage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		bytes := int64(max(c.Writer.Size(), 0))
+		userID := c.GetString("userID")
+		owner := c.GetString("contentOwner")
+		if userID != "" {
+			d := models.UsageDelta{Requests: 1}
+			if owner == "" || owner == userID {
+				d.Bytes = bytes
+			}
+			models.RecordUsage(userID, c.GetString("tokenID"), d)
+		}
+		if owner != "" && owner != userID {
+			models.RecordUsage(owner, "", models.UsageDelta{Bytes: bytes})
+		}
+		if shareID := c.GetString("contentShare"); shareID != "" && owner != "" {
+			models.RecordBandwidth(shareID, owner, bytes)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	webhookWindow         = 5 * time.Minute // 时间戳与服务器时间的最大偏差
+	maxWebhookNonceLength = 128
+)
+
+// WebhookGuard 入站回调的签名与重放校验参数
+type WebhookGuard struct {
+	Source  string        // 回调来源，各来源的 nonce 分开记录，如 payment
+	Header  string        // 请求头前缀：<Header>-Timestamp、<Header>-Nonce、<Header>-Signature
+	Secret  func() string // 签名密钥，为空时拒绝所有回调
+	MaxBody int64         // 请求体上限
+}
+
+// webhookSignature 计算 "<时间戳>.<nonce>.<请求体>" 的 HMAC-SHA256（十六进制）
+func webhookSignature(secret, timestamp, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook 校验入站回调：签名覆盖时间戳、nonce 与请求体，时间戳须在 5 分钟以内，
+// 同一 nonce 只接受一次（处理失败返回 5xx 时释放，发送方可原样重试）。校验通过后请求体可被后续处理函数再次读取
+func VerifyWebhook(g WebhookGuard) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, g.MaxBody+1))
+		if err != nil || int64(len(body)) > g.MaxBody {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"code": 1, "msg": "Invalid request"})
+			return
+		}
+		timestamp := c.GetHeader(g.Header + "-Timestamp")
+		nonce := c.GetHeader(g.Header + "-Nonce")
+		signature := strings.TrimPrefix(strings.TrimSpace(c.GetHeader(g.Header+"-Signature")), "sha256=")
+		secret := g.Secret()
+		if secret == "" || nonce == "" || len(nonce) > maxWebhookNonceLength ||
+			!hmac.Equal([]byte(strings.ToLower(signature)), []byte(webhookSignature(secret, timestamp, nonce, body))) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Invalid signature"})
+			return
+		}
+		sent, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || math.Abs(time.Since(time.Unix(sent, 0)).Seconds()) > webhookWindow.Seconds() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"code": 1, "msg": "Request timestamp out of range"})
+			return
+		}
+		// 时间戳两侧各有一个窗口的偏差，nonce 至少保留两个窗口
+		fresh, err := models.ClaimWebhookNonce(g.Source, nonce, 2*webhookWindow)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"code": 1, "msg": "Failed to record nonce: " + err.Error()})
+			return
+		}
+		if !fresh {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"code": 1, "msg": "Duplicate request"})
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		c.Next()
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			if err := models.ReleaseWebhookNonce(g.Source, nonce); err != nil {
+				log.Printf("Failed to release %s webhook nonce: %v", g.Source, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// runMigrate 执行 migrate 子命令：status 列出迁移状态，up 执行待执行的迁移；返回进程退出码
+func runMigrate(args []string) int {
+	cmd := "status"
+	if len(args) > 0 {
+		cmd = args[0]
+	}
+	if cmd != "status" && cmd != "up" {
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q (usage: migrate [status|up])\n", cmd)
+		return 2
+	}
+
+	if err := models.OpenDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer models.CloseDB()
+
+	if cmd == "up" {
+		if err := models.Migrate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
+			return 1
+		}
+	}
+
+	status, err := models.SchemaStatus()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read schema version: %v\n", err)
+		return 1
+	}
+	pending := 0
+	for _, s := range status {
+		state := "applied"
+		switch {
+		case s.Unknown:
+			state = "unknown (applied by a newer server)"
+		case !s.Applied:
+			state = "pending"
+			pending++
+		}
+		fmt.Printf("%-40s %s\n", s.ID, state)
+	}
+	if pending > 0 {
+		fmt.Printf("%d pending migrations\n", pending)
+	} else {
+		fmt.Println("Database schema is up to date")
+	}
+	return 0
+}
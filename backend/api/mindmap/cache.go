@@ -0,0 +1,63 @@
+package mindmap
+
+import (
+	"container/list"
+	"sync"
+)
+
+// cacheSize 缓存的思维导图数量上限
+const cacheSize = 256
+
+// Entry 缓存的解析与渲染结果
+type Entry struct {
+	Hash string
+	Root *Node
+	Err  error
+	key  string
+	svg  []byte
+	once sync.Once
+}
+
+// SVG 首次调用时渲染并缓存
+func (e *Entry) SVG() []byte {
+	e.once.Do(func() {
+		if e.Root != nil {
+			e.svg = SVG(e.Root)
+		}
+	})
+	return e.svg
+}
+
+var cache = struct {
+	sync.Mutex
+	items map[string]*list.Element
+	order *list.List
+}{items: map[string]*list.Element{}, order: list.New()}
+
+// Load 按内容哈希返回缓存的结果，未命中时解析并放入缓存（最近最少使用淘汰）
+func Load(source, title string) *Entry {
+	key := Hash(source) + "\x00" + title
+	cache.Lock()
+	if el, ok := cache.items[key]; ok {
+		cache.order.MoveToFront(el)
+		cache.Unlock()
+		return el.Value.(*Entry)
+	}
+	cache.Unlock()
+
+	e := &Entry{Hash: Hash(source), key: key}
+	e.Root, e.Err = Parse(source, title)
+
+	cache.Lock()
+	defer cache.Unlock()
+	if el, ok := cache.items[key]; ok {
+		return el.Value.(*Entry)
+	}
+	cache.items[key] = cache.order.PushFront(e)
+	for cache.order.Len() > cacheSize {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.items, oldest.Value.(*Entry).key)
+	}
+	return e
+}
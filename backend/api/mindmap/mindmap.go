@@ -0,0 +1,131 @@
+// Package mindmap 解析思维导图代码块（思源 mindmap / markmap 语法：标题与嵌套列表构成层级），
+// 生成节点树供阅读页交互渲染，并在服务端排版输出 SVG；结果按代码块内容哈希缓存。
+package mindmap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const (
+	maxNodes = 2000
+	maxDepth = 32
+)
+
+// Node 思维导图节点
+type Node struct {
+	Text     string  `json:"text"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(.*)$`)
+	taskPattern     = regexp.MustCompile(`^\[[ xX]\]\s+`)
+	linkPattern     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	emphasisPattern = regexp.MustCompile(`(\*\*|__|~~|==)(.+?)(\*\*|__|~~|==)`)
+	codePattern     = regexp.MustCompile("`([^`]*)`")
+	tagPattern      = regexp.MustCompile(`</?[A-Za-z][^<>]*>`)
+)
+
+// Hash 代码块内容的哈希，用作缓存键与 SVG 地址
+func Hash(source string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(source)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Parse 解析思维导图源码：标题按级别、列表按缩进确定层级，列表挂在最近的标题下。
+// 只有一个顶层节点时以其为根，否则以 title 作为根节点
+func Parse(source, title string) (*Node, error) {
+	type open struct {
+		level int
+		node  *Node
+	}
+	root := &Node{Text: title}
+	stack := []open{{0, root}}
+	headingLevel := 0
+	var listIndents []int
+	var last *Node
+	count := 0
+
+	add := func(level int, text string) error {
+		if count++; count > maxNodes {
+			return errors.New("too many nodes")
+		}
+		if level > maxDepth {
+			level = maxDepth
+		}
+		for len(stack) > 1 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].node
+		last = &Node{Text: text}
+		parent.Children = append(parent.Children, last)
+		stack = append(stack, open{level, last})
+		return nil
+	}
+
+	for _, raw := range strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n") {
+		line := strings.ReplaceAll(raw, "\t", "    ")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "{:") {
+			continue
+		}
+		if m := headingPattern.FindStringSubmatch(trimmed); m != nil && len(line) == len(strings.TrimLeft(line, " ")) {
+			headingLevel, listIndents = len(m[1]), nil
+			if err := add(headingLevel, cleanText(m[2])); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if m := listItemPattern.FindStringSubmatch(line); m != nil {
+			indent := len(m[1])
+			for len(listIndents) > 0 && indent <= listIndents[len(listIndents)-1] {
+				listIndents = listIndents[:len(listIndents)-1]
+			}
+			listIndents = append(listIndents, indent)
+			if err := add(headingLevel+len(listIndents), cleanText(taskPattern.ReplaceAllString(m[2], ""))); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// 缩进的普通行是上一个列表项的续行，其余作为当前标题下的节点
+		if last != nil && len(listIndents) > 0 && strings.HasPrefix(line, " ") {
+			last.Text = strings.TrimSpace(last.Text + " " + cleanText(trimmed))
+			continue
+		}
+		listIndents = nil
+		if err := add(headingLevel+1, cleanText(trimmed)); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(root.Children) == 0 {
+		return nil, errors.New("empty mind map")
+	}
+	if len(root.Children) == 1 {
+		return root.Children[0], nil
+	}
+	return root, nil
+}
+
+// cleanText 去除行内 Markdown 标记，保留可读文字
+func cleanText(s string) string {
+	s = linkPattern.ReplaceAllString(s, "$1")
+	s = emphasisPattern.ReplaceAllString(s, "$2")
+	s = codePattern.ReplaceAllString(s, "$1")
+	s = tagPattern.ReplaceAllString(s, "")
+	return strings.TrimSpace(s)
+}
+
+// Count 节点总数
+func (n *Node) Count() int {
+	total := 1
+	for _, c := range n.Children {
+		total += c.Count()
+	}
+	return total
+}
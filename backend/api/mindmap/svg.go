@@ -0,0 +1,164 @@
+package mindmap
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	gapX        = 36.0 // 父子节点的水平间距
+	gapY        = 10.0 // 相邻子树的垂直间距
+	padX        = 10.0
+	margin      = 16.0
+	maxLabel    = 40 // 节点文字超过该字数时截断，完整内容放在 <title> 中
+	defaultFont = `-apple-system, "Segoe UI", "PingFang SC", "Microsoft YaHei", sans-serif`
+)
+
+// palette 一级分支的颜色，子节点沿用所在分支的颜色
+var palette = []string{"#1677ff", "#fa8c16", "#52c41a", "#eb2f96", "#722ed1", "#13c2c2", "#faad14", "#f5222d"}
+
+// box 排版后的节点
+type box struct {
+	node          *Node
+	label         string
+	x, y          float64 // 节点左上角
+	width, height float64
+	fontSize      float64
+	subtree       float64 // 子树占用的高度
+	color         string
+	depth         int
+	children      []*box
+}
+
+// SVG 将节点树排版为从左到右展开的思维导图
+func SVG(root *Node) []byte {
+	b := measure(root, 0, "#333333")
+	place(b, margin, margin)
+	maxX, maxY := extent(b)
+
+	var out bytes.Buffer
+	w, h := num(maxX+margin), num(maxY+margin)
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s" font-family="%s">`,
+		w, h, w, h, html.EscapeString(defaultFont))
+	out.WriteString("\n")
+	edges(&out, b)
+	nodes(&out, b)
+	out.WriteString("</svg>\n")
+	return out.Bytes()
+}
+
+func measure(n *Node, depth int, color string) *box {
+	size := 13.0
+	switch depth {
+	case 0:
+		size = 18
+	case 1:
+		size = 15
+	}
+	label := n.Text
+	if utf8.RuneCountInString(label) > maxLabel {
+		label = string([]rune(label)[:maxLabel]) + "…"
+	}
+	b := &box{node: n, label: label, fontSize: size, depth: depth, color: color}
+	b.width = textWidth(label, size) + padX*2
+	b.height = size + 14
+	if depth == 0 {
+		b.height += 6
+	}
+	children := 0.0
+	for i, c := range n.Children {
+		cc := color
+		if depth == 0 {
+			cc = palette[i%len(palette)]
+		}
+		child := measure(c, depth+1, cc)
+		b.children = append(b.children, child)
+		children += child.subtree
+		if i > 0 {
+			children += gapY
+		}
+	}
+	b.subtree = math.Max(b.height, children)
+	return b
+}
+
+// place 确定节点位置：子节点在父节点右侧依次排列，父节点在子树范围内垂直居中
+func place(b *box, x, top float64) {
+	b.x = x
+	b.y = top + (b.subtree-b.height)/2
+	children := 0.0
+	for i, c := range b.children {
+		children += c.subtree
+		if i > 0 {
+			children += gapY
+		}
+	}
+	y := top + (b.subtree-children)/2
+	for _, c := range b.children {
+		place(c, x+b.width+gapX, y)
+		y += c.subtree + gapY
+	}
+}
+
+func extent(b *box) (float64, float64) {
+	maxX, maxY := b.x+b.width, b.y+b.height
+	for _, c := range b.children {
+		x, y := extent(c)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+	return maxX, maxY
+}
+
+// edges 父节点右侧中点到子节点左侧中点的曲线
+func edges(out *bytes.Buffer, b *box) {
+	x1, y1 := b.x+b.width, b.y+b.height/2
+	for _, c := range b.children {
+		x2, y2 := c.x, c.y+c.height/2
+		mx := (x1 + x2) / 2
+		width := math.Max(1.2, 3-float64(c.depth)*0.6)
+		fmt.Fprintf(out, `<path d="M%s %s C%s %s %s %s %s %s" fill="none" stroke="%s" stroke-width="%s"/>`+"\n",
+			num(x1), num(y1), num(mx), num(y1), num(mx), num(y2), num(x2), num(y2), c.color, num(width))
+		edges(out, c)
+	}
+}
+
+func nodes(out *bytes.Buffer, b *box) {
+	fill, stroke, text := "#ffffff", b.color, "#1f1f1f"
+	if b.depth == 0 {
+		fill, stroke, text = "#1f1f1f", "#1f1f1f", "#ffffff"
+	}
+	out.WriteString("<g>")
+	if b.label != b.node.Text {
+		out.WriteString("<title>" + html.EscapeString(b.node.Text) + "</title>")
+	}
+	fmt.Fprintf(out, `<rect x="%s" y="%s" width="%s" height="%s" rx="6" fill="%s" stroke="%s" stroke-width="1.2"/>`,
+		num(b.x), num(b.y), num(b.width), num(b.height), fill, stroke)
+	fmt.Fprintf(out, `<text x="%s" y="%s" font-size="%s" fill="%s" text-anchor="middle" dominant-baseline="central">%s</text>`,
+		num(b.x+b.width/2), num(b.y+b.height/2), num(b.fontSize), text, html.EscapeString(b.label))
+	out.WriteString("</g>\n")
+	for _, c := range b.children {
+		nodes(out, c)
+	}
+}
+
+// textWidth 估算文字宽度：全角字符按一个字号，其余按 0.6 个字号
+func textWidth(s string, size float64) float64 {
+	w := 0.0
+	for _, r := range s {
+		if r > 0x2e80 || unicode.Is(unicode.Han, r) {
+			w += size
+		} else {
+			w += size * 0.6
+		}
+	}
+	return math.Max(w, size)
+}
+
+func num(f float64) string {
+	return strconv.FormatFloat(math.Round(f*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"gorm.io/gorm"
+)
+
+// UsersDueForDeletion 注销宽限期已满、等待彻底删除的用户 ID，最多返回 limit 个；
+// 有分享处于封存保留期内的用户推迟到保留期满后删除
+func UsersDueForDeletion(now time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := DB.Unscoped().Model(&User{}).Where("deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now).
+		Where("NOT EXISTS (SELECT 1 FROM shares s WHERE s.user_id = users.id AND s.sealed_until > ?)", now).
+		Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// PurgeUser 彻底删除用户及其全部数据：分享（含已删除的）及其版本、资源、评论、批注、订阅、翻译、朗读、
+// 存档、统计、浏览记录与举报，合集、主题、自定义域名、API Token、登录会话、第三方登录身份与用量记录。
+// 数据库记录在一个事务中删除，存储中的对象随后逐个删除，失败的只记录日志（由孤立资源回收兜底）
+func PurgeUser(ctx context.Context, userID string) error {
+	var shareIDs []string
+	if err := DB.Unscoped().Model(&Share{}).Where("user_id = ?", userID).Pluck("id", &shareIDs).Error; err != nil {
+		return err
+	}
+	keys, err := shareStorageKeys(shareIDs)
+	if err != nil {
+		return err
+	}
+
+	err = DB.Transaction(func(tx *gorm.DB) error {
+		if err := purgeShareRows(tx, shareIDs); err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id IN (?)", tx.Model(&Collection{}).Select("id").Where("user_id = ?", userID)).
+			Delete(&CollectionItem{}).Error; err != nil {
+			return err
+		}
+		// 用户在他人分享下的评论、批注与访问名单条目一并删除
+		byUser := []any{
+			&Annotation{}, &Comment{}, &ShareAccess{}, &Collection{}, &Theme{}, &ShareTemplate{}, &NotificationSettings{}, &CustomDomain{}, &Session{},
+			&UserIdentity{}, &UserToken{}, &PushSubscription{}, &APIUsage{}, &ShareBandwidth{}, &AnnouncementDismissal{}, &QuotaWarning{},
+		}
+		for _, m := range byUser {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Where("id = ?", userID).Delete(&User{}).Error
+	})
+	if err != nil {
+		return err
+	}
+	RemoveShareIndex(shareIDs...)
+
+	deleteStorageKeys(ctx, "account purge", keys)
+	return nil
+}
+
+// purgeShareRows 在事务中彻底删除分享（含已删除的）及其版本、资源、评论、批注、订阅、翻译、朗读、存档、统计、
+// 浏览记录、举报、短链接、反馈、投票、表单提交、付费阅读订单与阅读进度的数据库记录；存储中的对象由调用方删除
+func purgeShareRows(tx *gorm.DB, shareIDs []string) error {
+	if len(shareIDs) == 0 {
+		return nil
+	}
+	byShare := []any{
+		&Annotation{}, &Comment{}, &Subscription{}, &ShareAccess{}, &ShareEmbedding{}, &ShareRevision{},
+		&ShareTranslation{}, &ShareNarration{}, &ShareLanguageCheck{}, &LinkSnapshot{}, &ExportJob{},
+		&CollectionItem{}, &ShareBandwidth{}, &ShareTermsAcceptance{}, &ShareView{}, &ShareViewDaily{}, &ShareReport{}, &ShortLink{},
+		&ShareRead{}, &ShareFeedback{}, &PollVote{}, &FormSubmission{}, &SharePayment{}, &ReadingProgress{},
+	}
+	for _, m := range byShare {
+		if err := tx.Unscoped().Where("share_id IN ?", shareIDs).Delete(m).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("asset_id IN (?)", tx.Model(&Asset{}).Select("id").Where("share_id IN ?", shareIDs)).
+		Delete(&AssetVariant{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("share_id IN ?", shareIDs).Delete(&Asset{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", shareIDs).Delete(&Share{}).Error
+}
+
+// deleteStorageKeys 逐个删除存储中的对象，失败的只记录日志（由孤立资源回收兜底）
+func deleteStorageKeys(ctx context.Context, what string, keys []string) {
+	if storage.Default == nil {
+		return
+	}
+	for _, key := range keys {
+		if err := storage.Default.Delete(ctx, key); err != nil {
+			log.Printf("%s: failed to delete %s: %v", what, key, err)
+		}
+	}
+}
+
+// shareStorageKeys 分享在存储中的对象：资源与图片副本、外置正文、PDF 缓存、链接存档与导出文件包
+func shareStorageKeys(shareIDs []string) ([]string, error) {
+	var keys []string
+	if len(shareIDs) == 0 {
+		return keys, nil
+	}
+	pluck := func(q *gorm.DB, column string) error {
+		var part []string
+		if err := q.Where(column+" <> ''").Pluck(column, &part).Error; err != nil {
+			return err
+		}
+		keys = append(keys, part...)
+		return nil
+	}
+	assets := DB.Model(&Asset{}).Select("id").Where("share_id IN ?", shareIDs)
+	queries := []struct {
+		q      *gorm.DB
+		column string
+	}{
+		{DB.Model(&Asset{}).Where("share_id IN ?", shareIDs), "storage_key"},
+		{DB.Model(&AssetVariant{}).Where("asset_id IN (?)", assets), "storage_key"},
+		{DB.Unscoped().Model(&Share{}).Where("id IN ?", shareIDs), "content_key"},
+		{DB.Model(&LinkSnapshot{}).Where("share_id IN ?", shareIDs), "storage_key"},
+		{DB.Model(&ExportJob{}).Where("share_id IN ?", shareIDs), "storage_key"},
+	}
+	for _, q := range queries {
+		if err := pluck(q.q, q.column); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range shareIDs {
+		keys = append(keys, "exports/"+id+"/share.pdf")
+	}
+	return keys, nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Annotation 读者在分享上的划线批注，通过块 ID + 引文及上下文锚定位置
+type Annotation struct {
+	ID          string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID     string    `gorm:"size:64;index" json:"shareId"`
+	UserID      string    `gorm:"size:64;index" json:"userId"`
+	Username    string    `gorm:"size:100" json:"username"`
+	BlockID     string    `gorm:"size:64" json:"blockId,omitempty"` // 锚定的块 ID（可选）
+	Quote       string    `gorm:"type:text" json:"quote"`           // 划线的原文
+	Prefix      string    `gorm:"size:255" json:"prefix,omitempty"` // 原文前的上下文，用于内容变化后重新定位
+	Suffix      string    `gorm:"size:255" json:"suffix,omitempty"` // 原文后的上下文
+	StartOffset int       `json:"startOffset"`
+	EndOffset   int       `json:"endOffset"`
+	Color       string    `gorm:"size:32" json:"color,omitempty"`
+	Note        string    `gorm:"type:text" json:"note,omitempty"`
+	Hidden      bool      `gorm:"default:false" json:"hidden"` // 被分享者隐藏
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Annotation) TableName() string {
+	return "annotations"
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Announcement 管理员发布的全站公告（如维护通知、条款更新），在 StartsAt 与 EndsAt 之间显示在控制面板和/或分享阅读页顶部
+type Announcement struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Title       string     `gorm:"size:200" json:"title"`
+	Content     string     `gorm:"size:2000" json:"content,omitempty"`
+	Link        string     `gorm:"size:2048" json:"link,omitempty"`     // 「了解详情」链接，站内路径或 http(s) 地址
+	Level       string     `gorm:"size:16;default:info" json:"level"`   // info / warning / error
+	Audience    string     `gorm:"size:16;default:all" json:"audience"` // all / dashboard / shares
+	Dismissible bool       `json:"dismissible"`                         // 读者可以关闭，登录用户的关闭记录保存在服务端
+	StartsAt    *time.Time `json:"startsAt,omitempty"`                  // 为空时立即显示
+	EndsAt      *time.Time `json:"endsAt,omitempty"`                    // 为空时一直显示，直到删除
+	CreatedBy   string     `gorm:"size:64" json:"createdBy,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Announcement) TableName() string {
+	return "announcements"
+}
+
+// ActiveAt 公告在 t 时是否处于显示时间段内
+func (a *Announcement) ActiveAt(t time.Time) bool {
+	return (a.StartsAt == nil || !t.Before(*a.StartsAt)) && (a.EndsAt == nil || t.Before(*a.EndsAt))
+}
+
+// AnnouncementDismissal 登录用户关闭公告的记录，关闭后不再向该用户显示；匿名读者的关闭状态只保存在浏览器中
+type AnnouncementDismissal struct {
+	AnnouncementID uint      `gorm:"primaryKey" json:"announcementId"`
+	UserID         string    `gorm:"primaryKey;size:64;index" json:"userId"`
+	DismissedAt    time.Time `json:"dismissedAt"`
+}
+
+// TableName 指定表名
+func (AnnouncementDismissal) TableName() string {
+	return "announcement_dismissals"
+}
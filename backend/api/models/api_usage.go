@@ -0,0 +1,117 @@
+package models
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// apiUsageFlushInterval 接口用量批量写库的间隔
+const apiUsageFlushInterval = time.Minute
+
+// APIUsage 用户每天的接口用量，按 API Token 分行（会话登录与读者访问的 token_id 为空）
+type APIUsage struct {
+	Date      string `gorm:"primaryKey;size:10" json:"date"` // 服务器本地日期 YYYY-MM-DD
+	UserID    string `gorm:"primaryKey;size:64;index" json:"userId"`
+	TokenID   string `gorm:"primaryKey;size:64" json:"tokenId"`
+	Requests  int64  `gorm:"default:0" json:"requests"`  // 已认证的接口请求数
+	Publishes int64  `gorm:"default:0" json:"publishes"` // 发布（创建或重新发布分享）次数
+	Views     int64  `gorm:"default:0" json:"views"`     // 读者浏览该用户分享的次数
+	Bytes     int64  `gorm:"default:0" json:"bytes"`     // 响应流量：用户自己的请求与读者读取其分享、资源的响应大小
+}
+
+// TableName 指定表名
+func (APIUsage) TableName() string {
+	return "api_usage"
+}
+
+// UsageDelta 一次请求产生的用量增量
+type UsageDelta struct {
+	Requests  int64
+	Publishes int64
+	Views     int64
+	Bytes     int64
+}
+
+type usageKey struct {
+	date, userID, tokenID string
+}
+
+var apiUsage = struct {
+	sync.Mutex
+	pending map[usageKey]*UsageDelta
+	once    sync.Once
+}{pending: map[usageKey]*UsageDelta{}}
+
+// RecordUsage 累计用户（及其 API Token）的用量，定时批量写库
+func RecordUsage(userID, tokenID string, d UsageDelta) {
+	if userID == "" {
+		return
+	}
+	key := usageKey{date: time.Now().Format(time.DateOnly), userID: userID, tokenID: tokenID}
+	apiUsage.Lock()
+	u := apiUsage.pending[key]
+	if u == nil {
+		u = &UsageDelta{}
+		apiUsage.pending[key] = u
+	}
+	u.Requests += d.Requests
+	u.Publishes += d.Publishes
+	u.Views += d.Views
+	u.Bytes += d.Bytes
+	apiUsage.Unlock()
+
+	apiUsage.once.Do(func() {
+		go func() {
+			for range time.Tick(apiUsageFlushInterval) {
+				FlushUsage()
+			}
+		}()
+	})
+}
+
+// FlushUsage 将累计的接口用量写入数据库
+func FlushUsage() {
+	apiUsage.Lock()
+	pending := apiUsage.pending
+	if len(pending) == 0 {
+		apiUsage.Unlock()
+		return
+	}
+	apiUsage.pending = map[usageKey]*UsageDelta{}
+	apiUsage.Unlock()
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for k, d := range pending {
+			row := APIUsage{Date: k.date, UserID: k.userID, TokenID: k.tokenID,
+				Requests: d.Requests, Publishes: d.Publishes, Views: d.Views, Bytes: d.Bytes}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "date"}, {Name: "user_id"}, {Name: "token_id"}},
+				DoUpdates: clause.Set{
+					{Column: clause.Column{Name: "requests"}, Value: gorm.Expr("api_usage.requests + ?", d.Requests)},
+					{Column: clause.Column{Name: "publishes"}, Value: gorm.Expr("api_usage.publishes + ?", d.Publishes)},
+					{Column: clause.Column{Name: "views"}, Value: gorm.Expr("api_usage.views + ?", d.Views)},
+					{Column: clause.Column{Name: "bytes"}, Value: gorm.Expr("api_usage.bytes + ?", d.Bytes)},
+				},
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to save API usage: %v", err)
+	}
+}
+
+// UserUsageSince 返回用户 from 日期（含）之后的每日用量，按日期、Token 排序；调用前先写入累计的用量
+func UserUsageSince(userID string, from time.Time) ([]APIUsage, error) {
+	FlushUsage()
+	var rows []APIUsage
+	err := DB.Where("user_id = ? AND date >= ?", userID, from.Format(time.DateOnly)).
+		Order("date, token_id").Find(&rows).Error
+	return rows, err
+}
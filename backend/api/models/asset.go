@@ -0,0 +1,78 @@
+package models
+
+import (
+	"errors"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Asset 分享引用的资源文件（图片/附件），实际内容保存在 storage 中
+type Asset struct {
+	ID                 string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID            string    `gorm:"size:64;uniqueIndex:idx_asset_share_path,priority:1" json:"shareId"`
+	UserID             string    `gorm:"size:64;index" json:"userId"`
+	Path               string    `gorm:"size:512;uniqueIndex:idx_asset_share_path,priority:2" json:"path"` // 文档中的引用路径，如 assets/xxx.png
+	StorageKey         string    `gorm:"size:600" json:"-"`
+	ContentType        string    `gorm:"size:128" json:"contentType"`
+	Size               int64     `json:"size"`
+	Hash               string    `gorm:"size:64;index" json:"hash"`                // sha256
+	Transcript         string    `gorm:"type:text;serializer:zstd" json:"-"`       // 音视频文字稿，带时间轴的字幕统一保存为 WebVTT
+	TranscriptFormat   string    `gorm:"size:8" json:"transcriptFormat,omitempty"` // vtt / text，空表示未附加文字稿
+	TranscriptLanguage string    `gorm:"size:16" json:"transcriptLanguage,omitempty"`
+	CreatedAt          time.Time `json:"createdAt"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+// mediaExtensions 常见音视频扩展名，部分系统的 MIME 表中缺少这些类型
+var mediaExtensions = map[string]bool{
+	".mp3": true, ".wav": true, ".ogg": true, ".oga": true, ".m4a": true, ".aac": true, ".flac": true, ".opus": true,
+	".mp4": true, ".m4v": true, ".webm": true, ".ogv": true, ".mov": true, ".mkv": true,
+}
+
+// TableName 指定表名
+func (Asset) TableName() string {
+	return "assets"
+}
+
+// IsMedia 是否为可附加文字稿的音频或视频
+func (a *Asset) IsMedia() bool {
+	if strings.HasPrefix(a.ContentType, "audio/") || strings.HasPrefix(a.ContentType, "video/") {
+		return true
+	}
+	return mediaExtensions[strings.ToLower(path.Ext(a.Path))]
+}
+
+// FindAsset 按分享与引用路径查找资源
+func FindAsset(shareID, path string) (*Asset, error) {
+	var asset Asset
+	err := DB.Where("share_id = ? AND path = ?", shareID, path).First(&asset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
+
+// AssetVariant 图片资源的优化副本（缩小尺寸或 WebP），由原图生成，原图内容变化后不再使用
+type AssetVariant struct {
+	ID          uint      `gorm:"primaryKey" json:"-"`
+	AssetID     string    `gorm:"size:64;uniqueIndex:idx_asset_variant,priority:1" json:"assetId"`
+	Format      string    `gorm:"size:16;uniqueIndex:idx_asset_variant,priority:2" json:"format"` // png / jpeg / webp
+	SourceHash  string    `gorm:"size:64" json:"sourceHash"`                                      // 生成时原图的 sha256
+	StorageKey  string    `gorm:"size:600" json:"-"`
+	ContentType string    `gorm:"size:128" json:"contentType"`
+	Size        int64     `json:"size"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (AssetVariant) TableName() string {
+	return "asset_variants"
+}
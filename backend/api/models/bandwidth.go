@@ -0,0 +1,115 @@
+package models
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bandwidthFlushInterval 流量记录批量写库的间隔
+const bandwidthFlushInterval = time.Minute
+
+// ShareBandwidth 分享每月向读者提供的流量（正文与资源的响应大小），按月汇总到所有者用于流量配额
+type ShareBandwidth struct {
+	Month   string `gorm:"primaryKey;size:7" json:"month"` // 服务器本地月份 YYYY-MM
+	ShareID string `gorm:"primaryKey;size:64" json:"shareId"`
+	UserID  string `gorm:"size:64;index" json:"userId"`
+	Bytes   int64  `gorm:"default:0" json:"bytes"`
+}
+
+// TableName 指定表名
+func (ShareBandwidth) TableName() string {
+	return "share_bandwidth"
+}
+
+// CurrentMonth 当前的统计月份
+func CurrentMonth() string {
+	return time.Now().Format("2006-01")
+}
+
+type bandwidthKey struct {
+	month, shareID, userID string
+}
+
+var bandwidth = struct {
+	sync.Mutex
+	pending map[bandwidthKey]int64
+	once    sync.Once
+}{pending: map[bandwidthKey]int64{}}
+
+// RecordBandwidth 累计分享向读者提供的流量，定时批量写库
+func RecordBandwidth(shareID, userID string, n int64) {
+	if shareID == "" || userID == "" || n <= 0 {
+		return
+	}
+	bandwidth.Lock()
+	bandwidth.pending[bandwidthKey{month: CurrentMonth(), shareID: shareID, userID: userID}] += n
+	bandwidth.Unlock()
+
+	bandwidth.once.Do(func() {
+		go func() {
+			for range time.Tick(bandwidthFlushInterval) {
+				FlushBandwidth()
+			}
+		}()
+	})
+}
+
+// FlushBandwidth 将累计的流量写入数据库
+func FlushBandwidth() {
+	bandwidth.Lock()
+	pending := bandwidth.pending
+	if len(pending) == 0 {
+		bandwidth.Unlock()
+		return
+	}
+	bandwidth.pending = map[bandwidthKey]int64{}
+	bandwidth.Unlock()
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for k, n := range pending {
+			row := ShareBandwidth{Month: k.month, ShareID: k.shareID, UserID: k.userID, Bytes: n}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "month"}, {Name: "share_id"}},
+				DoUpdates: clause.Set{{Column: clause.Column{Name: "bytes"}, Value: gorm.Expr("share_bandwidth.bytes + ?", n)}},
+			}).Create(&row).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to save bandwidth usage: %v", err)
+	}
+}
+
+// UserMonthBandwidth 用户本月的读者流量，包括尚未写库的部分
+func UserMonthBandwidth(userID string) (int64, error) {
+	month := CurrentMonth()
+	var total int64
+	if err := DB.Model(&ShareBandwidth{}).Select("COALESCE(SUM(bytes), 0)").
+		Where("user_id = ? AND month = ?", userID, month).Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	bandwidth.Lock()
+	for k, n := range bandwidth.pending {
+		if k.userID == userID && k.month == month {
+			total += n
+		}
+	}
+	bandwidth.Unlock()
+	return total, nil
+}
+
+// BandwidthExceeded 用户本月的读者流量是否已达到流量配额（含宽限）
+func BandwidthExceeded(userID string) bool {
+	limit := UserQuota(userID).MaxBandwidth
+	if limit <= 0 {
+		return false
+	}
+	used, err := UserMonthBandwidth(userID)
+	return err == nil && used >= GraceLimit(limit)
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Collection 分享合集：将多篇分享组织为有序的目录，拥有独立的公开首页 /c/<slug>（如多章节教程）
+type Collection struct {
+	ID          string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID      string    `gorm:"size:64;index" json:"userId"`
+	Slug        string    `gorm:"size:64;uniqueIndex" json:"slug"`
+	Title       string    `gorm:"size:200" json:"title"`
+	Description string    `gorm:"type:text" json:"description"`
+	CoverImage  string    `gorm:"size:2048" json:"coverImage"` // 封面图片地址，为空时使用第一篇分享的封面
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Collection) TableName() string {
+	return "collections"
+}
+
+// CollectionItem 合集中的分享，按 Position 升序排列
+type CollectionItem struct {
+	CollectionID string `gorm:"primaryKey;size:64" json:"collectionId"`
+	ShareID      string `gorm:"primaryKey;size:64;index" json:"shareId"`
+	Position     int    `json:"position"`
+}
+
+// TableName 指定表名
+func (CollectionItem) TableName() string {
+	return "collection_items"
+}
+
+// FindUserCollection 查找用户的指定合集
+func FindUserCollection(userID, id string) (*Collection, error) {
+	var col Collection
+	err := DB.Where("id = ? AND user_id = ?", id, userID).First(&col).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &col, nil
+}
+
+// CollectionShareIDs 按顺序返回合集中的分享 ID
+func CollectionShareIDs(collectionID string) ([]string, error) {
+	var ids []string
+	err := DB.Model(&CollectionItem{}).Where("collection_id = ?", collectionID).Order("position").Pluck("share_id", &ids).Error
+	return ids, err
+}
+
+// SetCollectionItems 以给定顺序替换合集中的分享
+func SetCollectionItems(tx *gorm.DB, collectionID string, shareIDs []string) error {
+	if err := tx.Where("collection_id = ?", collectionID).Delete(&CollectionItem{}).Error; err != nil {
+		return err
+	}
+	if len(shareIDs) == 0 {
+		return nil
+	}
+	items := make([]CollectionItem, len(shareIDs))
+	for i, id := range shareIDs {
+		items[i] = CollectionItem{CollectionID: collectionID, ShareID: id, Position: i}
+	}
+	return tx.Create(&items).Error
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// 评论审核状态
+const (
+	CommentStatusPending  = "pending"  // 待分享者审核，读者不可见
+	CommentStatusApproved = "approved" // 已公开
+)
+
+// Comment 读者在分享下的评论，登录读者以用户名署名，匿名读者填写昵称
+type Comment struct {
+	ID        string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID   string    `gorm:"size:64;index:idx_comment_share_status,priority:1" json:"shareId"`
+	UserID    string    `gorm:"size:64;index" json:"userId,omitempty"` // 匿名评论为空
+	Author    string    `gorm:"size:100" json:"author"`
+	Content   string    `gorm:"type:text" json:"content"`
+	Status    string    `gorm:"size:16;index:idx_comment_share_status,priority:2" json:"status"`
+	IP        string    `gorm:"size:64" json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Comment) TableName() string {
+	return "comments"
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/klauspost/compress/zstd"
+	"gorm.io/gorm/schema"
+)
+
+// zstdMagic zstd 帧头魔数，用于区分压缩数据与旧的明文数据
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+// compressMinBytes 超过该长度的内容才压缩 (content.compress_min_bytes，默认 4096，<0 表示关闭)
+func compressMinBytes() int {
+	return config.Get().Content.CompressMinBytes
+}
+
+func init() {
+	schema.RegisterSerializer("zstd", ZstdSerializer{})
+}
+
+// ZstdSerializer 字符串字段的透明压缩序列化器：写入时对大文本进行 zstd 压缩，读取时自动识别并解压
+type ZstdSerializer struct{}
+
+// Scan 从数据库读取并按需解压
+func (ZstdSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var raw []byte
+	switch v := dbValue.(type) {
+	case nil:
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("zstd serializer: unsupported value type %T", dbValue)
+	}
+	text, err := decompressText(raw)
+	if err != nil {
+		return err
+	}
+	return field.Set(ctx, dst, text)
+}
+
+// Value 写入数据库前按需压缩
+func (ZstdSerializer) Value(_ context.Context, _ *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	s, _ := fieldValue.(string)
+	if compressMinBytes() < 0 || len(s) < compressMinBytes() {
+		return s, nil
+	}
+	return zstdEncoder.EncodeAll([]byte(s), nil), nil
+}
+
+func decompressText(raw []byte) (string, error) {
+	if !bytes.HasPrefix(raw, zstdMagic) {
+		return string(raw), nil
+	}
+	out, err := zstdDecoder.DecodeAll(raw, nil)
+	if err != nil {
+		return "", fmt.Errorf("zstd serializer: %w", err)
+	}
+	return string(out), nil
+}
+
+// compressExistingContent 将历史上以明文存储的大文本分享内容迁移为压缩格式
+func compressExistingContent() {
+	if compressMinBytes() < 0 {
+		return
+	}
+	var ids []string
+	if err := DB.Unscoped().Model(&Share{}).
+		Where("typeof(content) = 'text' AND length(CAST(content AS BLOB)) >= ?", compressMinBytes()).
+		Pluck("id", &ids).Error; err != nil {
+		log.Printf("content compression migration skipped: %v", err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+	migrated := 0
+	for _, id := range ids {
+		var share Share
+		if err := DB.Unscoped().Where("id = ?", id).First(&share).Error; err != nil {
+			continue
+		}
+		if err := DB.Unscoped().Model(&share).Select("content").UpdateColumns(&share).Error; err != nil {
+			log.Printf("content compression migration failed (%s): %v", id, err)
+			continue
+		}
+		migrated++
+	}
+	log.Printf("Compressed %d existing share contents", migrated)
+}
@@ -1,75 +1,115 @@
 package models
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
+	"sync/atomic"
+	"time"
 
-	"github.com/glebarez/sqlite"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-// InitDB 初始化数据库连接
+// migrated 表结构迁移是否已完成
+var migrated atomic.Bool
+
+// InitDB 初始化数据库连接，检查表结构版本并按需执行迁移
 func InitDB() error {
-	// 确保数据目录存在
-	dataDir := os.Getenv("DATA_DIR")
-	if dataDir == "" {
-		dataDir = "./data"
+	if err := OpenDB(); err != nil {
+		return err
 	}
 
-	if err := os.MkdirAll(dataDir, 0755); err != nil {
+	// 表结构版本检查：数据库由更新版本迁移过时拒绝启动，落后时自动迁移或提示运行 migrate up；
+	// 测试模式的内存数据库总是从空库迁移
+	cfg := config.Get()
+	if err := checkSchema(cfg.Database.AutoMigrate || cfg.Server.TestMode); err != nil {
 		return err
 	}
+	migrated.Store(true)
 
-	dbPath := filepath.Join(dataDir, "siyuan-share.db")
-	log.Printf("Database path: %s", dbPath)
+	// 历史大文本内容迁移为压缩存储
+	compressExistingContent()
 
-	// 配置 GORM 日志级别 (SQLITE_LOG_MODE=info|warn|silent)
-	logMode := strings.ToLower(os.Getenv("SQLITE_LOG_MODE"))
-	var gormLogger logger.Interface = logger.Default.LogMode(logger.Warn)
-	switch logMode {
-	case "info":
-		gormLogger = logger.Default.LogMode(logger.Info)
-	case "silent":
-		gormLogger = logger.Default.LogMode(logger.Silent)
-	case "warn":
-		fallthrough
-	default:
-		gormLogger = logger.Default.LogMode(logger.Warn)
+	log.Println("Database initialized successfully")
+	return nil
+}
+
+// OpenDB 打开数据库连接，不执行迁移（migrate 子命令使用）
+func OpenDB() error {
+	cfg := config.Get()
+
+	// 确保数据目录存在
+	if err := os.MkdirAll(cfg.Server.DataDir, 0755); err != nil {
+		return err
+	}
+
+	dbPath := cfg.DBPath()
+	if dir := filepath.Dir(dbPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
 	}
-	config := &gorm.Config{Logger: gormLogger}
+	log.Printf("Database path: %s", dbPath)
 
-	// 使用 glebarez/sqlite 驱动连接数据库
-	var err error
-	DB, err = gorm.Open(sqlite.Open(dbPath), config)
+	// 默认使用 glebarez/sqlite 驱动连接数据库，driver 为 sqlcipher 时使用加密驱动
+	dialector, err := Dialector(dbPath)
 	if err != nil {
 		return err
 	}
+	// SQL 日志经标准库 log 输出，与服务日志使用相同的格式与目标
+	DB, err = gorm.Open(dialector, &gorm.Config{Logger: logger.New(log.Default(), logger.Config{
+		SlowThreshold: 200 * time.Millisecond,
+		LogLevel:      gormLogLevel(cfg),
+	})})
+	encrypted := cfg.Database.Driver == "sqlcipher" && !cfg.Server.TestMode
+	if err != nil {
+		// 密钥错误或数据库文件未加密时，首次读取即报 file is not a database
+		if encrypted {
+			return fmt.Errorf("open encrypted database (wrong key or unencrypted file?): %w", err)
+		}
+		return err
+	}
+	if encrypted {
+		log.Println("Database encryption: sqlcipher")
+	}
 
-	// 自动迁移数据库表结构
-	if err := autoMigrate(); err != nil {
+	if err := registerCacheCallbacks(); err != nil {
 		return err
 	}
 
 	// 性能优化 PRAGMA 设置（SQLite）
 	applySQLiteOptimizations()
-
-	log.Println("Database initialized successfully")
 	return nil
 }
 
-// autoMigrate 自动迁移所有模型
-func autoMigrate() error {
-	return DB.AutoMigrate(
-		&Share{},
-		&User{},
-		&UserToken{},
-		&BootstrapToken{}, // 兼容旧数据，后续可移除
-	)
+// gormLogLevel GORM 日志级别：database.log_mode=info|warn|silent，未设置时跟随 log.level
+func gormLogLevel(cfg *config.Config) logger.LogLevel {
+	mode := cfg.Database.LogMode
+	if mode == "" {
+		switch cfg.Log.Level {
+		case "debug":
+			mode = "info"
+		case "error":
+			mode = "error"
+		}
+	}
+	switch mode {
+	case "info":
+		return logger.Info
+	case "error":
+		return logger.Error
+	case "silent":
+		return logger.Silent
+	default:
+		return logger.Warn
+	}
 }
 
 // applySQLiteOptimizations 设置 SQLite 性能相关 PRAGMA
@@ -91,3 +131,39 @@ func applySQLiteOptimizations() {
 		}
 	}
 }
+
+// Ping 检查数据库连接是否可用，用于就绪检查
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("database not initialized")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// Migrated 表结构迁移是否已完成
+func Migrated() bool {
+	return migrated.Load()
+}
+
+// CloseDB 写入尚未保存的 Token 使用记录、接口用量、流量与浏览记录，将 WAL 中的数据写回主库并关闭数据库连接，在服务退出时调用
+func CloseDB() error {
+	if DB == nil {
+		return nil
+	}
+	FlushTokenUsage()
+	FlushUsage()
+	FlushBandwidth()
+	FlushShareViews()
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	if err := CheckpointWAL(); err != nil {
+		log.Printf("SQLite WAL checkpoint failed: %v", err)
+	}
+	return sqlDB.Close()
+}
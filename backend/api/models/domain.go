@@ -0,0 +1,49 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// DomainVerifyPrefix 自定义域名验证 TXT 记录值的前缀，完整记录值为前缀加验证令牌
+const DomainVerifyPrefix = "siyuan-share-verify="
+
+// CustomDomain 用户绑定的自定义域名：在 _siyuan-share.<域名> 添加 TXT 记录完成归属验证后，
+// 该域名只提供此用户的分享、合集与订阅源
+type CustomDomain struct {
+	ID         string     `gorm:"primaryKey;size:64" json:"id"`
+	UserID     string     `gorm:"size:64;index" json:"userId"`
+	Domain     string     `gorm:"size:253;index" json:"domain"` // 同一域名可被多人申请绑定，仅最先完成验证者生效
+	Token      string     `gorm:"size:64" json:"token"`
+	HomePath   string     `gorm:"size:200" json:"homePath"` // 访问域名根路径时跳转的页面，如 /c/<合集地址>
+	VerifiedAt *time.Time `json:"verifiedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (CustomDomain) TableName() string {
+	return "custom_domains"
+}
+
+// Verified 是否已完成归属验证
+func (d *CustomDomain) Verified() bool {
+	return d.VerifiedAt != nil
+}
+
+// VerifyRecordName 验证 TXT 记录的名称
+func (d *CustomDomain) VerifyRecordName() string {
+	return "_siyuan-share." + d.Domain
+}
+
+// VerifyRecordValue 验证 TXT 记录的值
+func (d *CustomDomain) VerifyRecordValue() string {
+	return DomainVerifyPrefix + d.Token
+}
+
+// IsVerifiedDomain 域名是否为已验证的自定义域名
+func IsVerifiedDomain(host string) bool {
+	var count int64
+	DB.Model(&CustomDomain{}).Where("domain = ? AND verified_at IS NOT NULL", strings.ToLower(host)).Count(&count)
+	return count > 0
+}
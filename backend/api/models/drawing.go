@@ -0,0 +1,30 @@
+package models
+
+import "encoding/json"
+
+// Drawing 随分享发布的白板绘图，正文中以 ![名称](drawing:ID) 引用
+type Drawing struct {
+	ID     string          `json:"id"`
+	Name   string          `json:"name,omitempty"`
+	Format string          `json:"format"` // 目前仅支持 excalidraw
+	Scene  json.RawMessage `json:"scene"`
+}
+
+// DrawingList 解析分享保存的绘图数据
+func (s *Share) DrawingList() []Drawing {
+	drawings := []Drawing{}
+	if s.Drawings != "" {
+		_ = json.Unmarshal([]byte(s.Drawings), &drawings)
+	}
+	return drawings
+}
+
+// FindDrawing 按 ID 查找绘图，不存在时返回 nil
+func (s *Share) FindDrawing(id string) *Drawing {
+	for _, d := range s.DrawingList() {
+		if d.ID == id {
+			return &d
+		}
+	}
+	return nil
+}
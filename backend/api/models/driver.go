@@ -0,0 +1,58 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqlcipherDriver SQLCipher 驱动注册的名称，仅在以 sqlcipher 构建标签编译时设置（见 sqlcipher.go）
+var sqlcipherDriver string
+
+// rawKeyPattern 64 位十六进制字符串作为 256 位原始密钥，跳过口令派生
+var rawKeyPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// SQLDriver 打开 path 处数据库使用的 database/sql 驱动名与 DSN。
+// driver 为 sqlcipher 时在 DSN 中附带密钥，驱动在每个新连接上先执行 PRAGMA key；测试模式的内存数据库不加密
+func SQLDriver(path string) (driver, dsn string, err error) {
+	cfg := config.Get()
+	if cfg.Database.Driver != "sqlcipher" || cfg.Server.TestMode {
+		return sqlite.DriverName, path, nil
+	}
+	if sqlcipherDriver == "" {
+		return "", "", errors.New(`database.driver sqlcipher is not available in this build; rebuild with CGO_ENABLED=1 and -tags "sqlcipher sqlite_fts5"`)
+	}
+	key, err := cfg.DatabaseKey()
+	if err != nil {
+		return "", "", err
+	}
+	if key == "" {
+		return "", "", errors.New("database encryption key is empty")
+	}
+	if rawKeyPattern.MatchString(key) {
+		key = "x'" + key + "'"
+	} else if strings.ContainsAny(key, "\"\x00") {
+		// 驱动以 PRAGMA key = "<密钥>" 执行，口令中不能含双引号
+		return "", "", errors.New(`database encryption passphrase must not contain '"'`)
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return sqlcipherDriver, fmt.Sprintf("%s%s_pragma_key=%s", path, sep, url.QueryEscape(key)), nil
+}
+
+// Dialector 按配置的驱动打开 path 处数据库的 gorm 方言
+func Dialector(path string) (gorm.Dialector, error) {
+	driver, dsn, err := SQLDriver(path)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlite.Dialector{DriverName: driver, DSN: dsn}, nil
+}
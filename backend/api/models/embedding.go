@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// ShareEmbedding 分享的语义向量，由标题、摘要与正文计算，用于相关分享推荐与语义搜索。
+// 向量按单位长度归一化后以 float32 小端序保存，比较时计算点积（即余弦相似度）
+type ShareEmbedding struct {
+	ShareID   string    `gorm:"primaryKey;size:64" json:"shareId"`
+	UserID    string    `gorm:"size:64;index" json:"userId"`
+	Model     string    `gorm:"size:128" json:"model"`   // 计算向量的模型，与当前配置不一致时视为过期
+	TextHash  string    `gorm:"size:64" json:"textHash"` // 参与计算的文本哈希，未变化时不重复计算
+	Dim       int       `json:"dim"`
+	Vector    []byte    `json:"-"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ShareEmbedding) TableName() string {
+	return "share_embeddings"
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// 端到端加密分享的正文格式：e2e1.<IV>.<密文>，两段均为无填充的 base64url。
+// 插件以 AES-256-GCM 加密 {"title", "content"} 的 JSON，密钥只出现在分享链接的 # 片段中，不会发送到服务器；
+// 服务器只保存与转发密文，不对正文做搜索索引、摘要、翻译、朗读、导出等任何处理
+const (
+	encryptedPrefix = "e2e1."
+	encryptedIVSize = 12
+	// encryptedTagSize AES-GCM 认证标签长度，密文至少包含该长度
+	encryptedTagSize = 16
+)
+
+// Encrypted 是否为端到端加密分享
+func (s *Share) Encrypted() bool {
+	return s.Mode == ShareModeEncrypted
+}
+
+// ValidEncryptedContent 正文是否为格式正确的加密分享密文；只检查格式，服务器无法也不尝试解密
+func ValidEncryptedContent(content string) bool {
+	rest, ok := strings.CutPrefix(content, encryptedPrefix)
+	if !ok {
+		return false
+	}
+	iv, ciphertext, ok := strings.Cut(rest, ".")
+	if !ok {
+		return false
+	}
+	ivBytes, err := base64.RawURLEncoding.DecodeString(iv)
+	if err != nil || len(ivBytes) != encryptedIVSize {
+		return false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(ciphertext)
+	return err == nil && len(data) > encryptedTagSize
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 导出任务状态
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportDone    = "done"
+	ExportFailed  = "failed"
+)
+
+// ExportJob 分享导出任务（如 LaTeX 文件包），在后台生成，结果保存在 storage
+type ExportJob struct {
+	ID           string     `gorm:"primaryKey;size:64" json:"id"`
+	ShareID      string     `gorm:"size:64;index" json:"shareId"`
+	UserID       string     `gorm:"size:64;index" json:"-"`
+	Format       string     `gorm:"size:20" json:"format"`
+	Reproducible bool       `gorm:"default:false" json:"reproducible"` // 可复现导出：相同内容生成逐字节一致的文件
+	Status       string     `gorm:"size:20;default:pending;index" json:"status"`
+	Error        string     `gorm:"size:500" json:"error,omitempty"`
+	FileName     string     `gorm:"size:255" json:"fileName,omitempty"`
+	StorageKey   string     `gorm:"size:255" json:"-"`
+	Size         int64      `json:"size"`
+	Warnings     string     `gorm:"type:text" json:"warnings,omitempty"` // 换行分隔，如未能打包的图片
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// ShareFeedback 读者对分享的反馈：「是否有帮助」及可选的文字说明。
+// 同一 IP 对同一分享在 FeedbackDedupWindow 内只保留一条，重复提交时覆盖
+type ShareFeedback struct {
+	ID        string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID   string    `gorm:"size:64;index:idx_share_feedback_share,priority:1" json:"shareId"`
+	Helpful   bool      `json:"helpful"`
+	Comment   string    `gorm:"type:text" json:"comment"` // 文字说明（可选）
+	IP        string    `gorm:"size:64" json:"-"`
+	CreatedAt time.Time `gorm:"index:idx_share_feedback_share,priority:2" json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ShareFeedback) TableName() string {
+	return "share_feedback"
+}
+
+// FeedbackDedupWindow 同一读者重复反馈的合并时间窗口
+const FeedbackDedupWindow = 24 * time.Hour
+
+// FeedbackSummary 分享的反馈汇总
+type FeedbackSummary struct {
+	Total       int64   `json:"total"`
+	Helpful     int64   `json:"helpful"`
+	NotHelpful  int64   `json:"notHelpful"`
+	HelpfulRate float64 `json:"helpfulRate"` // 认为有帮助的比例，没有反馈时为 0
+	Comments    int64   `json:"comments"`    // 附带文字说明的反馈数
+}
+
+// SummarizeFeedback 汇总分享在 [from, to) 内的反馈
+func SummarizeFeedback(shareID string, from, to time.Time) (*FeedbackSummary, error) {
+	var s FeedbackSummary
+	err := DB.Model(&ShareFeedback{}).
+		Select("COUNT(*) AS total, "+
+			"COALESCE(SUM(CASE WHEN helpful THEN 1 ELSE 0 END), 0) AS helpful, "+
+			"COALESCE(SUM(CASE WHEN comment <> '' THEN 1 ELSE 0 END), 0) AS comments").
+		Where("share_id = ? AND created_at >= ? AND created_at < ?", shareID, from, to).
+		Scan(&s).Error
+	if err != nil {
+		return nil, err
+	}
+	s.NotHelpful = s.Total - s.Helpful
+	if s.Total > 0 {
+		s.HelpfulRate = float64(s.Helpful) / float64(s.Total)
+	}
+	return &s, nil
+}
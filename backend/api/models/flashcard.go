@@ -0,0 +1,28 @@
+package models
+
+import "encoding/json"
+
+// 分享模式
+const (
+	ShareModeDoc        = "doc"        // 普通文档
+	ShareModeFlashcards = "flashcards" // 闪卡卡组：正文之外附带卡片数据，阅读页提供练习入口
+	ShareModeEncrypted  = "encrypted"  // 端到端加密：正文为插件加密后的密文，密钥只在链接的 # 片段中，见 encrypted.go
+)
+
+// Flashcard 闪卡，正反面为 Markdown
+type Flashcard struct {
+	ID      string   `json:"id"`
+	BlockID string   `json:"blockId,omitempty"` // 思源中的卡片块 ID
+	Front   string   `json:"front"`
+	Back    string   `json:"back"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// FlashcardList 解析分享保存的闪卡数据
+func (s *Share) FlashcardList() []Flashcard {
+	cards := []Flashcard{}
+	if s.Flashcards != "" {
+		_ = json.Unmarshal([]byte(s.Flashcards), &cards)
+	}
+	return cards
+}
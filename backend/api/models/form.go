@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// FormSubmission 读者在分享正文表单块中的一次提交，字段按标签记录
+type FormSubmission struct {
+	ID        string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID   string    `gorm:"size:64;index:idx_form_submission_form,priority:1" json:"shareId"`
+	FormKey   string    `gorm:"size:64;index:idx_form_submission_form,priority:2" json:"formKey"`
+	FormTitle string    `gorm:"size:512" json:"formTitle"` // 提交时的表单标题，表单从正文删除后仍可辨认
+	Data      string    `gorm:"type:text" json:"-"`        // 字段标签到填写内容的 JSON 对象
+	IP        string    `gorm:"size:64" json:"-"`
+	CreatedAt time.Time `gorm:"index:idx_form_submission_form,priority:3" json:"createdAt"`
+}
+
+// TableName 指定表名
+func (FormSubmission) TableName() string {
+	return "form_submissions"
+}
+
+// FormSubmissionCount 表单的提交数
+type FormSubmissionCount struct {
+	FormKey   string `json:"formKey"`
+	FormTitle string `json:"formTitle"`
+	Count     int64  `json:"count"`
+}
+
+// CountFormSubmissions 分享中各表单的提交数，包括已从正文删除的表单
+func CountFormSubmissions(shareID string) ([]FormSubmissionCount, error) {
+	var rows []FormSubmissionCount
+	err := DB.Model(&FormSubmission{}).
+		Select("form_key, MAX(form_title) AS form_title, COUNT(*) AS count").
+		Where("share_id = ?", shareID).Group("form_key").Scan(&rows).Error
+	return rows, err
+}
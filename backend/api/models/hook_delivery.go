@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// HookDelivery 投递失败、等待重试的异步钩子事件
+type HookDelivery struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Hook        string    `gorm:"size:128;index" json:"hook"` // 钩子名称
+	Event       string    `gorm:"size:32" json:"event"`
+	Payload     string    `gorm:"type:text;serializer:zstd" json:"-"` // 事件 JSON
+	Attempts    int       `json:"attempts"`                           // 已投递次数（含首次）
+	LastError   string    `gorm:"size:500" json:"lastError"`
+	NextAttempt time.Time `gorm:"index" json:"nextAttempt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (HookDelivery) TableName() string {
+	return "hook_deliveries"
+}
+
+// DueHookDeliveries 到达重试时间的钩子事件，按时间先后最多返回 limit 条
+func DueHookDeliveries(limit int) ([]HookDelivery, error) {
+	var deliveries []HookDelivery
+	err := DB.Where("next_attempt <= ?", time.Now()).Order("next_attempt").Limit(limit).Find(&deliveries).Error
+	return deliveries, err
+}
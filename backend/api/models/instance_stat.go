@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+// InstanceStat 实例指标的每日汇总，每天一行，当天内由定时任务更新为最新值
+type InstanceStat struct {
+	Date         string    `gorm:"primaryKey;size:10" json:"date"` // 服务器本地日期 YYYY-MM-DD
+	Users        int64     `json:"users"`
+	Shares       int64     `json:"shares"`       // 分享数（不含引用块分享）
+	Views        int64     `json:"views"`        // 累计浏览量（现存分享的浏览次数之和）
+	Assets       int64     `json:"assets"`       // 资源文件数
+	StorageBytes int64     `json:"storageBytes"` // 资源文件总大小
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (InstanceStat) TableName() string {
+	return "instance_stats"
+}
+
+// CollectInstanceStats 统计当前的实例指标
+func CollectInstanceStats() (InstanceStat, error) {
+	var s InstanceStat
+	if err := DB.Model(&User{}).Count(&s.Users).Error; err != nil {
+		return s, err
+	}
+	row := struct {
+		Count int64
+		Views int64
+	}{}
+	if err := DB.Model(&Share{}).Select("COUNT(*) AS count, COALESCE(SUM(view_count), 0) AS views").
+		Where("parent_share_id = ?", "").Scan(&row).Error; err != nil {
+		return s, err
+	}
+	s.Shares, s.Views = row.Count, row.Views
+	assets := struct {
+		Count int64
+		Bytes int64
+	}{}
+	if err := DB.Model(&Asset{}).Select("COUNT(*) AS count, COALESCE(SUM(size), 0) AS bytes").Scan(&assets).Error; err != nil {
+		return s, err
+	}
+	s.Assets, s.StorageBytes = assets.Count, assets.Bytes
+	return s, nil
+}
+
+// RecordInstanceStats 统计当前指标并写入 now 所在日期的汇总行
+func RecordInstanceStats(now time.Time) error {
+	s, err := CollectInstanceStats()
+	if err != nil {
+		return err
+	}
+	s.Date = now.Format(time.DateOnly)
+	return DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&s).Error
+}
+
+// InstanceStatsSince 返回 from 日期（含）之后的每日汇总，按日期升序
+func InstanceStatsSince(from time.Time) ([]InstanceStat, error) {
+	var stats []InstanceStat
+	err := DB.Where("date >= ?", from.Format(time.DateOnly)).Order("date").Find(&stats).Error
+	return stats, err
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InviteCode 管理员生成的注册邀请码，注册方式为 invite 时凭码注册
+type InviteCode struct {
+	Code      string     `gorm:"primaryKey;size:32" json:"code"`
+	CreatedBy string     `gorm:"size:64" json:"createdBy"` // 生成邀请码的管理员用户 ID
+	MaxUses   int        `gorm:"default:1" json:"maxUses"` // 可用次数，0 不限
+	Uses      int        `gorm:"default:0" json:"uses"`
+	ExpiresAt *time.Time `json:"expiresAt"` // 为空时长期有效
+	Note      string     `gorm:"size:200" json:"note,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (InviteCode) TableName() string {
+	return "invite_codes"
+}
+
+// Usable 邀请码是否仍可使用
+func (i *InviteCode) Usable() bool {
+	return (i.MaxUses == 0 || i.Uses < i.MaxUses) && (i.ExpiresAt == nil || time.Now().Before(*i.ExpiresAt))
+}
+
+// RedeemInvite 使用一次邀请码：仅当邀请码存在、未过期且未用完时计数并返回 true；
+// 应与创建用户在同一事务中执行，创建失败时一并回滚
+func RedeemInvite(tx *gorm.DB, code string) (bool, error) {
+	res := tx.Model(&InviteCode{}).
+		Where("code = ? AND (max_uses = 0 OR uses < max_uses) AND (expires_at IS NULL OR expires_at > ?)", code, time.Now()).
+		UpdateColumn("uses", gorm.Expr("uses + 1"))
+	return res.RowsAffected == 1, res.Error
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// 拼写与语法检查状态
+const (
+	LanguageCheckPending = "pending"
+	LanguageCheckOK      = "ok"
+	LanguageCheckFailed  = "failed"
+)
+
+// ShareLanguageCheck 分享正文的拼写与语法检查报告，只提供给作者。
+// 正文变化后文本哈希不再一致，报告视为过期，发布时重新检查
+type ShareLanguageCheck struct {
+	ShareID    string    `gorm:"primaryKey;size:64" json:"shareId"`
+	Status     string    `gorm:"size:20;default:pending" json:"status"`
+	Error      string    `gorm:"size:500" json:"error,omitempty"`
+	TextHash   string    `gorm:"size:64" json:"-"`                   // 检查时正文文本的 sha256
+	Language   string    `gorm:"size:32" json:"language,omitempty"`  // 检查服务识别或使用的语言
+	Issues     string    `gorm:"type:text;serializer:zstd" json:"-"` // 问题列表（JSON）
+	IssueCount int       `json:"issueCount"`
+	Truncated  bool      `json:"truncated"` // 正文或问题数超出上限，报告不完整
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ShareLanguageCheck) TableName() string {
+	return "share_language_checks"
+}
+
+// FindLanguageCheck 查询分享的拼写与语法检查报告，不存在时返回 nil
+func FindLanguageCheck(shareID string) *ShareLanguageCheck {
+	var l ShareLanguageCheck
+	if res := DB.Where("share_id = ?", shareID).Limit(1).Find(&l); res.Error != nil || res.RowsAffected == 0 {
+		return nil
+	}
+	return &l
+}
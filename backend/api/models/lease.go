@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Lease 多实例部署时的租约：同一名称同一时间只由一个实例持有，持有者定期续期，过期后其他实例可以接管（见 election）
+type Lease struct {
+	Name      string    `gorm:"primaryKey;size:64" json:"name"`
+	Holder    string    `gorm:"size:128;not null" json:"holder"` // 持有租约的实例
+	ExpiresAt time.Time `gorm:"not null" json:"expiresAt"`
+}
+
+// TableName 指定表名
+func (Lease) TableName() string {
+	return "leases"
+}
+
+// AcquireLease 租约不存在、已过期或已由 holder 持有时取得（续期）租约，有效期为 ttl；返回 holder 是否持有租约。
+// 先按条件更新，没有记录时依靠主键插入，并发争取时只有一个实例成功
+func AcquireLease(name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	res := DB.Model(&Lease{}).Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, now).
+		Updates(map[string]interface{}{"holder": holder, "expires_at": now.Add(ttl)})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+	err := DB.Create(&Lease{Name: name, Holder: holder, ExpiresAt: now.Add(ttl)}).Error
+	if IsUniqueViolation(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReleaseLease 释放 holder 持有的租约，其他实例不必等到过期即可接管
+func ReleaseLease(name, holder string) error {
+	return DB.Where("name = ? AND holder = ?", name, holder).Delete(&Lease{}).Error
+}
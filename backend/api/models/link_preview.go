@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// LinkPreview 外部链接的预览元数据缓存（标题、描述、缩略图），过期后重新抓取
+type LinkPreview struct {
+	ID          string    `gorm:"primaryKey;size:64" json:"-"` // URL 的 SHA-256
+	URL         string    `gorm:"type:text" json:"url"`
+	Title       string    `gorm:"size:500" json:"title"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	Image       string    `gorm:"type:text" json:"image,omitempty"`
+	SiteName    string    `gorm:"size:255" json:"siteName,omitempty"`
+	Failed      bool      `gorm:"default:false" json:"-"` // 抓取失败（短期缓存，避免反复请求）
+	FetchedAt   time.Time `json:"fetchedAt"`
+	ExpiresAt   time.Time `gorm:"index" json:"-"`
+}
+
+// TableName 指定表名
+func (LinkPreview) TableName() string {
+	return "link_previews"
+}
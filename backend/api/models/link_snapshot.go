@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// 存档状态
+const (
+	SnapshotPending = "pending"
+	SnapshotOK      = "ok"
+	SnapshotFailed  = "failed"
+)
+
+// LinkSnapshot 分享正文中外部链接的存档副本（正文提取后的 HTML 或原始 PDF），内容保存在 storage
+type LinkSnapshot struct {
+	ID          string     `gorm:"primaryKey;size:64" json:"id"`
+	ShareID     string     `gorm:"size:64;uniqueIndex:idx_snapshot_share_url" json:"-"`
+	URL         string     `gorm:"size:2048;uniqueIndex:idx_snapshot_share_url" json:"url"`
+	Title       string     `gorm:"size:500" json:"title"`
+	ContentType string     `gorm:"size:100" json:"contentType"`
+	StorageKey  string     `gorm:"size:255" json:"-"`
+	Size        int64      `json:"size"`
+	Status      string     `gorm:"size:20;default:pending" json:"status"`
+	Error       string     `gorm:"size:500" json:"error,omitempty"`
+	CapturedAt  *time.Time `json:"capturedAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (LinkSnapshot) TableName() string {
+	return "link_snapshots"
+}
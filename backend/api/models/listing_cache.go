@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/cache"
+)
+
+// listingChangedKey 公开列表（sitemap、RSS、日程订阅）最近一次变化的时间。早于该时间生成的列表不再使用，
+// 条目过期时此前生成的列表也都已过期
+const listingChangedKey = "listing:changed"
+
+// Listing 缓存的公开列表输出
+type Listing struct {
+	BuiltAt    time.Time // 开始查询的时间，之后发生的变化使其失效
+	ValidUntil time.Time // 列表中最早过期的分享的过期时间，之后需重新生成
+	Body       []byte
+}
+
+// InvalidateListings 分享发布、修改或删除后使缓存的公开列表失效（使用 Redis 时对所有实例生效）
+func InvalidateListings() {
+	cache.Save(listingChangedKey, time.Now().UnixNano())
+}
+
+// LoadListing 读取缓存的公开列表，未命中、已过期或在生成后有分享变化时返回 false
+func LoadListing(key string) ([]byte, bool) {
+	var l Listing
+	if !cache.Load("listing:"+key, &l) {
+		return nil, false
+	}
+	if !l.ValidUntil.IsZero() && time.Now().After(l.ValidUntil) {
+		return nil, false
+	}
+	var changed int64
+	if cache.Load(listingChangedKey, &changed) && changed >= l.BuiltAt.UnixNano() {
+		return nil, false
+	}
+	return l.Body, true
+}
+
+// SaveListing 缓存公开列表；builtAt 为开始查询的时间，validUntil 为零值时只受缓存有效期限制
+func SaveListing(key string, builtAt, validUntil time.Time, body []byte) {
+	cache.Save("listing:"+key, &Listing{BuiltAt: builtAt, ValidUntil: validUntil, Body: body})
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+// purgeBatch 定时清理任务单次处理的记录数
+const purgeBatch = 500
+
+// DeleteExpiredShares 删除在 before 之前过期的分享（封存保留期内的除外），返回删除的数量；
+// 每批之间检查 ctx，取消时返回已删除的数量与 ctx 的错误
+func DeleteExpiredShares(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		var ids []string
+		if err := DB.Model(&Share{}).Scopes(Unsealed).Where("expire_at < ?", before).Limit(purgeBatch).Pluck("id", &ids).Error; err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+		res := DB.Where("id IN ?", ids).Delete(&Share{})
+		if res.Error != nil {
+			return total, res.Error
+		}
+		RemoveShareIndex(ids...)
+		total += res.RowsAffected
+		if len(ids) < purgeBatch {
+			return total, nil
+		}
+	}
+}
+
+// OrphanAssets 所属分享已删除超过 grace（或记录已不存在）的资源，最多返回 limit 条
+func OrphanAssets(grace time.Duration, limit int) ([]Asset, error) {
+	var assets []Asset
+	err := DB.Where("NOT EXISTS (SELECT 1 FROM shares s WHERE s.id = assets.share_id AND (s.deleted_at IS NULL OR s.deleted_at > ?))",
+		time.Now().Add(-grace)).Limit(limit).Find(&assets).Error
+	return assets, err
+}
+
+// CheckpointWAL 将 SQLite WAL 中的内容写回数据库文件并截断 WAL
+func CheckpointWAL() error {
+	return DB.Exec("PRAGMA wal_checkpoint(TRUNCATE);").Error
+}
@@ -0,0 +1,490 @@
+package models
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// schemaMigrationsTable 记录已执行迁移 ID 的表
+const schemaMigrationsTable = "schema_migrations"
+
+// migrations 版本化的表结构迁移，按顺序执行，新安装同样从第一个迁移开始。
+// 已发布的迁移不能再修改：表结构变更须追加新的迁移，ID 取上一个迁移的序号加一（202610170001、202610170002……），
+// 顺序一目了然，插入新迁移时也不会与已有序号冲突
+var migrations = []*gormigrate.Migration{
+	{ID: "202610170001_baseline", Migrate: migrateBaseline},
+	{
+		// 引导令牌流程已由注册与个人中心的 Token 管理取代
+		ID: "202610170002_drop_bootstrap_tokens",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable("bootstrap_tokens")
+		},
+	},
+	{
+		ID: "202610170003_instance_stats",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&InstanceStat{})
+		},
+	},
+	{
+		// API Token 请求数、最近使用的 IP 与 User-Agent
+		ID: "202610170004_token_usage",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&UserToken{})
+		},
+	},
+	{
+		// 登录失败计数与账号锁定
+		ID: "202610170005_login_lockout",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&User{})
+		},
+	},
+	{
+		ID: "202610170006_api_usage",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&APIUsage{})
+		},
+	},
+	{
+		// 分享每月读者流量与用户流量配额覆盖
+		ID: "202610170007_bandwidth",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&User{}, &ShareBandwidth{})
+		},
+	},
+	{
+		// 用户语言偏好与订阅通知语言
+		ID: "202610170008_locale",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&User{}, &Subscription{})
+		},
+	},
+	{
+		// 分享合集
+		ID: "202610170009_collections",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Collection{}, &CollectionItem{})
+		},
+	},
+	{
+		// 用户自定义域名
+		ID: "202610170010_custom_domains",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&CustomDomain{})
+		},
+	},
+	{
+		// 分享朗读音频
+		ID: "202610170011_share_narrations",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareNarration{})
+		},
+	},
+	{
+		// 分享摘要与建议标签
+		ID: "202610170012_share_summary",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{})
+		},
+	},
+	{
+		// 图片资源的优化副本
+		ID: "202610170013_asset_variants",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&AssetVariant{})
+		},
+	},
+	{
+		// 分享语义向量
+		ID: "202610170014_share_embeddings",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareEmbedding{})
+		},
+	},
+	{
+		// 阅读页内容哈希与修改时间（ETag / Last-Modified），已有分享以最后更新时间为修改时间，
+		// 哈希在下次保存时补齐
+		ID: "202610170015_share_content_hash",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&Share{}); err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE shares SET content_modified = updated_at WHERE content_modified IS NULL").Error
+		},
+	},
+	{
+		// 读者问答开关
+		ID: "202610170016_share_allow_qa",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{})
+		},
+	},
+	{
+		// 分享的翻译版本
+		ID: "202610170017_share_translations",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareTranslation{})
+		},
+	},
+	{
+		// 发布时的拼写与语法检查报告
+		ID: "202610170018_share_language_checks",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareLanguageCheck{})
+		},
+	},
+	{
+		// 异步钩子投递失败后的重试队列
+		ID: "202610170019_hook_deliveries",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&HookDelivery{})
+		},
+	},
+	{
+		// 分享开放时间
+		ID: "202610170020_share_access_schedule",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&Share{}); err != nil {
+				return err
+			}
+			// 新增列在已有记录上为 NULL，公开列表按空字符串筛选未设置开放时间的分享
+			return tx.Exec("UPDATE shares SET access_schedule = '' WHERE access_schedule IS NULL").Error
+		},
+	},
+	{
+		// 附件下载策略
+		ID: "202610170021_share_asset_downloads",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{})
+		},
+	},
+	{
+		// 注册邀请码
+		ID: "202610170022_invite_codes",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&InviteCode{})
+		},
+	},
+	{
+		// 账号注销宽限期
+		ID: "202610170023_user_deletion_scheduled",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&User{})
+		},
+	},
+	{
+		// 分享使用条款与读者同意记录
+		ID: "202610170024_share_terms",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&Share{}, &ShareTermsAcceptance{}); err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE shares SET terms = '' WHERE terms IS NULL").Error
+		},
+	},
+	{
+		ID: "202610170025_share_seal",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{})
+		},
+	},
+	{
+		ID: "202610170026_share_max_views",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&Share{}); err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE shares SET max_views = 0 WHERE max_views IS NULL").Error
+		},
+	},
+	{
+		// 已有版本的哈希与签名在启动加载签名密钥后由 SignRevisions 补齐
+		ID: "202610170027_revision_signature",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareRevision{})
+		},
+	},
+	{
+		// 已有分享沿用侧边栏目录并显示标题锚点
+		ID: "202610170028_share_toc",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{})
+		},
+	},
+	{
+		ID: "202610170029_share_prerender",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{})
+		},
+	},
+	{
+		ID: "202610170030_share_views",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareView{})
+		},
+	},
+	{
+		ID: "202610170031_share_reports",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{}, &ShareReport{})
+		},
+	},
+	{
+		ID: "202610170032_token_cidrs",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&UserToken{})
+		},
+	},
+	{
+		ID: "202610170033_export_reproducible",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ExportJob{})
+		},
+	},
+	{
+		ID: "202610170034_short_links",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShortLink{})
+		},
+	},
+	{
+		ID: "202610170035_share_variants",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{}, &ShareView{}, &ShareRead{})
+		},
+	},
+	{
+		ID: "202610170036_share_feedback",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{}, &ShareFeedback{})
+		},
+	},
+	{
+		ID: "202610170037_poll_votes",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&PollVote{})
+		},
+	},
+	{
+		ID: "202610170038_form_submissions",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&FormSubmission{})
+		},
+	},
+	{
+		ID: "202610170039_share_payments",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&User{}, &Share{}, &SharePayment{})
+		},
+	},
+	{
+		ID: "202610170040_share_teaser",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{})
+		},
+	},
+	{
+		ID: "202610170041_share_templates",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareTemplate{})
+		},
+	},
+	{
+		ID: "202610170042_reading_progress",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ReadingProgress{})
+		},
+	},
+	{
+		ID: "202610170043_notification_settings",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Share{}, &NotificationSettings{})
+		},
+	},
+	{
+		ID: "202610170044_share_view_dailies",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&ShareViewDaily{})
+		},
+	},
+	{
+		ID: "202610170045_announcements",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Announcement{}, &AnnouncementDismissal{})
+		},
+	},
+	{
+		ID: "202610170046_quota_warnings",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&QuotaWarning{})
+		},
+	},
+	{
+		// 已有分享按当前正文补充字数与预计阅读时长
+		ID: "202610170047_reading_stats",
+		Migrate: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&Share{}, &ShareRead{}); err != nil {
+				return err
+			}
+			backfillReadingStats(tx)
+			return nil
+		},
+	},
+	{
+		// 多实例部署时定时任务的选主租约
+		ID: "202610170048_leases",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&Lease{})
+		},
+	},
+	{
+		// 入站回调的重放校验
+		ID: "202610170049_webhook_nonces",
+		Migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&WebhookNonce{})
+		},
+	},
+}
+
+// migrateBaseline 基线迁移：创建引入版本化迁移之前由 AutoMigrate 维护的全部表，
+// 并完成旧版本数据库升级所需的数据修正；对已有数据库可重复执行
+func migrateBaseline(tx *gorm.DB) error {
+	// 邮箱验证字段上线前注册的用户视为已验证，避免开启强制验证后无法登录
+	legacyUsers := tx.Migrator().HasTable(&User{}) && !tx.Migrator().HasColumn(&User{}, "email_verified")
+	// 任务统计字段上线前的分享需要补充统计
+	legacyTaskStats := tx.Migrator().HasTable(&Share{}) && !tx.Migrator().HasColumn(&Share{}, "tasks_total")
+	legacyDisabled := tx.Migrator().HasColumn(&Share{}, "disabled")
+
+	if err := tx.AutoMigrate(
+		&Share{},
+		&User{},
+		&UserToken{},
+		&UserIdentity{},
+		&Session{},
+		&Asset{},
+		&Theme{},
+		&Annotation{},
+		&Comment{},
+		&ShareAccess{},
+		&Subscription{},
+		&PushSubscription{},
+		&LinkPreview{},
+		&LinkSnapshot{},
+		&ExportJob{},
+		&ShareRevision{},
+		&Redirect{},
+	); err != nil {
+		return err
+	}
+	if legacyUsers {
+		tx.Model(&User{}).Where("1 = 1").Update("email_verified", true)
+	}
+	if legacyTaskStats {
+		backfillTaskStats()
+	}
+	if legacyDisabled {
+		if err := migrateShareStatus(tx); err != nil {
+			return err
+		}
+	}
+	// 全文搜索索引
+	return initSearchIndex()
+}
+
+// migrateShareStatus 将旧的 disabled 字段迁移为 status 后删除该列
+func migrateShareStatus(tx *gorm.DB) error {
+	res := tx.Unscoped().Model(&Share{}).Where("disabled = ?", true).Update("status", ShareStatusDisabled)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		log.Printf("Migrated %d disabled shares to status", res.RowsAffected)
+	}
+	return tx.Migrator().DropColumn(&Share{}, "disabled")
+}
+
+// MigrationStatus 迁移的执行状态
+type MigrationStatus struct {
+	ID      string
+	Applied bool
+	Unknown bool // 数据库中已执行但当前程序中不存在，说明数据库曾由更新版本的程序迁移
+}
+
+// SchemaStatus 返回全部迁移的执行状态，数据库中未知的迁移排在最后
+func SchemaStatus() ([]MigrationStatus, error) {
+	applied := map[string]bool{}
+	if DB.Migrator().HasTable(schemaMigrationsTable) {
+		var ids []string
+		if err := DB.Table(schemaMigrationsTable).Pluck("id", &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			applied[id] = true
+		}
+	}
+
+	status := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status = append(status, MigrationStatus{ID: m.ID, Applied: applied[m.ID]})
+		delete(applied, m.ID)
+	}
+	unknown := make([]string, 0, len(applied))
+	for id := range applied {
+		unknown = append(unknown, id)
+	}
+	sort.Strings(unknown)
+	for _, id := range unknown {
+		status = append(status, MigrationStatus{ID: id, Applied: true, Unknown: true})
+	}
+	return status, nil
+}
+
+// Migrate 依次执行待执行的迁移；数据库中存在未知迁移时拒绝执行
+func Migrate() error {
+	status, err := SchemaStatus()
+	if err != nil {
+		return err
+	}
+	for _, s := range status {
+		if !s.Applied {
+			log.Printf("Applying database migration %s", s.ID)
+		}
+	}
+	m := gormigrate.New(DB, &gormigrate.Options{
+		TableName:                 schemaMigrationsTable,
+		IDColumnName:              "id",
+		IDColumnSize:              255,
+		ValidateUnknownMigrations: true,
+	}, migrations)
+	return m.Migrate()
+}
+
+// checkSchema 启动时检查表结构版本：数据库由更新版本迁移过时返回错误；
+// 存在待执行迁移时按 autoMigrate 自动执行，或返回错误提示先运行 migrate up
+func checkSchema(autoMigrate bool) error {
+	status, err := SchemaStatus()
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, s := range status {
+		if s.Unknown {
+			return fmt.Errorf("database schema is newer than this server (unknown migration %s); upgrade the server instead of downgrading", s.ID)
+		}
+		if !s.Applied {
+			pending++
+		}
+	}
+	if pending == 0 {
+		return nil
+	}
+	if !autoMigrate {
+		return fmt.Errorf("database schema is out of date (%d pending migrations); run `siyuan-share-api migrate up` first or enable database.auto_migrate", pending)
+	}
+	return Migrate()
+}
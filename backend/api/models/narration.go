@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// 朗读音频生成状态
+const (
+	NarrationPending = "pending"
+	NarrationOK      = "ok"
+	NarrationFailed  = "failed"
+)
+
+// ShareNarration 分享的朗读音频：由语音合成生成，作为分享资源 assets/narration-<文本哈希>.<格式> 保存，
+// 正文变化后文本哈希不再一致，阅读页不再展示旧音频
+type ShareNarration struct {
+	ShareID   string    `gorm:"primaryKey;size:64" json:"shareId"`
+	Status    string    `gorm:"size:20;default:pending" json:"status"`
+	Error     string    `gorm:"size:500" json:"error,omitempty"`
+	TextHash  string    `gorm:"size:64" json:"textHash"` // 生成时朗读文本的 sha256
+	AssetPath string    `gorm:"size:512" json:"assetPath,omitempty"`
+	Chars     int       `json:"chars"` // 朗读文本的字符数
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ShareNarration) TableName() string {
+	return "share_narrations"
+}
+
+// FindNarration 查询分享的朗读音频记录，不存在时返回 nil
+func FindNarration(shareID string) *ShareNarration {
+	var n ShareNarration
+	if err := DB.Where("share_id = ?", shareID).First(&n).Error; err != nil {
+		return nil
+	}
+	return &n
+}
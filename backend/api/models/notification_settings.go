@@ -0,0 +1,38 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NotificationSettings 分享者的邮件通知偏好：首次浏览、浏览次数里程碑、即将到期与收到评论；
+// 没有记录时不发送任何通知
+type NotificationSettings struct {
+	UserID       string    `gorm:"primaryKey;size:64" json:"-"`
+	FirstView    bool      `gorm:"default:false" json:"firstView"` // 分享第一次被浏览
+	ViewsEvery   int       `gorm:"default:0" json:"viewsEvery"`    // 浏览次数每达到该数的倍数时通知，0 关闭
+	ExpiringDays int       `gorm:"default:0" json:"expiringDays"`  // 分享到期前若干天提醒，0 关闭
+	Comments     bool      `gorm:"default:false" json:"comments"`  // 收到评论
+	BaseURL      string    `gorm:"size:255" json:"-"`              // 保存设置时的站点地址，定时发送的到期提醒使用
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (NotificationSettings) TableName() string {
+	return "notification_settings"
+}
+
+// FindNotificationSettings 获取用户的通知偏好，未设置时返回 nil
+func FindNotificationSettings(userID string) (*NotificationSettings, error) {
+	var s NotificationSettings
+	err := DB.Where("user_id = ?", userID).First(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
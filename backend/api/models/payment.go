@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 付费阅读订单状态
+const (
+	PaymentStatusPending  = "pending"  // 已创建，等待支付平台回调
+	PaymentStatusPaid     = "paid"     // 已支付，持有解锁令牌的读者可阅读全文
+	PaymentStatusRefunded = "refunded" // 已退款，已签发的解锁令牌随即失效
+)
+
+// SharePayment 读者为付费阅读分享创建的订单：读者在支付平台付款时附带订单 ID，
+// 支付平台回调 webhook 后标记为已支付，读者凭订单兑换解锁令牌
+type SharePayment struct {
+	ID        string     `gorm:"primaryKey;size:64" json:"id"`
+	ShareID   string     `gorm:"size:64;index:idx_share_payment_share,priority:1" json:"shareId"` // 引用块子分享记在父分享下
+	Status    string     `gorm:"size:16;default:pending" json:"status"`
+	Amount    string     `gorm:"size:64" json:"amount"`     // 支付平台回调的金额（原样记录，含币种）
+	Reference string     `gorm:"size:255" json:"reference"` // 支付平台的交易号
+	IP        string     `gorm:"size:64" json:"-"`
+	CreatedAt time.Time  `gorm:"index:idx_share_payment_share,priority:2" json:"createdAt"`
+	PaidAt    *time.Time `json:"paidAt,omitempty"`
+}
+
+// TableName 指定表名
+func (SharePayment) TableName() string {
+	return "share_payments"
+}
+
+// PaymentSummary 分享的付费阅读汇总
+type PaymentSummary struct {
+	Paid     int64 `json:"paid"`
+	Pending  int64 `json:"pending"`
+	Refunded int64 `json:"refunded"`
+}
+
+// SummarizePayments 按状态统计分享的订单数
+func SummarizePayments(shareID string) (PaymentSummary, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := DB.Model(&SharePayment{}).Select("status, COUNT(*) AS count").
+		Where("share_id = ?", shareID).Group("status").Scan(&rows).Error
+	var s PaymentSummary
+	for _, r := range rows {
+		switch r.Status {
+		case PaymentStatusPaid:
+			s.Paid = r.Count
+		case PaymentStatusPending:
+			s.Pending = r.Count
+		case PaymentStatusRefunded:
+			s.Refunded = r.Count
+		}
+	}
+	return s, err
+}
+
+// DonationLinks 用户在公开页面展示的赞助链接，均为空时不展示
+type DonationLinks struct {
+	Kofi   string `json:"kofi,omitempty"`   // Ko-fi 用户名，链接为 https://ko-fi.com/<用户名>
+	Afdian string `json:"afdian,omitempty"` // 爱发电用户名，链接为 https://afdian.com/a/<用户名>
+	URL    string `json:"url,omitempty"`    // 其他赞助页面地址
+	Label  string `json:"label,omitempty"`  // 其他赞助页面的按钮文字，为空时显示「赞助」
+}
+
+// Empty 是否未设置任何赞助链接
+func (d DonationLinks) Empty() bool {
+	return d.Kofi == "" && d.Afdian == "" && d.URL == ""
+}
+
+// Donation 解析用户的赞助链接
+func (u *User) Donation() DonationLinks {
+	var d DonationLinks
+	if u.DonationLinks != "" {
+		_ = json.Unmarshal([]byte(u.DonationLinks), &d)
+	}
+	return d
+}
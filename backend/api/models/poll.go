@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// PollVote 读者在分享正文投票块中的投票。同一投票者（登录账号或浏览器指纹）在同一投票中只有一票，
+// 再次投票时改为新的选项；选项按文字记录，作者调整选项顺序不影响已有投票
+type PollVote struct {
+	ID        string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID   string    `gorm:"size:64;uniqueIndex:idx_poll_vote_voter,priority:1" json:"shareId"`
+	PollKey   string    `gorm:"size:64;uniqueIndex:idx_poll_vote_voter,priority:2" json:"pollKey"`
+	Voter     string    `gorm:"size:128;uniqueIndex:idx_poll_vote_voter,priority:3" json:"-"` // u:<用户 ID> 或 fp:<指纹哈希>
+	Choice    string    `gorm:"size:512" json:"choice"`
+	IP        string    `gorm:"size:64" json:"-"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (PollVote) TableName() string {
+	return "poll_votes"
+}
+
+// PollCounts 分享中各投票的各选项票数：投票 key -> 选项文字 -> 票数
+func PollCounts(shareID string) (map[string]map[string]int64, error) {
+	var rows []struct {
+		PollKey string
+		Choice  string
+		Votes   int64
+	}
+	if err := DB.Model(&PollVote{}).Select("poll_key, choice, COUNT(*) AS votes").
+		Where("share_id = ?", shareID).Group("poll_key, choice").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := map[string]map[string]int64{}
+	for _, r := range rows {
+		if counts[r.PollKey] == nil {
+			counts[r.PollKey] = map[string]int64{}
+		}
+		counts[r.PollKey][r.Choice] = r.Votes
+	}
+	return counts, nil
+}
+
+// VoterChoices 投票者在分享各投票中选择的选项：投票 key -> 选项文字
+func VoterChoices(shareID, voter string) (map[string]string, error) {
+	var votes []PollVote
+	if err := DB.Select("poll_key, choice").Where("share_id = ? AND voter = ?", shareID, voter).Find(&votes).Error; err != nil {
+		return nil, err
+	}
+	choices := make(map[string]string, len(votes))
+	for _, v := range votes {
+		choices[v.PollKey] = v.Choice
+	}
+	return choices, nil
+}
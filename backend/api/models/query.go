@@ -0,0 +1,215 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// ErrNotReadOnly 查询不是单条只读语句
+var ErrNotReadOnly = errors.New("only a single SELECT, WITH, VALUES or EXPLAIN statement is allowed")
+
+// queryCellChars 查询结果中单个文本值返回的最大字符数，超出部分截断
+const queryCellChars = 1000
+
+// readOnlyKeywords 允许的语句开头
+var readOnlyKeywords = map[string]bool{"SELECT": true, "WITH": true, "VALUES": true, "EXPLAIN": true}
+
+// QueryResult 只读查询的结果
+type QueryResult struct {
+	Columns   []string `json:"columns"`
+	Rows      [][]any  `json:"rows"`
+	Truncated bool     `json:"truncated"` // 结果超过行数上限，只返回前 maxRows 行
+	ElapsedMs int64    `json:"elapsedMs"`
+}
+
+// queryDB 以只读模式打开的数据库，供查询控制台使用；主库无法经由它写入，临时表仍可使用
+var queryDB = sync.OnceValues(func() (*sql.DB, error) {
+	driver, dsn, err := SQLDriver(readOnlyDSN(config.Get().DBPath()))
+	if err != nil {
+		return nil, err
+	}
+	return sql.Open(driver, dsn)
+})
+
+// readOnlyDSN 在数据库路径或 file: URI 的参数中加上 mode=ro，保留已有的参数。
+// 内存数据库（mode=memory，如测试模式）无法以只读模式打开，保持原样，由 RunReadOnlyQuery 回滚事务兜底
+func readOnlyDSN(path string) string {
+	if !strings.HasPrefix(path, "file:") {
+		path = "file:" + (&url.URL{Path: path}).EscapedPath()
+	}
+	base, rawQuery, _ := strings.Cut(path, "?")
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		params = url.Values{}
+	}
+	if params.Get("mode") != "memory" {
+		params.Set("mode", "ro")
+	}
+	return base + "?" + params.Encode()
+}
+
+// RunReadOnlyQuery 在只读连接上执行一条只读 SQL，最多返回 maxRows 行。
+// 语句先按开头关键字与语句数量检查，再作为子查询写入临时表：驱动只在执行阶段响应 ctx 的取消，
+// 结果先落到临时表中，超时的查询会被中断，而不会在逐行读取时失控。
+// 查询在事务中执行，结束后总是回滚，无法以只读模式打开的数据库也不会被写入；
+// 连接用后直接丢弃，临时表随之删除。二进制值显示为长度，过长的文本截断
+func RunReadOnlyQuery(ctx context.Context, query string, maxRows int) (*QueryResult, error) {
+	stmt, err := readOnlyStatement(query)
+	if err != nil {
+		return nil, err
+	}
+	db, err := queryDB()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	defer conn.Raw(func(any) error { return driver.ErrBadConn })
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	start := time.Now()
+	// EXPLAIN 不执行语句本身，也不能作为子查询，直接读取
+	if !strings.EqualFold(firstKeyword(stmt), "EXPLAIN") {
+		create := fmt.Sprintf("CREATE TEMP TABLE query_result AS SELECT * FROM (\n%s\n) LIMIT %d", stmt, maxRows+1)
+		if _, err := tx.ExecContext(ctx, create); err != nil {
+			return nil, err
+		}
+		stmt = "SELECT * FROM temp.query_result"
+	}
+	rows, err := tx.QueryContext(ctx, stmt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &QueryResult{Columns: columns, Rows: [][]any{}}
+	for rows.Next() {
+		if len(result.Rows) >= maxRows {
+			result.Truncated = true
+			break
+		}
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			values[i] = queryCell(v)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	result.ElapsedMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// queryCell 将查询结果中的值转换为可返回的形式
+func queryCell(v any) any {
+	switch v := v.(type) {
+	case []byte:
+		if !utf8.Valid(v) {
+			return fmt.Sprintf("<blob %d bytes>", len(v))
+		}
+		return truncateCell(string(v))
+	case string:
+		return truncateCell(v)
+	}
+	return v
+}
+
+func truncateCell(s string) string {
+	if utf8.RuneCountInString(s) <= queryCellChars {
+		return s
+	}
+	return string([]rune(s)[:queryCellChars]) + "…"
+}
+
+// firstKeyword 语句开头（跳过空白与注释）的关键字
+func firstKeyword(query string) string {
+	body := skipSQLSpace(query)
+	end := 0
+	for end < len(body) && (body[end] == '_' || body[end] >= 'A' && body[end] <= 'Z' || body[end] >= 'a' && body[end] <= 'z') {
+		end++
+	}
+	return body[:end]
+}
+
+// readOnlyStatement 检查查询是单条以只读关键字开头的语句，返回去掉结尾分号的语句：
+// 跳过注释与引号内的内容查找分号，分号后还有内容时视为多条语句
+func readOnlyStatement(query string) (string, error) {
+	if !readOnlyKeywords[strings.ToUpper(firstKeyword(query))] {
+		return "", ErrNotReadOnly
+	}
+	body := strings.TrimSpace(query)
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			j := strings.IndexByte(body[i+1:], closing)
+			if j < 0 {
+				return body, nil // 未闭合的引号由 SQLite 报错
+			}
+			i += j + 1
+		case strings.HasPrefix(body[i:], "--") || strings.HasPrefix(body[i:], "/*"):
+			rest := skipSQLSpace(body[i:])
+			i = len(body) - len(rest) - 1
+		case c == ';':
+			if skipSQLSpace(body[i+1:]) != "" {
+				return "", ErrNotReadOnly
+			}
+			return body[:i], nil
+		}
+	}
+	return body, nil
+}
+
+// skipSQLSpace 跳过开头的空白与注释
+func skipSQLSpace(s string) string {
+	for {
+		s = strings.TrimLeft(s, " \t\r\n")
+		switch {
+		case strings.HasPrefix(s, "--"):
+			if i := strings.IndexByte(s, '\n'); i >= 0 {
+				s = s[i+1:]
+			} else {
+				return ""
+			}
+		case strings.HasPrefix(s, "/*"):
+			if i := strings.Index(s[2:], "*/"); i >= 0 {
+				s = s[i+4:]
+			} else {
+				return ""
+			}
+		default:
+			return s
+		}
+	}
+}
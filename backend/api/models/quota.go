@@ -0,0 +1,168 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// Quota 用户生效的存储配额，0 表示不限制
+type Quota struct {
+	MaxBytes      int64 `json:"maxBytes"`
+	MaxShares     int   `json:"maxShares"`
+	MaxAssetBytes int64 `json:"maxAssetBytes"`
+	MaxBandwidth  int64 `json:"maxBandwidth"` // 每月读者流量
+}
+
+// QuotaOverride 管理员为单个用户设置的配额，nil 表示沿用默认配置
+type QuotaOverride struct {
+	MaxBytes      *int64 `json:"maxBytes"`
+	MaxShares     *int   `json:"maxShares"`
+	MaxAssetBytes *int64 `json:"maxAssetBytes"`
+	MaxBandwidth  *int64 `json:"maxBandwidth"`
+}
+
+// Usage 用户已使用的存储
+type Usage struct {
+	Bytes     int64 `json:"bytes"`     // 资源文件总大小
+	Assets    int64 `json:"assets"`    // 资源文件数
+	Shares    int64 `json:"shares"`    // 分享数（不含引用块分享）
+	Bandwidth int64 `json:"bandwidth"` // 本月读者流量
+}
+
+// Override 用户的配额覆盖设置
+func (u *User) Override() QuotaOverride {
+	return QuotaOverride{MaxBytes: u.QuotaMaxBytes, MaxShares: u.QuotaMaxShares, MaxAssetBytes: u.QuotaMaxAssetBytes,
+		MaxBandwidth: u.QuotaMaxBandwidth}
+}
+
+// EffectiveQuota 合并默认配置与用户覆盖后的配额
+func (u *User) EffectiveQuota() Quota {
+	cfg := config.Get().Quota
+	q := Quota{MaxBytes: cfg.MaxBytes, MaxShares: cfg.MaxShares, MaxAssetBytes: cfg.MaxAssetBytes, MaxBandwidth: cfg.MaxBandwidth}
+	if u.QuotaMaxBytes != nil {
+		q.MaxBytes = *u.QuotaMaxBytes
+	}
+	if u.QuotaMaxShares != nil {
+		q.MaxShares = *u.QuotaMaxShares
+	}
+	if u.QuotaMaxAssetBytes != nil {
+		q.MaxAssetBytes = *u.QuotaMaxAssetBytes
+	}
+	if u.QuotaMaxBandwidth != nil {
+		q.MaxBandwidth = *u.QuotaMaxBandwidth
+	}
+	return q
+}
+
+// UserQuota 查询用户生效的配额，用户不存在时使用默认配置
+func UserQuota(userID string) Quota {
+	var user User
+	DB.Select("id", "quota_max_bytes", "quota_max_shares", "quota_max_asset_bytes", "quota_max_bandwidth").Where("id = ?", userID).First(&user)
+	return user.EffectiveQuota()
+}
+
+// UserUsage 统计用户已使用的存储
+func UserUsage(userID string) (Usage, error) {
+	var u Usage
+	row := struct {
+		Bytes int64
+		Count int64
+	}{}
+	if err := DB.Model(&Asset{}).Select("COALESCE(SUM(size), 0) AS bytes, COUNT(*) AS count").
+		Where("user_id = ?", userID).Scan(&row).Error; err != nil {
+		return u, err
+	}
+	u.Bytes, u.Assets = row.Bytes, row.Count
+	if err := DB.Model(&Share{}).Where("user_id = ? AND parent_share_id = ?", userID, "").Count(&u.Shares).Error; err != nil {
+		return u, err
+	}
+	bw, err := UserMonthBandwidth(userID)
+	u.Bandwidth = bw
+	return u, err
+}
+
+// QuotaWarnLevels 用量达到配额的这些百分比时提醒用户，100 表示已超出配额、处于宽限中
+var QuotaWarnLevels = []int{80, 95, 100}
+
+// 配额状态
+const (
+	QuotaStateOK      = "ok"
+	QuotaStateWarning = "warning" // 达到配额的 80%
+	QuotaStateGrace   = "grace"   // 超出配额，仍在宽限内，可以继续发布
+	QuotaStateBlocked = "blocked" // 达到含宽限的上限，拒绝新的发布与上传（流量超出后资源以占位图代替）
+)
+
+// GraceLimit 含宽限（quota.grace_percent）的硬性上限，超过后才拒绝；limit 为 0（不限制）时返回 0
+func GraceLimit(limit int64) int64 {
+	if limit <= 0 {
+		return 0
+	}
+	return limit + limit*int64(config.Get().Quota.GracePercent)/100
+}
+
+// QuotaStatus 单项配额的用量状态
+type QuotaStatus struct {
+	Resource  string `json:"resource"` // storage / shares / bandwidth
+	Used      int64  `json:"used"`
+	Limit     int64  `json:"limit"`
+	HardLimit int64  `json:"hardLimit"` // 含宽限的上限
+	Percent   int    `json:"percent"`   // 用量占配额的百分比，向下取整
+	State     string `json:"state"`
+}
+
+// WarnLevel 用量达到的最高提醒级别（见 QuotaWarnLevels），未达到时为 0
+func (s QuotaStatus) WarnLevel() int {
+	level := 0
+	for _, l := range QuotaWarnLevels {
+		if s.Percent >= l {
+			level = l
+		}
+	}
+	return level
+}
+
+// QuotaStatuses 各项有限制的配额（存储、分享数、每月流量）的用量状态，单个资源大小的限制不计入
+func QuotaStatuses(usage Usage, quota Quota) []QuotaStatus {
+	items := []QuotaStatus{}
+	for _, r := range []struct {
+		resource    string
+		used, limit int64
+	}{
+		{"storage", usage.Bytes, quota.MaxBytes},
+		{"shares", usage.Shares, int64(quota.MaxShares)},
+		{"bandwidth", usage.Bandwidth, quota.MaxBandwidth},
+	} {
+		if r.limit <= 0 {
+			continue
+		}
+		s := QuotaStatus{Resource: r.resource, Used: r.used, Limit: r.limit, HardLimit: GraceLimit(r.limit)}
+		s.Percent = int(r.used * 100 / r.limit)
+		switch {
+		case r.used >= s.HardLimit:
+			s.State = QuotaStateBlocked
+		case r.used >= r.limit:
+			s.State = QuotaStateGrace
+		case s.Percent >= QuotaWarnLevels[0]:
+			s.State = QuotaStateWarning
+		default:
+			s.State = QuotaStateOK
+		}
+		items = append(items, s)
+	}
+	return items
+}
+
+// QuotaWarning 已向用户发送的配额提醒级别，用量回落后降低，再次上升时重新提醒
+type QuotaWarning struct {
+	UserID    string `gorm:"primaryKey;size:64"`
+	Resource  string `gorm:"primaryKey;size:16"`
+	Level     int    // 已提醒的最高级别
+	Period    string `gorm:"size:7"` // 每月流量所属的月份，其他配额为空
+	UpdatedAt time.Time
+}
+
+// TableName 指定表名
+func (QuotaWarning) TableName() string {
+	return "quota_warnings"
+}
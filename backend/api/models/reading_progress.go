@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// ReadingProgress 读者在长文档中的阅读进度（书签）：以匿名的读者令牌区分读者，不关联账号；
+// 读者在其他设备打开带令牌的续读链接即可从上次的位置继续阅读
+type ReadingProgress struct {
+	Token     string    `gorm:"primaryKey;size:64" json:"-"`
+	ShareID   string    `gorm:"primaryKey;size:64" json:"shareId"`
+	Anchor    string    `gorm:"size:128" json:"anchor"` // 读到的标题锚点，为空时按 Percent 定位
+	Percent   float64   `json:"percent"`                // 滚动位置（0-100）
+	UpdatedAt time.Time `gorm:"index" json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ReadingProgress) TableName() string {
+	return "reading_progress"
+}
+
+// ReadingProgressRetention 阅读进度在最后一次更新后保留的时间
+const ReadingProgressRetention = 180 * 24 * time.Hour
+
+// DeleteReadingProgressBefore 删除 before 之前最后更新的阅读进度，返回删除的数量
+func DeleteReadingProgressBefore(before time.Time) (int64, error) {
+	res := DB.Where("updated_at < ?", before).Delete(&ReadingProgress{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"log"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// 阅读速度：拉丁文字按词计，中日韩文字按字计
+const (
+	wordsPerMinute = 230
+	cjkPerMinute   = 400
+)
+
+// readingLinkTarget Markdown 链接与图片的地址部分，不计入字数
+var readingLinkTarget = regexp.MustCompile(`\]\([^)]*\)`)
+
+// ReadingStats 正文字数与预计阅读时长
+type ReadingStats struct {
+	Words   int `json:"words"`   // 字数：拉丁文字的词数与中日韩文字的字数之和
+	Minutes int `json:"minutes"` // 预计阅读分钟数，有正文时至少为 1
+}
+
+// isCJK 按字计数的文字：汉字、日文假名与韩文
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// CountReading 统计正文字数并估算阅读时长，跳过代码块与链接地址
+func CountReading(content string) ReadingStats {
+	words, cjk := 0, 0
+	fence := ""
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		inWord := false
+		for _, r := range readingLinkTarget.ReplaceAllString(line, "]") {
+			switch {
+			case isCJK(r):
+				cjk++
+				inWord = false
+			case unicode.IsLetter(r) || unicode.IsNumber(r) || r == '\'':
+				if !inWord {
+					words++
+					inWord = true
+				}
+			default:
+				inWord = false
+			}
+		}
+	}
+	stats := ReadingStats{Words: words + cjk}
+	if stats.Words > 0 {
+		minutes := float64(words)/wordsPerMinute + float64(cjk)/cjkPerMinute
+		stats.Minutes = max(1, int(math.Ceil(minutes)))
+	}
+	return stats
+}
+
+// updateReadingStats 随正文更新字数与预计阅读时长，供阅读页与分享列表展示而无需读取正文
+func (s *Share) updateReadingStats() {
+	if s.Encrypted() {
+		s.WordCount, s.ReadingMinutes = 0, 0
+		return
+	}
+	stats := CountReading(s.Content)
+	s.WordCount, s.ReadingMinutes = stats.Words, stats.Minutes
+}
+
+// backfillReadingStats 为阅读时长字段上线前的分享补充统计
+func backfillReadingStats(tx *gorm.DB) {
+	var ids []string
+	tx.Unscoped().Model(&Share{}).Pluck("id", &ids)
+	for _, id := range ids {
+		var share Share
+		if err := tx.Unscoped().Where("id = ?", id).First(&share).Error; err != nil {
+			continue
+		}
+		stats := CountReading(share.Content)
+		if err := tx.Unscoped().Model(&share).UpdateColumns(map[string]interface{}{
+			"word_count":      stats.Words,
+			"reading_minutes": stats.Minutes,
+		}).Error; err != nil {
+			log.Printf("reading stats migration failed (%s): %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Redirect 管理员配置的 URL 重定向规则：source 为路径（如 /old/page）或正则表达式（regex 为 true 时，
+// 目标中可用 $1、${name} 引用分组），target 为站内路径或外部地址
+type Redirect struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Source    string    `gorm:"size:1024;index" json:"source"`
+	Target    string    `gorm:"size:2048" json:"target"`
+	Status    int       `gorm:"default:301" json:"status"` // 301 / 302 / 307 / 308
+	Regex     bool      `gorm:"default:false" json:"regex"`
+	Note      string    `gorm:"size:200" json:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Redirect) TableName() string {
+	return "redirects"
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 举报处理状态
+const (
+	ReportStatusOpen      = "open"      // 待处理
+	ReportStatusDismissed = "dismissed" // 已驳回，分享不受影响
+	ReportStatusActioned  = "actioned"  // 已处理：停用分享或封禁所有者
+)
+
+// 管理员对举报的处理方式
+const (
+	ReportActionDismiss = "dismiss" // 驳回举报
+	ReportActionDisable = "disable" // 停用被举报的分享
+	ReportActionBan     = "ban"     // 封禁分享所有者并停用其全部分享
+)
+
+// ShareReport 读者对分享的举报，进入管理员的审核队列
+type ShareReport struct {
+	ID         string     `gorm:"primaryKey;size:64" json:"id"`
+	ShareID    string     `gorm:"size:64;index" json:"shareId"`
+	Reason     string     `gorm:"type:text" json:"reason"`
+	Contact    string     `gorm:"size:255" json:"contact"`             // 举报人的联系方式（可选）
+	ReporterID string     `gorm:"size:64" json:"reporterId,omitempty"` // 登录读者举报时的用户 ID
+	IP         string     `gorm:"size:64" json:"ip"`                   // 举报人 IP，同一 IP 对同一分享只保留一条待处理举报
+	Status     string     `gorm:"size:16;index" json:"status"`         // 见 ReportStatus*
+	Action     string     `gorm:"size:16" json:"action,omitempty"`     // 处理方式，见 ReportAction*
+	Note       string     `gorm:"type:text" json:"note,omitempty"`     // 管理员的处理备注
+	ResolvedBy string     `gorm:"size:64" json:"resolvedBy,omitempty"` // 处理的管理员用户 ID
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ShareReport) TableName() string {
+	return "share_reports"
+}
+
+// Moderated 分享是否被管理员停用；停用期间所有者不能重新上线，需管理员恢复
+func (s *Share) Moderated() bool {
+	return s.ModeratedAt != nil
+}
+
+// ResolveReports 将 shareIDs 对应的待处理举报标记为已处理
+func ResolveReports(tx *gorm.DB, shareIDs []string, action, note, adminID string) error {
+	if len(shareIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&ShareReport{}).Where("share_id IN ? AND status = ?", shareIDs, ReportStatusOpen).Updates(map[string]any{
+		"status":      ReportStatusActioned,
+		"action":      action,
+		"note":        note,
+		"resolved_by": adminID,
+		"resolved_at": time.Now(),
+	}).Error
+}
+
+// DeactivateUser 停用用户并撤销其登录会话与 API Token，返回撤销的数量
+func DeactivateUser(tx *gorm.DB, userID string) (sessions, tokens int64, err error) {
+	if err := tx.Model(&User{}).Where("id = ?", userID).Update("is_active", false).Error; err != nil {
+		return 0, 0, err
+	}
+	s := tx.Model(&Session{}).Where("user_id = ? AND revoked = ?", userID, false).Update("revoked", true)
+	if s.Error != nil {
+		return 0, 0, s.Error
+	}
+	t := tx.Model(&UserToken{}).Where("user_id = ? AND revoked = ?", userID, false).Update("revoked", true)
+	return s.RowsAffected, t.RowsAffected, t.Error
+}
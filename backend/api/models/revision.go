@@ -0,0 +1,117 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 版本来源
+const (
+	RevisionPublish  = "publish"  // 发布或重新发布
+	RevisionRollback = "rollback" // 回滚到历史版本
+)
+
+// keepRevisions 每个分享保留的历史版本数，更早的版本自动清理
+const keepRevisions = 50
+
+// ShareRevision 分享的历史版本：内容有变化的发布与回滚各记录一个版本，版本号最大者即当前内容
+type ShareRevision struct {
+	ID           string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID      string    `gorm:"size:64;uniqueIndex:idx_revision_share_version,priority:1" json:"shareId"`
+	Version      int       `gorm:"uniqueIndex:idx_revision_share_version,priority:2" json:"version"`
+	DocTitle     string    `gorm:"size:255" json:"docTitle"`
+	Content      string    `gorm:"type:text;serializer:zstd" json:"content,omitempty"`
+	References   string    `gorm:"type:text" json:"-"`
+	Citations    string    `gorm:"type:text" json:"-"`
+	Mode         string    `gorm:"size:16" json:"mode"`
+	Flashcards   string    `gorm:"type:text" json:"-"`
+	Drawings     string    `gorm:"type:text;serializer:zstd" json:"-"`
+	Size         int       `json:"size"` // 正文字节数
+	Source       string    `gorm:"size:20" json:"source"`
+	RestoredFrom int       `json:"restoredFrom,omitempty"`              // 回滚时的来源版本号
+	ContentHash  string    `gorm:"size:64;index" json:"contentHash"`    // 正文 Markdown 源文的 SHA-256
+	Signature    string    `gorm:"size:128" json:"signature,omitempty"` // 实例密钥对版本声明的签名
+	KeyID        string    `gorm:"size:32" json:"keyId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ShareRevision) TableName() string {
+	return "share_revisions"
+}
+
+// WithoutRevisionContent 列出版本时不加载正文等大字段
+func WithoutRevisionContent(db *gorm.DB) *gorm.DB {
+	return db.Omit("content", "references", "citations", "flashcards", "drawings")
+}
+
+// Apply 将版本内容恢复到分享
+func (r *ShareRevision) Apply(share *Share) {
+	share.DocTitle = r.DocTitle
+	share.Content = r.Content
+	share.References = r.References
+	share.Citations = r.Citations
+	share.Mode = r.Mode
+	share.Flashcards = r.Flashcards
+	share.Drawings = r.Drawings
+}
+
+// EnsureBaseRevision 分享尚无版本记录时（升级前发布的分享），先将覆盖前的内容记为第一个版本
+func EnsureBaseRevision(tx *gorm.DB, previous *Share) error {
+	var count int64
+	if err := tx.Model(&ShareRevision{}).Where("share_id = ?", previous.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	rev := newRevision(previous, 1, RevisionPublish, 0)
+	rev.CreatedAt = previous.UpdatedAt
+	return tx.Create(rev).Error
+}
+
+// RecordRevision 记录分享当前内容为新版本，并清理超出保留数的旧版本
+func RecordRevision(tx *gorm.DB, share *Share, source string, restoredFrom int) (*ShareRevision, error) {
+	var latest int
+	if err := tx.Model(&ShareRevision{}).Where("share_id = ?", share.ID).
+		Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+		return nil, err
+	}
+	rev := newRevision(share, latest+1, source, restoredFrom)
+	if err := tx.Create(rev).Error; err != nil {
+		return nil, err
+	}
+	if rev.Version > keepRevisions {
+		if err := tx.Where("share_id = ? AND version <= ?", share.ID, rev.Version-keepRevisions).
+			Delete(&ShareRevision{}).Error; err != nil {
+			return nil, err
+		}
+	}
+	return rev, nil
+}
+
+func newRevision(share *Share, version int, source string, restoredFrom int) *ShareRevision {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	rev := &ShareRevision{
+		ID:           "rev_" + hex.EncodeToString(b),
+		ShareID:      share.ID,
+		Version:      version,
+		DocTitle:     share.DocTitle,
+		Content:      share.Content,
+		References:   share.References,
+		Citations:    share.Citations,
+		Mode:         share.Mode,
+		Flashcards:   share.Flashcards,
+		Drawings:     share.Drawings,
+		Size:         len(share.Content),
+		Source:       source,
+		RestoredFrom: restoredFrom,
+		ContentHash:  SealDigest(share.Content),
+	}
+	rev.sign()
+	return rev
+}
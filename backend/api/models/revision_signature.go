@@ -0,0 +1,109 @@
+package models
+
+import (
+	"log"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/signing"
+)
+
+// RevisionSignature 版本签名信息：读者可按 statement 的格式由下载副本的 SHA-256 重建声明，
+// 再用实例公钥校验签名
+type RevisionSignature struct {
+	ShareID       string    `json:"shareId"`
+	Version       int       `json:"version"`
+	Algorithm     string    `json:"algorithm"`
+	HashAlgorithm string    `json:"hashAlgorithm"`
+	ContentHash   string    `json:"contentHash"`
+	Statement     string    `json:"statement"`
+	Signature     string    `json:"signature"`
+	KeyID         string    `json:"keyId"`
+	PublicKey     string    `json:"publicKey"`
+	SignedAt      time.Time `json:"signedAt"`
+}
+
+// sign 以实例密钥为版本签名；未加载密钥时保持未签名，由 SignRevisions 在启动时补签
+func (r *ShareRevision) sign() {
+	if signing.Default == nil {
+		return
+	}
+	r.Signature = signing.Default.Sign(signing.Statement(r.ShareID, r.Version, r.ContentHash))
+	r.KeyID = signing.Default.ID
+}
+
+// Signed 版本是否有当前实例密钥签出的签名
+func (r *ShareRevision) Signed() bool {
+	return r.Signature != "" && signing.Default != nil && r.KeyID == signing.Default.ID
+}
+
+// SignatureInfo 版本的签名信息，未签名时返回 nil
+func (r *ShareRevision) SignatureInfo() *RevisionSignature {
+	if !r.Signed() {
+		return nil
+	}
+	return &RevisionSignature{
+		ShareID:       r.ShareID,
+		Version:       r.Version,
+		Algorithm:     signing.Algorithm,
+		HashAlgorithm: "sha256",
+		ContentHash:   r.ContentHash,
+		Statement:     signing.Statement(r.ShareID, r.Version, r.ContentHash),
+		Signature:     r.Signature,
+		KeyID:         r.KeyID,
+		PublicKey:     signing.Default.PublicKey,
+		SignedAt:      r.CreatedAt,
+	}
+}
+
+// LatestRevision 分享的当前版本（版本号最大者），不加载正文等大字段
+func LatestRevision(shareID string) (*ShareRevision, error) {
+	var rev ShareRevision
+	if err := DB.Scopes(WithoutRevisionContent).Where("share_id = ?", shareID).
+		Order("version DESC").First(&rev).Error; err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// CurrentSignedRevision 分享当前正文对应的已签名版本；正文与最新版本不一致（如升级前发布且未再发布的分享，
+// 或译文）时返回 nil
+func CurrentSignedRevision(share *Share) *ShareRevision {
+	rev, err := LatestRevision(share.ID)
+	if err != nil || !rev.Signed() || rev.ContentHash != SealDigest(share.Content) {
+		return nil
+	}
+	return rev
+}
+
+// SignRevisions 为尚未签名或由其他密钥签名的版本补算正文哈希并签名（签名功能上线前的版本，或更换了实例密钥）
+func SignRevisions() {
+	if signing.Default == nil {
+		return
+	}
+	var ids []string
+	if err := DB.Model(&ShareRevision{}).Where("key_id IS NULL OR key_id <> ?", signing.Default.ID).
+		Pluck("id", &ids).Error; err != nil {
+		log.Printf("revision signing skipped: %v", err)
+		return
+	}
+	if len(ids) == 0 {
+		return
+	}
+	signed := 0
+	for _, id := range ids {
+		var rev ShareRevision
+		if err := DB.Select("id", "share_id", "version", "content").Where("id = ?", id).First(&rev).Error; err != nil {
+			continue
+		}
+		rev.ContentHash = SealDigest(rev.Content)
+		rev.sign()
+		if err := DB.Model(&ShareRevision{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+			"content_hash": rev.ContentHash, "signature": rev.Signature, "key_id": rev.KeyID,
+		}).Error; err != nil {
+			log.Printf("revision signing failed (%s): %v", id, err)
+			continue
+		}
+		signed++
+	}
+	log.Printf("Signed %d share revisions with key %s", signed, signing.Default.ID)
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SealedMutableColumns 封存期内仍可修改的列：只影响读者互动与收录，不改变正文与可访问性
+var SealedMutableColumns = map[string]bool{
+	"listed":           true,
+	"no_index":         true,
+	"allow_annotation": true,
+	"allow_comments":   true,
+	"allow_feedback":   true,
+	"allow_pdf":        true,
+	"archive_links":    true,
+	"allow_qa":         true,
+}
+
+// Sealed 分享是否处于封存保留期内
+func (s *Share) Sealed() bool {
+	return s.SealedUntil != nil && time.Now().Before(*s.SealedUntil)
+}
+
+// SealDigest 封存哈希：正文 Markdown 源文的 SHA-256（十六进制）
+func SealDigest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Unsealed 排除处于封存保留期内的分享
+func Unsealed(db *gorm.DB) *gorm.DB {
+	return db.Where("sealed_until IS NULL OR sealed_until <= ?", time.Now())
+}
+
+// SealShare 封存分享及其引用块子分享，保留到 until：首次封存时记录封存时间与正文哈希，再次封存只能延长期限；
+// 有效期早于期限时一并延长
+func SealShare(share *Share, until time.Time) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		var children []Share
+		if err := tx.Where("parent_share_id = ? AND user_id = ?", share.ID, share.UserID).Find(&children).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, s := range append([]*Share{share}, sharePtrs(children)...) {
+			updates := map[string]interface{}{"sealed_until": until}
+			if s.SealedAt == nil {
+				updates["sealed_at"] = now
+				updates["seal_hash"] = SealDigest(s.Content)
+			}
+			if s.ExpireAt.Before(until) {
+				updates["expire_at"] = until
+			}
+			if err := tx.Model(s).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func sharePtrs(shares []Share) []*Share {
+	out := make([]*Share, len(shares))
+	for i := range shares {
+		out[i] = &shares[i]
+	}
+	return out
+}
+
+// LatestSealedUntil 用户处于封存保留期内的分享中最晚的保留期限，没有时返回 false
+func LatestSealedUntil(userID string) (time.Time, bool) {
+	var share Share
+	err := DB.Select("id", "sealed_until").Where("user_id = ? AND sealed_until > ?", userID, time.Now()).
+		Order("sealed_until DESC").First(&share).Error
+	if err != nil || share.SealedUntil == nil {
+		return time.Time{}, false
+	}
+	return *share.SealedUntil, true
+}
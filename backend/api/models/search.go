@@ -0,0 +1,89 @@
+package models
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SearchHit 全文搜索结果
+type SearchHit struct {
+	ID        string    `json:"id"`
+	DocTitle  string    `json:"docTitle"`
+	Snippet   string    `json:"snippet"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// initSearchIndex 创建 FTS5 全文索引表（trigram 分词，兼容中文子串检索）；
+// SQLite 早于 3.34 时（如 SQLCipher 驱动内置的版本）没有 trigram 分词器，回退为默认分词
+func initSearchIndex() error {
+	err := DB.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS share_fts USING fts5(
+		share_id UNINDEXED, title, content, tokenize='trigram'
+	)`).Error
+	if err == nil || !strings.Contains(err.Error(), "tokenizer") {
+		return err
+	}
+	log.Printf("FTS5 trigram tokenizer unavailable, falling back to the default tokenizer: %v", err)
+	return DB.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS share_fts USING fts5(share_id UNINDEXED, title, content)`).Error
+}
+
+// SyncShareIndex 根据分享的 listed 状态更新全文索引，可传入事务以便与发布一同提交
+func SyncShareIndex(tx *gorm.DB, share *Share) error {
+	if err := tx.Exec("DELETE FROM share_fts WHERE share_id = ?", share.ID).Error; err != nil {
+		return err
+	}
+	if !share.Listed {
+		return nil
+	}
+	return tx.Exec("INSERT INTO share_fts (share_id, title, content) VALUES (?, ?, ?)",
+		share.ID, share.DocTitle, share.Content).Error
+}
+
+// RemoveShareIndex 从全文索引中移除分享
+func RemoveShareIndex(shareIDs ...string) {
+	if len(shareIDs) == 0 {
+		return
+	}
+	if err := DB.Exec("DELETE FROM share_fts WHERE share_id IN ?", shareIDs).Error; err != nil {
+		log.Printf("search index delete failed: %v", err)
+	}
+}
+
+// SearchListedShares 搜索公开收录的分享，仅返回可匿名访问的有效分享
+func SearchListedShares(q string, offset, limit int) ([]SearchHit, int64, error) {
+	q = strings.TrimSpace(q)
+	visible := "s.deleted_at IS NULL AND s.listed = 1 AND s.is_public = 1 AND s.status = 'published' " +
+		"AND s.require_password = 0 AND s.restricted = 0 AND s.access_schedule = '' AND s.terms = '' AND s.paywall = 0 AND s.teaser = 0 AND s.max_views = 0 AND s.expire_at > ?"
+	now := time.Now()
+
+	// trigram 分词至少需要 3 个字符，较短的关键字回退为 LIKE 查询
+	if len([]rune(q)) < 3 {
+		like := "%" + strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(q) + "%"
+		cond := visible + " AND (share_fts.title LIKE ? ESCAPE '\\' OR share_fts.content LIKE ? ESCAPE '\\')"
+		var total int64
+		if err := DB.Raw("SELECT COUNT(*) FROM share_fts JOIN shares s ON s.id = share_fts.share_id WHERE "+cond,
+			now, like, like).Scan(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		var hits []SearchHit
+		err := DB.Raw("SELECT s.id, s.doc_title, substr(share_fts.content, 1, 120) AS snippet, s.created_at "+
+			"FROM share_fts JOIN shares s ON s.id = share_fts.share_id WHERE "+cond+
+			" ORDER BY s.created_at DESC LIMIT ? OFFSET ?", now, like, like, limit, offset).Scan(&hits).Error
+		return hits, total, err
+	}
+
+	// 作为短语匹配，避免用户输入被解析为 FTS 语法
+	match := `"` + strings.ReplaceAll(q, `"`, `""`) + `"`
+	var total int64
+	if err := DB.Raw("SELECT COUNT(*) FROM share_fts JOIN shares s ON s.id = share_fts.share_id WHERE share_fts MATCH ? AND "+visible,
+		match, now).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var hits []SearchHit
+	err := DB.Raw("SELECT s.id, s.doc_title, snippet(share_fts, 2, '', '', '…', 32) AS snippet, s.created_at "+
+		"FROM share_fts JOIN shares s ON s.id = share_fts.share_id WHERE share_fts MATCH ? AND "+visible+
+		" ORDER BY bm25(share_fts) LIMIT ? OFFSET ?", match, now, limit, offset).Scan(&hits).Error
+	return hits, total, err
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Session Web 登录会话，会话 JWT 通过 jti 关联到该记录，撤销后令牌立即失效
+type Session struct {
+	ID         string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID     string    `gorm:"index;size:64" json:"-"`
+	Method     string    `gorm:"size:50" json:"method"` // 登录方式：password / oidc:<provider>
+	UserAgent  string    `gorm:"size:500" json:"userAgent"`
+	IP         string    `gorm:"size:64" json:"ip"`
+	Revoked    bool      `gorm:"default:false" json:"-"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	ExpiresAt  time.Time `gorm:"index" json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (Session) TableName() string {
+	return "sessions"
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -11,20 +12,137 @@ import (
 type Share struct {
 	ID string `gorm:"primaryKey;size:64" json:"id"`
 	// 组合索引加速 user+doc 查询与分页，并支持按创建时间排序
-	UserID          string         `gorm:"size:64;index:idx_user_doc,priority:1;index:idx_user_created,priority:1" json:"userId"`
-	DocID           string         `gorm:"size:64;index:idx_user_doc,priority:2" json:"docId"`
-	DocTitle        string         `gorm:"size:255" json:"docTitle"`
-	Content         string         `gorm:"type:text" json:"content"`
-	References      string         `gorm:"type:text" json:"references"`        // JSON 字符串存储引用块信息
-	ParentShareID   string         `gorm:"size:64;index" json:"parentShareId"` // 父分享ID(引用块分享时使用)
-	RequirePassword bool           `gorm:"default:false" json:"requirePassword"`
-	PasswordHash    string         `gorm:"size:255" json:"-"` // 不在 JSON 中暴露
-	ExpireAt        time.Time      `gorm:"index" json:"expireAt"`
-	IsPublic        bool           `gorm:"default:true" json:"isPublic"`
-	ViewCount       int            `gorm:"default:0" json:"viewCount"`
-	CreatedAt       time.Time      `gorm:"index:idx_user_created,priority:2" json:"createdAt"`
-	UpdatedAt       time.Time      `json:"updatedAt"`
-	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+	UserID           string         `gorm:"size:64;index:idx_user_doc,priority:1;index:idx_user_created,priority:1" json:"userId"`
+	DocID            string         `gorm:"size:64;index:idx_user_doc,priority:2" json:"docId"`
+	DocTitle         string         `gorm:"size:255" json:"docTitle"`
+	Content          string         `gorm:"type:text;serializer:zstd" json:"content"` // 大文本透明压缩存储
+	ContentKey       string         `gorm:"size:255" json:"-"`                        // 正文外置到 storage 时的对象键
+	References       string         `gorm:"type:text" json:"references"`              // JSON 字符串存储引用块信息
+	Citations        string         `gorm:"type:text" json:"-"`                       // CSL-JSON 文献数据（文献引用插件导出）
+	Mode             string         `gorm:"size:16;default:doc" json:"mode"`          // 分享模式：doc / flashcards
+	Flashcards       string         `gorm:"type:text" json:"-"`                       // 闪卡模式下的卡片数据（JSON 数组）
+	Drawings         string         `gorm:"type:text;serializer:zstd" json:"-"`       // 白板绘图（JSON 数组），见 Drawing
+	ParentShareID    string         `gorm:"size:64;index" json:"parentShareId"`       // 父分享ID(引用块分享时使用)
+	Tags             string         `gorm:"type:text" json:"-"`                       // JSON 数组字符串存储标签
+	Summary          string         `gorm:"size:1000" json:"summary"`                 // 摘要，用作 meta description 与订阅源简介，空时取正文开头
+	SummaryManual    bool           `gorm:"default:false" json:"summaryManual"`       // 摘要由作者填写，发布时不再自动生成覆盖
+	SuggestedTags    string         `gorm:"type:text" json:"-"`                       // 自动生成的建议标签（JSON 数组），由作者决定是否采纳
+	SummaryHash      string         `gorm:"size:64" json:"-"`                         // 生成摘要时标题与正文的哈希，未变化时不重复生成
+	Theme            string         `gorm:"size:64" json:"theme"`                     // 展示主题，空表示跟随默认
+	TOC              string         `gorm:"size:16;default:side" json:"toc"`          // 阅读页目录样式，见 ShareTOC*
+	VariantTheme     string         `gorm:"size:64" json:"-"`                         // A/B 测试 B 组的主题，见 ShareVariant
+	VariantTOC       string         `gorm:"size:16" json:"-"`                         // A/B 测试 B 组的目录样式
+	VariantPercent   int            `gorm:"default:50" json:"-"`                      // A/B 测试分配到 B 组的读者比例
+	HeadingAnchors   bool           `gorm:"default:true" json:"headingAnchors"`       // 标题旁显示可复制的锚点链接
+	PrerenderMath    bool           `gorm:"default:false" json:"prerenderMath"`       // 服务端预渲染公式（需配置 prerender.math）
+	PrerenderMermaid bool           `gorm:"default:false" json:"prerenderMermaid"`    // 服务端预渲染 Mermaid 图（需配置 prerender.mermaid）
+	PrerenderCode    bool           `gorm:"default:false" json:"prerenderCode"`       // 服务端代码高亮（需配置 prerender.highlight）
+	RequirePassword  bool           `gorm:"default:false" json:"requirePassword"`
+	PasswordHash     string         `gorm:"size:255" json:"-"` // 不在 JSON 中暴露
+	ExpireAt         time.Time      `gorm:"index" json:"expireAt"`
+	IsPublic         bool           `gorm:"default:true" json:"isPublic"`
+	Restricted       bool           `gorm:"default:false" json:"restricted"`                // 仅访问名单中的用户/邮箱可打开，见 ShareAccess
+	Status           string         `gorm:"size:16;default:published;index" json:"status"`  // 生命周期状态，见 ShareStatus*
+	Listed           bool           `gorm:"default:false" json:"listed"`                    // 是否收录到站内公开搜索
+	NoIndex          bool           `gorm:"default:false" json:"noIndex"`                   // 禁止搜索引擎收录，不出现在 sitemap.xml 中
+	AllowAnnotation  bool           `gorm:"default:false" json:"allowAnnotation"`           // 是否允许登录读者划线批注
+	AllowComments    bool           `gorm:"default:false" json:"allowComments"`             // 是否开放读者评论
+	AllowFeedback    bool           `gorm:"default:false" json:"allowFeedback"`             // 是否在文末询问读者「是否有帮助」
+	TrackReading     bool           `gorm:"default:false" json:"trackReading"`              // 是否统计读者读到了哪个标题（阅读漏斗），见 ShareRead
+	AllowPDF         bool           `gorm:"column:allow_pdf;default:false" json:"allowPdf"` // 是否允许读者下载 PDF
+	ArchiveLinks     bool           `gorm:"default:false" json:"archiveLinks"`              // 发布时为正文引用的外部链接保存存档副本
+	AllowQA          bool           `gorm:"column:allow_qa;default:false" json:"allowQa"`   // 是否允许读者就正文提问（需服务器开启 ai.qa）
+	AccessSchedule   string         `gorm:"type:text" json:"-"`                             // 开放时间（JSON），见 AccessSchedule；不在开放时间内时读者看到倒计时页
+	AssetDownloads   string         `gorm:"size:16;default:allow" json:"assetDownloads"`    // 附件下载策略，见 AssetDownloads*
+	Terms            string         `gorm:"type:text" json:"-"`                             // 使用条款（Markdown），非空时读者须先同意才能阅读，见 ShareTermsAcceptance
+	Paywall          bool           `gorm:"default:false" json:"paywall"`                   // 付费阅读：读者付款并经支付平台回调确认后才能阅读，见 SharePayment
+	PaywallPrice     string         `gorm:"size:64" json:"paywallPrice"`                    // 向读者展示的价格，如「¥5」
+	PaywallURL       string         `gorm:"column:paywall_url;size:1024" json:"paywallUrl"` // 支付页面地址，{order} 替换为订单 ID
+	Teaser           bool           `gorm:"default:false" json:"teaser"`                    // 试读模式：未通过密码、付费或登录校验的读者只能看到 <!-- more --> 之前的部分
+	SealedAt         *time.Time     `json:"sealedAt,omitempty"`                             // 封存时间，见 SealShare
+	SealedUntil      *time.Time     `gorm:"index" json:"sealedUntil,omitempty"`             // 封存保留期限，期满前不能修改正文、访问设置或删除
+	SealHash         string         `gorm:"size:64" json:"-"`                               // 封存时正文（Markdown 源文）的 SHA-256
+	ViewCount        int            `gorm:"default:0" json:"viewCount"`
+	MaxViews         int            `gorm:"default:0" json:"maxViews"`   // 浏览次数上限，0 不限；用尽后自动停用，见 ClaimView
+	ViewsExhaustedAt *time.Time     `json:"-"`                           // 浏览次数用尽、自动停用的时间
+	ModeratedAt      *time.Time     `json:"moderatedAt,omitempty"`       // 管理员因举报停用的时间，见 Moderated
+	ExpiryNotifiedAt *time.Time     `json:"-"`                           // 最近一次向分享者发送到期提醒的时间
+	TasksTotal       int            `gorm:"default:0" json:"tasksTotal"` // 正文任务列表项数，发布时统计
+	TasksDone        int            `gorm:"default:0" json:"tasksDone"`
+	WordCount        int            `json:"wordCount"`        // 正文字数，发布时统计，见 CountReading
+	ReadingMinutes   int            `json:"readingMinutes"`   // 预计阅读分钟数
+	ContentHash      string         `gorm:"size:64" json:"-"` // 阅读页内容与展示设置的哈希，见 updateContentHash
+	ContentModified  time.Time      `json:"-"`                // 内容哈希最近一次变化的时间，作为阅读页的 Last-Modified
+	CreatedAt        time.Time      `gorm:"index:idx_user_created,priority:2" json:"createdAt"`
+	UpdatedAt        time.Time      `json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	loadedContent  string // 最近一次从存储读取的正文，用于判断是否需要重新写入
+	pendingContent string // 保存过程中暂存的正文
+}
+
+// 分享生命周期状态
+const (
+	ShareStatusDraft     = "draft"     // 草稿：仅所有者可预览
+	ShareStatusPublished = "published" // 已发布：可通过链接访问，按设置收录到搜索、订阅源与日历
+	ShareStatusUnlisted  = "unlisted"  // 不公开列出：可通过链接访问，但不出现在搜索、订阅源与日历中
+	ShareStatusDisabled  = "disabled"  // 已停用：暂时下线，不删除
+)
+
+// 附件下载策略：图片、音视频、PDF 等可内联预览的资源在阅读页中的展示不受影响，
+// 限制的是带 download=1 的下载请求与无法内联预览的附件
+const (
+	AssetDownloadsAllow    = "allow"    // 允许下载
+	AssetDownloadsInline   = "inline"   // 仅允许内联预览，不提供下载
+	AssetDownloadsPassword = "password" // 下载时需再次输入访问密码
+)
+
+// 阅读页目录样式
+const (
+	ShareTOCSide     = "side"     // 桌面端固定在左侧，移动端通过按钮打开
+	ShareTOCFloating = "floating" // 悬浮在正文右上角，可收起
+	ShareTOCOff      = "off"      // 不显示目录
+)
+
+// DownloadPolicy 附件下载策略；要求输入密码但分享未设置密码时按仅内联预览处理
+func (s *Share) DownloadPolicy() string {
+	switch s.AssetDownloads {
+	case AssetDownloadsInline:
+		return AssetDownloadsInline
+	case AssetDownloadsPassword:
+		if !s.RequirePassword {
+			return AssetDownloadsInline
+		}
+		return AssetDownloadsPassword
+	}
+	return AssetDownloadsAllow
+}
+
+// shareStatusTransitions 允许的状态变更；发布过的分享不能再回到草稿
+var shareStatusTransitions = map[string][]string{
+	ShareStatusDraft:     {ShareStatusPublished, ShareStatusUnlisted, ShareStatusDisabled},
+	ShareStatusPublished: {ShareStatusUnlisted, ShareStatusDisabled},
+	ShareStatusUnlisted:  {ShareStatusPublished, ShareStatusDisabled},
+	ShareStatusDisabled:  {ShareStatusPublished, ShareStatusUnlisted},
+}
+
+// CanTransitionStatus 判断分享能否从 from 状态变更为 to 状态（相同状态视为允许）
+func CanTransitionStatus(from, to string) bool {
+	if from == to {
+		_, ok := shareStatusTransitions[to]
+		return ok
+	}
+	for _, s := range shareStatusTransitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Reachable 读者能否通过链接访问
+func (s *Share) Reachable() bool {
+	return s.Status == ShareStatusPublished || s.Status == ShareStatusUnlisted
 }
 
 // BlockReference 引用块信息
@@ -45,6 +163,33 @@ func (s *Share) IsExpired() bool {
 	return time.Now().After(s.ExpireAt)
 }
 
+// TagList 解析标签列表
+func (s *Share) TagList() []string {
+	tags := []string{}
+	if s.Tags != "" {
+		_ = json.Unmarshal([]byte(s.Tags), &tags)
+	}
+	return tags
+}
+
+// SuggestedTagList 解析自动生成的建议标签
+func (s *Share) SuggestedTagList() []string {
+	tags := []string{}
+	if s.SuggestedTags != "" {
+		_ = json.Unmarshal([]byte(s.SuggestedTags), &tags)
+	}
+	return tags
+}
+
+// EncodeTags 将标签列表编码为存储格式
+func EncodeTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(tags)
+	return string(b)
+}
+
 // FindActiveShareByDoc 查找用户某个文档的最新有效分享（未删除）
 func FindActiveShareByDoc(userID, docID string) (*Share, error) {
 	var share Share
@@ -60,8 +205,13 @@ func FindActiveShareByDoc(userID, docID string) (*Share, error) {
 	return &share, nil
 }
 
-// DeleteSharesByUser 删除用户的全部分享
+// DeleteSharesByUser 删除用户的全部分享，封存保留期内的分享保留
 func DeleteSharesByUser(userID string) (int64, error) {
-	res := DB.Where("user_id = ?", userID).Delete(&Share{})
+	var ids []string
+	DB.Model(&Share{}).Scopes(Unsealed).Where("user_id = ?", userID).Pluck("id", &ids)
+	res := DB.Scopes(Unsealed).Where("user_id = ?", userID).Delete(&Share{})
+	if res.Error == nil {
+		RemoveShareIndex(ids...)
+	}
 	return res.RowsAffected, res.Error
 }
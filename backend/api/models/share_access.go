@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ShareAccess 受限分享的访问名单条目：指定注册用户（UserID）或邮箱（Email）二选一
+type ShareAccess struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ShareID   string    `gorm:"size:64;index" json:"shareId"`
+	UserID    string    `gorm:"size:64;index" json:"userId,omitempty"`
+	Username  string    `gorm:"size:64" json:"username,omitempty"`     // 添加时的用户名，仅用于展示
+	Email     string    `gorm:"size:255;index" json:"email,omitempty"` // 小写存储
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TableName 指定表名
+func (ShareAccess) TableName() string {
+	return "share_access"
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"gorm.io/gorm"
+)
+
+// skipContentKey 查询时跳过加载外置正文（列表等不需要正文的场景）
+const skipContentKey = "share:skip_content"
+
+// contentInBlob 是否将分享正文外置到 storage (content.storage=blob)，默认存放在数据库
+func contentInBlob() bool {
+	return config.Get().Content.Storage == "blob"
+}
+
+// WithoutContent 查询分享时不加载正文，避免列表查询逐条读取外置文件
+func WithoutContent(db *gorm.DB) *gorm.DB {
+	return db.Omit("content", "references", "citations", "flashcards", "drawings").Set(skipContentKey, true)
+}
+
+func shareContentKey(id string) string {
+	return "contents/" + id + ".md"
+}
+
+// BeforeSave 更新任务统计、阅读时长与内容哈希；外置模式下将正文写入 storage，数据库仅保留元数据
+func (s *Share) BeforeSave(tx *gorm.DB) error {
+	if updates, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+		markContentModified(tx, updates)
+	}
+	if s.Content != "" || s.ContentKey == "" {
+		s.updateTaskStats()
+		s.updateReadingStats()
+		s.updateContentHash()
+	}
+	if !contentInBlob() {
+		// 从外置模式切回数据库模式时，正文重新落库
+		s.ContentKey = ""
+		return nil
+	}
+	if s.Content == "" && s.ContentKey != "" {
+		return nil
+	}
+	key := shareContentKey(s.ID)
+	if s.ContentKey != key || s.Content != s.loadedContent {
+		if storage.Default == nil {
+			return errors.New("storage not initialized")
+		}
+		if err := storage.Default.Put(tx.Statement.Context, key, strings.NewReader(s.Content), int64(len(s.Content)), "text/markdown; charset=utf-8"); err != nil {
+			return err
+		}
+	}
+	s.ContentKey = key
+	s.pendingContent = s.Content
+	s.Content = ""
+	return nil
+}
+
+// AfterSave 保存完成后恢复内存中的正文，调用方无需感知外置存储
+func (s *Share) AfterSave(tx *gorm.DB) error {
+	if s.ContentKey != "" && s.Content == "" {
+		s.Content = s.pendingContent
+		s.loadedContent = s.pendingContent
+	}
+	return nil
+}
+
+// AfterFind 读取外置正文
+func (s *Share) AfterFind(tx *gorm.DB) error {
+	if s.ContentKey == "" || s.Content != "" {
+		s.loadedContent = s.Content
+		return nil
+	}
+	if skip, ok := tx.Get(skipContentKey); ok && skip == true {
+		return nil
+	}
+	content, err := loadShareContent(tx.Statement.Context, s.ContentKey)
+	if err != nil {
+		return err
+	}
+	s.Content = content
+	s.loadedContent = content
+	return nil
+}
+
+func loadShareContent(ctx context.Context, key string) (string, error) {
+	if storage.Default == nil {
+		return "", errors.New("storage not initialized")
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	rc, _, err := storage.Default.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, rc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"reflect"
+	"regexp"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/cache"
+	"gorm.io/gorm"
+)
+
+// rawShareWritePattern 直接执行的 SQL 中修改 shares 表的语句
+var rawShareWritePattern = regexp.MustCompile(`(?is)^\s*(UPDATE|DELETE|INSERT|REPLACE)\b.*\bshares\b`)
+
+// viewOnlyColumns 读者浏览时更新的列，只更新这些列时不使缓存失效（缓存中的浏览次数在有效期内略有滞后）
+var viewOnlyColumns = map[string]bool{"view_count": true}
+
+func shareCacheKey(id string) string {
+	return "share:" + id
+}
+
+// FindShare 按 ID 查找分享，优先读取缓存。返回的分享供读者访问的只读路径使用，修改分享时应直接查询数据库
+func FindShare(id string) (*Share, error) {
+	var share Share
+	if cache.Load(shareCacheKey(id), &share) {
+		return &share, nil
+	}
+	if err := DB.Where("id = ?", id).First(&share).Error; err != nil {
+		return nil, err
+	}
+	cache.Save(shareCacheKey(id), &share)
+	return &share, nil
+}
+
+// registerCacheCallbacks 分享写入后使缓存失效：能确定主键时删除对应条目，按条件批量修改或直接执行 SQL 时整体失效；
+// 缓存的公开列表同时失效
+func registerCacheCallbacks() error {
+	cb := DB.Callback()
+	if err := cb.Create().After("gorm:create").Register("cache:share_create", invalidateShareCache); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("cache:share_update", invalidateShareCache); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("cache:share_delete", invalidateShareCache); err != nil {
+		return err
+	}
+	return cb.Raw().After("gorm:raw").Register("cache:share_raw", func(db *gorm.DB) {
+		if cache.Enabled() && db.Error == nil && rawShareWritePattern.MatchString(db.Statement.SQL.String()) {
+			cache.Flush()
+		}
+	})
+}
+
+func invalidateShareCache(db *gorm.DB) {
+	if !cache.Enabled() || db.Error != nil || db.RowsAffected == 0 ||
+		db.Statement.Schema == nil || db.Statement.Schema.Table != (Share{}).TableName() {
+		return
+	}
+	if m, ok := db.Statement.Dest.(map[string]interface{}); ok {
+		viewOnly := len(m) > 0
+		for col := range m {
+			viewOnly = viewOnly && viewOnlyColumns[col]
+		}
+		if viewOnly {
+			return
+		}
+	}
+	InvalidateListings()
+	ids := statementShareIDs(db)
+	if len(ids) == 0 {
+		cache.Flush()
+		return
+	}
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = shareCacheKey(id)
+	}
+	cache.Delete(keys...)
+}
+
+// statementShareIDs 语句操作的分享主键（取自 Model/Dest），无法确定时返回 nil
+func statementShareIDs(db *gorm.DB) []string {
+	field := db.Statement.Schema.PrioritizedPrimaryField
+	if field == nil {
+		return nil
+	}
+	rv := reflect.Indirect(db.Statement.ReflectValue)
+	var values []reflect.Value
+	switch rv.Kind() {
+	case reflect.Struct:
+		values = []reflect.Value{rv}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, reflect.Indirect(rv.Index(i)))
+		}
+	default:
+		return nil
+	}
+	ids := make([]string, 0, len(values))
+	for _, v := range values {
+		id, zero := field.ValueOf(db.Statement.Context, v)
+		s, ok := id.(string)
+		if zero || !ok || s == "" {
+			return nil
+		}
+		ids = append(ids, s)
+	}
+	return ids
+}
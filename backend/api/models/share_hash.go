@@ -0,0 +1,51 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// contentHashColumns 参与内容哈希的列
+var contentHashColumns = []string{
+	"doc_title", "content", "references", "citations", "flashcards", "drawings", "mode", "theme", "status",
+	"require_password", "restricted", "allow_pdf", "allow_qa", "expire_at", "access_schedule", "asset_downloads",
+	"terms", "paywall", "paywall_price", "teaser", "toc", "heading_anchors", "prerender_math", "prerender_mermaid", "prerender_code",
+}
+
+// updateContentHash 重新计算阅读页内容与展示设置的哈希，变化时更新 ContentModified；
+// 内容未变化的重新发布不影响读者的缓存
+func (s *Share) updateContentHash() {
+	h := sha256.New()
+	for _, part := range []string{
+		s.DocTitle, s.Content, s.References, s.Citations, s.Flashcards, s.Drawings, s.Mode, s.Theme, s.Status,
+		strconv.FormatBool(s.RequirePassword), strconv.FormatBool(s.Restricted), strconv.FormatBool(s.AllowPDF),
+		strconv.FormatBool(s.AllowQA),
+		s.ExpireAt.UTC().Format(time.RFC3339), s.AccessSchedule, s.AssetDownloads, s.Terms,
+		strconv.FormatBool(s.Paywall), s.PaywallPrice, strconv.FormatBool(s.Teaser),
+		s.TOC, strconv.FormatBool(s.HeadingAnchors),
+		strconv.FormatBool(s.PrerenderMath), strconv.FormatBool(s.PrerenderMermaid), strconv.FormatBool(s.PrerenderCode),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	hash := hex.EncodeToString(h.Sum(nil))
+	if hash != s.ContentHash || s.ContentModified.IsZero() {
+		s.ContentHash = hash
+		s.ContentModified = time.Now()
+	}
+}
+
+// markContentModified 按列更新（Updates/Update）不经过 updateContentHash，更新的列参与内容哈希时一并更新修改时间，
+// 哈希在下次完整保存时重新计算
+func markContentModified(tx *gorm.DB, updates map[string]interface{}) {
+	for _, col := range contentHashColumns {
+		if _, ok := updates[col]; ok {
+			tx.Statement.SetColumn("content_modified", time.Now())
+			return
+		}
+	}
+}
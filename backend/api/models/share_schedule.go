@@ -0,0 +1,219 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	// 内置时区数据库，精简镜像中没有系统时区数据时仍可解析发布者的时区
+	_ "time/tzdata"
+)
+
+// 开放时间中日期与时刻的格式（发布者时区的本地时间）
+const (
+	ScheduleDateLayout = "2006-01-02T15:04"
+	ScheduleTimeLayout = "15:04"
+)
+
+// maxScheduleRules 日期范围与每日时段各自的数量上限
+const maxScheduleRules = 20
+
+// AccessSchedule 分享的开放时间：按发布者时区解释的日期范围与每日时段，二者同时满足时读者才能打开；
+// 未设置的部分不作限制
+type AccessSchedule struct {
+	Timezone string         `json:"timezone"`         // IANA 时区名，如 Asia/Shanghai，空表示 UTC
+	Ranges   []AccessRange  `json:"ranges,omitempty"` // 开放的日期范围，满足任一即可
+	Daily    []AccessWindow `json:"daily,omitempty"`  // 每天开放的时段，满足任一即可
+}
+
+// AccessRange 开放的日期范围 [From, Until)，缺省一端表示不限
+type AccessRange struct {
+	From  string `json:"from,omitempty"`
+	Until string `json:"until,omitempty"`
+}
+
+// AccessWindow 每天开放的时段 [Start, End)，End 早于 Start 时跨越午夜
+type AccessWindow struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// Schedule 解析分享的开放时间，未设置时返回 nil
+func (s *Share) Schedule() *AccessSchedule {
+	if s.AccessSchedule == "" {
+		return nil
+	}
+	var a AccessSchedule
+	if err := json.Unmarshal([]byte(s.AccessSchedule), &a); err != nil {
+		return nil
+	}
+	return &a
+}
+
+// Scheduled 分享是否设置了开放时间
+func (s *Share) Scheduled() bool {
+	return s.AccessSchedule != ""
+}
+
+// OpenAt 分享在 t 时刻是否处于开放时间内，未设置开放时间时总是开放
+func (s *Share) OpenAt(t time.Time) bool {
+	a := s.Schedule()
+	return a == nil || a.Open(t)
+}
+
+// EncodeSchedule 校验开放时间并编码为存储格式；nil 或不含任何规则时返回空字符串（取消限制）
+func EncodeSchedule(a *AccessSchedule) (string, error) {
+	if a == nil || (len(a.Ranges) == 0 && len(a.Daily) == 0) {
+		return "", nil
+	}
+	if err := a.Validate(); err != nil {
+		return "", err
+	}
+	b, _ := json.Marshal(a)
+	return string(b), nil
+}
+
+// Validate 校验时区、日期范围与每日时段的格式
+func (a *AccessSchedule) Validate() error {
+	if _, err := time.LoadLocation(a.Timezone); err != nil {
+		return errors.New("unknown timezone: " + a.Timezone)
+	}
+	if len(a.Ranges) > maxScheduleRules || len(a.Daily) > maxScheduleRules {
+		return errors.New("at most 20 date ranges and 20 daily windows are allowed")
+	}
+	for _, r := range a.Ranges {
+		if r.From == "" && r.Until == "" {
+			return errors.New("date range must have a start or an end")
+		}
+		from, err1 := parseScheduleDate(r.From, time.UTC)
+		until, err2 := parseScheduleDate(r.Until, time.UTC)
+		if err1 != nil || err2 != nil {
+			return errors.New("dates must be formatted as YYYY-MM-DDTHH:MM")
+		}
+		if !from.IsZero() && !until.IsZero() && !until.After(from) {
+			return errors.New("date range must end after it starts")
+		}
+	}
+	for _, w := range a.Daily {
+		start, err1 := parseScheduleTime(w.Start)
+		end, err2 := parseScheduleTime(w.End)
+		if err1 != nil || err2 != nil {
+			return errors.New("daily times must be formatted as HH:MM")
+		}
+		if start == end {
+			return errors.New("daily window must not be empty")
+		}
+	}
+	return nil
+}
+
+// Location 开放时间所用的时区，无法解析时为 UTC
+func (a *AccessSchedule) Location() *time.Location {
+	loc, err := time.LoadLocation(a.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Open 判断 t 时刻是否处于开放时间内
+func (a *AccessSchedule) Open(t time.Time) bool {
+	loc := a.Location()
+	t = t.In(loc)
+	return a.inRanges(t, loc) && a.inDaily(t)
+}
+
+func (a *AccessSchedule) inRanges(t time.Time, loc *time.Location) bool {
+	if len(a.Ranges) == 0 {
+		return true
+	}
+	for _, r := range a.Ranges {
+		from, _ := parseScheduleDate(r.From, loc)
+		until, _ := parseScheduleDate(r.Until, loc)
+		if (from.IsZero() || !t.Before(from)) && (until.IsZero() || t.Before(until)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *AccessSchedule) inDaily(t time.Time) bool {
+	if len(a.Daily) == 0 {
+		return true
+	}
+	minute := t.Hour()*60 + t.Minute()
+	for _, w := range a.Daily {
+		start, err1 := parseScheduleTime(w.Start)
+		end, err2 := parseScheduleTime(w.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if start < end && minute >= start && minute < end {
+			return true
+		}
+		if start > end && (minute >= start || minute < end) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen 返回 t 之后（含 t）最近一次开放的时刻；不会再开放时第二个返回值为 false。
+// 开放时刻只可能是某个日期范围的开始，或某个日期范围内每日时段的开始，因此只需检查这些候选时刻
+func (a *AccessSchedule) NextOpen(t time.Time) (time.Time, bool) {
+	if a.Open(t) {
+		return t, true
+	}
+	loc := a.Location()
+	t = t.In(loc)
+	bases := []time.Time{t}
+	for _, r := range a.Ranges {
+		if from, err := parseScheduleDate(r.From, loc); err == nil && from.After(t) {
+			bases = append(bases, from)
+		}
+	}
+	var candidates []time.Time
+	for _, base := range bases {
+		if base.After(t) {
+			candidates = append(candidates, base)
+		}
+		// 基准时刻当天与次日的每日时段开始时刻
+		for d := 0; d < 2; d++ {
+			for _, w := range a.Daily {
+				start, err := parseScheduleTime(w.Start)
+				if err != nil {
+					continue
+				}
+				c := time.Date(base.Year(), base.Month(), base.Day()+d, start/60, start%60, 0, 0, loc)
+				if c.After(t) {
+					candidates = append(candidates, c)
+				}
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	for _, c := range candidates {
+		if a.Open(c) {
+			return c, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseScheduleDate 解析发布者时区的本地日期时间，空字符串返回零值
+func parseScheduleDate(s string, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(ScheduleDateLayout, s, loc)
+}
+
+// parseScheduleTime 解析每日时刻，返回自零点起的分钟数
+func parseScheduleTime(s string) (int, error) {
+	t, err := time.Parse(ScheduleTimeLayout, s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShareTemplate 发布模板：用户预设的一组发布设置，插件创建分享时通过 templateId 引用，
+// 请求中未传入的设置取模板的值
+type ShareTemplate struct {
+	ID              string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID          string    `gorm:"size:64;index" json:"userId"`
+	Name            string    `gorm:"size:100" json:"name"`
+	Theme           string    `gorm:"size:64" json:"theme"` // 展示主题，空表示跟随默认
+	TOC             string    `gorm:"size:16" json:"toc"`   // 目录样式，空表示不修改
+	ExpireDays      int       `json:"expireDays"`           // 有效天数，0 表示由请求指定
+	RequirePassword bool      `gorm:"default:false" json:"requirePassword"`
+	PasswordHash    string    `gorm:"size:255" json:"-"` // 默认访问密码，新建分享未传入密码时使用
+	IsPublic        bool      `gorm:"default:false" json:"isPublic"`
+	Listed          bool      `gorm:"default:false" json:"listed"`
+	IsDefault       bool      `gorm:"default:false" json:"isDefault"` // 新建分享未指定模板时使用
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ShareTemplate) TableName() string {
+	return "share_templates"
+}
+
+// FindShareTemplate 查找用户的指定发布模板
+func FindShareTemplate(userID, id string) (*ShareTemplate, error) {
+	var tpl ShareTemplate
+	err := DB.Where("id = ? AND user_id = ?", id, userID).First(&tpl).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
+
+// DefaultShareTemplate 用户的默认发布模板，未设置时返回 nil
+func DefaultShareTemplate(userID string) (*ShareTemplate, error) {
+	var tpl ShareTemplate
+	err := DB.Where("user_id = ? AND is_default = ?", userID, true).First(&tpl).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tpl, nil
+}
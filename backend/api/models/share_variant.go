@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// 分享 A/B 测试的分组：A 组使用分享本身的主题与目录样式，B 组使用 ShareVariant 中的设置
+const (
+	VariantA = "a"
+	VariantB = "b"
+)
+
+// ShareVariant 分享的 A/B 测试设置：B 组读者使用的主题与目录样式，空字段与 A 组相同；
+// Percent 为分配到 B 组的读者比例（0-100）
+type ShareVariant struct {
+	Theme   string `json:"theme"`
+	TOC     string `json:"toc"`
+	Percent int    `json:"percent"`
+}
+
+// Variant 返回分享的 A/B 测试设置，未开启时为 nil
+func (s *Share) Variant() *ShareVariant {
+	if s.VariantTheme == "" && s.VariantTOC == "" {
+		return nil
+	}
+	return &ShareVariant{Theme: s.VariantTheme, TOC: s.VariantTOC, Percent: s.VariantPercent}
+}
+
+// WithVariant 返回读者所在分组看到的分享：B 组为替换了主题与目录样式的副本，其他情况返回分享本身
+func (s *Share) WithVariant(variant string) *Share {
+	v := s.Variant()
+	if variant != VariantB || v == nil {
+		return s
+	}
+	cp := *s
+	if v.Theme != "" {
+		cp.Theme = v.Theme
+	}
+	if v.TOC != "" {
+		cp.TOC = v.TOC
+	}
+	return &cp
+}
+
+// ShareRead 读者离开阅读页时上报的一次阅读（原始记录），用于比较 A/B 测试各组的阅读时长与阅读深度，以及统计阅读漏斗；
+// Anchor 为读者读到的最后一个标题的锚点（标题之前离开时为空）。
+// 只在分享开启 A/B 测试或阅读统计（TrackReading）时记录，与浏览记录一同按 jobs.view_retention 删除
+type ShareRead struct {
+	ID      uint      `gorm:"primaryKey" json:"-"`
+	ShareID string    `gorm:"size:64;index:idx_share_reads_share,priority:1" json:"shareId"`
+	ReadAt  time.Time `gorm:"index:idx_share_reads_share,priority:2;index" json:"readAt"`
+	Variant string    `gorm:"size:1" json:"variant"`
+	Seconds int       `json:"seconds"` // 页面处于可见状态的时长
+	Depth   int       `json:"depth"`   // 最大滚动深度（百分比）
+	Anchor  string    `gorm:"size:128" json:"anchor"`
+}
+
+// TableName 指定表名
+func (ShareRead) TableName() string {
+	return "share_reads"
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// shareViewFlushInterval 浏览记录批量写库的间隔
+const shareViewFlushInterval = time.Minute
+
+// ShareView 读者的一次浏览（原始记录），供所有者导出分析；不保存 IP 等可识别读者的信息。
+// 记录保留 jobs.view_retention 后由 view_events 任务删除
+type ShareView struct {
+	ID       uint      `gorm:"primaryKey" json:"-"`
+	ShareID  string    `gorm:"size:64;index:idx_share_views_share,priority:1" json:"shareId"`
+	ViewedAt time.Time `gorm:"index:idx_share_views_share,priority:2;index" json:"viewedAt"`
+	Referrer string    `gorm:"size:255" json:"referrer"` // 来源站点的主机名，直接访问或来自本站时为空
+	Country  string    `gorm:"size:8" json:"country"`    // 国家/地区代码，未配置 IP 数据库或地区请求头时为空
+	Variant  string    `gorm:"size:1" json:"variant"`    // A/B 测试中读者所在的分组，未开启测试时为空
+}
+
+// TableName 指定表名
+func (ShareView) TableName() string {
+	return "share_views"
+}
+
+var shareViews = struct {
+	sync.Mutex
+	pending []ShareView
+	reads   []ShareRead
+	once    sync.Once
+}{}
+
+// RecordShareView 记录一次浏览，定时批量写库；jobs.view_retention 为 0 时不记录
+func RecordShareView(v ShareView) {
+	if v.ShareID == "" || config.Get().Jobs.ViewRetention <= 0 {
+		return
+	}
+	if v.ViewedAt.IsZero() {
+		v.ViewedAt = time.Now()
+	}
+	shareViews.Lock()
+	shareViews.pending = append(shareViews.pending, v)
+	shareViews.Unlock()
+	startShareViewFlusher()
+}
+
+// RecordShareRead 记录一次阅读，与浏览记录一同批量写库；jobs.view_retention 为 0 时不记录
+func RecordShareRead(r ShareRead) {
+	if r.ShareID == "" || config.Get().Jobs.ViewRetention <= 0 {
+		return
+	}
+	if r.ReadAt.IsZero() {
+		r.ReadAt = time.Now()
+	}
+	shareViews.Lock()
+	shareViews.reads = append(shareViews.reads, r)
+	shareViews.Unlock()
+	startShareViewFlusher()
+}
+
+// startShareViewFlusher 首次记录时启动定时写库
+func startShareViewFlusher() {
+	shareViews.once.Do(func() {
+		go func() {
+			for range time.Tick(shareViewFlushInterval) {
+				FlushShareViews()
+			}
+		}()
+	})
+}
+
+// FlushShareViews 将累计的浏览与阅读记录写入数据库
+func FlushShareViews() {
+	shareViews.Lock()
+	pending, reads := shareViews.pending, shareViews.reads
+	shareViews.pending, shareViews.reads = nil, nil
+	shareViews.Unlock()
+	if len(pending) > 0 {
+		if err := DB.CreateInBatches(pending, 500).Error; err != nil {
+			log.Printf("Failed to save share views: %v", err)
+		}
+	}
+	if len(reads) > 0 {
+		if err := DB.CreateInBatches(reads, 500).Error; err != nil {
+			log.Printf("Failed to save share reads: %v", err)
+		}
+	}
+}
+
+// DeleteShareViewsBefore 删除 before 之前的浏览与阅读记录，返回删除的浏览记录数量
+func DeleteShareViewsBefore(before time.Time) (int64, error) {
+	if err := DB.Where("read_at < ?", before).Delete(&ShareRead{}).Error; err != nil {
+		return 0, err
+	}
+	res := DB.Where("viewed_at < ?", before).Delete(&ShareView{})
+	return res.RowsAffected, res.Error
+}
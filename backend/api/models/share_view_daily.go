@@ -0,0 +1,175 @@
+package models
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// rollupMaxDays 单次汇总最多处理的天数，剩余日期留到下一次执行
+const rollupMaxDays = 400
+
+// ShareViewDaily 每日浏览汇总：view_rollup 任务把已结束日期的原始浏览记录按分享、来源站点与国家/地区计数。
+// 原始记录按 jobs.view_retention 删除后，按日期、来源或地区导出浏览数据仍使用汇总；汇总保留 jobs.summary_retention
+type ShareViewDaily struct {
+	ShareID  string `gorm:"primaryKey;size:64" json:"shareId"`
+	Date     string `gorm:"primaryKey;size:10;index" json:"date"` // 服务器本地日期 YYYY-MM-DD
+	Referrer string `gorm:"primaryKey;size:255" json:"referrer"`
+	Country  string `gorm:"primaryKey;size:8" json:"country"`
+	Views    int64  `json:"views"`
+}
+
+// TableName 指定表名
+func (ShareViewDaily) TableName() string {
+	return "share_view_dailies"
+}
+
+// ViewRollup 一次浏览汇总的结果，最近一次保存在内存中供管理后台查看
+type ViewRollup struct {
+	FinishedAt time.Time `json:"finishedAt"`
+	DurationMs int64     `json:"durationMs"`
+	Days       int       `json:"days"`    // 汇总的日期数（含没有浏览的日期）
+	Events     int64     `json:"events"`  // 汇总的原始浏览记录数
+	Rows       int       `json:"rows"`    // 写入的汇总行数
+	Through    string    `json:"through"` // 已汇总到的日期，尚未汇总过时为空
+	// Covered 此时间之前的原始记录均已汇总，可以删除
+	Covered time.Time `json:"-"`
+}
+
+var lastViewRollup struct {
+	sync.Mutex
+	result *ViewRollup
+}
+
+// LastViewRollup 启动以来最近一次浏览汇总的结果，尚未执行时返回 nil
+func LastViewRollup() *ViewRollup {
+	lastViewRollup.Lock()
+	defer lastViewRollup.Unlock()
+	if lastViewRollup.result == nil {
+		return nil
+	}
+	r := *lastViewRollup.result
+	return &r
+}
+
+// localDay t 所在日期（服务器本地）的 0 点
+func localDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// LastRolledUpDay 已汇总的最后一天（服务器本地 0 点），没有汇总数据时 ok 为 false
+func LastRolledUpDay() (time.Time, bool) {
+	var last *string
+	if err := DB.Model(&ShareViewDaily{}).Select("MAX(date)").Scan(&last).Error; err != nil || last == nil || *last == "" {
+		return time.Time{}, false
+	}
+	day, err := time.ParseInLocation(time.DateOnly, *last, time.Local)
+	return day, err == nil
+}
+
+// RollupShareViews 把 until 之前已结束的日期的原始浏览记录汇总为每日数据：从上次汇总的下一天开始（首次从最早的原始记录开始），
+// 逐日重新计数后整体替换该日的汇总。结束不足两个写库间隔的日期留到下次，避免遗漏尚在内存中的记录
+func RollupShareViews(ctx context.Context, until time.Time) (ViewRollup, error) {
+	started := time.Now()
+	FlushShareViews()
+	result := ViewRollup{}
+	end := localDay(until.Add(-2 * shareViewFlushInterval))
+
+	day, ok := LastRolledUpDay()
+	if ok {
+		day = day.AddDate(0, 0, 1)
+	}
+	// 跳过没有原始记录的日期
+	var first ShareView
+	q := DB.Select("viewed_at").Order("viewed_at")
+	if ok {
+		q = q.Where("viewed_at >= ?", day)
+	}
+	if err := q.Limit(1).Find(&first).Error; err != nil {
+		return result, err
+	}
+	if !first.ViewedAt.IsZero() {
+		if d := localDay(first.ViewedAt); !ok || d.After(day) {
+			day = d
+		}
+	} else {
+		day = end
+	}
+
+	for ; day.Before(end) && result.Days < rollupMaxDays; day = day.AddDate(0, 0, 1) {
+		if err := ctx.Err(); err != nil {
+			return finishViewRollup(result, started), err
+		}
+		next := day.AddDate(0, 0, 1)
+		var rows []ShareViewDaily
+		if err := DB.Model(&ShareView{}).Select("share_id, referrer, country, COUNT(*) AS views").
+			Where("viewed_at >= ? AND viewed_at < ?", day, next).Group("share_id, referrer, country").Scan(&rows).Error; err != nil {
+			return finishViewRollup(result, started), err
+		}
+		date := day.Format(time.DateOnly)
+		for i := range rows {
+			rows[i].Date = date
+			result.Events += rows[i].Views
+		}
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Where("date = ?", date).Delete(&ShareViewDaily{}).Error; err != nil {
+				return err
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			return tx.CreateInBatches(rows, 500).Error
+		})
+		if err != nil {
+			return finishViewRollup(result, started), err
+		}
+		result.Days++
+		result.Rows += len(rows)
+		result.Through = date
+	}
+	result.Covered = day
+	return finishViewRollup(result, started), nil
+}
+
+// finishViewRollup 记录汇总耗时并保存为最近一次的结果
+func finishViewRollup(result ViewRollup, started time.Time) ViewRollup {
+	result.FinishedAt = time.Now()
+	result.DurationMs = time.Since(started).Milliseconds()
+	if result.Through == "" {
+		if last, ok := LastRolledUpDay(); ok {
+			result.Through = last.Format(time.DateOnly)
+		}
+	}
+	lastViewRollup.Lock()
+	lastViewRollup.result = &result
+	lastViewRollup.Unlock()
+	return result
+}
+
+// DeleteShareViewDailiesBefore 删除 before 所在日期之前的每日汇总，返回删除的行数
+func DeleteShareViewDailiesBefore(before time.Time) (int64, error) {
+	res := DB.Where("date < ?", localDay(before).Format(time.DateOnly)).Delete(&ShareViewDaily{})
+	return res.RowsAffected, res.Error
+}
+
+// AddShareViewDailyCounts 将分享在 [from, to) 所在日期的每日汇总按 group（day / referrer / country）累加到 counts，
+// 汇总以整天计算
+func AddShareViewDailyCounts(counts map[string]int64, shareID, group string, from, to time.Time) error {
+	column := map[string]string{"day": "date", "referrer": "referrer", "country": "country"}[group]
+	var rows []struct {
+		GroupKey string
+		Views    int64
+	}
+	if err := DB.Model(&ShareViewDaily{}).Select(column+" AS group_key, SUM(views) AS views").
+		Where("share_id = ? AND date >= ? AND date < ?", shareID, localDay(from).Format(time.DateOnly), localDay(to).Format(time.DateOnly)).
+		Group(column).Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, r := range rows {
+		counts[r.GroupKey] += r.Views
+	}
+	return nil
+}
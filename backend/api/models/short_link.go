@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ShortLink 分享的短链接（/x/<code>），与分享的正式链接相互独立；同一分享可为不同受众创建多个短链接并分别统计点击
+type ShortLink struct {
+	Code          string     `gorm:"primaryKey;size:16" json:"code"`
+	ShareID       string     `gorm:"size:64;index" json:"shareId"`
+	UserID        string     `gorm:"size:64;index" json:"-"`
+	Label         string     `gorm:"size:100" json:"label"` // 用途说明，如投放渠道
+	Disabled      bool       `gorm:"default:false" json:"disabled"`
+	Clicks        int64      `gorm:"default:0" json:"clicks"`
+	LastClickedAt *time.Time `json:"lastClickedAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ShortLink) TableName() string {
+	return "short_links"
+}
+
+// RecordShortLinkClick 点击数加一并记录点击时间
+func RecordShortLinkClick(code string) error {
+	return DB.Model(&ShortLink{}).Where("code = ?", code).UpdateColumns(map[string]any{
+		"clicks":          gorm.Expr("clicks + 1"),
+		"last_clicked_at": time.Now(),
+	}).Error
+}
@@ -0,0 +1,10 @@
+//go:build sqlcipher
+
+package models
+
+// SQLCipher 驱动基于 cgo，默认构建不包含；以 -tags "sqlcipher sqlite_fts5" 编译后 database.driver 方可设为 sqlcipher
+import _ "github.com/mutecomm/go-sqlcipher/v4"
+
+func init() {
+	sqlcipherDriver = "sqlite3"
+}
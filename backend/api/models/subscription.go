@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Subscription 读者对分享更新的订阅（邮件等渠道），通过令牌确认与退订
+type Subscription struct {
+	ID             string     `gorm:"primaryKey;size:64" json:"id"`
+	ShareID        string     `gorm:"size:64;uniqueIndex:idx_subscription_target" json:"shareId"`
+	Channel        string     `gorm:"size:20;uniqueIndex:idx_subscription_target" json:"channel"` // email | push
+	Target         string     `gorm:"size:1024;uniqueIndex:idx_subscription_target" json:"-"`     // 邮箱地址或推送 endpoint
+	PushP256dh     string     `gorm:"size:255" json:"-"`                                          // Web Push 客户端公钥
+	PushAuth       string     `gorm:"size:64" json:"-"`                                           // Web Push 认证密钥
+	Token          string     `gorm:"size:64;uniqueIndex" json:"-"`                               // 确认/退订令牌
+	Confirmed      bool       `gorm:"default:false" json:"confirmed"`
+	Locale         string     `gorm:"size:16" json:"-"` // 订阅时的语言，用于更新通知邮件
+	LastNotifiedAt *time.Time `json:"lastNotifiedAt,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Subscription) TableName() string {
+	return "subscriptions"
+}
+
+// PushSubscription 登录用户在浏览器（仪表盘）注册的 Web Push 订阅
+type PushSubscription struct {
+	ID        string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID    string    `gorm:"size:64;index" json:"userId"`
+	Endpoint  string    `gorm:"size:1024;uniqueIndex" json:"endpoint"`
+	P256dh    string    `gorm:"size:255" json:"-"`
+	Auth      string    `gorm:"size:64" json:"-"`
+	UserAgent string    `gorm:"size:255" json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (PushSubscription) TableName() string {
+	return "push_subscriptions"
+}
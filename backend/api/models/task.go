@@ -0,0 +1,120 @@
+package models
+
+import (
+	"log"
+	"regexp"
+	"strings"
+)
+
+// TaskStats 正文中任务列表（- [ ] / - [x]）的完成情况
+type TaskStats struct {
+	Total    int           `json:"total"`
+	Done     int           `json:"done"`
+	Sections []TaskSection `json:"sections"`
+}
+
+// TaskSection 按标题分组的任务统计；看板代码块中每一列为一组
+type TaskSection struct {
+	Title  string `json:"title"`
+	Line   int    `json:"line"` // 标题所在行（从 1 开始），标题前的任务为 0
+	Kanban bool   `json:"kanban,omitempty"`
+	Total  int    `json:"total"`
+	Done   int    `json:"done"`
+}
+
+var (
+	taskItemPattern    = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\](?:\s|$)`)
+	taskHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+)
+
+// CountTasks 统计正文中的任务列表项，跳过普通代码块；kanban 代码块中的二级标题作为看板列统计
+func CountTasks(content string) TaskStats {
+	stats := TaskStats{Sections: []TaskSection{}}
+	section := TaskSection{}
+	flush := func() {
+		if section.Total > 0 {
+			stats.Sections = append(stats.Sections, section)
+		}
+	}
+
+	fence, kanban := "", false
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+				if kanban {
+					flush()
+					section = TaskSection{}
+					kanban = false
+				}
+				continue
+			}
+			if !kanban {
+				continue
+			}
+		} else if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			kanban = strings.EqualFold(strings.TrimSpace(strings.TrimLeft(trimmed, fence[:1])), "kanban")
+			if kanban {
+				flush()
+				section = TaskSection{Line: i + 1, Kanban: true}
+			}
+			continue
+		}
+
+		if m := taskHeadingPattern.FindStringSubmatch(trimmed); m != nil {
+			// 看板中的一级标题为看板名称，不作为列
+			if kanban && len(m[1]) == 1 {
+				continue
+			}
+			flush()
+			section = TaskSection{Title: m[2], Line: i + 1, Kanban: kanban}
+			continue
+		}
+		if m := taskItemPattern.FindStringSubmatch(line); m != nil {
+			section.Total++
+			stats.Total++
+			if m[1] != " " {
+				section.Done++
+				stats.Done++
+			}
+		}
+	}
+	flush()
+	return stats
+}
+
+// updateTaskStats 随正文更新任务统计，供分享列表展示进度而无需读取正文
+func (s *Share) updateTaskStats() {
+	stats := CountTasks(s.Content)
+	s.TasksTotal, s.TasksDone = stats.Total, stats.Done
+}
+
+// backfillTaskStats 为任务统计字段上线前的分享补充统计
+func backfillTaskStats() {
+	var ids []string
+	DB.Unscoped().Model(&Share{}).Pluck("id", &ids)
+	updated := 0
+	for _, id := range ids {
+		var share Share
+		if err := DB.Unscoped().Where("id = ?", id).First(&share).Error; err != nil {
+			continue
+		}
+		stats := CountTasks(share.Content)
+		if stats.Total == 0 {
+			continue
+		}
+		if err := DB.Unscoped().Model(&share).UpdateColumns(map[string]interface{}{
+			"tasks_total": stats.Total,
+			"tasks_done":  stats.Done,
+		}).Error; err != nil {
+			log.Printf("task stats migration failed (%s): %v", id, err)
+			continue
+		}
+		updated++
+	}
+	if updated > 0 {
+		log.Printf("Computed task stats for %d existing shares", updated)
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// ShareTermsAcceptance 读者同意分享使用条款的记录，供分享者留存；条款修改后需重新同意
+type ShareTermsAcceptance struct {
+	ID          string    `gorm:"primaryKey;size:64" json:"id"`
+	ShareID     string    `gorm:"size:64;index" json:"shareId"`    // 引用块子分享记录在父分享下
+	TermsHash   string    `gorm:"size:16" json:"termsHash"`        // 同意时的条款版本，见 Share.TermsVersion
+	UserID      string    `gorm:"size:64" json:"userId,omitempty"` // 登录读者
+	Email       string    `gorm:"size:255" json:"email,omitempty"` // 登录读者或通过邮件链接访问受限分享的读者的邮箱
+	Name        string    `gorm:"size:100" json:"name,omitempty"`  // 读者自行填写的姓名
+	Fingerprint string    `gorm:"size:128" json:"fingerprint"`     // 浏览器指纹，未提供时为 IP 与 User-Agent 的哈希
+	IP          string    `gorm:"size:64" json:"ip"`
+	UserAgent   string    `gorm:"size:500" json:"userAgent"`
+	AcceptedAt  time.Time `gorm:"index" json:"acceptedAt"`
+}
+
+// TableName 指定表名
+func (ShareTermsAcceptance) TableName() string {
+	return "share_terms_acceptances"
+}
+
+// TermsVersion 使用条款的版本（内容哈希前缀），未设置条款时为空
+func (s *Share) TermsVersion() string {
+	if s.Terms == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(s.Terms))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,28 @@
+package models
+
+import "gorm.io/gorm"
+
+// ResetData 清空全部数据，保留表结构与迁移记录，返回清空的表数；供测试模式的重置接口使用。
+// 尚未写库的 Token 使用记录、接口用量、流量与浏览记录先写入再一并清除
+func ResetData() (int, error) {
+	FlushTokenUsage()
+	FlushUsage()
+	FlushBandwidth()
+	FlushShareViews()
+
+	// 全文索引的内部表随 share_fts 一并清空，不能直接删除
+	var tables []string
+	if err := DB.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'share_fts_%' AND name != ?",
+		schemaMigrationsTable).Scan(&tables).Error; err != nil {
+		return 0, err
+	}
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for _, t := range tables {
+			if err := tx.Exec(`DELETE FROM "` + t + `"`).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return len(tables), err
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Theme 用户上传的自定义主题（CSS 包）
+type Theme struct {
+	ID        string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID    string    `gorm:"size:64;index" json:"userId"`
+	Name      string    `gorm:"size:100" json:"name"`
+	CSS       string    `gorm:"type:text" json:"css,omitempty"`
+	IsDefault bool      `gorm:"default:false" json:"isDefault"` // 用户分享的默认主题
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (Theme) TableName() string {
+	return "themes"
+}
+
+// FindUserTheme 查找用户的指定主题
+func FindUserTheme(userID, id string) (*Theme, error) {
+	var theme Theme
+	err := DB.Where("id = ? AND user_id = ?", id, userID).First(&theme).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &theme, nil
+}
+
+// ResolveShareTheme 获取分享实际使用的自定义主题：分享指定的主题优先，其次为用户默认主题
+func ResolveShareTheme(share *Share) *Theme {
+	var theme Theme
+	q := DB.Select("id", "user_id", "name", "is_default", "updated_at").Where("user_id = ?", share.UserID)
+	if share.Theme != "" {
+		q = q.Where("id = ?", share.Theme)
+	} else {
+		q = q.Where("is_default = ?", true)
+	}
+	if err := q.First(&theme).Error; err != nil {
+		return nil
+	}
+	return &theme
+}
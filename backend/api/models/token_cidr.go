@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// MaxTokenCIDRs 单个 API Token 允许的来源地址段数量上限
+const MaxTokenCIDRs = 20
+
+// NormalizeCIDRs 校验并规范化 Token 的来源地址白名单：单个 IP 视为 /32（IPv6 为 /128），
+// 地址段按掩码对齐并去重；存在无法解析的条目时返回错误
+func NormalizeCIDRs(entries []string) ([]string, error) {
+	out := make([]string, 0, len(entries))
+	seen := map[string]bool{}
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		var prefix netip.Prefix
+		if strings.Contains(e, "/") {
+			p, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR: %s", e)
+			}
+			if p.Addr().Is4In6() {
+				p = netip.PrefixFrom(p.Addr().Unmap(), max(p.Bits()-96, 0))
+			}
+			prefix = p.Masked()
+		} else {
+			addr, err := netip.ParseAddr(e)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR: %s", e)
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		if s := prefix.String(); !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	if len(out) > MaxTokenCIDRs {
+		return nil, fmt.Errorf("at most %d CIDR ranges are allowed", MaxTokenCIDRs)
+	}
+	return out, nil
+}
+
+// SetCIDRs 设置 Token 的来源地址白名单，cidrs 应已经过 NormalizeCIDRs；为空表示不限制
+func (t *UserToken) SetCIDRs(cidrs []string) {
+	if len(cidrs) == 0 {
+		t.AllowedCIDRs = ""
+		return
+	}
+	b, _ := json.Marshal(cidrs)
+	t.AllowedCIDRs = string(b)
+}
+
+// CIDRs 返回 Token 的来源地址白名单，未设置时为空
+func (t *UserToken) CIDRs() []string {
+	if t.AllowedCIDRs == "" {
+		return []string{}
+	}
+	var cidrs []string
+	if err := json.Unmarshal([]byte(t.AllowedCIDRs), &cidrs); err != nil {
+		return []string{}
+	}
+	return cidrs
+}
+
+// AllowsIP 请求来源 ip 是否在 Token 的白名单内；未设置白名单时总是允许，
+// 白名单无法解析时拒绝，避免误配置导致限制失效
+func (t *UserToken) AllowsIP(ip string) bool {
+	if t.AllowedCIDRs == "" {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	var cidrs []string
+	if err := json.Unmarshal([]byte(t.AllowedCIDRs), &cidrs); err != nil {
+		return false
+	}
+	for _, c := range cidrs {
+		if p, err := netip.ParsePrefix(c); err == nil && p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
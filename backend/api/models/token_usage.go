@@ -0,0 +1,86 @@
+package models
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"gorm.io/gorm"
+)
+
+// tokenUsageFlushInterval API Token 使用记录批量写库的间隔（auth.token_usage_flush_interval），每轮重新读取以支持热加载
+func tokenUsageFlushInterval() time.Duration {
+	if d := time.Duration(config.Get().Auth.TokenUsageFlushInterval); d >= time.Second {
+		return d
+	}
+	return time.Minute
+}
+
+// tokenUse 两次写库之间某个 Token 的使用情况
+type tokenUse struct {
+	count     int64
+	lastAt    time.Time
+	ip        string
+	userAgent string
+}
+
+var tokenUsage = struct {
+	sync.Mutex
+	pending map[string]*tokenUse
+	once    sync.Once
+}{pending: map[string]*tokenUse{}}
+
+// RecordTokenUse 记录一次 API Token 使用，先在内存中按 Token 累计，定时批量写库，避免每个请求一次 UPDATE
+func RecordTokenUse(tokenID, ip, userAgent string) {
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	tokenUsage.Lock()
+	u := tokenUsage.pending[tokenID]
+	if u == nil {
+		u = &tokenUse{}
+		tokenUsage.pending[tokenID] = u
+	}
+	u.count++
+	u.lastAt, u.ip, u.userAgent = time.Now(), ip, userAgent
+	tokenUsage.Unlock()
+
+	tokenUsage.once.Do(func() {
+		go func() {
+			for {
+				time.Sleep(tokenUsageFlushInterval())
+				FlushTokenUsage()
+			}
+		}()
+	})
+}
+
+// FlushTokenUsage 将累计的 API Token 使用记录写入数据库
+func FlushTokenUsage() {
+	tokenUsage.Lock()
+	pending := tokenUsage.pending
+	if len(pending) == 0 {
+		tokenUsage.Unlock()
+		return
+	}
+	tokenUsage.pending = map[string]*tokenUse{}
+	tokenUsage.Unlock()
+
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		for id, u := range pending {
+			if err := tx.Model(&UserToken{}).Where("id = ?", id).UpdateColumns(map[string]interface{}{
+				"request_count":   gorm.Expr("request_count + ?", u.count),
+				"last_used_at":    u.lastAt,
+				"last_used_ip":    u.ip,
+				"last_user_agent": u.userAgent,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Failed to save token usage: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// 译文状态
+const (
+	TranslationPending = "pending" // 正在生成
+	TranslationReady   = "ready"   // 可供读者访问
+	TranslationFailed  = "failed"  // 生成失败，见 Error
+)
+
+// ShareTranslation 分享的翻译版本，读者通过 /s/<分享 ID>/<语言> 访问。
+// 机器译文在原文变化后视为过期（SourceHash 不一致），作者修改过的译文 (Manual) 不会被自动更新覆盖
+type ShareTranslation struct {
+	ShareID    string    `gorm:"primaryKey;size:64" json:"shareId"`
+	Lang       string    `gorm:"primaryKey;size:16" json:"lang"` // 小写的 BCP 47 语言标签，如 en、zh-tw
+	DocTitle   string    `gorm:"size:255" json:"docTitle"`
+	Content    string    `gorm:"type:text;serializer:zstd" json:"content"`
+	SourceHash string    `gorm:"size:64" json:"-"` // 生成或修改时原文标题与正文的哈希，见 TranslationSourceHash
+	Status     string    `gorm:"size:16" json:"status"`
+	Error      string    `gorm:"size:500" json:"error,omitempty"`
+	Manual     bool      `gorm:"default:false" json:"manual"` // 作者修改过译文
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// TableName 指定表名
+func (ShareTranslation) TableName() string {
+	return "share_translations"
+}
+
+// TranslationSourceHash 原文标题与正文的哈希，用于判断译文是否过期
+func TranslationSourceHash(share *Share) string {
+	sum := sha256.Sum256([]byte(share.DocTitle + "\x00" + share.Content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Stale 原文在译文生成或修改后是否有变化
+func (t *ShareTranslation) Stale(share *Share) bool {
+	return t.SourceHash != TranslationSourceHash(share)
+}
+
+// ReadyTranslationLangs 分享可供读者访问的译文语言，按语言标签排序
+func ReadyTranslationLangs(shareID string) []string {
+	var langs []string
+	DB.Model(&ShareTranslation{}).Where("share_id = ? AND status = ?", shareID, TranslationReady).Order("lang").Pluck("lang", &langs)
+	return langs
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TrashedShares 用户回收站中的分享（已删除、尚未彻底删除），按删除时间倒序，不含正文
+func TrashedShares(userID string) ([]Share, error) {
+	var shares []Share
+	err := DB.Unscoped().Scopes(WithoutContent).Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").Find(&shares).Error
+	return shares, err
+}
+
+// FindTrashedShare 用户回收站中的分享，不存在时返回 gorm.ErrRecordNotFound
+func FindTrashedShare(userID, id string) (*Share, error) {
+	var share Share
+	if err := DB.Unscoped().Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", id, userID).First(&share).Error; err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+// RestoreShare 将回收站中的分享恢复为正常分享，并重新加入站内搜索索引
+func RestoreShare(share *Share) error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&Share{}).Where("id = ?", share.ID).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		share.DeletedAt = gorm.DeletedAt{}
+		return SyncShareIndex(tx, share)
+	})
+}
+
+// PurgeShares 彻底删除回收站中的分享及其全部关联数据与存储对象，只删除已在回收站中的分享
+func PurgeShares(ctx context.Context, ids []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	var trashed []string
+	if err := DB.Unscoped().Model(&Share{}).Where("id IN ? AND deleted_at IS NOT NULL", ids).Pluck("id", &trashed).Error; err != nil {
+		return 0, err
+	}
+	if len(trashed) == 0 {
+		return 0, nil
+	}
+	keys, err := shareStorageKeys(trashed)
+	if err != nil {
+		return 0, err
+	}
+	if err := DB.Transaction(func(tx *gorm.DB) error { return purgeShareRows(tx, trashed) }); err != nil {
+		return 0, err
+	}
+	deleteStorageKeys(ctx, "share purge", keys)
+	return int64(len(trashed)), nil
+}
+
+// TrashedShareIDs 删除时间早于 before 的分享 ID，userID 非空时只查询该用户的分享，最多返回 limit 个
+func TrashedShareIDs(userID string, before time.Time, limit int) ([]string, error) {
+	var ids []string
+	q := DB.Unscoped().Model(&Share{}).Where("deleted_at IS NOT NULL AND deleted_at < ?", before)
+	if userID != "" {
+		q = q.Where("user_id = ?", userID)
+	}
+	err := q.Order("deleted_at").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
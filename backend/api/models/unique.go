@@ -0,0 +1,61 @@
+package models
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxUniqueAttempts 写入随机 ID 或地址时冲突后的最多尝试次数
+const maxUniqueAttempts = 8
+
+// ErrTaken ID 或地址已被其他记录占用：用户指定的地址被占用，或多次重新生成后仍然冲突
+var ErrTaken = errors.New("identifier already taken")
+
+// IsUniqueViolation 错误是否为主键或唯一索引冲突，如并发请求写入了相同的 ID 或地址
+func IsUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// IsUniqueViolationOn 错误是否为 columns（「表名.列名」，如 shares.id）中某一列上的唯一冲突；
+// 驱动未给出冲突的列时返回 false
+func IsUniqueViolationOn(err error, columns ...string) bool {
+	if !IsUniqueViolation(err) {
+		return false
+	}
+	_, detail, ok := strings.Cut(err.Error(), "UNIQUE constraint failed: ")
+	if !ok {
+		return false
+	}
+	detail, _, _ = strings.Cut(detail, " (")
+	return slices.Contains(columns, strings.TrimSpace(detail))
+}
+
+// CreateUnique 调用 create 写入新记录，依靠数据库的唯一索引判断 ID 或地址是否已被占用，而不是写入前先查询：
+// columns（如 shares.id）上冲突时调用 next 换一个候选值并短暂退避后重试，next 为 nil 或返回 false（如用户指定的地址）时直接返回 ErrTaken。
+// 其他错误（包括 create 在同一事务中写入的其他表的唯一冲突）原样返回
+func CreateUnique(create func() error, next func(attempt int) bool, columns ...string) error {
+	for attempt := 1; ; attempt++ {
+		err := create()
+		if !IsUniqueViolationOn(err, columns...) {
+			return err
+		}
+		if attempt >= maxUniqueAttempts || next == nil || !next(attempt) {
+			return ErrTaken
+		}
+		time.Sleep(uniqueBackoff(attempt))
+	}
+}
+
+// uniqueBackoff 第 attempt 次冲突后的等待时间：从 2ms 起指数增长并加入随机抖动，让并发的请求错开
+func uniqueBackoff(attempt int) time.Duration {
+	d := 2 * time.Millisecond << min(attempt-1, 6)
+	return d/2 + rand.N(d)
+}
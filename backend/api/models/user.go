@@ -8,15 +8,34 @@ import (
 
 // User 用户模型
 type User struct {
-	ID           string         `gorm:"primaryKey;size:64" json:"id"`
-	Username     string         `gorm:"size:100;uniqueIndex" json:"username"`
-	Email        string         `gorm:"size:255;uniqueIndex" json:"email"`
-	PasswordHash string         `gorm:"size:255" json:"-"` // 密码哈希
-	IsActive     bool           `gorm:"default:true" json:"isActive"`
-	CreatedAt    time.Time      `json:"createdAt"`
-	UpdatedAt    time.Time      `json:"updatedAt"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
-	Tokens       []UserToken    `json:"-"` // 关联的多 API Token
+	ID            string `gorm:"primaryKey;size:64" json:"id"`
+	Username      string `gorm:"size:100;uniqueIndex" json:"username"`
+	Email         string `gorm:"size:255;uniqueIndex" json:"email"`
+	PasswordHash  string `gorm:"size:255" json:"-"` // 密码哈希
+	IsActive      bool   `gorm:"default:true" json:"isActive"`
+	EmailVerified bool   `gorm:"default:false" json:"emailVerified"` // 邮箱是否已验证
+	FeedEnabled   bool   `gorm:"default:false" json:"feedEnabled"`   // 是否公开 RSS 订阅源
+	Locale        string `gorm:"size:16" json:"locale"`              // 语言偏好（zh-CN / en），为空时按浏览器语言
+	DonationLinks string `gorm:"type:text" json:"-"`                 // 公开页面展示的赞助链接（JSON），见 DonationLinks
+	TOTPEnabled   bool   `gorm:"default:false" json:"totpEnabled"`   // 是否启用两步验证
+	TOTPSecret    string `gorm:"size:255" json:"-"`                  // 两步验证密钥（加密存储）
+	TOTPLastStep  int64  `json:"-"`                                  // 最近一次使用的验证码时间步，防止重放
+	RecoveryCodes string `gorm:"type:text" json:"-"`                 // 恢复码哈希（JSON 数组），使用后移除
+	// 防暴力破解：连续登录失败次数、连续锁定次数（再次锁定时时长加倍）与锁定截止时间；登录成功后全部清零，解锁只清除失败次数与锁定
+	FailedLogins int        `gorm:"default:0" json:"-"`
+	Lockouts     int        `gorm:"default:0" json:"-"`
+	LockedUntil  *time.Time `json:"-"`
+	// 用户申请注销后彻底删除账号的时间，为空表示未申请注销
+	DeletionScheduledAt *time.Time `json:"-"`
+	// 管理员设置的配额覆盖，为空时使用 quota 配置的默认值，0 表示不限制
+	QuotaMaxBytes      *int64         `json:"-"`
+	QuotaMaxShares     *int           `json:"-"`
+	QuotaMaxAssetBytes *int64         `json:"-"`
+	QuotaMaxBandwidth  *int64         `json:"-"`
+	CreatedAt          time.Time      `json:"createdAt"`
+	UpdatedAt          time.Time      `json:"updatedAt"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
+	Tokens             []UserToken    `json:"-"` // 关联的多 API Token
 }
 
 // TableName 指定表名
@@ -26,16 +45,33 @@ func (User) TableName() string {
 
 // UserToken 用户可管理的 API Token（多令牌支持）
 type UserToken struct {
-	ID         string         `gorm:"primaryKey;size:64" json:"id"`
-	UserID     string         `gorm:"index;size:64" json:"userId"`
-	Name       string         `gorm:"size:100" json:"name"`          // 令牌别名，便于区分用途
-	TokenHash  string         `gorm:"size:255;uniqueIndex" json:"-"` // 存储哈希，避免明文直接落库
-	PlainToken string         `gorm:"-" json:"token,omitempty"`      // 仅创建/刷新时返回，不入库
-	Revoked    bool           `gorm:"default:false" json:"revoked"`  // 是否已撤销
-	LastUsedAt *time.Time     `json:"lastUsedAt,omitempty"`
-	CreatedAt  time.Time      `json:"createdAt"`
-	UpdatedAt  time.Time      `json:"updatedAt"`
-	DeletedAt  gorm.DeletedAt `gorm:"index" json:"-"`
+	ID            string         `gorm:"primaryKey;size:64" json:"id"`
+	UserID        string         `gorm:"index;size:64" json:"userId"`
+	Name          string         `gorm:"size:100" json:"name"`          // 令牌别名，便于区分用途
+	TokenHash     string         `gorm:"size:255;uniqueIndex" json:"-"` // 存储哈希，避免明文直接落库
+	PlainToken    string         `gorm:"-" json:"token,omitempty"`      // 仅创建/刷新时返回，不入库
+	Revoked       bool           `gorm:"default:false" json:"revoked"`  // 是否已撤销
+	LastUsedAt    *time.Time     `json:"lastUsedAt,omitempty"`
+	RequestCount  int64          `gorm:"default:0" json:"requestCount"` // 使用统计由 RecordTokenUse 批量更新，便于撤销前辨认令牌所在的设备
+	LastUsedIP    string         `gorm:"column:last_used_ip;size:64" json:"lastUsedIp,omitempty"`
+	LastUserAgent string         `gorm:"size:255" json:"lastUserAgent,omitempty"`
+	AllowedCIDRs  string         `gorm:"column:allowed_cidrs;type:text" json:"-"` // 来源地址白名单（JSON 数组），为空表示不限制，见 AllowsIP
+	CreatedAt     time.Time      `json:"createdAt"`
+	UpdatedAt     time.Time      `json:"updatedAt"`
+	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func (UserToken) TableName() string { return "user_tokens" }
+
+// UserIdentity 第三方登录身份（OAuth2 / OIDC），同一提供方下 subject 唯一
+type UserIdentity struct {
+	ID        string    `gorm:"primaryKey;size:64" json:"id"`
+	UserID    string    `gorm:"index;size:64" json:"userId"`
+	Provider  string    `gorm:"size:50;uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject   string    `gorm:"size:255;uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email     string    `gorm:"size:255" json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (UserIdentity) TableName() string { return "user_identities" }
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ViewLimitGrace 浏览次数用尽后，最后一位读者继续加载图片、附件等资源的宽限时间
+const ViewLimitGrace = 10 * time.Minute
+
+// ViewsExhausted 分享的浏览次数是否已经用尽
+func (s *Share) ViewsExhausted() bool {
+	return s.MaxViews > 0 && s.ViewCount >= s.MaxViews
+}
+
+// InViewGrace 分享因浏览次数用尽而停用，且仍在宽限时间内
+func (s *Share) InViewGrace() bool {
+	return s.Status == ShareStatusDisabled && s.ViewsExhaustedAt != nil && time.Since(*s.ViewsExhaustedAt) < ViewLimitGrace
+}
+
+// ClaimView 记录一次浏览，成功时返回 true 并将最新的浏览次数写回 share。设置了浏览次数上限时，
+// 计数与上限判断在同一条 UPDATE 中完成，并发读者不会超出上限；最后一次浏览同时停用分享及其引用块子分享
+func ClaimView(share *Share) (bool, error) {
+	returning := clause.Returning{Columns: []clause.Column{{Name: "view_count"}, {Name: "status"}, {Name: "views_exhausted_at"}}}
+	if share.MaxViews <= 0 {
+		// 分享可能取自缓存，其中的浏览次数不一定是最新值：以 SQL 自增计数并取回结果；
+		// 未更新时说明缓存中的分享尚未反映新设置的上限，按上限处理
+		res := DB.Model(share).Where("max_views = 0").Clauses(returning).
+			UpdateColumn("view_count", gorm.Expr("view_count + ?", 1))
+		if res.Error != nil || res.RowsAffected > 0 {
+			return res.Error == nil, res.Error
+		}
+	}
+
+	res := DB.Model(share).Where("max_views > 0 AND view_count < max_views").Clauses(returning).
+		UpdateColumns(map[string]interface{}{
+			"view_count":         gorm.Expr("view_count + 1"),
+			"status":             gorm.Expr("CASE WHEN view_count + 1 >= max_views THEN ? ELSE status END", ShareStatusDisabled),
+			"views_exhausted_at": gorm.Expr("CASE WHEN view_count + 1 >= max_views THEN ? ELSE views_exhausted_at END", time.Now()),
+		})
+	if res.Error != nil || res.RowsAffected == 0 {
+		return false, res.Error
+	}
+	if share.Status == ShareStatusDisabled {
+		if err := DB.Model(&Share{}).Where("parent_share_id = ? AND user_id = ?", share.ID, share.UserID).
+			Update("status", ShareStatusDisabled).Error; err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
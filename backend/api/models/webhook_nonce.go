@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// WebhookNonce 入站回调已使用过的 nonce，有效期内再次出现即为重放（见 middleware.VerifyWebhook）。
+// 记录在数据库中，多实例部署时同样只接受一次
+type WebhookNonce struct {
+	ID        string    `gorm:"primaryKey;size:191"` // <来源>:<nonce>
+	ExpiresAt time.Time `gorm:"index;not null"`
+}
+
+// TableName 指定表名
+func (WebhookNonce) TableName() string {
+	return "webhook_nonces"
+}
+
+// ClaimWebhookNonce 记录来源 source 的 nonce，保留 ttl；已记录过（重放）时返回 false。顺带清理过期的记录
+func ClaimWebhookNonce(source, nonce string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	if err := DB.Where("expires_at < ?", now).Delete(&WebhookNonce{}).Error; err != nil {
+		return false, err
+	}
+	err := DB.Create(&WebhookNonce{ID: source + ":" + nonce, ExpiresAt: now.Add(ttl)}).Error
+	if IsUniqueViolation(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReleaseWebhookNonce 删除 nonce 记录：回调处理失败时调用，发送方可用同一 nonce 重试
+func ReleaseWebhookNonce(source, nonce string) error {
+	return DB.Where("id = ?", source+":"+nonce).Delete(&WebhookNonce{}).Error
+}
@@ -0,0 +1,25 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+func init() {
+	Register("email", sendEmail)
+}
+
+func sendEmail(_ context.Context, sub *models.Subscription, n *Notification) error {
+	return mailer.Send(mailer.Message{
+		To:      sub.Target,
+		Subject: i18n.T(sub.Locale, "email.share_updated.subject", n.Title),
+		Body:    i18n.T(sub.Locale, "email.share_updated.body", n.Title, n.URL, n.UnsubscribeURL),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + n.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+}
@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// Notification 分享更新通知
+type Notification struct {
+	ShareID        string
+	Title          string
+	URL            string // 分享访问地址
+	UnsubscribeURL string // 该订阅的退订地址
+}
+
+// Sender 某一渠道的投递实现
+type Sender func(ctx context.Context, sub *models.Subscription, n *Notification) error
+
+var senders = map[string]Sender{}
+
+// Register 注册渠道投递实现，需在 Start 之前调用
+func Register(channel string, s Sender) {
+	senders[channel] = s
+}
+
+type job struct {
+	shareID string
+	baseURL string
+}
+
+var queue = make(chan job, 256)
+
+// minInterval 同一订阅两次通知的最短间隔，避免自动重发布频繁打扰读者
+// (notify.subscription_interval，默认 1h)
+func minInterval() time.Duration {
+	return config.Get().Notify.SubscriptionInterval.Std()
+}
+
+// Start 启动后台通知任务
+func Start() {
+	go func() {
+		for j := range queue {
+			deliver(j)
+			background.Done()
+		}
+	}()
+}
+
+// ShareUpdated 分享重新发布后异步通知订阅者，队列已满或服务正在退出时丢弃
+func ShareUpdated(shareID, baseURL string) {
+	if !background.Add() {
+		return
+	}
+	select {
+	case queue <- job{shareID: shareID, baseURL: baseURL}:
+	default:
+		background.Done()
+		log.Printf("notify queue full, dropped update of share %s", shareID)
+	}
+}
+
+func deliver(j job) {
+	var share models.Share
+	if err := models.DB.Scopes(models.WithoutContent).Where("id = ?", j.shareID).First(&share).Error; err != nil {
+		return
+	}
+	if !share.Reachable() || share.IsExpired() {
+		return
+	}
+
+	cutoff := time.Now().Add(-minInterval())
+	var subs []models.Subscription
+	if err := models.DB.Where("share_id = ? AND confirmed = ? AND (last_notified_at IS NULL OR last_notified_at < ?)",
+		share.ID, true, cutoff).Find(&subs).Error; err != nil {
+		log.Printf("notify: failed to load subscriptions of %s: %v", share.ID, err)
+		return
+	}
+
+	for i := range subs {
+		sub := &subs[i]
+		send, ok := senders[sub.Channel]
+		if !ok {
+			continue
+		}
+		n := &Notification{
+			ShareID:        share.ID,
+			Title:          share.DocTitle,
+			URL:            j.baseURL + "/s/" + share.ID,
+			UnsubscribeURL: UnsubscribeURL(j.baseURL, sub.Token),
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		err := send(ctx, sub, n)
+		cancel()
+		if err != nil {
+			log.Printf("notify: %s delivery to subscription %s failed: %v", sub.Channel, sub.ID, err)
+			continue
+		}
+		now := time.Now()
+		models.DB.Model(sub).Update("last_notified_at", &now)
+	}
+}
+
+// UnsubscribeURL 退订地址
+func UnsubscribeURL(baseURL, token string) string {
+	return baseURL + "/api/subscriptions/unsubscribe?token=" + token
+}
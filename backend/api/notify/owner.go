@@ -0,0 +1,172 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// ownerRecipient 接收通知邮件的分享者：已启用且邮箱已验证；未配置 SMTP 时返回 nil
+func ownerRecipient(userID string) *models.User {
+	if !mailer.Enabled() {
+		return nil
+	}
+	var u models.User
+	if err := models.DB.Select("id", "email", "email_verified", "locale").
+		Where("id = ? AND is_active = ?", userID, true).First(&u).Error; err != nil {
+		return nil
+	}
+	if u.Email == "" || !u.EmailVerified {
+		return nil
+	}
+	return &u
+}
+
+// ownerLocale 分享者的语言偏好，未设置时为默认语言
+func ownerLocale(u *models.User) string {
+	if locale := i18n.Match(u.Locale); locale != "" {
+		return locale
+	}
+	return i18n.Default()
+}
+
+// sendOwnerEmail 按分享者的语言发送通知邮件，正文末尾附上通知设置的入口
+func sendOwnerEmail(u *models.User, baseURL, subject, body string) error {
+	locale := ownerLocale(u)
+	return mailer.Send(mailer.Message{
+		To:      u.Email,
+		Subject: subject,
+		Body:    body + i18n.T(locale, "email.owner.footer", baseURL+"/dashboard"),
+	})
+}
+
+// OwnerShareViewed 分享计入一次浏览后，按分享者的偏好异步发送首次浏览或浏览次数里程碑通知；
+// share.ViewCount 须为计入本次浏览后的次数
+func OwnerShareViewed(share *models.Share, baseURL string) {
+	count := share.ViewCount
+	if count <= 0 || !mailer.Enabled() {
+		return
+	}
+	shareID, userID, title := share.ID, share.UserID, share.DocTitle
+	background.Go(func() {
+		settings, err := models.FindNotificationSettings(userID)
+		if err != nil || settings == nil {
+			return
+		}
+		first := settings.FirstView && count == 1
+		milestone := settings.ViewsEvery > 0 && count%settings.ViewsEvery == 0
+		if !first && !milestone {
+			return
+		}
+		u := ownerRecipient(userID)
+		if u == nil {
+			return
+		}
+		locale := ownerLocale(u)
+		url := baseURL + "/s/" + shareID
+		var subject, body string
+		if first {
+			subject = i18n.T(locale, "email.owner_first_view.subject", title)
+			body = i18n.T(locale, "email.owner_first_view.body", title, url)
+		} else {
+			subject = i18n.T(locale, "email.owner_views.subject", title, count)
+			body = i18n.T(locale, "email.owner_views.body", title, count, url)
+		}
+		if err := sendOwnerEmail(u, baseURL, subject, body); err != nil {
+			log.Printf("notify: view notice of share %s failed: %v", shareID, err)
+		}
+	})
+}
+
+// OwnerCommentReceived 分享收到评论后，按分享者的偏好异步发送邮件通知
+func OwnerCommentReceived(share *models.Share, author, summary string, pending bool, baseURL string) {
+	if !mailer.Enabled() {
+		return
+	}
+	shareID, userID, title := share.ID, share.UserID, share.DocTitle
+	background.Go(func() {
+		settings, err := models.FindNotificationSettings(userID)
+		if err != nil || settings == nil || !settings.Comments {
+			return
+		}
+		u := ownerRecipient(userID)
+		if u == nil {
+			return
+		}
+		locale := ownerLocale(u)
+		status := ""
+		if pending {
+			status = i18n.T(locale, "email.owner_comment.pending")
+		}
+		subject := i18n.T(locale, "email.owner_comment.subject", title)
+		body := i18n.T(locale, "email.owner_comment.body", author, title, status, summary, baseURL+"/s/"+shareID)
+		if err := sendOwnerEmail(u, baseURL, subject, body); err != nil {
+			log.Printf("notify: comment notice of share %s failed: %v", shareID, err)
+		}
+	})
+}
+
+// ShareExpiryReminders 向开启了到期提醒的分享者汇总发送即将到期的分享：每位分享者一封邮件，
+// 同一分享在同一有效期内只提醒一次（延长有效期后会再次提醒）；返回发送的邮件数
+func ShareExpiryReminders(ctx context.Context) (int, error) {
+	if !mailer.Enabled() {
+		return 0, nil
+	}
+	var settings []models.NotificationSettings
+	if err := models.DB.Where("expiring_days > 0").Find(&settings).Error; err != nil {
+		return 0, err
+	}
+	sent := 0
+	now := time.Now()
+	for i := range settings {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+		s := &settings[i]
+		window := time.Duration(s.ExpiringDays) * 24 * time.Hour
+		var shares []models.Share
+		if err := models.DB.Scopes(models.WithoutContent).
+			Where("user_id = ? AND parent_share_id = ? AND status IN ? AND expire_at > ? AND expire_at <= ?",
+				s.UserID, "", []string{models.ShareStatusPublished, models.ShareStatusUnlisted}, now, now.Add(window)).
+			Order("expire_at").Find(&shares).Error; err != nil {
+			return sent, err
+		}
+		// 上次提醒早于本次有效期的提醒起点时，说明有效期已延长，需要再次提醒
+		due := shares[:0]
+		for _, share := range shares {
+			if share.ExpiryNotifiedAt == nil || share.ExpiryNotifiedAt.Before(share.ExpireAt.Add(-window)) {
+				due = append(due, share)
+			}
+		}
+		if len(due) == 0 {
+			continue
+		}
+		u := ownerRecipient(s.UserID)
+		if u == nil {
+			continue
+		}
+		locale := ownerLocale(u)
+		var list strings.Builder
+		ids := make([]string, len(due))
+		for j, share := range due {
+			ids[j] = share.ID
+			fmt.Fprintf(&list, "- %s (%s)\n  %s/s/%s\n", share.DocTitle, share.ExpireAt.Local().Format("2006-01-02 15:04"), s.BaseURL, share.ID)
+		}
+		subject := i18n.T(locale, "email.owner_expiring.subject", len(due))
+		body := i18n.T(locale, "email.owner_expiring.body", s.ExpiringDays, list.String(), s.BaseURL+"/shares")
+		if err := sendOwnerEmail(u, s.BaseURL, subject, body); err != nil {
+			log.Printf("notify: expiry reminder to %s failed: %v", s.UserID, err)
+			continue
+		}
+		models.DB.Model(&models.Share{}).Where("id IN ?", ids).UpdateColumn("expiry_notified_at", now)
+		sent++
+	}
+	return sent, nil
+}
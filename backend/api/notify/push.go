@@ -0,0 +1,75 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/webpush"
+)
+
+// pushTTL 推送服务暂存离线消息的时长（秒）
+const pushTTL = 24 * 60 * 60
+
+// PushMessage 推送到浏览器的消息，由前端 Service Worker 展示
+type PushMessage struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"`
+}
+
+func init() {
+	Register("push", sendSharePush)
+}
+
+func sendSharePush(ctx context.Context, sub *models.Subscription, n *Notification) error {
+	err := sendPush(ctx, &webpush.Subscription{Endpoint: sub.Target, P256dh: sub.PushP256dh, Auth: sub.PushAuth}, PushMessage{
+		Title: n.Title,
+		Body:  "你订阅的笔记有新的内容更新",
+		URL:   n.URL,
+	})
+	if errors.Is(err, webpush.ErrGone) {
+		// 浏览器已取消订阅
+		models.DB.Delete(sub)
+	}
+	return err
+}
+
+func sendPush(ctx context.Context, sub *webpush.Subscription, msg PushMessage) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return webpush.Send(ctx, sub, payload, pushTTL)
+}
+
+// UserPush 向用户在各浏览器注册的推送订阅发送通知（异步），失效的订阅会被清理
+func UserPush(userID string, msg PushMessage) {
+	background.Go(func() {
+		var subs []models.PushSubscription
+		if err := models.DB.Where("user_id = ?", userID).Find(&subs).Error; err != nil || len(subs) == 0 {
+			return
+		}
+		for i := range subs {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			err := SendUserPush(ctx, &subs[i], msg)
+			cancel()
+			if err != nil && !errors.Is(err, webpush.ErrGone) {
+				log.Printf("notify: push to %s failed: %v", subs[i].ID, err)
+			}
+		}
+	})
+}
+
+// SendUserPush 向单个浏览器订阅同步发送通知
+func SendUserPush(ctx context.Context, sub *models.PushSubscription, msg PushMessage) error {
+	err := sendPush(ctx, &webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, msg)
+	if errors.Is(err, webpush.ErrGone) {
+		models.DB.Delete(sub)
+	}
+	return err
+}
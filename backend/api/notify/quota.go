@@ -0,0 +1,148 @@
+package notify
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/i18n"
+	"github.com/ZeroHawkeye/siyuan-share-api/mailer"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+)
+
+// quotaCheckDelay 用量变化后延迟检查配额的时间，期间的多次发布、上传与读者访问合并为一次检查
+const quotaCheckDelay = 30 * time.Second
+
+// quotaChecks 等待检查配额的用户及触发检查的请求的站点地址
+var quotaChecks = struct {
+	sync.Mutex
+	pending map[string]string
+}{pending: map[string]string{}}
+
+// CheckQuota 用量可能变化后（发布、上传资源、读者流量）延迟检查用户的配额：用量达到新的提醒级别（80%、95%、超出配额）时
+// 发送邮件（已配置 SMTP 且邮箱已验证）与浏览器推送；同一级别只提醒一次，用量回落后再次上升时重新提醒
+func CheckQuota(userID, baseURL string) {
+	quotaChecks.Lock()
+	_, scheduled := quotaChecks.pending[userID]
+	quotaChecks.pending[userID] = baseURL
+	quotaChecks.Unlock()
+	if scheduled {
+		return
+	}
+	time.AfterFunc(quotaCheckDelay, func() {
+		quotaChecks.Lock()
+		baseURL := quotaChecks.pending[userID]
+		delete(quotaChecks.pending, userID)
+		quotaChecks.Unlock()
+		background.Go(func() {
+			if err := checkQuota(userID, baseURL); err != nil {
+				log.Printf("notify: quota check for %s failed: %v", userID, err)
+			}
+		})
+	})
+}
+
+// checkQuota 比较各项配额当前的提醒级别与已提醒的级别，级别升高时提醒用户
+func checkQuota(userID, baseURL string) error {
+	var u models.User
+	if err := models.DB.Where("id = ? AND is_active = ?", userID, true).First(&u).Error; err != nil {
+		return nil
+	}
+	quota := u.EffectiveQuota()
+	if quota.MaxBytes <= 0 && quota.MaxShares <= 0 && quota.MaxBandwidth <= 0 {
+		return nil
+	}
+	usage, err := models.UserUsage(userID)
+	if err != nil {
+		return err
+	}
+	var sent []models.QuotaWarning
+	if err := models.DB.Where("user_id = ?", userID).Find(&sent).Error; err != nil {
+		return err
+	}
+	previous := map[string]models.QuotaWarning{}
+	for _, w := range sent {
+		previous[w.Resource] = w
+	}
+
+	month := models.CurrentMonth()
+	var raised []models.QuotaStatus
+	for _, s := range models.QuotaStatuses(usage, quota) {
+		period := ""
+		if s.Resource == "bandwidth" {
+			period = month
+		}
+		level, prev := s.WarnLevel(), 0
+		if w, ok := previous[s.Resource]; ok && w.Period == period {
+			prev = w.Level
+		}
+		if level == prev {
+			continue
+		}
+		if level > prev {
+			raised = append(raised, s)
+		}
+		w := models.QuotaWarning{UserID: userID, Resource: s.Resource, Level: level, Period: period}
+		if err := models.DB.Save(&w).Error; err != nil {
+			return err
+		}
+	}
+	if len(raised) == 0 {
+		return nil
+	}
+
+	locale := ownerLocale(&u)
+	lines := make([]string, len(raised))
+	for i, s := range raised {
+		lines[i] = quotaLine(locale, s)
+	}
+	summary := strings.Join(lines, "\n")
+	if r := ownerRecipient(userID); r != nil {
+		err := mailer.Send(mailer.Message{
+			To:      r.Email,
+			Subject: i18n.T(locale, "email.quota_warning.subject"),
+			Body:    i18n.T(locale, "email.quota_warning.body", summary, baseURL+"/dashboard"),
+		})
+		if err != nil {
+			log.Printf("notify: quota warning to %s failed: %v", userID, err)
+		}
+	}
+	UserPush(userID, PushMessage{Title: i18n.T(locale, "quota.title"), Body: summary, URL: baseURL + "/dashboard"})
+	return nil
+}
+
+// quotaLine 单项配额的提醒说明，如“存储空间：已用 96%（9.6 MB / 10 MB）”
+func quotaLine(locale string, s models.QuotaStatus) string {
+	name := i18n.T(locale, "quota."+s.Resource)
+	used, limit, hard := quotaAmount(s.Resource, s.Used), quotaAmount(s.Resource, s.Limit), quotaAmount(s.Resource, s.HardLimit)
+	switch s.State {
+	case models.QuotaStateBlocked:
+		if s.Resource == "bandwidth" {
+			return i18n.T(locale, "quota.blocked_bandwidth", name, used, limit)
+		}
+		return i18n.T(locale, "quota.blocked", name, used, limit)
+	case models.QuotaStateGrace:
+		return i18n.T(locale, "quota.grace", name, s.Percent, used, limit, hard)
+	}
+	return i18n.T(locale, "quota.warning", name, s.Percent, used, limit)
+}
+
+// quotaAmount 配额数值的显示形式：分享数为个数，存储与流量为字节数
+func quotaAmount(resource string, n int64) string {
+	if resource == "shares" {
+		return fmt.Sprint(n)
+	}
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	v, i := float64(n), 0
+	for v >= 1024 && i < len(units)-1 {
+		v /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f %s", v, units[i])
+}
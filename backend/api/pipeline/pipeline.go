@@ -0,0 +1,172 @@
+// Package pipeline 按固定顺序执行的正文处理步骤（渲染前钩子、块引用、资源地址、预渲染等），
+// 记录每个步骤的耗时，并可逐步跟踪一篇正文的变化，便于排查“笔记显示不对”一类的问题。
+package pipeline
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Last 用作 Run 的 to 参数，表示执行到最后一个步骤
+const Last = math.MaxInt
+
+// Stage 处理步骤。Order 决定执行顺序（小的先执行），内置步骤之间留有间隔，新增步骤可插在其间；
+// Run 返回错误时记录日志并沿用该步骤的输入
+type Stage[D any] struct {
+	Name  string
+	Order int
+	Run   func(d D, content string) (string, error)
+}
+
+// Pipeline 有序的处理步骤，D 为每次执行的上下文（如当前分享与请求）
+type Pipeline[D any] struct {
+	name   string
+	mu     sync.RWMutex
+	stages []Stage[D]
+	stats  map[string]*StageStats
+}
+
+// StageStats 步骤自启动以来的执行统计
+type StageStats struct {
+	Name      string     `json:"name"`
+	Order     int        `json:"order"`
+	Runs      int64      `json:"runs"`
+	Changed   int64      `json:"changed"` // 改动了正文的次数
+	Errors    int64      `json:"errors"`
+	TotalMs   float64    `json:"totalMs"`
+	AvgMs     float64    `json:"avgMs"`
+	MaxMs     float64    `json:"maxMs"`
+	LastError string     `json:"lastError,omitempty"`
+	LastRunAt *time.Time `json:"lastRunAt"`
+
+	total, longest time.Duration
+}
+
+// Step Trace 中单个步骤的结果
+type Step struct {
+	Stage      string  `json:"stage"`
+	Order      int     `json:"order"`
+	Skipped    bool    `json:"skipped"`
+	Changed    bool    `json:"changed"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+	InputLen   int     `json:"inputLength"`
+	OutputLen  int     `json:"outputLength"`
+	Output     *string `json:"output"` // 步骤改动了正文时为改动后的全文，否则为 null
+}
+
+// New 创建处理流程，name 用于日志
+func New[D any](name string) *Pipeline[D] {
+	return &Pipeline[D]{name: name, stats: map[string]*StageStats{}}
+}
+
+// Register 添加步骤，同名步骤重复注册时 panic；Order 相同的步骤按注册先后执行
+func (p *Pipeline[D]) Register(s Stage[D]) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.stats[s.Name]; ok {
+		panic(fmt.Sprintf("pipeline %s: duplicate stage %q", p.name, s.Name))
+	}
+	// 复制后再排序，执行中的 Run 仍使用原来的切片
+	stages := append(slices.Clone(p.stages), s)
+	sort.SliceStable(stages, func(i, j int) bool { return stages[i].Order < stages[j].Order })
+	p.stages = stages
+	p.stats[s.Name] = &StageStats{Name: s.Name, Order: s.Order}
+}
+
+// Run 依次执行 Order 在 [from, to) 内的步骤，返回处理后的正文
+func (p *Pipeline[D]) Run(d D, content string, from, to int) string {
+	for _, s := range p.list() {
+		if s.Order < from || s.Order >= to {
+			continue
+		}
+		out, _, err := p.exec(s, d, content)
+		if err != nil {
+			log.Printf("Content pipeline %s: stage %s failed: %v", p.name, s.Name, err)
+			continue
+		}
+		content = out
+	}
+	return content
+}
+
+// Trace 依次执行全部步骤并记录每一步的结果，skip 中的步骤不执行（用于对比关闭某一步骤后的效果）
+func (p *Pipeline[D]) Trace(d D, content string, skip map[string]bool) []Step {
+	stages := p.list()
+	steps := make([]Step, 0, len(stages))
+	for _, s := range stages {
+		step := Step{Stage: s.Name, Order: s.Order, InputLen: len(content), OutputLen: len(content)}
+		if skip[s.Name] {
+			step.Skipped = true
+			steps = append(steps, step)
+			continue
+		}
+		out, elapsed, err := p.exec(s, d, content)
+		step.DurationMs = ms(elapsed)
+		if err != nil {
+			step.Error = err.Error()
+		} else if out != content {
+			step.Changed = true
+			step.OutputLen = len(out)
+			step.Output = &out
+			content = out
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Stats 按执行顺序返回各步骤的执行统计
+func (p *Pipeline[D]) Stats() []StageStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	list := make([]StageStats, 0, len(p.stages))
+	for _, s := range p.stages {
+		st := *p.stats[s.Name]
+		st.TotalMs, st.MaxMs = ms(st.total), ms(st.longest)
+		if st.Runs > 0 {
+			st.AvgMs = st.TotalMs / float64(st.Runs)
+		}
+		list = append(list, st)
+	}
+	return list
+}
+
+func (p *Pipeline[D]) list() []Stage[D] {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.stages
+}
+
+// exec 执行单个步骤并计入统计
+func (p *Pipeline[D]) exec(s Stage[D], d D, content string) (string, time.Duration, error) {
+	start := time.Now()
+	out, err := s.Run(d, content)
+	elapsed := time.Since(start)
+
+	p.mu.Lock()
+	st := p.stats[s.Name]
+	st.Runs++
+	st.total += elapsed
+	if elapsed > st.longest {
+		st.longest = elapsed
+	}
+	if err != nil {
+		st.Errors++
+		st.LastError = err.Error()
+	} else if out != content {
+		st.Changed++
+	}
+	st.LastRunAt = &start
+	p.mu.Unlock()
+	return out, elapsed, err
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,91 @@
+//go:build ignore
+
+// precompress 为前端构建产物生成 .br 与 .gz 预压缩文件，随 dist 一起嵌入可执行文件，
+// 静态文件按读者的 Accept-Encoding 直接返回，无需每次请求时压缩。用法：go run precompress.go dist
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// 小于该大小的文件不预压缩
+const minBytes = 1024
+
+// 预压缩的文件类型；图片、字体等本身已压缩
+var extensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".html": true, ".svg": true, ".json": true,
+	".txt": true, ".xml": true, ".map": true, ".wasm": true, ".webmanifest": true,
+}
+
+func main() {
+	dir := "dist"
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+	var files, written int
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !extensions[strings.ToLower(filepath.Ext(path))] {
+			return err
+		}
+		// index.html 每次请求时注入分享的主题与元信息，由压缩中间件压缩
+		if rel, _ := filepath.Rel(dir, path); filepath.ToSlash(rel) == "index.html" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || len(data) < minBytes {
+			return err
+		}
+		files++
+		for _, variant := range []struct {
+			suffix   string
+			compress func([]byte) ([]byte, error)
+		}{{".br", compressBrotli}, {".gz", compressGzip}} {
+			out, err := variant.compress(data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			// 压缩收益不足一成时不生成，读者直接获取原文件
+			if len(out) > len(data)*9/10 {
+				continue
+			}
+			if err := os.WriteFile(path+variant.suffix, out, 0o644); err != nil {
+				return err
+			}
+			written++
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("precompress: %v", err)
+	}
+	log.Printf("precompress: %d files, %d compressed variants written", files, written)
+}
+
+func compressBrotli(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := brotli.NewWriterLevel(&buf, brotli.BestCompression)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	err := w.Close()
+	return buf.Bytes(), err
+}
+
+func compressGzip(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	err := w.Close()
+	return buf.Bytes(), err
+}
@@ -0,0 +1,195 @@
+package qa
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/tts"
+)
+
+// chunkRunes 单个片段的长度上限（字符），过长的章节按段落与句子切分
+const chunkRunes = 1000
+
+var (
+	headingPattern     = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fenceLinePattern   = regexp.MustCompile("^ {0,3}(```|~~~)")
+	blockIDPattern     = regexp.MustCompile(`\{:[^}]*\bid="([0-9]{14}-[0-9a-z]{7})"[^}]*\}`)
+	headingIALPattern  = regexp.MustCompile(`\{:[^}]*\}`)
+	headingLinkPattern = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	headingMarkPattern = regexp.MustCompile("[*`~]|==|__")
+)
+
+// chunk 正文中可作为回答依据的片段，锚定到所在章节的标题
+type chunk struct {
+	doc     int    // 所属文档在输入中的序号
+	order   int    // 在文档中的先后顺序
+	Heading string // 所在章节的标题，正文开头（第一个标题之前）为空
+	Anchor  string // 标题在阅读页中的锚点 (rehype-slug)
+	BlockID string // 标题块在思源中的 ID（正文带块属性时）
+	Text    string
+	tokens  map[string]bool
+}
+
+// split 按标题将 Markdown 正文切分为片段，代码块中的 # 不视为标题
+func split(doc int, content string) []*chunk {
+	var chunks []*chunk
+	slugs := slugger{}
+	heading, anchor, blockID := "", "", ""
+	var body []string
+	inFence := false
+	flush := func() {
+		for _, text := range tts.Split(tts.Text(strings.Join(body, "\n")), chunkRunes) {
+			if text = strings.TrimSpace(text); text == "" {
+				continue
+			}
+			c := &chunk{doc: doc, order: len(chunks), Heading: heading, Anchor: anchor, BlockID: blockID, Text: text}
+			c.tokens = tokenSet(heading + "\n" + text)
+			chunks = append(chunks, c)
+		}
+		body = body[:0]
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if fenceLinePattern.MatchString(line) {
+			inFence = !inFence
+		}
+		m := headingPattern.FindStringSubmatch(line)
+		if inFence || m == nil {
+			body = append(body, line)
+			continue
+		}
+		flush()
+		heading = headingText(m[2])
+		anchor = slugs.slug(heading)
+		blockID = ""
+		// 思源导出的块属性位于标题行末或下一行
+		if id := blockIDPattern.FindStringSubmatch(line); id != nil {
+			blockID = id[1]
+		} else if i+1 < len(lines) {
+			if id := blockIDPattern.FindStringSubmatch(lines[i+1]); id != nil {
+				blockID = id[1]
+			}
+		}
+	}
+	flush()
+	return chunks
+}
+
+// headingText 标题在阅读页中显示的文字：去掉块属性、链接地址与强调标记
+func headingText(s string) string {
+	s = headingIALPattern.ReplaceAllString(s, "")
+	s = headingLinkPattern.ReplaceAllString(s, "$1")
+	s = headingMarkPattern.ReplaceAllString(s, "")
+	return strings.TrimSpace(s)
+}
+
+// slugger 与阅读页的 rehype-slug (github-slugger) 一致地生成标题锚点，重复的标题依次追加 -1、-2
+type slugger map[string]int
+
+func (s slugger) slug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			b.WriteByte('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r):
+			b.WriteRune(r)
+		}
+	}
+	base := b.String()
+	result := base
+	for {
+		if _, ok := s[result]; !ok {
+			break
+		}
+		s[base]++
+		result = base + "-" + strconv.Itoa(s[base])
+	}
+	s[result] = 0
+	return result
+}
+
+// tokenSet 检索用的词项：拉丁字母与数字按单词，中日韩文字按相邻两字
+func tokenSet(text string) map[string]bool {
+	set := map[string]bool{}
+	var word []rune
+	var cjk []rune
+	flushWord := func() {
+		if len(word) > 1 || (len(word) == 1 && unicode.IsDigit(word[0])) {
+			set[string(word)] = true
+		}
+		word = word[:0]
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			set[string(cjk)] = true
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			set[string(cjk[i:i+2])] = true
+		}
+		cjk = cjk[:0]
+	}
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return set
+}
+
+// pick 按与问题的相关度（词项的逆文档频率之和）选取片段，总长度不超过 budget 字符（<= 0 不限制）；
+// 相关度相同时按文档顺序，问题与正文没有共同词项时即取正文开头，返回结果按文档顺序排列
+func pick(chunks []*chunk, question string, budget int) []*chunk {
+	query := tokenSet(question)
+	df := map[string]int{}
+	for _, c := range chunks {
+		for t := range query {
+			if c.tokens[t] {
+				df[t]++
+			}
+		}
+	}
+	scores := make(map[*chunk]float64, len(chunks))
+	for _, c := range chunks {
+		for t := range query {
+			if c.tokens[t] {
+				scores[c] += math.Log(1 + float64(len(chunks))/float64(df[t]))
+			}
+		}
+	}
+	ranked := append([]*chunk(nil), chunks...)
+	sort.SliceStable(ranked, func(i, j int) bool { return scores[ranked[i]] > scores[ranked[j]] })
+
+	var picked []*chunk
+	used := 0
+	for _, c := range ranked {
+		n := utf8.RuneCountInString(c.Text)
+		if budget > 0 && used+n > budget {
+			continue
+		}
+		picked = append(picked, c)
+		used += n
+	}
+	sort.SliceStable(picked, func(i, j int) bool {
+		if picked[i].doc != picked[j].doc {
+			return picked[i].doc < picked[j].doc
+		}
+		return picked[i].order < picked[j].order
+	})
+	return picked
+}
@@ -0,0 +1,102 @@
+// Package reload 在不重启进程的情况下重新加载配置：重新读取配置文件与环境变量，替换生效的配置，
+// 并刷新按配置初始化的组件（日志级别、IP 数据库、地区拦截页、钩子、渲染插件）与数据库中的重定向规则、自定义域名。
+// 监听地址、数据库、存储等只在启动时读取的设置沿用当前值，修改后需重启才能生效
+package reload
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/geoip"
+	"github.com/ZeroHawkeye/siyuan-share-api/hooks"
+	"github.com/ZeroHawkeye/siyuan-share-api/logging"
+	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+)
+
+var mu sync.Mutex
+
+// Result 重新加载的结果
+type Result struct {
+	Source          string   `json:"source"`          // 配置文件路径，只使用环境变量时为空
+	RestartRequired []string `json:"restartRequired"` // 已修改但需重启才能生效的配置项，当前仍使用原值
+	Warnings        []string `json:"warnings"`
+}
+
+// Run 重新加载配置。配置无效或组件初始化失败时恢复原配置并返回错误，服务继续按原配置运行
+func Run() (*Result, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	running := config.Get()
+	cfg, warnings, err := config.Load(running.Source())
+	if err != nil {
+		return nil, err
+	}
+	restart := cfg.KeepStartupSettings(running)
+	config.Set(cfg)
+	if err := apply(); err != nil {
+		config.Set(running)
+		if err := apply(); err != nil {
+			log.Printf("Failed to restore components after config reload failure: %v", err)
+		}
+		return nil, err
+	}
+
+	log.Printf("Configuration reloaded (source %q)", cfg.Source())
+	for _, w := range warnings {
+		log.Printf("Config warning: %s", w)
+	}
+	for _, name := range restart {
+		log.Printf("Config %s changed; restart the server to apply it", name)
+	}
+	if restart == nil {
+		restart = []string{}
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return &Result{Source: cfg.Source(), RestartRequired: restart, Warnings: warnings}, nil
+}
+
+// RefreshSecrets 重新读取配置，file: / vault: 引用或 _FILE 文件中的密钥有变化时重新加载配置（见 Run），
+// 返回是否已重新加载；由 secrets.refresh_interval 定时调用
+func RefreshSecrets() (bool, error) {
+	running := config.Get()
+	cfg, _, err := config.Load(running.Source())
+	if err != nil {
+		return false, err
+	}
+	if cfg.SecretsDigest() == running.SecretsDigest() {
+		return false, nil
+	}
+	log.Printf("Secrets changed, reloading configuration")
+	if _, err := Run(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// apply 按当前配置刷新组件
+func apply() error {
+	logging.ApplyLevel()
+	middleware.ReloadBlockPage()
+	if err := geoip.Init(); err != nil {
+		return fmt.Errorf("load geo database: %w", err)
+	}
+	if err := hooks.Init(); err != nil {
+		return fmt.Errorf("initialize hooks: %w", err)
+	}
+	if err := renderer.Init(); err != nil {
+		return fmt.Errorf("initialize renderers: %w", err)
+	}
+	if err := middleware.ReloadRedirects(); err != nil {
+		return fmt.Errorf("reload redirects: %w", err)
+	}
+	if err := middleware.ReloadDomains(); err != nil {
+		return fmt.Errorf("reload domains: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package renderer
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+const (
+	// cacheSize 缓存的渲染结果数量上限
+	cacheSize = 256
+	// errorTTL 渲染失败的结果缓存时长，过后重新尝试
+	errorTTL = time.Minute
+)
+
+// Entry 缓存的渲染结果
+type Entry struct {
+	Hash        string
+	ContentType string
+	Data        []byte
+	Err         error
+	key         string
+	at          time.Time
+	done        chan struct{}
+}
+
+var cache = struct {
+	sync.Mutex
+	items map[string]*list.Element
+	order *list.List
+}{items: map[string]*list.Element{}, order: list.New()}
+
+// Load 返回代码块的渲染结果：命中缓存时直接返回，同一内容的并发请求只渲染一次（最近最少使用淘汰）
+func Load(p *Plugin, source string) *Entry {
+	hash := Hash(source)
+	key := p.Lang + "\x00" + hash
+	cache.Lock()
+	if el, ok := cache.items[key]; ok {
+		e := el.Value.(*Entry)
+		select {
+		case <-e.done:
+			if e.Err == nil || time.Since(e.at) < errorTTL {
+				cache.order.MoveToFront(el)
+				cache.Unlock()
+				return e
+			}
+			// 失败结果过期，移除后重新渲染
+			cache.order.Remove(el)
+			delete(cache.items, key)
+		default:
+			cache.Unlock()
+			<-e.done
+			return e
+		}
+	}
+	e := &Entry{Hash: hash, ContentType: p.ContentType, key: key, done: make(chan struct{})}
+	cache.items[key] = cache.order.PushFront(e)
+	for cache.order.Len() > cacheSize {
+		oldest := cache.order.Back()
+		cache.order.Remove(oldest)
+		delete(cache.items, oldest.Value.(*Entry).key)
+	}
+	cache.Unlock()
+
+	e.Data, e.Err = p.Render(source)
+	e.at = time.Now()
+	close(e.done)
+	return e
+}
+
+// clearCache 清空缓存的渲染结果，进行中的渲染照常完成
+func clearCache() {
+	cache.Lock()
+	defer cache.Unlock()
+	cache.items = map[string]*list.Element{}
+	cache.order.Init()
+}
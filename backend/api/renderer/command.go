@@ -0,0 +1,60 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Command 命令行渲染器：代码块内容写入标准输入，标准输出即渲染结果；退出码非 0 视为失败
+type Command struct {
+	Args     []string // 可执行文件及参数，不经过 shell
+	MaxBytes int64    // 标准输出大小上限，0 表示不限制
+}
+
+// Render 运行外部命令渲染代码块
+func (r *Command) Render(ctx context.Context, source []byte) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, r.Args[0], r.Args[1:]...)
+	cmd.Stdin = bytes.NewReader(source)
+	stdout := &limitedBuffer{limit: r.MaxBytes}
+	stderr := &limitedBuffer{limit: 2048}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	// 超时后给子进程留出退出时间，避免遗留的管道阻塞
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if stdout.overflow {
+			return nil, ErrTooLarge
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	if stdout.overflow {
+		return nil, ErrTooLarge
+	}
+	return stdout.Bytes(), nil
+}
+
+// limitedBuffer 超过上限后丢弃后续输出并记录溢出（仍返回写入成功，避免子进程因管道关闭报错）
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int64
+	overflow bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.limit > 0 {
+		if room := b.limit - int64(b.Len()); int64(len(p)) > room {
+			b.overflow = true
+			if room > 0 {
+				b.Buffer.Write(p[:room])
+			}
+			return len(p), nil
+		}
+	}
+	return b.Buffer.Write(p)
+}
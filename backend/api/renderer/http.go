@@ -0,0 +1,55 @@
+package renderer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HTTP HTTP 渲染服务：以 POST 请求发送代码块内容（text/plain），2xx 响应体即渲染结果
+type HTTP struct {
+	URL      string
+	MaxBytes int64 // 响应体大小上限，0 表示不限制
+	Client   *http.Client
+}
+
+// Render 请求渲染服务渲染代码块
+func (r *HTTP) Render(ctx context.Context, source []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(source))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	if r.MaxBytes > 0 {
+		body = io.LimitReader(resp.Body, r.MaxBytes+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("renderer service returned %s: %s", resp.Status, msg)
+	}
+	if r.MaxBytes > 0 && int64(len(data)) > r.MaxBytes {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,102 @@
+package renderer
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// 服务端预渲染的内容类型
+const (
+	PrerenderMath        = "math"         // 行内公式
+	PrerenderMathDisplay = "math_display" // 块级公式
+	PrerenderMermaid     = "mermaid"      // Mermaid 图
+	PrerenderHighlight   = "highlight"    // 代码高亮
+)
+
+// unsafeMarkupPattern 预渲染输出中可执行脚本的标记；输出直接嵌入页面，出现时放弃预渲染
+var unsafeMarkupPattern = regexp.MustCompile(`(?i)<script|<iframe|<object|<embed|<foreignObject|\son[a-z]+\s*=|javascript:`)
+
+// highlightLangPattern 可传给高亮程序的代码块语言，避免以 - 开头被当作命令参数
+var highlightLangPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#._-]{0,31}$`)
+
+var highlighters sync.Map // 代码块语言 -> *Plugin
+
+// prerenderConfig 预渲染项的配置；块级公式未单独配置时使用行内公式的命令
+func prerenderConfig(kind string) config.RendererConfig {
+	cfg := config.Get().Prerender
+	switch kind {
+	case PrerenderMath:
+		return cfg.Math
+	case PrerenderMathDisplay:
+		if cfg.MathDisplay.Configured() {
+			return cfg.MathDisplay
+		}
+		return cfg.Math
+	case PrerenderMermaid:
+		return cfg.Mermaid
+	case PrerenderHighlight:
+		return cfg.Highlight
+	}
+	return config.RendererConfig{}
+}
+
+// PrerenderEnabled 服务器是否配置了该预渲染项
+func PrerenderEnabled(kind string) bool {
+	rc := prerenderConfig(kind)
+	return rc.Configured()
+}
+
+// prerenderPlugin 预渲染项对应的插件，未配置时返回 nil；代码高亮按语言替换 {lang} 后分别缓存
+func prerenderPlugin(kind, lang string) *Plugin {
+	rc := prerenderConfig(kind)
+	if !rc.Configured() {
+		return nil
+	}
+	name := "prerender:" + kind
+	if kind == PrerenderHighlight {
+		if !highlightLangPattern.MatchString(lang) {
+			return nil
+		}
+		if p, ok := highlighters.Load(lang); ok {
+			return p.(*Plugin)
+		}
+		name += ":" + lang
+	}
+	p := &Plugin{Lang: name, ContentType: "text/html", Timeout: rc.Timeout.Std(), MaxBytes: rc.MaxBytes}
+	if len(rc.Command) > 0 {
+		args := make([]string, len(rc.Command))
+		for i, a := range rc.Command {
+			args[i] = strings.ReplaceAll(a, "{lang}", lang)
+		}
+		p.Renderer = &Command{Args: args, MaxBytes: rc.MaxBytes}
+	} else {
+		p.Renderer = &HTTP{URL: strings.ReplaceAll(rc.URL, "{lang}", url.QueryEscape(lang)), MaxBytes: rc.MaxBytes}
+	}
+	if kind == PrerenderHighlight {
+		highlighters.Store(lang, p)
+	}
+	return p
+}
+
+// Prerender 预渲染公式、Mermaid 图或代码块（lang 为代码块语言），返回嵌入页面的 HTML 片段。
+// 结果与外部渲染插件共用缓存；输出含脚本等可执行标记时返回错误
+func Prerender(kind, lang, source string) (string, error) {
+	p := prerenderPlugin(kind, lang)
+	if p == nil {
+		return "", errors.New("prerender " + kind + " is not configured")
+	}
+	entry := Load(p, source)
+	if entry.Err != nil {
+		return "", entry.Err
+	}
+	out := strings.TrimSpace(string(entry.Data))
+	if unsafeMarkupPattern.MatchString(out) {
+		return "", errors.New("prerender " + kind + ": output contains executable markup")
+	}
+	return out, nil
+}
@@ -0,0 +1,132 @@
+// Package renderer 外部渲染插件：按代码块语言注册渲染器，由外部命令或 HTTP 服务把 ABC 乐谱、
+// Graphviz、Typst 等代码块渲染为图片，新增块类型只需修改配置而无需改动核心代码。
+// 渲染结果按语言与代码块内容哈希缓存。
+package renderer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// Renderer 渲染器：输入代码块内容，输出渲染结果（图片数据）
+type Renderer interface {
+	Render(ctx context.Context, source []byte) ([]byte, error)
+}
+
+// Plugin 注册到某个代码块语言的渲染插件
+type Plugin struct {
+	Lang        string        // 代码块语言（小写）
+	ContentType string        // 输出的 MIME 类型
+	Timeout     time.Duration // 单次渲染超时
+	MaxBytes    int64         // 输出大小上限
+	Renderer    Renderer
+}
+
+// maxConcurrent 同时进行的外部渲染数上限，避免突发访问拉起过多进程
+const maxConcurrent = 4
+
+var (
+	registry = struct {
+		sync.RWMutex
+		plugins map[string]*Plugin
+	}{plugins: map[string]*Plugin{}}
+	slots = make(chan struct{}, maxConcurrent)
+)
+
+// ErrTooLarge 渲染输出超过大小上限
+var ErrTooLarge = errors.New("renderer output exceeds size limit")
+
+// Register 注册（或替换）代码块语言的渲染插件
+func Register(p *Plugin) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.plugins[strings.ToLower(p.Lang)] = p
+}
+
+// Lookup 返回代码块语言对应的渲染插件，未注册时返回 nil
+func Lookup(lang string) *Plugin {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.plugins[strings.ToLower(lang)]
+}
+
+// Langs 返回已注册的代码块语言（按字母排序）
+func Langs() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	langs := make([]string, 0, len(registry.plugins))
+	for lang := range registry.plugins {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Init 按配置 renderers 注册渲染插件，替换已注册的插件；重新加载配置后再次调用时清空渲染结果缓存
+func Init() error {
+	plugins := map[string]*Plugin{}
+	for lang, rc := range config.Get().Renderers {
+		p := &Plugin{
+			Lang:        lang,
+			ContentType: rc.ContentType,
+			Timeout:     rc.Timeout.Std(),
+			MaxBytes:    rc.MaxBytes,
+		}
+		switch {
+		case len(rc.Command) > 0:
+			p.Renderer = &Command{Args: rc.Command, MaxBytes: rc.MaxBytes}
+		case rc.URL != "":
+			p.Renderer = &HTTP{URL: rc.URL, MaxBytes: rc.MaxBytes}
+		default:
+			return fmt.Errorf("renderer %s: command or url is required", lang)
+		}
+		plugins[strings.ToLower(lang)] = p
+	}
+	registry.Lock()
+	registry.plugins = plugins
+	registry.Unlock()
+	highlighters.Clear()
+	clearCache()
+	return nil
+}
+
+// Hash 代码块内容的哈希，用作缓存键与图片地址
+func Hash(source string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(source)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Render 在超时与并发限制内调用渲染器，并校验输出大小
+func (p *Plugin) Render(source string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+	select {
+	case slots <- struct{}{}:
+		defer func() { <-slots }()
+	case <-ctx.Done():
+		return nil, errors.New("renderer busy")
+	}
+	out, err := p.Renderer.Render(ctx, []byte(source))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("render %s: timed out after %s", p.Lang, p.Timeout)
+		}
+		return nil, fmt.Errorf("render %s: %w", p.Lang, err)
+	}
+	if p.MaxBytes > 0 && int64(len(out)) > p.MaxBytes {
+		return nil, ErrTooLarge
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("render %s: empty output", p.Lang)
+	}
+	return out, nil
+}
@@ -3,17 +3,18 @@ package routes
 import (
 	"embed"
 	"io/fs"
+	"log"
 	"mime"
 	"net/http"
-	"os"
 	"path"
 	"strings"
 	"time"
 
+	"github.com/ZeroHawkeye/siyuan-share-api/apidoc"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
 	"github.com/ZeroHawkeye/siyuan-share-api/controllers"
 	"github.com/ZeroHawkeye/siyuan-share-api/middleware"
 	"github.com/ZeroHawkeye/siyuan-share-api/models"
-	gz "github.com/gin-contrib/gzip"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,19 +22,30 @@ import (
 func SetupRouter(staticFiles *embed.FS) *gin.Engine {
 	// 自定义 Engine 以便关闭不必要的中间件或切换 JSON 序列化库
 	r := gin.New()
-	r.Use(gin.Recovery())
-	// 仅在开发时启用默认 Logger，生产可通过 GIN_MODE=release 禁用
-	if gin.Mode() != gin.ReleaseMode {
-		r.Use(gin.Logger())
+	// 只信任配置的反向代理转发的 X-Forwarded-For / X-Real-IP，限流、统计与审计日志使用的客户端 IP 不能被直连的请求伪造
+	if err := r.SetTrustedProxies(config.Get().Server.TrustedProxies); err != nil {
+		log.Printf("Invalid trusted proxies: %v", err)
 	}
+	// 请求 ID 与结构化访问日志（log.access 控制是否记录）、接口用量与 5xx 告警统计，随后捕获 panic
+	r.Use(middleware.RequestID(), middleware.AccessLog(), middleware.Usage(), middleware.ErrorAlert(), middleware.Recovery())
 
 	// 禁用自动重定向，避免根路径触发 301
 	r.RedirectTrailingSlash = false
 	r.RedirectFixedPath = false
 
-	// 使用 CORS 中间件 & 响应压缩
-	r.Use(middleware.CORSMiddleware())
-	r.Use(gz.Gzip(gz.BestSpeed))
+	// 自定义域名只提供绑定用户的分享与合集
+	r.Use(middleware.CustomDomains())
+	// 管理员配置的旧链接重定向，先于路由处理
+	r.Use(middleware.Redirects())
+	// 按国家/地区限制读者访问分享
+	r.Use(middleware.GeoRestrict())
+	// 限制页面被其他网站放入 iframe，分享嵌入页除外
+	r.Use(middleware.FrameOptions())
+
+	// 响应压缩：按 Accept-Encoding 使用 brotli 或 gzip（备份包本身已压缩，不再重复压缩；事件流需要逐条推送）
+	r.Use(middleware.Compress("/api/admin/backup", "/api/events", "/api/v1/admin/backup", "/api/v1/events"))
+	// JSON 错误响应附带请求 ID，错误信息按请求语言翻译
+	r.Use(middleware.ErrorRequestID(), middleware.LocalizeErrors())
 	// 静态文件服务（前端）
 	if staticFiles != nil {
 		// 获取嵌入的 dist 子文件系统
@@ -84,8 +96,28 @@ func SetupRouter(staticFiles *embed.FS) *gin.Engine {
 					if ext == ".html" || target == "index.html" {
 						contentType = "text/html; charset=utf-8"
 						c.Header("Cache-Control", "no-cache")
-					} else {
+						// 分享页面注入主题样式与 Open Graph 元信息
+						if target == "index.html" {
+							data = controllers.InjectAppManifest(c, data)
+							data = controllers.RenderSharePage(c, data)
+							if controllers.PageNotModified(c, data) {
+								return true
+							}
+						}
+					} else if strings.HasPrefix(target, "assets/") {
+						// Vite 构建的 assets 文件名带内容哈希，内容变化时文件名随之变化，可长期缓存
 						c.Header("Cache-Control", "public, max-age=31536000, immutable")
+					} else {
+						// favicon 等文件名固定的文件，更新后最多一天生效
+						c.Header("Cache-Control", "public, max-age=86400")
+					}
+
+					// 优先返回构建时生成的预压缩文件，压缩中间件见到 Content-Encoding 后不再压缩
+					if target != "index.html" {
+						if compressed, encoding := precompressed(distFS, target, c.GetHeader("Accept-Encoding")); compressed != nil {
+							c.Header("Content-Encoding", encoding)
+							data = compressed
+						}
 					}
 
 					c.Data(http.StatusOK, contentType, data)
@@ -99,74 +131,473 @@ func SetupRouter(staticFiles *embed.FS) *gin.Engine {
 					}
 				}
 
+				// 分享链接按 Accept 请求头返回 JSON 或 Markdown 原文
+				if controllers.ServeNegotiatedShare(c) {
+					return
+				}
+
+				// 慢速网络下分享页面返回服务端渲染的轻量版
+				if controllers.ServeLitePage(c) {
+					return
+				}
+
 				// 所有其他路径返回 index.html（SPA 路由）
 				serveFile("index.html")
 			})
 		}
 	}
-	// API 路由组 - 所有后端 API 都在 /api 前缀下
-	api := r.Group("/api")
-	{
-		// 健康检查（公开）
-		api.GET("/health", func(c *gin.Context) {
-			var userCount int64
-			models.DB.Model(&models.User{}).Count(&userCount)
-			c.JSON(http.StatusOK, gin.H{
-				"status":    "ok",
-				"ts":        time.Now().Unix(),
-				"userCount": userCount,
-				"ginMode":   os.Getenv("GIN_MODE"),
-				"version":   "v1", // 可后续从构建信息注入
-			})
+	// 存活与就绪检查（供 Kubernetes 探针与可用性监控使用，无需认证）
+	r.GET("/healthz", controllers.Healthz)
+	r.HEAD("/healthz", controllers.Healthz)
+	r.GET("/readyz", controllers.Readyz)
+	r.HEAD("/readyz", controllers.Readyz)
+
+	// 搜索引擎抓取规则与站点地图
+	r.GET("/robots.txt", controllers.Robots)
+	r.GET("/sitemap.xml", controllers.Sitemap)
+
+	// 安装为应用（PWA）的清单与图标
+	r.GET("/manifest.webmanifest", controllers.AppManifest)
+	r.GET("/pwa/icon", controllers.AppIcon)
+	r.GET("/pwa/maskable-icon", controllers.AppIcon)
+
+	// 用户 RSS 订阅源
+	r.GET("/u/:username/feed.xml", controllers.UserFeed)
+	r.GET("/u/:username/calendar.ics", controllers.UserCalendar)
+
+	// 嵌入其他网站的分享页面（iframe）
+	r.GET("/s/:id/embed", controllers.ShareEmbed)
+
+	// 分享短链接，记录点击后跳转到分享页面
+	r.GET("/x/:code", controllers.FollowShortLink)
+	r.HEAD("/x/:code", controllers.FollowShortLink)
+
+	// API 路由：/api/v1 为当前版本；/api 下保留同样的旧路径，已发布的插件、浏览器扩展与已生成的链接继续可用。
+	// 限流中间件两组路由共用，新旧路径计入同一份额
+	limits := apiLimits{
+		publish:  middleware.PublishRateLimit(),
+		ask:      middleware.QuestionRateLimit(),
+		comment:  middleware.CommentRateLimit(),
+		feedback: middleware.FeedbackRateLimit(),
+		report:   middleware.ReportRateLimit(),
+		vote:     middleware.VoteRateLimit(),
+		form:     middleware.FormRateLimit(),
+		order:    middleware.OrderRateLimit(),
+		progress: middleware.ProgressRateLimit(),
+	}
+	// OpenAPI 文档在全部路由注册后生成
+	var spec []byte
+	serveSpec := func(c *gin.Context) {
+		c.Header("Cache-Control", "no-cache")
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	}
+	for _, prefix := range []string{apidoc.BasePath, "/api"} {
+		api := r.Group(prefix)
+		// 插件与浏览器扩展跨域调用 API，预检请求由 CORS 中间件直接应答
+		api.Use(middleware.CORSMiddleware())
+		api.GET("/openapi.json", serveSpec)
+		registerAPI(api, limits)
+	}
+	// 预检请求的通配路由覆盖 /api/v1 下的路径，只注册一次
+	r.Group("/api", middleware.CORSMiddleware()).OPTIONS("/*path", func(c *gin.Context) {})
+	spec = apidoc.Build(r.Routes())
+
+	return r
+}
+
+// precompressed 读取静态文件的预压缩版本（.br 优先，其次 .gz），读者不接受或文件不存在时返回 nil
+func precompressed(distFS fs.FS, target, acceptEncoding string) ([]byte, string) {
+	for _, v := range []struct{ encoding, suffix string }{{"br", ".br"}, {"gzip", ".gz"}} {
+		if !middleware.AcceptsEncoding(acceptEncoding, v.encoding) {
+			continue
+		}
+		if data, err := fs.ReadFile(distFS, target+v.suffix); err == nil {
+			return data, v.encoding
+		}
+	}
+	return nil, ""
+}
+
+// apiLimits 接口限流中间件
+type apiLimits struct {
+	publish, ask, comment, feedback, report, vote, form, order, progress gin.HandlerFunc
+}
+
+// registerAPI 在 api 路由组下注册全部后端接口
+func registerAPI(api *gin.RouterGroup, limits apiLimits) {
+	// 健康检查（公开）
+	api.GET("/health", func(c *gin.Context) {
+		var userCount int64
+		models.DB.Model(&models.User{}).Count(&userCount)
+		c.JSON(http.StatusOK, gin.H{
+			"status":    "ok",
+			"ts":        time.Now().Unix(),
+			"userCount": userCount,
+			"ginMode":   gin.Mode(),
+			"version":   "v1", // 可后续从构建信息注入
 		})
+	})
 
-		// 注册与登录（无需认证）
-		api.POST("/auth/register", controllers.Register)
-		api.POST("/auth/login", controllers.Login)
-
-		// 健康检查（需要认证，用于测试 API Token）
-		api.GET("/auth/health", middleware.AuthMiddleware(), func(c *gin.Context) {
-			userID, _ := c.Get("userID")
-			c.JSON(http.StatusOK, gin.H{
-				"code": 0,
-				"msg":  "success",
-				"data": gin.H{
-					"status": "ok",
-					"userID": userID,
-					"ts":     time.Now().Unix(),
-				},
-			})
+	// 测试模式：清空数据并重置 ID 生成序列，只在 TEST_MODE 下注册
+	if config.Get().Server.TestMode {
+		api.POST("/test/reset", controllers.ResetTestData)
+	}
+
+	// 注册与登录（无需认证）
+	api.GET("/auth/registration", controllers.RegistrationInfo)
+	api.GET("/challenge", controllers.GetChallengeInfo)
+	api.POST("/auth/register", controllers.Register)
+	api.POST("/auth/login", controllers.Login)
+	api.POST("/auth/logout", middleware.AuthMiddleware(), controllers.Logout)
+
+	// 邮箱验证与找回密码
+	api.GET("/auth/verify-email", controllers.VerifyEmail)
+	api.POST("/auth/verify-email", controllers.VerifyEmail)
+	api.POST("/auth/resend-verification", middleware.AuthMiddleware(), controllers.ResendVerification)
+	api.POST("/auth/forgot-password", controllers.ForgotPassword)
+	api.POST("/auth/reset-password", controllers.ResetPassword)
+	// 通过锁定通知邮件解锁账号
+	api.GET("/auth/unlock", controllers.UnlockAccount)
+	api.POST("/auth/unlock", controllers.UnlockAccount)
+
+	// 两步验证（TOTP）管理
+	twoFactor := api.Group("/auth/2fa")
+	twoFactor.Use(middleware.AuthMiddleware())
+	{
+		twoFactor.POST("/setup", controllers.SetupTwoFactor)
+		twoFactor.POST("/enable", controllers.EnableTwoFactor)
+		twoFactor.POST("/disable", controllers.DisableTwoFactor)
+		twoFactor.POST("/recovery-codes", controllers.RegenerateRecoveryCodes)
+	}
+
+	// 第三方登录（OAuth2 / OIDC）
+	api.GET("/auth/oidc", controllers.ListOIDCProviders)
+	api.GET("/auth/oidc/:provider", controllers.OIDCLogin)
+	api.GET("/auth/oidc/:provider/callback", controllers.OIDCCallback)
+	api.POST("/auth/oidc/:provider/2fa", controllers.OIDCTwoFactor)
+
+	// 健康检查（需要认证，用于测试 API Token）
+	api.GET("/auth/health", middleware.AuthMiddleware(), func(c *gin.Context) {
+		userID, _ := c.Get("userID")
+		c.JSON(http.StatusOK, gin.H{
+			"code": 0,
+			"msg":  "success",
+			"data": gin.H{
+				"status": "ok",
+				"userID": userID,
+				"ts":     time.Now().Unix(),
+			},
 		})
+	})
 
-		// 需要认证的分享管理接口
-		share := api.Group("/share")
-		share.Use(middleware.AuthMiddleware())
-		{
-			share.POST("/create", controllers.CreateShare)
-			share.GET("/list", controllers.ListShares)
-			share.DELETE("/batch", controllers.DeleteSharesBatch)
-			share.DELETE(":id", controllers.DeleteShare)
-		}
+	// 需要认证的分享管理接口
+	publishLimit := limits.publish
+	share := api.Group("/share")
+	share.Use(middleware.AuthMiddleware())
+	{
+		share.POST("/create", publishLimit, controllers.CreateShare)
+		share.POST("/import", publishLimit, controllers.ImportShare)
+		share.GET("/list", controllers.ListShares)
+		share.DELETE("/batch", controllers.DeleteSharesBatch)
+		share.DELETE(":id", controllers.DeleteShare)
+		share.PATCH(":id", controllers.UpdateShare)
+		share.POST(":id/assets", publishLimit, controllers.UploadAsset)
+		share.GET(":id/assets", controllers.ListAssets)
+		share.GET(":id/assets/missing", controllers.CheckShareAssets)
+		share.PUT(":id/assets/transcript", controllers.AttachTranscript)
+		share.DELETE(":id/assets/transcript", controllers.RemoveTranscript)
+	}
 
-		user := api.Group("/user")
-		user.Use(middleware.AuthMiddleware())
-		{
-			user.GET("/me", controllers.Me)
-		}
+	// 分享资源集合接口
+	shares := api.Group("/shares")
+	shares.Use(middleware.AuthMiddleware())
+	{
+		shares.GET("", controllers.ListShares)
+		shares.POST("/batch", controllers.BatchShares)
+		shares.GET("/semantic-search", controllers.SemanticSearch)
+		shares.POST("/semantic-index", controllers.ReindexShares)
+		shares.GET("/trash", controllers.ListTrash)
+		shares.DELETE("/trash", controllers.EmptyTrash)
+		shares.POST("/trash/:id/restore", controllers.RestoreTrashedShare)
+		shares.DELETE("/trash/:id", controllers.PurgeTrashedShare)
+		shares.GET("/:id/related", controllers.RelatedShares)
+		shares.GET("/:id/annotations", controllers.ListOwnerAnnotations)
+		shares.PATCH("/:id/annotations/:aid", controllers.ModerateAnnotation)
+		shares.GET("/:id/comments", controllers.ListOwnerComments)
+		shares.POST("/:id/comments/:cid/approve", controllers.ApproveComment)
+		shares.DELETE("/:id/comments/:cid", controllers.DeleteComment)
+		shares.GET("/:id/access", controllers.GetShareAccess)
+		shares.PUT("/:id/access", controllers.UpdateShareAccess)
+		shares.GET("/:id/terms", controllers.ListShareTermsAcceptances)
+		shares.GET("/:id/snapshots", controllers.ListOwnerSnapshots)
+		shares.GET("/:id/narration", controllers.GetNarration)
+		shares.POST("/:id/narration", controllers.CreateNarration)
+		shares.DELETE("/:id/narration", controllers.DeleteNarration)
+		shares.GET("/:id/translations", controllers.ListTranslations)
+		shares.POST("/:id/translations", controllers.CreateTranslation)
+		shares.GET("/:id/translations/:lang", controllers.GetTranslation)
+		shares.PUT("/:id/translations/:lang", controllers.SaveTranslation)
+		shares.DELETE("/:id/translations/:lang", controllers.DeleteTranslation)
+		shares.GET("/:id/language-check", controllers.GetLanguageCheck)
+		shares.POST("/:id/language-check", controllers.CreateLanguageCheck)
+		shares.GET("/:id/prerender", controllers.GetPrerender)
+		shares.POST("/:id/render-trace", controllers.TraceShareRender)
+		shares.GET("/:id/stats/export", controllers.ExportShareViews)
+		shares.GET("/:id/stats/variants", controllers.GetShareVariantStats)
+		shares.GET("/:id/stats/feedback", controllers.GetShareFeedbackStats)
+		shares.GET("/:id/stats/reading", controllers.GetShareReadingStats)
+		shares.DELETE("/:id/feedback/:fid", controllers.DeleteShareFeedback)
+		shares.GET("/:id/forms", controllers.ListOwnerForms)
+		shares.GET("/:id/forms/:key/submissions", controllers.ListFormSubmissions)
+		shares.GET("/:id/forms/:key/export", controllers.ExportFormSubmissions)
+		shares.DELETE("/:id/forms/:key/submissions/:sid", controllers.DeleteFormSubmission)
+		shares.GET("/:id/payments", controllers.ListSharePayments)
+		shares.GET("/:id/summary", controllers.GetSummary)
+		shares.POST("/:id/summary", controllers.CreateSummary)
+		shares.POST("/:id/status", controllers.UpdateShareStatus)
+		shares.POST("/:id/seal", controllers.SealShare)
+		shares.GET("/:id/preview", controllers.PreviewShare)
+		shares.GET("/:id/revisions", controllers.ListShareRevisions)
+		shares.GET("/:id/revisions/:rid", controllers.GetShareRevision)
+		shares.POST("/:id/revisions/:rid/rollback", controllers.RollbackShare)
+		shares.GET("/:id/export", controllers.ExportShareBundle)
+		shares.POST("/:id/exports", controllers.CreateExport)
+		shares.GET("/:id/exports", controllers.ListExports)
+		shares.GET("/:id/exports/:eid", controllers.GetExport)
+		shares.GET("/:id/exports/:eid/download", controllers.DownloadExport)
+		shares.GET("/:id/links", controllers.ListShortLinks)
+		shares.POST("/:id/links", controllers.CreateShortLink)
+		shares.GET("/:id/links/:code", controllers.GetShortLink)
+		shares.PATCH("/:id/links/:code", controllers.UpdateShortLink)
+		shares.DELETE("/:id/links/:code", controllers.DeleteShortLink)
+	}
 
-		// Token 管理端点（需要认证）
-		token := api.Group("/token")
-		token.Use(middleware.AuthMiddleware())
-		{
-			token.GET("/list", controllers.ListTokens)
-			token.POST("/create", controllers.CreateToken)
-			token.POST("/refresh/:id", controllers.RefreshToken)
-			token.POST("/revoke/:id", controllers.RevokeToken)
-		}
+	// 自定义主题管理
+	themes := api.Group("/themes")
+	themes.Use(middleware.AuthMiddleware())
+	{
+		themes.GET("", controllers.ListThemes)
+		themes.POST("", controllers.CreateTheme)
+		themes.GET("/:id", controllers.GetTheme)
+		themes.PUT("/:id", controllers.UpdateTheme)
+		themes.DELETE("/:id", controllers.DeleteTheme)
+	}
+	api.GET("/themes/:id/style.css", controllers.ServeThemeCSS)
 
-		// 公开访问的分享查看接口
-		api.GET("/s/:id", controllers.GetShare)
+	// 发布模板管理
+	templates := api.Group("/templates")
+	templates.Use(middleware.AuthMiddleware())
+	{
+		templates.GET("", controllers.ListShareTemplates)
+		templates.POST("", controllers.CreateShareTemplate)
+		templates.GET("/:id", controllers.GetShareTemplate)
+		templates.PUT("/:id", controllers.UpdateShareTemplate)
+		templates.DELETE("/:id", controllers.DeleteShareTemplate)
 	}
 
-	return r
+	// 分享合集管理，合集首页数据公开
+	collections := api.Group("/collections")
+	collections.Use(middleware.AuthMiddleware())
+	{
+		collections.GET("", controllers.ListCollections)
+		collections.POST("", controllers.CreateCollection)
+		collections.PUT("/:id", controllers.UpdateCollection)
+		collections.DELETE("/:id", controllers.DeleteCollection)
+	}
+	api.GET("/c/:slug", controllers.GetCollection)
+
+	// 自定义域名绑定与 DNS 验证
+	domains := api.Group("/domains")
+	domains.Use(middleware.AuthMiddleware())
+	{
+		domains.GET("", controllers.ListDomains)
+		domains.POST("", controllers.CreateDomain)
+		domains.PATCH("/:id", controllers.UpdateDomain)
+		domains.POST("/:id/verify", controllers.VerifyDomain)
+		domains.DELETE("/:id", controllers.DeleteDomain)
+	}
+
+	user := api.Group("/user")
+	user.Use(middleware.AuthMiddleware())
+	{
+		user.GET("/me", controllers.Me)
+		user.PATCH("/settings", controllers.UpdateSettings)
+		user.GET("/notifications", controllers.GetNotificationSettings)
+		user.PUT("/notifications", controllers.UpdateNotificationSettings)
+		user.GET("/sessions", controllers.ListSessions)
+		user.DELETE("/sessions", controllers.RevokeAllSessions)
+		user.DELETE("/sessions/:id", controllers.RevokeSession)
+	}
+
+	// 当前用户资料（用户名、邮箱、密码）与账号注销
+	me := api.Group("/me")
+	me.Use(middleware.AuthMiddleware())
+	{
+		me.GET("", controllers.Me)
+		me.PATCH("", controllers.UpdateProfile)
+		me.DELETE("", controllers.DeleteAccount)
+		me.POST("/restore", controllers.CancelAccountDeletion)
+	}
+
+	// 当前用户存储用量与配额、接口用量、本月各分享读者流量
+	api.GET("/me/usage", middleware.AuthMiddleware(), controllers.GetUsage)
+	api.GET("/me/api-usage", middleware.AuthMiddleware(), controllers.GetAPIUsage)
+	api.GET("/me/bandwidth", middleware.AuthMiddleware(), controllers.GetBandwidth)
+	// 插件读取的服务端能力、限制与配额状态
+	api.GET("/capabilities", middleware.AuthMiddleware(), controllers.GetCapabilities)
+
+	// 全站公告：控制面板与阅读页读取，登录用户关闭后不再显示
+	api.GET("/announcements", controllers.ListAnnouncements)
+	api.POST("/announcements/:id/dismiss", middleware.AuthMiddleware(), controllers.DismissAnnouncement)
+
+	// 控制面板的实时事件流（SSE）
+	api.GET("/events", middleware.AuthMiddleware(), controllers.Events)
+
+	// 管理员接口：用户配额覆盖、解锁、接口用量与代绑自定义域名，重定向规则、公告与实例指标
+	admin := api.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	{
+		admin.GET("/users/:user/quota", controllers.GetUserQuota)
+		admin.PUT("/users/:user/quota", controllers.UpdateUserQuota)
+		admin.POST("/users/:user/unlock", controllers.AdminUnlockUser)
+		admin.GET("/users/:user/api-usage", controllers.GetUserAPIUsage)
+		admin.POST("/users/:user/domains", controllers.AdminBindDomain)
+		admin.GET("/api-usage", controllers.ListAPIUsage)
+		admin.GET("/redirects", controllers.ListRedirects)
+		admin.POST("/redirects", controllers.CreateRedirect)
+		admin.PUT("/redirects/:id", controllers.UpdateRedirect)
+		admin.DELETE("/redirects/:id", controllers.DeleteRedirect)
+		admin.GET("/stats", controllers.AdminStats)
+		admin.GET("/stats/history", controllers.StatsHistory)
+		admin.GET("/jobs", controllers.ListJobs)
+		admin.POST("/jobs/:name/run", controllers.RunJob)
+		admin.GET("/content-pipeline", controllers.ContentPipelineStats)
+		admin.POST("/shares/:id/render-trace", controllers.AdminTraceShareRender)
+		admin.POST("/backup", controllers.CreateBackup)
+		admin.POST("/site-export", controllers.ExportSite)
+		admin.POST("/config/reload", controllers.ReloadConfig)
+		admin.POST("/sql", controllers.RunSQLQuery)
+		admin.GET("/reports", controllers.ListReports)
+		admin.POST("/reports/:rid/resolve", controllers.ResolveReport)
+		admin.POST("/shares/:id/reinstate", controllers.ReinstateShare)
+		admin.GET("/invites", controllers.ListInvites)
+		admin.POST("/invites", controllers.CreateInvite)
+		admin.DELETE("/invites/:code", controllers.DeleteInvite)
+		admin.GET("/announcements", controllers.AdminListAnnouncements)
+		admin.POST("/announcements", controllers.CreateAnnouncement)
+		admin.PUT("/announcements/:id", controllers.UpdateAnnouncement)
+		admin.DELETE("/announcements/:id", controllers.DeleteAnnouncement)
+	}
+
+	// oEmbed（由分享链接生成嵌入代码）
+	api.GET("/oembed", controllers.OEmbed)
+
+	// 内容签名公钥
+	api.GET("/signing-key", controllers.GetSigningKey)
+
+	// 浏览器推送（Web Push）
+	api.GET("/push/vapid-public-key", controllers.GetVAPIDPublicKey)
+	push := api.Group("/push/subscriptions")
+	push.Use(middleware.AuthMiddleware())
+	{
+		push.GET("", controllers.ListPushSubscriptions)
+		push.POST("", controllers.CreatePushSubscription)
+		push.DELETE("/:id", controllers.DeletePushSubscription)
+		push.POST("/:id/test", controllers.TestPush)
+	}
+
+	// Token 管理端点（需要认证）
+	token := api.Group("/token")
+	token.Use(middleware.AuthMiddleware())
+	{
+		token.GET("/list", controllers.ListTokens)
+		token.POST("/create", controllers.CreateToken)
+		token.POST("/refresh/:id", controllers.RefreshToken)
+		token.POST("/revoke/:id", controllers.RevokeToken)
+	}
+
+	// 公开访问的分享查看接口
+	api.GET("/s/:id", controllers.GetShare)
+	api.GET("/s/:id/assets/*path", controllers.ServeAsset)
+	api.GET("/s/:id/translations/:lang", controllers.GetShareTranslation)
+	api.GET("/s/:id/transcripts", controllers.ListShareTranscripts)
+	api.GET("/s/:id/transcripts/*path", controllers.ServeTranscript)
+	api.GET("/s/:id/drawings", controllers.ListShareDrawings)
+	api.GET("/s/:id/drawings/:file", controllers.ServeDrawing)
+	api.GET("/s/:id/mindmaps", controllers.ListShareMindmaps)
+	api.GET("/s/:id/mindmaps/:file", controllers.ServeMindmap)
+	api.GET("/s/:id/renders", controllers.ListShareRenders)
+	api.GET("/s/:id/renders/:hash", controllers.ServeRender)
+	api.GET("/s/:id/offline", controllers.GetOfflineManifest)
+	api.GET("/s/:id/calendar.ics", controllers.ShareCalendar)
+	api.GET("/s/:id/citations", controllers.ShareCitations)
+	api.GET("/s/:id/seal", controllers.GetShareSeal)
+	api.GET("/s/:id/seal/source", controllers.GetShareSealSource)
+	api.GET("/s/:id/signature", controllers.GetShareSignature)
+	api.GET("/s/:id/signature/source", controllers.GetShareSignedSource)
+	api.POST("/s/:id/verify", controllers.VerifyShare)
+	api.GET("/s/:id/export/pdf", controllers.ExportSharePDF)
+	api.GET("/s/:id/tables", controllers.ShareTables)
+	api.GET("/s/:id/tables/:index/csv", controllers.ShareTableCSV)
+	api.GET("/s/:id/flashcards", controllers.ShareFlashcards)
+	api.GET("/s/:id/archive", controllers.ListShareSnapshots)
+	api.GET("/s/:id/archive/:sid", controllers.ServeSnapshot)
+	api.POST("/s/:id/read", controllers.RecordShareRead)
+
+	// 读者阅读进度（书签）：以匿名读者令牌保存，续读链接可在其他设备上恢复
+	api.GET("/s/:id/progress", controllers.GetReadingProgress)
+	api.PUT("/s/:id/progress", limits.progress, controllers.SaveReadingProgress)
+	api.DELETE("/s/:id/progress", controllers.DeleteReadingProgress)
+
+	// 读者问答（需开启 ai.qa，作者为分享开启问答）
+	askLimit := limits.ask
+	api.POST("/s/:id/ask", askLimit, controllers.AskShare)
+	api.POST("/c/:slug/ask", askLimit, controllers.AskCollection)
+
+	// 受限分享的邮件登录链接与同意使用条款
+	api.POST("/s/:id/access", controllers.RequestShareAccess)
+	api.POST("/s/:id/access/verify", controllers.VerifyShareAccess)
+	api.POST("/s/:id/terms", controllers.AcceptShareTerms)
+
+	// 读者划线批注
+	api.GET("/s/:id/annotations", controllers.ListShareAnnotations)
+	annotations := api.Group("/s/:id/annotations")
+	annotations.Use(middleware.AuthMiddleware())
+	{
+		annotations.POST("", controllers.CreateAnnotation)
+		annotations.PATCH("/:aid", controllers.UpdateAnnotation)
+		annotations.DELETE("/:aid", controllers.DeleteAnnotation)
+	}
+
+	// 读者评论（可匿名）
+	api.GET("/s/:id/comments", controllers.ListShareComments)
+	api.POST("/s/:id/comments", limits.comment, middleware.OptionalAuthMiddleware(), controllers.CreateComment)
+
+	// 读者反馈「是否有帮助」
+	api.POST("/s/:id/feedback", limits.feedback, controllers.SubmitFeedback)
+
+	// 正文中的投票块：实时结果与投票（登录读者按账号、匿名读者按浏览器指纹去重）
+	api.GET("/s/:id/polls", middleware.OptionalAuthMiddleware(), controllers.ListSharePolls)
+	api.POST("/s/:id/polls/:key/vote", limits.vote, middleware.OptionalAuthMiddleware(), controllers.VotePoll)
+
+	// 正文中的表单块：读者提交，所有者在分享管理中查看与导出
+	api.GET("/s/:id/forms", controllers.ListShareForms)
+	api.POST("/s/:id/forms/:key", limits.form, controllers.SubmitForm)
+
+	// 付费阅读：读者创建订单并轮询状态，支付平台回调确认付款或退款
+	api.POST("/s/:id/payments", limits.order, controllers.CreatePaymentOrder)
+	api.GET("/s/:id/payments/:oid", controllers.GetPaymentOrder)
+	api.POST("/payments/webhook", middleware.VerifyWebhook(controllers.PaymentWebhookGuard), controllers.PaymentWebhook)
+
+	// 读者举报分享
+	api.POST("/s/:id/report", limits.report, middleware.OptionalAuthMiddleware(), controllers.ReportShare)
+
+	// 读者订阅分享更新
+	api.POST("/s/:id/subscribe", controllers.SubscribeShare)
+	api.GET("/subscriptions/confirm", controllers.ConfirmSubscription)
+	api.GET("/subscriptions/unsubscribe", controllers.Unsubscribe)
+	api.POST("/subscriptions/unsubscribe", controllers.Unsubscribe)
+
+	// 站内公开搜索
+	api.GET("/search", controllers.SearchShares)
 }
@@ -0,0 +1,64 @@
+// Package safehttp 提供抓取外部页面用的 HTTP 客户端：连接时校验解析后的 IP，
+// 拒绝访问内网、本机与链路本地地址，防止 SSRF。
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// ErrPrivateAddress 目标地址属于内网或本机
+var ErrPrivateAddress = errors.New("safehttp: refusing to fetch private address")
+
+// UserAgent 抓取外部页面时使用的 User-Agent
+const UserAgent = "Mozilla/5.0 (compatible; SiYuanShareBot/1.0)"
+
+// allowPrivate 是否允许访问内网地址 (links.allow_private)，默认拒绝
+func allowPrivate() bool {
+	return config.Get().Links.AllowPrivate
+}
+
+// NewClient 创建限制目标地址的 HTTP 客户端，最多跟随 5 次 http/https 重定向
+func NewClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: (&net.Dialer{
+				Timeout: 5 * time.Second,
+				Control: func(network, address string, _ syscall.RawConn) error {
+					if allowPrivate() {
+						return nil
+					}
+					host, _, err := net.SplitHostPort(address)
+					if err != nil {
+						return err
+					}
+					ip := net.ParseIP(host)
+					if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() ||
+						ip.IsLinkLocalMulticast() || ip.IsUnspecified() || ip.IsMulticast() {
+						return ErrPrivateAddress
+					}
+					return nil
+				},
+			}).DialContext,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     30 * time.Second,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("safehttp: too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errors.New("safehttp: unsupported redirect scheme")
+			}
+			return nil
+		},
+	}
+}
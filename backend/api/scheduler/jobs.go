@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/backup"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/hooks"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// orphanGrace 分享彻底删除后保留资源文件的时间，避免与进行中的上传或重新发布冲突；
+// 回收站中的分享另外保留 jobs.trash_retention
+const orphanGrace = 24 * time.Hour
+
+// orphanBatch 每次回收的资源数上限，剩余部分留到下一次执行
+const orphanBatch = 1000
+
+// deletionBatch 每次删除的账号数上限
+const deletionBatch = 20
+
+// trashBatch 彻底删除回收站分享时每批的数量
+const trashBatch = 100
+
+func init() {
+	register("expired_shares", "删除过期超过 jobs.share_retention 的分享", expiredShares)
+	register("orphan_assets", "回收已删除分享的资源文件与图片副本", orphanAssets)
+	register("wal_checkpoint", "SQLite WAL 检查点并截断 WAL 文件", walCheckpoint)
+	register("instance_stats", "汇总实例指标（用户、分享、浏览量、存储用量），每天保存一行", instanceStats)
+	register("hook_retries", "重试投递失败的异步 HTTP 钩子", hookRetries)
+	register("backup", "备份数据库与存储对象到 backup.dir 或 backup.s3", runBackup)
+	register("account_deletions", "彻底删除注销宽限期已满的账号及其全部数据", accountDeletions)
+	register("view_events", "删除超过 jobs.view_retention 且已汇总的原始浏览记录与 180 天未更新的阅读进度", viewEvents)
+	register("view_rollup", "将已结束日期的原始浏览记录汇总为每日数据，删除超过 jobs.summary_retention 的汇总", viewRollup)
+	register("trash", "彻底删除回收站中超过 jobs.trash_retention 的分享", purgeTrash)
+	register("share_expiry_notices", "按分享者的通知设置邮件提醒即将到期的分享", shareExpiryNotices)
+}
+
+func expiredShares(ctx context.Context) (string, error) {
+	before := time.Now().Add(-config.Get().Jobs.ShareRetention.Std())
+	n, err := models.DeleteExpiredShares(ctx, before)
+	return fmt.Sprintf("deleted %d shares", n), err
+}
+
+func orphanAssets(ctx context.Context) (string, error) {
+	assets, err := models.OrphanAssets(orphanGrace+config.Get().Jobs.TrashRetention.Std(), orphanBatch)
+	if err != nil {
+		return "", err
+	}
+	removed, freed := 0, int64(0)
+	for i := range assets {
+		if ctx.Err() != nil {
+			break
+		}
+		a := &assets[i]
+		var variants []models.AssetVariant
+		models.DB.Where("asset_id = ?", a.ID).Find(&variants)
+		for _, v := range variants {
+			if err := storage.Default.Delete(ctx, v.StorageKey); err != nil {
+				log.Printf("orphan variant cleanup failed (%s): %v", v.StorageKey, err)
+				continue
+			}
+			models.DB.Delete(&v)
+			freed += v.Size
+		}
+		if err := storage.Default.Delete(ctx, a.StorageKey); err != nil {
+			log.Printf("orphan asset cleanup failed (%s): %v", a.StorageKey, err)
+			continue
+		}
+		if err := models.DB.Delete(a).Error; err != nil {
+			return "", err
+		}
+		removed++
+		freed += a.Size
+	}
+	return fmt.Sprintf("removed %d assets (%d bytes)", removed, freed), ctx.Err()
+}
+
+func walCheckpoint(context.Context) (string, error) {
+	return "", models.CheckpointWAL()
+}
+
+// instanceStats 当天的汇总随任务执行不断更新，日期切换后保留前一天最后一次汇总的值
+func instanceStats(context.Context) (string, error) {
+	return "", models.RecordInstanceStats(time.Now())
+}
+
+func hookRetries(ctx context.Context) (string, error) {
+	sent, dropped, err := hooks.RetryDeliveries(ctx)
+	return fmt.Sprintf("sent %d, dropped %d", sent, dropped), err
+}
+
+func runBackup(ctx context.Context) (string, error) {
+	return backup.Push(ctx)
+}
+
+func accountDeletions(ctx context.Context) (string, error) {
+	ids, err := models.UsersDueForDeletion(time.Now(), deletionBatch)
+	if err != nil {
+		return "", err
+	}
+	deleted := 0
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := models.PurgeUser(ctx, id); err != nil {
+			return fmt.Sprintf("deleted %d accounts", deleted), err
+		}
+		log.Printf("account %s deleted after grace period", id)
+		deleted++
+	}
+	return fmt.Sprintf("deleted %d accounts", deleted), ctx.Err()
+}
+
+// viewEvents view_retention 为 0 时不再记录，已有的记录汇总后全部删除。删除前先汇总已结束的日期，
+// 尚未汇总的记录（如当天的）保留到汇总之后
+func viewEvents(ctx context.Context) (string, error) {
+	r, err := models.RollupShareViews(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	before := time.Now().Add(-config.Get().Jobs.ViewRetention.Std())
+	if before.After(r.Covered) {
+		before = r.Covered
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	n, err := models.DeleteShareViewsBefore(before)
+	if err != nil {
+		return "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Sprintf("deleted %d view events", n), err
+	}
+	p, err := models.DeleteReadingProgressBefore(time.Now().Add(-models.ReadingProgressRetention))
+	return fmt.Sprintf("deleted %d view events, %d reading progress", n, p), err
+}
+
+// viewRollup 汇总结果（含耗时）同时保存为最近一次的汇总指标，见管理后台统计概览
+func viewRollup(ctx context.Context) (string, error) {
+	r, err := models.RollupShareViews(ctx, time.Now())
+	if err != nil {
+		return "", err
+	}
+	var pruned int64
+	if keep := config.Get().Jobs.RollupRetention; keep > 0 {
+		if err = ctx.Err(); err == nil {
+			pruned, err = models.DeleteShareViewDailiesBefore(time.Now().Add(-keep.Std()))
+		}
+	}
+	return fmt.Sprintf("rolled up %d view events over %d days into %d rows in %dms, deleted %d daily rows",
+		r.Events, r.Days, r.Rows, r.DurationMs, pruned), err
+}
+
+func purgeTrash(ctx context.Context) (string, error) {
+	before := time.Now().Add(-config.Get().Jobs.TrashRetention.Std())
+	var total int64
+	for ctx.Err() == nil {
+		ids, err := models.TrashedShareIDs("", before, trashBatch)
+		if err != nil || len(ids) == 0 {
+			return fmt.Sprintf("purged %d shares", total), err
+		}
+		n, err := models.PurgeShares(ctx, ids)
+		total += n
+		if err != nil || len(ids) < trashBatch {
+			return fmt.Sprintf("purged %d shares", total), err
+		}
+	}
+	return fmt.Sprintf("purged %d shares", total), ctx.Err()
+}
+
+func shareExpiryNotices(ctx context.Context) (string, error) {
+	n, err := notify.ShareExpiryReminders(ctx)
+	return fmt.Sprintf("sent %d reminders", n), err
+}
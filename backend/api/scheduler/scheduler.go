@@ -0,0 +1,232 @@
+// Package scheduler 进程内定时任务：按 jobs 配置为每个任务设置开关与间隔，每次执行前加入随机延迟，
+// 同一任务不会重叠执行。多实例部署时按 jobs.leader_election 选主，定时执行只在主实例上进行。
+// 最近一次执行的状态保存在内存中，供管理后台查看与手动触发
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/cache"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/election"
+)
+
+// leaseName 定时任务选主使用的租约名称
+const leaseName = "scheduler"
+
+// runTimeout 单次执行的超时
+const runTimeout = 30 * time.Minute
+
+var (
+	// ErrNotFound 没有该名称的任务
+	ErrNotFound = errors.New("job not found")
+	// ErrRunning 任务正在执行
+	ErrRunning = errors.New("job is already running")
+	// ErrShuttingDown 服务正在退出
+	ErrShuttingDown = errors.New("server is shutting down")
+)
+
+// Func 任务函数，返回简短的执行结果（如处理的记录数）
+type Func func(ctx context.Context) (string, error)
+
+// Status 任务的配置与最近一次执行情况
+type Status struct {
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Enabled      bool       `json:"enabled"`
+	Interval     string     `json:"interval"`
+	Running      bool       `json:"running"`
+	Runs         int        `json:"runs"`     // 启动以来的执行次数
+	Failures     int        `json:"failures"` // 启动以来失败的次数
+	Skipped      int        `json:"skipped"`  // 启动以来因本实例不是主实例而跳过的定时执行次数
+	LastStart    *time.Time `json:"lastStart,omitempty"`
+	LastDuration int64      `json:"lastDurationMs"`
+	LastResult   string     `json:"lastResult,omitempty"`
+	LastError    string     `json:"lastError,omitempty"`
+	NextRun      *time.Time `json:"nextRun,omitempty"`
+}
+
+type job struct {
+	name        string
+	description string
+	run         Func
+
+	mu     sync.Mutex
+	status Status
+}
+
+var jobs []*job
+
+// elector 定时任务的选主器，jobs.leader_election 为 none 时为 nil（每个实例都执行）
+var elector *election.Elector
+
+func register(name, description string, run Func) {
+	jobs = append(jobs, &job{name: name, description: description, run: run})
+}
+
+func lookup(name string) *job {
+	for _, j := range jobs {
+		if j.name == name {
+			return j
+		}
+	}
+	return nil
+}
+
+// Start 开始选主，并为启用的任务启动定时循环：启动后经过随机延迟执行第一次，之后每隔 interval（加随机延迟）执行，
+// 本实例不是主实例时跳过该次执行
+func Start() error {
+	cfg := config.Get().Jobs
+	var backend election.Backend
+	switch cfg.LeaderElection {
+	case "db":
+		backend = election.DB{}
+	case "redis":
+		r, err := cache.NewRedis(config.Get().Cache.RedisURL)
+		if err != nil {
+			return err
+		}
+		backend = election.Redis{Client: r}
+	}
+	if backend != nil {
+		elector = election.New(backend, leaseName, cfg.LeaseTTL.Std())
+		go elector.Run()
+	}
+
+	tasks := cfg.Tasks()
+	for _, j := range jobs {
+		task := tasks[j.name]
+		if task == nil || !task.Enabled {
+			continue
+		}
+		go j.loop(task.Interval.Std(), cfg.Jitter.Std())
+	}
+	return nil
+}
+
+// Stop 停止选主并释放租约，应在进行中的任务结束后调用，其他实例随即接管定时任务
+func Stop() {
+	if elector != nil {
+		elector.Stop()
+	}
+}
+
+// IsLeader 本实例是否执行定时任务：未启用选主时总是 true
+func IsLeader() bool {
+	return elector == nil || elector.IsLeader()
+}
+
+// jitter [0, min(max, interval/2)) 内的随机延迟
+func jitter(max, interval time.Duration) time.Duration {
+	if half := interval / 2; max > half {
+		max = half
+	}
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+func (j *job) loop(interval, maxJitter time.Duration) {
+	delay := jitter(maxJitter, interval)
+	for {
+		next := time.Now().Add(delay)
+		j.mu.Lock()
+		j.status.NextRun = &next
+		j.mu.Unlock()
+		time.Sleep(delay)
+		if !IsLeader() {
+			j.mu.Lock()
+			j.status.Skipped++
+			j.mu.Unlock()
+		} else if err := j.start(true); errors.Is(err, ErrShuttingDown) {
+			return
+		}
+		delay = interval + jitter(maxJitter, interval)
+	}
+}
+
+// start 在后台执行一次任务；上一次执行尚未结束时返回 ErrRunning。
+// scheduled 为定时执行：启用选主时 ctx 在本实例失去主实例身份后取消，任务在批次之间停止
+func (j *job) start(scheduled bool) error {
+	j.mu.Lock()
+	if j.status.Running {
+		j.mu.Unlock()
+		return ErrRunning
+	}
+	j.status.Running = true
+	j.mu.Unlock()
+
+	if !background.Go(func() { j.execute(scheduled) }) {
+		j.mu.Lock()
+		j.status.Running = false
+		j.mu.Unlock()
+		return ErrShuttingDown
+	}
+	return nil
+}
+
+func (j *job) execute(scheduled bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), runTimeout)
+	defer cancel()
+	if scheduled && elector != nil {
+		ctx, cancel = elector.LeaderContext(ctx)
+		defer cancel()
+	}
+	started := time.Now()
+	result, err := j.run(ctx)
+	if cause := context.Cause(ctx); err != nil && errors.Is(cause, election.ErrNotLeader) {
+		err = fmt.Errorf("stopped: %w", cause)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Running = false
+	j.status.Runs++
+	j.status.LastStart = &started
+	j.status.LastDuration = time.Since(started).Milliseconds()
+	j.status.LastResult = result
+	j.status.LastError = ""
+	if err != nil {
+		j.status.Failures++
+		j.status.LastError = err.Error()
+		log.Printf("Job %s failed: %v", j.name, err)
+	}
+}
+
+// Run 立即执行一次任务（不论是否启用、本实例是否为主实例），不影响定时安排
+func Run(name string) error {
+	j := lookup(name)
+	if j == nil {
+		return ErrNotFound
+	}
+	return j.start(false)
+}
+
+// List 按注册顺序列出全部任务的状态
+func List() []Status {
+	tasks := config.Get().Jobs.Tasks()
+	list := make([]Status, 0, len(jobs))
+	for _, j := range jobs {
+		j.mu.Lock()
+		s := j.status
+		j.mu.Unlock()
+		s.Name, s.Description = j.name, j.description
+		if task := tasks[j.name]; task != nil {
+			s.Enabled = task.Enabled
+			s.Interval = task.Interval.Std().String()
+		}
+		if !s.Enabled {
+			s.NextRun = nil
+		}
+		list = append(list, s)
+	}
+	return list
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm/clause"
+)
+
+// seedSharePassword 示例数据中受密码保护的分享的访问密码
+const seedSharePassword = "share123"
+
+// seedSentences 生成示例正文的句子
+var seedSentences = []string{
+	"思源笔记以块为基本单位组织内容，每个段落、标题和列表项都有独立的 ID。",
+	"分享发布后读者无需登录即可阅读，所有者可随时修改有效期或停用。",
+	"引用块会被单独发布为子分享，读者点击引用时在浮层中查看原文。",
+	"The quick brown fox jumps over the lazy dog while the build is running.",
+	"资源文件上传到对象存储，正文中的链接在发布时改写为分享地址。",
+	"定期回顾笔记能把零散的想法整理成体系，这也是双向链接的价值所在。",
+	"Markdown keeps the source readable, so diffs between revisions stay small.",
+	"阅读页根据主题切换浅色与深色配色，也可以上传自定义 CSS。",
+	"评论默认需要审核，匿名读者需要填写昵称。",
+	"Long documents benefit from a table of contents and stable heading anchors.",
+	"数据库中的正文使用 zstd 压缩，大文档也不会明显增加存储占用。",
+	"发布前钩子可以拒绝发布或改写标题，便于接入内容审查流程。",
+}
+
+// seedTitles 示例分享的标题
+var seedTitles = []string{
+	"读书笔记：深度工作", "周报 %d", "Go 并发模式速查", "旅行计划", "项目复盘", "英语单词本",
+	"Kubernetes 排障手册", "会议纪要 %d", "家庭菜谱", "论文阅读：Attention Is All You Need", "年度总结", "API 设计规范",
+}
+
+var (
+	seedTags      = []string{"读书", "工作", "技术", "生活", "Go", "随笔", "教程", "笔记"}
+	seedReferrers = []string{"", "", "www.google.com", "github.com", "twitter.com", "www.zhihu.com", "news.ycombinator.com"}
+	seedCountries = []string{"CN", "CN", "CN", "US", "JP", "DE", "SG", ""}
+	seedCommenter = []string{"路人甲", "小王", "Reader", "匿名读者", "Alice", "老张"}
+	seedComments  = []string{"写得很清楚，收藏了！", "第二节的例子能再展开讲讲吗？", "Thanks, this saved me a lot of time.", "有一处错别字：「的」应为「地」。", "期待后续更新。"}
+	seedThemes    = []string{"", "light", "dark", "auto"}
+	seedDocIDRune = []rune("abcdefghijklmnopqrstuvwxyz0123456789")
+)
+
+// seedVariant 示例分享的可见性组合，按序号轮换
+type seedVariant struct {
+	status string
+	public bool
+	listed bool
+	secret bool // 需要访问密码
+	expire bool // 已过期
+}
+
+var seedVariants = []seedVariant{
+	{status: models.ShareStatusPublished, public: true, listed: true},
+	{status: models.ShareStatusUnlisted, public: true},
+	{status: models.ShareStatusPublished, public: true, secret: true},
+	{status: models.ShareStatusDraft, public: true},
+	{status: models.ShareStatusPublished},
+	{status: models.ShareStatusDisabled, public: true},
+	{status: models.ShareStatusPublished, public: true, expire: true},
+	{status: models.ShareStatusPublished, public: true, listed: true},
+}
+
+// runSeed 执行 seed 子命令：向开发实例写入示例用户、API Token、分享（不同篇幅、主题与可见性）、评论与统计数据，
+// 便于开发前端与插件；相同的 -seed 生成相同的内容。返回进程退出码
+func runSeed(args []string) int {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	users := fs.Int("users", 3, "用户数")
+	shares := fs.Int("shares", 12, "每个用户的分享数")
+	prefix := fs.String("prefix", "demo", "用户名前缀，用户名为 <前缀><序号>")
+	password := fs.String("password", "password", "用户密码（至少6位）")
+	days := fs.Int("days", 30, "生成浏览记录与统计的天数")
+	seed := fs.Int64("seed", 1, "随机数种子")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *users < 1 || *shares < 0 || *days < 1 || *prefix == "" {
+		fmt.Fprintln(os.Stderr, "usage: seed [-users 3] [-shares 12] [-prefix demo] [-password password] [-days 30] [-seed 1]")
+		return 2
+	}
+	if len(*password) < 6 {
+		fmt.Fprintln(os.Stderr, "Password must be at least 6 characters")
+		return 2
+	}
+
+	if err := storage.Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+		return 1
+	}
+	if err := models.InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer models.CloseDB()
+
+	names := make([]string, *users)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s%d", *prefix, i+1)
+	}
+	var count int64
+	models.DB.Model(&models.User{}).Where("username IN ?", names).Count(&count)
+	if count > 0 {
+		fmt.Fprintf(os.Stderr, "Users with prefix %q already exist; use a fresh data directory or another -prefix\n", *prefix)
+		return 1
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to hash password: %v\n", err)
+		return 1
+	}
+	shareHash, err := bcrypt.GenerateFromPassword([]byte(seedSharePassword), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to hash password: %v\n", err)
+		return 1
+	}
+
+	s := &seeder{
+		rng:       rand.New(rand.NewSource(*seed)),
+		now:       time.Now(),
+		days:      *days,
+		shareHash: string(shareHash),
+		views:     config.Get().Jobs.ViewRetention > 0,
+	}
+	for _, name := range names {
+		user := &models.User{
+			ID:            "user_" + randHex(16),
+			Username:      name,
+			Email:         name + "@example.com",
+			PasswordHash:  string(hash),
+			IsActive:      true,
+			EmailVerified: true,
+		}
+		if err := models.DB.Create(user).Error; err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create user: %v\n", err)
+			return 1
+		}
+		raw, err := createToken(user.ID, "seed", nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create API token: %v\n", err)
+			return 1
+		}
+		if err := s.seedUser(user, *shares); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to seed %s: %v\n", user.Username, err)
+			return 1
+		}
+		fmt.Printf("User %s / %s  API token: %s\n", user.Username, *password, raw)
+	}
+	if err := s.seedInstanceStats(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to seed instance stats: %v\n", err)
+		return 1
+	}
+	fmt.Printf("Seeded %d users, %d shares, %d comments and %d view events (password-protected shares use %q)\n",
+		*users, s.shares, s.comments, s.viewEvents, seedSharePassword)
+	return 0
+}
+
+// seeder 生成示例数据，记录生成的数量
+type seeder struct {
+	rng       *rand.Rand
+	now       time.Time
+	days      int
+	shareHash string
+	views     bool // jobs.view_retention 为 0 时不生成浏览记录
+
+	shares, comments, viewEvents int
+}
+
+// seedUser 为用户生成自定义主题、分享、评论、浏览记录与每日接口用量
+func (s *seeder) seedUser(user *models.User, n int) error {
+	theme := &models.Theme{
+		ID:     "thm_" + randHex(8),
+		UserID: user.ID,
+		Name:   "Sepia",
+		CSS:    ":root{--share-bg:#f4ecd8;--share-text:#433422;--share-link:#8b4513;}",
+	}
+	if err := models.DB.Create(theme).Error; err != nil {
+		return err
+	}
+	themes := append(append([]string{}, seedThemes...), theme.ID)
+
+	var totalViews int64
+	for j := 0; j < n; j++ {
+		v := seedVariants[j%len(seedVariants)]
+		title := seedTitles[s.rng.Intn(len(seedTitles))]
+		if strings.Contains(title, "%d") {
+			title = fmt.Sprintf(title, j+1)
+		}
+		created := s.now.Add(-time.Duration(s.rng.Int63n(int64(s.days) * int64(24*time.Hour))))
+		share := &models.Share{
+			ID:              randHex(16),
+			UserID:          user.ID,
+			DocID:           s.docID(created),
+			DocTitle:        title,
+			Content:         s.content(title, j%3),
+			Tags:            models.EncodeTags(s.pick(seedTags, 1+s.rng.Intn(3))),
+			Theme:           themes[j%len(themes)],
+			TOC:             models.ShareTOCSide,
+			HeadingAnchors:  true,
+			IsPublic:        v.public,
+			Listed:          v.listed,
+			Status:          v.status,
+			AllowComments:   j%2 == 0,
+			AllowAnnotation: j%3 == 0,
+			AssetDownloads:  models.AssetDownloadsAllow,
+			ExpireAt:        s.now.AddDate(0, 0, 7+s.rng.Intn(90)),
+			CreatedAt:       created,
+		}
+		if v.secret {
+			share.RequirePassword = true
+			share.PasswordHash = s.shareHash
+		}
+		if v.expire {
+			share.ExpireAt = s.now.AddDate(0, 0, -1-s.rng.Intn(7))
+		}
+		if v.status != models.ShareStatusDraft {
+			share.ViewCount = s.rng.Intn(300)
+		}
+		if err := models.DB.Create(share).Error; err != nil {
+			return err
+		}
+		// is_public 带数据库默认值 true，创建时不会写入零值
+		if !v.public {
+			if err := models.DB.Model(share).Update("is_public", false).Error; err != nil {
+				return err
+			}
+		}
+		if err := models.SyncShareIndex(models.DB, share); err != nil {
+			return err
+		}
+		s.shares++
+		totalViews += int64(share.ViewCount)
+
+		if share.AllowComments && share.ViewCount > 0 {
+			if err := s.seedComments(share); err != nil {
+				return err
+			}
+		}
+		if s.views && share.ViewCount > 0 {
+			if err := s.seedViews(share); err != nil {
+				return err
+			}
+		}
+	}
+	return s.seedUsage(user, totalViews)
+}
+
+// seedComments 生成已公开与待审核的评论，登录读者的评论署名为所有者本人
+func (s *seeder) seedComments(share *models.Share) error {
+	for k := s.rng.Intn(5); k >= 0; k-- {
+		c := &models.Comment{
+			ID:        "cmt_" + randHex(10),
+			ShareID:   share.ID,
+			Author:    seedCommenter[s.rng.Intn(len(seedCommenter))],
+			Content:   seedComments[s.rng.Intn(len(seedComments))],
+			Status:    models.CommentStatusApproved,
+			IP:        "127.0.0.1",
+			CreatedAt: s.between(share.CreatedAt, s.now),
+		}
+		if k%3 == 2 {
+			c.Status = models.CommentStatusPending
+		}
+		if err := models.DB.Create(c).Error; err != nil {
+			return err
+		}
+		s.comments++
+	}
+	return nil
+}
+
+// seedViews 按分享的浏览次数生成原始浏览记录，分布在发布之后
+func (s *seeder) seedViews(share *models.Share) error {
+	views := make([]models.ShareView, share.ViewCount)
+	for k := range views {
+		views[k] = models.ShareView{
+			ShareID:  share.ID,
+			ViewedAt: s.between(share.CreatedAt, s.now),
+			Referrer: seedReferrers[s.rng.Intn(len(seedReferrers))],
+			Country:  seedCountries[s.rng.Intn(len(seedCountries))],
+		}
+	}
+	s.viewEvents += len(views)
+	return models.DB.CreateInBatches(views, 500).Error
+}
+
+// seedUsage 生成用户最近几天的接口用量，浏览量按天平均分配
+func (s *seeder) seedUsage(user *models.User, views int64) error {
+	rows := make([]models.APIUsage, 0, s.days)
+	for d := 0; d < s.days; d++ {
+		requests := int64(10 + s.rng.Intn(200))
+		rows = append(rows, models.APIUsage{
+			Date:      s.now.AddDate(0, 0, -d).Format(time.DateOnly),
+			UserID:    user.ID,
+			Requests:  requests,
+			Publishes: int64(s.rng.Intn(5)),
+			Views:     views / int64(s.days),
+			Bytes:     requests * int64(2048+s.rng.Intn(8192)),
+		})
+	}
+	return models.DB.Clauses(clause.OnConflict{UpdateAll: true}).CreateInBatches(rows, 500).Error
+}
+
+// seedInstanceStats 以当前指标为终点生成逐日增长的实例统计
+func (s *seeder) seedInstanceStats() error {
+	cur, err := models.CollectInstanceStats()
+	if err != nil {
+		return err
+	}
+	for d := s.days - 1; d >= 0; d-- {
+		f := func(v int64) int64 { return v * int64(s.days-d) / int64(s.days) }
+		st := models.InstanceStat{
+			Date:         s.now.AddDate(0, 0, -d).Format(time.DateOnly),
+			Users:        max(f(cur.Users), 1),
+			Shares:       f(cur.Shares),
+			Views:        f(cur.Views),
+			Assets:       f(cur.Assets),
+			StorageBytes: f(cur.StorageBytes),
+		}
+		if err := models.DB.Clauses(clause.OnConflict{UpdateAll: true}).Create(&st).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// content 生成示例正文，size 为 0（短）、1（中）、2（长）
+func (s *seeder) content(title string, size int) string {
+	sections := []int{1, 5, 60}[size]
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	for i := 1; i <= sections; i++ {
+		fmt.Fprintf(&b, "## 第 %d 节\n\n", i)
+		for p := 0; p < 1+s.rng.Intn(3); p++ {
+			b.WriteString(strings.Join(s.pick(seedSentences, 2+s.rng.Intn(4)), ""))
+			b.WriteString("\n\n")
+		}
+		switch (i + size) % 6 {
+		case 1:
+			b.WriteString("- [x] 整理资料\n- [ ] 撰写初稿\n- [ ] 请同事审阅\n\n")
+		case 2:
+			b.WriteString("```go\nfunc main() {\n\tfmt.Println(\"hello, siyuan\")\n}\n```\n\n")
+		case 3:
+			b.WriteString("| 项目 | 进度 | 负责人 |\n| --- | --- | --- |\n| 前端 | 80% | 小王 |\n| 插件 | 50% | 老张 |\n\n")
+		case 4:
+			b.WriteString("> 好的笔记不是记下了什么，而是之后还能找到并用上它。\n\n")
+		case 5:
+			b.WriteString("$$\nE = mc^2\n$$\n\n")
+		}
+	}
+	return b.String()
+}
+
+// docID 生成思源文档 ID（创建时间 + 7 位随机字符）
+func (s *seeder) docID(t time.Time) string {
+	r := make([]rune, 7)
+	for i := range r {
+		r[i] = seedDocIDRune[s.rng.Intn(len(seedDocIDRune))]
+	}
+	return t.Format("20060102150405") + "-" + string(r)
+}
+
+// pick 不重复地随机选取 n 个元素
+func (s *seeder) pick(items []string, n int) []string {
+	idx := s.rng.Perm(len(items))
+	out := make([]string, 0, n)
+	for _, i := range idx[:min(n, len(items))] {
+		out = append(out, items[i])
+	}
+	return out
+}
+
+// between 返回 [from, to) 之间的随机时间
+func (s *seeder) between(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(s.rng.Int63n(int64(to.Sub(from)))))
+}
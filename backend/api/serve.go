@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/cache"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/events"
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/geoip"
+	"github.com/ZeroHawkeye/siyuan-share-api/hooks"
+	"github.com/ZeroHawkeye/siyuan-share-api/idgen"
+	"github.com/ZeroHawkeye/siyuan-share-api/logging"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/notify"
+	"github.com/ZeroHawkeye/siyuan-share-api/reload"
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+	"github.com/ZeroHawkeye/siyuan-share-api/routes"
+	"github.com/ZeroHawkeye/siyuan-share-api/scheduler"
+	"github.com/ZeroHawkeye/siyuan-share-api/signing"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"github.com/ZeroHawkeye/siyuan-share-api/webpush"
+	"github.com/gin-gonic/gin"
+)
+
+// runServe 执行 serve 子命令：初始化各组件并启动服务，收到 SIGINT / SIGTERM 后优雅退出
+func runServe(args []string) int {
+	if len(args) > 0 {
+		usage()
+		return 2
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	// 收到第一个信号后恢复默认处理，优雅退出期间再次按 Ctrl+C 可立即结束
+	context.AfterFunc(ctx, stop)
+	defer stop()
+	return serve(ctx)
+}
+
+// serve 初始化各组件并启动服务，ctx 结束后优雅退出（收到退出信号或 Windows 服务管理器的停止请求）
+func serve(ctx context.Context) int {
+	cfg := config.Get()
+
+	// 测试模式：数据目录使用临时目录并在退出时删除，ID 与 Token 按种子确定性生成
+	if cfg.Server.TestMode {
+		dir, err := os.MkdirTemp("", "siyuan-share-test-")
+		if err != nil {
+			log.Fatalf("Failed to create test data directory: %v", err)
+		}
+		defer os.RemoveAll(dir)
+		cfg.Server.DataDir = dir
+		idgen.Seed(cfg.Server.TestSeed)
+		log.Printf("Test mode enabled: in-memory database, data directory %s, seed %d", dir, cfg.Server.TestSeed)
+	}
+
+	// 初始化资源存储（本地磁盘或 S3）
+	if err := storage.Init(); err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// 初始化热点分享缓存（进程内或 Redis）
+	if err := cache.Init(); err != nil {
+		log.Fatalf("Failed to initialize cache: %v", err)
+	}
+
+	// 初始化数据库
+	if err := models.InitDB(); err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	// 加载内容签名密钥，并为签名功能上线前的版本补签
+	if err := signing.Init(); err != nil {
+		log.Fatalf("Failed to load signing key: %v", err)
+	}
+	models.SignRevisions()
+
+	// 加载 Web Push 的 VAPID 密钥（不可用时仅禁用浏览器推送）
+	if err := webpush.Init(); err != nil {
+		log.Printf("Web Push disabled: %v", err)
+	}
+
+	// 加载国家/地区 IP 数据库
+	if err := geoip.Init(); err != nil {
+		log.Fatalf("Failed to load geo database: %v", err)
+	}
+
+	// 注册外部渲染插件
+	if err := renderer.Init(); err != nil {
+		log.Fatalf("Failed to initialize renderers: %v", err)
+	}
+
+	// 注册配置中的外部 HTTP 钩子
+	if err := hooks.Init(); err != nil {
+		log.Fatalf("Failed to initialize hooks: %v", err)
+	}
+
+	// 启动订阅通知后台任务
+	notify.Start()
+
+	// 启动导出后台任务
+	export.Start()
+
+	// 启动定时任务（过期分享清理、孤立资源回收、WAL 检查点、指标汇总、钩子重试），多实例时只在主实例上执行
+	if err := scheduler.Start(); err != nil {
+		log.Fatalf("Failed to start scheduler: %v", err)
+	}
+
+	// 移除引导令牌流程：用户通过注册与个人中心管理 Token
+
+	// 设置 Gin 模式
+	gin.SetMode(cfg.Server.Mode)
+
+	// 创建路由
+	r := routes.SetupRouter(&staticFiles)
+
+	// 启动服务器（配置 tls.domains 时直接提供 HTTPS 并自动申请证书）
+	servers := newServers(cfg, r)
+	for _, srv := range servers {
+		go func(srv *http.Server) {
+			log.Printf("Server starting on %s (tls=%v)...", srv.Addr, srv.TLSConfig != nil)
+			if err := listen(srv); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("Failed to start server: %v", err)
+			}
+		}(srv)
+	}
+
+	// 收到 SIGHUP 后重新加载配置，不中断已有连接
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if _, err := reload.Run(); err != nil {
+				log.Printf("Config reload failed, keeping current configuration: %v", err)
+			}
+		}
+	}()
+	// 定时重新读取密钥文件与 Vault，密钥轮换后自动重新加载配置
+	if interval := config.Get().Secrets.RefreshInterval.Std(); interval > 0 {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for range ticker.C {
+				if _, err := reload.RefreshSecrets(); err != nil {
+					log.Printf("Secrets refresh failed, keeping current configuration: %v", err)
+				}
+			}
+		}()
+	}
+
+	// 以 systemd 服务（Type=notify）运行时报告已就绪
+	notifySystemd("READY=1")
+
+	// 优雅退出：停止接受新连接，等待进行中的请求与后台任务，最后关闭数据库
+	<-ctx.Done()
+	signal.Stop(hup)
+	notifySystemd("STOPPING=1")
+	timeout := config.Get().Server.ShutdownTimeout.Std()
+	log.Printf("Shutting down (timeout %s)...", timeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	// 先关闭控制面板的事件流，长连接不会拖延退出
+	events.Close()
+	for _, srv := range servers {
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("HTTP server shutdown (%s): %v", srv.Addr, err)
+		}
+	}
+	if err := background.Drain(shutdownCtx); err != nil {
+		log.Printf("Background tasks not finished before timeout: %v", err)
+	}
+	// 进行中的任务结束后释放选主租约，其他实例立即接管定时任务
+	scheduler.Stop()
+	if err := models.CloseDB(); err != nil {
+		log.Printf("Failed to close database: %v", err)
+	}
+	log.Println("Server stopped")
+	logging.Close()
+	return 0
+}
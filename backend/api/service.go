@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// defaultServiceName 默认的服务名称，同时作为 Linux 下的数据目录名与 Windows 事件日志来源
+const defaultServiceName = "siyuan-share"
+
+// serviceOptions service install 的参数
+type serviceOptions struct {
+	name    string
+	exe     string   // 可执行文件的绝对路径
+	config  string   // 配置文件的绝对路径，未使用配置文件时为空
+	dataDir string   // 数据目录，写入服务环境变量 DATA_DIR
+	user    string   // 运行服务的系统用户（仅 systemd）
+	env     []string // 额外的环境变量 KEY=VALUE
+	start   bool     // 安装后立即启动
+}
+
+// environment 服务进程的环境变量：数据目录、系统日志输出与 -env 指定的变量
+func (o *serviceOptions) environment(logOutput string) []string {
+	env := []string{"DATA_DIR=" + o.dataDir}
+	if logOutput != "" {
+		env = append(env, "LOG_OUTPUT="+logOutput, "LOG_TAG="+o.name)
+	}
+	return append(env, o.env...)
+}
+
+// envFlag 可重复的 -env KEY=VALUE 参数
+type envFlag []string
+
+func (e *envFlag) String() string { return strings.Join(*e, ",") }
+
+func (e *envFlag) Set(v string) error {
+	if k, _, ok := strings.Cut(v, "="); !ok || k == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", v)
+	}
+	*e = append(*e, v)
+	return nil
+}
+
+// runService 执行 service 子命令：install 安装为 systemd 服务或 Windows 服务并启动，uninstall 停止并移除服务，
+// run 由 Windows 服务管理器调用以服务方式运行；返回进程退出码
+func runService(args []string) int {
+	usage := "usage: service install [-name siyuan-share] [-data-dir <dir>] [-user <user>] [-env KEY=VALUE]... [-no-start]  |  service uninstall [-name siyuan-share]"
+	if len(args) == 0 || (args[0] != "install" && args[0] != "uninstall" && args[0] != "run") {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("service "+args[0], flag.ContinueOnError)
+	name := fs.String("name", defaultServiceName, "服务名称")
+	dataDir := fs.String("data-dir", "", "数据目录，默认 Linux 为 /var/lib/<服务名称>，Windows 为 %ProgramData%\\<服务名称>")
+	user := fs.String("user", "", "运行服务的系统用户（仅 systemd），默认 root")
+	noStart := fs.Bool("no-start", false, "安装后不立即启动")
+	var env envFlag
+	fs.Var(&env, "env", "服务进程的环境变量 KEY=VALUE，可重复")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *name == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "run":
+		return runAsService(*name)
+	case "uninstall":
+		if err := uninstallService(*name); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to uninstall service: %v\n", err)
+			return 1
+		}
+		fmt.Fprintf(os.Stderr, "Service %s removed; the data directory is kept\n", *name)
+		return 0
+	}
+
+	opts := &serviceOptions{name: *name, dataDir: *dataDir, user: *user, env: env, start: !*noStart}
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to locate executable: %v\n", err)
+		return 1
+	}
+	opts.exe = exe
+	// 服务不在当前目录运行，配置文件与数据目录使用绝对路径
+	if src := config.Get().Source(); src != "" {
+		if opts.config, err = filepath.Abs(src); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid config path: %v\n", err)
+			return 1
+		}
+	}
+	if opts.dataDir == "" {
+		opts.dataDir = defaultServiceDataDir(opts.name)
+	}
+	if opts.dataDir, err = filepath.Abs(opts.dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid data directory: %v\n", err)
+		return 1
+	}
+	if err := installService(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to install service: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Service %s installed (data directory %s)\n", opts.name, opts.dataDir)
+	return 0
+}
+
+// notifySystemd 向 systemd 报告服务状态（sd_notify 协议），未以 Type=notify 服务运行时忽略
+func notifySystemd(state string) {
+	addr := os.Getenv("NOTIFY_SOCKET")
+	if addr == "" {
+		return
+	}
+	// @ 开头为 Linux 抽象命名空间套接字
+	if strings.HasPrefix(addr, "@") {
+		addr = "\x00" + addr[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.Write([]byte(state))
+}
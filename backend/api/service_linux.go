@@ -0,0 +1,133 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// systemdUnitDir systemd 单元文件目录
+const systemdUnitDir = "/etc/systemd/system"
+
+// defaultServiceDataDir systemd 服务的默认数据目录
+func defaultServiceDataDir(name string) string {
+	return filepath.Join("/var/lib", name)
+}
+
+// installService 写入 /etc/systemd/system/<名称>.service，创建数据目录并启用服务
+func installService(o *serviceOptions) error {
+	path := filepath.Join(systemdUnitDir, o.name+".service")
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists; run service uninstall first", path)
+	}
+	if err := os.MkdirAll(o.dataDir, 0o750); err != nil {
+		return err
+	}
+	if o.user != "" {
+		u, err := user.Lookup(o.user)
+		if err != nil {
+			return err
+		}
+		uid, _ := strconv.Atoi(u.Uid)
+		gid, _ := strconv.Atoi(u.Gid)
+		if err := os.Chown(o.dataDir, uid, gid); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(path, []byte(systemdUnit(o)), 0o644); err != nil {
+		return err
+	}
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	if o.start {
+		return systemctl("enable", "--now", o.name)
+	}
+	return systemctl("enable", o.name)
+}
+
+// systemdUnit 生成单元文件：Type=notify（启动完成后报告就绪），systemctl reload 发送 SIGHUP 重新加载配置，
+// 日志输出到标准输出由 journald 收集
+func systemdUnit(o *serviceOptions) string {
+	cmd := []string{systemdQuote(o.exe)}
+	if o.config != "" {
+		cmd = append(cmd, "-config", systemdQuote(o.config))
+	}
+	cmd = append(cmd, "serve")
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=SiYuan Share\n")
+	b.WriteString("After=network-online.target\n")
+	b.WriteString("Wants=network-online.target\n\n")
+	b.WriteString("[Service]\n")
+	b.WriteString("Type=notify\n")
+	b.WriteString("ExecStart=" + strings.Join(cmd, " ") + "\n")
+	b.WriteString("ExecReload=/bin/kill -HUP $MAINPID\n")
+	b.WriteString("WorkingDirectory=" + strings.ReplaceAll(o.dataDir, "%", "%%") + "\n")
+	for _, kv := range o.environment("") {
+		b.WriteString("Environment=" + systemdQuote(kv) + "\n")
+	}
+	if o.user != "" {
+		b.WriteString("User=" + o.user + "\n")
+	}
+	// 留出优雅退出的时间 (server.shutdown_timeout)
+	fmt.Fprintf(&b, "TimeoutStopSec=%d\n", int((config.Get().Server.ShutdownTimeout.Std() + 10*time.Second).Seconds()))
+	b.WriteString("Restart=on-failure\n")
+	b.WriteString("RestartSec=5s\n")
+	b.WriteString("NoNewPrivileges=true\n")
+	b.WriteString("ProtectSystem=full\n")
+	b.WriteString("PrivateTmp=true\n\n")
+	b.WriteString("[Install]\n")
+	b.WriteString("WantedBy=multi-user.target\n")
+	return b.String()
+}
+
+// systemdQuote 含空白、引号或反斜杠的值加双引号，% 转义为 %%
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// uninstallService 停止并禁用服务，删除单元文件；数据目录保留
+func uninstallService(name string) error {
+	path := filepath.Join(systemdUnitDir, name+".service")
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("%s not found", path)
+	}
+	if err := systemctl("disable", "--now", name); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return systemctl("daemon-reload")
+}
+
+// runAsService 仅用于 Windows；systemd 直接以 serve 子命令启动服务
+func runAsService(name string) int {
+	fmt.Fprintln(os.Stderr, "service run is only used by the Windows service manager; systemd starts the serve command")
+	return 2
+}
+
+func systemctl(args ...string) error {
+	cmd := exec.Command("systemctl", args...)
+	cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("systemctl %s: %w", strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+//go:build !linux && !windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+var errServiceUnsupported = errors.New("service install is supported on Linux (systemd) and Windows")
+
+func defaultServiceDataDir(name string) string {
+	return "data"
+}
+
+func installService(o *serviceOptions) error {
+	return errServiceUnsupported
+}
+
+func uninstallService(name string) error {
+	return errServiceUnsupported
+}
+
+func runAsService(name string) int {
+	fmt.Fprintln(os.Stderr, errServiceUnsupported)
+	return 2
+}
@@ -0,0 +1,171 @@
+//go:build windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/eventlog"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// defaultServiceDataDir Windows 服务的默认数据目录 %ProgramData%\<服务名称>
+func defaultServiceDataDir(name string) string {
+	base := os.Getenv("ProgramData")
+	if base == "" {
+		base = `C:\ProgramData`
+	}
+	return filepath.Join(base, name)
+}
+
+// installService 注册自动启动的 Windows 服务（以 LocalSystem 运行，失败后自动重启），写入服务环境变量，
+// 并注册同名的事件日志来源，服务日志写入 Windows 事件日志（应用程序）
+func installService(o *serviceOptions) error {
+	if o.user != "" {
+		return errors.New("-user is only supported with systemd; change the service account in services.msc")
+	}
+	if err := os.MkdirAll(o.dataDir, 0o750); err != nil {
+		return err
+	}
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	if s, err := m.OpenService(o.name); err == nil {
+		s.Close()
+		return fmt.Errorf("service %s already exists; run service uninstall first", o.name)
+	}
+
+	var args []string
+	if o.config != "" {
+		args = append(args, "-config", o.config)
+	}
+	args = append(args, "service", "run", "-name", o.name)
+	s, err := m.CreateService(o.name, o.exe, mgr.Config{
+		DisplayName: "SiYuan Share (" + o.name + ")",
+		Description: "SiYuan Share server",
+		StartType:   mgr.StartAutomatic,
+	}, args...)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	if err := setServiceEnvironment(o.name, o.environment("eventlog")); err != nil {
+		s.Delete()
+		return err
+	}
+	if err := eventlog.InstallAsEventCreate(o.name, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		s.Delete()
+		return fmt.Errorf("register event log source: %w", err)
+	}
+	if err := s.SetRecoveryActions([]mgr.RecoveryAction{
+		{Type: mgr.ServiceRestart, Delay: 5 * time.Second},
+		{Type: mgr.ServiceRestart, Delay: 30 * time.Second},
+	}, uint32((24 * time.Hour).Seconds())); err != nil {
+		log.Printf("Failed to set service recovery actions: %v", err)
+	}
+	if o.start {
+		return s.Start()
+	}
+	return nil
+}
+
+// setServiceEnvironment 写入服务注册表项的 Environment 值，服务进程启动时附加这些环境变量
+func setServiceEnvironment(name string, env []string) error {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+name, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	return key.SetStringsValue("Environment", env)
+}
+
+// uninstallService 停止并删除服务，移除事件日志来源；数据目录保留
+func uninstallService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return err
+	}
+	defer m.Disconnect()
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("service %s not found", name)
+	}
+	defer s.Close()
+	if status, err := s.Control(svc.Stop); err == nil {
+		// 等待优雅退出完成后再删除
+		deadline := time.Now().Add(config.Get().Server.ShutdownTimeout.Std() + 10*time.Second)
+		for status.State != svc.Stopped && time.Now().Before(deadline) {
+			time.Sleep(500 * time.Millisecond)
+			if status, err = s.Query(); err != nil {
+				break
+			}
+		}
+	}
+	if err := s.Delete(); err != nil {
+		return err
+	}
+	if err := eventlog.Remove(name); err != nil {
+		log.Printf("Failed to remove event log source: %v", err)
+	}
+	return nil
+}
+
+// runAsService 以 Windows 服务方式运行：工作目录切换到数据目录，收到停止或关机请求后优雅退出
+func runAsService(name string) int {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		fmt.Fprintln(os.Stderr, "service run must be started by the Windows service manager; use service install")
+		return 2
+	}
+	// 服务进程的工作目录为 System32，相对路径以数据目录为准
+	dataDir := config.Get().Server.DataDir
+	if err := os.MkdirAll(dataDir, 0o750); err == nil {
+		os.Chdir(dataDir)
+	}
+	h := &windowsService{}
+	if err := svc.Run(name, h); err != nil {
+		log.Printf("Windows service failed: %v", err)
+		return 1
+	}
+	return h.code
+}
+
+// windowsService 实现 svc.Handler，serve 在 Execute 中运行
+type windowsService struct {
+	code int
+}
+
+func (h *windowsService) Execute(_ []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan int, 1)
+	go func() { done <- serve(ctx) }()
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+	for {
+		select {
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+			}
+		case h.code = <-done:
+			// 非零退出码作为服务自定义错误码报告
+			return h.code != 0, uint32(h.code)
+		}
+	}
+}
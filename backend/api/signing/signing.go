@@ -0,0 +1,134 @@
+// Package signing 以实例密钥（Ed25519）为发布的每个版本签名，读者可用实例公钥离线校验
+// 下载的副本与发布时的内容一致。
+package signing
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// Algorithm 签名算法
+const Algorithm = "ed25519"
+
+// statementPrefix 签名声明的首行，区分格式版本
+const statementPrefix = "siyuan-share-signature-v1"
+
+// Key 实例签名密钥（公钥与私钥种子均为 base64url 编码）
+type Key struct {
+	ID         string `json:"keyId"`
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+// Default 全局签名密钥，由 Init 初始化；为 nil 时不签名
+var Default *Key
+
+// Init 加载签名密钥：优先使用配置中的 signing.private_key，否则读取 data_dir/signing.json，
+// 不存在时自动生成并持久化，保证重启后已发布版本的签名仍可校验
+func Init() error {
+	cfg := config.Get()
+	if seed := cfg.Signing.PrivateKey; seed != "" {
+		key, err := parseKey(seed)
+		if err != nil {
+			return err
+		}
+		Default = key
+		return nil
+	}
+
+	path := filepath.Join(cfg.Server.DataDir, "signing.json")
+	if data, err := os.ReadFile(path); err == nil {
+		var stored Key
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return errors.New("signing: invalid " + path + ": " + err.Error())
+		}
+		key, err := parseKey(stored.PrivateKey)
+		if err != nil {
+			return err
+		}
+		Default = key
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	seed := make([]byte, ed25519.SeedSize)
+	if _, err := rand.Read(seed); err != nil {
+		return err
+	}
+	key, err := parseKey(base64.RawURLEncoding.EncodeToString(seed))
+	if err != nil {
+		return err
+	}
+	data, _ := json.MarshalIndent(key, "", "  ")
+	if err := os.MkdirAll(cfg.Server.DataDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	log.Printf("Generated signing key %s at %s", key.ID, path)
+	Default = key
+	return nil
+}
+
+func parseKey(seed string) (*Key, error) {
+	b, err := base64.RawURLEncoding.DecodeString(seed)
+	if err != nil || len(b) != ed25519.SeedSize {
+		return nil, errors.New("signing: invalid private key")
+	}
+	private := ed25519.NewKeyFromSeed(b)
+	public := private.Public().(ed25519.PublicKey)
+	sum := sha256.Sum256(public)
+	return &Key{
+		ID:         hex.EncodeToString(sum[:8]),
+		PublicKey:  base64.RawURLEncoding.EncodeToString(public),
+		PrivateKey: seed,
+		private:    private,
+		public:     public,
+	}, nil
+}
+
+// PublicKeyPEM 公钥的 PEM（SubjectPublicKeyInfo）编码，便于用 openssl 校验
+func (k *Key) PublicKeyPEM() string {
+	der, err := x509.MarshalPKIXPublicKey(k.public)
+	if err != nil {
+		return ""
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// Sign 对声明签名，返回 base64url 编码的签名
+func (k *Key) Sign(statement string) string {
+	return base64.RawURLEncoding.EncodeToString(ed25519.Sign(k.private, []byte(statement)))
+}
+
+// Verify 校验签名是否由本密钥对声明签出
+func (k *Key) Verify(statement, signature string) bool {
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	return ed25519.Verify(k.public, []byte(statement), sig)
+}
+
+// Statement 被签名的声明：分享 ID、版本号与正文 Markdown 源文的 SHA-256（十六进制），每项一行，以换行结尾
+func Statement(shareID string, version int, contentHash string) string {
+	return fmt.Sprintf("%s\nshare: %s\nversion: %d\nsha256: %s\n", statementPrefix, shareID, version, contentHash)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/export"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// runSite 执行 site export 子命令：将全部公开收录的分享导出为静态 HTML 站点。
+// 目标以 .zip 结尾时写为压缩包，为 - 时将压缩包写到标准输出，否则写入目录（须为空目录或不存在）；返回进程退出码
+func runSite(args []string) int {
+	usage := "usage: site export [-title <标题>] [-base-url <原站点地址>] <dir|file.zip|->"
+	if len(args) == 0 || args[0] != "export" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fs := flag.NewFlagSet("site export", flag.ContinueOnError)
+	title := fs.String("title", "", "首页标题（默认「思源分享」）")
+	baseURL := fs.String("base-url", "", "原站点地址（如 https://share.example.com），页脚链接到原分享；为空时不输出原文链接")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	target := fs.Arg(0)
+	opts := export.SiteOptions{Title: *title, BaseURL: *baseURL}
+
+	if err := storage.Init(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize storage: %v\n", err)
+		return 1
+	}
+	if err := models.OpenDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open database: %v\n", err)
+		return 1
+	}
+	defer models.CloseDB()
+
+	var summary *export.SiteSummary
+	var err error
+	switch {
+	case target == "-" || strings.HasSuffix(strings.ToLower(target), ".zip"):
+		var w io.Writer = os.Stdout
+		if target != "-" {
+			f, ferr := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+			if ferr != nil {
+				fmt.Fprintf(os.Stderr, "Failed to create archive: %v\n", ferr)
+				return 1
+			}
+			defer f.Close()
+			w = f
+		}
+		summary, err = export.WriteSiteZip(ctx, w, opts)
+		if err != nil && target != "-" {
+			os.Remove(target)
+		}
+	default:
+		if entries, rerr := os.ReadDir(target); rerr == nil && len(entries) > 0 {
+			fmt.Fprintf(os.Stderr, "Target directory %s is not empty\n", target)
+			return 1
+		}
+		summary, err = export.Site(ctx, opts, func(name string, data []byte) error {
+			// 文件名来自分享 ID 与资源路径，写入前确认不会越出目标目录
+			p := filepath.Join(target, filepath.FromSlash(name))
+			if rel, rerr := filepath.Rel(target, p); rerr != nil || strings.HasPrefix(rel, "..") {
+				return fmt.Errorf("invalid path %q", name)
+			}
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				return err
+			}
+			return os.WriteFile(p, data, 0644)
+		})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Export failed: %v\n", err)
+		return 1
+	}
+	for _, w := range summary.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d shares and %d assets (%d bytes), %d warnings\n", summary.Shares, summary.Assets, summary.Bytes, len(summary.Warnings))
+	return 0
+}
@@ -0,0 +1,119 @@
+// Package slug 标题的规范化与由标题生成地址标识：标题统一为 NFC 形式并去除控制字符与不可见的格式字符，
+// 地址标识只含小写 ASCII 字母、数字与连字符。中文、日文等无法转写为拉丁字母的标题以内容哈希代替，
+// 保证不同标题得到不同且稳定的标识，而不是空字符串或一串连字符。
+package slug
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// latinFolds 分解后不含基本字母的拉丁字母
+var latinFolds = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'œ': "oe", 'Œ': "oe", 'ø': "o", 'Ø': "o",
+	'đ': "d", 'Đ': "d", 'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th", 'ð': "d", 'Ð': "d", 'ı': "i",
+}
+
+// Title 规范化标题：统一为 NFC（组合字符与预组字符一致），去除控制字符、零宽空格与双向文本控制符等不可见字符，
+// 连续空白合并为一个空格。保留全角标点（不做 NFKC 兼容分解），零宽连接符保留以免拆散组合表情
+func Title(s string) string {
+	s = norm.NFC.String(strings.ToValidUTF8(s, ""))
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u200d':
+			return r
+		case unicode.IsSpace(r):
+			return ' '
+		case unicode.Is(unicode.Cc, r), unicode.Is(unicode.Cf, r):
+			return -1
+		}
+		return r
+	}, s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// Truncate 按字符（而非字节）截断，不会截断多字节字符
+func Truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:n]))
+}
+
+// FromContent 由 Markdown 正文的第一行文字生成标题（去除标题、列表与引用标记），用于未提供标题的文档
+func FromContent(content string, n int) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "```") || strings.HasPrefix(line, "{:") || strings.HasPrefix(line, "<!--") {
+			continue
+		}
+		line = strings.TrimLeft(line, "#>-*+ \t")
+		line = strings.NewReplacer("**", "", "__", "", "`", "", "~~", "").Replace(line)
+		if line = Title(line); line != "" {
+			return Truncate(line, n)
+		}
+	}
+	return ""
+}
+
+// FileName 由标题生成下载文件名（不含扩展名）：保留中文等字符，替换文件名中不允许的字符，标题为空时使用 fallback
+func FileName(title, fallback string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, Title(title))
+	name = strings.Trim(Truncate(name, 80), ". ")
+	if name == "" {
+		return fallback
+	}
+	return name
+}
+
+// Make 由标题生成地址标识，最长 max 个字符：拉丁字母去除重音，其余字母与数字之外的字符视为分隔符。
+// 标题含汉字、假名等不能转写的文字时附加 8 位内容哈希（全部为此类文字时只有哈希），如
+// “Go 语言入门” 生成 go-f6b0eb0a，“思源笔记” 生成 8 位哈希
+func Make(title string, max int) string {
+	title = Title(title)
+	var b strings.Builder
+	untransliterated := false
+	pendingDash := false
+	write := func(s string) {
+		if pendingDash && b.Len() > 0 {
+			b.WriteByte('-')
+		}
+		pendingDash = false
+		b.WriteString(s)
+	}
+	// NFKD：全角字母与数字转为 ASCII，带重音的字母分解为基本字母与组合符号
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(unicode.ToLower(r)))
+		case latinFolds[r] != "":
+			write(latinFolds[r])
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			untransliterated = true
+			pendingDash = true
+		default:
+			pendingDash = true
+		}
+	}
+	out := b.String()
+	if !untransliterated && out != "" {
+		return strings.Trim(Truncate(out, max), "-")
+	}
+	sum := sha256.Sum256([]byte(title))
+	hash := hex.EncodeToString(sum[:4])
+	if out = strings.Trim(Truncate(out, max-len(hash)-1), "-"); out == "" {
+		return hash
+	}
+	return out + "-" + hash
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// unixPeerAddr 经 Unix 套接字连接的请求使用的远端地址：对端只能是本机进程（通常是反向代理），
+// 按本机地址参与可信代理判断，客户端 IP 取自代理转发的请求头
+const unixPeerAddr = "127.0.0.1:0"
+
+// listenUnix 在 Unix 套接字上监听：清理上次未正常退出遗留的套接字文件，并按 mode 设置权限
+func listenUnix(path, mode string) (net.Listener, error) {
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid socket mode %q", mode)
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode()&os.ModeSocket == 0 {
+			return nil, fmt.Errorf("%s exists and is not a socket", path)
+		}
+		// 仍有服务在监听时不能删除
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("%s is in use by another process", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, os.FileMode(perm)); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return ln, nil
+}
+
+// unixPeer 为 Unix 套接字上的请求补上本机远端地址（Go 对 Unix 套接字连接给出的远端地址为空或 @）
+func unixPeer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.RemoteAddr = unixPeerAddr
+		next.ServeHTTP(w, r)
+	})
+}
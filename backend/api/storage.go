@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+)
+
+// runStorage 执行 storage migrate 子命令：将存储对象从一个后端按原键复制到另一个后端（local / s3），
+// 完成后将 storage.driver 切换为目标后端即可，资源地址与数据库中的存储键不变；返回进程退出码
+func runStorage(args []string) int {
+	usage := "usage: storage migrate -from <local|s3> -to <local|s3> [-dry-run] [-overwrite] [-delete-source]"
+	if len(args) == 0 || args[0] != "migrate" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fs := flag.NewFlagSet("storage migrate", flag.ContinueOnError)
+	from := fs.String("from", config.Get().Storage.Driver, "源存储后端（默认为当前的 storage.driver）")
+	to := fs.String("to", "", "目标存储后端")
+	var opts storage.MigrateOptions
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "只统计需要复制的对象，不写入")
+	fs.BoolVar(&opts.Overwrite, "overwrite", false, "目标中已有同样大小的对象时仍重新复制")
+	fs.BoolVar(&opts.DeleteSource, "delete-source", false, "复制成功后删除源对象")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *to == "" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	if normalizeDriver(*from) == normalizeDriver(*to) {
+		fmt.Fprintln(os.Stderr, "Source and target storage must be different")
+		return 2
+	}
+
+	src, err := storage.Open(*from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open source storage: %v\n", err)
+		return 1
+	}
+	dst, err := storage.Open(*to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open target storage: %v\n", err)
+		return 1
+	}
+	opts.OnError = func(key string, err error) {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", key, err)
+	}
+	res, err := storage.Migrate(ctx, src, dst, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Migration aborted: %v\n", err)
+	}
+	verb := "Copied"
+	if opts.DryRun {
+		verb = "Would copy"
+	}
+	fmt.Fprintf(os.Stderr, "%s %d of %d objects (%d bytes), %d already present, %d failed\n",
+		verb, res.Copied, res.Objects, res.Bytes, res.Skipped, res.Failed)
+	if err != nil || res.Failed > 0 {
+		return 1
+	}
+	if !opts.DryRun && normalizeDriver(*to) != normalizeDriver(config.Get().Storage.Driver) {
+		fmt.Fprintf(os.Stderr, "Set storage.driver (STORAGE_DRIVER) to %s and restart the server to use the migrated storage\n", *to)
+	}
+	return 0
+}
+
+// normalizeDriver 未配置的存储后端按 local 处理
+func normalizeDriver(driver string) string {
+	if driver == "" {
+		return "local"
+	}
+	return driver
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Local 本地磁盘存储
+type Local struct {
+	root string
+}
+
+// NewLocal 创建本地磁盘存储，root 不存在时自动创建
+func NewLocal(root string) (*Local, error) {
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &Local{root: root}, nil
+}
+
+func (l *Local) path(key string) (string, error) {
+	k, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(l.root, filepath.FromSlash(k)), nil
+}
+
+// Put 写入对象：先写临时文件再原子重命名，避免读到半截内容
+func (l *Local) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// Get 读取对象
+func (l *Local) Get(_ context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	return f, &ObjectInfo{Key: key, Size: st.Size(), ContentType: mime.TypeByExtension(filepath.Ext(p))}, nil
+}
+
+// Stat 获取对象元信息
+func (l *Local) Stat(_ context.Context, key string) (*ObjectInfo, error) {
+	p, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ObjectInfo{Key: key, Size: st.Size(), ContentType: mime.TypeByExtension(filepath.Ext(p))}, nil
+}
+
+// Delete 删除对象
+func (l *Local) Delete(_ context.Context, key string) error {
+	p, err := l.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// SignedURL 本地磁盘的对象只能经本服务读取，不支持直链
+func (l *Local) SignedURL(context.Context, string, time.Duration, string) (string, error) {
+	return "", ErrNotSupported
+}
+
+// Walk 按键遍历对象，跳过写入中的临时文件
+func (l *Local) Walk(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	// 从前缀所在的目录开始遍历，避免扫描整个存储
+	start := l.root
+	if dir := prefix[:strings.LastIndex(prefix, "/")+1]; dir != "" {
+		p, err := l.path(dir)
+		if err != nil {
+			return err
+		}
+		start = p
+	}
+	return filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		// 前缀目录不存在或对象在遍历中被删除
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(l.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return fn(ObjectInfo{Key: key, Size: info.Size(), ContentType: mime.TypeByExtension(filepath.Ext(p))})
+	})
+}
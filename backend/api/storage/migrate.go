@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// MigrateOptions 在存储后端之间迁移对象的选项
+type MigrateOptions struct {
+	DryRun       bool // 只统计需要复制的对象，不写入
+	Overwrite    bool // 目标中已有同样大小的对象时仍重新复制
+	DeleteSource bool // 复制成功（或目标已有）后删除源对象
+	// OnError 单个对象迁移失败时调用，迁移继续进行
+	OnError func(key string, err error)
+}
+
+// MigrateResult 迁移统计
+type MigrateResult struct {
+	Objects int   // 源中的对象数
+	Copied  int   // 复制（DryRun 时为需要复制）的对象数
+	Skipped int   // 目标中已有同样大小、未复制的对象数
+	Failed  int   // 失败的对象数
+	Bytes   int64 // 复制的字节数
+}
+
+// Migrate 将 from 中的全部对象按原键复制到 to。对象键不变，数据库中的存储键与资源地址无需改写；
+// 重复执行只复制尚未迁移或大小不一致的对象，可在服务运行时先迁移一遍，停止服务后再补齐期间新增的对象
+func Migrate(ctx context.Context, from, to Storage, opts MigrateOptions) (*MigrateResult, error) {
+	res := &MigrateResult{}
+	fail := func(key string, err error) {
+		res.Failed++
+		if opts.OnError != nil {
+			opts.OnError(key, err)
+		}
+	}
+	// 遍历结束后再删除源对象，避免边列举边删除打乱分页
+	var migrated []string
+	err := from.Walk(ctx, "", func(obj ObjectInfo) error {
+		res.Objects++
+		if !opts.Overwrite {
+			dst, err := to.Stat(ctx, obj.Key)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				fail(obj.Key, err)
+				return nil
+			}
+			if err == nil && dst.Size == obj.Size {
+				res.Skipped++
+				migrated = append(migrated, obj.Key)
+				return nil
+			}
+		}
+		if opts.DryRun {
+			res.Copied++
+			res.Bytes += obj.Size
+			return nil
+		}
+		if err := copyObject(ctx, from, to, obj.Key); err != nil {
+			fail(obj.Key, err)
+			return nil
+		}
+		res.Copied++
+		res.Bytes += obj.Size
+		migrated = append(migrated, obj.Key)
+		return ctx.Err()
+	})
+	if err != nil || !opts.DeleteSource || opts.DryRun {
+		return res, err
+	}
+	for _, key := range migrated {
+		if err := from.Delete(ctx, key); err != nil {
+			fail(key, err)
+		}
+	}
+	return res, ctx.Err()
+}
+
+// copyObject 复制单个对象，写入后核对大小
+func copyObject(ctx context.Context, from, to Storage, key string) error {
+	rc, info, err := from.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := to.Put(ctx, key, rc, info.Size, info.ContentType); err != nil {
+		return err
+	}
+	dst, err := to.Stat(ctx, key)
+	if err != nil {
+		return err
+	}
+	if dst.Size != info.Size {
+		return fmt.Errorf("size mismatch after copy: %d != %d", dst.Size, info.Size)
+	}
+	return nil
+}
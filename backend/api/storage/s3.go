@@ -0,0 +1,348 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// S3 S3 兼容对象存储（AWS S3 / MinIO / R2 / OSS 等），使用 SigV4 签名
+type S3 struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	prefix    string
+	pathStyle bool
+	client    *http.Client
+	// credentials 非空时每次签名前读取访问密钥，用于轮换后无需重启
+	credentials func() (accessKey, secretKey string)
+}
+
+// NewS3 根据 storage.s3 配置创建 S3 存储，path_style 默认开启（MinIO 等需要）
+func NewS3(cfg config.S3Config) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("storage.s3.endpoint and storage.s3.bucket are required for s3 storage")
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid storage.s3.endpoint: %s", cfg.Endpoint)
+	}
+	region := cfg.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3{
+		endpoint:  u,
+		region:    region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKeyID,
+		secretKey: cfg.SecretAccessKey,
+		prefix:    strings.Trim(cfg.Prefix, "/"),
+		pathStyle: cfg.PathStyle,
+		client:    &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	k, err := CleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if s.prefix != "" {
+		k = s.prefix + "/" + k
+	}
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = "/" + s.bucket + "/" + k
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = "/" + k
+	}
+	return &u, nil
+}
+
+// bucketURL 桶的地址，用于列举对象
+func (s *S3) bucketURL() *url.URL {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = "/" + s.bucket + "/"
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = "/"
+	}
+	return &u
+}
+
+func (s *S3) do(ctx context.Context, method, key string, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(ctx, method, u, body, size, contentType)
+}
+
+func (s *S3) send(ctx context.Context, method string, u *url.URL, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil && size >= 0 {
+		req.ContentLength = size
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.sign(req, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+// Put 上传对象
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, r, size, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("s3 put %s: %s %s", key, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Get 下载对象
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, 0, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("s3 get %s: %s", key, resp.Status)
+	}
+	return resp.Body, &ObjectInfo{Key: key, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// Stat 查询对象元信息
+func (s *S3) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("s3 head %s: %s", key, resp.Status)
+	}
+	return &ObjectInfo{Key: key, Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+}
+
+// Delete 删除对象
+func (s *S3) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, "")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("s3 delete %s: %s", key, resp.Status)
+	}
+	return nil
+}
+
+// maxSignedURLTTL SigV4 预签名地址的最长有效期
+const maxSignedURLTTL = 7 * 24 * time.Hour
+
+// SignedURL 签发预签名的 GET 地址（查询参数签名），有效期不超过 7 天
+func (s *S3) SignedURL(_ context.Context, key string, ttl time.Duration, fileName string) (string, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+	if ttl <= 0 || ttl > maxSignedURLTTL {
+		ttl = maxSignedURLTTL
+	}
+	s.presign(u, time.Now().UTC(), ttl, fileName)
+	return u.String(), nil
+}
+
+// presign 为 GET 地址追加 SigV4 查询参数签名
+func (s *S3) presign(u *url.URL, now time.Time, ttl time.Duration, fileName string) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	accessKey, secretKey := s.keys()
+	scope := date + "/" + s.region + "/s3/aws4_request"
+
+	q := url.Values{}
+	q.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	q.Set("X-Amz-Credential", accessKey+"/"+scope)
+	q.Set("X-Amz-Date", amzDate)
+	q.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	q.Set("X-Amz-SignedHeaders", "host")
+	if fileName != "" {
+		q.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	}
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery(q),
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	q.Set("X-Amz-Signature", s.signature(secretKey, date, amzDate, scope, canonicalRequest))
+	u.RawQuery = canonicalQuery(q)
+}
+
+// listResult ListObjectsV2 的响应
+type listResult struct {
+	IsTruncated           bool
+	NextContinuationToken string
+	Contents              []struct {
+		Key  string
+		Size int64
+	}
+}
+
+// Walk 通过 ListObjectsV2 分页列举对象，返回的键不含 storage.s3.prefix
+func (s *S3) Walk(ctx context.Context, prefix string, fn func(ObjectInfo) error) error {
+	base := ""
+	if s.prefix != "" {
+		base = s.prefix + "/"
+	}
+	token := ""
+	for {
+		u := s.bucketURL()
+		q := url.Values{}
+		q.Set("list-type", "2")
+		q.Set("prefix", base+prefix)
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = canonicalQuery(q)
+		resp, err := s.send(ctx, http.MethodGet, u, nil, 0, "")
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			resp.Body.Close()
+			return fmt.Errorf("s3 list %s: %s %s", prefix, resp.Status, strings.TrimSpace(string(msg)))
+		}
+		var page listResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("s3 list %s: %w", prefix, err)
+		}
+		for _, obj := range page.Contents {
+			// 以 / 结尾的占位对象（控制台创建的“目录”）不是资源
+			if strings.HasSuffix(obj.Key, "/") {
+				continue
+			}
+			if err := fn(ObjectInfo{Key: strings.TrimPrefix(obj.Key, base), Size: obj.Size}); err != nil {
+				return err
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// sign 按 AWS SigV4 规范签名请求（负载不参与签名，使用 UNSIGNED-PAYLOAD）
+func (s *S3) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", "UNSIGNED-PAYLOAD")
+	req.Header.Set("Host", req.URL.Host)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": "UNSIGNED-PAYLOAD",
+		"x-amz-date":           amzDate,
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "" {
+		headers["content-type"] = ct
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, k := range names {
+		canonHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonHeaders.String(),
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	accessKey, secretKey := s.keys()
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	signature := s.signature(secretKey, date, amzDate, scope, canonicalRequest)
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// keys 当前的访问密钥
+func (s *S3) keys() (accessKey, secretKey string) {
+	if s.credentials != nil {
+		return s.credentials()
+	}
+	return s.accessKey, s.secretKey
+}
+
+// signature 计算 SigV4 签名
+func (s *S3) signature(secretKey, date, amzDate, scope, canonicalRequest string) string {
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(crHash[:])
+	kDate := hmacSHA256([]byte("AWS4"+secretKey), date)
+	kRegion := hmacSHA256(kDate, s.region)
+	kService := hmacSHA256(kRegion, "s3")
+	kSigning := hmacSHA256(kService, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(kSigning, stringToSign))
+}
+
+// canonicalQuery 按 SigV4 规范编码查询参数：键排序，空格编码为 %20 而不是 +
+func canonicalQuery(q url.Values) string {
+	return strings.ReplaceAll(q.Encode(), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
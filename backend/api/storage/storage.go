@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("storage: object not found")
+
+// ErrNotSupported 存储后端不支持该操作（如本地磁盘无法签发直链）
+var ErrNotSupported = errors.New("storage: operation not supported")
+
+// ObjectInfo 对象元信息
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+}
+
+// Storage 对象存储抽象：本地磁盘为默认实现，可切换为 S3 兼容存储
+type Storage interface {
+	// Put 写入对象（覆盖同名对象）
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get 读取对象，调用方负责关闭返回的 ReadCloser
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Stat 获取对象元信息，不存在时返回 ErrNotFound
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete 删除对象，对象不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// SignedURL 签发在 ttl 内有效的对象直链，浏览器可绕过本服务直接下载；fileName 非空时以附件形式下载并使用该文件名。
+	// 不支持时返回 ErrNotSupported
+	SignedURL(ctx context.Context, key string, ttl time.Duration, fileName string) (string, error)
+	// Walk 按键遍历 prefix 下的全部对象（prefix 为空时遍历全部），fn 返回错误时停止遍历并返回该错误
+	Walk(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+}
+
+// Default 全局存储实例，由 Init 初始化
+var Default Storage
+
+// Init 根据配置初始化存储后端 (storage.driver=local|s3)
+func Init() error {
+	s, err := Open(config.Get().Storage.Driver)
+	if err != nil {
+		return err
+	}
+	Default = s
+	return nil
+}
+
+// Open 按当前配置创建指定后端的存储实例，不修改 Default；storage migrate 借此同时打开新旧两个后端
+func Open(driver string) (Storage, error) {
+	cfg := config.Get()
+	switch driver {
+	case "s3":
+		s, err := NewS3(cfg.Storage.S3)
+		if err != nil {
+			return nil, err
+		}
+		// 访问密钥随重新加载的配置轮换，其余设置只在启动时读取
+		s.credentials = func() (string, string) {
+			s3 := config.Get().Storage.S3
+			return s3.AccessKeyID, s3.SecretAccessKey
+		}
+		log.Printf("Storage driver: s3 (bucket=%s)", s.bucket)
+		return s, nil
+	case "", "local":
+		s, err := NewLocal(filepath.Join(cfg.Server.DataDir, "blobs"))
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Storage driver: local (%s)", s.root)
+		return s, nil
+	default:
+		return nil, errors.New("unsupported storage driver: " + driver)
+	}
+}
+
+// CleanKey 规范化对象键，禁止目录穿越与绝对路径
+func CleanKey(key string) (string, error) {
+	key = strings.ReplaceAll(key, "\\", "/")
+	key = strings.TrimLeft(key, "/")
+	if key == "" {
+		return "", errors.New("storage: empty key")
+	}
+	for _, seg := range strings.Split(key, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return "", errors.New("storage: invalid key")
+		}
+	}
+	return key, nil
+}
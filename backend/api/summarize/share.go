@@ -0,0 +1,79 @@
+package summarize
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/tts"
+)
+
+// 同时进行的生成请求数，外部接口通常按并发限流
+var slots = make(chan struct{}, 2)
+
+// inputHash 标题与正文纯文本的哈希，用于判断摘要是否需要重新生成
+func inputHash(share *models.Share) (text, hash string) {
+	// 朗读文本去掉了代码块、公式与标记符号，同样适合作为摘要的输入
+	text = tts.Text(share.Content)
+	return text, tts.Hash(share.DocTitle + "\n" + text)
+}
+
+// Refresh 为分享生成摘要与建议标签并保存。force 为 true 时（作者手动生成）总是重新生成并覆盖作者填写的摘要；
+// 否则标题与正文未变化时跳过，作者填写的摘要保持不变、只更新建议标签
+func Refresh(ctx context.Context, share *models.Share, force bool) error {
+	text, hash := inputHash(share)
+	if text == "" {
+		return ErrNoText
+	}
+	if !force && share.SummaryHash == hash {
+		return nil
+	}
+	r, err := Generate(ctx, share.DocTitle, text)
+	if err != nil {
+		return err
+	}
+
+	suggested := models.EncodeTags(r.Tags)
+	if err := models.DB.Model(&models.Share{}).Where("id = ?", share.ID).
+		UpdateColumns(map[string]interface{}{"suggested_tags": suggested, "summary_hash": hash}).Error; err != nil {
+		return err
+	}
+	share.SuggestedTags, share.SummaryHash = suggested, hash
+
+	q := models.DB.Model(&models.Share{}).Where("id = ?", share.ID)
+	if !force {
+		// 生成期间作者可能已填写摘要
+		q = q.Where("summary_manual = ?", false)
+	}
+	res := q.UpdateColumns(map[string]interface{}{"summary": r.Summary, "summary_manual": false})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		share.Summary, share.SummaryManual = r.Summary, false
+	}
+	return nil
+}
+
+// Auto 开启 ai.auto 时在后台为发布的分享生成摘要，标题与正文未变化时跳过
+func Auto(share *models.Share) {
+	if !config.Get().AI.Auto || !Enabled() || share.Status != models.ShareStatusPublished {
+		return
+	}
+	if _, hash := inputHash(share); hash == share.SummaryHash {
+		return
+	}
+	s := *share
+	background.Go(func() {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		ctx, cancel := context.WithTimeout(context.Background(), config.Get().AI.Timeout.Std())
+		defer cancel()
+		if err := Refresh(ctx, &s, false); err != nil && !errors.Is(err, ErrNoText) {
+			log.Printf("summary for share %s failed: %v", s.ID, err)
+		}
+	})
+}
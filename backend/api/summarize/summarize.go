@@ -0,0 +1,175 @@
+// Package summarize 分享摘要：把分享正文交给 OpenAI 兼容的对话补全接口，生成摘要（用作页面
+// meta description、订阅源与合集的简介）及建议标签，结果保存在分享上。作者填写的摘要不会被覆盖，
+// 建议标签仅供作者采纳。
+package summarize
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+const (
+	// MaxRunes 摘要长度上限（字符）
+	MaxRunes = 300
+	// maxTags 建议标签数量上限
+	maxTags = 5
+	// maxTagRunes 单个建议标签长度上限，与分享标签一致
+	maxTagRunes = 50
+	// maxResponseBytes 接口响应体大小上限
+	maxResponseBytes = 1 << 20
+)
+
+// ErrNoText 正文中没有可用于生成摘要的文字
+var ErrNoText = errors.New("share has no text to summarize")
+
+// Result 生成的摘要与建议标签
+type Result struct {
+	Summary string   `json:"summary"`
+	Tags    []string `json:"tags"`
+}
+
+// Enabled 是否配置了摘要生成接口 (ai.url)
+func Enabled() bool {
+	return config.Get().AI.Enabled()
+}
+
+// systemPrompt 要求模型只返回 JSON，摘要与标签使用正文的语言
+const systemPrompt = `You write metadata for a published document. Reply with a single JSON object and nothing else:
+{"summary": "...", "tags": ["..."]}
+- summary: one or two plain sentences describing what the document is about, suitable as a web page meta description; at most 120 characters for Chinese or Japanese text, 250 characters otherwise.
+- tags: up to 5 short topic tags, without "#".
+Write both in the same language as the document.`
+
+// Message 对话补全接口的一条消息
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Generate 请求对话补全接口生成摘要与建议标签，正文超过 ai.max_input_chars 时截断
+func Generate(ctx context.Context, title, text string) (*Result, error) {
+	if n := config.Get().AI.MaxInputChars; n > 0 && utf8.RuneCountInString(text) > n {
+		text = string([]rune(text)[:n])
+	}
+	reply, err := Complete(ctx, []Message{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: "Title: " + title + "\n\n" + text},
+	}, 0.3)
+	if err != nil {
+		return nil, err
+	}
+	return parse(reply)
+}
+
+// Endpoint OpenAI 兼容的对话补全接口
+type Endpoint struct {
+	URL    string
+	APIKey string // 以 Bearer 令牌发送（可选）
+	Model  string
+}
+
+// Complete 请求 ai.url 配置的对话补全接口，返回第一条回复的内容
+func Complete(ctx context.Context, messages []Message, temperature float64) (string, error) {
+	cfg := config.Get().AI
+	return CompleteAt(ctx, Endpoint{URL: cfg.URL, APIKey: cfg.APIKey, Model: cfg.Model}, messages, temperature)
+}
+
+// CompleteAt 同 Complete，使用指定的接口与模型
+func CompleteAt(ctx context.Context, cfg Endpoint, messages []Message, temperature float64) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"model":       cfg.Model,
+		"messages":    messages,
+		"temperature": temperature,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return "", fmt.Errorf("AI service returned %s: %s", resp.Status, msg)
+	}
+	var completion struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return "", fmt.Errorf("invalid AI service response: %w", err)
+	}
+	if len(completion.Choices) == 0 {
+		return "", errors.New("AI service returned no choices")
+	}
+	return completion.Choices[0].Message.Content, nil
+}
+
+// parse 解析模型回复中的 JSON 对象，容忍代码块包裹与前后说明文字
+func parse(content string) (*Result, error) {
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return nil, errors.New("summary service reply contains no JSON object")
+	}
+	var r Result
+	if err := json.Unmarshal([]byte(content[start:end+1]), &r); err != nil {
+		return nil, fmt.Errorf("invalid summary service reply: %w", err)
+	}
+	r.Summary = strings.Join(strings.Fields(r.Summary), " ")
+	if runes := []rune(r.Summary); len(runes) > MaxRunes {
+		r.Summary = strings.TrimSpace(string(runes[:MaxRunes-1])) + "…"
+	}
+	r.Tags = cleanTags(r.Tags)
+	if r.Summary == "" && len(r.Tags) == 0 {
+		return nil, errors.New("summary service returned an empty summary")
+	}
+	return &r, nil
+}
+
+// cleanTags 去掉 # 前缀、空白与重复项，超长标签丢弃
+func cleanTags(tags []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, t := range tags {
+		t = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(t), "#"))
+		key := strings.ToLower(t)
+		if t == "" || seen[key] || utf8.RuneCountInString(t) > maxTagRunes {
+			continue
+		}
+		seen[key] = true
+		out = append(out, t)
+		if len(out) == maxTags {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// letsEncryptStaging Let's Encrypt 测试环境，证书不受信任但申请频率限制宽松
+const letsEncryptStaging = "https://acme-staging-v02.api.letsencrypt.org/directory"
+
+// newServers 创建 HTTP 服务；配置 tls.domains 时返回 HTTPS 服务与负责证书验证、跳转 HTTPS 的 HTTP 服务
+func newServers(cfg *config.Config, handler http.Handler) []*http.Server {
+	if !cfg.TLS.Enabled() {
+		if cfg.Server.UnixSocket() != "" {
+			handler = unixPeer(handler)
+		}
+		return []*http.Server{{Addr: cfg.Server.ListenAddr(), Handler: handler}}
+	}
+
+	m := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cfg.TLS.CacheDir),
+		HostPolicy: hostPolicy(cfg),
+		Email:      cfg.TLS.Email,
+	}
+	if cfg.TLS.Staging {
+		m.Client = &acme.Client{DirectoryURL: letsEncryptStaging}
+	}
+	return []*http.Server{
+		{Addr: ":" + cfg.TLS.HTTPSPort, Handler: handler, TLSConfig: m.TLSConfig()},
+		{Addr: ":" + cfg.TLS.HTTPPort, Handler: m.HTTPHandler(redirectHTTPS(cfg.TLS.HTTPSPort))},
+	}
+}
+
+// hostPolicy 允许申请证书的域名：tls.domains，开启 tls.custom_domains 时还包括已验证的用户自定义域名
+func hostPolicy(cfg *config.Config) autocert.HostPolicy {
+	whitelist := autocert.HostWhitelist(cfg.TLS.Domains...)
+	if !cfg.TLS.CustomDomains {
+		return whitelist
+	}
+	return func(ctx context.Context, host string) error {
+		if err := whitelist(ctx, host); err == nil {
+			return nil
+		}
+		if models.IsVerifiedDomain(host) {
+			return nil
+		}
+		return fmt.Errorf("acme/autocert: host %q not configured", host)
+	}
+}
+
+// redirectHTTPS 将 HTTP 请求永久跳转到 HTTPS（非 443 端口时保留端口号）
+func redirectHTTPS(port string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		code := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			code = http.StatusMovedPermanently
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), code)
+	})
+}
+
+// listen 启动服务，带证书配置的服务以 HTTPS 方式监听，unix:<路径> 地址在 Unix 套接字上监听
+func listen(srv *http.Server) error {
+	if srv.TLSConfig != nil {
+		return srv.ListenAndServeTLS("", "")
+	}
+	if path, ok := strings.CutPrefix(srv.Addr, "unix:"); ok {
+		ln, err := listenUnix(path, config.Get().Server.SocketMode)
+		if err != nil {
+			return err
+		}
+		return srv.Serve(ln)
+	}
+	return srv.ListenAndServe()
+}
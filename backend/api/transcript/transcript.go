@@ -0,0 +1,130 @@
+// Package transcript 解析音视频文字稿：WebVTT 与 SRT 字幕解析为带时间轴的段落，
+// 纯文本文字稿按原样保存；带时间轴的文字稿统一转换为 WebVTT 存储与输出。
+package transcript
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// 文字稿格式
+const (
+	FormatVTT  = "vtt"  // WebVTT，带时间轴
+	FormatSRT  = "srt"  // SubRip，保存时转换为 WebVTT
+	FormatText = "text" // 纯文本，无时间轴
+)
+
+// Cue 带时间轴的一段文字，时间单位为秒
+type Cue struct {
+	Start float64 `json:"start"`
+	End   float64 `json:"end"`
+	Text  string  `json:"text"`
+}
+
+var (
+	timingPattern = regexp.MustCompile(`^((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})\s+-->\s+((?:\d+:)?\d{1,2}:\d{2}[.,]\d{1,3})`)
+	tagPattern    = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+// Detect 根据内容判断格式：以 WEBVTT 开头为 vtt，含时间轴为 srt，否则为纯文本
+func Detect(content string) string {
+	trimmed := strings.TrimLeft(content, "\ufeff \t\r\n")
+	if strings.HasPrefix(trimmed, "WEBVTT") {
+		return FormatVTT
+	}
+	for _, line := range strings.Split(trimmed, "\n") {
+		if timingPattern.MatchString(strings.TrimSpace(line)) {
+			return FormatSRT
+		}
+	}
+	return FormatText
+}
+
+// Parse 解析 WebVTT 或 SRT 字幕，去除样式标签；没有任何有效段落时返回错误
+func Parse(content string) ([]Cue, error) {
+	content = strings.TrimPrefix(strings.ReplaceAll(content, "\r\n", "\n"), "\ufeff")
+	var cues []Cue
+	var cur *Cue
+	var text []string
+	flush := func() {
+		if cur != nil && len(text) > 0 {
+			cur.Text = strings.Join(text, "\n")
+			cues = append(cues, *cur)
+		}
+		cur, text = nil, nil
+	}
+	skipBlock := false
+	for _, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" {
+			flush()
+			skipBlock = false
+			continue
+		}
+		if skipBlock {
+			continue
+		}
+		if cur == nil {
+			// NOTE、STYLE、REGION 块与文件头不是字幕内容
+			if strings.HasPrefix(line, "WEBVTT") || strings.HasPrefix(line, "NOTE") ||
+				line == "STYLE" || line == "REGION" {
+				skipBlock = true
+				continue
+			}
+			m := timingPattern.FindStringSubmatch(line)
+			if m == nil {
+				continue // 段落标识（SRT 序号或 VTT cue id）
+			}
+			start, err1 := parseTimestamp(m[1])
+			end, err2 := parseTimestamp(m[2])
+			if err1 != nil || err2 != nil || end < start {
+				return nil, fmt.Errorf("invalid timing %q", line)
+			}
+			cur = &Cue{Start: start, End: end}
+			continue
+		}
+		if t := strings.TrimSpace(tagPattern.ReplaceAllString(line, "")); t != "" {
+			text = append(text, t)
+		}
+	}
+	flush()
+	if len(cues) == 0 {
+		return nil, errors.New("no cues found")
+	}
+	return cues, nil
+}
+
+// parseTimestamp 解析 [hh:]mm:ss.ttt（SRT 使用逗号分隔毫秒）
+func parseTimestamp(s string) (float64, error) {
+	parts := strings.Split(strings.Replace(s, ",", ".", 1), ":")
+	var total float64
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return 0, err
+		}
+		total = total*60 + v
+	}
+	return total, nil
+}
+
+// VTT 将段落输出为 WebVTT 文本
+func VTT(cues []Cue) string {
+	var b strings.Builder
+	b.WriteString("WEBVTT\n")
+	for _, c := range cues {
+		b.WriteString("\n" + formatTimestamp(c.Start) + " --> " + formatTimestamp(c.End) + "\n")
+		// 空行会结束段落，段内空行替换为单个空格
+		b.WriteString(strings.ReplaceAll(c.Text, "\n\n", "\n \n") + "\n")
+	}
+	return b.String()
+}
+
+func formatTimestamp(sec float64) string {
+	ms := int64(math.Round(sec * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
@@ -0,0 +1,187 @@
+package translate
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrUnsupportedLanguage 语言标签无效或不在 translation.languages 中
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	// ErrNoText 分享没有可翻译的内容
+	ErrNoText = errors.New("share has no text to translate")
+)
+
+// 同时进行的翻译任务数，外部接口通常按并发限流
+var slots = make(chan struct{}, 1)
+
+// running 正在生成的译文（分享 ID/语言）。重新生成已有译文期间读者仍可访问旧译文
+var (
+	runningMu sync.Mutex
+	running   = map[string]bool{}
+)
+
+func runningKey(shareID, lang string) string {
+	return shareID + "/" + lang
+}
+
+// Generating 是否正在为分享生成该语言的译文
+func Generating(shareID, lang string) bool {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	return running[runningKey(shareID, lang)]
+}
+
+// Find 查找分享的译文，不存在时返回 nil
+func Find(shareID, lang string) *models.ShareTranslation {
+	var t models.ShareTranslation
+	if res := models.DB.Where("share_id = ? AND lang = ?", shareID, lang).Limit(1).Find(&t); res.Error != nil || res.RowsAffected == 0 {
+		return nil
+	}
+	return &t
+}
+
+// Start 在后台为分享生成（或重新生成）该语言的译文，返回当前记录；已在生成中时直接返回
+func Start(share *models.Share, lang string) (*models.ShareTranslation, error) {
+	if !Enabled() {
+		return nil, errors.New("translation is not configured")
+	}
+	if !Allowed(lang) {
+		return nil, ErrUnsupportedLanguage
+	}
+	if share.DocTitle == "" && share.Content == "" {
+		return nil, ErrNoText
+	}
+	key := runningKey(share.ID, lang)
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	if running[key] {
+		return Find(share.ID, lang), nil
+	}
+
+	// 新译文在生成完成前不对读者开放，已有的可用译文保持可用
+	t := &models.ShareTranslation{ShareID: share.ID, Lang: lang, Status: models.TranslationPending}
+	if err := models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "share_id"}, {Name: "lang"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"error": "", "updated_at": time.Now()}),
+	}).Create(t).Error; err != nil {
+		return nil, err
+	}
+	if err := models.DB.Model(&models.ShareTranslation{}).
+		Where("share_id = ? AND lang = ? AND status = ?", share.ID, lang, models.TranslationFailed).
+		UpdateColumn("status", models.TranslationPending).Error; err != nil {
+		return nil, err
+	}
+
+	shareID := share.ID
+	running[key] = true
+	if !background.Go(func() {
+		defer func() {
+			runningMu.Lock()
+			delete(running, key)
+			runningMu.Unlock()
+		}()
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		run(shareID, lang)
+	}) {
+		delete(running, key)
+		return nil, errors.New("server is shutting down")
+	}
+	return Find(share.ID, lang), nil
+}
+
+// run 翻译分享的当前标题与正文并保存；期间作者修改过译文时放弃本次结果
+func run(shareID, lang string) {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), config.Get().Translation.Timeout.Std())
+	defer cancel()
+
+	var share models.Share
+	err := models.DB.Where("id = ?", shareID).First(&share).Error
+	var title, content string
+	if err == nil {
+		title, err = Title(ctx, share.DocTitle, lang)
+	}
+	if err == nil {
+		content, err = Markdown(ctx, share.Content, lang)
+	}
+	if err != nil {
+		log.Printf("translation %s of share %s failed: %v", lang, shareID, err)
+		msg := err.Error()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		q := models.DB.Model(&models.ShareTranslation{}).Where("share_id = ? AND lang = ?", shareID, lang)
+		if e := q.UpdateColumn("error", msg).Error; e != nil {
+			log.Printf("translation save failed (%s/%s): %v", shareID, lang, e)
+		}
+		models.DB.Model(&models.ShareTranslation{}).Where("share_id = ? AND lang = ? AND status = ?", shareID, lang, models.TranslationPending).
+			UpdateColumn("status", models.TranslationFailed)
+		return
+	}
+	if t := Find(shareID, lang); t == nil || (t.Manual && t.UpdatedAt.After(started)) {
+		// 已被删除或作者在生成期间修改过译文
+		return
+	}
+	if err := save(&share, lang, title, content, false); err != nil {
+		log.Printf("translation save failed (%s/%s): %v", shareID, lang, err)
+	}
+}
+
+// save 保存可供读者访问的译文，并记录对应的原文哈希
+func save(share *models.Share, lang, title, content string, manual bool) error {
+	t := &models.ShareTranslation{
+		ShareID: share.ID, Lang: lang, DocTitle: title, Content: content,
+		SourceHash: models.TranslationSourceHash(share), Status: models.TranslationReady, Manual: manual,
+	}
+	return models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "share_id"}, {Name: "lang"}},
+		DoUpdates: clause.AssignmentColumns([]string{"doc_title", "content", "source_hash", "status", "error", "manual", "updated_at"}),
+	}).Create(t).Error
+}
+
+// Save 保存作者提供或修改的译文（不受 translation.languages 限制，之后不会被自动更新覆盖）
+func Save(share *models.Share, lang, title, content string) (*models.ShareTranslation, error) {
+	if !config.IsLanguageTag(lang) {
+		return nil, ErrUnsupportedLanguage
+	}
+	if err := save(share, lang, title, content, true); err != nil {
+		return nil, err
+	}
+	return Find(share.ID, lang), nil
+}
+
+// Remove 删除分享的译文
+func Remove(shareID, lang string) error {
+	return models.DB.Where("share_id = ? AND lang = ?", shareID, lang).Delete(&models.ShareTranslation{}).Error
+}
+
+// Auto 开启 translation.auto 时，在分享重新发布后于后台更新已过期的机器译文
+func Auto(share *models.Share) {
+	if !config.Get().Translation.Auto || !Enabled() || share.Status != models.ShareStatusPublished {
+		return
+	}
+	var list []models.ShareTranslation
+	if err := models.DB.Select("share_id", "lang", "source_hash", "manual").
+		Where("share_id = ? AND manual = ?", share.ID, false).Find(&list).Error; err != nil {
+		log.Printf("translation lookup for share %s failed: %v", share.ID, err)
+		return
+	}
+	for i := range list {
+		if !list[i].Stale(share) {
+			continue
+		}
+		if _, err := Start(share, list[i].Lang); err != nil && !errors.Is(err, ErrUnsupportedLanguage) {
+			log.Printf("translation %s of share %s failed: %v", list[i].Lang, share.ID, err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/background"
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"github.com/ZeroHawkeye/siyuan-share-api/storage"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNoText 正文中没有可朗读的文字
+	ErrNoText = errors.New("share has no readable text")
+	// ErrTextTooLong 朗读文本超过 tts.max_chars
+	ErrTextTooLong = errors.New("share text is too long for narration")
+	// ErrQuotaExceeded 音频超出所有者的存储配额
+	ErrQuotaExceeded = errors.New("narration exceeds the storage quota")
+)
+
+// narrationPathPattern 朗读音频的资源路径（LIKE 模式），不匹配作者上传的同名前缀资源
+const narrationPathPattern = "assets/narration-____________.%"
+
+// 同时进行的合成任务数，外部接口通常按并发限流
+var slots = make(chan struct{}, 1)
+
+// Generate 为分享生成朗读音频，合成在后台进行；已有与当前正文一致（或正在生成中）的音频时直接返回
+func Generate(share *models.Share) (*models.ShareNarration, error) {
+	if !Enabled() || storage.Default == nil {
+		return nil, errors.New("text-to-speech is not configured")
+	}
+	text := Text(share.Content)
+	chars := utf8.RuneCountInString(text)
+	if chars == 0 {
+		return nil, ErrNoText
+	}
+	if chars > config.Get().TTS.MaxChars {
+		return nil, ErrTextTooLong
+	}
+	hash := Hash(text)
+	if n := models.FindNarration(share.ID); n != nil && n.TextHash == hash {
+		// 进程在合成中途退出时记录停留在 pending，超过合成超时后允许重新生成
+		stale := n.Status == models.NarrationPending && time.Since(n.UpdatedAt) > config.Get().TTS.Timeout.Std()+time.Minute
+		if n.Status == models.NarrationOK || (n.Status == models.NarrationPending && !stale) {
+			return n, nil
+		}
+	}
+
+	n := &models.ShareNarration{ShareID: share.ID, Status: models.NarrationPending, TextHash: hash, Chars: chars}
+	if err := models.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "share_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"status": models.NarrationPending, "error": "", "text_hash": hash, "chars": chars, "updated_at": time.Now()}),
+	}).Create(n).Error; err != nil {
+		return nil, err
+	}
+	n = models.FindNarration(share.ID)
+	if n == nil {
+		return nil, errors.New("narration record not found")
+	}
+	shareID, userID := share.ID, share.UserID
+	if !background.Go(func() {
+		slots <- struct{}{}
+		defer func() { <-slots }()
+		narrate(shareID, userID, text, hash)
+	}) {
+		return nil, errors.New("server is shutting down")
+	}
+	return n, nil
+}
+
+// narrate 合成音频并保存为分享资源，结果写回记录；期间正文再次变化时放弃本次结果
+func narrate(shareID, userID, text, hash string) {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Get().TTS.Timeout.Std())
+	defer cancel()
+
+	assetPath, err := synthesizeAsset(ctx, shareID, userID, text, hash)
+	updates := map[string]interface{}{"status": models.NarrationOK, "error": "", "asset_path": assetPath}
+	if err != nil {
+		msg := err.Error()
+		if len(msg) > 500 {
+			msg = msg[:500]
+		}
+		updates = map[string]interface{}{"status": models.NarrationFailed, "error": msg}
+		log.Printf("narration for share %s failed: %v", shareID, err)
+	}
+	res := models.DB.Model(&models.ShareNarration{}).Where("share_id = ? AND text_hash = ?", shareID, hash).Updates(updates)
+	if res.Error != nil {
+		log.Printf("narration save failed (%s): %v", shareID, res.Error)
+	}
+	if err != nil {
+		return
+	}
+	if res.Error != nil || res.RowsAffected == 0 {
+		// 已被新的生成任务取代
+		removeAsset(shareID, assetPath)
+		return
+	}
+	// 清理旧正文对应的音频
+	var old []models.Asset
+	models.DB.Where("share_id = ? AND path LIKE ? AND path <> ?", shareID, narrationPathPattern, assetPath).Find(&old)
+	for _, a := range old {
+		removeAsset(shareID, a.Path)
+	}
+}
+
+// synthesizeAsset 合成音频并按所有者配额保存为 assets/narration-<文本哈希前 12 位>.<格式>
+func synthesizeAsset(ctx context.Context, shareID, userID, text, hash string) (string, error) {
+	audio, err := Synthesize(ctx, text)
+	if err != nil {
+		return "", err
+	}
+	if len(audio) == 0 {
+		return "", errors.New("speech engine returned no audio")
+	}
+	size := int64(len(audio))
+	ext, contentType := Format()
+	assetPath := "assets/narration-" + hash[:12] + "." + ext
+
+	existing, err := models.FindAsset(shareID, assetPath)
+	if err != nil {
+		return "", err
+	}
+	quota := models.UserQuota(userID)
+	if quota.MaxAssetBytes > 0 && size > quota.MaxAssetBytes {
+		return "", ErrQuotaExceeded
+	}
+	if quota.MaxBytes > 0 {
+		usage, err := models.UserUsage(userID)
+		if err != nil {
+			return "", err
+		}
+		used := usage.Bytes
+		if existing != nil {
+			used -= existing.Size
+		}
+		if used+size > quota.MaxBytes {
+			return "", ErrQuotaExceeded
+		}
+	}
+
+	key := "shares/" + shareID + "/" + assetPath
+	if err := storage.Default.Put(ctx, key, bytes.NewReader(audio), size, contentType); err != nil {
+		return "", fmt.Errorf("store narration: %w", err)
+	}
+	sum := sha256.Sum256(audio)
+	asset := existing
+	if asset == nil {
+		asset = &models.Asset{ID: "ast_" + randHex(12), ShareID: shareID, UserID: userID, Path: assetPath}
+	}
+	asset.StorageKey = key
+	asset.ContentType = contentType
+	asset.Size = size
+	asset.Hash = hex.EncodeToString(sum[:])
+	if err := models.DB.Save(asset).Error; err != nil {
+		return "", err
+	}
+	return assetPath, nil
+}
+
+// removeAsset 删除朗读音频资源及其存储对象
+func removeAsset(shareID, assetPath string) {
+	asset, err := models.FindAsset(shareID, assetPath)
+	if err != nil || asset == nil {
+		return
+	}
+	if err := storage.Default.Delete(context.Background(), asset.StorageKey); err != nil {
+		log.Printf("narration cleanup failed (%s): %v", asset.StorageKey, err)
+		return
+	}
+	models.DB.Delete(asset)
+}
+
+// Remove 删除分享的朗读音频及记录
+func Remove(shareID string) error {
+	var assets []models.Asset
+	if err := models.DB.Where("share_id = ? AND path LIKE ?", shareID, narrationPathPattern).Find(&assets).Error; err != nil {
+		return err
+	}
+	for _, a := range assets {
+		removeAsset(shareID, a.Path)
+	}
+	return models.DB.Where("share_id = ?", shareID).Delete(&models.ShareNarration{}).Error
+}
+
+// Current 返回与分享当前正文一致的已生成朗读音频，没有时返回 nil
+func Current(share *models.Share) *models.ShareNarration {
+	n := models.FindNarration(share.ID)
+	if n == nil || n.Status != models.NarrationOK || n.AssetPath == "" || n.TextHash != Hash(Text(share.Content)) {
+		return nil
+	}
+	return n
+}
+
+func randHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI OpenAI 兼容的语音合成接口（/v1/audio/speech），许多本地引擎也提供同样的接口
+type OpenAI struct {
+	URL      string
+	APIKey   string
+	Model    string
+	Voice    string
+	Format   string
+	MaxBytes int64 // 响应体大小上限，0 表示不限制
+	Client   *http.Client
+}
+
+// Synthesize 请求合成接口，2xx 响应体即音频
+func (e *OpenAI) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{
+		"model":           e.Model,
+		"input":           text,
+		"voice":           e.Voice,
+		"response_format": e.Format,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.APIKey)
+	}
+	client := e.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var r io.Reader = resp.Body
+	if e.MaxBytes > 0 {
+		r = io.LimitReader(resp.Body, e.MaxBytes+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200]
+		}
+		return nil, fmt.Errorf("speech service returned %s: %s", resp.Status, msg)
+	}
+	if e.MaxBytes > 0 && int64(len(data)) > e.MaxBytes {
+		return nil, ErrAudioTooLarge
+	}
+	return data, nil
+}
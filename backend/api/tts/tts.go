@@ -0,0 +1,187 @@
+// Package tts 分享朗读音频：把分享正文转换为适合朗读的纯文本，交由外部命令或 OpenAI 兼容的语音合成接口
+// 生成音频，结果作为分享资源保存，供阅读页的播放器使用。
+package tts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	"github.com/ZeroHawkeye/siyuan-share-api/renderer"
+)
+
+// maxAudioBytes 单次合成输出与整篇音频的大小上限
+const maxAudioBytes = 200 << 20
+
+// Engine 语音合成引擎：输入一段朗读文本，输出音频数据
+type Engine interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// ErrAudioTooLarge 合成的音频超过大小上限
+var ErrAudioTooLarge = errors.New("synthesized audio exceeds size limit")
+
+// Enabled 是否配置了语音合成 (tts.command / tts.url)
+func Enabled() bool {
+	return config.Get().TTS.Enabled()
+}
+
+// contentTypes 音频格式对应的 MIME 类型
+var contentTypes = map[string]string{
+	"mp3":  "audio/mpeg",
+	"opus": "audio/ogg",
+	"aac":  "audio/aac",
+	"flac": "audio/flac",
+	"wav":  "audio/wav",
+}
+
+// Format 配置的音频格式（文件扩展名）与 MIME 类型
+func Format() (ext, contentType string) {
+	ext = config.Get().TTS.Format
+	if ext == "opus" {
+		return "ogg", contentTypes[ext]
+	}
+	return ext, contentTypes[ext]
+}
+
+// engine 按配置创建合成引擎
+func engine() Engine {
+	cfg := config.Get().TTS
+	if cfg.URL != "" {
+		return &OpenAI{URL: cfg.URL, APIKey: cfg.APIKey, Model: cfg.Model, Voice: cfg.Voice, Format: cfg.Format, MaxBytes: maxAudioBytes}
+	}
+	return &command{renderer.Command{Args: cfg.Command, MaxBytes: maxAudioBytes}}
+}
+
+// command 命令行合成引擎：朗读文本写入标准输入，标准输出即音频
+type command struct {
+	renderer.Command
+}
+
+func (e *command) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	return e.Render(ctx, []byte(text))
+}
+
+// Synthesize 合成整段文本：超过 tts.chunk_chars 时按句分段依次合成并直接拼接
+func Synthesize(ctx context.Context, text string) ([]byte, error) {
+	e := engine()
+	var audio []byte
+	for _, chunk := range Split(text, config.Get().TTS.ChunkChars) {
+		data, err := e.Synthesize(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		if len(audio)+len(data) > maxAudioBytes {
+			return nil, ErrAudioTooLarge
+		}
+		audio = append(audio, data...)
+	}
+	return audio, nil
+}
+
+var (
+	fencePattern      = regexp.MustCompile("(?ms)^\\s*(```|~~~).*?^\\s*(```|~~~)\\s*$")
+	mathBlockPattern  = regexp.MustCompile(`(?s)\$\$.*?\$\$`)
+	inlineMathPattern = regexp.MustCompile(`\$[^$\n]+\$`)
+	imagePattern      = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	linkPattern       = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	refPattern        = regexp.MustCompile(`\(\([0-9]{14,}-[0-9a-z]{7,}(?:\s+["']([^"']*)["'])?\)\)`)
+	ialPattern        = regexp.MustCompile(`\{:[^}]*\}`)
+	tagPattern        = regexp.MustCompile(`<[^>]+>`)
+	tableRulePattern  = regexp.MustCompile(`^\|?\s*:?-{3,}`)
+	markerPattern     = regexp.MustCompile(`^(?:#{1,6}\s+|>\s*|[-*+]\s+(?:\[[ xX]\]\s+)?|\d+[.)]\s+)+`)
+	symbolPattern     = regexp.MustCompile("[*_`~=|#]+")
+	blankPattern      = regexp.MustCompile(`[ \t]+`)
+)
+
+// Text 将 Markdown 正文转换为朗读文本：去掉代码块、公式、图片与块属性，链接与块引用只保留文字，
+// 每个段落占一行
+func Text(content string) string {
+	text := fencePattern.ReplaceAllString(content, "")
+	text = mathBlockPattern.ReplaceAllString(text, "")
+	text = inlineMathPattern.ReplaceAllString(text, "")
+	text = imagePattern.ReplaceAllString(text, "")
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = refPattern.ReplaceAllString(text, "$1")
+	text = ialPattern.ReplaceAllString(text, "")
+	text = tagPattern.ReplaceAllString(text, "")
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || tableRulePattern.MatchString(line) {
+			continue
+		}
+		line = markerPattern.ReplaceAllString(line, "")
+		line = symbolPattern.ReplaceAllString(line, " ")
+		if line = strings.TrimSpace(blankPattern.ReplaceAllString(line, " ")); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Hash 朗读文本的 sha256，用于判断已生成的音频是否与当前正文一致
+func Hash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Split 将文本按段落与句子切分为不超过 n 个字符的片段，n <= 0 时不切分；超长的单句按字符数截断
+func Split(text string, n int) []string {
+	if n <= 0 || utf8.RuneCountInString(text) <= n {
+		return []string{text}
+	}
+	var chunks []string
+	var cur strings.Builder
+	curLen := 0
+	flush := func() {
+		if s := strings.TrimSpace(cur.String()); s != "" {
+			chunks = append(chunks, s)
+		}
+		cur.Reset()
+		curLen = 0
+	}
+	for _, sentence := range sentences(text) {
+		runes := []rune(sentence)
+		for len(runes) > n {
+			flush()
+			chunks = append(chunks, string(runes[:n]))
+			runes = runes[n:]
+		}
+		if curLen+len(runes) > n {
+			flush()
+		}
+		cur.WriteString(string(runes))
+		curLen += len(runes)
+	}
+	flush()
+	return chunks
+}
+
+// sentences 按句末标点与换行切分，保留标点与分隔符
+func sentences(text string) []string {
+	var out []string
+	start := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		switch r {
+		case '\n', '。', '！', '？', '；', '.', '!', '?', ';':
+			// 英文句点后须为空白或结尾，避免切开小数与缩写
+			if r == '.' && i+1 < len(runes) && runes[i+1] != ' ' && runes[i+1] != '\n' {
+				continue
+			}
+			out = append(out, string(runes[start:i+1]))
+			start = i + 1
+		}
+	}
+	if start < len(runes) {
+		out = append(out, string(runes[start:]))
+	}
+	return out
+}
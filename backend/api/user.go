@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/models"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// runUser 执行 user 子命令：create 创建用户（可同时创建一个 API Token），
+// disable 停用用户并撤销其登录会话与 API Token；返回进程退出码
+func runUser(args []string) int {
+	usage := "usage: user create -username <name> -email <email> [-password <password>] [-token-name <name>]  |  user disable <username>"
+	if len(args) == 0 || (args[0] != "create" && args[0] != "disable") {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("user "+args[0], flag.ContinueOnError)
+	username := fs.String("username", "", "用户名")
+	email := fs.String("email", "", "邮箱")
+	password := fs.String("password", "", "密码（至少6位），留空时从标准输入读取一行")
+	tokenName := fs.String("token-name", "", "可选：创建一个同名 API Token")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if args[0] == "disable" {
+		if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, usage)
+			return 2
+		}
+		return disableUser(fs.Arg(0))
+	}
+
+	if *username == "" || *email == "" || fs.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	if *password == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			fmt.Fprintln(os.Stderr, "Password must be provided with -password or on standard input")
+			return 2
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+	if len(*password) < 6 {
+		fmt.Fprintln(os.Stderr, "Password must be at least 6 characters")
+		return 2
+	}
+
+	if err := models.InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer models.CloseDB()
+
+	var count int64
+	models.DB.Model(&models.User{}).Where("username = ?", *username).Or("email = ?", *email).Count(&count)
+	if count > 0 {
+		fmt.Fprintln(os.Stderr, "Username or email already exists")
+		return 1
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to hash password: %v\n", err)
+		return 1
+	}
+	user := &models.User{
+		ID:           "user_" + randHex(16),
+		Username:     *username,
+		Email:        *email,
+		PasswordHash: string(hash),
+		IsActive:     true,
+	}
+	if err := models.DB.Create(user).Error; err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create user: %v\n", err)
+		return 1
+	}
+	fmt.Printf("User created: %s (%s)\n", user.Username, user.ID)
+
+	if *tokenName != "" {
+		raw, err := createToken(user.ID, *tokenName, nil)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to create API token: %v\n", err)
+			return 1
+		}
+		fmt.Printf("API token (%s): %s\n", *tokenName, raw)
+	}
+	return 0
+}
+
+// disableUser 停用用户：API Token 认证随即拒绝该用户，同时撤销其登录会话与 API Token
+func disableUser(username string) int {
+	if err := models.InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer models.CloseDB()
+
+	var user models.User
+	if err := models.DB.Where("username = ?", username).First(&user).Error; err != nil {
+		fmt.Fprintf(os.Stderr, "User not found: %s\n", username)
+		return 1
+	}
+	sessions, tokens, err := models.DeactivateUser(models.DB, user.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to disable user: %v\n", err)
+		return 1
+	}
+	fmt.Printf("User disabled: %s (%d sessions and %d API tokens revoked)\n", user.Username, sessions, tokens)
+	return 0
+}
+
+// runToken 执行 token 子命令：create 为用户创建 API Token，明文只输出一次；返回进程退出码
+func runToken(args []string) int {
+	usage := "usage: token create [-name <name>] [-allow-cidr <cidr,...>] <username>"
+	if len(args) == 0 || args[0] != "create" {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+	name := fs.String("name", "cli", "Token 名称")
+	allowCIDR := fs.String("allow-cidr", "", "可选：来源地址白名单，多个 IP 或 CIDR 以逗号分隔")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, usage)
+		return 2
+	}
+	cidrs, err := models.NormalizeCIDRs(strings.Split(*allowCIDR, ","))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 2
+	}
+
+	if err := models.InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer models.CloseDB()
+
+	var user models.User
+	if err := models.DB.Where("username = ? AND is_active = ?", fs.Arg(0), true).First(&user).Error; err != nil {
+		fmt.Fprintf(os.Stderr, "User not found or inactive: %s\n", fs.Arg(0))
+		return 1
+	}
+	raw, err := createToken(user.ID, *name, cidrs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create API token: %v\n", err)
+		return 1
+	}
+	// 只将 Token 写到标准输出，便于脚本读取
+	fmt.Println(raw)
+	return 0
+}
+
+// createToken 为用户创建 API Token，返回明文；cidrs 为空时不限制来源地址
+func createToken(userID, name string, cidrs []string) (string, error) {
+	raw := randHex(32)
+	hash := sha256.Sum256([]byte(raw))
+	ut := &models.UserToken{
+		ID:        "tok_" + randHex(12),
+		UserID:    userID,
+		Name:      name,
+		TokenHash: hex.EncodeToString(hash[:]),
+	}
+	ut.SetCIDRs(cidrs)
+	return raw, models.DB.Create(ut).Error
+}
+
+func randHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
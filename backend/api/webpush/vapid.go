@@ -0,0 +1,112 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+)
+
+// Keys VAPID 密钥对（base64url 编码：公钥为 65 字节未压缩点，私钥为 32 字节标量）
+type Keys struct {
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+
+	signer *ecdsa.PrivateKey
+}
+
+// VAPID 全局密钥，由 Init 初始化
+var VAPID *Keys
+
+// Init 加载 VAPID 密钥：优先使用配置中的 push.vapid_public_key / push.vapid_private_key，
+// 否则读取 data_dir/vapid.json，不存在时自动生成并持久化，保证重启后浏览器订阅仍然有效
+func Init() error {
+	cfg := config.Get()
+	if pub, priv := cfg.Push.VAPIDPublicKey, cfg.Push.VAPIDPrivateKey; pub != "" || priv != "" {
+		keys, err := parseKeys(pub, priv)
+		if err != nil {
+			return err
+		}
+		VAPID = keys
+		return nil
+	}
+
+	path := filepath.Join(cfg.Server.DataDir, "vapid.json")
+	if data, err := os.ReadFile(path); err == nil {
+		var stored Keys
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return errors.New("webpush: invalid " + path + ": " + err.Error())
+		}
+		keys, err := parseKeys(stored.PublicKey, stored.PrivateKey)
+		if err != nil {
+			return err
+		}
+		VAPID = keys
+		return nil
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	keys, err := generateKeys()
+	if err != nil {
+		return err
+	}
+	data, _ := json.MarshalIndent(keys, "", "  ")
+	if err := os.MkdirAll(cfg.Server.DataDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	log.Printf("Generated VAPID keys at %s", path)
+	VAPID = keys
+	return nil
+}
+
+func generateKeys() (*Keys, error) {
+	priv, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return parseKeys(
+		base64.RawURLEncoding.EncodeToString(priv.PublicKey().Bytes()),
+		base64.RawURLEncoding.EncodeToString(priv.Bytes()),
+	)
+}
+
+func parseKeys(pub, priv string) (*Keys, error) {
+	d, err := base64.RawURLEncoding.DecodeString(priv)
+	if err != nil {
+		return nil, errors.New("webpush: invalid VAPID private key")
+	}
+	key, err := ecdh.P256().NewPrivateKey(d)
+	if err != nil {
+		return nil, errors.New("webpush: invalid VAPID private key")
+	}
+	point := key.PublicKey().Bytes()
+	if pub != "" && pub != base64.RawURLEncoding.EncodeToString(point) {
+		return nil, errors.New("webpush: VAPID public key does not match private key")
+	}
+	signer := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(point[1:33]),
+			Y:     new(big.Int).SetBytes(point[33:]),
+		},
+		D: new(big.Int).SetBytes(d),
+	}
+	return &Keys{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(point),
+		PrivateKey: priv,
+		signer:     signer,
+	}, nil
+}
@@ -0,0 +1,185 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZeroHawkeye/siyuan-share-api/config"
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+// ErrGone 推送服务返回 404/410，订阅已失效，调用方应删除该订阅
+var ErrGone = errors.New("webpush: subscription is no longer valid")
+
+// Subscription 浏览器 PushSubscription（endpoint + keys）
+type Subscription struct {
+	Endpoint string `json:"endpoint"`
+	P256dh   string `json:"p256dh"`
+	Auth     string `json:"auth"`
+}
+
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Send 加密（RFC 8291 aes128gcm）并投递推送消息，ttl 为推送服务暂存消息的秒数
+func Send(ctx context.Context, sub *Subscription, payload []byte, ttl int) error {
+	if VAPID == nil {
+		return errors.New("webpush: VAPID keys not initialized")
+	}
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return errors.New("webpush: invalid endpoint")
+	}
+
+	body, err := encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	auth, err := vapidAuthorization(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(ttl))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", auth)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("webpush: push service responded %s", resp.Status)
+	}
+	return nil
+}
+
+// vapidAuthorization 生成 VAPID 认证头（RFC 8292）
+func vapidAuthorization(audience string) (string, error) {
+	subject := config.Get().Push.VAPIDSubject
+	if subject == "" {
+		subject = "mailto:admin@localhost"
+	}
+	claims := jwt.MapClaims{
+		"aud": audience,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": subject,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(VAPID.signer)
+	if err != nil {
+		return "", err
+	}
+	return "vapid t=" + token + ", k=" + VAPID.PublicKey, nil
+}
+
+// encrypt 按 RFC 8291 加密消息体：单条记录，头部携带 salt、记录大小与临时公钥
+func encrypt(sub *Subscription, payload []byte) ([]byte, error) {
+	uaPublicBytes, err := decodeKey(sub.P256dh)
+	if err != nil {
+		return nil, errors.New("webpush: invalid p256dh key")
+	}
+	authSecret, err := decodeKey(sub.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("webpush: invalid auth secret")
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, errors.New("webpush: invalid p256dh key")
+	}
+
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	prkKey, err := hkdf.Extract(sha256.New, sharedSecret, authSecret)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(uaPublicBytes) + string(asPublicBytes)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// 末尾 0x02 为最后一条记录的分隔符
+	plaintext := append(append([]byte{}, payload...), 0x02)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+
+	recordSize := uint32(len(ciphertext))
+	if recordSize < 4096 {
+		recordSize = 4096
+	}
+	var buf bytes.Buffer
+	buf.Write(salt)
+	_ = binary.Write(&buf, binary.BigEndian, recordSize)
+	buf.WriteByte(byte(len(asPublicBytes)))
+	buf.Write(asPublicBytes)
+	buf.Write(ciphertext)
+	return buf.Bytes(), nil
+}
+
+// decodeKey 兼容浏览器返回的 base64url（可能带填充）与标准 base64
+func decodeKey(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	if b, err := base64.RawURLEncoding.DecodeString(s); err == nil {
+		return b, nil
+	}
+	return base64.RawStdEncoding.DecodeString(s)
+}
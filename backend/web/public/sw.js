@@ -0,0 +1,150 @@
+// 思源分享的 Service Worker：浏览器推送通知与分享的离线阅读
+
+// 浏览器推送：展示服务端推送的消息，点击后打开对应页面
+self.addEventListener('push', (event) => {
+  let data = {}
+  try {
+    data = event.data ? event.data.json() : {}
+  } catch {
+    data = { title: '思源分享', body: event.data ? event.data.text() : '' }
+  }
+  event.waitUntil(
+    self.registration.showNotification(data.title || '思源分享', {
+      body: data.body || '',
+      icon: '/favicon.ico',
+      data: { url: data.url || '/' },
+    })
+  )
+})
+
+self.addEventListener('notificationclick', (event) => {
+  event.notification.close()
+  const url = (event.notification.data && event.notification.data.url) || '/'
+  event.waitUntil(self.clients.openWindow(url))
+})
+
+// 离线阅读：阅读页保存分享时发来离线清单（GET /api/s/:id/offline），预缓存清单中的资源地址；
+// 之后阅读页数据与页面本身在线时从网络获取并更新缓存，离线时从缓存读取
+const SHELL_CACHE = 'app-shell'
+const SHARE_CACHE_PREFIX = 'share-'
+const MANIFEST_KEY = '/__offline__/manifest'
+
+const shareCache = (id) => SHARE_CACHE_PREFIX + id
+
+const isSaved = (id) => caches.has(shareCache(id))
+
+// 跨域地址（CDN）以 no-cors 请求，缓存不透明响应
+const fetchForCache = (url) =>
+  fetch(url, new URL(url).origin === self.location.origin ? { credentials: 'same-origin' } : { mode: 'no-cors' })
+
+// 缓存阅读页用到的脚本与样式，离线时页面才能启动
+const cacheShell = async (pageUrl) => {
+  const res = await fetch(pageUrl, { credentials: 'same-origin' })
+  if (!res.ok) return
+  const html = await res.clone().text()
+  const cache = await caches.open(SHELL_CACHE)
+  await cache.put('/index.html', res)
+  const refs = new Set([...html.matchAll(/(?:src|href)="(\/assets\/[^"]+)"/g)].map((m) => m[1]))
+  await Promise.all([...refs].map(async (ref) => {
+    if (await cache.match(ref)) return
+    const r = await fetch(ref)
+    if (r.ok) await cache.put(ref, r)
+  }))
+}
+
+// 保存分享的离线副本：版本未变时不重新下载，返回是否下载了新版本。阅读页数据 payload 由阅读页提供，
+// 请求阅读页数据会计入浏览
+const saveShare = async (manifest, payload) => {
+  const name = shareCache(manifest.shareId)
+  const existing = await caches.open(name)
+  const prev = await existing.match(MANIFEST_KEY)
+  if (prev && (await prev.json()).version === manifest.version) return false
+
+  await caches.delete(name)
+  const cache = await caches.open(name)
+  await Promise.all(manifest.urls.map(async (url) => {
+    try {
+      const res = await fetchForCache(url)
+      if (res.ok || res.type === 'opaque') await cache.put(url, res)
+    } catch {}
+  }))
+  await cacheShell(manifest.page).catch(() => {})
+  if (payload) {
+    await cache.put(manifest.data, new Response(JSON.stringify(payload), { headers: { 'Content-Type': 'application/json' } }))
+  }
+  await cache.put(MANIFEST_KEY, new Response(JSON.stringify(manifest), { headers: { 'Content-Type': 'application/json' } }))
+  return true
+}
+
+// 首次注册后立即接管已打开的阅读页，保存离线副本后无需刷新即可缓存后续请求
+self.addEventListener('activate', (event) => {
+  event.waitUntil(self.clients.claim())
+})
+
+self.addEventListener('message', (event) => {
+  const data = event.data || {}
+  const reply = (result) => event.ports[0] && event.ports[0].postMessage(result)
+  if (data.type === 'offline-save' && data.manifest) {
+    event.waitUntil(saveShare(data.manifest, data.payload).then(
+      (updated) => reply({ ok: true, updated }),
+      (err) => reply({ ok: false, error: String(err) }),
+    ))
+  } else if (data.type === 'offline-remove' && data.shareId) {
+    event.waitUntil(caches.delete(shareCache(data.shareId)).then(() => reply({ ok: true })))
+  }
+})
+
+// 网络优先：成功时以 key 更新离线副本，失败时读取缓存
+const networkFirst = async (request, id, key) => {
+  try {
+    const res = await fetch(request)
+    if (res.ok && await isSaved(id)) {
+      const cache = await caches.open(shareCache(id))
+      await cache.put(key, res.clone())
+    }
+    return res
+  } catch (err) {
+    const cache = await caches.open(shareCache(id))
+    const hit = await cache.match(key)
+    if (hit) return hit
+    throw err
+  }
+}
+
+self.addEventListener('fetch', (event) => {
+  const request = event.request
+  if (request.method !== 'GET' || request.headers.has('range')) return
+  const url = new URL(request.url)
+  const sameOrigin = url.origin === self.location.origin
+
+  // 阅读页：离线时返回缓存的页面入口
+  const page = sameOrigin && request.mode === 'navigate' && url.pathname.match(/^\/s\/([^/]+)\/?$/)
+  if (page) {
+    event.respondWith(
+      fetch(request).catch(async () => (await caches.match('/index.html', { cacheName: SHELL_CACHE })) || Response.error()),
+    )
+    return
+  }
+
+  // 阅读页数据：密码等查询参数不参与缓存键
+  const data = sameOrigin && url.pathname.match(/^\/api\/s\/([^/]+)$/)
+  if (data) {
+    event.respondWith(networkFirst(request, data[1], url.origin + url.pathname))
+    return
+  }
+
+  // 前端脚本与样式、分享资源：已缓存时直接使用，签名地址过期后按路径匹配
+  if (!sameOrigin || url.pathname.startsWith('/assets/') || url.pathname.startsWith('/api/s/')) {
+    event.respondWith((async () => {
+      const hit = await caches.match(request)
+      if (hit) return hit
+      try {
+        return await fetch(request)
+      } catch (err) {
+        const fallback = url.pathname.startsWith('/api/s/') && await caches.match(request, { ignoreSearch: true })
+        if (fallback) return fallback
+        throw err
+      }
+    })())
+  }
+})
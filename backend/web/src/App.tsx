@@ -1,8 +1,13 @@
 import { Route, Routes } from 'react-router-dom'
 import './App.css'
+import CollectionList from './pages/CollectionList'
+import CollectionView from './pages/CollectionView'
 import Dashboard from './pages/Dashboard'
+import DomainList from './pages/DomainList'
+import FlashcardView from './pages/FlashcardView'
 import Home from './pages/Home'
 import NotFound from './pages/NotFound.tsx'
+import ResetPassword from './pages/ResetPassword'
 import ShareList from './pages/ShareList'
 import ShareView from './pages/ShareView'
 
@@ -12,8 +17,14 @@ function App() {
       <Routes>
         <Route path="/" element={<Home />} />
         <Route path="/s/:shareId" element={<ShareView />} />
+        <Route path="/s/:shareId/cards" element={<FlashcardView />} />
+        <Route path="/s/:shareId/:lang" element={<ShareView />} />
+        <Route path="/c/:slug" element={<CollectionView />} />
         <Route path="/dashboard" element={<Dashboard />} />
         <Route path="/shares" element={<ShareList />} />
+        <Route path="/collections" element={<CollectionList />} />
+        <Route path="/domains" element={<DomainList />} />
+        <Route path="/reset-password" element={<ResetPassword />} />
         <Route path="*" element={<NotFound />} />
       </Routes>
     </div>
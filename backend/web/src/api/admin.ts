@@ -0,0 +1,201 @@
+import api from './index'
+import type { Announcement } from './announcement'
+
+// 实例指标，views 为现存分享的累计浏览量，storageBytes 为资源文件总大小
+export interface InstanceTotals {
+  users: number
+  shares: number
+  views: number
+  assets: number
+  storageBytes: number
+}
+
+export interface AdminStats extends InstanceTotals {
+  sharesPerDay: { date: string; count: number }[]
+  topShares: {
+    id: string
+    docTitle: string
+    username: string
+    viewCount: number
+    createdAt: string
+  }[]
+}
+
+// 每日汇总，date 为服务器本地日期 YYYY-MM-DD
+export interface InstanceStat extends InstanceTotals {
+  date: string
+  updatedAt: string
+}
+
+/**
+ * 实例概览（仅管理员）
+ */
+export const getAdminStats = async (days = 30): Promise<{ code: number; msg: string; data?: AdminStats }> => {
+  return api.get('/api/admin/stats', { params: { days } })
+}
+
+/**
+ * 每日指标趋势（仅管理员）
+ */
+export const getStatsHistory = async (days = 30): Promise<{ code: number; msg: string; data?: { days: number; items: InstanceStat[] } }> => {
+  return api.get('/api/admin/stats/history', { params: { days } })
+}
+
+// 定时任务的配置与最近一次执行情况（只保存在内存中，服务重启后重新计数）
+export interface Job {
+  name: string
+  description: string
+  enabled: boolean
+  interval: string
+  running: boolean
+  runs: number
+  failures: number
+  skipped: number // 本实例不是主实例而跳过的定时执行次数
+  lastStart?: string
+  lastDurationMs: number
+  lastResult?: string
+  lastError?: string
+  nextRun?: string
+}
+
+/**
+ * 定时任务列表（仅管理员）
+ */
+export const listJobs = async (): Promise<{ code: number; msg: string; data?: Job[] }> => {
+  return api.get('/api/admin/jobs')
+}
+
+/**
+ * 立即执行一次定时任务（仅管理员），任务在后台执行
+ */
+export const runJob = async (name: string): Promise<{ code: number; msg: string }> => {
+  return api.post(`/api/admin/jobs/${encodeURIComponent(name)}/run`)
+}
+
+// 邀请码，maxUses 为 0 表示不限次数，expiresAt 为空表示长期有效
+export interface InviteCode {
+  code: string
+  createdBy: string
+  maxUses: number
+  uses: number
+  expiresAt?: string
+  note: string
+  createdAt: string
+  usable: boolean
+}
+
+/**
+ * 邀请码列表与当前注册方式（仅管理员）
+ */
+export const listInvites = async (): Promise<{ code: number; msg: string; data?: { registration: string; items: InviteCode[] } }> => {
+  return api.get('/api/admin/invites')
+}
+
+/**
+ * 生成邀请码（仅管理员）
+ */
+export const createInvite = async (params: { maxUses?: number; expiresDays?: number; note?: string }): Promise<{ code: number; msg: string; data?: InviteCode }> => {
+  return api.post('/api/admin/invites', params)
+}
+
+/**
+ * 删除邀请码（仅管理员）
+ */
+export const deleteInvite = async (code: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/admin/invites/${encodeURIComponent(code)}`)
+}
+
+// 只读 SQL 查询结果，二进制值显示为长度，过长的文本被截断
+export interface SQLQueryResult {
+  columns: string[]
+  rows: any[][]
+  truncated: boolean
+  elapsedMs: number
+}
+
+/**
+ * 执行一条只读 SQL 查询（仅管理员，需服务器开启查询控制台）
+ */
+export const runSQLQuery = async (query: string): Promise<{ code: number; msg: string; data?: SQLQueryResult }> => {
+  return api.post('/api/admin/sql', { query })
+}
+
+// 读者举报，附带被举报分享的标题、状态与所有者
+export interface ShareReport {
+  id: string
+  shareId: string
+  reason: string
+  contact: string
+  reporterId?: string
+  ip: string
+  status: 'open' | 'dismissed' | 'actioned'
+  action?: 'dismiss' | 'disable' | 'ban'
+  note?: string
+  resolvedBy?: string
+  resolvedAt?: string
+  createdAt: string
+  docTitle: string
+  shareStatus: string
+  moderatedAt?: string
+  ownerId: string
+  owner: string
+  ownerActive: boolean
+}
+
+/**
+ * 举报列表（仅管理员），status 为 open（默认）、dismissed、actioned 或 all
+ */
+export const listReports = async (params: { status?: string; page?: number; size?: number } = {}): Promise<{ code: number; msg: string; data?: { items: ShareReport[]; total: number; page: number; size: number } }> => {
+  return api.get('/api/admin/reports', { params })
+}
+
+/**
+ * 处理举报（仅管理员）：dismiss 驳回，disable 停用分享，ban 封禁所有者并停用其全部分享
+ */
+export const resolveReport = async (id: string, action: 'dismiss' | 'disable' | 'ban', note?: string): Promise<{ code: number; msg: string; data?: { id: string; action: string; disabledShares?: string[] } }> => {
+  return api.post(`/api/admin/reports/${encodeURIComponent(id)}/resolve`, { action, note })
+}
+
+/**
+ * 解除管理员对分享的停用（仅管理员），分享仍保持停用，由所有者决定是否重新上线
+ */
+export const reinstateShare = async (id: string): Promise<{ code: number; msg: string }> => {
+  return api.post(`/api/admin/shares/${encodeURIComponent(id)}/reinstate`)
+}
+
+// 公告管理列表的条目，active 表示当前处于显示时间段内，dismissals 为关闭该公告的登录用户数
+export interface AdminAnnouncement extends Announcement {
+  createdBy: string
+  active: boolean
+  dismissals: number
+}
+
+export type AnnouncementInput = Pick<Announcement, 'title' | 'content' | 'link' | 'level' | 'audience' | 'dismissible' | 'startsAt' | 'endsAt'>
+
+/**
+ * 全部公告，含未开始与已结束的（仅管理员）
+ */
+export const listAdminAnnouncements = async (): Promise<{ code: number; msg: string; data?: { items: AdminAnnouncement[] } }> => {
+  return api.get('/api/admin/announcements')
+}
+
+/**
+ * 发布公告（仅管理员）
+ */
+export const createAnnouncement = async (input: AnnouncementInput): Promise<{ code: number; msg: string; data?: Announcement }> => {
+  return api.post('/api/admin/announcements', input)
+}
+
+/**
+ * 修改公告（仅管理员），resetDismissals 为 true 时已关闭的用户重新看到公告
+ */
+export const updateAnnouncement = async (id: number, input: AnnouncementInput, resetDismissals = false): Promise<{ code: number; msg: string; data?: Announcement }> => {
+  return api.put(`/api/admin/announcements/${id}`, input, { params: resetDismissals ? { resetDismissals: 1 } : undefined })
+}
+
+/**
+ * 删除公告（仅管理员）
+ */
+export const deleteAnnouncement = async (id: number): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/admin/announcements/${id}`)
+}
@@ -0,0 +1,33 @@
+import api from './index'
+
+// 全站公告，startsAt / endsAt 为空表示立即显示 / 一直显示
+export interface Announcement {
+  id: number
+  title: string
+  content?: string
+  link?: string
+  level: 'info' | 'warning' | 'error'
+  audience: 'all' | 'dashboard' | 'shares'
+  dismissible: boolean
+  startsAt?: string
+  endsAt?: string
+  createdAt: string
+  updatedAt: string
+}
+
+// 匿名读者关闭公告后在 localStorage 中的键，公告修改后重新显示
+export const dismissedAnnouncementKey = (a: Announcement) => `announcement_dismissed:${a.id}:${a.updatedAt}`
+
+/**
+ * 当前显示的公告，scope 为 dashboard（控制面板）或 shares（阅读页）；登录用户已关闭的公告不返回
+ */
+export const getAnnouncements = async (scope: 'dashboard' | 'shares'): Promise<{ code: number; msg: string; data?: { items: Announcement[] } }> => {
+  return api.get('/api/announcements', { params: { scope } })
+}
+
+/**
+ * 关闭公告（需登录），之后不再显示
+ */
+export const dismissAnnouncement = async (id: number): Promise<{ code: number; msg: string }> => {
+  return api.post(`/api/announcements/${id}/dismiss`)
+}
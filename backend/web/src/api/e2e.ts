@@ -0,0 +1,41 @@
+// 端到端加密分享：正文为 e2e1.<IV>.<密文>（base64url），插件以 AES-256-GCM 加密 {"title","content"} 的 JSON，
+// 密钥只出现在分享链接的 #key= 片段中，不会随请求发送到服务器，只在浏览器中解密
+
+export interface DecryptedShare {
+  title: string
+  content: string
+}
+
+// 解密成功后在本标签页记住密钥：点击目录等操作会改写地址的 # 片段，刷新后仍可解密
+export const shareKeyStorage = (shareId: string) => `share_key:${shareId}`
+
+const fromBase64Url = (s: string): Uint8Array => {
+  const b64 = s.replace(/-/g, '+').replace(/_/g, '/').padEnd(Math.ceil(s.length / 4) * 4, '=')
+  return Uint8Array.from(atob(b64), c => c.charCodeAt(0))
+}
+
+// keyFromText 从 #key=... 片段、完整分享链接或直接粘贴的密钥中取出密钥
+export const keyFromText = (text: string): string => {
+  const m = text.match(/[#&]key=([A-Za-z0-9_-]+)/)
+  return (m ? m[1] : text).trim()
+}
+
+// encryptionKey 当前分享的密钥：优先取地址中的 #key=，其次为本标签页已记住的密钥
+export const encryptionKey = (shareId: string): string => {
+  const m = window.location.hash.match(/[#&]key=([A-Za-z0-9_-]+)/)
+  return m ? m[1] : sessionStorage.getItem(shareKeyStorage(shareId)) || ''
+}
+
+/**
+ * 解密分享正文；密钥为空、错误或正文被篡改时抛出异常
+ */
+export const decryptShare = async (payload: string, key: string): Promise<DecryptedShare> => {
+  const parts = payload.split('.')
+  if (parts.length !== 3 || parts[0] !== 'e2e1') throw new Error('加密正文格式无效')
+  const raw = fromBase64Url(key)
+  if (raw.length !== 32) throw new Error('密钥无效')
+  const cryptoKey = await crypto.subtle.importKey('raw', raw, 'AES-GCM', false, ['decrypt'])
+  const plain = await crypto.subtle.decrypt({ name: 'AES-GCM', iv: fromBase64Url(parts[1]) }, cryptoKey, fromBase64Url(parts[2]))
+  const data = JSON.parse(new TextDecoder().decode(plain))
+  return { title: String(data.title || ''), content: String(data.content || '') }
+}
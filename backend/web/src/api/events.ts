@@ -0,0 +1,79 @@
+import { useEffect, useRef } from 'react'
+
+export type LiveEventType = 'share_viewed' | 'share_published' | 'token_used'
+
+export interface LiveEvent {
+  type: LiveEventType
+  time: string
+  shareId?: string
+  title?: string
+  viewCount?: number
+  reused?: boolean
+  tokenId?: string
+  ip?: string
+}
+
+const baseURL = import.meta.env.DEV ? (import.meta.env.VITE_API_URL || 'http://localhost:8080') : ''
+
+// 解析 SSE 文本中的完整消息，返回未读完的剩余部分
+const parseMessages = (buf: string, onEvent: (ev: LiveEvent) => void) => {
+  const parts = buf.split('\n\n')
+  for (const part of parts.slice(0, -1)) {
+    const data = part.split('\n').filter(l => l.startsWith('data:')).map(l => l.slice(5).trim()).join('\n')
+    if (!data) continue
+    try {
+      onEvent(JSON.parse(data))
+    } catch {}
+  }
+  return parts[parts.length - 1]
+}
+
+// 订阅控制面板的实时事件（/api/events）。EventSource 不能附带 Authorization 请求头，这里用 fetch 读取事件流；
+// 断线后按退避时间重连，重连成功时调用 onReconnect，调用方应重新加载一次数据以补上断线期间的变化。返回取消函数
+export const subscribeEvents = (onEvent: (ev: LiveEvent) => void, onReconnect?: () => void) => {
+  const controller = new AbortController()
+  let delay = 1000
+  let connected = false
+
+  const connect = async () => {
+    while (!controller.signal.aborted) {
+      try {
+        const token = localStorage.getItem('session_token')
+        if (!token) return
+        const res = await fetch(`${baseURL}/api/events`, {
+          headers: { Authorization: `Bearer ${token}` },
+          signal: controller.signal,
+        })
+        if (res.status === 401) return
+        if (!res.ok || !res.body) throw new Error(res.statusText)
+        if (connected) onReconnect?.()
+        connected = true
+        delay = 1000
+        const reader = res.body.getReader()
+        const decoder = new TextDecoder()
+        let buf = ''
+        for (;;) {
+          const { done, value } = await reader.read()
+          if (done) break
+          buf = parseMessages(buf + decoder.decode(value, { stream: true }).replace(/\r\n/g, '\n'), onEvent)
+        }
+      } catch {
+        if (controller.signal.aborted) return
+      }
+      await new Promise(r => setTimeout(r, delay))
+      delay = Math.min(delay * 2, 30000)
+    }
+  }
+  connect()
+  return () => controller.abort()
+}
+
+// 在组件挂载期间订阅实时事件，处理函数总是使用最新的闭包
+export const useLiveEvents = (onEvent: (ev: LiveEvent) => void, onReconnect?: () => void) => {
+  const handlers = useRef({ onEvent, onReconnect })
+  handlers.current = { onEvent, onReconnect }
+  useEffect(() => subscribeEvents(
+    ev => handlers.current.onEvent(ev),
+    () => handlers.current.onReconnect?.(),
+  ), [])
+}
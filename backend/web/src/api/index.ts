@@ -1,4 +1,5 @@
 import axios from 'axios';
+import { solveChallenge } from '../components/ChallengeModal'
 
 // 生产环境使用相对路径，开发环境使用环境变量指定的完整URL
 const api = axios.create({
@@ -16,6 +17,42 @@ api.interceptors.request.use(
         config.headers = config.headers || {}
         ;(config.headers as any)['Authorization'] = `Bearer ${token}`
       }
+      // 受限分享通过邮件链接验证后获得的访问令牌
+      const m = config.url?.match(/^\/api\/s\/([^/?]+)/)
+      const access = m && localStorage.getItem(`share_access:${m[1]}`)
+      if (access) {
+        config.headers = config.headers || {}
+        ;(config.headers as any)['X-Share-Access'] = access
+      }
+      // 同意使用条款后获得的令牌
+      const terms = m && localStorage.getItem(`share_terms:${m[1]}`)
+      if (terms) {
+        config.headers = config.headers || {}
+        ;(config.headers as any)['X-Share-Terms'] = terms
+      }
+      // 付费分享支付后获得的解锁令牌
+      const unlock = m && localStorage.getItem(`share_unlock:${m[1]}`)
+      if (unlock) {
+        config.headers = config.headers || {}
+        ;(config.headers as any)['X-Share-Unlock'] = unlock
+      }
+      // 限制浏览次数的分享计入浏览后获得的令牌，用尽后短时间内继续加载阅读页数据
+      const view = m && sessionStorage.getItem(`share_view:${m[1]}`)
+      if (view) {
+        config.headers = config.headers || {}
+        ;(config.headers as any)['X-Share-View'] = view
+      }
+      // 阅读页的来源页面，阅读页数据由脚本请求，请求自带的 Referer 只是阅读页本身
+      if (m && document.referrer) {
+        config.headers = config.headers || {}
+        ;(config.headers as any)['X-Share-Referrer'] = document.referrer
+      }
+      // 服务端导出 PDF 时无头浏览器打开的打印页
+      const print = m && new URLSearchParams(window.location.search).get('print')
+      if (print) {
+        config.headers = config.headers || {}
+        ;(config.headers as any)['X-Share-Print'] = print
+      }
     } catch {}
     return config
   },
@@ -29,7 +66,23 @@ api.interceptors.response.use(
   (response) => {
     return response.data
   },
-  (error) => {
+  async (error) => {
+    // 需要人机验证（注册、多次失败后的登录与分享密码、匿名评论与举报）：完成验证后携带令牌重试一次
+    const data = error?.response?.data
+    const config = error?.config
+    if (data?.code === 1012 && config && !config._challenge) {
+      const token = await solveChallenge(data.data?.provider, data.data?.siteKey)
+      if (token) {
+        config._challenge = true
+        config.headers = config.headers || {}
+        config.headers['X-Challenge-Token'] = token
+        return api.request(config)
+      }
+    }
+    // 错误提示附带请求 ID，便于用户反馈时对应服务端日志
+    if (data && typeof data.msg === 'string' && data.requestId) {
+      data.msg = `${data.msg}（请求 ID：${data.requestId}）`
+    }
     return Promise.reject(error)
   }
 )
@@ -0,0 +1,62 @@
+import api from './index'
+
+interface ApiResp<T = any> { code: number; msg: string; data: T }
+
+// 离线阅读清单：预缓存的地址与内容版本，见 GET /api/s/:id/offline
+export interface OfflineManifest {
+  shareId: string
+  version: string
+  updatedAt: string
+  page: string
+  data: string
+  urls: string[]
+}
+
+export const offlineSupported = () => 'serviceWorker' in navigator && 'caches' in window
+
+// 已保存离线副本的分享在本地记录版本
+export const offlineKey = (shareId: string) => `share_offline:${shareId}`
+
+const getOfflineManifest = async (shareId: string, password?: string) => {
+  const params = password ? { password } : {}
+  const res = await api.get(`/api/s/${shareId}/offline`, { params }) as ApiResp<OfflineManifest>
+  if (res.code !== 0) throw new Error(res.msg || '获取离线清单失败')
+  return res.data
+}
+
+// 向 Service Worker 发送消息并等待结果
+const postToWorker = async <T = any>(message: any): Promise<T> => {
+  const registration = await navigator.serviceWorker.register('/sw.js')
+  await navigator.serviceWorker.ready
+  const worker = registration.active || navigator.serviceWorker.controller
+  if (!worker) throw new Error('Service Worker 未就绪')
+  return new Promise<T>((resolve, reject) => {
+    const channel = new MessageChannel()
+    channel.port1.onmessage = (e) => (e.data?.ok ? resolve(e.data) : reject(new Error(e.data?.error || '保存失败')))
+    worker.postMessage(message, [channel.port2])
+  })
+}
+
+/**
+ * 保存分享的离线副本；版本与已保存的相同时不重新下载，返回是否下载了新版本。
+ * payload 为阅读页已加载的分享数据，避免 Service Worker 再次请求而计入浏览
+ */
+export const saveShareOffline = async (shareId: string, payload: any, password?: string): Promise<boolean> => {
+  if (!offlineSupported()) throw new Error('当前浏览器不支持离线阅读')
+  const manifest = await getOfflineManifest(shareId, password)
+  const res = await postToWorker<{ updated: boolean }>({
+    type: 'offline-save',
+    manifest,
+    payload: { code: 0, msg: 'success', data: payload },
+  })
+  localStorage.setItem(offlineKey(shareId), manifest.version)
+  return res.updated
+}
+
+/**
+ * 删除分享的离线副本
+ */
+export const removeShareOffline = async (shareId: string) => {
+  localStorage.removeItem(offlineKey(shareId))
+  if (offlineSupported()) await postToWorker({ type: 'offline-remove', shareId })
+}
@@ -0,0 +1,47 @@
+import api from './index'
+
+interface ApiResp<T = any> { code: number; msg: string; data: T }
+
+export const pushSupported = () =>
+  'serviceWorker' in navigator && 'PushManager' in window && 'Notification' in window
+
+const urlBase64ToUint8Array = (base64: string) => {
+  const padding = '='.repeat((4 - (base64.length % 4)) % 4)
+  const raw = atob((base64 + padding).replace(/-/g, '+').replace(/_/g, '/'))
+  return Uint8Array.from(raw, c => c.charCodeAt(0))
+}
+
+// 请求通知权限并订阅浏览器推送，返回 PushSubscription 的 JSON 形式
+export const subscribeBrowserPush = async (): Promise<PushSubscriptionJSON> => {
+  if (!pushSupported()) throw new Error('当前浏览器不支持推送通知')
+  const permission = await Notification.requestPermission()
+  if (permission !== 'granted') throw new Error('未授予通知权限')
+
+  const key = await api.get('/api/push/vapid-public-key') as ApiResp<{ publicKey: string }>
+  if (key.code !== 0) throw new Error(key.msg || '推送服务不可用')
+
+  const registration = await navigator.serviceWorker.register('/sw.js')
+  await navigator.serviceWorker.ready
+  const subscription = await registration.pushManager.getSubscription() ||
+    await registration.pushManager.subscribe({
+      userVisibleOnly: true,
+      applicationServerKey: urlBase64ToUint8Array(key.data.publicKey),
+    })
+  return subscription.toJSON()
+}
+
+// 仪表盘：登记当前浏览器接收账号通知
+export const enableDashboardPush = async () => {
+  const subscription = await subscribeBrowserPush()
+  const res = await api.post('/api/push/subscriptions', subscription) as ApiResp<{ id: string }>
+  if (res.code !== 0) throw new Error(res.msg || '开启通知失败')
+  return res.data
+}
+
+// 分享页：订阅该分享的更新推送
+export const subscribeSharePush = async (shareId: string) => {
+  const subscription = await subscribeBrowserPush()
+  const res = await api.post(`/api/s/${shareId}/subscribe`, { channel: 'push', subscription }) as ApiResp<{ unsubscribeUrl: string }>
+  if (res.code !== 0) throw new Error(res.msg || '订阅失败')
+  return res.data
+}
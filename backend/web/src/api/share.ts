@@ -5,9 +5,235 @@ export interface ShareData {
   docTitle: string
   content: string
   requirePassword: boolean
+  restricted?: boolean
+  allowPdf?: boolean
+  assetDownloads?: AssetDownloads
+  allowQa?: boolean
   expireAt: string
   viewCount: number
   createdAt: string
+  linkPreviews?: Record<string, LinkPreview>
+  archivedLinks?: Record<string, string>
+  narration?: { url: string; contentType: string } | null
+  bibliography?: BibliographyEntry[]
+  tables?: ShareTable[]
+  tasks?: TaskStats
+  mode?: 'doc' | 'flashcards' | 'encrypted'
+  cardCount?: number
+  status?: ShareStatus
+  seal?: ShareSeal | null
+  signature?: ShareSignature | null
+  viewLimit?: ViewLimit | null
+  lang?: string // 当前版本的语言，原文无法判断时为空
+  sourceLang?: string // 译文页中原文的语言
+  translations?: string[] // 可供阅读的译文语言
+  toc?: ShareTOC
+  headingAnchors?: boolean
+  headings?: ShareHeading[] // 正文标题，id 为稳定的锚点（#id）
+  prerender?: SharePrerender // 实际生效的服务端预渲染项
+  variant?: 'a' | 'b' | '' // 主题 A/B 测试中读者所在的分组，未开启测试时为空
+  allowFeedback?: boolean // 文末询问读者「是否有帮助」
+  trackReading?: boolean // 离开时上报读到的标题（阅读漏斗）
+  readingMinutes?: number // 预计阅读分钟数
+  wordCount?: number
+  paywall?: boolean
+  donation?: DonationLinks | null // 分享者的赞助链接
+  teaser?: ShareTeaser // 试读：正文仅为开头部分，gate 为阅读全文需要完成的校验
+}
+
+// 试读分享中阅读全文需要完成的校验：输入密码、付费或登录
+export interface ShareTeaser {
+  gate: 'password' | 'payment' | 'login'
+  price?: string
+  payable?: boolean
+}
+
+// 服务端预渲染：公式、Mermaid 图与代码高亮
+export interface SharePrerender {
+  math: boolean
+  mermaid: boolean
+  code: boolean
+}
+
+// 阅读页目录样式：固定在侧边、悬浮在正文右上角或不显示
+export type ShareTOC = 'side' | 'floating' | 'off'
+
+// 正文标题：锚点按标题文字生成，同名标题依次加 -1、-2 后缀
+export interface ShareHeading {
+  id: string
+  text: string
+  level: number
+}
+
+// 封存信息：hash 为封存时正文 Markdown 源文的 SHA-256，intact 表示当前正文与之一致
+export interface ShareSeal {
+  sealed: boolean
+  sealedAt: string
+  sealedUntil: string
+  algorithm: 'sha256'
+  hash: string
+  intact: boolean
+}
+
+// 当前版本的签名：实例密钥（Ed25519）对版本声明的签名，contentHash 为正文 Markdown 源文的 SHA-256
+export interface ShareSignature {
+  version: number
+  algorithm: 'ed25519'
+  contentHash: string
+  signature: string
+  keyId: string
+}
+
+// 副本校验结果：match 表示与某个发布过的版本一致，current 表示即当前版本
+export interface ShareVerifyResult {
+  match: boolean
+  contentHash: string
+  version?: number
+  current?: boolean
+  publishedAt?: string
+  signatureValid?: boolean
+}
+
+// 浏览次数限制：viewsLeft 为本次之后剩余的次数，viewToken 供用尽后短时间内继续加载资源
+export interface ViewLimit {
+  maxViews: number
+  viewsLeft: number
+  viewToken: string
+}
+
+// 浏览令牌只在当前标签页内有效
+export const shareViewKey = (shareId: string) => `share_view:${shareId}`
+
+// 附件下载策略：允许下载、仅内联预览、下载时需再次输入访问密码
+export type AssetDownloads = 'allow' | 'inline' | 'password'
+
+// 分享生命周期状态：草稿仅所有者可预览，不公开列出的分享可通过链接访问但不出现在搜索、订阅源与日历中
+export type ShareStatus = 'draft' | 'published' | 'unlisted' | 'disabled'
+
+// 允许的状态变更，发布过的分享不能再回到草稿
+export const shareStatusTransitions: Record<ShareStatus, ShareStatus[]> = {
+  draft: ['published', 'unlisted', 'disabled'],
+  published: ['unlisted', 'disabled'],
+  unlisted: ['published', 'disabled'],
+  disabled: ['published', 'unlisted'],
+}
+
+// 正文任务列表的完成情况，sections 按标题（看板中为列）分组
+export interface TaskStats {
+  total: number
+  done: number
+  sections: TaskSection[]
+}
+
+export interface TaskSection {
+  title: string
+  line: number
+  kanban?: boolean
+  total: number
+  done: number
+}
+
+// 较大的表格附带结构化数据，阅读页据此提供排序、筛选与 CSV 下载
+export interface ShareTable {
+  index: number
+  line: number
+  headers: string[]
+  align: ('left' | 'center' | 'right' | '')[]
+  rows: string[][]
+}
+
+// 闪卡卡组，scheduler 为服务端推荐的 SM-2 参数
+export interface Flashcard {
+  id: string
+  blockId?: string
+  front: string
+  back: string
+  tags?: string[]
+}
+
+export interface FlashcardScheduler {
+  algorithm: string
+  initialEase: number
+  minEase: number
+  learningSteps: number[]
+  graduatingInterval: number
+  easyInterval: number
+}
+
+export interface FlashcardDeck {
+  deck: { id: string; name: string; cardCount: number; updatedAt: string }
+  scheduler: FlashcardScheduler
+  cards: Flashcard[]
+}
+
+// 音视频文字稿：vtt 附带时间轴段落（秒），text 为纯文本全文
+export interface TranscriptCue {
+  start: number
+  end: number
+  text: string
+}
+
+export interface MediaTranscript {
+  path: string
+  url: string
+  format: 'vtt' | 'text'
+  language?: string
+  cues?: TranscriptCue[]
+  vttUrl?: string
+  text?: string
+}
+
+export interface BibliographyEntry {
+  id: string
+  anchor: string
+  text: string
+  doi?: string
+  url?: string
+}
+
+export interface LinkPreview {
+  url: string
+  title: string
+  description?: string
+  image?: string
+  siteName?: string
+  fetchedAt: string
+}
+
+// 思维导图代码块解析出的节点树，line 为代码块起始行
+export interface MindmapNode {
+  text: string
+  children?: MindmapNode[]
+}
+
+export interface ShareMindmap {
+  index: number
+  line: number
+  hash: string
+  root?: MindmapNode
+  nodes?: number
+  svgUrl?: string
+  error?: string
+}
+
+// 由服务端配置的外部渲染插件（如 abc、graphviz、typst）渲染为图片的代码块
+export interface ShareRender {
+  index: number
+  line: number
+  lang: string
+  hash: string
+  url: string
+}
+
+// 读者评论：匿名评论需分享者审核（pending）后公开
+export interface ShareComment {
+  id: string
+  shareId: string
+  userId?: string
+  author: string
+  content: string
+  status: 'pending' | 'approved'
+  createdAt: string
 }
 
 export interface ShareResponse {
@@ -23,7 +249,25 @@ export interface ShareListItem {
   requirePassword: boolean
   expireAt: string
   isPublic: boolean
+  restricted?: boolean
+  accessSchedule?: AccessSchedule | null
+  hasTerms?: boolean
+  maxViews?: number
+  paywall?: boolean
+  paywallPrice?: string
+  paywallUrl?: string
+  teaser?: boolean
+  sealedUntil?: string
   viewCount: number
+  status: ShareStatus
+  tasksTotal?: number
+  tasksDone?: number
+  readingMinutes?: number // 预计阅读分钟数
+  trackReading?: boolean // 统计读者读到了哪个标题
+  toc?: ShareTOC
+  theme?: string
+  variant?: ShareVariant | null
+  headingAnchors?: boolean
   createdAt: string
   shareUrl: string
 }
@@ -47,6 +291,318 @@ export const getShare = async (shareId: string, password?: string): Promise<Shar
   return api.get(`/api/s/${shareId}`, { params })
 }
 
+/**
+ * 获取分享的译文，访问校验同原文
+ */
+export const getShareTranslation = async (shareId: string, lang: string, password?: string): Promise<ShareResponse> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/translations/${lang}`, { params })
+}
+
+/**
+ * 所有者预览分享（包括草稿与已停用的分享）
+ */
+export const previewShare = async (shareId: string): Promise<ShareResponse> => {
+  return api.get(`/api/shares/${shareId}/preview`)
+}
+
+/**
+ * 变更分享状态
+ */
+export const updateShareStatus = async (id: string, status: ShareStatus): Promise<{ code: number; msg: string; data?: { id: string; status: ShareStatus; previous: ShareStatus } }> => {
+  return api.post(`/api/shares/${id}/status`, { status })
+}
+
+/**
+ * 获取闪卡卡组
+ */
+export const getFlashcards = async (shareId: string, password?: string): Promise<{ code: number; msg: string; data?: FlashcardDeck }> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/flashcards`, { params })
+}
+
+/**
+ * 获取分享中音视频的文字稿
+ */
+export const getTranscripts = async (shareId: string, password?: string): Promise<{ code: number; msg: string; data?: { items: MediaTranscript[] } }> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/transcripts`, { params })
+}
+
+/**
+ * 获取正文中的思维导图
+ */
+export const getMindmaps = async (shareId: string, password?: string): Promise<{ code: number; msg: string; data?: { items: ShareMindmap[] } }> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/mindmaps`, { params })
+}
+
+/**
+ * 获取正文中由外部渲染插件渲染的代码块
+ */
+export const getRenders = async (shareId: string, password?: string): Promise<{ code: number; msg: string; data?: { items: ShareRender[] } }> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/renders`, { params })
+}
+
+/**
+ * 获取分享下已公开的评论
+ */
+export const getComments = async (shareId: string, password?: string): Promise<{ code: number; msg: string; data?: { enabled: boolean; items: ShareComment[] } }> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/comments`, { params })
+}
+
+/**
+ * 发表评论（website 为蜜罐字段，保持为空）
+ */
+export const createComment = async (shareId: string, body: { author?: string; content: string; website?: string }, password?: string): Promise<{ code: number; msg: string; data?: ShareComment }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/comments`, body, { params })
+}
+
+/**
+ * 举报分享（website 为蜜罐字段，保持为空）
+ */
+export const reportShare = async (shareId: string, body: { reason: string; contact?: string; website?: string }, password?: string): Promise<{ code: number; msg: string }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/report`, body, { params })
+}
+
+/**
+ * 分享者查看全部评论（含待审核）
+ */
+export const listOwnerComments = async (id: string, status?: string): Promise<{ code: number; msg: string; data: { enabled: boolean; items: ShareComment[] } }> => {
+  return api.get(`/api/shares/${id}/comments`, { params: status ? { status } : {} })
+}
+
+/**
+ * 审核通过评论
+ */
+export const approveComment = async (id: string, commentId: string): Promise<{ code: number; msg: string }> => {
+  return api.post(`/api/shares/${id}/comments/${commentId}/approve`)
+}
+
+/**
+ * 删除评论
+ */
+export const deleteComment = async (id: string, commentId: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/shares/${id}/comments/${commentId}`)
+}
+
+/**
+ * 开放或关闭读者评论
+ */
+export const setCommentsEnabled = async (id: string, allowComments: boolean): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { allowComments })
+}
+
+// 受限分享的访问名单条目：注册用户或邮箱二选一
+export interface ShareAccessEntry {
+  id: number
+  userId?: string
+  username?: string
+  email?: string
+  createdAt: string
+}
+
+// 受限分享的访问令牌按分享保存在本地，请求拦截器据此附带 X-Share-Access 请求头
+export const shareAccessKey = (shareId: string) => `share_access:${shareId}`
+
+/**
+ * 获取分享的访问限制与访问名单
+ */
+export const getShareAccess = async (id: string): Promise<{ code: number; msg: string; data: { restricted: boolean; assetDownloads?: AssetDownloads; emailAccess: boolean; items: ShareAccessEntry[] } }> => {
+  return api.get(`/api/shares/${id}/access`)
+}
+
+/**
+ * 替换访问名单，每项为用户名或邮箱
+ */
+export const updateShareAccess = async (id: string, entries: string[]): Promise<{ code: number; msg: string; data?: { items: ShareAccessEntry[] } }> => {
+  return api.put(`/api/shares/${id}/access`, { entries })
+}
+
+/**
+ * 开启或关闭仅限名单访问
+ */
+export const setShareRestricted = async (id: string, restricted: boolean): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { restricted })
+}
+
+// 开放时间：按发布者时区解释的日期范围（YYYY-MM-DDTHH:MM）与每日时段（HH:MM），二者同时满足时读者才能打开
+export interface AccessSchedule {
+  timezone: string
+  ranges?: { from?: string; until?: string }[]
+  daily?: { start: string; end: string }[]
+}
+
+/**
+ * 设置开放时间，传入不含日期范围与每日时段的对象时取消限制
+ */
+export const setShareSchedule = async (id: string, accessSchedule: AccessSchedule): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { accessSchedule })
+}
+
+/**
+ * 设置浏览次数上限（含已有的浏览次数），1 为阅后即焚，0 取消限制
+ */
+export const setShareMaxViews = async (id: string, maxViews: number): Promise<{ code: number; msg: string; data?: { maxViews: number; status: ShareStatus } }> => {
+  return api.patch(`/api/share/${id}`, { maxViews })
+}
+
+/**
+ * 封存分享，保留期满前不能修改或删除；已封存的分享再次封存只能延长期限
+ */
+export const sealShare = async (id: string, retentionDays: number): Promise<{ code: number; msg: string; data?: ShareSeal }> => {
+  return api.post(`/api/shares/${id}/seal`, { retentionDays })
+}
+
+/**
+ * 校验下载的副本（正文 Markdown 源文的 SHA-256）是否与分享发布过的版本一致
+ */
+export const verifyShareCopy = async (shareId: string, hash: string): Promise<{ code: number; msg: string; data?: ShareVerifyResult }> => {
+  return api.post(`/api/s/${shareId}/verify`, { hash })
+}
+
+/**
+ * 设置阅读页的目录样式、标题锚点与服务端预渲染
+ */
+export const setShareDisplay = async (id: string, display: { toc: ShareTOC; headingAnchors: boolean; prerender?: Partial<SharePrerender> }): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, display)
+}
+
+/**
+ * 查看服务端预渲染设置，available 为服务器已配置渲染命令的项
+ */
+export const getSharePrerender = async (id: string): Promise<{ code: number; msg: string; data: { settings: SharePrerender; available: SharePrerender } }> => {
+  return api.get(`/api/shares/${id}/prerender`)
+}
+
+/**
+ * 设置附件下载策略
+ */
+export const setShareAssetDownloads = async (id: string, assetDownloads: AssetDownloads): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { assetDownloads })
+}
+
+/**
+ * 读者申请受限分享的邮件登录链接（邮箱不在名单中时同样返回成功）
+ */
+export const requestShareAccess = async (shareId: string, email: string): Promise<{ code: number; msg: string }> => {
+  return api.post(`/api/s/${shareId}/access`, { email })
+}
+
+/**
+ * 用邮件链接中的令牌兑换访问令牌
+ */
+export const verifyShareAccess = async (shareId: string, token: string): Promise<{ code: number; msg: string; data?: { accessToken: string; email: string; expiresAt: string } }> => {
+  return api.post(`/api/s/${shareId}/access/verify`, { token })
+}
+
+// 读者同意使用条款的记录
+export interface ShareTermsAcceptance {
+  id: string
+  termsHash: string
+  userId?: string
+  email?: string
+  name?: string
+  fingerprint: string
+  ip: string
+  userAgent: string
+  acceptedAt: string
+}
+
+// 同意使用条款后获得的令牌按分享保存在本地，请求拦截器据此附带 X-Share-Terms 请求头
+export const shareTermsKey = (shareId: string) => `share_terms:${shareId}`
+
+/**
+ * 读者同意使用条款，version 为阅读时看到的条款版本
+ */
+export const acceptShareTerms = async (shareId: string, body: { version: string; name?: string; fingerprint?: string }, password?: string): Promise<{ code: number; msg: string; data?: { termsToken: string; acceptedAt: string; expiresAt: string } }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/terms`, body, { params })
+}
+
+/**
+ * 设置使用条款，传入空字符串时取消
+ */
+export const setShareTerms = async (id: string, terms: string): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { terms })
+}
+
+/**
+ * 获取使用条款与读者的同意记录
+ */
+export const getShareTermsAcceptances = async (id: string, page = 1, size = 50): Promise<{ code: number; msg: string; data: { terms: string; version: string; items: ShareTermsAcceptance[]; total: number; page: number; size: number } }> => {
+  return api.get(`/api/shares/${id}/terms`, { params: { page, size } })
+}
+
+// 赞助链接：Ko-fi 与爱发电为用户名，url 为其他赞助页面
+export interface DonationLinks {
+  kofi?: string
+  afdian?: string
+  url?: string
+  label?: string
+}
+
+/**
+ * 设置赞助链接，各项均为空时取消
+ */
+export const updateDonationLinks = async (donation: DonationLinks): Promise<{ code: number; msg: string }> => {
+  return api.patch('/api/user/settings', { donation })
+}
+
+// 付费阅读订单
+export interface SharePayment {
+  id: string
+  status: 'pending' | 'paid' | 'refunded'
+  amount: string
+  reference: string
+  createdAt: string
+  paidAt?: string
+}
+
+// 支付后获得的解锁令牌按分享保存在本地，请求拦截器据此附带 X-Share-Unlock 请求头
+export const shareUnlockKey = (shareId: string) => `share_unlock:${shareId}`
+
+/**
+ * 读者为付费分享创建订单，返回支付页面地址
+ */
+export const createPaymentOrder = async (shareId: string, password?: string): Promise<{ code: number; msg: string; data?: { orderId: string; checkoutUrl: string } }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/payments`, {}, { params })
+}
+
+/**
+ * 查询订单状态，已支付时返回解锁令牌
+ */
+export const getPaymentOrder = async (shareId: string, orderId: string, password?: string): Promise<{ code: number; msg: string; data?: { orderId: string; status: SharePayment['status']; unlockToken?: string; expiresAt?: string } }> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/payments/${orderId}`, { params })
+}
+
+/**
+ * 设置付费阅读：价格、支付页面地址（含 {order} 占位符）与是否开启
+ */
+export const setSharePaywall = async (id: string, body: { paywall?: boolean; paywallPrice?: string; paywallUrl?: string }): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, body)
+}
+
+/**
+ * 开启或关闭试读模式：未通过密码、付费或登录校验的读者可阅读 <!-- more --> 之前的内容
+ */
+export const setShareTeaser = async (id: string, teaser: boolean): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { teaser })
+}
+
+/**
+ * 获取付费阅读订单与汇总，pending 为 true 时列出未支付的订单
+ */
+export const listSharePayments = async (id: string, page = 1, pending = false): Promise<{ code: number; msg: string; data: { enabled: boolean; summary: { paid: number; pending: number; refunded: number }; items: SharePayment[]; total: number; page: number; size: number } }> => {
+  return api.get(`/api/shares/${id}/payments`, { params: { page, size: 20, status: pending ? 'pending' : undefined } })
+}
+
 /**
  * 获取分享列表
  */
@@ -60,3 +616,875 @@ export const listShares = async (page = 1, size = 10): Promise<ShareListResponse
 export const deleteShare = async (id: string): Promise<{ code: number; msg: string }> => {
   return api.delete(`/api/share/${id}`)
 }
+
+export interface ExportJob {
+  id: string
+  shareId: string
+  format: string
+  status: 'pending' | 'running' | 'done' | 'failed'
+  error?: string
+  fileName?: string
+  size: number
+  warnings?: string
+  finishedAt?: string
+  createdAt: string
+}
+
+/**
+ * 创建导出任务（后台生成）
+ */
+export const createExport = async (id: string, format = 'latex'): Promise<{ code: number; msg: string; data?: ExportJob }> => {
+  return api.post(`/api/shares/${id}/exports`, { format })
+}
+
+/**
+ * 查询导出任务状态
+ */
+export const getExport = async (id: string, exportId: string): Promise<{ code: number; msg: string; data?: ExportJob }> => {
+  return api.get(`/api/shares/${id}/exports/${exportId}`)
+}
+
+/**
+ * 下载导出文件
+ */
+export const downloadExport = async (id: string, exportId: string): Promise<Blob> => {
+  return api.get(`/api/shares/${id}/exports/${exportId}/download`, { responseType: 'blob', timeout: 120000 })
+}
+
+/**
+ * 下载独立文件包（md / html），即时生成
+ */
+export const downloadBundle = async (id: string, format: 'md' | 'html'): Promise<Blob> => {
+  return api.get(`/api/shares/${id}/export`, { params: { format }, responseType: 'blob', timeout: 120000 })
+}
+
+/**
+ * 导出浏览数据：group 为空时导出原始浏览记录，否则按日期、来源站点或国家/地区汇总
+ */
+export const downloadShareViews = async (
+  id: string,
+  params: { format?: 'csv' | 'json'; group?: 'day' | 'referrer' | 'country'; from?: string; to?: string } = {}
+): Promise<Blob> => {
+  return api.get(`/api/shares/${id}/stats/export`, { params, responseType: 'blob', timeout: 120000 })
+}
+
+// 分享的历史版本，version 最大者为当前内容
+export interface ShareRevision {
+  id: string
+  shareId: string
+  version: number
+  docTitle: string
+  content?: string
+  mode: string
+  size: number
+  source: 'publish' | 'rollback'
+  restoredFrom?: number
+  createdAt: string
+}
+
+/**
+ * 获取分享的历史版本列表
+ */
+export const listRevisions = async (id: string): Promise<{ code: number; msg: string; data: { items: ShareRevision[]; current: number } }> => {
+  return api.get(`/api/shares/${id}/revisions`)
+}
+
+/**
+ * 获取历史版本内容
+ */
+export const getRevision = async (id: string, revisionId: string): Promise<{ code: number; msg: string; data?: ShareRevision }> => {
+  return api.get(`/api/shares/${id}/revisions/${revisionId}`)
+}
+
+/**
+ * 回滚到历史版本
+ */
+export const rollbackRevision = async (id: string, revisionId: string): Promise<{ code: number; msg: string; data?: ShareRevision }> => {
+  return api.post(`/api/shares/${id}/revisions/${revisionId}/rollback`)
+}
+
+export interface ShareCollection {
+  id: string
+  slug: string
+  title: string
+  description: string
+  coverImage: string
+  shareIds: string[]
+  url: string
+  createdAt: string
+  updatedAt: string
+}
+
+export interface CollectionInput {
+  slug?: string // 留空时创建合集由标题生成地址，更新合集保持原地址
+  title: string
+  description?: string
+  coverImage?: string
+  shareIds: string[]
+}
+
+export interface CollectionEntry {
+  id: string
+  docTitle: string
+  summary?: string
+  url: string
+  locked: boolean
+  updatedAt: string
+}
+
+export interface PublicCollection {
+  slug: string
+  title: string
+  description: string
+  coverImage: string
+  author: string
+  updatedAt: string
+  items: CollectionEntry[]
+  askEnabled?: boolean
+}
+
+/**
+ * 获取合集首页数据（公开）
+ */
+export const getCollection = async (slug: string): Promise<{ code: number; msg: string; data?: PublicCollection }> => {
+  return api.get(`/api/c/${slug}`)
+}
+
+/**
+ * 列出当前用户的合集
+ */
+export const listCollections = async (): Promise<{ code: number; msg: string; data: { items: ShareCollection[] } }> => {
+  return api.get('/api/collections')
+}
+
+/**
+ * 创建合集
+ */
+export const createCollection = async (input: CollectionInput): Promise<{ code: number; msg: string; data?: ShareCollection }> => {
+  return api.post('/api/collections', input)
+}
+
+/**
+ * 更新合集信息与分享顺序
+ */
+export const updateCollection = async (id: string, input: CollectionInput): Promise<{ code: number; msg: string; data?: ShareCollection }> => {
+  return api.put(`/api/collections/${id}`, input)
+}
+
+/**
+ * 删除合集（不影响其中的分享）
+ */
+export const deleteCollection = async (id: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/collections/${id}`)
+}
+
+export interface CustomDomain {
+  id: string
+  domain: string
+  homePath: string
+  verified: boolean
+  verifiedAt?: string
+  recordName: string
+  recordValue: string
+  createdAt: string
+}
+
+/**
+ * 列出当前用户绑定的自定义域名
+ */
+export const listDomains = async (): Promise<{ code: number; msg: string; data: { items: CustomDomain[] } }> => {
+  return api.get('/api/domains')
+}
+
+/**
+ * 绑定自定义域名，返回需要添加的 TXT 验证记录
+ */
+export const createDomain = async (domain: string, homePath: string): Promise<{ code: number; msg: string; data?: CustomDomain }> => {
+  return api.post('/api/domains', { domain, homePath })
+}
+
+/**
+ * 修改访问域名根路径时跳转的首页
+ */
+export const updateDomain = async (id: string, homePath: string): Promise<{ code: number; msg: string; data?: CustomDomain }> => {
+  return api.patch(`/api/domains/${id}`, { homePath })
+}
+
+/**
+ * 查询 TXT 记录完成域名验证
+ */
+export const verifyDomain = async (id: string): Promise<{ code: number; msg: string; data?: CustomDomain }> => {
+  return api.post(`/api/domains/${id}/verify`)
+}
+
+/**
+ * 解绑自定义域名
+ */
+export const deleteDomain = async (id: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/domains/${id}`)
+}
+
+// 分享朗读音频的生成状态
+export interface ShareNarration {
+  shareId: string
+  status: 'pending' | 'ok' | 'failed'
+  error?: string
+  textHash: string
+  assetPath?: string
+  chars: number
+  createdAt: string
+  updatedAt: string
+}
+
+/**
+ * 查看分享朗读音频的生成状态，url 仅在音频与当前正文一致时返回
+ */
+export const getNarration = async (id: string): Promise<{ code: number; msg: string; data: { enabled: boolean; narration: ShareNarration | null; url?: string } }> => {
+  return api.get(`/api/shares/${id}/narration`)
+}
+
+/**
+ * 生成朗读音频（后台进行），正文未变化时返回已有音频
+ */
+export const createNarration = async (id: string): Promise<{ code: number; msg: string; data?: ShareNarration }> => {
+  return api.post(`/api/shares/${id}/narration`)
+}
+
+/**
+ * 删除朗读音频
+ */
+export const deleteNarration = async (id: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/shares/${id}/narration`)
+}
+
+// 拼写与语法检查发现的问题
+export interface LanguageIssue {
+  message: string
+  shortMessage?: string
+  rule: string
+  category?: string
+  type?: string
+  text: string
+  context: string
+  offset: number // 问题在上下文中的字符位置
+  length: number
+  replacements?: string[]
+}
+
+// 分享正文的拼写与语法检查报告
+export interface LanguageCheckReport {
+  shareId: string
+  status: 'pending' | 'ok' | 'failed'
+  error?: string
+  language?: string
+  issueCount: number
+  truncated: boolean // 正文或问题数超出上限，报告不完整
+  stale: boolean // 正文在检查后有变化
+  issues: LanguageIssue[]
+  updatedAt: string
+}
+
+/**
+ * 查看分享的拼写与语法检查报告
+ */
+export const getLanguageCheck = async (id: string): Promise<{ code: number; msg: string; data: { enabled: boolean; report: LanguageCheckReport | null } }> => {
+  return api.get(`/api/shares/${id}/language-check`)
+}
+
+/**
+ * 重新检查分享正文（后台进行）
+ */
+export const createLanguageCheck = async (id: string): Promise<{ code: number; msg: string }> => {
+  return api.post(`/api/shares/${id}/language-check`)
+}
+
+// 分享译文的状态
+export interface ShareTranslation {
+  lang: string
+  docTitle: string
+  status: 'pending' | 'ready' | 'failed'
+  error?: string
+  manual: boolean // 作者修改过，不会被自动更新覆盖
+  stale: boolean // 原文在译文生成后有变化
+  generating: boolean
+  url: string
+  updatedAt: string
+  content?: string
+}
+
+export interface ShareTranslationList {
+  enabled: boolean
+  languages: string[] // 服务器限定的目标语言，为空时不限制
+  sourceLang: string
+  items: ShareTranslation[]
+}
+
+/**
+ * 查看分享的译文
+ */
+export const listTranslations = async (id: string): Promise<{ code: number; msg: string; data: ShareTranslationList }> => {
+  return api.get(`/api/shares/${id}/translations`)
+}
+
+/**
+ * 读取译文全文
+ */
+export const getTranslation = async (id: string, lang: string): Promise<{ code: number; msg: string; data: ShareTranslation }> => {
+  return api.get(`/api/shares/${id}/translations/${lang}`)
+}
+
+/**
+ * 生成或重新生成译文（后台进行）
+ */
+export const createTranslation = async (id: string, lang: string): Promise<{ code: number; msg: string; data?: ShareTranslation }> => {
+  return api.post(`/api/shares/${id}/translations`, { lang })
+}
+
+/**
+ * 保存作者提供或修改的译文
+ */
+export const saveTranslation = async (id: string, lang: string, docTitle: string, content: string): Promise<{ code: number; msg: string; data?: ShareTranslation }> => {
+  return api.put(`/api/shares/${id}/translations/${lang}`, { docTitle, content })
+}
+
+/**
+ * 删除译文
+ */
+export const deleteTranslation = async (id: string, lang: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/shares/${id}/translations/${lang}`)
+}
+
+// 分享摘要与建议标签
+export interface ShareSummary {
+  enabled: boolean
+  summary: string
+  summaryManual: boolean
+  suggestedTags: string[]
+  tags: string[]
+  qaEnabled: boolean
+  allowQa: boolean
+}
+
+/**
+ * 查看分享的摘要与建议标签
+ */
+export const getSummary = async (id: string): Promise<{ code: number; msg: string; data: ShareSummary }> => {
+  return api.get(`/api/shares/${id}/summary`)
+}
+
+/**
+ * 立即重新生成摘要与建议标签，覆盖作者填写的摘要
+ */
+export const generateSummary = async (id: string): Promise<{ code: number; msg: string; data?: ShareSummary }> => {
+  return api.post(`/api/shares/${id}/summary`)
+}
+
+/**
+ * 修改摘要与标签，摘要为空时恢复自动生成
+ */
+export const updateShareSummary = async (id: string, data: { summary?: string; tags?: string[] }): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, data)
+}
+
+// 语义搜索与相关分享的结果项，score 为余弦相似度
+export interface SemanticItem {
+  id: string
+  docTitle: string
+  summary: string
+  status: ShareStatus
+  score: number
+  createdAt: string
+  shareUrl: string
+}
+
+/**
+ * 按语义搜索本人的分享
+ */
+export const semanticSearch = async (q: string, limit = 10): Promise<{ code: number; msg: string; data: { items: SemanticItem[] } }> => {
+  return api.get('/api/shares/semantic-search', { params: { q, limit } })
+}
+
+/**
+ * 与指定分享内容相近的本人其他分享
+ */
+export const getRelatedShares = async (id: string, limit = 5): Promise<{ code: number; msg: string; data: { items: SemanticItem[] } }> => {
+  return api.get(`/api/shares/${id}/related`, { params: { limit } })
+}
+
+/**
+ * 在后台为尚未索引或内容已变化的分享重建语义索引
+ */
+export const reindexShares = async (): Promise<{ code: number; msg: string; data: { shares: number } }> => {
+  return api.post('/api/shares/semantic-index')
+}
+
+/**
+ * 开启或关闭读者问答
+ */
+export const setQAEnabled = async (id: string, allowQa: boolean): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { allowQa })
+}
+
+// 回答引用的出处，url 指向阅读页中对应章节
+export interface AnswerCitation {
+  index: number
+  shareId: string
+  docTitle: string
+  heading: string
+  anchor: string
+  blockId?: string
+  excerpt: string
+  url: string
+}
+
+export interface ShareAnswer {
+  answer: string
+  citations: AnswerCitation[]
+}
+
+/**
+ * 就分享正文提问
+ */
+export const askShare = async (shareId: string, question: string, password?: string): Promise<{ code: number; msg: string; data: ShareAnswer }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/ask`, { question }, { params })
+}
+
+/**
+ * 就合集中的分享提问
+ */
+export const askCollection = async (slug: string, question: string): Promise<{ code: number; msg: string; data: ShareAnswer }> => {
+  return api.post(`/api/c/${slug}/ask`, { question })
+}
+
+export interface ShortLink {
+  code: string
+  shareId: string
+  url: string
+  label: string
+  disabled: boolean
+  clicks: number
+  lastClickedAt?: string
+  createdAt: string
+}
+
+/**
+ * 分享的短链接及各自的点击数
+ */
+export const listShortLinks = async (shareId: string): Promise<{ code: number; msg: string; data: { items: ShortLink[] } }> => {
+  return api.get(`/api/shares/${shareId}/links`)
+}
+
+/**
+ * 创建短链接，label 为用途说明（如投放渠道）
+ */
+export const createShortLink = async (shareId: string, label: string): Promise<{ code: number; msg: string; data: ShortLink }> => {
+  return api.post(`/api/shares/${shareId}/links`, { label })
+}
+
+/**
+ * 修改短链接的用途说明或停用 / 启用
+ */
+export const updateShortLink = async (shareId: string, code: string, patch: { label?: string; disabled?: boolean }): Promise<{ code: number; msg: string; data: ShortLink }> => {
+  return api.patch(`/api/shares/${shareId}/links/${code}`, patch)
+}
+
+/**
+ * 删除短链接
+ */
+export const deleteShortLink = async (shareId: string, code: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/shares/${shareId}/links/${code}`)
+}
+
+export interface ShareVariant {
+  theme: string
+  toc: ShareTOC | ''
+  percent: number
+}
+
+export interface ShareVariantStats {
+  variant: 'a' | 'b'
+  theme: string
+  toc: ShareTOC
+  views: number
+  reads: number
+  avgSeconds: number
+  avgDepth: number
+  completionRate: number
+}
+
+/**
+ * 设置主题 A/B 测试，theme 与 toc 都为空时关闭
+ */
+export const setShareVariant = async (id: string, variant: Partial<ShareVariant>): Promise<{ code: number; msg: string; data?: { variant: ShareVariant | null } }> => {
+  return api.patch(`/api/share/${id}`, { variant })
+}
+
+/**
+ * A/B 测试各分组的浏览次数、阅读时长、阅读深度与读完比例
+ */
+export const getShareVariantStats = async (id: string): Promise<{ code: number; msg: string; data: { variant: ShareVariant | null; from: string; to: string; items: ShareVariantStats[] } }> => {
+  return api.get(`/api/shares/${id}/stats/variants`)
+}
+
+/**
+ * 离开阅读页时上报阅读时长、滚动深度与读到的标题（A/B 测试与阅读统计），使用 sendBeacon 保证页面关闭时也能送达
+ */
+export const reportShareRead = (shareId: string, data: { variant?: string; seconds: number; depth: number; anchor?: string }) => {
+  const url = `${api.defaults.baseURL || ''}/api/s/${shareId}/read`
+  const body = new Blob([JSON.stringify(data)], { type: 'application/json' })
+  if (!navigator.sendBeacon?.(url, body)) {
+    fetch(url, { method: 'POST', body, keepalive: true }).catch(() => {})
+  }
+}
+
+// 阅读进度：匿名读者令牌在该分享中保存的位置，resumeUrl 可在其他设备上继续阅读
+export interface ReadingProgress {
+  token?: string
+  anchor: string
+  percent: number
+  updatedAt: string
+  resumeUrl: string
+}
+
+// 匿名读者令牌在 localStorage 中的键，所有分享共用
+export const readerTokenKey = 'share_reader_token'
+
+/**
+ * 查询阅读进度，没有记录时 data 为 null
+ */
+export const getReadingProgress = async (shareId: string, token: string, password?: string): Promise<{ code: number; msg: string; data: ReadingProgress | null }> => {
+  const params = password ? { password, token } : { token }
+  return api.get(`/api/s/${shareId}/progress`, { params })
+}
+
+/**
+ * 保存阅读进度，未携带令牌时服务端签发新令牌
+ */
+export const saveReadingProgress = async (shareId: string, body: { token?: string; anchor: string; percent: number }, password?: string): Promise<{ code: number; msg: string; data: ReadingProgress }> => {
+  const params = password ? { password } : {}
+  return api.put(`/api/s/${shareId}/progress`, body, { params })
+}
+
+/**
+ * 读者反馈「是否有帮助」，comment 为可选的文字说明
+ */
+export const submitFeedback = async (shareId: string, body: { helpful: boolean; comment?: string; website?: string }, password?: string): Promise<{ code: number; msg: string }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/feedback`, body, { params })
+}
+
+export interface ShareFeedback {
+  id: string
+  helpful: boolean
+  comment: string
+  createdAt: string
+}
+
+export interface FeedbackSummary {
+  total: number
+  helpful: number
+  notHelpful: number
+  helpfulRate: number
+  comments: number
+}
+
+/**
+ * 分享的反馈汇总与附带文字说明的反馈
+ */
+export const getFeedbackStats = async (id: string, page = 1): Promise<{ code: number; msg: string; data: { enabled: boolean; summary: FeedbackSummary; items: ShareFeedback[]; total: number; page: number; size: number } }> => {
+  return api.get(`/api/shares/${id}/stats/feedback`, { params: { page } })
+}
+
+/**
+ * 开启或关闭读者反馈
+ */
+export const setFeedbackEnabled = async (id: string, allowFeedback: boolean): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { allowFeedback })
+}
+
+// 阅读漏斗中的一个标题：reached 为读到该标题或更靠后位置的阅读数，rate 为占全部阅读的比例
+export interface ReadingFunnelStep {
+  id: string
+  text: string
+  level: number
+  reached: number
+  rate: number
+}
+
+export interface ReadingStats {
+  trackReading: boolean
+  wordCount: number
+  readingMinutes: number
+  from: string
+  to: string
+  reads: number
+  avgSeconds: number
+  avgDepth: number
+  completionRate: number
+  funnel: ReadingFunnelStep[]
+}
+
+/**
+ * 分享的字数、预计阅读时长、阅读时长与深度，以及按标题统计的阅读漏斗
+ */
+export const getReadingStats = async (id: string): Promise<{ code: number; msg: string; data: ReadingStats }> => {
+  return api.get(`/api/shares/${id}/stats/reading`)
+}
+
+/**
+ * 开启或关闭阅读统计
+ */
+export const setReadingTracking = async (id: string, trackReading: boolean): Promise<{ code: number; msg: string }> => {
+  return api.patch(`/api/share/${id}`, { trackReading })
+}
+
+/**
+ * 删除一条反馈
+ */
+export const deleteFeedback = async (id: string, feedbackId: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/shares/${id}/feedback/${feedbackId}`)
+}
+
+export interface TrashedShare {
+  id: string
+  docId: string
+  docTitle: string
+  parentShareId?: string
+  viewCount: number
+  createdAt: string
+  deletedAt: string
+  purgeAt: string
+}
+
+/**
+ * 回收站中的分享，到 purgeAt 后被彻底删除
+ */
+export const listTrash = async (): Promise<{ code: number; msg: string; data: { items: TrashedShare[]; retention: string } }> => {
+  return api.get('/api/shares/trash')
+}
+
+/**
+ * 恢复回收站中的分享
+ */
+export const restoreShare = async (id: string): Promise<{ code: number; msg: string; data?: { id: string; shareUrl: string } }> => {
+  return api.post(`/api/shares/trash/${id}/restore`)
+}
+
+/**
+ * 彻底删除回收站中的分享，不可恢复
+ */
+export const purgeShare = async (id: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/shares/trash/${id}`)
+}
+
+/**
+ * 清空回收站
+ */
+export const emptyTrash = async (): Promise<{ code: number; msg: string; data?: { purged: number } }> => {
+  return api.delete('/api/shares/trash')
+}
+
+// 正文投票块的选项与票数
+export interface PollOption {
+  text: string
+  votes: number
+}
+
+// 正文中的投票块（```poll 代码块），voted 为当前读者选择的选项
+export interface SharePoll {
+  key: string
+  index: number
+  line: number
+  question: string
+  options: PollOption[]
+  total: number
+  voted: string
+}
+
+/**
+ * 获取正文中的投票与实时结果
+ */
+export const getPolls = async (shareId: string, fingerprint: string, password?: string): Promise<{ code: number; msg: string; data?: { items: SharePoll[] } }> => {
+  const params = password ? { password, fingerprint } : { fingerprint }
+  return api.get(`/api/s/${shareId}/polls`, { params })
+}
+
+/**
+ * 投票，再次投票时改为新的选项，返回该投票的最新结果
+ */
+export const votePoll = async (shareId: string, key: string, body: { option: string; fingerprint: string }, password?: string): Promise<{ code: number; msg: string; data?: SharePoll }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/polls/${key}/vote`, body, { params })
+}
+
+// 表单字段：text 单行文本、textarea 多行文本、email 邮箱
+export interface FormField {
+  label: string
+  type: 'text' | 'textarea' | 'email'
+  required: boolean
+}
+
+// 正文中的表单块（```form 代码块）
+export interface ShareForm {
+  key: string
+  index: number
+  line: number
+  title: string
+  fields: FormField[]
+}
+
+/**
+ * 获取正文中的表单定义
+ */
+export const getForms = async (shareId: string, password?: string): Promise<{ code: number; msg: string; data?: { items: ShareForm[] } }> => {
+  const params = password ? { password } : {}
+  return api.get(`/api/s/${shareId}/forms`, { params })
+}
+
+/**
+ * 提交表单，values 为字段标签到填写内容
+ */
+export const submitForm = async (shareId: string, key: string, body: { values: Record<string, string>; website?: string }, password?: string): Promise<{ code: number; msg: string }> => {
+  const params = password ? { password } : {}
+  return api.post(`/api/s/${shareId}/forms/${key}`, body, { params })
+}
+
+// 所有者看到的表单与提交数，inContent 为 false 表示表单已从正文删除
+export interface OwnerForm {
+  key: string
+  title: string
+  fields: FormField[]
+  submissions: number
+  inContent: boolean
+}
+
+export interface FormSubmission {
+  id: string
+  values: Record<string, string>
+  createdAt: string
+}
+
+/**
+ * 分享正文中的表单与各表单的提交数
+ */
+export const listOwnerForms = async (id: string): Promise<{ code: number; msg: string; data: { items: OwnerForm[] } }> => {
+  return api.get(`/api/shares/${id}/forms`)
+}
+
+/**
+ * 按时间倒序分页获取表单的提交
+ */
+export const listFormSubmissions = async (id: string, key: string, page = 1): Promise<{ code: number; msg: string; data: { items: FormSubmission[]; total: number; page: number; size: number } }> => {
+  return api.get(`/api/shares/${id}/forms/${key}/submissions`, { params: { page } })
+}
+
+/**
+ * 导出表单的全部提交（CSV）
+ */
+export const downloadFormSubmissions = async (id: string, key: string): Promise<Blob> => {
+  return api.get(`/api/shares/${id}/forms/${key}/export`, { responseType: 'blob', timeout: 120000 })
+}
+
+/**
+ * 删除一条表单提交
+ */
+export const deleteFormSubmission = async (id: string, key: string, submissionId: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/shares/${id}/forms/${key}/submissions/${submissionId}`)
+}
+
+// 发布模板：创建分享时通过 templateId 引用的一组默认发布设置
+export interface ShareTemplate {
+  id: string
+  name: string
+  theme: string
+  toc: '' | 'side' | 'floating' | 'off'
+  expireDays: number
+  requirePassword: boolean
+  hasPassword: boolean
+  isPublic: boolean
+  listed: boolean
+  isDefault: boolean
+  createdAt: string
+  updatedAt: string
+}
+
+export type ShareTemplateInput = Omit<ShareTemplate, 'id' | 'hasPassword' | 'createdAt' | 'updatedAt'> & { password?: string }
+
+/**
+ * 获取发布模板列表
+ */
+export const listShareTemplates = async (): Promise<{ code: number; msg: string; data: { items: ShareTemplate[] } }> => {
+  return api.get('/api/templates')
+}
+
+/**
+ * 创建或更新发布模板，更新时 password 留空则保留原密码
+ */
+export const saveShareTemplate = async (id: string | null, body: ShareTemplateInput): Promise<{ code: number; msg: string; data: ShareTemplate }> => {
+  return id ? api.put(`/api/templates/${id}`, body) : api.post('/api/templates', body)
+}
+
+/**
+ * 删除发布模板
+ */
+export const deleteShareTemplate = async (id: string): Promise<{ code: number; msg: string }> => {
+  return api.delete(`/api/templates/${id}`)
+}
+
+// 导入思源导出文件的发布设置，未传入的项取发布模板的值
+export interface ImportShareOptions {
+  title?: string
+  expireDays?: number
+  requirePassword?: boolean
+  password?: string
+  isPublic?: boolean
+  listed?: boolean
+  templateId?: string
+  status?: 'draft' | 'published' | 'unlisted'
+}
+
+export interface ImportShareResult {
+  shareId: string
+  shareUrl: string
+  docTitle: string
+  missingAssets: { path: string; references: number }[]
+}
+
+/**
+ * 上传思源笔记导出的 zip（Markdown 或 HTML 及资源）创建分享
+ */
+export const importShare = async (file: File, options: ImportShareOptions): Promise<{ code: number; msg: string; data: ImportShareResult }> => {
+  const form = new FormData()
+  form.append('file', file)
+  for (const [key, value] of Object.entries(options)) {
+    if (value !== undefined && value !== '') form.append(key, String(value))
+  }
+  return api.post('/api/share/import', form, { timeout: 300000 })
+}
+
+// 分享者邮件通知设置
+export interface NotificationSettings {
+  firstView: boolean
+  viewsEvery: number
+  expiringDays: number
+  comments: boolean
+  emailEnabled: boolean
+  emailVerified: boolean
+}
+
+export type NotificationSettingsInput = Omit<NotificationSettings, 'emailEnabled' | 'emailVerified'>
+
+/**
+ * 获取邮件通知设置
+ */
+export const getNotificationSettings = async (): Promise<{ code: number; msg: string; data: NotificationSettings }> => {
+  return api.get('/api/user/notifications')
+}
+
+/**
+ * 更新邮件通知设置
+ */
+export const saveNotificationSettings = async (body: NotificationSettingsInput): Promise<{ code: number; msg: string; data: NotificationSettings }> => {
+  return api.put('/api/user/notifications', body)
+}
@@ -0,0 +1,114 @@
+import { message, Modal, Radio, Select, Space, Switch, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { getShareAccess, setShareAssetDownloads, setShareRestricted, updateShareAccess, type AssetDownloads, type ShareAccessEntry } from '../api/share'
+
+const { Text } = Typography
+
+interface AccessModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+  onChanged?: () => void
+}
+
+const entryLabel = (e: ShareAccessEntry) => e.email || e.username || ''
+
+// 受限访问设置：开启后仅分享者、名单中的注册用户与通过邮件链接验证的受邀邮箱可打开；附带附件下载策略
+function AccessModal({ shareId, docTitle, onClose, onChanged }: AccessModalProps) {
+  const [restricted, setRestricted] = useState(false)
+  const [downloads, setDownloads] = useState<AssetDownloads>('allow')
+  const [emailAccess, setEmailAccess] = useState(false)
+  const [entries, setEntries] = useState<string[]>([])
+  const [loading, setLoading] = useState(false)
+  const [saving, setSaving] = useState(false)
+
+  useEffect(() => {
+    if (!shareId) return
+    setLoading(true)
+    getShareAccess(shareId)
+      .then(res => {
+        if (res.code === 0) {
+          setRestricted(res.data.restricted)
+          setDownloads(res.data.assetDownloads || 'allow')
+          setEmailAccess(res.data.emailAccess)
+          setEntries((res.data.items || []).map(entryLabel))
+        } else {
+          message.error(res.msg || '加载失败')
+        }
+      })
+      .catch((e: any) => message.error(e.response?.data?.msg || e.message || '加载失败'))
+      .finally(() => setLoading(false))
+  }, [shareId])
+
+  const save = async () => {
+    if (!shareId) return
+    setSaving(true)
+    try {
+      const res = await updateShareAccess(shareId, entries)
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      const toggled = await setShareRestricted(shareId, restricted)
+      if (toggled.code !== 0) {
+        message.error(toggled.msg || '保存失败')
+        return
+      }
+      const downloadsSaved = await setShareAssetDownloads(shareId, downloads)
+      if (downloadsSaved.code !== 0) {
+        message.error(downloadsSaved.msg || '保存失败')
+        return
+      }
+      message.success('已保存')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`访问限制${docTitle ? ` · ${docTitle}` : ''}`}
+      okText="保存"
+      confirmLoading={saving}
+      onOk={save}
+      onCancel={onClose}
+    >
+      <Space direction="vertical" style={{ width: '100%' }}>
+        <Space>
+          <Switch checked={restricted} loading={loading} onChange={setRestricted} />
+          <Text>仅限名单中的读者访问</Text>
+        </Space>
+        <Select
+          mode="tags"
+          style={{ width: '100%' }}
+          placeholder="输入用户名或邮箱，回车添加"
+          value={entries}
+          onChange={setEntries}
+          tokenSeparators={[',', ' ', '\n']}
+          open={false}
+          disabled={loading}
+        />
+        <Text type="secondary">
+          注册用户登录后即可访问；邮箱读者{emailAccess ? '可在分享页申请邮件访问链接，已验证该邮箱的注册用户也可直接访问' : '需使用已验证该邮箱的账号登录（服务端未配置邮件，无法发送访问链接）'}。
+          引用块子分享沿用本分享的名单。
+        </Text>
+        <Text strong style={{ marginTop: 8 }}>附件下载</Text>
+        <Radio.Group value={downloads} onChange={(e) => setDownloads(e.target.value)} disabled={loading}>
+          <Radio value="allow">允许下载</Radio>
+          <Radio value="inline">仅在线预览</Radio>
+          <Radio value="password">下载需输入密码</Radio>
+        </Radio.Group>
+        <Text type="secondary">
+          图片、音视频与 PDF 仍可在阅读页中预览，限制的是下载请求与无法在线预览的附件；分享未设置访问密码时“下载需输入密码”按仅在线预览处理。
+        </Text>
+      </Space>
+    </Modal>
+  )
+}
+
+export default AccessModal
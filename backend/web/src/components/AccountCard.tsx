@@ -0,0 +1,143 @@
+import { EditOutlined, WarningOutlined } from '@ant-design/icons'
+import { Alert, Button, Card, Form, Input, Modal, Space, Typography, message } from 'antd'
+import { useState } from 'react'
+import api from '../api'
+
+const { Text, Paragraph } = Typography
+
+interface ApiResp<T = any> { code: number; msg: string; data: T }
+
+interface Props {
+  user: { username: string; email: string; deletionScheduledAt?: string | null }
+  onChange: () => void
+}
+
+// 账号资料与注销：修改用户名、邮箱、密码（需当前密码），申请注销与宽限期内撤销
+function AccountCard({ user, onChange }: Props) {
+  const [editOpen, setEditOpen] = useState(false)
+  const [deleteOpen, setDeleteOpen] = useState(false)
+  const [loading, setLoading] = useState(false)
+  const [profileForm] = Form.useForm()
+  const [deleteForm] = Form.useForm()
+
+  const run = async (fn: () => Promise<ApiResp>, ok: (data: any) => void) => {
+    setLoading(true)
+    try {
+      const res = await fn()
+      if (res.code === 0) ok(res.data)
+      else message.error(res.msg || '操作失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  const saveProfile = async (values: any) => {
+    const body: Record<string, string> = { currentPassword: values.currentPassword }
+    if (values.username && values.username !== user.username) body.username = values.username
+    if (values.email && values.email !== user.email) body.email = values.email
+    if (values.newPassword) body.newPassword = values.newPassword
+    await run(() => api.patch('/api/me', body), (data) => {
+      message.success(data?.verificationSent ? '已保存，验证邮件已发送到新邮箱' : '已保存')
+      setEditOpen(false)
+      profileForm.resetFields()
+      onChange()
+    })
+  }
+
+  const requestDeletion = async (values: any) => {
+    await run(() => api.delete('/api/me', { data: values }), (data) => {
+      setDeleteOpen(false)
+      deleteForm.resetFields()
+      if (data?.deleted) {
+        localStorage.removeItem('session_token')
+        message.success('账号已删除')
+        window.location.href = '/'
+        return
+      }
+      message.success('已申请注销')
+      onChange()
+    })
+  }
+
+  const cancelDeletion = () => run(() => api.post('/api/me/restore'), () => {
+    message.success('已撤销注销，请重新创建 API Token')
+    onChange()
+  })
+
+  return (
+    <Card
+      title={<Space><EditOutlined /><span>账号设置</span></Space>}
+      bordered={false}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      {user.deletionScheduledAt && (
+        <Alert
+          type="warning"
+          showIcon
+          style={{ marginBottom: 16 }}
+          message={`账号将于 ${new Date(user.deletionScheduledAt).toLocaleString('zh-CN')} 彻底删除`}
+          description="届时全部分享、资源文件与统计数据将被删除且无法恢复。"
+          action={<Button size="small" loading={loading} onClick={cancelDeletion}>撤销注销</Button>}
+        />
+      )}
+      <Space>
+        <Button onClick={() => { profileForm.setFieldsValue({ username: user.username, email: user.email }); setEditOpen(true) }}>
+          修改资料或密码
+        </Button>
+        {!user.deletionScheduledAt && (
+          <Button danger icon={<WarningOutlined />} onClick={() => setDeleteOpen(true)}>注销账号</Button>
+        )}
+      </Space>
+
+      <Modal
+        open={editOpen}
+        title="修改资料或密码"
+        okText="保存"
+        confirmLoading={loading}
+        onOk={() => profileForm.submit()}
+        onCancel={() => setEditOpen(false)}
+      >
+        <Form form={profileForm} layout="vertical" onFinish={saveProfile}>
+          <Form.Item name="username" label="用户名" rules={[{ required: true, message: '请输入用户名' }, { min: 3, message: '至少3个字符' }]}>
+            <Input />
+          </Form.Item>
+          <Form.Item name="email" label="邮箱" rules={[{ required: true, message: '请输入邮箱' }, { type: 'email', message: '邮箱格式不正确' }]} extra="修改后需重新验证邮箱">
+            <Input />
+          </Form.Item>
+          <Form.Item name="newPassword" label="新密码" rules={[{ min: 6, message: '至少6个字符' }]} extra="留空则不修改；修改后其他设备需重新登录">
+            <Input.Password autoComplete="new-password" />
+          </Form.Item>
+          <Form.Item name="currentPassword" label="当前密码" rules={[{ required: true, message: '请输入当前密码' }]}>
+            <Input.Password autoComplete="current-password" />
+          </Form.Item>
+        </Form>
+      </Modal>
+
+      <Modal
+        open={deleteOpen}
+        title="注销账号"
+        okText="确认注销"
+        okButtonProps={{ danger: true }}
+        confirmLoading={loading}
+        onOk={() => deleteForm.submit()}
+        onCancel={() => setDeleteOpen(false)}
+      >
+        <Paragraph>
+          注销后全部 API Token 立即失效；宽限期结束后账号与全部分享、资源文件、评论、统计数据将被彻底删除，宽限期内登录可撤销注销。
+        </Paragraph>
+        <Form form={deleteForm} layout="vertical" onFinish={requestDeletion}>
+          <Form.Item name="confirm" label={<Text>输入用户名 <Text code>{user.username}</Text> 以确认</Text>} rules={[{ required: true, message: '请输入用户名' }]}>
+            <Input />
+          </Form.Item>
+          <Form.Item name="password" label="当前密码" rules={[{ required: true, message: '请输入当前密码' }]}>
+            <Input.Password autoComplete="current-password" />
+          </Form.Item>
+        </Form>
+      </Modal>
+    </Card>
+  )
+}
+
+export default AccountCard
@@ -0,0 +1,58 @@
+import { Alert, Space } from 'antd'
+import { useEffect, useState, type CSSProperties } from 'react'
+import { dismissAnnouncement, dismissedAnnouncementKey, getAnnouncements, type Announcement } from '../api/announcement'
+
+interface AnnouncementBannerProps {
+  scope: 'dashboard' | 'shares'
+  style?: CSSProperties
+}
+
+// 全站公告横幅：登录用户关闭后记录在服务端，匿名读者的关闭状态保存在浏览器中
+function AnnouncementBanner({ scope, style }: AnnouncementBannerProps) {
+  const [items, setItems] = useState<Announcement[]>([])
+
+  useEffect(() => {
+    let cancelled = false
+    getAnnouncements(scope)
+      .then(res => {
+        if (cancelled || res.code !== 0 || !res.data) return
+        setItems(res.data.items.filter(a => !a.dismissible || !localStorage.getItem(dismissedAnnouncementKey(a))))
+      })
+      .catch(() => {})
+    return () => { cancelled = true }
+  }, [scope])
+
+  const dismiss = (a: Announcement) => {
+    setItems(prev => prev.filter(x => x.id !== a.id))
+    if (localStorage.getItem('session_token')) {
+      dismissAnnouncement(a.id).catch(() => {})
+    } else {
+      localStorage.setItem(dismissedAnnouncementKey(a), '1')
+    }
+  }
+
+  if (items.length === 0) return null
+  return (
+    <Space direction="vertical" style={{ width: '100%', ...style }}>
+      {items.map(a => (
+        <Alert
+          key={a.id}
+          type={a.level}
+          showIcon
+          banner
+          message={a.title}
+          description={(a.content || a.link) && (
+            <>
+              {a.content && <div style={{ whiteSpace: 'pre-wrap' }}>{a.content}</div>}
+              {a.link && <a href={a.link} target={a.link.startsWith('/') ? undefined : '_blank'} rel="noreferrer">了解详情</a>}
+            </>
+          )}
+          closable={a.dismissible}
+          onClose={() => dismiss(a)}
+        />
+      ))}
+    </Space>
+  )
+}
+
+export default AnnouncementBanner
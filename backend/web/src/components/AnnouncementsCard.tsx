@@ -0,0 +1,204 @@
+import { NotificationOutlined, PlusOutlined, ReloadOutlined } from '@ant-design/icons'
+import { Button, Card, Checkbox, Form, Input, message, Modal, Popconfirm, Select, Space, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { createAnnouncement, deleteAnnouncement, listAdminAnnouncements, updateAnnouncement, type AdminAnnouncement, type AnnouncementInput } from '../api/admin'
+
+const { Text } = Typography
+
+const levelOptions = [
+  { label: '通知', value: 'info' },
+  { label: '警告', value: 'warning' },
+  { label: '紧急', value: 'error' },
+]
+const levelColors: Record<string, string> = { info: 'blue', warning: 'orange', error: 'red' }
+
+const audienceOptions = [
+  { label: '控制面板与阅读页', value: 'all' },
+  { label: '仅控制面板', value: 'dashboard' },
+  { label: '仅阅读页', value: 'shares' },
+]
+
+// datetime-local 输入框的值与 ISO 时间互转
+const toLocalInput = (iso?: string) => {
+  if (!iso) return ''
+  const d = new Date(iso)
+  return new Date(d.getTime() - d.getTimezoneOffset() * 60000).toISOString().slice(0, 16)
+}
+const fromLocalInput = (v?: string) => (v ? new Date(v).toISOString() : undefined)
+
+interface FormValues extends Omit<AnnouncementInput, 'startsAt' | 'endsAt'> {
+  startsAt?: string
+  endsAt?: string
+  resetDismissals?: boolean
+}
+
+// 全站公告管理（仅管理员）：发布维护通知、条款更新等，按时间段显示在控制面板与阅读页顶部
+function AnnouncementsCard() {
+  const [items, setItems] = useState<AdminAnnouncement[]>([])
+  const [loading, setLoading] = useState(false)
+  const [editing, setEditing] = useState<AdminAnnouncement | 'new' | null>(null)
+  const [saving, setSaving] = useState(false)
+  const [form] = Form.useForm<FormValues>()
+
+  const load = async () => {
+    setLoading(true)
+    try {
+      const res = await listAdminAnnouncements()
+      if (res.code === 0 && res.data) {
+        setItems(res.data.items)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => { load() }, [])
+
+  const openEditor = (a: AdminAnnouncement | 'new') => {
+    setEditing(a)
+    form.resetFields()
+    if (a === 'new') {
+      form.setFieldsValue({ level: 'info', audience: 'all', dismissible: true })
+    } else {
+      form.setFieldsValue({ ...a, startsAt: toLocalInput(a.startsAt), endsAt: toLocalInput(a.endsAt), resetDismissals: false })
+    }
+  }
+
+  const save = async () => {
+    const { resetDismissals, ...values } = await form.validateFields()
+    const input: AnnouncementInput = { ...values, startsAt: fromLocalInput(values.startsAt), endsAt: fromLocalInput(values.endsAt) }
+    setSaving(true)
+    try {
+      const res = editing === 'new' || !editing
+        ? await createAnnouncement(input)
+        : await updateAnnouncement(editing.id, input, !!resetDismissals)
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      message.success('已保存')
+      setEditing(null)
+      load()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const remove = async (a: AdminAnnouncement) => {
+    try {
+      const res = await deleteAnnouncement(a.id)
+      if (res.code === 0) {
+        message.success('已删除')
+        load()
+      } else {
+        message.error(res.msg || '删除失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '删除失败')
+    }
+  }
+
+  const columns = [
+    {
+      title: '公告',
+      key: 'title',
+      render: (_: any, a: AdminAnnouncement) => (
+        <Space direction="vertical" size={0}>
+          <Space size={4}>
+            <Tag color={levelColors[a.level]}>{levelOptions.find(o => o.value === a.level)?.label}</Tag>
+            <Text strong>{a.title}</Text>
+          </Space>
+          {a.content && <Text type="secondary" ellipsis style={{ maxWidth: 360 }}>{a.content}</Text>}
+        </Space>
+      ),
+    },
+    { title: '显示位置', dataIndex: 'audience', key: 'audience', width: 150, render: (v: string) => audienceOptions.find(o => o.value === v)?.label },
+    {
+      title: '时间',
+      key: 'time',
+      width: 220,
+      render: (_: any, a: AdminAnnouncement) => (
+        <Space direction="vertical" size={0}>
+          <Text style={{ fontSize: 12 }}>{a.startsAt ? new Date(a.startsAt).toLocaleString() : '立即'} 起</Text>
+          <Text style={{ fontSize: 12 }}>{a.endsAt ? `至 ${new Date(a.endsAt).toLocaleString()}` : '不限结束时间'}</Text>
+          {a.active ? <Tag color="green">显示中</Tag> : <Tag>未显示</Tag>}
+        </Space>
+      ),
+    },
+    { title: '关闭人数', dataIndex: 'dismissals', key: 'dismissals', width: 90, render: (v: number, a: AdminAnnouncement) => (a.dismissible ? v : '-') },
+    {
+      title: '操作',
+      key: 'actions',
+      width: 130,
+      render: (_: any, a: AdminAnnouncement) => (
+        <Space>
+          <Button size="small" onClick={() => openEditor(a)}>编辑</Button>
+          <Popconfirm title="删除该公告？" onConfirm={() => remove(a)}>
+            <Button size="small" danger>删除</Button>
+          </Popconfirm>
+        </Space>
+      ),
+    },
+  ]
+
+  return (
+    <Card
+      title={<Space><NotificationOutlined /><span>全站公告</span></Space>}
+      extra={
+        <Space>
+          <Button icon={<ReloadOutlined />} onClick={load} loading={loading}>刷新</Button>
+          <Button type="primary" icon={<PlusOutlined />} onClick={() => openEditor('new')}>发布公告</Button>
+        </Space>
+      }
+      bordered={false}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      <Table rowKey="id" size="small" loading={loading} columns={columns} dataSource={items} pagination={{ pageSize: 10, hideOnSinglePage: true }} scroll={{ x: true }} />
+
+      <Modal
+        title={editing === 'new' ? '发布公告' : '编辑公告'}
+        open={!!editing}
+        onCancel={() => setEditing(null)}
+        onOk={save}
+        confirmLoading={saving}
+        destroyOnClose
+      >
+        <Form form={form} layout="vertical">
+          <Form.Item name="title" label="标题" rules={[{ required: true, message: '请输入标题' }, { max: 200 }]}>
+            <Input placeholder="如：本周六 22:00-23:00 系统维护" />
+          </Form.Item>
+          <Form.Item name="content" label="内容" rules={[{ max: 2000 }]}>
+            <Input.TextArea rows={3} />
+          </Form.Item>
+          <Form.Item name="link" label="详情链接" extra="站内路径（以 / 开头）或 http(s) 地址">
+            <Input placeholder="https://" />
+          </Form.Item>
+          <Space wrap>
+            <Form.Item name="level" label="级别"><Select options={levelOptions} style={{ width: 120 }} /></Form.Item>
+            <Form.Item name="audience" label="显示位置"><Select options={audienceOptions} style={{ width: 180 }} /></Form.Item>
+          </Space>
+          <Space wrap>
+            <Form.Item name="startsAt" label="开始时间" extra="为空时立即显示"><Input type="datetime-local" /></Form.Item>
+            <Form.Item name="endsAt" label="结束时间" extra="为空时一直显示"><Input type="datetime-local" /></Form.Item>
+          </Space>
+          <Form.Item name="dismissible" valuePropName="checked">
+            <Checkbox>允许关闭</Checkbox>
+          </Form.Item>
+          {editing !== 'new' && (
+            <Form.Item name="resetDismissals" valuePropName="checked">
+              <Checkbox>重新向已关闭的用户显示</Checkbox>
+            </Form.Item>
+          )}
+        </Form>
+      </Modal>
+    </Card>
+  )
+}
+
+export default AnnouncementsCard
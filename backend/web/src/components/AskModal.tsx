@@ -0,0 +1,114 @@
+import { Alert, Button, Input, List, Modal, Space, Typography } from 'antd'
+import { Fragment, useEffect, useState } from 'react'
+import type { AnswerCitation, ShareAnswer } from '../api/share'
+
+const { Paragraph, Text } = Typography
+
+interface AskModalProps {
+  open: boolean
+  title: string
+  ask: (question: string) => Promise<{ code: number; msg: string; data: ShareAnswer }>
+  onClose: () => void
+}
+
+// 回答中的 [n] 渲染为指向出处章节的链接
+function renderAnswer(answer: string, citations: AnswerCitation[], onNavigate: () => void) {
+  const byIndex = new Map(citations.map((c) => [c.index, c]))
+  return answer.split(/(\[\d+\])/).map((part, i) => {
+    const m = part.match(/^\[(\d+)\]$/)
+    const cite = m ? byIndex.get(Number(m[1])) : undefined
+    if (!cite) return <Fragment key={i}>{part}</Fragment>
+    return (
+      <a key={i} href={cite.url} title={cite.heading || cite.docTitle} onClick={onNavigate}>
+        <sup>[{cite.index}]</sup>
+      </a>
+    )
+  })
+}
+
+// 读者问答：依据分享或合集的正文回答问题，并列出引用的章节
+function AskModal({ open, title, ask, onClose }: AskModalProps) {
+  const [question, setQuestion] = useState('')
+  const [result, setResult] = useState<ShareAnswer | null>(null)
+  const [error, setError] = useState('')
+  const [loading, setLoading] = useState(false)
+
+  useEffect(() => {
+    if (!open) {
+      setResult(null)
+      setError('')
+    }
+  }, [open])
+
+  const submit = async () => {
+    const q = question.trim()
+    if (!q) return
+    setLoading(true)
+    setError('')
+    try {
+      const res = await ask(q)
+      if (res.code === 0) {
+        setResult(res.data)
+      } else {
+        setError(res.msg || '回答失败')
+      }
+    } catch (e: any) {
+      setError(e.response?.data?.msg || e.message || '回答失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  return (
+    <Modal open={open} title={title} width={640} footer={null} onCancel={onClose}>
+      <Space direction="vertical" style={{ width: '100%' }} size="middle">
+        <Input.TextArea
+          value={question}
+          maxLength={500}
+          autoSize={{ minRows: 2, maxRows: 5 }}
+          placeholder="就正文内容提问，回答仅依据正文并注明出处"
+          onChange={(e) => setQuestion(e.target.value)}
+          onPressEnter={(e) => {
+            if (!e.shiftKey) {
+              e.preventDefault()
+              submit()
+            }
+          }}
+        />
+        <Button type="primary" loading={loading} disabled={!question.trim()} onClick={submit}>
+          提问
+        </Button>
+        {error && <Alert type="error" showIcon message={error} />}
+        {result && (
+          <>
+            <Paragraph style={{ whiteSpace: 'pre-wrap', marginBottom: 0 }}>
+              {renderAnswer(result.answer, result.citations, onClose)}
+            </Paragraph>
+            {result.citations.length > 0 && (
+              <List
+                size="small"
+                header={<Text type="secondary">出处</Text>}
+                dataSource={result.citations}
+                renderItem={(c) => (
+                  <List.Item>
+                    <List.Item.Meta
+                      title={
+                        <a href={c.url} onClick={onClose}>
+                          [{c.index}] {c.docTitle}{c.heading ? ` › ${c.heading}` : ''}
+                        </a>
+                      }
+                      description={c.excerpt}
+                    />
+                  </List.Item>
+                )}
+              />
+            )}
+            <Text type="secondary">回答由模型生成，可能有误，请以原文为准。</Text>
+          </>
+        )}
+      </Space>
+    </Modal>
+  )
+}
+
+export default AskModal
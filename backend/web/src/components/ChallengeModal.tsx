@@ -0,0 +1,88 @@
+import { Modal, Typography } from 'antd'
+import { useEffect, useRef, useState } from 'react'
+
+const { Text } = Typography
+
+// 各服务商的组件脚本与全局对象，三者的 render(el, { sitekey, callback }) 用法一致
+const providers: Record<string, { script: string; global: string }> = {
+  turnstile: { script: 'https://challenges.cloudflare.com/turnstile/v0/api.js', global: 'turnstile' },
+  hcaptcha: { script: 'https://js.hcaptcha.com/1/api.js', global: 'hcaptcha' },
+  recaptcha: { script: 'https://www.google.com/recaptcha/api.js', global: 'grecaptcha' },
+}
+
+const loaded: Record<string, Promise<any>> = {}
+
+// 按需加载服务商脚本（只加载一次），脚本就绪后返回其全局对象
+const loadProvider = (provider: string): Promise<any> => {
+  const p = providers[provider]
+  if (!p) return Promise.reject(new Error(`unknown challenge provider: ${provider}`))
+  if (!loaded[provider]) {
+    loaded[provider] = new Promise((resolve, reject) => {
+      const callback = `__challengeLoaded_${provider}`
+      ;(window as any)[callback] = () => resolve((window as any)[p.global])
+      const script = document.createElement('script')
+      script.src = `${p.script}?render=explicit&onload=${callback}`
+      script.async = true
+      script.onerror = () => {
+        delete loaded[provider]
+        reject(new Error('人机验证组件加载失败'))
+      }
+      document.head.appendChild(script)
+    })
+  }
+  return loaded[provider]
+}
+
+interface ChallengeWidgetProps {
+  provider: string
+  siteKey: string
+  onToken: (token: string) => void
+}
+
+// 人机验证组件：完成验证后回调令牌
+function ChallengeWidget({ provider, siteKey, onToken }: ChallengeWidgetProps) {
+  const ref = useRef<HTMLDivElement>(null)
+  const [error, setError] = useState('')
+
+  useEffect(() => {
+    let cancelled = false
+    loadProvider(provider)
+      .then((api) => {
+        if (cancelled || !ref.current) return
+        api.render(ref.current, { sitekey: siteKey, callback: onToken })
+      })
+      .catch((e: Error) => setError(e.message))
+    return () => {
+      cancelled = true
+    }
+  }, [provider, siteKey])
+
+  return (
+    <div>
+      <div ref={ref} style={{ minHeight: 65, marginTop: 12 }} />
+      {error && <Text type="danger">{error}</Text>}
+    </div>
+  )
+}
+
+// solveChallenge 弹出人机验证，完成后返回令牌；读者关闭弹窗时返回 null
+export const solveChallenge = (provider: string, siteKey: string): Promise<string | null> =>
+  new Promise((resolve) => {
+    let done = false
+    const finish = (token: string | null) => {
+      if (done) return
+      done = true
+      modal.destroy()
+      resolve(token)
+    }
+    const modal = Modal.info({
+      title: '请完成人机验证',
+      content: <ChallengeWidget provider={provider} siteKey={siteKey} onToken={(t) => finish(t)} />,
+      okText: '取消',
+      okType: 'default',
+      closable: true,
+      maskClosable: false,
+      onOk: () => finish(null),
+      onCancel: () => finish(null),
+    })
+  })
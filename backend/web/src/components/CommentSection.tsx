@@ -0,0 +1,105 @@
+import { Button, Input, List, message, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { createComment, getComments, type ShareComment } from '../api/share'
+
+const { Text, Title } = Typography
+
+interface CommentSectionProps {
+  shareId: string
+  password?: string
+}
+
+// 分享页底部的读者评论：未开放评论时不显示；匿名读者需填写昵称，评论审核后公开
+function CommentSection({ shareId, password }: CommentSectionProps) {
+  const [enabled, setEnabled] = useState(false)
+  const [items, setItems] = useState<ShareComment[]>([])
+  const [author, setAuthor] = useState('')
+  const [content, setContent] = useState('')
+  const [website, setWebsite] = useState('')
+  const [submitting, setSubmitting] = useState(false)
+  const loggedIn = !!localStorage.getItem('session_token')
+
+  useEffect(() => {
+    getComments(shareId, password)
+      .then(res => {
+        setEnabled(!!res.data?.enabled)
+        setItems(res.data?.items ?? [])
+      })
+      .catch(() => setEnabled(false))
+  }, [shareId])
+
+  if (!enabled) return null
+
+  const submit = async () => {
+    if (!content.trim()) {
+      message.warning('请输入评论内容')
+      return
+    }
+    if (!loggedIn && !author.trim()) {
+      message.warning('请输入昵称')
+      return
+    }
+    setSubmitting(true)
+    try {
+      const res = await createComment(shareId, { author, content, website }, password)
+      if (res.code === 0 && res.data) {
+        setContent('')
+        if (res.data.status === 'approved') {
+          setItems([...items, res.data])
+          message.success('评论已发表')
+        } else {
+          message.info('评论已提交，审核通过后显示')
+        }
+      } else {
+        message.error(res.msg || '发表失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.status === 429 ? '评论过于频繁，请稍后再试' : e.response?.data?.msg || e.message || '发表失败')
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  return (
+    <section className="share-comments">
+      <Title level={4}>评论（{items.length}）</Title>
+      <List
+        dataSource={items}
+        locale={{ emptyText: '还没有评论' }}
+        renderItem={(item) => (
+          <List.Item key={item.id}>
+            <List.Item.Meta
+              title={<>{item.author} <Text type="secondary" className="share-comment-time">{new Date(item.createdAt).toLocaleString('zh-CN')}</Text></>}
+              description={<div className="share-comment-content">{item.content}</div>}
+            />
+          </List.Item>
+        )}
+      />
+      <div className="share-comment-form">
+        {!loggedIn && (
+          <Input placeholder="昵称" maxLength={50} value={author} onChange={(e) => setAuthor(e.target.value)} />
+        )}
+        {/* 蜜罐字段：对读者隐藏，自动填表的机器人会填写 */}
+        <input
+          className="share-comment-hp"
+          name="website"
+          tabIndex={-1}
+          autoComplete="off"
+          aria-hidden="true"
+          value={website}
+          onChange={(e) => setWebsite(e.target.value)}
+        />
+        <Input.TextArea
+          placeholder={loggedIn ? '写下你的评论' : '写下你的评论（匿名评论需审核后显示）'}
+          autoSize={{ minRows: 3, maxRows: 8 }}
+          maxLength={5000}
+          value={content}
+          onChange={(e) => setContent(e.target.value)}
+        />
+        <Button type="primary" loading={submitting} onClick={submit}>发表评论</Button>
+      </div>
+    </section>
+  )
+}
+
+export default CommentSection
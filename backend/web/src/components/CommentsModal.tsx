@@ -0,0 +1,138 @@
+import { Button, message, Modal, Popconfirm, Segmented, Space, Switch, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { approveComment, deleteComment, listOwnerComments, setCommentsEnabled, type ShareComment } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface CommentsModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+}
+
+// 分享评论管理：开放/关闭评论，审核匿名评论并删除不当内容
+function CommentsModal({ shareId, docTitle, onClose }: CommentsModalProps) {
+  const [items, setItems] = useState<ShareComment[]>([])
+  const [enabled, setEnabled] = useState(false)
+  const [filter, setFilter] = useState<'pending' | ''>('pending')
+  const [loading, setLoading] = useState(false)
+
+  const load = async (id: string) => {
+    setLoading(true)
+    try {
+      const res = await listOwnerComments(id, filter)
+      if (res.code === 0) {
+        setItems(res.data.items || [])
+        setEnabled(res.data.enabled)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) load(shareId)
+    else setItems([])
+  }, [shareId, filter])
+
+  const run = async (action: () => Promise<{ code: number; msg: string }>, ok: string) => {
+    if (!shareId) return
+    try {
+      const res = await action()
+      if (res.code === 0) {
+        message.success(ok)
+        load(shareId)
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    }
+  }
+
+  const toggle = async (checked: boolean) => {
+    if (!shareId) return
+    await run(() => setCommentsEnabled(shareId, checked), checked ? '已开放评论' : '已关闭评论')
+  }
+
+  const columns = [
+    {
+      title: '评论者',
+      key: 'author',
+      width: 140,
+      render: (r: ShareComment) => (
+        <Space size={4} direction="vertical">
+          <Text strong>{r.author}</Text>
+          {!r.userId && <Tag>匿名</Tag>}
+        </Space>
+      ),
+    },
+    {
+      title: '内容',
+      dataIndex: 'content',
+      key: 'content',
+      render: (t: string) => <Paragraph ellipsis={{ rows: 3, expandable: true }} style={{ margin: 0, whiteSpace: 'pre-wrap' }}>{t}</Paragraph>,
+    },
+    {
+      title: '状态',
+      dataIndex: 'status',
+      key: 'status',
+      width: 90,
+      render: (s: ShareComment['status']) => s === 'pending' ? <Tag color="orange">待审核</Tag> : <Tag color="green">已公开</Tag>,
+    },
+    { title: '时间', dataIndex: 'createdAt', key: 'createdAt', width: 170, render: (t: string) => new Date(t).toLocaleString() },
+    {
+      title: '操作',
+      key: 'action',
+      width: 130,
+      render: (r: ShareComment) => (
+        <Space size="small">
+          {r.status === 'pending' && (
+            <Button type="link" size="small" onClick={() => run(() => approveComment(shareId!, r.id), '已通过')}>通过</Button>
+          )}
+          <Popconfirm title="删除这条评论？" onConfirm={() => run(() => deleteComment(shareId!, r.id), '已删除')}>
+            <Button type="link" size="small" danger>删除</Button>
+          </Popconfirm>
+        </Space>
+      ),
+    },
+  ]
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`评论${docTitle ? ` · ${docTitle}` : ''}`}
+      width={860}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Space style={{ marginBottom: 16, width: '100%', justifyContent: 'space-between' }}>
+        <Space>
+          <Switch checked={enabled} onChange={toggle} />
+          <Text>开放读者评论</Text>
+          <Text type="secondary">匿名评论需审核后公开</Text>
+        </Space>
+        <Segmented
+          value={filter}
+          onChange={(v) => setFilter(v as 'pending' | '')}
+          options={[{ label: '待审核', value: 'pending' }, { label: '全部', value: '' }]}
+        />
+      </Space>
+      <Table
+        size="small"
+        rowKey="id"
+        loading={loading}
+        dataSource={items}
+        columns={columns}
+        pagination={items.length > 10 ? { pageSize: 10 } : false}
+        locale={{ emptyText: filter === 'pending' ? '没有待审核的评论' : '暂无评论' }}
+      />
+    </Modal>
+  )
+}
+
+export default CommentsModal
@@ -0,0 +1,71 @@
+import { DownloadOutlined } from '@ant-design/icons'
+import { Button, Input, Space, Table, Typography } from 'antd'
+import { useMemo, useState } from 'react'
+import { ShareTable } from '../api/share'
+
+const { Text } = Typography
+
+interface DataTableProps {
+  table: ShareTable
+  csvUrl: string
+}
+
+// 数值列按数值比较，其余按文本（中文按拼音）比较
+const compareCells = (a: string, b: string) => {
+  const na = Number(a.replace(/[,，%\s]/g, ''))
+  const nb = Number(b.replace(/[,，%\s]/g, ''))
+  if (a.trim() !== '' && b.trim() !== '' && !Number.isNaN(na) && !Number.isNaN(nb)) {
+    return na - nb
+  }
+  return a.localeCompare(b, 'zh-CN', { numeric: true })
+}
+
+// 分享正文中的大表格：客户端排序、筛选，并提供 CSV 下载
+function DataTable({ table, csvUrl }: DataTableProps) {
+  const [keyword, setKeyword] = useState('')
+
+  const rows = useMemo(() => {
+    const kw = keyword.trim().toLowerCase()
+    const all = table.rows.map((cells, i) => ({ key: i, cells }))
+    if (!kw) return all
+    return all.filter(r => r.cells.some(cell => cell.toLowerCase().includes(kw)))
+  }, [table.rows, keyword])
+
+  const columns = table.headers.map((header, col) => ({
+    key: col,
+    title: header,
+    align: table.align[col] || undefined,
+    sorter: (a: { cells: string[] }, b: { cells: string[] }) => compareCells(a.cells[col] ?? '', b.cells[col] ?? ''),
+    render: (_: unknown, r: { cells: string[] }) => <span className="data-table-cell">{r.cells[col]}</span>,
+  }))
+
+  return (
+    <div className="data-table">
+      <Space className="data-table-toolbar" wrap>
+        <Input.Search
+          allowClear
+          size="small"
+          placeholder="筛选表格"
+          onChange={e => setKeyword(e.target.value)}
+          style={{ width: 200 }}
+        />
+        <Text type="secondary">
+          {rows.length === table.rows.length ? `${table.rows.length} 行` : `${rows.length} / ${table.rows.length} 行`}
+        </Text>
+        <Button size="small" icon={<DownloadOutlined />} href={csvUrl}>
+          CSV
+        </Button>
+      </Space>
+      <Table
+        size="small"
+        bordered
+        columns={columns}
+        dataSource={rows}
+        scroll={{ x: 'max-content' }}
+        pagination={rows.length > 50 ? { pageSize: 50, showSizeChanger: true } : false}
+      />
+    </div>
+  )
+}
+
+export default DataTable
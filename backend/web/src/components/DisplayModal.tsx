@@ -0,0 +1,112 @@
+import { Divider, message, Modal, Radio, Space, Switch, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { getSharePrerender, setShareDisplay, type SharePrerender, type ShareTOC } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface DisplayModalProps {
+  shareId: string | null
+  docTitle?: string
+  toc?: ShareTOC
+  headingAnchors?: boolean
+  onClose: () => void
+  onChanged?: () => void
+}
+
+const noPrerender: SharePrerender = { math: false, mermaid: false, code: false }
+
+// 阅读页展示设置：目录样式、标题锚点与服务端预渲染。标题锚点按标题文字生成，重新发布后不变，可用 #锚点 链接到具体章节
+function DisplayModal({ shareId, docTitle, toc, headingAnchors, onClose, onChanged }: DisplayModalProps) {
+  const [tocValue, setTocValue] = useState<ShareTOC>('side')
+  const [anchors, setAnchors] = useState(true)
+  const [prerender, setPrerender] = useState<SharePrerender>(noPrerender)
+  const [available, setAvailable] = useState<SharePrerender>(noPrerender)
+  const [saving, setSaving] = useState(false)
+
+  useEffect(() => {
+    if (!shareId) return
+    setTocValue(toc || 'side')
+    setAnchors(headingAnchors ?? true)
+    setPrerender(noPrerender)
+    setAvailable(noPrerender)
+    getSharePrerender(shareId)
+      .then(res => {
+        if (res.code !== 0) return
+        setPrerender(res.data.settings)
+        setAvailable(res.data.available)
+      })
+      .catch(() => {})
+  }, [shareId])
+
+  const save = async () => {
+    if (!shareId) return
+    setSaving(true)
+    try {
+      const res = await setShareDisplay(shareId, { toc: tocValue, headingAnchors: anchors, prerender })
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      message.success('已保存')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`阅读页显示${docTitle ? ` · ${docTitle}` : ''}`}
+      okText="保存"
+      confirmLoading={saving}
+      onOk={save}
+      onCancel={onClose}
+    >
+      <Paragraph>目录样式</Paragraph>
+      <Radio.Group value={tocValue} onChange={e => setTocValue(e.target.value)}>
+        <Space direction="vertical">
+          <Radio value="side">侧边栏（移动端通过按钮打开）</Radio>
+          <Radio value="floating">悬浮在正文右上角，可收起</Radio>
+          <Radio value="off">不显示目录</Radio>
+        </Space>
+      </Radio.Group>
+      <Space style={{ marginTop: 16 }}>
+        <Switch checked={anchors} onChange={setAnchors} />
+        <Text>标题旁显示锚点链接</Text>
+      </Space>
+      <Paragraph type="secondary" style={{ marginTop: 12 }}>
+        每个标题都有按文字生成的锚点，在分享链接后加上 <Text code>#锚点</Text> 即可直接打开到对应章节；
+        标题文字与顺序不变时，重新发布后锚点保持不变。
+      </Paragraph>
+      <Divider />
+      <Paragraph>服务端预渲染</Paragraph>
+      <Space direction="vertical">
+        {([
+          ['math', '公式（KaTeX）'],
+          ['mermaid', 'Mermaid 图'],
+          ['code', '代码高亮'],
+        ] as [keyof SharePrerender, string][]).map(([key, label]) => (
+          <Space key={key}>
+            <Switch
+              checked={prerender[key]}
+              disabled={!available[key] && !prerender[key]}
+              onChange={v => setPrerender(p => ({ ...p, [key]: v }))}
+            />
+            <Text>{label}</Text>
+            {!available[key] && <Text type="secondary">（服务器未配置）</Text>}
+          </Space>
+        ))}
+      </Space>
+      <Paragraph type="secondary" style={{ marginTop: 12 }}>
+        在服务器上将公式、Mermaid 图与代码高亮渲染为 HTML，阅读页无需加载相应脚本，禁用脚本时也能正确显示；
+        渲染结果按内容缓存，渲染失败的部分按原样显示。
+      </Paragraph>
+    </Modal>
+  )
+}
+
+export default DisplayModal
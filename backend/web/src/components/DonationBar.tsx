@@ -0,0 +1,32 @@
+import { CoffeeOutlined, HeartOutlined, LinkOutlined } from '@ant-design/icons'
+import { Button, Space, Typography } from 'antd'
+import type { DonationLinks } from '../api/share'
+
+const { Text } = Typography
+
+interface DonationBarProps {
+  donation: DonationLinks
+}
+
+// 文末的赞助按钮：分享者在账号设置中填写的 Ko-fi、爱发电与其他赞助页面
+function DonationBar({ donation }: DonationBarProps) {
+  const link = (href: string) => ({ href, target: '_blank', rel: 'noopener noreferrer' })
+  return (
+    <div className="share-donation">
+      <Text type="secondary">觉得有帮助？欢迎赞助作者</Text>
+      <Space wrap>
+        {donation.kofi && (
+          <Button icon={<CoffeeOutlined />} {...link(`https://ko-fi.com/${encodeURIComponent(donation.kofi)}`)}>Ko-fi</Button>
+        )}
+        {donation.afdian && (
+          <Button icon={<HeartOutlined />} {...link(`https://afdian.com/a/${encodeURIComponent(donation.afdian)}`)}>爱发电</Button>
+        )}
+        {donation.url && (
+          <Button icon={<LinkOutlined />} {...link(donation.url)}>{donation.label || '赞助'}</Button>
+        )}
+      </Space>
+    </div>
+  )
+}
+
+export default DonationBar
@@ -0,0 +1,65 @@
+import { HeartOutlined } from '@ant-design/icons'
+import { Button, Card, Form, Input, Space, Typography, message } from 'antd'
+import { useEffect, useState } from 'react'
+import { updateDonationLinks, type DonationLinks } from '../api/share'
+
+const { Paragraph } = Typography
+
+interface Props {
+  donation?: DonationLinks | null
+  onChange: () => void
+}
+
+// 赞助链接：Ko-fi、爱发电与其他赞助页面，显示在全部公开分享的阅读页底部
+function DonationCard({ donation, onChange }: Props) {
+  const [form] = Form.useForm<DonationLinks>()
+  const [saving, setSaving] = useState(false)
+
+  useEffect(() => {
+    form.setFieldsValue({ kofi: '', afdian: '', url: '', label: '', ...donation })
+  }, [donation])
+
+  const save = async (values: DonationLinks) => {
+    setSaving(true)
+    try {
+      const res = await updateDonationLinks(values)
+      if (res.code === 0) {
+        message.success('已保存')
+        onChange()
+      } else {
+        message.error(res.msg || '保存失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Card
+      title={<Space><HeartOutlined /><span>赞助链接</span></Space>}
+      bordered={false}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      <Paragraph type="secondary">设置后，你的公开分享的阅读页底部会显示赞助按钮；全部留空则不显示。</Paragraph>
+      <Form form={form} layout="vertical" onFinish={save} style={{ maxWidth: 480 }}>
+        <Form.Item name="kofi" label="Ko-fi 用户名" rules={[{ pattern: /^[A-Za-z0-9_-]{0,64}$/, message: '只能包含字母、数字、- 与 _' }]}>
+          <Input addonBefore="ko-fi.com/" />
+        </Form.Item>
+        <Form.Item name="afdian" label="爱发电用户名" rules={[{ pattern: /^[A-Za-z0-9_-]{0,64}$/, message: '只能包含字母、数字、- 与 _' }]}>
+          <Input addonBefore="afdian.com/a/" />
+        </Form.Item>
+        <Form.Item name="url" label="其他赞助页面" rules={[{ type: 'url', message: '请输入 http(s) 链接' }]}>
+          <Input placeholder="https://" />
+        </Form.Item>
+        <Form.Item name="label" label="按钮文字" extra="其他赞助页面的按钮文字，留空时显示「赞助」">
+          <Input maxLength={30} />
+        </Form.Item>
+        <Button type="primary" htmlType="submit" loading={saving}>保存</Button>
+      </Form>
+    </Card>
+  )
+}
+
+export default DonationCard
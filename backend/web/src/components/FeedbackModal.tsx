@@ -0,0 +1,134 @@
+import { Button, message, Modal, Popconfirm, Space, Statistic, Switch, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { deleteFeedback, getFeedbackStats, setFeedbackEnabled, type FeedbackSummary, type ShareFeedback } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface FeedbackModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+  onChanged?: () => void
+}
+
+const emptySummary: FeedbackSummary = { total: 0, helpful: 0, notHelpful: 0, helpfulRate: 0, comments: 0 }
+
+// 读者反馈：开启文末的「是否有帮助」提示，查看汇总与读者补充的文字说明，删除垃圾信息
+function FeedbackModal({ shareId, docTitle, onClose, onChanged }: FeedbackModalProps) {
+  const [enabled, setEnabled] = useState(false)
+  const [summary, setSummary] = useState<FeedbackSummary>(emptySummary)
+  const [items, setItems] = useState<ShareFeedback[]>([])
+  const [page, setPage] = useState(1)
+  const [total, setTotal] = useState(0)
+  const [loading, setLoading] = useState(false)
+
+  const load = async (id: string, p: number) => {
+    setLoading(true)
+    try {
+      const res = await getFeedbackStats(id, p)
+      if (res.code === 0) {
+        setEnabled(res.data.enabled)
+        setSummary(res.data.summary)
+        setItems(res.data.items || [])
+        setTotal(res.data.total)
+        setPage(res.data.page)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) load(shareId, 1)
+    else {
+      setItems([])
+      setSummary(emptySummary)
+    }
+  }, [shareId])
+
+  const toggle = async (checked: boolean) => {
+    if (!shareId) return
+    try {
+      const res = await setFeedbackEnabled(shareId, checked)
+      if (res.code === 0) {
+        setEnabled(checked)
+        message.success(checked ? '已开启反馈' : '已关闭反馈')
+        onChanged?.()
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    }
+  }
+
+  const remove = async (fb: ShareFeedback) => {
+    if (!shareId) return
+    try {
+      const res = await deleteFeedback(shareId, fb.id)
+      if (res.code === 0) load(shareId, page)
+      else message.error(res.msg || '删除失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '删除失败')
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`读者反馈${docTitle ? ` · ${docTitle}` : ''}`}
+      width={760}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Space style={{ marginBottom: 16 }}>
+        <Switch checked={enabled} onChange={toggle} />
+        <Text>在文末询问读者「是否有帮助」</Text>
+      </Space>
+      <Space size={48} style={{ marginBottom: 16 }}>
+        <Statistic title="反馈数" value={summary.total} />
+        <Statistic title="有帮助" value={summary.helpful} />
+        <Statistic title="没有帮助" value={summary.notHelpful} />
+        <Statistic title="有帮助比例" value={summary.total ? (summary.helpfulRate * 100).toFixed(1) : '-'} suffix={summary.total ? '%' : undefined} />
+      </Space>
+      <Table<ShareFeedback>
+        size="small"
+        rowKey="id"
+        loading={loading}
+        dataSource={items}
+        pagination={total > 20 ? { current: page, pageSize: 20, total, onChange: p => shareId && load(shareId, p) } : false}
+        locale={{ emptyText: '暂无文字反馈' }}
+        columns={[
+          {
+            title: '评价',
+            dataIndex: 'helpful',
+            width: 100,
+            render: (v: boolean) => v ? <Tag color="green">有帮助</Tag> : <Tag color="orange">没有帮助</Tag>,
+          },
+          {
+            title: '说明',
+            dataIndex: 'comment',
+            render: (t: string) => <Paragraph ellipsis={{ rows: 3, expandable: true }} style={{ margin: 0, whiteSpace: 'pre-wrap' }}>{t}</Paragraph>,
+          },
+          { title: '时间', dataIndex: 'createdAt', width: 170, render: (t: string) => new Date(t).toLocaleString() },
+          {
+            title: '操作',
+            key: 'action',
+            width: 80,
+            render: (fb: ShareFeedback) => (
+              <Popconfirm title="删除这条反馈？" onConfirm={() => remove(fb)}>
+                <Button type="link" size="small" danger>删除</Button>
+              </Popconfirm>
+            ),
+          },
+        ]}
+      />
+    </Modal>
+  )
+}
+
+export default FeedbackModal
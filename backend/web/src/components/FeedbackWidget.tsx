@@ -0,0 +1,80 @@
+import { DislikeOutlined, LikeOutlined } from '@ant-design/icons'
+import { Button, Input, message, Space, Typography } from 'antd'
+import { useState } from 'react'
+import { submitFeedback } from '../api/share'
+
+const { Text } = Typography
+
+interface FeedbackWidgetProps {
+  shareId: string
+  password?: string
+}
+
+// 文末的「是否有帮助」反馈：先选择有 / 没有帮助，再可选填写文字说明；比评论更轻量，不公开显示
+function FeedbackWidget({ shareId, password }: FeedbackWidgetProps) {
+  const [helpful, setHelpful] = useState<boolean | null>(null)
+  const [comment, setComment] = useState('')
+  const [website, setWebsite] = useState('')
+  const [submitting, setSubmitting] = useState(false)
+  const [done, setDone] = useState(false)
+
+  const send = async (value: boolean, text?: string) => {
+    setSubmitting(true)
+    try {
+      const res = await submitFeedback(shareId, { helpful: value, comment: text, website }, password)
+      if (res.code !== 0) {
+        message.error(res.msg || '提交失败')
+        return false
+      }
+      return true
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '提交失败')
+      return false
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  const choose = async (value: boolean) => {
+    if (await send(value)) setHelpful(value)
+  }
+
+  const submitComment = async () => {
+    if (helpful === null || !comment.trim()) return
+    if (await send(helpful, comment.trim())) setDone(true)
+  }
+
+  if (done) {
+    return (
+      <div className="share-feedback">
+        <Text type="secondary">感谢你的反馈！</Text>
+      </div>
+    )
+  }
+
+  return (
+    <div className="share-feedback">
+      <Space wrap>
+        <Text>这篇内容对你有帮助吗？</Text>
+        <Button size="small" icon={<LikeOutlined />} type={helpful === true ? 'primary' : 'default'} loading={submitting && helpful === null} onClick={() => choose(true)}>
+          有帮助
+        </Button>
+        <Button size="small" icon={<DislikeOutlined />} type={helpful === false ? 'primary' : 'default'} onClick={() => choose(false)}>
+          没有帮助
+        </Button>
+      </Space>
+      {helpful !== null && (
+        <Space direction="vertical" style={{ width: '100%', marginTop: 12 }}>
+          <Text type="secondary">{helpful ? '感谢！' : '抱歉没能帮到你。'}愿意的话可以补充说明（仅作者可见）：</Text>
+          <Input.TextArea value={comment} onChange={e => setComment(e.target.value)} autoSize={{ minRows: 2, maxRows: 6 }} maxLength={1000} showCount />
+          <input type="text" name="website" value={website} onChange={e => setWebsite(e.target.value)} tabIndex={-1} autoComplete="off" style={{ display: 'none' }} />
+          <Button size="small" type="primary" loading={submitting} disabled={!comment.trim()} onClick={submitComment}>
+            提交
+          </Button>
+        </Space>
+      )}
+    </div>
+  )
+}
+
+export default FeedbackWidget
@@ -0,0 +1,79 @@
+import { Button, Form, Input, message, Typography } from 'antd'
+import { useState } from 'react'
+import { submitForm, type ShareForm } from '../api/share'
+
+const { Text } = Typography
+
+interface FormBlockProps {
+  shareId: string
+  form: ShareForm
+  password?: string
+}
+
+// 正文中的表单块：按字段定义渲染输入框，提交后仅作者可在分享管理中查看与导出
+function FormBlock({ shareId, form, password }: FormBlockProps) {
+  const [instance] = Form.useForm<Record<string, string>>()
+  const [website, setWebsite] = useState('')
+  const [submitting, setSubmitting] = useState(false)
+  const [done, setDone] = useState(false)
+
+  const submit = async (values: Record<string, string>) => {
+    setSubmitting(true)
+    try {
+      const res = await submitForm(shareId, form.key, { values, website }, password)
+      if (res.code === 0) setDone(true)
+      else message.error(res.msg || '提交失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '提交失败')
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  if (done) {
+    return (
+      <div className="share-form">
+        {form.title && <Text strong className="share-form-title">{form.title}</Text>}
+        <Text type="secondary">已提交，感谢填写！</Text>
+        <Button
+          size="small"
+          onClick={() => {
+            instance.resetFields()
+            setDone(false)
+          }}
+        >
+          再填一份
+        </Button>
+      </div>
+    )
+  }
+
+  return (
+    <div className="share-form">
+      {form.title && <Text strong className="share-form-title">{form.title}</Text>}
+      <Form form={instance} layout="vertical" onFinish={submit} requiredMark>
+        {form.fields.map(f => (
+          <Form.Item
+            key={f.label}
+            name={f.label}
+            label={f.label}
+            rules={[
+              { required: f.required, whitespace: true, message: `请填写${f.label}` },
+              ...(f.type === 'email' ? [{ type: 'email' as const, message: '邮箱格式不正确' }] : []),
+            ]}
+          >
+            {f.type === 'textarea'
+              ? <Input.TextArea autoSize={{ minRows: 3, maxRows: 8 }} maxLength={2000} showCount />
+              : <Input type={f.type === 'email' ? 'email' : 'text'} maxLength={200} />}
+          </Form.Item>
+        ))}
+        <input type="text" name="website" value={website} onChange={e => setWebsite(e.target.value)} tabIndex={-1} autoComplete="off" style={{ display: 'none' }} />
+        <Button type="primary" htmlType="submit" loading={submitting}>
+          提交
+        </Button>
+      </Form>
+    </div>
+  )
+}
+
+export default FormBlock
@@ -0,0 +1,181 @@
+import { DownloadOutlined } from '@ant-design/icons'
+import { Button, Empty, message, Modal, Popconfirm, Select, Space, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { deleteFormSubmission, downloadFormSubmissions, listFormSubmissions, listOwnerForms, type FormSubmission, type OwnerForm } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface FormsModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+}
+
+// 表单提交：选择正文中的表单查看读者提交，导出 CSV，删除垃圾信息
+function FormsModal({ shareId, docTitle, onClose }: FormsModalProps) {
+  const [forms, setForms] = useState<OwnerForm[]>([])
+  const [current, setCurrent] = useState<string>('')
+  const [items, setItems] = useState<FormSubmission[]>([])
+  const [page, setPage] = useState(1)
+  const [total, setTotal] = useState(0)
+  const [loading, setLoading] = useState(false)
+  const [exporting, setExporting] = useState(false)
+
+  const loadForms = async (id: string) => {
+    try {
+      const res = await listOwnerForms(id)
+      if (res.code === 0) {
+        const list = res.data.items || []
+        setForms(list)
+        setCurrent(list[0]?.key ?? '')
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    }
+  }
+
+  const load = async (id: string, key: string, p: number) => {
+    setLoading(true)
+    try {
+      const res = await listFormSubmissions(id, key, p)
+      if (res.code === 0) {
+        setItems(res.data.items || [])
+        setTotal(res.data.total)
+        setPage(res.data.page)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) loadForms(shareId)
+    else {
+      setForms([])
+      setCurrent('')
+    }
+  }, [shareId])
+
+  useEffect(() => {
+    if (shareId && current) load(shareId, current, 1)
+    else setItems([])
+  }, [shareId, current])
+
+  const form = forms.find(f => f.key === current)
+  // 当前字段在前，只出现在早期提交中的字段在后
+  const columns = form ? form.fields.map(f => f.label) : []
+  for (const item of items) {
+    for (const label of Object.keys(item.values)) {
+      if (!columns.includes(label)) columns.push(label)
+    }
+  }
+
+  const exportCsv = async () => {
+    if (!shareId || !form) return
+    setExporting(true)
+    try {
+      const blob = await downloadFormSubmissions(shareId, form.key)
+      const url = URL.createObjectURL(blob)
+      const a = document.createElement('a')
+      a.href = url
+      a.download = `${form.title || docTitle || shareId}-form.csv`
+      a.click()
+      URL.revokeObjectURL(url)
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '导出失败')
+    } finally {
+      setExporting(false)
+    }
+  }
+
+  const remove = async (sub: FormSubmission) => {
+    if (!shareId || !current) return
+    try {
+      const res = await deleteFormSubmission(shareId, current, sub.id)
+      if (res.code === 0) {
+        load(shareId, current, page)
+        setForms(prev => prev.map(f => (f.key === current ? { ...f, submissions: Math.max(0, f.submissions - 1) } : f)))
+      } else {
+        message.error(res.msg || '删除失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '删除失败')
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`表单提交${docTitle ? ` · ${docTitle}` : ''}`}
+      width={880}
+      footer={null}
+      onCancel={onClose}
+    >
+      {forms.length === 0 ? (
+        <Empty description="正文中没有表单，可添加 ```form 代码块收集读者提交" />
+      ) : (
+        <>
+          <Space style={{ marginBottom: 16 }} wrap>
+            <Select
+              value={current}
+              style={{ minWidth: 280 }}
+              onChange={setCurrent}
+              options={forms.map(f => ({
+                value: f.key,
+                label: (
+                  <Space>
+                    <Text>{f.title || '未命名表单'}</Text>
+                    <Text type="secondary">{f.submissions} 条</Text>
+                    {!f.inContent && <Tag>已从正文删除</Tag>}
+                  </Space>
+                ),
+              }))}
+            />
+            <Button icon={<DownloadOutlined />} loading={exporting} disabled={!form?.submissions} onClick={exportCsv}>
+              导出 CSV
+            </Button>
+          </Space>
+          <Table<FormSubmission>
+            size="small"
+            rowKey="id"
+            loading={loading}
+            dataSource={items}
+            scroll={{ x: 'max-content' }}
+            pagination={total > 20 ? { current: page, pageSize: 20, total, onChange: p => shareId && load(shareId, current, p) } : false}
+            locale={{ emptyText: '暂无提交' }}
+            columns={[
+              ...columns.map(label => ({
+                title: label,
+                key: `field-${label}`,
+                render: (sub: FormSubmission) => (
+                  <Paragraph ellipsis={{ rows: 3, expandable: true }} style={{ margin: 0, maxWidth: 320, whiteSpace: 'pre-wrap' }}>
+                    {sub.values[label] ?? ''}
+                  </Paragraph>
+                ),
+              })),
+              { title: '时间', dataIndex: 'createdAt', width: 170, render: (t: string) => new Date(t).toLocaleString() },
+              {
+                title: '操作',
+                key: 'action',
+                width: 80,
+                render: (sub: FormSubmission) => (
+                  <Popconfirm title="删除这条提交？" onConfirm={() => remove(sub)}>
+                    <Button type="link" size="small" danger>删除</Button>
+                  </Popconfirm>
+                ),
+              },
+            ]}
+          />
+        </>
+      )}
+    </Modal>
+  )
+}
+
+export default FormsModal
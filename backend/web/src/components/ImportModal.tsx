@@ -0,0 +1,138 @@
+import { InboxOutlined } from '@ant-design/icons'
+import { Checkbox, Form, Input, InputNumber, message, Modal, Select, Typography, Upload } from 'antd'
+import { useEffect, useState } from 'react'
+import { importShare, listShareTemplates, type ImportShareOptions, type ShareTemplate } from '../api/share'
+
+const { Paragraph, Text } = Typography
+
+interface ImportModalProps {
+  open: boolean
+  onClose: () => void
+  onImported?: () => void
+}
+
+// 导入思源笔记导出的 zip（Markdown 或 HTML 及 assets/ 资源）创建分享，无需安装插件
+function ImportModal({ open, onClose, onImported }: ImportModalProps) {
+  const [file, setFile] = useState<File | null>(null)
+  const [templates, setTemplates] = useState<ShareTemplate[]>([])
+  const [importing, setImporting] = useState(false)
+  const [form] = Form.useForm<ImportShareOptions>()
+  const requirePassword = Form.useWatch('requirePassword', form)
+
+  useEffect(() => {
+    if (!open) return
+    setFile(null)
+    listShareTemplates()
+      .then(res => {
+        if (res.code !== 0) return
+        const items = res.data.items || []
+        setTemplates(items)
+        // 有默认模板时其设置优先，不再预填有效天数与公开
+        const preset = items.find(t => t.isDefault)
+        if (preset) form.setFieldsValue({ templateId: preset.id, expireDays: undefined, isPublic: undefined })
+      })
+      .catch(() => {})
+  }, [open])
+
+  const submit = async (values: ImportShareOptions) => {
+    if (!file) {
+      message.warning('请选择导出的 zip 文件')
+      return
+    }
+    setImporting(true)
+    try {
+      const res = await importShare(file, values)
+      if (res.code !== 0) {
+        message.error(res.msg || '导入失败')
+        return
+      }
+      const missing = res.data.missingAssets?.length || 0
+      Modal.success({
+        title: '导入成功',
+        content: (
+          <div>
+            <Paragraph copyable={{ text: res.data.shareUrl }} style={{ marginBottom: 8 }}>
+              <a href={res.data.shareUrl} target="_blank" rel="noreferrer">{res.data.docTitle}</a>
+            </Paragraph>
+            {missing > 0 && <Text type="warning">有 {missing} 个引用的资源不在压缩包中，阅读页中无法显示</Text>}
+          </div>
+        ),
+      })
+      onImported?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '导入失败')
+    } finally {
+      setImporting(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={open}
+      title="导入思源导出文件"
+      onCancel={onClose}
+      onOk={() => form.submit()}
+      okText="导入并发布"
+      confirmLoading={importing}
+      destroyOnClose
+    >
+      <Upload.Dragger
+        accept=".zip,application/zip"
+        maxCount={1}
+        fileList={file ? [{ uid: '-1', name: file.name, status: 'done' }] : []}
+        beforeUpload={(f) => { setFile(f); return false }}
+        onRemove={() => setFile(null)}
+        style={{ marginBottom: 16 }}
+      >
+        <p className="ant-upload-drag-icon"><InboxOutlined /></p>
+        <p className="ant-upload-text">点击或拖入 zip 文件</p>
+        <p className="ant-upload-hint">思源笔记中“导出 → Markdown 或 HTML”得到的压缩包，assets 中的图片与附件一并上传</p>
+      </Upload.Dragger>
+      <Form form={form} layout="vertical" onFinish={submit} preserve={false} initialValues={{ expireDays: 7, isPublic: true }}>
+        <Form.Item name="title" label="标题" extra="留空时使用文档中的标题或文件名">
+          <Input maxLength={200} />
+        </Form.Item>
+        {templates.length > 0 && (
+          <Form.Item name="templateId" label="发布模板">
+            <Select
+              allowClear
+              placeholder="不使用模板"
+              options={templates.map(t => ({ value: t.id, label: t.isDefault ? `${t.name}（默认）` : t.name }))}
+            />
+          </Form.Item>
+        )}
+        <Form.Item name="expireDays" label="有效天数" extra="使用模板时留空取模板的值">
+          <InputNumber min={1} max={365} style={{ width: '100%' }} />
+        </Form.Item>
+        <Form.Item name="status" label="状态">
+          <Select
+            allowClear
+            placeholder="发布"
+            options={[
+              { value: 'published', label: '发布' },
+              { value: 'unlisted', label: '不公开列出' },
+              { value: 'draft', label: '草稿' },
+            ]}
+          />
+        </Form.Item>
+        <Form.Item name="requirePassword" valuePropName="checked" style={{ marginBottom: 8 }}>
+          <Checkbox>需要访问密码</Checkbox>
+        </Form.Item>
+        {requirePassword && (
+          <Form.Item name="password" label="访问密码" rules={[{ min: 4, message: '密码至少 4 位' }]}>
+            <Input.Password />
+          </Form.Item>
+        )}
+        <Form.Item name="isPublic" valuePropName="checked" style={{ marginBottom: 8 }}>
+          <Checkbox>公开</Checkbox>
+        </Form.Item>
+        <Form.Item name="listed" valuePropName="checked">
+          <Checkbox>收录到站内搜索</Checkbox>
+        </Form.Item>
+      </Form>
+    </Modal>
+  )
+}
+
+export default ImportModal
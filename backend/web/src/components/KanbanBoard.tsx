@@ -0,0 +1,71 @@
+import { Checkbox, Progress, Typography } from 'antd'
+
+const { Text } = Typography
+
+interface KanbanColumn {
+  title: string
+  cards: { text: string; done: boolean }[]
+}
+
+// 解析 kanban 代码块：# 看板名称，## 列，- [ ] / - [x] 卡片
+const parseKanban = (source: string) => {
+  let title = ''
+  const columns: KanbanColumn[] = []
+  for (const line of source.split('\n')) {
+    const heading = line.match(/^(#{1,2})\s+(.+?)\s*$/)
+    if (heading) {
+      if (heading[1] === '#') {
+        title = heading[2]
+      } else {
+        columns.push({ title: heading[2], cards: [] })
+      }
+      continue
+    }
+    const card = line.match(/^\s*[-*+]\s+(?:\[([ xX])\]\s*)?(.*)$/)
+    if (card) {
+      if (columns.length === 0) columns.push({ title: '', cards: [] })
+      columns[columns.length - 1].cards.push({ text: card[2], done: !!card[1] && card[1] !== ' ' })
+    }
+  }
+  return { title, columns }
+}
+
+const percent = (done: number, total: number) => (total ? Math.round(done / total * 100) : 0)
+
+// 看板视图：按列展示卡片，列头与看板标题显示完成数与进度
+function KanbanBoard({ source }: { source: string }) {
+  const { title, columns } = parseKanban(source)
+  const total = columns.reduce((n, c) => n + c.cards.length, 0)
+  const done = columns.reduce((n, c) => n + c.cards.filter(card => card.done).length, 0)
+
+  return (
+    <div className="kanban">
+      <div className="kanban-header">
+        {title && <Text strong>{title}</Text>}
+        <Progress percent={percent(done, total)} size="small" format={() => `${done}/${total}`} />
+      </div>
+      <div className="kanban-columns">
+        {columns.map((col, i) => {
+          const colDone = col.cards.filter(card => card.done).length
+          return (
+            <div className="kanban-column" key={i}>
+              <div className="kanban-column-title">
+                <Text strong>{col.title || '未分组'}</Text>
+                <Text type="secondary">{colDone}/{col.cards.length}</Text>
+              </div>
+              <Progress percent={percent(colDone, col.cards.length)} size="small" showInfo={false} />
+              {col.cards.map((card, j) => (
+                <div className={`kanban-card ${card.done ? 'done' : ''}`} key={j}>
+                  <Checkbox checked={card.done} disabled />
+                  <span>{card.text}</span>
+                </div>
+              ))}
+            </div>
+          )
+        })}
+      </div>
+    </div>
+  )
+}
+
+export default KanbanBoard
@@ -0,0 +1,153 @@
+import { Alert, Button, List, message, Modal, Space, Spin, Tag, Typography } from 'antd'
+import { useEffect, useRef, useState } from 'react'
+import { createLanguageCheck, getLanguageCheck, type LanguageCheckReport, type LanguageIssue } from '../api/share'
+
+const { Text } = Typography
+
+interface LanguageCheckModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+}
+
+const statusLabels: Record<LanguageCheckReport['status'], { text: string; color: string }> = {
+  pending: { text: '检查中', color: 'processing' },
+  ok: { text: '已完成', color: 'green' },
+  failed: { text: '检查失败', color: 'red' },
+}
+
+const typeColors: Record<string, string> = {
+  misspelling: 'red',
+  grammar: 'orange',
+  typographical: 'gold',
+  style: 'blue',
+}
+
+// 在上下文中标出有问题的文字
+function issueContext(issue: LanguageIssue) {
+  const chars = Array.from(issue.context)
+  return (
+    <Text type="secondary">
+      {chars.slice(0, issue.offset).join('')}
+      <Text mark>{chars.slice(issue.offset, issue.offset + issue.length).join('')}</Text>
+      {chars.slice(issue.offset + issue.length).join('')}
+    </Text>
+  )
+}
+
+// 分享正文的拼写与语法检查报告：发布时在后台生成，只供作者参考，不修改分享内容
+function LanguageCheckModal({ shareId, docTitle, onClose }: LanguageCheckModalProps) {
+  const [enabled, setEnabled] = useState(true)
+  const [report, setReport] = useState<LanguageCheckReport | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [working, setWorking] = useState(false)
+  const timer = useRef<number>()
+
+  const load = async (id: string) => {
+    try {
+      const res = await getLanguageCheck(id)
+      if (res.code === 0) {
+        setEnabled(res.data.enabled)
+        setReport(res.data.report)
+        // 检查中时轮询状态
+        window.clearTimeout(timer.current)
+        if (res.data.report?.status === 'pending') {
+          timer.current = window.setTimeout(() => load(id), 3000)
+        }
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) {
+      setLoading(true)
+      load(shareId).finally(() => setLoading(false))
+    } else {
+      setReport(null)
+    }
+    return () => window.clearTimeout(timer.current)
+  }, [shareId])
+
+  const recheck = async () => {
+    if (!shareId) return
+    setWorking(true)
+    try {
+      const res = await createLanguageCheck(shareId)
+      if (res.code === 0) {
+        message.success('已开始检查')
+        load(shareId)
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    } finally {
+      setWorking(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`拼写与语法检查${docTitle ? ` · ${docTitle}` : ''}`}
+      width={680}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Spin spinning={loading}>
+        {!enabled ? (
+          <Alert type="info" showIcon message="服务器未配置拼写与语法检查" />
+        ) : (
+          <Space direction="vertical" style={{ width: '100%' }} size="middle">
+            <Space wrap>
+              <Text>状态：</Text>
+              {report ? <Tag color={statusLabels[report.status].color}>{statusLabels[report.status].text}</Tag> : <Tag>未检查</Tag>}
+              {report?.status === 'ok' && <Text type="secondary">{report.issueCount} 个问题</Text>}
+              {report?.language && <Text type="secondary">语言：{report.language}</Text>}
+              <Button size="small" loading={working} disabled={report?.status === 'pending'} onClick={recheck}>
+                {report ? '重新检查' : '开始检查'}
+              </Button>
+            </Space>
+            {report?.status === 'failed' && report.error && <Alert type="error" showIcon message={report.error} />}
+            {report?.stale && report.status !== 'pending' && <Alert type="warning" showIcon message="正文已更新，报告与当前内容不一致，重新发布或重新检查后更新" />}
+            {report?.truncated && <Alert type="info" showIcon message="正文或问题数超出服务器上限，报告只包含部分内容" />}
+            {report && report.status !== 'failed' && (
+              <List
+                size="small"
+                locale={{ emptyText: report.status === 'pending' ? '检查中' : '未发现问题' }}
+                dataSource={report.issues}
+                renderItem={(issue) => (
+                  <List.Item>
+                    <List.Item.Meta
+                      title={
+                        <Space wrap>
+                          <Text>{issue.message}</Text>
+                          {issue.type && <Tag color={typeColors[issue.type]}>{issue.type}</Tag>}
+                        </Space>
+                      }
+                      description={
+                        <Space direction="vertical" size={2}>
+                          {issueContext(issue)}
+                          {!!issue.replacements?.length && (
+                            <Text type="secondary">建议：{issue.replacements.join('、')}</Text>
+                          )}
+                        </Space>
+                      }
+                    />
+                  </List.Item>
+                )}
+              />
+            )}
+            <Text type="secondary">发布或更新分享时自动检查，代码块与公式不参与检查；报告只供参考，不会修改分享内容。</Text>
+          </Space>
+        )}
+      </Spin>
+    </Modal>
+  )
+}
+
+export default LanguageCheckModal
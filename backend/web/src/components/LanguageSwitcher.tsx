@@ -0,0 +1,40 @@
+import { TranslationOutlined } from '@ant-design/icons'
+import { Select } from 'antd'
+import { useNavigate } from 'react-router-dom'
+
+// languageName 以浏览器语言显示语言标签的名称，如 en → 英语
+export function languageName(lang: string) {
+  try {
+    return new Intl.DisplayNames([navigator.language], { type: 'language' }).of(lang) || lang
+  } catch {
+    return lang
+  }
+}
+
+interface LanguageSwitcherProps {
+  shareId: string
+  lang?: string // 当前阅读的译文语言，原文为空
+  sourceLang?: string
+  translations: string[]
+}
+
+// 阅读页的语言切换：在原文 (/s/<id>) 与各译文 (/s/<id>/<语言>) 之间切换
+function LanguageSwitcher({ shareId, lang, sourceLang, translations }: LanguageSwitcherProps) {
+  const navigate = useNavigate()
+  const options = [
+    { value: '', label: sourceLang ? `${languageName(sourceLang)}（原文）` : '原文' },
+    ...translations.map((l) => ({ value: l, label: languageName(l) })),
+  ]
+  return (
+    <Select
+      size="small"
+      value={lang || ''}
+      options={options}
+      suffixIcon={<TranslationOutlined />}
+      popupMatchSelectWidth={false}
+      onChange={(v) => navigate(v ? `/s/${shareId}/${v}` : `/s/${shareId}`)}
+    />
+  )
+}
+
+export default LanguageSwitcher
@@ -0,0 +1,83 @@
+import { Typography } from 'antd'
+import { useEffect, useRef, useState } from 'react'
+import { MediaTranscript as Transcript } from '../api/share'
+
+const { Text } = Typography
+
+interface MediaTranscriptProps {
+  kind: 'audio' | 'video'
+  src: string
+  transcript: Transcript
+  mediaProps?: Record<string, unknown>
+}
+
+// 秒数格式化为 m:ss 或 h:mm:ss
+const formatTime = (sec: number) => {
+  const s = Math.floor(sec)
+  const h = Math.floor(s / 3600)
+  const mm = String(Math.floor(s / 60) % 60)
+  const ss = String(s % 60).padStart(2, '0')
+  return h > 0 ? `${h}:${mm.padStart(2, '0')}:${ss}` : `${mm}:${ss}`
+}
+
+// 带文字稿的音视频：播放时高亮当前段落并自动滚动，点击段落跳转到对应时间
+function MediaTranscript({ kind, src, transcript, mediaProps }: MediaTranscriptProps) {
+  const mediaRef = useRef<HTMLVideoElement & HTMLAudioElement>(null)
+  const listRef = useRef<HTMLOListElement>(null)
+  const [active, setActive] = useState(-1)
+  const cues = transcript.cues ?? []
+
+  const handleTimeUpdate = () => {
+    const t = mediaRef.current?.currentTime ?? 0
+    setActive(cues.findIndex(c => t >= c.start && t < c.end))
+  }
+
+  // 仅滚动文字稿列表本身，不带动整个页面
+  useEffect(() => {
+    const list = listRef.current
+    const item = active >= 0 ? list?.children[active] as HTMLElement | undefined : undefined
+    if (!list || !item) return
+    if (item.offsetTop < list.scrollTop || item.offsetTop + item.offsetHeight > list.scrollTop + list.clientHeight) {
+      list.scrollTo({ top: item.offsetTop - list.clientHeight / 3, behavior: 'smooth' })
+    }
+  }, [active])
+
+  const seek = (start: number) => {
+    const media = mediaRef.current
+    if (!media) return
+    media.currentTime = start
+    media.play().catch(() => undefined)
+  }
+
+  const Media = kind
+  return (
+    <div className={`media-transcript media-transcript-${kind}`}>
+      <Media {...mediaProps} ref={mediaRef} src={src} controls onTimeUpdate={handleTimeUpdate}>
+        {kind === 'video' && transcript.vttUrl && (
+          <track kind="subtitles" src={transcript.vttUrl} srcLang={transcript.language || undefined} label="文字稿" />
+        )}
+      </Media>
+      {cues.length > 0 ? (
+        <ol ref={listRef} className="media-transcript-cues" lang={transcript.language || undefined}>
+          {cues.map((cue, i) => (
+            <li
+              key={i}
+              className={i === active ? 'active' : undefined}
+              onClick={() => seek(cue.start)}
+            >
+              <Text type="secondary" className="media-transcript-time">{formatTime(cue.start)}</Text>
+              <span className="media-transcript-text">{cue.text}</span>
+            </li>
+          ))}
+        </ol>
+      ) : transcript.text && (
+        <details className="media-transcript-plain" lang={transcript.language || undefined}>
+          <summary>文字稿</summary>
+          <div>{transcript.text}</div>
+        </details>
+      )}
+    </div>
+  )
+}
+
+export default MediaTranscript
@@ -0,0 +1,95 @@
+import { Button, Segmented, Space } from 'antd'
+import { useState } from 'react'
+import { MindmapNode, ShareMindmap } from '../api/share'
+
+const palette = ['#1677ff', '#fa8c16', '#52c41a', '#eb2f96', '#722ed1', '#13c2c2', '#faad14', '#f5222d']
+
+interface BranchProps {
+  node: MindmapNode
+  path: string
+  depth: number
+  color: string
+  collapsed: Set<string>
+  onToggle: (path: string) => void
+}
+
+// 节点及其子树：有子节点时点击节点折叠/展开
+function Branch({ node, path, depth, color, collapsed, onToggle }: BranchProps) {
+  const children = node.children ?? []
+  const folded = collapsed.has(path)
+  return (
+    <div className="mindmap-branch">
+      <span
+        className={`mindmap-node mindmap-depth-${Math.min(depth, 2)}${children.length ? ' has-children' : ''}`}
+        style={{ borderColor: depth === 0 ? undefined : color }}
+        onClick={() => children.length && onToggle(path)}
+        title={children.length ? (folded ? '展开' : '折叠') : undefined}
+      >
+        {node.text}
+        {folded && <span className="mindmap-folded" style={{ background: color }}>{children.length}</span>}
+      </span>
+      {children.length > 0 && !folded && (
+        <div className="mindmap-children">
+          {children.map((child, i) => {
+            const c = depth === 0 ? palette[i % palette.length] : color
+            return (
+              <div key={i} className="mindmap-child" style={{ ['--branch-color' as string]: c }}>
+                <Branch node={child} path={`${path}.${i}`} depth={depth + 1} color={c} collapsed={collapsed} onToggle={onToggle} />
+              </div>
+            )
+          })}
+        </div>
+      )}
+    </div>
+  )
+}
+
+// 收集第 depth 层及更深的非叶子节点路径，用于“收起到一级”
+const collectPaths = (node: MindmapNode, path: string, depth: number, from: number, out: string[]) => {
+  if (node.children?.length && depth >= from) out.push(path)
+  node.children?.forEach((c, i) => collectPaths(c, `${path}.${i}`, depth + 1, from, out))
+  return out
+}
+
+// 思维导图：交互视图可折叠节点，图片视图使用服务端渲染的 SVG
+function MindMap({ data }: { data: ShareMindmap }) {
+  const [view, setView] = useState<'tree' | 'svg'>('tree')
+  const [collapsed, setCollapsed] = useState<Set<string>>(new Set())
+
+  if (!data.root) return null
+  const root = data.root
+
+  const toggle = (path: string) => {
+    const next = new Set(collapsed)
+    if (next.has(path)) next.delete(path)
+    else next.add(path)
+    setCollapsed(next)
+  }
+
+  return (
+    <div className="mindmap">
+      <Space className="mindmap-toolbar" wrap>
+        <Segmented
+          size="small"
+          value={view}
+          onChange={(v) => setView(v as 'tree' | 'svg')}
+          options={[{ label: '交互', value: 'tree' }, { label: '图片', value: 'svg' }]}
+        />
+        {view === 'tree' && (
+          <>
+            <Button size="small" onClick={() => setCollapsed(new Set())}>全部展开</Button>
+            <Button size="small" onClick={() => setCollapsed(new Set(collectPaths(root, '0', 0, 1, [])))}>收起到一级</Button>
+          </>
+        )}
+        {data.svgUrl && <a href={data.svgUrl} download={`mindmap-${data.index}.svg`}>下载 SVG</a>}
+      </Space>
+      <div className="mindmap-canvas">
+        {view === 'tree'
+          ? <Branch node={root} path="0" depth={0} color="#1f1f1f" collapsed={collapsed} onToggle={toggle} />
+          : <img src={data.svgUrl} alt={root.text} />}
+      </div>
+    </div>
+  )
+}
+
+export default MindMap
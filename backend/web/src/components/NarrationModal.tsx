@@ -0,0 +1,120 @@
+import { Alert, Button, message, Modal, Popconfirm, Space, Spin, Tag, Typography } from 'antd'
+import { useEffect, useRef, useState } from 'react'
+import { createNarration, deleteNarration, getNarration, type ShareNarration } from '../api/share'
+
+const { Text } = Typography
+
+interface NarrationModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+}
+
+const statusLabels: Record<ShareNarration['status'], { text: string; color: string }> = {
+  pending: { text: '生成中', color: 'processing' },
+  ok: { text: '已生成', color: 'green' },
+  failed: { text: '生成失败', color: 'red' },
+}
+
+// 分享朗读音频：调用语音合成生成全文朗读，生成后阅读页显示播放器
+function NarrationModal({ shareId, docTitle, onClose }: NarrationModalProps) {
+  const [enabled, setEnabled] = useState(true)
+  const [narration, setNarration] = useState<ShareNarration | null>(null)
+  const [url, setUrl] = useState<string>()
+  const [loading, setLoading] = useState(false)
+  const [working, setWorking] = useState(false)
+  const timer = useRef<number>()
+
+  const load = async (id: string) => {
+    try {
+      const res = await getNarration(id)
+      if (res.code === 0) {
+        setEnabled(res.data.enabled)
+        setNarration(res.data.narration)
+        setUrl(res.data.url)
+        // 生成中时轮询状态
+        window.clearTimeout(timer.current)
+        if (res.data.narration?.status === 'pending') {
+          timer.current = window.setTimeout(() => load(id), 3000)
+        }
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) {
+      setLoading(true)
+      load(shareId).finally(() => setLoading(false))
+    } else {
+      setNarration(null)
+      setUrl(undefined)
+    }
+    return () => window.clearTimeout(timer.current)
+  }, [shareId])
+
+  const run = async (action: () => Promise<{ code: number; msg: string }>, ok: string) => {
+    if (!shareId) return
+    setWorking(true)
+    try {
+      const res = await action()
+      if (res.code === 0) {
+        message.success(ok)
+        load(shareId)
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    } finally {
+      setWorking(false)
+    }
+  }
+
+  const stale = narration?.status === 'ok' && !url
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`朗读音频${docTitle ? ` · ${docTitle}` : ''}`}
+      width={560}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Spin spinning={loading}>
+        {!enabled ? (
+          <Alert type="info" showIcon message="服务器未配置语音合成，暂不能生成朗读音频" />
+        ) : (
+          <Space direction="vertical" style={{ width: '100%' }} size="middle">
+            <Space>
+              <Text>状态：</Text>
+              {narration ? <Tag color={statusLabels[narration.status].color}>{statusLabels[narration.status].text}</Tag> : <Tag>未生成</Tag>}
+              {narration && <Text type="secondary">{narration.chars} 字</Text>}
+            </Space>
+            {narration?.status === 'failed' && narration.error && <Alert type="error" showIcon message={narration.error} />}
+            {stale && <Alert type="warning" showIcon message="正文已更新，朗读音频与当前内容不一致，阅读页暂不显示播放器" />}
+            {url && <audio controls preload="none" src={url} style={{ width: '100%' }} />}
+            <Space>
+              {(!narration || narration.status === 'failed' || stale) && (
+                <Button type="primary" loading={working} onClick={() => run(() => createNarration(shareId!), '已开始生成')}>
+                  {narration ? '重新生成' : '生成朗读音频'}
+                </Button>
+              )}
+              {narration && (
+                <Popconfirm title="删除朗读音频？" onConfirm={() => run(() => deleteNarration(shareId!), '已删除')}>
+                  <Button danger disabled={working || narration.status === 'pending'}>删除</Button>
+                </Popconfirm>
+              )}
+            </Space>
+            <Text type="secondary">音频作为分享资源保存并计入存储空间，正文更新后需重新生成。</Text>
+          </Space>
+        )}
+      </Spin>
+    </Modal>
+  )
+}
+
+export default NarrationModal
@@ -0,0 +1,80 @@
+import { MailOutlined } from '@ant-design/icons'
+import { Alert, Button, Card, Checkbox, Form, InputNumber, message, Space, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { getNotificationSettings, saveNotificationSettings, type NotificationSettings, type NotificationSettingsInput } from '../api/share'
+
+const { Paragraph } = Typography
+
+// 分享者邮件通知：首次浏览、浏览次数里程碑、即将到期与收到评论
+function NotificationsCard() {
+  const [settings, setSettings] = useState<NotificationSettings | null>(null)
+  const [saving, setSaving] = useState(false)
+  const [form] = Form.useForm<NotificationSettingsInput>()
+
+  useEffect(() => {
+    getNotificationSettings()
+      .then(res => {
+        if (res.code === 0) {
+          setSettings(res.data)
+          form.setFieldsValue(res.data)
+        }
+      })
+      .catch((e: any) => message.error(e.response?.data?.msg || e.message || '加载失败'))
+  }, [form])
+
+  const save = async (values: NotificationSettingsInput) => {
+    setSaving(true)
+    try {
+      const res = await saveNotificationSettings({
+        ...values,
+        viewsEvery: values.viewsEvery || 0,
+        expiringDays: values.expiringDays || 0,
+      })
+      if (res.code === 0) {
+        setSettings(res.data)
+        message.success('已保存')
+      } else {
+        message.error(res.msg || '保存失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Card
+      title={<Space><MailOutlined /><span>邮件通知</span></Space>}
+      bordered={false}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      <Paragraph type="secondary">分享被浏览、收到评论或即将到期时发送邮件到账号邮箱，自己浏览不计入。</Paragraph>
+      {settings && !settings.emailEnabled && (
+        <Alert type="warning" showIcon style={{ marginBottom: 16 }} message="站点未配置邮件服务，暂时无法发送通知" />
+      )}
+      {settings?.emailEnabled && !settings.emailVerified && (
+        <Alert type="warning" showIcon style={{ marginBottom: 16 }} message="账号邮箱尚未验证，验证后才会收到通知" />
+      )}
+      <Form form={form} layout="vertical" onFinish={save} disabled={!settings}>
+        <Form.Item name="firstView" valuePropName="checked" style={{ marginBottom: 8 }}>
+          <Checkbox>分享第一次被浏览</Checkbox>
+        </Form.Item>
+        <Form.Item name="comments" valuePropName="checked">
+          <Checkbox>收到评论（含待审核）</Checkbox>
+        </Form.Item>
+        <Space size="large" wrap>
+          <Form.Item name="viewsEvery" label="浏览次数每达到" extra="的倍数时通知，留空关闭">
+            <InputNumber min={0} max={1000000} placeholder="如 100" style={{ width: 160 }} />
+          </Form.Item>
+          <Form.Item name="expiringDays" label="到期前提醒天数" extra="留空关闭">
+            <InputNumber min={0} max={30} placeholder="如 3" style={{ width: 160 }} />
+          </Form.Item>
+        </Space>
+        <Button type="primary" htmlType="submit" loading={saving}>保存</Button>
+      </Form>
+    </Card>
+  )
+}
+
+export default NotificationsCard
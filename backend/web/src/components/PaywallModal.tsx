@@ -0,0 +1,143 @@
+import { Alert, Button, Form, Input, message, Modal, Segmented, Space, Statistic, Switch, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { listSharePayments, setSharePaywall, type SharePayment } from '../api/share'
+
+const { Paragraph } = Typography
+
+interface PaywallModalProps {
+  shareId: string | null
+  docTitle?: string
+  paywall?: boolean
+  price?: string
+  checkoutUrl?: string
+  onClose: () => void
+  onChanged?: () => void
+}
+
+const statusTags: Record<SharePayment['status'], { color: string; label: string }> = {
+  pending: { color: 'default', label: '待支付' },
+  paid: { color: 'green', label: '已支付' },
+  refunded: { color: 'red', label: '已退款' },
+}
+
+// 付费阅读：设置价格与支付页面地址，查看订单；支付平台回调确认付款后读者解锁全文
+function PaywallModal({ shareId, docTitle, paywall, price, checkoutUrl, onClose, onChanged }: PaywallModalProps) {
+  const [form] = Form.useForm<{ paywall: boolean; paywallPrice: string; paywallUrl: string }>()
+  const [saving, setSaving] = useState(false)
+  const [enabled, setEnabled] = useState(true)
+  const [summary, setSummary] = useState({ paid: 0, pending: 0, refunded: 0 })
+  const [items, setItems] = useState<SharePayment[]>([])
+  const [pending, setPending] = useState(false)
+  const [page, setPage] = useState(1)
+  const [total, setTotal] = useState(0)
+  const [loading, setLoading] = useState(false)
+
+  const load = async (id: string, p: number, onlyPending: boolean) => {
+    setLoading(true)
+    try {
+      const res = await listSharePayments(id, p, onlyPending)
+      if (res.code === 0) {
+        setEnabled(res.data.enabled)
+        setSummary(res.data.summary)
+        setItems(res.data.items || [])
+        setTotal(res.data.total)
+        setPage(res.data.page)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (!shareId) return
+    form.setFieldsValue({ paywall: !!paywall, paywallPrice: price || '', paywallUrl: checkoutUrl || '' })
+    setPending(false)
+    load(shareId, 1, false)
+  }, [shareId])
+
+  const save = async (values: { paywall: boolean; paywallPrice: string; paywallUrl: string }) => {
+    if (!shareId) return
+    setSaving(true)
+    try {
+      const res = await setSharePaywall(shareId, values)
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      message.success('已保存')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`付费阅读${docTitle ? ` · ${docTitle}` : ''}`}
+      width={760}
+      okText="保存"
+      confirmLoading={saving}
+      onOk={() => form.submit()}
+      onCancel={onClose}
+    >
+      {!enabled && (
+        <Alert style={{ marginBottom: 16 }} type="warning" showIcon message="服务器未配置支付回调密钥（PAYMENT_WEBHOOK_SECRET），暂时不能开启付费阅读" />
+      )}
+      <Paragraph type="secondary">
+        读者点击购买后创建订单并打开支付页面，地址中的 {'{order}'} 替换为订单号；支付平台回调确认付款后读者即可阅读全文，退款后解锁失效。
+      </Paragraph>
+      <Form form={form} layout="vertical" onFinish={save}>
+        <Form.Item name="paywall" label="开启付费阅读" valuePropName="checked">
+          <Switch disabled={!enabled && !paywall} />
+        </Form.Item>
+        <Form.Item name="paywallPrice" label="价格" extra="显示给读者的价格文字，如「¥9.9」">
+          <Input maxLength={32} />
+        </Form.Item>
+        <Form.Item name="paywallUrl" label="支付页面地址" rules={[{ pattern: /^https?:\/\/.*\{order\}/, message: '须为 http(s) 链接并包含 {order}' }]}>
+          <Input placeholder="https://pay.example.com/checkout?order={order}" />
+        </Form.Item>
+      </Form>
+
+      <Space size={48} style={{ margin: '8px 0 16px' }}>
+        <Statistic title="已支付" value={summary.paid} />
+        <Statistic title="待支付" value={summary.pending} />
+        <Statistic title="已退款" value={summary.refunded} />
+      </Space>
+      <Segmented
+        style={{ marginBottom: 12, display: 'flex', width: 'fit-content' }}
+        value={pending ? 'pending' : 'done'}
+        options={[{ value: 'done', label: '已支付 / 已退款' }, { value: 'pending', label: '待支付' }]}
+        onChange={v => {
+          setPending(v === 'pending')
+          if (shareId) load(shareId, 1, v === 'pending')
+        }}
+      />
+      <Table<SharePayment>
+        size="small"
+        rowKey="id"
+        loading={loading}
+        dataSource={items}
+        pagination={total > 20 ? { current: page, pageSize: 20, total, onChange: p => shareId && load(shareId, p, pending) } : false}
+        locale={{ emptyText: '暂无订单' }}
+        columns={[
+          { title: '订单号', dataIndex: 'id', render: (id: string) => <Typography.Text copyable>{id}</Typography.Text> },
+          { title: '状态', dataIndex: 'status', width: 90, render: (s: SharePayment['status']) => <Tag color={statusTags[s].color}>{statusTags[s].label}</Tag> },
+          { title: '金额', dataIndex: 'amount', width: 110 },
+          { title: '交易号', dataIndex: 'reference', ellipsis: true },
+          { title: '时间', key: 'time', width: 170, render: (p: SharePayment) => new Date(p.paidAt || p.createdAt).toLocaleString() },
+        ]}
+      />
+      <Button type="link" style={{ padding: 0 }} onClick={() => shareId && load(shareId, page, pending)}>刷新</Button>
+    </Modal>
+  )
+}
+
+export default PaywallModal
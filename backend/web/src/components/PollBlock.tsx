@@ -0,0 +1,64 @@
+import { CheckOutlined } from '@ant-design/icons'
+import { message, Progress, Typography } from 'antd'
+import { useState } from 'react'
+import { votePoll, type SharePoll } from '../api/share'
+
+const { Text } = Typography
+
+interface PollBlockProps {
+  shareId: string
+  poll: SharePoll
+  fingerprint: string
+  password?: string
+  onVoted: (poll: SharePoll) => void
+}
+
+// 正文中的投票块：点击选项投票，投票后显示各选项的票数与比例，可改投其他选项
+function PollBlock({ shareId, poll, fingerprint, password, onVoted }: PollBlockProps) {
+  const [voting, setVoting] = useState(false)
+
+  const vote = async (option: string) => {
+    if (voting || option === poll.voted) return
+    setVoting(true)
+    try {
+      const res = await votePoll(shareId, poll.key, { option, fingerprint }, password)
+      if (res.code === 0 && res.data) onVoted(res.data)
+      else message.error(res.msg || '投票失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '投票失败')
+    } finally {
+      setVoting(false)
+    }
+  }
+
+  const showResults = !!poll.voted
+  return (
+    <div className="share-poll">
+      {poll.question && <Text strong className="share-poll-question">{poll.question}</Text>}
+      {poll.options.map(opt => {
+        const percent = poll.total ? Math.round(opt.votes / poll.total * 100) : 0
+        const chosen = opt.text === poll.voted
+        return (
+          <button
+            key={opt.text}
+            type="button"
+            className={`share-poll-option${chosen ? ' chosen' : ''}`}
+            disabled={voting}
+            onClick={() => vote(opt.text)}
+          >
+            <span className="share-poll-label">
+              <span>{chosen && <CheckOutlined />} {opt.text}</span>
+              {showResults && <Text type="secondary">{opt.votes} 票 · {percent}%</Text>}
+            </span>
+            {showResults && <Progress percent={percent} showInfo={false} size="small" />}
+          </button>
+        )
+      })}
+      <Text type="secondary" className="share-poll-total">
+        {showResults ? `共 ${poll.total} 票，可点击其他选项改投` : '选择一个选项投票后查看结果'}
+      </Text>
+    </div>
+  )
+}
+
+export default PollBlock
@@ -0,0 +1,107 @@
+import { message, Modal, Progress, Space, Statistic, Switch, Table, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { getReadingStats, setReadingTracking, type ReadingFunnelStep, type ReadingStats } from '../api/share'
+
+const { Text } = Typography
+
+interface ReadingModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+  onChanged?: () => void
+}
+
+// 阅读统计：预计阅读时长，开启后按标题查看读者读到了哪里（阅读漏斗）
+function ReadingModal({ shareId, docTitle, onClose, onChanged }: ReadingModalProps) {
+  const [stats, setStats] = useState<ReadingStats | null>(null)
+  const [loading, setLoading] = useState(false)
+
+  const load = async (id: string) => {
+    setLoading(true)
+    try {
+      const res = await getReadingStats(id)
+      if (res.code === 0) {
+        setStats(res.data)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) load(shareId)
+    else setStats(null)
+  }, [shareId])
+
+  const toggle = async (checked: boolean) => {
+    if (!shareId) return
+    try {
+      const res = await setReadingTracking(shareId, checked)
+      if (res.code === 0) {
+        setStats(prev => (prev ? { ...prev, trackReading: checked } : prev))
+        message.success(checked ? '已开启阅读统计' : '已关闭阅读统计')
+        onChanged?.()
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    }
+  }
+
+  const reads = stats?.reads ?? 0
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`阅读统计${docTitle ? ` · ${docTitle}` : ''}`}
+      width={760}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Space direction="vertical" size={4} style={{ marginBottom: 16 }}>
+        <Space>
+          <Switch checked={!!stats?.trackReading} onChange={toggle} disabled={!stats} />
+          <Text>统计读者读到了哪个标题</Text>
+        </Space>
+        <Text type="secondary" style={{ fontSize: 12 }}>读者离开阅读页时匿名上报阅读时长、滚动深度与读到的最后一个标题，不记录读者身份</Text>
+      </Space>
+      <Space size={48} style={{ marginBottom: 16 }} wrap>
+        <Statistic title="预计阅读" value={stats?.readingMinutes ?? '-'} suffix={stats ? '分钟' : undefined} />
+        <Statistic title="字数" value={stats?.wordCount ?? '-'} />
+        <Statistic title="阅读数" value={reads} />
+        <Statistic title="平均时长" value={reads ? Math.round(stats!.avgSeconds) : '-'} suffix={reads ? '秒' : undefined} />
+        <Statistic title="读完比例" value={reads ? (stats!.completionRate * 100).toFixed(1) : '-'} suffix={reads ? '%' : undefined} />
+      </Space>
+      <Table<ReadingFunnelStep>
+        size="small"
+        rowKey="id"
+        loading={loading}
+        dataSource={stats?.funnel ?? []}
+        pagination={false}
+        scroll={{ y: 360 }}
+        locale={{ emptyText: '正文中没有标题' }}
+        columns={[
+          {
+            title: '标题',
+            dataIndex: 'text',
+            render: (t: string, step) => <span style={{ paddingLeft: (step.level - 1) * 12 }}>{t}</span>,
+          },
+          { title: '读到', dataIndex: 'reached', width: 80, align: 'right' },
+          {
+            title: '比例',
+            dataIndex: 'rate',
+            width: 180,
+            render: (rate: number) => <Progress percent={Math.round(rate * 100)} size="small" />,
+          },
+        ]}
+      />
+    </Modal>
+  )
+}
+
+export default ReadingModal
@@ -0,0 +1,27 @@
+import { useState, type ReactNode } from 'react'
+import { ShareRender } from '../api/share'
+
+interface RenderedBlockProps {
+  data: ShareRender
+  fallback: ReactNode
+}
+
+// 外部渲染插件输出的代码块图片，可切换查看源码；渲染失败时回退为代码块
+function RenderedBlock({ data, fallback }: RenderedBlockProps) {
+  const [failed, setFailed] = useState(false)
+  const [showSource, setShowSource] = useState(false)
+
+  if (failed) return <>{fallback}</>
+  return (
+    <div className="rendered-block">
+      {showSource
+        ? fallback
+        : <img src={data.url} alt={data.lang} loading="lazy" onError={() => setFailed(true)} />}
+      <button type="button" className="rendered-block-toggle" onClick={() => setShowSource(!showSource)}>
+        {showSource ? '查看渲染结果' : '查看源码'}
+      </button>
+    </div>
+  )
+}
+
+export default RenderedBlock
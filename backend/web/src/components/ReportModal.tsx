@@ -0,0 +1,59 @@
+import { Form, Input, message, Modal, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { reportShare } from '../api/share'
+
+const { Text } = Typography
+
+interface ReportModalProps {
+  open: boolean
+  shareId: string
+  password?: string
+  onClose: () => void
+}
+
+// 举报分享：读者填写原因与可选的联系方式，提交后进入管理员的审核队列
+function ReportModal({ open, shareId, password, onClose }: ReportModalProps) {
+  const [form] = Form.useForm()
+  const [submitting, setSubmitting] = useState(false)
+
+  useEffect(() => {
+    if (open) form.resetFields()
+  }, [open])
+
+  const submit = async () => {
+    const values = await form.validateFields()
+    setSubmitting(true)
+    try {
+      const res = await reportShare(shareId, values, password)
+      if (res.code === 0) {
+        message.success('举报已提交，管理员会尽快处理')
+        onClose()
+      } else {
+        message.error(res.msg || '提交失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '提交失败')
+    } finally {
+      setSubmitting(false)
+    }
+  }
+
+  return (
+    <Modal title="举报此分享" open={open} onOk={submit} onCancel={onClose} okText="提交" confirmLoading={submitting} destroyOnClose>
+      <Text type="secondary">如果该内容涉及违法、侵权、垃圾信息等问题，请说明原因，管理员审核后会处理。</Text>
+      <Form form={form} layout="vertical" style={{ marginTop: 16 }}>
+        <Form.Item name="reason" label="举报原因" rules={[{ required: true, whitespace: true, message: '请填写举报原因' }, { max: 2000 }]}>
+          <Input.TextArea autoSize={{ minRows: 3, maxRows: 8 }} maxLength={2000} showCount />
+        </Form.Item>
+        <Form.Item name="contact" label="联系方式（可选）" rules={[{ max: 255 }]}>
+          <Input placeholder="邮箱等，便于管理员联系你" />
+        </Form.Item>
+        <Form.Item name="website" hidden>
+          <Input tabIndex={-1} autoComplete="off" />
+        </Form.Item>
+      </Form>
+    </Modal>
+  )
+}
+
+export default ReportModal
@@ -0,0 +1,146 @@
+import { FlagOutlined, ReloadOutlined } from '@ant-design/icons'
+import { Button, Card, message, Popconfirm, Segmented, Space, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { listReports, reinstateShare, resolveReport, type ShareReport } from '../api/admin'
+
+const { Text } = Typography
+
+const statusOptions = [
+  { label: '待处理', value: 'open' },
+  { label: '已驳回', value: 'dismissed' },
+  { label: '已处理', value: 'actioned' },
+  { label: '全部', value: 'all' },
+]
+
+const actionLabels: Record<string, string> = { dismiss: '驳回', disable: '停用分享', ban: '封禁所有者' }
+
+// 举报审核（仅管理员）：查看读者举报，驳回、停用被举报的分享或封禁分享所有者
+function ReportsCard() {
+  const [status, setStatus] = useState('open')
+  const [items, setItems] = useState<ShareReport[]>([])
+  const [total, setTotal] = useState(0)
+  const [page, setPage] = useState(1)
+  const [loading, setLoading] = useState(false)
+
+  const load = async (p = page) => {
+    setLoading(true)
+    try {
+      const res = await listReports({ status, page: p, size: 20 })
+      if (res.code === 0 && res.data) {
+        setItems(res.data.items)
+        setTotal(res.data.total)
+        setPage(p)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => { load(1) }, [status])
+
+  const resolve = async (r: ShareReport, action: 'dismiss' | 'disable' | 'ban') => {
+    try {
+      const res = await resolveReport(r.id, action)
+      if (res.code === 0) {
+        message.success(action === 'dismiss' ? '已驳回' : `已停用 ${res.data?.disabledShares?.length || 0} 个分享`)
+        load()
+      } else {
+        message.error(res.msg || '处理失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '处理失败')
+    }
+  }
+
+  const reinstate = async (r: ShareReport) => {
+    try {
+      const res = await reinstateShare(r.shareId)
+      if (res.code === 0) {
+        message.success('已解除停用，所有者可重新上线')
+        load()
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    }
+  }
+
+  const columns = [
+    {
+      title: '分享',
+      key: 'share',
+      render: (_: any, r: ShareReport) => (
+        <Space direction="vertical" size={0}>
+          <a href={`/s/${r.shareId}`} target="_blank" rel="noreferrer">{r.docTitle || r.shareId}</a>
+          <Text type="secondary" style={{ fontSize: 12 }}>
+            {r.owner}{!r.ownerActive && <Tag color="red" style={{ marginLeft: 6 }}>已封禁</Tag>}
+            {r.moderatedAt && <Tag color="orange" style={{ marginLeft: 6 }}>管理员停用</Tag>}
+          </Text>
+        </Space>
+      ),
+    },
+    {
+      title: '举报原因',
+      key: 'reason',
+      render: (_: any, r: ShareReport) => (
+        <Space direction="vertical" size={0}>
+          <Text style={{ whiteSpace: 'pre-wrap' }}>{r.reason}</Text>
+          {r.contact && <Text type="secondary" style={{ fontSize: 12 }}>联系方式：{r.contact}</Text>}
+        </Space>
+      ),
+    },
+    { title: '时间', dataIndex: 'createdAt', key: 'createdAt', width: 170, render: (v: string) => new Date(v).toLocaleString() },
+    {
+      title: '操作',
+      key: 'actions',
+      width: 230,
+      render: (_: any, r: ShareReport) => r.status === 'open' ? (
+        <Space wrap>
+          <Button size="small" onClick={() => resolve(r, 'dismiss')}>驳回</Button>
+          <Popconfirm title="停用该分享？所有者在解除前不能重新上线" onConfirm={() => resolve(r, 'disable')}>
+            <Button size="small" danger>停用分享</Button>
+          </Popconfirm>
+          <Popconfirm title="封禁所有者？将停用其账号与全部分享并注销所有登录" onConfirm={() => resolve(r, 'ban')}>
+            <Button size="small" danger type="primary">封禁</Button>
+          </Popconfirm>
+        </Space>
+      ) : (
+        <Space wrap>
+          <Tag>{actionLabels[r.action || ''] || r.status}</Tag>
+          {r.moderatedAt && (
+            <Popconfirm title="解除管理员停用？" onConfirm={() => reinstate(r)}>
+              <Button size="small">解除停用</Button>
+            </Popconfirm>
+          )}
+        </Space>
+      ),
+    },
+  ]
+
+  return (
+    <Card
+      title={<Space><FlagOutlined /><span>举报审核</span></Space>}
+      extra={<Button icon={<ReloadOutlined />} onClick={() => load()} loading={loading}>刷新</Button>}
+      bordered={false}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      <Segmented options={statusOptions} value={status} onChange={v => setStatus(v as string)} style={{ marginBottom: 12 }} />
+      <Table
+        rowKey="id"
+        size="small"
+        loading={loading}
+        columns={columns}
+        dataSource={items}
+        pagination={{ current: page, pageSize: 20, total, hideOnSinglePage: true, onChange: p => load(p) }}
+        scroll={{ x: true }}
+      />
+    </Card>
+  )
+}
+
+export default ReportsCard
@@ -0,0 +1,152 @@
+import { Button, message, Modal, Popconfirm, Space, Table, Tag, Typography } from 'antd'
+import 'github-markdown-css/github-markdown-light.css'
+import { useEffect, useState } from 'react'
+import ReactMarkdown from 'react-markdown'
+import remarkGfm from 'remark-gfm'
+import { getRevision, listRevisions, rollbackRevision, type ShareRevision } from '../api/share'
+
+const { Text } = Typography
+
+interface RevisionsModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+  onRolledBack?: () => void
+}
+
+// 分享历史版本：查看任意版本内容，并回滚到该版本（回滚本身也会记录为新版本）
+function RevisionsModal({ shareId, docTitle, onClose, onRolledBack }: RevisionsModalProps) {
+  const [items, setItems] = useState<ShareRevision[]>([])
+  const [current, setCurrent] = useState(0)
+  const [loading, setLoading] = useState(false)
+  const [viewing, setViewing] = useState<ShareRevision | null>(null)
+
+  const load = async (id: string) => {
+    setLoading(true)
+    try {
+      const res = await listRevisions(id)
+      if (res.code === 0) {
+        setItems(res.data.items || [])
+        setCurrent(res.data.current)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) load(shareId)
+    else setItems([])
+  }, [shareId])
+
+  const view = async (rev: ShareRevision) => {
+    if (!shareId) return
+    try {
+      const res = await getRevision(shareId, rev.id)
+      if (res.code === 0 && res.data) setViewing(res.data)
+      else message.error(res.msg || '加载失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    }
+  }
+
+  const rollback = async (rev: ShareRevision) => {
+    if (!shareId) return
+    try {
+      const res = await rollbackRevision(shareId, rev.id)
+      if (res.code === 0) {
+        message.success(`已回滚到版本 ${rev.version}`)
+        setViewing(null)
+        load(shareId)
+        onRolledBack?.()
+      } else {
+        message.error(res.msg || '回滚失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '回滚失败')
+    }
+  }
+
+  const columns = [
+    {
+      title: '版本',
+      key: 'version',
+      width: 110,
+      render: (r: ShareRevision) => (
+        <Space size={4}>
+          <Text strong>v{r.version}</Text>
+          {r.version === current && <Tag color="green">当前</Tag>}
+        </Space>
+      ),
+    },
+    { title: '标题', dataIndex: 'docTitle', key: 'docTitle', ellipsis: true },
+    {
+      title: '来源',
+      key: 'source',
+      width: 120,
+      render: (r: ShareRevision) => r.source === 'rollback'
+        ? <Tag color="orange">回滚自 v{r.restoredFrom}</Tag>
+        : <Tag>发布</Tag>,
+    },
+    { title: '大小', dataIndex: 'size', key: 'size', width: 90, render: (n: number) => `${n} 字节` },
+    { title: '时间', dataIndex: 'createdAt', key: 'createdAt', width: 170, render: (t: string) => new Date(t).toLocaleString() },
+    {
+      title: '操作',
+      key: 'action',
+      width: 130,
+      render: (r: ShareRevision) => (
+        <Space size="small">
+          <Button type="link" size="small" onClick={() => view(r)}>查看</Button>
+          {r.version !== current && (
+            <Popconfirm title={`将分享内容恢复为版本 ${r.version}？`} onConfirm={() => rollback(r)}>
+              <Button type="link" size="small">回滚</Button>
+            </Popconfirm>
+          )}
+        </Space>
+      ),
+    },
+  ]
+
+  return (
+    <>
+      <Modal
+        open={!!shareId}
+        title={`历史版本${docTitle ? ` · ${docTitle}` : ''}`}
+        width={860}
+        footer={null}
+        onCancel={onClose}
+      >
+        <Table
+          size="small"
+          rowKey="id"
+          loading={loading}
+          dataSource={items}
+          columns={columns}
+          pagination={items.length > 10 ? { pageSize: 10 } : false}
+          locale={{ emptyText: '暂无历史版本' }}
+        />
+      </Modal>
+      <Modal
+        open={!!viewing}
+        title={viewing ? `v${viewing.version} · ${viewing.docTitle}` : ''}
+        width={860}
+        onCancel={() => setViewing(null)}
+        footer={viewing && viewing.version !== current ? (
+          <Popconfirm title={`将分享内容恢复为版本 ${viewing.version}？`} onConfirm={() => rollback(viewing)}>
+            <Button type="primary">回滚到此版本</Button>
+          </Popconfirm>
+        ) : null}
+      >
+        <div className="markdown-body" style={{ maxHeight: '65vh', overflow: 'auto' }}>
+          <ReactMarkdown remarkPlugins={[remarkGfm]}>{viewing?.content || ''}</ReactMarkdown>
+        </div>
+      </Modal>
+    </>
+  )
+}
+
+export default RevisionsModal
@@ -0,0 +1,78 @@
+import { ConsoleSqlOutlined } from '@ant-design/icons'
+import { Alert, Button, Card, Input, Space, Table, Typography } from 'antd'
+import { useState } from 'react'
+import { runSQLQuery, type SQLQueryResult } from '../api/admin'
+
+const { Text } = Typography
+
+// 数据库查询控制台（仅管理员）：执行只读 SQL 排查数据问题，服务器需开启 database.query_console
+function SQLConsoleCard() {
+  const [query, setQuery] = useState('SELECT id, username, created_at FROM users LIMIT 20')
+  const [result, setResult] = useState<SQLQueryResult | null>(null)
+  const [error, setError] = useState('')
+  const [running, setRunning] = useState(false)
+
+  const run = async () => {
+    if (!query.trim()) return
+    setRunning(true)
+    setError('')
+    try {
+      const res = await runSQLQuery(query)
+      if (res.code === 0 && res.data) {
+        setResult(res.data)
+      } else {
+        setError(res.msg || '查询失败')
+      }
+    } catch (e: any) {
+      setError(e.response?.data?.msg || e.message || '查询失败')
+    } finally {
+      setRunning(false)
+    }
+  }
+
+  const columns = (result?.columns || []).map((name, i) => ({
+    title: name,
+    key: String(i),
+    render: (_: any, row: any[]) => row[i] === null ? <Text type="secondary">NULL</Text> : String(row[i]),
+  }))
+
+  return (
+    <Card
+      title={<Space><ConsoleSqlOutlined /><span>数据库查询</span></Space>}
+      bordered={false}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      <Input.TextArea
+        value={query}
+        onChange={e => setQuery(e.target.value)}
+        onKeyDown={e => { if (e.key === 'Enter' && (e.ctrlKey || e.metaKey)) run() }}
+        autoSize={{ minRows: 3, maxRows: 10 }}
+        style={{ fontFamily: 'monospace' }}
+      />
+      <Space style={{ margin: '12px 0' }}>
+        <Button type="primary" loading={running} onClick={run}>执行（Ctrl+Enter）</Button>
+        <Text type="secondary">只允许单条 SELECT / WITH / VALUES / EXPLAIN 语句，每次查询都会记录审计日志</Text>
+      </Space>
+      {error && <Alert type="error" showIcon message={error} style={{ marginBottom: 12 }} />}
+      {result && !error && (
+        <>
+          <Text type="secondary">
+            {result.rows.length} 行，耗时 {result.elapsedMs} ms
+            {result.truncated && '，结果超过行数上限，只显示前面的部分'}
+          </Text>
+          <Table
+            rowKey={(_, i) => String(i)}
+            size="small"
+            columns={columns}
+            dataSource={result.rows}
+            pagination={{ pageSize: 20, hideOnSinglePage: true }}
+            scroll={{ x: true }}
+            style={{ marginTop: 8 }}
+          />
+        </>
+      )}
+    </Card>
+  )
+}
+
+export default SQLConsoleCard
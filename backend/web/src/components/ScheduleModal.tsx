@@ -0,0 +1,117 @@
+import { DeleteOutlined, PlusOutlined } from '@ant-design/icons'
+import { Button, Input, message, Modal, Select, Space, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { setShareSchedule, type AccessSchedule } from '../api/share'
+
+const { Text } = Typography
+
+interface ScheduleModalProps {
+  shareId: string | null
+  docTitle?: string
+  schedule?: AccessSchedule | null
+  onClose: () => void
+  onChanged?: () => void
+}
+
+type Range = { from?: string; until?: string }
+type Window = { start: string; end: string }
+
+const browserTimezone = () => Intl.DateTimeFormat().resolvedOptions().timeZone || 'UTC'
+
+const timezoneOptions = (() => {
+  const zones: string[] = (Intl as any).supportedValuesOf?.('timeZone') ?? [browserTimezone()]
+  return zones.map(z => ({ value: z, label: z }))
+})()
+
+// 开放时间设置：读者只能在日期范围与每日时段内打开分享，其余时间看到倒计时页；时间按所选时区解释
+function ScheduleModal({ shareId, docTitle, schedule, onClose, onChanged }: ScheduleModalProps) {
+  const [timezone, setTimezone] = useState(browserTimezone())
+  const [ranges, setRanges] = useState<Range[]>([])
+  const [daily, setDaily] = useState<Window[]>([])
+  const [saving, setSaving] = useState(false)
+
+  useEffect(() => {
+    if (!shareId) return
+    setTimezone(schedule?.timezone || browserTimezone())
+    setRanges(schedule?.ranges || [])
+    setDaily(schedule?.daily || [])
+  }, [shareId, schedule])
+
+  const save = async (next: AccessSchedule) => {
+    if (!shareId) return
+    setSaving(true)
+    try {
+      const res = await setShareSchedule(shareId, next)
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      message.success('已保存')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const updateRange = (i: number, patch: Range) => setRanges(ranges.map((r, j) => (j === i ? { ...r, ...patch } : r)))
+  const updateWindow = (i: number, patch: Partial<Window>) => setDaily(daily.map((w, j) => (j === i ? { ...w, ...patch } : w)))
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`开放时间${docTitle ? ` · ${docTitle}` : ''}`}
+      okText="保存"
+      confirmLoading={saving}
+      onOk={() => save({
+        timezone,
+        ranges: ranges.map(r => ({ from: r.from || undefined, until: r.until || undefined })).filter(r => r.from || r.until),
+        daily: daily.filter(w => w.start && w.end),
+      })}
+      onCancel={onClose}
+      footer={(_, { OkBtn, CancelBtn }) => (
+        <>
+          <Button danger disabled={saving} onClick={() => save({ timezone })}>不限时间</Button>
+          <CancelBtn />
+          <OkBtn />
+        </>
+      )}
+    >
+      <Space direction="vertical" style={{ width: '100%' }}>
+        <Text strong>时区</Text>
+        <Select showSearch style={{ width: '100%' }} value={timezone} onChange={setTimezone} options={timezoneOptions} />
+
+        <Text strong>日期范围</Text>
+        {ranges.map((r, i) => (
+          <Space key={i}>
+            <Input type="datetime-local" value={r.from || ''} onChange={(e) => updateRange(i, { from: e.target.value })} />
+            <Text>至</Text>
+            <Input type="datetime-local" value={r.until || ''} onChange={(e) => updateRange(i, { until: e.target.value })} />
+            <Button type="text" icon={<DeleteOutlined />} onClick={() => setRanges(ranges.filter((_, j) => j !== i))} />
+          </Space>
+        ))}
+        <Button size="small" icon={<PlusOutlined />} onClick={() => setRanges([...ranges, {}])}>添加日期范围</Button>
+
+        <Text strong>每日时段</Text>
+        {daily.map((w, i) => (
+          <Space key={i}>
+            <Input type="time" value={w.start} onChange={(e) => updateWindow(i, { start: e.target.value })} />
+            <Text>至</Text>
+            <Input type="time" value={w.end} onChange={(e) => updateWindow(i, { end: e.target.value })} />
+            <Button type="text" icon={<DeleteOutlined />} onClick={() => setDaily(daily.filter((_, j) => j !== i))} />
+          </Space>
+        ))}
+        <Button size="small" icon={<PlusOutlined />} onClick={() => setDaily([...daily, { start: '09:00', end: '17:00' }])}>添加每日时段</Button>
+
+        <Text type="secondary">
+          日期范围与每日时段同时满足时读者才能打开，留空的一项不作限制；结束时刻早于开始时刻的时段跨越午夜。
+          设置了开放时间的分享不出现在站内搜索、订阅源、日历与 sitemap 中，引用块子分享沿用本分享的开放时间。
+        </Text>
+      </Space>
+    </Modal>
+  )
+}
+
+export default ScheduleModal
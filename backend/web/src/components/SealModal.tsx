@@ -0,0 +1,73 @@
+import { InputNumber, message, Modal, Space, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { sealShare } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface SealModalProps {
+  shareId: string | null
+  docTitle?: string
+  sealedUntil?: string
+  onClose: () => void
+  onChanged?: () => void
+}
+
+// 封存分享：记录封存时间与正文哈希，保留期满前不能修改正文、访问设置、停用或删除；已封存的只能延长保留期限
+function SealModal({ shareId, docTitle, sealedUntil, onClose, onChanged }: SealModalProps) {
+  const [days, setDays] = useState<number | null>(365)
+  const [saving, setSaving] = useState(false)
+
+  const remaining = sealedUntil ? Math.ceil((new Date(sealedUntil).getTime() - Date.now()) / 86400000) : 0
+
+  useEffect(() => {
+    if (shareId) setDays(remaining > 0 ? remaining + 30 : 365)
+  }, [shareId])
+
+  const save = async () => {
+    if (!shareId || !days) return
+    setSaving(true)
+    try {
+      const res = await sealShare(shareId, days)
+      if (res.code !== 0) {
+        message.error(res.msg || '封存失败')
+        return
+      }
+      message.success('已封存')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '封存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`${remaining > 0 ? '延长封存' : '封存分享'}${docTitle ? ` · ${docTitle}` : ''}`}
+      okText={remaining > 0 ? '延长' : '封存'}
+      okButtonProps={{ danger: remaining <= 0, disabled: !days || days <= remaining }}
+      confirmLoading={saving}
+      onOk={save}
+      onCancel={onClose}
+    >
+      <Paragraph>
+        封存后服务端记录封存时间与正文的 SHA-256，读者可在阅读页查看并下载源文校验。保留期满前不能修改正文、标题与访问设置，
+        不能重新发布、停用或删除，账号也不能注销；评论、批注等互动设置仍可调整。此操作无法撤销。
+      </Paragraph>
+      {remaining > 0 && (
+        <Paragraph>
+          <Text type="secondary">当前保留至 {new Date(sealedUntil!).toLocaleString('zh-CN')}，新的期限须晚于此时间。</Text>
+        </Paragraph>
+      )}
+      <Space>
+        <Text>保留</Text>
+        <InputNumber min={1} max={3650} value={days} onChange={setDays} />
+        <Text>天（从现在起算）</Text>
+      </Space>
+    </Modal>
+  )
+}
+
+export default SealModal
@@ -0,0 +1,105 @@
+import { Button, Empty, Input, List, message, Modal, Space, Spin, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { getRelatedShares, reindexShares, semanticSearch, type SemanticItem } from '../api/share'
+
+const { Text } = Typography
+
+interface SemanticSearchModalProps {
+  open: boolean
+  // 指定时展示与该分享相关的分享，否则为语义搜索
+  relatedTo?: { id: string; docTitle: string } | null
+  onClose: () => void
+}
+
+// 语义搜索与相关分享：按内容相似度在本人的分享中查找
+function SemanticSearchModal({ open, relatedTo, onClose }: SemanticSearchModalProps) {
+  const [items, setItems] = useState<SemanticItem[] | null>(null)
+  const [loading, setLoading] = useState(false)
+  const [reindexing, setReindexing] = useState(false)
+
+  const run = async (request: () => Promise<{ code: number; msg: string; data: { items: SemanticItem[] } }>) => {
+    setLoading(true)
+    try {
+      const res = await request()
+      if (res.code === 0) {
+        setItems(res.data.items || [])
+      } else {
+        message.error(res.msg || '查询失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '查询失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    setItems(null)
+    if (open && relatedTo) {
+      run(() => getRelatedShares(relatedTo.id))
+    }
+  }, [open, relatedTo?.id])
+
+  const handleReindex = async () => {
+    setReindexing(true)
+    try {
+      const res = await reindexShares()
+      if (res.code === 0) {
+        message.success(`已开始为 ${res.data.shares} 个分享建立索引`)
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    } finally {
+      setReindexing(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={open}
+      title={relatedTo ? `相关分享 · ${relatedTo.docTitle}` : '语义搜索'}
+      width={640}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Space direction="vertical" style={{ width: '100%' }} size="middle">
+        {!relatedTo && (
+          <Input.Search
+            placeholder="用一句话描述要找的内容"
+            enterButton="搜索"
+            maxLength={500}
+            loading={loading}
+            onSearch={(q) => q.trim() && run(() => semanticSearch(q.trim()))}
+          />
+        )}
+        <Spin spinning={loading}>
+          {items && items.length === 0 ? (
+            <Empty description="没有找到内容相近的分享" />
+          ) : (
+            items && (
+              <List
+                dataSource={items}
+                renderItem={(item) => (
+                  <List.Item extra={<Tag>{Math.round(item.score * 100)}%</Tag>}>
+                    <List.Item.Meta
+                      title={<a href={item.shareUrl} target="_blank" rel="noreferrer">{item.docTitle}</a>}
+                      description={item.summary || new Date(item.createdAt).toLocaleString()}
+                    />
+                  </List.Item>
+                )}
+              />
+            )
+          )}
+        </Spin>
+        <Space>
+          <Text type="secondary">发布时自动建立索引，开启前发布的分享需手动重建。</Text>
+          <Button size="small" loading={reindexing} onClick={handleReindex}>重建索引</Button>
+        </Space>
+      </Space>
+    </Modal>
+  )
+}
+
+export default SemanticSearchModal
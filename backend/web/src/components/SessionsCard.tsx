@@ -0,0 +1,120 @@
+import { DesktopOutlined } from '@ant-design/icons'
+import { Button, Card, message, Popconfirm, Space, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import api from '../api'
+
+const { Text } = Typography
+
+interface ApiResp<T = any> { code: number; msg: string; data: T }
+
+interface SessionItem {
+  id: string
+  method: string
+  userAgent: string
+  ip: string
+  lastSeenAt: string
+  createdAt: string
+  current: boolean
+}
+
+// 登录设备管理：查看活跃会话，单独或一键注销其他设备
+function SessionsCard() {
+  const [sessions, setSessions] = useState<SessionItem[]>([])
+  const [loading, setLoading] = useState(false)
+
+  const load = async () => {
+    setLoading(true)
+    try {
+      const res = await api.get('/api/user/sessions') as ApiResp<{ items: SessionItem[] }>
+      if (res.code === 0) setSessions(res.data.items || [])
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => { load() }, [])
+
+  const revoke = async (id: string) => {
+    try {
+      const res = await api.delete(`/api/user/sessions/${id}`) as ApiResp
+      if (res.code === 0) {
+        message.success('已注销该设备')
+        load()
+      } else {
+        message.error(res.msg || '注销失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '注销失败')
+    }
+  }
+
+  const revokeOthers = async () => {
+    try {
+      const res = await api.delete('/api/user/sessions') as ApiResp<{ revoked: number }>
+      if (res.code === 0) {
+        message.success(`已注销 ${res.data.revoked} 个其他设备`)
+        load()
+      } else {
+        message.error(res.msg || '注销失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '注销失败')
+    }
+  }
+
+  return (
+    <Card
+      title={<Space><DesktopOutlined /><span>登录设备</span></Space>}
+      bordered={false}
+      extra={
+        <Popconfirm title="注销除当前设备外的所有登录？" onConfirm={revokeOthers}>
+          <Button danger size="small" disabled={sessions.length <= 1}>注销其他设备</Button>
+        </Popconfirm>
+      }
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      <Table
+        rowKey="id"
+        size="small"
+        loading={loading}
+        dataSource={sessions}
+        pagination={false}
+        columns={[
+          {
+            title: '设备',
+            dataIndex: 'userAgent',
+            render: (ua: string, r: SessionItem) => (
+              <Space direction="vertical" size={0}>
+                <Space>
+                  <Text ellipsis style={{ maxWidth: 320 }}>{ua || '未知设备'}</Text>
+                  {r.current && <Tag color="blue">当前</Tag>}
+                </Space>
+                <Text type="secondary" style={{ fontSize: 12 }}>{r.method === 'password' ? '密码登录' : r.method}</Text>
+              </Space>
+            ),
+          },
+          { title: 'IP', dataIndex: 'ip', width: 140 },
+          {
+            title: '最近活跃',
+            dataIndex: 'lastSeenAt',
+            width: 180,
+            render: (v: string) => new Date(v).toLocaleString('zh-CN'),
+          },
+          {
+            title: '操作',
+            width: 90,
+            render: (_: any, r: SessionItem) => r.current ? null : (
+              <Popconfirm title="注销该设备的登录？" onConfirm={() => revoke(r.id)}>
+                <Button type="link" danger size="small">注销</Button>
+              </Popconfirm>
+            ),
+          },
+        ]}
+      />
+    </Card>
+  )
+}
+
+export default SessionsCard
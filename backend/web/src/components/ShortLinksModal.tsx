@@ -0,0 +1,139 @@
+import { CopyOutlined, DeleteOutlined } from '@ant-design/icons'
+import { Button, Input, message, Modal, Popconfirm, Space, Switch, Table, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { createShortLink, deleteShortLink, listShortLinks, updateShortLink, type ShortLink } from '../api/share'
+
+const { Text } = Typography
+
+interface ShortLinksModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+}
+
+// 短链接：同一分享可为不同受众创建多个短链接，分别统计点击；停用后短链接失效，正式链接不受影响
+function ShortLinksModal({ shareId, docTitle, onClose }: ShortLinksModalProps) {
+  const [items, setItems] = useState<ShortLink[]>([])
+  const [loading, setLoading] = useState(false)
+  const [label, setLabel] = useState('')
+  const [creating, setCreating] = useState(false)
+
+  const load = async () => {
+    if (!shareId) return
+    setLoading(true)
+    try {
+      const res = await listShortLinks(shareId)
+      if (res.code === 0) setItems(res.data.items || [])
+      else message.error(res.msg || '加载失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) {
+      setLabel('')
+      load()
+    }
+  }, [shareId])
+
+  const create = async () => {
+    if (!shareId) return
+    setCreating(true)
+    try {
+      const res = await createShortLink(shareId, label.trim())
+      if (res.code === 0) {
+        setLabel('')
+        load()
+      } else {
+        message.error(res.msg || '创建失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '创建失败')
+    } finally {
+      setCreating(false)
+    }
+  }
+
+  const toggle = async (link: ShortLink, enabled: boolean) => {
+    try {
+      const res = await updateShortLink(link.shareId, link.code, { disabled: !enabled })
+      if (res.code === 0) setItems(list => list.map(l => l.code === link.code ? res.data : l))
+      else message.error(res.msg || '操作失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    }
+  }
+
+  const remove = async (link: ShortLink) => {
+    try {
+      const res = await deleteShortLink(link.shareId, link.code)
+      if (res.code === 0) setItems(list => list.filter(l => l.code !== link.code))
+      else message.error(res.msg || '删除失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '删除失败')
+    }
+  }
+
+  const copy = (url: string) => {
+    navigator.clipboard.writeText(url).then(() => message.success('链接已复制')).catch(() => message.error('复制失败'))
+  }
+
+  const columns = [
+    {
+      title: '短链接',
+      key: 'url',
+      render: (_: any, l: ShortLink) => (
+        <Space direction="vertical" size={0}>
+          <Space size={4}>
+            <Text delete={l.disabled}>{l.url}</Text>
+            <Button type="link" size="small" icon={<CopyOutlined />} onClick={() => copy(l.url)} />
+          </Space>
+          {l.label && <Text type="secondary" style={{ fontSize: 12 }}>{l.label}</Text>}
+        </Space>
+      ),
+    },
+    {
+      title: '点击',
+      dataIndex: 'clicks',
+      key: 'clicks',
+      width: 140,
+      render: (clicks: number, l: ShortLink) => (
+        <Space direction="vertical" size={0}>
+          <Text>{clicks}</Text>
+          {l.lastClickedAt && <Text type="secondary" style={{ fontSize: 12 }}>{new Date(l.lastClickedAt).toLocaleString('zh-CN')}</Text>}
+        </Space>
+      ),
+    },
+    {
+      title: '启用',
+      key: 'enabled',
+      width: 70,
+      render: (_: any, l: ShortLink) => <Switch size="small" checked={!l.disabled} onChange={v => toggle(l, v)} />,
+    },
+    {
+      title: '',
+      key: 'delete',
+      width: 50,
+      render: (_: any, l: ShortLink) => (
+        <Popconfirm title="删除短链接？点击统计一并删除" onConfirm={() => remove(l)}>
+          <Button type="link" size="small" danger icon={<DeleteOutlined />} />
+        </Popconfirm>
+      ),
+    },
+  ]
+
+  return (
+    <Modal open={!!shareId} title={`短链接${docTitle ? ` · ${docTitle}` : ''}`} onCancel={onClose} footer={null} width={720} destroyOnClose>
+      <Space.Compact style={{ width: '100%', marginBottom: 16 }}>
+        <Input placeholder="用途说明（可选），如：邮件通讯、微博" value={label} maxLength={100} onChange={e => setLabel(e.target.value)} onPressEnter={create} />
+        <Button type="primary" loading={creating} onClick={create}>创建短链接</Button>
+      </Space.Compact>
+      <Table rowKey="code" size="small" loading={loading} columns={columns} dataSource={items} pagination={false} />
+    </Modal>
+  )
+}
+
+export default ShortLinksModal
@@ -0,0 +1,167 @@
+import { Alert, Button, Input, message, Modal, Space, Spin, Switch, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { generateSummary, getSummary, setQAEnabled, updateShareSummary, type ShareSummary } from '../api/share'
+
+const { Text } = Typography
+
+interface SummaryModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+}
+
+// 分享摘要：查看与修改摘要（meta description），采纳自动生成的建议标签
+function SummaryModal({ shareId, docTitle, onClose }: SummaryModalProps) {
+  const [data, setData] = useState<ShareSummary | null>(null)
+  const [summary, setSummary] = useState('')
+  const [loading, setLoading] = useState(false)
+  const [generating, setGenerating] = useState(false)
+  const [saving, setSaving] = useState(false)
+
+  const apply = (d: ShareSummary) => {
+    setData(d)
+    setSummary(d.summary)
+  }
+
+  useEffect(() => {
+    if (!shareId) {
+      setData(null)
+      setSummary('')
+      return
+    }
+    setLoading(true)
+    getSummary(shareId)
+      .then((res) => {
+        if (res.code === 0) {
+          apply(res.data)
+        } else {
+          message.error(res.msg || '加载失败')
+        }
+      })
+      .catch((e: any) => message.error(e.response?.data?.msg || e.message || '加载失败'))
+      .finally(() => setLoading(false))
+  }, [shareId])
+
+  const handleGenerate = async () => {
+    if (!shareId) return
+    setGenerating(true)
+    try {
+      const res = await generateSummary(shareId)
+      if (res.code === 0 && res.data) {
+        apply(res.data)
+        message.success('已生成')
+      } else {
+        message.error(res.msg || '生成失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '生成失败')
+    } finally {
+      setGenerating(false)
+    }
+  }
+
+  const save = async (patch: { summary?: string; tags?: string[] }, next: Partial<ShareSummary>) => {
+    if (!shareId || !data) return
+    setSaving(true)
+    try {
+      const res = await updateShareSummary(shareId, patch)
+      if (res.code === 0) {
+        apply({ ...data, ...next })
+        message.success('已保存')
+      } else {
+        message.error(res.msg || '保存失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const saveSummary = () => {
+    const value = summary.trim()
+    save({ summary: value }, { summary: value, summaryManual: value !== '' })
+  }
+
+  const adoptTag = (tag: string) => {
+    if (!data) return
+    const tags = [...data.tags, tag]
+    save({ tags }, { tags })
+  }
+
+  const toggleQA = async (checked: boolean) => {
+    if (!shareId || !data) return
+    setSaving(true)
+    try {
+      const res = await setQAEnabled(shareId, checked)
+      if (res.code === 0) {
+        setData({ ...data, allowQa: checked })
+        message.success(checked ? '已开启读者问答' : '已关闭读者问答')
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const pending = data?.suggestedTags.filter((t) => !data.tags.some((x) => x.toLowerCase() === t.toLowerCase())) ?? []
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`摘要${docTitle ? ` · ${docTitle}` : ''}`}
+      width={600}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Spin spinning={loading}>
+        {data && (
+          <Space direction="vertical" style={{ width: '100%' }} size="middle">
+            {!data.enabled && <Alert type="info" showIcon message="服务器未配置摘要生成，可手动填写摘要" />}
+            <Input.TextArea
+              value={summary}
+              maxLength={300}
+              showCount
+              autoSize={{ minRows: 3, maxRows: 6 }}
+              placeholder="未填写时使用正文开头作为链接预览与订阅源的简介"
+              onChange={(e) => setSummary(e.target.value)}
+            />
+            <Space>
+              <Button type="primary" loading={saving} disabled={summary.trim() === data.summary} onClick={saveSummary}>
+                保存摘要
+              </Button>
+              {data.enabled && (
+                <Button loading={generating} onClick={handleGenerate}>
+                  {data.summary ? '重新生成' : '生成摘要'}
+                </Button>
+              )}
+              {data.summaryManual ? <Tag color="blue">作者填写</Tag> : data.summary && <Tag>自动生成</Tag>}
+            </Space>
+            {pending.length > 0 && (
+              <Space wrap>
+                <Text>建议标签：</Text>
+                {pending.map((t) => (
+                  <Tag key={t} color="green" style={{ cursor: 'pointer' }} onClick={() => !saving && adoptTag(t)}>
+                    + {t}
+                  </Tag>
+                ))}
+              </Space>
+            )}
+            <Text type="secondary">作者填写的摘要不会在发布时被自动生成覆盖，清空后恢复自动生成；点击建议标签即可添加到分享。</Text>
+            {data.qaEnabled && (
+              <Space>
+                <Switch checked={data.allowQa} loading={saving} onChange={toggleQA} />
+                <Text>允许读者提问（回答仅依据正文，并标注出处章节）</Text>
+              </Space>
+            )}
+          </Space>
+        )}
+      </Spin>
+    </Modal>
+  )
+}
+
+export default SummaryModal
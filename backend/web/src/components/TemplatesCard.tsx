@@ -0,0 +1,188 @@
+import { ProfileOutlined } from '@ant-design/icons'
+import { Button, Card, Checkbox, Form, Input, InputNumber, message, Modal, Popconfirm, Select, Space, Table, Tag, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { deleteShareTemplate, listShareTemplates, saveShareTemplate, type ShareTemplate, type ShareTemplateInput } from '../api/share'
+
+const { Paragraph, Text } = Typography
+
+const tocLabels: Record<string, string> = { '': '不修改', side: '侧边', floating: '悬浮', off: '关闭' }
+
+// 发布模板：预设主题、有效期、密码、目录与收录设置，插件创建分享时通过 templateId 引用
+function TemplatesCard() {
+  const [templates, setTemplates] = useState<ShareTemplate[]>([])
+  const [loading, setLoading] = useState(false)
+  const [editing, setEditing] = useState<ShareTemplate | null | undefined>(undefined)
+  const [saving, setSaving] = useState(false)
+  const [form] = Form.useForm<ShareTemplateInput>()
+  const requirePassword = Form.useWatch('requirePassword', form)
+
+  const load = async () => {
+    setLoading(true)
+    try {
+      const res = await listShareTemplates()
+      if (res.code === 0) setTemplates(res.data.items || [])
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => { load() }, [])
+
+  const open = (tpl: ShareTemplate | null) => {
+    setEditing(tpl)
+    form.setFieldsValue({
+      name: tpl?.name ?? '',
+      theme: tpl?.theme ?? '',
+      toc: tpl?.toc ?? '',
+      expireDays: tpl?.expireDays ?? 7,
+      requirePassword: !!tpl?.requirePassword,
+      password: '',
+      isPublic: tpl?.isPublic ?? true,
+      listed: !!tpl?.listed,
+      isDefault: !!tpl?.isDefault,
+    })
+  }
+
+  const save = async (values: ShareTemplateInput) => {
+    setSaving(true)
+    try {
+      const res = await saveShareTemplate(editing?.id ?? null, { ...values, expireDays: values.expireDays || 0 })
+      if (res.code === 0) {
+        message.success('已保存')
+        setEditing(undefined)
+        load()
+      } else {
+        message.error(res.msg || '保存失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const remove = async (id: string) => {
+    try {
+      const res = await deleteShareTemplate(id)
+      if (res.code === 0) {
+        message.success('已删除')
+        load()
+      } else {
+        message.error(res.msg || '删除失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '删除失败')
+    }
+  }
+
+  const copyId = async (id: string) => {
+    await navigator.clipboard.writeText(id)
+    message.success('已复制模板 ID')
+  }
+
+  return (
+    <Card
+      title={<Space><ProfileOutlined /><span>发布模板</span></Space>}
+      bordered={false}
+      extra={<Button size="small" onClick={() => open(null)}>新建模板</Button>}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      <Paragraph type="secondary">
+        创建分享时传入模板 ID（templateId），未指定的设置取模板的值；默认模板在新建分享且未指定模板时自动使用。
+      </Paragraph>
+      <Table
+        rowKey="id"
+        size="small"
+        loading={loading}
+        dataSource={templates}
+        pagination={false}
+        columns={[
+          {
+            title: '名称',
+            dataIndex: 'name',
+            render: (name: string, r: ShareTemplate) => (
+              <Space>
+                <Text>{name}</Text>
+                {r.isDefault && <Tag color="blue">默认</Tag>}
+              </Space>
+            ),
+          },
+          {
+            title: '设置',
+            render: (_: any, r: ShareTemplate) => (
+              <Space size={4} wrap>
+                {r.theme && <Tag>主题 {r.theme}</Tag>}
+                {r.toc && <Tag>目录{tocLabels[r.toc]}</Tag>}
+                {r.expireDays > 0 && <Tag>{r.expireDays} 天</Tag>}
+                {r.requirePassword && <Tag color="orange">{r.hasPassword ? '默认密码' : '需要密码'}</Tag>}
+                {r.listed && <Tag color="green">收录</Tag>}
+              </Space>
+            ),
+          },
+          {
+            title: '操作',
+            width: 200,
+            render: (_: any, r: ShareTemplate) => (
+              <Space size={0}>
+                <Button type="link" size="small" onClick={() => copyId(r.id)}>复制 ID</Button>
+                <Button type="link" size="small" onClick={() => open(r)}>编辑</Button>
+                <Popconfirm title="删除该模板？已创建的分享不受影响" onConfirm={() => remove(r.id)}>
+                  <Button type="link" danger size="small">删除</Button>
+                </Popconfirm>
+              </Space>
+            ),
+          },
+        ]}
+      />
+      <Modal
+        open={editing !== undefined}
+        title={editing ? '编辑发布模板' : '新建发布模板'}
+        onCancel={() => setEditing(undefined)}
+        onOk={() => form.submit()}
+        confirmLoading={saving}
+        destroyOnClose
+      >
+        <Form form={form} layout="vertical" onFinish={save} preserve={false}>
+          <Form.Item name="name" label="名称" rules={[{ required: true, message: '请输入名称' }, { max: 100 }]}>
+            <Input />
+          </Form.Item>
+          <Form.Item name="theme" label="主题" extra="内置主题 auto / light / dark 或自定义主题 ID，留空跟随默认">
+            <Input placeholder="auto" />
+          </Form.Item>
+          <Form.Item name="toc" label="目录样式">
+            <Select options={Object.entries(tocLabels).map(([value, label]) => ({ value, label }))} />
+          </Form.Item>
+          <Form.Item name="expireDays" label="有效天数" extra="留空时由创建请求指定">
+            <InputNumber min={1} max={365} style={{ width: '100%' }} />
+          </Form.Item>
+          <Form.Item name="requirePassword" valuePropName="checked">
+            <Checkbox>需要访问密码</Checkbox>
+          </Form.Item>
+          {requirePassword && (
+            <Form.Item
+              name="password"
+              label="默认密码"
+              extra={editing?.hasPassword ? '留空保留原密码' : '创建分享时未传入密码则使用该密码'}
+              rules={[{ min: 4, message: '密码至少 4 位' }]}
+            >
+              <Input.Password />
+            </Form.Item>
+          )}
+          <Form.Item name="isPublic" valuePropName="checked" style={{ marginBottom: 8 }}>
+            <Checkbox>公开</Checkbox>
+          </Form.Item>
+          <Form.Item name="listed" valuePropName="checked" style={{ marginBottom: 8 }}>
+            <Checkbox>收录到站内搜索</Checkbox>
+          </Form.Item>
+          <Form.Item name="isDefault" valuePropName="checked">
+            <Checkbox>设为默认模板</Checkbox>
+          </Form.Item>
+        </Form>
+      </Modal>
+    </Card>
+  )
+}
+
+export default TemplatesCard
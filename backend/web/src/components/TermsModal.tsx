@@ -0,0 +1,157 @@
+import { Button, Input, message, Modal, Space, Table, Tabs, Typography } from 'antd'
+import type { ColumnsType } from 'antd/es/table'
+import { useEffect, useState } from 'react'
+import { getShareTermsAcceptances, setShareTerms, type ShareTermsAcceptance } from '../api/share'
+
+const { Text } = Typography
+
+interface TermsModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+  onChanged?: () => void
+}
+
+// 使用条款：读者须同意后才能阅读，同意记录（时间、IP、浏览器指纹）供分享者查看；修改条款后读者需重新同意
+function TermsModal({ shareId, docTitle, onClose, onChanged }: TermsModalProps) {
+  const [terms, setTerms] = useState('')
+  const [version, setVersion] = useState('')
+  const [items, setItems] = useState<ShareTermsAcceptance[]>([])
+  const [total, setTotal] = useState(0)
+  const [page, setPage] = useState(1)
+  const [loading, setLoading] = useState(false)
+  const [saving, setSaving] = useState(false)
+
+  const load = async (p = 1) => {
+    if (!shareId) return
+    setLoading(true)
+    try {
+      const res = await getShareTermsAcceptances(shareId, p)
+      if (res.code === 0) {
+        setTerms(res.data.terms)
+        setVersion(res.data.version)
+        setItems(res.data.items)
+        setTotal(res.data.total)
+        setPage(res.data.page)
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) load()
+  }, [shareId])
+
+  const save = async (next: string) => {
+    if (!shareId) return
+    setSaving(true)
+    try {
+      const res = await setShareTerms(shareId, next)
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      message.success(next.trim() ? '已保存，读者需重新同意' : '已取消使用条款')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const columns: ColumnsType<ShareTermsAcceptance> = [
+    {
+      title: '时间',
+      dataIndex: 'acceptedAt',
+      width: 170,
+      render: (v: string) => new Date(v).toLocaleString('zh-CN'),
+    },
+    {
+      title: '读者',
+      key: 'reader',
+      render: (_, r) => r.name || r.email || <Text type="secondary">匿名</Text>,
+    },
+    {
+      title: 'IP',
+      dataIndex: 'ip',
+      width: 130,
+    },
+    {
+      title: '浏览器指纹',
+      dataIndex: 'fingerprint',
+      render: (v: string, r) => <Text code title={r.userAgent}>{v.slice(0, 12)}</Text>,
+    },
+    {
+      title: '版本',
+      dataIndex: 'termsHash',
+      width: 90,
+      render: (v: string) => (v === version ? <Text type="success">当前</Text> : <Text type="secondary">{v.slice(0, 8)}</Text>),
+    },
+  ]
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`使用条款${docTitle ? ` · ${docTitle}` : ''}`}
+      width={760}
+      okText="保存"
+      confirmLoading={saving}
+      onOk={() => save(terms)}
+      onCancel={onClose}
+      footer={(_, { OkBtn, CancelBtn }) => (
+        <>
+          <Button danger disabled={saving || !version} onClick={() => save('')}>取消条款</Button>
+          <CancelBtn />
+          <OkBtn />
+        </>
+      )}
+    >
+      <Tabs
+        items={[
+          {
+            key: 'terms',
+            label: '条款内容',
+            children: (
+              <Space direction="vertical" style={{ width: '100%' }}>
+                <Input.TextArea
+                  value={terms}
+                  onChange={(e) => setTerms(e.target.value)}
+                  autoSize={{ minRows: 8, maxRows: 20 }}
+                  maxLength={20000}
+                  placeholder="支持 Markdown，留空则不要求同意"
+                />
+                <Text type="secondary">
+                  读者在阅读前须同意条款，修改条款后此前的同意失效。设置了使用条款的分享不出现在站内搜索、订阅源、日历与 sitemap 中，
+                  引用块子分享沿用本分享的条款。
+                </Text>
+              </Space>
+            ),
+          },
+          {
+            key: 'acceptances',
+            label: `同意记录（${total}）`,
+            children: (
+              <Table
+                rowKey="id"
+                size="small"
+                loading={loading}
+                columns={columns}
+                dataSource={items}
+                pagination={{ current: page, pageSize: 50, total, onChange: load }}
+              />
+            ),
+          },
+        ]}
+      />
+    </Modal>
+  )
+}
+
+export default TermsModal
@@ -0,0 +1,226 @@
+import { Alert, Button, Input, List, message, Modal, Popconfirm, Select, Space, Spin, Tag, Typography } from 'antd'
+import { useEffect, useRef, useState } from 'react'
+import {
+  createTranslation,
+  deleteTranslation,
+  getTranslation,
+  listTranslations,
+  saveTranslation,
+  type ShareTranslation,
+  type ShareTranslationList,
+} from '../api/share'
+import { languageName } from './LanguageSwitcher'
+
+const { Text } = Typography
+
+interface TranslationsModalProps {
+  shareId: string | null
+  docTitle?: string
+  onClose: () => void
+}
+
+// 未限定目标语言时提供的常用语言
+const commonLanguages = ['en', 'zh', 'zh-tw', 'ja', 'ko', 'fr', 'de', 'es', 'ru']
+
+const statusLabels: Record<ShareTranslation['status'], { text: string; color: string }> = {
+  pending: { text: '生成中', color: 'processing' },
+  ready: { text: '已发布', color: 'green' },
+  failed: { text: '生成失败', color: 'red' },
+}
+
+// 分享的翻译版本：生成机器译文或手动提供译文，读者在阅读页切换语言
+function TranslationsModal({ shareId, docTitle, onClose }: TranslationsModalProps) {
+  const [data, setData] = useState<ShareTranslationList | null>(null)
+  const [lang, setLang] = useState<string>()
+  const [loading, setLoading] = useState(false)
+  const [working, setWorking] = useState(false)
+  const [editing, setEditing] = useState<{ lang: string; docTitle: string; content: string } | null>(null)
+  const timer = useRef<number>()
+
+  const load = async (id: string) => {
+    try {
+      const res = await listTranslations(id)
+      if (res.code === 0) {
+        setData(res.data)
+        // 生成中时轮询状态
+        window.clearTimeout(timer.current)
+        if (res.data.items.some((t) => t.generating || t.status === 'pending')) {
+          timer.current = window.setTimeout(() => load(id), 3000)
+        }
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    }
+  }
+
+  useEffect(() => {
+    if (shareId) {
+      setLoading(true)
+      load(shareId).finally(() => setLoading(false))
+    } else {
+      setData(null)
+      setLang(undefined)
+      setEditing(null)
+    }
+    return () => window.clearTimeout(timer.current)
+  }, [shareId])
+
+  const run = async (action: () => Promise<{ code: number; msg: string }>, ok: string) => {
+    if (!shareId) return false
+    setWorking(true)
+    try {
+      const res = await action()
+      if (res.code === 0) {
+        message.success(ok)
+        load(shareId)
+        return true
+      }
+      message.error(res.msg || '操作失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    } finally {
+      setWorking(false)
+    }
+    return false
+  }
+
+  const edit = async (l: string) => {
+    if (!shareId) return
+    if (!data?.items.some((t) => t.lang === l)) {
+      setEditing({ lang: l, docTitle: docTitle || '', content: '' })
+      return
+    }
+    try {
+      const res = await getTranslation(shareId, l)
+      if (res.code === 0) {
+        setEditing({ lang: l, docTitle: res.data.docTitle, content: res.data.content || '' })
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    }
+  }
+
+  const save = async () => {
+    if (!shareId || !editing) return
+    const { lang: l, docTitle: title, content } = editing
+    if (await run(() => saveTranslation(shareId, l, title, content), '已保存')) {
+      setEditing(null)
+    }
+  }
+
+  const existing = new Set(data?.items.map((t) => t.lang))
+  const choices = (data?.languages.length ? data.languages : commonLanguages)
+    .filter((l) => !existing.has(l) && l !== data?.sourceLang)
+    .map((l) => ({ value: l, label: `${languageName(l)} (${l})` }))
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`翻译${docTitle ? ` · ${docTitle}` : ''}`}
+      width={680}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Spin spinning={loading}>
+        {editing ? (
+          <Space direction="vertical" style={{ width: '100%' }} size="middle">
+            <Text>{languageName(editing.lang)} ({editing.lang})</Text>
+            <Input
+              value={editing.docTitle}
+              maxLength={255}
+              placeholder="译文标题"
+              onChange={(e) => setEditing({ ...editing, docTitle: e.target.value })}
+            />
+            <Input.TextArea
+              value={editing.content}
+              autoSize={{ minRows: 10, maxRows: 24 }}
+              placeholder="译文正文（Markdown）"
+              onChange={(e) => setEditing({ ...editing, content: e.target.value })}
+            />
+            <Text type="secondary">手动修改的译文不会在原文更新后被自动覆盖。</Text>
+            <Space>
+              <Button type="primary" loading={working} disabled={!editing.docTitle.trim()} onClick={save}>保存</Button>
+              <Button onClick={() => setEditing(null)}>取消</Button>
+            </Space>
+          </Space>
+        ) : (
+          <Space direction="vertical" style={{ width: '100%' }} size="middle">
+            {data && !data.enabled && (
+              <Alert type="info" showIcon message="服务器未配置翻译接口，只能手动提供译文" />
+            )}
+            <Space wrap>
+              <Select
+                showSearch
+                style={{ width: 220 }}
+                placeholder="选择或输入语言，如 en"
+                value={lang}
+                options={choices}
+                onChange={setLang}
+                onSearch={(v) => v && setLang(v.trim().toLowerCase())}
+              />
+              {data?.enabled && (
+                <Button
+                  type="primary"
+                  loading={working}
+                  disabled={!lang}
+                  onClick={() => run(() => createTranslation(shareId!, lang!), '已开始翻译').then((ok) => ok && setLang(undefined))}
+                >
+                  生成译文
+                </Button>
+              )}
+              <Button disabled={!lang} onClick={() => lang && edit(lang)}>手动提供</Button>
+            </Space>
+            <List
+              size="small"
+              locale={{ emptyText: '暂无译文' }}
+              dataSource={data?.items || []}
+              renderItem={(t) => (
+                <List.Item
+                  actions={[
+                    data?.enabled && (
+                      <Button
+                        key="regenerate"
+                        type="link"
+                        size="small"
+                        disabled={t.generating}
+                        onClick={() => run(() => createTranslation(shareId!, t.lang), '已开始翻译')}
+                      >
+                        重新生成
+                      </Button>
+                    ),
+                    <Button key="edit" type="link" size="small" disabled={t.generating} onClick={() => edit(t.lang)}>
+                      修改
+                    </Button>,
+                    <Popconfirm key="delete" title="删除该译文？" onConfirm={() => run(() => deleteTranslation(shareId!, t.lang), '已删除')}>
+                      <Button type="link" size="small" danger>删除</Button>
+                    </Popconfirm>,
+                  ].filter(Boolean)}
+                >
+                  <List.Item.Meta
+                    title={
+                      <Space>
+                        {t.status === 'ready' ? <a href={t.url} target="_blank" rel="noreferrer">{languageName(t.lang)}</a> : languageName(t.lang)}
+                        <Tag color={statusLabels[t.status].color}>{statusLabels[t.status].text}</Tag>
+                        {t.generating && t.status === 'ready' && <Tag color="processing">更新中</Tag>}
+                        {t.stale && <Tag color="orange">原文已更新</Tag>}
+                        {t.manual && <Tag>手动修改</Tag>}
+                      </Space>
+                    }
+                    description={t.error ? <Text type="danger">{t.error}</Text> : t.docTitle}
+                  />
+                </List.Item>
+              )}
+            />
+            <Text type="secondary">读者通过 /s/分享ID/语言 访问译文，阅读页显示语言切换。</Text>
+          </Space>
+        )}
+      </Spin>
+    </Modal>
+  )
+}
+
+export default TranslationsModal
@@ -0,0 +1,95 @@
+import { Button, message, Modal, Popconfirm, Space, Table, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { emptyTrash, listTrash, purgeShare, restoreShare, type TrashedShare } from '../api/share'
+
+const { Text } = Typography
+
+interface TrashModalProps {
+  open: boolean
+  onClose: () => void
+  onRestored?: () => void
+}
+
+// 回收站：删除的分享在保留期内可以恢复（链接、正文、资源与统计不变），到期后自动彻底删除
+function TrashModal({ open, onClose, onRestored }: TrashModalProps) {
+  const [items, setItems] = useState<TrashedShare[]>([])
+  const [loading, setLoading] = useState(false)
+
+  const load = async () => {
+    setLoading(true)
+    try {
+      const res = await listTrash()
+      if (res.code === 0) setItems(res.data.items || [])
+      else message.error(res.msg || '加载失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    if (open) load()
+  }, [open])
+
+  const run = async (action: () => Promise<{ code: number; msg: string }>, ok: string, restored = false) => {
+    try {
+      const res = await action()
+      if (res.code === 0) {
+        message.success(ok)
+        load()
+        if (restored) onRestored?.()
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    }
+  }
+
+  return (
+    <Modal
+      open={open}
+      title="回收站"
+      width={820}
+      footer={null}
+      onCancel={onClose}
+    >
+      <Space style={{ marginBottom: 16, width: '100%', justifyContent: 'space-between' }}>
+        <Text type="secondary">删除的分享保留一段时间后自动彻底删除，期间可以恢复，链接保持不变。</Text>
+        <Popconfirm title="彻底删除回收站中的全部分享？此操作不可恢复" onConfirm={() => run(emptyTrash, '已清空回收站')} disabled={items.length === 0}>
+          <Button danger size="small" disabled={items.length === 0}>清空回收站</Button>
+        </Popconfirm>
+      </Space>
+      <Table<TrashedShare>
+        size="small"
+        rowKey="id"
+        loading={loading}
+        dataSource={items}
+        pagination={items.length > 10 ? { pageSize: 10 } : false}
+        locale={{ emptyText: '回收站为空' }}
+        columns={[
+          { title: '标题', dataIndex: 'docTitle', ellipsis: true },
+          { title: '浏览', dataIndex: 'viewCount', width: 70 },
+          { title: '删除时间', dataIndex: 'deletedAt', width: 170, render: (t: string) => new Date(t).toLocaleString() },
+          { title: '彻底删除时间', dataIndex: 'purgeAt', width: 170, render: (t: string) => new Date(t).toLocaleString() },
+          {
+            title: '操作',
+            key: 'action',
+            width: 140,
+            render: (r: TrashedShare) => (
+              <Space size="small">
+                <Button type="link" size="small" onClick={() => run(() => restoreShare(r.id), '已恢复', true)}>恢复</Button>
+                <Popconfirm title="彻底删除这个分享？此操作不可恢复" onConfirm={() => run(() => purgeShare(r.id), '已彻底删除')}>
+                  <Button type="link" size="small" danger>彻底删除</Button>
+                </Popconfirm>
+              </Space>
+            ),
+          },
+        ]}
+      />
+    </Modal>
+  )
+}
+
+export default TrashModal
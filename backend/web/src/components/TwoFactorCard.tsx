@@ -0,0 +1,135 @@
+import { SafetyOutlined } from '@ant-design/icons'
+import { Alert, Button, Card, Input, Modal, QRCode, Space, Tag, Typography, message } from 'antd'
+import { useState } from 'react'
+import api from '../api'
+
+const { Text, Paragraph } = Typography
+
+interface ApiResp<T = any> { code: number; msg: string; data: T }
+
+interface Props {
+  enabled: boolean
+  onChange: () => void
+}
+
+// 两步验证（TOTP）设置：扫码绑定验证器、展示恢复码、关闭两步验证
+function TwoFactorCard({ enabled, onChange }: Props) {
+  const [setup, setSetup] = useState<{ secret: string; otpauthUrl: string } | null>(null)
+  const [code, setCode] = useState('')
+  const [password, setPassword] = useState('')
+  const [recoveryCodes, setRecoveryCodes] = useState<string[] | null>(null)
+  const [disableOpen, setDisableOpen] = useState(false)
+  const [loading, setLoading] = useState(false)
+
+  const request = async <T,>(url: string, body?: any): Promise<T | null> => {
+    setLoading(true)
+    try {
+      const res = await api.post(url, body) as ApiResp<T>
+      if (res.code === 0) return res.data
+      message.error(res.msg || '操作失败')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    } finally {
+      setLoading(false)
+    }
+    return null
+  }
+
+  const startSetup = async () => {
+    const data = await request<{ secret: string; otpauthUrl: string }>('/api/auth/2fa/setup')
+    if (data) {
+      setSetup(data)
+      setCode('')
+    }
+  }
+
+  const confirmSetup = async () => {
+    const data = await request<{ recoveryCodes: string[] }>('/api/auth/2fa/enable', { code })
+    if (data) {
+      setSetup(null)
+      setRecoveryCodes(data.recoveryCodes)
+      onChange()
+    }
+  }
+
+  const regenerate = async () => {
+    const data = await request<{ recoveryCodes: string[] }>('/api/auth/2fa/recovery-codes', { code })
+    if (data) {
+      setCode('')
+      setRecoveryCodes(data.recoveryCodes)
+    }
+  }
+
+  const disable = async () => {
+    const data = await request('/api/auth/2fa/disable', { password, code })
+    if (data !== null) {
+      setDisableOpen(false)
+      setPassword('')
+      setCode('')
+      message.success('两步验证已关闭')
+      onChange()
+    }
+  }
+
+  return (
+    <Card
+      title={<Space><SafetyOutlined /><span>两步验证</span></Space>}
+      bordered={false}
+      extra={enabled ? <Tag color="success">已启用</Tag> : <Tag>未启用</Tag>}
+      style={{ marginBottom: 24, borderRadius: 12, boxShadow: '0 2px 16px rgba(0,0,0,0.04)' }}
+    >
+      {enabled ? (
+        <Space direction="vertical" size="middle">
+          <Paragraph type="secondary" style={{ margin: 0 }}>
+            登录时需输入验证器应用中的 6 位验证码；API Token 不受影响。
+          </Paragraph>
+          <Space wrap>
+            <Input placeholder="当前验证码" value={code} onChange={e => setCode(e.target.value)} style={{ width: 160 }} />
+            <Button loading={loading} disabled={!code} onClick={regenerate}>重新生成恢复码</Button>
+            <Button danger onClick={() => setDisableOpen(true)}>关闭两步验证</Button>
+          </Space>
+        </Space>
+      ) : setup ? (
+        <Space direction="vertical" size="middle">
+          <Text>使用验证器应用扫描二维码，或手动输入密钥：<Text code copyable>{setup.secret}</Text></Text>
+          <QRCode value={setup.otpauthUrl} />
+          <Space>
+            <Input placeholder="6 位验证码" value={code} onChange={e => setCode(e.target.value)} style={{ width: 160 }} />
+            <Button type="primary" loading={loading} disabled={!code} onClick={confirmSetup}>验证并启用</Button>
+            <Button onClick={() => setSetup(null)}>取消</Button>
+          </Space>
+        </Space>
+      ) : (
+        <Button type="primary" loading={loading} onClick={startSetup}>启用两步验证</Button>
+      )}
+
+      <Modal
+        title="恢复码"
+        open={!!recoveryCodes}
+        onCancel={() => setRecoveryCodes(null)}
+        footer={<Button type="primary" onClick={() => setRecoveryCodes(null)}>我已保存</Button>}
+      >
+        <Alert type="warning" showIcon style={{ marginBottom: 16 }} message="恢复码仅显示一次，每个只能使用一次。丢失验证器时可用它登录。" />
+        <Paragraph copyable={{ text: recoveryCodes?.join('\n') }}>
+          {recoveryCodes?.map(c => <div key={c}><Text code>{c}</Text></div>)}
+        </Paragraph>
+      </Modal>
+
+      <Modal
+        title="关闭两步验证"
+        open={disableOpen}
+        onCancel={() => setDisableOpen(false)}
+        onOk={disable}
+        okButtonProps={{ danger: true, loading, disabled: !password || !code }}
+        okText="关闭"
+      >
+        <Space direction="vertical" style={{ width: '100%' }}>
+          <Input.Password placeholder="登录密码" value={password} onChange={e => setPassword(e.target.value)} />
+          <Input placeholder="验证码或恢复码" value={code} onChange={e => setCode(e.target.value)} />
+        </Space>
+      </Modal>
+    </Card>
+  )
+}
+
+export default TwoFactorCard
@@ -0,0 +1,138 @@
+import { AutoComplete, Divider, message, Modal, Radio, Slider, Space, Switch, Table, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { getShareVariantStats, setShareVariant, type ShareTOC, type ShareVariant, type ShareVariantStats } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface VariantModalProps {
+  shareId: string | null
+  docTitle?: string
+  variant?: ShareVariant | null
+  onClose: () => void
+  onChanged?: () => void
+}
+
+const themeOptions = [
+  { value: 'auto', label: 'auto（跟随系统）' },
+  { value: 'light', label: 'light（浅色）' },
+  { value: 'dark', label: 'dark（深色）' },
+]
+
+const tocLabels: Record<string, string> = { '': '与 A 组相同', side: '侧边栏', floating: '悬浮', off: '不显示' }
+
+// 主题 A/B 测试：B 组读者使用另一套主题与目录样式，按比例分配，同一读者 30 天内保持同一分组；
+// 下方比较两组的浏览次数、平均阅读时长、平均阅读深度与读完比例
+function VariantModal({ shareId, docTitle, variant, onClose, onChanged }: VariantModalProps) {
+  const [enabled, setEnabled] = useState(false)
+  const [theme, setTheme] = useState('')
+  const [toc, setToc] = useState<ShareTOC | ''>('')
+  const [percent, setPercent] = useState(50)
+  const [stats, setStats] = useState<ShareVariantStats[]>([])
+  const [loading, setLoading] = useState(false)
+  const [saving, setSaving] = useState(false)
+
+  useEffect(() => {
+    if (!shareId) return
+    setEnabled(!!variant)
+    setTheme(variant?.theme || '')
+    setToc(variant?.toc || '')
+    setPercent(variant?.percent ?? 50)
+    setStats([])
+    setLoading(true)
+    getShareVariantStats(shareId)
+      .then(res => {
+        if (res.code === 0) setStats(res.data.items || [])
+      })
+      .catch(() => {})
+      .finally(() => setLoading(false))
+  }, [shareId])
+
+  const save = async () => {
+    if (!shareId) return
+    if (enabled && !theme.trim() && !toc) {
+      message.warning('请为 B 组选择主题或目录样式')
+      return
+    }
+    setSaving(true)
+    try {
+      const res = await setShareVariant(shareId, enabled ? { theme: theme.trim(), toc, percent } : { theme: '', toc: '' })
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      message.success('已保存')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`主题 A/B 测试${docTitle ? ` · ${docTitle}` : ''}`}
+      okText="保存"
+      confirmLoading={saving}
+      onOk={save}
+      onCancel={onClose}
+      width={720}
+    >
+      <Space>
+        <Switch checked={enabled} onChange={setEnabled} />
+        <Text>开启 A/B 测试</Text>
+      </Space>
+      <Paragraph type="secondary" style={{ marginTop: 12 }}>
+        A 组使用分享当前的主题与目录样式，B 组使用下方的设置。读者按比例随机分组，30 天内重复访问看到同一版本；
+        在分享链接后加上 <Text code>?variant=a</Text> 或 <Text code>?variant=b</Text> 可预览指定版本。
+      </Paragraph>
+      {enabled && (
+        <Space direction="vertical" style={{ width: '100%' }}>
+          <Text>B 组主题</Text>
+          <AutoComplete
+            value={theme}
+            options={themeOptions}
+            onChange={setTheme}
+            placeholder="与 A 组相同；可填写自定义主题 ID"
+            style={{ width: 320 }}
+            allowClear
+          />
+          <Text>B 组目录样式</Text>
+          <Radio.Group value={toc} onChange={e => setToc(e.target.value)}>
+            {Object.entries(tocLabels).map(([value, label]) => (
+              <Radio key={value} value={value}>{label}</Radio>
+            ))}
+          </Radio.Group>
+          <Text>分配到 B 组的读者：{percent}%</Text>
+          <Slider min={0} max={100} value={percent} onChange={setPercent} style={{ width: 320 }} />
+        </Space>
+      )}
+      <Divider />
+      <Paragraph>各组数据（浏览记录保留期内）</Paragraph>
+      <Table<ShareVariantStats>
+        rowKey="variant"
+        size="small"
+        loading={loading}
+        dataSource={stats}
+        pagination={false}
+        columns={[
+          { title: '分组', dataIndex: 'variant', render: (v: string) => v.toUpperCase() },
+          { title: '主题', dataIndex: 'theme', render: (v: string) => v || '默认' },
+          { title: '目录', dataIndex: 'toc', render: (v: string) => tocLabels[v] || v },
+          { title: '浏览', dataIndex: 'views' },
+          { title: '阅读上报', dataIndex: 'reads' },
+          { title: '平均时长', dataIndex: 'avgSeconds', render: (v: number) => `${Math.round(v)} 秒` },
+          { title: '平均深度', dataIndex: 'avgDepth', render: (v: number) => `${Math.round(v)}%` },
+          { title: '读完比例', dataIndex: 'completionRate', render: (v: number) => `${(v * 100).toFixed(1)}%` },
+        ]}
+      />
+      <Paragraph type="secondary" style={{ marginTop: 12 }}>
+        阅读时长与深度由阅读页在读者离开时上报，只统计页面可见的时间；滚动到正文 90% 以上视为读完。
+      </Paragraph>
+    </Modal>
+  )
+}
+
+export default VariantModal
@@ -0,0 +1,90 @@
+import { UploadOutlined } from '@ant-design/icons'
+import { Alert, Button, Descriptions, message, Modal, Space, Typography, Upload } from 'antd'
+import { useEffect, useState } from 'react'
+import { ShareSignature, ShareVerifyResult, verifyShareCopy } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface VerifyModalProps {
+  open: boolean
+  shareId: string
+  signature: ShareSignature
+  sourceUrl: string // 被签名的正文源文下载地址（已附带访问凭据）
+  onClose: () => void
+}
+
+// sha256Hex 文件内容的 SHA-256（十六进制），只在浏览器中计算，不上传文件
+const sha256Hex = async (file: File): Promise<string> => {
+  const digest = await crypto.subtle.digest('SHA-256', await file.arrayBuffer())
+  return Array.from(new Uint8Array(digest)).map(b => b.toString(16).padStart(2, '0')).join('')
+}
+
+// 内容签名与副本校验：展示当前版本的签名，读者选择下载的源文后比对发布过的版本
+function VerifyModal({ open, shareId, signature, sourceUrl, onClose }: VerifyModalProps) {
+  const [checking, setChecking] = useState(false)
+  const [result, setResult] = useState<ShareVerifyResult | null>(null)
+
+  useEffect(() => {
+    if (open) setResult(null)
+  }, [open])
+
+  const check = async (file: File) => {
+    setChecking(true)
+    try {
+      const res = await verifyShareCopy(shareId, await sha256Hex(file))
+      if (res.code !== 0 || !res.data) {
+        message.error(res.msg || '校验失败')
+        return
+      }
+      setResult(res.data)
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '校验失败')
+    } finally {
+      setChecking(false)
+    }
+  }
+
+  return (
+    <Modal open={open} title="内容签名" footer={null} onCancel={onClose} width={640}>
+      <Paragraph type="secondary">
+        每次发布的正文 Markdown 源文都由本站的实例密钥（Ed25519）签名。下载源文后可在此比对，或用
+        <Text code>/api/signing-key</Text> 提供的公钥离线校验。
+      </Paragraph>
+      <Descriptions column={1} size="small" bordered>
+        <Descriptions.Item label="版本">{signature.version}</Descriptions.Item>
+        <Descriptions.Item label="SHA-256">
+          <Text code copyable style={{ wordBreak: 'break-all' }}>{signature.contentHash}</Text>
+        </Descriptions.Item>
+        <Descriptions.Item label="签名">
+          <Text code copyable style={{ wordBreak: 'break-all' }}>{signature.signature}</Text>
+        </Descriptions.Item>
+        <Descriptions.Item label="密钥 ID">{signature.keyId}</Descriptions.Item>
+      </Descriptions>
+      <Space style={{ marginTop: 16 }}>
+        <Button href={sourceUrl}>下载源文</Button>
+        <Upload
+          accept=".md,.markdown,.txt"
+          showUploadList={false}
+          beforeUpload={(file) => { check(file); return false }}
+        >
+          <Button icon={<UploadOutlined />} loading={checking}>选择副本校验</Button>
+        </Upload>
+      </Space>
+      {result && (
+        <Alert
+          style={{ marginTop: 16 }}
+          showIcon
+          type={!result.match ? 'error' : result.signatureValid ? 'success' : 'warning'}
+          message={!result.match
+            ? '副本与发布过的任何版本都不一致'
+            : result.current
+              ? `与当前版本（第 ${result.version} 版）一致`
+              : `与第 ${result.version} 版一致，该版本发布于 ${new Date(result.publishedAt!).toLocaleString('zh-CN')}，之后已有更新`}
+          description={result.match && !result.signatureValid ? '该版本的签名无法校验（可能在更换实例密钥前发布）。' : undefined}
+        />
+      )}
+    </Modal>
+  )
+}
+
+export default VerifyModal
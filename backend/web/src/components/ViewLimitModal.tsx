@@ -0,0 +1,78 @@
+import { Button, InputNumber, message, Modal, Space, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { setShareMaxViews } from '../api/share'
+
+const { Text, Paragraph } = Typography
+
+interface ViewLimitModalProps {
+  shareId: string | null
+  docTitle?: string
+  maxViews?: number
+  viewCount?: number
+  onClose: () => void
+  onChanged?: () => void
+}
+
+// 浏览次数上限：读者打开次数达到上限后分享自动停用；上限包含已有的浏览次数，设为 1 即阅后即焚
+function ViewLimitModal({ shareId, docTitle, maxViews, viewCount = 0, onClose, onChanged }: ViewLimitModalProps) {
+  const [value, setValue] = useState<number | null>(null)
+  const [saving, setSaving] = useState(false)
+
+  useEffect(() => {
+    if (shareId) setValue(maxViews || viewCount + 1)
+  }, [shareId])
+
+  const save = async (next: number) => {
+    if (!shareId) return
+    setSaving(true)
+    try {
+      const res = await setShareMaxViews(shareId, next)
+      if (res.code !== 0) {
+        message.error(res.msg || '保存失败')
+        return
+      }
+      message.success(next ? '已保存' : '已取消浏览次数限制')
+      onChanged?.()
+      onClose()
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  return (
+    <Modal
+      open={!!shareId}
+      title={`浏览次数${docTitle ? ` · ${docTitle}` : ''}`}
+      okText="保存"
+      okButtonProps={{ disabled: !value }}
+      confirmLoading={saving}
+      onOk={() => value && save(value)}
+      onCancel={onClose}
+      footer={(_, { OkBtn, CancelBtn }) => (
+        <>
+          <Button danger disabled={saving || !maxViews} onClick={() => save(0)}>不限次数</Button>
+          <CancelBtn />
+          <OkBtn />
+        </>
+      )}
+    >
+      <Paragraph>
+        读者每打开一次计为一次浏览（你本人登录后打开不计），达到上限后分享自动停用。上限包含已有的 {viewCount} 次浏览，
+        因次数用尽而停用的分享提高上限后重新上线。
+      </Paragraph>
+      <Space>
+        <Text>最多浏览</Text>
+        <InputNumber min={1} max={10000} value={value} onChange={setValue} />
+        <Text>次</Text>
+        <Button size="small" onClick={() => setValue(viewCount + 1)}>阅后即焚</Button>
+      </Space>
+      <Paragraph type="secondary" style={{ marginTop: 12 }}>
+        限制浏览次数的分享不出现在站内搜索、订阅源、日历与 sitemap 中，链接预览不显示标题与摘要。
+      </Paragraph>
+    </Modal>
+  )
+}
+
+export default ViewLimitModal
@@ -6,6 +6,13 @@ import { BrowserRouter } from "react-router-dom";
 import App from "./App.tsx";
 import "./index.css";
 
+// 注册 Service Worker：安装为应用后离线时仍能打开已缓存的页面
+if ("serviceWorker" in navigator && import.meta.env.PROD) {
+  window.addEventListener("load", () => {
+    navigator.serviceWorker.register("/sw.js").catch(() => {});
+  });
+}
+
 // 检测暗黑模式
 const isDark = window.matchMedia("(prefers-color-scheme: dark)").matches;
 
@@ -0,0 +1,262 @@
+import { ArrowDownOutlined, ArrowLeftOutlined, ArrowUpOutlined, CloseOutlined, CopyOutlined, DeleteOutlined, EditOutlined, PlusOutlined } from '@ant-design/icons'
+import { Button, Card, Form, Input, List, message, Modal, Select, Space, Table, Typography } from 'antd'
+import type { ColumnsType } from 'antd/es/table'
+import { useEffect, useState } from 'react'
+import { useNavigate } from 'react-router-dom'
+import { createCollection, deleteCollection, listCollections, listShares, updateCollection, type CollectionInput, type ShareCollection, type ShareListItem } from '../api/share'
+
+const { Title, Text } = Typography
+
+function CollectionList() {
+  const navigate = useNavigate()
+  const [collections, setCollections] = useState<ShareCollection[]>([])
+  const [shares, setShares] = useState<ShareListItem[]>([])
+  const [loading, setLoading] = useState(true)
+  const [editing, setEditing] = useState<ShareCollection | 'new' | null>(null)
+  const [shareIds, setShareIds] = useState<string[]>([])
+  const [saving, setSaving] = useState(false)
+  const [form] = Form.useForm<Omit<CollectionInput, 'shareIds'>>()
+
+  const load = async () => {
+    setLoading(true)
+    try {
+      const [cols, list] = await Promise.all([listCollections(), listShares(1, 100)])
+      if (cols.code === 0) {
+        setCollections(cols.data.items || [])
+      } else {
+        message.error(cols.msg || '加载失败')
+      }
+      if (list.code === 0) {
+        setShares(list.data.items || [])
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    load()
+  }, [])
+
+  const titleOf = (id: string) => shares.find(s => s.id === id)?.docTitle || id
+
+  const openEditor = (col: ShareCollection | 'new') => {
+    setEditing(col)
+    setShareIds(col === 'new' ? [] : col.shareIds || [])
+  }
+
+  const move = (index: number, delta: number) => {
+    const next = [...shareIds]
+    const [item] = next.splice(index, 1)
+    next.splice(index + delta, 0, item)
+    setShareIds(next)
+  }
+
+  const handleSave = async () => {
+    const values = await form.validateFields()
+    setSaving(true)
+    try {
+      const input = { ...values, shareIds }
+      const res = editing && editing !== 'new' ? await updateCollection(editing.id, input) : await createCollection(input)
+      if (res.code === 0) {
+        message.success('已保存')
+        setEditing(null)
+        load()
+      } else {
+        message.error(res.msg || '保存失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const handleDelete = (col: ShareCollection) => {
+    Modal.confirm({
+      title: '确认删除',
+      content: `确定要删除合集"${col.title}"吗？合集中的分享不会被删除。`,
+      okText: '删除',
+      okType: 'danger',
+      cancelText: '取消',
+      onOk: async () => {
+        try {
+          const res = await deleteCollection(col.id)
+          if (res.code === 0) {
+            message.success('删除成功')
+            load()
+          } else {
+            message.error(res.msg || '删除失败')
+          }
+        } catch (e: any) {
+          message.error(e.response?.data?.msg || e.message || '删除失败')
+        }
+      }
+    })
+  }
+
+  const copyUrl = (url: string) => {
+    navigator.clipboard.writeText(url).then(() => {
+      message.success('链接已复制')
+    }).catch(() => {
+      message.error('复制失败')
+    })
+  }
+
+  const columns: ColumnsType<ShareCollection> = [
+    {
+      title: '标题',
+      dataIndex: 'title',
+      key: 'title',
+      ellipsis: true,
+      render: (text: string, record) => <a href={record.url} target="_blank" rel="noreferrer"><Text strong>{text}</Text></a>
+    },
+    {
+      title: '地址',
+      dataIndex: 'slug',
+      key: 'slug',
+      width: 200,
+      render: (slug: string) => <Text code>/c/{slug}</Text>
+    },
+    {
+      title: '分享数',
+      key: 'count',
+      width: 100,
+      align: 'center',
+      render: (record: ShareCollection) => record.shareIds?.length ?? 0
+    },
+    {
+      title: '更新时间',
+      dataIndex: 'updatedAt',
+      key: 'updatedAt',
+      width: 180,
+      render: (time: string) => new Date(time).toLocaleString(),
+    },
+    {
+      title: '操作',
+      key: 'action',
+      width: 260,
+      render: (record: ShareCollection) => (
+        <Space size="small">
+          <Button type="link" size="small" icon={<CopyOutlined />} onClick={() => copyUrl(record.url)}>
+            复制
+          </Button>
+          <Button type="link" size="small" icon={<EditOutlined />} onClick={() => openEditor(record)}>
+            编辑
+          </Button>
+          <Button type="link" size="small" danger icon={<DeleteOutlined />} onClick={() => handleDelete(record)}>
+            删除
+          </Button>
+        </Space>
+      )
+    }
+  ]
+
+  return (
+    <div style={{ maxWidth: 1200, margin: '60px auto', padding: '0 24px' }}>
+      <Card>
+        <div style={{ marginBottom: 24, display: 'flex', alignItems: 'center', justifyContent: 'space-between' }}>
+          <div style={{ display: 'flex', alignItems: 'center', gap: 16 }}>
+            <Button icon={<ArrowLeftOutlined />} onClick={() => navigate('/dashboard')}>
+              返回仪表盘
+            </Button>
+            <Title level={3} style={{ margin: 0 }}>
+              合集管理
+            </Title>
+          </div>
+          <Button type="primary" icon={<PlusOutlined />} onClick={() => openEditor('new')}>
+            新建合集
+          </Button>
+        </div>
+
+        <Table
+          dataSource={collections}
+          columns={columns}
+          rowKey="id"
+          loading={loading}
+          pagination={false}
+          locale={{
+            emptyText: (
+              <div style={{ padding: '40px 0', color: 'rgba(0,0,0,0.25)' }}>
+                <div style={{ fontSize: 48, marginBottom: 16 }}>📚</div>
+                <div>暂无合集，可将多篇分享组织为一个带首页的合集</div>
+              </div>
+            )
+          }}
+        />
+      </Card>
+
+      <Modal
+        title={editing === 'new' ? '新建合集' : '编辑合集'}
+        open={editing !== null}
+        onCancel={() => setEditing(null)}
+        onOk={handleSave}
+        confirmLoading={saving}
+        okText="保存"
+        cancelText="取消"
+        width={640}
+        destroyOnClose
+      >
+        <Form
+          form={form}
+          layout="vertical"
+          preserve={false}
+          initialValues={editing && editing !== 'new' ? { slug: editing.slug, title: editing.title, description: editing.description, coverImage: editing.coverImage } : undefined}
+        >
+          <Form.Item
+            name="title"
+            label="标题"
+            rules={[{ required: true, message: '请输入标题' }, { max: 200, message: '标题不超过 200 个字符' }]}
+          >
+            <Input placeholder="如：思源笔记入门教程" />
+          </Form.Item>
+          <Form.Item
+            name="slug"
+            label="地址"
+            extra="合集首页为 /c/地址，只能包含小写字母、数字与连字符；留空时由标题生成（中文标题生成一段短字符）"
+            rules={[{ pattern: /^[a-z0-9][a-z0-9-]{0,63}$/, message: '只能包含小写字母、数字与连字符，不超过 64 个字符' }]}
+          >
+            <Input addonBefore="/c/" placeholder="留空自动生成，如 siyuan-tutorial" />
+          </Form.Item>
+          <Form.Item name="description" label="描述">
+            <Input.TextArea rows={3} maxLength={2000} showCount />
+          </Form.Item>
+          <Form.Item name="coverImage" label="封面图片" extra="图片地址，留空时使用第一篇分享中的图片">
+            <Input placeholder="https://" />
+          </Form.Item>
+        </Form>
+        <div style={{ marginBottom: 8 }}>分享（按顺序展示）</div>
+        <Select
+          style={{ width: '100%', marginBottom: 12 }}
+          placeholder="添加分享"
+          showSearch
+          optionFilterProp="label"
+          value={null}
+          options={shares.filter(s => !shareIds.includes(s.id)).map(s => ({ value: s.id, label: s.docTitle }))}
+          onChange={(id: string | null) => id && setShareIds([...shareIds, id])}
+        />
+        <List
+          size="small"
+          bordered
+          dataSource={shareIds}
+          locale={{ emptyText: '尚未添加分享' }}
+          renderItem={(id, index) => (
+            <List.Item
+              actions={[
+                <Button key="up" type="text" size="small" icon={<ArrowUpOutlined />} disabled={index === 0} onClick={() => move(index, -1)} />,
+                <Button key="down" type="text" size="small" icon={<ArrowDownOutlined />} disabled={index === shareIds.length - 1} onClick={() => move(index, 1)} />,
+                <Button key="remove" type="text" size="small" icon={<CloseOutlined />} onClick={() => setShareIds(shareIds.filter(s => s !== id))} />,
+              ]}
+            >
+              <Text ellipsis>{index + 1}. {titleOf(id)}</Text>
+            </List.Item>
+          )}
+        />
+      </Modal>
+    </div>
+  )
+}
+
+export default CollectionList
@@ -0,0 +1,110 @@
+import { LockOutlined, QuestionCircleOutlined } from '@ant-design/icons'
+import { Button, Result, Spin, Typography } from 'antd'
+import { useEffect, useState } from 'react'
+import { useNavigate, useParams } from 'react-router-dom'
+import { askCollection, getCollection, type PublicCollection } from '../api/share'
+import AskModal from '../components/AskModal'
+import './CollectionView.css'
+
+const { Title, Paragraph, Text } = Typography
+
+function CollectionView() {
+  const { slug } = useParams<{ slug: string }>()
+  const navigate = useNavigate()
+  const [loading, setLoading] = useState(true)
+  const [error, setError] = useState<string | null>(null)
+  const [collection, setCollection] = useState<PublicCollection | null>(null)
+  const [askOpen, setAskOpen] = useState(false)
+
+  useEffect(() => {
+    if (!slug) return
+    setLoading(true)
+    setError(null)
+    getCollection(slug)
+      .then(res => {
+        if (res.code === 0 && res.data) {
+          setCollection(res.data)
+        } else {
+          setError(res.msg || '加载失败')
+        }
+      })
+      .catch((err: any) => setError(err.response?.data?.msg || err.message || '加载失败'))
+      .finally(() => setLoading(false))
+  }, [slug])
+
+  useEffect(() => {
+    if (collection) {
+      document.title = collection.title
+    }
+  }, [collection])
+
+  if (loading) {
+    return (
+      <div className="collection-view-loading">
+        <Spin size="large" />
+      </div>
+    )
+  }
+
+  if (error || !collection) {
+    return (
+      <div className="collection-view-error">
+        <Result
+          status="404"
+          title="合集不可用"
+          subTitle={error}
+          extra={<Button type="primary" onClick={() => navigate('/')}>返回首页</Button>}
+        />
+      </div>
+    )
+  }
+
+  return (
+    <div className="collection-view">
+      {collection.coverImage && (
+        <div className="collection-cover" style={{ backgroundImage: `url("${collection.coverImage.replace(/"/g, '%22')}")` }} />
+      )}
+      <header className="collection-header">
+        <Title level={1}>{collection.title}</Title>
+        {collection.description && <Paragraph className="collection-description">{collection.description}</Paragraph>}
+        <Text type="secondary">
+          {collection.author} · 共 {collection.items.length} 篇 · 更新于 {new Date(collection.updatedAt).toLocaleDateString()}
+        </Text>
+        {collection.askEnabled && (
+          <div>
+            <Button icon={<QuestionCircleOutlined />} onClick={() => setAskOpen(true)} style={{ marginTop: 12 }}>
+              向合集提问
+            </Button>
+          </div>
+        )}
+      </header>
+      <ol className="collection-items">
+        {collection.items.map((item, index) => (
+          <li key={item.id} className="collection-item">
+            <a href={item.url}>
+              <span className="collection-item-index">{String(index + 1).padStart(2, '0')}</span>
+              <span className="collection-item-body">
+                <span className="collection-item-title">
+                  {item.docTitle}
+                  {item.locked && <LockOutlined className="collection-item-lock" />}
+                </span>
+                {item.summary && <span className="collection-item-summary">{item.summary}</span>}
+              </span>
+            </a>
+          </li>
+        ))}
+      </ol>
+      {collection.items.length === 0 && <Paragraph type="secondary" style={{ textAlign: 'center' }}>合集中暂无可访问的分享</Paragraph>}
+      {collection.askEnabled && (
+        <AskModal
+          open={askOpen}
+          title={`提问 · ${collection.title}`}
+          ask={(q) => askCollection(collection.slug, q)}
+          onClose={() => setAskOpen(false)}
+        />
+      )}
+    </div>
+  )
+}
+
+export default CollectionView
@@ -1,18 +1,47 @@
-import { ApiOutlined, CopyOutlined, DeleteOutlined, HomeOutlined, PlusOutlined, ReloadOutlined, ShareAltOutlined, UserOutlined } from '@ant-design/icons'
-import { Button, Card, Divider, Form, Input, message, Modal, Space, Table, Tag, Typography } from 'antd'
+import { ApiOutlined, BellOutlined, BookOutlined, CopyOutlined, DeleteOutlined, GlobalOutlined, HomeOutlined, PlusOutlined, ReloadOutlined, ShareAltOutlined, UserOutlined } from '@ant-design/icons'
+import { Button, Card, Divider, Form, Input, message, Modal, Progress, Space, Table, Tag, Typography } from 'antd'
 import { useEffect, useState } from 'react'
 import { useNavigate } from 'react-router-dom'
 import api from '../api'
+import { useLiveEvents } from '../api/events'
+import { enableDashboardPush, pushSupported } from '../api/push'
+import AccountCard from '../components/AccountCard'
+import AnnouncementBanner from '../components/AnnouncementBanner'
+import AnnouncementsCard from '../components/AnnouncementsCard'
+import DonationCard from '../components/DonationCard'
+import NotificationsCard from '../components/NotificationsCard'
+import ReportsCard from '../components/ReportsCard'
+import SessionsCard from '../components/SessionsCard'
+import SQLConsoleCard from '../components/SQLConsoleCard'
+import TemplatesCard from '../components/TemplatesCard'
+import TwoFactorCard from '../components/TwoFactorCard'
 
 const { Title, Text, Paragraph } = Typography
 
 interface ApiResp<T = any> { code: number; msg: string; data: T }
-interface TokenItem { id: string; name: string; revoked: boolean; createdAt: string; lastUsedAt?: string }
+interface TokenItem { id: string; name: string; revoked: boolean; createdAt: string; lastUsedAt?: string; requestCount?: number; lastUsedIp?: string; lastUserAgent?: string; allowedCidrs?: string[] }
+interface UsageInfo {
+  usage: { bytes: number; assets: number; shares: number; bandwidth: number }
+  quota: { maxBytes: number; maxShares: number; maxAssetBytes: number; maxBandwidth: number }
+}
+interface APIUsageTotal { requests: number; publishes: number; views: number; bytes: number }
+
+const formatBytes = (n: number) => {
+  const units = ['B', 'KB', 'MB', 'GB', 'TB']
+  let i = 0
+  while (n >= 1024 && i < units.length - 1) {
+    n /= 1024
+    i++
+  }
+  return `${i === 0 ? n : n.toFixed(1)} ${units[i]}`
+}
 
 function Dashboard() {
   const navigate = useNavigate()
   const [user, setUser] = useState<any>(null)
   const [tokens, setTokens] = useState<TokenItem[]>([])
+  const [usage, setUsage] = useState<UsageInfo | null>(null)
+  const [apiUsage, setApiUsage] = useState<APIUsageTotal | null>(null)
   const [loading, setLoading] = useState(true)
   const [actionLoading, setActionLoading] = useState<string>('')
   const [createModalOpen, setCreateModalOpen] = useState(false)
@@ -26,6 +55,10 @@ function Dashboard() {
       if (me.code === 0) setUser(me.data)
       const list = await api.get('/api/token/list') as ApiResp<{ items: TokenItem[] }>
       if (list.code === 0) setTokens(list.data.items || [])
+      const u = await api.get('/api/me/usage') as ApiResp<UsageInfo>
+      if (u.code === 0) setUsage(u.data)
+      const au = await api.get('/api/me/api-usage', { params: { days: 30 } }) as ApiResp<{ total: APIUsageTotal }>
+      if (au.code === 0) setApiUsage(au.data.total)
     } catch (e: any) {
       message.error(e.message || '加载失败')
     } finally {
@@ -35,10 +68,39 @@ function Dashboard() {
 
   useEffect(() => { loadAll() }, [])
 
+  // 实时更新：API Token 被使用或插件发布分享时刷新令牌列表与用量，不显示加载状态
+  const refreshLive = async () => {
+    try {
+      const list = await api.get('/api/token/list') as ApiResp<{ items: TokenItem[] }>
+      if (list.code === 0) setTokens(list.data.items || [])
+      const u = await api.get('/api/me/usage') as ApiResp<UsageInfo>
+      if (u.code === 0) setUsage(u.data)
+      const au = await api.get('/api/me/api-usage', { params: { days: 30 } }) as ApiResp<{ total: APIUsageTotal }>
+      if (au.code === 0) setApiUsage(au.data.total)
+    } catch {}
+  }
+  useLiveEvents(ev => {
+    if (ev.type === 'token_used' || ev.type === 'share_published') refreshLive()
+  }, refreshLive)
+
+  const enablePush = async () => {
+    setActionLoading('push')
+    try {
+      const sub = await enableDashboardPush()
+      await api.post(`/api/push/subscriptions/${sub.id}/test`)
+      message.success('浏览器通知已开启')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '开启通知失败')
+    } finally {
+      setActionLoading('')
+    }
+  }
+
   const createToken = async (values: any) => {
     setActionLoading('create')
     try {
-      const res = await api.post('/api/token/create', values) as ApiResp<any>
+      const allowedCidrs = (values.allowedCidrs || '').split(/[\s,，]+/).filter(Boolean)
+      const res = await api.post('/api/token/create', { name: values.name, allowedCidrs }) as ApiResp<any>
       if (res.code === 0) {
         setNewTokenData({ name: res.data.name, token: res.data.token })
         message.success('Token 创建成功')
@@ -133,6 +195,30 @@ function Dashboard() {
       key: 'lastUsedAt',
       render: (time?: string) => time ? new Date(time).toLocaleString('zh-CN') : '-'
     },
+    {
+      title: '来源限制',
+      dataIndex: 'allowedCidrs',
+      key: 'allowedCidrs',
+      render: (cidrs?: string[]) => cidrs?.length ? (
+        <Space size={[0, 4]} wrap>
+          {cidrs.map(c => <Tag key={c}>{c}</Tag>)}
+        </Space>
+      ) : <Text type="secondary">不限</Text>
+    },
+    {
+      title: '使用情况',
+      key: 'usage',
+      render: (_: any, record: TokenItem) => record.requestCount ? (
+        <Space direction="vertical" size={0}>
+          <Text>{record.requestCount} 次请求{record.lastUsedIp ? ` · ${record.lastUsedIp}` : ''}</Text>
+          {record.lastUserAgent && (
+            <Text type="secondary" ellipsis={{ tooltip: record.lastUserAgent }} style={{ maxWidth: 240, fontSize: 12 }}>
+              {record.lastUserAgent}
+            </Text>
+          )}
+        </Space>
+      ) : '-'
+    },
     {
       title: '操作',
       key: 'action',
@@ -186,6 +272,7 @@ function Dashboard() {
 
   return (
     <div style={{ maxWidth: 1200, margin: '60px auto', padding: '0 24px' }}>
+      <AnnouncementBanner scope="dashboard" style={{ marginBottom: 24 }} />
       <div style={{ marginBottom: 32 }}>
         <Space size="middle" style={{ width: '100%', justifyContent: 'space-between' }}>
           <Title level={2} style={{ margin: 0 }}>
@@ -196,6 +283,17 @@ function Dashboard() {
             <Button icon={<ShareAltOutlined />} onClick={() => navigate('/shares')}>
               分享管理
             </Button>
+            <Button icon={<BookOutlined />} onClick={() => navigate('/collections')}>
+              合集管理
+            </Button>
+            <Button icon={<GlobalOutlined />} onClick={() => navigate('/domains')}>
+              自定义域名
+            </Button>
+            {pushSupported() && (
+              <Button icon={<BellOutlined />} loading={actionLoading === 'push'} onClick={enablePush}>
+                开启通知
+              </Button>
+            )}
             <Button icon={<HomeOutlined />} href="/">返回首页</Button>
           </Space>
         </Space>
@@ -215,9 +313,71 @@ function Dashboard() {
           <Text><Text strong>用户名：</Text>{user.username}</Text>
           <Text><Text strong>邮箱：</Text>{user.email}</Text>
           <Text type="secondary"><Text strong>创建时间：</Text>{new Date(user.createdAt).toLocaleString('zh-CN')}</Text>
+          {usage && (
+            <>
+              <Text>
+                <Text strong>存储用量：</Text>
+                {formatBytes(usage.usage.bytes)}
+                {usage.quota.maxBytes > 0 ? ` / ${formatBytes(usage.quota.maxBytes)}` : '（不限）'}
+                ，{usage.usage.assets} 个资源文件
+              </Text>
+              {usage.quota.maxBytes > 0 && (
+                <Progress
+                  percent={Math.min(100, Math.round(usage.usage.bytes / usage.quota.maxBytes * 100))}
+                  size="small"
+                  style={{ width: 320 }}
+                />
+              )}
+              <Text>
+                <Text strong>分享数：</Text>
+                {usage.usage.shares}
+                {usage.quota.maxShares > 0 ? ` / ${usage.quota.maxShares}` : '（不限）'}
+              </Text>
+              {usage.quota.maxAssetBytes > 0 && (
+                <Text type="secondary"><Text strong>单个资源上限：</Text>{formatBytes(usage.quota.maxAssetBytes)}</Text>
+              )}
+              <Text>
+                <Text strong>本月读者流量：</Text>
+                {formatBytes(usage.usage.bandwidth)}
+                {usage.quota.maxBandwidth > 0 ? ` / ${formatBytes(usage.quota.maxBandwidth)}` : '（不限）'}
+              </Text>
+              {usage.quota.maxBandwidth > 0 && (
+                <Progress
+                  percent={Math.min(100, Math.round(usage.usage.bandwidth / usage.quota.maxBandwidth * 100))}
+                  size="small"
+                  status={usage.usage.bandwidth >= usage.quota.maxBandwidth ? 'exception' : undefined}
+                  style={{ width: 320 }}
+                />
+              )}
+            </>
+          )}
+          {apiUsage && (
+            <Text>
+              <Text strong>近 30 天：</Text>
+              {apiUsage.requests} 次接口请求，{apiUsage.publishes} 次发布，分享被浏览 {apiUsage.views} 次，流量 {formatBytes(apiUsage.bytes)}
+            </Text>
+          )}
         </Space>
       </Card>
 
+      <AccountCard user={user} onChange={loadAll} />
+
+      <DonationCard donation={user.donation} onChange={loadAll} />
+
+      <TemplatesCard />
+
+      <NotificationsCard />
+
+      <TwoFactorCard enabled={!!user.totpEnabled} onChange={loadAll} />
+
+      <SessionsCard />
+
+      {user.isAdmin && <ReportsCard />}
+
+      {user.isAdmin && <AnnouncementsCard />}
+
+      {user.isAdmin && <SQLConsoleCard />}
+
       <Card
         title={
           <Space>
@@ -267,6 +427,13 @@ function Dashboard() {
           >
             <Input placeholder="例如：思源插件 Token" />
           </Form.Item>
+          <Form.Item
+            name="allowedCidrs"
+            label="允许的来源地址（可选）"
+            extra="IP 或 CIDR 网段，多个以逗号或换行分隔；留空表示不限制"
+          >
+            <Input.TextArea autoSize={{ minRows: 2, maxRows: 6 }} placeholder={'203.0.113.7\n198.51.100.0/24'} />
+          </Form.Item>
           <Form.Item>
             <Space>
               <Button type="primary" htmlType="submit" loading={actionLoading === 'create'}>
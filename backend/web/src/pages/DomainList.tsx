@@ -0,0 +1,220 @@
+import { ArrowLeftOutlined, CheckCircleOutlined, DeleteOutlined, EditOutlined, PlusOutlined, SafetyCertificateOutlined } from '@ant-design/icons'
+import { Button, Card, Form, Input, message, Modal, Space, Table, Tag, Typography } from 'antd'
+import type { ColumnsType } from 'antd/es/table'
+import { useEffect, useState } from 'react'
+import { useNavigate } from 'react-router-dom'
+import { createDomain, deleteDomain, listDomains, updateDomain, verifyDomain, type CustomDomain } from '../api/share'
+
+const { Title, Text, Paragraph } = Typography
+
+function DomainList() {
+  const navigate = useNavigate()
+  const [domains, setDomains] = useState<CustomDomain[]>([])
+  const [loading, setLoading] = useState(true)
+  const [editing, setEditing] = useState<CustomDomain | 'new' | null>(null)
+  const [saving, setSaving] = useState(false)
+  const [verifying, setVerifying] = useState<string | null>(null)
+  const [form] = Form.useForm<{ domain: string; homePath: string }>()
+
+  const load = async () => {
+    setLoading(true)
+    try {
+      const res = await listDomains()
+      if (res.code === 0) {
+        setDomains(res.data.items || [])
+      } else {
+        message.error(res.msg || '加载失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '加载失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => {
+    load()
+  }, [])
+
+  const handleSave = async () => {
+    const values = await form.validateFields()
+    setSaving(true)
+    try {
+      const res = editing && editing !== 'new'
+        ? await updateDomain(editing.id, values.homePath || '')
+        : await createDomain(values.domain, values.homePath || '')
+      if (res.code === 0) {
+        message.success(editing === 'new' ? '已添加，请按提示添加 TXT 记录后验证' : '已保存')
+        setEditing(null)
+        load()
+      } else {
+        message.error(res.msg || '保存失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '保存失败')
+    } finally {
+      setSaving(false)
+    }
+  }
+
+  const handleVerify = async (record: CustomDomain) => {
+    setVerifying(record.id)
+    try {
+      const res = await verifyDomain(record.id)
+      if (res.code === 0) {
+        message.success('验证成功')
+        load()
+      } else {
+        message.error(res.msg || '验证失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '验证失败')
+    } finally {
+      setVerifying(null)
+    }
+  }
+
+  const handleDelete = (record: CustomDomain) => {
+    Modal.confirm({
+      title: '确认解绑',
+      content: `确定要解绑域名"${record.domain}"吗？`,
+      okText: '解绑',
+      okType: 'danger',
+      cancelText: '取消',
+      onOk: async () => {
+        try {
+          const res = await deleteDomain(record.id)
+          if (res.code === 0) {
+            message.success('已解绑')
+            load()
+          } else {
+            message.error(res.msg || '解绑失败')
+          }
+        } catch (e: any) {
+          message.error(e.response?.data?.msg || e.message || '解绑失败')
+        }
+      }
+    })
+  }
+
+  const columns: ColumnsType<CustomDomain> = [
+    {
+      title: '域名',
+      dataIndex: 'domain',
+      key: 'domain',
+      render: (domain: string, record) => (
+        <div>
+          <Space>
+            <Text strong>{domain}</Text>
+            {record.verified ? <Tag color="success" icon={<CheckCircleOutlined />}>已验证</Tag> : <Tag color="warning">待验证</Tag>}
+          </Space>
+          {!record.verified && (
+            <Paragraph type="secondary" style={{ margin: '8px 0 0', fontSize: 12 }}>
+              添加 TXT 记录：<Text code copyable>{record.recordName}</Text> 值为 <Text code copyable>{record.recordValue}</Text>
+            </Paragraph>
+          )}
+        </div>
+      )
+    },
+    {
+      title: '首页',
+      dataIndex: 'homePath',
+      key: 'homePath',
+      width: 220,
+      render: (homePath: string) => homePath ? <Text code>{homePath}</Text> : <Text type="secondary">未设置</Text>
+    },
+    {
+      title: '操作',
+      key: 'action',
+      width: 260,
+      render: (record: CustomDomain) => (
+        <Space size="small">
+          {!record.verified && (
+            <Button type="link" size="small" icon={<SafetyCertificateOutlined />} loading={verifying === record.id} onClick={() => handleVerify(record)}>
+              验证
+            </Button>
+          )}
+          <Button type="link" size="small" icon={<EditOutlined />} onClick={() => setEditing(record)}>
+            首页
+          </Button>
+          <Button type="link" size="small" danger icon={<DeleteOutlined />} onClick={() => handleDelete(record)}>
+            解绑
+          </Button>
+        </Space>
+      )
+    }
+  ]
+
+  return (
+    <div style={{ maxWidth: 1200, margin: '60px auto', padding: '0 24px' }}>
+      <Card>
+        <div style={{ marginBottom: 24, display: 'flex', alignItems: 'center', justifyContent: 'space-between' }}>
+          <div style={{ display: 'flex', alignItems: 'center', gap: 16 }}>
+            <Button icon={<ArrowLeftOutlined />} onClick={() => navigate('/dashboard')}>
+              返回仪表盘
+            </Button>
+            <Title level={3} style={{ margin: 0 }}>
+              自定义域名
+            </Title>
+          </div>
+          <Button type="primary" icon={<PlusOutlined />} onClick={() => setEditing('new')}>
+            绑定域名
+          </Button>
+        </div>
+
+        <Table
+          dataSource={domains}
+          columns={columns}
+          rowKey="id"
+          loading={loading}
+          pagination={false}
+          locale={{
+            emptyText: (
+              <div style={{ padding: '40px 0', color: 'rgba(0,0,0,0.25)' }}>
+                <div style={{ fontSize: 48, marginBottom: 16 }}>🌐</div>
+                <div>暂无自定义域名，绑定后可通过自己的域名访问分享与合集</div>
+              </div>
+            )
+          }}
+        />
+      </Card>
+
+      <Modal
+        title={editing === 'new' ? '绑定域名' : '修改首页'}
+        open={editing !== null}
+        onCancel={() => setEditing(null)}
+        onOk={handleSave}
+        confirmLoading={saving}
+        okText="保存"
+        cancelText="取消"
+        destroyOnClose
+      >
+        <Form
+          form={form}
+          layout="vertical"
+          preserve={false}
+          initialValues={editing && editing !== 'new' ? { domain: editing.domain, homePath: editing.homePath } : undefined}
+        >
+          <Form.Item
+            name="domain"
+            label="域名"
+            extra="域名需解析到本服务，添加后按提示在 DNS 中添加 TXT 记录完成验证"
+            rules={[{ required: true, message: '请输入域名' }]}
+          >
+            <Input placeholder="notes.example.com" disabled={editing !== 'new'} />
+          </Form.Item>
+          <Form.Item
+            name="homePath"
+            label="首页"
+            extra="访问域名根路径时跳转的页面，如合集首页 /c/地址"
+            rules={[{ pattern: /^\/(?!\/)/, message: '须为以 / 开头的站内路径' }]}
+          >
+            <Input placeholder="/c/siyuan-tutorial" />
+          </Form.Item>
+        </Form>
+      </Modal>
+    </div>
+  )
+}
+
+export default DomainList
@@ -0,0 +1,356 @@
+import { ArrowLeftOutlined, DownloadOutlined, ExclamationCircleOutlined, ReloadOutlined } from '@ant-design/icons'
+import { Button, Input, Progress, Result, Space, Spin, Typography } from 'antd'
+import 'github-markdown-css/github-markdown-light.css'
+import 'highlight.js/styles/github.css'
+import { useCallback, useEffect, useState } from 'react'
+import ReactMarkdown from 'react-markdown'
+import { useLocation, useNavigate, useParams } from 'react-router-dom'
+import rehypeHighlight from 'rehype-highlight'
+import rehypeRaw from 'rehype-raw'
+import remarkGfm from 'remark-gfm'
+import { Flashcard, FlashcardDeck, FlashcardScheduler, getFlashcards } from '../api/share'
+import './FlashcardView.css'
+import './ShareView.css'
+
+const { Title, Text } = Typography
+
+// 单张卡片的复习状态：step >= 0 表示处于学习阶段（对应 learningSteps 下标），-1 表示已进入复习
+interface CardState {
+  ease: number
+  interval: number // 天
+  due: number // 下次到期时间戳（毫秒）
+  reps: number
+  lapses: number
+  step: number
+}
+
+type DeckProgress = Record<string, CardState>
+type Grade = 0 | 1 | 2 | 3
+
+const MINUTE = 60 * 1000
+const DAY = 24 * 60 * MINUTE
+// 每轮最多引入的新卡数
+const NEW_PER_SESSION = 20
+
+const grades: { grade: Grade; label: string; key: string }[] = [
+  { grade: 0, label: '重来', key: '1' },
+  { grade: 1, label: '困难', key: '2' },
+  { grade: 2, label: '良好', key: '3' },
+  { grade: 3, label: '简单', key: '4' },
+]
+
+const storageKey = (shareId: string) => `siyuan-share:flashcards:${shareId}`
+
+const loadProgress = (shareId: string): DeckProgress => {
+  try {
+    return JSON.parse(localStorage.getItem(storageKey(shareId)) || '{}')
+  } catch {
+    return {}
+  }
+}
+
+// SM-2：学习阶段按分钟间隔推进，毕业后按难度系数放大间隔，遗忘时重新学习并降低难度系数
+const schedule = (prev: CardState | undefined, grade: Grade, s: FlashcardScheduler, now: number): CardState => {
+  const state: CardState = prev
+    ? { ...prev }
+    : { ease: s.initialEase, interval: 0, due: now, reps: 0, lapses: 0, step: 0 }
+  state.reps++
+  const steps = s.learningSteps.length > 0 ? s.learningSteps : [1]
+
+  if (state.step >= 0) {
+    if (grade === 3) {
+      state.step = -1
+      state.interval = Math.max(state.interval, s.easyInterval)
+    } else if (grade === 0) {
+      state.step = 0
+    } else if (grade === 2) {
+      state.step++
+      if (state.step >= steps.length) {
+        state.step = -1
+        state.interval = Math.max(state.interval, s.graduatingInterval)
+      }
+    }
+    state.due = state.step >= 0 ? now + steps[state.step] * MINUTE : now + state.interval * DAY
+    return state
+  }
+
+  switch (grade) {
+    case 0:
+      state.lapses++
+      state.ease = Math.max(s.minEase, state.ease - 0.2)
+      state.interval = Math.max(1, Math.round(state.interval * 0.5))
+      state.step = 0
+      state.due = now + steps[0] * MINUTE
+      return state
+    case 1:
+      state.ease = Math.max(s.minEase, state.ease - 0.15)
+      state.interval = Math.max(state.interval + 1, Math.round(state.interval * 1.2))
+      break
+    case 2:
+      state.interval = Math.max(state.interval + 1, Math.round(state.interval * state.ease))
+      break
+    case 3:
+      state.ease += 0.15
+      state.interval = Math.max(state.interval + 1, Math.round(state.interval * state.ease * 1.3))
+      break
+  }
+  state.due = now + state.interval * DAY
+  return state
+}
+
+const formatInterval = (ms: number) => {
+  if (ms < 60 * MINUTE) return `${Math.max(1, Math.round(ms / MINUTE))} 分钟`
+  if (ms < DAY) return `${Math.round(ms / (60 * MINUTE))} 小时`
+  if (ms < 30 * DAY) return `${Math.round(ms / DAY)} 天`
+  if (ms < 365 * DAY) return `${Math.round(ms / (30 * DAY))} 个月`
+  return `${(ms / (365 * DAY)).toFixed(1)} 年`
+}
+
+// 本轮待复习队列：先到期的复习卡，再补充新卡
+const buildQueue = (cards: Flashcard[], progress: DeckProgress, now: number) => {
+  const due = cards
+    .filter(c => progress[c.id] && progress[c.id].due <= now)
+    .sort((a, b) => progress[a.id].due - progress[b.id].due)
+  const fresh = cards.filter(c => !progress[c.id]).slice(0, NEW_PER_SESSION)
+  return [...due, ...fresh].map(c => c.id)
+}
+
+const Markdown = ({ children }: { children: string }) => (
+  <div className="markdown-body">
+    <ReactMarkdown remarkPlugins={[remarkGfm]} rehypePlugins={[rehypeRaw, rehypeHighlight]}>
+      {children}
+    </ReactMarkdown>
+  </div>
+)
+
+// 闪卡练习页：复习进度保存在浏览器本地，不上传服务端
+function FlashcardView() {
+  const { shareId } = useParams<{ shareId: string }>()
+  const navigate = useNavigate()
+  const location = useLocation()
+  const [loading, setLoading] = useState(true)
+  const [error, setError] = useState<string | null>(null)
+  const [requirePassword, setRequirePassword] = useState(false)
+  const [password, setPassword] = useState<string>((location.state as { password?: string } | null)?.password || '')
+  const [passwordError, setPasswordError] = useState('')
+  const [deck, setDeck] = useState<FlashcardDeck | null>(null)
+  const [progress, setProgress] = useState<DeckProgress>({})
+  const [queue, setQueue] = useState<string[]>([])
+  const [total, setTotal] = useState(0)
+  const [revealed, setRevealed] = useState(false)
+  const [cram, setCram] = useState(false)
+
+  const startSession = useCallback((d: FlashcardDeck, p: DeckProgress, all = false) => {
+    const q = all ? d.cards.map(c => c.id) : buildQueue(d.cards, p, Date.now())
+    setQueue(q)
+    setTotal(q.length)
+    setRevealed(false)
+    setCram(all)
+  }, [])
+
+  const load = async (pwd?: string) => {
+    if (!shareId) return
+    setLoading(true)
+    setError(null)
+    setPasswordError('')
+    try {
+      const res = await getFlashcards(shareId, pwd)
+      if (res.code === 0 && res.data) {
+        const p = loadProgress(shareId)
+        setDeck(res.data)
+        setProgress(p)
+        setRequirePassword(false)
+        startSession(res.data, p)
+      } else {
+        setError(res.msg || '加载失败')
+      }
+    } catch (err: any) {
+      const msg = err.response?.data?.msg || err.message || '加载失败'
+      const key = err.response?.data?.msgKey || msg
+      if (key.includes('Password required')) {
+        setRequirePassword(true)
+      } else if (key.includes('Invalid password')) {
+        setRequirePassword(true)
+        setPasswordError('密码错误')
+      } else {
+        setError(msg)
+      }
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  useEffect(() => { load(password || undefined) }, [shareId])
+
+  const current = deck && queue.length > 0 ? deck.cards.find(c => c.id === queue[0]) : undefined
+
+  const rate = useCallback((grade: Grade) => {
+    if (!deck || !current || !shareId) return
+    const rest = queue.slice(1)
+    if (cram) {
+      // 自由练习不改变复习计划，选“重来”的卡片放回队尾
+      setQueue(grade === 0 ? [...rest, current.id] : rest)
+      setRevealed(false)
+      return
+    }
+    const now = Date.now()
+    const next = schedule(progress[current.id], grade, deck.scheduler, now)
+    const updated = { ...progress, [current.id]: next }
+    setProgress(updated)
+    localStorage.setItem(storageKey(shareId), JSON.stringify(updated))
+    // 仍处于学习阶段的卡片在本轮稍后再次出现
+    setQueue(next.step >= 0 ? [...rest, current.id] : rest)
+    setRevealed(false)
+  }, [deck, current, queue, progress, cram, shareId])
+
+  useEffect(() => {
+    const onKey = (e: KeyboardEvent) => {
+      if (!current || (e.target as HTMLElement)?.tagName === 'INPUT') return
+      if (!revealed && (e.key === ' ' || e.key === 'Enter')) {
+        e.preventDefault()
+        setRevealed(true)
+        return
+      }
+      const g = grades.find(g => g.key === e.key)
+      if (revealed && g) rate(g.grade)
+    }
+    window.addEventListener('keydown', onKey)
+    return () => window.removeEventListener('keydown', onKey)
+  }, [current, revealed, rate])
+
+  const resetProgress = () => {
+    if (!deck || !shareId) return
+    localStorage.removeItem(storageKey(shareId))
+    setProgress({})
+    startSession(deck, {})
+  }
+
+  const tsvUrl = () => {
+    const params = new URLSearchParams({ format: 'tsv' })
+    if (password) params.set('password', password)
+    return `/api/s/${shareId}/flashcards?${params}`
+  }
+
+  if (loading) {
+    return (
+      <div className="share-view-loading">
+        <Spin size="large" tip="加载中..." />
+      </div>
+    )
+  }
+
+  if (requirePassword) {
+    return (
+      <div className="share-view-password">
+        <div className="password-card">
+          <Title level={3}>此分享需要密码</Title>
+          <form onSubmit={e => { e.preventDefault(); if (password.trim()) load(password) }}>
+            <Input.Password
+              size="large"
+              value={password}
+              onChange={e => setPassword(e.target.value)}
+              placeholder="请输入访问密码"
+              status={passwordError ? 'error' : ''}
+            />
+            {passwordError && <Text type="danger">{passwordError}</Text>}
+            <Button type="primary" htmlType="submit" size="large" block style={{ marginTop: '16px' }}>
+              开始练习
+            </Button>
+          </form>
+        </div>
+      </div>
+    )
+  }
+
+  if (error || !deck) {
+    return (
+      <div className="share-view-error">
+        <Result
+          icon={<ExclamationCircleOutlined />}
+          status="error"
+          title="加载失败"
+          subTitle={error}
+          extra={<Button onClick={() => navigate(`/s/${shareId}`)}>查看文档</Button>}
+        />
+      </div>
+    )
+  }
+
+  const now = Date.now()
+  const learned = deck.cards.filter(c => progress[c.id]).length
+  const nextDue = deck.cards
+    .map(c => progress[c.id]?.due)
+    .filter((d): d is number => d !== undefined && d > now)
+    .sort((a, b) => a - b)[0]
+
+  return (
+    <div className="flashcard-view">
+      <div className="flashcard-header">
+        <Button type="text" icon={<ArrowLeftOutlined />} onClick={() => navigate(`/s/${shareId}`)}>
+          文档
+        </Button>
+        <Title level={3} className="flashcard-title">{deck.deck.name}</Title>
+        <Text type="secondary">已学 {learned} / {deck.cards.length}</Text>
+      </div>
+
+      {current ? (
+        <>
+          <Progress
+            percent={total ? Math.round((total - queue.length) / total * 100) : 0}
+            size="small"
+            format={() => `剩余 ${queue.length}`}
+          />
+          <div className="flashcard-card">
+            <Markdown>{current.front}</Markdown>
+            {revealed && current.back && (
+              <>
+                <div className="flashcard-divider" />
+                <Markdown>{current.back}</Markdown>
+              </>
+            )}
+          </div>
+          <div className="flashcard-actions">
+            {!revealed ? (
+              <Button type="primary" size="large" onClick={() => setRevealed(true)}>
+                显示答案（空格）
+              </Button>
+            ) : (
+              <Space wrap>
+                {grades.map(g => (
+                  <Button key={g.grade} size="large" className={`flashcard-grade grade-${g.grade}`} onClick={() => rate(g.grade)}>
+                    <span>{g.label}</span>
+                    {!cram && (
+                      <Text type="secondary" className="flashcard-interval">
+                        {formatInterval(schedule(progress[current.id], g.grade, deck.scheduler, now).due - now)}
+                      </Text>
+                    )}
+                  </Button>
+                ))}
+              </Space>
+            )}
+          </div>
+          {revealed && <Text type="secondary" className="flashcard-hint">快捷键 1–4 评分</Text>}
+        </>
+      ) : (
+        <Result
+          status="success"
+          title={cram ? '本轮练习完成' : '今日复习完成'}
+          subTitle={nextDue ? `下一张卡片将在 ${formatInterval(nextDue - now)}后到期` : undefined}
+          extra={[
+            <Button key="cram" type="primary" onClick={() => startSession(deck, progress, true)}>
+              自由练习全部卡片
+            </Button>,
+            <Button key="reset" icon={<ReloadOutlined />} onClick={resetProgress}>
+              重置进度
+            </Button>,
+            <Button key="tsv" icon={<DownloadOutlined />} href={tsvUrl()}>
+              导出 TSV（Anki）
+            </Button>,
+          ]}
+        />
+      )}
+    </div>
+  )
+}
+
+export default FlashcardView
@@ -1,4 +1,4 @@
-import { ApiOutlined, DashboardOutlined, LockOutlined, LogoutOutlined, MailOutlined, UserOutlined } from '@ant-design/icons'
+import { ApiOutlined, DashboardOutlined, KeyOutlined, LockOutlined, LogoutOutlined, MailOutlined, SafetyOutlined, UserOutlined } from '@ant-design/icons'
 import { Button, Card, Divider, Form, Input, Space, Tabs, Tag, Typography, message } from 'antd'
 import { useEffect, useState } from 'react'
 import api from '../api'
@@ -22,12 +22,20 @@ interface ApiResponse<T = any> {
 
 interface LoginResponse { token: string; user: { id: string; username: string; email: string } }
 
+interface OIDCProvider { name: string; displayName: string }
+
 function Home() {
   const [health, setHealth] = useState<HealthData | null>(null)
   const [loading, setLoading] = useState(true)
   const [activeTab, setActiveTab] = useState('status')
   const [sessionUser, setSessionUser] = useState<{ id: string; username: string; email: string } | null>(null)
   const [loadingAction, setLoadingAction] = useState(false)
+  const [oidcProviders, setOidcProviders] = useState<OIDCProvider[]>([])
+  const [otpRequired, setOtpRequired] = useState(false)
+  // 第三方登录的账号启用了两步验证：回调回传的限时令牌，输入验证码后换取会话
+  const [oidcPending, setOidcPending] = useState<{ provider: string; token: string } | null>(null)
+  // 注册方式：open 开放注册 / invite 凭邀请码注册 / closed 关闭注册
+  const [registration, setRegistration] = useState('open')
   const [loginForm] = Form.useForm()
   const [registerForm] = Form.useForm()
 
@@ -53,9 +61,45 @@ function Home() {
     } catch {}
   }
 
+  const loadOIDCProviders = async () => {
+    try {
+      const res = await api.get('/api/auth/oidc') as ApiResponse<{ items: OIDCProvider[] }>
+      if (res.code === 0) setOidcProviders(res.data.items || [])
+    } catch {}
+  }
+
+  const loadRegistration = async () => {
+    try {
+      const res = await api.get('/api/auth/registration') as ApiResponse<{ mode: string }>
+      if (res.code === 0) setRegistration(res.data.mode)
+    } catch {}
+  }
+
+  // 第三方登录回调通过 URL fragment 回传会话令牌、待两步验证的令牌或错误信息
+  const consumeOIDCResult = () => {
+    const params = new URLSearchParams(window.location.hash.slice(1))
+    const token = params.get('oidc_token')
+    const pending = params.get('oidc_2fa')
+    const provider = params.get('oidc_provider')
+    const error = params.get('oidc_error')
+    if (!token && !pending && !error) return
+    window.history.replaceState(null, '', window.location.pathname + window.location.search)
+    if (token) {
+      localStorage.setItem('session_token', token)
+    } else if (pending && provider) {
+      setOidcPending({ provider, token: pending })
+      setActiveTab('login')
+    } else if (error) {
+      message.error(error)
+    }
+  }
+
   useEffect(() => {
+    consumeOIDCResult()
     loadHealth()
     restoreSession()
+    loadOIDCProviders()
+    loadRegistration()
   }, [])
 
   const handleRegister = async (values: any) => {
@@ -63,7 +107,7 @@ function Home() {
     try {
       const res = await api.post('/api/auth/register', values) as ApiResponse
       if (res.code === 0) {
-        message.success('注册成功！请登录')
+        message.success(res.data?.verificationSent ? '注册成功！验证邮件已发送，请查收' : '注册成功！请登录')
         registerForm.resetFields()
         setActiveTab('login')
       } else {
@@ -79,24 +123,48 @@ function Home() {
   const handleLogin = async (values: any) => {
     setLoadingAction(true)
     try {
-      const res = await api.post('/api/auth/login', values) as ApiResponse<LoginResponse>
+      const res = (oidcPending
+        ? await api.post(`/api/auth/oidc/${encodeURIComponent(oidcPending.provider)}/2fa`, { token: oidcPending.token, otp: values.otp })
+        : await api.post('/api/auth/login', values)) as ApiResponse<LoginResponse>
       if (res.code === 0) {
         localStorage.setItem('session_token', res.data.token)
         setSessionUser(res.data.user)
         message.success(`欢迎回来，${res.data.user.username}！`)
         loginForm.resetFields()
+        setOtpRequired(false)
+        setOidcPending(null)
         setActiveTab('status')
       } else {
         message.error(res.msg || '登录失败')
       }
     } catch (e: any) {
+      // 1002：账号已启用两步验证，需输入验证码
+      if (e.response?.data?.code === 1002) {
+        if (otpRequired || oidcPending) message.error('验证码错误')
+        setOtpRequired(true)
+        return
+      }
+      // 1005：连续登录失败，账号被临时锁定
+      if (e.response?.data?.code === 1005) {
+        const until = e.response.data.data?.lockedUntil
+        message.error(`登录失败次数过多，账号已锁定${until ? `至 ${new Date(until).toLocaleString('zh-CN')}` : ''}，可通过邮件中的链接提前解锁`)
+        return
+      }
+      if (e.response?.status === 429) {
+        message.error('登录失败次数过多，请稍后再试')
+        return
+      }
+      // 第三方登录的限时令牌已过期或失效，需重新发起第三方登录
+      if (oidcPending && e.response?.status === 401) setOidcPending(null)
       message.error(e.response?.data?.msg || e.message || '登录失败')
     } finally {
       setLoadingAction(false)
     }
   }
 
-  const handleLogout = () => {
+  const handleLogout = async () => {
+    // 通知服务端注销当前会话，失败时仍清除本地登录态
+    await api.post('/api/auth/logout').catch(() => {})
     localStorage.removeItem('session_token')
     setSessionUser(null)
     message.info('已退出登录')
@@ -158,20 +226,47 @@ function Home() {
         <div style={{ padding: '24px 0', maxWidth: 400, margin: '0 auto' }}>
           <Title level={4} style={{ textAlign: 'center', marginBottom: 24 }}>欢迎回来</Title>
           <Form form={loginForm} onFinish={handleLogin} layout="vertical" size="large">
-            <Form.Item name="username" rules={[{ required: true, message: '请输入用户名' }]}>
-              <Input prefix={<UserOutlined />} placeholder="用户名" />
-            </Form.Item>
-            <Form.Item name="password" rules={[{ required: true, message: '请输入密码' }]}>
-              <Input.Password prefix={<LockOutlined />} placeholder="密码" />
-            </Form.Item>
+            {!oidcPending && (
+              <>
+                <Form.Item name="username" rules={[{ required: true, message: '请输入用户名' }]}>
+                  <Input prefix={<UserOutlined />} placeholder="用户名" />
+                </Form.Item>
+                <Form.Item name="password" rules={[{ required: true, message: '请输入密码' }]}>
+                  <Input.Password prefix={<LockOutlined />} placeholder="密码" />
+                </Form.Item>
+              </>
+            )}
+            {(otpRequired || oidcPending) && (
+              <Form.Item name="otp" rules={[{ required: true, message: '请输入验证码' }]} extra="输入验证器应用中的 6 位验证码，或使用恢复码">
+                <Input prefix={<SafetyOutlined />} placeholder="两步验证码" autoComplete="one-time-code" autoFocus />
+              </Form.Item>
+            )}
             <Form.Item>
               <Button type="primary" htmlType="submit" block loading={loadingAction} size="large">
                 登录
               </Button>
             </Form.Item>
           </Form>
+          {oidcProviders.length > 0 && (
+            <>
+              <Divider plain>或使用以下方式登录</Divider>
+              <Space direction="vertical" style={{ width: '100%' }}>
+                {oidcProviders.map(p => (
+                  <Button key={p.name} block href={`/api/auth/oidc/${p.name}`}>
+                    {p.displayName}
+                  </Button>
+                ))}
+              </Space>
+            </>
+          )}
           <Paragraph style={{ textAlign: 'center', marginTop: 16, color: '#8c8c8c' }}>
-            还没有账号？<a onClick={() => setActiveTab('register')}>立即注册</a>
+            {registration !== 'closed' && (
+              <>
+                还没有账号？<a onClick={() => setActiveTab('register')}>立即注册</a>
+                <Divider type="vertical" />
+              </>
+            )}
+            <a href="/reset-password">忘记密码</a>
           </Paragraph>
         </div>
       )
@@ -209,6 +304,11 @@ function Home() {
             >
               <Input.Password prefix={<LockOutlined />} placeholder="确认密码" />
             </Form.Item>
+            {registration === 'invite' && (
+              <Form.Item name="inviteCode" rules={[{ required: true, message: '请输入邀请码' }]} extra="本站仅限受邀注册，请向管理员索取邀请码">
+                <Input prefix={<KeyOutlined />} placeholder="邀请码" />
+              </Form.Item>
+            )}
             <Form.Item>
               <Button type="primary" htmlType="submit" block loading={loadingAction} size="large">
                 注册
@@ -236,7 +336,7 @@ function Home() {
       </div>
 
       <Card className="home-card" bordered={false}>
-        <Tabs activeKey={activeTab} onChange={setActiveTab} items={sessionUser ? tabItems.filter(item => item.key === 'status') : tabItems} size="large" />
+        <Tabs activeKey={activeTab} onChange={setActiveTab} items={sessionUser ? tabItems.filter(item => item.key === 'status') : tabItems.filter(item => item.key !== 'register' || registration !== 'closed')} size="large" />
       </Card>
 
       <Card className="usage-card" bordered={false} style={{ marginTop: 24 }}>
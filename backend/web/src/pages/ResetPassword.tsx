@@ -0,0 +1,119 @@
+import { LockOutlined, MailOutlined } from '@ant-design/icons'
+import { Button, Card, Form, Input, Result, Typography, message } from 'antd'
+import { useState } from 'react'
+import { useSearchParams } from 'react-router-dom'
+import api from '../api'
+import './Home.css'
+
+const { Title, Paragraph } = Typography
+
+interface ApiResponse<T = any> {
+  code: number
+  msg: string
+  data: T
+}
+
+// 找回密码：无 token 时填写邮箱申请重置邮件，带 token（邮件链接）时设置新密码
+function ResetPassword() {
+  const [searchParams] = useSearchParams()
+  const token = searchParams.get('token')
+  const [loading, setLoading] = useState(false)
+  const [done, setDone] = useState(false)
+
+  const handleForgot = async (values: { email: string }) => {
+    setLoading(true)
+    try {
+      const res = await api.post('/api/auth/forgot-password', values) as ApiResponse
+      if (res.code === 0) {
+        setDone(true)
+      } else {
+        message.error(res.msg || '发送失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '发送失败')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  const handleReset = async (values: { password: string }) => {
+    setLoading(true)
+    try {
+      const res = await api.post('/api/auth/reset-password', { token, password: values.password }) as ApiResponse
+      if (res.code === 0) {
+        setDone(true)
+      } else {
+        message.error(res.msg || '重置失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '重置失败，链接可能已失效')
+    } finally {
+      setLoading(false)
+    }
+  }
+
+  return (
+    <div className="home-container">
+      <Card className="home-card" bordered={false}>
+        {done ? (
+          <Result
+            status="success"
+            title={token ? '密码已重置' : '邮件已发送'}
+            subTitle={token ? '请使用新密码登录' : '如果该邮箱已注册，你将收到一封重置密码的邮件'}
+            extra={<Button type="primary" href="/">返回首页</Button>}
+          />
+        ) : token ? (
+          <div style={{ maxWidth: 400, margin: '0 auto' }}>
+            <Title level={4} style={{ textAlign: 'center', marginBottom: 24 }}>设置新密码</Title>
+            <Form onFinish={handleReset} layout="vertical" size="large">
+              <Form.Item name="password" rules={[{ required: true, message: '请输入新密码' }, { min: 6, message: '至少6个字符' }]}>
+                <Input.Password prefix={<LockOutlined />} placeholder="新密码" />
+              </Form.Item>
+              <Form.Item
+                name="password2"
+                dependencies={['password']}
+                rules={[
+                  { required: true, message: '请确认密码' },
+                  ({ getFieldValue }) => ({
+                    validator(_, value) {
+                      if (!value || getFieldValue('password') === value) {
+                        return Promise.resolve()
+                      }
+                      return Promise.reject(new Error('两次密码不一致'))
+                    }
+                  })
+                ]}
+              >
+                <Input.Password prefix={<LockOutlined />} placeholder="确认密码" />
+              </Form.Item>
+              <Form.Item>
+                <Button type="primary" htmlType="submit" block loading={loading} size="large">
+                  重置密码
+                </Button>
+              </Form.Item>
+            </Form>
+          </div>
+        ) : (
+          <div style={{ maxWidth: 400, margin: '0 auto' }}>
+            <Title level={4} style={{ textAlign: 'center', marginBottom: 8 }}>找回密码</Title>
+            <Paragraph type="secondary" style={{ textAlign: 'center', marginBottom: 24 }}>
+              输入注册邮箱，我们会发送重置密码的链接
+            </Paragraph>
+            <Form onFinish={handleForgot} layout="vertical" size="large">
+              <Form.Item name="email" rules={[{ required: true, message: '请输入邮箱' }, { type: 'email', message: '邮箱格式不正确' }]}>
+                <Input prefix={<MailOutlined />} placeholder="邮箱" />
+              </Form.Item>
+              <Form.Item>
+                <Button type="primary" htmlType="submit" block loading={loading} size="large">
+                  发送重置邮件
+                </Button>
+              </Form.Item>
+            </Form>
+          </div>
+        )}
+      </Card>
+    </div>
+  )
+}
+
+export default ResetPassword
@@ -1,18 +1,70 @@
-import { ArrowLeftOutlined, CopyOutlined, DeleteOutlined, ReloadOutlined } from '@ant-design/icons'
-import { Button, Card, message, Modal, Space, Table, Tag, Typography } from 'antd'
+import { ApartmentOutlined, ArrowLeftOutlined, AuditOutlined, BgColorsOutlined, CheckSquareOutlined, FireOutlined, SafetyCertificateOutlined, ClockCircleOutlined, CommentOutlined, CopyOutlined, DeleteOutlined, FieldTimeOutlined, FileTextOutlined, FileZipOutlined, FormOutlined, HistoryOutlined, ImportOutlined, LikeOutlined, LinkOutlined, PayCircleOutlined, ReadOutlined, ReloadOutlined, SearchOutlined, SoundOutlined, TeamOutlined, TranslationOutlined, UnorderedListOutlined } from '@ant-design/icons'
+import { Button, Card, Dropdown, message, Modal, Progress, Space, Table, Tag, Typography } from 'antd'
 import type { ColumnsType } from 'antd/es/table'
 import { useEffect, useState } from 'react'
 import { useNavigate } from 'react-router-dom'
-import { deleteShare, listShares, type ShareListItem } from '../api/share'
+import { useLiveEvents } from '../api/events'
+import { createExport, deleteShare, downloadBundle, downloadExport, downloadShareViews, getExport, listShares, setShareTeaser, shareStatusTransitions, updateShareStatus, type ExportJob, type ShareListItem, type ShareStatus } from '../api/share'
+import AccessModal from '../components/AccessModal'
+import CommentsModal from '../components/CommentsModal'
+import DisplayModal from '../components/DisplayModal'
+import FeedbackModal from '../components/FeedbackModal'
+import FormsModal from '../components/FormsModal'
+import ImportModal from '../components/ImportModal'
+import PaywallModal from '../components/PaywallModal'
+import ReadingModal from '../components/ReadingModal'
+import NarrationModal from '../components/NarrationModal'
+import TranslationsModal from '../components/TranslationsModal'
+import LanguageCheckModal from '../components/LanguageCheckModal'
+import SummaryModal from '../components/SummaryModal'
+import RevisionsModal from '../components/RevisionsModal'
+import ScheduleModal from '../components/ScheduleModal'
+import SealModal from '../components/SealModal'
+import SemanticSearchModal from '../components/SemanticSearchModal'
+import ShortLinksModal from '../components/ShortLinksModal'
+import TermsModal from '../components/TermsModal'
+import TrashModal from '../components/TrashModal'
+import VariantModal from '../components/VariantModal'
+import ViewLimitModal from '../components/ViewLimitModal'
 
 const { Title, Text } = Typography
 
+const statusLabels: Record<ShareStatus, { text: string; color: string }> = {
+  draft: { text: '草稿', color: 'gold' },
+  published: { text: '已发布', color: 'success' },
+  unlisted: { text: '不公开列出', color: 'processing' },
+  disabled: { text: '已停用', color: 'default' },
+}
+
 function ShareList() {
   const navigate = useNavigate()
   const [shares, setShares] = useState<ShareListItem[]>([])
   const [loading, setLoading] = useState(true)
   const [page, setPage] = useState(1)
   const [total, setTotal] = useState(0)
+  const [exporting, setExporting] = useState<string | null>(null)
+  const [revisionsOf, setRevisionsOf] = useState<ShareListItem | null>(null)
+  const [commentsOf, setCommentsOf] = useState<ShareListItem | null>(null)
+  const [feedbackOf, setFeedbackOf] = useState<ShareListItem | null>(null)
+  const [readingOf, setReadingOf] = useState<ShareListItem | null>(null)
+  const [formsOf, setFormsOf] = useState<ShareListItem | null>(null)
+  const [narrationOf, setNarrationOf] = useState<ShareListItem | null>(null)
+  const [summaryOf, setSummaryOf] = useState<ShareListItem | null>(null)
+  const [translationsOf, setTranslationsOf] = useState<ShareListItem | null>(null)
+  const [languageCheckOf, setLanguageCheckOf] = useState<ShareListItem | null>(null)
+  const [accessOf, setAccessOf] = useState<ShareListItem | null>(null)
+  const [scheduleOf, setScheduleOf] = useState<ShareListItem | null>(null)
+  const [termsOf, setTermsOf] = useState<ShareListItem | null>(null)
+  const [sealOf, setSealOf] = useState<ShareListItem | null>(null)
+  const [viewLimitOf, setViewLimitOf] = useState<ShareListItem | null>(null)
+  const [paywallOf, setPaywallOf] = useState<ShareListItem | null>(null)
+  const [linksOf, setLinksOf] = useState<ShareListItem | null>(null)
+  const [displayOf, setDisplayOf] = useState<ShareListItem | null>(null)
+  const [variantOf, setVariantOf] = useState<ShareListItem | null>(null)
+  const [relatedOf, setRelatedOf] = useState<ShareListItem | null>(null)
+  const [searchOpen, setSearchOpen] = useState(false)
+  const [trashOpen, setTrashOpen] = useState(false)
+  const [importOpen, setImportOpen] = useState(false)
   const pageSize = 10
 
   const loadShares = async (currentPage = 1) => {
@@ -37,6 +89,15 @@ function ShareList() {
     loadShares()
   }, [])
 
+  // 实时更新：读者浏览时刷新浏览次数，插件发布新分享或重连后重新加载当前页
+  useLiveEvents(ev => {
+    if (ev.type === 'share_viewed' && ev.viewCount) {
+      setShares(items => items.map(s => s.id === ev.shareId ? { ...s, viewCount: Math.max(s.viewCount, ev.viewCount!) } : s))
+    } else if (ev.type === 'share_published') {
+      loadShares(page)
+    }
+  }, () => loadShares(page))
+
   const copyShareUrl = (url: string) => {
     navigator.clipboard.writeText(url).then(() => {
       message.success('链接已复制')
@@ -45,10 +106,20 @@ function ShareList() {
     })
   }
 
+  // 复制 iframe 嵌入代码，嵌入页为去掉站点导航的 /s/<id>/embed；需验证才能阅读的分享在嵌入页中只显示打开链接
+  const copyEmbedCode = (record: ShareListItem) => {
+    const code = `<iframe src="${record.shareUrl}/embed" width="100%" height="600" frameborder="0" loading="lazy" style="border:0" title="${record.docTitle.replace(/"/g, '&quot;')}"></iframe>`
+    navigator.clipboard.writeText(code).then(() => {
+      message.success('嵌入代码已复制')
+    }).catch(() => {
+      message.error('复制失败')
+    })
+  }
+
   const handleDelete = async (id: string, docTitle: string) => {
     Modal.confirm({
       title: '确认删除',
-      content: `确定要删除分享"${docTitle}"吗？删除后无法恢复。`,
+      content: `确定要删除分享"${docTitle}"吗？删除后移入回收站，可在回收站中恢复。`,
       okText: '删除',
       okType: 'danger',
       cancelText: '取消',
@@ -68,10 +139,126 @@ function ShareList() {
     })
   }
 
+  // 试读模式：未通过密码、付费或登录校验的读者可阅读 <!-- more --> 之前的内容
+  const handleTeaser = (record: ShareListItem) => {
+    const enable = !record.teaser
+    Modal.confirm({
+      title: enable ? '开启试读' : '关闭试读',
+      content: enable
+        ? '尚未输入密码、付费或登录的读者可阅读正文中 <!-- more --> 之前的内容（没有分隔线时为开头约 300 字），开启后该分享不再出现在搜索与订阅源中。'
+        : `关闭后，读者须通过全部访问校验才能阅读"${record.docTitle}"。`,
+      okText: enable ? '开启' : '关闭',
+      cancelText: '取消',
+      onOk: async () => {
+        try {
+          const res = await setShareTeaser(record.id, enable)
+          if (res.code === 0) {
+            message.success('已保存')
+            loadShares(page)
+          } else {
+            message.error(res.msg || '保存失败')
+          }
+        } catch (e: any) {
+          message.error(e.response?.data?.msg || e.message || '保存失败')
+        }
+      }
+    })
+  }
+
+  const saveBlob = (blob: Blob, fileName: string) => {
+    const url = URL.createObjectURL(blob)
+    const a = document.createElement('a')
+    a.href = url
+    a.download = fileName
+    a.click()
+    URL.revokeObjectURL(url)
+  }
+
+  // 导出 Markdown / HTML 文件包：服务端即时生成，直接下载
+  const handleBundle = async (record: ShareListItem, format: 'md' | 'html') => {
+    setExporting(record.id)
+    try {
+      const blob = await downloadBundle(record.id, format)
+      saveBlob(blob, `${record.docTitle || record.id}-${format}.zip`)
+      message.success('导出完成')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '导出失败')
+    } finally {
+      setExporting(null)
+    }
+  }
+
+  // 导出浏览记录 CSV（原始记录或按日期汇总），可导入表格或 BI 工具分析
+  const handleViews = async (record: ShareListItem, group?: 'day') => {
+    setExporting(record.id)
+    try {
+      const blob = await downloadShareViews(record.id, { format: 'csv', group })
+      saveBlob(blob, `${record.docTitle || record.id}-views${group ? '-by-day' : ''}.csv`)
+      message.success('导出完成')
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '导出失败')
+    } finally {
+      setExporting(null)
+    }
+  }
+
+  // 导出 LaTeX：创建后台任务并轮询，完成后下载 zip 文件包
+  const handleExport = async (id: string) => {
+    setExporting(id)
+    try {
+      const res = await createExport(id, 'latex')
+      if (res.code !== 0 || !res.data) {
+        message.error(res.msg || '导出失败')
+        return
+      }
+      let job: ExportJob = res.data
+      for (let i = 0; i < 150 && (job.status === 'pending' || job.status === 'running'); i++) {
+        await new Promise(resolve => setTimeout(resolve, 2000))
+        const next = await getExport(id, job.id)
+        if (next.code !== 0 || !next.data) {
+          message.error(next.msg || '导出失败')
+          return
+        }
+        job = next.data
+      }
+      if (job.status !== 'done') {
+        message.error(job.error || '导出超时，请稍后重试')
+        return
+      }
+      const blob = await downloadExport(id, job.id)
+      saveBlob(blob, job.fileName || `${id}.zip`)
+      if (job.warnings) {
+        message.warning('部分图片未能打包，已以文字代替')
+      } else {
+        message.success('导出完成')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '导出失败')
+    } finally {
+      setExporting(null)
+    }
+  }
+
+  const handleStatusChange = async (record: ShareListItem, status: ShareStatus) => {
+    try {
+      const res = await updateShareStatus(record.id, status)
+      if (res.code === 0) {
+        message.success(`已设为${statusLabels[status].text}`)
+        loadShares(page)
+      } else {
+        message.error(res.msg || '操作失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '操作失败')
+    }
+  }
+
   const isExpired = (expireAt: string) => {
     return new Date(expireAt) <= new Date()
   }
 
+  const isSealed = (record: ShareListItem) => !!record.sealedUntil && new Date(record.sealedUntil) > new Date()
+
   const columns: ColumnsType<ShareListItem> = [
     {
       title: '文档标题',
@@ -82,24 +269,55 @@ function ShareList() {
     },
     {
       title: '状态',
-      dataIndex: 'expireAt',
       key: 'status',
-      width: 100,
-      render: (expireAt: string) => (
-        <Tag color={isExpired(expireAt) ? 'default' : 'success'}>
-          {isExpired(expireAt) ? '已过期' : '有效'}
-        </Tag>
-      )
+      width: 140,
+      render: (record: ShareListItem) => {
+        if (isExpired(record.expireAt)) {
+          return <Tag color="default">已过期</Tag>
+        }
+        const status = record.status || 'published'
+        if (isSealed(record)) {
+          return (
+            <>
+              <Tag color={statusLabels[status].color}>{statusLabels[status].text}</Tag>
+              <Tag color="geekblue" title={`保留至 ${new Date(record.sealedUntil!).toLocaleString('zh-CN')}`}>已封存</Tag>
+            </>
+          )
+        }
+        return (
+          <Dropdown
+            trigger={['click']}
+            menu={{
+              items: shareStatusTransitions[status].map(next => ({ key: next, label: `设为${statusLabels[next].text}` })),
+              onClick: ({ key }) => handleStatusChange(record, key as ShareStatus),
+            }}
+          >
+            <Tag color={statusLabels[status].color} style={{ cursor: 'pointer' }}>
+              {statusLabels[status].text} ▾
+            </Tag>
+          </Dropdown>
+        )
+      }
     },
     {
       title: '访问控制',
       key: 'access',
       width: 120,
       render: (record: ShareListItem) => {
+        const scheduled = <>
+          {record.accessSchedule && <Tag color="cyan">定时开放</Tag>}
+          {record.hasTerms && <Tag color="gold">使用条款</Tag>}
+          {!!record.maxViews && <Tag color="volcano">{record.maxViews === 1 ? '阅后即焚' : `限 ${record.maxViews} 次`}</Tag>}
+          {record.paywall && <Tag color="magenta">付费{record.paywallPrice ? ` ${record.paywallPrice}` : ''}</Tag>}
+          {record.teaser && <Tag color="lime">试读</Tag>}
+        </>
+        if (record.restricted) {
+          return <>{scheduled}<Tag color="purple">仅限名单</Tag></>
+        }
         if (record.requirePassword) {
-          return <Tag color="orange">密码保护</Tag>
+          return <>{scheduled}<Tag color="orange">密码保护</Tag></>
         }
-        return record.isPublic ? <Tag color="blue">公开</Tag> : <Tag>仅链接</Tag>
+        return <>{scheduled}{record.isPublic ? <Tag color="blue">公开</Tag> : <Tag>仅链接</Tag>}</>
       }
     },
     {
@@ -110,6 +328,24 @@ function ShareList() {
       align: 'center',
       sorter: (a, b) => a.viewCount - b.viewCount,
     },
+    {
+      title: '任务进度',
+      key: 'tasks',
+      width: 140,
+      render: (record: ShareListItem) => record.tasksTotal
+        ? <Progress percent={Math.round((record.tasksDone ?? 0) / record.tasksTotal * 100)} size="small" format={() => `${record.tasksDone ?? 0}/${record.tasksTotal}`} />
+        : <Text type="secondary">-</Text>
+    },
+    {
+      title: '阅读时长',
+      key: 'readingMinutes',
+      width: 100,
+      align: 'center',
+      render: (record: ShareListItem) => record.readingMinutes
+        ? <Text>约 {record.readingMinutes} 分钟</Text>
+        : <Text type="secondary">-</Text>,
+      sorter: (a, b) => (a.readingMinutes ?? 0) - (b.readingMinutes ?? 0),
+    },
     {
       title: '创建时间',
       dataIndex: 'createdAt',
@@ -129,23 +365,211 @@ function ShareList() {
     {
       title: '操作',
       key: 'action',
-      width: 140,
+      width: 1040,
       fixed: 'right',
       render: (record: ShareListItem) => (
         <Space size="small">
-          <Button
+          <Dropdown.Button
             type="link"
             size="small"
-            icon={<CopyOutlined />}
             onClick={() => copyShareUrl(record.shareUrl)}
+            menu={{
+              items: [{ key: 'embed', label: '复制嵌入代码' }],
+              onClick: () => copyEmbedCode(record),
+            }}
+          >
+            <CopyOutlined /> 复制
+          </Dropdown.Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<LinkOutlined />}
+            onClick={() => setLinksOf(record)}
+          >
+            短链
+          </Button>
+          <Dropdown
+            trigger={['click']}
+            disabled={exporting !== null && exporting !== record.id}
+            menu={{
+              items: [
+                { key: 'md', label: 'Markdown 文件包' },
+                { key: 'html', label: 'HTML 文件包' },
+                { key: 'latex', label: 'LaTeX 工程' },
+                { type: 'divider' },
+                { key: 'views', label: '浏览记录（CSV）' },
+                { key: 'views-day', label: '每日浏览量（CSV）' },
+              ],
+              onClick: ({ key }) => {
+                if (key === 'latex') handleExport(record.id)
+                else if (key === 'views') handleViews(record)
+                else if (key === 'views-day') handleViews(record, 'day')
+                else handleBundle(record, key as 'md' | 'html')
+              },
+            }}
+          >
+            <Button type="link" size="small" icon={<FileZipOutlined />} loading={exporting === record.id}>
+              导出
+            </Button>
+          </Dropdown>
+          <Button
+            type="link"
+            size="small"
+            icon={<HistoryOutlined />}
+            onClick={() => setRevisionsOf(record)}
+          >
+            版本
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<CommentOutlined />}
+            onClick={() => setCommentsOf(record)}
           >
-            复制
+            评论
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<LikeOutlined />}
+            onClick={() => setFeedbackOf(record)}
+          >
+            反馈
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<FieldTimeOutlined />}
+            onClick={() => setReadingOf(record)}
+          >
+            阅读
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<FormOutlined />}
+            onClick={() => setFormsOf(record)}
+          >
+            表单
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<SoundOutlined />}
+            onClick={() => setNarrationOf(record)}
+          >
+            朗读
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<FileTextOutlined />}
+            onClick={() => setSummaryOf(record)}
+          >
+            摘要
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<TranslationOutlined />}
+            onClick={() => setTranslationsOf(record)}
+          >
+            翻译
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<AuditOutlined />}
+            onClick={() => setLanguageCheckOf(record)}
+          >
+            校对
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<ApartmentOutlined />}
+            onClick={() => setRelatedOf(record)}
+          >
+            相关
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<UnorderedListOutlined />}
+            onClick={() => setDisplayOf(record)}
+          >
+            目录
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<BgColorsOutlined />}
+            onClick={() => setVariantOf(record)}
+          >
+            {record.variant ? 'A/B 测试中' : 'A/B'}
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<TeamOutlined />}
+            onClick={() => setAccessOf(record)}
+          >
+            访问
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<ClockCircleOutlined />}
+            onClick={() => setScheduleOf(record)}
+          >
+            定时
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<CheckSquareOutlined />}
+            onClick={() => setTermsOf(record)}
+          >
+            条款
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<SafetyCertificateOutlined />}
+            onClick={() => setSealOf(record)}
+          >
+            封存
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<FireOutlined />}
+            onClick={() => setViewLimitOf(record)}
+          >
+            次数
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<PayCircleOutlined />}
+            onClick={() => setPaywallOf(record)}
+          >
+            付费
+          </Button>
+          <Button
+            type="link"
+            size="small"
+            icon={<ReadOutlined />}
+            onClick={() => handleTeaser(record)}
+          >
+            {record.teaser ? '关闭试读' : '试读'}
           </Button>
           <Button
             type="link"
             size="small"
             danger
             icon={<DeleteOutlined />}
+            disabled={isSealed(record)}
             onClick={() => handleDelete(record.id, record.docTitle)}
           >
             删除
@@ -171,14 +595,25 @@ function ShareList() {
                 分享管理
               </Title>
             </div>
-            <Button
-              type="primary"
-              icon={<ReloadOutlined />}
-              onClick={() => loadShares(page)}
-              loading={loading}
-            >
-              刷新
-            </Button>
+            <Space>
+              <Button icon={<SearchOutlined />} onClick={() => setSearchOpen(true)}>
+                语义搜索
+              </Button>
+              <Button icon={<ImportOutlined />} onClick={() => setImportOpen(true)}>
+                导入
+              </Button>
+              <Button icon={<DeleteOutlined />} onClick={() => setTrashOpen(true)}>
+                回收站
+              </Button>
+              <Button
+                type="primary"
+                icon={<ReloadOutlined />}
+                onClick={() => loadShares(page)}
+                loading={loading}
+              >
+                刷新
+              </Button>
+            </Space>
           </div>
         </div>
 
@@ -195,7 +630,7 @@ function ShareList() {
             showTotal: (total) => `共 ${total} 条记录`,
             onChange: loadShares
           }}
-          scroll={{ x: 1200 }}
+          scroll={{ x: 1600 }}
           locale={{
             emptyText: (
               <div style={{ padding: '40px 0', color: 'rgba(0,0,0,0.25)' }}>
@@ -206,6 +641,135 @@ function ShareList() {
           }}
         />
       </Card>
+
+      <RevisionsModal
+        shareId={revisionsOf?.id ?? null}
+        docTitle={revisionsOf?.docTitle}
+        onClose={() => setRevisionsOf(null)}
+        onRolledBack={() => loadShares(page)}
+      />
+      <CommentsModal
+        shareId={commentsOf?.id ?? null}
+        docTitle={commentsOf?.docTitle}
+        onClose={() => setCommentsOf(null)}
+      />
+      <FeedbackModal
+        shareId={feedbackOf?.id ?? null}
+        docTitle={feedbackOf?.docTitle}
+        onClose={() => setFeedbackOf(null)}
+      />
+      <ReadingModal
+        shareId={readingOf?.id ?? null}
+        docTitle={readingOf?.docTitle}
+        onClose={() => setReadingOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <FormsModal
+        shareId={formsOf?.id ?? null}
+        docTitle={formsOf?.docTitle}
+        onClose={() => setFormsOf(null)}
+      />
+      <NarrationModal
+        shareId={narrationOf?.id ?? null}
+        docTitle={narrationOf?.docTitle}
+        onClose={() => setNarrationOf(null)}
+      />
+      <TranslationsModal
+        shareId={translationsOf?.id ?? null}
+        docTitle={translationsOf?.docTitle}
+        onClose={() => setTranslationsOf(null)}
+      />
+      <LanguageCheckModal
+        shareId={languageCheckOf?.id ?? null}
+        docTitle={languageCheckOf?.docTitle}
+        onClose={() => setLanguageCheckOf(null)}
+      />
+      <SummaryModal
+        shareId={summaryOf?.id ?? null}
+        docTitle={summaryOf?.docTitle}
+        onClose={() => setSummaryOf(null)}
+      />
+      <TrashModal
+        open={trashOpen}
+        onClose={() => setTrashOpen(false)}
+        onRestored={() => loadShares(page)}
+      />
+      <ImportModal
+        open={importOpen}
+        onClose={() => setImportOpen(false)}
+        onImported={() => loadShares(1)}
+      />
+      <SemanticSearchModal
+        open={searchOpen || !!relatedOf}
+        relatedTo={relatedOf}
+        onClose={() => {
+          setSearchOpen(false)
+          setRelatedOf(null)
+        }}
+      />
+      <DisplayModal
+        shareId={displayOf?.id ?? null}
+        docTitle={displayOf?.docTitle}
+        toc={displayOf?.toc}
+        headingAnchors={displayOf?.headingAnchors}
+        onClose={() => setDisplayOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <VariantModal
+        shareId={variantOf?.id ?? null}
+        docTitle={variantOf?.docTitle}
+        variant={variantOf?.variant}
+        onClose={() => setVariantOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <AccessModal
+        shareId={accessOf?.id ?? null}
+        docTitle={accessOf?.docTitle}
+        onClose={() => setAccessOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <ScheduleModal
+        shareId={scheduleOf?.id ?? null}
+        docTitle={scheduleOf?.docTitle}
+        schedule={scheduleOf?.accessSchedule}
+        onClose={() => setScheduleOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <TermsModal
+        shareId={termsOf?.id ?? null}
+        docTitle={termsOf?.docTitle}
+        onClose={() => setTermsOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <ViewLimitModal
+        shareId={viewLimitOf?.id ?? null}
+        docTitle={viewLimitOf?.docTitle}
+        maxViews={viewLimitOf?.maxViews}
+        viewCount={viewLimitOf?.viewCount}
+        onClose={() => setViewLimitOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <PaywallModal
+        shareId={paywallOf?.id ?? null}
+        docTitle={paywallOf?.docTitle}
+        paywall={paywallOf?.paywall}
+        price={paywallOf?.paywallPrice}
+        checkoutUrl={paywallOf?.paywallUrl}
+        onClose={() => setPaywallOf(null)}
+        onChanged={() => loadShares(page)}
+      />
+      <ShortLinksModal
+        shareId={linksOf?.id ?? null}
+        docTitle={linksOf?.docTitle}
+        onClose={() => setLinksOf(null)}
+      />
+      <SealModal
+        shareId={sealOf?.id ?? null}
+        docTitle={sealOf?.docTitle}
+        sealedUntil={sealOf?.sealedUntil}
+        onClose={() => setSealOf(null)}
+        onChanged={() => loadShares(page)}
+      />
     </div>
   )
 }
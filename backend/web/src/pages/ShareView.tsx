@@ -1,20 +1,58 @@
-import { ExclamationCircleOutlined, EyeOutlined, FileSearchOutlined, HomeOutlined, UpOutlined } from '@ant-design/icons'
-import { Anchor, Button, Drawer, Image, Input, Layout, message, Result, Spin, Typography } from 'antd'
+import { ClockCircleOutlined, CloudDownloadOutlined, ExclamationCircleOutlined, EyeOutlined, LinkOutlined, SafetyCertificateOutlined, FilePdfOutlined, FileSearchOutlined, HomeOutlined, QuestionCircleOutlined, SoundOutlined, ThunderboltOutlined, UnorderedListOutlined, UpOutlined } from '@ant-design/icons'
+import { Alert, Anchor, Button, Drawer, Image, Input, Layout, message, Modal, Progress, Result, Spin, Statistic, Typography } from 'antd'
 import 'github-markdown-css/github-markdown-light.css'
 import 'highlight.js/styles/github.css'
 import { useEffect, useRef, useState } from 'react'
 import ReactMarkdown from 'react-markdown'
-import { useParams } from 'react-router-dom'
+import { useNavigate, useParams } from 'react-router-dom'
 import rehypeHighlight from 'rehype-highlight'
 import rehypeRaw from 'rehype-raw'
 import rehypeSlug from 'rehype-slug'
 import remarkGfm from 'remark-gfm'
-import { getShare, ShareData } from '../api/share'
+import { acceptShareTerms, askShare, createPaymentOrder, getForms, getMindmaps, getPaymentOrder, getPolls, getReadingProgress, getRenders, getShare, getShareTranslation, getTranscripts, MediaTranscript as Transcript, previewShare, ReadingProgress, readerTokenKey, reportShareRead, requestShareAccess, saveReadingProgress, shareAccessKey, ShareData, ShareForm, ShareMindmap, SharePoll, ShareRender, shareTermsKey, shareUnlockKey, shareViewKey, verifyShareAccess } from '../api/share'
+import { decryptShare, encryptionKey, keyFromText, shareKeyStorage } from '../api/e2e'
+import { offlineKey, offlineSupported, removeShareOffline, saveShareOffline } from '../api/offline'
+import AnnouncementBanner from '../components/AnnouncementBanner'
+import AskModal from '../components/AskModal'
+import LanguageSwitcher from '../components/LanguageSwitcher'
+import CommentSection from '../components/CommentSection'
+import FeedbackWidget from '../components/FeedbackWidget'
+import DataTable from '../components/DataTable'
+import DonationBar from '../components/DonationBar'
+import FormBlock from '../components/FormBlock'
+import KanbanBoard from '../components/KanbanBoard'
+import MediaTranscript from '../components/MediaTranscript'
+import MindMap from '../components/MindMap'
+import PollBlock from '../components/PollBlock'
+import RenderedBlock from '../components/RenderedBlock'
+import ReportModal from '../components/ReportModal'
+import VerifyModal from '../components/VerifyModal'
 import './ShareView.css'
 
 const { Content, Sider } = Layout
 const { Title, Text } = Typography
 
+// 分享附件链接，以及浏览器可内联预览、不受下载策略限制的附件类型
+const assetLinkPattern = /(^|\/api\/s\/[^/]+\/)assets\//
+const inlineAssetPattern = /\.(png|jpe?g|gif|webp|avif|bmp|svg|mp3|wav|ogg|m4a|flac|mp4|webm|mov|pdf|txt)(\?|$)/i
+
+// browserFingerprint 浏览器指纹：常见浏览器特征的 SHA-256，随同意记录提交
+const browserFingerprint = async (): Promise<string> => {
+  const parts = [
+    navigator.userAgent,
+    navigator.language,
+    `${screen.width}x${screen.height}x${screen.colorDepth}`,
+    Intl.DateTimeFormat().resolvedOptions().timeZone,
+    String(navigator.hardwareConcurrency || ''),
+  ]
+  try {
+    const digest = await crypto.subtle.digest('SHA-256', new TextEncoder().encode(parts.join('|')))
+    return Array.from(new Uint8Array(digest)).map(b => b.toString(16).padStart(2, '0')).join('')
+  } catch {
+    return ''
+  }
+}
+
 interface TocNode {
   id: string
   text: string
@@ -23,43 +61,135 @@ interface TocNode {
 }
 
 function ShareView() {
-  const { shareId } = useParams<{ shareId: string }>()
+  const { shareId, lang } = useParams<{ shareId: string; lang?: string }>()
+  const navigate = useNavigate()
   const [loading, setLoading] = useState(true)
   const [error, setError] = useState<string | null>(null)
   const [share, setShare] = useState<ShareData | null>(null)
   const [requirePassword, setRequirePassword] = useState(false)
   const [password, setPassword] = useState('')
   const [passwordError, setPasswordError] = useState('')
+  const [askOpen, setAskOpen] = useState(false)
+  const [verifyOpen, setVerifyOpen] = useState(false)
+  const [reportOpen, setReportOpen] = useState(false)
+  const [restricted, setRestricted] = useState<{ emailAccess: boolean } | null>(null)
+  // 不在开放时间内：下一次开放时间（不会再开放时为 null）与发布者时区
+  const [notOpen, setNotOpen] = useState<{ opensAt: string | null; timezone: string } | null>(null)
+  const [termsGate, setTermsGate] = useState<{ terms: string; version: string } | null>(null)
+  // 付费分享未解锁：价格与能否在线购买；orderId 为已创建、等待支付的订单
+  const [paywall, setPaywall] = useState<{ price: string; payable: boolean; orderId?: string } | null>(null)
+  const [paying, setPaying] = useState(false)
+  const [readerName, setReaderName] = useState('')
+  const [accepting, setAccepting] = useState(false)
+  const [accessEmail, setAccessEmail] = useState('')
+  const [accessSent, setAccessSent] = useState(false)
   const [tocVisible, setTocVisible] = useState(false)
   const [tocTree, setTocTree] = useState<TocNode[]>([])
+  const [floatingTocOpen, setFloatingTocOpen] = useState(() => window.innerWidth > 768)
   const [showBackTop, setShowBackTop] = useState(false)
   const [headerShrink, setHeaderShrink] = useState(false)
+  const [transcripts, setTranscripts] = useState<Transcript[]>([])
+  const [mindmaps, setMindmaps] = useState<ShareMindmap[]>([])
+  const [renders, setRenders] = useState<ShareRender[]>([])
+  const [polls, setPolls] = useState<SharePoll[]>([])
+  const [forms, setForms] = useState<ShareForm[]>([])
+  const [fingerprint, setFingerprint] = useState('')
+  // 上次保存的阅读进度（提示继续阅读）与在其他设备继续阅读的链接
+  const [resume, setResume] = useState<ReadingProgress | null>(null)
+  const [resumeUrl, setResumeUrl] = useState('')
+  // 是否已保存离线副本
+  const [offlineSaved, setOfflineSaved] = useState(() => !!shareId && !!localStorage.getItem(offlineKey(shareId)))
+  const [savingOffline, setSavingOffline] = useState(false)
+  // 端到端加密分享：缺少密钥或密钥错误时保留密文，等待读者输入密钥
+  const [sealed, setSealed] = useState<ShareData | null>(null)
+  const [keyInput, setKeyInput] = useState('')
+  const [keyError, setKeyError] = useState('')
   const contentRef = useRef<HTMLDivElement>(null)
 
+  // 显示分享数据；加密分享先用密钥在浏览器中解密，标题与正文都只在密文中
+  const showShare = async (data: ShareData, key = shareId ? encryptionKey(shareId) : '') => {
+    if (data.mode !== 'encrypted' || !shareId) {
+      setShare(data)
+      return
+    }
+    try {
+      const plain = await decryptShare(data.content, key)
+      sessionStorage.setItem(shareKeyStorage(shareId), key)
+      setSealed(null)
+      setKeyError('')
+      setShare({ ...data, docTitle: plain.title, content: plain.content })
+    } catch {
+      setSealed(data)
+      setKeyError(key ? '密钥错误或链接不完整' : '')
+    }
+  }
+
+  const handleKeySubmit = (e: React.FormEvent) => {
+    e.preventDefault()
+    if (!sealed || !keyInput.trim()) {
+      setKeyError('请输入密钥')
+      return
+    }
+    showShare(sealed, keyFromText(keyInput))
+  }
+
   const loadShare = async (pwd?: string) => {
     if (!shareId) return
 
     setLoading(true)
     setError(null)
     setPasswordError('')
+    setRestricted(null)
+    setNotOpen(null)
+    setTermsGate(null)
+    setPaywall(null)
 
     try {
-      const response = await getShare(shareId, pwd)
+      const response = lang ? await getShareTranslation(shareId, lang, pwd) : await getShare(shareId, pwd)
       
       if (response.code === 0 && response.data) {
-        setShare(response.data)
+        if (response.data.viewLimit?.viewToken) {
+          sessionStorage.setItem(shareViewKey(shareId), response.data.viewLimit.viewToken)
+        }
+        await showShare(response.data)
         setRequirePassword(false)
+        // 试读付费分享：在正文下方提供购买入口
+        if (response.data.teaser?.gate === 'payment') {
+          setPaywall({
+            price: response.data.teaser.price || '',
+            payable: !!response.data.teaser.payable,
+            orderId: sessionStorage.getItem(`share_order:${shareId}`) || undefined,
+          })
+        }
       } else {
         setError(response.msg || '加载失败')
       }
     } catch (err: any) {
       const errorMsg = err.response?.data?.msg || err.message || '加载失败'
+      // 错误信息按语言翻译后，英文原文保留在 msgKey 中
+      const errorKey = err.response?.data?.msgKey || errorMsg
       
-      if (errorMsg.includes('Password required')) {
+      if (err.response?.data?.code === 1004) {
+        setRestricted({ emailAccess: !!err.response.data.data?.emailAccess })
+      } else if (err.response?.data?.code === 1006) {
+        if (!(await loadPreview(errorKey))) {
+          setNotOpen({ opensAt: err.response.data.data?.opensAt ?? null, timezone: err.response.data.data?.timezone || '' })
+        }
+      } else if (err.response?.data?.code === 1007) {
+        setTermsGate({ terms: err.response.data.data?.terms || '', version: err.response.data.data?.version || '' })
+      } else if (err.response?.data?.code === 1010) {
+        // 订单退款后原解锁令牌失效
+        localStorage.removeItem(shareUnlockKey(shareId))
+        setPaywall({
+          price: err.response.data.data?.price || '',
+          payable: !!err.response.data.data?.payable,
+          orderId: sessionStorage.getItem(`share_order:${shareId}`) || undefined,
+        })
+      } else if (errorKey.includes('Password required')) {
         setRequirePassword(true)
-      } else if (errorMsg.includes('Invalid password')) {
+      } else if (errorKey.includes('Invalid password')) {
         setPasswordError('密码错误')
-      } else {
+      } else if (!(await loadPreview(errorKey))) {
         setError(errorMsg)
       }
     } finally {
@@ -67,6 +197,25 @@ function ShareView() {
     }
   }
 
+  // 草稿、已停用或不在开放时间内的分享：已登录的所有者改为加载预览
+  const loadPreview = async (errorMsg: string) => {
+    if (!shareId || !localStorage.getItem('session_token') ||
+      !(errorMsg.includes('not published') || errorMsg.includes('disabled') || errorMsg.includes('not open'))) {
+      return false
+    }
+    try {
+      const response = await previewShare(shareId)
+      if (response.code === 0 && response.data) {
+        await showShare(response.data)
+        setRequirePassword(false)
+        return true
+      }
+    } catch {
+      // 非所有者，按原错误处理
+    }
+    return false
+  }
+
   // 从已渲染的 DOM 中提取标题，排除代码块内部的伪标题
   useEffect(() => {
     if (!share?.content) {
@@ -82,9 +231,10 @@ function ShareView() {
     const idCount: Record<string, number> = {}
 
     const slugify = (text: string) => {
-      let slug = text.trim().toLowerCase()
+      // 保留各种文字的字母与数字（不只是汉字），与 rehype-slug 一致
+      let slug = text.normalize('NFC').trim().toLowerCase()
         .replace(/\s+/g, '-')
-        .replace(/[^\w\u4e00-\u9fa5-]/g, '')
+        .replace(/[^\p{L}\p{M}\p{N}_-]/gu, '')
       slug = slug.replace(/-+/g, '-')
       if (!slug) slug = 'section'
       if (idCount[slug] !== undefined) {
@@ -96,15 +246,28 @@ function ShareView() {
       return slug
     }
 
+    // 服务端按标题文字生成的锚点在重新发布后保持不变，按顺序与文字对应到已渲染的标题
+    const serverHeadings = share.headings || []
+    let next = 0
+
     headingEls.forEach(el => {
       // 排除代码块内部标题: 如果祖先存在 PRE 或 CODE（且不是本身就是代码标记）
       if (el.closest('pre, code')) return
       const level = Number(el.tagName.substring(1))
-      const text = el.textContent?.trim() || ''
+      const text = Array.from(el.childNodes)
+        .filter(n => !(n instanceof HTMLElement && n.classList.contains('heading-anchor')))
+        .map(n => n.textContent).join('').trim()
       if (!text) return
-      // 如果 rehypeSlug 已生成 id 使用之，否则自生成
+      const match = serverHeadings.findIndex((h, i) => i >= next && h.level === level && h.text === text)
       let id = el.id
-      if (!id) {
+      if (match >= 0) {
+        // 服务端锚点已保证唯一
+        next = match + 1
+        id = serverHeadings[match].id
+        el.id = id
+        idCount[id] = idCount[id] ?? 0
+      } else if (!id) {
+        // 如果 rehypeSlug 已生成 id 使用之，否则自生成
         id = slugify(text)
         el.id = id
       } else {
@@ -133,11 +296,272 @@ function ShareView() {
       stack.push(node)
     })
     setTocTree(nodes)
+
+    // 链接中的 #锚点：正文渲染完成后跳转到对应标题
+    const hash = decodeURIComponent(window.location.hash.slice(1))
+    if (hash) {
+      requestAnimationFrame(() => document.getElementById(hash)?.scrollIntoView())
+    }
   }, [share?.content])
 
   useEffect(() => {
-    loadShare()
-  }, [shareId])
+    // 受限分享的邮件链接：兑换访问令牌后从地址栏移除一次性令牌
+    const params = new URLSearchParams(window.location.search)
+    const linkToken = params.get('access')
+    if (!shareId || !linkToken) {
+      loadShare()
+      return
+    }
+    params.delete('access')
+    const query = params.toString()
+    window.history.replaceState(null, '', window.location.pathname + (query ? `?${query}` : '') + window.location.hash)
+    verifyShareAccess(shareId, linkToken)
+      .then(res => {
+        if (res.code === 0 && res.data) localStorage.setItem(shareAccessKey(shareId), res.data.accessToken)
+      })
+      .catch(() => message.error('访问链接无效或已过期，请重新获取'))
+      .finally(() => loadShare())
+  }, [shareId, lang])
+
+  // 正文含音视频时加载文字稿
+  useEffect(() => {
+    if (!shareId || !share?.content || !/<(audio|video)\b/i.test(share.content)) {
+      setTranscripts([])
+      return
+    }
+    getTranscripts(shareId, password || undefined)
+      .then(res => setTranscripts(res.data?.items ?? []))
+      .catch(() => setTranscripts([]))
+  }, [shareId, share?.content])
+
+  // 正文含思维导图代码块时加载解析后的节点树
+  useEffect(() => {
+    if (!shareId || !share?.content || !/^\s*(```|~~~)\s*(mindmap|markmap)\s*$/im.test(share.content)) {
+      setMindmaps([])
+      return
+    }
+    getMindmaps(shareId, password || undefined)
+      .then(res => setMindmaps(res.data?.items ?? []))
+      .catch(() => setMindmaps([]))
+  }, [shareId, share?.content])
+
+  // 正文含投票块时加载投票结果，页面可见时每 15 秒刷新一次，显示其他读者的实时投票
+  useEffect(() => {
+    if (!shareId || !share?.content || !/^\s*(```|~~~)\s*poll\s*$/im.test(share.content)) {
+      setPolls([])
+      return
+    }
+    let cancelled = false
+    let fp = ''
+    const load = async () => {
+      if (document.visibilityState === 'hidden') return
+      if (!fp) {
+        fp = await browserFingerprint()
+        if (!cancelled) setFingerprint(fp)
+      }
+      try {
+        const res = await getPolls(shareId, fp, password || undefined)
+        if (!cancelled) setPolls(res.data?.items ?? [])
+      } catch {
+        // 刷新失败时保留上一次的结果
+      }
+    }
+    load()
+    const timer = window.setInterval(load, 15000)
+    return () => {
+      cancelled = true
+      window.clearInterval(timer)
+    }
+  }, [shareId, share?.content])
+
+  // 正文含表单块时加载表单定义
+  useEffect(() => {
+    if (!shareId || !share?.content || !/^\s*(```|~~~)\s*form\s*$/im.test(share.content)) {
+      setForms([])
+      return
+    }
+    getForms(shareId, password || undefined)
+      .then(res => setForms(res.data?.items ?? []))
+      .catch(() => setForms([]))
+  }, [shareId, share?.content])
+
+  // 正文含带语言的代码块时查询服务端外部渲染插件的渲染结果
+  useEffect(() => {
+    if (!shareId || !share?.content || !/^\s*(```|~~~)\s*[\w-]+/m.test(share.content)) {
+      setRenders([])
+      return
+    }
+    getRenders(shareId, password || undefined)
+      .then(res => setRenders(res.data?.items ?? []))
+      .catch(() => setRenders([]))
+  }, [shareId, share?.content])
+
+  // 服务端预渲染了公式时加载 KaTeX 样式（预渲染结果为 HTML 时需要，MathML 不受影响）
+  useEffect(() => {
+    if (!share?.prerender?.math) return
+    const link = document.createElement('link')
+    link.rel = 'stylesheet'
+    link.href = 'https://cdn.jsdelivr.net/npm/katex@0.16/dist/katex.min.css'
+    document.head.appendChild(link)
+    return () => link.remove()
+  }, [share?.prerender?.math])
+
+  // 当前阅读位置：视口顶部附近最后一个已读过的标题锚点与滚动百分比
+  const readingPosition = () => {
+    const max = document.documentElement.scrollHeight - window.innerHeight
+    const percent = max > 0 ? Math.round(Math.min(window.scrollY / max, 1) * 1000) / 10 : 0
+    let anchor = ''
+    contentRef.current?.querySelectorAll<HTMLElement>('h1[id], h2[id], h3[id], h4[id], h5[id], h6[id]').forEach(h => {
+      if (h.getBoundingClientRect().top <= 80) anchor = h.id
+    })
+    return { anchor: anchor.slice(0, 128), percent }
+  }
+
+  // 跳到保存的位置；正文改动后百分比对不上标题时以标题锚点为准
+  const jumpTo = (p: ReadingProgress) => {
+    const max = document.documentElement.scrollHeight - window.innerHeight
+    window.scrollTo({ top: (max * p.percent) / 100 })
+    const heading = p.anchor ? document.getElementById(p.anchor) : null
+    if (heading) {
+      const top = heading.getBoundingClientRect().top
+      if (top > window.innerHeight || top < -window.innerHeight * 2) heading.scrollIntoView()
+    }
+    setResume(null)
+  }
+
+  // 加载阅读进度：续读链接（?resume=令牌）打开时沿用其中的令牌并直接跳转，否则提示继续阅读
+  useEffect(() => {
+    if (!shareId || !share?.id || share.teaser || share.mode === 'flashcards') return
+    const params = new URLSearchParams(window.location.search)
+    const shared = params.get('resume')
+    if (shared) {
+      localStorage.setItem(readerTokenKey, shared)
+      params.delete('resume')
+      const query = params.toString()
+      window.history.replaceState(null, '', `${window.location.pathname}${query ? `?${query}` : ''}${window.location.hash}`)
+    }
+    const token = localStorage.getItem(readerTokenKey)
+    if (!token) return
+    let cancelled = false
+    getReadingProgress(shareId, token, password || undefined)
+      .then(res => {
+        if (cancelled || res.code !== 0 || !res.data) return
+        setResumeUrl(res.data.resumeUrl)
+        if (res.data.percent < 2 && !res.data.anchor) return
+        if (shared) {
+          const progress = res.data
+          window.setTimeout(() => jumpTo(progress), 300)
+        } else {
+          setResume(res.data)
+        }
+      })
+      .catch(() => {})
+    return () => { cancelled = true }
+  }, [shareId, share?.id, share?.teaser])
+
+  // 长文档每 15 秒及离开页面时保存阅读位置（位置未变化时不保存）
+  useEffect(() => {
+    if (!shareId || !share?.id || share.teaser || share.mode === 'flashcards') return
+    let last = ''
+    const save = () => {
+      if (document.documentElement.scrollHeight < window.innerHeight * 3) return
+      const pos = readingPosition()
+      const key = `${pos.anchor}|${pos.percent}`
+      if (key === last || (!last && pos.percent < 2)) return
+      last = key
+      const token = localStorage.getItem(readerTokenKey) || undefined
+      saveReadingProgress(shareId, { token, ...pos }, password || undefined)
+        .then(res => {
+          if (res.code !== 0) return
+          if (res.data.token) localStorage.setItem(readerTokenKey, res.data.token)
+          setResumeUrl(res.data.resumeUrl)
+        })
+        .catch(() => {})
+    }
+    const onVisibility = () => {
+      if (document.visibilityState === 'hidden') save()
+    }
+    const timer = window.setInterval(save, 15000)
+    document.addEventListener('visibilitychange', onVisibility)
+    return () => {
+      window.clearInterval(timer)
+      document.removeEventListener('visibilitychange', onVisibility)
+    }
+  }, [shareId, share?.id, share?.teaser])
+
+  const copyResumeLink = () => {
+    navigator.clipboard.writeText(resumeUrl)
+      .then(() => message.success('续读链接已复制，可在其他设备上打开继续阅读'))
+      .catch(() => {})
+  }
+
+  // 已保存离线副本的分享在线打开时检查版本，内容或资源有变化时重新下载
+  useEffect(() => {
+    if (!shareId || !share?.id || share.teaser || lang || !offlineSaved || !navigator.onLine) return
+    saveShareOffline(shareId, share, password || undefined)
+      .then(updated => { if (updated) message.info('离线副本已更新到最新版本') })
+      .catch(() => {})
+  }, [shareId, share?.id])
+
+  const toggleOffline = () => {
+    if (!shareId || !share) return
+    if (offlineSaved) {
+      Modal.confirm({
+        title: '删除离线副本？',
+        content: '删除后断网时将无法阅读该分享。',
+        onOk: () => removeShareOffline(shareId)
+          .then(() => { setOfflineSaved(false); message.success('已删除离线副本') })
+          .catch((e: any) => message.error(e.message || '删除失败')),
+      })
+      return
+    }
+    setSavingOffline(true)
+    saveShareOffline(shareId, share, password || undefined)
+      .then(() => { setOfflineSaved(true); message.success('已保存，断网时也可以在本设备上阅读') })
+      .catch((e: any) => message.error(e.response?.data?.msg || e.message || '保存失败'))
+      .finally(() => setSavingOffline(false))
+  }
+
+  // 主题 A/B 测试与阅读统计：统计页面可见时长、最大滚动深度与读到的最后一个标题，离开页面时上报一次
+  useEffect(() => {
+    const variant = share?.variant
+    if (!shareId || (!variant && !share?.trackReading)) return
+    let visibleMs = 0
+    let since = document.visibilityState === 'visible' ? Date.now() : 0
+    let depth = 0
+    let anchor = ''
+    let sent = false
+    const measure = () => {
+      const max = document.documentElement.scrollHeight - window.innerHeight
+      const d = max > 0 ? Math.round((window.scrollY / max) * 100) : 100
+      if (d >= depth) anchor = readingPosition().anchor || anchor
+      depth = Math.max(depth, Math.min(d, 100))
+    }
+    const onVisibility = () => {
+      if (document.visibilityState === 'visible') {
+        since = Date.now()
+      } else if (since) {
+        visibleMs += Date.now() - since
+        since = 0
+      }
+    }
+    const send = () => {
+      if (sent) return
+      sent = true
+      if (since) visibleMs += Date.now() - since
+      reportShareRead(shareId, { variant, seconds: Math.round(visibleMs / 1000), depth, anchor })
+    }
+    measure()
+    window.addEventListener('scroll', measure, { passive: true })
+    document.addEventListener('visibilitychange', onVisibility)
+    window.addEventListener('pagehide', send)
+    return () => {
+      window.removeEventListener('scroll', measure)
+      document.removeEventListener('visibilitychange', onVisibility)
+      window.removeEventListener('pagehide', send)
+      send()
+    }
+  }, [shareId, share?.variant, share?.trackReading])
 
   // 监听滚动显示回到顶部按钮和标题收缩
   useEffect(() => {
@@ -178,7 +602,7 @@ function ShareView() {
       if (pre.querySelector('.copy-code-btn')) return
 
       const wrapper = document.createElement('div')
-      wrapper.className = 'code-block-wrapper'
+      wrapper.className = pre.classList.contains('code-output') ? 'code-block-wrapper code-output-wrapper' : 'code-block-wrapper'
       pre.parentNode?.insertBefore(wrapper, pre)
       wrapper.appendChild(pre)
 
@@ -211,6 +635,196 @@ function ShareView() {
     window.scrollTo({ top: 0, behavior: 'smooth' })
   }
 
+  // 分享附属资源地址（存档副本、文献导出），密码分享需附带密码，受限分享需附带访问令牌
+  const withAccess = (path: string) => {
+    const params = new URLSearchParams()
+    if (password) params.set('password', password)
+    const access = shareId && localStorage.getItem(shareAccessKey(shareId))
+    if (access) params.set('access', access)
+    const terms = shareId && localStorage.getItem(shareTermsKey(shareId))
+    if (terms) params.set('terms', terms)
+    const view = shareId && sessionStorage.getItem(shareViewKey(shareId))
+    if (view) params.set('view', view)
+    const unlock = shareId && localStorage.getItem(shareUnlockKey(shareId))
+    if (unlock) params.set('unlock', unlock)
+    const query = params.toString()
+    return query ? `${path}${path.includes('?') ? '&' : '?'}${query}` : path
+  }
+
+  // 作者限制了附件下载：仅预览时提示已关闭，需要密码时输入密码后下载
+  const downloadAsset = (href: string) => {
+    if (share?.assetDownloads !== 'password') {
+      message.warning('作者已关闭附件下载')
+      return
+    }
+    let pwd = ''
+    Modal.confirm({
+      title: '下载附件需要输入访问密码',
+      content: <Input.Password autoFocus onChange={(e) => { pwd = e.target.value }} />,
+      okText: '下载',
+      cancelText: '取消',
+      onOk: async () => {
+        const params = new URLSearchParams({ download: '1', password: pwd })
+        const res = await fetch(`${href}${href.includes('?') ? '&' : '?'}${params}`)
+        if (!res.ok) {
+          message.error('密码错误')
+          throw new Error('invalid password')
+        }
+        const url = URL.createObjectURL(await res.blob())
+        const a = document.createElement('a')
+        a.href = url
+        a.download = decodeURIComponent(href.split('?')[0].split('/').pop() || 'download')
+        a.click()
+        URL.revokeObjectURL(url)
+      },
+    })
+  }
+
+  // 标题锚点：地址栏切换到该标题的 #锚点 并复制链接（锚点 id 在渲染后才确定，点击时从标题读取）
+  const copyHeadingLink = (e: React.MouseEvent<HTMLAnchorElement>) => {
+    e.preventDefault()
+    const heading = e.currentTarget.parentElement
+    if (!heading?.id) return
+    window.history.replaceState(null, '', `${window.location.pathname}${window.location.search}#${encodeURIComponent(heading.id)}`)
+    heading.scrollIntoView({ behavior: 'smooth' })
+    navigator.clipboard.writeText(window.location.href)
+      .then(() => message.success('章节链接已复制'))
+      .catch(() => {})
+  }
+
+  // 标题下方显示该节任务列表的完成进度（作为标题的兄弟节点，避免影响目录文字）
+  const headingWithProgress = (Tag: 'h1' | 'h2' | 'h3' | 'h4' | 'h5' | 'h6') =>
+    ({ node, children, ...props }: any) => {
+      const line = node?.position?.start.line
+      const section = line ? share?.tasks?.sections.find(s => s.line === line && !s.kanban) : undefined
+      return (
+        <>
+          <Tag {...props}>
+            {children}
+            {share?.headingAnchors !== false && (
+              <a className="heading-anchor" href="#" aria-label="复制章节链接" onClick={copyHeadingLink}>#</a>
+            )}
+          </Tag>
+          {section && (
+            <Progress
+              className="task-progress"
+              percent={Math.round(section.done / section.total * 100)}
+              size="small"
+              format={() => `${section.done}/${section.total}`}
+            />
+          )}
+        </>
+      )
+    }
+
+  // 音视频按资源路径匹配文字稿；相对路径的资源改用服务端资源地址
+  const mediaWithTranscript = (kind: 'audio' | 'video') =>
+    ({ node, src, children, ...props }: any) => {
+      let path = src ? String(src).split(/[?#]/)[0] : ''
+      try {
+        path = decodeURI(path)
+      } catch {
+        // 非法编码按原样匹配
+      }
+      const transcript = path ? transcripts.find(t => path === t.url || path.endsWith(t.path)) : undefined
+      if (!transcript) {
+        const Tag = kind
+        return <Tag src={src} {...props}>{children}</Tag>
+      }
+      const relative = !/^([a-z][a-z0-9+.-]*:|\/)/i.test(src)
+      return <MediaTranscript kind={kind} src={relative ? transcript.url : src} transcript={transcript} mediaProps={props} />
+    }
+
+  const handleAccessRequest = async (e: React.FormEvent) => {
+    e.preventDefault()
+    if (!shareId || !/^[^\s@]+@[^\s@]+$/.test(accessEmail.trim())) {
+      message.warning('请输入有效的邮箱')
+      return
+    }
+    try {
+      await requestShareAccess(shareId, accessEmail.trim())
+      setAccessSent(true)
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '发送失败')
+    }
+  }
+
+  const handleAcceptTerms = async () => {
+    if (!shareId || !termsGate) return
+    setAccepting(true)
+    try {
+      const res = await acceptShareTerms(shareId, {
+        version: termsGate.version,
+        name: readerName.trim() || undefined,
+        fingerprint: await browserFingerprint(),
+      }, password || undefined)
+      if (res.code === 0 && res.data) {
+        localStorage.setItem(shareTermsKey(shareId), res.data.termsToken)
+        loadShare(password || undefined)
+      } else {
+        message.error(res.msg || '提交失败')
+      }
+    } catch (e: any) {
+      // 条款在阅读期间被修改时重新加载最新版本
+      if (e.response?.status === 409) {
+        message.warning('使用条款已更新，请重新阅读')
+        loadShare(password || undefined)
+      } else {
+        message.error(e.response?.data?.msg || e.message || '提交失败')
+      }
+    } finally {
+      setAccepting(false)
+    }
+  }
+
+  // 在新窗口打开支付页面，支付平台回调确认后轮询到解锁令牌
+  const handlePurchase = async () => {
+    if (!shareId || !paywall) return
+    setPaying(true)
+    try {
+      const res = await createPaymentOrder(shareId, password || undefined)
+      if (res.code === 0 && res.data) {
+        sessionStorage.setItem(`share_order:${shareId}`, res.data.orderId)
+        setPaywall({ ...paywall, orderId: res.data.orderId })
+        window.open(res.data.checkoutUrl, '_blank', 'noopener')
+      } else {
+        message.error(res.msg || '创建订单失败')
+      }
+    } catch (e: any) {
+      message.error(e.response?.data?.msg || e.message || '创建订单失败')
+    } finally {
+      setPaying(false)
+    }
+  }
+
+  const checkPayment = async (quiet = false) => {
+    if (!shareId || !paywall?.orderId) return
+    try {
+      const res = await getPaymentOrder(shareId, paywall.orderId, password || undefined)
+      if (res.code === 0 && res.data?.unlockToken) {
+        localStorage.setItem(shareUnlockKey(shareId), res.data.unlockToken)
+        sessionStorage.removeItem(`share_order:${shareId}`)
+        loadShare(password || undefined)
+      } else if (!quiet) {
+        message.info(res.data?.status === 'refunded' ? '订单已退款' : '尚未收到支付结果，请稍后再试')
+      }
+    } catch (e: any) {
+      if (e.response?.status === 404) {
+        sessionStorage.removeItem(`share_order:${shareId}`)
+        setPaywall({ ...paywall, orderId: undefined })
+      } else if (!quiet) {
+        message.error(e.response?.data?.msg || e.message || '查询失败')
+      }
+    }
+  }
+
+  // 等待支付期间每 5 秒查询一次订单
+  useEffect(() => {
+    if (!paywall?.orderId) return
+    const timer = window.setInterval(() => checkPayment(true), 5000)
+    return () => window.clearInterval(timer)
+  }, [paywall?.orderId])
+
   const handlePasswordSubmit = (e: React.FormEvent) => {
     e.preventDefault()
     if (!password.trim()) {
@@ -230,6 +844,7 @@ function ShareView() {
     }))
   }
   const anchorItems = buildAnchorItems(tocTree)
+  const tocStyle = tocTree.length > 0 ? share?.toc || 'side' : 'off'
 
   if (loading) {
     return (
@@ -239,6 +854,117 @@ function ShareView() {
     )
   }
 
+  if (restricted) {
+    const loggedIn = !!localStorage.getItem('session_token')
+    return (
+      <div className="share-view-password">
+        <div className="password-card">
+          <Title level={3}>此分享仅限受邀读者访问</Title>
+          <Text type="secondary">
+            {loggedIn ? '当前账号不在访问名单中。' : '请登录受邀账号。'}
+            {restricted.emailAccess && '受邀邮箱也可以通过邮件链接访问。'}
+          </Text>
+          {restricted.emailAccess && (accessSent ? (
+            <Alert style={{ marginTop: 16 }} type="success" showIcon message="如果该邮箱在访问名单中，你将收到一封包含访问链接的邮件" />
+          ) : (
+            <form onSubmit={handleAccessRequest}>
+              <Input
+                size="large"
+                type="email"
+                value={accessEmail}
+                onChange={(e) => setAccessEmail(e.target.value)}
+                placeholder="受邀邮箱"
+              />
+              <Button type="primary" htmlType="submit" size="large" block style={{ marginTop: '16px' }}>
+                发送访问链接
+              </Button>
+            </form>
+          ))}
+          {!loggedIn && (
+            <Button type="link" style={{ marginTop: 8 }} onClick={() => navigate('/')}>登录</Button>
+          )}
+        </div>
+      </div>
+    )
+  }
+
+  if (notOpen) {
+    const opensAt = notOpen.opensAt ? new Date(notOpen.opensAt) : null
+    return (
+      <div className="share-view-password">
+        <div className="password-card">
+          <Title level={3}>{opensAt ? '此分享尚未开放' : '此分享已关闭'}</Title>
+          {opensAt ? (
+            <>
+              <Statistic.Countdown
+                value={opensAt.getTime()}
+                format="D 天 HH:mm:ss"
+                onFinish={() => loadShare()}
+              />
+              <Text type="secondary">
+                将于 {opensAt.toLocaleString('zh-CN', { timeZone: notOpen.timezone || undefined })}
+                {notOpen.timezone && `（${notOpen.timezone}）`}开放
+              </Text>
+            </>
+          ) : (
+            <Text type="secondary">分享的开放时间已经结束。</Text>
+          )}
+        </div>
+      </div>
+    )
+  }
+
+  if (termsGate) {
+    return (
+      <div className="share-view-password">
+        <div className="password-card" style={{ maxWidth: 720 }}>
+          <Title level={3}>使用条款</Title>
+          <Text type="secondary">阅读前请确认你已了解并同意以下条款。</Text>
+          <div className="markdown-body" style={{ maxHeight: '50vh', overflow: 'auto', margin: '16px 0', textAlign: 'left' }}>
+            <ReactMarkdown remarkPlugins={[remarkGfm]}>{termsGate.terms}</ReactMarkdown>
+          </div>
+          <Input
+            size="large"
+            value={readerName}
+            onChange={(e) => setReaderName(e.target.value)}
+            maxLength={100}
+            placeholder="姓名（可选）"
+          />
+          <Button type="primary" size="large" block loading={accepting} style={{ marginTop: '16px' }} onClick={handleAcceptTerms}>
+            同意并继续
+          </Button>
+        </div>
+      </div>
+    )
+  }
+
+  if (paywall && !share?.teaser) {
+    return (
+      <div className="share-view-password">
+        <div className="password-card">
+          <Title level={3}>付费阅读</Title>
+          <Text type="secondary">
+            {paywall.price ? `支付 ${paywall.price} 后即可阅读全文。` : '支付后即可阅读全文。'}
+          </Text>
+          {paywall.payable ? (
+            <>
+              <Button type="primary" size="large" block loading={paying} style={{ marginTop: '16px' }} onClick={handlePurchase}>
+                {paywall.orderId ? '重新购买' : '购买'}
+              </Button>
+              {paywall.orderId && (
+                <Button size="large" block style={{ marginTop: '8px' }} onClick={() => checkPayment()}>
+                  我已完成支付
+                </Button>
+              )}
+            </>
+          ) : (
+            <Alert style={{ marginTop: 16 }} type="info" showIcon message="暂时无法在线购买，请联系分享者" />
+          )}
+        </div>
+      </div>
+    )
+  }
+
   if (requirePassword) {
     return (
       <div className="share-view-password">
@@ -268,6 +994,31 @@ function ShareView() {
     )
   }
 
+  if (sealed) {
+    return (
+      <div className="share-view-password">
+        <div className="password-card">
+          <Title level={3}>此分享已加密</Title>
+          <Text type="secondary">正文只能用分享链接 # 之后的密钥在浏览器中解密，服务器无法读取。请使用完整的分享链接，或粘贴密钥。</Text>
+          <form onSubmit={handleKeySubmit}>
+            <Input.Password
+              size="large"
+              value={keyInput}
+              onChange={(e) => setKeyInput(e.target.value)}
+              placeholder="密钥或完整的分享链接"
+              status={keyError ? 'error' : ''}
+              style={{ marginTop: '16px' }}
+            />
+            {keyError && <Text type="danger">{keyError}</Text>}
+            <Button type="primary" htmlType="submit" size="large" block style={{ marginTop: '16px' }}>
+              解密
+            </Button>
+          </form>
+        </div>
+      </div>
+    )
+  }
+
   if (error) {
     const isNotFound = error.toLowerCase().includes('not found') || error.includes('不存在')
     
@@ -342,7 +1093,7 @@ function ShareView() {
     <div className="share-view">
       <Layout>
         {/* 移动端目录按钮 */}
-        {tocTree.length > 0 && (
+        {tocStyle === 'side' && (
           <Button
             className="mobile-toc-button"
             type="primary"
@@ -364,6 +1115,22 @@ function ShareView() {
           />
         )}
 
+        {/* 悬浮目录：固定在正文右上角，可收起 */}
+        {tocStyle === 'floating' && (
+          <div className={`floating-toc ${floatingTocOpen ? 'open' : ''}`}>
+            <Button size="small" icon={<UnorderedListOutlined />} onClick={() => setFloatingTocOpen(!floatingTocOpen)}>
+              目录
+            </Button>
+            {floatingTocOpen && (
+              <Anchor
+                affix={false}
+                items={anchorItems}
+                onClick={() => window.innerWidth <= 768 && setFloatingTocOpen(false)}
+              />
+            )}
+          </div>
+        )}
+
         {/* 移动端抽屉目录 */}
         <Drawer
           title="目录"
@@ -381,7 +1148,7 @@ function ShareView() {
 
         <Layout className="share-layout">
           {/* 桌面端侧边栏目录 */}
-          {tocTree.length > 0 && (
+          {tocStyle === 'side' && (
             <Sider 
               width={250} 
               className="desktop-toc-sider"
@@ -398,6 +1165,7 @@ function ShareView() {
           )}
 
           <Content className="share-content-wrapper">
+            <AnnouncementBanner scope="shares" style={{ marginBottom: 16 }} />
             <div className={`share-header ${headerShrink ? 'shrink' : ''}`}>
               <Title level={1}>{share.docTitle}</Title>
               <div className="share-meta">
@@ -410,15 +1178,149 @@ function ShareView() {
                 <Text type="secondary">
                   过期时间: {new Date(share.expireAt).toLocaleString('zh-CN')}
                 </Text>
+                {!!share.readingMinutes && (
+                  <Text type="secondary">
+                    <ClockCircleOutlined /> 约 {share.readingMinutes} 分钟读完
+                  </Text>
+                )}
+                {share.tasks && share.tasks.total > 0 && (
+                  <span className="share-task-progress">
+                    <Text type="secondary">任务进度</Text>
+                    <Progress
+                      percent={Math.round(share.tasks.done / share.tasks.total * 100)}
+                      size="small"
+                      format={() => `${share.tasks!.done}/${share.tasks!.total}`}
+                    />
+                  </span>
+                )}
+                {!!share.translations?.length && (
+                  <LanguageSwitcher
+                    shareId={share.id}
+                    lang={lang}
+                    sourceLang={lang ? share.sourceLang : share.lang}
+                    translations={share.translations}
+                  />
+                )}
+                {share.allowPdf && (
+                  <Button size="small" icon={<FilePdfOutlined />} href={withAccess(`/api/s/${share.id}/export/pdf`)}>
+                    下载 PDF
+                  </Button>
+                )}
+                {share.allowQa && (
+                  <Button size="small" icon={<QuestionCircleOutlined />} onClick={() => setAskOpen(true)}>
+                    提问
+                  </Button>
+                )}
+                {share.signature && !lang && (
+                  <Button size="small" icon={<SafetyCertificateOutlined />} onClick={() => setVerifyOpen(true)}>
+                    校验内容
+                  </Button>
+                )}
+                {resumeUrl && (
+                  <Button size="small" icon={<LinkOutlined />} onClick={copyResumeLink}>
+                    续读链接
+                  </Button>
+                )}
+                {offlineSupported() && !share.teaser && !lang && share.mode !== 'encrypted' && (
+                  <Button size="small" icon={<CloudDownloadOutlined />} loading={savingOffline} onClick={toggleOffline}>
+                    {offlineSaved ? '已离线保存' : '离线阅读'}
+                  </Button>
+                )}
+                {share.mode === 'flashcards' && !!share.cardCount && (
+                  <Button
+                    size="small"
+                    type="primary"
+                    icon={<ThunderboltOutlined />}
+                    onClick={() => navigate(`/s/${share.id}/cards`, { state: { password } })}
+                  >
+                    练习闪卡（{share.cardCount} 张）
+                  </Button>
+                )}
               </div>
             </div>
             
+            {share.narration && (
+              <div className="share-narration">
+                <Text type="secondary"><SoundOutlined /> 朗读全文</Text>
+                <audio controls preload="none" src={share.narration.url} aria-label="朗读全文" />
+              </div>
+            )}
+
+            {(share.status === 'draft' || share.status === 'disabled') && (
+              <Alert
+                type="warning"
+                showIcon
+                style={{ marginBottom: 16 }}
+                message={share.status === 'draft' ? '草稿预览' : '已停用的分享'}
+                description={share.status === 'draft'
+                  ? '此分享尚未发布，仅你本人可见。可在分享管理中发布。'
+                  : '此分享已停用，读者无法访问。可在分享管理中重新上线。'}
+              />
+            )}
+
+            {resume && (
+              <Alert
+                type="info"
+                showIcon
+                closable
+                style={{ marginBottom: 16 }}
+                onClose={() => setResume(null)}
+                message={`上次读到 ${Math.round(resume.percent)}%（${new Date(resume.updatedAt).toLocaleString('zh-CN')}）`}
+                action={<Button size="small" type="primary" onClick={() => jumpTo(resume)}>继续阅读</Button>}
+              />
+            )}
+
+            {share.viewLimit && (
+              <Alert
+                type="warning"
+                showIcon
+                style={{ marginBottom: 16 }}
+                message={share.viewLimit.viewsLeft === 0
+                  ? (share.viewLimit.maxViews === 1 ? '阅后即焚：此分享只能打开一次' : '这是此分享的最后一次浏览')
+                  : `此分享还可浏览 ${share.viewLimit.viewsLeft} 次`}
+                description={share.viewLimit.viewsLeft === 0 ? '关闭或刷新页面后将无法再次打开，分享已自动停用。' : undefined}
+              />
+            )}
+
+            {share.seal && (
+              <Alert
+                type={share.seal.intact ? 'info' : 'error'}
+                showIcon
+                icon={<SafetyCertificateOutlined />}
+                style={{ marginBottom: 16 }}
+                message={share.seal.intact
+                  ? `已于 ${new Date(share.seal.sealedAt).toLocaleString('zh-CN')} 封存${share.seal.sealed ? `，保留至 ${new Date(share.seal.sealedUntil).toLocaleString('zh-CN')}` : ''}`
+                  : '正文与封存时的哈希不一致'}
+                description={
+                  <>
+                    <Text type="secondary">SHA-256：</Text>
+                    <Text code copyable style={{ wordBreak: 'break-all' }}>{share.seal.hash}</Text>
+                    <Button type="link" size="small" href={withAccess(`/api/s/${share.id}/seal/source`)} target="_blank">
+                      下载源文校验
+                    </Button>
+                  </>
+                }
+              />
+            )}
+
             <div ref={contentRef} className="markdown-body share-content">
               <ReactMarkdown
                 remarkPlugins={[remarkGfm]}
-                rehypePlugins={[rehypeRaw, rehypeHighlight, rehypeSlug]}
+                rehypePlugins={[rehypeRaw, [rehypeHighlight, { plainText: ['output', 'kanban', 'mindmap', 'markmap'] }], rehypeSlug]}
                 components={{
                   img: ({ src, alt }) => {
+                    // 服务端渲染的白板绘图，附带 Excalidraw 源文件下载
+                    const drawing = src?.match(/\/api\/s\/[^/]+\/drawings\/([A-Za-z0-9_-]+)\.svg$/)
+                    if (drawing) {
+                      return (
+                        <span className="share-drawing">
+                          <Image src={src} alt={alt} preview={{ mask: '点击预览' }} />
+                          <a className="share-drawing-source" href={src!.replace(/\.svg$/, '.excalidraw')} download>
+                            {alt || drawing[1]} · 下载 Excalidraw 源文件
+                          </a>
+                        </span>
+                      )
+                    }
                     return (
                       <Image
                         src={src}
@@ -429,17 +1331,223 @@ function ShareView() {
                         style={{ maxWidth: '100%', height: 'auto' }}
                       />
                     )
-                  }
+                  },
+                  table: ({ node, children, ...props }) => {
+                    // 较大的表格提供排序、筛选与 CSV 下载，按表头所在行与服务端解析结果对应
+                    const line = node?.position?.start.line
+                    const data = line ? share.tables?.find(t => t.line === line) : undefined
+                    if (!data) {
+                      return <table {...props}>{children}</table>
+                    }
+                    return <DataTable table={data} csvUrl={withAccess(`/api/s/${shareId}/tables/${data.index}/csv`)} />
+                  },
+                  audio: mediaWithTranscript('audio'),
+                  video: mediaWithTranscript('video'),
+                  h1: headingWithProgress('h1'),
+                  h2: headingWithProgress('h2'),
+                  h3: headingWithProgress('h3'),
+                  h4: headingWithProgress('h4'),
+                  h5: headingWithProgress('h5'),
+                  h6: headingWithProgress('h6'),
+                  pre: ({ node, children, ...props }) => {
+                    // output 代码块是上一个代码块保存的运行结果，与源码区分显示
+                    const code = node?.children[0]
+                    const classes = code?.type === 'element' ? code.properties?.className : undefined
+                    // kanban 代码块（思源看板视图）渲染为带进度的看板
+                    if (code?.type === 'element' && Array.isArray(classes) && classes.includes('language-kanban')) {
+                      const source = code.children.map(c => (c.type === 'text' ? c.value : '')).join('')
+                      return <KanbanBoard source={source} />
+                    }
+                    // mindmap / markmap 代码块渲染为思维导图，按代码块起始行对应服务端解析结果
+                    if (Array.isArray(classes) && (classes.includes('language-mindmap') || classes.includes('language-markmap'))) {
+                      const line = node?.position?.start.line
+                      const data = mindmaps.find(m => m.line === line && m.root)
+                      if (data) return <MindMap data={data} />
+                    }
+                    // poll 代码块渲染为投票，按代码块起始行对应服务端解析结果
+                    if (Array.isArray(classes) && classes.includes('language-poll')) {
+                      const line = node?.position?.start.line
+                      const poll = polls.find(p => p.line === line)
+                      if (poll) {
+                        return (
+                          <PollBlock
+                            shareId={share.id}
+                            poll={poll}
+                            fingerprint={fingerprint}
+                            password={password || undefined}
+                            onVoted={updated => setPolls(prev => prev.map(p => (p.key === updated.key ? updated : p)))}
+                          />
+                        )
+                      }
+                    }
+                    // form 代码块渲染为表单，按代码块起始行对应服务端解析结果
+                    if (Array.isArray(classes) && classes.includes('language-form')) {
+                      const line = node?.position?.start.line
+                      const form = forms.find(f => f.line === line)
+                      if (form) return <FormBlock shareId={share.id} form={form} password={password || undefined} />
+                    }
+                    if (Array.isArray(classes) && classes.includes('language-output')) {
+                      return <pre {...props} className="code-output">{children}</pre>
+                    }
+                    // 服务端配置了外部渲染插件的代码块显示渲染出的图片
+                    const rendered = renders.find(r => r.line === node?.position?.start.line)
+                    if (rendered) {
+                      return <RenderedBlock data={rendered} fallback={<pre {...props}>{children}</pre>} />
+                    }
+                    return <pre {...props}>{children}</pre>
+                  },
+                  a: ({ node, href, children, ...props }) => {
+                    // 已存档的外部链接附带存档副本入口，原链接失效时仍可查阅
+                    const archived = href ? share.archivedLinks?.[href] : undefined
+                    if (href && share.assetDownloads && share.assetDownloads !== 'allow' &&
+                      assetLinkPattern.test(href) && !inlineAssetPattern.test(href)) {
+                      return <a href={href} {...props} onClick={(e) => { e.preventDefault(); downloadAsset(href) }}>{children}</a>
+                    }
+                    const link = <a href={href} {...props}>{children}</a>
+                    if (!archived) {
+                      return link
+                    }
+                    return (
+                      <>
+                        {link}
+                        <a className="archived-link" href={withAccess(archived)} target="_blank" rel="noopener noreferrer" title="查看存档副本">存档</a>
+                      </>
+                    )
+                  },
+                  p: ({ node, children, ...props }) => {
+                    // 独占一段的外部链接渲染为链接卡片
+                    const only = node?.children.length === 1 ? node.children[0] : undefined
+                    const href = only?.type === 'element' && only.tagName === 'a' ? String(only.properties?.href ?? '') : ''
+                    const preview = href ? share.linkPreviews?.[href] : undefined
+                    if (!preview) {
+                      return <p {...props}>{children}</p>
+                    }
+                    const archived = share.archivedLinks?.[href]
+                    return (
+                      <a className="link-preview-card" href={preview.url} target="_blank" rel="noopener noreferrer">
+                        <span className="link-preview-text">
+                          <span className="link-preview-title">{preview.title}</span>
+                          {preview.description && <span className="link-preview-desc">{preview.description}</span>}
+                          <span className="link-preview-site">
+                            {preview.siteName}
+                            {archived && <> · <span className="link-preview-archived" onClick={(e) => { e.preventDefault(); window.open(withAccess(archived), '_blank', 'noopener') }}>存档副本</span></>}
+                          </span>
+                        </span>
+                        {preview.image && <img className="link-preview-image" src={preview.image} alt="" loading="lazy" />}
+                      </a>
+                    )
+                  },
                 }}
               >
                 {share.content}
               </ReactMarkdown>
+              {share.bibliography && share.bibliography.length > 0 && (
+                <section className="bibliography">
+                  <h2 id="references">参考文献</h2>
+                  <ol>
+                    {share.bibliography.map(entry => (
+                      <li key={entry.id} id={entry.anchor} data-cite={entry.id}>
+                        {entry.text}
+                      </li>
+                    ))}
+                  </ol>
+                  <a href={withAccess(`/api/s/${shareId}/citations?download=true`)} className="bibliography-export">
+                    导出 CSL-JSON
+                  </a>
+                </section>
+              )}
             </div>
 
+            {share.teaser && (
+              <div className="share-teaser">
+                <Title level={4}>{share.teaser.gate === 'payment' ? '付费阅读' : '试读结束'}</Title>
+                {share.teaser.gate === 'password' && (
+                  <form onSubmit={handlePasswordSubmit}>
+                    <Text type="secondary">输入访问密码后即可阅读全文。</Text>
+                    <Input.Password
+                      size="large"
+                      value={password}
+                      onChange={(e) => setPassword(e.target.value)}
+                      placeholder="请输入访问密码"
+                      status={passwordError ? 'error' : ''}
+                      style={{ marginTop: '12px' }}
+                    />
+                    {passwordError && <Text type="danger">{passwordError}</Text>}
+                    <Button type="primary" htmlType="submit" size="large" block style={{ marginTop: '16px' }}>
+                      阅读全文
+                    </Button>
+                  </form>
+                )}
+                {share.teaser.gate === 'payment' && paywall && (
+                  <>
+                    <Text type="secondary">
+                      {paywall.price ? `支付 ${paywall.price} 后即可阅读全文。` : '支付后即可阅读全文。'}
+                    </Text>
+                    {paywall.payable ? (
+                      <>
+                        <Button type="primary" size="large" block loading={paying} style={{ marginTop: '16px' }} onClick={handlePurchase}>
+                          {paywall.orderId ? '重新购买' : '购买'}
+                        </Button>
+                        {paywall.orderId && (
+                          <Button size="large" block style={{ marginTop: '8px' }} onClick={() => checkPayment()}>
+                            我已完成支付
+                          </Button>
+                        )}
+                      </>
+                    ) : (
+                      <Alert style={{ marginTop: 16 }} type="info" showIcon message="暂时无法在线购买，请联系分享者" />
+                    )}
+                  </>
+                )}
+                {share.teaser.gate === 'login' && (
+                  <>
+                    <Text type="secondary">登录后即可阅读全文。</Text>
+                    <Button type="primary" size="large" block style={{ marginTop: '16px' }} onClick={() => navigate('/')}>
+                      登录
+                    </Button>
+                  </>
+                )}
+              </div>
+            )}
+
+            {share.allowFeedback && !share.teaser && share.status !== 'draft' && share.status !== 'disabled' && (
+              <FeedbackWidget shareId={share.id} password={password || undefined} />
+            )}
+
+            {!share.teaser && share.status !== 'draft' && share.status !== 'disabled' && (
+              <CommentSection shareId={share.id} password={password || undefined} />
+            )}
+
+            {share.donation && <DonationBar donation={share.donation} />}
+
             <div className="share-footer">
               <Text type="secondary">由思源笔记分享插件提供支持</Text>
+              <Button type="link" size="small" onClick={() => setReportOpen(true)}>举报</Button>
             </div>
           </Content>
+          {share.allowQa && (
+            <AskModal
+              open={askOpen}
+              title={`提问 · ${share.docTitle}`}
+              ask={(q) => askShare(share.id, q, password || undefined)}
+              onClose={() => setAskOpen(false)}
+            />
+          )}
+          {share.signature && (
+            <VerifyModal
+              open={verifyOpen}
+              shareId={share.id}
+              signature={share.signature}
+              sourceUrl={withAccess(`/api/s/${share.id}/signature/source`)}
+              onClose={() => setVerifyOpen(false)}
+            />
+          )}
+          <ReportModal
+            open={reportOpen}
+            shareId={share.id}
+            password={password || undefined}
+            onClose={() => setReportOpen(false)}
+          />
         </Layout>
       </Layout>
     </div>
@@ -17,6 +17,9 @@ export class ShareDialog {
     private passwordHr!: HTMLElement;
     private passwordInput!: HTMLInputElement;
     private expireDaysInput!: HTMLInputElement;
+    private flashcardsCheckbox!: HTMLInputElement;
+    private encryptedCheckbox!: HTMLInputElement;
+    private draftCheckbox!: HTMLInputElement;
     private confirmBtn!: HTMLButtonElement;
     private copyBtn!: HTMLButtonElement | null;
     private existingUrlInput!: HTMLInputElement;
@@ -110,6 +113,39 @@ export class ShareDialog {
                         <input class="b3-text-field fn__flex-center fn__size200" id="shareExpireDays" type="number" min="1" max="365" />
                     </label>
 
+                    <div class="fn__hr"></div>
+
+                    <label class="fn__flex b3-label config__item">
+                        <div class="fn__flex-1">
+                            ${this.plugin.i18n.shareDialogFlashcards}
+                            <div class="b3-label__text">${this.plugin.i18n.shareDialogFlashcardsDesc}</div>
+                        </div>
+                        <span class="fn__space"></span>
+                        <input class="b3-switch fn__flex-center" id="shareFlashcards" type="checkbox" />
+                    </label>
+
+                    <div class="fn__hr"></div>
+
+                    <label class="fn__flex b3-label config__item">
+                        <div class="fn__flex-1">
+                            ${this.plugin.i18n.shareDialogEncrypted}
+                            <div class="b3-label__text">${this.plugin.i18n.shareDialogEncryptedDesc}</div>
+                        </div>
+                        <span class="fn__space"></span>
+                        <input class="b3-switch fn__flex-center" id="shareEncrypted" type="checkbox" />
+                    </label>
+
+                    <div class="fn__hr"></div>
+
+                    <label class="fn__flex b3-label config__item">
+                        <div class="fn__flex-1">
+                            ${this.plugin.i18n.shareDialogDraft}
+                            <div class="b3-label__text">${this.plugin.i18n.shareDialogDraftDesc}</div>
+                        </div>
+                        <span class="fn__space"></span>
+                        <input class="b3-switch fn__flex-center" id="shareDraft" type="checkbox" />
+                    </label>
+
                     <div class="fn__hr" id="uploadProgressHr" style="display: none;"></div>
 
                     <div id="uploadProgressContainer" style="display: none;">
@@ -162,6 +198,9 @@ export class ShareDialog {
         this.passwordHr = this.dialog.element.querySelector("#sharePasswordHr") as HTMLElement;
         this.passwordInput = this.dialog.element.querySelector("#sharePassword") as HTMLInputElement;
         this.expireDaysInput = this.dialog.element.querySelector("#shareExpireDays") as HTMLInputElement;
+        this.flashcardsCheckbox = this.dialog.element.querySelector("#shareFlashcards") as HTMLInputElement;
+        this.encryptedCheckbox = this.dialog.element.querySelector("#shareEncrypted") as HTMLInputElement;
+        this.draftCheckbox = this.dialog.element.querySelector("#shareDraft") as HTMLInputElement;
         this.confirmBtn = this.dialog.element.querySelector("#shareConfirmBtn") as HTMLButtonElement;
         this.copyBtn = this.dialog.element.querySelector("#shareCopyCurrentBtn");
         this.existingUrlInput = this.dialog.element.querySelector("#shareExistingUrl") as HTMLInputElement;
@@ -192,6 +231,15 @@ export class ShareDialog {
             ? Math.max(1, Math.ceil((this.existingRecord.expireAt - Date.now()) / DAY_IN_MS))
             : config.defaultExpireDays;
         this.expireDaysInput.value = String(expireValue);
+        this.flashcardsCheckbox.checked = hasActive && this.existingRecord?.mode === "flashcards";
+        // 已有分享不能在加密与普通模式之间切换，需先停止分享
+        this.encryptedCheckbox.checked = hasActive && this.existingRecord?.mode === "encrypted";
+        this.encryptedCheckbox.disabled = hasActive;
+        this.flashcardsCheckbox.disabled = this.encryptedCheckbox.checked;
+        // 已发布的分享不能再回到草稿
+        const isDraft = hasActive && this.existingRecord?.status === "draft";
+        this.draftCheckbox.checked = isDraft;
+        this.draftCheckbox.disabled = hasActive && !isDraft;
 
         this.confirmBtn.textContent = hasActive
             ? this.plugin.i18n.shareDialogUpdate
@@ -230,6 +278,14 @@ export class ShareDialog {
             }
         });
 
+        // 加密分享不包含闪卡
+        this.encryptedCheckbox.addEventListener("change", () => {
+            if (this.encryptedCheckbox.checked) {
+                this.flashcardsCheckbox.checked = false;
+            }
+            this.flashcardsCheckbox.disabled = this.encryptedCheckbox.checked;
+        });
+
         const cancelBtn = this.dialog.element.querySelector(".b3-button--cancel") as HTMLButtonElement;
         cancelBtn.addEventListener("click", () => {
             this.dialog.destroy();
@@ -307,6 +363,12 @@ export class ShareDialog {
             password: password || undefined,
             expireDays,
             isPublic,
+            flashcards: this.flashcardsCheckbox.checked,
+            encrypted: this.encryptedCheckbox.checked,
+            // 取消草稿时正式发布，其余情况保持服务端的现有状态
+            status: this.draftCheckbox.checked
+                ? "draft"
+                : (this.existingRecord?.status === "draft" ? "published" : undefined),
         };
 
         const s3Enabled = this.plugin.settings.getConfig().s3?.enabled;
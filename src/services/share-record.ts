@@ -1,9 +1,12 @@
 import type SharePlugin from "../index";
-import type { ShareRecord } from "../types";
+import type { EncryptedShareKey, ShareRecord } from "../types";
+import { withShareKey } from "../utils/e2e-crypto";
 
 export class ShareRecordManager {
     private plugin: SharePlugin;
     private records: ShareRecord[] = [];
+    // 端到端加密分享的密钥，按文档 ID 保存；服务器不知道密钥，同步后的记录据此补全链接与标题
+    private keys: Record<string, EncryptedShareKey> = {};
     private syncInterval: number = 5 * 60 * 1000; // 基准 5 分钟
     private syncTimer: number | null = null;
     private syncing = false;
@@ -21,6 +24,10 @@ export class ShareRecordManager {
         if (localRecords && Array.isArray(localRecords)) {
             this.records = localRecords;
         }
+        const keys = await this.plugin.loadData("share-keys");
+        if (keys && typeof keys === "object") {
+            this.keys = keys;
+        }
 
         // 2. 从后端同步
         await this.syncFromBackend();
@@ -111,7 +118,7 @@ export class ShareRecordManager {
                 const items = (result.data?.items || []) as any[];
                 for (const it of items) {
                     if (it.docId === docId) {
-                        found = {
+                        found = this.withKey({
                             id: it.id,
                             docId: it.docId,
                             docTitle: it.docTitle,
@@ -122,7 +129,7 @@ export class ShareRecordManager {
                             createdAt: new Date(it.createdAt).getTime(),
                             updatedAt: new Date(it.createdAt).getTime(),
                             viewCount: it.viewCount,
-                        };
+                        });
                         break;
                     }
                 }
@@ -168,6 +175,45 @@ export class ShareRecordManager {
         }
     }
 
+    /**
+     * 获取文档的端到端加密密钥，更新加密分享时沿用，已发出的链接保持有效
+     */
+    getEncryptionKey(docId: string): EncryptedShareKey | null {
+        return this.keys[docId] || null;
+    }
+
+    /**
+     * 保存文档的端到端加密密钥
+     */
+    async setEncryptionKey(docId: string, key: EncryptedShareKey): Promise<void> {
+        this.keys[docId] = key;
+        await this.plugin.saveData("share-keys", this.keys);
+    }
+
+    /**
+     * 为加密分享的记录补全密钥片段与本地保存的标题
+     */
+    private withKey(record: ShareRecord): ShareRecord {
+        const key = this.keys[record.docId];
+        if (!key) {
+            return record;
+        }
+        return { ...record, docTitle: key.title, shareUrl: withShareKey(record.shareUrl, key.key), mode: "encrypted" };
+    }
+
+    /**
+     * 删除已不在任何记录中的文档的密钥
+     */
+    private async pruneKeys(): Promise<void> {
+        const docIds = new Set(this.records.map(r => r.docId));
+        const stale = Object.keys(this.keys).filter(docId => !docIds.has(docId));
+        if (stale.length === 0) {
+            return;
+        }
+        stale.forEach(docId => delete this.keys[docId]);
+        await this.plugin.saveData("share-keys", this.keys);
+    }
+
     /**
      * 添加分享记录
      */
@@ -195,6 +241,7 @@ export class ShareRecordManager {
     async removeRecord(shareId: string): Promise<void> {
         this.records = this.records.filter(r => r.id !== shareId);
         await this.saveToLocal();
+        await this.pruneKeys();
     }
 
     /**
@@ -207,6 +254,7 @@ export class ShareRecordManager {
         const idSet = new Set(shareIds);
         this.records = this.records.filter(r => !idSet.has(r.id));
         await this.saveToLocal();
+        await this.pruneKeys();
     }
 
     /**
@@ -215,6 +263,7 @@ export class ShareRecordManager {
     async clearAll(): Promise<void> {
         this.records = [];
         await this.saveToLocal();
+        await this.pruneKeys();
     }
 
     /**
@@ -300,7 +349,7 @@ export class ShareRecordManager {
                 const items = (result.data?.items || []) as any[];
                 // 映射为 ShareRecord（缺失内容字段，通过本地结构）
                 for (const it of items) {
-                    all.push({
+                    all.push(this.withKey({
                         id: it.id,
                         docId: it.docId,
                         docTitle: it.docTitle,
@@ -310,7 +359,7 @@ export class ShareRecordManager {
                         isPublic: it.isPublic,
                         createdAt: new Date(it.createdAt).getTime(),
                         updatedAt: new Date(it.createdAt).getTime(),
-                    });
+                    }));
                 }
                 const total = result.data?.total || 0;
                 if (all.length >= total || all.length >= 1000 || items.length === 0) {
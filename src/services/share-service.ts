@@ -1,8 +1,13 @@
 import { showMessage } from "siyuan";
 import type SharePlugin from "../index";
-import type { AssetUploadRecord, BatchDeleteShareResponse, BlockReference, KramdownResponse, ShareOptions, ShareRecord, ShareResponse, UploadProgressCallback } from "../types";
+import type { AssetUploadRecord, BatchDeleteShareResponse, BlockReference, Flashcard, KramdownResponse, ShareOptions, ShareRecord, ShareResponse, UploadProgressCallback } from "../types";
+import { AttributeViewResolver } from "../utils/attribute-view-resolver";
 import { BlockReferenceResolver } from "../utils/block-reference-resolver";
+import { DrawingResolver } from "../utils/drawing-resolver";
+import { encryptShare, generateShareKey, withShareKey } from "../utils/e2e-crypto";
+import { FlashcardResolver } from "../utils/flashcard-resolver";
 import { parseKramdownToMarkdown } from "../utils/kramdown-parser";
+import { formatQuotaStatus } from "../utils/quota";
 import { S3UploadService } from "./s3-upload";
 
 export class ShareService {
@@ -34,10 +39,49 @@ export class ShareService {
         }
 
         // 1. 导出文档内容及引用块
-        const { content, references } = await this.exportDocContentWithRefs(options.docId);
-        if (!content) {
+        const exported = await this.exportDocContentWithRefs(options.docId);
+        if (!exported.content) {
             throw new Error(this.plugin.i18n.shareErrorExportFailed);
         }
+        const { references } = exported;
+
+        // 端到端加密：正文与标题在本地加密，图片内联到密文中；引用块、白板与闪卡在服务器上以明文保存，不随加密分享发布
+        if (options.encrypted) {
+            if (options.flashcards) {
+                throw new Error(this.plugin.i18n.shareErrorEncryptedFlashcards || "加密分享不支持闪卡模式");
+            }
+            const saved = this.plugin.shareRecordManager.getEncryptionKey(options.docId);
+            const key = saved?.key || generateShareKey();
+            const content = await this.inlineAssets(exported.content);
+            const payload = {
+                docId: options.docId,
+                // 服务器只保存文档 ID 作为标题，真实标题在密文中
+                docTitle: options.docId,
+                content: await encryptShare(key, options.docTitle, content),
+                requirePassword: options.requirePassword,
+                password: options.requirePassword ? options.password ?? "" : "",
+                expireDays: options.expireDays,
+                isPublic: options.isPublic,
+                mode: "encrypted",
+                status: options.status,
+                templateId: options.templateId,
+            };
+            await this.plugin.shareRecordManager.setEncryptionKey(options.docId, { key, title: options.docTitle });
+            return this.publish(options, payload, [], key);
+        }
+
+        // 白板绘图随分享发布，链接替换为 drawing:ID，不再作为普通资源上传
+        const { content, drawings } = await new DrawingResolver({ siyuanToken: config.siyuanToken })
+            .collect(exported.content);
+
+        // 闪卡模式：收集文档中已制卡的块
+        let flashcards: Flashcard[] = [];
+        if (options.flashcards) {
+            flashcards = await new FlashcardResolver({ siyuanToken: config.siyuanToken }).collect(options.docId);
+            if (flashcards.length === 0) {
+                throw new Error(this.plugin.i18n.shareErrorNoFlashcards || "文档中没有闪卡");
+            }
+        }
 
         // 2. 处理资源上传（如果启用了 S3）
         let processedContent = content;
@@ -52,6 +96,11 @@ export class ShareService {
                 );
                 processedContent = result.content;
                 uploadedAssets = result.assets;
+                flashcards = flashcards.map(card => ({
+                    ...card,
+                    front: this.replaceAssetLinks(card.front, uploadedAssets),
+                    back: this.replaceAssetLinks(card.back, uploadedAssets),
+                }));
             } catch (error) {
                 console.error("资源上传失败:", error);
                 showMessage(
@@ -74,8 +123,27 @@ export class ShareService {
             isPublic: options.isPublic,
             references: references, // 包含引用块信息
             assets: uploadedAssets, // 包含上传的资源信息
+            mode: options.flashcards ? "flashcards" : "doc",
+            flashcards: options.flashcards ? flashcards : undefined,
+            drawings,
+            status: options.status,
+            templateId: options.templateId,
         };
 
+        return this.publish(options, payload, uploadedAssets);
+    }
+
+    /**
+     * 调用后端 API 发布并保存本地记录；加密分享的链接附加密钥片段，记录使用本地标题
+     */
+    private async publish(
+        options: ShareOptions,
+        payload: any,
+        uploadedAssets: AssetUploadRecord[],
+        encryptionKey?: string
+    ): Promise<ShareRecord> {
+        const config = this.plugin.settings.getConfig();
+
         // 4. 调用后端 API
         try {
             const response = await this.callShareAPI(config.serverUrl, config.apiToken, payload);
@@ -89,18 +157,43 @@ export class ShareService {
             const record: ShareRecord = {
                 id: shareData.shareId,
                 docId: shareData.docId || options.docId,
-                docTitle: shareData.docTitle || options.docTitle,
-                shareUrl: shareData.shareUrl,
+                docTitle: encryptionKey ? options.docTitle : shareData.docTitle || options.docTitle,
+                shareUrl: encryptionKey ? withShareKey(shareData.shareUrl, encryptionKey) : shareData.shareUrl,
                 requirePassword: shareData.requirePassword,
                 expireAt: new Date(shareData.expireAt).getTime(),
                 isPublic: shareData.isPublic,
                 createdAt: new Date(shareData.createdAt).getTime(),
                 updatedAt: new Date(shareData.updatedAt).getTime(),
                 reused: shareData.reused,
+                mode: shareData.mode,
+                status: shareData.status,
             };
 
             await this.plugin.shareRecordManager.addRecord(record);
 
+            // 提示正文引用但服务器上缺失的资源，避免公开页面出现失效的图片
+            const missing = shareData.missingAssets ?? [];
+            if (missing.length > 0) {
+                const paths = missing.slice(0, 5).map(asset => asset.path).join(", ");
+                const more = missing.length > 5 ? ` (+${missing.length - 5})` : "";
+                showMessage(
+                    `${this.plugin.i18n.shareMissingAssets || "以下资源未上传，公开页面中将无法显示"}: ${paths}${more}`,
+                    8000,
+                    "error"
+                );
+            }
+
+            // 提示接近或超出配额，避免下次发布或上传时才发现被拒绝
+            const quotaWarnings = shareData.quotaWarnings ?? [];
+            if (quotaWarnings.length > 0) {
+                const lines = quotaWarnings.map(status => formatQuotaStatus(this.plugin.i18n, status)).join("; ");
+                showMessage(
+                    `${this.plugin.i18n.shareQuotaWarning || "用量接近配额"}: ${lines}`,
+                    8000,
+                    quotaWarnings.some(status => status.state !== "warning") ? "error" : "info"
+                );
+            }
+
             // 6. 保存资源映射记录到本地
             if (uploadedAssets.length > 0) {
                 await this.plugin.assetRecordManager.addOrUpdateMapping(
@@ -182,8 +275,9 @@ export class ShareService {
                 引用块ID列表: references.map(r => r.blockId),
             });
 
-            // 3. 将 Kramdown 转换为 Markdown
-            const markdown = parseKramdownToMarkdown(kramdownContent);
+            // 3. 展开属性视图（数据库表格 / 看板）并将 Kramdown 转换为 Markdown
+            const expanded = await new AttributeViewResolver({ siyuanToken: config.siyuanToken }).expand(kramdownContent);
+            const markdown = parseKramdownToMarkdown(expanded);
             
             if (!markdown) {
                 console.error("Kramdown 解析结果为空", { docId, kramdownLength: kramdownContent.length });
@@ -268,7 +362,8 @@ export class ShareService {
 
             // 使用解析器将 Kramdown 转换为 Markdown
             try {
-                const markdown = parseKramdownToMarkdown(result.data.kramdown);
+                const expanded = await new AttributeViewResolver({ siyuanToken: config.siyuanToken }).expand(result.data.kramdown);
+                const markdown = parseKramdownToMarkdown(expanded);
                 
                 if (!markdown) {
                     console.error("Kramdown 解析结果为空", { docId, kramdownLength: result.data.kramdown.length });
@@ -489,7 +584,13 @@ export class ShareService {
             uploadedAssets.push(...uploaded);
         }
 
-        // 替换内容中的资源链接
+        return { content: this.replaceAssetLinks(content, uploadedAssets), assets: uploadedAssets };
+    }
+
+    /**
+     * 将内容中的本地资源链接替换为已上传的地址
+     */
+    private replaceAssetLinks(content: string, uploadedAssets: AssetUploadRecord[]): string {
         let processedContent = content;
         for (const asset of uploadedAssets) {
             // 替换 Markdown 图片链接
@@ -511,7 +612,7 @@ export class ShareService {
             processedContent = processedContent.replace(urlPattern, asset.s3Url);
         }
 
-        return { content: processedContent, assets: uploadedAssets };
+        return processedContent;
     }
 
     /**
@@ -567,6 +668,28 @@ export class ShareService {
         return path.startsWith('assets/') || path.startsWith('/assets/');
     }
 
+    /**
+     * 将正文中的本地图片内联为 data URI，加密分享的图片随正文一起加密，不上传到服务器
+     */
+    private async inlineAssets(content: string): Promise<string> {
+        let processedContent = content;
+        for (const assetPath of this.extractAssetPaths(content)) {
+            const file = await this.fetchAssetFile(assetPath);
+            if (!file || !file.type.startsWith("image/")) {
+                continue;
+            }
+            const dataUri = await new Promise<string>((resolve, reject) => {
+                const reader = new FileReader();
+                reader.onload = () => resolve(reader.result as string);
+                reader.onerror = () => reject(reader.error);
+                reader.readAsDataURL(file);
+            });
+            const imagePattern = new RegExp(`(!\\[[^\\]]*\\]\\()${this.escapeRegex(assetPath)}\\)`, "g");
+            processedContent = processedContent.replace(imagePattern, `$1${dataUri})`);
+        }
+        return processedContent;
+    }
+
     /**
      * 获取资源文件
      */
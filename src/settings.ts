@@ -2,7 +2,8 @@ import { Setting } from "siyuan";
 import { AssetListView } from "./components/asset-list-view";
 import { ShareListDialog } from "./components/share-list";
 import type SharePlugin from "./index";
-import type { S3Config } from "./types";
+import type { CapabilitiesResponse, S3Config } from "./types";
+import { formatQuotaStatus } from "./utils/quota";
 
 export interface ShareConfig {
     serverUrl: string;
@@ -504,6 +505,23 @@ export class ShareSettings {
         });
     }
 
+    /**
+     * 查询服务端能力与配额状态，返回接近或超出配额的提示；旧版本后端没有 /api/capabilities 时不提示
+     */
+    private async quotaLines(base: string, fetchWithToken: (url: string) => Promise<Response>): Promise<string[]> {
+        try {
+            const response = await fetchWithToken(`${base}/api/capabilities`);
+            if (!response.ok) return [];
+            const json: CapabilitiesResponse = await response.json();
+            const items = json?.data?.quota?.items ?? [];
+            return items
+                .filter(item => item.state !== "ok")
+                .map(item => `${item.state === "blocked" ? "❌" : "⚠️"} ${formatQuotaStatus(this.plugin.i18n, item)}`);
+        } catch {
+            return [];
+        }
+    }
+
     isConfigured(): boolean {
         return !!(this.config.serverUrl && this.config.apiToken && this.config.siyuanToken);
     }
@@ -560,6 +578,7 @@ export class ShareSettings {
                         if (json && json.code === 0) {
                             const userID = json?.data?.userID || "unknown";
                             results.push(`✅ ${this.plugin.i18n.testBackendSuccess} (用户: ${userID})`);
+                            results.push(...await this.quotaLines(base, fetchWithToken));
                         } else {
                             results.push(`❌ ${this.plugin.i18n.testBackendFailed}: 返回格式异常或 code!=0`);
                             hasError = true;
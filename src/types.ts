@@ -24,6 +24,36 @@ export interface ShareOptions {
     password?: string;
     expireDays: number;
     isPublic: boolean;
+    flashcards?: boolean; // 以闪卡卡组模式分享
+    encrypted?: boolean; // 端到端加密：正文在本地加密，密钥只附在分享链接的 # 片段中
+    status?: "draft" | "published"; // 保存为草稿或发布草稿，不传则保持原状态
+    templateId?: string; // 发布模板（在网页端「发布模板」中管理），提供主题、目录样式与默认密码等设置
+}
+
+/**
+ * 分享生命周期状态
+ */
+export type ShareStatus = "draft" | "published" | "unlisted" | "disabled";
+
+/**
+ * 闪卡（正反面为 Markdown）
+ */
+export interface Flashcard {
+    id: string;
+    blockId?: string;
+    front: string;
+    back: string;
+    tags?: string[];
+}
+
+/**
+ * 白板绘图（Excalidraw 场景）
+ */
+export interface Drawing {
+    id: string;
+    name?: string;
+    format: "excalidraw";
+    scene: any;
 }
 
 /**
@@ -56,6 +86,16 @@ export interface ShareRecord {
     updatedAt: number;
     viewCount?: number;
     reused?: boolean;
+    mode?: "doc" | "flashcards" | "encrypted";
+    status?: ShareStatus;
+}
+
+/**
+ * 端到端加密分享的密钥与标题，只保存在本地（服务器上保存的标题为文档 ID）
+ */
+export interface EncryptedShareKey {
+    key: string;
+    title: string;
 }
 
 export interface ShareResponse {
@@ -72,9 +112,48 @@ export interface ShareResponse {
         createdAt: string;
         updatedAt: string;
         reused: boolean;
+        mode?: "doc" | "flashcards";
+        status?: ShareStatus;
+        missingAssets?: MissingAsset[]; // 正文引用但服务器上没有对应资源，阅读页中将无法显示
+        quotaWarnings?: QuotaStatus[]; // 用量已达到 80% 以上的配额
+    };
+}
+
+/**
+ * 单项配额的用量状态：warning 接近配额，grace 超出配额但仍在宽限范围内，blocked 已无法继续发布或上传
+ */
+export interface QuotaStatus {
+    resource: "storage" | "shares" | "bandwidth";
+    used: number;
+    limit: number;
+    hardLimit: number;
+    percent: number;
+    state: "ok" | "warning" | "grace" | "blocked";
+}
+
+export interface CapabilitiesResponse {
+    code: number;
+    msg: string;
+    data?: {
+        version: string;
+        limits: {
+            maxAssetBytes: number;
+            maxImportBytes: number;
+            publishDailyQuota: number;
+        };
+        quota: {
+            gracePercent: number;
+            warnLevels: number[];
+            items: QuotaStatus[];
+        };
     };
 }
 
+export interface MissingAsset {
+    path: string;
+    references: number;
+}
+
 export interface ShareListResponse {
     code: number;
     msg: string;
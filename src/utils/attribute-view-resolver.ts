@@ -0,0 +1,226 @@
+/**
+ * 属性视图（数据库）解析器
+ * 思源导出的 Kramdown 中属性视图只是一个空的占位 div，需要调用内核接口取回数据后转换为 Markdown：
+ * - 表格视图 → GFM 表格
+ * - 看板视图或分组视图 → ```kanban 代码块（每个分组一个二级标题，卡片为任务列表项），由阅读页渲染为带进度的看板
+ */
+
+/**
+ * 解析器选项
+ */
+export interface AttributeViewResolverOptions {
+    siyuanToken: string;
+}
+
+interface AVColumn {
+    name?: string;
+    type?: string;
+    hidden?: boolean;
+}
+
+interface AVCell {
+    value?: any;
+}
+
+interface AVRecord {
+    cells?: AVCell[];
+    values?: AVCell[];
+}
+
+interface AVGroup {
+    name?: string;
+    columns?: AVColumn[];
+    fields?: AVColumn[];
+    rows?: AVRecord[];
+    cards?: AVRecord[];
+}
+
+/**
+ * 属性视图占位块: <div data-type="NodeAttributeView" data-av-id="..." data-av-type="table"></div>
+ */
+const AV_BLOCK_PATTERN = /^([ \t]*)<div[^>]*data-type="NodeAttributeView"[^>]*>\s*<\/div>[ \t]*$/gm;
+
+/**
+ * 视为已完成的分组名
+ */
+const DONE_GROUP_PATTERN = /^(done|completed?|closed|finished|resolved|已完成|完成|已关闭|已解决)$/i;
+
+export class AttributeViewResolver {
+    private siyuanToken: string;
+
+    constructor(options: AttributeViewResolverOptions) {
+        this.siyuanToken = options.siyuanToken;
+    }
+
+    /**
+     * 将 Kramdown 中的属性视图占位块替换为 Markdown，获取失败的视图保持原样
+     */
+    async expand(kramdown: string): Promise<string> {
+        const matches = Array.from(kramdown.matchAll(AV_BLOCK_PATTERN));
+        if (matches.length === 0) {
+            return kramdown;
+        }
+
+        const rendered = await Promise.all(matches.map(async (m) => {
+            const avId = m[0].match(/data-av-id="([^"]+)"/)?.[1];
+            if (!avId) {
+                return null;
+            }
+            const markdown = await this.render(avId);
+            if (!markdown) {
+                return null;
+            }
+            return markdown.split("\n").map(line => line ? m[1] + line : line).join("\n");
+        }));
+
+        let index = 0;
+        return kramdown.replace(AV_BLOCK_PATTERN, (match) => {
+            const markdown = rendered[index++];
+            return markdown ?? match;
+        });
+    }
+
+    /**
+     * 获取属性视图当前视图的数据并转换为 Markdown
+     */
+    private async render(avId: string): Promise<string | null> {
+        try {
+            const controller = new AbortController();
+            const timeout = setTimeout(() => controller.abort(), 10000);
+
+            const response = await fetch("/api/av/renderAttributeView", {
+                method: "POST",
+                headers: {
+                    "Content-Type": "application/json",
+                    "Authorization": `Token ${this.siyuanToken}`,
+                },
+                body: JSON.stringify({ id: avId, page: 1, pageSize: -1 }),
+                signal: controller.signal,
+            });
+
+            clearTimeout(timeout);
+
+            if (!response.ok) {
+                console.error(`获取属性视图失败: HTTP ${response.status}`, avId);
+                return null;
+            }
+
+            const result = await response.json();
+            if (result.code !== 0 || !result.data?.view) {
+                console.error("属性视图API返回错误:", result.msg, avId);
+                return null;
+            }
+
+            const { view, viewType, name } = result.data;
+            const grouped = Array.isArray(view.groups) && view.groups.length > 0;
+            if (viewType === "kanban" || grouped) {
+                return renderKanban(name, grouped ? view.groups : [view], view);
+            }
+            return renderTable(name, view);
+        } catch (error) {
+            console.error("获取属性视图异常:", error, avId);
+            return null;
+        }
+    }
+}
+
+/**
+ * 属性值转为纯文本
+ */
+function valueText(value: any): string {
+    if (!value) {
+        return "";
+    }
+    switch (value.type) {
+        case "block":
+            return value.block?.content ?? "";
+        case "text":
+        case "url":
+        case "email":
+        case "phone":
+            return value[value.type]?.content ?? "";
+        case "number":
+            return value.number?.isNotEmpty ? String(value.number.formattedContent || value.number.content) : "";
+        case "select":
+        case "mSelect":
+            return (value.mSelect || []).map((s: any) => s.content).join(", ");
+        case "checkbox":
+            return value.checkbox?.checked ? "✓" : "";
+        case "date":
+        case "created":
+        case "updated": {
+            const d = value[value.type];
+            return d?.isNotEmpty && d.content ? new Date(d.content).toLocaleDateString() : "";
+        }
+        case "relation":
+            return (value.relation?.contents || []).map((c: any) => valueText(c)).join(", ");
+        case "rollup":
+            return (value.rollup?.contents || []).map((c: any) => valueText(c)).join(", ");
+        case "mAsset":
+            return (value.mAsset || []).map((a: any) => a.name || a.content).join(", ");
+        case "template":
+            return value.template?.content ?? "";
+        default:
+            return "";
+    }
+}
+
+/**
+ * 单元格文本转义，避免破坏表格或列表结构
+ */
+function escapeCell(text: string): string {
+    return text.replace(/\|/g, "\\|").replace(/\r?\n/g, "<br>").trim();
+}
+
+function recordValues(record: AVRecord): AVCell[] {
+    return record.cells || record.values || [];
+}
+
+/**
+ * 表格视图转换为 GFM 表格
+ */
+function renderTable(name: string | undefined, view: AVGroup): string | null {
+    const columns = view.columns || view.fields || [];
+    const visible = columns.map((c, i) => ({ c, i })).filter(({ c }) => !c.hidden);
+    if (visible.length === 0) {
+        return null;
+    }
+    const lines: string[] = [];
+    if (name) {
+        lines.push(`**${escapeCell(name)}**`, "");
+    }
+    lines.push("| " + visible.map(({ c }) => escapeCell(c.name || "")).join(" | ") + " |");
+    lines.push("| " + visible.map(() => "---").join(" | ") + " |");
+    for (const record of view.rows || view.cards || []) {
+        const values = recordValues(record);
+        lines.push("| " + visible.map(({ i }) => escapeCell(valueText(values[i]?.value))).join(" | ") + " |");
+    }
+    return lines.join("\n");
+}
+
+/**
+ * 看板 / 分组视图转换为 kanban 代码块：分组为列，卡片标题取主键列，
+ * 有复选框列时以其勾选状态表示完成，否则按分组名判断
+ */
+function renderKanban(name: string | undefined, groups: AVGroup[], view: AVGroup): string | null {
+    const lines: string[] = ["```kanban"];
+    if (name) {
+        lines.push(`# ${name.trim()}`);
+    }
+    for (const group of groups) {
+        const columns = group.columns || group.fields || view.columns || view.fields || [];
+        const checkbox = columns.findIndex(c => c.type === "checkbox");
+        const groupName = (group.name || "").trim() || "未分组";
+        const groupDone = DONE_GROUP_PATTERN.test(groupName);
+        lines.push(`## ${groupName}`);
+        for (const record of group.rows || group.cards || []) {
+            const values = recordValues(record);
+            const keyIndex = columns.findIndex(c => c.type === "block");
+            const title = valueText(values[keyIndex >= 0 ? keyIndex : 0]?.value).replace(/\s+/g, " ").trim();
+            const done = checkbox >= 0 ? !!values[checkbox]?.value?.checkbox?.checked : groupDone;
+            lines.push(`- [${done ? "x" : " "}] ${title || "（无标题）"}`);
+        }
+    }
+    lines.push("```");
+    return lines.length > 2 ? lines.join("\n") : null;
+}
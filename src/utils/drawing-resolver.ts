@@ -0,0 +1,108 @@
+/**
+ * 白板绘图解析器
+ * 文档中链接到 assets/ 下 .excalidraw 文件（Excalidraw 场景 JSON）的图片或链接，
+ * 读取场景随分享一起发布，并将链接替换为 ![名称](drawing:ID)，由服务端渲染为 SVG
+ */
+
+import type { Drawing } from "../types";
+
+/**
+ * 解析器选项
+ */
+export interface DrawingResolverOptions {
+    siyuanToken: string;
+}
+
+/**
+ * 绘图链接: ![alt](assets/xxx.excalidraw) 或 [text](assets/xxx.excalidraw.json "title")
+ */
+const DRAWING_LINK_PATTERN = /!?\[([^\]]*)\]\((\/?assets\/[^)\s]+?\.excalidraw(?:\.json)?)(?:\s+"[^"]*")?\)/g;
+
+export class DrawingResolver {
+    private siyuanToken: string;
+
+    constructor(options: DrawingResolverOptions) {
+        this.siyuanToken = options.siyuanToken;
+    }
+
+    /**
+     * 收集正文引用的绘图并替换链接，读取失败或不是 Excalidraw 场景的链接保持原样
+     */
+    async collect(content: string): Promise<{ content: string; drawings: Drawing[] }> {
+        const matches = Array.from(content.matchAll(DRAWING_LINK_PATTERN));
+        if (matches.length === 0) {
+            return { content, drawings: [] };
+        }
+
+        const byPath = new Map<string, Drawing | null>();
+        const usedIds = new Set<string>();
+        for (const m of matches) {
+            const path = m[2];
+            if (byPath.has(path)) {
+                continue;
+            }
+            const scene = await this.fetchScene(path);
+            if (!scene) {
+                byPath.set(path, null);
+                continue;
+            }
+            const id = uniqueId(drawingId(path), usedIds);
+            byPath.set(path, { id, name: m[1] || fileBaseName(path), format: "excalidraw", scene });
+        }
+
+        const result = content.replace(DRAWING_LINK_PATTERN, (match, alt: string, path: string) => {
+            const drawing = byPath.get(path);
+            return drawing ? `![${alt || drawing.name}](drawing:${drawing.id})` : match;
+        });
+        const drawings = Array.from(byPath.values()).filter((d): d is Drawing => d !== null);
+        return { content: result, drawings };
+    }
+
+    private async fetchScene(path: string): Promise<any | null> {
+        const controller = new AbortController();
+        const timeout = setTimeout(() => controller.abort(), 10000);
+        try {
+            const response = await fetch(path.startsWith("/") ? path : "/" + path, {
+                headers: { "Authorization": `Token ${this.siyuanToken}` },
+                signal: controller.signal,
+            });
+            if (!response.ok) {
+                console.error(`获取绘图失败: HTTP ${response.status}`, path);
+                return null;
+            }
+            const scene = await response.json();
+            if (scene?.type !== "excalidraw" || !Array.isArray(scene.elements)) {
+                console.warn("不是 Excalidraw 场景，按普通资源处理:", path);
+                return null;
+            }
+            return scene;
+        } catch (error) {
+            console.error("获取绘图异常:", error, path);
+            return null;
+        } finally {
+            clearTimeout(timeout);
+        }
+    }
+}
+
+function fileBaseName(path: string): string {
+    const name = decodeURIComponent(path.split("/").pop() || "drawing");
+    return name.replace(/\.excalidraw(\.json)?$/, "");
+}
+
+/**
+ * 由文件名生成服务端接受的 ID（字母、数字、_ 与 -，至多 64 个字符）
+ */
+function drawingId(path: string): string {
+    const id = fileBaseName(path).replace(/[^A-Za-z0-9_-]+/g, "-").replace(/^-+|-+$/g, "").slice(0, 56);
+    return id || "drawing";
+}
+
+function uniqueId(base: string, used: Set<string>): string {
+    let id = base;
+    for (let i = 2; used.has(id); i++) {
+        id = `${base}-${i}`;
+    }
+    used.add(id);
+    return id;
+}
@@ -0,0 +1,56 @@
+/**
+ * 端到端加密分享
+ * 以 AES-256-GCM 加密 {"title","content"} 的 JSON，正文格式为 e2e1.<IV>.<密文>（均为无填充 base64url）。
+ * 密钥只附在分享链接的 #key= 片段中，浏览器不会把 # 片段发送到服务器，服务器只保存与转发密文
+ */
+
+const ENVELOPE_VERSION = "e2e1";
+const KEY_BYTES = 32;
+const IV_BYTES = 12;
+
+const toBase64Url = (bytes: Uint8Array): string => {
+    let binary = "";
+    for (let i = 0; i < bytes.length; i++) {
+        binary += String.fromCharCode(bytes[i]);
+    }
+    return btoa(binary).replace(/\+/g, "-").replace(/\//g, "_").replace(/=+$/, "");
+};
+
+const fromBase64Url = (text: string): Uint8Array => {
+    const b64 = text.replace(/-/g, "+").replace(/_/g, "/");
+    const binary = atob(b64 + "===".slice((b64.length + 3) % 4));
+    const bytes = new Uint8Array(binary.length);
+    for (let i = 0; i < binary.length; i++) {
+        bytes[i] = binary.charCodeAt(i);
+    }
+    return bytes;
+};
+
+/**
+ * 生成新的随机密钥（base64url）
+ */
+export function generateShareKey(): string {
+    return toBase64Url(crypto.getRandomValues(new Uint8Array(KEY_BYTES)));
+}
+
+/**
+ * 加密标题与正文，每次使用新的随机 IV
+ */
+export async function encryptShare(key: string, title: string, content: string): Promise<string> {
+    const raw = fromBase64Url(key);
+    if (raw.length !== KEY_BYTES) {
+        throw new Error("Invalid share key");
+    }
+    const cryptoKey = await crypto.subtle.importKey("raw", raw, "AES-GCM", false, ["encrypt"]);
+    const iv = crypto.getRandomValues(new Uint8Array(IV_BYTES));
+    const plain = new TextEncoder().encode(JSON.stringify({ title, content }));
+    const ciphertext = await crypto.subtle.encrypt({ name: "AES-GCM", iv }, cryptoKey, plain);
+    return `${ENVELOPE_VERSION}.${toBase64Url(iv)}.${toBase64Url(new Uint8Array(ciphertext))}`;
+}
+
+/**
+ * 为分享链接附加密钥片段
+ */
+export function withShareKey(shareUrl: string, key: string): string {
+    return `${shareUrl.split("#")[0]}#key=${key}`;
+}
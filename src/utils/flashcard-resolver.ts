@@ -0,0 +1,135 @@
+/**
+ * 闪卡解析器
+ * 收集文档中已制卡的块，按思源的制卡规则拆分正反面并转换为 Markdown：
+ * - 标题：标题为正面，标题下的内容为反面
+ * - 列表、列表项、超级块、引述：第一个子块为正面，其余子块为反面
+ * - 其他块：含 ==标记== 时为挖空卡（正面隐藏标记内容），否则整块为正面
+ */
+
+import type { Flashcard } from "../types";
+import { parseKramdownToMarkdown } from "./kramdown-parser";
+
+/**
+ * 解析器选项
+ */
+export interface FlashcardResolverOptions {
+    siyuanToken: string;
+}
+
+interface RiffBlock {
+    id: string;
+    type?: string;
+}
+
+const HEADING_TYPES = new Set(["h", "NodeHeading"]);
+const CONTAINER_TYPES = new Set(["l", "i", "s", "b", "NodeList", "NodeListItem", "NodeSuperBlock", "NodeBlockquote"]);
+
+/**
+ * 挖空标记: ==text==
+ */
+const CLOZE_PATTERN = /==([^=\n]+)==/g;
+
+const PAGE_SIZE = 100;
+
+export class FlashcardResolver {
+    private siyuanToken: string;
+
+    constructor(options: FlashcardResolverOptions) {
+        this.siyuanToken = options.siyuanToken;
+    }
+
+    /**
+     * 收集文档中的全部闪卡，单张卡片解析失败时跳过
+     */
+    async collect(docId: string): Promise<Flashcard[]> {
+        const blocks: RiffBlock[] = [];
+        for (let page = 1; ; page++) {
+            const data = await this.request("/api/riff/getTreeRiffCards", { id: docId, page, pageSize: PAGE_SIZE });
+            const items: RiffBlock[] = data?.blocks || [];
+            blocks.push(...items);
+            if (items.length < PAGE_SIZE || page >= (data?.pageCount || 1)) {
+                break;
+            }
+        }
+
+        const cards: Flashcard[] = [];
+        for (const block of blocks) {
+            try {
+                const card = await this.resolveCard(block);
+                if (card) {
+                    cards.push(card);
+                }
+            } catch (error) {
+                console.error("解析闪卡失败:", error, block.id);
+            }
+        }
+        return cards;
+    }
+
+    private async resolveCard(block: RiffBlock): Promise<Flashcard | null> {
+        const type = block.type || "";
+        if (HEADING_TYPES.has(type) || CONTAINER_TYPES.has(type)) {
+            const children: RiffBlock[] = (await this.request("/api/block/getChildBlocks", { id: block.id })) || [];
+            if (HEADING_TYPES.has(type)) {
+                const front = await this.markdown(block.id);
+                const back = await Promise.all(children.map(c => this.markdown(c.id)));
+                return front ? { id: block.id, blockId: block.id, front, back: back.filter(Boolean).join("\n\n") } : null;
+            }
+            if (children.length > 0) {
+                const parts = await Promise.all(children.map(c => this.markdown(c.id)));
+                const [front, ...back] = parts;
+                if (front) {
+                    return { id: block.id, blockId: block.id, front, back: back.filter(Boolean).join("\n\n") };
+                }
+            }
+        }
+
+        const markdown = await this.markdown(block.id);
+        if (!markdown) {
+            return null;
+        }
+        if (markdown.match(CLOZE_PATTERN)) {
+            return {
+                id: block.id,
+                blockId: block.id,
+                front: markdown.replace(CLOZE_PATTERN, "**\\[…\\]**"),
+                back: markdown.replace(CLOZE_PATTERN, "**$1**"),
+            };
+        }
+        return { id: block.id, blockId: block.id, front: markdown, back: "" };
+    }
+
+    /**
+     * 获取块的 Kramdown 并转换为 Markdown
+     */
+    private async markdown(blockId: string): Promise<string> {
+        const data = await this.request("/api/block/getBlockKramdown", { id: blockId, mode: "md" });
+        return data?.kramdown ? parseKramdownToMarkdown(data.kramdown).trim() : "";
+    }
+
+    private async request(url: string, body: any): Promise<any> {
+        const controller = new AbortController();
+        const timeout = setTimeout(() => controller.abort(), 10000);
+        try {
+            const response = await fetch(url, {
+                method: "POST",
+                headers: {
+                    "Content-Type": "application/json",
+                    "Authorization": `Token ${this.siyuanToken}`,
+                },
+                body: JSON.stringify(body),
+                signal: controller.signal,
+            });
+            if (!response.ok) {
+                throw new Error(`HTTP ${response.status}`);
+            }
+            const result = await response.json();
+            if (result.code !== 0) {
+                throw new Error(result.msg || "unknown error");
+            }
+            return result.data;
+        } finally {
+            clearTimeout(timeout);
+        }
+    }
+}
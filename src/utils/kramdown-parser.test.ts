@@ -94,6 +94,19 @@ date: 2021-01-01
 2. 列表项二
 
 段落内容继续。`
+    },
+    {
+        name: "代码块运行结果",
+        input: `\`\`\`python
+print("a < b")
+\`\`\`
+{: id="20251106140708-noc3gik" custom-output="&quot;a &lt; b&quot;_esc_newline_"}`,
+        expected: `\`\`\`python
+print("a < b")
+\`\`\`
+\`\`\`output
+"a < b"
+\`\`\``
     },
     {
         name: "空输入处理",
@@ -31,6 +31,9 @@ export function parseKramdownToMarkdown(kramdown: string, options?: ParserOption
 
     let result = kramdown;
 
+    // 0. 代码块保存的运行结果展开为紧随其后的 output 代码块（须在清理 IAL 前进行）
+    result = convertCodeOutputs(result);
+
     // 1. 清理 IAL 属性块 {: id="..." ...}
     result = cleanIALAttributes(result);
 
@@ -86,6 +89,84 @@ function cleanIALAttributes(content: string): string {
     return result;
 }
 
+/**
+ * 代码块运行结果所在的 IAL 属性名
+ */
+const CODE_OUTPUT_ATTR = 'custom-output';
+
+/**
+ * 展开代码块的运行结果
+ *
+ * 代码块执行后（Jupyter 风格）输出保存在块属性 custom-output 中，
+ * 转换为紧随源码之后、语言标记为 output 的独立代码块，避免与源码合并或随 IAL 一起被清理。
+ *
+ * 示例输入: "```python\nprint(1)\n```\n{: id=\"xxx\" custom-output=\"1\"}"
+ * 示例输出: "```python\nprint(1)\n```\n```output\n1\n```\n{: id=\"xxx\" custom-output=\"1\"}"
+ */
+function convertCodeOutputs(content: string): string {
+    const lines = content.split('\n');
+    const result: string[] = [];
+    let fence: { indent: string; marker: string } | null = null;
+    let closedIndent: string | null = null;
+
+    for (const line of lines) {
+        if (fence) {
+            result.push(line);
+            const close = line.match(/^([ \t]*)(`{3,}|~{3,})[ \t]*$/);
+            if (close && close[2][0] === fence.marker[0] && close[2].length >= fence.marker.length) {
+                closedIndent = fence.indent;
+                fence = null;
+            }
+            continue;
+        }
+
+        const open = line.match(/^([ \t]*)(`{3,}|~{3,})/);
+        if (open) {
+            fence = { indent: open[1], marker: open[2] };
+            closedIndent = null;
+            result.push(line);
+            continue;
+        }
+
+        // 紧跟在代码块结束标记后的 IAL
+        if (closedIndent !== null && /^[ \t]*\{:.*\}[ \t]*$/.test(line)) {
+            const output = readIALValue(line, CODE_OUTPUT_ATTR);
+            if (output && output.trim()) {
+                const body = output.replace(/\n+$/, '');
+                // 输出中包含反引号围栏时加长外层围栏
+                const longest = Math.max(2, ...(body.match(/`{3,}/g) || []).map(m => m.length));
+                const marker = '`'.repeat(longest + 1);
+                result.push(closedIndent + marker + 'output');
+                for (const outLine of body.split('\n')) {
+                    result.push(closedIndent + outLine);
+                }
+                result.push(closedIndent + marker);
+            }
+        }
+        closedIndent = null;
+        result.push(line);
+    }
+
+    return result.join('\n');
+}
+
+/**
+ * 读取 IAL 中指定属性的值（还原思源的转义：HTML 实体与 _esc_newline_ 换行）
+ */
+function readIALValue(ial: string, name: string): string | null {
+    const match = ial.match(new RegExp(`(?:^|[\\s{:])${name}="([^"]*)"`));
+    if (!match) {
+        return null;
+    }
+    return match[1]
+        .replace(/_esc_newline_/g, '\n')
+        .replace(/&quot;/g, '"')
+        .replace(/&#39;/g, "'")
+        .replace(/&lt;/g, '<')
+        .replace(/&gt;/g, '>')
+        .replace(/&amp;/g, '&');
+}
+
 /**
  * 转换块引用语法
  * 格式1: ((20210101-abc))
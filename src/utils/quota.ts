@@ -0,0 +1,45 @@
+/**
+ * 配额状态的提示文字
+ * 用于发布后提示接近配额，以及设置页测试连接时展示当前用量
+ */
+
+import type { QuotaStatus } from "../types";
+
+/**
+ * 配额数值的显示形式：分享数为个数，存储与流量为字节数
+ */
+export function formatQuotaAmount(resource: QuotaStatus["resource"], n: number): string {
+    if (resource === "shares") {
+        return String(n);
+    }
+    const units = ["B", "KB", "MB", "GB", "TB"];
+    let v = n;
+    let i = 0;
+    while (v >= 1024 && i < units.length - 1) {
+        v /= 1024;
+        i++;
+    }
+    return i === 0 ? `${n} B` : `${v.toFixed(1)} ${units[i]}`;
+}
+
+/**
+ * 单项配额的提示，如“存储空间：已用 96%（9.6 MB / 10.0 MB）”
+ */
+export function formatQuotaStatus(i18n: Record<string, any>, status: QuotaStatus): string {
+    const names: Record<QuotaStatus["resource"], string> = {
+        storage: i18n.quotaStorage || "存储空间",
+        shares: i18n.quotaShares || "分享数",
+        bandwidth: i18n.quotaBandwidth || "本月流量",
+    };
+    const used = formatQuotaAmount(status.resource, status.used);
+    const limit = formatQuotaAmount(status.resource, status.limit);
+    const text = `${names[status.resource]}: ${status.percent}% (${used} / ${limit})`;
+    switch (status.state) {
+        case "blocked":
+            return `${text} ${i18n.quotaBlocked || "已达上限"}`;
+        case "grace":
+            return `${text} ${i18n.quotaGrace || "已超出配额，处于宽限范围内"}`;
+        default:
+            return text;
+    }
+}